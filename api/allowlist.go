@@ -0,0 +1,226 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/api/context"
+	"github.com/tsuru/tsuru/auth"
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/servicemanager"
+	authTypes "github.com/tsuru/tsuru/types/auth"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+)
+
+// ipAllowlistMiddleware rejects requests authenticated with tokens restricted
+// by IP allowlists, of the token or of its team, when the address of the
+// client isn't in them.
+type ipAllowlistMiddleware struct {
+	// trustedProxies are the networks of proxies in front of the API, the
+	// address of clients behind them is taken from X-Forwarded-For.
+	trustedProxies []*net.IPNet
+}
+
+// newIPAllowlistMiddleware returns the middleware with the trusted proxies
+// configured in the api:ip-allowlist:trusted-proxies key.
+func newIPAllowlistMiddleware() (*ipAllowlistMiddleware, error) {
+	proxies, err := config.GetList("api:ip-allowlist:trusted-proxies")
+	if err != nil {
+		if _, isNotFound := err.(config.ErrKeyNotFound); !isNotFound {
+			return nil, errors.Wrap(err, "unable to load api ip allowlist")
+		}
+	}
+	cidrs, err := auth.ParseAllowedCIDRs(proxies)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to load api ip allowlist trusted proxies")
+	}
+	m := &ipAllowlistMiddleware{}
+	for _, cidr := range cidrs {
+		_, ipNet, _ := net.ParseCIDR(cidr)
+		m.trustedProxies = append(m.trustedProxies, ipNet)
+	}
+	return m, nil
+}
+
+func (m *ipAllowlistMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	t := context.GetAuthToken(r)
+	if t == nil {
+		next(w, r)
+		return
+	}
+	allowlists, err := auth.TokenAllowlists(r.Context(), t)
+	if err != nil {
+		context.AddRequestError(r, err)
+		return
+	}
+	ip := m.clientIP(r)
+	for _, allowlist := range allowlists {
+		if !auth.AddressAllowed(allowlist, ip) {
+			context.AddRequestError(r, &tsuruErrors.HTTP{
+				Code:      http.StatusForbidden,
+				Message:   fmt.Sprintf("token not allowed from address %s", ip),
+				ErrorCode: "auth.address-not-allowed",
+			})
+			return
+		}
+	}
+	next(w, r)
+}
+
+// clientIP returns the address of the client sending the request. When the
+// request comes from a trusted proxy, it's the rightmost address in
+// X-Forwarded-For that isn't a trusted proxy.
+func (m *ipAllowlistMiddleware) clientIP(r *http.Request) net.IP {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	ip := net.ParseIP(host)
+	if !m.isTrustedProxy(ip) {
+		return ip
+	}
+	forwarded := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		forwardedIP := net.ParseIP(strings.TrimSpace(forwarded[i]))
+		if forwardedIP == nil {
+			break
+		}
+		ip = forwardedIP
+		if !m.isTrustedProxy(ip) {
+			break
+		}
+	}
+	return ip
+}
+
+func (m *ipAllowlistMiddleware) isTrustedProxy(ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+	for _, ipNet := range m.trustedProxies {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func allowlistError(err error) error {
+	if _, ok := err.(*tsuruErrors.ValidationError); ok {
+		return &tsuruErrors.HTTP{Code: http.StatusBadRequest, Message: err.Error()}
+	}
+	return err
+}
+
+func writeAllowlist(w http.ResponseWriter, cidrs []string) error {
+	if cidrs == nil {
+		cidrs = []string{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(cidrs)
+}
+
+// title: set team ip allowlist
+// path: /teams/{name}/allowlist
+// method: PUT
+// consume: application/x-www-form-urlencoded
+// produce: application/json
+// responses:
+//   200: Allowlist updated
+//   400: Invalid data
+//   401: Unauthorized
+//   404: Team not found
+func setTeamAllowlist(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	ctx := r.Context()
+	teamName := r.URL.Query().Get(":name")
+	if !permission.Check(t, permission.PermTeamUpdateAllowlist) {
+		return permission.ErrUnauthorized
+	}
+	_, err = servicemanager.Team.FindByName(ctx, teamName)
+	if err == authTypes.ErrTeamNotFound {
+		return &tsuruErrors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+	}
+	if err != nil {
+		return err
+	}
+	cidrs, _ := InputValues(r, "cidr")
+	evt, err := event.New(&event.Opts{
+		Target:     teamTarget(teamName),
+		Kind:       permission.PermTeamUpdateAllowlist,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		CustomData: event.FormToCustomData(InputFields(r)),
+		Allowed:    event.Allowed(permission.PermTeamReadEvents, permission.Context(permTypes.CtxTeam, teamName)),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	err = servicemanager.Team.SetAllowedCIDRs(ctx, teamName, cidrs)
+	if err != nil {
+		return allowlistError(err)
+	}
+	team, err := servicemanager.Team.FindByName(ctx, teamName)
+	if err != nil {
+		return err
+	}
+	return writeAllowlist(w, team.AllowedCIDRs)
+}
+
+// title: set token ip allowlist
+// path: /tokens/{token_id}/allowlist
+// method: PUT
+// consume: application/x-www-form-urlencoded
+// produce: application/json
+// responses:
+//   200: Allowlist updated
+//   400: Invalid data
+//   401: Unauthorized
+//   404: Token not found
+func setTokenAllowlist(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	ctx := r.Context()
+	tokenID := r.URL.Query().Get(":token_id")
+	if !permission.Check(t, permission.PermTeamTokenUpdateAllowlist) {
+		return permission.ErrUnauthorized
+	}
+	teamToken, err := servicemanager.TeamToken.FindByTokenID(ctx, tokenID)
+	if err == authTypes.ErrTeamTokenNotFound {
+		return &tsuruErrors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+	}
+	if err != nil {
+		return err
+	}
+	cidrs, _ := InputValues(r, "cidr")
+	evt, err := event.New(&event.Opts{
+		Target:     teamTarget(teamToken.Team),
+		Kind:       permission.PermTeamTokenUpdateAllowlist,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		CustomData: event.FormToCustomData(InputFields(r)),
+		Allowed:    event.Allowed(permission.PermTeamReadEvents, permission.Context(permTypes.CtxTeam, teamToken.Team)),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	err = servicemanager.TeamToken.SetAllowedCIDRs(ctx, tokenID, cidrs)
+	if err != nil {
+		return allowlistError(err)
+	}
+	teamToken, err = servicemanager.TeamToken.FindByTokenID(ctx, tokenID)
+	if err != nil {
+		return err
+	}
+	return writeAllowlist(w, teamToken.AllowedCIDRs)
+}
@@ -0,0 +1,226 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	stdContext "context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/api/context"
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event/eventtest"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/servicemanager"
+	authTypes "github.com/tsuru/tsuru/types/auth"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestNewIPAllowlistMiddlewareInvalidProxy(c *check.C) {
+	config.Set("api:ip-allowlist:trusted-proxies", []interface{}{"10.0.0.0/33"})
+	defer config.Unset("api:ip-allowlist")
+	m, err := newIPAllowlistMiddleware()
+	c.Assert(err, check.ErrorMatches, `.*invalid CIDR "10.0.0.0/33"`)
+	c.Assert(m, check.IsNil)
+}
+
+func (s *S) TestIPAllowlistMiddlewareClientIP(c *check.C) {
+	config.Set("api:ip-allowlist:trusted-proxies", []interface{}{"10.0.0.0/8"})
+	defer config.Unset("api:ip-allowlist")
+	m, err := newIPAllowlistMiddleware()
+	c.Assert(err, check.IsNil)
+	tests := []struct {
+		remoteAddr string
+		forwarded  []string
+		expected   string
+	}{
+		{remoteAddr: "192.168.0.1:4040", expected: "192.168.0.1"},
+		{remoteAddr: "192.168.0.1:4040", forwarded: []string{"172.16.0.1"}, expected: "192.168.0.1"},
+		{remoteAddr: "10.0.0.1:4040", expected: "10.0.0.1"},
+		{remoteAddr: "10.0.0.1:4040", forwarded: []string{"172.16.0.1"}, expected: "172.16.0.1"},
+		{remoteAddr: "10.0.0.1:4040", forwarded: []string{"1.1.1.1, 172.16.0.1, 10.0.0.2"}, expected: "172.16.0.1"},
+		{remoteAddr: "10.0.0.1:4040", forwarded: []string{"1.1.1.1", "172.16.0.1"}, expected: "172.16.0.1"},
+		{remoteAddr: "10.0.0.1:4040", forwarded: []string{"garbage, 10.0.0.2"}, expected: "10.0.0.2"},
+	}
+	for _, tt := range tests {
+		request, err := http.NewRequest("GET", "/apps", nil)
+		c.Assert(err, check.IsNil)
+		request.RemoteAddr = tt.remoteAddr
+		for _, f := range tt.forwarded {
+			request.Header.Add("X-Forwarded-For", f)
+		}
+		c.Check(m.clientIP(request).String(), check.Equals, tt.expected, check.Commentf("%+v", tt))
+	}
+}
+
+func (s *S) TestIPAllowlistMiddleware(c *check.C) {
+	m, err := newIPAllowlistMiddleware()
+	c.Assert(err, check.IsNil)
+	teamToken, err := servicemanager.TeamToken.Create(stdContext.TODO(), authTypes.TeamTokenCreateArgs{
+		Team: s.team.Name,
+	}, s.token)
+	c.Assert(err, check.IsNil)
+	err = servicemanager.TeamToken.SetAllowedCIDRs(stdContext.TODO(), teamToken.TokenID, []string{"192.168.0.0/16"})
+	c.Assert(err, check.IsNil)
+	err = servicemanager.Team.SetAllowedCIDRs(stdContext.TODO(), s.team.Name, []string{"192.168.1.0/24", "172.16.0.1"})
+	c.Assert(err, check.IsNil)
+	t, err := servicemanager.TeamToken.Authenticate(stdContext.TODO(), "bearer "+teamToken.Token)
+	c.Assert(err, check.IsNil)
+	doRequest := func(token authTypes.Token, remoteAddr string) (*http.Request, *handlerLog) {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest("GET", "/apps", nil)
+		c.Assert(err, check.IsNil)
+		request.RemoteAddr = remoteAddr
+		if token != nil {
+			context.SetAuthToken(request, token)
+		}
+		h, log := doHandler()
+		m.ServeHTTP(recorder, request, h)
+		return request, log
+	}
+	request, log := doRequest(t, "192.168.1.10:4040")
+	c.Assert(log.called, check.Equals, true)
+	c.Assert(context.GetRequestError(request), check.IsNil)
+	for _, addr := range []string{"192.168.2.10:4040", "172.16.0.1:4040", "10.0.0.1:4040"} {
+		request, log = doRequest(t, addr)
+		c.Assert(log.called, check.Equals, false)
+		httpErr, ok := context.GetRequestError(request).(*tsuruErrors.HTTP)
+		c.Assert(ok, check.Equals, true)
+		c.Assert(httpErr.Code, check.Equals, http.StatusForbidden)
+		c.Assert(httpErr.ErrorCode, check.Equals, "auth.address-not-allowed")
+	}
+	request, log = doRequest(s.token, "10.0.0.1:4040")
+	c.Assert(log.called, check.Equals, true)
+	c.Assert(context.GetRequestError(request), check.IsNil)
+	request, log = doRequest(nil, "10.0.0.1:4040")
+	c.Assert(log.called, check.Equals, true)
+	c.Assert(context.GetRequestError(request), check.IsNil)
+}
+
+func (s *S) TestSetTeamAllowlist(c *check.C) {
+	body := strings.NewReader("cidr=10.0.0.0/8&cidr=192.168.0.1")
+	request, err := http.NewRequest("PUT", "/1.13/teams/"+s.team.Name+"/allowlist", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK, check.Commentf("body: %s", recorder.Body.String()))
+	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, "application/json")
+	var result []string
+	err = json.Unmarshal(recorder.Body.Bytes(), &result)
+	c.Assert(err, check.IsNil)
+	c.Assert(result, check.DeepEquals, []string{"10.0.0.0/8", "192.168.0.1/32"})
+	team, err := servicemanager.Team.FindByName(stdContext.TODO(), s.team.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(team.AllowedCIDRs, check.DeepEquals, []string{"10.0.0.0/8", "192.168.0.1/32"})
+	c.Assert(eventtest.EventDesc{
+		Target: teamTarget(s.team.Name),
+		Owner:  s.token.GetUserName(),
+		Kind:   "team.update.allowlist",
+		StartCustomData: []map[string]interface{}{
+			{"name": "cidr", "value": []string{"10.0.0.0/8", "192.168.0.1"}},
+		},
+	}, eventtest.HasEvent)
+}
+
+func (s *S) TestSetTeamAllowlistClear(c *check.C) {
+	err := servicemanager.Team.SetAllowedCIDRs(stdContext.TODO(), s.team.Name, []string{"10.0.0.0/8"})
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest("PUT", "/1.13/teams/"+s.team.Name+"/allowlist", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK, check.Commentf("body: %s", recorder.Body.String()))
+	c.Assert(recorder.Body.String(), check.Equals, "[]\n")
+	team, err := servicemanager.Team.FindByName(stdContext.TODO(), s.team.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(team.AllowedCIDRs, check.IsNil)
+}
+
+func (s *S) TestSetTeamAllowlistInvalidCIDR(c *check.C) {
+	body := strings.NewReader("cidr=10.0.0.0/33")
+	request, err := http.NewRequest("PUT", "/1.13/teams/"+s.team.Name+"/allowlist", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(recorder.Body.String(), check.Equals, "invalid CIDR \"10.0.0.0/33\"\n")
+}
+
+func (s *S) TestSetTeamAllowlistTeamNotFound(c *check.C) {
+	request, err := http.NewRequest("PUT", "/1.13/teams/unknown/allowlist", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNotFound)
+}
+
+func (s *S) TestSetTeamAllowlistUnauthorized(c *check.C) {
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermTeamUpdate,
+		Context: permission.Context(permTypes.CtxTeam, s.team.Name),
+	})
+	request, err := http.NewRequest("PUT", "/1.13/teams/"+s.team.Name+"/allowlist", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
+
+func (s *S) TestSetTokenAllowlist(c *check.C) {
+	teamToken, err := servicemanager.TeamToken.Create(stdContext.TODO(), authTypes.TeamTokenCreateArgs{
+		Team: s.team.Name,
+	}, s.token)
+	c.Assert(err, check.IsNil)
+	body := strings.NewReader("cidr=10.0.0.0/8")
+	request, err := http.NewRequest("PUT", "/1.13/tokens/"+teamToken.TokenID+"/allowlist", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK, check.Commentf("body: %s", recorder.Body.String()))
+	c.Assert(recorder.Body.String(), check.Equals, "[\"10.0.0.0/8\"]\n")
+	t, err := servicemanager.TeamToken.FindByTokenID(stdContext.TODO(), teamToken.TokenID)
+	c.Assert(err, check.IsNil)
+	c.Assert(t.AllowedCIDRs, check.DeepEquals, []string{"10.0.0.0/8"})
+	c.Assert(eventtest.EventDesc{
+		Target: teamTarget(s.team.Name),
+		Owner:  s.token.GetUserName(),
+		Kind:   "team.token.update.allowlist",
+	}, eventtest.HasEvent)
+}
+
+func (s *S) TestSetTokenAllowlistTokenNotFound(c *check.C) {
+	request, err := http.NewRequest("PUT", "/1.13/tokens/unknown/allowlist", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNotFound)
+}
+
+func (s *S) TestSetTokenAllowlistUnauthorized(c *check.C) {
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermTeamTokenUpdate,
+		Context: permission.Context(permTypes.CtxTeam, s.team.Name),
+	})
+	request, err := http.NewRequest("PUT", "/1.13/tokens/sometoken/allowlist", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
@@ -11,6 +11,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"reflect"
 	"strconv"
 	"strings"
 	"time"
@@ -39,6 +40,7 @@ import (
 	appTypes "github.com/tsuru/tsuru/types/app"
 	permTypes "github.com/tsuru/tsuru/types/permission"
 	"github.com/tsuru/tsuru/types/quota"
+	volumeTypes "github.com/tsuru/tsuru/types/volume"
 )
 
 var (
@@ -79,7 +81,7 @@ func getApp(ctx stdContext.Context, name string) (*app.App, error) {
 	a, err := app.GetByName(ctx, name)
 	if err != nil {
 		if err == appTypes.ErrAppNotFound {
-			return nil, &errors.HTTP{Code: http.StatusNotFound, Message: fmt.Sprintf("App %s not found.", name)}
+			return nil, &errors.HTTP{Code: http.StatusNotFound, Message: fmt.Sprintf("App %s not found.", name), ErrorCode: "app.not-found"}
 		}
 		return nil, err
 	}
@@ -252,6 +254,8 @@ contextsLoop:
 // responses:
 //   200: List apps
 //   204: No content
+//   304: Not modified
+//   400: Invalid data
 //   401: Unauthorized
 func appList(w http.ResponseWriter, r *http.Request, t auth.Token) error {
 	ctx := r.Context()
@@ -278,8 +282,16 @@ func appList(w http.ResponseWriter, r *http.Request, t auth.Token) error {
 	if status, ok := r.URL.Query()["status"]; ok {
 		filter.Statuses = status
 	}
-	if tags, ok := r.URL.Query()["tag"]; ok {
-		filter.Tags = tags
+	for _, selector := range r.URL.Query()["tag"] {
+		requirements, err := app.ParseTagSelector(selector)
+		if err != nil {
+			return &errors.HTTP{Code: http.StatusBadRequest, Message: err.Error()}
+		}
+		filter.TagSelector = append(filter.TagSelector, requirements...)
+	}
+	fields, err := parseFieldSelection(r, reflect.TypeOf(miniApp{}))
+	if err != nil {
+		return err
 	}
 	contexts := permission.ContextsForPermission(t, permission.PermAppRead)
 	contexts = append(contexts, permission.ContextsForPermission(t, permission.PermAppReadInfo)...)
@@ -291,13 +303,25 @@ func appList(w http.ResponseWriter, r *http.Request, t auth.Token) error {
 	if err != nil {
 		return err
 	}
+	start, end, err := paginate(w, r, len(apps))
+	if err != nil {
+		return err
+	}
+	apps = apps[start:end]
 	if len(apps) == 0 {
 		w.WriteHeader(http.StatusNoContent)
 		return nil
 	}
 	simple, _ := strconv.ParseBool(r.URL.Query().Get("simplified"))
 	extended, _ := strconv.ParseBool(r.URL.Query().Get("extended"))
-	w.Header().Set("Content-Type", "application/json")
+	if fields != nil {
+		// fields only available in the extended listing are always loaded
+		// when selected.
+		extended = extended || fields.includes("platform") || fields.includes("description") || fields.includes("metadata")
+		// units are loaded from the provisioners, the slowest part of the
+		// listing, so they're skipped when not selected.
+		simple = simple || !(fields.includes("units") || fields.includes("error"))
+	}
 	miniApps := make([]miniApp, len(apps))
 	if simple {
 		for i, ap := range apps {
@@ -307,7 +331,7 @@ func appList(w http.ResponseWriter, r *http.Request, t auth.Token) error {
 				return err
 			}
 		}
-		return json.NewEncoder(w).Encode(miniApps)
+		return writeAppList(w, r, miniApps, fields)
 	}
 	appUnits, err := app.Units(ctx, apps)
 	if err != nil {
@@ -320,7 +344,18 @@ func appList(w http.ResponseWriter, r *http.Request, t auth.Token) error {
 			return err
 		}
 	}
-	return json.NewEncoder(w).Encode(miniApps)
+	return writeAppList(w, r, miniApps, fields)
+}
+
+func writeAppList(w http.ResponseWriter, r *http.Request, miniApps []miniApp, fields fieldSelection) error {
+	if fields == nil {
+		return writeJSONWithETag(w, r, miniApps)
+	}
+	sparse, err := fields.apply(miniApps)
+	if err != nil {
+		return err
+	}
+	return writeJSONWithETag(w, r, sparse)
 }
 
 // title: app info
@@ -361,7 +396,12 @@ type inputApp struct {
 	RouterOpts   map[string]string
 	Tags         []string
 	PlanOverride appTypes.PlanOverride
+	ProcessPlans map[string]string
 	Metadata     appTypes.Metadata
+	Internal     string
+
+	TerminationGracePeriods map[string]int
+	Scheduling              *appTypes.Scheduling
 }
 
 func autoTeamOwner(ctx stdContext.Context, t auth.Token, perm *permission.PermissionScheme) (string, error) {
@@ -389,12 +429,21 @@ func autoTeamOwner(ctx stdContext.Context, t auth.Token, perm *permission.Permis
 // produce: application/json
 // responses:
 //   201: App created
+//   202: App creation started
 //   400: Invalid data
 //   401: Unauthorized
 //   403: Quota exceeded
 //   409: App already exists
 func createApp(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
 	ctx := r.Context()
+	dryRun, err := isDryRun(r)
+	if err != nil {
+		return err
+	}
+	async, err := isAsync(r)
+	if err != nil {
+		return err
+	}
 	var ia inputApp
 	err = ParseInput(r, &ia)
 	if err != nil {
@@ -413,6 +462,12 @@ func createApp(w http.ResponseWriter, r *http.Request, t auth.Token) (err error)
 		Metadata:    ia.Metadata,
 		Quota:       quota.UnlimitedQuota,
 	}
+	if ia.Internal != "" {
+		a.Internal, err = strconv.ParseBool(ia.Internal)
+		if err != nil {
+			return &errors.HTTP{Code: http.StatusBadRequest, Message: "invalid value for internal, expected a boolean"}
+		}
+	}
 	tags, _ := InputValues(r, "tag")
 	a.Tags = append(a.Tags, tags...) // for compatibility
 	if a.TeamOwner == "" {
@@ -421,10 +476,7 @@ func createApp(w http.ResponseWriter, r *http.Request, t auth.Token) (err error)
 			return err
 		}
 	}
-	canCreate := permission.Check(t, permission.PermAppCreate,
-		permission.Context(permTypes.CtxTeam, a.TeamOwner),
-	)
-	if !canCreate {
+	if !canCreateApp(t, a.TeamOwner, a.Pool) {
 		return permission.ErrUnauthorized
 	}
 	u, err := auth.ConvertNewUser(t.User())
@@ -445,6 +497,22 @@ func createApp(w http.ResponseWriter, r *http.Request, t auth.Token) (err error)
 			}
 		}
 	}
+	if dryRun {
+		err = app.ValidateNewApp(ctx, &a, u)
+		if err != nil {
+			return createAppError(err)
+		}
+		return writeDryRunApp(w, &a)
+	}
+	if async {
+		// the app is prepared again on its creation, so a copy is validated
+		// to report invalid apps before responding.
+		validated := a
+		err = app.ValidateNewApp(ctx, &validated, u)
+		if err != nil {
+			return createAppError(err)
+		}
+	}
 	evt, err := event.New(&event.Opts{
 		Target:     appTarget(a.Name),
 		Kind:       permission.PermAppCreate,
@@ -456,31 +524,16 @@ func createApp(w http.ResponseWriter, r *http.Request, t auth.Token) (err error)
 	if err != nil {
 		return err
 	}
+	if async {
+		return runAsync(w, evt, func(ctx stdContext.Context) (interface{}, error) {
+			return nil, app.CreateApp(ctx, &a, u)
+		})
+	}
 	defer func() { evt.Done(err) }()
 	err = app.CreateApp(ctx, &a, u)
 	if err != nil {
 		log.Errorf("Got error while creating app: %s", err)
-		if _, ok := err.(appTypes.NoTeamsError); ok {
-			return &errors.HTTP{
-				Code:    http.StatusBadRequest,
-				Message: "In order to create an app, you should be member of at least one team",
-			}
-		}
-		if e, ok := err.(*appTypes.AppCreationError); ok {
-			if e.Err == app.ErrAppAlreadyExists {
-				return &errors.HTTP{Code: http.StatusConflict, Message: e.Error()}
-			}
-			if _, ok := pkgErrors.Cause(e.Err).(*quota.QuotaExceededError); ok {
-				return &errors.HTTP{
-					Code:    http.StatusForbidden,
-					Message: "Quota exceeded",
-				}
-			}
-		}
-		if err == appTypes.ErrInvalidPlatform {
-			return &errors.HTTP{Code: http.StatusBadRequest, Message: err.Error()}
-		}
-		return err
+		return createAppError(err)
 	}
 	msg := map[string]interface{}{
 		"status": "success",
@@ -502,6 +555,32 @@ func createApp(w http.ResponseWriter, r *http.Request, t auth.Token) (err error)
 	return nil
 }
 
+// createAppError converts errors of app creation to their HTTP errors.
+func createAppError(err error) error {
+	if _, ok := err.(appTypes.NoTeamsError); ok {
+		return &errors.HTTP{
+			Code:    http.StatusBadRequest,
+			Message: "In order to create an app, you should be member of at least one team",
+		}
+	}
+	if e, ok := err.(*appTypes.AppCreationError); ok {
+		if e.Err == app.ErrAppAlreadyExists {
+			return &errors.HTTP{Code: http.StatusConflict, Message: e.Error(), ErrorCode: "app.already-exists"}
+		}
+		if _, ok := pkgErrors.Cause(e.Err).(*quota.QuotaExceededError); ok {
+			return &errors.HTTP{
+				Code:      http.StatusForbidden,
+				Message:   "Quota exceeded",
+				ErrorCode: "app.quota.exceeded",
+			}
+		}
+	}
+	if err == appTypes.ErrInvalidPlatform {
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: err.Error()}
+	}
+	return err
+}
+
 // title: app update
 // path: /apps/{name}
 // method: PUT
@@ -514,6 +593,10 @@ func createApp(w http.ResponseWriter, r *http.Request, t auth.Token) (err error)
 //   404: Not found
 func updateApp(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
 	ctx := r.Context()
+	dryRun, err := isDryRun(r)
+	if err != nil {
+		return err
+	}
 	var ia inputApp
 	err = ParseInput(r, &ia)
 	if err != nil {
@@ -532,6 +615,14 @@ func updateApp(w http.ResponseWriter, r *http.Request, t auth.Token) (err error)
 		RouterOpts:     ia.RouterOpts,
 		Metadata:       ia.Metadata,
 	}
+	for process, planName := range ia.ProcessPlans {
+		if updateData.ProcessPlans == nil {
+			updateData.ProcessPlans = make(map[string]appTypes.Plan)
+		}
+		updateData.ProcessPlans[process] = appTypes.Plan{Name: planName}
+	}
+	updateData.TerminationGracePeriods = ia.TerminationGracePeriods
+	updateData.Scheduling = ia.Scheduling
 	tags, _ := InputValues(r, "tag")
 	noRestart, _ := strconv.ParseBool(InputValue(r, "noRestart"))
 	updateData.Tags = append(updateData.Tags, tags...) // for compatibility
@@ -550,7 +641,7 @@ func updateApp(w http.ResponseWriter, r *http.Request, t auth.Token) (err error)
 	if len(updateData.Tags) > 0 {
 		wantedPerms = append(wantedPerms, permission.PermAppUpdateTags)
 	}
-	if updateData.Plan.Name != "" {
+	if updateData.Plan.Name != "" || len(updateData.ProcessPlans) > 0 {
 		wantedPerms = append(wantedPerms, permission.PermAppUpdatePlan)
 	}
 	if updateData.Plan.Override != (appTypes.PlanOverride{}) {
@@ -587,8 +678,23 @@ func updateApp(w http.ResponseWriter, r *http.Request, t auth.Token) (err error)
 	if len(updateData.Metadata.Annotations) > 0 || len(updateData.Metadata.Labels) > 0 {
 		wantedPerms = append(wantedPerms, permission.PermAppUpdateMetadata)
 	}
+	var internal *bool
+	if ia.Internal != "" {
+		value, errParse := strconv.ParseBool(ia.Internal)
+		if errParse != nil {
+			return &errors.HTTP{Code: http.StatusBadRequest, Message: "invalid value for internal, expected a boolean"}
+		}
+		internal = &value
+		wantedPerms = append(wantedPerms, permission.PermAppUpdateInternal)
+	}
+	if len(updateData.TerminationGracePeriods) > 0 {
+		wantedPerms = append(wantedPerms, permission.PermAppUpdateTerminationGracePeriod)
+	}
+	if updateData.Scheduling != nil {
+		wantedPerms = append(wantedPerms, permission.PermAppUpdateScheduling)
+	}
 	if len(wantedPerms) == 0 {
-		msg := "Neither the description, tags, plan, pool, team owner or platform were set. You must define at least one."
+		msg := "Neither the description, tags, plan, pool, team owner, platform, internal, termination grace periods or scheduling were set. You must define at least one."
 		return &errors.HTTP{Code: http.StatusBadRequest, Message: msg}
 	}
 	for _, perm := range wantedPerms {
@@ -599,6 +705,27 @@ func updateApp(w http.ResponseWriter, r *http.Request, t auth.Token) (err error)
 			return permission.ErrUnauthorized
 		}
 	}
+	if updateData.Pool != "" {
+		// users allowed to change the pool of apps only in some pools can't
+		// move apps to other pools.
+		moved := a
+		moved.Pool = updateData.Pool
+		if !permission.Check(t, permission.PermAppUpdatePool, contextsForApp(&moved)...) {
+			return permission.ErrUnauthorized
+		}
+	}
+	if dryRun {
+		err = a.Update(app.UpdateAppArgs{
+			UpdateData:    updateData,
+			ShouldRestart: !noRestart,
+			Internal:      internal,
+			DryRun:        true,
+		})
+		if err != nil {
+			return updateAppError(err)
+		}
+		return writeDryRunApp(w, &a)
+	}
 	evt, err := event.New(&event.Opts{
 		Target:        appTarget(appName),
 		Kind:          permission.PermAppUpdate,
@@ -621,243 +748,896 @@ func updateApp(w http.ResponseWriter, r *http.Request, t auth.Token) (err error)
 	w.Header().Set("Content-Type", "application/x-json-stream")
 	writer := &tsuruIo.SimpleJsonMessageEncoderWriter{Encoder: json.NewEncoder(keepAliveWriter)}
 	evt.SetLogWriter(writer)
+	before := appUpdateSnapshot(&a)
 	err = a.Update(app.UpdateAppArgs{
 		UpdateData:    updateData,
 		Writer:        evt,
 		ShouldRestart: !noRestart,
+		Internal:      internal,
 	})
+	if err != nil {
+		return updateAppError(err)
+	}
+	return evt.SetChanges(before, appUpdateSnapshot(&a))
+}
+
+// updateAppError converts errors of app updates to their HTTP errors.
+func updateAppError(err error) error {
 	if err == appTypes.ErrPlanNotFound {
 		return &errors.HTTP{Code: http.StatusBadRequest, Message: err.Error()}
 	}
+	if _, ok := err.(*quota.QuotaExceededError); ok {
+		return &errors.HTTP{Code: http.StatusForbidden, Message: err.Error(), ErrorCode: "app.quota.exceeded"}
+	}
 	if _, ok := err.(*router.ErrRouterNotFound); ok {
 		return &errors.HTTP{Code: http.StatusBadRequest, Message: err.Error()}
 	}
 	return err
 }
 
-func numberOfUnits(r *http.Request) (uint, error) {
-	unitsStr := InputValue(r, "units")
-	if unitsStr == "" {
-		return 0, &errors.HTTP{
-			Code:    http.StatusBadRequest,
-			Message: "You must provide the number of units.",
-		}
-	}
-	n, err := strconv.ParseUint(unitsStr, 10, 32)
-	if err != nil || n == 0 {
-		return 0, &errors.HTTP{
-			Code:    http.StatusBadRequest,
-			Message: "Invalid number of units: the number must be an integer greater than 0.",
-		}
+// appUpdateSnapshot returns the app fields which may be changed by an app
+// update, leaving sensitive data like environment variables out.
+func appUpdateSnapshot(a *app.App) map[string]interface{} {
+	return map[string]interface{}{
+		"description":     a.Description,
+		"tags":            a.Tags,
+		"plan":            a.Plan.Name,
+		"pool":            a.Pool,
+		"teamOwner":       a.TeamOwner,
+		"platform":        a.Platform,
+		"platformVersion": a.PlatformVersion,
+		"metadata":        a.Metadata,
+		"internal":        a.Internal,
+
+		"terminationGracePeriods": a.TerminationGracePeriods,
+		"scheduling":              a.Scheduling,
 	}
-	return uint(n), nil
 }
 
-// title: add units
-// path: /apps/{name}/units
-// method: PUT
+// title: app rename
+// path: /apps/{app}/rename
+// method: POST
 // consume: application/x-www-form-urlencoded
 // produce: application/x-json-stream
 // responses:
-//   200: Units added
+//   200: App renamed
 //   400: Invalid data
 //   401: Unauthorized
 //   404: App not found
-func addUnits(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
-	n, err := numberOfUnits(r)
-	if err != nil {
-		return err
-	}
-	processName := InputValue(r, "process")
-	version := InputValue(r, "version")
+//   409: App already exists
+func renameApp(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
 	appName := r.URL.Query().Get(":app")
+	newName := InputValue(r, "name")
+	if newName == "" {
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: "new app name is required"}
+	}
 	a, err := getAppFromContext(appName, r)
 	if err != nil {
 		return err
 	}
-	allowed := permission.Check(t, permission.PermAppUpdateUnitAdd,
+	allowed := permission.Check(t, permission.PermAppUpdateRename,
 		contextsForApp(&a)...,
 	)
 	if !allowed {
 		return permission.ErrUnauthorized
 	}
 	evt, err := event.New(&event.Opts{
-		Target:        appTarget(appName),
-		Kind:          permission.PermAppUpdateUnitAdd,
-		Owner:         t,
-		RemoteAddr:    r.RemoteAddr,
-		CustomData:    event.FormToCustomData(InputFields(r)),
-		Allowed:       event.Allowed(permission.PermAppReadEvents, contextsForApp(&a)...),
-		AllowedCancel: event.Allowed(permission.PermAppUpdateEvents, contextsForApp(&a)...),
-		Cancelable:    true,
+		Target: appTarget(appName),
+		ExtraTargets: []event.ExtraTarget{
+			{Target: appTarget(newName), Lock: true},
+		},
+		Kind:       permission.PermAppUpdateRename,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		CustomData: event.FormToCustomData(InputFields(r)),
+		Allowed:    event.Allowed(permission.PermAppReadEvents, contextsForApp(&a)...),
 	})
 	if err != nil {
 		return err
 	}
 	defer func() { evt.Done(err) }()
-	ctx, cancel := evt.CancelableContext(a.Context())
-	defer cancel()
-	a.ReplaceContext(ctx)
-	w.Header().Set("Content-Type", "application/x-json-stream")
 	keepAliveWriter := tsuruIo.NewKeepAliveWriter(w, 30*time.Second, "")
 	defer keepAliveWriter.Stop()
+	w.Header().Set("Content-Type", "application/x-json-stream")
 	writer := &tsuruIo.SimpleJsonMessageEncoderWriter{Encoder: json.NewEncoder(keepAliveWriter)}
 	evt.SetLogWriter(writer)
-	return a.AddUnits(n, processName, version, evt)
+	err = a.Rename(r.Context(), newName, evt, requestIDHeader(r))
+	if v, ok := err.(*errors.ValidationError); ok {
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: v.Message}
+	}
+	if err == app.ErrAppAlreadyExists {
+		return &errors.HTTP{Code: http.StatusConflict, Message: err.Error()}
+	}
+	return err
 }
 
-// title: remove units
-// path: /apps/{name}/units
-// method: DELETE
-// produce: application/x-json-stream
+// title: app export
+// path: /apps/{app}/export
+// method: GET
+// produce: application/json
 // responses:
-//   200: Units removed
-//   400: Invalid data
+//   200: OK
 //   401: Unauthorized
-//   403: Not enough reserved units
 //   404: App not found
-func removeUnits(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
-	n, err := numberOfUnits(r)
+func exportApp(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	a, err := getAppFromContext(r.URL.Query().Get(":app"), r)
 	if err != nil {
 		return err
 	}
-	version := InputValue(r, "version")
-	processName := InputValue(r, "process")
-	appName := r.URL.Query().Get(":app")
-	a, err := getAppFromContext(appName, r)
+	for _, perm := range []*permission.PermissionScheme{permission.PermAppRead, permission.PermAppReadEnv, permission.PermAppReadPrivateEnv, permission.PermAppReadCertificate} {
+		if !permission.Check(t, perm, contextsForApp(&a)...) {
+			return permission.ErrUnauthorized
+		}
+	}
+	bundle, err := a.Export(r.Context())
 	if err != nil {
 		return err
 	}
-	allowed := permission.Check(t, permission.PermAppUpdateUnitRemove,
-		contextsForApp(&a)...,
-	)
-	if !allowed {
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(bundle)
+}
+
+// title: app import
+// path: /apps/import
+// method: POST
+// consume: application/json
+// produce: application/x-json-stream
+// responses:
+//   200: App imported
+//   400: Invalid data
+//   401: Unauthorized
+//   409: App already exists
+func importApp(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	ctx := r.Context()
+	var bundle app.Bundle
+	err = ParseInput(r, &bundle)
+	if err != nil {
+		return err
+	}
+	if bundle.App.Name == "" {
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: "app name is required"}
+	}
+	if bundle.Version != app.BundleVersion {
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: fmt.Sprintf("unsupported bundle version %d", bundle.Version)}
+	}
+	if bundle.App.TeamOwner == "" {
+		bundle.App.TeamOwner, err = autoTeamOwner(ctx, t, permission.PermAppCreate)
+		if err != nil {
+			return err
+		}
+	}
+	if !canCreateApp(t, bundle.App.TeamOwner, bundle.App.Pool) {
 		return permission.ErrUnauthorized
 	}
+	for _, bundleSI := range bundle.ServiceInstances {
+		instance, errGet := service.GetServiceInstance(ctx, bundleSI.Service, bundleSI.Instance)
+		if errGet == service.ErrServiceInstanceNotFound {
+			continue
+		}
+		if errGet != nil {
+			return errGet
+		}
+		allowed := permission.Check(t, permission.PermServiceInstanceUpdateBind,
+			contextsForServiceInstance(instance, bundleSI.Service)...,
+		)
+		if !allowed {
+			return permission.ErrUnauthorized
+		}
+	}
+	for _, bundleVolume := range bundle.Volumes {
+		v, errGet := servicemanager.Volume.Get(ctx, bundleVolume.Name)
+		var allowed bool
+		switch errGet {
+		case nil:
+			allowed = permission.Check(t, permission.PermVolumeUpdateBind, contextsForVolume(v)...)
+		case volumeTypes.ErrVolumeNotFound:
+			allowed = permission.Check(t, permission.PermVolumeCreate,
+				permission.Context(permTypes.CtxTeam, bundleVolume.TeamOwner),
+				permission.Context(permTypes.CtxPool, bundleVolume.Pool),
+			)
+		default:
+			return errGet
+		}
+		if !allowed {
+			return permission.ErrUnauthorized
+		}
+	}
+	u, err := auth.ConvertNewUser(t.User())
+	if err != nil {
+		return err
+	}
 	evt, err := event.New(&event.Opts{
-		Target:        appTarget(appName),
-		Kind:          permission.PermAppUpdateUnitRemove,
-		Owner:         t,
-		RemoteAddr:    r.RemoteAddr,
-		CustomData:    event.FormToCustomData(InputFields(r)),
-		Allowed:       event.Allowed(permission.PermAppReadEvents, contextsForApp(&a)...),
-		AllowedCancel: event.Allowed(permission.PermAppUpdateEvents, contextsForApp(&a)...),
-		Cancelable:    true,
+		Target:     appTarget(bundle.App.Name),
+		Kind:       permission.PermAppCreate,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		CustomData: map[string]interface{}{"import": true},
+		Allowed: event.Allowed(permission.PermAppReadEvents, append(
+			contextsForNewApp(bundle.App.TeamOwner, bundle.App.Pool),
+			permission.Context(permTypes.CtxApp, bundle.App.Name),
+		)...),
 	})
 	if err != nil {
 		return err
 	}
 	defer func() { evt.Done(err) }()
-	ctx, cancel := evt.CancelableContext(a.Context())
-	defer cancel()
-	a.ReplaceContext(ctx)
-	w.Header().Set("Content-Type", "application/x-json-stream")
 	keepAliveWriter := tsuruIo.NewKeepAliveWriter(w, 30*time.Second, "")
 	defer keepAliveWriter.Stop()
+	w.Header().Set("Content-Type", "application/x-json-stream")
 	writer := &tsuruIo.SimpleJsonMessageEncoderWriter{Encoder: json.NewEncoder(keepAliveWriter)}
 	evt.SetLogWriter(writer)
-	return a.RemoveUnits(ctx, n, processName, version, evt)
+	_, err = app.ImportBundle(ctx, &bundle, u, evt, requestIDHeader(r), evt)
+	if e, ok := err.(*appTypes.AppCreationError); ok && e.Err == app.ErrAppAlreadyExists {
+		return &errors.HTTP{Code: http.StatusConflict, Message: e.Error()}
+	}
+	return err
 }
 
-// title: set unit status
-// path: /apps/{app}/units/{unit}
-// method: POST
+// title: set app extra pool
+// path: /apps/{app}/pools/{pool}
+// method: PUT
 // consume: application/x-www-form-urlencoded
+// produce: application/x-json-stream
 // responses:
-//   200: Ok
+//   200: Extra pool set
 //   400: Invalid data
 //   401: Unauthorized
-//   404: App or unit not found
-func setUnitStatus(w http.ResponseWriter, r *http.Request, t auth.Token) error {
-	ctx := r.Context()
-	unitName := r.URL.Query().Get(":unit")
-	if unitName == "" {
-		return &errors.HTTP{
-			Code:    http.StatusBadRequest,
-			Message: "missing unit",
-		}
-	}
-	postStatus := InputValue(r, "status")
-	status, err := provision.ParseStatus(postStatus)
+//   404: App or pool not found
+func setAppExtraPool(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	appName := r.URL.Query().Get(":app")
+	poolName := r.URL.Query().Get(":pool")
+	units, err := strconv.ParseUint(InputValue(r, "units"), 10, 32)
 	if err != nil {
-		return &errors.HTTP{
-			Code:    http.StatusBadRequest,
-			Message: err.Error(),
-		}
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: "Invalid number of units: the number must be an integer greater than 0."}
 	}
-	appName := r.URL.Query().Get(":app")
-	a, err := app.GetByName(ctx, appName)
+	a, err := getAppFromContext(appName, r)
 	if err != nil {
-		return &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+		return err
 	}
-	allowed := permission.Check(t, permission.PermAppUpdateUnitStatus,
-		contextsForApp(a)...,
+	allowed := permission.Check(t, permission.PermAppUpdatePool,
+		append(contextsForApp(&a), permission.Context(permTypes.CtxPool, poolName))...,
 	)
 	if !allowed {
 		return permission.ErrUnauthorized
 	}
-	err = a.SetUnitStatus(unitName, status)
-	if _, ok := err.(*provision.UnitNotFoundError); ok {
-		return &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+	evt, err := event.New(&event.Opts{
+		Target:     appTarget(appName),
+		Kind:       permission.PermAppUpdatePool,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		CustomData: event.FormToCustomData(InputFields(r)),
+		Allowed:    event.Allowed(permission.PermAppReadEvents, contextsForApp(&a)...),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	keepAliveWriter := tsuruIo.NewKeepAliveWriter(w, 30*time.Second, "")
+	defer keepAliveWriter.Stop()
+	w.Header().Set("Content-Type", "application/x-json-stream")
+	writer := &tsuruIo.SimpleJsonMessageEncoderWriter{Encoder: json.NewEncoder(keepAliveWriter)}
+	evt.SetLogWriter(writer)
+	err = a.SetExtraPool(r.Context(), app.ExtraPool{Name: poolName, Units: uint(units)}, evt)
+	if v, ok := err.(*errors.ValidationError); ok {
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: v.Message}
+	}
+	if err == pool.ErrPoolNotFound {
+		return &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+	}
+	return err
+}
+
+// title: remove app extra pool
+// path: /apps/{app}/pools/{pool}
+// method: DELETE
+// produce: application/x-json-stream
+// responses:
+//   200: Extra pool removed
+//   401: Unauthorized
+//   404: App or extra pool not found
+func removeAppExtraPool(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	appName := r.URL.Query().Get(":app")
+	poolName := r.URL.Query().Get(":pool")
+	a, err := getAppFromContext(appName, r)
+	if err != nil {
+		return err
+	}
+	allowed := permission.Check(t, permission.PermAppUpdatePool,
+		append(contextsForApp(&a), permission.Context(permTypes.CtxPool, poolName))...,
+	)
+	if !allowed {
+		return permission.ErrUnauthorized
+	}
+	evt, err := event.New(&event.Opts{
+		Target:     appTarget(appName),
+		Kind:       permission.PermAppUpdatePool,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		CustomData: event.FormToCustomData(InputFields(r)),
+		Allowed:    event.Allowed(permission.PermAppReadEvents, contextsForApp(&a)...),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	keepAliveWriter := tsuruIo.NewKeepAliveWriter(w, 30*time.Second, "")
+	defer keepAliveWriter.Stop()
+	w.Header().Set("Content-Type", "application/x-json-stream")
+	writer := &tsuruIo.SimpleJsonMessageEncoderWriter{Encoder: json.NewEncoder(keepAliveWriter)}
+	evt.SetLogWriter(writer)
+	err = a.RemoveExtraPool(r.Context(), poolName, evt)
+	if err == app.ErrExtraPoolNotFound {
+		return &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+	}
+	return err
+}
+
+// title: set app maintenance
+// path: /apps/{app}/maintenance
+// method: POST
+// consume: application/x-www-form-urlencoded
+// produce: application/x-json-stream
+// responses:
+//   200: Maintenance mode enabled
+//   400: Invalid data
+//   401: Unauthorized
+//   404: App not found
+func setAppMaintenance(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	appName := r.URL.Query().Get(":app")
+	m := app.Maintenance{Page: InputValue(r, "page")}
+	if retryAfter := InputValue(r, "retry-after"); retryAfter != "" {
+		m.RetryAfter, err = strconv.Atoi(retryAfter)
+		if err != nil {
+			return &errors.HTTP{Code: http.StatusBadRequest, Message: "Invalid retry-after: the value must be a number of seconds."}
+		}
+	}
+	a, err := getAppFromContext(appName, r)
+	if err != nil {
+		return err
+	}
+	allowed := permission.Check(t, permission.PermAppUpdateMaintenance, contextsForApp(&a)...)
+	if !allowed {
+		return permission.ErrUnauthorized
+	}
+	evt, err := event.New(&event.Opts{
+		Target:     appTarget(appName),
+		Kind:       permission.PermAppUpdateMaintenance,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		CustomData: event.FormToCustomData(InputFields(r)),
+		Allowed:    event.Allowed(permission.PermAppReadEvents, contextsForApp(&a)...),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	keepAliveWriter := tsuruIo.NewKeepAliveWriter(w, 30*time.Second, "")
+	defer keepAliveWriter.Stop()
+	w.Header().Set("Content-Type", "application/x-json-stream")
+	writer := &tsuruIo.SimpleJsonMessageEncoderWriter{Encoder: json.NewEncoder(keepAliveWriter)}
+	evt.SetLogWriter(writer)
+	err = a.SetMaintenance(r.Context(), m, evt)
+	if v, ok := err.(*errors.ValidationError); ok {
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: v.Message}
+	}
+	return err
+}
+
+// title: unset app maintenance
+// path: /apps/{app}/maintenance
+// method: DELETE
+// produce: application/x-json-stream
+// responses:
+//   200: Maintenance mode disabled
+//   400: App not in maintenance
+//   401: Unauthorized
+//   404: App not found
+func unsetAppMaintenance(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	appName := r.URL.Query().Get(":app")
+	a, err := getAppFromContext(appName, r)
+	if err != nil {
+		return err
+	}
+	allowed := permission.Check(t, permission.PermAppUpdateMaintenance, contextsForApp(&a)...)
+	if !allowed {
+		return permission.ErrUnauthorized
+	}
+	if a.Maintenance == nil {
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: app.ErrAppNotInMaintenance.Error()}
+	}
+	evt, err := event.New(&event.Opts{
+		Target:     appTarget(appName),
+		Kind:       permission.PermAppUpdateMaintenance,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		CustomData: event.FormToCustomData(InputFields(r)),
+		Allowed:    event.Allowed(permission.PermAppReadEvents, contextsForApp(&a)...),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	keepAliveWriter := tsuruIo.NewKeepAliveWriter(w, 30*time.Second, "")
+	defer keepAliveWriter.Stop()
+	w.Header().Set("Content-Type", "application/x-json-stream")
+	writer := &tsuruIo.SimpleJsonMessageEncoderWriter{Encoder: json.NewEncoder(keepAliveWriter)}
+	evt.SetLogWriter(writer)
+	return a.UnsetMaintenance(r.Context(), evt)
+}
+
+// title: freeze app
+// path: /apps/{app}/freeze
+// method: POST
+// consume: application/x-www-form-urlencoded
+// produce: application/json
+// responses:
+//   200: App frozen
+//   400: Invalid data
+//   401: Unauthorized
+//   404: App not found
+func freezeApp(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	appName := r.URL.Query().Get(":app")
+	a, err := getAppFromContext(appName, r)
+	if err != nil {
+		return err
+	}
+	allowed := permission.Check(t, permission.PermAppUpdateFreeze, contextsForApp(&a)...)
+	if !allowed {
+		return permission.ErrUnauthorized
+	}
+	var expiresAt time.Time
+	if expiresIn := InputValue(r, "expires-in"); expiresIn != "" {
+		duration, parseErr := time.ParseDuration(expiresIn)
+		if parseErr != nil || duration <= 0 {
+			return &errors.HTTP{Code: http.StatusBadRequest, Message: fmt.Sprintf("invalid expires-in duration: %q", expiresIn)}
+		}
+		expiresAt = time.Now().Add(duration)
+	}
+	evt, err := event.New(&event.Opts{
+		Target:     appTarget(appName),
+		Kind:       permission.PermAppUpdateFreeze,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		CustomData: event.FormToCustomData(InputFields(r)),
+		Allowed:    event.Allowed(permission.PermAppReadEvents, contextsForApp(&a)...),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	block, err := a.Freeze(InputValue(r, "reason"), expiresAt)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(block)
+}
+
+// title: unfreeze app
+// path: /apps/{app}/freeze
+// method: DELETE
+// responses:
+//   200: App unfrozen
+//   400: App not frozen
+//   401: Unauthorized
+//   404: App not found
+func unfreezeApp(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	appName := r.URL.Query().Get(":app")
+	a, err := getAppFromContext(appName, r)
+	if err != nil {
+		return err
+	}
+	allowed := permission.Check(t, permission.PermAppUpdateFreeze, contextsForApp(&a)...)
+	if !allowed {
+		return permission.ErrUnauthorized
+	}
+	evt, err := event.New(&event.Opts{
+		Target:     appTarget(appName),
+		Kind:       permission.PermAppUpdateFreeze,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		CustomData: event.FormToCustomData(InputFields(r)),
+		Allowed:    event.Allowed(permission.PermAppReadEvents, contextsForApp(&a)...),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	err = a.Unfreeze()
+	if err == app.ErrAppNotFrozen {
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: err.Error()}
+	}
+	return err
+}
+
+func numberOfUnits(r *http.Request) (uint, error) {
+	unitsStr := InputValue(r, "units")
+	if unitsStr == "" {
+		return 0, &errors.HTTP{
+			Code:    http.StatusBadRequest,
+			Message: "You must provide the number of units.",
+		}
+	}
+	n, err := strconv.ParseUint(unitsStr, 10, 32)
+	if err != nil || n == 0 {
+		return 0, &errors.HTTP{
+			Code:    http.StatusBadRequest,
+			Message: "Invalid number of units: the number must be an integer greater than 0.",
+		}
+	}
+	return uint(n), nil
+}
+
+// title: add units
+// path: /apps/{name}/units
+// method: PUT
+// consume: application/x-www-form-urlencoded
+// produce: application/x-json-stream
+// responses:
+//   200: Units added
+//   400: Invalid data
+//   401: Unauthorized
+//   404: App not found
+func addUnits(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	dryRun, err := isDryRun(r)
+	if err != nil {
+		return err
+	}
+	n, err := numberOfUnits(r)
+	if err != nil {
+		return err
+	}
+	processName := InputValue(r, "process")
+	version := InputValue(r, "version")
+	appName := r.URL.Query().Get(":app")
+	a, err := getAppFromContext(appName, r)
+	if err != nil {
+		return err
+	}
+	allowed := permission.Check(t, permission.PermAppUpdateUnitAdd,
+		contextsForApp(&a)...,
+	)
+	if !allowed {
+		return permission.ErrUnauthorized
+	}
+	if dryRun {
+		units, errValidate := a.ValidateAddUnits(n, processName, version)
+		if errValidate != nil {
+			return unitsDryRunError(errValidate)
+		}
+		return writeDryRunUnits(w, processName, units)
+	}
+	evt, err := event.New(&event.Opts{
+		Target:        appTarget(appName),
+		Kind:          permission.PermAppUpdateUnitAdd,
+		Owner:         t,
+		RemoteAddr:    r.RemoteAddr,
+		CustomData:    event.FormToCustomData(InputFields(r)),
+		Allowed:       event.Allowed(permission.PermAppReadEvents, contextsForApp(&a)...),
+		AllowedCancel: event.Allowed(permission.PermAppUpdateEvents, contextsForApp(&a)...),
+		Cancelable:    true,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	ctx, cancel := evt.CancelableContext(a.Context())
+	defer cancel()
+	a.ReplaceContext(ctx)
+	w.Header().Set("Content-Type", "application/x-json-stream")
+	keepAliveWriter := tsuruIo.NewKeepAliveWriter(w, 30*time.Second, "")
+	defer keepAliveWriter.Stop()
+	writer := &tsuruIo.SimpleJsonMessageEncoderWriter{Encoder: json.NewEncoder(keepAliveWriter)}
+	evt.SetLogWriter(writer)
+	return a.AddUnits(n, processName, version, evt)
+}
+
+// title: remove units
+// path: /apps/{name}/units
+// method: DELETE
+// produce: application/x-json-stream
+// responses:
+//   200: Units removed
+//   400: Invalid data
+//   401: Unauthorized
+//   403: Not enough reserved units
+//   404: App not found
+func removeUnits(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	dryRun, err := isDryRun(r)
+	if err != nil {
+		return err
+	}
+	n, err := numberOfUnits(r)
+	if err != nil {
+		return err
+	}
+	version := InputValue(r, "version")
+	processName := InputValue(r, "process")
+	appName := r.URL.Query().Get(":app")
+	a, err := getAppFromContext(appName, r)
+	if err != nil {
+		return err
+	}
+	allowed := permission.Check(t, permission.PermAppUpdateUnitRemove,
+		contextsForApp(&a)...,
+	)
+	if !allowed {
+		return permission.ErrUnauthorized
+	}
+	if dryRun {
+		units, errValidate := a.ValidateRemoveUnits(r.Context(), n, processName, version)
+		if errValidate != nil {
+			return unitsDryRunError(errValidate)
+		}
+		return writeDryRunUnits(w, processName, units)
+	}
+	evt, err := event.New(&event.Opts{
+		Target:        appTarget(appName),
+		Kind:          permission.PermAppUpdateUnitRemove,
+		Owner:         t,
+		RemoteAddr:    r.RemoteAddr,
+		CustomData:    event.FormToCustomData(InputFields(r)),
+		Allowed:       event.Allowed(permission.PermAppReadEvents, contextsForApp(&a)...),
+		AllowedCancel: event.Allowed(permission.PermAppUpdateEvents, contextsForApp(&a)...),
+		Cancelable:    true,
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	ctx, cancel := evt.CancelableContext(a.Context())
+	defer cancel()
+	a.ReplaceContext(ctx)
+	w.Header().Set("Content-Type", "application/x-json-stream")
+	keepAliveWriter := tsuruIo.NewKeepAliveWriter(w, 30*time.Second, "")
+	defer keepAliveWriter.Stop()
+	writer := &tsuruIo.SimpleJsonMessageEncoderWriter{Encoder: json.NewEncoder(keepAliveWriter)}
+	evt.SetLogWriter(writer)
+	return a.RemoveUnits(ctx, n, processName, version, evt)
+}
+
+// unitsDryRunError converts errors of dry runs of adding or removing units to
+// their HTTP errors.
+func unitsDryRunError(err error) error {
+	if _, ok := pkgErrors.Cause(err).(*quota.QuotaExceededError); ok {
+		return &errors.HTTP{Code: http.StatusForbidden, Message: err.Error(), ErrorCode: "app.quota.exceeded"}
+	}
+	return &errors.HTTP{Code: http.StatusBadRequest, Message: err.Error()}
+}
+
+// title: set unit status
+// path: /apps/{app}/units/{unit}
+// method: POST
+// consume: application/x-www-form-urlencoded
+// responses:
+//   200: Ok
+//   400: Invalid data
+//   401: Unauthorized
+//   404: App or unit not found
+func setUnitStatus(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	ctx := r.Context()
+	unitName := r.URL.Query().Get(":unit")
+	if unitName == "" {
+		return &errors.HTTP{
+			Code:    http.StatusBadRequest,
+			Message: "missing unit",
+		}
+	}
+	postStatus := InputValue(r, "status")
+	status, err := provision.ParseStatus(postStatus)
+	if err != nil {
+		return &errors.HTTP{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+		}
+	}
+	appName := r.URL.Query().Get(":app")
+	a, err := app.GetByName(ctx, appName)
+	if err != nil {
+		return &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+	}
+	allowed := permission.Check(t, permission.PermAppUpdateUnitStatus,
+		contextsForApp(a)...,
+	)
+	if !allowed {
+		return permission.ErrUnauthorized
+	}
+	err = a.SetUnitStatus(unitName, status)
+	if _, ok := err.(*provision.UnitNotFoundError); ok {
+		return &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+	}
+	return err
+}
+
+// title: kill a running unit
+// path: /apps/{app}/units/{unit}
+// method: DELETE
+// consume: application/x-www-form-urlencoded
+// responses:
+//   200: Ok
+//   400: Invalid data
+//   401: Unauthorized
+//   404: App or unit not found
+func killUnit(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	ctx := r.Context()
+	unitName := r.URL.Query().Get(":unit")
+	if unitName == "" {
+		return &errors.HTTP{
+			Code:    http.StatusBadRequest,
+			Message: "missing unit",
+		}
+	}
+	appName := r.URL.Query().Get(":app")
+	a, err := app.GetByName(ctx, appName)
+	if err != nil {
+		return &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+	}
+	force, _ := strconv.ParseBool(InputValue(r, "force"))
+	allowed := permission.Check(t, permission.PermAppUpdateUnitKill,
+		contextsForApp(a)...,
+	)
+	if !allowed {
+		return permission.ErrUnauthorized
+	}
+
+	evt, err := event.New(&event.Opts{
+		Target:     appTarget(a.Name),
+		Kind:       permission.PermAppUpdateUnitKill,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		CustomData: []map[string]interface{}{
+			{
+				"unit":  unitName,
+				"force": force,
+			},
+		},
+		Allowed: event.Allowed(permission.PermAppReadEvents, contextsForApp(a)...),
+	})
+	if err != nil {
+		return err
+	}
+
+	defer func() { evt.Done(err) }()
+
+	err = a.KillUnit(unitName, force)
+	if _, ok := err.(*provision.UnitNotFoundError); ok {
+		return &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+	}
+	return err
+}
+
+// title: units metrics
+// path: /apps/{app}/units/metrics
+// method: GET
+// produce: application/json
+// responses:
+//   200: Ok
+//   401: Unauthorized
+//   404: App not found
+func unitsMetrics(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	appName := r.URL.Query().Get(":app")
+	a, err := getAppFromContext(appName, r)
+	if err != nil {
+		return err
+	}
+	canRead := permission.Check(t, permission.PermAppRead,
+		contextsForApp(&a)...,
+	)
+	if !canRead {
+		return permission.ErrUnauthorized
+	}
+	metrics, err := a.UnitsMetrics()
+	if err != nil {
+		return err
+	}
+	if metrics == nil {
+		metrics = []provision.UnitMetric{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(metrics)
+}
+
+// title: app resource recommendations
+// path: /apps/{app}/resources/recommendations
+// method: GET
+// produce: application/json
+// responses:
+//   200: Ok
+//   401: Unauthorized
+//   404: App not found
+func appResourceRecommendations(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	appName := r.URL.Query().Get(":app")
+	a, err := getAppFromContext(appName, r)
+	if err != nil {
+		return err
+	}
+	canRead := permission.Check(t, permission.PermAppRead,
+		contextsForApp(&a)...,
+	)
+	if !canRead {
+		return permission.ErrUnauthorized
+	}
+	recommendations, err := a.ResourceRecommendations()
+	if err != nil {
+		return err
+	}
+	if recommendations == nil {
+		recommendations = []app.ResourceRecommendation{}
 	}
-	return err
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(recommendations)
 }
 
-// title: kill a running unit
-// path: /apps/{app}/units/{unit}
-// method: DELETE
-// consume: application/x-www-form-urlencoded
+// title: app timeline
+// path: /apps/{app}/timeline
+// method: GET
+// produce: application/json
 // responses:
-//   200: Ok
+//   200: OK
+//   204: No content
 //   400: Invalid data
 //   401: Unauthorized
-//   404: App or unit not found
-func killUnit(w http.ResponseWriter, r *http.Request, t auth.Token) error {
-	ctx := r.Context()
-	unitName := r.URL.Query().Get(":unit")
-	if unitName == "" {
-		return &errors.HTTP{
-			Code:    http.StatusBadRequest,
-			Message: "missing unit",
-		}
-	}
+//   404: App not found
+func appTimeline(w http.ResponseWriter, r *http.Request, t auth.Token) error {
 	appName := r.URL.Query().Get(":app")
-	a, err := app.GetByName(ctx, appName)
+	a, err := getAppFromContext(appName, r)
 	if err != nil {
-		return &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+		return err
 	}
-	force, _ := strconv.ParseBool(InputValue(r, "force"))
-	allowed := permission.Check(t, permission.PermAppUpdateUnitKill,
-		contextsForApp(a)...,
+	canRead := permission.Check(t, permission.PermAppReadEvents,
+		contextsForApp(&a)...,
 	)
-	if !allowed {
+	if !canRead {
 		return permission.ErrUnauthorized
 	}
-
-	evt, err := event.New(&event.Opts{
-		Target:     appTarget(a.Name),
-		Kind:       permission.PermAppUpdateUnitKill,
-		Owner:      t,
-		RemoteAddr: r.RemoteAddr,
-		CustomData: []map[string]interface{}{
-			{
-				"unit":  unitName,
-				"force": force,
-			},
-		},
-		Allowed: event.Allowed(permission.PermAppReadEvents, contextsForApp(a)...),
-	})
+	query := r.URL.Query()
+	var opts app.TimelineOpts
+	for _, category := range query["category"] {
+		opts.Categories = append(opts.Categories, app.TimelineCategory(category))
+	}
+	if limit := query.Get("limit"); limit != "" {
+		opts.Limit, err = strconv.Atoi(limit)
+		if err != nil {
+			return &errors.HTTP{Code: http.StatusBadRequest, Message: "limit must be an integer"}
+		}
+	}
+	for param, dst := range map[string]*time.Time{"since": &opts.Since, "until": &opts.Until} {
+		value := query.Get(param)
+		if value == "" {
+			continue
+		}
+		*dst, err = time.Parse(time.RFC3339, value)
+		if err != nil {
+			return &errors.HTTP{Code: http.StatusBadRequest, Message: fmt.Sprintf("%s must be a RFC3339 date", param)}
+		}
+	}
+	opts.After = query.Get("after")
+	opts.Permissions, err = t.Permissions()
 	if err != nil {
 		return err
 	}
-
-	defer func() { evt.Done(err) }()
-
-	err = a.KillUnit(unitName, force)
-	if _, ok := err.(*provision.UnitNotFoundError); ok {
-		return &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+	entries, err := a.Timeline(&opts)
+	if err != nil {
+		if _, ok := err.(event.ErrValidation); ok {
+			return &errors.HTTP{Code: http.StatusBadRequest, Message: err.Error()}
+		}
+		return err
 	}
-	return err
+	if len(entries) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+	for _, entry := range entries {
+		err = event.Redact(entry.Event)
+		if err != nil {
+			return err
+		}
+	}
+	if len(entries) == opts.Limit {
+		w.Header().Set("Link", eventListNextLink(r, entries[len(entries)-1].Event))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(entries)
 }
 
 // title: set node status
@@ -996,6 +1776,7 @@ func revokeAppAccess(w http.ResponseWriter, r *http.Request, t auth.Token) (err
 // method: POST
 // responses:
 //   200: Ok
+//   202: Detached run started
 //   401: Unauthorized
 //   404: App not found
 func runCommand(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
@@ -1007,6 +1788,7 @@ func runCommand(w http.ResponseWriter, r *http.Request, t auth.Token) (err error
 	appName := r.URL.Query().Get(":app")
 	once := InputValue(r, "once")
 	isolated := InputValue(r, "isolated")
+	detached := InputValue(r, "detached")
 	a, err := getAppFromContext(appName, r)
 	if err != nil {
 		return err
@@ -1028,18 +1810,57 @@ func runCommand(w http.ResponseWriter, r *http.Request, t auth.Token) (err error
 	if err != nil {
 		return err
 	}
-	defer func() { evt.Done(err) }()
+	onceBool, _ := strconv.ParseBool(once)
+	isolatedBool, _ := strconv.ParseBool(isolated)
+	args := provision.RunArgs{Once: onceBool, Isolated: isolatedBool}
+	if detachedBool, _ := strconv.ParseBool(detached); detachedBool {
+		err = a.RunDetached(command, evt, args)
+		if err != nil {
+			return err
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		return json.NewEncoder(w).Encode(map[string]string{"id": evt.UniqueID.Hex()})
+	}
+	defer func() { app.RunDone(evt, err) }()
 	w.Header().Set("Content-Type", "application/x-json-stream")
 	keepAliveWriter := tsuruIo.NewKeepAliveWriter(w, 30*time.Second, "")
 	defer keepAliveWriter.Stop()
 	writer := &tsuruIo.SimpleJsonMessageEncoderWriter{Encoder: json.NewEncoder(keepAliveWriter)}
 	evt.SetLogWriter(writer)
-	onceBool, _ := strconv.ParseBool(once)
-	isolatedBool, _ := strconv.ParseBool(isolated)
-	args := provision.RunArgs{Once: onceBool, Isolated: isolatedBool}
 	return a.Run(command, evt, args)
 }
 
+// title: run info
+// path: /apps/{app}/runs/{id}
+// method: GET
+// produce: application/json
+// responses:
+//   200: OK
+//   401: Unauthorized
+//   404: Run not found
+func runInfo(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	a, err := getAppFromContext(r.URL.Query().Get(":app"), r)
+	if err != nil {
+		return err
+	}
+	allowed := permission.Check(t, permission.PermAppRun,
+		contextsForApp(&a)...,
+	)
+	if !allowed {
+		return permission.ErrUnauthorized
+	}
+	result, err := a.GetRun(r.URL.Query().Get(":id"))
+	if err == app.ErrRunNotFound {
+		return &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+	}
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(result)
+}
+
 // title: get envs
 // path: /apps/{app}/env
 // method: GET
@@ -1065,6 +1886,9 @@ func getEnv(w http.ResponseWriter, r *http.Request, t auth.Token) error {
 		if !allowed {
 			return permission.ErrUnauthorized
 		}
+		if !permission.Check(t, permission.PermAppReadPrivateEnv, contextsForApp(&a)...) {
+			a.SuppressSensitiveEnvs()
+		}
 	}
 	return writeEnvVars(w, &a, variables...)
 }
@@ -1097,6 +1921,10 @@ func writeEnvVars(w http.ResponseWriter, a *app.App, variables ...string) error
 //   401: Unauthorized
 //   404: App not found
 func setEnv(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	dryRun, err := isDryRun(r)
+	if err != nil {
+		return err
+	}
 	var e apiTypes.Envs
 	err = ParseInput(r, &e)
 	if err != nil {
@@ -1128,25 +1956,6 @@ func setEnv(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
 		return permission.ErrUnauthorized
 	}
 
-	var toExclude []string
-	for i := 0; i < len(e.Envs); i++ {
-		if (e.Envs[i].Private != nil && *e.Envs[i].Private) || e.Private {
-			toExclude = append(toExclude, fmt.Sprintf("Envs.%d.Value", i))
-		}
-	}
-
-	evt, err := event.New(&event.Opts{
-		Target:     appTarget(appName),
-		Kind:       permission.PermAppUpdateEnvSet,
-		Owner:      t,
-		RemoteAddr: r.RemoteAddr,
-		CustomData: event.FormToCustomData(InputFields(r, toExclude...)),
-		Allowed:    event.Allowed(permission.PermAppReadEvents, contextsForApp(&a)...),
-	})
-	if err != nil {
-		return err
-	}
-	defer func() { evt.Done(err) }()
 	envs := map[string]string{}
 	variables := []bind.EnvVar{}
 	for _, v := range e.Envs {
@@ -1165,8 +1974,53 @@ func setEnv(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
 			Public:    !private,
 			Alias:     v.Alias,
 			ManagedBy: e.ManagedBy,
+			SecretRef: v.SecretRef,
 		})
 	}
+	if dryRun {
+		err = a.SetEnvs(bind.SetEnvArgs{
+			Envs:        variables,
+			ManagedBy:   e.ManagedBy,
+			PruneUnused: e.PruneUnused,
+			DryRun:      true,
+		})
+		if v, ok := err.(*errors.ValidationError); ok {
+			return &errors.HTTP{Code: http.StatusBadRequest, Message: v.Message}
+		}
+		if err != nil {
+			return err
+		}
+		// the response only includes the variables of the request for
+		// users not allowed to read the app envs.
+		var names []string
+		if !permission.Check(t, permission.PermAppReadEnv, contextsForApp(&a)...) {
+			for _, v := range variables {
+				names = append(names, v.Name)
+			}
+		}
+		a.SuppressSensitiveEnvs()
+		return writeEnvVars(w, &a, names...)
+	}
+
+	var toExclude []string
+	for i := 0; i < len(e.Envs); i++ {
+		if (e.Envs[i].Private != nil && *e.Envs[i].Private) || e.Private {
+			toExclude = append(toExclude, fmt.Sprintf("Envs.%d.Value", i))
+		}
+	}
+
+	evt, err := event.New(&event.Opts{
+		Target:     appTarget(appName),
+		Kind:       permission.PermAppUpdateEnvSet,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		CustomData: event.FormToCustomData(InputFields(r, toExclude...)),
+		Allowed:    event.Allowed(permission.PermAppReadEvents, contextsForApp(&a)...),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
 	w.Header().Set("Content-Type", "application/x-json-stream")
 	keepAliveWriter := tsuruIo.NewKeepAliveWriter(w, 30*time.Second, "")
 	defer keepAliveWriter.Stop()
@@ -1696,6 +2550,146 @@ func restart(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
 	return a.Restart(ctx, process, version, evt)
 }
 
+// title: unit restart
+// path: /apps/{app}/units/{unit}/restart
+// method: POST
+// produce: application/x-json-stream
+// responses:
+//   200: Ok
+//   401: Unauthorized
+//   404: App or unit not found
+func restartUnit(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	unitName := r.URL.Query().Get(":unit")
+	appName := r.URL.Query().Get(":app")
+	a, err := getAppFromContext(appName, r)
+	if err != nil {
+		return err
+	}
+	allowed := permission.Check(t, permission.PermAppUpdateRestart,
+		contextsForApp(&a)...,
+	)
+	if !allowed {
+		return permission.ErrUnauthorized
+	}
+	evt, err := event.New(&event.Opts{
+		Target:     appTarget(appName),
+		Kind:       permission.PermAppUpdateRestart,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		CustomData: []map[string]interface{}{
+			{"name": "unit", "value": unitName},
+		},
+		Allowed: event.Allowed(permission.PermAppReadEvents, contextsForApp(&a)...),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	w.Header().Set("Content-Type", "application/x-json-stream")
+	keepAliveWriter := tsuruIo.NewKeepAliveWriter(w, 30*time.Second, "")
+	defer keepAliveWriter.Stop()
+	writer := &tsuruIo.SimpleJsonMessageEncoderWriter{Encoder: json.NewEncoder(keepAliveWriter)}
+	evt.SetLogWriter(writer)
+	err = a.RestartUnit(r.Context(), unitName, evt)
+	if _, ok := err.(*provision.UnitNotFoundError); ok {
+		return &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+	}
+	return err
+}
+
+// title: unit debug
+// path: /apps/{app}/units/{unit}/debug
+// method: POST
+// produce: application/vnd.tsuru.raw-stream
+// responses:
+//   101: Switch protocol to raw stream
+//   401: Unauthorized
+//   404: App or unit not found
+func debugUnit(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	unitName := r.URL.Query().Get(":unit")
+	appName := r.URL.Query().Get(":app")
+	a, err := getAppFromContext(appName, r)
+	if err != nil {
+		return err
+	}
+	allowed := permission.Check(t, permission.PermAppRunShell,
+		contextsForApp(&a)...,
+	)
+	if !allowed {
+		return permission.ErrUnauthorized
+	}
+	units, err := a.Units()
+	if err != nil {
+		return err
+	}
+	var found bool
+	for _, u := range units {
+		if u.ID == unitName {
+			found = true
+			break
+		}
+	}
+	if !found {
+		err = &provision.UnitNotFoundError{ID: unitName}
+		return &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+	}
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		return &errors.HTTP{Code: http.StatusInternalServerError, Message: "cannot hijack connection"}
+	}
+	width, _ := strconv.Atoi(InputValue(r, "width"))
+	height, _ := strconv.Atoi(InputValue(r, "height"))
+	// A custom image runs any code next to the unit, so it's only used for
+	// users also allowed to deploy the app, others get the debug image
+	// configured in the cluster.
+	image := InputValue(r, "image")
+	if image != "" && !permission.Check(t, permission.PermAppDeploy, contextsForApp(&a)...) {
+		image = ""
+	}
+	evt, err := event.New(&event.Opts{
+		Target:     appTarget(appName),
+		Kind:       permission.PermAppRunShell,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		CustomData: []map[string]interface{}{
+			{"name": "unit", "value": unitName},
+			{"name": "debug", "value": true},
+			{"name": "image", "value": image},
+		},
+		Allowed:     event.Allowed(permission.PermAppReadEvents, contextsForApp(&a)...),
+		DisableLock: true,
+	})
+	if err != nil {
+		return err
+	}
+	shellSessions.add()
+	defer shellSessions.done()
+	conn, _, err := hijacker.Hijack()
+	if err != nil {
+		evt.Done(err)
+		return err
+	}
+	defer conn.Close()
+	fmt.Fprint(conn, "HTTP/1.1 101 UPGRADED\r\nContent-Type: application/vnd.tsuru.raw-stream\r\nConnection: Upgrade\r\nUpgrade: tcp\r\n\r\n")
+	err = a.DebugUnit(r.Context(), provision.DebugOptions{
+		Unit:   unitName,
+		Image:  image,
+		Stdin:  conn,
+		Stdout: conn,
+		Stderr: conn,
+		Width:  width,
+		Height: height,
+		Term:   InputValue(r, "term"),
+	})
+	if err != nil {
+		fmt.Fprintf(conn, "Error: %v\n", err)
+	}
+	// The connection is hijacked, errors are only reported to the client
+	// through the stream and recorded in the event.
+	evt.Done(err)
+	return nil
+}
+
 // title: app sleep
 // path: /apps/{app}/sleep
 // method: POST
@@ -1805,6 +2799,8 @@ func swap(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
 	app2Name := InputValue(r, "app2")
 	forceSwap := InputValue(r, "force")
 	cnameOnly, _ := strconv.ParseBool(InputValue(r, "cnameOnly"))
+	addressOnly, _ := strconv.ParseBool(InputValue(r, "addressOnly"))
+	cnames, _ := InputValues(r, "cname")
 	if forceSwap == "" {
 		forceSwap = "false"
 	}
@@ -1866,7 +2862,15 @@ func swap(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
 			}
 		}
 	}
-	return app.Swap(ctx, app1, app2, cnameOnly)
+	err = app.SwapWithOptions(ctx, app1, app2, app.SwapOptions{
+		CNameOnly:   cnameOnly,
+		CNames:      cnames,
+		AddressOnly: addressOnly,
+	})
+	if v, ok := err.(*errors.ValidationError); ok {
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: v.Message}
+	}
+	return err
 }
 
 // title: app start
@@ -2250,6 +3254,25 @@ func listCertificates(w http.ResponseWriter, r *http.Request, t auth.Token) erro
 	return json.NewEncoder(w).Encode(&result)
 }
 
+// canCreateApp checks that the token may create apps owned by the team and,
+// when set by the user, in the pool.
+func canCreateApp(t auth.Token, teamOwner, pool string) bool {
+	if !permission.Check(t, permission.PermAppCreate, permission.Context(permTypes.CtxTeam, teamOwner)) {
+		return false
+	}
+	return pool == "" || permission.Check(t, permission.PermAppCreate, permission.Context(permTypes.CtxPool, pool))
+}
+
+// contextsForNewApp returns the contexts of an app being created, the pool
+// is only known when set by the user.
+func contextsForNewApp(teamOwner, pool string) []permTypes.PermissionContext {
+	contexts := []permTypes.PermissionContext{permission.Context(permTypes.CtxTeam, teamOwner)}
+	if pool != "" {
+		contexts = append(contexts, permission.Context(permTypes.CtxPool, pool))
+	}
+	return contexts
+}
+
 func contextsForApp(a *app.App) []permTypes.PermissionContext {
 	return append(permission.Contexts(permTypes.CtxTeam, a.Teams),
 		permission.Context(permTypes.CtxApp, a.Name),
@@ -10,6 +10,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -37,6 +38,7 @@ import (
 	"github.com/tsuru/tsuru/provision/nodecontainer"
 	"github.com/tsuru/tsuru/provision/pool"
 	"github.com/tsuru/tsuru/provision/provisiontest"
+	"github.com/tsuru/tsuru/router"
 	"github.com/tsuru/tsuru/router/rebuild"
 	"github.com/tsuru/tsuru/router/routertest"
 	"github.com/tsuru/tsuru/service"
@@ -49,6 +51,7 @@ import (
 	permTypes "github.com/tsuru/tsuru/types/permission"
 	"github.com/tsuru/tsuru/types/quota"
 	check "gopkg.in/check.v1"
+	apiv1 "k8s.io/api/core/v1"
 )
 
 func (s *S) setupMockForCreateApp(c *check.C, platName string) {
@@ -205,6 +208,39 @@ func (s *S) TestAppListFilteringByTags(c *check.C) {
 	c.Assert(apps[0].Tags, check.DeepEquals, app1.Tags)
 }
 
+func (s *S) TestAppListFilteringByTagSelector(c *check.C) {
+	app1 := app.App{Name: "app1", TeamOwner: s.team.Name, Tags: []string{"env=prod", "team=x"}}
+	err := app.CreateApp(context.TODO(), &app1, s.user)
+	c.Assert(err, check.IsNil)
+	app2 := app.App{Name: "app2", TeamOwner: s.team.Name, Tags: []string{"env=prod", "team=y"}}
+	err = app.CreateApp(context.TODO(), &app2, s.user)
+	c.Assert(err, check.IsNil)
+	app3 := app.App{Name: "app3", TeamOwner: s.team.Name, Tags: []string{"env=dev"}}
+	err = app.CreateApp(context.TODO(), &app3, s.user)
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest("GET", "/apps?tag=env%3Dprod,team!%3Dx", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	apps := []app.App{}
+	err = json.Unmarshal(recorder.Body.Bytes(), &apps)
+	c.Assert(err, check.IsNil)
+	c.Assert(apps, check.HasLen, 1)
+	c.Assert(apps[0].Name, check.Equals, app2.Name)
+}
+
+func (s *S) TestAppListFilteringByInvalidTagSelector(c *check.C) {
+	request, err := http.NewRequest("GET", "/apps?tag=!%3Dx", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(recorder.Body.String(), check.Matches, `invalid tag selector "!=x": missing key\n`)
+}
+
 func (s *S) TestAppListFilteringByLockState(c *check.C) {
 	app1 := app.App{Name: "app1", Platform: "zend", TeamOwner: s.team.Name}
 	err := app.CreateApp(context.TODO(), &app1, s.user)
@@ -686,6 +722,55 @@ func (s *S) TestAppListUnitsError(c *check.C) {
 	c.Assert(apps[0].Error, check.Equals, "unable to list app units: some units error")
 }
 
+func (s *S) TestAppListFields(c *check.C) {
+	app1 := app.App{Name: "app1", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &app1, s.user)
+	c.Assert(err, check.IsNil)
+	s.provisioner.AddUnits(context.TODO(), &app1, 1, "web", nil, nil)
+	request, err := http.NewRequest("GET", "/apps?fields=name,pool,teamowner,units.status", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, "application/json")
+	var apps []map[string]interface{}
+	err = json.Unmarshal(recorder.Body.Bytes(), &apps)
+	c.Assert(err, check.IsNil)
+	c.Assert(apps, check.DeepEquals, []map[string]interface{}{
+		{
+			"name":      "app1",
+			"pool":      "test1",
+			"teamowner": s.team.Name,
+			"units":     []interface{}{map[string]interface{}{"Status": "started"}},
+		},
+	})
+}
+
+func (s *S) TestAppListFieldsWithoutUnits(c *check.C) {
+	app1 := app.App{Name: "app1", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &app1, s.user)
+	c.Assert(err, check.IsNil)
+	provisiontest.ProvisionerInstance.PrepareFailure("Units", fmt.Errorf("some units error"))
+	request, err := http.NewRequest("GET", "/apps?fields=name&fields=platform", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(recorder.Body.String(), check.Equals, `[{"name":"app1","platform":"zend"}]`+"\n")
+}
+
+func (s *S) TestAppListInvalidField(c *check.C) {
+	request, err := http.NewRequest("GET", "/apps?fields=name,units.xyz", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(recorder.Body.String(), check.Equals, `invalid field "units.xyz"`+"\n")
+}
+
 func (s *S) TestAppListShouldListAllAppsOfAllTeamsThatTheUserHasPermission(c *check.C) {
 	team := authTypes.Team{Name: "angra"}
 	s.mockService.Team.OnList = func() ([]authTypes.Team, error) {
@@ -1256,6 +1341,40 @@ func (s *S) TestCreateAppWithDescription(c *check.C) {
 	}, eventtest.HasEvent)
 }
 
+func (s *S) TestCreateInternalApp(c *check.C) {
+	s.setupMockForCreateApp(c, "zend")
+	b := strings.NewReader("name=someapp&platform=zend&internal=true")
+	request, err := http.NewRequest("POST", "/apps", b)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusCreated)
+	var obtained map[string]string
+	err = json.Unmarshal(recorder.Body.Bytes(), &obtained)
+	c.Assert(err, check.IsNil)
+	c.Assert(obtained, check.DeepEquals, map[string]string{"status": "success"})
+	var gotApp app.App
+	err = s.conn.Apps().Find(bson.M{"name": "someapp"}).One(&gotApp)
+	c.Assert(err, check.IsNil)
+	c.Assert(gotApp.Internal, check.Equals, true)
+	c.Assert(gotApp.GetRouters(), check.HasLen, 0)
+}
+
+func (s *S) TestCreateInternalAppInvalid(c *check.C) {
+	s.setupMockForCreateApp(c, "zend")
+	b := strings.NewReader("name=someapp&platform=zend&internal=maybe")
+	request, err := http.NewRequest("POST", "/apps", b)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(recorder.Body.String(), check.Equals, "invalid value for internal, expected a boolean\n")
+}
+
 func (s *S) TestCreateAppWithTags(c *check.C) {
 	s.setupMockForCreateApp(c, "zend")
 	data, err := url.QueryUnescape("name=someapp&platform=zend&tag=tag1&tag=tag2&tags.0=tag0")
@@ -1345,6 +1464,73 @@ func (s *S) TestCreateAppWithMetadata(c *check.C) {
 	}, eventtest.HasEvent)
 }
 
+func (s *S) TestCreateAppWithPoolPermission(c *check.C) {
+	platName := "zend"
+	s.setupMockForCreateApp(c, platName)
+	err := pool.AddPool(context.TODO(), pool.AddPoolOptions{Name: "mypool1", Public: true})
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermAppCreate,
+		Context: permission.Context(permTypes.CtxTeam, s.team.Name),
+	}, permission.Permission{
+		Scheme:  permission.PermAppCreate,
+		Context: permission.Context(permTypes.CtxPool, "mypool1"),
+	})
+	b := strings.NewReader(fmt.Sprintf("name=someapp&platform=%s&pool=mypool1&teamOwner=%s", platName, s.team.Name))
+	request, err := http.NewRequest("POST", "/apps", b)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "b "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusCreated)
+	var gotApp app.App
+	err = s.conn.Apps().Find(bson.M{"name": "someapp"}).One(&gotApp)
+	c.Assert(err, check.IsNil)
+	c.Assert(gotApp.Pool, check.Equals, "mypool1")
+}
+
+func (s *S) TestCreateAppWithPoolPermissionInOtherPool(c *check.C) {
+	platName := "zend"
+	s.setupMockForCreateApp(c, platName)
+	err := pool.AddPool(context.TODO(), pool.AddPoolOptions{Name: "mypool1", Public: true})
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermAppCreate,
+		Context: permission.Context(permTypes.CtxTeam, s.team.Name),
+	}, permission.Permission{
+		Scheme:  permission.PermAppCreate,
+		Context: permission.Context(permTypes.CtxPool, "mypool1"),
+	})
+	b := strings.NewReader(fmt.Sprintf("name=someapp&platform=%s&pool=test1&teamOwner=%s", platName, s.team.Name))
+	request, err := http.NewRequest("POST", "/apps", b)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "b "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
+
+func (s *S) TestCreateAppWithPoolPermissionInOtherTeam(c *check.C) {
+	platName := "zend"
+	s.setupMockForCreateApp(c, platName)
+	err := pool.AddPool(context.TODO(), pool.AddPoolOptions{Name: "mypool1", Public: true})
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermAppCreate,
+		Context: permission.Context(permTypes.CtxPool, "mypool1"),
+	})
+	b := strings.NewReader(fmt.Sprintf("name=someapp&platform=%s&pool=mypool1&teamOwner=%s", platName, s.team.Name))
+	request, err := http.NewRequest("POST", "/apps", b)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "b "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
+
 func (s *S) TestCreateAppWithPool(c *check.C) {
 	platName := "zend"
 	s.setupMockForCreateApp(c, platName)
@@ -1361,6 +1547,9 @@ func (s *S) TestCreateAppWithPool(c *check.C) {
 	token := userWithPermission(c, permission.Permission{
 		Scheme:  permission.PermAppCreate,
 		Context: permission.Context(permTypes.CtxTeam, s.team.Name),
+	}, permission.Permission{
+		Scheme:  permission.PermAppCreate,
+		Context: permission.Context(permTypes.CtxPool, "mypool1"),
 	})
 	request.Header.Set("Authorization", "b "+token.GetValue())
 	s.testServer.ServeHTTP(recorder, request)
@@ -1509,6 +1698,7 @@ func (s *S) TestCreateAppUserQuotaExceeded(c *check.C) {
 	s.testServer.ServeHTTP(recorder, request)
 	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
 	c.Assert(recorder.Body.String(), check.Equals, "Quota exceeded\n")
+	c.Assert(recorder.Header().Get("X-Tsuru-Error-Code"), check.Equals, "app.quota.exceeded")
 	c.Assert(eventtest.EventDesc{
 		Target: appTarget("someapp"),
 		Owner:  token.GetUserName(),
@@ -1688,6 +1878,126 @@ func (s *S) TestUpdateAppWithDescriptionOnly(c *check.C) {
 	}, eventtest.HasEvent)
 }
 
+func (s *S) TestUpdateAppTerminationGracePeriods(c *check.C) {
+	a := app.App{Name: "myapp", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermAppUpdateTerminationGracePeriod,
+		Context: permission.Context(permTypes.CtxApp, a.Name),
+	})
+	b := strings.NewReader("terminationGracePeriods.web=120&terminationGracePeriods.worker=600")
+	request, err := http.NewRequest("PUT", "/apps/myapp", b)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	var gotApp app.App
+	err = s.conn.Apps().Find(bson.M{"name": "myapp"}).One(&gotApp)
+	c.Assert(err, check.IsNil)
+	c.Assert(gotApp.TerminationGracePeriods, check.DeepEquals, map[string]int{"web": 120, "worker": 600})
+}
+
+func (s *S) TestUpdateAppTerminationGracePeriodsInvalid(c *check.C) {
+	a := app.App{Name: "myapp", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	b := strings.NewReader("terminationGracePeriods.web=-10")
+	request, err := http.NewRequest("PUT", "/apps/myapp", b)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(recorder.Body.String(), check.Equals, "termination grace period must be greater than or equal to 0\n")
+}
+
+func (s *S) TestUpdateAppScheduling(c *check.C) {
+	a := app.App{Name: "myapp", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermAppUpdateScheduling,
+		Context: permission.Context(permTypes.CtxApp, a.Name),
+	})
+	b := strings.NewReader(`{"scheduling": {"tolerations": [{"key": "gpu", "operator": "Exists", "effect": "NoSchedule"}]}}`)
+	request, err := http.NewRequest("PUT", "/apps/myapp", b)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	request.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	var gotApp app.App
+	err = s.conn.Apps().Find(bson.M{"name": "myapp"}).One(&gotApp)
+	c.Assert(err, check.IsNil)
+	c.Assert(gotApp.Scheduling, check.DeepEquals, &appTypes.Scheduling{
+		Tolerations: []apiv1.Toleration{{Key: "gpu", Operator: apiv1.TolerationOpExists, Effect: apiv1.TaintEffectNoSchedule}},
+	})
+}
+
+func (s *S) TestUpdateAppSchedulingWithoutPermission(c *check.C) {
+	a := app.App{Name: "myapp", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermAppUpdateTerminationGracePeriod,
+		Context: permission.Context(permTypes.CtxApp, a.Name),
+	})
+	b := strings.NewReader(`{"scheduling": {"tolerations": [{"key": "gpu", "operator": "Exists"}]}}`)
+	request, err := http.NewRequest("PUT", "/apps/myapp", b)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	request.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
+
+func (s *S) TestUpdateAppInternal(c *check.C) {
+	a := app.App{Name: "myapp", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermAppUpdateInternal,
+		Context: permission.Context(permTypes.CtxApp, a.Name),
+	})
+	b := strings.NewReader("internal=true&noRestart=true")
+	request, err := http.NewRequest("PUT", "/apps/myapp", b)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	var gotApp app.App
+	err = s.conn.Apps().Find(bson.M{"name": "myapp"}).One(&gotApp)
+	c.Assert(err, check.IsNil)
+	c.Assert(gotApp.Internal, check.Equals, true)
+	c.Assert(gotApp.GetRouters(), check.HasLen, 0)
+}
+
+func (s *S) TestUpdateAppInternalWithoutPermission(c *check.C) {
+	a := app.App{Name: "myapp", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermAppUpdateDescription,
+		Context: permission.Context(permTypes.CtxApp, a.Name),
+	})
+	b := strings.NewReader("internal=true")
+	request, err := http.NewRequest("PUT", "/apps/myapp", b)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
+
 func (s *S) TestUpdateAppPlatformOnly(c *check.C) {
 	s.setupMockForCreateApp(c, "zend")
 	a := app.App{Name: "myapp", Platform: "zend", TeamOwner: s.team.Name}
@@ -1980,16 +2290,36 @@ func (s *S) TestUpdateAppPoolForbiddenIfTheUserDoesNotHaveAccess(c *check.C) {
 	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
 }
 
-func (s *S) TestUpdateAppPoolWhenAppDoesNotExist(c *check.C) {
-	body := strings.NewReader("pool=test")
+func (s *S) TestUpdateAppPoolToPoolNotAllowed(c *check.C) {
+	a := app.App{Name: "myappx", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	err = pool.AddPool(context.TODO(), pool.AddPoolOptions{Name: "prod", Public: true})
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermAppUpdatePool,
+		Context: permission.Context(permTypes.CtxPool, "test1"),
+	})
+	body := strings.NewReader("pool=prod")
 	request, err := http.NewRequest("PUT", "/apps/myappx", body)
 	c.Assert(err, check.IsNil)
 	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
 	recorder := httptest.NewRecorder()
 	s.testServer.ServeHTTP(recorder, request)
-	c.Assert(recorder.Code, check.Equals, http.StatusNotFound)
-	c.Assert(recorder.Body.String(), check.Matches, "^App myappx not found.\n$")
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
+
+func (s *S) TestUpdateAppPoolWhenAppDoesNotExist(c *check.C) {
+	body := strings.NewReader("pool=test")
+	request, err := http.NewRequest("PUT", "/apps/myappx", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNotFound)
+	c.Assert(recorder.Body.String(), check.Matches, "^App myappx not found.\n$")
 }
 
 func (s *S) TestUpdateAppPoolWithDifferentProvisioner(c *check.C) {
@@ -2128,6 +2458,57 @@ func (s *S) TestUpdateAppPlanOverrideOnly(c *check.C) {
 	}
 }
 
+func (s *S) TestUpdateAppProcessPlan(c *check.C) {
+	config.Set("docker:router", "fake")
+	defer config.Unset("docker:router")
+	plans := []appTypes.Plan{
+		{Name: "hiperplan", Memory: 536870912, Swap: 536870912, CpuShare: 100, CPUMilli: 1000},
+		{Name: "superplan", Memory: 268435456, Swap: 268435456, CpuShare: 100, CPUMilli: 500},
+	}
+	s.mockService.Plan.OnFindByName = func(name string) (*appTypes.Plan, error) {
+		for i := range plans {
+			if plans[i].Name == name {
+				return &plans[i], nil
+			}
+		}
+		return nil, appTypes.ErrPlanNotFound
+	}
+	s.mockService.Plan.OnList = func() ([]appTypes.Plan, error) {
+		return plans, nil
+	}
+	a := app.App{Name: "someapp", Platform: "zend", TeamOwner: s.team.Name, Plan: plans[0]}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	newSuccessfulAppVersion(c, &a)
+	body := strings.NewReader("processplans.worker=superplan")
+	request, err := http.NewRequest("PUT", "/apps/someapp", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK, check.Commentf("body: %v", recorder.Body.String()))
+	dbApp, err := app.GetByName(context.TODO(), a.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.Plan, check.DeepEquals, plans[0])
+	c.Assert(dbApp.ProcessPlans, check.DeepEquals, map[string]appTypes.Plan{"worker": plans[1]})
+	c.Assert(dbApp.GetProcessMemory("worker"), check.Equals, int64(268435456))
+	c.Assert(dbApp.GetProcessMemory("web"), check.Equals, int64(536870912))
+	c.Assert(s.provisioner.Restarts(&a, ""), check.Equals, 1)
+	body = strings.NewReader("processplans.worker=")
+	request, err = http.NewRequest("PUT", "/apps/someapp", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder = httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK, check.Commentf("body: %v", recorder.Body.String()))
+	dbApp, err = app.GetByName(context.TODO(), a.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.ProcessPlans, check.IsNil)
+	c.Assert(s.provisioner.Restarts(&a, ""), check.Equals, 2)
+}
+
 func (s *S) TestUpdateAppPlanNotFound(c *check.C) {
 	s.plan = appTypes.Plan{Name: "superplan", Memory: 268435456, Swap: 268435456, CpuShare: 100}
 	a := app.App{Name: "someapp", Platform: "zend", TeamOwner: s.team.Name, Plan: s.plan}
@@ -2159,7 +2540,7 @@ func (s *S) TestUpdateAppWithoutFlag(c *check.C) {
 	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	recorder := httptest.NewRecorder()
 	s.testServer.ServeHTTP(recorder, request)
-	errorMessage := "Neither the description, tags, plan, pool, team owner or platform were set. You must define at least one.\n"
+	errorMessage := "Neither the description, tags, plan, pool, team owner, platform, internal, termination grace periods or scheduling were set. You must define at least one.\n"
 	c.Check(recorder.Code, check.Equals, http.StatusBadRequest)
 	c.Check(recorder.Body.String(), check.Equals, errorMessage)
 }
@@ -2256,6 +2637,375 @@ func (s *S) TestUpdateAppTeamOwnerSetNewTeamToAppAddThatTeamToAppTeamList(c *che
 	c.Assert(rec.Code, check.Equals, http.StatusOK)
 }
 
+func (s *S) TestRenameAppHandler(c *check.C) {
+	a := app.App{Name: "myapp", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	body := strings.NewReader("name=newapp")
+	request, err := http.NewRequest("POST", "/1.13/apps/myapp/rename", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK, check.Commentf("body: %s", recorder.Body.String()))
+	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, "application/x-json-stream")
+	c.Assert(recorder.Body.String(), check.Matches, `(?s).*---- Renaming application \\"myapp\\" to \\"newapp\\" ----.*`)
+	_, err = app.GetByName(context.TODO(), "myapp")
+	c.Assert(err, check.Equals, appTypes.ErrAppNotFound)
+	renamed, err := app.GetByName(context.TODO(), "newapp")
+	c.Assert(err, check.IsNil)
+	c.Assert(renamed.TeamOwner, check.Equals, s.team.Name)
+	c.Assert(eventtest.EventDesc{
+		Target: appTarget("myapp"),
+		Owner:  s.token.GetUserName(),
+		Kind:   "app.update.rename",
+		StartCustomData: []map[string]interface{}{
+			{"name": ":app", "value": "myapp"},
+			{"name": "name", "value": "newapp"},
+		},
+	}, eventtest.HasEvent)
+}
+
+func (s *S) TestRenameAppHandlerAlreadyExists(c *check.C) {
+	for _, name := range []string{"myapp", "otherapp"} {
+		a := app.App{Name: name, Platform: "zend", TeamOwner: s.team.Name}
+		err := app.CreateApp(context.TODO(), &a, s.user)
+		c.Assert(err, check.IsNil)
+	}
+	body := strings.NewReader("name=otherapp")
+	request, err := http.NewRequest("POST", "/1.13/apps/myapp/rename", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusConflict)
+	c.Assert(recorder.Body.String(), check.Equals, app.ErrAppAlreadyExists.Error()+"\n")
+}
+
+func (s *S) TestRenameAppHandlerInvalidName(c *check.C) {
+	a := app.App{Name: "myapp", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	for _, name := range []string{"", "Invalid_Name", "myapp"} {
+		body := strings.NewReader("name=" + name)
+		request, err := http.NewRequest("POST", "/1.13/apps/myapp/rename", body)
+		c.Assert(err, check.IsNil)
+		request.Header.Set("Authorization", "b "+s.token.GetValue())
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		recorder := httptest.NewRecorder()
+		s.testServer.ServeHTTP(recorder, request)
+		c.Assert(recorder.Code, check.Equals, http.StatusBadRequest, check.Commentf("name: %q", name))
+	}
+	_, err = app.GetByName(context.TODO(), "myapp")
+	c.Assert(err, check.IsNil)
+}
+
+func (s *S) TestRenameAppHandlerForbidden(c *check.C) {
+	a := app.App{Name: "myapp"}
+	err := s.conn.Apps().Insert(a)
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermAppUpdateRename,
+		Context: permission.Context(permTypes.CtxApp, "-invalid-"),
+	})
+	body := strings.NewReader("name=newapp")
+	request, err := http.NewRequest("POST", "/1.13/apps/myapp/rename", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+token.GetValue())
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
+
+func (s *S) TestExportAppHandler(c *check.C) {
+	a := app.App{Name: "myapp", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	err = a.SetEnvs(bind.SetEnvArgs{Envs: []bind.EnvVar{{Name: "PRIVATE", Value: "secret"}}})
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest("GET", "/1.13/apps/myapp/export", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK, check.Commentf("body: %s", recorder.Body.String()))
+	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, "application/json")
+	var bundle app.Bundle
+	err = json.Unmarshal(recorder.Body.Bytes(), &bundle)
+	c.Assert(err, check.IsNil)
+	c.Assert(bundle.Version, check.Equals, app.BundleVersion)
+	c.Assert(bundle.App.Name, check.Equals, "myapp")
+	c.Assert(bundle.App.TeamOwner, check.Equals, s.team.Name)
+	c.Assert(bundle.Envs, check.DeepEquals, []bind.EnvVar{{Name: "PRIVATE", Value: "secret"}})
+}
+
+func (s *S) TestExportAppHandlerForbidden(c *check.C) {
+	a := app.App{Name: "myapp", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermAppRead,
+		Context: permission.Context(permTypes.CtxApp, "myapp"),
+	})
+	request, err := http.NewRequest("GET", "/1.13/apps/myapp/export", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
+
+func (s *S) TestImportAppHandler(c *check.C) {
+	bundle := app.Bundle{
+		Version: app.BundleVersion,
+		App:     app.BundleApp{Name: "imported", Platform: "zend", TeamOwner: s.team.Name},
+		Envs:    []bind.EnvVar{{Name: "PRIVATE", Value: "secret"}},
+	}
+	body, err := json.Marshal(bundle)
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest("POST", "/1.13/apps/import", bytes.NewReader(body))
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	request.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK, check.Commentf("body: %s", recorder.Body.String()))
+	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, "application/x-json-stream")
+	c.Assert(recorder.Body.String(), check.Matches, `(?s).*---- Creating app \\"imported\\" ----.*`)
+	imported, err := app.GetByName(context.TODO(), "imported")
+	c.Assert(err, check.IsNil)
+	c.Assert(imported.TeamOwner, check.Equals, s.team.Name)
+	c.Assert(imported.Env["PRIVATE"], check.DeepEquals, bind.EnvVar{Name: "PRIVATE", Value: "secret"})
+	c.Assert(eventtest.EventDesc{
+		Target:          appTarget("imported"),
+		Owner:           s.token.GetUserName(),
+		Kind:            "app.create",
+		StartCustomData: map[string]interface{}{"import": true},
+	}, eventtest.HasEvent)
+}
+
+func (s *S) TestImportAppHandlerAlreadyExists(c *check.C) {
+	a := app.App{Name: "myapp", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	body, err := json.Marshal(app.Bundle{Version: app.BundleVersion, App: app.BundleApp{Name: "myapp"}})
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest("POST", "/1.13/apps/import", bytes.NewReader(body))
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	request.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusConflict)
+}
+
+func (s *S) TestImportAppHandlerInvalidVersion(c *check.C) {
+	body, err := json.Marshal(app.Bundle{Version: 99, App: app.BundleApp{Name: "myapp"}})
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest("POST", "/1.13/apps/import", bytes.NewReader(body))
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	request.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(recorder.Body.String(), check.Equals, "unsupported bundle version 99\n")
+}
+
+func (s *S) TestSetAppMaintenanceHandler(c *check.C) {
+	config.Set("routers:fake-maintenance:type", "fake-maintenance")
+	defer routertest.MaintenanceRouter.Reset()
+	a := app.App{Name: "myapp", Platform: "zend", TeamOwner: s.team.Name, Routers: []appTypes.AppRouter{{Name: "fake-maintenance"}}}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	body := strings.NewReader("page=down&retry-after=30")
+	request, err := http.NewRequest("POST", "/1.13/apps/myapp/maintenance", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK, check.Commentf("body: %s", recorder.Body.String()))
+	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, "application/x-json-stream")
+	dbApp, err := app.GetByName(context.TODO(), a.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.Maintenance, check.DeepEquals, &app.Maintenance{Page: "down", RetryAfter: 30})
+	c.Assert(routertest.MaintenanceRouter.Maintenance[a.Name], check.DeepEquals, router.MaintenanceOpts{Page: "down", RetryAfter: 30})
+	c.Assert(eventtest.EventDesc{
+		Target: appTarget(a.Name),
+		Owner:  s.token.GetUserName(),
+		Kind:   "app.update.maintenance",
+		StartCustomData: []map[string]interface{}{
+			{"name": "page", "value": "down"},
+			{"name": "retry-after", "value": "30"},
+			{"name": ":app", "value": a.Name},
+		},
+	}, eventtest.HasEvent)
+	request, err = http.NewRequest("DELETE", "/1.13/apps/myapp/maintenance", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder = httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK, check.Commentf("body: %s", recorder.Body.String()))
+	dbApp, err = app.GetByName(context.TODO(), a.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.Maintenance, check.IsNil)
+	c.Assert(routertest.MaintenanceRouter.Maintenance, check.HasLen, 0)
+}
+
+func (s *S) TestSetAppMaintenanceHandlerRouterNotSupported(c *check.C) {
+	a := app.App{Name: "myapp", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest("POST", "/1.13/apps/myapp/maintenance", strings.NewReader("page=down"))
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(recorder.Body.String(), check.Equals, "router \"fake\" does not support maintenance mode\n")
+}
+
+func (s *S) TestUnsetAppMaintenanceHandlerNotInMaintenance(c *check.C) {
+	a := app.App{Name: "myapp", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest("DELETE", "/1.13/apps/myapp/maintenance", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(recorder.Body.String(), check.Equals, app.ErrAppNotInMaintenance.Error()+"\n")
+}
+
+func (s *S) TestSetAppMaintenanceHandlerForbidden(c *check.C) {
+	a := app.App{Name: "myapp", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermAppUpdateMaintenance,
+		Context: permission.Context(permTypes.CtxApp, "other-app"),
+	})
+	request, err := http.NewRequest("POST", "/1.13/apps/myapp/maintenance", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
+
+func (s *S) TestFreezeAppHandler(c *check.C) {
+	a := app.App{Name: "myapp", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	body := strings.NewReader("reason=release+window&expires-in=1h")
+	request, err := http.NewRequest("POST", "/1.13/apps/myapp/freeze", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK, check.Commentf("body: %s", recorder.Body.String()))
+	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, "application/json")
+	var block event.Block
+	err = json.Unmarshal(recorder.Body.Bytes(), &block)
+	c.Assert(err, check.IsNil)
+	c.Assert(block.Reason, check.Equals, "release window")
+	c.Assert(block.Target, check.Equals, event.Target{Type: event.TargetTypeApp, Value: a.Name})
+	c.Assert(block.ExpiresAt.After(time.Now().Add(59*time.Minute)), check.Equals, true)
+	c.Assert(eventtest.EventDesc{
+		Target: appTarget(a.Name),
+		Owner:  s.token.GetUserName(),
+		Kind:   "app.update.freeze",
+		StartCustomData: []map[string]interface{}{
+			{"name": "reason", "value": "release window"},
+			{"name": "expires-in", "value": "1h"},
+			{"name": ":app", "value": a.Name},
+		},
+	}, eventtest.HasEvent)
+	v, err := form.EncodeToValues(&apiTypes.Envs{Envs: []apiTypes.Env{{Name: "DATABASE_HOST", Value: "localhost"}}})
+	c.Assert(err, check.IsNil)
+	request, err = http.NewRequest("POST", "/apps/myapp/env", strings.NewReader(v.Encode()))
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder = httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Not(check.Equals), http.StatusOK)
+	c.Assert(recorder.Body.String(), check.Matches, `(?s).*release window.*`)
+	request, err = http.NewRequest("DELETE", "/1.13/apps/myapp/freeze", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder = httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK, check.Commentf("body: %s", recorder.Body.String()))
+	frozen, err := a.ActiveFreeze()
+	c.Assert(err, check.IsNil)
+	c.Assert(frozen, check.IsNil)
+}
+
+func (s *S) TestFreezeAppHandlerWithoutReason(c *check.C) {
+	a := app.App{Name: "myapp", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest("POST", "/1.13/apps/myapp/freeze", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(recorder.Body.String(), check.Equals, app.ErrFreezeReason.Error()+"\n")
+}
+
+func (s *S) TestFreezeAppHandlerInvalidExpiration(c *check.C) {
+	a := app.App{Name: "myapp", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest("POST", "/1.13/apps/myapp/freeze", strings.NewReader("reason=release&expires-in=-1h"))
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(recorder.Body.String(), check.Equals, "invalid expires-in duration: \"-1h\"\n")
+}
+
+func (s *S) TestUnfreezeAppHandlerNotFrozen(c *check.C) {
+	a := app.App{Name: "myapp", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest("DELETE", "/1.13/apps/myapp/freeze", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(recorder.Body.String(), check.Equals, app.ErrAppNotFrozen.Error()+"\n")
+}
+
+func (s *S) TestFreezeAppHandlerForbidden(c *check.C) {
+	a := app.App{Name: "myapp", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermAppUpdateFreeze,
+		Context: permission.Context(permTypes.CtxApp, "other-app"),
+	})
+	request, err := http.NewRequest("POST", "/1.13/apps/myapp/freeze", strings.NewReader("reason=release"))
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+token.GetValue())
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
+
 func (s *S) TestAddUnits(c *check.C) {
 	a := app.App{Name: "armorandsword", Platform: "zend", TeamOwner: s.team.Name, Quota: quota.Quota{Limit: 10, InUse: 0}}
 	err := app.CreateApp(context.TODO(), &a, s.user)
@@ -2433,6 +3183,7 @@ func (s *S) TestAddUnitsQuotaExceeded(c *check.C) {
 	recorder := httptest.NewRecorder()
 	s.testServer.ServeHTTP(recorder, request)
 	c.Assert(recorder.Body.String(), check.Matches, `(?s).*Quota exceeded. Available: 2, Requested: 3.*`)
+	c.Assert(recorder.Header().Get("X-Tsuru-Error-Code"), check.Equals, "app.quota.exceeded")
 	c.Assert(eventtest.EventDesc{
 		Target:          appTarget("armorandsword"),
 		Owner:           s.token.GetUserName(),
@@ -2987,33 +3738,109 @@ func (s *S) TestRunOnce(c *check.C) {
 	url := fmt.Sprintf("/apps/%s/run", a.Name)
 	request, err := http.NewRequest("POST", url, strings.NewReader("command=ls&once=true"))
 	c.Assert(err, check.IsNil)
-	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, "application/x-json-stream")
+	c.Assert(recorder.Body.String(), check.Matches, `{"Message":"lots of files","Timestamp":".*"}`+"\n")
+	expected := "[ -f /home/application/apprc ] && source /home/application/apprc;"
+	expected += " [ -d /home/application/current ] && cd /home/application/current;"
+	expected += " ls"
+	units, err := a.GetUnits()
+	c.Assert(err, check.IsNil)
+	c.Assert(units, check.HasLen, 3)
+	allExecs := s.provisioner.AllExecs()
+	c.Assert(allExecs, check.HasLen, 1)
+	c.Assert(allExecs[units[0].GetID()], check.HasLen, 1)
+	c.Assert(allExecs[units[0].GetID()][0].Cmds, check.DeepEquals, []string{"/bin/sh", "-c", expected})
+	c.Assert(eventtest.EventDesc{
+		Target: appTarget(a.Name),
+		Owner:  s.token.GetUserName(),
+		Kind:   "app.run",
+		StartCustomData: []map[string]interface{}{
+			{"name": "command", "value": "ls"},
+			{"name": "once", "value": "true"},
+			{"name": ":app", "value": a.Name},
+		},
+	}, eventtest.HasEvent)
+}
+
+func (s *S) TestRunDetached(c *check.C) {
+	s.provisioner.PrepareOutput([]byte("lots of files"))
+	a := app.App{Name: "secrets", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	s.provisioner.AddUnits(context.TODO(), &a, 1, "web", nil, nil)
+	url := fmt.Sprintf("/apps/%s/run", a.Name)
+	request, err := http.NewRequest("POST", url, strings.NewReader("command=ls&detached=true"))
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusAccepted)
+	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, "application/json")
+	var run map[string]string
+	err = json.Unmarshal(recorder.Body.Bytes(), &run)
+	c.Assert(err, check.IsNil)
+	c.Assert(run["id"], check.Not(check.Equals), "")
+	var result app.RunResult
+	timeout := time.After(5 * time.Second)
+	for {
+		request, err = http.NewRequest("GET", fmt.Sprintf("/1.13/apps/%s/runs/%s", a.Name, run["id"]), nil)
+		c.Assert(err, check.IsNil)
+		request.Header.Set("Authorization", "b "+s.token.GetValue())
+		recorder = httptest.NewRecorder()
+		s.testServer.ServeHTTP(recorder, request)
+		c.Assert(recorder.Code, check.Equals, http.StatusOK)
+		err = json.Unmarshal(recorder.Body.Bytes(), &result)
+		c.Assert(err, check.IsNil)
+		if result.Status != app.RunStatusRunning {
+			break
+		}
+		select {
+		case <-timeout:
+			c.Fatal("timeout waiting for detached run to finish")
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+	c.Assert(result.ID, check.Equals, run["id"])
+	c.Assert(result.Command, check.Equals, "ls")
+	c.Assert(result.Status, check.Equals, app.RunStatusSucceeded)
+	c.Assert(result.ExitCode, check.NotNil)
+	c.Assert(*result.ExitCode, check.Equals, 0)
+	c.Assert(result.Output, check.Matches, `(?s).*lots of files.*`)
+}
+
+func (s *S) TestRunInfoNotFound(c *check.C) {
+	a := app.App{Name: "secrets", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest("GET", fmt.Sprintf("/1.13/apps/%s/runs/5fa4d5a7a0fab4a1ad4d5ff2", a.Name), nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNotFound)
+	c.Assert(recorder.Body.String(), check.Equals, app.ErrRunNotFound.Error()+"\n")
+}
+
+func (s *S) TestRunInfoForbidden(c *check.C) {
+	a := app.App{Name: "secrets", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermAppRead,
+		Context: permission.Context(permTypes.CtxApp, a.Name),
+	})
+	request, err := http.NewRequest("GET", fmt.Sprintf("/1.13/apps/%s/runs/5fa4d5a7a0fab4a1ad4d5ff2", a.Name), nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+token.GetValue())
 	recorder := httptest.NewRecorder()
 	s.testServer.ServeHTTP(recorder, request)
-	c.Assert(recorder.Code, check.Equals, http.StatusOK)
-	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, "application/x-json-stream")
-	c.Assert(recorder.Body.String(), check.Matches, `{"Message":"lots of files","Timestamp":".*"}`+"\n")
-	expected := "[ -f /home/application/apprc ] && source /home/application/apprc;"
-	expected += " [ -d /home/application/current ] && cd /home/application/current;"
-	expected += " ls"
-	units, err := a.GetUnits()
-	c.Assert(err, check.IsNil)
-	c.Assert(units, check.HasLen, 3)
-	allExecs := s.provisioner.AllExecs()
-	c.Assert(allExecs, check.HasLen, 1)
-	c.Assert(allExecs[units[0].GetID()], check.HasLen, 1)
-	c.Assert(allExecs[units[0].GetID()][0].Cmds, check.DeepEquals, []string{"/bin/sh", "-c", expected})
-	c.Assert(eventtest.EventDesc{
-		Target: appTarget(a.Name),
-		Owner:  s.token.GetUserName(),
-		Kind:   "app.run",
-		StartCustomData: []map[string]interface{}{
-			{"name": "command", "value": "ls"},
-			{"name": "once", "value": "true"},
-			{"name": ":app", "value": a.Name},
-		},
-	}, eventtest.HasEvent)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
 }
 
 func (s *S) TestRun(c *check.C) {
@@ -3272,6 +4099,71 @@ func (s *S) TestGetEnvMultipleVariables(c *check.C) {
 	c.Assert(got, check.DeepEquals, expected)
 }
 
+func (s *S) TestGetEnvPrivateSuppressed(c *check.C) {
+	a := app.App{
+		Name:      "everything-i-want",
+		Platform:  "zend",
+		TeamOwner: s.team.Name,
+		Env: map[string]bind.EnvVar{
+			"DATABASE_HOST":     {Name: "DATABASE_HOST", Value: "localhost", Public: true},
+			"DATABASE_PASSWORD": {Name: "DATABASE_PASSWORD", Value: "secret", Public: false},
+		},
+	}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermAppReadEnv,
+		Context: permission.Context(permTypes.CtxApp, a.Name),
+	})
+	url := fmt.Sprintf("/apps/%s/env?env=DATABASE_HOST&env=DATABASE_PASSWORD", a.Name)
+	request, err := http.NewRequest("GET", url, nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	var result []bind.EnvVar
+	err = json.Unmarshal(recorder.Body.Bytes(), &result)
+	c.Assert(err, check.IsNil)
+	c.Assert(result, check.DeepEquals, []bind.EnvVar{
+		{Name: "DATABASE_HOST", Value: "localhost", Public: true},
+		{Name: "DATABASE_PASSWORD", Value: app.SuppressedEnv, Public: false},
+	})
+}
+
+func (s *S) TestGetEnvPrivateWithPermission(c *check.C) {
+	a := app.App{
+		Name:      "everything-i-want",
+		Platform:  "zend",
+		TeamOwner: s.team.Name,
+		Env: map[string]bind.EnvVar{
+			"DATABASE_PASSWORD": {Name: "DATABASE_PASSWORD", Value: "secret", Public: false},
+		},
+	}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermAppReadEnv,
+		Context: permission.Context(permTypes.CtxApp, a.Name),
+	}, permission.Permission{
+		Scheme:  permission.PermAppReadPrivateEnv,
+		Context: permission.Context(permTypes.CtxApp, a.Name),
+	})
+	url := fmt.Sprintf("/apps/%s/env?env=DATABASE_PASSWORD", a.Name)
+	request, err := http.NewRequest("GET", url, nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	var result []bind.EnvVar
+	err = json.Unmarshal(recorder.Body.Bytes(), &result)
+	c.Assert(err, check.IsNil)
+	c.Assert(result, check.DeepEquals, []bind.EnvVar{
+		{Name: "DATABASE_PASSWORD", Value: "secret", Public: false},
+	})
+}
+
 func (s *S) TestGetEnvAppDoesNotExist(c *check.C) {
 	request, err := http.NewRequest("GET", "/apps/unknown/env", nil)
 	c.Assert(err, check.IsNil)
@@ -5789,35 +6681,207 @@ func (s *S) TestUnbindHandlerReturns404IfTheAppDoesNotExist(c *check.C) {
 	c.Assert(e, check.ErrorMatches, "^App unknown not found.$")
 }
 
-func (s *S) TestUnbindHandlerReturns403IfTheUserDoesNotHaveAccessToTheApp(c *check.C) {
+func (s *S) TestUnbindHandlerReturns403IfTheUserDoesNotHaveAccessToTheApp(c *check.C) {
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermServiceInstanceUpdateUnbind,
+		Context: permission.Context(permTypes.CtxTeam, s.team.Name),
+	}, permission.Permission{
+		Scheme:  permission.PermAppUpdateUnbind,
+		Context: permission.Context(permTypes.CtxTeam, "other-team"),
+	})
+	instance := service.ServiceInstance{Name: "my-mysql", ServiceName: "mysql", Teams: []string{s.team.Name}}
+	err := s.conn.ServiceInstances().Insert(instance)
+	c.Assert(err, check.IsNil)
+	a := app.App{Name: "serviceapp", Platform: "zend"}
+	err = s.conn.Apps().Insert(a)
+	c.Assert(err, check.IsNil)
+	url := fmt.Sprintf("/services/%s/instances/%s/%s?:service=%s&:instance=%s&:app=%s&noRestart=false", instance.ServiceName, instance.Name,
+		a.Name, instance.ServiceName, instance.Name, a.Name)
+	request, err := http.NewRequest("PUT", url, nil)
+	c.Assert(err, check.IsNil)
+	recorder := httptest.NewRecorder()
+	err = unbindServiceInstance(recorder, request, token)
+	c.Assert(err, check.NotNil)
+	e, ok := err.(*errors.HTTP)
+	c.Assert(ok, check.Equals, true)
+	c.Assert(e.Code, check.Equals, http.StatusForbidden)
+}
+
+func (s *S) TestRestartHandler(c *check.C) {
+	config.Set("docker:router", "fake")
+	defer config.Unset("docker:router")
+	a := app.App{
+		Name:      "stress",
+		Platform:  "zend",
+		TeamOwner: s.team.Name,
+	}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	newSuccessfulAppVersion(c, &a)
+	url := fmt.Sprintf("/apps/%s/restart", a.Name)
+	request, err := http.NewRequest("POST", url, nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, "application/x-json-stream")
+	c.Assert(recorder.Body.String(), check.Matches,
+		`{"Message":".*---- Restarting the app \\"stress\\" ----\\n","Timestamp":".*"}`+"\n"+
+			`{"Message":".*restarting app","Timestamp":".*"}`+"\n",
+	)
+	c.Assert(eventtest.EventDesc{
+		Target: appTarget(a.Name),
+		Owner:  s.token.GetUserName(),
+		Kind:   "app.update.restart",
+		StartCustomData: []map[string]interface{}{
+			{"name": ":app", "value": a.Name},
+		},
+	}, eventtest.HasEvent)
+}
+
+func (s *S) TestRestartHandlerSingleProcess(c *check.C) {
+	config.Set("docker:router", "fake")
+	defer config.Unset("docker:router")
+	a := app.App{
+		Name:      "stress",
+		Platform:  "zend",
+		TeamOwner: s.team.Name,
+	}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	newSuccessfulAppVersion(c, &a)
+	url := fmt.Sprintf("/apps/%s/restart", a.Name)
+	body := strings.NewReader("process=web")
+	request, err := http.NewRequest("POST", url, body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, "application/x-json-stream")
+	c.Assert(recorder.Body.String(), check.Matches,
+		`{"Message":".*---- Restarting process \\"web\\" ----\\n","Timestamp":".*"}`+"\n"+
+			`{"Message":".*restarting app","Timestamp":".*"}`+"\n",
+	)
+	restarts := s.provisioner.Restarts(&a, "web")
+	c.Assert(restarts, check.Equals, 1)
+	restarts = s.provisioner.Restarts(&a, "worker")
+	c.Assert(restarts, check.Equals, 0)
+	c.Assert(eventtest.EventDesc{
+		Target: appTarget(a.Name),
+		Owner:  s.token.GetUserName(),
+		Kind:   "app.update.restart",
+		StartCustomData: []map[string]interface{}{
+			{"name": ":app", "value": a.Name},
+			{"name": "process", "value": "web"},
+		},
+	}, eventtest.HasEvent)
+}
+
+func (s *S) TestRestartHandlerReturns404IfTheAppDoesNotExist(c *check.C) {
+	request, err := http.NewRequest("GET", "/apps/unknown/restart?:app=unknown", nil)
+	c.Assert(err, check.IsNil)
+	recorder := httptest.NewRecorder()
+	err = restart(recorder, request, s.token)
+	c.Assert(err, check.NotNil)
+	e, ok := err.(*errors.HTTP)
+	c.Assert(ok, check.Equals, true)
+	c.Assert(e.Code, check.Equals, http.StatusNotFound)
+}
+
+func (s *S) TestRestartHandlerReturns403IfTheUserDoesNotHaveAccessToTheApp(c *check.C) {
+	a := app.App{Name: "nightmist"}
+	err := s.conn.Apps().Insert(a)
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermAppUpdateRestart,
+		Context: permission.Context(permTypes.CtxApp, "-invalid-"),
+	})
+	url := fmt.Sprintf("/apps/%s/restart?:app=%s", a.Name, a.Name)
+	request, err := http.NewRequest("GET", url, nil)
+	c.Assert(err, check.IsNil)
+	recorder := httptest.NewRecorder()
+	err = restart(recorder, request, token)
+	c.Assert(err, check.NotNil)
+	e, ok := err.(*errors.HTTP)
+	c.Assert(ok, check.Equals, true)
+	c.Assert(e.Code, check.Equals, http.StatusForbidden)
+}
+
+func (s *S) TestRestartUnitHandler(c *check.C) {
+	a := app.App{
+		Name:      "stress",
+		Platform:  "zend",
+		TeamOwner: s.team.Name,
+	}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	s.provisioner.AddUnits(context.TODO(), &a, 2, "web", nil, nil)
+	units := s.provisioner.GetUnits(&a)
+	url := fmt.Sprintf("/1.13/apps/%s/units/%s/restart", a.Name, units[0].ID)
+	request, err := http.NewRequest("POST", url, nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, "application/x-json-stream")
+	c.Assert(recorder.Body.String(), check.Matches,
+		`{"Message":".*---- Restarting unit \\"`+units[0].ID+`\\" ----\\n","Timestamp":".*"}`+"\n"+
+			`{"Message":".*restarting unit `+units[0].ID+`","Timestamp":".*"}`+"\n",
+	)
+	c.Assert(s.provisioner.UnitRestarts(&a, units[0].ID), check.Equals, 1)
+	c.Assert(s.provisioner.UnitRestarts(&a, units[1].ID), check.Equals, 0)
+	c.Assert(eventtest.EventDesc{
+		Target: appTarget(a.Name),
+		Owner:  s.token.GetUserName(),
+		Kind:   "app.update.restart",
+		StartCustomData: []map[string]interface{}{
+			{"name": "unit", "value": units[0].ID},
+		},
+	}, eventtest.HasEvent)
+}
+
+func (s *S) TestRestartUnitHandlerUnitNotFound(c *check.C) {
+	a := app.App{
+		Name:      "stress",
+		Platform:  "zend",
+		TeamOwner: s.team.Name,
+	}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	url := fmt.Sprintf("/1.13/apps/%s/units/unknown/restart", a.Name)
+	request, err := http.NewRequest("POST", url, nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNotFound)
+	c.Assert(recorder.Body.String(), check.Equals, "unit \"unknown\" not found\n")
+}
+
+func (s *S) TestRestartUnitHandlerForbidden(c *check.C) {
+	a := app.App{Name: "nightmist"}
+	err := s.conn.Apps().Insert(a)
+	c.Assert(err, check.IsNil)
 	token := userWithPermission(c, permission.Permission{
-		Scheme:  permission.PermServiceInstanceUpdateUnbind,
-		Context: permission.Context(permTypes.CtxTeam, s.team.Name),
-	}, permission.Permission{
-		Scheme:  permission.PermAppUpdateUnbind,
-		Context: permission.Context(permTypes.CtxTeam, "other-team"),
+		Scheme:  permission.PermAppUpdateRestart,
+		Context: permission.Context(permTypes.CtxApp, "-invalid-"),
 	})
-	instance := service.ServiceInstance{Name: "my-mysql", ServiceName: "mysql", Teams: []string{s.team.Name}}
-	err := s.conn.ServiceInstances().Insert(instance)
-	c.Assert(err, check.IsNil)
-	a := app.App{Name: "serviceapp", Platform: "zend"}
-	err = s.conn.Apps().Insert(a)
-	c.Assert(err, check.IsNil)
-	url := fmt.Sprintf("/services/%s/instances/%s/%s?:service=%s&:instance=%s&:app=%s&noRestart=false", instance.ServiceName, instance.Name,
-		a.Name, instance.ServiceName, instance.Name, a.Name)
-	request, err := http.NewRequest("PUT", url, nil)
+	url := fmt.Sprintf("/apps/%s/units/unit1/restart?:app=%s&:unit=unit1", a.Name, a.Name)
+	request, err := http.NewRequest("POST", url, nil)
 	c.Assert(err, check.IsNil)
 	recorder := httptest.NewRecorder()
-	err = unbindServiceInstance(recorder, request, token)
+	err = restartUnit(recorder, request, token)
 	c.Assert(err, check.NotNil)
 	e, ok := err.(*errors.HTTP)
 	c.Assert(ok, check.Equals, true)
 	c.Assert(e.Code, check.Equals, http.StatusForbidden)
 }
 
-func (s *S) TestRestartHandler(c *check.C) {
-	config.Set("docker:router", "fake")
-	defer config.Unset("docker:router")
+func (s *S) TestDebugUnitHandler(c *check.C) {
 	a := app.App{
 		Name:      "stress",
 		Platform:  "zend",
@@ -5825,32 +6889,44 @@ func (s *S) TestRestartHandler(c *check.C) {
 	}
 	err := app.CreateApp(context.TODO(), &a, s.user)
 	c.Assert(err, check.IsNil)
-	newSuccessfulAppVersion(c, &a)
-	url := fmt.Sprintf("/apps/%s/restart", a.Name)
-	request, err := http.NewRequest("POST", url, nil)
+	s.provisioner.AddUnits(context.TODO(), &a, 2, "web", nil, nil)
+	units := s.provisioner.GetUnits(&a)
+	server := httptest.NewServer(s.testServer)
+	defer server.Close()
+	body := strings.NewReader("image=busybox&width=140&height=38&term=xterm")
+	url := fmt.Sprintf("%s/1.13/apps/%s/units/%s/debug", server.URL, a.Name, units[0].ID)
+	request, err := http.NewRequest("POST", url, body)
 	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	request.Header.Set("Authorization", "b "+s.token.GetValue())
-	recorder := httptest.NewRecorder()
-	s.testServer.ServeHTTP(recorder, request)
-	c.Assert(recorder.Code, check.Equals, http.StatusOK)
-	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, "application/x-json-stream")
-	c.Assert(recorder.Body.String(), check.Matches,
-		`{"Message":".*---- Restarting the app \\"stress\\" ----\\n","Timestamp":".*"}`+"\n"+
-			`{"Message":".*restarting app","Timestamp":".*"}`+"\n",
-	)
+	rsp, err := http.DefaultClient.Do(request)
+	c.Assert(err, check.IsNil)
+	defer rsp.Body.Close()
+	c.Assert(rsp.StatusCode, check.Equals, http.StatusSwitchingProtocols)
+	c.Assert(rsp.Header.Get("Content-Type"), check.Equals, "application/vnd.tsuru.raw-stream")
+	data, err := ioutil.ReadAll(rsp.Body)
+	c.Assert(err, check.IsNil)
+	c.Assert(string(data), check.Equals, "debugging unit "+units[0].ID)
+	debugs := s.provisioner.Debugs(units[0].ID)
+	c.Assert(debugs, check.HasLen, 1)
+	c.Assert(debugs[0].Image, check.Equals, "busybox")
+	c.Assert(debugs[0].Width, check.Equals, 140)
+	c.Assert(debugs[0].Height, check.Equals, 38)
+	c.Assert(debugs[0].Term, check.Equals, "xterm")
+	c.Assert(s.provisioner.Debugs(units[1].ID), check.HasLen, 0)
 	c.Assert(eventtest.EventDesc{
 		Target: appTarget(a.Name),
 		Owner:  s.token.GetUserName(),
-		Kind:   "app.update.restart",
+		Kind:   "app.run.shell",
 		StartCustomData: []map[string]interface{}{
-			{"name": ":app", "value": a.Name},
+			{"name": "unit", "value": units[0].ID},
+			{"name": "debug", "value": true},
+			{"name": "image", "value": "busybox"},
 		},
 	}, eventtest.HasEvent)
 }
 
-func (s *S) TestRestartHandlerSingleProcess(c *check.C) {
-	config.Set("docker:router", "fake")
-	defer config.Unset("docker:router")
+func (s *S) TestDebugUnitHandlerImageWithoutDeployPermission(c *check.C) {
 	a := app.App{
 		Name:      "stress",
 		Platform:  "zend",
@@ -5858,60 +6934,62 @@ func (s *S) TestRestartHandlerSingleProcess(c *check.C) {
 	}
 	err := app.CreateApp(context.TODO(), &a, s.user)
 	c.Assert(err, check.IsNil)
-	newSuccessfulAppVersion(c, &a)
-	url := fmt.Sprintf("/apps/%s/restart", a.Name)
-	body := strings.NewReader("process=web")
+	s.provisioner.AddUnits(context.TODO(), &a, 1, "web", nil, nil)
+	units := s.provisioner.GetUnits(&a)
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermAppRunShell,
+		Context: permission.Context(permTypes.CtxApp, a.Name),
+	})
+	server := httptest.NewServer(s.testServer)
+	defer server.Close()
+	body := strings.NewReader("image=evil/image")
+	url := fmt.Sprintf("%s/1.13/apps/%s/units/%s/debug", server.URL, a.Name, units[0].ID)
 	request, err := http.NewRequest("POST", url, body)
 	c.Assert(err, check.IsNil)
-	request.Header.Set("Authorization", "b "+s.token.GetValue())
 	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	recorder := httptest.NewRecorder()
-	s.testServer.ServeHTTP(recorder, request)
-	c.Assert(recorder.Code, check.Equals, http.StatusOK)
-	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, "application/x-json-stream")
-	c.Assert(recorder.Body.String(), check.Matches,
-		`{"Message":".*---- Restarting process \\"web\\" ----\\n","Timestamp":".*"}`+"\n"+
-			`{"Message":".*restarting app","Timestamp":".*"}`+"\n",
-	)
-	restarts := s.provisioner.Restarts(&a, "web")
-	c.Assert(restarts, check.Equals, 1)
-	restarts = s.provisioner.Restarts(&a, "worker")
-	c.Assert(restarts, check.Equals, 0)
-	c.Assert(eventtest.EventDesc{
-		Target: appTarget(a.Name),
-		Owner:  s.token.GetUserName(),
-		Kind:   "app.update.restart",
-		StartCustomData: []map[string]interface{}{
-			{"name": ":app", "value": a.Name},
-			{"name": "process", "value": "web"},
-		},
-	}, eventtest.HasEvent)
+	request.Header.Set("Authorization", "b "+token.GetValue())
+	rsp, err := http.DefaultClient.Do(request)
+	c.Assert(err, check.IsNil)
+	defer rsp.Body.Close()
+	c.Assert(rsp.StatusCode, check.Equals, http.StatusSwitchingProtocols)
+	_, err = ioutil.ReadAll(rsp.Body)
+	c.Assert(err, check.IsNil)
+	debugs := s.provisioner.Debugs(units[0].ID)
+	c.Assert(debugs, check.HasLen, 1)
+	c.Assert(debugs[0].Image, check.Equals, "")
 }
 
-func (s *S) TestRestartHandlerReturns404IfTheAppDoesNotExist(c *check.C) {
-	request, err := http.NewRequest("GET", "/apps/unknown/restart?:app=unknown", nil)
+func (s *S) TestDebugUnitHandlerUnitNotFound(c *check.C) {
+	a := app.App{
+		Name:      "stress",
+		Platform:  "zend",
+		TeamOwner: s.team.Name,
+	}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	url := fmt.Sprintf("/1.13/apps/%s/units/unknown/debug", a.Name)
+	request, err := http.NewRequest("POST", url, nil)
 	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
 	recorder := httptest.NewRecorder()
-	err = restart(recorder, request, s.token)
-	c.Assert(err, check.NotNil)
-	e, ok := err.(*errors.HTTP)
-	c.Assert(ok, check.Equals, true)
-	c.Assert(e.Code, check.Equals, http.StatusNotFound)
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNotFound)
+	c.Assert(recorder.Body.String(), check.Equals, "unit \"unknown\" not found\n")
 }
 
-func (s *S) TestRestartHandlerReturns403IfTheUserDoesNotHaveAccessToTheApp(c *check.C) {
+func (s *S) TestDebugUnitHandlerForbidden(c *check.C) {
 	a := app.App{Name: "nightmist"}
 	err := s.conn.Apps().Insert(a)
 	c.Assert(err, check.IsNil)
 	token := userWithPermission(c, permission.Permission{
-		Scheme:  permission.PermAppUpdateRestart,
+		Scheme:  permission.PermAppRunShell,
 		Context: permission.Context(permTypes.CtxApp, "-invalid-"),
 	})
-	url := fmt.Sprintf("/apps/%s/restart?:app=%s", a.Name, a.Name)
-	request, err := http.NewRequest("GET", url, nil)
+	url := fmt.Sprintf("/apps/%s/units/unit1/debug?:app=%s&:unit=unit1", a.Name, a.Name)
+	request, err := http.NewRequest("POST", url, nil)
 	c.Assert(err, check.IsNil)
 	recorder := httptest.NewRecorder()
-	err = restart(recorder, request, token)
+	err = debugUnit(recorder, request, token)
 	c.Assert(err, check.NotNil)
 	e, ok := err.(*errors.HTTP)
 	c.Assert(ok, check.Equals, true)
@@ -6157,6 +7235,48 @@ func (s *S) TestSwapCnameOnly(c *check.C) {
 	}, eventtest.HasEvent)
 }
 
+func (s *S) TestSwapSelectedCNames(c *check.C) {
+	app1 := app.App{Name: "app1", Platform: "zend", TeamOwner: s.team.Name, CName: []string{"app1.io", "www.app1.io"}}
+	err := app.CreateApp(context.TODO(), &app1, s.user)
+	c.Assert(err, check.IsNil)
+	app2 := app.App{Name: "app2", Platform: "zend", TeamOwner: s.team.Name, CName: []string{"app2.io"}}
+	err = app.CreateApp(context.TODO(), &app2, s.user)
+	c.Assert(err, check.IsNil)
+	b := strings.NewReader("app1=app1&app2=app2&cname=www.app1.io")
+	request, err := http.NewRequest("POST", "/swap", b)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK, check.Commentf("body: %s", recorder.Body.String()))
+	var dbApp app.App
+	err = s.conn.Apps().Find(bson.M{"name": app1.Name}).One(&dbApp)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.CName, check.DeepEquals, []string{"app1.io"})
+	err = s.conn.Apps().Find(bson.M{"name": app2.Name}).One(&dbApp)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.CName, check.DeepEquals, []string{"app2.io", "www.app1.io"})
+}
+
+func (s *S) TestSwapSelectedCNamesInvalid(c *check.C) {
+	app1 := app.App{Name: "app1", Platform: "zend", TeamOwner: s.team.Name, CName: []string{"app1.io"}}
+	err := app.CreateApp(context.TODO(), &app1, s.user)
+	c.Assert(err, check.IsNil)
+	app2 := app.App{Name: "app2", Platform: "zend", TeamOwner: s.team.Name}
+	err = app.CreateApp(context.TODO(), &app2, s.user)
+	c.Assert(err, check.IsNil)
+	b := strings.NewReader("app1=app1&app2=app2&cname=other.io")
+	request, err := http.NewRequest("POST", "/swap", b)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(recorder.Body.String(), check.Equals, "cname \"other.io\" doesn't belong to \"app1\" or \"app2\"\n")
+}
+
 func (s *S) TestSwapApp1Locked(c *check.C) {
 	app1 := app.App{Name: "app1", Platform: "zend", TeamOwner: s.team.Name}
 	err := app.CreateApp(context.TODO(), &app1, s.user)
@@ -6850,3 +7970,191 @@ func (s *S) TestFollowLogs(c *check.C) {
 	c.Assert(msgSlice, check.HasLen, 1)
 	c.Assert(msgSlice[0].Message, check.Equals, "xyz")
 }
+
+func (s *S) TestUnitsMetricsHandler(c *check.C) {
+	a := app.App{Name: "stress", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	s.provisioner.AddUnits(context.TODO(), &a, 2, "web", nil, nil)
+	units := s.provisioner.GetUnits(&a)
+	request, err := http.NewRequest("GET", "/1.13/apps/stress/units/metrics", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, "application/json")
+	var metrics []provision.UnitMetric
+	err = json.Unmarshal(recorder.Body.Bytes(), &metrics)
+	c.Assert(err, check.IsNil)
+	c.Assert(metrics, check.DeepEquals, []provision.UnitMetric{
+		{ID: units[0].ID, CPU: "10m", Memory: "100Mi"},
+		{ID: units[1].ID, CPU: "10m", Memory: "100Mi"},
+	})
+}
+
+func (s *S) TestUnitsMetricsHandlerNoUnits(c *check.C) {
+	a := app.App{Name: "stress", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest("GET", "/1.13/apps/stress/units/metrics", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(recorder.Body.String(), check.Equals, "[]\n")
+}
+
+func (s *S) TestAppResourceRecommendationsHandler(c *check.C) {
+	provision.DefaultProvisioner = "autoscaleProv"
+	provision.Register("autoscaleProv", func() (provision.Provisioner, error) {
+		return &provisiontest.AutoScaleProvisioner{FakeProvisioner: provisiontest.ProvisionerInstance}, nil
+	})
+	defer provision.Unregister("autoscaleProv")
+	s.plan = appTypes.Plan{Name: "small", Memory: 128 * 1024 * 1024, CPUMilli: 200}
+	a := app.App{Name: "stress", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	err = a.AutoScale(provision.AutoScaleSpec{Process: "p1"})
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermAppRead,
+		Context: permission.Context(permTypes.CtxApp, a.Name),
+	})
+	request, err := http.NewRequest("GET", "/1.13/apps/stress/resources/recommendations", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, "application/json")
+	var recommendations []app.ResourceRecommendation
+	err = json.Unmarshal(recorder.Body.Bytes(), &recommendations)
+	c.Assert(err, check.IsNil)
+	c.Assert(recommendations, check.DeepEquals, []app.ResourceRecommendation{
+		{
+			Process:             "p1",
+			Plan:                "default-plan",
+			Memory:              1024,
+			RecommendedCPUMilli: 100,
+			RecommendedMemory:   100 * 1024 * 1024,
+			SuggestedPlan:       "small",
+		},
+	})
+}
+
+func (s *S) TestAppResourceRecommendationsHandlerNoRecommendations(c *check.C) {
+	a := app.App{Name: "stress", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest("GET", "/1.13/apps/stress/resources/recommendations", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(recorder.Body.String(), check.Equals, "[]\n")
+}
+
+func (s *S) TestAppResourceRecommendationsHandlerForbidden(c *check.C) {
+	a := app.App{Name: "stress", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermAppRead,
+		Context: permission.Context(permTypes.CtxApp, "-invalid-"),
+	})
+	request, err := http.NewRequest("GET", "/1.13/apps/stress/resources/recommendations", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
+
+func (s *S) TestUnitsMetricsHandlerForbidden(c *check.C) {
+	a := app.App{Name: "stress", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermAppRead,
+		Context: permission.Context(permTypes.CtxApp, "-invalid-"),
+	})
+	request, err := http.NewRequest("GET", "/1.13/apps/stress/units/metrics", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
+
+func (s *S) TestAppTimeline(c *check.C) {
+	a := app.App{Name: "myapp", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	for _, kind := range []*permission.PermissionScheme{permission.PermAppDeploy, permission.PermAppUpdateDescription, permission.PermAppUpdateEnvSet} {
+		evt, errEvt := event.New(&event.Opts{
+			Target:  appTarget(a.Name),
+			Owner:   s.token,
+			Kind:    kind,
+			Allowed: event.Allowed(permission.PermAppReadEvents),
+		})
+		c.Assert(errEvt, check.IsNil)
+		c.Assert(evt.Done(nil), check.IsNil)
+	}
+	request, err := http.NewRequest("GET", "/1.13/apps/myapp/timeline?limit=1", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, "application/json")
+	var entries []map[string]interface{}
+	err = json.Unmarshal(recorder.Body.Bytes(), &entries)
+	c.Assert(err, check.IsNil)
+	c.Assert(entries, check.HasLen, 1)
+	c.Assert(entries[0]["Category"], check.Equals, "env")
+	link := recorder.Header().Get("Link")
+	c.Assert(link, check.Matches, `<.*/apps/myapp/timeline\?after=[0-9a-f]+&limit=1>; rel="next"`)
+	request, err = http.NewRequest("GET", "/1.13/apps/myapp/timeline?category=deploy", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder = httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	err = json.Unmarshal(recorder.Body.Bytes(), &entries)
+	c.Assert(err, check.IsNil)
+	c.Assert(entries, check.HasLen, 1)
+	c.Assert(entries[0]["Category"], check.Equals, "deploy")
+	c.Assert(recorder.Header().Get("Link"), check.Equals, "")
+}
+
+func (s *S) TestAppTimelineInvalidCategory(c *check.C) {
+	a := app.App{Name: "myapp", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest("GET", "/1.13/apps/myapp/timeline?category=nope", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(recorder.Body.String(), check.Matches, `invalid timeline category "nope".*\n`)
+}
+
+func (s *S) TestAppTimelineForbidden(c *check.C) {
+	a := app.App{Name: "myapp", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermAppReadEvents,
+		Context: permission.Context(permTypes.CtxApp, "-invalid-"),
+	})
+	request, err := http.NewRequest("GET", "/1.13/apps/myapp/timeline", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
@@ -0,0 +1,116 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/tsuru/tsuru/app"
+	"github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/log"
+)
+
+// asyncOperations tracks the operations running in background after
+// responding to requests with async=true, so that the shutdown waits for them.
+var asyncOperations = &operationTracker{}
+
+// asyncLogInterval is how often the log of async operations is stored in
+// their events.
+var asyncLogInterval = 5 * time.Second
+
+// isAsync returns whether the request asks, through the async query string
+// parameter, to run the operation in background, responding as soon as its
+// event is created.
+func isAsync(r *http.Request) (bool, error) {
+	value := r.URL.Query().Get("async")
+	if value == "" {
+		return false, nil
+	}
+	async, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, &errors.HTTP{Code: http.StatusBadRequest, Message: "invalid value for async, expected a boolean"}
+	}
+	return async, nil
+}
+
+// asyncResponse is the response to requests with async=true, the progress of
+// the operation is retrieved with the event handlers.
+type asyncResponse struct {
+	EventID string `json:"eventID"`
+}
+
+// runAsync responds with 202 and the ID of evt, running fn in background.
+// The context given to fn isn't canceled with the request, but it's canceled
+// when evt is canceled. evt is done with the custom data and the error
+// returned by fn, and its log is stored periodically while fn runs.
+func runAsync(w http.ResponseWriter, evt *event.Event, fn func(ctx context.Context) (interface{}, error)) error {
+	asyncOperations.add()
+	ctx, cancel := evt.CancelableContext(context.Background())
+	go func() {
+		defer asyncOperations.done()
+		defer cancel()
+		stop := make(chan struct{})
+		finished := make(chan struct{})
+		go func() {
+			defer close(finished)
+			for {
+				select {
+				case <-stop:
+					return
+				case <-time.After(asyncLogInterval):
+				}
+				if err := evt.SaveLog(); err != nil {
+					log.Errorf("[async] unable to store log of event %s: %v", evt.UniqueID.Hex(), err)
+				}
+			}
+		}()
+		customData, err := fn(ctx)
+		close(stop)
+		<-finished
+		if err != nil {
+			log.Errorf("[async] event %s failed: %v", evt.UniqueID.Hex(), err)
+		}
+		evt.DoneCustomData(err, customData)
+	}()
+	eventID := evt.UniqueID.Hex()
+	w.Header().Set(eventIDHeader, eventID)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	return json.NewEncoder(w).Encode(asyncResponse{EventID: eventID})
+}
+
+// detachUploadedFile copies the file uploaded in a deploy to a temporary
+// file, as uploaded files are removed when the request finishes. The returned
+// function closes and removes the copy, the original file is left open.
+func detachUploadedFile(opts *app.DeployOptions) (func(), error) {
+	if opts.File == nil {
+		return func() {}, nil
+	}
+	tmp, err := os.CreateTemp("", "tsuru-deploy-")
+	if err != nil {
+		return nil, err
+	}
+	cleanup := func() {
+		tmp.Close()
+		os.Remove(tmp.Name())
+	}
+	_, err = io.Copy(tmp, opts.File)
+	if err == nil {
+		_, err = tmp.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		cleanup()
+		return nil, err
+	}
+	opts.File = tmp
+	return cleanup, nil
+}
@@ -0,0 +1,105 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/tsuru/tsuru/app"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/event/eventtest"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestIsAsync(c *check.C) {
+	tests := []struct {
+		url      string
+		expected bool
+		err      string
+	}{
+		{url: "/apps", expected: false},
+		{url: "/apps?async=true", expected: true},
+		{url: "/apps?async=1", expected: true},
+		{url: "/apps?async=false", expected: false},
+		{url: "/apps?async=later", err: "invalid value for async, expected a boolean"},
+	}
+	for _, tt := range tests {
+		request, err := http.NewRequest("POST", tt.url, nil)
+		c.Assert(err, check.IsNil)
+		async, err := isAsync(request)
+		if tt.err != "" {
+			c.Assert(err, check.ErrorMatches, tt.err)
+			continue
+		}
+		c.Assert(err, check.IsNil)
+		c.Assert(async, check.Equals, tt.expected)
+	}
+}
+
+// waitAsyncOperations waits for the operations started by requests with
+// async=true to finish.
+func waitAsyncOperations(c *check.C) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err := asyncOperations.wait(ctx)
+	c.Assert(err, check.IsNil)
+}
+
+// asyncEventID returns the ID of the event in the response to a request with
+// async=true.
+func asyncEventID(c *check.C, recorder *httptest.ResponseRecorder) string {
+	c.Assert(recorder.Code, check.Equals, http.StatusAccepted)
+	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, "application/json")
+	var result asyncResponse
+	err := json.Unmarshal(recorder.Body.Bytes(), &result)
+	c.Assert(err, check.IsNil)
+	c.Assert(result.EventID, check.Not(check.Equals), "")
+	c.Assert(recorder.Header().Get(eventIDHeader), check.Equals, result.EventID)
+	return result.EventID
+}
+
+func (s *S) TestCreateAppAsync(c *check.C) {
+	s.setupMockForCreateApp(c, "zend")
+	request, err := http.NewRequest("POST", "/apps?async=true", strings.NewReader("name=someapp&platform=zend"))
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	eventID := asyncEventID(c, recorder)
+	waitAsyncOperations(c)
+	gotApp, err := app.GetByName(context.TODO(), "someapp")
+	c.Assert(err, check.IsNil)
+	c.Assert(gotApp.Platform, check.Equals, "zend")
+	evt, err := event.GetByHexID(eventID)
+	c.Assert(err, check.IsNil)
+	c.Assert(evt.Running, check.Equals, false)
+	c.Assert(evt.Error, check.Equals, "")
+	c.Assert(eventtest.EventDesc{
+		Target: appTarget("someapp"),
+		Owner:  s.token.GetUserName(),
+		Kind:   "app.create",
+	}, eventtest.HasEvent)
+}
+
+func (s *S) TestCreateAppAsyncAlreadyExists(c *check.C) {
+	s.setupMockForCreateApp(c, "zend")
+	a := app.App{Name: "someapp", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest("POST", "/apps?async=true", strings.NewReader("name=someapp&platform=zend"))
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusConflict)
+	s.assertNoEvents(c, appTarget("someapp"))
+}
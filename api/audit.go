@@ -0,0 +1,145 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	stdContext "context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/codegangsta/negroni"
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/api/context"
+	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/log"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/servicemanager"
+	auditTypes "github.com/tsuru/tsuru/types/audit"
+	authTypes "github.com/tsuru/tsuru/types/auth"
+)
+
+const (
+	auditQueueSize        = 1000
+	auditDefaultListLimit = 100
+)
+
+// auditMiddleware records the authenticated calls to the API in the audit
+// log. Entries are stored in background, so that API responses are not
+// delayed by the storage, and are dropped when the queue is full.
+type auditMiddleware struct {
+	entries chan auditTypes.Entry
+}
+
+func newAuditMiddleware() *auditMiddleware {
+	m := &auditMiddleware{entries: make(chan auditTypes.Entry, auditQueueSize)}
+	go m.run()
+	return m
+}
+
+func (m *auditMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	start := time.Now()
+	next(w, r)
+	t := context.GetAuthToken(r)
+	if t == nil {
+		return
+	}
+	entry := auditTypes.Entry{
+		Time:     start.UTC(),
+		Method:   r.Method,
+		Path:     r.URL.Query().Get(":mux-path-template"),
+		URL:      r.URL.Path,
+		Status:   auditStatus(w, r),
+		Latency:  time.Since(start),
+		SourceIP: auditSourceIP(r),
+		Owner:    t.GetUserName(),
+		TokenID:  auditTokenID(t),
+	}
+	if entry.Owner == "" {
+		entry.Owner = t.GetAppName()
+	}
+	if it, ok := t.(authTypes.ImpersonatedToken); ok {
+		entry.Impersonator = it.GetImpersonator()
+	}
+	if requestIDHeader, _ := config.GetString("request-id-header"); requestIDHeader != "" {
+		entry.RequestID = context.GetRequestID(r, requestIDHeader)
+	}
+	select {
+	case m.entries <- entry:
+	default:
+		log.Errorf("[audit] queue is full, dropping entry for %s %s", entry.Method, entry.URL)
+	}
+}
+
+func (m *auditMiddleware) run() {
+	for entry := range m.entries {
+		err := servicemanager.Audit.Add(stdContext.Background(), entry)
+		if err != nil {
+			log.Errorf("[audit] unable to store entry for %s %s: %v", entry.Method, entry.URL, err)
+		}
+	}
+}
+
+// auditStatus returns the status code of the response, hijacked websocket
+// connections don't have one.
+func auditStatus(w http.ResponseWriter, r *http.Request) int {
+	if rw, ok := w.(negroni.ResponseWriter); ok && rw.Status() != 0 {
+		return rw.Status()
+	}
+	if r.Header.Get("Upgrade") != "" {
+		return http.StatusSwitchingProtocols
+	}
+	return http.StatusOK
+}
+
+func auditSourceIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// auditTokenID identifies the token without exposing it.
+func auditTokenID(t auth.Token) string {
+	sum := sha256.Sum256([]byte(t.GetValue()))
+	return hex.EncodeToString(sum[:8])
+}
+
+// title: audit list
+// path: /audit
+// method: GET
+// produce: application/json
+// responses:
+//   200: OK
+//   204: No content
+//   400: Invalid data
+//   401: Unauthorized
+func auditList(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	if !permission.Check(t, permission.PermAuditRead) {
+		return permission.ErrUnauthorized
+	}
+	var filter auditTypes.Filter
+	err := ParseInput(r, &filter)
+	if err != nil {
+		return err
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = auditDefaultListLimit
+	}
+	entries, err := servicemanager.Audit.List(r.Context(), &filter)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(entries)
+}
@@ -0,0 +1,146 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	stdContext "context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/codegangsta/negroni"
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/api/context"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/servicemanager"
+	auditTypes "github.com/tsuru/tsuru/types/audit"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestAuditMiddleware(c *check.C) {
+	config.Set("request-id-header", "Request-ID")
+	defer config.Unset("request-id-header")
+	m := &auditMiddleware{entries: make(chan auditTypes.Entry, 1)}
+	request, err := http.NewRequest("POST", "/1.0/apps/myapp/restart?:mux-path-template=/apps/{app}/restart", nil)
+	c.Assert(err, check.IsNil)
+	request.RemoteAddr = "10.1.1.1:4321"
+	context.SetRequestID(request, "Request-ID", "req-1")
+	recorder := httptest.NewRecorder()
+	m.ServeHTTP(negroni.NewResponseWriter(recorder), request, func(w http.ResponseWriter, r *http.Request) {
+		context.SetAuthToken(r, s.token)
+		w.WriteHeader(http.StatusAccepted)
+	})
+	c.Assert(m.entries, check.HasLen, 1)
+	entry := <-m.entries
+	c.Assert(time.Since(entry.Time) < time.Minute, check.Equals, true)
+	entry.Time = time.Time{}
+	c.Assert(entry.Latency > 0, check.Equals, true)
+	entry.Latency = 0
+	c.Assert(entry, check.DeepEquals, auditTypes.Entry{
+		Method:    "POST",
+		Path:      "/apps/{app}/restart",
+		URL:       "/1.0/apps/myapp/restart",
+		Status:    http.StatusAccepted,
+		SourceIP:  "10.1.1.1",
+		RequestID: "req-1",
+		Owner:     s.user.Email,
+		TokenID:   auditTokenID(s.token),
+	})
+	c.Assert(entry.TokenID, check.HasLen, 16)
+	c.Assert(entry.TokenID, check.Not(check.Equals), s.token.GetValue())
+}
+
+func (s *S) TestAuditMiddlewareDefaultStatus(c *check.C) {
+	m := &auditMiddleware{entries: make(chan auditTypes.Entry, 1)}
+	request, err := http.NewRequest("GET", "/1.0/apps", nil)
+	c.Assert(err, check.IsNil)
+	recorder := httptest.NewRecorder()
+	m.ServeHTTP(negroni.NewResponseWriter(recorder), request, func(w http.ResponseWriter, r *http.Request) {
+		context.SetAuthToken(r, s.token)
+	})
+	entry := <-m.entries
+	c.Assert(entry.Status, check.Equals, http.StatusOK)
+}
+
+func (s *S) TestAuditMiddlewareUnauthenticated(c *check.C) {
+	m := &auditMiddleware{entries: make(chan auditTypes.Entry, 1)}
+	request, err := http.NewRequest("GET", "/1.0/apps", nil)
+	c.Assert(err, check.IsNil)
+	recorder := httptest.NewRecorder()
+	m.ServeHTTP(negroni.NewResponseWriter(recorder), request, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+	c.Assert(m.entries, check.HasLen, 0)
+}
+
+func (s *S) TestAuditMiddlewareQueueFull(c *check.C) {
+	m := &auditMiddleware{entries: make(chan auditTypes.Entry, 1)}
+	m.entries <- auditTypes.Entry{URL: "/previous"}
+	request, err := http.NewRequest("GET", "/1.0/apps", nil)
+	c.Assert(err, check.IsNil)
+	recorder := httptest.NewRecorder()
+	m.ServeHTTP(negroni.NewResponseWriter(recorder), request, func(w http.ResponseWriter, r *http.Request) {
+		context.SetAuthToken(r, s.token)
+		w.WriteHeader(http.StatusOK)
+	})
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(m.entries, check.HasLen, 1)
+	entry := <-m.entries
+	c.Assert(entry.URL, check.Equals, "/previous")
+}
+
+func (s *S) TestAuditList(c *check.C) {
+	now := time.Now().UTC().Truncate(time.Second)
+	entries := []auditTypes.Entry{
+		{Time: now.Add(-time.Minute), Method: "GET", Path: "/apps", URL: "/1.0/apps", Status: 200, Owner: "a@example.com"},
+		{Time: now, Method: "DELETE", Path: "/apps/{app}", URL: "/1.0/apps/myapp", Status: 200, Owner: "a@example.com"},
+		{Time: now, Method: "GET", Path: "/apps", URL: "/1.0/apps", Status: 200, Owner: "b@example.com"},
+	}
+	for _, e := range entries {
+		err := servicemanager.Audit.Add(stdContext.TODO(), e)
+		c.Assert(err, check.IsNil)
+	}
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermAuditRead,
+		Context: permission.Context(permTypes.CtxGlobal, ""),
+	})
+	request, err := http.NewRequest("GET", "/1.13/audit?owner=a@example.com", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, "application/json")
+	var result []auditTypes.Entry
+	err = json.Unmarshal(recorder.Body.Bytes(), &result)
+	c.Assert(err, check.IsNil)
+	c.Assert(result, check.HasLen, 2)
+	c.Assert(result[0].Method, check.Equals, "DELETE")
+	c.Assert(result[1].Method, check.Equals, "GET")
+}
+
+func (s *S) TestAuditListNoContent(c *check.C) {
+	request, err := http.NewRequest("GET", "/1.13/audit?method=PATCH", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNoContent)
+}
+
+func (s *S) TestAuditListWithoutPermission(c *check.C) {
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermAppRead,
+		Context: permission.Context(permTypes.CtxGlobal, ""),
+	})
+	request, err := http.NewRequest("GET", "/1.13/audit", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
@@ -11,6 +11,8 @@ import (
 	"net/http"
 	"reflect"
 	"runtime"
+	"strings"
+	"time"
 
 	"github.com/tsuru/config"
 	"github.com/tsuru/tsuru/app"
@@ -30,6 +32,10 @@ import (
 const (
 	nonManagedSchemeMsg = "Authentication scheme does not allow this operation."
 	createDisabledMsg   = "User registration is disabled for non-admin users."
+
+	// loginEventKind is the internal kind of the events recording login
+	// attempts.
+	loginEventKind = "user.login"
 )
 
 var createDisabledErr = &errors.HTTP{Code: http.StatusUnauthorized, Message: createDisabledMsg}
@@ -38,6 +44,9 @@ func handleAuthError(err error) error {
 	if err == authTypes.ErrUserNotFound {
 		return &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
 	}
+	if err == auth.ErrTwoFactorRequired {
+		return &errors.HTTP{Code: http.StatusUnauthorized, Message: err.Error(), ErrorCode: "auth.two-factor-required"}
+	}
 	switch err.(type) {
 	case *errors.ValidationError:
 		return &errors.HTTP{Code: http.StatusBadRequest, Message: err.Error()}
@@ -47,6 +56,8 @@ func handleAuthError(err error) error {
 		return &errors.HTTP{Code: http.StatusForbidden, Message: err.Error()}
 	case auth.AuthenticationFailure:
 		return &errors.HTTP{Code: http.StatusUnauthorized, Message: err.Error()}
+	case *auth.AccountLockedError:
+		return &errors.HTTP{Code: http.StatusTooManyRequests, Message: err.Error(), ErrorCode: "auth.account-locked"}
 	default:
 		return err
 	}
@@ -130,12 +141,54 @@ func login(w http.ResponseWriter, r *http.Request) (err error) {
 		params[key] = values[0]
 	}
 	token, err := app.AuthScheme.Login(ctx, params)
+	email := params["email"]
+	if token != nil {
+		email = token.GetUserName()
+	}
+	registerLoginAttempt(r, email, err)
 	if err != nil {
 		return handleAuthError(err)
 	}
 	return json.NewEncoder(w).Encode(map[string]string{"token": token.GetValue()})
 }
 
+// registerLoginAttempt records the login attempt in an event targeting the
+// user, failed attempts are stored with the error returned by the scheme.
+// Attempts against unknown emails are not recorded, so anonymous requests
+// can't fill the event storage.
+func registerLoginAttempt(r *http.Request, email string, loginErr error) {
+	if email == "" {
+		return
+	}
+	if _, err := auth.GetUserByEmail(email); err != nil {
+		if err != authTypes.ErrUserNotFound {
+			log.Debugf("not registering login attempt of %q: %v", email, err)
+		}
+		return
+	}
+	data := map[string]string{
+		"scheme":  app.AuthScheme.Name(),
+		"address": auditSourceIP(r),
+	}
+	if provider := InputValue(r, "provider"); provider != "" {
+		data["provider"] = provider
+	}
+	evt, err := event.NewInternal(&event.Opts{
+		Target:       userTarget(email),
+		InternalKind: loginEventKind,
+		RawOwner:     event.Owner{Type: event.OwnerTypeUser, Name: email},
+		RemoteAddr:   r.RemoteAddr,
+		DisableLock:  true,
+		CustomData:   data,
+		Allowed: event.Allowed(permission.PermUserReadEvents, permission.Context(permTypes.CtxUser, email)),
+	})
+	if err != nil {
+		log.Errorf("unable to register login attempt of %q: %v", email, err)
+		return
+	}
+	evt.Done(loginErr)
+}
+
 // title: logout
 // path: /users/tokens
 // method: DELETE
@@ -156,6 +209,9 @@ func logout(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
 //   403: Forbidden
 //   404: Not found
 func changePassword(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	if auth.IsImpersonated(t) {
+		return errImpersonated
+	}
 	ctx := r.Context()
 	managed, ok := app.AuthScheme.(auth.ManagedScheme)
 	if !ok {
@@ -166,12 +222,16 @@ func changePassword(w http.ResponseWriter, r *http.Request, t auth.Token) (err e
 		Kind:       permission.PermUserUpdatePassword,
 		Owner:      t,
 		RemoteAddr: r.RemoteAddr,
+		CustomData: event.FormToCustomData(InputFields(r, "old", "new", "confirm", "otp")),
 		Allowed:    event.Allowed(permission.PermUserReadEvents, permission.Context(permTypes.CtxUser, t.GetUserName())),
 	})
 	if err != nil {
 		return err
 	}
 	defer func() { evt.Done(err) }()
+	if err = checkTwoFactor(r, t); err != nil {
+		return err
+	}
 	oldPassword := InputValue(r, "old")
 	newPassword := InputValue(r, "new")
 	confirmPassword := InputValue(r, "confirm")
@@ -242,6 +302,7 @@ var teamRenameFns = []func(ctx context.Context, oldName, newName string) error{
 	service.RenameServiceInstanceTeam,
 	volume.RenameTeam,
 	pool.RenamePoolTeam,
+	auth.RenameParentTeam,
 }
 
 // title: team update
@@ -272,7 +333,7 @@ func updateTeam(w http.ResponseWriter, r *http.Request, t auth.Token) error {
 	if !allowed {
 		return permission.ErrUnauthorized
 	}
-	_, err := servicemanager.Team.FindByName(ctx, name)
+	team, err := servicemanager.Team.FindByName(ctx, name)
 	if err != nil {
 		if err == authTypes.ErrTeamNotFound {
 			return &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
@@ -319,6 +380,18 @@ func updateTeam(w http.ResponseWriter, r *http.Request, t auth.Token) error {
 			}
 		}
 	}()
+	if len(team.AllowedCIDRs) > 0 {
+		err = servicemanager.Team.SetAllowedCIDRs(ctx, changeRequest.NewName, team.AllowedCIDRs)
+		if err != nil {
+			return err
+		}
+	}
+	if team.Parent != "" {
+		err = servicemanager.Team.SetParent(ctx, changeRequest.NewName, team.Parent)
+		if err != nil {
+			return err
+		}
+	}
 	for _, fn := range teamRenameFns {
 		err = fn(ctx, name, changeRequest.NewName)
 		if err != nil {
@@ -329,6 +402,54 @@ func updateTeam(w http.ResponseWriter, r *http.Request, t auth.Token) error {
 	return servicemanager.Team.Remove(ctx, name)
 }
 
+// title: set team parent
+// path: /teams/{name}/parent
+// method: PUT
+// consume: application/x-www-form-urlencoded
+// responses:
+//   200: Team parent updated
+//   400: Invalid data
+//   401: Unauthorized
+//   404: Team not found
+func setTeamParent(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	ctx := r.Context()
+	name := r.URL.Query().Get(":name")
+	parent := strings.TrimSpace(InputValue(r, "parent"))
+	team, err := servicemanager.Team.FindByName(ctx, name)
+	if err == authTypes.ErrTeamNotFound {
+		return &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+	}
+	if err != nil {
+		return err
+	}
+	// Nesting a team grants the admins of the new parent access to it, so
+	// the permission is required on the team and on both of its parents.
+	for _, teamName := range []string{name, team.Parent, parent} {
+		if teamName == "" {
+			continue
+		}
+		allowed := permission.Check(t, permission.PermTeamUpdateParent,
+			permission.Context(permTypes.CtxTeam, teamName),
+		)
+		if !allowed {
+			return permission.ErrUnauthorized
+		}
+	}
+	evt, err := event.New(&event.Opts{
+		Target:     teamTarget(name),
+		Kind:       permission.PermTeamUpdateParent,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		CustomData: event.FormToCustomData(InputFields(r)),
+		Allowed:    event.Allowed(permission.PermTeamReadEvents, permission.Context(permTypes.CtxTeam, name)),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	return servicemanager.Team.SetParent(ctx, name, parent)
+}
+
 // title: team create
 // path: /teams
 // method: POST
@@ -429,6 +550,7 @@ func removeTeam(w http.ResponseWriter, r *http.Request, t auth.Token) (err error
 // responses:
 //   200: List teams
 //   204: No content
+//   400: Invalid data
 //   401: Unauthorized
 func teamList(w http.ResponseWriter, r *http.Request, t auth.Token) error {
 	ctx := r.Context()
@@ -437,14 +559,12 @@ func teamList(w http.ResponseWriter, r *http.Request, t auth.Token) error {
 	if err != nil {
 		return err
 	}
-	teamsMap := map[string]authTypes.Team{}
 	permsMap := map[string][]string{}
 	perms, err := t.Permissions()
 	if err != nil {
 		return err
 	}
 	for _, team := range teams {
-		teamsMap[team.Name] = team
 		teamCtx := permission.Context(permTypes.CtxTeam, team.Name)
 		var parent *permission.PermissionScheme
 		for _, p := range permsForTeam {
@@ -457,18 +577,27 @@ func teamList(w http.ResponseWriter, r *http.Request, t auth.Token) error {
 			}
 		}
 	}
-	if len(permsMap) == 0 {
-		w.WriteHeader(http.StatusNoContent)
-		return nil
-	}
 	var result []map[string]interface{}
-	for name, permissions := range permsMap {
+	for _, team := range teams {
+		permissions, ok := permsMap[team.Name]
+		if !ok {
+			continue
+		}
 		result = append(result, map[string]interface{}{
-			"name":        name,
-			"tags":        teamsMap[name].Tags,
+			"name":        team.Name,
+			"tags":        team.Tags,
 			"permissions": permissions,
 		})
 	}
+	start, end, err := paginate(w, r, len(result))
+	if err != nil {
+		return err
+	}
+	result = result[start:end]
+	if len(result) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
 	w.Header().Set("Content-Type", "application/json")
 	return json.NewEncoder(w).Encode(result)
 }
@@ -556,6 +685,9 @@ func teamInfo(w http.ResponseWriter, r *http.Request, t auth.Token) error {
 //   401: Unauthorized
 //   404: Not found
 func removeUser(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	if auth.IsImpersonated(t) {
+		return errImpersonated
+	}
 	ctx := r.Context()
 	email := r.URL.Query().Get("user")
 	if email == "" {
@@ -572,18 +704,38 @@ func removeUser(w http.ResponseWriter, r *http.Request, t auth.Token) (err error
 		Kind:       permission.PermUserDelete,
 		Owner:      t,
 		RemoteAddr: r.RemoteAddr,
-		CustomData: event.FormToCustomData(InputFields(r)),
+		CustomData: event.FormToCustomData(InputFields(r, "otp")),
 		Allowed:    event.Allowed(permission.PermUserReadEvents, permission.Context(permTypes.CtxUser, email)),
 	})
 	if err != nil {
 		return err
 	}
 	defer func() { evt.Done(err) }()
+	if err = checkTwoFactor(r, t); err != nil {
+		return err
+	}
 	u, err := auth.GetUserByEmail(email)
 	if err != nil {
 		return &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
 	}
-	return app.AuthScheme.Remove(ctx, u)
+	// Events are tombstoned before removing the user, so a failure doesn't
+	// leave events owned by a removed user behind.
+	_, err = event.TombstoneUserOwner(email)
+	if err != nil {
+		return err
+	}
+	err = app.AuthScheme.Remove(ctx, u)
+	if err != nil {
+		_, restoreErr := event.ReassignOwner(
+			event.Owner{Type: event.OwnerTypeRemovedUser, Name: email},
+			event.Owner{Type: event.OwnerTypeUser, Name: email},
+		)
+		if restoreErr != nil {
+			log.Errorf("unable to restore owner of events of user %q: %v", email, restoreErr)
+		}
+		return err
+	}
+	return nil
 }
 
 type schemeData struct {
@@ -616,6 +768,9 @@ func authScheme(w http.ResponseWriter, r *http.Request) error {
 //   401: Unauthorized
 //   404: User not found
 func regenerateAPIToken(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	if auth.IsImpersonated(t) {
+		return errImpersonated
+	}
 	email := r.URL.Query().Get("user")
 	if email == "" {
 		email = t.GetUserName()
@@ -631,13 +786,16 @@ func regenerateAPIToken(w http.ResponseWriter, r *http.Request, t auth.Token) (e
 		Kind:       permission.PermUserUpdateToken,
 		Owner:      t,
 		RemoteAddr: r.RemoteAddr,
-		CustomData: event.FormToCustomData(InputFields(r)),
+		CustomData: event.FormToCustomData(InputFields(r, "otp")),
 		Allowed:    event.Allowed(permission.PermUserReadEvents, permission.Context(permTypes.CtxUser, email)),
 	})
 	if err != nil {
 		return err
 	}
 	defer func() { evt.Done(err) }()
+	if err = checkTwoFactor(r, t); err != nil {
+		return err
+	}
 	u, err := auth.GetUserByEmail(email)
 	if err != nil {
 		return &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
@@ -659,6 +817,9 @@ func regenerateAPIToken(w http.ResponseWriter, r *http.Request, t auth.Token) (e
 //   401: Unauthorized
 //   404: User not found
 func showAPIToken(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	if auth.IsImpersonated(t) {
+		return errImpersonated
+	}
 	u, err := auth.ConvertNewUser(t.User())
 	if err != nil {
 		return err
@@ -685,7 +846,8 @@ type rolePermissionData struct {
 	Name         string
 	ContextType  string
 	ContextValue string
-	Group        string `json:",omitempty"`
+	Group        string     `json:",omitempty"`
+	ExpiresAt    *time.Time `json:",omitempty"`
 }
 
 type apiUser struct {
@@ -693,6 +855,7 @@ type apiUser struct {
 	Roles       []rolePermissionData
 	Permissions []rolePermissionData
 	Groups      []string
+	Origin      string `json:",omitempty"`
 }
 
 func createAPIUser(perms []permission.Permission, user *auth.User, roleMap map[string]*permission.Role, includeAll bool) (*apiUser, error) {
@@ -704,10 +867,15 @@ func createAPIUser(perms []permission.Permission, user *auth.User, roleMap map[s
 	apiUsr := &apiUser{
 		Email:  user.Email,
 		Groups: user.Groups,
+		Origin: user.Origin,
 		Roles:  make([]rolePermissionData, 0, len(user.Roles)),
 	}
 
+	now := time.Now()
 	for _, userRole := range user.Roles {
+		if userRole.Expired(now) {
+			continue
+		}
 		isGlobal, err := expandRoleData(perms, userRole, apiUsr, roleMap, includeAll, "")
 		if err != nil {
 			return nil, err
@@ -775,6 +943,7 @@ func expandRoleData(perms []permission.Permission, userRole authTypes.RoleInstan
 		ContextType:  string(role.ContextType),
 		ContextValue: userRole.ContextValue,
 		Group:        group,
+		ExpiresAt:    userRole.ExpiresAt,
 	})
 	user.Permissions = append(user.Permissions, rolePerms...)
 	return role.ContextType == permTypes.CtxGlobal, nil
@@ -786,6 +955,7 @@ func expandRoleData(perms []permission.Permission, userRole authTypes.RoleInstan
 // produce: application/json
 // responses:
 //   200: OK
+//   400: Invalid data
 //   401: Unauthorized
 func listUsers(w http.ResponseWriter, r *http.Request, t auth.Token) error {
 	userEmail := r.URL.Query().Get("userEmail")
@@ -849,6 +1019,11 @@ func listUsers(w http.ResponseWriter, r *http.Request, t auth.Token) error {
 		}
 		apiUsers = append(apiUsers, *userData)
 	}
+	start, end, err := paginate(w, r, len(apiUsers))
+	if err != nil {
+		return err
+	}
+	apiUsers = apiUsers[start:end]
 	w.Header().Add("Content-Type", "application/json")
 	return json.NewEncoder(w).Encode(apiUsers)
 }
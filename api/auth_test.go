@@ -8,6 +8,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	stdErrors "errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -24,6 +25,7 @@ import (
 	"github.com/tsuru/tsuru/db"
 	"github.com/tsuru/tsuru/db/dbtest"
 	"github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event"
 	"github.com/tsuru/tsuru/event/eventtest"
 	"github.com/tsuru/tsuru/log"
 	"github.com/tsuru/tsuru/permission"
@@ -620,6 +622,65 @@ func (s *AuthSuite) TestRemoveUser(c *check.C) {
 	}, eventtest.HasEvent)
 }
 
+func (s *AuthSuite) TestRemoveUserTombstonesEventOwner(c *check.C) {
+	u := auth.User{Email: "her-voices@painofsalvation.com", Password: "123456"}
+	_, err := nativeScheme.Create(context.TODO(), &u)
+	c.Assert(err, check.IsNil)
+	token, err := nativeScheme.Login(context.TODO(), map[string]string{"email": u.Email, "password": "123456"})
+	c.Assert(err, check.IsNil)
+	evt, err := event.New(&event.Opts{
+		Target:  event.Target{Type: event.TargetTypeApp, Value: "myapp"},
+		Kind:    permission.PermAppUpdateEnvSet,
+		Owner:   token,
+		Allowed: event.Allowed(permission.PermAppReadEvents),
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(evt.Done(nil), check.IsNil)
+	request, err := http.NewRequest(http.MethodDelete, "/users", nil)
+	c.Assert(err, check.IsNil)
+	recorder := httptest.NewRecorder()
+	err = removeUser(recorder, request, token)
+	c.Assert(err, check.IsNil)
+	dbEvt, err := event.GetByID(evt.UniqueID)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbEvt.Owner, check.Equals, event.Owner{Type: event.OwnerTypeRemovedUser, Name: u.Email})
+}
+
+type removeFailureScheme struct {
+	native.NativeScheme
+}
+
+func (removeFailureScheme) Remove(ctx context.Context, u *auth.User) error {
+	return stdErrors.New("remove failure")
+}
+
+func (s *AuthSuite) TestRemoveUserFailureKeepsEventOwner(c *check.C) {
+	u := auth.User{Email: "her-voices@painofsalvation.com", Password: "123456"}
+	_, err := nativeScheme.Create(context.TODO(), &u)
+	c.Assert(err, check.IsNil)
+	token, err := nativeScheme.Login(context.TODO(), map[string]string{"email": u.Email, "password": "123456"})
+	c.Assert(err, check.IsNil)
+	evt, err := event.New(&event.Opts{
+		Target:  event.Target{Type: event.TargetTypeApp, Value: "myapp"},
+		Kind:    permission.PermAppUpdateEnvSet,
+		Owner:   token,
+		Allowed: event.Allowed(permission.PermAppReadEvents),
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(evt.Done(nil), check.IsNil)
+	oldScheme := app.AuthScheme
+	defer func() { app.AuthScheme = oldScheme }()
+	app.AuthScheme = removeFailureScheme{}
+	request, err := http.NewRequest(http.MethodDelete, "/users", nil)
+	c.Assert(err, check.IsNil)
+	recorder := httptest.NewRecorder()
+	err = removeUser(recorder, request, token)
+	c.Assert(err, check.ErrorMatches, "remove failure")
+	dbEvt, err := event.GetByID(evt.UniqueID)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbEvt.Owner, check.Equals, event.Owner{Type: event.OwnerTypeUser, Name: u.Email})
+}
+
 func (s *AuthSuite) TestRemoveUserProvidingOwnEmail(c *check.C) {
 	u := auth.User{Email: "her-voices@painofsalvation.com", Password: "123456"}
 	_, err := nativeScheme.Create(context.TODO(), &u)
@@ -1286,6 +1347,25 @@ func (s *AuthSuite) TestUserInfoWithoutRoles(c *check.C) {
 	c.Assert(got, check.DeepEquals, expected)
 }
 
+func (s *AuthSuite) TestUserInfoWithOrigin(c *check.C) {
+	token := userWithPermission(c)
+	u, err := auth.ConvertNewUser(token.User())
+	c.Assert(err, check.IsNil)
+	u.Origin = "oidc:github"
+	err = u.Update()
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest(http.MethodGet, "/users/info", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Add("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	var got apiUser
+	err = json.NewDecoder(recorder.Body).Decode(&got)
+	c.Assert(err, check.IsNil)
+	c.Assert(got.Origin, check.Equals, "oidc:github")
+}
+
 type rolePermList []rolePermissionData
 
 func (l rolePermList) Len() int      { return len(l) }
@@ -1504,6 +1584,77 @@ func (s *AuthSuite) TestUpdateTeamTags(c *check.C) {
 	c.Assert(updated, check.DeepEquals, true)
 }
 
+func (s *AuthSuite) TestSetTeamParent(c *check.C) {
+	s.mockTeamService.OnFindByName = func(name string) (*authTypes.Team, error) {
+		c.Assert(name, check.Equals, "team1")
+		return &authTypes.Team{Name: name}, nil
+	}
+	var updated bool
+	s.mockTeamService.OnSetParent = func(name, parent string) error {
+		c.Assert(name, check.Equals, "team1")
+		c.Assert(parent, check.Equals, "org")
+		updated = true
+		return nil
+	}
+	body := strings.NewReader("parent=org")
+	request, err := http.NewRequest(http.MethodPut, "/1.13/teams/team1/parent", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK, check.Commentf("body: %q", recorder.Body.String()))
+	c.Assert(updated, check.Equals, true)
+	c.Assert(eventtest.EventDesc{
+		Target: teamTarget("team1"),
+		Owner:  s.token.GetUserName(),
+		Kind:   "team.update.parent",
+		StartCustomData: []map[string]interface{}{
+			{"name": "parent", "value": "org"},
+		},
+	}, eventtest.HasEvent)
+}
+
+func (s *AuthSuite) TestSetTeamParentCycle(c *check.C) {
+	s.mockTeamService.OnFindByName = func(name string) (*authTypes.Team, error) {
+		return &authTypes.Team{Name: name}, nil
+	}
+	s.mockTeamService.OnSetParent = func(name, parent string) error {
+		return authTypes.ErrTeamParentCycle
+	}
+	body := strings.NewReader("parent=team2")
+	request, err := http.NewRequest(http.MethodPut, "/1.13/teams/team1/parent", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(recorder.Body.String(), check.Equals, authTypes.ErrTeamParentCycle.Error()+"\n")
+}
+
+func (s *AuthSuite) TestSetTeamParentRequiresPermissionOnParent(c *check.C) {
+	s.mockTeamService.OnFindByName = func(name string) (*authTypes.Team, error) {
+		return &authTypes.Team{Name: name}, nil
+	}
+	s.mockTeamService.OnSetParent = func(name, parent string) error {
+		c.Fatal("team parent must not be changed")
+		return nil
+	}
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermTeamUpdateParent,
+		Context: permission.Context(permTypes.CtxTeam, "team1"),
+	})
+	body := strings.NewReader("parent=org")
+	request, err := http.NewRequest(http.MethodPut, "/1.13/teams/team1/parent", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
+
 func (s *AuthSuite) TestUpdateTeamCallFnsAndRollback(c *check.C) {
 	s.mockTeamService.OnFindByName = func(_ string) (*authTypes.Team, error) {
 		return &authTypes.Team{}, nil
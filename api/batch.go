@@ -0,0 +1,281 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/tsuru/tsuru/app"
+	"github.com/tsuru/tsuru/app/bind"
+	"github.com/tsuru/tsuru/auth"
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/permission"
+	apiTypes "github.com/tsuru/tsuru/types/api"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+)
+
+// batchConcurrency is the number of apps changed at the same time by each
+// operation of a batch.
+const batchConcurrency = 10
+
+var batchActionPermissions = map[string]*permission.PermissionScheme{
+	apiTypes.BatchActionEnvSet:  permission.PermAppUpdateEnvSet,
+	apiTypes.BatchActionRestart: permission.PermAppUpdateRestart,
+}
+
+// batchItem is an app targeted by an operation, err is set when the app
+// couldn't be loaded.
+type batchItem struct {
+	name string
+	app  *app.App
+	err  error
+}
+
+// title: batch
+// path: /batch
+// method: POST
+// consume: application/json
+// produce: application/json
+// responses:
+//   200: Operations executed
+//   400: Invalid data
+//   401: Unauthorized
+func batch(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	var b apiTypes.Batch
+	err = ParseJSON(r, &b)
+	if err != nil {
+		return err
+	}
+	if len(b.Operations) == 0 {
+		return &tsuruErrors.HTTP{Code: http.StatusBadRequest, Message: "You must provide at least one operation"}
+	}
+	for i, op := range b.Operations {
+		if err = validateBatchOperation(op); err != nil {
+			return &tsuruErrors.HTTP{Code: http.StatusBadRequest, Message: fmt.Sprintf("operation %d: %v", i, err)}
+		}
+	}
+	ctx := r.Context()
+	items := make([][]batchItem, len(b.Operations))
+	var allowedContexts []permTypes.PermissionContext
+	for i, op := range b.Operations {
+		items[i], err = batchItems(r, t, op)
+		if err != nil {
+			return err
+		}
+		for _, item := range items[i] {
+			if item.app != nil {
+				allowedContexts = append(allowedContexts, contextsForApp(item.app)...)
+			}
+		}
+	}
+	parent, err := newBatchEvent(r, t, b, allowedContexts)
+	if err != nil {
+		return err
+	}
+	response := apiTypes.BatchResponse{EventID: parent.UniqueID.Hex()}
+	var failed int
+	defer func() {
+		if err == nil && failed > 0 {
+			parent.Done(fmt.Errorf("%d of %d items failed", failed, len(response.Results)))
+			return
+		}
+		parent.Done(err)
+	}()
+	ctx, cancel := parent.CancelableContext(ctx)
+	defer cancel()
+	for i, op := range b.Operations {
+		results := make([]apiTypes.BatchResult, len(items[i]))
+		sem := make(chan struct{}, batchConcurrency)
+		var wg sync.WaitGroup
+		for j, item := range items[i] {
+			results[j] = apiTypes.BatchResult{Operation: i, Action: op.Action, App: item.name}
+			if item.err != nil {
+				results[j].Error = item.err.Error()
+				continue
+			}
+			if ctx.Err() != nil {
+				results[j].Error = ctx.Err().Error()
+				continue
+			}
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(j int, a *app.App) {
+				defer func() {
+					<-sem
+					wg.Done()
+				}()
+				a.ReplaceContext(ctx)
+				if opErr := runBatchOperation(r, t, parent, op, a); opErr != nil {
+					results[j].Error = opErr.Error()
+				}
+			}(j, item.app)
+		}
+		wg.Wait()
+		for _, result := range results {
+			if result.Error != "" {
+				failed++
+			}
+		}
+		response.Results = append(response.Results, results...)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(response)
+}
+
+func validateBatchOperation(op apiTypes.BatchOperation) error {
+	if _, ok := batchActionPermissions[op.Action]; !ok {
+		return fmt.Errorf("invalid action %q", op.Action)
+	}
+	if len(op.Apps) == 0 && op.Tag == "" {
+		return fmt.Errorf("you must provide the apps or a tag selector")
+	}
+	if op.Action == apiTypes.BatchActionEnvSet {
+		if len(op.Envs) == 0 {
+			return fmt.Errorf("you must provide the list of environment variables")
+		}
+		for _, e := range op.Envs {
+			if isInternalEnv(e.Name) {
+				return fmt.Errorf("can't change the following environment variables (write protected): %s", internalEnvs())
+			}
+		}
+	}
+	return nil
+}
+
+// batchItems lists the apps targeted by op, apps matching the tag selector
+// are only listed when the user is allowed to run the action on them.
+func batchItems(r *http.Request, t auth.Token, op apiTypes.BatchOperation) ([]batchItem, error) {
+	var items []batchItem
+	seen := map[string]bool{}
+	for _, name := range op.Apps {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		a, err := getApp(r.Context(), name)
+		items = append(items, batchItem{name: name, app: a, err: err})
+	}
+	if op.Tag == "" {
+		return items, nil
+	}
+	requirements, err := app.ParseTagSelector(op.Tag)
+	if err != nil {
+		return nil, &tsuruErrors.HTTP{Code: http.StatusBadRequest, Message: err.Error()}
+	}
+	contexts := permission.ContextsForPermission(t, batchActionPermissions[op.Action])
+	if len(contexts) == 0 {
+		return items, nil
+	}
+	apps, err := app.List(r.Context(), appFilterByContext(contexts, &app.Filter{TagSelector: requirements}))
+	if err != nil {
+		return nil, err
+	}
+	for i := range apps {
+		if seen[apps[i].Name] {
+			continue
+		}
+		seen[apps[i].Name] = true
+		items = append(items, batchItem{name: apps[i].Name, app: &apps[i]})
+	}
+	return items, nil
+}
+
+// runBatchOperation runs op on a single app, recording it as a child of the
+// batch event.
+func runBatchOperation(r *http.Request, t auth.Token, parent *event.Event, op apiTypes.BatchOperation, a *app.App) (err error) {
+	scheme := batchActionPermissions[op.Action]
+	if !permission.Check(t, scheme, contextsForApp(a)...) {
+		return permission.ErrUnauthorized
+	}
+	evt, err := newBatchOperationEvent(r, t, parent, op, a)
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	switch op.Action {
+	case apiTypes.BatchActionEnvSet:
+		return a.SetEnvs(bind.SetEnvArgs{
+			Envs:          batchEnvVars(op),
+			ShouldRestart: !op.NoRestart,
+			Writer:        evt,
+		})
+	case apiTypes.BatchActionRestart:
+		ctx, cancel := evt.CancelableContext(a.Context())
+		defer cancel()
+		a.ReplaceContext(ctx)
+		return a.Restart(ctx, op.Process, "", evt)
+	}
+	return nil
+}
+
+// newBatchEvent creates the parent event of a batch, cancelling it also
+// cancels the operations still running under it.
+func newBatchEvent(r *http.Request, t auth.Token, b apiTypes.Batch, allowedContexts []permTypes.PermissionContext) (*event.Event, error) {
+	return event.New(&event.Opts{
+		Target:        event.Target{Type: event.TargetTypeGlobal},
+		Kind:          permission.PermAppUpdate,
+		Owner:         t,
+		RemoteAddr:    r.RemoteAddr,
+		CustomData:    batchCustomData(b),
+		DisableLock:   true,
+		Allowed:       event.Allowed(permission.PermAppReadEvents, allowedContexts...),
+		AllowedCancel: event.Allowed(permission.PermAppUpdateEvents, allowedContexts...),
+		Cancelable:    true,
+	})
+}
+
+func newBatchOperationEvent(r *http.Request, t auth.Token, parent *event.Event, op apiTypes.BatchOperation, a *app.App) (*event.Event, error) {
+	return event.New(&event.Opts{
+		Target:        appTarget(a.Name),
+		Kind:          batchActionPermissions[op.Action],
+		Owner:         t,
+		RemoteAddr:    r.RemoteAddr,
+		ParentID:      parent.UniqueID,
+		CustomData:    batchOperationCustomData(op),
+		Allowed:       event.Allowed(permission.PermAppReadEvents, contextsForApp(a)...),
+		AllowedCancel: event.Allowed(permission.PermAppUpdateEvents, contextsForApp(a)...),
+		Cancelable:    op.Action == apiTypes.BatchActionRestart,
+	})
+}
+
+func batchEnvVars(op apiTypes.BatchOperation) []bind.EnvVar {
+	variables := make([]bind.EnvVar, len(op.Envs))
+	for i, e := range op.Envs {
+		private := op.Private
+		if e.Private != nil && *e.Private {
+			private = true
+		}
+		variables[i] = bind.EnvVar{
+			Name:   e.Name,
+			Value:  e.Value,
+			Alias:  e.Alias,
+			Public: !private,
+		}
+	}
+	return variables
+}
+
+// batchOperationCustomData describes op in events, values of environment
+// variables are omitted, as they may be private.
+func batchOperationCustomData(op apiTypes.BatchOperation) apiTypes.BatchOperation {
+	envs := make([]apiTypes.Env, len(op.Envs))
+	for i, e := range op.Envs {
+		envs[i] = apiTypes.Env{Name: e.Name, Alias: e.Alias, Private: e.Private}
+	}
+	op.Envs = envs
+	return op
+}
+
+func batchCustomData(b apiTypes.Batch) apiTypes.Batch {
+	operations := make([]apiTypes.BatchOperation, len(b.Operations))
+	for i, op := range b.Operations {
+		operations[i] = batchOperationCustomData(op)
+	}
+	return apiTypes.Batch{Operations: operations}
+}
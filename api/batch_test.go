@@ -0,0 +1,195 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/app"
+	"github.com/tsuru/tsuru/app/bind"
+	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/permission"
+	apiTypes "github.com/tsuru/tsuru/types/api"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) batchRequest(c *check.C, b apiTypes.Batch, token auth.Token) *httptest.ResponseRecorder {
+	body, err := json.Marshal(b)
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest("POST", "/1.13/batch", bytes.NewReader(body))
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func (s *S) TestBatchEnvSet(c *check.C) {
+	for _, name := range []string{"app1", "app2"} {
+		a := app.App{Name: name, Platform: "zend", TeamOwner: s.team.Name}
+		err := app.CreateApp(context.TODO(), &a, s.user)
+		c.Assert(err, check.IsNil)
+	}
+	recorder := s.batchRequest(c, apiTypes.Batch{Operations: []apiTypes.BatchOperation{{
+		Action:    apiTypes.BatchActionEnvSet,
+		Apps:      []string{"app1", "app2"},
+		Envs:      []apiTypes.Env{{Name: "DATABASE_HOST", Value: "localhost"}},
+		NoRestart: true,
+	}}}, s.token)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, "application/json")
+	var response apiTypes.BatchResponse
+	err := json.Unmarshal(recorder.Body.Bytes(), &response)
+	c.Assert(err, check.IsNil)
+	c.Assert(response.Results, check.DeepEquals, []apiTypes.BatchResult{
+		{Operation: 0, Action: "env-set", App: "app1"},
+		{Operation: 0, Action: "env-set", App: "app2"},
+	})
+	parent, err := event.GetByHexID(response.EventID)
+	c.Assert(err, check.IsNil)
+	c.Assert(parent.Running, check.Equals, false)
+	c.Assert(parent.Error, check.Equals, "")
+	for _, name := range []string{"app1", "app2"} {
+		a, err := app.GetByName(context.TODO(), name)
+		c.Assert(err, check.IsNil)
+		c.Assert(a.Env["DATABASE_HOST"], check.DeepEquals, bind.EnvVar{Name: "DATABASE_HOST", Value: "localhost", Public: true})
+		evts, err := event.List(&event.Filter{
+			Target:    appTarget(name),
+			KindNames: []string{permission.PermAppUpdateEnvSet.FullName()},
+		})
+		c.Assert(err, check.IsNil)
+		c.Assert(evts, check.HasLen, 1)
+		c.Assert(evts[0].ParentID, check.Equals, parent.UniqueID)
+	}
+}
+
+func (s *S) TestBatchRestartByTag(c *check.C) {
+	config.Set("docker:router", "fake")
+	defer config.Unset("docker:router")
+	apps := []app.App{
+		{Name: "web1", Platform: "zend", TeamOwner: s.team.Name, Tags: []string{"tier=web"}},
+		{Name: "web2", Platform: "zend", TeamOwner: s.team.Name, Tags: []string{"tier=web"}},
+		{Name: "worker", Platform: "zend", TeamOwner: s.team.Name, Tags: []string{"tier=worker"}},
+	}
+	for i := range apps {
+		err := app.CreateApp(context.TODO(), &apps[i], s.user)
+		c.Assert(err, check.IsNil)
+		newSuccessfulAppVersion(c, &apps[i])
+	}
+	recorder := s.batchRequest(c, apiTypes.Batch{Operations: []apiTypes.BatchOperation{{
+		Action: apiTypes.BatchActionRestart,
+		Tag:    "tier=web",
+	}}}, s.token)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	var response apiTypes.BatchResponse
+	err := json.Unmarshal(recorder.Body.Bytes(), &response)
+	c.Assert(err, check.IsNil)
+	c.Assert(response.Results, check.HasLen, 2)
+	var restarted []string
+	for _, result := range response.Results {
+		c.Assert(result.Error, check.Equals, "")
+		restarted = append(restarted, result.App)
+	}
+	c.Assert(restarted, check.DeepEquals, []string{"web1", "web2"})
+	evts, err := event.List(&event.Filter{
+		Target:    appTarget("worker"),
+		KindNames: []string{permission.PermAppUpdateRestart.FullName()},
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(evts, check.HasLen, 0)
+}
+
+func (s *S) TestBatchPerItemErrors(c *check.C) {
+	for _, name := range []string{"app1", "app2"} {
+		a := app.App{Name: name, Platform: "zend", TeamOwner: s.team.Name}
+		err := app.CreateApp(context.TODO(), &a, s.user)
+		c.Assert(err, check.IsNil)
+	}
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermAppUpdateEnvSet,
+		Context: permission.Context(permTypes.CtxApp, "app1"),
+	})
+	recorder := s.batchRequest(c, apiTypes.Batch{Operations: []apiTypes.BatchOperation{{
+		Action:    apiTypes.BatchActionEnvSet,
+		Apps:      []string{"app1", "app2", "unknown"},
+		Envs:      []apiTypes.Env{{Name: "DATABASE_HOST", Value: "localhost"}},
+		NoRestart: true,
+	}}}, token)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	var response apiTypes.BatchResponse
+	err := json.Unmarshal(recorder.Body.Bytes(), &response)
+	c.Assert(err, check.IsNil)
+	c.Assert(response.Results, check.DeepEquals, []apiTypes.BatchResult{
+		{Operation: 0, Action: "env-set", App: "app1"},
+		{Operation: 0, Action: "env-set", App: "app2", Error: permission.ErrUnauthorized.Error()},
+		{Operation: 0, Action: "env-set", App: "unknown", Error: "App unknown not found."},
+	})
+	parent, err := event.GetByHexID(response.EventID)
+	c.Assert(err, check.IsNil)
+	c.Assert(parent.Error, check.Equals, "2 of 3 items failed")
+	a, err := app.GetByName(context.TODO(), "app2")
+	c.Assert(err, check.IsNil)
+	_, ok := a.Env["DATABASE_HOST"]
+	c.Assert(ok, check.Equals, false)
+}
+
+func (s *S) TestBatchInvalidOperation(c *check.C) {
+	tests := []struct {
+		op       apiTypes.BatchOperation
+		expected string
+	}{
+		{op: apiTypes.BatchOperation{Action: "destroy", Apps: []string{"app1"}}, expected: `operation 0: invalid action "destroy"`},
+		{op: apiTypes.BatchOperation{Action: "restart"}, expected: "operation 0: you must provide the apps or a tag selector"},
+		{op: apiTypes.BatchOperation{Action: "env-set", Apps: []string{"app1"}}, expected: "operation 0: you must provide the list of environment variables"},
+		{op: apiTypes.BatchOperation{Action: "env-set", Apps: []string{"app1"}, Envs: []apiTypes.Env{{Name: "TSURU_APPNAME", Value: "x"}}}, expected: "operation 0: can't change .*"},
+	}
+	for _, tt := range tests {
+		recorder := s.batchRequest(c, apiTypes.Batch{Operations: []apiTypes.BatchOperation{tt.op}}, s.token)
+		c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+		c.Assert(recorder.Body.String(), check.Matches, tt.expected+"\n")
+	}
+	recorder := s.batchRequest(c, apiTypes.Batch{}, s.token)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+}
+
+func (s *S) TestBatchCancelParentCancelsOperations(c *check.C) {
+	a := app.App{Name: "app1", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	b := apiTypes.Batch{Operations: []apiTypes.BatchOperation{{
+		Action: apiTypes.BatchActionRestart,
+		Apps:   []string{"app1"},
+	}}}
+	r, err := http.NewRequest("POST", "/1.13/batch", nil)
+	c.Assert(err, check.IsNil)
+	parent, err := newBatchEvent(r, s.token, b, contextsForApp(&a))
+	c.Assert(err, check.IsNil)
+	defer parent.Done(nil)
+	child, err := newBatchOperationEvent(r, s.token, parent, b.Operations[0], &a)
+	c.Assert(err, check.IsNil)
+	defer child.Done(nil)
+	body := strings.NewReader("reason=stop the batch")
+	request, err := http.NewRequest("POST", fmt.Sprintf("/events/%s/cancel", parent.UniqueID.Hex()), body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNoContent)
+	dbChild, err := event.GetByID(child.UniqueID)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbChild.CancelInfo.Asked, check.Equals, true)
+	c.Assert(dbChild.CancelInfo.Reason, check.Equals, "stop the batch")
+}
@@ -77,6 +77,7 @@ func build(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
 		Kind:          permission.PermAppBuild,
 		RawOwner:      event.Owner{Type: event.OwnerTypeUser, Name: userName},
 		RemoteAddr:    r.RemoteAddr,
+		Context:       r.Context(),
 		CustomData:    opts,
 		Allowed:       event.Allowed(permission.PermAppReadEvents, contextsForApp(instance)...),
 		AllowedCancel: event.Allowed(permission.PermAppUpdateEvents, contextsForApp(instance)...),
@@ -5,16 +5,22 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/pkg/errors"
+	"github.com/tsuru/tsuru/app"
 	"github.com/tsuru/tsuru/auth"
 	tsuruErrors "github.com/tsuru/tsuru/errors"
 	"github.com/tsuru/tsuru/event"
 	tsuruIo "github.com/tsuru/tsuru/io"
+	"github.com/tsuru/tsuru/log"
 	"github.com/tsuru/tsuru/permission"
 	"github.com/tsuru/tsuru/provision"
 	"github.com/tsuru/tsuru/provision/cluster"
@@ -169,10 +175,133 @@ func updateCluster(w http.ResponseWriter, r *http.Request, t auth.Token) (err er
 		evt.SetLogWriter(writer)
 	}
 	provCluster.Writer = evt
+	activeClusters, err := poolsActiveClusters(ctx, provCluster.Provisioner)
+	if err != nil {
+		return err
+	}
 	err = servicemanager.Cluster.Update(ctx, provCluster)
 	if err != nil {
 		return errors.WithStack(err)
 	}
+	return failoverApps(ctx, provCluster.Provisioner, activeClusters, evt)
+}
+
+// poolsActiveClusters returns the name of the cluster currently serving each
+// pool of the provisioner.
+func poolsActiveClusters(ctx context.Context, provisioner string) (map[string]string, error) {
+	pools, err := pool.ListAllPools(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]string)
+	for _, p := range pools {
+		prov, err := p.GetProvisioner()
+		if err != nil {
+			return nil, err
+		}
+		if prov.GetName() != provisioner {
+			continue
+		}
+		c, err := servicemanager.Cluster.FindByPool(ctx, provisioner, p.Name)
+		if err == provTypes.ErrNoCluster {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if c != nil {
+			result[p.Name] = c.Name
+		}
+	}
+	return result, nil
+}
+
+// failoverAppKind is the kind of the internal event under which an app is
+// moved to the cluster now serving its pool.
+const failoverAppKind = "cluster-failover"
+
+// runFailover runs the move of the failed over apps, it's replaced by tests
+// that need to wait for the moves.
+var runFailover = func(f func()) { go f() }
+
+// failoverApps moves the deployed apps of the pools whose serving cluster
+// changed. Each app is moved in background, under an internal event of the
+// app, creating its units on the new cluster and then removing its resources
+// from the previous one.
+func failoverApps(ctx context.Context, provisioner string, previous map[string]string, w io.Writer) error {
+	current, err := poolsActiveClusters(ctx, provisioner)
+	if err != nil {
+		return err
+	}
+	var pools []string
+	for poolName, clusterName := range current {
+		if previousName, ok := previous[poolName]; ok && previousName != clusterName {
+			pools = append(pools, poolName)
+		}
+	}
+	if len(pools) == 0 {
+		return nil
+	}
+	sort.Strings(pools)
+	apps, err := app.List(ctx, &app.Filter{Pools: pools})
+	if err != nil {
+		return err
+	}
+	var moves []*app.App
+	for i := range apps {
+		a := &apps[i]
+		if a.Deploys == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "---- Moving app %q to cluster %q in background, check the app events ----\n", a.Name, current[a.Pool])
+		moves = append(moves, a)
+	}
+	if len(moves) == 0 {
+		return nil
+	}
+	runFailover(func() {
+		for _, a := range moves {
+			err := moveApp(context.Background(), a, previous[a.Pool], current[a.Pool])
+			if err != nil {
+				log.Errorf("[cluster-failover] unable to move app %q to cluster %q: %v", a.Name, current[a.Pool], err)
+			}
+		}
+	})
+	return nil
+}
+
+// moveApp restarts the app, creating its units on the cluster now serving its
+// pool, and removes its resources from the previous cluster. The removal is
+// best effort, as the previous cluster may be unreachable.
+func moveApp(ctx context.Context, a *app.App, from, to string) (err error) {
+	evt, err := event.NewInternal(&event.Opts{
+		Target:       appTarget(a.Name),
+		InternalKind: failoverAppKind,
+		CustomData:   map[string]string{"from": from, "to": to},
+		Allowed:      event.Allowed(permission.PermAppReadEvents, contextsForApp(a)...),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	fmt.Fprintf(evt, "---- Moving app %q from cluster %q to cluster %q ----\n", a.Name, from, to)
+	err = a.Restart(ctx, "", "", evt)
+	if err != nil {
+		return err
+	}
+	prov, err := pool.GetProvisionerForPool(ctx, a.Pool)
+	if err != nil {
+		return err
+	}
+	remover, ok := prov.(provision.ClusterResourcesProvisioner)
+	if !ok {
+		return nil
+	}
+	fmt.Fprintf(evt, "---- Removing app %q from cluster %q ----\n", a.Name, from)
+	if rmErr := remover.RemoveClusterResources(ctx, a, from); rmErr != nil {
+		fmt.Fprintf(evt, "unable to remove app %q from cluster %q: %v\n", a.Name, from, rmErr)
+		log.Errorf("[cluster-failover] unable to remove app %q from cluster %q: %v", a.Name, from, rmErr)
+	}
 	return nil
 }
 
@@ -237,6 +366,46 @@ func clusterInfo(w http.ResponseWriter, r *http.Request, t auth.Token) (err erro
 	return json.NewEncoder(w).Encode(cluster)
 }
 
+// title: provisioner cluster status
+// path: /provisioner/clusters/{name}/status
+// method: GET
+// produce: application/json
+// responses:
+//   200: Ok
+//   400: Status not supported by the cluster provisioner
+//   401: Unauthorized
+//   404: Cluster not found
+func clusterStatus(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	ctx := r.Context()
+	allowed := permission.Check(t, permission.PermClusterRead)
+	if !allowed {
+		return permission.ErrUnauthorized
+	}
+	name := r.URL.Query().Get(":name")
+	c, err := servicemanager.Cluster.FindByName(ctx, name)
+	if err != nil {
+		if err == provTypes.ErrClusterNotFound {
+			return &tsuruErrors.HTTP{
+				Code:    http.StatusNotFound,
+				Message: err.Error(),
+			}
+		}
+		return err
+	}
+	status, err := cluster.Status(ctx, c)
+	if err != nil {
+		if err == cluster.ErrStatusNotSupported {
+			return &tsuruErrors.HTTP{
+				Code:    http.StatusBadRequest,
+				Message: err.Error(),
+			}
+		}
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(status)
+}
+
 // title: delete provisioner cluster
 // path: /provisioner/clusters/{name}
 // method: DELETE
@@ -6,10 +6,14 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 
+	"github.com/globalsign/mgo/bson"
+	"github.com/tsuru/tsuru/app"
+	"github.com/tsuru/tsuru/event/eventtest"
 	"github.com/tsuru/tsuru/permission"
 	permTypes "github.com/tsuru/tsuru/types/permission"
 	"github.com/tsuru/tsuru/types/provision"
@@ -112,6 +116,70 @@ func (s *S) TestUpdateCluster(c *check.C) {
 	c.Assert(recorder.Code, check.Equals, http.StatusOK, check.Commentf("body: %q", recorder.Body.String()))
 }
 
+func (s *S) TestUpdateClusterFailoverApps(c *check.C) {
+	var moves []func()
+	originalRunFailover := runFailover
+	defer func() { runFailover = originalRunFailover }()
+	runFailover = func(f func()) { moves = append(moves, f) }
+	updated := false
+	s.mockService.Cluster.OnFindByName = func(name string) (*provision.Cluster, error) {
+		return &provision.Cluster{Name: name, Provisioner: "fake", Pools: []string{"test1"}}, nil
+	}
+	s.mockService.Cluster.OnUpdate = func(c provision.Cluster) error {
+		updated = true
+		return nil
+	}
+	s.mockService.Cluster.OnFindByPool = func(prov, pool string) (*provision.Cluster, error) {
+		c.Assert(prov, check.Equals, "fake")
+		if updated {
+			return &provision.Cluster{Name: "c2", Provisioner: "fake", StandbyPools: []string{"test1"}}, nil
+		}
+		return &provision.Cluster{Name: "c1", Provisioner: "fake", Pools: []string{"test1"}}, nil
+	}
+	deployed := app.App{Name: "deployed", Platform: "zend", TeamOwner: s.team.Name, Pool: "test1"}
+	err := app.CreateApp(context.TODO(), &deployed, s.user)
+	c.Assert(err, check.IsNil)
+	newSuccessfulAppVersion(c, &deployed)
+	err = s.conn.Apps().Update(bson.M{"name": deployed.Name}, bson.M{"$set": bson.M{"deploys": 1}})
+	c.Assert(err, check.IsNil)
+	notDeployed := app.App{Name: "not-deployed", Platform: "zend", TeamOwner: s.team.Name, Pool: "test1"}
+	err = app.CreateApp(context.TODO(), &notDeployed, s.user)
+	c.Assert(err, check.IsNil)
+	kubeCluster := provision.Cluster{
+		Name:        "c1",
+		Addresses:   []string{"addr1"},
+		Provisioner: "fake",
+		Pools:       []string{"test1"},
+		Unavailable: true,
+	}
+	var buf bytes.Buffer
+	err = json.NewEncoder(&buf).Encode(kubeCluster)
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest(http.MethodPost, "/1.4/provisioner/clusters/c1", &buf)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	request.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK, check.Commentf("body: %q", recorder.Body.String()))
+	c.Assert(updated, check.Equals, true)
+	c.Assert(s.provisioner.Restarts(&deployed, ""), check.Equals, 0)
+	c.Assert(moves, check.HasLen, 1)
+	moves[0]()
+	c.Assert(s.provisioner.Restarts(&deployed, ""), check.Equals, 1)
+	c.Assert(s.provisioner.Restarts(&notDeployed, ""), check.Equals, 0)
+	c.Assert(s.provisioner.RemovedClusterResources(&deployed), check.DeepEquals, []string{"c1"})
+	c.Assert(s.provisioner.RemovedClusterResources(&notDeployed), check.HasLen, 0)
+	c.Assert(eventtest.EventDesc{
+		Target: appTarget(deployed.Name),
+		Kind:   failoverAppKind,
+		StartCustomData: map[string]interface{}{
+			"from": "c1",
+			"to":   "c2",
+		},
+	}, eventtest.HasEvent)
+}
+
 func (s *S) TestUpdateClusterNonExistentPool(c *check.C) {
 	kubeCluster := provision.Cluster{
 		Name:        "c1",
@@ -266,6 +334,45 @@ func (s *S) TestDeleteClusterNotFound(c *check.C) {
 	c.Assert(recorder.Code, check.Equals, http.StatusNotFound, check.Commentf("body: %q", recorder.Body.String()))
 }
 
+func (s *S) TestClusterStatusNotSupported(c *check.C) {
+	s.mockService.Cluster.OnFindByName = func(name string) (*provision.Cluster, error) {
+		c.Assert(name, check.Equals, "c1")
+		return &provision.Cluster{Name: "c1", Provisioner: "fake", Default: true}, nil
+	}
+	request, err := http.NewRequest(http.MethodGet, "/1.13/provisioner/clusters/c1/status", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest, check.Commentf("body: %q", recorder.Body.String()))
+	c.Assert(recorder.Body.String(), check.Equals, "cluster provisioner does not support status\n")
+}
+
+func (s *S) TestClusterStatusNotFound(c *check.C) {
+	s.mockService.Cluster.OnFindByName = func(name string) (*provision.Cluster, error) {
+		return nil, provision.ErrClusterNotFound
+	}
+	request, err := http.NewRequest(http.MethodGet, "/1.13/provisioner/clusters/c1/status", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNotFound, check.Commentf("body: %q", recorder.Body.String()))
+}
+
+func (s *S) TestClusterStatusForbidden(c *check.C) {
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermAppRead,
+		Context: permission.Context(permTypes.CtxGlobal, ""),
+	})
+	request, err := http.NewRequest(http.MethodGet, "/1.13/provisioner/clusters/c1/status", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
+
 func (s *S) TestDeleteCluster(c *check.C) {
 	kubeCluster := provision.Cluster{
 		Name:        "c1",
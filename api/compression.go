@@ -0,0 +1,200 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"bufio"
+	"compress/gzip"
+	"compress/zlib"
+	stdIO "io"
+	"mime"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// compressionMinSize is the minimum size of the first write of a response
+// for it to be compressed, smaller responses are not worth it.
+const compressionMinSize = 1024
+
+// compressibleContentTypes are the media types of responses that are
+// compressed. Streaming responses, like application/x-json-stream, are left
+// untouched, as compression would delay the delivery of their messages.
+var compressibleContentTypes = map[string]bool{
+	"application/json": true,
+	"application/xml":  true,
+	"text/html":        true,
+	"text/plain":       true,
+	"text/xml":         true,
+}
+
+// compressionMiddleware compresses responses with gzip or deflate, according
+// to the Accept-Encoding header of the request.
+type compressionMiddleware struct{}
+
+func (m *compressionMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+	if encoding == "" || r.Method == http.MethodHead || r.Header.Get("Upgrade") != "" {
+		next(w, r)
+		return
+	}
+	cw := &compressingWriter{ResponseWriter: w, encoding: encoding}
+	defer cw.Close()
+	next(cw, r)
+}
+
+// negotiateEncoding returns the preferred encoding among the ones accepted
+// by the client, gzip is preferred over deflate.
+func negotiateEncoding(acceptEncoding string) string {
+	accepted := map[string]bool{}
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		params := strings.Split(part, ";")
+		name := strings.ToLower(strings.TrimSpace(params[0]))
+		quality := 1.0
+		for _, param := range params[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				quality, _ = strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64)
+			}
+		}
+		if quality > 0 {
+			accepted[name] = true
+		}
+	}
+	for _, encoding := range []string{"gzip", "deflate"} {
+		if accepted[encoding] {
+			return encoding
+		}
+	}
+	return ""
+}
+
+type compressWriteCloser interface {
+	stdIO.WriteCloser
+	Flush() error
+}
+
+// compressingWriter defers the status line of the response until its first
+// write, when it's known whether the response should be compressed.
+type compressingWriter struct {
+	http.ResponseWriter
+	encoding   string
+	mu         sync.Mutex
+	code       int
+	decided    bool
+	compressor compressWriteCloser
+	hijacked   bool
+}
+
+func (w *compressingWriter) WriteHeader(code int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.decided || w.code != 0 {
+		return
+	}
+	w.code = code
+}
+
+func (w *compressingWriter) Write(data []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.decided {
+		w.decide(len(data))
+	}
+	if w.compressor != nil {
+		return w.compressor.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// decide checks whether the response should be compressed and writes the
+// status line.
+func (w *compressingWriter) decide(size int) {
+	w.decided = true
+	header := w.Header()
+	code := w.code
+	if code == 0 {
+		code = http.StatusOK
+	}
+	if w.shouldCompress(code, size) {
+		header.Del("Content-Length")
+		header.Set("Content-Encoding", w.encoding)
+		if w.encoding == "gzip" {
+			w.compressor = gzip.NewWriter(w.ResponseWriter)
+		} else {
+			w.compressor = zlib.NewWriter(w.ResponseWriter)
+		}
+	}
+	if w.code != 0 {
+		w.ResponseWriter.WriteHeader(w.code)
+	}
+}
+
+func (w *compressingWriter) shouldCompress(code, size int) bool {
+	header := w.Header()
+	if code < http.StatusOK || code == http.StatusNoContent || code == http.StatusNotModified {
+		return false
+	}
+	if header.Get("Content-Encoding") != "" {
+		return false
+	}
+	mediaType, _, _ := mime.ParseMediaType(header.Get("Content-Type"))
+	if !compressibleContentTypes[mediaType] {
+		return false
+	}
+	header.Add("Vary", "Accept-Encoding")
+	return size >= compressionMinSize
+}
+
+func (w *compressingWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.hijacked {
+		return
+	}
+	if !w.decided {
+		w.decide(0)
+	}
+	if w.compressor != nil {
+		w.compressor.Flush()
+	}
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Close writes the pending status line or finishes the compressed stream.
+func (w *compressingWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.hijacked {
+		return nil
+	}
+	if !w.decided {
+		w.decided = true
+		if w.code != 0 {
+			w.ResponseWriter.WriteHeader(w.code)
+		}
+		return nil
+	}
+	if w.compressor != nil {
+		return w.compressor.Close()
+	}
+	return nil
+}
+
+func (w *compressingWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if hijacker, ok := w.ResponseWriter.(http.Hijacker); ok {
+		w.hijacked = true
+		return hijacker.Hijack()
+	}
+	return nil, nil, errors.New("cannot hijack connection")
+}
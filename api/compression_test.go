@@ -0,0 +1,140 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/tsuru/tsuru/io"
+	check "gopkg.in/check.v1"
+)
+
+func doCompressionRequest(c *check.C, acceptEncoding string, handler http.HandlerFunc) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest("GET", "/apps", nil)
+	c.Assert(err, check.IsNil)
+	if acceptEncoding != "" {
+		request.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	m := &compressionMiddleware{}
+	m.ServeHTTP(recorder, request, handler)
+	return recorder
+}
+
+func (s *S) TestNegotiateEncoding(c *check.C) {
+	tests := []struct {
+		acceptEncoding string
+		expected       string
+	}{
+		{"", ""},
+		{"gzip", "gzip"},
+		{"deflate", "deflate"},
+		{"deflate, gzip", "gzip"},
+		{"gzip;q=0, deflate", "deflate"},
+		{"gzip;q=0", ""},
+		{"br, identity", ""},
+		{" GZIP ;q=0.5", "gzip"},
+	}
+	for _, tt := range tests {
+		c.Assert(negotiateEncoding(tt.acceptEncoding), check.Equals, tt.expected, check.Commentf("%q", tt.acceptEncoding))
+	}
+}
+
+func (s *S) TestCompressionMiddlewareGzip(c *check.C) {
+	body := `["` + strings.Repeat("myapp", 1000) + `"]`
+	recorder := doCompressionRequest(c, "gzip, deflate", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(body))
+	})
+	c.Assert(recorder.Code, check.Equals, http.StatusCreated)
+	c.Assert(recorder.Header().Get("Content-Encoding"), check.Equals, "gzip")
+	c.Assert(recorder.Header().Get("Vary"), check.Equals, "Accept-Encoding")
+	c.Assert(recorder.Body.Len() < len(body), check.Equals, true)
+	reader, err := gzip.NewReader(recorder.Body)
+	c.Assert(err, check.IsNil)
+	data, err := ioutil.ReadAll(reader)
+	c.Assert(err, check.IsNil)
+	c.Assert(string(data), check.Equals, body)
+}
+
+func (s *S) TestCompressionMiddlewareDeflate(c *check.C) {
+	body := strings.Repeat("some text ", 200)
+	recorder := doCompressionRequest(c, "deflate", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Write([]byte(body))
+	})
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(recorder.Header().Get("Content-Encoding"), check.Equals, "deflate")
+	reader, err := zlib.NewReader(recorder.Body)
+	c.Assert(err, check.IsNil)
+	data, err := ioutil.ReadAll(reader)
+	c.Assert(err, check.IsNil)
+	c.Assert(string(data), check.Equals, body)
+}
+
+func (s *S) TestCompressionMiddlewareNotAccepted(c *check.C) {
+	body := strings.Repeat("a", 2000)
+	recorder := doCompressionRequest(c, "", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	})
+	c.Assert(recorder.Header().Get("Content-Encoding"), check.Equals, "")
+	c.Assert(recorder.Body.String(), check.Equals, body)
+}
+
+func (s *S) TestCompressionMiddlewareSmallResponse(c *check.C) {
+	recorder := doCompressionRequest(c, "gzip", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"name":"myapp"}`))
+	})
+	c.Assert(recorder.Header().Get("Content-Encoding"), check.Equals, "")
+	c.Assert(recorder.Header().Get("Vary"), check.Equals, "Accept-Encoding")
+	c.Assert(recorder.Body.String(), check.Equals, `{"name":"myapp"}`)
+}
+
+func (s *S) TestCompressionMiddlewareStreaming(c *check.C) {
+	body := strings.Repeat(`{"Message":"deploying"}`+"\n", 100)
+	recorder := doCompressionRequest(c, "gzip", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-json-stream")
+		w.Write([]byte(body))
+	})
+	c.Assert(recorder.Header().Get("Content-Encoding"), check.Equals, "")
+	c.Assert(recorder.Body.String(), check.Equals, body)
+}
+
+func (s *S) TestCompressionMiddlewareNoContent(c *check.C) {
+	recorder := doCompressionRequest(c, "gzip", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	c.Assert(recorder.Code, check.Equals, http.StatusNoContent)
+	c.Assert(recorder.Header().Get("Content-Encoding"), check.Equals, "")
+	c.Assert(recorder.Body.Len(), check.Equals, 0)
+}
+
+func (s *S) TestCompressionMiddlewareWithFlushingWriter(c *check.C) {
+	body := `["` + strings.Repeat("myapp", 1000) + `"]`
+	recorder := doCompressionRequest(c, "gzip", func(w http.ResponseWriter, r *http.Request) {
+		m := &flushingWriterMiddleware{}
+		m.ServeHTTP(w, r, func(w http.ResponseWriter, r *http.Request) {
+			_, ok := w.(*io.FlushingWriter)
+			c.Assert(ok, check.Equals, true)
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(body))
+		})
+	})
+	c.Assert(recorder.Header().Get("Content-Encoding"), check.Equals, "gzip")
+	c.Assert(recorder.Flushed, check.Equals, true)
+	reader, err := gzip.NewReader(recorder.Body)
+	c.Assert(err, check.IsNil)
+	data, err := ioutil.ReadAll(reader)
+	c.Assert(err, check.IsNil)
+	c.Assert(string(data), check.Equals, body)
+}
@@ -0,0 +1,167 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/tsuru/config"
+	internalConfig "github.com/tsuru/tsuru/config"
+)
+
+var (
+	defaultCORSMethods = []string{http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+	defaultCORSHeaders = []string{"Accept", "Authorization", "Content-Type", "If-None-Match"}
+)
+
+type corsConfig struct {
+	AllowedOrigins   []string `json:"allowed-origins"`
+	AllowedMethods   []string `json:"allowed-methods"`
+	AllowedHeaders   []string `json:"allowed-headers"`
+	ExposedHeaders   []string `json:"exposed-headers"`
+	AllowCredentials bool     `json:"allow-credentials"`
+	MaxAge           int      `json:"max-age"`
+}
+
+// corsMiddleware enforces the cross-origin resource sharing policy of the
+// API, allowing browser-based clients served from other origins to call it.
+// Preflight requests are answered by the middleware itself, as they don't
+// carry the credentials required by most handlers.
+type corsMiddleware struct {
+	config corsConfig
+}
+
+// newCORSMiddleware returns the middleware configured in the cors key, or nil
+// when no origin is allowed.
+func newCORSMiddleware() (*corsMiddleware, error) {
+	var conf corsConfig
+	err := internalConfig.UnmarshalConfig("cors", &conf)
+	if err != nil {
+		if _, isNotFound := errors.Cause(err).(config.ErrKeyNotFound); isNotFound {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "unable to load cors config")
+	}
+	if len(conf.AllowedOrigins) == 0 {
+		return nil, nil
+	}
+	if len(conf.AllowedMethods) == 0 {
+		conf.AllowedMethods = defaultCORSMethods
+	}
+	if len(conf.AllowedHeaders) == 0 {
+		conf.AllowedHeaders = defaultCORSHeaders
+	}
+	for i := range conf.AllowedMethods {
+		conf.AllowedMethods[i] = strings.ToUpper(conf.AllowedMethods[i])
+	}
+	return &corsMiddleware{config: conf}, nil
+}
+
+func (m *corsMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		next(w, r)
+		return
+	}
+	w.Header().Add("Vary", "Origin")
+	preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+	if !preflight {
+		if m.originAllowed(origin) {
+			m.setOriginHeaders(w, origin)
+			if len(m.config.ExposedHeaders) > 0 {
+				w.Header().Set("Access-Control-Expose-Headers", strings.Join(m.config.ExposedHeaders, ", "))
+			}
+		}
+		next(w, r)
+		return
+	}
+	w.Header().Add("Vary", "Access-Control-Request-Method")
+	w.Header().Add("Vary", "Access-Control-Request-Headers")
+	if m.originAllowed(origin) &&
+		m.methodAllowed(r.Header.Get("Access-Control-Request-Method")) &&
+		m.headersAllowed(r.Header.Get("Access-Control-Request-Headers")) {
+		m.setOriginHeaders(w, origin)
+		w.Header().Set("Access-Control-Allow-Methods", strings.Join(m.config.AllowedMethods, ", "))
+		w.Header().Set("Access-Control-Allow-Headers", strings.Join(m.config.AllowedHeaders, ", "))
+		if m.config.MaxAge > 0 {
+			w.Header().Set("Access-Control-Max-Age", strconv.Itoa(m.config.MaxAge))
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (m *corsMiddleware) setOriginHeaders(w http.ResponseWriter, origin string) {
+	// browsers reject the * wildcard in requests with credentials, so the
+	// origin is echoed instead.
+	if m.allowsAnyOrigin() && !m.config.AllowCredentials {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+	} else {
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+	}
+	if m.config.AllowCredentials {
+		w.Header().Set("Access-Control-Allow-Credentials", "true")
+	}
+}
+
+func (m *corsMiddleware) allowsAnyOrigin() bool {
+	for _, allowed := range m.config.AllowedOrigins {
+		if allowed == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// originAllowed checks origin against the allowed origins, which may be *,
+// or contain a wildcard for subdomains, like https://*.example.com.
+func (m *corsMiddleware) originAllowed(origin string) bool {
+	origin = strings.ToLower(origin)
+	for _, allowed := range m.config.AllowedOrigins {
+		allowed = strings.ToLower(allowed)
+		if allowed == "*" || allowed == origin {
+			return true
+		}
+		if i := strings.Index(allowed, "*"); i >= 0 {
+			prefix, suffix := allowed[:i], allowed[i+1:]
+			if len(origin) > len(prefix)+len(suffix) && strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, suffix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (m *corsMiddleware) methodAllowed(method string) bool {
+	method = strings.ToUpper(method)
+	for _, allowed := range m.config.AllowedMethods {
+		if allowed == method {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *corsMiddleware) headersAllowed(requested string) bool {
+	for _, header := range strings.Split(requested, ",") {
+		header = strings.TrimSpace(header)
+		if header == "" {
+			continue
+		}
+		found := false
+		for _, allowed := range m.config.AllowedHeaders {
+			if strings.EqualFold(allowed, header) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
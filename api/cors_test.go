@@ -0,0 +1,151 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/tsuru/config"
+	check "gopkg.in/check.v1"
+)
+
+func newTestCORSMiddleware(c *check.C, conf map[interface{}]interface{}) *corsMiddleware {
+	config.Set("cors", conf)
+	m, err := newCORSMiddleware()
+	c.Assert(err, check.IsNil)
+	c.Assert(m, check.NotNil)
+	return m
+}
+
+func (s *S) TestNewCORSMiddlewareDisabled(c *check.C) {
+	m, err := newCORSMiddleware()
+	c.Assert(err, check.IsNil)
+	c.Assert(m, check.IsNil)
+	config.Set("cors:max-age", 600)
+	defer config.Unset("cors")
+	m, err = newCORSMiddleware()
+	c.Assert(err, check.IsNil)
+	c.Assert(m, check.IsNil)
+}
+
+func (s *S) TestCORSMiddleware(c *check.C) {
+	defer config.Unset("cors")
+	m := newTestCORSMiddleware(c, map[interface{}]interface{}{
+		"allowed-origins":   []interface{}{"https://dashboard.example.com", "https://*.tsuru.example.com"},
+		"exposed-headers":   []interface{}{"X-Total-Count"},
+		"allow-credentials": true,
+	})
+	tests := []struct {
+		origin  string
+		allowed bool
+	}{
+		{origin: "https://dashboard.example.com", allowed: true},
+		{origin: "https://ui.tsuru.example.com", allowed: true},
+		{origin: "https://tsuru.example.com", allowed: false},
+		{origin: "http://dashboard.example.com", allowed: false},
+		{origin: "https://evil.com", allowed: false},
+	}
+	for _, tt := range tests {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest("GET", "/apps", nil)
+		c.Assert(err, check.IsNil)
+		request.Header.Set("Origin", tt.origin)
+		h, log := doHandler()
+		m.ServeHTTP(recorder, request, h)
+		c.Assert(log.called, check.Equals, true)
+		c.Assert(recorder.Header().Get("Vary"), check.Equals, "Origin")
+		if tt.allowed {
+			c.Assert(recorder.Header().Get("Access-Control-Allow-Origin"), check.Equals, tt.origin)
+			c.Assert(recorder.Header().Get("Access-Control-Allow-Credentials"), check.Equals, "true")
+			c.Assert(recorder.Header().Get("Access-Control-Expose-Headers"), check.Equals, "X-Total-Count")
+		} else {
+			c.Assert(recorder.Header().Get("Access-Control-Allow-Origin"), check.Equals, "", check.Commentf("origin %s", tt.origin))
+		}
+	}
+}
+
+func (s *S) TestCORSMiddlewareWithoutOrigin(c *check.C) {
+	defer config.Unset("cors")
+	m := newTestCORSMiddleware(c, map[interface{}]interface{}{
+		"allowed-origins": []interface{}{"*"},
+	})
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest("GET", "/apps", nil)
+	c.Assert(err, check.IsNil)
+	h, log := doHandler()
+	m.ServeHTTP(recorder, request, h)
+	c.Assert(log.called, check.Equals, true)
+	c.Assert(recorder.Header(), check.HasLen, 0)
+}
+
+func (s *S) TestCORSMiddlewareAnyOrigin(c *check.C) {
+	defer config.Unset("cors")
+	m := newTestCORSMiddleware(c, map[interface{}]interface{}{
+		"allowed-origins": []interface{}{"*"},
+	})
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest("GET", "/apps", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Origin", "https://dashboard.example.com")
+	h, _ := doHandler()
+	m.ServeHTTP(recorder, request, h)
+	c.Assert(recorder.Header().Get("Access-Control-Allow-Origin"), check.Equals, "*")
+	c.Assert(recorder.Header().Get("Access-Control-Allow-Credentials"), check.Equals, "")
+}
+
+func (s *S) TestCORSMiddlewarePreflight(c *check.C) {
+	defer config.Unset("cors")
+	m := newTestCORSMiddleware(c, map[interface{}]interface{}{
+		"allowed-origins": []interface{}{"https://dashboard.example.com"},
+		"allowed-methods": []interface{}{"get", "post"},
+		"max-age":         600,
+	})
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest("OPTIONS", "/apps", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Origin", "https://dashboard.example.com")
+	request.Header.Set("Access-Control-Request-Method", "POST")
+	request.Header.Set("Access-Control-Request-Headers", "authorization, content-type")
+	h, log := doHandler()
+	m.ServeHTTP(recorder, request, h)
+	c.Assert(log.called, check.Equals, false)
+	c.Assert(recorder.Code, check.Equals, http.StatusNoContent)
+	c.Assert(recorder.Header().Get("Access-Control-Allow-Origin"), check.Equals, "https://dashboard.example.com")
+	c.Assert(recorder.Header().Get("Access-Control-Allow-Methods"), check.Equals, "GET, POST")
+	c.Assert(recorder.Header().Get("Access-Control-Allow-Headers"), check.Equals, "Accept, Authorization, Content-Type, If-None-Match")
+	c.Assert(recorder.Header().Get("Access-Control-Max-Age"), check.Equals, "600")
+	c.Assert(recorder.Header().Values("Vary"), check.DeepEquals, []string{"Origin", "Access-Control-Request-Method", "Access-Control-Request-Headers"})
+}
+
+func (s *S) TestCORSMiddlewarePreflightNotAllowed(c *check.C) {
+	defer config.Unset("cors")
+	m := newTestCORSMiddleware(c, map[interface{}]interface{}{
+		"allowed-origins": []interface{}{"https://dashboard.example.com"},
+	})
+	tests := []struct {
+		origin  string
+		method  string
+		headers string
+	}{
+		{origin: "https://evil.com", method: "GET"},
+		{origin: "https://dashboard.example.com", method: "TRACE"},
+		{origin: "https://dashboard.example.com", method: "GET", headers: "X-Custom"},
+	}
+	for _, tt := range tests {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest("OPTIONS", "/apps", nil)
+		c.Assert(err, check.IsNil)
+		request.Header.Set("Origin", tt.origin)
+		request.Header.Set("Access-Control-Request-Method", tt.method)
+		request.Header.Set("Access-Control-Request-Headers", tt.headers)
+		h, log := doHandler()
+		m.ServeHTTP(recorder, request, h)
+		c.Assert(log.called, check.Equals, false)
+		c.Assert(recorder.Code, check.Equals, http.StatusNoContent)
+		c.Assert(recorder.Header().Get("Access-Control-Allow-Origin"), check.Equals, "")
+		c.Assert(recorder.Header().Get("Access-Control-Allow-Methods"), check.Equals, "")
+	}
+}
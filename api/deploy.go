@@ -5,10 +5,14 @@
 package api
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/tsuru/tsuru/app"
@@ -17,6 +21,7 @@ import (
 	"github.com/tsuru/tsuru/event"
 	tsuruIo "github.com/tsuru/tsuru/io"
 	"github.com/tsuru/tsuru/permission"
+	appTypes "github.com/tsuru/tsuru/types/app"
 )
 
 const eventIDHeader = "X-Tsuru-Eventid"
@@ -27,11 +32,17 @@ const eventIDHeader = "X-Tsuru-Eventid"
 // consume: application/x-www-form-urlencoded
 // responses:
 //   200: OK
+//   202: Deploy started
 //   400: Invalid data
 //   403: Forbidden
 //   404: Not found
+//   409: Timeout waiting for queued deploys
 func deploy(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
 	ctx := r.Context()
+	async, err := isAsync(r)
+	if err != nil {
+		return err
+	}
 	opts, err := prepareToBuild(r)
 	if err != nil {
 		return err
@@ -79,6 +90,27 @@ func deploy(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
 	opts.Message = message
 	opts.NewVersion, _ = strconv.ParseBool(InputValue(r, "new-version"))
 	opts.OverrideVersions, _ = strconv.ParseBool(InputValue(r, "override-versions"))
+	if canary := InputValue(r, "canary"); canary != "" {
+		opts.CanaryWeight, err = strconv.Atoi(strings.TrimSuffix(canary, "%"))
+		if err != nil {
+			return &tsuruErrors.HTTP{Code: http.StatusBadRequest, Message: app.ErrInvalidCanaryWeight.Error()}
+		}
+	}
+	opts.BlueGreen = app.BlueGreenSwitch(InputValue(r, "blue-green"))
+	if opts.BlueGreen != "" && !opts.BlueGreen.Valid() {
+		return &tsuruErrors.HTTP{Code: http.StatusBadRequest, Message: app.ErrInvalidBlueGreenSwitch.Error()}
+	}
+	queue, _ := strconv.ParseBool(InputValue(r, "queue"))
+	queueTimeout := app.DeployQueueTimeout()
+	if timeout := InputValue(r, "queue-timeout"); timeout != "" {
+		queueTimeout, err = time.ParseDuration(timeout)
+		if err != nil || queueTimeout <= 0 {
+			return &tsuruErrors.HTTP{Code: http.StatusBadRequest, Message: fmt.Sprintf("invalid queue timeout %q", timeout)}
+		}
+	}
+	if async && queue {
+		return &tsuruErrors.HTTP{Code: http.StatusBadRequest, Message: "async deploys can't be queued"}
+	}
 	opts.GetKind()
 	if t.GetAppName() != app.InternalAppName {
 		canDeploy := permission.Check(t, permSchemeForDeploy(opts), contextsForApp(instance)...)
@@ -87,27 +119,45 @@ func deploy(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
 		}
 	}
 	var imageID string
-	evt, err := event.New(&event.Opts{
+	evtOpts := &event.Opts{
 		Target:        appTarget(appName),
 		Kind:          permission.PermAppDeploy,
 		RawOwner:      event.Owner{Type: event.OwnerTypeUser, Name: userName},
 		RemoteAddr:    r.RemoteAddr,
+		Context:       r.Context(),
 		CustomData:    opts,
 		Allowed:       event.Allowed(permission.PermAppReadEvents, contextsForApp(instance)...),
 		AllowedCancel: event.Allowed(permission.PermAppUpdateEvents, contextsForApp(instance)...),
 		Cancelable:    true,
-	})
+	}
+	if async {
+		return deployAsync(w, evtOpts, opts)
+	}
+	var evt *event.Event
+	var queueOutput bytes.Buffer
+	if queue {
+		// Nothing is written to the response while the deploy is queued, the
+		// event ID header must be set before the first write and a timeout is
+		// reported as a conflict.
+		evt, err = app.QueueDeploy(ctx, evtOpts, queueTimeout, &queueOutput)
+	} else {
+		evt, err = event.New(evtOpts)
+	}
 	if err != nil {
+		if err == app.ErrDeployQueueTimeout {
+			return &tsuruErrors.HTTP{Code: http.StatusConflict, Message: err.Error()}
+		}
 		return err
 	}
 	defer func() { evt.DoneCustomData(err, map[string]string{"image": imageID}) }()
+	w.Header().Set(eventIDHeader, evt.UniqueID.Hex())
+	writer := tsuruIo.NewKeepAliveWriter(w, 30*time.Second, "please wait...")
+	defer writer.Stop()
+	queueOutput.WriteTo(writer)
 	ctx, cancel := evt.CancelableContext(opts.App.Context())
 	defer cancel()
 	opts.App.ReplaceContext(ctx)
-	w.Header().Set(eventIDHeader, evt.UniqueID.Hex())
 	opts.Event = evt
-	writer := tsuruIo.NewKeepAliveWriter(w, 30*time.Second, "please wait...")
-	defer writer.Stop()
 	opts.OutputStream = writer
 	imageID, err = app.Deploy(ctx, opts)
 	if err == nil {
@@ -116,6 +166,28 @@ func deploy(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
 	return err
 }
 
+// deployAsync creates the deploy event and runs the deploy in background, its
+// output is only written to the event log.
+func deployAsync(w http.ResponseWriter, evtOpts *event.Opts, opts app.DeployOptions) error {
+	cleanup, err := detachUploadedFile(&opts)
+	if err != nil {
+		return err
+	}
+	evt, err := event.New(evtOpts)
+	if err != nil {
+		cleanup()
+		return err
+	}
+	opts.Event = evt
+	opts.OutputStream = io.Discard
+	return runAsync(w, evt, func(ctx context.Context) (interface{}, error) {
+		defer cleanup()
+		opts.App.ReplaceContext(ctx)
+		imageID, err := app.Deploy(ctx, opts)
+		return map[string]string{"image": imageID}, err
+	})
+}
+
 func permSchemeForDeploy(opts app.DeployOptions) *permission.PermissionScheme {
 	switch opts.GetKind() {
 	case app.DeployGit:
@@ -192,8 +264,14 @@ func deployRollback(w http.ResponseWriter, r *http.Request, t auth.Token) error
 	}
 	opts.NewVersion, _ = strconv.ParseBool(InputValue(r, "new-version"))
 	opts.OverrideVersions, _ = strconv.ParseBool(InputValue(r, "override-versions"))
+	opts.RestoreEnvs, _ = strconv.ParseBool(InputValue(r, "restore-envs"))
 	opts.GetKind()
 	canRollback := permission.Check(t, permSchemeForDeploy(opts), contextsForApp(instance)...)
+	if opts.RestoreEnvs {
+		canRollback = canRollback &&
+			permission.Check(t, permission.PermAppUpdateEnvSet, contextsForApp(instance)...) &&
+			permission.Check(t, permission.PermAppUpdateEnvUnset, contextsForApp(instance)...)
+	}
 	if !canRollback {
 		return &tsuruErrors.HTTP{Code: http.StatusForbidden, Message: permission.ErrUnauthorized.Error()}
 	}
@@ -203,6 +281,7 @@ func deployRollback(w http.ResponseWriter, r *http.Request, t auth.Token) error
 		Kind:          permission.PermAppDeploy,
 		Owner:         t,
 		RemoteAddr:    r.RemoteAddr,
+		Context:       r.Context(),
 		CustomData:    opts,
 		Allowed:       event.Allowed(permission.PermAppReadEvents, contextsForApp(instance)...),
 		AllowedCancel: event.Allowed(permission.PermAppUpdateEvents, contextsForApp(instance)...),
@@ -223,6 +302,112 @@ func deployRollback(w http.ResponseWriter, r *http.Request, t auth.Token) error
 	return nil
 }
 
+// title: rollback env diff
+// path: /apps/{app}/deploy/rollback/envs
+// method: GET
+// produce: application/json
+// responses:
+//   200: OK
+//   204: No changes
+//   400: Invalid data
+//   403: Forbidden
+//   404: Not found
+func deployRollbackEnvDiff(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	ctx := r.Context()
+	appName := r.URL.Query().Get(":app")
+	instance, err := app.GetByName(ctx, appName)
+	if err != nil {
+		return &tsuruErrors.HTTP{Code: http.StatusNotFound, Message: fmt.Sprintf("App %s not found.", appName)}
+	}
+	if !permission.Check(t, permission.PermAppReadEnv, contextsForApp(instance)...) {
+		return permission.ErrUnauthorized
+	}
+	image := r.URL.Query().Get("image")
+	if image == "" {
+		return &tsuruErrors.HTTP{
+			Code:    http.StatusBadRequest,
+			Message: "you cannot rollback without an image name",
+		}
+	}
+	diff, err := instance.RollbackEnvDiff(ctx, image)
+	if err != nil {
+		if _, ok := err.(app.ErrNoEnvSnapshot); ok {
+			return &tsuruErrors.HTTP{Code: http.StatusBadRequest, Message: err.Error()}
+		}
+		if appTypes.IsInvalidVersionError(err) {
+			return &tsuruErrors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+		}
+		return err
+	}
+	if len(diff) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(diff)
+}
+
+// title: promote canary deploy
+// path: /apps/{app}/deploy/canary/promote
+// method: POST
+// produce: application/x-json-stream
+// responses:
+//   200: OK
+//   400: No canary deploy in progress
+//   403: Forbidden
+//   404: Not found
+func deployCanaryPromote(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	return deployCanaryFinish(w, r, t, (*app.App).PromoteCanary)
+}
+
+// title: abort canary deploy
+// path: /apps/{app}/deploy/canary/abort
+// method: POST
+// produce: application/x-json-stream
+// responses:
+//   200: OK
+//   400: No canary deploy in progress
+//   403: Forbidden
+//   404: Not found
+func deployCanaryAbort(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	return deployCanaryFinish(w, r, t, (*app.App).AbortCanary)
+}
+
+func deployCanaryFinish(w http.ResponseWriter, r *http.Request, t auth.Token, finish func(*app.App, context.Context, io.Writer) error) (err error) {
+	ctx := r.Context()
+	appName := r.URL.Query().Get(":app")
+	instance, err := app.GetByName(ctx, appName)
+	if err != nil {
+		return &tsuruErrors.HTTP{Code: http.StatusNotFound, Message: fmt.Sprintf("App %s not found.", appName)}
+	}
+	allowed := permission.Check(t, permission.PermAppUpdateDeployCanary, contextsForApp(instance)...)
+	if !allowed {
+		return &tsuruErrors.HTTP{Code: http.StatusForbidden, Message: permission.ErrUnauthorized.Error()}
+	}
+	if instance.Canary == nil {
+		return &tsuruErrors.HTTP{Code: http.StatusBadRequest, Message: app.ErrNoCanaryDeploy.Error()}
+	}
+	evt, err := event.New(&event.Opts{
+		Target:     appTarget(appName),
+		Kind:       permission.PermAppUpdateDeployCanary,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		Context:    r.Context(),
+		CustomData: instance.Canary,
+		Allowed:    event.Allowed(permission.PermAppReadEvents, contextsForApp(instance)...),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	w.Header().Set("Content-Type", "application/x-json-stream")
+	keepAliveWriter := tsuruIo.NewKeepAliveWriter(w, 30*time.Second, "")
+	defer keepAliveWriter.Stop()
+	writer := &tsuruIo.SimpleJsonMessageEncoderWriter{Encoder: json.NewEncoder(keepAliveWriter)}
+	evt.SetLogWriter(writer)
+	return finish(instance, ctx, evt)
+}
+
 // title: deploy list
 // path: /deploys
 // method: GET
@@ -332,6 +517,7 @@ func deployRebuild(w http.ResponseWriter, r *http.Request, t auth.Token) error {
 		Kind:          permission.PermAppDeploy,
 		Owner:         t,
 		RemoteAddr:    r.RemoteAddr,
+		Context:       r.Context(),
 		CustomData:    opts,
 		Allowed:       event.Allowed(permission.PermAppReadEvents, contextsForApp(instance)...),
 		AllowedCancel: event.Allowed(permission.PermAppUpdateEvents, contextsForApp(instance)...),
@@ -404,6 +590,7 @@ func deployRollbackUpdate(w http.ResponseWriter, r *http.Request, t auth.Token)
 		Kind:          permission.PermAppUpdateDeployRollback,
 		Owner:         t,
 		RemoteAddr:    r.RemoteAddr,
+		Context:       r.Context(),
 		CustomData:    event.FormToCustomData(InputFields(r)),
 		Allowed:       event.Allowed(permission.PermAppReadEvents, contextsForApp(instance)...),
 		AllowedCancel: event.Allowed(permission.PermAppUpdateEvents, contextsForApp(instance)...),
@@ -0,0 +1,68 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// errInterruptedByShutdown is the error of events still running when the API
+// finishes shutting down.
+var errInterruptedByShutdown = errors.New("interrupted by API shutdown")
+
+// shellSessions tracks the shell sessions running in the API, they run on
+// hijacked connections, which aren't waited by http.Server on shutdown.
+var shellSessions = &operationTracker{}
+
+// operationTracker counts running operations, allowing the shutdown to wait
+// for them to finish.
+type operationTracker struct {
+	mu    sync.Mutex
+	count int
+	idle  chan struct{}
+}
+
+func (t *operationTracker) add() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.count == 0 {
+		t.idle = make(chan struct{})
+	}
+	t.count++
+}
+
+func (t *operationTracker) done() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.count--
+	if t.count == 0 {
+		close(t.idle)
+	}
+}
+
+func (t *operationTracker) running() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.count
+}
+
+// wait blocks until there are no running operations, or until ctx is done.
+func (t *operationTracker) wait(ctx context.Context) error {
+	t.mu.Lock()
+	if t.count == 0 {
+		t.mu.Unlock()
+		return nil
+	}
+	idle := t.idle
+	t.mu.Unlock()
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
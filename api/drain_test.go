@@ -0,0 +1,48 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"time"
+
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestOperationTrackerWait(c *check.C) {
+	tracker := &operationTracker{}
+	err := tracker.wait(context.Background())
+	c.Assert(err, check.IsNil)
+	tracker.add()
+	tracker.add()
+	c.Assert(tracker.running(), check.Equals, 2)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err = tracker.wait(ctx)
+	c.Assert(err, check.Equals, context.DeadlineExceeded)
+	done := make(chan error)
+	go func() {
+		done <- tracker.wait(context.Background())
+	}()
+	tracker.done()
+	select {
+	case <-done:
+		c.Fatal("wait returned with running operations")
+	case <-time.After(50 * time.Millisecond):
+	}
+	tracker.done()
+	select {
+	case err = <-done:
+		c.Assert(err, check.IsNil)
+	case <-time.After(5 * time.Second):
+		c.Fatal("timeout waiting for operations")
+	}
+	c.Assert(tracker.running(), check.Equals, 0)
+	tracker.add()
+	c.Assert(tracker.running(), check.Equals, 1)
+	tracker.done()
+	err = tracker.wait(context.Background())
+	c.Assert(err, check.IsNil)
+}
@@ -0,0 +1,86 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/tsuru/tsuru/app"
+	"github.com/tsuru/tsuru/errors"
+	appTypes "github.com/tsuru/tsuru/types/app"
+)
+
+// isDryRun returns whether the request asks, through the dry-run query
+// string parameter, to only validate the changes, without applying them.
+func isDryRun(r *http.Request) (bool, error) {
+	value := r.URL.Query().Get("dry-run")
+	if value == "" {
+		return false, nil
+	}
+	dryRun, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, &errors.HTTP{Code: http.StatusBadRequest, Message: "invalid value for dry-run, expected a boolean"}
+	}
+	return dryRun, nil
+}
+
+// dryRunApp is the app as it would be after a dry run of its creation or
+// update. Unlike the app representation in other handlers, it doesn't
+// include information from provisioners and routers, as the app may not
+// exist yet.
+type dryRunApp struct {
+	Name                    string                   `json:"name"`
+	Platform                string                   `json:"platform"`
+	Pool                    string                   `json:"pool"`
+	TeamOwner               string                   `json:"teamowner"`
+	Teams                   []string                 `json:"teams"`
+	Owner                   string                   `json:"owner"`
+	Description             string                   `json:"description"`
+	Plan                    appTypes.Plan            `json:"plan"`
+	ProcessPlans            map[string]appTypes.Plan `json:"processPlans,omitempty"`
+	TerminationGracePeriods map[string]int           `json:"terminationGracePeriods,omitempty"`
+	Routers                 []appTypes.AppRouter     `json:"routers"`
+	Tags                    []string                 `json:"tags"`
+	Metadata                appTypes.Metadata        `json:"metadata"`
+	Internal                bool                     `json:"internal,omitempty"`
+}
+
+func writeDryRunApp(w http.ResponseWriter, a *app.App) error {
+	platform := a.Platform
+	if version := a.GetPlatformVersion(); platform != "" && version != "latest" {
+		platform += ":" + version
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(dryRunApp{
+		Name:                    a.Name,
+		Platform:                platform,
+		Pool:                    a.Pool,
+		TeamOwner:               a.TeamOwner,
+		Teams:                   a.Teams,
+		Owner:                   a.Owner,
+		Description:             a.Description,
+		Plan:                    a.Plan,
+		ProcessPlans:            a.ProcessPlans,
+		TerminationGracePeriods: a.TerminationGracePeriods,
+		Routers:                 a.GetRouters(),
+		Tags:                    a.Tags,
+		Metadata:                a.Metadata,
+		Internal:                a.Internal,
+	})
+}
+
+// dryRunUnits is the number of units a process would have after a dry run of
+// adding or removing units.
+type dryRunUnits struct {
+	Process string `json:"process,omitempty"`
+	Units   int    `json:"units"`
+}
+
+func writeDryRunUnits(w http.ResponseWriter, process string, units int) error {
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(dryRunUnits{Process: process, Units: units})
+}
@@ -0,0 +1,276 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/ajg/form"
+	"github.com/tsuru/tsuru/app"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/provision/pool"
+	apiTypes "github.com/tsuru/tsuru/types/api"
+	appTypes "github.com/tsuru/tsuru/types/app"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+	"github.com/tsuru/tsuru/types/quota"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestIsDryRun(c *check.C) {
+	tests := []struct {
+		url      string
+		expected bool
+		err      string
+	}{
+		{url: "/apps", expected: false},
+		{url: "/apps?dry-run=true", expected: true},
+		{url: "/apps?dry-run=1", expected: true},
+		{url: "/apps?dry-run=false", expected: false},
+		{url: "/apps?dry-run=yes", err: "invalid value for dry-run, expected a boolean"},
+	}
+	for _, tt := range tests {
+		request, err := http.NewRequest("POST", tt.url, nil)
+		c.Assert(err, check.IsNil)
+		dryRun, err := isDryRun(request)
+		if tt.err != "" {
+			c.Assert(err, check.ErrorMatches, tt.err)
+			continue
+		}
+		c.Assert(err, check.IsNil)
+		c.Assert(dryRun, check.Equals, tt.expected)
+	}
+}
+
+func (s *S) dryRunRequest(c *check.C, method, url, body, contentType string) *httptest.ResponseRecorder {
+	request, err := http.NewRequest(method, url, strings.NewReader(body))
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", contentType)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func (s *S) assertNoEvents(c *check.C, target event.Target) {
+	evts, err := event.List(&event.Filter{Target: target})
+	c.Assert(err, check.IsNil)
+	c.Assert(evts, check.HasLen, 0)
+}
+
+func (s *S) TestCreateAppDryRun(c *check.C) {
+	s.setupMockForCreateApp(c, "zend")
+	s.mockService.UserQuota.OnInc = func(item quota.QuotaItem, q int) error {
+		c.Error("quota must not be reserved in dry runs")
+		return nil
+	}
+	recorder := s.dryRunRequest(c, "POST", "/apps?dry-run=true", "name=someapp&platform=zend&description=my app", "application/x-www-form-urlencoded")
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, "application/json")
+	var result dryRunApp
+	err := json.Unmarshal(recorder.Body.Bytes(), &result)
+	c.Assert(err, check.IsNil)
+	c.Assert(result.Name, check.Equals, "someapp")
+	c.Assert(result.Platform, check.Equals, "zend")
+	c.Assert(result.Description, check.Equals, "my app")
+	c.Assert(result.Pool, check.Equals, "test1")
+	c.Assert(result.TeamOwner, check.Equals, s.team.Name)
+	c.Assert(result.Teams, check.DeepEquals, []string{s.team.Name})
+	c.Assert(result.Plan.Name, check.Equals, s.defaultPlan.Name)
+	c.Assert(result.Routers, check.HasLen, 1)
+	_, err = app.GetByName(context.TODO(), "someapp")
+	c.Assert(err, check.Equals, appTypes.ErrAppNotFound)
+	s.assertNoEvents(c, appTarget("someapp"))
+}
+
+func (s *S) TestCreateAppDryRunValidations(c *check.C) {
+	s.setupMockForCreateApp(c, "zend")
+	a := app.App{Name: "myapp", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	recorder := s.dryRunRequest(c, "POST", "/apps?dry-run=true", "name=myapp&platform=zend", "application/x-www-form-urlencoded")
+	c.Assert(recorder.Code, check.Equals, http.StatusConflict)
+	c.Assert(recorder.Header().Get("X-Tsuru-Error-Code"), check.Equals, "app.already-exists")
+	s.mockService.UserQuota.OnGet = func(item quota.QuotaItem) (*quota.Quota, error) {
+		return &quota.Quota{Limit: 1, InUse: 1}, nil
+	}
+	recorder = s.dryRunRequest(c, "POST", "/apps?dry-run=true", "name=otherapp&platform=zend", "application/x-www-form-urlencoded")
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+	c.Assert(recorder.Header().Get("X-Tsuru-Error-Code"), check.Equals, "app.quota.exceeded")
+	_, err = app.GetByName(context.TODO(), "otherapp")
+	c.Assert(err, check.Equals, appTypes.ErrAppNotFound)
+}
+
+func (s *S) TestUpdateAppDryRun(c *check.C) {
+	a := app.App{Name: "myapp", Platform: "zend", TeamOwner: s.team.Name, Description: "old"}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	recorder := s.dryRunRequest(c, "PUT", "/apps/myapp?dry-run=true", "description=new&tag=a&tag=b", "application/x-www-form-urlencoded")
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, "application/json")
+	var result dryRunApp
+	err = json.Unmarshal(recorder.Body.Bytes(), &result)
+	c.Assert(err, check.IsNil)
+	c.Assert(result.Name, check.Equals, "myapp")
+	c.Assert(result.Description, check.Equals, "new")
+	c.Assert(result.Tags, check.DeepEquals, []string{"a", "b"})
+	dbApp, err := app.GetByName(context.TODO(), "myapp")
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.Description, check.Equals, "old")
+	c.Assert(dbApp.Tags, check.HasLen, 0)
+	evts, err := event.List(&event.Filter{Target: appTarget("myapp"), KindNames: []string{permission.PermAppUpdate.FullName()}})
+	c.Assert(err, check.IsNil)
+	c.Assert(evts, check.HasLen, 0)
+}
+
+func (s *S) TestUpdateAppDryRunInvalidPlan(c *check.C) {
+	a := app.App{Name: "myapp", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	recorder := s.dryRunRequest(c, "PUT", "/apps/myapp?dry-run=true", "plan=unknown", "application/x-www-form-urlencoded")
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(recorder.Body.String(), check.Equals, appTypes.ErrPlanNotFound.Error()+"\n")
+}
+
+func (s *S) TestUpdateAppDryRunPermission(c *check.C) {
+	a := app.App{Name: "myapp", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermAppUpdateDescription,
+		Context: permission.Context(permTypes.CtxApp, a.Name),
+	})
+	request, err := http.NewRequest("PUT", "/apps/myapp?dry-run=true", strings.NewReader("pool=test1"))
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
+
+func (s *S) TestSetEnvDryRun(c *check.C) {
+	a := app.App{Name: "myapp", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	d := apiTypes.Envs{
+		Envs: []apiTypes.Env{
+			{Name: "DATABASE_HOST", Value: "localhost"},
+			{Name: "DATABASE_PASSWORD", Value: "secret", Private: func(b bool) *bool { return &b }(true)},
+		},
+	}
+	v, err := form.EncodeToValues(&d)
+	c.Assert(err, check.IsNil)
+	recorder := s.dryRunRequest(c, "POST", "/apps/myapp/env?dry-run=true", v.Encode(), "application/x-www-form-urlencoded")
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, "application/json")
+	var result []map[string]interface{}
+	err = json.Unmarshal(recorder.Body.Bytes(), &result)
+	c.Assert(err, check.IsNil)
+	values := map[string]interface{}{}
+	for _, env := range result {
+		values[env["name"].(string)] = env["value"]
+	}
+	c.Assert(values["DATABASE_HOST"], check.Equals, "localhost")
+	c.Assert(values["DATABASE_PASSWORD"], check.Equals, app.SuppressedEnv)
+	dbApp, err := app.GetByName(context.TODO(), "myapp")
+	c.Assert(err, check.IsNil)
+	_, ok := dbApp.Env["DATABASE_HOST"]
+	c.Assert(ok, check.Equals, false)
+	evts, err := event.List(&event.Filter{Target: appTarget("myapp"), KindNames: []string{permission.PermAppUpdateEnvSet.FullName()}})
+	c.Assert(err, check.IsNil)
+	c.Assert(evts, check.HasLen, 0)
+}
+
+func (s *S) TestSetEnvDryRunInvalidEnv(c *check.C) {
+	a := app.App{Name: "myapp", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	d := apiTypes.Envs{Envs: []apiTypes.Env{{Name: "INVALID-NAME", Value: "x"}}}
+	v, err := form.EncodeToValues(&d)
+	c.Assert(err, check.IsNil)
+	recorder := s.dryRunRequest(c, "POST", "/apps/myapp/env?dry-run=true", v.Encode(), "application/x-www-form-urlencoded")
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+}
+
+func (s *S) TestAddUnitsDryRun(c *check.C) {
+	a := app.App{Name: "myapp", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	newSuccessfulAppVersion(c, &a)
+	s.provisioner.AddUnits(context.TODO(), &a, 1, "web", nil, nil)
+	s.mockService.AppQuota.OnInc = func(item quota.QuotaItem, quantity int) error {
+		c.Error("quota must not be reserved in dry runs")
+		return nil
+	}
+	recorder := s.dryRunRequest(c, "PUT", "/apps/myapp/units?dry-run=true", "units=3&process=web", "application/x-www-form-urlencoded")
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, "application/json")
+	c.Assert(recorder.Body.String(), check.Equals, `{"process":"web","units":4}`+"\n")
+	c.Assert(s.provisioner.GetUnits(&a), check.HasLen, 1)
+	s.assertNoEvents(c, appTarget("myapp"))
+}
+
+func (s *S) TestAddUnitsDryRunQuotaExceeded(c *check.C) {
+	a := app.App{Name: "myapp", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	newSuccessfulAppVersion(c, &a)
+	s.mockService.AppQuota.OnGet = func(item quota.QuotaItem) (*quota.Quota, error) {
+		return &quota.Quota{Limit: 2, InUse: 0}, nil
+	}
+	recorder := s.dryRunRequest(c, "PUT", "/apps/myapp/units?dry-run=true", "units=3&process=web", "application/x-www-form-urlencoded")
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+	c.Assert(recorder.Header().Get("X-Tsuru-Error-Code"), check.Equals, "app.quota.exceeded")
+}
+
+func (s *S) TestRemoveUnitsDryRun(c *check.C) {
+	a := app.App{Name: "myapp", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	newSuccessfulAppVersion(c, &a)
+	s.provisioner.AddUnits(context.TODO(), &a, 3, "web", nil, nil)
+	recorder := s.dryRunRequest(c, "DELETE", "/apps/myapp/units?dry-run=true&units=2&process=web", "", "application/x-www-form-urlencoded")
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(recorder.Body.String(), check.Equals, `{"process":"web","units":1}`+"\n")
+	c.Assert(s.provisioner.GetUnits(&a), check.HasLen, 3)
+	recorder = s.dryRunRequest(c, "DELETE", "/apps/myapp/units?dry-run=true&units=4&process=web", "", "application/x-www-form-urlencoded")
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(recorder.Body.String(), check.Equals, "cannot remove 4 units, the app has 3 units\n")
+	s.assertNoEvents(c, appTarget("myapp"))
+}
+
+func (s *S) TestPoolUpdateDryRun(c *check.C) {
+	err := pool.AddPool(context.TODO(), pool.AddPoolOptions{Name: "pool1"})
+	c.Assert(err, check.IsNil)
+	recorder := s.dryRunRequest(c, http.MethodPut, "/pools/pool1?dry-run=true", "default=true&force=true", "application/x-www-form-urlencoded")
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, "application/json")
+	var result pool.Pool
+	err = json.Unmarshal(recorder.Body.Bytes(), &result)
+	c.Assert(err, check.IsNil)
+	c.Assert(result.Name, check.Equals, "pool1")
+	c.Assert(result.Default, check.Equals, true)
+	p, err := pool.GetPoolByName(context.TODO(), "pool1")
+	c.Assert(err, check.IsNil)
+	c.Assert(p.Default, check.Equals, false)
+	p, err = pool.GetPoolByName(context.TODO(), "test1")
+	c.Assert(err, check.IsNil)
+	c.Assert(p.Default, check.Equals, true)
+	s.assertNoEvents(c, event.Target{Type: event.TargetTypePool, Value: "pool1"})
+}
+
+func (s *S) TestPoolUpdateDryRunDefaultPoolAlreadyExists(c *check.C) {
+	err := pool.AddPool(context.TODO(), pool.AddPoolOptions{Name: "pool1"})
+	c.Assert(err, check.IsNil)
+	recorder := s.dryRunRequest(c, http.MethodPut, "/pools/pool1?dry-run=true", "default=true", "application/x-www-form-urlencoded")
+	c.Assert(recorder.Code, check.Equals, http.StatusConflict)
+	recorder = s.dryRunRequest(c, http.MethodPut, "/pools/unknown?dry-run=true", "default=false", "application/x-www-form-urlencoded")
+	c.Assert(recorder.Code, check.Equals, http.StatusNotFound)
+}
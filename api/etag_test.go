@@ -0,0 +1,104 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/tsuru/tsuru/app"
+	"github.com/tsuru/tsuru/provision/pool"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestEtagMatches(c *check.C) {
+	tests := []struct {
+		ifNoneMatch string
+		etag        string
+		expected    bool
+	}{
+		{"", `W/"abc"`, false},
+		{`W/"abc"`, `W/"abc"`, true},
+		{`"abc"`, `W/"abc"`, true},
+		{`W/"xyz", W/"abc"`, `W/"abc"`, true},
+		{`W/"xyz"`, `W/"abc"`, false},
+		{"*", `W/"abc"`, true},
+	}
+	for _, tt := range tests {
+		c.Assert(etagMatches(tt.ifNoneMatch, tt.etag), check.Equals, tt.expected, check.Commentf("%q", tt.ifNoneMatch))
+	}
+}
+
+func (s *S) TestWriteJSONWithETag(c *check.C) {
+	request, err := http.NewRequest("GET", "/pools", nil)
+	c.Assert(err, check.IsNil)
+	recorder := httptest.NewRecorder()
+	err = writeJSONWithETag(recorder, request, []string{"a", "b"})
+	c.Assert(err, check.IsNil)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(recorder.Body.String(), check.Equals, `["a","b"]`+"\n")
+	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, "application/json")
+	etag := recorder.Header().Get("ETag")
+	c.Assert(etag, check.Matches, `W/"[0-9a-f]{32}"`)
+	request.Header.Set("If-None-Match", etag)
+	recorder = httptest.NewRecorder()
+	err = writeJSONWithETag(recorder, request, []string{"a", "b"})
+	c.Assert(err, check.IsNil)
+	c.Assert(recorder.Code, check.Equals, http.StatusNotModified)
+	c.Assert(recorder.Body.Len(), check.Equals, 0)
+	c.Assert(recorder.Header().Get("ETag"), check.Equals, etag)
+	recorder = httptest.NewRecorder()
+	err = writeJSONWithETag(recorder, request, []string{"a", "c"})
+	c.Assert(err, check.IsNil)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(recorder.Header().Get("ETag"), check.Not(check.Equals), etag)
+}
+
+func (s *S) TestAppListNotModified(c *check.C) {
+	a := app.App{Name: "app1", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest("GET", "/apps", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	etag := recorder.Header().Get("ETag")
+	c.Assert(etag, check.Not(check.Equals), "")
+	request.Header.Set("If-None-Match", etag)
+	recorder = httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNotModified)
+	c.Assert(recorder.Body.Len(), check.Equals, 0)
+	a = app.App{Name: "app2", Platform: "zend", TeamOwner: s.team.Name}
+	err = app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	recorder = httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(recorder.Header().Get("ETag"), check.Not(check.Equals), etag)
+}
+
+func (s *S) TestPoolListNotModified(c *check.C) {
+	request, err := http.NewRequest("GET", "/pools", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	etag := recorder.Header().Get("ETag")
+	c.Assert(etag, check.Not(check.Equals), "")
+	request.Header.Set("If-None-Match", etag)
+	recorder = httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNotModified)
+	err = pool.AddPool(context.TODO(), pool.AddPoolOptions{Name: "pool2", Public: true})
+	c.Assert(err, check.IsNil)
+	recorder = httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+}
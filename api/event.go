@@ -8,15 +8,17 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/globalsign/mgo/bson"
-	"github.com/tsuru/config"
-	"github.com/tsuru/tsuru/app"
 	"github.com/tsuru/tsuru/auth"
 	"github.com/tsuru/tsuru/errors"
 	"github.com/tsuru/tsuru/event"
 	"github.com/tsuru/tsuru/permission"
+	permTypes "github.com/tsuru/tsuru/types/permission"
 )
 
 // title: event list
@@ -26,6 +28,7 @@ import (
 // responses:
 //   200: OK
 //   204: No content
+//   400: Invalid cursor
 func eventList(w http.ResponseWriter, r *http.Request, t auth.Token) error {
 	var filter *event.Filter
 	err := ParseInput(r, &filter)
@@ -40,22 +43,43 @@ func eventList(w http.ResponseWriter, r *http.Request, t auth.Token) error {
 	}
 	events, err := event.List(filter)
 	if err != nil {
+		if _, ok := err.(event.ErrValidation); ok {
+			return &errors.HTTP{Code: http.StatusBadRequest, Message: err.Error()}
+		}
 		return err
 	}
 	if len(events) == 0 {
 		w.WriteHeader(http.StatusNoContent)
 		return nil
 	}
-	for _, event := range events {
-		err = suppressSensitiveEnvs(event)
+	for _, evt := range events {
+		err = event.Redact(evt)
 		if err != nil {
 			return err
 		}
 	}
+	if len(events) == filter.Limit && filter.Skip == 0 && filter.Sort == "" {
+		w.Header().Set("Link", eventListNextLink(r, events[len(events)-1]))
+	}
 	w.Header().Add("Content-Type", "application/json")
 	return json.NewEncoder(w).Encode(events)
 }
 
+// eventListNextLink returns a Link header value pointing to the next page of
+// events, using the last listed event as cursor.
+func eventListNextLink(r *http.Request, last *event.Event) string {
+	query := url.Values{}
+	for k, v := range r.URL.Query() {
+		if strings.HasPrefix(k, ":") {
+			continue
+		}
+		query[k] = v
+	}
+	query.Set("after", last.UniqueID.Hex())
+	next := url.URL{Path: r.URL.Path, RawQuery: query.Encode()}
+	return fmt.Sprintf(`<%s>; rel="next"`, next.String())
+}
+
 // title: kind list
 // path: /events/kinds
 // method: GET
@@ -76,6 +100,51 @@ func kindList(w http.ResponseWriter, r *http.Request, t auth.Token) error {
 	return json.NewEncoder(w).Encode(kinds)
 }
 
+// title: event summary
+// path: /events/summary
+// method: GET
+// produce: application/json
+// responses:
+//   200: OK
+//   204: No content
+//   400: Invalid group by or period
+//   401: Unauthorized
+func eventSummary(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	groupBy := r.URL.Query().Get("group-by")
+	if groupBy == "" {
+		groupBy = event.SummaryGroupByKind
+	}
+	period := 24 * time.Hour
+	if periodStr := r.URL.Query().Get("period"); periodStr != "" {
+		var err error
+		period, err = time.ParseDuration(periodStr)
+		if err != nil || period <= 0 {
+			return &errors.HTTP{Code: http.StatusBadRequest, Message: "invalid period, must be a positive duration like 24h"}
+		}
+	}
+	perms, err := t.Permissions()
+	if err != nil {
+		return err
+	}
+	filter := &event.Filter{
+		Since:       time.Now().Add(-period),
+		Permissions: perms,
+	}
+	entries, err := event.Summary(filter, groupBy)
+	if err != nil {
+		if _, ok := err.(event.ErrValidation); ok {
+			return &errors.HTTP{Code: http.StatusBadRequest, Message: err.Error()}
+		}
+		return err
+	}
+	if len(entries) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+	w.Header().Add("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(entries)
+}
+
 // title: event info
 // path: /events/{uuid}
 // method: GET
@@ -105,13 +174,49 @@ func eventInfo(w http.ResponseWriter, r *http.Request, t auth.Token) error {
 		return permission.ErrUnauthorized
 	}
 	w.Header().Add("Content-Type", "application/json")
-	err = suppressSensitiveEnvs(e)
+	err = event.Redact(e)
 	if err != nil {
 		return err
 	}
 	return json.NewEncoder(w).Encode(e)
 }
 
+// title: event diff
+// path: /events/{uuid}/diff
+// method: GET
+// produce: application/json
+// responses:
+//   200: OK
+//   204: No content
+//   400: Invalid uuid
+//   401: Unauthorized
+//   404: Not found
+func eventDiff(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	uuid := r.URL.Query().Get(":uuid")
+	if !bson.IsObjectIdHex(uuid) {
+		msg := fmt.Sprintf("uuid parameter is not ObjectId: %s", uuid)
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: msg}
+	}
+	e, err := event.GetByID(bson.ObjectIdHex(uuid))
+	if err != nil {
+		return &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+	}
+	scheme, err := permission.SafeGet(e.Allowed.Scheme)
+	if err != nil {
+		return err
+	}
+	if !permission.Check(t, scheme, e.Allowed.Contexts...) {
+		return permission.ErrUnauthorized
+	}
+	diff := e.Diff()
+	if len(diff) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(diff)
+}
+
 // title: event cancel
 // path: /events/{uuid}/cancel
 // method: POST
@@ -155,6 +260,135 @@ func eventCancel(w http.ResponseWriter, r *http.Request, t auth.Token) error {
 	return nil
 }
 
+type eventCancelResult struct {
+	ID    string `json:"id"`
+	Error string `json:"error,omitempty"`
+}
+
+// title: event bulk cancel
+// path: /events/cancel
+// method: POST
+// consume: application/x-www-form-urlencoded
+// produce: application/json
+// responses:
+//   200: OK
+//   204: No content
+//   400: Empty reason or filter
+//   401: Unauthorized
+func eventBulkCancel(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	var input event.Filter
+	err := ParseInput(r, &input)
+	if err != nil {
+		return err
+	}
+	input.LoadKindNames(r.Form)
+	reason := InputValue(r, "reason")
+	if reason == "" {
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: "reason is mandatory"}
+	}
+	if len(input.KindNames) == 0 && input.Target.Type == "" && input.Target.Value == "" && input.OwnerName == "" {
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: "at least one of kind, target or owner filters is mandatory"}
+	}
+	perms, err := t.Permissions()
+	if err != nil {
+		return err
+	}
+	running, cancelable := true, true
+	filter := &event.Filter{
+		Target:      input.Target,
+		KindNames:   input.KindNames,
+		OwnerName:   input.OwnerName,
+		Running:     &running,
+		Cancelable:  &cancelable,
+		Permissions: perms,
+	}
+	var results []eventCancelResult
+	for {
+		events, err := event.List(filter)
+		if err != nil {
+			return err
+		}
+		if len(events) == 0 {
+			break
+		}
+		for _, e := range events {
+			result := eventCancelResult{ID: e.UniqueID.Hex()}
+			scheme, err := permission.SafeGet(e.AllowedCancel.Scheme)
+			if err != nil {
+				return err
+			}
+			if !permission.Check(t, scheme, e.AllowedCancel.Contexts...) {
+				result.Error = permission.ErrUnauthorized.Error()
+			} else if err = e.TryCancel(reason, t.GetUserName()); err != nil {
+				result.Error = err.Error()
+			}
+			results = append(results, result)
+		}
+		// events are listed in pages, using the last one as cursor.
+		filter.After = events[len(events)-1].UniqueID.Hex()
+	}
+	if len(results) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(results)
+}
+
+// title: event owner reassign
+// path: /events/owner
+// method: POST
+// consume: application/x-www-form-urlencoded
+// produce: application/json
+// responses:
+//   200: OK
+//   400: Invalid data
+//   401: Unauthorized
+//   404: User not found
+func eventOwnerReassign(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	from := InputValue(r, "from")
+	to := InputValue(r, "to")
+	if from == "" {
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: "from is mandatory"}
+	}
+	if !permission.Check(t, permission.PermUserUpdateEvents, permission.Context(permTypes.CtxUser, from)) {
+		return permission.ErrUnauthorized
+	}
+	newOwner := event.Owner{Type: event.OwnerTypeRemovedUser, Name: from}
+	if to != "" {
+		if _, err = auth.GetUserByEmail(to); err != nil {
+			return &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+		}
+		newOwner = event.Owner{Type: event.OwnerTypeUser, Name: to}
+	}
+	evt, err := event.New(&event.Opts{
+		Target:     event.Target{Type: event.TargetTypeUser, Value: from},
+		Kind:       permission.PermUserUpdateEvents,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		CustomData: event.FormToCustomData(InputFields(r)),
+		Allowed:    event.Allowed(permission.PermUserReadEvents, permission.Context(permTypes.CtxUser, from)),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	var updated int
+	for _, owner := range []event.Owner{
+		{Type: event.OwnerTypeUser, Name: from},
+		{Type: event.OwnerTypeRemovedUser, Name: from},
+	} {
+		var n int
+		n, err = event.ReassignOwner(owner, newOwner)
+		if err != nil {
+			return err
+		}
+		updated += n
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(map[string]int{"updated": updated})
+}
+
 // title: event block list
 // path: /events/blocks
 // method: GET
@@ -204,6 +438,13 @@ func eventBlockAdd(w http.ResponseWriter, r *http.Request, t auth.Token) (err er
 	if block.Reason == "" {
 		return &errors.HTTP{Code: http.StatusBadRequest, Message: "reason is required"}
 	}
+	if expiresIn := InputValue(r, "expires-in"); expiresIn != "" {
+		duration, parseErr := time.ParseDuration(expiresIn)
+		if parseErr != nil || duration <= 0 {
+			return &errors.HTTP{Code: http.StatusBadRequest, Message: fmt.Sprintf("invalid expires-in duration: %q", expiresIn)}
+		}
+		block.ExpiresAt = time.Now().Add(duration)
+	}
 	evt, err := event.New(&event.Opts{
 		Target:     event.Target{Type: event.TargetTypeEventBlock},
 		Kind:       permission.PermEventBlockAdd,
@@ -260,30 +501,3 @@ func eventBlockRemove(w http.ResponseWriter, r *http.Request, t auth.Token) (err
 	}
 	return err
 }
-
-func suppressSensitiveEnvs(e *event.Event) error {
-	if supressEnabled, _ := config.GetBool("events:suppress-sensitive-envs"); !supressEnabled {
-		return nil
-	}
-	if e.Kind.Name != permission.PermAppDeploy.FullName() || len(e.StartCustomData.Data) == 0 {
-		return nil
-	}
-
-	deployOptions := &app.DeployOptions{}
-	err := bson.Unmarshal(e.StartCustomData.Data, deployOptions)
-	if err != nil {
-		return err
-	}
-
-	if deployOptions.App == nil {
-		return nil
-	}
-
-	deployOptions.App.SuppressSensitiveEnvs()
-
-	e.StartCustomData.Data, err = bson.Marshal(deployOptions)
-	if err != nil {
-		return err
-	}
-	return nil
-}
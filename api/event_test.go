@@ -10,7 +10,10 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/ajg/form"
 	"github.com/globalsign/mgo/bson"
@@ -171,6 +174,61 @@ func (s *EventSuite) TestEventList(c *check.C) {
 	c.Assert(result, check.HasLen, 10)
 }
 
+func (s *EventSuite) TestEventListWithCursor(c *check.C) {
+	_, err := s.insertEvents("app", nil, c)
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest("GET", "/events?limit=6&target.type=app", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	server := RunServer(true)
+	server.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	var result []event.Event
+	err = json.Unmarshal(recorder.Body.Bytes(), &result)
+	c.Assert(err, check.IsNil)
+	c.Assert(result, check.HasLen, 6)
+	link := recorder.Header().Get("Link")
+	c.Assert(link, check.Matches, `^</events\?.*>; rel="next"$`)
+	nextURL, err := url.Parse(strings.TrimSuffix(strings.TrimPrefix(link, "<"), `>; rel="next"`))
+	c.Assert(err, check.IsNil)
+	c.Assert(nextURL.Query(), check.DeepEquals, url.Values{
+		"limit":       []string{"6"},
+		"target.type": []string{"app"},
+		"after":       []string{result[5].UniqueID.Hex()},
+	})
+	request, err = http.NewRequest("GET", nextURL.String(), nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder = httptest.NewRecorder()
+	server.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	var nextResult []event.Event
+	err = json.Unmarshal(recorder.Body.Bytes(), &nextResult)
+	c.Assert(err, check.IsNil)
+	c.Assert(nextResult, check.HasLen, 4)
+	c.Assert(recorder.Header().Get("Link"), check.Equals, "")
+	seen := map[string]bool{}
+	for i := range result {
+		seen[result[i].UniqueID.Hex()] = true
+	}
+	for i := range nextResult {
+		seen[nextResult[i].UniqueID.Hex()] = true
+	}
+	c.Assert(seen, check.HasLen, 10)
+}
+
+func (s *EventSuite) TestEventListInvalidCursor(c *check.C) {
+	request, err := http.NewRequest("GET", "/events?after=invalid", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	server := RunServer(true)
+	server.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(recorder.Body.String(), check.Equals, event.ErrInvalidCursor.Error()+"\n")
+}
+
 func (s *EventSuite) TestEventListFilterByTarget(c *check.C) {
 	_, err := s.insertEvents("app", nil, c)
 	c.Assert(err, check.IsNil)
@@ -291,6 +349,56 @@ func (s *EventSuite) TestKindListNoContent(c *check.C) {
 	c.Assert(recorder.Code, check.Equals, http.StatusNoContent)
 }
 
+func (s *EventSuite) TestEventSummary(c *check.C) {
+	_, err := s.insertEvents("app", nil, c)
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest("GET", "/1.13/events/summary?group-by=team&period=1h", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	server := RunServer(true)
+	server.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, "application/json")
+	var result []event.SummaryEntry
+	err = json.Unmarshal(recorder.Body.Bytes(), &result)
+	c.Assert(err, check.IsNil)
+	c.Assert(result, check.DeepEquals, []event.SummaryEntry{
+		{Key: s.team.Name, Count: 10},
+	})
+}
+
+func (s *EventSuite) TestEventSummaryNoContent(c *check.C) {
+	request, err := http.NewRequest("GET", "/1.13/events/summary", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	server := RunServer(true)
+	server.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNoContent)
+}
+
+func (s *EventSuite) TestEventSummaryInvalidInput(c *check.C) {
+	tests := []struct {
+		query   string
+		message string
+	}{
+		{query: "group-by=owner", message: "invalid group by, must be one of: kind, target, team\n"},
+		{query: "period=1d", message: "invalid period, must be a positive duration like 24h\n"},
+		{query: "period=-1h", message: "invalid period, must be a positive duration like 24h\n"},
+	}
+	for _, tt := range tests {
+		request, err := http.NewRequest("GET", "/1.13/events/summary?"+tt.query, nil)
+		c.Assert(err, check.IsNil)
+		request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+		recorder := httptest.NewRecorder()
+		server := RunServer(true)
+		server.ServeHTTP(recorder, request)
+		c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+		c.Assert(recorder.Body.String(), check.Equals, tt.message)
+	}
+}
+
 func (s *EventSuite) TestEventInfoInvalidObjectID(c *check.C) {
 	u := fmt.Sprintf("/events/%s", "123")
 	request, err := http.NewRequest("GET", u, nil)
@@ -390,6 +498,293 @@ func (s *EventSuite) TestEventCancelNotCancelable(c *check.C) {
 	c.Assert(recorder.Body.String(), check.Equals, "event is not cancelable\n")
 }
 
+func (s *EventSuite) TestEventDiff(c *check.C) {
+	evt, err := event.New(&event.Opts{
+		Target:  event.Target{Type: event.TargetTypePool, Value: "pool1"},
+		Owner:   s.token,
+		Kind:    permission.PermPoolUpdate,
+		Allowed: event.Allowed(permission.PermPoolReadEvents),
+	})
+	c.Assert(err, check.IsNil)
+	err = evt.SetChanges(map[string]interface{}{"default": false}, map[string]interface{}{"default": true})
+	c.Assert(err, check.IsNil)
+	err = evt.Done(nil)
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest("GET", fmt.Sprintf("/events/%s/diff", evt.UniqueID.Hex()), nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	server := RunServer(true)
+	server.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, "application/json")
+	var result []event.FieldChange
+	err = json.Unmarshal(recorder.Body.Bytes(), &result)
+	c.Assert(err, check.IsNil)
+	c.Assert(result, check.DeepEquals, []event.FieldChange{
+		{Field: "default", Before: false, After: true},
+	})
+}
+
+func (s *EventSuite) TestEventDiffNoChanges(c *check.C) {
+	events, err := s.insertEvents("app", nil, c)
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest("GET", fmt.Sprintf("/events/%s/diff", events[0].UniqueID.Hex()), nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	server := RunServer(true)
+	server.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNoContent)
+}
+
+func (s *EventSuite) TestEventDiffNotFound(c *check.C) {
+	request, err := http.NewRequest("GET", fmt.Sprintf("/events/%s/diff", bson.NewObjectId().Hex()), nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	server := RunServer(true)
+	server.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNotFound)
+}
+
+func (s *EventSuite) TestEventOwnerReassign(c *check.C) {
+	evt, err := event.New(&event.Opts{
+		Target:   event.Target{Type: event.TargetTypeApp, Value: "myapp"},
+		Kind:     permission.PermAppUpdateEnvSet,
+		RawOwner: event.Owner{Type: event.OwnerTypeRemovedUser, Name: "old@example.com"},
+		Allowed:  event.Allowed(permission.PermAppReadEvents),
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(evt.Done(nil), check.IsNil)
+	body := strings.NewReader("from=old@example.com&to=" + s.token.GetUserName())
+	request, err := http.NewRequest("POST", "/1.13/events/owner", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	recorder := httptest.NewRecorder()
+	server := RunServer(true)
+	server.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(recorder.Body.String(), check.Equals, "{\"updated\":1}\n")
+	dbEvt, err := event.GetByID(evt.UniqueID)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbEvt.Owner, check.Equals, event.Owner{Type: event.OwnerTypeUser, Name: s.token.GetUserName()})
+}
+
+func (s *EventSuite) TestEventOwnerReassignForbidden(c *check.C) {
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermUserDelete,
+		Context: permission.Context(permTypes.CtxUser, "old@example.com"),
+	})
+	body := strings.NewReader("from=old@example.com")
+	request, err := http.NewRequest("POST", "/1.13/events/owner", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	recorder := httptest.NewRecorder()
+	server := RunServer(true)
+	server.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
+
+func (s *EventSuite) TestEventOwnerReassignUserUpdateEvents(c *check.C) {
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermUserUpdateEvents,
+		Context: permission.Context(permTypes.CtxUser, "old@example.com"),
+	})
+	body := strings.NewReader("from=old@example.com")
+	request, err := http.NewRequest("POST", "/1.13/events/owner", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	recorder := httptest.NewRecorder()
+	server := RunServer(true)
+	server.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(eventtest.EventDesc{
+		Target: event.Target{Type: event.TargetTypeUser, Value: "old@example.com"},
+		Owner:  token.GetUserName(),
+		Kind:   "user.update.events",
+	}, eventtest.HasEvent)
+}
+
+func (s *EventSuite) TestEventOwnerReassignUserNotFound(c *check.C) {
+	body := strings.NewReader("from=old@example.com&to=notfound@example.com")
+	request, err := http.NewRequest("POST", "/1.13/events/owner", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	recorder := httptest.NewRecorder()
+	server := RunServer(true)
+	server.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNotFound)
+}
+
+func (s *EventSuite) TestEventOwnerReassignNoFrom(c *check.C) {
+	request, err := http.NewRequest("POST", "/1.13/events/owner", strings.NewReader("to=x@example.com"))
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	recorder := httptest.NewRecorder()
+	server := RunServer(true)
+	server.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(recorder.Body.String(), check.Equals, "from is mandatory\n")
+}
+
+func (s *EventSuite) TestEventBulkCancel(c *check.C) {
+	var evts []*event.Event
+	for i, kind := range []*permission.PermissionScheme{permission.PermAppDeploy, permission.PermAppDeploy, permission.PermAppUpdateEnvSet} {
+		evt, err := event.New(&event.Opts{
+			Target:        event.Target{Type: event.TargetTypeApp, Value: fmt.Sprintf("app-%d", i)},
+			Owner:         s.token,
+			Kind:          kind,
+			Cancelable:    true,
+			Allowed:       event.Allowed(permission.PermAppReadEvents, permission.Context(permTypes.CtxTeam, s.team.Name)),
+			AllowedCancel: event.Allowed(permission.PermAppUpdateEvents, permission.Context(permTypes.CtxTeam, s.team.Name)),
+		})
+		c.Assert(err, check.IsNil)
+		evts = append(evts, evt)
+	}
+	body := strings.NewReader("kindname=app.deploy&reason=incident")
+	request, err := http.NewRequest("POST", "/events/cancel", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	recorder := httptest.NewRecorder()
+	server := RunServer(true)
+	server.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	var results []eventCancelResult
+	err = json.Unmarshal(recorder.Body.Bytes(), &results)
+	c.Assert(err, check.IsNil)
+	c.Assert(results, check.HasLen, 2)
+	ids := []string{results[0].ID, results[1].ID}
+	sort.Strings(ids)
+	expected := []string{evts[0].UniqueID.Hex(), evts[1].UniqueID.Hex()}
+	sort.Strings(expected)
+	c.Assert(ids, check.DeepEquals, expected)
+	c.Assert(results[0].Error, check.Equals, "")
+	c.Assert(results[1].Error, check.Equals, "")
+	for i, evt := range evts {
+		dbEvt, err := event.GetByID(evt.UniqueID)
+		c.Assert(err, check.IsNil)
+		c.Assert(dbEvt.CancelInfo.Asked, check.Equals, i < 2)
+	}
+}
+
+func (s *EventSuite) TestEventBulkCancelUnreadableEvents(c *check.C) {
+	_, token := permissiontest.CustomUserWithPermission(c, nativeScheme, "myuser", permission.Permission{
+		Scheme:  permission.PermAppRead,
+		Context: permission.Context(permTypes.CtxTeam, s.team.Name),
+	})
+	var evts []*event.Event
+	for _, team := range []string{s.team.Name, "otherteam"} {
+		evt, err := event.New(&event.Opts{
+			Target:        event.Target{Type: event.TargetTypeApp, Value: "app-" + team},
+			Owner:         s.token,
+			Kind:          permission.PermAppDeploy,
+			Cancelable:    true,
+			Allowed:       event.Allowed(permission.PermAppReadEvents, permission.Context(permTypes.CtxTeam, team)),
+			AllowedCancel: event.Allowed(permission.PermAppUpdateEvents, permission.Context(permTypes.CtxTeam, team)),
+		})
+		c.Assert(err, check.IsNil)
+		evts = append(evts, evt)
+	}
+	body := strings.NewReader("kindname=app.deploy&reason=incident")
+	request, err := http.NewRequest("POST", "/events/cancel", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	recorder := httptest.NewRecorder()
+	server := RunServer(true)
+	server.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	var results []eventCancelResult
+	err = json.Unmarshal(recorder.Body.Bytes(), &results)
+	c.Assert(err, check.IsNil)
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].ID < results[j].ID
+	})
+	c.Assert(results, check.DeepEquals, []eventCancelResult{
+		{ID: evts[0].UniqueID.Hex(), Error: permission.ErrUnauthorized.Error()},
+	})
+	for _, evt := range evts {
+		dbEvt, err := event.GetByID(evt.UniqueID)
+		c.Assert(err, check.IsNil)
+		c.Assert(dbEvt.CancelInfo.Asked, check.Equals, false)
+	}
+}
+
+func (s *EventSuite) TestEventBulkCancelMoreThanOnePage(c *check.C) {
+	for i := 0; i < 101; i++ {
+		_, err := event.New(&event.Opts{
+			Target:        event.Target{Type: event.TargetTypeApp, Value: fmt.Sprintf("app-%d", i)},
+			Owner:         s.token,
+			Kind:          permission.PermAppDeploy,
+			Cancelable:    true,
+			Allowed:       event.Allowed(permission.PermAppReadEvents, permission.Context(permTypes.CtxTeam, s.team.Name)),
+			AllowedCancel: event.Allowed(permission.PermAppUpdateEvents, permission.Context(permTypes.CtxTeam, s.team.Name)),
+		})
+		c.Assert(err, check.IsNil)
+	}
+	body := strings.NewReader("kindname=app.deploy&reason=incident")
+	request, err := http.NewRequest("POST", "/events/cancel", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	recorder := httptest.NewRecorder()
+	server := RunServer(true)
+	server.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	var results []eventCancelResult
+	err = json.Unmarshal(recorder.Body.Bytes(), &results)
+	c.Assert(err, check.IsNil)
+	c.Assert(results, check.HasLen, 101)
+	for _, result := range results {
+		c.Assert(result.Error, check.Equals, "")
+	}
+}
+
+func (s *EventSuite) TestEventBulkCancelNoMatches(c *check.C) {
+	body := strings.NewReader("kindname=app.deploy&reason=incident")
+	request, err := http.NewRequest("POST", "/events/cancel", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	recorder := httptest.NewRecorder()
+	server := RunServer(true)
+	server.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNoContent)
+}
+
+func (s *EventSuite) TestEventBulkCancelNoReason(c *check.C) {
+	body := strings.NewReader("kindname=app.deploy")
+	request, err := http.NewRequest("POST", "/events/cancel", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	recorder := httptest.NewRecorder()
+	server := RunServer(true)
+	server.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(recorder.Body.String(), check.Equals, "reason is mandatory\n")
+}
+
+func (s *EventSuite) TestEventBulkCancelNoFilter(c *check.C) {
+	body := strings.NewReader("reason=incident")
+	request, err := http.NewRequest("POST", "/events/cancel", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	recorder := httptest.NewRecorder()
+	server := RunServer(true)
+	server.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(recorder.Body.String(), check.Equals, "at least one of kind, target or owner filters is mandatory\n")
+}
+
 func (s *EventSuite) TestEventInfoPermission(c *check.C) {
 	_, token := permissiontest.CustomUserWithPermission(c, nativeScheme, "myuser", permission.Permission{
 		Scheme:  permission.PermAppRead,
@@ -500,6 +895,38 @@ func (s *EventSuite) TestEventInfoPermissionWithSensitiveData(c *check.C) {
 
 }
 
+func (s *EventSuite) TestEventInfoRedactsWebhookHeaders(c *check.C) {
+	evt, err := event.New(&event.Opts{
+		Target: event.Target{Type: event.TargetTypeWebhook, Value: "wh1"},
+		Owner:  s.token,
+		Kind:   permission.PermWebhookCreate,
+		CustomData: []map[string]interface{}{
+			{"name": "name", "value": "wh1"},
+			{"name": "headers.Authorization.0", "value": "Bearer mysecret"},
+		},
+		Allowed: event.Allowed(permission.PermWebhookReadEvents),
+	})
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest("GET", fmt.Sprintf("/events/%s", evt.UniqueID.Hex()), nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	server := RunServer(true)
+	server.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	var result event.Event
+	err = json.Unmarshal(recorder.Body.Bytes(), &result)
+	c.Assert(err, check.IsNil)
+	var data []map[string]interface{}
+	err = result.StartData(&data)
+	c.Assert(err, check.IsNil)
+	c.Assert(data, check.DeepEquals, []map[string]interface{}{
+		{"name": "name", "value": "wh1"},
+		{"name": "headers.Authorization.0", "value": event.RedactedValue},
+	})
+	c.Assert(recorder.Body.String(), check.Not(check.Matches), "(?s).*mysecret.*")
+}
+
 func (s *EventSuite) TestEventInfoWithoutPermission(c *check.C) {
 	_, token := permissiontest.CustomUserWithPermission(c, nativeScheme, "myuser", permission.Permission{
 		Scheme:  permission.PermAppRead,
@@ -667,6 +1094,48 @@ func (s *EventSuite) TestEventBlockAdd(c *check.C) {
 	c.Assert(blocks[0].Reason, check.Equals, "block reason")
 }
 
+func (s *EventSuite) TestEventBlockAddWithExpiration(c *check.C) {
+	_, token := permissiontest.CustomUserWithPermission(c, nativeScheme, "myuser", permission.Permission{
+		Scheme:  permission.PermEventBlockAdd,
+		Context: permTypes.PermissionContext{CtxType: permTypes.CtxGlobal},
+	})
+	values := url.Values{"KindName": []string{"app.deploy"}, "Reason": []string{"block reason"}, "expires-in": []string{"1h"}}
+	request, err := http.NewRequest("POST", "/events/blocks", strings.NewReader(values.Encode()))
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	recorder := httptest.NewRecorder()
+	server := RunServer(true)
+	server.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	blocks, err := event.ListBlocks(nil)
+	c.Assert(err, check.IsNil)
+	c.Assert(len(blocks), check.Equals, 1)
+	c.Assert(blocks[0].Active, check.Equals, true)
+	c.Assert(blocks[0].ExpiresAt.After(time.Now().Add(59*time.Minute)), check.Equals, true)
+	c.Assert(blocks[0].ExpiresAt.Before(time.Now().Add(61*time.Minute)), check.Equals, true)
+}
+
+func (s *EventSuite) TestEventBlockAddInvalidExpiration(c *check.C) {
+	_, token := permissiontest.CustomUserWithPermission(c, nativeScheme, "myuser", permission.Permission{
+		Scheme:  permission.PermEventBlockAdd,
+		Context: permTypes.PermissionContext{CtxType: permTypes.CtxGlobal},
+	})
+	values := url.Values{"KindName": []string{"app.deploy"}, "Reason": []string{"block reason"}, "expires-in": []string{"-1h"}}
+	request, err := http.NewRequest("POST", "/events/blocks", strings.NewReader(values.Encode()))
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	recorder := httptest.NewRecorder()
+	server := RunServer(true)
+	server.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(recorder.Body.String(), check.Equals, "invalid expires-in duration: \"-1h\"\n")
+	blocks, err := event.ListBlocks(nil)
+	c.Assert(err, check.IsNil)
+	c.Assert(len(blocks), check.Equals, 0)
+}
+
 func (s *EventSuite) TestEventBlockAddWithoutPermission(c *check.C) {
 	block := &event.Block{KindName: "app.deploy", Reason: "block reason"}
 	values, err := form.EncodeToValues(block)
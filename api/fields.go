@@ -0,0 +1,145 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+)
+
+// fieldSelection is the set of fields requested in the fields parameter of
+// a request, e.g. ?fields=name,units.status. Nested fields are mapped by
+// their parent field, a nil selection means the whole field was requested.
+// Field names are case insensitive.
+type fieldSelection map[string]fieldSelection
+
+// parseFieldSelection parses the fields parameter of r, checking that each
+// field exists in the JSON representation of values of type t. It returns
+// nil when the parameter isn't set.
+func parseFieldSelection(r *http.Request, t reflect.Type) (fieldSelection, error) {
+	var selection fieldSelection
+	for _, value := range r.URL.Query()["fields"] {
+		for _, field := range strings.Split(value, ",") {
+			field = strings.ToLower(strings.TrimSpace(field))
+			if field == "" {
+				continue
+			}
+			path := strings.Split(field, ".")
+			if !hasJSONField(t, path) {
+				return nil, &tsuruErrors.HTTP{
+					Code:    http.StatusBadRequest,
+					Message: fmt.Sprintf("invalid field %q", field),
+				}
+			}
+			if selection == nil {
+				selection = fieldSelection{}
+			}
+			selection.add(path)
+		}
+	}
+	return selection, nil
+}
+
+func (s fieldSelection) add(path []string) {
+	sub, ok := s[path[0]]
+	if len(path) == 1 {
+		s[path[0]] = nil
+		return
+	}
+	if ok && sub == nil {
+		return
+	}
+	if sub == nil {
+		sub = fieldSelection{}
+		s[path[0]] = sub
+	}
+	sub.add(path[1:])
+}
+
+// includes returns whether the top level field is selected.
+func (s fieldSelection) includes(field string) bool {
+	if s == nil {
+		return true
+	}
+	_, ok := s[field]
+	return ok
+}
+
+// apply returns the JSON representation of v with only the selected fields.
+func (s fieldSelection) apply(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value interface{}
+	err = decoder.Decode(&value)
+	if err != nil {
+		return nil, err
+	}
+	return s.filter(value), nil
+}
+
+func (s fieldSelection) filter(value interface{}) interface{} {
+	if s == nil {
+		return value
+	}
+	switch v := value.(type) {
+	case []interface{}:
+		for i := range v {
+			v[i] = s.filter(v[i])
+		}
+		return v
+	case map[string]interface{}:
+		result := map[string]interface{}{}
+		for key, item := range v {
+			sub, ok := s[strings.ToLower(key)]
+			if !ok {
+				continue
+			}
+			result[key] = sub.filter(item)
+		}
+		return result
+	}
+	return value
+}
+
+// hasJSONField returns whether the field path exists in the JSON
+// representation of values of type t.
+func hasJSONField(t reflect.Type, path []string) bool {
+	if len(path) == 0 {
+		return true
+	}
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		if strings.ToLower(name) == path[0] {
+			return hasJSONField(f.Type, path[1:])
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+
+	check "gopkg.in/check.v1"
+)
+
+type fieldsTestItem struct {
+	Name    string `json:"name"`
+	Secret  string `json:"-"`
+	Units   []fieldsTestUnit
+	Details *fieldsTestUnit `json:"details,omitempty"`
+}
+
+type fieldsTestUnit struct {
+	ID     string
+	Status string
+}
+
+func (s *S) TestParseFieldSelection(c *check.C) {
+	request, err := http.NewRequest("GET", "/items?fields=name,Units.status&fields=units.id,details", nil)
+	c.Assert(err, check.IsNil)
+	fields, err := parseFieldSelection(request, reflect.TypeOf(fieldsTestItem{}))
+	c.Assert(err, check.IsNil)
+	c.Assert(fields, check.DeepEquals, fieldSelection{
+		"name":    nil,
+		"units":   fieldSelection{"status": nil, "id": nil},
+		"details": nil,
+	})
+	c.Assert(fields.includes("units"), check.Equals, true)
+	c.Assert(fields.includes("secret"), check.Equals, false)
+}
+
+func (s *S) TestParseFieldSelectionWholeFieldWins(c *check.C) {
+	request, err := http.NewRequest("GET", "/items?fields=units.status,units,units.id", nil)
+	c.Assert(err, check.IsNil)
+	fields, err := parseFieldSelection(request, reflect.TypeOf(fieldsTestItem{}))
+	c.Assert(err, check.IsNil)
+	c.Assert(fields, check.DeepEquals, fieldSelection{"units": nil})
+}
+
+func (s *S) TestParseFieldSelectionEmpty(c *check.C) {
+	request, err := http.NewRequest("GET", "/items", nil)
+	c.Assert(err, check.IsNil)
+	fields, err := parseFieldSelection(request, reflect.TypeOf(fieldsTestItem{}))
+	c.Assert(err, check.IsNil)
+	c.Assert(fields, check.IsNil)
+	c.Assert(fields.includes("name"), check.Equals, true)
+}
+
+func (s *S) TestParseFieldSelectionInvalid(c *check.C) {
+	for _, value := range []string{"xyz", "secret", "name.id", "units.xyz"} {
+		request, err := http.NewRequest("GET", "/items?fields="+value, nil)
+		c.Assert(err, check.IsNil)
+		_, err = parseFieldSelection(request, reflect.TypeOf(fieldsTestItem{}))
+		c.Assert(err, check.ErrorMatches, `invalid field "`+value+`"`)
+	}
+}
+
+func (s *S) TestFieldSelectionApply(c *check.C) {
+	items := []fieldsTestItem{
+		{Name: "a", Secret: "x", Units: []fieldsTestUnit{{ID: "u1", Status: "started"}}},
+		{Name: "b", Details: &fieldsTestUnit{ID: "u2", Status: "error"}},
+	}
+	fields := fieldSelection{"name": nil, "units": fieldSelection{"status": nil}, "details": nil}
+	result, err := fields.apply(items)
+	c.Assert(err, check.IsNil)
+	data, err := json.Marshal(result)
+	c.Assert(err, check.IsNil)
+	c.Assert(string(data), check.Equals, `[{"Units":[{"Status":"started"}],"name":"a"},{"Units":null,"details":{"ID":"u2","Status":"error"},"name":"b"}]`)
+}
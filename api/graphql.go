@@ -0,0 +1,473 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	stdContext "context"
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/tsuru/tsuru/app"
+	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/provision"
+	"github.com/tsuru/tsuru/provision/pool"
+	"github.com/tsuru/tsuru/service"
+	appTypes "github.com/tsuru/tsuru/types/app"
+)
+
+const (
+	graphQLMaxDepth       = 10
+	graphQLMaxParallelism = 10
+	graphQLDefaultDeploys = 10
+)
+
+const graphQLSchemaString = `
+schema {
+	query: Query
+}
+
+scalar Time
+
+type Query {
+	apps(name: String, platform: String, teamOwner: String, pool: String): [App!]!
+	app(name: String!): App
+	pools: [Pool!]!
+	services: [Service!]!
+	events(targetType: String, targetValue: String, kindName: String, running: Boolean, limit: Int): [Event!]!
+}
+
+type App {
+	name: String!
+	description: String!
+	platform: String!
+	teamOwner: String!
+	teams: [String!]!
+	pool: String!
+	plan: String!
+	cname: [String!]!
+	tags: [String!]!
+	units: [Unit!]!
+	serviceInstances: [ServiceInstance!]!
+	deploys(limit: Int): [Deploy!]!
+}
+
+type Unit {
+	id: String!
+	name: String!
+	processName: String!
+	type: String!
+	ip: String!
+	status: String!
+	statusReason: String!
+}
+
+type Deploy {
+	id: String!
+	timestamp: Time!
+	duration: Float!
+	commit: String!
+	image: String!
+	user: String!
+	origin: String!
+	error: String!
+}
+
+type Pool {
+	name: String!
+	provisioner: String!
+	default: Boolean!
+	teams: [String!]!
+}
+
+type Service {
+	name: String!
+	instances: [ServiceInstance!]!
+}
+
+type ServiceInstance {
+	name: String!
+	serviceName: String!
+	planName: String!
+	teamOwner: String!
+	teams: [String!]!
+	pool: String!
+	apps: [String!]!
+}
+
+type Event {
+	id: String!
+	targetType: String!
+	targetValue: String!
+	kind: String!
+	ownerType: String!
+	ownerName: String!
+	startTime: Time!
+	endTime: Time
+	running: Boolean!
+	error: String!
+}
+`
+
+var graphQLSchema = graphql.MustParseSchema(graphQLSchemaString, &graphQLResolver{},
+	graphql.MaxDepth(graphQLMaxDepth),
+	graphql.MaxParallelism(graphQLMaxParallelism),
+)
+
+type graphQLTokenKey struct{}
+
+func graphQLToken(ctx stdContext.Context) auth.Token {
+	t, _ := ctx.Value(graphQLTokenKey{}).(auth.Token)
+	return t
+}
+
+type graphQLRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// title: graphql query
+// path: /graphql
+// method: POST
+// consume: application/json
+// produce: application/json
+// responses:
+//   200: OK
+//   400: Invalid data
+//   401: Unauthorized
+func graphQLQuery(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	var req graphQLRequest
+	err := ParseJSON(r, &req)
+	if err != nil {
+		return err
+	}
+	if req.Query == "" {
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: "query is required"}
+	}
+	ctx := stdContext.WithValue(r.Context(), graphQLTokenKey{}, t)
+	response := graphQLSchema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(response)
+}
+
+// graphQLResolver resolves the read-only queries of the GraphQL API, the same
+// permissions of the equivalent REST endpoints are required.
+type graphQLResolver struct{}
+
+type graphQLAppsArgs struct {
+	Name      *string
+	Platform  *string
+	TeamOwner *string
+	Pool      *string
+}
+
+func (*graphQLResolver) Apps(ctx stdContext.Context, args graphQLAppsArgs) ([]*graphQLAppResolver, error) {
+	t := graphQLToken(ctx)
+	filter := &app.Filter{}
+	if args.Name != nil {
+		filter.NameMatches = *args.Name
+	}
+	if args.Platform != nil {
+		filter.Platform = *args.Platform
+	}
+	if args.TeamOwner != nil {
+		filter.TeamOwner = *args.TeamOwner
+	}
+	if args.Pool != nil {
+		filter.Pool = *args.Pool
+	}
+	contexts := permission.ContextsForPermission(t, permission.PermAppRead)
+	contexts = append(contexts, permission.ContextsForPermission(t, permission.PermAppReadInfo)...)
+	if len(contexts) == 0 {
+		return nil, nil
+	}
+	apps, err := app.List(ctx, appFilterByContext(contexts, filter))
+	if err != nil {
+		return nil, err
+	}
+	units := &graphQLAppsUnits{apps: apps}
+	resolvers := make([]*graphQLAppResolver, len(apps))
+	for i := range apps {
+		resolvers[i] = &graphQLAppResolver{app: &apps[i], units: units}
+	}
+	return resolvers, nil
+}
+
+func (*graphQLResolver) App(ctx stdContext.Context, args struct{ Name string }) (*graphQLAppResolver, error) {
+	a, err := app.GetByName(ctx, args.Name)
+	if err != nil {
+		if err == appTypes.ErrAppNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if !permission.Check(graphQLToken(ctx), permission.PermAppReadInfo, contextsForApp(a)...) {
+		return nil, permission.ErrUnauthorized
+	}
+	units := &graphQLAppsUnits{apps: []app.App{*a}}
+	return &graphQLAppResolver{app: a, units: units}, nil
+}
+
+func (*graphQLResolver) Pools(ctx stdContext.Context) ([]*graphQLPoolResolver, error) {
+	pools, err := readablePools(ctx, graphQLToken(ctx))
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*graphQLPoolResolver, len(pools))
+	for i := range pools {
+		resolvers[i] = &graphQLPoolResolver{pool: &pools[i]}
+	}
+	return resolvers, nil
+}
+
+func (*graphQLResolver) Services(ctx stdContext.Context) ([]*graphQLServiceResolver, error) {
+	t := graphQLToken(ctx)
+	contexts := permission.ContextsForPermission(t, permission.PermServiceRead)
+	services, err := readableServices(ctx, t, contexts)
+	if err != nil {
+		return nil, err
+	}
+	contexts = permission.ContextsForPermission(t, permission.PermServiceInstanceRead)
+	instances, err := readableInstances(t, contexts, "", "")
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*graphQLServiceResolver, len(services))
+	for i, s := range services {
+		resolvers[i] = &graphQLServiceResolver{name: s.Name}
+		for j := range instances {
+			if instances[j].ServiceName == s.Name {
+				resolvers[i].instances = append(resolvers[i].instances, &graphQLServiceInstanceResolver{instance: &instances[j]})
+			}
+		}
+	}
+	return resolvers, nil
+}
+
+type graphQLEventsArgs struct {
+	TargetType  *string
+	TargetValue *string
+	KindName    *string
+	Running     *bool
+	Limit       *int32
+}
+
+func (*graphQLResolver) Events(ctx stdContext.Context, args graphQLEventsArgs) ([]*graphQLEventResolver, error) {
+	filter := &event.Filter{Running: args.Running}
+	if args.TargetType != nil {
+		targetType, err := event.GetTargetType(*args.TargetType)
+		if err != nil {
+			return nil, err
+		}
+		filter.Target.Type = targetType
+	}
+	if args.TargetValue != nil {
+		filter.Target.Value = *args.TargetValue
+	}
+	if args.KindName != nil {
+		filter.KindNames = []string{*args.KindName}
+	}
+	if args.Limit != nil {
+		filter.Limit = int(*args.Limit)
+	}
+	filter.PruneUserValues()
+	var err error
+	filter.Permissions, err = graphQLToken(ctx).Permissions()
+	if err != nil {
+		return nil, err
+	}
+	events, err := event.List(filter)
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*graphQLEventResolver, len(events))
+	for i, evt := range events {
+		resolvers[i] = &graphQLEventResolver{event: evt}
+	}
+	return resolvers, nil
+}
+
+// graphQLAppsUnits loads the units of all listed apps at once, the first time
+// units of any of them are requested.
+type graphQLAppsUnits struct {
+	once  sync.Once
+	apps  []app.App
+	units map[string]app.AppUnitsResponse
+	err   error
+}
+
+func (u *graphQLAppsUnits) get(ctx stdContext.Context, appName string) ([]provision.Unit, error) {
+	u.once.Do(func() {
+		u.units, u.err = app.Units(ctx, u.apps)
+	})
+	if u.err != nil {
+		return nil, u.err
+	}
+	unitData := u.units[appName]
+	return unitData.Units, unitData.Err
+}
+
+type graphQLAppResolver struct {
+	app   *app.App
+	units *graphQLAppsUnits
+}
+
+func (r *graphQLAppResolver) Name() string        { return r.app.Name }
+func (r *graphQLAppResolver) Description() string { return r.app.Description }
+func (r *graphQLAppResolver) Platform() string    { return r.app.Platform }
+func (r *graphQLAppResolver) TeamOwner() string   { return r.app.TeamOwner }
+func (r *graphQLAppResolver) Teams() []string     { return nonNilStrings(r.app.Teams) }
+func (r *graphQLAppResolver) Pool() string        { return r.app.Pool }
+func (r *graphQLAppResolver) Plan() string        { return r.app.Plan.Name }
+func (r *graphQLAppResolver) Cname() []string     { return nonNilStrings(r.app.CName) }
+func (r *graphQLAppResolver) Tags() []string      { return nonNilStrings(r.app.Tags) }
+
+func (r *graphQLAppResolver) Units(ctx stdContext.Context) ([]*graphQLUnitResolver, error) {
+	units, err := r.units.get(ctx, r.app.Name)
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*graphQLUnitResolver, len(units))
+	for i := range units {
+		resolvers[i] = &graphQLUnitResolver{unit: &units[i]}
+	}
+	return resolvers, nil
+}
+
+func (r *graphQLAppResolver) ServiceInstances(ctx stdContext.Context) ([]*graphQLServiceInstanceResolver, error) {
+	t := graphQLToken(ctx)
+	contexts := permission.ContextsForPermission(t, permission.PermServiceInstanceRead)
+	instances, err := readableInstances(t, contexts, r.app.Name, "")
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*graphQLServiceInstanceResolver, len(instances))
+	for i := range instances {
+		resolvers[i] = &graphQLServiceInstanceResolver{instance: &instances[i]}
+	}
+	return resolvers, nil
+}
+
+func (r *graphQLAppResolver) Deploys(ctx stdContext.Context, args struct{ Limit *int32 }) ([]*graphQLDeployResolver, error) {
+	if !permission.Check(graphQLToken(ctx), permission.PermAppReadDeploy, contextsForApp(r.app)...) {
+		return nil, nil
+	}
+	limit := graphQLDefaultDeploys
+	if args.Limit != nil {
+		limit = int(*args.Limit)
+	}
+	deploys, err := app.ListDeploys(ctx, &app.Filter{Name: r.app.Name}, 0, limit)
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*graphQLDeployResolver, len(deploys))
+	for i := range deploys {
+		resolvers[i] = &graphQLDeployResolver{deploy: &deploys[i]}
+	}
+	return resolvers, nil
+}
+
+type graphQLUnitResolver struct {
+	unit *provision.Unit
+}
+
+func (r *graphQLUnitResolver) ID() string           { return r.unit.ID }
+func (r *graphQLUnitResolver) Name() string         { return r.unit.Name }
+func (r *graphQLUnitResolver) ProcessName() string  { return r.unit.ProcessName }
+func (r *graphQLUnitResolver) Type() string         { return r.unit.Type }
+func (r *graphQLUnitResolver) IP() string           { return r.unit.IP }
+func (r *graphQLUnitResolver) Status() string       { return r.unit.Status.String() }
+func (r *graphQLUnitResolver) StatusReason() string { return r.unit.StatusReason }
+
+type graphQLDeployResolver struct {
+	deploy *app.DeployData
+}
+
+func (r *graphQLDeployResolver) ID() string { return r.deploy.ID.Hex() }
+func (r *graphQLDeployResolver) Timestamp() graphql.Time {
+	return graphql.Time{Time: r.deploy.Timestamp}
+}
+func (r *graphQLDeployResolver) Duration() float64 { return r.deploy.Duration.Seconds() }
+func (r *graphQLDeployResolver) Commit() string    { return r.deploy.Commit }
+func (r *graphQLDeployResolver) Image() string     { return r.deploy.Image }
+func (r *graphQLDeployResolver) User() string      { return r.deploy.User }
+func (r *graphQLDeployResolver) Origin() string    { return r.deploy.Origin }
+func (r *graphQLDeployResolver) Error() string     { return r.deploy.Error }
+
+type graphQLPoolResolver struct {
+	pool *pool.Pool
+}
+
+func (r *graphQLPoolResolver) Name() string        { return r.pool.Name }
+func (r *graphQLPoolResolver) Provisioner() string { return r.pool.Provisioner }
+func (r *graphQLPoolResolver) Default() bool       { return r.pool.Default }
+
+func (r *graphQLPoolResolver) Teams() ([]string, error) {
+	teams, err := r.pool.GetTeams()
+	if err != nil && err != pool.ErrPoolHasNoTeam {
+		return nil, err
+	}
+	return nonNilStrings(teams), nil
+}
+
+type graphQLServiceResolver struct {
+	name      string
+	instances []*graphQLServiceInstanceResolver
+}
+
+func (r *graphQLServiceResolver) Name() string { return r.name }
+
+func (r *graphQLServiceResolver) Instances() []*graphQLServiceInstanceResolver {
+	return r.instances
+}
+
+type graphQLServiceInstanceResolver struct {
+	instance *service.ServiceInstance
+}
+
+func (r *graphQLServiceInstanceResolver) Name() string        { return r.instance.Name }
+func (r *graphQLServiceInstanceResolver) ServiceName() string { return r.instance.ServiceName }
+func (r *graphQLServiceInstanceResolver) PlanName() string    { return r.instance.PlanName }
+func (r *graphQLServiceInstanceResolver) TeamOwner() string   { return r.instance.TeamOwner }
+func (r *graphQLServiceInstanceResolver) Teams() []string     { return nonNilStrings(r.instance.Teams) }
+func (r *graphQLServiceInstanceResolver) Pool() string        { return r.instance.Pool }
+func (r *graphQLServiceInstanceResolver) Apps() []string      { return nonNilStrings(r.instance.Apps) }
+
+type graphQLEventResolver struct {
+	event *event.Event
+}
+
+func (r *graphQLEventResolver) ID() string          { return r.event.UniqueID.Hex() }
+func (r *graphQLEventResolver) TargetType() string  { return string(r.event.Target.Type) }
+func (r *graphQLEventResolver) TargetValue() string { return r.event.Target.Value }
+func (r *graphQLEventResolver) Kind() string        { return r.event.Kind.Name }
+func (r *graphQLEventResolver) OwnerType() string   { return string(r.event.Owner.Type) }
+func (r *graphQLEventResolver) OwnerName() string   { return r.event.Owner.Name }
+func (r *graphQLEventResolver) Running() bool       { return r.event.Running }
+func (r *graphQLEventResolver) Error() string       { return r.event.Error }
+
+func (r *graphQLEventResolver) StartTime() graphql.Time {
+	return graphql.Time{Time: r.event.StartTime}
+}
+
+func (r *graphQLEventResolver) EndTime() *graphql.Time {
+	if r.event.EndTime.IsZero() {
+		return nil
+	}
+	return &graphql.Time{Time: r.event.EndTime}
+}
+
+// nonNilStrings avoids null values in non-null GraphQL lists.
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
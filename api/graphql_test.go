@@ -0,0 +1,132 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/tsuru/tsuru/app"
+	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/provision/pool"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+	check "gopkg.in/check.v1"
+)
+
+type graphQLTestResponse struct {
+	Data   json.RawMessage
+	Errors []struct {
+		Message string
+	}
+}
+
+func (s *S) doGraphQLQuery(c *check.C, token auth.Token, query string) (*httptest.ResponseRecorder, graphQLTestResponse) {
+	body, err := json.Marshal(map[string]string{"query": query})
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest("POST", "/1.13/graphql", strings.NewReader(string(body)))
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Authorization", "b "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	var response graphQLTestResponse
+	if recorder.Code == http.StatusOK {
+		err = json.Unmarshal(recorder.Body.Bytes(), &response)
+		c.Assert(err, check.IsNil)
+	}
+	return recorder, response
+}
+
+func (s *S) TestGraphQLQueryApps(c *check.C) {
+	app1 := app.App{Name: "app1", Platform: "zend", TeamOwner: s.team.Name, Tags: []string{"a"}}
+	err := app.CreateApp(context.TODO(), &app1, s.user)
+	c.Assert(err, check.IsNil)
+	app2 := app.App{Name: "app2", Platform: "python", TeamOwner: s.team.Name}
+	err = app.CreateApp(context.TODO(), &app2, s.user)
+	c.Assert(err, check.IsNil)
+	recorder, response := s.doGraphQLQuery(c, s.token, `{ apps(platform: "zend") { name platform teamOwner tags units { name } serviceInstances { name } } }`)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, "application/json")
+	c.Assert(response.Errors, check.HasLen, 0)
+	c.Assert(string(response.Data), check.Equals, `{"apps":[{"name":"app1","platform":"zend","teamOwner":"tsuruteam","tags":["a"],"units":[],"serviceInstances":[]}]}`)
+}
+
+func (s *S) TestGraphQLQueryAppsWithoutPermission(c *check.C) {
+	app1 := app.App{Name: "app1", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &app1, s.user)
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c)
+	recorder, response := s.doGraphQLQuery(c, token, `{ apps { name } }`)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(response.Errors, check.HasLen, 0)
+	c.Assert(string(response.Data), check.Equals, `{"apps":[]}`)
+}
+
+func (s *S) TestGraphQLQueryApp(c *check.C) {
+	app1 := app.App{Name: "app1", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &app1, s.user)
+	c.Assert(err, check.IsNil)
+	recorder, response := s.doGraphQLQuery(c, s.token, `{ app(name: "app1") { name pool deploys(limit: 1) { image } } }`)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(response.Errors, check.HasLen, 0)
+	c.Assert(string(response.Data), check.Equals, `{"app":{"name":"app1","pool":"test1","deploys":[]}}`)
+}
+
+func (s *S) TestGraphQLQueryAppNotFound(c *check.C) {
+	recorder, response := s.doGraphQLQuery(c, s.token, `{ app(name: "unknown") { name } }`)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(response.Errors, check.HasLen, 0)
+	c.Assert(string(response.Data), check.Equals, `{"app":null}`)
+}
+
+func (s *S) TestGraphQLQueryAppForbidden(c *check.C) {
+	app1 := app.App{Name: "app1", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &app1, s.user)
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermAppReadInfo,
+		Context: permission.Context(permTypes.CtxApp, "otherapp"),
+	})
+	recorder, response := s.doGraphQLQuery(c, token, `{ app(name: "app1") { name } }`)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(response.Errors, check.HasLen, 1)
+	c.Assert(response.Errors[0].Message, check.Equals, permission.ErrUnauthorized.Error())
+}
+
+func (s *S) TestGraphQLQueryPools(c *check.C) {
+	err := pool.AddPool(context.TODO(), pool.AddPoolOptions{Name: "pool1", Public: true})
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c)
+	recorder, response := s.doGraphQLQuery(c, token, `{ pools { name default } }`)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(response.Errors, check.HasLen, 0)
+	c.Assert(string(response.Data), check.Equals, `{"pools":[{"name":"test1","default":true},{"name":"pool1","default":false}]}`)
+}
+
+func (s *S) TestGraphQLQueryMutationNotAllowed(c *check.C) {
+	recorder, response := s.doGraphQLQuery(c, s.token, `mutation { removeApp(name: "app1") }`)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(response.Errors, check.HasLen, 1)
+	c.Assert(response.Errors[0].Message, check.Equals, "no mutations are offered by the schema")
+}
+
+func (s *S) TestGraphQLQueryEmptyQuery(c *check.C) {
+	recorder, _ := s.doGraphQLQuery(c, s.token, "")
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(recorder.Body.String(), check.Equals, "query is required\n")
+}
+
+func (s *S) TestGraphQLQueryRequiresAuthentication(c *check.C) {
+	request, err := http.NewRequest("POST", "/1.13/graphql", strings.NewReader(`{"query": "{ pools { name } }"}`))
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusUnauthorized)
+}
@@ -15,8 +15,9 @@ import (
 
 var (
 	tokenRequiredErr = &errors.HTTP{
-		Code:    http.StatusUnauthorized,
-		Message: "You must provide a valid Authorization header",
+		Code:      http.StatusUnauthorized,
+		Message:   "You must provide a valid Authorization header",
+		ErrorCode: "auth.token-required",
 	}
 )
 
@@ -7,12 +7,29 @@ package api
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/tsuru/tsuru/hc"
 )
 
+// readiness is the JSON representation of the healthcheck, with the status
+// of each dependency and an overall verdict.
+type readiness struct {
+	Ready  bool              `json:"ready"`
+	Checks []readinessResult `json:"checks"`
+}
+
+type readinessResult struct {
+	Name      string  `json:"name"`
+	OK        bool    `json:"ok"`
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latencyMs"`
+}
+
 // title: healthcheck
 // path: /healthcheck
 // method: GET
@@ -25,6 +42,10 @@ func healthcheck(w http.ResponseWriter, r *http.Request) {
 	if values != nil {
 		checks = values["check"]
 	}
+	if values.Get("format") == "json" {
+		jsonHealthcheck(r.Context(), w, checks)
+		return
+	}
 	fullHealthcheck(r.Context(), w, checks)
 }
 
@@ -45,3 +66,25 @@ func fullHealthcheck(ctx context.Context, w http.ResponseWriter, checks []string
 	w.WriteHeader(status)
 	w.Write(buf.Bytes())
 }
+
+func jsonHealthcheck(ctx context.Context, w http.ResponseWriter, checks []string) {
+	results := hc.Check(ctx, checks...)
+	data := readiness{Ready: true, Checks: make([]readinessResult, len(results))}
+	for i, result := range results {
+		ok := result.Status == hc.HealthCheckOK
+		data.Checks[i] = readinessResult{
+			Name:      result.Name,
+			OK:        ok,
+			Status:    strings.TrimPrefix(result.Status, "fail - "),
+			LatencyMs: float64(result.Duration) / float64(time.Millisecond),
+		}
+		if !ok {
+			data.Ready = false
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if !data.Ready {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	json.NewEncoder(w).Encode(data)
+}
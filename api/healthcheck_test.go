@@ -6,6 +6,8 @@ package api
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 
@@ -70,3 +72,56 @@ func (s *HealthCheckSuite) TestHealthCheckWithChecksInvalidChecker(c *check.C) {
 	c.Assert(recorder.Code, check.Equals, http.StatusOK)
 	c.Assert(recorder.Body.String(), check.Equals, "WORKING")
 }
+
+func (s *HealthCheckSuite) TestHealthCheckJSON(c *check.C) {
+	hc.AddChecker("json-ok", func(context.Context) error {
+		return nil
+	})
+	failing := true
+	defer func() { failing = false }()
+	hc.AddChecker("json-fail", func(context.Context) error {
+		if failing {
+			return errors.New("connection refused")
+		}
+		return nil
+	})
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest("GET", "/healthcheck?format=json&check=json-ok", nil)
+	c.Assert(err, check.IsNil)
+	healthcheck(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, "application/json")
+	var result readiness
+	err = json.Unmarshal(recorder.Body.Bytes(), &result)
+	c.Assert(err, check.IsNil)
+	c.Assert(result.Ready, check.Equals, true)
+	c.Assert(result.Checks, check.HasLen, 1)
+	c.Assert(result.Checks[0].Name, check.Equals, "json-ok")
+	c.Assert(result.Checks[0].OK, check.Equals, true)
+	c.Assert(result.Checks[0].Status, check.Equals, hc.HealthCheckOK)
+	c.Assert(result.Checks[0].LatencyMs >= 0, check.Equals, true)
+	recorder = httptest.NewRecorder()
+	request, err = http.NewRequest("GET", "/healthcheck?format=json&check=json-ok&check=json-fail", nil)
+	c.Assert(err, check.IsNil)
+	healthcheck(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusInternalServerError)
+	err = json.Unmarshal(recorder.Body.Bytes(), &result)
+	c.Assert(err, check.IsNil)
+	c.Assert(result.Ready, check.Equals, false)
+	c.Assert(result.Checks, check.HasLen, 2)
+	c.Assert(result.Checks[1], check.DeepEquals, readinessResult{
+		Name:      "json-fail",
+		OK:        false,
+		Status:    "connection refused",
+		LatencyMs: result.Checks[1].LatencyMs,
+	})
+}
+
+func (s *HealthCheckSuite) TestHealthCheckJSONWithoutChecks(c *check.C) {
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest("GET", "/healthcheck?format=json", nil)
+	c.Assert(err, check.IsNil)
+	healthcheck(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(recorder.Body.String(), check.Equals, `{"ready":true,"checks":[]}`+"\n")
+}
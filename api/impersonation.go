@@ -0,0 +1,112 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/permission"
+	authTypes "github.com/tsuru/tsuru/types/auth"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+)
+
+// errImpersonated is returned by handlers managing the credentials of the
+// user, which can't be used while impersonating it.
+var errImpersonated = &errors.HTTP{
+	Code:      http.StatusForbidden,
+	Message:   "This operation is not allowed while impersonating users.",
+	ErrorCode: "auth.impersonated",
+}
+
+// title: impersonate user
+// path: /users/{email}/impersonate
+// method: POST
+// consume: application/x-www-form-urlencoded
+// produce: application/json
+// responses:
+//   201: Impersonation token created
+//   400: Invalid data
+//   401: Unauthorized
+//   403: Forbidden
+//   404: User not found
+func impersonateUser(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	if auth.IsImpersonated(t) {
+		return errImpersonated
+	}
+	if !permission.Check(t, permission.PermUserImpersonate) {
+		return permission.ErrUnauthorized
+	}
+	u, err := auth.GetUserByEmail(r.URL.Query().Get(":email"))
+	if err != nil {
+		if err == authTypes.ErrUserNotFound {
+			return &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+		}
+		return err
+	}
+	err = canImpersonate(t, u)
+	if err != nil {
+		return err
+	}
+	var duration time.Duration
+	if raw := InputValue(r, "duration"); raw != "" {
+		duration, err = time.ParseDuration(raw)
+		if err != nil {
+			return &errors.HTTP{Code: http.StatusBadRequest, Message: "duration must be a duration like 15m"}
+		}
+	}
+	evt, err := event.New(&event.Opts{
+		Target:     userTarget(u.Email),
+		Kind:       permission.PermUserImpersonate,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		CustomData: event.FormToCustomData(InputFields(r)),
+		Allowed:    event.Allowed(permission.PermUserReadEvents, permission.Context(permTypes.CtxUser, u.Email)),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	token, err := auth.CreateImpersonationToken(u, t.GetUserName(), InputValue(r, "reason"), duration)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	return json.NewEncoder(w).Encode(token)
+}
+
+// canImpersonate checks that the token holds every permission of the user,
+// so impersonating it can't be used to escalate privileges.
+func canImpersonate(t auth.Token, u *auth.User) error {
+	perms, err := u.Permissions()
+	if err != nil {
+		return err
+	}
+	for _, perm := range perms {
+		if !permission.Check(t, perm.Scheme, perm.Context) {
+			return permission.ErrUnauthorized
+		}
+	}
+	return nil
+}
+
+// title: end impersonation
+// path: /users/impersonate
+// method: DELETE
+// responses:
+//   200: Impersonation ended
+//   400: Not an impersonation token
+//   401: Unauthorized
+func endImpersonation(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	if !auth.IsImpersonated(t) {
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: "The token is not an impersonation token."}
+	}
+	return auth.RevokeImpersonationToken(t.GetValue())
+}
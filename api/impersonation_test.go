@@ -0,0 +1,203 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/event/eventtest"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/permission/permissiontest"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+	check "gopkg.in/check.v1"
+)
+
+func (s *AuthSuite) impersonateRequest(c *check.C, token, email, body string) *httptest.ResponseRecorder {
+	request, err := http.NewRequest(http.MethodPost, "/1.13/users/"+email+"/impersonate", strings.NewReader(body))
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+token)
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func (s *AuthSuite) impersonate(c *check.C, email string) auth.ImpersonationToken {
+	recorder := s.impersonateRequest(c, s.token.GetValue(), email, "reason=debugging+permissions&duration=10m")
+	c.Assert(recorder.Code, check.Equals, http.StatusCreated)
+	var token auth.ImpersonationToken
+	err := json.NewDecoder(recorder.Body).Decode(&token)
+	c.Assert(err, check.IsNil)
+	return token
+}
+
+func (s *AuthSuite) TestImpersonateUser(c *check.C) {
+	u := auth.User{Email: "nobody@globo.com", Password: "123456"}
+	_, err := nativeScheme.Create(context.TODO(), &u)
+	c.Assert(err, check.IsNil)
+	token := s.impersonate(c, u.Email)
+	c.Assert(token.UserEmail, check.Equals, u.Email)
+	c.Assert(token.Impersonator, check.Equals, s.user.Email)
+	c.Assert(token.Reason, check.Equals, "debugging permissions")
+	c.Assert(eventtest.EventDesc{
+		Target: userTarget(u.Email),
+		Owner:  s.token.GetUserName(),
+		Kind:   "user.impersonate",
+		StartCustomData: []map[string]interface{}{
+			{"name": "reason", "value": "debugging permissions"},
+			{"name": "duration", "value": "10m"},
+		},
+	}, eventtest.HasEvent)
+	request, err := http.NewRequest(http.MethodGet, "/users/info", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.Token)
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	var info apiUser
+	err = json.NewDecoder(recorder.Body).Decode(&info)
+	c.Assert(err, check.IsNil)
+	c.Assert(info.Email, check.Equals, u.Email)
+}
+
+func (s *AuthSuite) TestImpersonateUserRequiresReason(c *check.C) {
+	u := auth.User{Email: "nobody@globo.com", Password: "123456"}
+	_, err := nativeScheme.Create(context.TODO(), &u)
+	c.Assert(err, check.IsNil)
+	recorder := s.impersonateRequest(c, s.token.GetValue(), u.Email, "")
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(recorder.Body.String(), check.Equals, "a reason is required to impersonate users\n")
+}
+
+func (s *AuthSuite) TestImpersonateUserInvalidDuration(c *check.C) {
+	u := auth.User{Email: "nobody@globo.com", Password: "123456"}
+	_, err := nativeScheme.Create(context.TODO(), &u)
+	c.Assert(err, check.IsNil)
+	recorder := s.impersonateRequest(c, s.token.GetValue(), u.Email, "reason=debugging&duration=forever")
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(recorder.Body.String(), check.Equals, "duration must be a duration like 15m\n")
+}
+
+func (s *AuthSuite) TestImpersonateUserNotFound(c *check.C) {
+	recorder := s.impersonateRequest(c, s.token.GetValue(), "unknown@globo.com", "reason=debugging")
+	c.Assert(recorder.Code, check.Equals, http.StatusNotFound)
+}
+
+func (s *AuthSuite) TestImpersonateUserUnauthorized(c *check.C) {
+	_, token := permissiontest.CustomUserWithPermission(c, nativeScheme, "impersonator", permission.Permission{
+		Scheme:  permission.PermUser,
+		Context: permission.Context(permTypes.CtxUser, "nobody@globo.com"),
+	})
+	u := auth.User{Email: "nobody@globo.com", Password: "123456"}
+	_, err := nativeScheme.Create(context.TODO(), &u)
+	c.Assert(err, check.IsNil)
+	recorder := s.impersonateRequest(c, token.GetValue(), u.Email, "reason=debugging")
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
+
+func (s *AuthSuite) TestImpersonateUserWithMorePermissions(c *check.C) {
+	_, token := permissiontest.CustomUserWithPermission(c, nativeScheme, "impersonator", permission.Permission{
+		Scheme:  permission.PermUserImpersonate,
+		Context: permission.Context(permTypes.CtxGlobal, ""),
+	}, permission.Permission{
+		Scheme:  permission.PermUser,
+		Context: permission.Context(permTypes.CtxUser, "nobody@globo.com"),
+	})
+	u := auth.User{Email: "nobody@globo.com", Password: "123456"}
+	_, err := nativeScheme.Create(context.TODO(), &u)
+	c.Assert(err, check.IsNil)
+	recorder := s.impersonateRequest(c, token.GetValue(), u.Email, "reason=debugging")
+	c.Assert(recorder.Code, check.Equals, http.StatusCreated)
+	role, err := permission.NewRole("deployer", "team", "")
+	c.Assert(err, check.IsNil)
+	err = role.AddPermissions("app.deploy")
+	c.Assert(err, check.IsNil)
+	err = u.AddRole(role.Name, s.team.Name)
+	c.Assert(err, check.IsNil)
+	recorder = s.impersonateRequest(c, token.GetValue(), u.Email, "reason=debugging")
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
+
+func (s *AuthSuite) TestImpersonateUserWhileImpersonating(c *check.C) {
+	u := auth.User{Email: "nobody@globo.com", Password: "123456"}
+	_, err := nativeScheme.Create(context.TODO(), &u)
+	c.Assert(err, check.IsNil)
+	token := s.impersonate(c, u.Email)
+	recorder := s.impersonateRequest(c, token.Token, s.user.Email, "reason=debugging")
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+	c.Assert(recorder.Header().Get("X-Tsuru-Error-Code"), check.Equals, "auth.impersonated")
+}
+
+func (s *AuthSuite) TestImpersonatedTokenCantChangePassword(c *check.C) {
+	u := auth.User{Email: "nobody@globo.com", Password: "123456"}
+	_, err := nativeScheme.Create(context.TODO(), &u)
+	c.Assert(err, check.IsNil)
+	token := s.impersonate(c, u.Email)
+	body := strings.NewReader("old=123456&new=654321&confirm=654321")
+	request, err := http.NewRequest(http.MethodPut, "/users/password", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+token.Token)
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+	c.Assert(recorder.Header().Get("X-Tsuru-Error-Code"), check.Equals, "auth.impersonated")
+}
+
+func (s *AuthSuite) TestImpersonatedTokenCantManageCredentials(c *check.C) {
+	u := auth.User{Email: "nobody@globo.com", Password: "123456"}
+	_, err := nativeScheme.Create(context.TODO(), &u)
+	c.Assert(err, check.IsNil)
+	token := s.impersonate(c, u.Email)
+	tests := []struct {
+		method string
+		path   string
+		body   string
+	}{
+		{method: http.MethodPost, path: "/1.6/tokens", body: "team=" + s.team.Name},
+		{method: http.MethodPut, path: "/1.6/tokens/mytoken", body: "regenerate=true"},
+		{method: http.MethodPost, path: "/1.13/tokens/mytoken/rotate", body: ""},
+		{method: http.MethodPost, path: "/1.13/service-accounts", body: "name=mysa&team=" + s.team.Name},
+	}
+	for _, tt := range tests {
+		request, err := http.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+		c.Assert(err, check.IsNil)
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		request.Header.Set("Authorization", "bearer "+token.Token)
+		recorder := httptest.NewRecorder()
+		s.testServer.ServeHTTP(recorder, request)
+		c.Assert(recorder.Code, check.Equals, http.StatusForbidden, check.Commentf("path %s", tt.path))
+		c.Assert(recorder.Header().Get("X-Tsuru-Error-Code"), check.Equals, "auth.impersonated", check.Commentf("path %s", tt.path))
+	}
+}
+
+func (s *AuthSuite) TestEndImpersonation(c *check.C) {
+	u := auth.User{Email: "nobody@globo.com", Password: "123456"}
+	_, err := nativeScheme.Create(context.TODO(), &u)
+	c.Assert(err, check.IsNil)
+	token := s.impersonate(c, u.Email)
+	request, err := http.NewRequest(http.MethodDelete, "/1.13/users/impersonate", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.Token)
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	_, err = auth.ImpersonationAuth("bearer " + token.Token)
+	c.Assert(err, check.Equals, auth.ErrInvalidToken)
+}
+
+func (s *AuthSuite) TestEndImpersonationNotImpersonating(c *check.C) {
+	request, err := http.NewRequest(http.MethodDelete, "/1.13/users/impersonate", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+}
@@ -0,0 +1,296 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/servicemanager"
+	appTypes "github.com/tsuru/tsuru/types/app"
+	jobTypes "github.com/tsuru/tsuru/types/job"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+	"github.com/tsuru/tsuru/types/quota"
+)
+
+type inputJob struct {
+	Name              string
+	TeamOwner         string
+	Pool              string
+	Plan              string
+	Image             string
+	Command           []string
+	Envs              map[string]string
+	Schedule          string
+	ConcurrencyPolicy jobTypes.ConcurrencyPolicy
+	Retry             jobTypes.RetryPolicy
+}
+
+func jobFilterByContext(contexts []permTypes.PermissionContext) *jobTypes.Filter {
+	filter := &jobTypes.Filter{}
+contextsLoop:
+	for _, c := range contexts {
+		switch c.CtxType {
+		case permTypes.CtxGlobal:
+			filter = nil
+			break contextsLoop
+		case permTypes.CtxTeam:
+			filter.Teams = append(filter.Teams, c.Value)
+		case permTypes.CtxPool:
+			filter.Pools = append(filter.Pools, c.Value)
+		}
+	}
+	return filter
+}
+
+func contextsForJob(job *jobTypes.Job) []permTypes.PermissionContext {
+	return []permTypes.PermissionContext{
+		permission.Context(permTypes.CtxTeam, job.TeamOwner),
+		permission.Context(permTypes.CtxPool, job.Pool),
+	}
+}
+
+// startJobRun runs the job in background, the outcome of each attempt is
+// recorded in the returned event, which is finished once the run finishes.
+func startJobRun(r *http.Request, t auth.Token, job *jobTypes.Job) (*event.Event, error) {
+	evt, err := event.New(&event.Opts{
+		Target:     event.Target{Type: event.TargetTypeJob, Value: job.Name},
+		Kind:       permission.PermJobRun,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		CustomData: event.FormToCustomData(InputFields(r)),
+		Allowed:    event.Allowed(permission.PermJobReadEvents, contextsForJob(job)...),
+	})
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		evt.Done(servicemanager.Job.Run(context.Background(), job, evt))
+	}()
+	return evt, nil
+}
+
+func getJob(r *http.Request) (*jobTypes.Job, error) {
+	job, err := servicemanager.Job.Get(r.Context(), r.URL.Query().Get(":name"))
+	if err == jobTypes.ErrJobNotFound {
+		return nil, &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+	}
+	return job, err
+}
+
+// title: job list
+// path: /jobs
+// method: GET
+// produce: application/json
+// responses:
+//   200: List jobs
+//   204: No content
+//   401: Unauthorized
+func jobList(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	contexts := permission.ContextsForPermission(t, permission.PermJobRead)
+	if len(contexts) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+	jobs, err := servicemanager.Job.List(r.Context(), jobFilterByContext(contexts))
+	if err != nil {
+		return err
+	}
+	if len(jobs) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(jobs)
+}
+
+// title: job info
+// path: /jobs/{name}
+// method: GET
+// produce: application/json
+// responses:
+//   200: Show job
+//   401: Unauthorized
+//   404: Job not found
+func jobInfo(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	job, err := getJob(r)
+	if err != nil {
+		return err
+	}
+	if !permission.Check(t, permission.PermJobRead, contextsForJob(job)...) {
+		return permission.ErrUnauthorized
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(job)
+}
+
+// title: job create
+// path: /jobs
+// method: POST
+// consume: application/x-www-form-urlencoded
+// produce: application/json
+// responses:
+//   201: Job created, one-off jobs start running right away
+//   400: Invalid data
+//   401: Unauthorized
+//   409: Job already exists
+func jobCreate(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	ctx := r.Context()
+	var ij inputJob
+	err = ParseInput(r, &ij)
+	if err != nil {
+		return err
+	}
+	job := jobTypes.Job{
+		Name:              ij.Name,
+		TeamOwner:         ij.TeamOwner,
+		Pool:              ij.Pool,
+		Plan:              appTypes.Plan{Name: ij.Plan},
+		Owner:             t.GetUserName(),
+		Image:             ij.Image,
+		Command:           ij.Command,
+		Envs:              ij.Envs,
+		Schedule:          ij.Schedule,
+		ConcurrencyPolicy: ij.ConcurrencyPolicy,
+		Retry:             ij.Retry,
+	}
+	if job.TeamOwner == "" {
+		job.TeamOwner, err = autoTeamOwner(ctx, t, permission.PermJobCreate)
+		if err != nil {
+			return err
+		}
+	}
+	canCreate := permission.Check(t, permission.PermJobCreate,
+		permission.Context(permTypes.CtxTeam, job.TeamOwner),
+		permission.Context(permTypes.CtxPool, job.Pool),
+	)
+	if !canCreate {
+		return permission.ErrUnauthorized
+	}
+	evt, err := event.New(&event.Opts{
+		Target:     event.Target{Type: event.TargetTypeJob, Value: job.Name},
+		Kind:       permission.PermJobCreate,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		CustomData: event.FormToCustomData(InputFields(r)),
+		Allowed:    event.Allowed(permission.PermJobReadEvents, contextsForJob(&job)...),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	err = servicemanager.Job.Create(ctx, &job)
+	if err == jobTypes.ErrJobAlreadyExists {
+		return &errors.HTTP{Code: http.StatusConflict, Message: err.Error(), ErrorCode: "job.already-exists"}
+	}
+	if err != nil {
+		return err
+	}
+	if !job.IsCron() {
+		_, err = startJobRun(r, t, &job)
+		if err != nil {
+			return err
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	return json.NewEncoder(w).Encode(job)
+}
+
+// title: job run
+// path: /jobs/{name}/run
+// method: POST
+// produce: application/json
+// responses:
+//   202: Job started
+//   401: Unauthorized
+//   404: Job not found
+func jobRun(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	job, err := getJob(r)
+	if err != nil {
+		return err
+	}
+	if !permission.Check(t, permission.PermJobRun, contextsForJob(job)...) {
+		return permission.ErrUnauthorized
+	}
+	evt, err := startJobRun(r, t, job)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	return json.NewEncoder(w).Encode(map[string]string{"id": evt.UniqueID.Hex()})
+}
+
+// title: job trigger
+// path: /jobs/{name}/trigger
+// method: POST
+// responses:
+//   200: Job triggered
+//   400: Job is not a cronjob
+//   401: Unauthorized
+//   403: Quota exceeded
+//   404: Job not found
+func jobTrigger(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	job, err := getJob(r)
+	if err != nil {
+		return err
+	}
+	if !permission.Check(t, permission.PermJobTrigger, contextsForJob(job)...) {
+		return permission.ErrUnauthorized
+	}
+	evt, err := event.New(&event.Opts{
+		Target:     event.Target{Type: event.TargetTypeJob, Value: job.Name},
+		Kind:       permission.PermJobTrigger,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		CustomData: event.FormToCustomData(InputFields(r)),
+		Allowed:    event.Allowed(permission.PermJobReadEvents, contextsForJob(job)...),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	err = servicemanager.Job.Trigger(r.Context(), job)
+	if _, ok := err.(*quota.QuotaExceededError); ok {
+		return &errors.HTTP{Code: http.StatusForbidden, Message: err.Error(), ErrorCode: "app.quota.exceeded"}
+	}
+	return err
+}
+
+// title: job delete
+// path: /jobs/{name}
+// method: DELETE
+// responses:
+//   200: Job removed
+//   401: Unauthorized
+//   404: Job not found
+func jobDelete(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	job, err := getJob(r)
+	if err != nil {
+		return err
+	}
+	if !permission.Check(t, permission.PermJobDelete, contextsForJob(job)...) {
+		return permission.ErrUnauthorized
+	}
+	evt, err := event.New(&event.Opts{
+		Target:     event.Target{Type: event.TargetTypeJob, Value: job.Name},
+		Kind:       permission.PermJobDelete,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		CustomData: event.FormToCustomData(InputFields(r)),
+		Allowed:    event.Allowed(permission.PermJobReadEvents, contextsForJob(job)...),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	return servicemanager.Job.Remove(r.Context(), job)
+}
@@ -0,0 +1,356 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/ajg/form"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/event/eventtest"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/servicemanager"
+	jobTypes "github.com/tsuru/tsuru/types/job"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+	"github.com/tsuru/tsuru/types/quota"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) createTestJob(c *check.C, job jobTypes.Job) *jobTypes.Job {
+	if job.TeamOwner == "" {
+		job.TeamOwner = s.team.Name
+	}
+	if job.Image == "" {
+		job.Image = "busybox"
+	}
+	err := servicemanager.Job.Create(context.TODO(), &job)
+	c.Assert(err, check.IsNil)
+	return &job
+}
+
+func waitJobRunFinished(c *check.C, name string) *event.Event {
+	timeout := time.After(5 * time.Second)
+	for {
+		evts, err := event.List(&event.Filter{
+			Target:    event.Target{Type: event.TargetTypeJob, Value: name},
+			KindNames: []string{permission.PermJobRun.FullName()},
+		})
+		c.Assert(err, check.IsNil)
+		if len(evts) > 0 && !evts[0].Running {
+			return evts[0]
+		}
+		select {
+		case <-timeout:
+			c.Fatalf("timeout waiting for job %q run to finish", name)
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+}
+
+func (s *S) jobCreateRequest(c *check.C, ij inputJob, token string) *httptest.ResponseRecorder {
+	v, err := form.EncodeToValues(&ij)
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest("POST", "/1.13/jobs", strings.NewReader(v.Encode()))
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+token)
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func (s *S) TestJobCreate(c *check.C) {
+	ij := inputJob{
+		Name:      "myjob",
+		TeamOwner: s.team.Name,
+		Image:     "busybox",
+		Command:   []string{"echo", "hello"},
+		Envs:      map[string]string{"A": "1"},
+	}
+	recorder := s.jobCreateRequest(c, ij, s.token.GetValue())
+	c.Assert(recorder.Code, check.Equals, http.StatusCreated, check.Commentf("body: %s", recorder.Body.String()))
+	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, "application/json")
+	var created jobTypes.Job
+	err := json.Unmarshal(recorder.Body.Bytes(), &created)
+	c.Assert(err, check.IsNil)
+	c.Assert(created.Name, check.Equals, "myjob")
+	c.Assert(created.Pool, check.Equals, s.Pool)
+	c.Assert(created.Plan.Name, check.Equals, s.defaultPlan.Name)
+	c.Assert(created.Owner, check.Equals, s.token.GetUserName())
+	job, err := servicemanager.Job.Get(context.TODO(), "myjob")
+	c.Assert(err, check.IsNil)
+	c.Assert(job.Command, check.DeepEquals, []string{"echo", "hello"})
+	c.Assert(job.Envs, check.DeepEquals, map[string]string{"A": "1"})
+	waitJobRunFinished(c, "myjob")
+	c.Assert(s.provisioner.JobRuns("myjob"), check.Equals, 1)
+	c.Assert(eventtest.EventDesc{
+		Target: event.Target{Type: event.TargetTypeJob, Value: "myjob"},
+		Owner:  s.token.GetUserName(),
+		Kind:   "job.create",
+		StartCustomData: []map[string]interface{}{
+			{"name": "Name", "value": "myjob"},
+			{"name": "Image", "value": "busybox"},
+		},
+	}, eventtest.HasEvent)
+}
+
+func (s *S) TestJobCreateCron(c *check.C) {
+	ij := inputJob{
+		Name:              "mycron",
+		TeamOwner:         s.team.Name,
+		Image:             "busybox",
+		Schedule:          "*/5 * * * *",
+		ConcurrencyPolicy: jobTypes.ConcurrencyAllow,
+	}
+	recorder := s.jobCreateRequest(c, ij, s.token.GetValue())
+	c.Assert(recorder.Code, check.Equals, http.StatusCreated, check.Commentf("body: %s", recorder.Body.String()))
+	schedule, ok := s.provisioner.CronJob("mycron")
+	c.Assert(ok, check.Equals, true)
+	c.Assert(schedule, check.Equals, "*/5 * * * *")
+	job, err := servicemanager.Job.Get(context.TODO(), "mycron")
+	c.Assert(err, check.IsNil)
+	c.Assert(job.ConcurrencyPolicy, check.Equals, jobTypes.ConcurrencyAllow)
+	c.Assert(s.provisioner.JobRuns("mycron"), check.Equals, 0)
+}
+
+func (s *S) TestJobCreateWithRetryPolicy(c *check.C) {
+	s.provisioner.PrepareFailure("RunJob", errors.New("exit status 1"))
+	ij := inputJob{
+		Name:      "myjob",
+		TeamOwner: s.team.Name,
+		Image:     "busybox",
+		Retry:     jobTypes.RetryPolicy{Limit: 2},
+	}
+	recorder := s.jobCreateRequest(c, ij, s.token.GetValue())
+	c.Assert(recorder.Code, check.Equals, http.StatusCreated, check.Commentf("body: %s", recorder.Body.String()))
+	job, err := servicemanager.Job.Get(context.TODO(), "myjob")
+	c.Assert(err, check.IsNil)
+	c.Assert(job.Retry, check.DeepEquals, jobTypes.RetryPolicy{Limit: 2})
+	evt := waitJobRunFinished(c, "myjob")
+	c.Assert(evt.Error, check.Equals, "")
+	c.Assert(evt.Log(), check.Matches, `(?s).*Attempt 1 failed: exit status 1.*Attempt 2 succeeded.*`)
+	c.Assert(s.provisioner.JobRuns("myjob"), check.Equals, 1)
+}
+
+func (s *S) TestJobCreateInvalidSchedule(c *check.C) {
+	ij := inputJob{
+		Name:      "mycron",
+		TeamOwner: s.team.Name,
+		Image:     "busybox",
+		Schedule:  "every minute",
+	}
+	recorder := s.jobCreateRequest(c, ij, s.token.GetValue())
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(recorder.Body.String(), check.Matches, `invalid job schedule "every minute".*\n`)
+	_, err := servicemanager.Job.Get(context.TODO(), "mycron")
+	c.Assert(err, check.Equals, jobTypes.ErrJobNotFound)
+}
+
+func (s *S) TestJobCreateAlreadyExists(c *check.C) {
+	s.createTestJob(c, jobTypes.Job{Name: "myjob"})
+	ij := inputJob{Name: "myjob", TeamOwner: s.team.Name, Image: "busybox"}
+	recorder := s.jobCreateRequest(c, ij, s.token.GetValue())
+	c.Assert(recorder.Code, check.Equals, http.StatusConflict)
+	c.Assert(recorder.Body.String(), check.Equals, jobTypes.ErrJobAlreadyExists.Error()+"\n")
+}
+
+func (s *S) TestJobCreateForbidden(c *check.C) {
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermJobCreate,
+		Context: permission.Context(permTypes.CtxTeam, "otherteam"),
+	})
+	ij := inputJob{Name: "myjob", TeamOwner: s.team.Name, Image: "busybox"}
+	recorder := s.jobCreateRequest(c, ij, token.GetValue())
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+	_, err := servicemanager.Job.Get(context.TODO(), "myjob")
+	c.Assert(err, check.Equals, jobTypes.ErrJobNotFound)
+}
+
+func (s *S) TestJobList(c *check.C) {
+	s.createTestJob(c, jobTypes.Job{Name: "job1"})
+	s.createTestJob(c, jobTypes.Job{Name: "job2", Schedule: "0 * * * *"})
+	request, err := http.NewRequest("GET", "/1.13/jobs", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, "application/json")
+	var jobs []jobTypes.Job
+	err = json.Unmarshal(recorder.Body.Bytes(), &jobs)
+	c.Assert(err, check.IsNil)
+	c.Assert(jobs, check.HasLen, 2)
+	c.Assert(jobs[0].Name, check.Equals, "job1")
+	c.Assert(jobs[1].Name, check.Equals, "job2")
+	c.Assert(jobs[1].Schedule, check.Equals, "0 * * * *")
+}
+
+func (s *S) TestJobListFilteredByPermission(c *check.C) {
+	s.createTestJob(c, jobTypes.Job{Name: "job1"})
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermJobRead,
+		Context: permission.Context(permTypes.CtxTeam, "otherteam"),
+	})
+	request, err := http.NewRequest("GET", "/1.13/jobs", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNoContent)
+}
+
+func (s *S) TestJobInfo(c *check.C) {
+	s.createTestJob(c, jobTypes.Job{Name: "myjob", Command: []string{"date"}})
+	request, err := http.NewRequest("GET", "/1.13/jobs/myjob", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	var job jobTypes.Job
+	err = json.Unmarshal(recorder.Body.Bytes(), &job)
+	c.Assert(err, check.IsNil)
+	c.Assert(job.Name, check.Equals, "myjob")
+	c.Assert(job.TeamOwner, check.Equals, s.team.Name)
+	c.Assert(job.Command, check.DeepEquals, []string{"date"})
+	c.Assert(job.Plan, check.DeepEquals, s.defaultPlan)
+}
+
+func (s *S) TestJobInfoNotFound(c *check.C) {
+	request, err := http.NewRequest("GET", "/1.13/jobs/unknown", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNotFound)
+	c.Assert(recorder.Body.String(), check.Equals, jobTypes.ErrJobNotFound.Error()+"\n")
+}
+
+func (s *S) TestJobRun(c *check.C) {
+	s.createTestJob(c, jobTypes.Job{Name: "mycron", Schedule: "0 0 * * *"})
+	request, err := http.NewRequest("POST", "/1.13/jobs/mycron/run", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusAccepted)
+	var run map[string]string
+	err = json.Unmarshal(recorder.Body.Bytes(), &run)
+	c.Assert(err, check.IsNil)
+	evt := waitJobRunFinished(c, "mycron")
+	c.Assert(run["id"], check.Equals, evt.UniqueID.Hex())
+	c.Assert(s.provisioner.JobRuns("mycron"), check.Equals, 1)
+	c.Assert(eventtest.EventDesc{
+		Target: event.Target{Type: event.TargetTypeJob, Value: "mycron"},
+		Owner:  s.token.GetUserName(),
+		Kind:   "job.run",
+	}, eventtest.HasEvent)
+}
+
+func (s *S) TestJobRunForbidden(c *check.C) {
+	s.createTestJob(c, jobTypes.Job{Name: "myjob"})
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermJobRead,
+		Context: permission.Context(permTypes.CtxTeam, s.team.Name),
+	})
+	request, err := http.NewRequest("POST", "/1.13/jobs/myjob/run", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+	c.Assert(s.provisioner.JobRuns("myjob"), check.Equals, 0)
+}
+
+func (s *S) TestJobTrigger(c *check.C) {
+	s.createTestJob(c, jobTypes.Job{Name: "mycron", Schedule: "0 0 * * *"})
+	request, err := http.NewRequest("POST", "/1.13/jobs/mycron/trigger", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(s.provisioner.JobRuns("mycron"), check.Equals, 1)
+	c.Assert(eventtest.EventDesc{
+		Target: event.Target{Type: event.TargetTypeJob, Value: "mycron"},
+		Owner:  s.token.GetUserName(),
+		Kind:   "job.trigger",
+	}, eventtest.HasEvent)
+}
+
+func (s *S) TestJobTriggerNotCron(c *check.C) {
+	s.createTestJob(c, jobTypes.Job{Name: "myjob"})
+	request, err := http.NewRequest("POST", "/1.13/jobs/myjob/trigger", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(recorder.Body.String(), check.Equals, "only cronjobs can be triggered\n")
+}
+
+func (s *S) TestJobTriggerForbidden(c *check.C) {
+	s.createTestJob(c, jobTypes.Job{Name: "mycron", Schedule: "0 0 * * *"})
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermJobRun,
+		Context: permission.Context(permTypes.CtxTeam, s.team.Name),
+	})
+	request, err := http.NewRequest("POST", "/1.13/jobs/mycron/trigger", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+	c.Assert(s.provisioner.JobRuns("mycron"), check.Equals, 0)
+}
+
+func (s *S) TestJobTriggerQuotaExceeded(c *check.C) {
+	s.createTestJob(c, jobTypes.Job{Name: "mycron", Schedule: "0 0 * * *"})
+	s.mockService.JobUnitsQuota.OnInc = func(item quota.QuotaItem, delta int) error {
+		return &quota.QuotaExceededError{Available: 0, Requested: 1}
+	}
+	request, err := http.NewRequest("POST", "/1.13/jobs/mycron/trigger", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+	c.Assert(recorder.Header().Get("X-Tsuru-Error-Code"), check.Equals, "app.quota.exceeded")
+	c.Assert(s.provisioner.JobRuns("mycron"), check.Equals, 0)
+}
+
+func (s *S) TestJobDelete(c *check.C) {
+	s.createTestJob(c, jobTypes.Job{Name: "mycron", Schedule: "0 0 * * *"})
+	request, err := http.NewRequest("DELETE", "/1.13/jobs/mycron", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	_, err = servicemanager.Job.Get(context.TODO(), "mycron")
+	c.Assert(err, check.Equals, jobTypes.ErrJobNotFound)
+	_, ok := s.provisioner.CronJob("mycron")
+	c.Assert(ok, check.Equals, false)
+	c.Assert(eventtest.EventDesc{
+		Target: event.Target{Type: event.TargetTypeJob, Value: "mycron"},
+		Owner:  s.token.GetUserName(),
+		Kind:   "job.delete",
+	}, eventtest.HasEvent)
+}
+
+func (s *S) TestJobDeleteNotFound(c *check.C) {
+	request, err := http.NewRequest("DELETE", "/1.13/jobs/unknown", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNotFound)
+}
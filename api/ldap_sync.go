@@ -0,0 +1,36 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/auth/ldapsync"
+	"github.com/tsuru/tsuru/permission"
+)
+
+// title: ldap sync report
+// path: /auth/ldap-sync
+// method: GET
+// produce: application/json
+// responses:
+//   200: Pending changes
+//   400: Sync not configured
+//   401: Unauthorized
+func ldapSyncReport(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	if !permission.Check(t, permission.PermRoleReadLdapSync) {
+		return permission.ErrUnauthorized
+	}
+	report, err := ldapSyncPlan(r.Context())
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(report)
+}
+
+var ldapSyncPlan = ldapsync.Plan
@@ -0,0 +1,63 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/tsuru/tsuru/auth/ldapsync"
+	"github.com/tsuru/tsuru/permission"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestLDAPSyncReport(c *check.C) {
+	oldPlan := ldapSyncPlan
+	defer func() { ldapSyncPlan = oldPlan }()
+	ldapSyncPlan = func(ctx context.Context) (*ldapsync.Report, error) {
+		return &ldapsync.Report{
+			Add: []ldapsync.RoleChange{{Email: "alice@example.com", Role: "team-member", Team: "devs"}},
+		}, nil
+	}
+	request, err := http.NewRequest(http.MethodGet, "/1.13/auth/ldap-sync", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK, check.Commentf("body: %s", recorder.Body.String()))
+	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, "application/json")
+	var report ldapsync.Report
+	err = json.Unmarshal(recorder.Body.Bytes(), &report)
+	c.Assert(err, check.IsNil)
+	c.Assert(report, check.DeepEquals, ldapsync.Report{
+		Add: []ldapsync.RoleChange{{Email: "alice@example.com", Role: "team-member", Team: "devs"}},
+	})
+}
+
+func (s *S) TestLDAPSyncReportNotConfigured(c *check.C) {
+	request, err := http.NewRequest(http.MethodGet, "/1.13/auth/ldap-sync", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(recorder.Body.String(), check.Equals, ldapsync.ErrNotConfigured.Error()+"\n")
+}
+
+func (s *S) TestLDAPSyncReportUnauthorized(c *check.C) {
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermTeamRead,
+		Context: permission.Context(permTypes.CtxGlobal, ""),
+	})
+	request, err := http.NewRequest(http.MethodGet, "/1.13/auth/ldap-sync", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
@@ -0,0 +1,58 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/tsuru/tsuru/app"
+	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/permission"
+	authTypes "github.com/tsuru/tsuru/types/auth"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+)
+
+const lockoutUnsupportedMsg = "Authentication scheme does not support locking users out."
+
+// title: unlock user
+// path: /users/{email}/lockout
+// method: DELETE
+// responses:
+//   200: User unlocked
+//   400: Invalid data
+//   401: Unauthorized
+//   403: Forbidden
+//   404: User not found
+func unlockUser(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	scheme, ok := app.AuthScheme.(auth.LockoutScheme)
+	if !ok {
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: lockoutUnsupportedMsg}
+	}
+	if !permission.Check(t, permission.PermUserUpdateUnlock) {
+		return permission.ErrUnauthorized
+	}
+	email := r.URL.Query().Get(":email")
+	u, err := auth.GetUserByEmail(email)
+	if err != nil {
+		if err == authTypes.ErrUserNotFound {
+			return &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+		}
+		return err
+	}
+	evt, err := event.New(&event.Opts{
+		Target:     userTarget(u.Email),
+		Kind:       permission.PermUserUpdateUnlock,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		Allowed:    event.Allowed(permission.PermUserReadEvents, permission.Context(permTypes.CtxUser, u.Email)),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	return scheme.Unlock(r.Context(), u)
+}
@@ -0,0 +1,117 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/event/eventtest"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/permission/permissiontest"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+	check "gopkg.in/check.v1"
+)
+
+func (s *AuthSuite) loginRequest(c *check.C, email, password string) *httptest.ResponseRecorder {
+	b := strings.NewReader("password=" + password)
+	request, err := http.NewRequest(http.MethodPost, "/users/"+email+"/tokens", b)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func (s *AuthSuite) TestLoginRegistersEvents(c *check.C) {
+	u := auth.User{Email: "nobody@globo.com", Password: "123456"}
+	_, err := nativeScheme.Create(context.TODO(), &u)
+	c.Assert(err, check.IsNil)
+	recorder := s.loginRequest(c, u.Email, "123456")
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(eventtest.EventDesc{
+		Target: userTarget(u.Email),
+		Owner:  u.Email,
+		Kind:   "user.login",
+		StartCustomData: map[string]interface{}{
+			"scheme": "native",
+		},
+	}, eventtest.HasEvent)
+	recorder = s.loginRequest(c, u.Email, "1234567")
+	c.Assert(recorder.Code, check.Equals, http.StatusUnauthorized)
+	c.Assert(eventtest.EventDesc{
+		Target:       userTarget(u.Email),
+		Owner:        u.Email,
+		Kind:         "user.login",
+		ErrorMatches: "Authentication failed, wrong password.",
+	}, eventtest.HasEvent)
+}
+
+func (s *AuthSuite) TestLoginUnknownUserDoesNotRegisterEvents(c *check.C) {
+	recorder := s.loginRequest(c, "unknown@globo.com", "123456")
+	c.Assert(recorder.Code, check.Equals, http.StatusNotFound)
+	c.Assert(eventtest.EventDesc{
+		Target: userTarget("unknown@globo.com"),
+		Owner:  "unknown@globo.com",
+		Kind:   "user.login",
+	}, check.Not(eventtest.HasEvent))
+}
+
+func (s *AuthSuite) TestLoginLockedOut(c *check.C) {
+	config.Set("auth:lockout:max-failures", 2)
+	defer config.Unset("auth:lockout")
+	u := auth.User{Email: "nobody@globo.com", Password: "123456"}
+	_, err := nativeScheme.Create(context.TODO(), &u)
+	c.Assert(err, check.IsNil)
+	for i := 0; i < 2; i++ {
+		recorder := s.loginRequest(c, u.Email, "1234567")
+		c.Assert(recorder.Code, check.Equals, http.StatusUnauthorized)
+	}
+	recorder := s.loginRequest(c, u.Email, "123456")
+	c.Assert(recorder.Code, check.Equals, http.StatusTooManyRequests)
+	c.Assert(recorder.Header().Get("X-Tsuru-Error-Code"), check.Equals, "auth.account-locked")
+	request, err := http.NewRequest(http.MethodDelete, "/1.13/users/"+u.Email+"/lockout", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder = httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(eventtest.EventDesc{
+		Target: userTarget(u.Email),
+		Owner:  s.token.GetUserName(),
+		Kind:   "user.update.unlock",
+	}, eventtest.HasEvent)
+	recorder = s.loginRequest(c, u.Email, "123456")
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+}
+
+func (s *AuthSuite) TestUnlockUserNotFound(c *check.C) {
+	request, err := http.NewRequest(http.MethodDelete, "/1.13/users/unknown@globo.com/lockout", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNotFound)
+}
+
+func (s *AuthSuite) TestUnlockUserUnauthorized(c *check.C) {
+	_, token := permissiontest.CustomUserWithPermission(c, nativeScheme, "unlocker", permission.Permission{
+		Scheme:  permission.PermUser,
+		Context: permission.Context(permTypes.CtxUser, "nobody@globo.com"),
+	})
+	u := auth.User{Email: "nobody@globo.com", Password: "123456"}
+	_, err := nativeScheme.Create(context.TODO(), &u)
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest(http.MethodDelete, "/1.13/users/"+u.Email+"/lockout", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
@@ -0,0 +1,223 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	stdContext "context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/tsuru/tsuru/api/context"
+	"github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/log"
+	tsuruNet "github.com/tsuru/tsuru/net"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/servicemanager"
+	appTypes "github.com/tsuru/tsuru/types/app"
+)
+
+const (
+	logStreamDefaultLines = 100
+	logStreamWriteWait    = 10 * time.Second
+)
+
+// logStreamMessage is the message sent to clients of the log websocket. The
+// cursor may be sent back in the cursor parameter, when reconnecting, so
+// that only logs not yet received are sent.
+type logStreamMessage struct {
+	Cursor string            `json:"cursor,omitempty"`
+	Logs   []appTypes.Applog `json:"logs,omitempty"`
+	Error  string            `json:"error,omitempty"`
+}
+
+// logCursor is the position of the last log sent to a client: its date and
+// how many logs with the same date were sent.
+type logCursor struct {
+	date  time.Time
+	count int
+}
+
+func parseLogCursor(value string) (logCursor, error) {
+	parts := strings.SplitN(value, "-", 2)
+	if len(parts) != 2 {
+		return logCursor{}, &errors.ValidationError{Message: "invalid cursor"}
+	}
+	nsec, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return logCursor{}, &errors.ValidationError{Message: "invalid cursor"}
+	}
+	count, err := strconv.Atoi(parts[1])
+	if err != nil || count < 0 {
+		return logCursor{}, &errors.ValidationError{Message: "invalid cursor"}
+	}
+	return logCursor{date: time.Unix(0, nsec), count: count}, nil
+}
+
+func (c logCursor) String() string {
+	if c.date.IsZero() {
+		return ""
+	}
+	return fmt.Sprintf("%d-%d", c.date.UnixNano(), c.count)
+}
+
+func (c *logCursor) advance(date time.Time) {
+	if date.Equal(c.date) {
+		c.count++
+		return
+	}
+	c.date = date
+	c.count = 1
+}
+
+// after returns the logs, sorted by date, that come after the cursor.
+func (c logCursor) after(logs []appTypes.Applog) []appTypes.Applog {
+	if c.date.IsZero() {
+		return logs
+	}
+	skip := c.count
+	var result []appTypes.Applog
+	for _, l := range logs {
+		if l.Date.Before(c.date) {
+			continue
+		}
+		if l.Date.Equal(c.date) && skip > 0 {
+			skip--
+			continue
+		}
+		result = append(result, l)
+	}
+	return result
+}
+
+// wsLogEncoder sends logs to the websocket, along with the cursor of the last
+// sent log.
+type wsLogEncoder struct {
+	ws     *websocket.Conn
+	cursor logCursor
+}
+
+func (e *wsLogEncoder) Encode(v interface{}) error {
+	logs, _ := v.([]appTypes.Applog)
+	for _, l := range logs {
+		e.cursor.advance(l.Date)
+	}
+	e.ws.SetWriteDeadline(time.Now().Add(logStreamWriteWait))
+	return e.ws.WriteJSON(logStreamMessage{Cursor: e.cursor.String(), Logs: logs})
+}
+
+// title: app log stream
+// path: /apps/{appname}/log/ws
+// method: GET
+// produce: Websocket connection upgrade
+// responses:
+//   101: Switch Protocol to websocket
+func appLogStream(w http.ResponseWriter, r *http.Request) {
+	ws, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		fmt.Fprintf(w, "unable to upgrade ws connection: %v", err)
+		return
+	}
+	defer ws.Close()
+	err = streamAppLogs(r, ws)
+	if err != nil {
+		ws.SetWriteDeadline(time.Now().Add(logStreamWriteWait))
+		ws.WriteJSON(logStreamMessage{Error: err.Error()})
+		if _, isHTTP := err.(*errors.HTTP); !isHTTP {
+			log.Errorf("failure streaming logs: %s", err)
+		}
+	}
+	ws.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(logStreamWriteWait))
+}
+
+func streamAppLogs(r *http.Request, ws *websocket.Conn) error {
+	t := context.GetAuthToken(r)
+	if t == nil {
+		return &errors.HTTP{Code: http.StatusUnauthorized, Message: "no token provided"}
+	}
+	urlValues := r.URL.Query()
+	lines := logStreamDefaultLines
+	if l := urlValues.Get("lines"); l != "" {
+		var err error
+		lines, err = strconv.Atoi(l)
+		if err != nil {
+			return &errors.HTTP{Code: http.StatusBadRequest, Message: `Parameter "lines" must be an integer.`}
+		}
+	}
+	encoder := &wsLogEncoder{ws: ws}
+	if c := urlValues.Get("cursor"); c != "" {
+		cursor, err := parseLogCursor(c)
+		if err != nil {
+			return &errors.HTTP{Code: http.StatusBadRequest, Message: err.Error()}
+		}
+		encoder.cursor = cursor
+	}
+	a, err := getAppFromContext(urlValues.Get(":appname"), r)
+	if err != nil {
+		return err
+	}
+	if !permission.Check(t, permission.PermAppReadLog, contextsForApp(&a)...) {
+		return permission.ErrUnauthorized
+	}
+	invert, _ := strconv.ParseBool(urlValues.Get("invert-source"))
+	listArgs := appTypes.ListLogArgs{
+		AppName:      a.Name,
+		Limit:        lines,
+		Source:       urlValues.Get("source"),
+		InvertSource: invert,
+		Units:        urlValues["unit"],
+		Token:        t,
+	}
+	ctx, cancel := stdContext.WithCancel(tsuruNet.CancelableParentContext(r.Context()))
+	defer cancel()
+	logService := servicemanager.AppLog
+	logs, err := a.LastLogs(ctx, logService, listArgs)
+	if err != nil {
+		return err
+	}
+	if logs = encoder.cursor.after(logs); len(logs) > 0 {
+		err = encoder.Encode(logs)
+		if err != nil {
+			return err
+		}
+	}
+	watcher, err := logService.Watch(ctx, listArgs)
+	if err != nil {
+		return err
+	}
+	ws.SetReadDeadline(time.Now().Add(pongWait))
+	ws.SetPongHandler(func(string) error {
+		ws.SetReadDeadline(time.Now().Add(pongWait))
+		return nil
+	})
+	go func() {
+		// Reading is required to process pong and close messages, the
+		// stream is canceled as soon as the client goes away.
+		defer cancel()
+		for {
+			if _, _, err := ws.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(pingInterval):
+			}
+			ws.WriteControl(websocket.PingMessage, nil, time.Now().Add(logStreamWriteWait))
+		}
+	}()
+	err = followLogs(ctx, a.Name, watcher, encoder)
+	if err != nil && ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,155 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/tsuru/tsuru/app"
+	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/servicemanager"
+	appTypes "github.com/tsuru/tsuru/types/app"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestLogCursor(c *check.C) {
+	date := time.Date(2022, 5, 10, 12, 0, 0, 0, time.UTC)
+	logs := []appTypes.Applog{
+		{Date: date, Message: "a"},
+		{Date: date, Message: "b"},
+		{Date: date.Add(time.Second), Message: "c"},
+	}
+	var cursor logCursor
+	c.Assert(cursor.String(), check.Equals, "")
+	c.Assert(cursor.after(logs), check.DeepEquals, logs)
+	cursor.advance(logs[0].Date)
+	c.Assert(cursor.String(), check.Equals, fmt.Sprintf("%d-1", date.UnixNano()))
+	c.Assert(cursor.after(logs), check.DeepEquals, logs[1:])
+	cursor.advance(logs[1].Date)
+	c.Assert(cursor.String(), check.Equals, fmt.Sprintf("%d-2", date.UnixNano()))
+	c.Assert(cursor.after(logs), check.DeepEquals, logs[2:])
+	cursor.advance(logs[2].Date)
+	c.Assert(cursor.after(logs), check.HasLen, 0)
+	parsed, err := parseLogCursor(cursor.String())
+	c.Assert(err, check.IsNil)
+	c.Assert(parsed.date.Equal(cursor.date), check.Equals, true)
+	c.Assert(parsed.count, check.Equals, 1)
+}
+
+func (s *S) TestParseLogCursorInvalid(c *check.C) {
+	for _, value := range []string{"abc", "1-", "x-1", "1--1"} {
+		_, err := parseLogCursor(value)
+		c.Assert(err, check.ErrorMatches, "invalid cursor", check.Commentf("%q", value))
+	}
+}
+
+func (s *S) dialLogStream(c *check.C, token auth.Token, appName, query string) (*websocket.Conn, func()) {
+	server := httptest.NewServer(s.testServer)
+	serverURL, err := url.Parse(server.URL)
+	c.Assert(err, check.IsNil)
+	wsURL := fmt.Sprintf("ws://%s/1.13/apps/%s/log/ws?%s", serverURL.Host, appName, query)
+	header := http.Header{}
+	header.Set("Authorization", "bearer "+token.GetValue())
+	ws, _, err := websocket.DefaultDialer.Dial(wsURL, header)
+	c.Assert(err, check.IsNil)
+	return ws, func() {
+		ws.Close()
+		server.Close()
+	}
+}
+
+func readLogStreamMessage(c *check.C, ws *websocket.Conn) logStreamMessage {
+	var msg logStreamMessage
+	ws.SetReadDeadline(time.Now().Add(5 * time.Second))
+	err := ws.ReadJSON(&msg)
+	c.Assert(err, check.IsNil)
+	return msg
+}
+
+func (s *S) TestAppLogStream(c *check.C) {
+	a := app.App{Name: "lost1", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	err = servicemanager.AppLog.Add(a.Name, "first", "web", "")
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermAppReadLog,
+		Context: permission.Context(permTypes.CtxTeam, s.team.Name),
+	})
+	ws, closeFn := s.dialLogStream(c, token, a.Name, "lines=10")
+	defer closeFn()
+	msg := readLogStreamMessage(c, ws)
+	c.Assert(msg.Error, check.Equals, "")
+	c.Assert(msg.Logs, check.HasLen, 1)
+	c.Assert(msg.Logs[0].Message, check.Equals, "first")
+	c.Assert(msg.Cursor, check.Not(check.Equals), "")
+	cursor := msg.Cursor
+	var listener appTypes.LogWatcher
+	timeout := time.After(5 * time.Second)
+	for listener == nil {
+		select {
+		case <-timeout:
+			c.Fatal("timeout after 5 seconds")
+		case <-time.After(50 * time.Millisecond):
+		}
+		logTracker.Lock()
+		for listener = range logTracker.conn {
+		}
+		logTracker.Unlock()
+	}
+	err = servicemanager.AppLog.Add(a.Name, "second", "web", "")
+	c.Assert(err, check.IsNil)
+	msg = readLogStreamMessage(c, ws)
+	c.Assert(msg.Logs, check.HasLen, 1)
+	c.Assert(msg.Logs[0].Message, check.Equals, "second")
+	c.Assert(msg.Cursor, check.Not(check.Equals), cursor)
+	closeFn()
+	ws, closeFn = s.dialLogStream(c, token, a.Name, "lines=10&cursor="+cursor)
+	defer closeFn()
+	msg = readLogStreamMessage(c, ws)
+	c.Assert(msg.Logs, check.HasLen, 1)
+	c.Assert(msg.Logs[0].Message, check.Equals, "second")
+}
+
+func (s *S) TestAppLogStreamWithoutPermission(c *check.C) {
+	a := app.App{Name: "lost1", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermAppReadLog,
+		Context: permission.Context(permTypes.CtxTeam, "no-access"),
+	})
+	ws, closeFn := s.dialLogStream(c, token, a.Name, "lines=10")
+	defer closeFn()
+	msg := readLogStreamMessage(c, ws)
+	c.Assert(msg.Error, check.Equals, permission.ErrUnauthorized.Error())
+	_, _, err = ws.ReadMessage()
+	c.Assert(websocket.IsCloseError(err, websocket.CloseNormalClosure), check.Equals, true)
+}
+
+func (s *S) TestAppLogStreamAppNotFound(c *check.C) {
+	ws, closeFn := s.dialLogStream(c, s.token, "unknown", "lines=10")
+	defer closeFn()
+	msg := readLogStreamMessage(c, ws)
+	c.Assert(msg.Error, check.Equals, "App unknown not found.")
+}
+
+func (s *S) TestAppLogStreamInvalidCursor(c *check.C) {
+	a := app.App{Name: "lost1", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	ws, closeFn := s.dialLogStream(c, s.token, a.Name, "cursor=abc")
+	defer closeFn()
+	msg := readLogStreamMessage(c, ws)
+	c.Assert(msg.Error, check.Equals, "invalid cursor")
+}
@@ -21,16 +21,19 @@ import (
 	"github.com/pkg/errors"
 	"github.com/tsuru/config"
 	"github.com/tsuru/tsuru/api/context"
+	apiRouter "github.com/tsuru/tsuru/api/router"
 	"github.com/tsuru/tsuru/app"
 	"github.com/tsuru/tsuru/auth"
 	"github.com/tsuru/tsuru/cmd"
 	tsuruErrors "github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event"
 	"github.com/tsuru/tsuru/io"
 	"github.com/tsuru/tsuru/log"
 	tsuruNet "github.com/tsuru/tsuru/net"
 	"github.com/tsuru/tsuru/servicemanager"
 	"github.com/tsuru/tsuru/set"
 	appTypes "github.com/tsuru/tsuru/types/app"
+	"github.com/tsuru/tsuru/types/quota"
 )
 
 const (
@@ -47,9 +50,12 @@ func validate(token string, r *http.Request) (auth.Token, error) {
 	if err != nil {
 		t, err = auth.APIAuth(token)
 		if err != nil {
-			t, err = servicemanager.TeamToken.Authenticate(r.Context(), token)
+			t, err = auth.ImpersonationAuth(token)
 			if err != nil {
-				return nil, err
+				t, err = servicemanager.TeamToken.Authenticate(r.Context(), token)
+				if err != nil {
+					return nil, err
+				}
 			}
 		}
 	}
@@ -141,6 +147,33 @@ func setVersionHeadersMiddleware(w http.ResponseWriter, r *http.Request, next ht
 	next(w, r)
 }
 
+// setDeprecationHeadersMiddleware signals clients of the 1.x API, including
+// requests without version, that they should move to the latest version. The
+// Sunset header is sent when the api:legacy-sunset config is set.
+func setDeprecationHeadersMiddleware(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	if r.URL.Query().Get(":mux-path-template") != "" && !isLatestAPIVersion(r) {
+		w.Header().Set("Deprecation", "true")
+		if sunset, _ := config.GetString("api:legacy-sunset"); sunset != "" {
+			if date, err := time.Parse("2006-01-02", sunset); err == nil {
+				w.Header().Set("Sunset", date.Format(http.TimeFormat))
+			}
+		}
+	}
+	next(w, r)
+}
+
+func isLatestAPIVersion(r *http.Request) bool {
+	return r.URL.Query().Get(":version") == apiRouter.LatestVersion
+}
+
+// apiError is the body of error responses in the latest API version, and in
+// older versions when the client accepts JSON, otherwise errors are answered
+// in plain text.
+type apiError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
 func errorHandlingMiddleware(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
 	next(w, r)
 	err := context.GetRequestError(r)
@@ -152,10 +185,13 @@ func errorHandlingMiddleware(w http.ResponseWriter, r *http.Request, next http.H
 			code = http.StatusBadRequest
 		case *tsuruErrors.HTTP:
 			code = t.Code
+		case event.ErrThrottled:
+			code = http.StatusTooManyRequests
 		}
 		if errors.Cause(err) == appTypes.ErrAppNotFound {
 			code = http.StatusNotFound
 		}
+		errCode := errorCode(err, code)
 		if verbosity == 0 {
 			err = fmt.Errorf("%s", err)
 		} else {
@@ -171,13 +207,49 @@ func errorHandlingMiddleware(w http.ResponseWriter, r *http.Request, next http.H
 			} else {
 				fmt.Fprintln(w, err)
 			}
+		} else if acceptsJSONError(r) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("X-Content-Type-Options", "nosniff")
+			w.Header().Set("X-Tsuru-Error-Code", errCode)
+			w.WriteHeader(code)
+			json.NewEncoder(w).Encode(apiError{Code: errCode, Message: err.Error()})
 		} else {
+			w.Header().Set("X-Tsuru-Error-Code", errCode)
 			http.Error(w, err.Error(), code)
 		}
 		log.Errorf("failure running HTTP request %s %s (%d): %s", r.Method, r.URL.Path, code, err)
 	}
 }
 
+func acceptsJSONError(r *http.Request) bool {
+	return isLatestAPIVersion(r) || strings.Contains(r.Header.Get("Accept"), "application/json")
+}
+
+// errorCode returns the stable identifier of err, errors without one are
+// identified by their status code, e.g. not-found.
+func errorCode(err error, status int) string {
+	cause := errors.Cause(err)
+	switch t := cause.(type) {
+	case *tsuruErrors.HTTP:
+		if t.ErrorCode != "" {
+			return t.ErrorCode
+		}
+	case *quota.QuotaExceededError:
+		return "app.quota.exceeded"
+	case event.ErrThrottled:
+		return "event.throttled"
+	case event.ErrEventLocked:
+		return "event.locked"
+	}
+	if cause == appTypes.ErrAppNotFound {
+		return "app.not-found"
+	}
+	if text := http.StatusText(status); text != "" {
+		return strings.ReplaceAll(strings.ToLower(text), " ", "-")
+	}
+	return "error"
+}
+
 func authTokenMiddleware(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
 	token := r.Header.Get("Authorization")
 	if token != "" {
@@ -190,6 +262,7 @@ func authTokenMiddleware(w http.ResponseWriter, r *http.Request, next http.Handl
 			log.Debugf("Ignored invalid token for %s: %s", r.URL.Path, err.Error())
 		} else {
 			context.SetAuthToken(r, t)
+			touchSession(r, t)
 		}
 	}
 	next(w, r)
@@ -6,6 +6,7 @@ package api
 
 import (
 	stdContext "context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -23,9 +24,13 @@ import (
 	"github.com/tsuru/tsuru/auth"
 	"github.com/tsuru/tsuru/cmd"
 	tsuruErrors "github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event"
 	"github.com/tsuru/tsuru/io"
+	"github.com/tsuru/tsuru/permission"
 	"github.com/tsuru/tsuru/servicemanager"
+	appTypes "github.com/tsuru/tsuru/types/app"
 	authTypes "github.com/tsuru/tsuru/types/auth"
+	"github.com/tsuru/tsuru/types/quota"
 	check "gopkg.in/check.v1"
 )
 
@@ -159,6 +164,72 @@ func (s *S) TestSetVersionHeadersMiddleware(c *check.C) {
 	c.Assert(recorder.Header().Get("Supported-Tsuru-Admin"), check.Equals, tsuruAdminMin)
 }
 
+func (s *S) TestSetDeprecationHeadersMiddleware(c *check.C) {
+	config.Set("api:legacy-sunset", "2023-06-30")
+	defer config.Unset("api:legacy-sunset")
+	tests := []struct {
+		url        string
+		deprecated bool
+	}{
+		{"/apps?:mux-path-template=/apps&:version=1.0", true},
+		{"/apps?:mux-path-template=/apps", true},
+		{"/apps?:mux-path-template=/apps&:version=2", false},
+		{"/unknown", false},
+	}
+	for _, tt := range tests {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest("GET", tt.url, nil)
+		c.Assert(err, check.IsNil)
+		h, log := doHandler()
+		setDeprecationHeadersMiddleware(recorder, request, h)
+		c.Assert(log.called, check.Equals, true)
+		if tt.deprecated {
+			c.Assert(recorder.Header().Get("Deprecation"), check.Equals, "true", check.Commentf("%s", tt.url))
+			c.Assert(recorder.Header().Get("Sunset"), check.Equals, "Fri, 30 Jun 2023 00:00:00 GMT")
+		} else {
+			c.Assert(recorder.Header().Get("Deprecation"), check.Equals, "", check.Commentf("%s", tt.url))
+			c.Assert(recorder.Header().Get("Sunset"), check.Equals, "")
+		}
+	}
+}
+
+func (s *S) TestSetDeprecationHeadersMiddlewareWithoutSunset(c *check.C) {
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest("GET", "/apps?:mux-path-template=/apps&:version=1.0", nil)
+	c.Assert(err, check.IsNil)
+	h, _ := doHandler()
+	setDeprecationHeadersMiddleware(recorder, request, h)
+	c.Assert(recorder.Header().Get("Deprecation"), check.Equals, "true")
+	c.Assert(recorder.Header().Get("Sunset"), check.Equals, "")
+}
+
+func (s *S) TestLatestVersionServesLegacyHandlers(c *check.C) {
+	for _, path := range []string{"/1.0/apps", "/apps", "/2/apps"} {
+		request, err := http.NewRequest("GET", path, nil)
+		c.Assert(err, check.IsNil)
+		request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+		recorder := httptest.NewRecorder()
+		s.testServer.ServeHTTP(recorder, request)
+		c.Assert(recorder.Code, check.Equals, http.StatusNoContent, check.Commentf("%s", path))
+		if path == "/2/apps" {
+			c.Assert(recorder.Header().Get("Deprecation"), check.Equals, "")
+		} else {
+			c.Assert(recorder.Header().Get("Deprecation"), check.Equals, "true", check.Commentf("%s", path))
+		}
+	}
+	request, err := http.NewRequest("GET", "/2/apps/unknown", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNotFound)
+	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, "application/json")
+	var apiErr apiError
+	err = json.Unmarshal(recorder.Body.Bytes(), &apiErr)
+	c.Assert(err, check.IsNil)
+	c.Assert(apiErr.Code, check.Equals, http.StatusNotFound)
+}
+
 func (s *S) TestErrorHandlingMiddlewareWithoutError(c *check.C) {
 	recorder := httptest.NewRecorder()
 	request, err := http.NewRequest("GET", "/", nil)
@@ -191,6 +262,81 @@ func (s *S) TestErrorHandlingMiddlewareWithHTTPError(c *check.C) {
 	c.Assert(recorder.Code, check.Equals, 403)
 }
 
+func (s *S) TestErrorHandlingMiddlewareLatestVersion(c *check.C) {
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest("GET", "/apps?:version=2", nil)
+	c.Assert(err, check.IsNil)
+	h, _ := doHandler()
+	context.AddRequestError(request, &tsuruErrors.HTTP{Code: 403, Message: "other msg"})
+	errorHandlingMiddleware(recorder, request, h)
+	c.Assert(recorder.Code, check.Equals, 403)
+	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, "application/json")
+	c.Assert(recorder.Header().Get("X-Tsuru-Error-Code"), check.Equals, "forbidden")
+	c.Assert(recorder.Body.String(), check.Equals, `{"code":"forbidden","message":"other msg"}`+"\n")
+}
+
+func (s *S) TestErrorHandlingMiddlewareAcceptJSON(c *check.C) {
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest("GET", "/apps", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Accept", "application/json")
+	h, _ := doHandler()
+	context.AddRequestError(request, &tsuruErrors.HTTP{Code: 403, Message: "Quota exceeded", ErrorCode: "app.quota.exceeded"})
+	errorHandlingMiddleware(recorder, request, h)
+	c.Assert(recorder.Code, check.Equals, 403)
+	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, "application/json")
+	var result apiError
+	err = json.Unmarshal(recorder.Body.Bytes(), &result)
+	c.Assert(err, check.IsNil)
+	c.Assert(result, check.DeepEquals, apiError{Code: "app.quota.exceeded", Message: "Quota exceeded"})
+}
+
+func (s *S) TestErrorHandlingMiddlewarePlainTextErrorCode(c *check.C) {
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest("GET", "/apps", nil)
+	c.Assert(err, check.IsNil)
+	h, _ := doHandler()
+	context.AddRequestError(request, appTypes.ErrAppNotFound)
+	errorHandlingMiddleware(recorder, request, h)
+	c.Assert(recorder.Code, check.Equals, http.StatusNotFound)
+	c.Assert(recorder.Header().Get("X-Tsuru-Error-Code"), check.Equals, "app.not-found")
+	c.Assert(recorder.Body.String(), check.Equals, appTypes.ErrAppNotFound.Error()+"\n")
+}
+
+func (s *S) TestErrorCode(c *check.C) {
+	tests := []struct {
+		err      error
+		status   int
+		expected string
+	}{
+		{err: &tsuruErrors.HTTP{Code: 403, ErrorCode: "app.quota.exceeded"}, status: 403, expected: "app.quota.exceeded"},
+		{err: &tsuruErrors.HTTP{Code: 404}, status: 404, expected: "not-found"},
+		{err: errors.WithStack(&quota.QuotaExceededError{Requested: 2, Available: 1}), status: 500, expected: "app.quota.exceeded"},
+		{err: permission.ErrUnauthorized, status: 403, expected: "permission.denied"},
+		{err: event.ErrThrottled{}, status: 429, expected: "event.throttled"},
+		{err: errors.New("unknown"), status: 500, expected: "internal-server-error"},
+		{err: errors.New("unknown"), status: 999, expected: "error"},
+	}
+	for _, tt := range tests {
+		c.Check(errorCode(tt.err, tt.status), check.Equals, tt.expected)
+	}
+}
+
+func (s *S) TestErrorHandlingMiddlewareWithThrottledEvent(c *check.C) {
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest("GET", "/", nil)
+	c.Assert(err, check.IsNil)
+	h, log := doHandler()
+	context.AddRequestError(request, event.ErrThrottled{
+		Spec:   &event.ThrottlingSpec{KindName: "app.deploy", Max: 10, Time: time.Minute},
+		Target: event.Target{Type: event.TargetTypeApp, Value: "myapp"},
+	})
+	errorHandlingMiddleware(recorder, request, h)
+	c.Assert(log.called, check.Equals, true)
+	c.Assert(recorder.Code, check.Equals, http.StatusTooManyRequests)
+	c.Assert(recorder.Body.String(), check.Equals, "event throttled, limit for app.deploy on app \"myapp\" is 10 every 1m0s\n")
+}
+
 func (s *S) TestErrorHandlingMiddlewareWithValidationError(c *check.C) {
 	recorder := httptest.NewRecorder()
 	request, err := http.NewRequest("GET", "/", nil)
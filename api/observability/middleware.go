@@ -27,6 +27,18 @@ const (
 )
 
 var (
+	httpDurationBuckets = []float64{
+		0.001, // 1ms
+		0.01,  // 10ms
+		0.1,   // 100 ms
+		0.5,
+		1.0, // 1s
+		5.0,
+		10.0, // 10s
+		20.0,
+		30.0,
+	}
+
 	httpRequests = promauto.NewCounterVec(prometheus.CounterOpts{
 		Namespace: metricsNamespace,
 		Subsystem: metricsSubsystem,
@@ -39,18 +51,23 @@ var (
 		Subsystem: metricsSubsystem,
 		Name:      "request_duration_seconds",
 		Help:      "Spend time by processing a route",
-		Buckets: []float64{
-			0.001, // 1ms
-			0.01,  // 10ms
-			0.1,   // 100 ms
-			0.5,
-			1.0, // 1s
-			5.0,
-			10.0, // 10s
-			20.0,
-			30.0,
-		},
+		Buckets:   httpDurationBuckets,
 	}, []string{"method", "path"})
+
+	httpRouteRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "route_requests_total",
+		Help:      "Number of HTTP operations by route",
+	}, []string{"route", "method", "status"})
+
+	httpRouteDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "route_request_duration_seconds",
+		Help:      "Spend time by processing a route, by status class",
+		Buckets:   httpDurationBuckets,
+	}, []string{"route", "method", "status"})
 )
 
 type middleware struct {
@@ -94,6 +111,12 @@ func (l *middleware) ServeHTTP(rw http.ResponseWriter, r *http.Request, next htt
 	status := normalizeHTTPStatus(statusCode)
 	httpRequests.WithLabelValues(status, r.Method, path).Inc()
 	httpDuration.WithLabelValues(r.Method, path).Observe(duration.Seconds())
+	route := r.URL.Query().Get(":mux-route-name")
+	if route == "" {
+		route = path
+	}
+	httpRouteRequests.WithLabelValues(route, r.Method, status).Inc()
+	httpRouteDuration.WithLabelValues(route, r.Method, status).Observe(duration.Seconds())
 
 	// finish logs
 	l.logger.Printf("%s %s %s %s %d %q in %0.6fms%s", nowFormatted, scheme, r.Method, r.URL.Path, statusCode, r.UserAgent(), float64(duration)/float64(time.Millisecond), requestID)
@@ -118,12 +141,14 @@ func NewMiddleware() *middleware {
 	}
 }
 
-func PrePopulateMetrics(method, path string) {
-	httpRequests.WithLabelValues("1xx", method, path)
-	httpRequests.WithLabelValues("2xx", method, path)
-	httpRequests.WithLabelValues("3xx", method, path)
-	httpRequests.WithLabelValues("4xx", method, path)
-	httpRequests.WithLabelValues("5xx", method, path)
+func PrePopulateMetrics(route, method, path string) {
+	if route == "" {
+		route = path
+	}
+	for _, status := range []string{"1xx", "2xx", "3xx", "4xx", "5xx"} {
+		httpRequests.WithLabelValues(status, method, path)
+		httpRouteRequests.WithLabelValues(route, method, status)
+	}
 	httpDuration.WithLabelValues(method, path)
 }
 
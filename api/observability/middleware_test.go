@@ -7,6 +7,7 @@ package observability
 import (
 	"bytes"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"net/http/httptest"
@@ -19,6 +20,7 @@ import (
 	opentracingExt "github.com/opentracing/opentracing-go/ext"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/prometheus/common/expfmt"
 	"github.com/tsuru/config"
 	"github.com/tsuru/tsuru/api/context"
@@ -73,6 +75,50 @@ func (s *S) TestMiddleware(c *check.C) {
 	}
 }
 
+func (s *S) TestMiddlewareRouteMetrics(c *check.C) {
+	httpRouteRequests.Reset()
+	httpRouteDuration.Reset()
+
+	promReg := prometheus.NewRegistry()
+	promReg.Register(httpRouteRequests)
+	promReg.Register(httpRouteDuration)
+
+	h, handlerLog := doHandler()
+	middle := middleware{
+		logger: log.New(io.Discard, "", 0),
+	}
+	request, err := http.NewRequest("POST", "/apps?:mux-route-name=createApp&:mux-path-template=/apps", nil)
+	c.Assert(err, check.IsNil)
+	handlerLog.response = http.StatusCreated
+	middle.ServeHTTP(negroni.NewResponseWriter(httptest.NewRecorder()), request, h)
+	request, err = http.NewRequest("GET", "/apps/myapp?:mux-path-template=/apps/{app}", nil)
+	c.Assert(err, check.IsNil)
+	handlerLog.response = http.StatusNotFound
+	middle.ServeHTTP(negroni.NewResponseWriter(httptest.NewRecorder()), request, h)
+
+	metricsFamilies, err := promReg.Gather()
+	c.Assert(err, check.IsNil)
+	c.Assert(metricsFamilies, check.HasLen, 2)
+	var buf bytes.Buffer
+	for _, metricFamily := range metricsFamilies {
+		expfmt.MetricFamilyToText(&buf, metricFamily)
+	}
+	metrics := buf.String()
+	c.Check(strings.Contains(metrics, `tsuru_http_route_requests_total{method="POST",route="createApp",status="2xx"} 1`), check.Equals, true, check.Commentf("metrics: %s", metrics))
+	c.Check(strings.Contains(metrics, `tsuru_http_route_requests_total{method="GET",route="/apps/{app}",status="4xx"} 1`), check.Equals, true, check.Commentf("metrics: %s", metrics))
+	c.Check(strings.Contains(metrics, `tsuru_http_route_request_duration_seconds_count{method="POST",route="createApp",status="2xx"} 1`), check.Equals, true, check.Commentf("metrics: %s", metrics))
+}
+
+func (s *S) TestPrePopulateMetrics(c *check.C) {
+	httpRouteRequests.Reset()
+	PrePopulateMetrics("appList", "GET", "/apps")
+	PrePopulateMetrics("", "GET", "/plans")
+	for _, status := range []string{"1xx", "2xx", "3xx", "4xx", "5xx"} {
+		c.Check(testutil.ToFloat64(httpRouteRequests.WithLabelValues("appList", "GET", status)), check.Equals, float64(0))
+	}
+	c.Assert(testutil.CollectAndCount(httpRouteRequests), check.Equals, 10)
+}
+
 func (s *S) TestMiddlewareWithoutStatusCode(c *check.C) {
 	recorder := httptest.NewRecorder()
 	request, err := http.NewRequest("PUT", "/my/path", nil)
@@ -0,0 +1,25 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/tsuru/tsuru/api/openapi"
+)
+
+//go:generate bash -c "rm -f openapi_handlers.go && go run ./openapi/generator/main.go -o openapi_handlers.go"
+
+// title: openapi spec
+// path: /openapi.json
+// method: GET
+// produce: application/json
+// responses:
+//   200: OK
+func openAPISpec(w http.ResponseWriter, r *http.Request) error {
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(openapi.NewDocument(openAPIHandlers, Version))
+}
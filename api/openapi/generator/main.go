@@ -0,0 +1,99 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"flag"
+	"go/format"
+	"log"
+	"os"
+	"text/template"
+	"time"
+
+	"github.com/tsuru/tsuru/api/openapi"
+)
+
+var fileTpl = `// AUTOMATICALLY GENERATED FILE - DO NOT EDIT!
+// Please run 'go generate' to update this file.
+//
+// Copyright {{.Time.Year}} tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package {{.Package}}
+
+import "github.com/tsuru/tsuru/api/openapi"
+
+var openAPIHandlers = []openapi.Handler{
+{{range .Handlers}} \
+    {
+        Name: {{printf "%q" .Name}},
+        Group: {{printf "%q" .Group}},
+        Title: {{printf "%q" .Title}},
+        Path: {{printf "%q" .Path}},
+        Method: {{printf "%q" .Method}},
+{{if .Consume}}        Consume: {{printf "%q" .Consume}},
+{{end}} \
+{{if .Produce}}        Produce: {{printf "%q" .Produce}},
+{{end}} \
+{{if .Version}}        Version: {{printf "%q" .Version}},
+{{end}} \
+{{if .Public}}        Public: true,
+{{end}} \
+{{if .Responses}} \
+        Responses: []openapi.Response{
+{{range .Responses}} \
+            {Code: {{.Code}}, Description: {{printf "%q" .Description}}},
+{{end}} \
+        },
+{{end}} \
+    },
+{{end}} \
+}
+`
+
+type context struct {
+	Time     time.Time
+	Package  string
+	Handlers []openapi.Handler
+}
+
+func main() {
+	out := flag.String("o", "", "output file")
+	dir := flag.String("d", ".", "directory of the package declaring the handlers")
+	pkg := flag.String("p", "api", "package of the output file")
+	flag.Parse()
+	tmpl, err := template.New("tpl").Parse(fileTpl)
+	if err != nil {
+		log.Fatal(err)
+	}
+	handlers, err := openapi.Parse(*dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	data := context{
+		Time:     time.Now(),
+		Package:  *pkg,
+		Handlers: handlers,
+	}
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, data)
+	if err != nil {
+		log.Fatal(err)
+	}
+	rawFile := buf.Bytes()
+	rawFile = bytes.Replace(rawFile, []byte("\\\n"), []byte{}, -1)
+	formatedFile, err := format.Source(rawFile)
+	if err != nil {
+		log.Fatalf("unable to format code: %s\n%s", err, rawFile)
+	}
+	file, err := os.OpenFile(*out, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0660)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer file.Close()
+	file.Write(formatedFile)
+}
@@ -0,0 +1,217 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package openapi builds the OpenAPI document of the tsuru API from the
+// annotations in the doc comments of its handlers:
+//
+//	// title: app info
+//	// path: /apps/{app}
+//	// method: GET
+//	// produce: application/json
+//	// responses:
+//	//   200: OK
+//	//   401: Unauthorized
+//	//   404: Not found
+//
+// The annotations are parsed at build time, by go generate, as the source code
+// isn't available to the running server.
+package openapi
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	openAPIVersion   = "3.0.3"
+	securitySchemeID = "bearerAuth"
+)
+
+var pathParamRegexp = regexp.MustCompile(`{([^}:]+)(:[^}]*)?}`)
+
+// Handler holds the annotations of an API handler, along with the route it's
+// registered in.
+type Handler struct {
+	// Name is the name of the handler function.
+	Name string
+	// Group is the name of the source file declaring the handler, without
+	// extension.
+	Group     string
+	Title     string
+	Path      string
+	Method    string
+	Consume   string
+	Produce   string
+	Version   string
+	Public    bool
+	Responses []Response
+}
+
+type Response struct {
+	Code        int
+	Description string
+}
+
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// PathItem maps the lower case HTTP methods to the operations of a path.
+type PathItem map[string]*Operation
+
+type Operation struct {
+	OperationID string                       `json:"operationId"`
+	Summary     string                       `json:"summary"`
+	Tags        []string                     `json:"tags,omitempty"`
+	Parameters  []Parameter                  `json:"parameters,omitempty"`
+	RequestBody *RequestBody                 `json:"requestBody,omitempty"`
+	Responses   map[string]OperationResponse `json:"responses"`
+	Security    []map[string][]string        `json:"security,omitempty"`
+}
+
+type Parameter struct {
+	Name     string `json:"name"`
+	In       string `json:"in"`
+	Required bool   `json:"required"`
+	Schema   Schema `json:"schema"`
+}
+
+type Schema struct {
+	Type string `json:"type"`
+}
+
+type RequestBody struct {
+	Content map[string]MediaType `json:"content"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema,omitempty"`
+}
+
+type OperationResponse struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type Components struct {
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes"`
+}
+
+type SecurityScheme struct {
+	Type   string `json:"type"`
+	Scheme string `json:"scheme"`
+}
+
+// NewDocument returns the OpenAPI document describing the handlers. When more
+// than one handler is registered with the same path and method, the first one
+// is used.
+func NewDocument(handlers []Handler, version string) *Document {
+	doc := &Document{
+		OpenAPI: openAPIVersion,
+		Info: Info{
+			Title:       "tsuru",
+			Description: "Open source and extensible Platform as a Service (PaaS)",
+			Version:     version,
+		},
+		Paths: map[string]PathItem{},
+		Components: Components{
+			SecuritySchemes: map[string]SecurityScheme{
+				securitySchemeID: {Type: "http", Scheme: "bearer"},
+			},
+		},
+	}
+	for _, h := range handlers {
+		path := h.fullPath()
+		item, ok := doc.Paths[path]
+		if !ok {
+			item = PathItem{}
+			doc.Paths[path] = item
+		}
+		method := strings.ToLower(h.Method)
+		if _, ok := item[method]; ok {
+			continue
+		}
+		item[method] = h.operation()
+	}
+	return doc
+}
+
+func (h *Handler) fullPath() string {
+	path := pathParamRegexp.ReplaceAllString(h.Path, "{$1}")
+	if h.Version == "" {
+		return path
+	}
+	return "/" + h.Version + path
+}
+
+func (h *Handler) operation() *Operation {
+	op := &Operation{
+		OperationID: h.Name,
+		Summary:     h.Title,
+		Responses:   map[string]OperationResponse{},
+	}
+	if h.Group != "" {
+		op.Tags = []string{h.Group}
+	}
+	for _, match := range pathParamRegexp.FindAllStringSubmatch(h.Path, -1) {
+		op.Parameters = append(op.Parameters, Parameter{
+			Name:     match[1],
+			In:       "path",
+			Required: true,
+			Schema:   Schema{Type: "string"},
+		})
+	}
+	if isMediaType(h.Consume) {
+		op.RequestBody = &RequestBody{
+			Content: map[string]MediaType{
+				h.Consume: {Schema: &Schema{Type: "object"}},
+			},
+		}
+	}
+	for _, r := range h.Responses {
+		resp := OperationResponse{Description: r.Description}
+		if r.Code >= 200 && r.Code < 300 && isMediaType(h.Produce) {
+			resp.Content = map[string]MediaType{h.Produce: {}}
+		}
+		op.Responses[strconv.Itoa(r.Code)] = resp
+	}
+	if !h.Public {
+		op.Security = []map[string][]string{{securitySchemeID: {}}}
+	}
+	return op
+}
+
+// isMediaType returns whether value is a media type, annotations may also
+// describe the content in plain text, e.g. "Websocket connection upgrade".
+func isMediaType(value string) bool {
+	return value != "" && !strings.Contains(value, " ") && strings.Contains(value, "/")
+}
+
+// SortHandlers sorts the handlers by group, path, method and name, so that
+// generated code is stable.
+func SortHandlers(handlers []Handler) {
+	sort.SliceStable(handlers, func(i, j int) bool {
+		if handlers[i].Group != handlers[j].Group {
+			return handlers[i].Group < handlers[j].Group
+		}
+		if handlers[i].Path != handlers[j].Path {
+			return handlers[i].Path < handlers[j].Path
+		}
+		if handlers[i].Method != handlers[j].Method {
+			return handlers[i].Method < handlers[j].Method
+		}
+		return handlers[i].Name < handlers[j].Name
+	})
+}
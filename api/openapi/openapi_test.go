@@ -0,0 +1,178 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package openapi
+
+import (
+	"testing"
+
+	check "gopkg.in/check.v1"
+)
+
+type S struct{}
+
+var _ = check.Suite(&S{})
+
+func Test(t *testing.T) {
+	check.TestingT(t)
+}
+
+func (s *S) TestParse(c *check.C) {
+	handlers, err := Parse("testdata")
+	c.Assert(err, check.IsNil)
+	c.Assert(handlers, check.DeepEquals, []Handler{
+		{
+			Name:    "appInfo",
+			Group:   "handlers",
+			Title:   "app info",
+			Path:    "/apps/{app}",
+			Method:  "GET",
+			Produce: "application/json",
+			Version: "1.0",
+			Responses: []Response{
+				{Code: 200, Description: "OK"},
+				{Code: 404, Description: "Not found"},
+			},
+		},
+		{
+			Name:    "runCommand",
+			Group:   "handlers",
+			Title:   "run command",
+			Path:    "/apps/{app}/run",
+			Method:  "POST",
+			Consume: "application/x-www-form-urlencoded",
+			Produce: "application/x-json-stream",
+			Version: "1.13",
+			Responses: []Response{
+				{Code: 200, Description: "OK"},
+				{Code: 401, Description: "Unauthorized"},
+			},
+		},
+		{
+			Name:      "healthcheck",
+			Group:     "handlers",
+			Title:     "healthcheck",
+			Path:      "/healthcheck",
+			Method:    "GET",
+			Version:   "1.0",
+			Public:    true,
+			Responses: []Response{{Code: 200, Description: "OK"}},
+		},
+		{
+			Name:      "nodeInfo",
+			Group:     "handlers",
+			Title:     "node info",
+			Path:      "/node/{address:.*}",
+			Method:    "GET",
+			Produce:   "application/json",
+			Version:   "1.2",
+			Responses: []Response{{Code: 200, Description: "OK"}},
+		},
+		{
+			Name:      "unregistered",
+			Group:     "handlers",
+			Title:     "unregistered handler",
+			Path:      "/unregistered",
+			Method:    "DELETE",
+			Responses: []Response{{Code: 200, Description: "OK"}},
+		},
+	})
+}
+
+func (s *S) TestParseAnnotationsMissingPath(c *check.C) {
+	_, err := parseAnnotations("title: app info\nmethod: GET\n")
+	c.Assert(err, check.ErrorMatches, "path and method are required")
+}
+
+func (s *S) TestNewDocument(c *check.C) {
+	handlers := []Handler{
+		{
+			Name:    "runCommand",
+			Group:   "app",
+			Title:   "run command",
+			Path:    "/apps/{app}/run",
+			Method:  "POST",
+			Consume: "application/x-www-form-urlencoded",
+			Produce: "application/x-json-stream",
+			Version: "1.13",
+			Responses: []Response{
+				{Code: 200, Description: "OK"},
+				{Code: 404, Description: "App not found"},
+			},
+		},
+		{
+			Name:      "remoteShellHandler",
+			Group:     "shell",
+			Title:     "app shell",
+			Path:      "/apps/{appname}/shell",
+			Method:    "GET",
+			Produce:   "Websocket connection upgrade",
+			Version:   "1.0",
+			Public:    true,
+			Responses: []Response{{Code: 200, Description: "OK"}},
+		},
+		{
+			Name:      "nodeInfo",
+			Group:     "node",
+			Title:     "node info",
+			Path:      "/node/{address:.*}",
+			Method:    "GET",
+			Version:   "1.2",
+			Responses: []Response{{Code: 200, Description: "OK"}},
+		},
+		{
+			Name:      "otherRunCommand",
+			Title:     "duplicated",
+			Path:      "/apps/{app}/run",
+			Method:    "POST",
+			Version:   "1.13",
+			Responses: []Response{{Code: 200, Description: "OK"}},
+		},
+	}
+	doc := NewDocument(handlers, "1.13.0")
+	c.Assert(doc.OpenAPI, check.Equals, "3.0.3")
+	c.Assert(doc.Info.Version, check.Equals, "1.13.0")
+	c.Assert(doc.Components.SecuritySchemes, check.DeepEquals, map[string]SecurityScheme{
+		"bearerAuth": {Type: "http", Scheme: "bearer"},
+	})
+	c.Assert(doc.Paths, check.HasLen, 3)
+	c.Assert(doc.Paths["/1.13/apps/{app}/run"], check.DeepEquals, PathItem{
+		"post": {
+			OperationID: "runCommand",
+			Summary:     "run command",
+			Tags:        []string{"app"},
+			Parameters: []Parameter{
+				{Name: "app", In: "path", Required: true, Schema: Schema{Type: "string"}},
+			},
+			RequestBody: &RequestBody{
+				Content: map[string]MediaType{
+					"application/x-www-form-urlencoded": {Schema: &Schema{Type: "object"}},
+				},
+			},
+			Responses: map[string]OperationResponse{
+				"200": {Description: "OK", Content: map[string]MediaType{"application/x-json-stream": {}}},
+				"404": {Description: "App not found"},
+			},
+			Security: []map[string][]string{{"bearerAuth": {}}},
+		},
+	})
+	c.Assert(doc.Paths["/1.0/apps/{appname}/shell"], check.DeepEquals, PathItem{
+		"get": {
+			OperationID: "remoteShellHandler",
+			Summary:     "app shell",
+			Tags:        []string{"shell"},
+			Parameters: []Parameter{
+				{Name: "appname", In: "path", Required: true, Schema: Schema{Type: "string"}},
+			},
+			Responses: map[string]OperationResponse{
+				"200": {Description: "OK"},
+			},
+		},
+	})
+	nodeInfo := doc.Paths["/1.2/node/{address}"]["get"]
+	c.Assert(nodeInfo, check.NotNil)
+	c.Assert(nodeInfo.Parameters, check.DeepEquals, []Parameter{
+		{Name: "address", In: "path", Required: true, Schema: Schema{Type: "string"}},
+	})
+}
@@ -0,0 +1,216 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package openapi
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// publicWrappers are the functions wrapping handlers that don't require
+// authentication when they're registered in the router.
+var publicWrappers = map[string]bool{
+	"Handler":           true,
+	"http.HandlerFunc":  true,
+	"websocket.Handler": true,
+}
+
+type route struct {
+	version string
+	method  string
+	path    string
+	public  bool
+}
+
+// Parse returns the annotated handlers declared in the Go package in dir,
+// along with the version of the route they're registered in. Handlers not
+// registered in the package keep the path from their annotations.
+func Parse(dir string) ([]Handler, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	var handlers []Handler
+	routes := map[string][]route{}
+	for _, pkg := range pkgs {
+		for fileName, file := range pkg.Files {
+			group := strings.TrimSuffix(filepath.Base(fileName), ".go")
+			for _, decl := range file.Decls {
+				fn, ok := decl.(*ast.FuncDecl)
+				if !ok || fn.Recv != nil || fn.Doc == nil {
+					continue
+				}
+				h, err := parseAnnotations(fn.Doc.Text())
+				if err != nil {
+					return nil, errors.Wrapf(err, "invalid annotations in handler %s", fn.Name.Name)
+				}
+				if h == nil {
+					continue
+				}
+				h.Name = fn.Name.Name
+				h.Group = group
+				handlers = append(handlers, *h)
+			}
+			ast.Inspect(file, func(n ast.Node) bool {
+				call, ok := n.(*ast.CallExpr)
+				if !ok {
+					return true
+				}
+				if name, r, ok := parseRoute(call); ok {
+					routes[name] = append(routes[name], r)
+				}
+				return true
+			})
+		}
+	}
+	for i := range handlers {
+		h := &handlers[i]
+		for _, r := range routes[h.Name] {
+			if r.method != "" && r.method != h.Method {
+				continue
+			}
+			h.Version = r.version
+			h.Path = r.path
+			h.Public = r.public
+			break
+		}
+	}
+	SortHandlers(handlers)
+	return handlers, nil
+}
+
+// parseAnnotations parses the annotations in the doc comment of a function,
+// it returns nil when the function is not an annotated handler.
+func parseAnnotations(doc string) (*Handler, error) {
+	var h Handler
+	var inResponses bool
+	for _, line := range strings.Split(doc, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		key, value := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+		if inResponses {
+			if code, err := strconv.Atoi(key); err == nil {
+				h.Responses = append(h.Responses, Response{Code: code, Description: value})
+				continue
+			}
+			inResponses = false
+		}
+		switch key {
+		case "title":
+			h.Title = value
+		case "path":
+			h.Path = value
+		case "method":
+			h.Method = strings.ToUpper(value)
+		case "consume":
+			h.Consume = value
+		case "produce":
+			h.Produce = value
+		case "responses":
+			inResponses = true
+		}
+	}
+	if h.Title == "" {
+		return nil, nil
+	}
+	if h.Path == "" || h.Method == "" {
+		return nil, errors.New("path and method are required")
+	}
+	return &h, nil
+}
+
+// parseRoute parses calls registering handlers in the router, like:
+//
+//	m.Add("1.0", http.MethodGet, "/apps", AuthorizationRequiredHandler(appList))
+func parseRoute(call *ast.CallExpr) (string, route, bool) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return "", route{}, false
+	}
+	var args []ast.Expr
+	switch sel.Sel.Name {
+	case "Add":
+		args = call.Args
+	case "AddNamed":
+		if len(call.Args) > 0 {
+			args = call.Args[1:]
+		}
+	case "AddAll":
+		if len(call.Args) == 3 {
+			args = []ast.Expr{call.Args[0], nil, call.Args[1], call.Args[2]}
+		}
+	}
+	if len(args) != 4 {
+		return "", route{}, false
+	}
+	var r route
+	if r.version, ok = stringLiteral(args[0]); !ok {
+		return "", route{}, false
+	}
+	if args[1] != nil {
+		if r.method, ok = httpMethod(args[1]); !ok {
+			return "", route{}, false
+		}
+	}
+	if r.path, ok = stringLiteral(args[2]); !ok {
+		return "", route{}, false
+	}
+	wrapper, ok := args[3].(*ast.CallExpr)
+	if !ok || len(wrapper.Args) != 1 {
+		return "", route{}, false
+	}
+	handler, ok := wrapper.Args[0].(*ast.Ident)
+	if !ok {
+		return "", route{}, false
+	}
+	r.public = publicWrappers[exprName(wrapper.Fun)]
+	return handler.Name, r, true
+}
+
+func stringLiteral(expr ast.Expr) (string, bool) {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	value, err := strconv.Unquote(lit.Value)
+	return value, err == nil
+}
+
+func httpMethod(expr ast.Expr) (string, bool) {
+	if value, ok := stringLiteral(expr); ok {
+		return strings.ToUpper(value), true
+	}
+	name := exprName(expr)
+	if !strings.HasPrefix(name, "http.Method") {
+		return "", false
+	}
+	return strings.ToUpper(strings.TrimPrefix(name, "http.Method")), true
+}
+
+func exprName(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return e.Name
+	case *ast.SelectorExpr:
+		return exprName(e.X) + "." + e.Sel.Name
+	}
+	return ""
+}
@@ -0,0 +1,70 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package testdata
+
+import "net/http"
+
+func routes(m router) {
+	m.Add("1.0", http.MethodGet, "/apps/{app}", AuthorizationRequiredHandler(appInfo))
+	m.Add("1.13", "POST", "/apps/{app}/run", AuthorizationRequiredHandler(runCommand))
+	m.Add("1.0", http.MethodGet, "/healthcheck", http.HandlerFunc(healthcheck))
+	m.Add("1.2", http.MethodGet, "/node/{address:.*}", AuthorizationRequiredHandler(nodeInfo))
+}
+
+// title: app info
+// path: /apps/{app}
+// method: GET
+// produce: application/json
+// responses:
+//   200: OK
+//   404: Not found
+func appInfo(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	return nil
+}
+
+// runCommand runs a command in the app units.
+//
+// title: run command
+// path: /apps/{app}/run
+// method: POST
+// consume: application/x-www-form-urlencoded
+// produce: application/x-json-stream
+// responses:
+//   200: OK
+//	401: Unauthorized
+func runCommand(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	return nil
+}
+
+// title: healthcheck
+// path: /healthcheck
+// method: GET
+// responses:
+//   200: OK
+func healthcheck(w http.ResponseWriter, r *http.Request) {
+}
+
+// title: node info
+// path: /node/{address}
+// method: GET
+// produce: application/json
+// responses:
+//   200: OK
+func nodeInfo(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	return nil
+}
+
+// title: unregistered handler
+// path: /unregistered
+// method: DELETE
+// responses:
+//   200: OK
+func unregistered(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	return nil
+}
+
+// notAHandler is not annotated.
+func notAHandler() {
+}
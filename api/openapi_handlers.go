@@ -0,0 +1,3925 @@
+// AUTOMATICALLY GENERATED FILE - DO NOT EDIT!
+// Please run 'go generate' to update this file.
+//
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import "github.com/tsuru/tsuru/api/openapi"
+
+var openAPIHandlers = []openapi.Handler{
+	{
+		Name:    "setTeamAllowlist",
+		Group:   "allowlist",
+		Title:   "set team ip allowlist",
+		Path:    "/teams/{name}/allowlist",
+		Method:  "PUT",
+		Consume: "application/x-www-form-urlencoded",
+		Produce: "application/json",
+		Version: "1.13",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Allowlist updated"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Team not found"},
+		},
+	},
+	{
+		Name:    "setTokenAllowlist",
+		Group:   "allowlist",
+		Title:   "set token ip allowlist",
+		Path:    "/tokens/{token_id}/allowlist",
+		Method:  "PUT",
+		Consume: "application/x-www-form-urlencoded",
+		Produce: "application/json",
+		Version: "1.13",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Allowlist updated"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Token not found"},
+		},
+	},
+	{
+		Name:    "appList",
+		Group:   "app",
+		Title:   "app list",
+		Path:    "/apps",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "List apps"},
+			{Code: 204, Description: "No content"},
+			{Code: 304, Description: "Not modified"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+		},
+	},
+	{
+		Name:    "createApp",
+		Group:   "app",
+		Title:   "app create",
+		Path:    "/apps",
+		Method:  "POST",
+		Consume: "application/x-www-form-urlencoded",
+		Produce: "application/json",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 201, Description: "App created"},
+			{Code: 202, Description: "App creation started"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 403, Description: "Quota exceeded"},
+			{Code: 409, Description: "App already exists"},
+		},
+	},
+	{
+		Name:    "importApp",
+		Group:   "app",
+		Title:   "app import",
+		Path:    "/apps/import",
+		Method:  "POST",
+		Consume: "application/json",
+		Produce: "application/x-json-stream",
+		Version: "1.13",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "App imported"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 409, Description: "App already exists"},
+		},
+	},
+	{
+		Name:    "appDelete",
+		Group:   "app",
+		Title:   "remove app",
+		Path:    "/apps/{app}",
+		Method:  "DELETE",
+		Produce: "application/x-json-stream",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "App removed"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Not found"},
+		},
+	},
+	{
+		Name:    "appInfo",
+		Group:   "app",
+		Title:   "app info",
+		Path:    "/apps/{app}",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Not found"},
+		},
+	},
+	{
+		Name:    "updateApp",
+		Group:   "app",
+		Title:   "app update",
+		Path:    "/apps/{app}",
+		Method:  "PUT",
+		Consume: "application/x-www-form-urlencoded",
+		Produce: "application/x-json-stream",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "App updated"},
+			{Code: 400, Description: "Invalid new pool"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Not found"},
+		},
+	},
+	{
+		Name:    "unsetCertificate",
+		Group:   "app",
+		Title:   "unset app certificate",
+		Path:    "/apps/{app}/certificate",
+		Method:  "DELETE",
+		Consume: "application/x-www-form-urlencoded",
+		Version: "1.2",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "App not found"},
+		},
+	},
+	{
+		Name:    "listCertificates",
+		Group:   "app",
+		Title:   "list app certificates",
+		Path:    "/apps/{app}/certificate",
+		Method:  "GET",
+		Consume: "application/x-www-form-urlencoded",
+		Version: "1.2",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "App not found"},
+		},
+	},
+	{
+		Name:    "setCertificate",
+		Group:   "app",
+		Title:   "set app certificate",
+		Path:    "/apps/{app}/certificate",
+		Method:  "PUT",
+		Consume: "application/x-www-form-urlencoded",
+		Version: "1.2",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "App not found"},
+		},
+	},
+	{
+		Name:    "unsetCName",
+		Group:   "app",
+		Title:   "unset cname",
+		Path:    "/apps/{app}/cname",
+		Method:  "DELETE",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "App not found"},
+		},
+	},
+	{
+		Name:    "setCName",
+		Group:   "app",
+		Title:   "set cname",
+		Path:    "/apps/{app}/cname",
+		Method:  "POST",
+		Consume: "application/x-www-form-urlencoded",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "App not found"},
+		},
+	},
+	{
+		Name:    "unsetEnv",
+		Group:   "app",
+		Title:   "unset envs",
+		Path:    "/apps/{app}/env",
+		Method:  "DELETE",
+		Produce: "application/x-json-stream",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Envs removed"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "App not found"},
+		},
+	},
+	{
+		Name:    "getEnv",
+		Group:   "app",
+		Title:   "get envs",
+		Path:    "/apps/{app}/env",
+		Method:  "GET",
+		Produce: "application/x-json-stream",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "App not found"},
+		},
+	},
+	{
+		Name:    "setEnv",
+		Group:   "app",
+		Title:   "set envs",
+		Path:    "/apps/{app}/env",
+		Method:  "POST",
+		Consume: "application/json",
+		Produce: "application/x-json-stream",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Envs updated"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "App not found"},
+		},
+	},
+	{
+		Name:    "exportApp",
+		Group:   "app",
+		Title:   "app export",
+		Path:    "/apps/{app}/export",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.13",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "App not found"},
+		},
+	},
+	{
+		Name:    "unfreezeApp",
+		Group:   "app",
+		Title:   "unfreeze app",
+		Path:    "/apps/{app}/freeze",
+		Method:  "DELETE",
+		Version: "1.13",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "App unfrozen"},
+			{Code: 400, Description: "App not frozen"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "App not found"},
+		},
+	},
+	{
+		Name:    "freezeApp",
+		Group:   "app",
+		Title:   "freeze app",
+		Path:    "/apps/{app}/freeze",
+		Method:  "POST",
+		Consume: "application/x-www-form-urlencoded",
+		Produce: "application/json",
+		Version: "1.13",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "App frozen"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "App not found"},
+		},
+	},
+	{
+		Name:    "forceDeleteLock",
+		Group:   "app",
+		Title:   "app unlock",
+		Path:    "/apps/{app}/lock",
+		Method:  "DELETE",
+		Produce: "application/json",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 410, Description: "Not available anymore"},
+		},
+	},
+	{
+		Name:    "appLog",
+		Group:   "app",
+		Title:   "app log",
+		Path:    "/apps/{app}/log",
+		Method:  "GET",
+		Produce: "application/x-json-stream",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "App not found"},
+		},
+	},
+	{
+		Name:    "addLog",
+		Group:   "app",
+		Title:   "app log",
+		Path:    "/apps/{app}/log",
+		Method:  "POST",
+		Consume: "application/x-www-form-urlencoded",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "App not found"},
+		},
+	},
+	{
+		Name:    "unsetAppMaintenance",
+		Group:   "app",
+		Title:   "unset app maintenance",
+		Path:    "/apps/{app}/maintenance",
+		Method:  "DELETE",
+		Produce: "application/x-json-stream",
+		Version: "1.13",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Maintenance mode disabled"},
+			{Code: 400, Description: "App not in maintenance"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "App not found"},
+		},
+	},
+	{
+		Name:    "setAppMaintenance",
+		Group:   "app",
+		Title:   "set app maintenance",
+		Path:    "/apps/{app}/maintenance",
+		Method:  "POST",
+		Consume: "application/x-www-form-urlencoded",
+		Produce: "application/x-json-stream",
+		Version: "1.13",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Maintenance mode enabled"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "App not found"},
+		},
+	},
+	{
+		Name:    "appMetricEnvs",
+		Group:   "app",
+		Title:   "metric envs",
+		Path:    "/apps/{app}/metric/envs",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "App not found"},
+		},
+	},
+	{
+		Name:    "removeAppExtraPool",
+		Group:   "app",
+		Title:   "remove app extra pool",
+		Path:    "/apps/{app}/pools/{pool}",
+		Method:  "DELETE",
+		Produce: "application/x-json-stream",
+		Version: "1.13",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Extra pool removed"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "App or extra pool not found"},
+		},
+	},
+	{
+		Name:    "setAppExtraPool",
+		Group:   "app",
+		Title:   "set app extra pool",
+		Path:    "/apps/{app}/pools/{pool}",
+		Method:  "PUT",
+		Consume: "application/x-www-form-urlencoded",
+		Produce: "application/x-json-stream",
+		Version: "1.13",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Extra pool set"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "App or pool not found"},
+		},
+	},
+	{
+		Name:    "renameApp",
+		Group:   "app",
+		Title:   "app rename",
+		Path:    "/apps/{app}/rename",
+		Method:  "POST",
+		Consume: "application/x-www-form-urlencoded",
+		Produce: "application/x-json-stream",
+		Version: "1.13",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "App renamed"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "App not found"},
+			{Code: 409, Description: "App already exists"},
+		},
+	},
+	{
+		Name:    "appResourceRecommendations",
+		Group:   "app",
+		Title:   "app resource recommendations",
+		Path:    "/apps/{app}/resources/recommendations",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.13",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "App not found"},
+		},
+	},
+	{
+		Name:    "restart",
+		Group:   "app",
+		Title:   "app restart",
+		Path:    "/apps/{app}/restart",
+		Method:  "POST",
+		Consume: "application/x-www-form-urlencoded",
+		Produce: "application/x-json-stream",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "App not found"},
+		},
+	},
+	{
+		Name:    "appRebuildRoutes",
+		Group:   "app",
+		Title:   "rebuild routes",
+		Path:    "/apps/{app}/routes",
+		Method:  "POST",
+		Produce: "application/json",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "App not found"},
+		},
+	},
+	{
+		Name:    "runCommand",
+		Group:   "app",
+		Title:   "run commands",
+		Path:    "/apps/{app}/run",
+		Method:  "POST",
+		Consume: "application/x-www-form-urlencoded",
+		Produce: "application/x-json-stream",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 202, Description: "Detached run started"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "App not found"},
+		},
+	},
+	{
+		Name:    "runInfo",
+		Group:   "app",
+		Title:   "run info",
+		Path:    "/apps/{app}/runs/{id}",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.13",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Run not found"},
+		},
+	},
+	{
+		Name:    "sleep",
+		Group:   "app",
+		Title:   "app sleep",
+		Path:    "/apps/{app}/sleep",
+		Method:  "POST",
+		Consume: "application/x-www-form-urlencoded",
+		Produce: "application/x-json-stream",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "App not found"},
+		},
+	},
+	{
+		Name:    "start",
+		Group:   "app",
+		Title:   "app start",
+		Path:    "/apps/{app}/start",
+		Method:  "POST",
+		Consume: "application/x-www-form-urlencoded",
+		Produce: "application/x-json-stream",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "App not found"},
+		},
+	},
+	{
+		Name:    "stop",
+		Group:   "app",
+		Title:   "app stop",
+		Path:    "/apps/{app}/stop",
+		Method:  "POST",
+		Consume: "application/x-www-form-urlencoded",
+		Produce: "application/x-json-stream",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "App not found"},
+		},
+	},
+	{
+		Name:    "revokeAppAccess",
+		Group:   "app",
+		Title:   "revoke access to app",
+		Path:    "/apps/{app}/teams/{team}",
+		Method:  "DELETE",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Access revoked"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 403, Description: "Forbidden"},
+			{Code: 404, Description: "App or team not found"},
+		},
+	},
+	{
+		Name:    "grantAppAccess",
+		Group:   "app",
+		Title:   "grant access to app",
+		Path:    "/apps/{app}/teams/{team}",
+		Method:  "PUT",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Access granted"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "App or team not found"},
+			{Code: 409, Description: "Grant already exists"},
+		},
+	},
+	{
+		Name:    "appTimeline",
+		Group:   "app",
+		Title:   "app timeline",
+		Path:    "/apps/{app}/timeline",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.13",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+			{Code: 204, Description: "No content"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "App not found"},
+		},
+	},
+	{
+		Name:    "removeUnits",
+		Group:   "app",
+		Title:   "remove units",
+		Path:    "/apps/{app}/units",
+		Method:  "DELETE",
+		Produce: "application/x-json-stream",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Units removed"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 403, Description: "Not enough reserved units"},
+			{Code: 404, Description: "App not found"},
+		},
+	},
+	{
+		Name:    "addUnits",
+		Group:   "app",
+		Title:   "add units",
+		Path:    "/apps/{app}/units",
+		Method:  "PUT",
+		Consume: "application/x-www-form-urlencoded",
+		Produce: "application/x-json-stream",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Units added"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "App not found"},
+		},
+	},
+	{
+		Name:    "unitsMetrics",
+		Group:   "app",
+		Title:   "units metrics",
+		Path:    "/apps/{app}/units/metrics",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.13",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "App not found"},
+		},
+	},
+	{
+		Name:    "registerUnit",
+		Group:   "app",
+		Title:   "register unit",
+		Path:    "/apps/{app}/units/register",
+		Method:  "POST",
+		Consume: "application/x-www-form-urlencoded",
+		Produce: "application/json",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "App not found"},
+		},
+	},
+	{
+		Name:    "killUnit",
+		Group:   "app",
+		Title:   "kill a running unit",
+		Path:    "/apps/{app}/units/{unit}",
+		Method:  "DELETE",
+		Consume: "application/x-www-form-urlencoded",
+		Version: "1.12",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "App or unit not found"},
+		},
+	},
+	{
+		Name:    "setUnitStatus",
+		Group:   "app",
+		Title:   "set unit status",
+		Path:    "/apps/{app}/units/{unit}",
+		Method:  "POST",
+		Consume: "application/x-www-form-urlencoded",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "App or unit not found"},
+		},
+	},
+	{
+		Name:    "debugUnit",
+		Group:   "app",
+		Title:   "unit debug",
+		Path:    "/apps/{app}/units/{unit}/debug",
+		Method:  "POST",
+		Produce: "application/vnd.tsuru.raw-stream",
+		Version: "1.13",
+		Responses: []openapi.Response{
+			{Code: 101, Description: "Switch protocol to raw stream"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "App or unit not found"},
+		},
+	},
+	{
+		Name:    "restartUnit",
+		Group:   "app",
+		Title:   "unit restart",
+		Path:    "/apps/{app}/units/{unit}/restart",
+		Method:  "POST",
+		Produce: "application/x-json-stream",
+		Version: "1.13",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "App or unit not found"},
+		},
+	},
+	{
+		Name:    "appVersionDelete",
+		Group:   "app",
+		Title:   "app version delete",
+		Path:    "/apps/{app}/versions/{version}",
+		Method:  "DELETE",
+		Consume: "application/x-www-form-urlencoded",
+		Produce: "application/x-json-stream",
+		Version: "1.10",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "App not found"},
+			{Code: 404, Description: "Version not found"},
+		},
+	},
+	{
+		Name:    "setNodeStatus",
+		Group:   "app",
+		Title:   "set node status",
+		Path:    "/node/status",
+		Method:  "POST",
+		Consume: "application/x-www-form-urlencoded",
+		Produce: "application/json",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "App or unit not found"},
+		},
+	},
+	{
+		Name:    "unbindServiceInstance",
+		Group:   "app",
+		Title:   "unbind service instance",
+		Path:    "/services/{service}/instances/{instance}/{app}",
+		Method:  "DELETE",
+		Produce: "application/x-json-stream",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "App not found"},
+		},
+	},
+	{
+		Name:    "bindServiceInstance",
+		Group:   "app",
+		Title:   "bind service instance",
+		Path:    "/services/{service}/instances/{instance}/{app}",
+		Method:  "PUT",
+		Consume: "application/x-www-form-urlencoded",
+		Produce: "application/x-json-stream",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "App not found"},
+		},
+	},
+	{
+		Name:    "swap",
+		Group:   "app",
+		Title:   "app swap",
+		Path:    "/swap",
+		Method:  "POST",
+		Consume: "application/x-www-form-urlencoded",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "App not found"},
+			{Code: 409, Description: "App locked"},
+			{Code: 412, Description: "Number of units or platform don't match"},
+		},
+	},
+	{
+		Name:    "auditList",
+		Group:   "audit",
+		Title:   "audit list",
+		Path:    "/audit",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.13",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+			{Code: 204, Description: "No content"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+		},
+	},
+	{
+		Name:    "login",
+		Group:   "auth",
+		Title:   "login",
+		Path:    "/auth/login",
+		Method:  "POST",
+		Consume: "application/x-www-form-urlencoded",
+		Produce: "application/json",
+		Version: "1.0",
+		Public:  true,
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 403, Description: "Forbidden"},
+			{Code: 404, Description: "Not found"},
+		},
+	},
+	{
+		Name:    "authScheme",
+		Group:   "auth",
+		Title:   "get auth scheme",
+		Path:    "/auth/scheme",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.0",
+		Public:  true,
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+		},
+	},
+	{
+		Name:    "teamList",
+		Group:   "auth",
+		Title:   "team list",
+		Path:    "/teams",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "List teams"},
+			{Code: 204, Description: "No content"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+		},
+	},
+	{
+		Name:    "createTeam",
+		Group:   "auth",
+		Title:   "team create",
+		Path:    "/teams",
+		Method:  "POST",
+		Consume: "application/x-www-form-urlencoded",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 201, Description: "Team created"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 409, Description: "Team already exists"},
+		},
+	},
+	{
+		Name:    "removeTeam",
+		Group:   "auth",
+		Title:   "remove team",
+		Path:    "/teams/{name}",
+		Method:  "DELETE",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Team removed"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 403, Description: "Forbidden"},
+			{Code: 404, Description: "Not found"},
+		},
+	},
+	{
+		Name:    "teamInfo",
+		Group:   "auth",
+		Title:   "team info",
+		Path:    "/teams/{name}",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.4",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Info team"},
+			{Code: 404, Description: "Not found"},
+			{Code: 401, Description: "Unauthorized"},
+		},
+	},
+	{
+		Name:    "updateTeam",
+		Group:   "auth",
+		Title:   "team update",
+		Path:    "/teams/{name}",
+		Method:  "PUT",
+		Consume: "application/x-www-form-urlencoded",
+		Version: "1.6",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Team updated"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Team not found"},
+		},
+	},
+	{
+		Name:    "setTeamParent",
+		Group:   "auth",
+		Title:   "set team parent",
+		Path:    "/teams/{name}/parent",
+		Method:  "PUT",
+		Consume: "application/x-www-form-urlencoded",
+		Version: "1.13",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Team parent updated"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Team not found"},
+		},
+	},
+	{
+		Name:    "removeUser",
+		Group:   "auth",
+		Title:   "remove user",
+		Path:    "/users",
+		Method:  "DELETE",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "User removed"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Not found"},
+		},
+	},
+	{
+		Name:    "listUsers",
+		Group:   "auth",
+		Title:   "user list",
+		Path:    "/users",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+		},
+	},
+	{
+		Name:    "createUser",
+		Group:   "auth",
+		Title:   "user create",
+		Path:    "/users",
+		Method:  "POST",
+		Consume: "application/x-www-form-urlencoded",
+		Version: "1.0",
+		Public:  true,
+		Responses: []openapi.Response{
+			{Code: 201, Description: "User created"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 403, Description: "Forbidden"},
+			{Code: 409, Description: "User already exists"},
+		},
+	},
+	{
+		Name:    "showAPIToken",
+		Group:   "auth",
+		Title:   "show token",
+		Path:    "/users/api-key",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "User not found"},
+		},
+	},
+	{
+		Name:    "regenerateAPIToken",
+		Group:   "auth",
+		Title:   "regenerate token",
+		Path:    "/users/api-key",
+		Method:  "POST",
+		Produce: "application/json",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "User not found"},
+		},
+	},
+	{
+		Name:    "userInfo",
+		Group:   "auth",
+		Title:   "user info",
+		Path:    "/users/info",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+			{Code: 401, Description: "Unauthorized"},
+		},
+	},
+	{
+		Name:    "changePassword",
+		Group:   "auth",
+		Title:   "change password",
+		Path:    "/users/password",
+		Method:  "PUT",
+		Consume: "application/x-www-form-urlencoded",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 403, Description: "Forbidden"},
+			{Code: 404, Description: "Not found"},
+		},
+	},
+	{
+		Name:    "logout",
+		Group:   "auth",
+		Title:   "logout",
+		Path:    "/users/tokens",
+		Method:  "DELETE",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+		},
+	},
+	{
+		Name:    "resetPassword",
+		Group:   "auth",
+		Title:   "reset password",
+		Path:    "/users/{email}/password",
+		Method:  "POST",
+		Version: "1.0",
+		Public:  true,
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 403, Description: "Forbidden"},
+			{Code: 404, Description: "Not found"},
+		},
+	},
+	{
+		Name:    "autoScaleHistoryHandler",
+		Group:   "autoscale",
+		Title:   "list autoscale history",
+		Path:    "/node/autoscale",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.3",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 204, Description: "No content"},
+			{Code: 401, Description: "Unauthorized"},
+		},
+	},
+	{
+		Name:    "autoScaleGetConfig",
+		Group:   "autoscale",
+		Title:   "get autoscale config",
+		Path:    "/node/autoscale/config",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.3",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 401, Description: "Unauthorized"},
+		},
+	},
+	{
+		Name:    "autoScaleDeleteRule",
+		Group:   "autoscale",
+		Title:   "delete autoscale rule",
+		Path:    "/node/autoscale/rules",
+		Method:  "DELETE",
+		Version: "1.3",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Not found"},
+		},
+	},
+	{
+		Name:    "autoScaleListRules",
+		Group:   "autoscale",
+		Title:   "autoscale rules list",
+		Path:    "/node/autoscale/rules",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.3",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 204, Description: "No content"},
+			{Code: 401, Description: "Unauthorized"},
+		},
+	},
+	{
+		Name:    "autoScaleSetRule",
+		Group:   "autoscale",
+		Title:   "autoscale set rule",
+		Path:    "/node/autoscale/rules",
+		Method:  "POST",
+		Consume: "application/x-www-form-urlencoded",
+		Version: "1.3",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+		},
+	},
+	{
+		Name:    "autoScaleRunHandler",
+		Group:   "autoscale",
+		Title:   "autoscale run",
+		Path:    "/node/autoscale/run",
+		Method:  "POST",
+		Produce: "application/x-json-stream",
+		Version: "1.3",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 401, Description: "Unauthorized"},
+		},
+	},
+	{
+		Name:    "batch",
+		Group:   "batch",
+		Title:   "batch",
+		Path:    "/batch",
+		Method:  "POST",
+		Consume: "application/json",
+		Produce: "application/json",
+		Version: "1.13",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Operations executed"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+		},
+	},
+	{
+		Name:    "build",
+		Group:   "build",
+		Title:   "app build",
+		Path:    "/apps/{appname}/build",
+		Method:  "POST",
+		Consume: "application/x-www-form-urlencoded",
+		Version: "1.5",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 403, Description: "Forbidden"},
+			{Code: 404, Description: "Not found"},
+		},
+	},
+	{
+		Name:    "provisionerList",
+		Group:   "cluster",
+		Title:   "list provisioners",
+		Path:    "/provisioner",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.7",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 204, Description: "No Content"},
+			{Code: 401, Description: "Unauthorized"},
+		},
+	},
+	{
+		Name:    "listClusters",
+		Group:   "cluster",
+		Title:   "list provisioner clusters",
+		Path:    "/provisioner/clusters",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.3",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 204, Description: "No Content"},
+			{Code: 401, Description: "Unauthorized"},
+		},
+	},
+	{
+		Name:    "createCluster",
+		Group:   "cluster",
+		Title:   "create provisioner cluster",
+		Path:    "/provisioner/clusters",
+		Method:  "POST",
+		Consume: "application/json",
+		Produce: "application/x-json-stream",
+		Version: "1.3",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Pool does not exist"},
+			{Code: 409, Description: "Cluster already exists"},
+		},
+	},
+	{
+		Name:    "deleteCluster",
+		Group:   "cluster",
+		Title:   "delete provisioner cluster",
+		Path:    "/provisioner/clusters/{name}",
+		Method:  "DELETE",
+		Produce: "application/x-json-stream",
+		Version: "1.3",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Cluster not found"},
+		},
+	},
+	{
+		Name:    "clusterInfo",
+		Group:   "cluster",
+		Title:   "provisioner cluster info",
+		Path:    "/provisioner/clusters/{name}",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.8",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Cluster not found"},
+		},
+	},
+	{
+		Name:    "updateCluster",
+		Group:   "cluster",
+		Title:   "update provisioner cluster",
+		Path:    "/provisioner/clusters/{name}",
+		Method:  "POST",
+		Consume: "application/json",
+		Produce: "application/x-json-stream",
+		Version: "1.4",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Cluster not found"},
+		},
+	},
+	{
+		Name:    "clusterStatus",
+		Group:   "cluster",
+		Title:   "provisioner cluster status",
+		Path:    "/provisioner/clusters/{name}/status",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.13",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 400, Description: "Status not supported by the cluster provisioner"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Cluster not found"},
+		},
+	},
+	{
+		Name:    "dumpGoroutines",
+		Group:   "debug",
+		Title:   "dump goroutines",
+		Path:    "/debug/goroutines",
+		Method:  "GET",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+		},
+	},
+	{
+		Name:    "diffDeploy",
+		Group:   "deploy",
+		Title:   "deploy diff",
+		Path:    "/apps/{appname}/diff",
+		Method:  "POST",
+		Consume: "application/x-www-form-urlencoded",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 410, Description: "Gone"},
+		},
+	},
+	{
+		Name:    "deploy",
+		Group:   "deploy",
+		Title:   "app deploy",
+		Path:    "/apps/{appname}/repository/clone",
+		Method:  "POST",
+		Consume: "application/x-www-form-urlencoded",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+			{Code: 202, Description: "Deploy started"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 403, Description: "Forbidden"},
+			{Code: 404, Description: "Not found"},
+			{Code: 409, Description: "Timeout waiting for queued deploys"},
+		},
+	},
+	{
+		Name:    "deployCanaryAbort",
+		Group:   "deploy",
+		Title:   "abort canary deploy",
+		Path:    "/apps/{app}/deploy/canary/abort",
+		Method:  "POST",
+		Produce: "application/x-json-stream",
+		Version: "1.13",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+			{Code: 400, Description: "No canary deploy in progress"},
+			{Code: 403, Description: "Forbidden"},
+			{Code: 404, Description: "Not found"},
+		},
+	},
+	{
+		Name:    "deployCanaryPromote",
+		Group:   "deploy",
+		Title:   "promote canary deploy",
+		Path:    "/apps/{app}/deploy/canary/promote",
+		Method:  "POST",
+		Produce: "application/x-json-stream",
+		Version: "1.13",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+			{Code: 400, Description: "No canary deploy in progress"},
+			{Code: 403, Description: "Forbidden"},
+			{Code: 404, Description: "Not found"},
+		},
+	},
+	{
+		Name:    "deployRebuild",
+		Group:   "deploy",
+		Title:   "rebuild",
+		Path:    "/apps/{app}/deploy/rebuild",
+		Method:  "POST",
+		Consume: "application/x-www-form-urlencoded",
+		Produce: "application/x-json-stream",
+		Version: "1.3",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 403, Description: "Forbidden"},
+			{Code: 404, Description: "Not found"},
+		},
+	},
+	{
+		Name:    "deployRollback",
+		Group:   "deploy",
+		Title:   "rollback",
+		Path:    "/apps/{app}/deploy/rollback",
+		Method:  "POST",
+		Consume: "application/x-www-form-urlencoded",
+		Produce: "application/x-json-stream",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 403, Description: "Forbidden"},
+			{Code: 404, Description: "Not found"},
+		},
+	},
+	{
+		Name:    "deployRollbackEnvDiff",
+		Group:   "deploy",
+		Title:   "rollback env diff",
+		Path:    "/apps/{app}/deploy/rollback/envs",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.13",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+			{Code: 204, Description: "No changes"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 403, Description: "Forbidden"},
+			{Code: 404, Description: "Not found"},
+		},
+	},
+	{
+		Name:    "deployRollbackUpdate",
+		Group:   "deploy",
+		Title:   "rollback update",
+		Path:    "/apps/{app}/deploy/rollback/update",
+		Method:  "PUT",
+		Consume: "application/x-www-form-urlencoded",
+		Version: "1.4",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Rollback updated"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 403, Description: "Forbidden"},
+		},
+	},
+	{
+		Name:    "deploysList",
+		Group:   "deploy",
+		Title:   "deploy list",
+		Path:    "/deploys",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+			{Code: 204, Description: "No content"},
+		},
+	},
+	{
+		Name:    "deployInfo",
+		Group:   "deploy",
+		Title:   "deploy info",
+		Path:    "/deploys/{deploy}",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Not found"},
+		},
+	},
+	{
+		Name:    "eventList",
+		Group:   "event",
+		Title:   "event list",
+		Path:    "/events",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.1",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+			{Code: 204, Description: "No content"},
+			{Code: 400, Description: "Invalid cursor"},
+		},
+	},
+	{
+		Name:    "eventBlockList",
+		Group:   "event",
+		Title:   "event block list",
+		Path:    "/events/blocks",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.3",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+			{Code: 204, Description: "No content"},
+			{Code: 401, Description: "Unauthorized"},
+		},
+	},
+	{
+		Name:    "eventBlockAdd",
+		Group:   "event",
+		Title:   "add event block",
+		Path:    "/events/blocks",
+		Method:  "POST",
+		Consume: "application/x-www-form-urlencoded",
+		Version: "1.3",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+			{Code: 400, Description: "Invalid data or empty reason"},
+			{Code: 401, Description: "Unauthorized"},
+		},
+	},
+	{
+		Name:    "eventBlockRemove",
+		Group:   "event",
+		Title:   "remove event block",
+		Path:    "/events/blocks/{uuid}",
+		Method:  "DELETE",
+		Version: "1.3",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+			{Code: 400, Description: "Invalid uuid"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Active block with provided uuid not found"},
+		},
+	},
+	{
+		Name:    "eventBulkCancel",
+		Group:   "event",
+		Title:   "event bulk cancel",
+		Path:    "/events/cancel",
+		Method:  "POST",
+		Consume: "application/x-www-form-urlencoded",
+		Produce: "application/json",
+		Version: "1.13",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+			{Code: 204, Description: "No content"},
+			{Code: 400, Description: "Empty reason or filter"},
+			{Code: 401, Description: "Unauthorized"},
+		},
+	},
+	{
+		Name:    "kindList",
+		Group:   "event",
+		Title:   "kind list",
+		Path:    "/events/kinds",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.1",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+			{Code: 204, Description: "No content"},
+		},
+	},
+	{
+		Name:    "eventOwnerReassign",
+		Group:   "event",
+		Title:   "event owner reassign",
+		Path:    "/events/owner",
+		Method:  "POST",
+		Consume: "application/x-www-form-urlencoded",
+		Produce: "application/json",
+		Version: "1.13",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "User not found"},
+		},
+	},
+	{
+		Name:    "eventSummary",
+		Group:   "event",
+		Title:   "event summary",
+		Path:    "/events/summary",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.13",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+			{Code: 204, Description: "No content"},
+			{Code: 400, Description: "Invalid group by or period"},
+			{Code: 401, Description: "Unauthorized"},
+		},
+	},
+	{
+		Name:    "eventInfo",
+		Group:   "event",
+		Title:   "event info",
+		Path:    "/events/{uuid}",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.1",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+			{Code: 400, Description: "Invalid uuid"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Not found"},
+		},
+	},
+	{
+		Name:    "eventCancel",
+		Group:   "event",
+		Title:   "event cancel",
+		Path:    "/events/{uuid}/cancel",
+		Method:  "POST",
+		Produce: "application/json",
+		Version: "1.1",
+		Responses: []openapi.Response{
+			{Code: 204, Description: "OK"},
+			{Code: 400, Description: "Invalid uuid or empty reason"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Not found"},
+		},
+	},
+	{
+		Name:    "eventDiff",
+		Group:   "event",
+		Title:   "event diff",
+		Path:    "/events/{uuid}/diff",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.13",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+			{Code: 204, Description: "No content"},
+			{Code: 400, Description: "Invalid uuid"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Not found"},
+		},
+	},
+	{
+		Name:    "graphQLQuery",
+		Group:   "graphql",
+		Title:   "graphql query",
+		Path:    "/graphql",
+		Method:  "POST",
+		Consume: "application/json",
+		Produce: "application/json",
+		Version: "1.13",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+		},
+	},
+	{
+		Name:    "healingHistoryHandler",
+		Group:   "healing",
+		Title:   "docker healing history",
+		Path:    "/healing",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.3",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 204, Description: "No content"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+		},
+	},
+	{
+		Name:    "healthcheck",
+		Group:   "healthcheck",
+		Title:   "healthcheck",
+		Path:    "/healthcheck/",
+		Method:  "GET",
+		Version: "1.0",
+		Public:  true,
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+			{Code: 500, Description: "Internal server error"},
+		},
+	},
+	{
+		Name:    "machinesList",
+		Group:   "iaas",
+		Title:   "machine list",
+		Path:    "/iaas/machines",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+			{Code: 401, Description: "Unauthorized"},
+		},
+	},
+	{
+		Name:    "machineDestroy",
+		Group:   "iaas",
+		Title:   "machine destroy",
+		Path:    "/iaas/machines/{machine_id}",
+		Method:  "DELETE",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Not found"},
+		},
+	},
+	{
+		Name:    "templatesList",
+		Group:   "iaas",
+		Title:   "machine template list",
+		Path:    "/iaas/templates",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+			{Code: 401, Description: "Unauthorized"},
+		},
+	},
+	{
+		Name:    "templateCreate",
+		Group:   "iaas",
+		Title:   "template create",
+		Path:    "/iaas/templates",
+		Method:  "POST",
+		Consume: "application/x-www-form-urlencoded",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 201, Description: "Template created"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 409, Description: "Existent template"},
+		},
+	},
+	{
+		Name:    "templateDestroy",
+		Group:   "iaas",
+		Title:   "template destroy",
+		Path:    "/iaas/templates/{template_name}",
+		Method:  "DELETE",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Not found"},
+		},
+	},
+	{
+		Name:    "templateUpdate",
+		Group:   "iaas",
+		Title:   "template update",
+		Path:    "/iaas/templates/{template_name}",
+		Method:  "PUT",
+		Consume: "application/x-www-form-urlencoded",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Not found"},
+		},
+	},
+	{
+		Name:    "endImpersonation",
+		Group:   "impersonation",
+		Title:   "end impersonation",
+		Path:    "/users/impersonate",
+		Method:  "DELETE",
+		Version: "1.13",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Impersonation ended"},
+			{Code: 400, Description: "Not an impersonation token"},
+			{Code: 401, Description: "Unauthorized"},
+		},
+	},
+	{
+		Name:    "impersonateUser",
+		Group:   "impersonation",
+		Title:   "impersonate user",
+		Path:    "/users/{email}/impersonate",
+		Method:  "POST",
+		Consume: "application/x-www-form-urlencoded",
+		Produce: "application/json",
+		Version: "1.13",
+		Responses: []openapi.Response{
+			{Code: 201, Description: "Impersonation token created"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 403, Description: "Forbidden"},
+			{Code: 404, Description: "User not found"},
+		},
+	},
+	{
+		Name:    "index",
+		Group:   "index",
+		Title:   "index",
+		Path:    "/",
+		Method:  "GET",
+		Version: "1.0",
+		Public:  true,
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+		},
+	},
+	{
+		Name:    "info",
+		Group:   "info",
+		Title:   "api info",
+		Path:    "/info",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+		},
+	},
+	{
+		Name:    "installHostList",
+		Group:   "install",
+		Title:   "list install hosts",
+		Path:    "/install/hosts",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.2",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+			{Code: 401, Description: "Unauthorized"},
+		},
+	},
+	{
+		Name:    "installHostAdd",
+		Group:   "install",
+		Title:   "add install host",
+		Path:    "/install/hosts",
+		Method:  "POST",
+		Consume: "application/x-www-form-urlencoded",
+		Produce: "application/json",
+		Version: "1.2",
+		Responses: []openapi.Response{
+			{Code: 201, Description: "Host added"},
+			{Code: 401, Description: "Unauthorized"},
+		},
+	},
+	{
+		Name:    "installHostInfo",
+		Group:   "install",
+		Title:   "install host info",
+		Path:    "/install/hosts/{name}",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.2",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Not Found"},
+		},
+	},
+	{
+		Name:    "jobList",
+		Group:   "job",
+		Title:   "job list",
+		Path:    "/jobs",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.13",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "List jobs"},
+			{Code: 204, Description: "No content"},
+			{Code: 401, Description: "Unauthorized"},
+		},
+	},
+	{
+		Name:    "jobCreate",
+		Group:   "job",
+		Title:   "job create",
+		Path:    "/jobs",
+		Method:  "POST",
+		Consume: "application/x-www-form-urlencoded",
+		Produce: "application/json",
+		Version: "1.13",
+		Responses: []openapi.Response{
+			{Code: 201, Description: "Job created, one-off jobs start running right away"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 409, Description: "Job already exists"},
+		},
+	},
+	{
+		Name:    "jobDelete",
+		Group:   "job",
+		Title:   "job delete",
+		Path:    "/jobs/{name}",
+		Method:  "DELETE",
+		Version: "1.13",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Job removed"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Job not found"},
+		},
+	},
+	{
+		Name:    "jobInfo",
+		Group:   "job",
+		Title:   "job info",
+		Path:    "/jobs/{name}",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.13",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Show job"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Job not found"},
+		},
+	},
+	{
+		Name:    "jobRun",
+		Group:   "job",
+		Title:   "job run",
+		Path:    "/jobs/{name}/run",
+		Method:  "POST",
+		Produce: "application/json",
+		Version: "1.13",
+		Responses: []openapi.Response{
+			{Code: 202, Description: "Job started"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Job not found"},
+		},
+	},
+	{
+		Name:    "jobTrigger",
+		Group:   "job",
+		Title:   "job trigger",
+		Path:    "/jobs/{name}/trigger",
+		Method:  "POST",
+		Version: "1.13",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Job triggered"},
+			{Code: 400, Description: "Job is not a cronjob"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 403, Description: "Quota exceeded"},
+			{Code: 404, Description: "Job not found"},
+		},
+	},
+	{
+		Name:    "ldapSyncReport",
+		Group:   "ldap_sync",
+		Title:   "ldap sync report",
+		Path:    "/auth/ldap-sync",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.13",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Pending changes"},
+			{Code: 400, Description: "Sync not configured"},
+			{Code: 401, Description: "Unauthorized"},
+		},
+	},
+	{
+		Name:    "unlockUser",
+		Group:   "lockout",
+		Title:   "unlock user",
+		Path:    "/users/{email}/lockout",
+		Method:  "DELETE",
+		Version: "1.13",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "User unlocked"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 403, Description: "Forbidden"},
+			{Code: 404, Description: "User not found"},
+		},
+	},
+	{
+		Name:    "appLogStream",
+		Group:   "log_stream",
+		Title:   "app log stream",
+		Path:    "/apps/{appname}/log/ws",
+		Method:  "GET",
+		Produce: "Websocket connection upgrade",
+		Version: "1.13",
+		Public:  true,
+		Responses: []openapi.Response{
+			{Code: 101, Description: "Switch Protocol to websocket"},
+		},
+	},
+	{
+		Name:    "nodeHealingDelete",
+		Group:   "node",
+		Title:   "remove node healing",
+		Path:    "/healing/node",
+		Method:  "DELETE",
+		Produce: "application/json",
+		Version: "1.2",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 401, Description: "Unauthorized"},
+		},
+	},
+	{
+		Name:    "nodeHealingRead",
+		Group:   "node",
+		Title:   "node healing info",
+		Path:    "/healing/node",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.2",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 401, Description: "Unauthorized"},
+		},
+	},
+	{
+		Name:    "nodeHealingUpdate",
+		Group:   "node",
+		Title:   "node healing update",
+		Path:    "/healing/node",
+		Method:  "POST",
+		Consume: "application/x-www-form-urlencoded",
+		Version: "1.2",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 401, Description: "Unauthorized"},
+		},
+	},
+	{
+		Name:    "listNodesHandler",
+		Group:   "node",
+		Title:   "list nodes",
+		Path:    "/node",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.2",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 204, Description: "No content"},
+		},
+	},
+	{
+		Name:    "addNodeHandler",
+		Group:   "node",
+		Title:   "add node",
+		Path:    "/node",
+		Method:  "POST",
+		Consume: "application/x-www-form-urlencoded",
+		Produce: "application/x-json-stream",
+		Version: "1.2",
+		Responses: []openapi.Response{
+			{Code: 201, Description: "Ok"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Not found"},
+		},
+	},
+	{
+		Name:    "updateNodeHandler",
+		Group:   "node",
+		Title:   "update nodes",
+		Path:    "/node",
+		Method:  "PUT",
+		Consume: "application/x-www-form-urlencoded",
+		Version: "1.2",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Not found"},
+		},
+	},
+	{
+		Name:    "listUnitsByApp",
+		Group:   "node",
+		Title:   "list units by app",
+		Path:    "/node/apps/{appname}/containers",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.2",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 204, Description: "No content"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Not found"},
+		},
+	},
+	{
+		Name:    "rebalanceNodesHandler",
+		Group:   "node",
+		Title:   "rebalance units in nodes",
+		Path:    "/node/rebalance",
+		Method:  "POST",
+		Consume: "application/x-www-form-urlencoded",
+		Produce: "application/x-json-stream",
+		Version: "1.3",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+		},
+	},
+	{
+		Name:    "removeNodeHandler",
+		Group:   "node",
+		Title:   "remove node",
+		Path:    "/node/{address:.*}",
+		Method:  "DELETE",
+		Version: "1.2",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Not found"},
+		},
+	},
+	{
+		Name:    "infoNodeHandler",
+		Group:   "node",
+		Title:   "node info",
+		Path:    "/node/{address:.*}",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.6",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Not found"},
+		},
+	},
+	{
+		Name:    "listUnitsByNode",
+		Group:   "node",
+		Title:   "list units by node",
+		Path:    "/node/{address:.*}/containers",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.2",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 204, Description: "No content"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Not found"},
+		},
+	},
+	{
+		Name:    "nodeContainerList",
+		Group:   "nodecontainer",
+		Title:   "remove node container list",
+		Path:    "/nodecontainers",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.2",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 401, Description: "Unauthorized"},
+		},
+	},
+	{
+		Name:    "nodeContainerCreate",
+		Group:   "nodecontainer",
+		Title:   "node container create",
+		Path:    "/nodecontainers",
+		Method:  "POST",
+		Consume: "application/x-www-form-urlencoded",
+		Version: "1.2",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 400, Description: "Invald data"},
+			{Code: 401, Description: "Unauthorized"},
+		},
+	},
+	{
+		Name:    "nodeContainerDelete",
+		Group:   "nodecontainer",
+		Title:   "remove node container",
+		Path:    "/nodecontainers/{name}",
+		Method:  "DELETE",
+		Version: "1.2",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Not found"},
+		},
+	},
+	{
+		Name:    "nodeContainerInfo",
+		Group:   "nodecontainer",
+		Title:   "node container info",
+		Path:    "/nodecontainers/{name}",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.2",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Not found"},
+		},
+	},
+	{
+		Name:    "nodeContainerUpdate",
+		Group:   "nodecontainer",
+		Title:   "node container update",
+		Path:    "/nodecontainers/{name}",
+		Method:  "POST",
+		Consume: "application/x-www-form-urlencoded",
+		Version: "1.2",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 400, Description: "Invald data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Not found"},
+		},
+	},
+	{
+		Name:    "nodeContainerUpgrade",
+		Group:   "nodecontainer",
+		Title:   "node container upgrade",
+		Path:    "/nodecontainers/{name}/upgrade",
+		Method:  "POST",
+		Consume: "application/x-www-form-urlencoded",
+		Produce: "application/x-json-stream",
+		Version: "1.2",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 400, Description: "Invald data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Not found"},
+		},
+	},
+	{
+		Name:    "openAPISpec",
+		Group:   "openapi",
+		Title:   "openapi spec",
+		Path:    "/openapi.json",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.13",
+		Public:  true,
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+		},
+	},
+	{
+		Name:    "listPermissions",
+		Group:   "permission",
+		Title:   "list permissions",
+		Path:    "/permissions",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 401, Description: "Unauthorized"},
+		},
+	},
+	{
+		Name:    "removeDefaultRole",
+		Group:   "permission",
+		Title:   "remove default role",
+		Path:    "/role/default",
+		Method:  "DELETE",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+		},
+	},
+	{
+		Name:    "listDefaultRoles",
+		Group:   "permission",
+		Title:   "list default roles",
+		Path:    "/role/default",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 401, Description: "Unauthorized"},
+		},
+	},
+	{
+		Name:    "addDefaultRole",
+		Group:   "permission",
+		Title:   "add default role",
+		Path:    "/role/default",
+		Method:  "POST",
+		Consume: "application/x-www-form-urlencoded",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+		},
+	},
+	{
+		Name:    "listRoles",
+		Group:   "permission",
+		Title:   "role list",
+		Path:    "/roles",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+			{Code: 401, Description: "Unauthorized"},
+		},
+	},
+	{
+		Name:    "addRole",
+		Group:   "permission",
+		Title:   "role create",
+		Path:    "/roles",
+		Method:  "POST",
+		Consume: "application/x-www-form-urlencoded",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 201, Description: "Role created"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 409, Description: "Role already exists"},
+		},
+	},
+	{
+		Name:    "roleUpdate",
+		Group:   "permission",
+		Title:   "updates a role",
+		Path:    "/roles",
+		Method:  "PUT",
+		Version: "1.4",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+		},
+	},
+	{
+		Name:    "removeRole",
+		Group:   "permission",
+		Title:   "remove role",
+		Path:    "/roles/{name}",
+		Method:  "DELETE",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Role removed"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Role not found"},
+			{Code: 412, Description: "Role with users"},
+		},
+	},
+	{
+		Name:    "roleInfo",
+		Group:   "permission",
+		Title:   "role info",
+		Path:    "/roles/{name}",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Role not found"},
+		},
+	},
+	{
+		Name:    "assignRoleToGroup",
+		Group:   "permission",
+		Title:   "assign role to group",
+		Path:    "/roles/{name}/group",
+		Method:  "POST",
+		Consume: "application/x-www-form-urlencoded",
+		Version: "1.9",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Role not found"},
+		},
+	},
+	{
+		Name:    "dissociateRoleFromGroup",
+		Group:   "permission",
+		Title:   "dissociate role from group",
+		Path:    "/roles/{name}/group/{group_name}",
+		Method:  "DELETE",
+		Version: "1.9",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Role not found"},
+		},
+	},
+	{
+		Name:    "addPermissions",
+		Group:   "permission",
+		Title:   "add permissions",
+		Path:    "/roles/{name}/permissions",
+		Method:  "POST",
+		Consume: "application/x-www-form-urlencoded",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 409, Description: "Permission not allowed"},
+		},
+	},
+	{
+		Name:    "removePermissions",
+		Group:   "permission",
+		Title:   "remove permission",
+		Path:    "/roles/{name}/permissions/{permission}",
+		Method:  "DELETE",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Permission removed"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Not found"},
+		},
+	},
+	{
+		Name:    "assignRoleToServiceAccount",
+		Group:   "permission",
+		Title:   "assign role to service account",
+		Path:    "/roles/{name}/service-account",
+		Method:  "POST",
+		Consume: "application/x-www-form-urlencoded",
+		Version: "1.13",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Role or service account not found"},
+		},
+	},
+	{
+		Name:    "dissociateRoleFromServiceAccount",
+		Group:   "permission",
+		Title:   "dissociate role from service account",
+		Path:    "/roles/{name}/service-account/{service_account}",
+		Method:  "DELETE",
+		Version: "1.13",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Role or service account not found"},
+		},
+	},
+	{
+		Name:    "assignRoleToToken",
+		Group:   "permission",
+		Title:   "assign role to token",
+		Path:    "/roles/{name}/token",
+		Method:  "POST",
+		Consume: "application/x-www-form-urlencoded",
+		Version: "1.6",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Role or team token not found"},
+		},
+	},
+	{
+		Name:    "dissociateRoleFromToken",
+		Group:   "permission",
+		Title:   "dissociate role from token",
+		Path:    "/roles/{name}/token/{token_id}",
+		Method:  "DELETE",
+		Version: "1.6",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Role or team token not found"},
+		},
+	},
+	{
+		Name:    "assignRole",
+		Group:   "permission",
+		Title:   "assign role to user",
+		Path:    "/roles/{name}/user",
+		Method:  "POST",
+		Consume: "application/x-www-form-urlencoded",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Role not found"},
+		},
+	},
+	{
+		Name:    "dissociateRole",
+		Group:   "permission",
+		Title:   "dissociate role from user",
+		Path:    "/roles/{name}/user/{email}",
+		Method:  "DELETE",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Role not found"},
+		},
+	},
+	{
+		Name:    "listPlans",
+		Group:   "plan",
+		Title:   "plan list",
+		Path:    "/plans",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+			{Code: 204, Description: "No content"},
+		},
+	},
+	{
+		Name:    "addPlan",
+		Group:   "plan",
+		Title:   "plan create",
+		Path:    "/plans",
+		Method:  "POST",
+		Consume: "application/x-www-form-urlencoded",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 201, Description: "Plan created"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 409, Description: "Plan already exists"},
+		},
+	},
+	{
+		Name:    "removePlan",
+		Group:   "plan",
+		Title:   "remove plan",
+		Path:    "/plans/{planname}",
+		Method:  "DELETE",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Plan removed"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Plan not found"},
+		},
+	},
+	{
+		Name:    "platformList",
+		Group:   "platform",
+		Title:   "platform list",
+		Path:    "/platforms",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "List platforms"},
+			{Code: 204, Description: "No content"},
+			{Code: 401, Description: "Unauthorized"},
+		},
+	},
+	{
+		Name:    "platformAdd",
+		Group:   "platform",
+		Title:   "add platform",
+		Path:    "/platforms",
+		Method:  "POST",
+		Consume: "multipart/form-data",
+		Produce: "application/x-json-stream",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Platform created"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+		},
+	},
+	{
+		Name:    "platformRemove",
+		Group:   "platform",
+		Title:   "remove platform",
+		Path:    "/platforms/{name}",
+		Method:  "DELETE",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Platform removed"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Not found"},
+		},
+	},
+	{
+		Name:    "platformInfo",
+		Group:   "platform",
+		Title:   "platform info",
+		Path:    "/platforms/{name}",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.6",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Platform info"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "NotFound"},
+		},
+	},
+	{
+		Name:    "platformUpdate",
+		Group:   "platform",
+		Title:   "update platform",
+		Path:    "/platforms/{name}",
+		Method:  "PUT",
+		Produce: "application/x-json-stream",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Platform updated"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Not found"},
+		},
+	},
+	{
+		Name:    "platformRollback",
+		Group:   "platform",
+		Title:   "rollback platform",
+		Path:    "/platforms/{name}/rollback",
+		Method:  "POST",
+		Produce: "application/x-json-stream",
+		Version: "1.6",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+			{Code: 400, Description: "BadRequest"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Not found"},
+		},
+	},
+	{
+		Name:    "poolConstraintList",
+		Group:   "pool",
+		Title:   "pool constraints list",
+		Path:    "/constraints",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.3",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+			{Code: 204, Description: "No content"},
+			{Code: 401, Description: "Unauthorized"},
+		},
+	},
+	{
+		Name:    "poolConstraintSet",
+		Group:   "pool",
+		Title:   "set a pool constraint",
+		Path:    "/constraints",
+		Method:  "PUT",
+		Consume: "application/x-www-form-urlencoded",
+		Version: "1.3",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+			{Code: 401, Description: "Unauthorized"},
+		},
+	},
+	{
+		Name:    "poolList",
+		Group:   "pool",
+		Title:   "pool list",
+		Path:    "/pools",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+			{Code: 204, Description: "No content"},
+			{Code: 304, Description: "Not modified"},
+			{Code: 401, Description: "Unauthorized"},
+		},
+	},
+	{
+		Name:    "addPoolHandler",
+		Group:   "pool",
+		Title:   "pool create",
+		Path:    "/pools",
+		Method:  "POST",
+		Consume: "application/x-www-form-urlencoded",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 201, Description: "Pool created"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 409, Description: "Pool already exists"},
+		},
+	},
+	{
+		Name:    "removePoolHandler",
+		Group:   "pool",
+		Title:   "remove pool",
+		Path:    "/pools/{name}",
+		Method:  "DELETE",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Pool removed"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 403, Description: "Pool still has apps"},
+			{Code: 404, Description: "Pool not found"},
+		},
+	},
+	{
+		Name:    "getPoolHandler",
+		Group:   "pool",
+		Title:   "pool get",
+		Path:    "/pools/{name}",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.8",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+			{Code: 404, Description: "Not found"},
+			{Code: 401, Description: "Unauthorized"},
+		},
+	},
+	{
+		Name:    "poolUpdateHandler",
+		Group:   "pool",
+		Title:   "pool update",
+		Path:    "/pools/{name}",
+		Method:  "PUT",
+		Consume: "application/x-www-form-urlencoded",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Pool updated"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Pool not found"},
+			{Code: 409, Description: "Default pool already defined"},
+		},
+	},
+	{
+		Name:    "removeTeamToPoolHandler",
+		Group:   "pool",
+		Title:   "remove team from pool",
+		Path:    "/pools/{name}/team",
+		Method:  "DELETE",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Pool updated"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 404, Description: "Pool not found"},
+		},
+	},
+	{
+		Name:    "addTeamToPoolHandler",
+		Group:   "pool",
+		Title:   "add team too pool",
+		Path:    "/pools/{name}/team",
+		Method:  "POST",
+		Consume: "application/x-www-form-urlencoded",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Pool updated"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 404, Description: "Pool not found"},
+		},
+	},
+	{
+		Name:    "getAppQuota",
+		Group:   "quota",
+		Title:   "application quota",
+		Path:    "/apps/{app}/quota",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Application not found"},
+		},
+	},
+	{
+		Name:    "changeAppQuota",
+		Group:   "quota",
+		Title:   "update application quota",
+		Path:    "/apps/{app}/quota",
+		Method:  "PUT",
+		Consume: "application/x-www-form-urlencoded",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Quota updated"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 403, Description: "Limit lower than allocated"},
+			{Code: 404, Description: "Application not found"},
+		},
+	},
+	{
+		Name:    "getTeamJobQuota",
+		Group:   "quota",
+		Title:   "team job quota",
+		Path:    "/teams/{name}/job-quota",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.13",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Team not found"},
+		},
+	},
+	{
+		Name:    "changeTeamJobQuota",
+		Group:   "quota",
+		Title:   "update team job quota",
+		Path:    "/teams/{name}/job-quota",
+		Method:  "PUT",
+		Consume: "application/x-www-form-urlencoded",
+		Version: "1.13",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Quota updated"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 403, Description: "Limit lower than allocated value"},
+			{Code: 404, Description: "Team not found"},
+		},
+	},
+	{
+		Name:    "getTeamQuota",
+		Group:   "quota",
+		Title:   "team quota",
+		Path:    "/teams/{name}/quota",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.12",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Team not found"},
+		},
+	},
+	{
+		Name:    "changeTeamQuota",
+		Group:   "quota",
+		Title:   "update team quota",
+		Path:    "/teams/{name}/quota",
+		Method:  "PUT",
+		Consume: "application/x-www-form-urlencoded",
+		Version: "1.12",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Quota updated"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 403, Description: "Limit lower than allocated value"},
+			{Code: 404, Description: "Team not found"},
+		},
+	},
+	{
+		Name:    "getUserQuota",
+		Group:   "quota",
+		Title:   "user quota",
+		Path:    "/users/{email}/quota",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "User not found"},
+		},
+	},
+	{
+		Name:    "changeUserQuota",
+		Group:   "quota",
+		Title:   "update user quota",
+		Path:    "/users/{email}/quota",
+		Method:  "PUT",
+		Consume: "application/x-www-form-urlencoded",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Quota updated"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 403, Description: "Limit lower than allocated value"},
+			{Code: 404, Description: "User not found"},
+		},
+	},
+	{
+		Name:    "listRoleTemplates",
+		Group:   "role_template",
+		Title:   "role template list",
+		Path:    "/role-templates",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.13",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+			{Code: 401, Description: "Unauthorized"},
+		},
+	},
+	{
+		Name:    "addRoleTemplate",
+		Group:   "role_template",
+		Title:   "role template create",
+		Path:    "/role-templates",
+		Method:  "POST",
+		Consume: "application/x-www-form-urlencoded",
+		Version: "1.13",
+		Responses: []openapi.Response{
+			{Code: 201, Description: "Role template created"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 409, Description: "Role template already exists"},
+		},
+	},
+	{
+		Name:    "removeRoleTemplate",
+		Group:   "role_template",
+		Title:   "role template remove",
+		Path:    "/role-templates/{name}",
+		Method:  "DELETE",
+		Version: "1.13",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Role template removed"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Role template not found"},
+		},
+	},
+	{
+		Name:    "instantiateRoleTemplate",
+		Group:   "role_template",
+		Title:   "role template instantiate",
+		Path:    "/role-templates/{name}/roles",
+		Method:  "POST",
+		Consume: "application/x-www-form-urlencoded",
+		Produce: "application/json",
+		Version: "1.13",
+		Responses: []openapi.Response{
+			{Code: 201, Description: "Role created"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Role template or team not found"},
+			{Code: 409, Description: "Role already exists"},
+		},
+	},
+	{
+		Name:    "appSetRoutable",
+		Group:   "router",
+		Title:   "toggle an app version as routable",
+		Path:    "/apps/{app}/routable",
+		Method:  "POST",
+		Version: "1.8",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+			{Code: 400, Description: "Bad request"},
+			{Code: 401, Description: "Not authorized"},
+			{Code: 404, Description: "App not found"},
+		},
+	},
+	{
+		Name:    "listAppRouters",
+		Group:   "router",
+		Title:   "list app routers",
+		Path:    "/apps/{app}/routers",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.5",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+			{Code: 204, Description: "No content"},
+			{Code: 404, Description: "App not found"},
+		},
+	},
+	{
+		Name:    "addAppRouter",
+		Group:   "router",
+		Title:   "add app router",
+		Path:    "/apps/{app}/routers",
+		Method:  "POST",
+		Produce: "application/json",
+		Version: "1.5",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+			{Code: 404, Description: "App or router not found"},
+			{Code: 400, Description: "Invalid request"},
+		},
+	},
+	{
+		Name:    "removeAppRouter",
+		Group:   "router",
+		Title:   "delete app router",
+		Path:    "/apps/{app}/routers/{router}",
+		Method:  "DELETE",
+		Produce: "application/json",
+		Version: "1.5",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+			{Code: 404, Description: "App or router not found"},
+		},
+	},
+	{
+		Name:    "updateAppRouter",
+		Group:   "router",
+		Title:   "update app router",
+		Path:    "/apps/{app}/routers/{router}",
+		Method:  "PUT",
+		Produce: "application/json",
+		Version: "1.5",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+			{Code: 404, Description: "App or router not found"},
+			{Code: 400, Description: "Invalid request"},
+		},
+	},
+	{
+		Name:    "listRouters",
+		Group:   "router",
+		Title:   "router list",
+		Path:    "/routers",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.3",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+			{Code: 204, Description: "No content"},
+		},
+	},
+	{
+		Name:    "addRouter",
+		Group:   "router",
+		Title:   "router add",
+		Path:    "/routers",
+		Method:  "POST",
+		Version: "1.8",
+		Responses: []openapi.Response{
+			{Code: 201, Description: "Created"},
+			{Code: 400, Description: "Invalid router"},
+			{Code: 409, Description: "Router already exists"},
+		},
+	},
+	{
+		Name:    "deleteRouter",
+		Group:   "router",
+		Title:   "router delete",
+		Path:    "/routers/{name}",
+		Method:  "DELETE",
+		Version: "1.8",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+			{Code: 404, Description: "Router not found"},
+		},
+	},
+	{
+		Name:    "updateRouter",
+		Group:   "router",
+		Title:   "router update",
+		Path:    "/routers/{name}",
+		Method:  "PUT",
+		Version: "1.8",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+			{Code: 400, Description: "Invalid router"},
+			{Code: 404, Description: "Router not found"},
+		},
+	},
+	{
+		Name:    "samlMetadata",
+		Group:   "saml",
+		Title:   "saml metadata",
+		Path:    "/auth/saml",
+		Method:  "GET",
+		Produce: "application/xml",
+		Version: "1.0",
+		Public:  true,
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 400, Description: "Invalid data"},
+		},
+	},
+	{
+		Name:    "samlCallbackLogin",
+		Group:   "saml",
+		Title:   "saml callback",
+		Path:    "/auth/saml",
+		Method:  "POST",
+		Version: "1.0",
+		Public:  true,
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 400, Description: "Invalid data"},
+		},
+	},
+	{
+		Name:    "removeAutoScaleUnits",
+		Group:   "scale",
+		Title:   "remove unit auto scale",
+		Path:    "/apps/{app}/units/autoscale",
+		Method:  "DELETE",
+		Consume: "application/json",
+		Version: "1.9",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "App not found"},
+		},
+	},
+	{
+		Name:    "autoScaleUnitsInfo",
+		Group:   "scale",
+		Title:   "units autoscale info",
+		Path:    "/apps/{app}/units/autoscale",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.9",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "App not found"},
+		},
+	},
+	{
+		Name:    "addAutoScaleUnits",
+		Group:   "scale",
+		Title:   "add unit auto scale",
+		Path:    "/apps/{app}/units/autoscale",
+		Method:  "POST",
+		Consume: "application/json",
+		Version: "1.9",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Ok"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "App not found"},
+		},
+	},
+	{
+		Name:    "serviceList",
+		Group:   "service",
+		Title:   "service list",
+		Path:    "/services",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "List services"},
+			{Code: 204, Description: "No content"},
+			{Code: 304, Description: "Not modified"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+		},
+	},
+	{
+		Name:    "serviceCreate",
+		Group:   "service",
+		Title:   "service create",
+		Path:    "/services",
+		Method:  "POST",
+		Consume: "application/x-www-form-urlencoded",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 201, Description: "Service created"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 409, Description: "Service already exists"},
+		},
+	},
+	{
+		Name:    "serviceProxy",
+		Group:   "service",
+		Title:   "service proxy",
+		Path:    "/services/proxy/service/{service}",
+		Method:  "\"*\"",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Service not found"},
+		},
+	},
+	{
+		Name:    "serviceDelete",
+		Group:   "service",
+		Title:   "service delete",
+		Path:    "/services/{name}",
+		Method:  "DELETE",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Service removed"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 403, Description: "Forbidden (team is not the owner or service with instances)"},
+			{Code: 404, Description: "Service not found"},
+		},
+	},
+	{
+		Name:    "serviceUpdate",
+		Group:   "service",
+		Title:   "service update",
+		Path:    "/services/{name}",
+		Method:  "PUT",
+		Consume: "application/x-www-form-urlencoded",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Service updated"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 403, Description: "Forbidden (team is not the owner)"},
+			{Code: 404, Description: "Service not found"},
+		},
+	},
+	{
+		Name:    "serviceAddDoc",
+		Group:   "service",
+		Title:   "change service documentation",
+		Path:    "/services/{name}/doc",
+		Method:  "PUT",
+		Consume: "application/x-www-form-urlencoded",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Documentation updated"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 403, Description: "Forbidden (team is not the owner or service with instances)"},
+		},
+	},
+	{
+		Name:    "revokeServiceAccess",
+		Group:   "service",
+		Title:   "revoke access to a service",
+		Path:    "/services/{service}/team/{team}",
+		Method:  "DELETE",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Access revoked"},
+			{Code: 400, Description: "Team not found"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Service not found"},
+			{Code: 409, Description: "Team does not has access to this service"},
+		},
+	},
+	{
+		Name:    "grantServiceAccess",
+		Group:   "service",
+		Title:   "grant access to a service",
+		Path:    "/services/{service}/team/{team}",
+		Method:  "PUT",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Service updated"},
+			{Code: 400, Description: "Team not found"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Service not found"},
+			{Code: 409, Description: "Team already has access to this service"},
+		},
+	},
+	{
+		Name:    "serviceAccountList",
+		Group:   "service_account",
+		Title:   "service account list",
+		Path:    "/service-accounts",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.13",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "List service accounts"},
+			{Code: 204, Description: "No content"},
+			{Code: 401, Description: "Unauthorized"},
+		},
+	},
+	{
+		Name:    "serviceAccountCreate",
+		Group:   "service_account",
+		Title:   "service account create",
+		Path:    "/service-accounts",
+		Method:  "POST",
+		Consume: "application/x-www-form-urlencoded",
+		Produce: "application/json",
+		Version: "1.13",
+		Responses: []openapi.Response{
+			{Code: 201, Description: "Service account created"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 403, Description: "Forbidden"},
+			{Code: 409, Description: "Service account already exists"},
+		},
+	},
+	{
+		Name:    "serviceAccountDelete",
+		Group:   "service_account",
+		Title:   "service account delete",
+		Path:    "/service-accounts/{name}",
+		Method:  "DELETE",
+		Version: "1.13",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Service account removed"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 403, Description: "Forbidden"},
+			{Code: 404, Description: "Service account not found"},
+			{Code: 412, Description: "Service account has tokens or owns apps"},
+		},
+	},
+	{
+		Name:    "serviceAccountInfo",
+		Group:   "service_account",
+		Title:   "service account info",
+		Path:    "/service-accounts/{name}",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.13",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Get service account"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 403, Description: "Forbidden"},
+			{Code: 404, Description: "Service account not found"},
+		},
+	},
+	{
+		Name:    "serviceBrokerList",
+		Group:   "service_broker",
+		Title:   "service broker list",
+		Path:    "/brokers",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.7",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "List service brokers"},
+			{Code: 204, Description: "No content"},
+			{Code: 401, Description: "Unauthorized"},
+		},
+	},
+	{
+		Name:    "serviceBrokerAdd",
+		Group:   "service_broker",
+		Title:   "Add service broker",
+		Path:    "/brokers",
+		Method:  "POST",
+		Version: "1.7",
+		Responses: []openapi.Response{
+			{Code: 201, Description: "Service broker created"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 409, Description: "Broker already exists"},
+		},
+	},
+	{
+		Name:    "serviceBrokerDelete",
+		Group:   "service_broker",
+		Title:   "Delete service broker",
+		Path:    "/brokers/{broker}",
+		Method:  "DELETE",
+		Version: "1.7",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Service broker deleted"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Not Found"},
+		},
+	},
+	{
+		Name:    "serviceBrokerUpdate",
+		Group:   "service_broker",
+		Title:   "Update service broker",
+		Path:    "/brokers/{broker}",
+		Method:  "PUT",
+		Version: "1.7",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Service broker updated"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Not Found"},
+		},
+	},
+	{
+		Name:    "serviceInstances",
+		Group:   "service_instance",
+		Title:   "service instance list",
+		Path:    "/services/instances",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "List services instances"},
+			{Code: 204, Description: "No content"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+		},
+	},
+	{
+		Name:    "serviceInfo",
+		Group:   "service_instance",
+		Title:   "service info",
+		Path:    "/services/{name}",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+		},
+	},
+	{
+		Name:    "serviceDoc",
+		Group:   "service_instance",
+		Title:   "service doc",
+		Path:    "/services/{name}/doc",
+		Method:  "GET",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Not found"},
+		},
+	},
+	{
+		Name:    "servicePlans",
+		Group:   "service_instance",
+		Title:   "service plans",
+		Path:    "/services/{name}/plans",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Service not found"},
+		},
+	},
+	{
+		Name:    "createServiceInstance",
+		Group:   "service_instance",
+		Title:   "service instance create",
+		Path:    "/services/{service}/instances",
+		Method:  "POST",
+		Consume: "application/x-www-form-urlencoded",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 201, Description: "Service created"},
+			{Code: 202, Description: "Service instance creation started"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 409, Description: "Service already exists"},
+		},
+	},
+	{
+		Name:    "serviceInstanceRevokeTeam",
+		Group:   "service_instance",
+		Title:   "revoke access to service instance",
+		Path:    "/services/{service}/instances/permission/{instance}/{team}",
+		Method:  "DELETE",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Access revoked"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Service instance not found"},
+		},
+	},
+	{
+		Name:    "serviceInstanceGrantTeam",
+		Group:   "service_instance",
+		Title:   "grant access to service instance",
+		Path:    "/services/{service}/instances/permission/{instance}/{team}",
+		Method:  "PUT",
+		Consume: "application/x-www-form-urlencoded",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Access granted"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Service instance not found"},
+		},
+	},
+	{
+		Name:    "removeServiceInstance",
+		Group:   "service_instance",
+		Title:   "remove service instance",
+		Path:    "/services/{service}/instances/{instance}",
+		Method:  "DELETE",
+		Produce: "application/x-json-stream",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Service removed"},
+			{Code: 400, Description: "Bad request"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Service instance not found"},
+		},
+	},
+	{
+		Name:    "serviceInstance",
+		Group:   "service_instance",
+		Title:   "service instance info",
+		Path:    "/services/{service}/instances/{instance}",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Service instance not found"},
+		},
+	},
+	{
+		Name:    "updateServiceInstance",
+		Group:   "service_instance",
+		Title:   "service instance update",
+		Path:    "/services/{service}/instances/{instance}",
+		Method:  "PUT",
+		Consume: "application/x-www-form-urlencoded",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Service instance updated"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Service instance not found"},
+		},
+	},
+	{
+		Name:    "serviceInstanceStatus",
+		Group:   "service_instance",
+		Title:   "service instance status",
+		Path:    "/services/{service}/instances/{instance}/status",
+		Method:  "GET",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "List services instances"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Service instance not found"},
+		},
+	},
+	{
+		Name:    "serviceInstanceProxy",
+		Group:   "service_instance",
+		Title:   "service instance proxy",
+		Path:    "/services/{service}/proxy/{instance}",
+		Method:  "\"*\"",
+		Version: "1.0",
+		Responses: []openapi.Response{
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Instance not found"},
+		},
+	},
+	{
+		Name:    "listSessions",
+		Group:   "session",
+		Title:   "session list",
+		Path:    "/users/{email}/sessions",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.13",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "OK"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 403, Description: "Forbidden"},
+			{Code: 404, Description: "User not found"},
+		},
+	},
+	{
+		Name:    "revokeSession",
+		Group:   "session",
+		Title:   "session revoke",
+		Path:    "/users/{email}/sessions/{id}",
+		Method:  "DELETE",
+		Version: "1.13",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Session revoked"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 403, Description: "Forbidden"},
+			{Code: 404, Description: "Session not found"},
+		},
+	},
+	{
+		Name:    "remoteShellHandler",
+		Group:   "shell",
+		Title:   "app shell",
+		Path:    "/apps/{appname}/shell",
+		Method:  "GET",
+		Produce: "Websocket connection upgrade",
+		Version: "1.0",
+		Public:  true,
+		Responses: []openapi.Response{
+			{Code: 101, Description: "Switch Protocol to websocket"},
+		},
+	},
+	{
+		Name:    "tokenList",
+		Group:   "team_token",
+		Title:   "token list",
+		Path:    "/tokens",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.6",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "List tokens"},
+			{Code: 204, Description: "No content"},
+			{Code: 401, Description: "Unauthorized"},
+		},
+	},
+	{
+		Name:    "tokenCreate",
+		Group:   "team_token",
+		Title:   "token create",
+		Path:    "/tokens",
+		Method:  "POST",
+		Produce: "application/json",
+		Version: "1.6",
+		Responses: []openapi.Response{
+			{Code: 201, Description: "Token created"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 403, Description: "Forbidden"},
+			{Code: 409, Description: "Token already exists"},
+		},
+	},
+	{
+		Name:    "tokenDelete",
+		Group:   "team_token",
+		Title:   "token delete",
+		Path:    "/tokens/{token_id}",
+		Method:  "DELETE",
+		Produce: "application/json",
+		Version: "1.6",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Token created"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Token not found"},
+		},
+	},
+	{
+		Name:    "tokenInfo",
+		Group:   "team_token",
+		Title:   "token info",
+		Path:    "/tokens/{token_id}",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.7",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Get token"},
+			{Code: 401, Description: "Unauthorized"},
+		},
+	},
+	{
+		Name:    "tokenUpdate",
+		Group:   "team_token",
+		Title:   "token update",
+		Path:    "/tokens/{token_id}",
+		Method:  "PUT",
+		Produce: "application/json",
+		Version: "1.6",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Token updated"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 403, Description: "Forbidden"},
+			{Code: 404, Description: "Token not found"},
+		},
+	},
+	{
+		Name:    "tokenRotate",
+		Group:   "team_token",
+		Title:   "token rotate",
+		Path:    "/tokens/{token_id}/rotate",
+		Method:  "POST",
+		Produce: "application/json",
+		Version: "1.13",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Token rotated"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 403, Description: "Forbidden"},
+			{Code: 404, Description: "Token not found"},
+		},
+	},
+	{
+		Name:    "disableTwoFactor",
+		Group:   "twofactor",
+		Title:   "disable two-factor authentication",
+		Path:    "/users/2fa",
+		Method:  "DELETE",
+		Version: "1.13",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Two-factor authentication disabled"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+		},
+	},
+	{
+		Name:    "enrollTwoFactor",
+		Group:   "twofactor",
+		Title:   "enroll two-factor authentication",
+		Path:    "/users/2fa",
+		Method:  "POST",
+		Produce: "application/json",
+		Version: "1.13",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Enrollment started"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 409, Description: "Two-factor authentication already enabled"},
+		},
+	},
+	{
+		Name:    "enableTwoFactor",
+		Group:   "twofactor",
+		Title:   "enable two-factor authentication",
+		Path:    "/users/2fa",
+		Method:  "PUT",
+		Consume: "application/x-www-form-urlencoded",
+		Produce: "application/json",
+		Version: "1.13",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Two-factor authentication enabled"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 409, Description: "Two-factor authentication already enabled"},
+		},
+	},
+	{
+		Name:    "regenerateRecoveryCodes",
+		Group:   "twofactor",
+		Title:   "regenerate two-factor authentication recovery codes",
+		Path:    "/users/2fa/recovery-codes",
+		Method:  "POST",
+		Produce: "application/json",
+		Version: "1.13",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Recovery codes regenerated"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+		},
+	},
+	{
+		Name:    "resetTwoFactor",
+		Group:   "twofactor",
+		Title:   "reset two-factor authentication",
+		Path:    "/users/{email}/2fa",
+		Method:  "DELETE",
+		Version: "1.13",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Two-factor authentication reset"},
+			{Code: 400, Description: "Invalid data"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 403, Description: "Forbidden"},
+			{Code: 404, Description: "User not found"},
+		},
+	},
+	{
+		Name:    "volumePlansList",
+		Group:   "volume",
+		Title:   "volume plan list",
+		Path:    "/volumeplans",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.4",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "List volume plans"},
+			{Code: 401, Description: "Unauthorized"},
+		},
+	},
+	{
+		Name:    "volumesList",
+		Group:   "volume",
+		Title:   "volume list",
+		Path:    "/volumes",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.4",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "List volumes"},
+			{Code: 204, Description: "No content"},
+			{Code: 401, Description: "Unauthorized"},
+		},
+	},
+	{
+		Name:    "volumeCreate",
+		Group:   "volume",
+		Title:   "volume create",
+		Path:    "/volumes",
+		Method:  "POST",
+		Produce: "application/json",
+		Version: "1.4",
+		Responses: []openapi.Response{
+			{Code: 201, Description: "Volume created"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 409, Description: "Volume already exists"},
+		},
+	},
+	{
+		Name:    "volumeDelete",
+		Group:   "volume",
+		Title:   "volume delete",
+		Path:    "/volumes/{name}",
+		Method:  "DELETE",
+		Produce: "application/json",
+		Version: "1.4",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Volume deleted"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Volume not found"},
+		},
+	},
+	{
+		Name:    "volumeInfo",
+		Group:   "volume",
+		Title:   "volume info",
+		Path:    "/volumes/{name}",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.4",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Show volume"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Volume not found"},
+		},
+	},
+	{
+		Name:    "volumeUpdate",
+		Group:   "volume",
+		Title:   "volume update",
+		Path:    "/volumes/{name}",
+		Method:  "POST",
+		Produce: "application/json",
+		Version: "1.4",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Volume updated"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Volume not found"},
+		},
+	},
+	{
+		Name:    "volumeUnbind",
+		Group:   "volume",
+		Title:   "volume unbind",
+		Path:    "/volumes/{name}/bind",
+		Method:  "DELETE",
+		Produce: "application/json",
+		Version: "1.4",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Volume unbinded"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Volume not found"},
+		},
+	},
+	{
+		Name:    "volumeBind",
+		Group:   "volume",
+		Title:   "volume bind",
+		Path:    "/volumes/{name}/bind",
+		Method:  "POST",
+		Produce: "application/json",
+		Version: "1.4",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Volume binded"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Volume not found"},
+			{Code: 409, Description: "Volume bind already exists"},
+		},
+	},
+	{
+		Name:    "webhookList",
+		Group:   "webhook",
+		Title:   "webhook list",
+		Path:    "/events/webhooks",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.6",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "List webhooks"},
+			{Code: 204, Description: "No content"},
+		},
+	},
+	{
+		Name:    "webhookCreate",
+		Group:   "webhook",
+		Title:   "webhook create",
+		Path:    "/events/webhooks",
+		Method:  "POST",
+		Version: "1.6",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Webhook created"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 400, Description: "Invalid webhook"},
+			{Code: 409, Description: "Webhook already exists"},
+		},
+	},
+	{
+		Name:    "webhookDelete",
+		Group:   "webhook",
+		Title:   "webhook delete",
+		Path:    "/events/webhooks/{name}",
+		Method:  "DELETE",
+		Version: "1.6",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Webhook deleted"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Webhook not found"},
+		},
+	},
+	{
+		Name:    "webhookInfo",
+		Group:   "webhook",
+		Title:   "webhook info",
+		Path:    "/events/webhooks/{name}",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.6",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Get webhook"},
+			{Code: 404, Description: "Not found"},
+			{Code: 401, Description: "Unauthorized"},
+		},
+	},
+	{
+		Name:    "webhookUpdate",
+		Group:   "webhook",
+		Title:   "webhook update",
+		Path:    "/events/webhooks/{name}",
+		Method:  "PUT",
+		Version: "1.6",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Webhook updated"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 400, Description: "Invalid webhook"},
+			{Code: 404, Description: "Webhook not found"},
+		},
+	},
+	{
+		Name:    "webhookDeadLetterList",
+		Group:   "webhook",
+		Title:   "webhook dead letter list",
+		Path:    "/events/webhooks/{name}/deadletters",
+		Method:  "GET",
+		Produce: "application/json",
+		Version: "1.13",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "List dead letters"},
+			{Code: 204, Description: "No content"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Webhook not found"},
+		},
+	},
+	{
+		Name:    "webhookDeadLetterRedispatch",
+		Group:   "webhook",
+		Title:   "webhook dead letter redispatch",
+		Path:    "/events/webhooks/{name}/deadletters/{id}/redispatch",
+		Method:  "POST",
+		Version: "1.13",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Dead letter redispatched"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Webhook or dead letter not found"},
+		},
+	},
+	{
+		Name:    "webhookDispatch",
+		Group:   "webhook",
+		Title:   "webhook event dispatch",
+		Path:    "/events/webhooks/{name}/dispatch",
+		Method:  "POST",
+		Consume: "application/x-www-form-urlencoded",
+		Version: "1.13",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Event dispatched"},
+			{Code: 400, Description: "Invalid event id"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Webhook or event not found"},
+		},
+	},
+	{
+		Name:    "webhookTestFire",
+		Group:   "webhook",
+		Title:   "webhook test",
+		Path:    "/events/webhooks/{name}/test",
+		Method:  "POST",
+		Version: "1.13",
+		Responses: []openapi.Response{
+			{Code: 200, Description: "Test event delivered"},
+			{Code: 401, Description: "Unauthorized"},
+			{Code: 404, Description: "Webhook not found"},
+		},
+	},
+}
@@ -0,0 +1,43 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/tsuru/tsuru/api/openapi"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestOpenAPISpec(c *check.C) {
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest("GET", "/openapi.json", nil)
+	c.Assert(err, check.IsNil)
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, "application/json")
+	var doc openapi.Document
+	err = json.Unmarshal(recorder.Body.Bytes(), &doc)
+	c.Assert(err, check.IsNil)
+	c.Assert(doc.OpenAPI, check.Equals, "3.0.3")
+	c.Assert(doc.Info.Version, check.Equals, Version)
+	jobCreate := doc.Paths["/1.13/jobs"]["post"]
+	c.Assert(jobCreate, check.NotNil)
+	c.Assert(jobCreate.OperationID, check.Equals, "jobCreate")
+	c.Assert(jobCreate.Tags, check.DeepEquals, []string{"job"})
+	c.Assert(jobCreate.Responses["201"].Description, check.Equals, "Job created, one-off jobs start running right away")
+	c.Assert(jobCreate.Security, check.DeepEquals, []map[string][]string{{"bearerAuth": {}}})
+	spec := doc.Paths["/1.13/openapi.json"]["get"]
+	c.Assert(spec, check.NotNil)
+	c.Assert(spec.Security, check.IsNil)
+}
+
+func (s *S) TestOpenAPIHandlersUpToDate(c *check.C) {
+	handlers, err := openapi.Parse(".")
+	c.Assert(err, check.IsNil)
+	c.Assert(handlers, check.DeepEquals, openAPIHandlers, check.Commentf("handlers annotations changed, please run 'go generate ./api/...'"))
+}
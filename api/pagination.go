@@ -0,0 +1,124 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/tsuru/tsuru/errors"
+)
+
+const totalCountHeader = "X-Total-Count"
+
+// page is the slice of a listing requested with the limit and offset query
+// parameters. A zero limit means all items after the offset are listed, so
+// that handlers keep listing everything when no parameters are sent.
+type page struct {
+	limit  int
+	offset int
+}
+
+func parsePage(r *http.Request) (page, error) {
+	var p page
+	query := r.URL.Query()
+	for param, dst := range map[string]*int{"limit": &p.limit, "offset": &p.offset} {
+		value := query.Get(param)
+		if value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return page{}, &errors.HTTP{Code: http.StatusBadRequest, Message: fmt.Sprintf("%s must be a non-negative integer", param)}
+		}
+		*dst = n
+	}
+	return p, nil
+}
+
+// bounds returns the start and end indexes of the page in a listing with
+// total items.
+func (p page) bounds(total int) (int, int) {
+	start := p.offset
+	if start > total {
+		start = total
+	}
+	end := total
+	if p.limit > 0 && start+p.limit < total {
+		end = start + p.limit
+	}
+	return start, end
+}
+
+// paginate parses the page requested in r and sets the X-Total-Count and Link
+// headers of the response, returning the bounds of the page in a listing with
+// total items. Handlers slice their listings with the returned bounds:
+//
+//	start, end, err := paginate(w, r, len(apps))
+//	if err != nil {
+//		return err
+//	}
+//	apps = apps[start:end]
+func paginate(w http.ResponseWriter, r *http.Request, total int) (int, int, error) {
+	p, err := parsePage(r)
+	if err != nil {
+		return 0, 0, err
+	}
+	w.Header().Set(totalCountHeader, strconv.Itoa(total))
+	if links := p.links(r, total); len(links) > 0 {
+		w.Header().Set("Link", strings.Join(links, ", "))
+	}
+	start, end := p.bounds(total)
+	return start, end, nil
+}
+
+// links returns the Link header values pointing to the first, previous, next
+// and last pages of the listing.
+func (p page) links(r *http.Request, total int) []string {
+	if p.limit == 0 {
+		return nil
+	}
+	var links []string
+	addLink := func(rel string, offset int) {
+		links = append(links, fmt.Sprintf(`<%s>; rel="%s"`, pageURL(r, p.limit, offset), rel))
+	}
+	lastOffset := 0
+	if total > 0 {
+		lastOffset = ((total - 1) / p.limit) * p.limit
+	}
+	addLink("first", 0)
+	if p.offset > 0 {
+		prevOffset := p.offset - p.limit
+		if prevOffset > lastOffset {
+			prevOffset = lastOffset
+		}
+		if prevOffset < 0 {
+			prevOffset = 0
+		}
+		addLink("prev", prevOffset)
+	}
+	if p.offset+p.limit < total {
+		addLink("next", p.offset+p.limit)
+	}
+	addLink("last", lastOffset)
+	return links
+}
+
+func pageURL(r *http.Request, limit, offset int) string {
+	query := url.Values{}
+	for k, v := range r.URL.Query() {
+		if strings.HasPrefix(k, ":") {
+			continue
+		}
+		query[k] = v
+	}
+	query.Set("limit", strconv.Itoa(limit))
+	query.Set("offset", strconv.Itoa(offset))
+	u := url.URL{Path: r.URL.Path, RawQuery: query.Encode()}
+	return u.String()
+}
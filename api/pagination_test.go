@@ -0,0 +1,134 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+
+	"github.com/tsuru/tsuru/app"
+	"github.com/tsuru/tsuru/errors"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestPaginate(c *check.C) {
+	tests := []struct {
+		url           string
+		total         int
+		expectedStart int
+		expectedEnd   int
+		expectedLink  string
+	}{
+		{
+			url:           "/apps",
+			total:         5,
+			expectedStart: 0,
+			expectedEnd:   5,
+		},
+		{
+			url:           "/apps?limit=2",
+			total:         5,
+			expectedStart: 0,
+			expectedEnd:   2,
+			expectedLink:  `</apps?limit=2&offset=0>; rel="first", </apps?limit=2&offset=2>; rel="next", </apps?limit=2&offset=4>; rel="last"`,
+		},
+		{
+			url:           "/apps?limit=2&offset=2&pool=mypool&:mux-route-name=x",
+			total:         5,
+			expectedStart: 2,
+			expectedEnd:   4,
+			expectedLink:  `</apps?limit=2&offset=0&pool=mypool>; rel="first", </apps?limit=2&offset=0&pool=mypool>; rel="prev", </apps?limit=2&offset=4&pool=mypool>; rel="next", </apps?limit=2&offset=4&pool=mypool>; rel="last"`,
+		},
+		{
+			url:           "/apps?limit=2&offset=4",
+			total:         5,
+			expectedStart: 4,
+			expectedEnd:   5,
+			expectedLink:  `</apps?limit=2&offset=0>; rel="first", </apps?limit=2&offset=2>; rel="prev", </apps?limit=2&offset=4>; rel="last"`,
+		},
+		{
+			url:           "/apps?offset=3",
+			total:         5,
+			expectedStart: 3,
+			expectedEnd:   5,
+		},
+		{
+			url:           "/apps?limit=2&offset=10",
+			total:         5,
+			expectedStart: 5,
+			expectedEnd:   5,
+			expectedLink:  `</apps?limit=2&offset=0>; rel="first", </apps?limit=2&offset=4>; rel="prev", </apps?limit=2&offset=4>; rel="last"`,
+		},
+		{
+			url:           "/apps?limit=2",
+			total:         0,
+			expectedStart: 0,
+			expectedEnd:   0,
+			expectedLink:  `</apps?limit=2&offset=0>; rel="first", </apps?limit=2&offset=0>; rel="last"`,
+		},
+	}
+	for _, tt := range tests {
+		request, err := http.NewRequest("GET", tt.url, nil)
+		c.Assert(err, check.IsNil)
+		recorder := httptest.NewRecorder()
+		start, end, err := paginate(recorder, request, tt.total)
+		c.Assert(err, check.IsNil)
+		c.Assert(start, check.Equals, tt.expectedStart, check.Commentf("url: %s", tt.url))
+		c.Assert(end, check.Equals, tt.expectedEnd, check.Commentf("url: %s", tt.url))
+		c.Assert(recorder.Header().Get("Link"), check.Equals, tt.expectedLink, check.Commentf("url: %s", tt.url))
+		c.Assert(recorder.Header().Get("X-Total-Count"), check.Equals, strconv.Itoa(tt.total))
+	}
+}
+
+func (s *S) TestPaginateInvalidParams(c *check.C) {
+	for _, url := range []string{"/apps?limit=a", "/apps?limit=-1", "/apps?offset=x"} {
+		request, err := http.NewRequest("GET", url, nil)
+		c.Assert(err, check.IsNil)
+		recorder := httptest.NewRecorder()
+		_, _, err = paginate(recorder, request, 10)
+		c.Assert(err, check.NotNil)
+		httpErr, ok := err.(*errors.HTTP)
+		c.Assert(ok, check.Equals, true)
+		c.Assert(httpErr.Code, check.Equals, http.StatusBadRequest)
+	}
+}
+
+func (s *S) TestAppListPaginated(c *check.C) {
+	for _, name := range []string{"app1", "app2", "app3"} {
+		a := app.App{Name: name, Platform: "zend", TeamOwner: s.team.Name}
+		err := app.CreateApp(context.TODO(), &a, s.user)
+		c.Assert(err, check.IsNil)
+	}
+	request, err := http.NewRequest("GET", "/apps?limit=2&offset=1", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(recorder.Header().Get("X-Total-Count"), check.Equals, "3")
+	c.Assert(recorder.Header().Get("Link"), check.Equals, `</apps?limit=2&offset=0>; rel="first", </apps?limit=2&offset=0>; rel="prev", </apps?limit=2&offset=2>; rel="last"`)
+	var apps []app.App
+	err = json.Unmarshal(recorder.Body.Bytes(), &apps)
+	c.Assert(err, check.IsNil)
+	c.Assert(apps, check.HasLen, 2)
+	c.Assert(apps[0].Name, check.Equals, "app2")
+	c.Assert(apps[1].Name, check.Equals, "app3")
+}
+
+func (s *S) TestAppListPaginatedInvalidLimit(c *check.C) {
+	a := app.App{Name: "app1", Platform: "zend", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest("GET", "/apps?limit=abc", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "b "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(recorder.Body.String(), check.Equals, "limit must be a non-negative integer\n")
+}
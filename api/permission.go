@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"net/http"
 	"sort"
+	"time"
 
 	"github.com/tsuru/tsuru/app"
 	"github.com/tsuru/tsuru/auth"
@@ -349,7 +350,16 @@ func assignRole(w http.ResponseWriter, r *http.Request, t auth.Token) (err error
 	if err != nil {
 		return err
 	}
-
+	if expiresAt := InputValue(r, "expires_at"); expiresAt != "" {
+		expiration, parseErr := time.Parse(time.RFC3339, expiresAt)
+		if parseErr != nil {
+			return &errors.HTTP{Code: http.StatusBadRequest, Message: "expires_at must be a RFC 3339 timestamp"}
+		}
+		if !expiration.After(time.Now()) {
+			return &errors.HTTP{Code: http.StatusBadRequest, Message: "expires_at must be in the future"}
+		}
+		return user.AddTemporaryRole(roleName, contextValue, expiration)
+	}
 	return user.AddRole(roleName, contextValue)
 }
 
@@ -434,7 +444,7 @@ func listPermissions(w http.ResponseWriter, r *http.Request, t auth.Token) error
 // title: add default role
 // path: /role/default
 // method: POST
-// consme: application/x-www-form-urlencoded
+// consume: application/x-www-form-urlencoded
 // responses:
 //	200: Ok
 //	400: Invalid data
@@ -708,6 +718,9 @@ func assignRoleToToken(w http.ResponseWriter, r *http.Request, t auth.Token) err
 		w.WriteHeader(http.StatusNotFound)
 		return nil
 	}
+	if err == authTypes.ErrServiceAccountTokenRoles {
+		return &errors.ValidationError{Message: err.Error()}
+	}
 	return err
 }
 
@@ -755,6 +768,90 @@ func dissociateRoleFromToken(w http.ResponseWriter, r *http.Request, t auth.Toke
 	return err
 }
 
+// title: assign role to service account
+// path: /roles/{name}/service-account
+// method: POST
+// consume: application/x-www-form-urlencoded
+// responses:
+//	200: Ok
+//	400: Invalid data
+//	401: Unauthorized
+//	404: Role or service account not found
+func assignRoleToServiceAccount(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	ctx := r.Context()
+	if !permission.Check(t, permission.PermRoleUpdateAssign) {
+		return permission.ErrUnauthorized
+	}
+	serviceAccount := InputValue(r, "service_account")
+	contextValue := InputValue(r, "context")
+	roleName := r.URL.Query().Get(":name")
+	evt, err := event.New(&event.Opts{
+		Target:     event.Target{Type: event.TargetTypeRole, Value: roleName},
+		Kind:       permission.PermRoleUpdateAssign,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		CustomData: event.FormToCustomData(InputFields(r)),
+		Allowed:    event.Allowed(permission.PermRoleReadEvents),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	role, err := getRoleReturnNotFound(roleName)
+	if err != nil {
+		return err
+	}
+	if err = validateContextValue(ctx, role, contextValue); err != nil {
+		return err
+	}
+	err = canUseRole(t, role, contextValue)
+	if err != nil {
+		return err
+	}
+	err = servicemanager.ServiceAccount.AddRole(ctx, serviceAccount, roleName, contextValue)
+	return serviceAccountNotFound(err)
+}
+
+// title: dissociate role from service account
+// path: /roles/{name}/service-account/{service_account}
+// method: DELETE
+// responses:
+//	200: Ok
+//	400: Invalid data
+//	401: Unauthorized
+//	404: Role or service account not found
+func dissociateRoleFromServiceAccount(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	ctx := r.Context()
+	if !permission.Check(t, permission.PermRoleUpdateDissociate) {
+		return permission.ErrUnauthorized
+	}
+	serviceAccount := r.URL.Query().Get(":service_account")
+	contextValue := InputValue(r, "context")
+	roleName := r.URL.Query().Get(":name")
+	evt, err := event.New(&event.Opts{
+		Target:     event.Target{Type: event.TargetTypeRole, Value: roleName},
+		Kind:       permission.PermRoleUpdateDissociate,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		CustomData: event.FormToCustomData(InputFields(r)),
+		Allowed:    event.Allowed(permission.PermRoleReadEvents),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	role, err := getRoleReturnNotFound(roleName)
+	if err != nil {
+		return err
+	}
+	err = canUseRole(t, role, contextValue)
+	if err != nil {
+		return err
+	}
+	err = servicemanager.ServiceAccount.RemoveRole(ctx, serviceAccount, roleName, contextValue)
+	return serviceAccountNotFound(err)
+}
+
 // title: assign role to group
 // path: /roles/{name}/group
 // method: POST
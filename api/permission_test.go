@@ -11,8 +11,10 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/tsuru/tsuru/app"
@@ -420,6 +422,71 @@ func (s *S) TestAssignRoleNotFound(c *check.C) {
 	c.Assert(recorder.Code, check.Equals, http.StatusNotFound)
 }
 
+func (s *S) TestAssignRoleWithExpiration(c *check.C) {
+	role, err := permission.NewRole("test", "team", "")
+	c.Assert(err, check.IsNil)
+	err = role.AddPermissions("app.create")
+	c.Assert(err, check.IsNil)
+	_, emptyToken := permissiontest.CustomUserWithPermission(c, nativeScheme, "user2")
+	expiresAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	body := url.Values{
+		"email":      []string{emptyToken.GetUserName()},
+		"context":    []string{"myteam"},
+		"expires_at": []string{expiresAt.Format(time.RFC3339)},
+	}
+	req, err := http.NewRequest(http.MethodPost, "/roles/test/user", strings.NewReader(body.Encode()))
+	c.Assert(err, check.IsNil)
+	_, token := permissiontest.CustomUserWithPermission(c, nativeScheme, "user1", permission.Permission{
+		Scheme:  permission.PermRoleUpdateAssign,
+		Context: permission.Context(permTypes.CtxGlobal, ""),
+	}, permission.Permission{
+		Scheme:  permission.PermAppCreate,
+		Context: permission.Context(permTypes.CtxTeam, "myteam"),
+	})
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	server := RunServer(true)
+	server.ServeHTTP(recorder, req)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	emptyUser, err := emptyToken.User()
+	c.Assert(err, check.IsNil)
+	c.Assert(emptyUser.Roles, check.HasLen, 1)
+	c.Assert(emptyUser.Roles[0].ExpiresAt, check.NotNil)
+	c.Assert(emptyUser.Roles[0].ExpiresAt.Equal(expiresAt), check.Equals, true)
+}
+
+func (s *S) TestAssignRoleWithInvalidExpiration(c *check.C) {
+	_, err := permission.NewRole("test", "team", "")
+	c.Assert(err, check.IsNil)
+	_, emptyToken := permissiontest.CustomUserWithPermission(c, nativeScheme, "user2")
+	tests := []struct {
+		expiresAt string
+		message   string
+	}{
+		{"tomorrow", "expires_at must be a RFC 3339 timestamp\n"},
+		{time.Now().Add(-time.Hour).Format(time.RFC3339), "expires_at must be in the future\n"},
+	}
+	for _, tt := range tests {
+		body := url.Values{
+			"email":      []string{emptyToken.GetUserName()},
+			"context":    []string{"myteam"},
+			"expires_at": []string{tt.expiresAt},
+		}
+		req, err := http.NewRequest(http.MethodPost, "/roles/test/user", strings.NewReader(body.Encode()))
+		c.Assert(err, check.IsNil)
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set("Authorization", "bearer "+s.token.GetValue())
+		recorder := httptest.NewRecorder()
+		s.testServer.ServeHTTP(recorder, req)
+		c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+		c.Assert(recorder.Body.String(), check.Equals, tt.message)
+	}
+	emptyUser, err := emptyToken.User()
+	c.Assert(err, check.IsNil)
+	c.Assert(emptyUser.Roles, check.HasLen, 0)
+}
+
 func (s *S) TestRoleAssignEmptyContextValueAndGlobalContextType(c *check.C) {
 	_, err := permission.NewRole("test", "global", "")
 	c.Assert(err, check.IsNil)
@@ -33,6 +33,10 @@ func addPlan(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
 	cpuShare, _ := strconv.Atoi(InputValue(r, "cpushare"))
 	cpuMilli, _ := strconv.Atoi(InputValue(r, "cpumilli"))
 
+	minUnits, _ := strconv.ParseUint(InputValue(r, "minUnits"), 10, 32)
+	maxUnits, _ := strconv.ParseUint(InputValue(r, "maxUnits"), 10, 32)
+	gpu, _ := strconv.Atoi(InputValue(r, "gpu"))
+
 	isDefault, _ := strconv.ParseBool(InputValue(r, "default"))
 	memory := getSize(InputValue(r, "memory"))
 	swap := getSize(InputValue(r, "swap"))
@@ -43,6 +47,10 @@ func addPlan(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
 		CpuShare: cpuShare,
 		CPUMilli: cpuMilli,
 		Default:  isDefault,
+		MinUnits: uint(minUnits),
+		MaxUnits: uint(maxUnits),
+		GPU:      gpu,
+		GPUType:  InputValue(r, "gpuType"),
 	}
 	allowed := permission.Check(t, permission.PermPlanCreate)
 	if !allowed {
@@ -67,7 +75,7 @@ func addPlan(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
 			Message: err.Error(),
 		}
 	}
-	if err == appTypes.ErrLimitOfMemory || err == appTypes.ErrLimitOfCpuShare {
+	if err == appTypes.ErrLimitOfMemory || err == appTypes.ErrLimitOfCpuShare || err == appTypes.ErrLimitOfUnits || err == appTypes.ErrLimitOfGPU {
 		return &errors.HTTP{
 			Code:    http.StatusBadRequest,
 			Message: err.Error(),
@@ -51,6 +51,78 @@ func (s *S) TestPlanAdd(c *check.C) {
 	c.Assert(json.NewDecoder(recorder.Body).Decode(&fill), check.IsNil)
 }
 
+func (s *S) TestPlanAddWithUnitsLimits(c *check.C) {
+	s.mockService.Plan.OnCreate = func(plan appTypes.Plan) error {
+		c.Assert(plan, check.DeepEquals, appTypes.Plan{
+			Name:     "xyz",
+			Memory:   9223372036854775807,
+			CPUMilli: 2000,
+			MinUnits: 1,
+			MaxUnits: 10,
+		})
+		return nil
+	}
+	recorder := httptest.NewRecorder()
+	body := strings.NewReader("name=xyz&memory=9223372036854775807&cpumilli=2000&minUnits=1&maxUnits=10")
+	request, err := http.NewRequest("POST", "/plans", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusCreated)
+}
+
+func (s *S) TestPlanAddInvalidUnitsLimits(c *check.C) {
+	s.mockService.Plan.OnCreate = func(plan appTypes.Plan) error {
+		return appTypes.ErrLimitOfUnits
+	}
+	recorder := httptest.NewRecorder()
+	body := strings.NewReader("name=xyz&memory=9223372036854775807&minUnits=10&maxUnits=1")
+	request, err := http.NewRequest("POST", "/plans", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(recorder.Body.String(), check.Equals, appTypes.ErrLimitOfUnits.Error()+"\n")
+}
+
+func (s *S) TestPlanAddWithGPU(c *check.C) {
+	s.mockService.Plan.OnCreate = func(plan appTypes.Plan) error {
+		c.Assert(plan, check.DeepEquals, appTypes.Plan{
+			Name:     "gpu",
+			Memory:   9223372036854775807,
+			CPUMilli: 2000,
+			GPU:      2,
+			GPUType:  "nvidia-tesla-t4",
+		})
+		return nil
+	}
+	recorder := httptest.NewRecorder()
+	body := strings.NewReader("name=gpu&memory=9223372036854775807&cpumilli=2000&gpu=2&gpuType=nvidia-tesla-t4")
+	request, err := http.NewRequest("POST", "/plans", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusCreated)
+}
+
+func (s *S) TestPlanAddInvalidGPU(c *check.C) {
+	s.mockService.Plan.OnCreate = func(plan appTypes.Plan) error {
+		return appTypes.ErrLimitOfGPU
+	}
+	recorder := httptest.NewRecorder()
+	body := strings.NewReader("name=gpu&memory=9223372036854775807&gpuType=nvidia-tesla-t4")
+	request, err := http.NewRequest("POST", "/plans", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(recorder.Body.String(), check.Equals, appTypes.ErrLimitOfGPU.Error()+"\n")
+}
+
 func (s *S) TestPlanAddWithDeprecatedCPUShare(c *check.C) {
 	s.mockService.Plan.OnCreate = func(plan appTypes.Plan) error {
 		c.Assert(plan, check.DeepEquals, appTypes.Plan{
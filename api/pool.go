@@ -53,8 +53,23 @@ func getPoolHandler(w http.ResponseWriter, r *http.Request, t auth.Token) error
 // responses:
 //   200: OK
 //   204: No content
+//   304: Not modified
 //   401: Unauthorized
 func poolList(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	poolList, err := readablePools(r.Context(), t)
+	if err != nil {
+		return err
+	}
+	if len(poolList) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+	return writeJSONWithETag(w, r, poolList)
+}
+
+// readablePools returns the pools visible to the token, either because apps
+// may be created in them or because they may be read.
+func readablePools(ctx context.Context, t auth.Token) ([]pool.Pool, error) {
 	var teams, poolNames []string
 	isGlobal := false
 	contexts := permission.ContextsForPermission(t, permission.PermAppCreate)
@@ -74,19 +89,19 @@ func poolList(w http.ResponseWriter, r *http.Request, t auth.Token) error {
 	var pools []pool.Pool
 	var err error
 	if isGlobal {
-		pools, err = pool.ListAllPools(context.TODO())
+		pools, err = pool.ListAllPools(ctx)
 		if err != nil {
-			return err
+			return nil, err
 		}
 	} else {
-		pools, err = pool.ListPossiblePools(context.TODO(), teams)
+		pools, err = pool.ListPossiblePools(ctx, teams)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		if len(poolNames) > 0 {
-			namedPools, err := pool.ListPools(context.TODO(), poolNames...)
+			namedPools, err := pool.ListPools(ctx, poolNames...)
 			if err != nil {
-				return err
+				return nil, err
 			}
 			pools = append(pools, namedPools...)
 		}
@@ -100,12 +115,7 @@ func poolList(w http.ResponseWriter, r *http.Request, t auth.Token) error {
 		poolList = append(poolList, p)
 		poolsMap[p.Name] = struct{}{}
 	}
-	if len(poolList) == 0 {
-		w.WriteHeader(http.StatusNoContent)
-		return nil
-	}
-	w.Header().Set("Content-Type", "application/json")
-	return json.NewEncoder(w).Encode(poolList)
+	return poolList, nil
 }
 
 // title: pool create
@@ -299,6 +309,23 @@ func poolUpdateHandler(w http.ResponseWriter, r *http.Request, t auth.Token) (er
 		return permission.ErrUnauthorized
 	}
 	poolName := r.URL.Query().Get(":name")
+	dryRun, err := isDryRun(r)
+	if err != nil {
+		return err
+	}
+	if dryRun {
+		var updateOpts pool.UpdatePoolOptions
+		err = ParseInput(r, &updateOpts)
+		if err != nil {
+			return err
+		}
+		after, errValidate := pool.ValidatePoolUpdate(ctx, poolName, updateOpts)
+		if errValidate != nil {
+			return poolUpdateError(errValidate)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		return json.NewEncoder(w).Encode(after)
+	}
 	evt, err := event.New(&event.Opts{
 		Target:     event.Target{Type: event.TargetTypePool, Value: poolName},
 		Kind:       permission.PermPoolUpdate,
@@ -316,7 +343,26 @@ func poolUpdateHandler(w http.ResponseWriter, r *http.Request, t auth.Token) (er
 	if err != nil {
 		return err
 	}
+	before, err := pool.GetPoolByName(ctx, poolName)
+	if err == pool.ErrPoolNotFound {
+		return &terrors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+	}
+	if err != nil {
+		return err
+	}
 	err = pool.PoolUpdate(ctx, poolName, updateOpts)
+	if err != nil {
+		return poolUpdateError(err)
+	}
+	after, err := pool.GetPoolByName(ctx, poolName)
+	if err != nil {
+		return err
+	}
+	return evt.SetChanges(before, after)
+}
+
+// poolUpdateError converts errors of pool updates to their HTTP errors.
+func poolUpdateError(err error) error {
 	if err == pool.ErrPoolNotFound {
 		return &terrors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
 	}
@@ -326,6 +372,9 @@ func poolUpdateHandler(w http.ResponseWriter, r *http.Request, t auth.Token) (er
 			Message: err.Error(),
 		}
 	}
+	if v, ok := err.(*terrors.ValidationError); ok {
+		return &terrors.HTTP{Code: http.StatusBadRequest, Message: v.Message}
+	}
 	return err
 }
 
@@ -9,9 +9,11 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/tsuru/tsuru/app"
 	"github.com/tsuru/tsuru/auth"
 	"github.com/tsuru/tsuru/errors"
 	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/job"
 	"github.com/tsuru/tsuru/permission"
 	"github.com/tsuru/tsuru/servicemanager"
 	authTypes "github.com/tsuru/tsuru/types/auth"
@@ -200,8 +202,15 @@ func getTeamQuota(w http.ResponseWriter, r *http.Request, t auth.Token) error {
 	if err != nil {
 		return err
 	}
+	teamQuota := &team.Quota
+	if app.TeamQuotaUnit() != app.TeamQuotaUnitApps {
+		teamQuota, err = servicemanager.TeamQuota.Get(r.Context(), app.TeamQuotaItem(team.Name))
+		if err != nil {
+			return err
+		}
+	}
 	w.Header().Set("Content-Type", "application/json")
-	return json.NewEncoder(w).Encode(team.Quota)
+	return json.NewEncoder(w).Encode(teamQuota)
 }
 
 // title: update team quota
@@ -249,7 +258,122 @@ func changeTeamQuota(w http.ResponseWriter, r *http.Request, t auth.Token) (err
 			Message: "Invalid limit",
 		}
 	}
-	err = servicemanager.TeamQuota.SetLimit(r.Context(), team, limit)
+	err = servicemanager.TeamQuota.SetLimit(r.Context(), app.TeamQuotaItem(team.Name), limit)
+	if err == quota.ErrLimitLowerThanAllocated {
+		return &errors.HTTP{
+			Code:    http.StatusForbidden,
+			Message: err.Error(),
+		}
+	}
+	return err
+}
+
+type teamJobQuota struct {
+	Units  *quota.Quota `json:"units"`
+	Memory *quota.Quota `json:"memory"`
+}
+
+// title: team job quota
+// path: /teams/{name}/job-quota
+// method: GET
+// produce: application/json
+// responses:
+//   200: OK
+//   401: Unauthorized
+//   404: Team not found
+func getTeamJobQuota(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	teamName := r.URL.Query().Get(":name")
+	allowed := permission.Check(t, permission.PermTeamReadQuota, permission.Context(permTypes.CtxTeam, teamName))
+	if !allowed {
+		return permission.ErrUnauthorized
+	}
+	team, err := servicemanager.Team.FindByName(r.Context(), teamName)
+	if err == authTypes.ErrTeamNotFound {
+		return &errors.HTTP{
+			Code:    http.StatusNotFound,
+			Message: err.Error(),
+		}
+	}
+	if err != nil {
+		return err
+	}
+	var jobQuota teamJobQuota
+	jobQuota.Units, err = servicemanager.JobUnitsQuota.Get(r.Context(), job.UnitsQuotaItem(team.Name))
+	if err != nil {
+		return err
+	}
+	jobQuota.Memory, err = servicemanager.JobMemoryQuota.Get(r.Context(), job.MemoryQuotaItem(team.Name))
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(jobQuota)
+}
+
+// title: update team job quota
+// path: /teams/{name}/job-quota
+// method: PUT
+// consume: application/x-www-form-urlencoded
+// responses:
+//   200: Quota updated
+//   400: Invalid data
+//   401: Unauthorized
+//   403: Limit lower than allocated value
+//   404: Team not found
+func changeTeamJobQuota(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	teamName := r.URL.Query().Get(":name")
+	allowed := permission.Check(t, permission.PermTeamUpdateQuota, permission.Context(permTypes.CtxTeam, teamName))
+	if !allowed {
+		return permission.ErrUnauthorized
+	}
+	team, err := servicemanager.Team.FindByName(r.Context(), teamName)
+	if err == authTypes.ErrTeamNotFound {
+		return &errors.HTTP{
+			Code:    http.StatusNotFound,
+			Message: err.Error(),
+		}
+	}
+	if err != nil {
+		return err
+	}
+	limits := map[string]int{}
+	for _, name := range []string{"units", "memory"} {
+		value := InputValue(r, name)
+		if value == "" {
+			continue
+		}
+		limits[name], err = strconv.Atoi(value)
+		if err != nil {
+			return &errors.HTTP{
+				Code:    http.StatusBadRequest,
+				Message: "Invalid " + name + " limit",
+			}
+		}
+	}
+	if len(limits) == 0 {
+		return &errors.HTTP{
+			Code:    http.StatusBadRequest,
+			Message: "Either units or memory limit is required",
+		}
+	}
+	evt, err := event.New(&event.Opts{
+		Target:     event.Target{Type: event.TargetTypeTeam, Value: teamName},
+		Kind:       permission.PermTeamUpdateQuota,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		CustomData: event.FormToCustomData(InputFields(r)),
+		Allowed:    event.Allowed(permission.PermTeamReadEvents, permission.Context(permTypes.CtxTeam, teamName)),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	if limit, ok := limits["units"]; ok {
+		err = servicemanager.JobUnitsQuota.SetLimit(r.Context(), job.UnitsQuotaItem(team.Name), limit)
+	}
+	if limit, ok := limits["memory"]; ok && err == nil {
+		err = servicemanager.JobMemoryQuota.SetLimit(r.Context(), job.MemoryQuotaItem(team.Name), limit)
+	}
 	if err == quota.ErrLimitLowerThanAllocated {
 		return &errors.HTTP{
 			Code:    http.StatusForbidden,
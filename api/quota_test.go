@@ -464,6 +464,114 @@ func (s *QuotaSuite) TestChangeTeamQuotaTeamNotFound(c *check.C) {
 	c.Assert(recorder.Body.String(), check.Equals, authTypes.ErrTeamNotFound.Error()+"\n")
 }
 
+func (s *QuotaSuite) TestGetTeamJobQuota(c *check.C) {
+	s.mockService.Team.OnFindByName = func(s string) (*authTypes.Team, error) {
+		return &authTypes.Team{Name: "avengers"}, nil
+	}
+	s.mockService.JobUnitsQuota.OnGet = func(item quota.QuotaItem) (*quota.Quota, error) {
+		c.Assert(item.GetName(), check.Equals, "avengers")
+		return &quota.Quota{Limit: 4, InUse: 2}, nil
+	}
+	s.mockService.JobMemoryQuota.OnGet = func(item quota.QuotaItem) (*quota.Quota, error) {
+		c.Assert(item.GetName(), check.Equals, "avengers")
+		return &quota.Quota{Limit: 2048, InUse: 512}, nil
+	}
+	request, err := http.NewRequest("GET", "/teams/avengers/job-quota", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, "application/json")
+	var qt map[string]quota.Quota
+	err = json.NewDecoder(recorder.Body).Decode(&qt)
+	c.Assert(err, check.IsNil)
+	c.Assert(qt, check.DeepEquals, map[string]quota.Quota{
+		"units":  {Limit: 4, InUse: 2},
+		"memory": {Limit: 2048, InUse: 512},
+	})
+}
+
+func (s *QuotaSuite) TestGetTeamJobQuotaRequiresPermission(c *check.C) {
+	token := userWithPermission(c)
+	request, _ := http.NewRequest("GET", "/teams/avengers/job-quota", nil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
+
+func (s *QuotaSuite) TestChangeTeamJobQuota(c *check.C) {
+	s.mockService.Team.OnFindByName = func(s string) (*authTypes.Team, error) {
+		return &authTypes.Team{Name: "avengers"}, nil
+	}
+	s.mockService.JobUnitsQuota.OnSetLimit = func(item quota.QuotaItem, limit int) error {
+		c.Assert(item.GetName(), check.Equals, "avengers")
+		c.Assert(limit, check.Equals, 10)
+		return nil
+	}
+	var memoryChanged bool
+	s.mockService.JobMemoryQuota.OnSetLimit = func(item quota.QuotaItem, limit int) error {
+		memoryChanged = true
+		return nil
+	}
+	body := bytes.NewBufferString("units=10")
+	request, _ := http.NewRequest("PUT", "/teams/avengers/job-quota", body)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(memoryChanged, check.Equals, false)
+	c.Assert(eventtest.EventDesc{
+		Target: event.Target{Type: event.TargetTypeTeam, Value: "avengers"},
+		Owner:  s.token.GetUserName(),
+		Kind:   "team.update.quota",
+		StartCustomData: []map[string]interface{}{
+			{"name": ":name", "value": "avengers"},
+			{"name": "units", "value": "10"},
+		},
+	}, eventtest.HasEvent)
+}
+
+func (s *QuotaSuite) TestChangeTeamJobQuotaInvalidLimit(c *check.C) {
+	s.mockService.Team.OnFindByName = func(s string) (*authTypes.Team, error) {
+		return &authTypes.Team{Name: "avengers"}, nil
+	}
+	tests := []struct {
+		body    string
+		message string
+	}{
+		{body: "units=four", message: "Invalid units limit\n"},
+		{body: "memory=", message: "Either units or memory limit is required\n"},
+	}
+	for _, tt := range tests {
+		request, _ := http.NewRequest("PUT", "/teams/avengers/job-quota", bytes.NewBufferString(tt.body))
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+		recorder := httptest.NewRecorder()
+		s.testServer.ServeHTTP(recorder, request)
+		c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+		c.Assert(recorder.Body.String(), check.Equals, tt.message)
+	}
+}
+
+func (s *QuotaSuite) TestChangeTeamJobQuotaLimitLowerThanAllocated(c *check.C) {
+	s.mockService.Team.OnFindByName = func(s string) (*authTypes.Team, error) {
+		return &authTypes.Team{Name: "avengers"}, nil
+	}
+	s.mockService.JobMemoryQuota.OnSetLimit = func(item quota.QuotaItem, limit int) error {
+		return quota.ErrLimitLowerThanAllocated
+	}
+	body := bytes.NewBufferString("memory=128")
+	request, _ := http.NewRequest("PUT", "/teams/avengers/job-quota", body)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
+
 func (s *QuotaSuite) TestGetAppQuota(c *check.C) {
 	s.mockService.AppQuota.OnGet = func(item quota.QuotaItem) (*quota.Quota, error) {
 		c.Assert(item.GetName(), check.Equals, "civil")
@@ -0,0 +1,176 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/api/context"
+	internalConfig "github.com/tsuru/tsuru/config"
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+	"golang.org/x/time/rate"
+)
+
+// rateLimitCleanupInterval is how often limiters of idle clients are
+// discarded.
+const rateLimitCleanupInterval = 10 * time.Minute
+
+type rateLimitConfig struct {
+	RequestsPerMinute int              `json:"requests-per-minute"`
+	Burst             int              `json:"burst"`
+	Routes            []rateLimitRoute `json:"routes"`
+}
+
+// rateLimitRoute overrides the default rate limit for requests to a route,
+// identified by its path template, e.g. /apps/{app}/deploy, and optionally by
+// its method.
+type rateLimitRoute struct {
+	Method            string `json:"method"`
+	Path              string `json:"path"`
+	RequestsPerMinute int    `json:"requests-per-minute"`
+	Burst             int    `json:"burst"`
+}
+
+type clientLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// rateLimitMiddleware limits the number of requests each client may send to
+// the API, clients are identified by their token or, for requests without a
+// valid token, by their IP address. It must run after authTokenMiddleware so
+// that only validated tokens are used as keys.
+type rateLimitMiddleware struct {
+	config      rateLimitConfig
+	now         func() time.Time
+	mu          sync.Mutex
+	limiters    map[string]*clientLimiter
+	lastCleanup time.Time
+}
+
+// newRateLimitMiddleware returns the middleware configured in the
+// api:rate-limit key, or nil when rate limiting is disabled.
+func newRateLimitMiddleware() (*rateLimitMiddleware, error) {
+	var conf rateLimitConfig
+	err := internalConfig.UnmarshalConfig("api:rate-limit", &conf)
+	if err != nil {
+		if _, isNotFound := errors.Cause(err).(config.ErrKeyNotFound); isNotFound {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "unable to load api rate limit")
+	}
+	if conf.RequestsPerMinute <= 0 {
+		return nil, nil
+	}
+	for _, route := range conf.Routes {
+		if route.Path == "" {
+			return nil, errors.New("unable to load api rate limit: path is required in route overrides")
+		}
+	}
+	return &rateLimitMiddleware{
+		config:   conf,
+		now:      time.Now,
+		limiters: map[string]*clientLimiter{},
+	}, nil
+}
+
+func (m *rateLimitMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	key, requestsPerMinute, burst := m.limitFor(r)
+	if delay := m.reserve(key, requestsPerMinute, burst); delay > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(delay.Seconds()))))
+		context.AddRequestError(r, &tsuruErrors.HTTP{
+			Code:    http.StatusTooManyRequests,
+			Message: "API rate limit exceeded",
+		})
+		return
+	}
+	next(w, r)
+}
+
+// limitFor returns the limiter key of the request along with the limit
+// applied to it, route overrides have their own limiters.
+func (m *rateLimitMiddleware) limitFor(r *http.Request) (string, int, int) {
+	client := rateLimitClient(r)
+	pathTemplate := r.URL.Query().Get(":mux-path-template")
+	for _, route := range m.config.Routes {
+		if route.Path != pathTemplate {
+			continue
+		}
+		if route.Method != "" && !strings.EqualFold(route.Method, r.Method) {
+			continue
+		}
+		key := fmt.Sprintf("%s %s %s", strings.ToUpper(route.Method), route.Path, client)
+		return key, route.RequestsPerMinute, route.Burst
+	}
+	return client, m.config.RequestsPerMinute, m.config.Burst
+}
+
+// reserve consumes a request from the limiter identified by key, returning
+// how long the client must wait before retrying when the limit is exceeded.
+func (m *rateLimitMiddleware) reserve(key string, requestsPerMinute, burst int) time.Duration {
+	if requestsPerMinute <= 0 {
+		return 0
+	}
+	if burst <= 0 {
+		burst = requestsPerMinute
+	}
+	now := m.now()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cleanup(now)
+	cl, ok := m.limiters[key]
+	if !ok {
+		cl = &clientLimiter{
+			limiter: rate.NewLimiter(rate.Limit(float64(requestsPerMinute)/60), burst),
+		}
+		m.limiters[key] = cl
+	}
+	cl.lastSeen = now
+	reservation := cl.limiter.ReserveN(now, 1)
+	delay := reservation.DelayFrom(now)
+	if delay > 0 {
+		reservation.CancelAt(now)
+	}
+	return delay
+}
+
+func (m *rateLimitMiddleware) cleanup(now time.Time) {
+	if now.Sub(m.lastCleanup) < rateLimitCleanupInterval {
+		return
+	}
+	for key, cl := range m.limiters {
+		if now.Sub(cl.lastSeen) >= rateLimitCleanupInterval {
+			delete(m.limiters, key)
+		}
+	}
+	m.lastCleanup = now
+}
+
+// rateLimitClient identifies the client sending the request, tokens are
+// hashed so that they're not kept in memory. Requests with invalid tokens
+// are identified by their IP address, otherwise clients could bypass the
+// limit by sending a different token on each request.
+func rateLimitClient(r *http.Request) string {
+	if t := context.GetAuthToken(r); t != nil {
+		sum := sha256.Sum256([]byte(t.GetValue()))
+		return "token:" + hex.EncodeToString(sum[:])
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
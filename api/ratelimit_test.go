@@ -0,0 +1,181 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/api/context"
+	"github.com/tsuru/tsuru/auth"
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+	check "gopkg.in/check.v1"
+)
+
+func newTestRateLimitMiddleware(c *check.C, now *time.Time) *rateLimitMiddleware {
+	m, err := newRateLimitMiddleware()
+	c.Assert(err, check.IsNil)
+	c.Assert(m, check.NotNil)
+	m.now = func() time.Time { return *now }
+	return m
+}
+
+func (s *S) TestNewRateLimitMiddlewareDisabled(c *check.C) {
+	m, err := newRateLimitMiddleware()
+	c.Assert(err, check.IsNil)
+	c.Assert(m, check.IsNil)
+	config.Set("api:rate-limit:requests-per-minute", 0)
+	defer config.Unset("api:rate-limit")
+	m, err = newRateLimitMiddleware()
+	c.Assert(err, check.IsNil)
+	c.Assert(m, check.IsNil)
+}
+
+func (s *S) TestNewRateLimitMiddlewareInvalidRoute(c *check.C) {
+	config.Set("api:rate-limit", map[interface{}]interface{}{
+		"requests-per-minute": 60,
+		"routes": []interface{}{
+			map[interface{}]interface{}{"method": "POST", "requests-per-minute": 1},
+		},
+	})
+	defer config.Unset("api:rate-limit")
+	m, err := newRateLimitMiddleware()
+	c.Assert(err, check.ErrorMatches, ".*path is required in route overrides")
+	c.Assert(m, check.IsNil)
+}
+
+func (s *S) TestRateLimitMiddleware(c *check.C) {
+	config.Set("api:rate-limit:requests-per-minute", 60)
+	config.Set("api:rate-limit:burst", 2)
+	defer config.Unset("api:rate-limit")
+	now := time.Now()
+	m := newTestRateLimitMiddleware(c, &now)
+	otherToken := userWithPermission(c)
+	doRequest := func(token auth.Token) (*httptest.ResponseRecorder, *http.Request, *handlerLog) {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest("GET", "/apps", nil)
+		c.Assert(err, check.IsNil)
+		request.Header.Set("Authorization", "bearer "+token.GetValue())
+		context.SetAuthToken(request, token)
+		h, log := doHandler()
+		m.ServeHTTP(recorder, request, h)
+		return recorder, request, log
+	}
+	for i := 0; i < 2; i++ {
+		_, request, log := doRequest(s.token)
+		c.Assert(log.called, check.Equals, true)
+		c.Assert(context.GetRequestError(request), check.IsNil)
+	}
+	recorder, request, log := doRequest(s.token)
+	c.Assert(log.called, check.Equals, false)
+	c.Assert(recorder.Header().Get("Retry-After"), check.Equals, "1")
+	err := context.GetRequestError(request)
+	c.Assert(err, check.NotNil)
+	httpErr, ok := err.(*tsuruErrors.HTTP)
+	c.Assert(ok, check.Equals, true)
+	c.Assert(httpErr.Code, check.Equals, http.StatusTooManyRequests)
+	_, request, log = doRequest(otherToken)
+	c.Assert(log.called, check.Equals, true)
+	c.Assert(context.GetRequestError(request), check.IsNil)
+	now = now.Add(time.Second)
+	_, request, log = doRequest(s.token)
+	c.Assert(log.called, check.Equals, true)
+	c.Assert(context.GetRequestError(request), check.IsNil)
+}
+
+func (s *S) TestRateLimitMiddlewareByIP(c *check.C) {
+	config.Set("api:rate-limit:requests-per-minute", 1)
+	defer config.Unset("api:rate-limit")
+	now := time.Now()
+	m := newTestRateLimitMiddleware(c, &now)
+	doRequest := func(remoteAddr string) (*httptest.ResponseRecorder, *handlerLog) {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest("GET", "/info", nil)
+		c.Assert(err, check.IsNil)
+		request.RemoteAddr = remoteAddr
+		h, log := doHandler()
+		m.ServeHTTP(recorder, request, h)
+		return recorder, log
+	}
+	_, log := doRequest("10.0.0.1:4040")
+	c.Assert(log.called, check.Equals, true)
+	recorder, log := doRequest("10.0.0.1:4041")
+	c.Assert(log.called, check.Equals, false)
+	c.Assert(recorder.Header().Get("Retry-After"), check.Equals, "60")
+	_, log = doRequest("10.0.0.2:4040")
+	c.Assert(log.called, check.Equals, true)
+}
+
+func (s *S) TestRateLimitMiddlewareInvalidTokensByIP(c *check.C) {
+	config.Set("api:rate-limit:requests-per-minute", 1)
+	defer config.Unset("api:rate-limit")
+	now := time.Now()
+	m := newTestRateLimitMiddleware(c, &now)
+	doRequest := func(token string) *handlerLog {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest("GET", "/apps", nil)
+		c.Assert(err, check.IsNil)
+		request.RemoteAddr = "10.0.0.1:4040"
+		request.Header.Set("Authorization", token)
+		h, log := doHandler()
+		m.ServeHTTP(recorder, request, h)
+		return log
+	}
+	log := doRequest("bearer invalid1")
+	c.Assert(log.called, check.Equals, true)
+	log = doRequest("bearer invalid2")
+	c.Assert(log.called, check.Equals, false)
+}
+
+func (s *S) TestRateLimitMiddlewareRouteOverride(c *check.C) {
+	config.Set("api:rate-limit", map[interface{}]interface{}{
+		"requests-per-minute": 600,
+		"routes": []interface{}{
+			map[interface{}]interface{}{
+				"method":              "POST",
+				"path":                "/apps/{app}/deploy",
+				"requests-per-minute": 2,
+				"burst":               1,
+			},
+		},
+	})
+	defer config.Unset("api:rate-limit")
+	now := time.Now()
+	m := newTestRateLimitMiddleware(c, &now)
+	doRequest := func(method, url string) (*httptest.ResponseRecorder, *handlerLog) {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(method, url, nil)
+		c.Assert(err, check.IsNil)
+		request.Header.Set("Authorization", "bearer mytoken")
+		h, log := doHandler()
+		m.ServeHTTP(recorder, request, h)
+		return recorder, log
+	}
+	deployURL := "/apps/myapp/deploy?:mux-path-template=/apps/{app}/deploy"
+	_, log := doRequest("POST", deployURL)
+	c.Assert(log.called, check.Equals, true)
+	recorder, log := doRequest("POST", deployURL)
+	c.Assert(log.called, check.Equals, false)
+	c.Assert(recorder.Header().Get("Retry-After"), check.Equals, "30")
+	_, log = doRequest("GET", deployURL)
+	c.Assert(log.called, check.Equals, true)
+	_, log = doRequest("GET", "/apps?:mux-path-template=/apps")
+	c.Assert(log.called, check.Equals, true)
+}
+
+func (s *S) TestRateLimitMiddlewareCleanup(c *check.C) {
+	config.Set("api:rate-limit:requests-per-minute", 1)
+	defer config.Unset("api:rate-limit")
+	now := time.Now()
+	m := newTestRateLimitMiddleware(c, &now)
+	c.Assert(m.reserve("ip:10.0.0.1", 1, 1), check.Equals, time.Duration(0))
+	c.Assert(m.limiters, check.HasLen, 1)
+	now = now.Add(rateLimitCleanupInterval)
+	c.Assert(m.reserve("ip:10.0.0.2", 1, 1), check.Equals, time.Duration(0))
+	c.Assert(m.limiters, check.HasLen, 1)
+	c.Assert(m.limiters["ip:10.0.0.2"], check.NotNil)
+}
@@ -0,0 +1,412 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/api/context"
+	"github.com/tsuru/tsuru/auth"
+	internalConfig "github.com/tsuru/tsuru/config"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/log"
+	tsuruRedis "github.com/tsuru/tsuru/redis"
+	"github.com/tsuru/tsuru/router"
+	redis "gopkg.in/redis.v3"
+)
+
+const (
+	responseCacheHeader          = "X-Tsuru-Cache"
+	responseCacheCleanupInterval = 10 * time.Minute
+	responseCacheRedisPrefix     = "tsuru:api:response-cache:"
+)
+
+// responseCacheRoute is a GET route whose responses are cached, routes in
+// the same group are invalidated together by events on any of the group
+// target types.
+type responseCacheRoute struct {
+	path        string
+	group       string
+	targetTypes []event.TargetType
+}
+
+var responseCacheRoutes = []responseCacheRoute{
+	{
+		path:        "/services",
+		group:       "services",
+		targetTypes: []event.TargetType{event.TargetTypeService, event.TargetTypeServiceInstance, event.TargetTypeServiceBroker},
+	},
+	{
+		path:        "/services/{name}/plans",
+		group:       "service-plans",
+		targetTypes: []event.TargetType{event.TargetTypeService, event.TargetTypeServiceBroker},
+	},
+	{
+		path:        "/plans",
+		group:       "plans",
+		targetTypes: []event.TargetType{event.TargetTypePlan},
+	},
+	{
+		path:        "/platforms",
+		group:       "platforms",
+		targetTypes: []event.TargetType{event.TargetTypePlatform},
+	},
+	{
+		path:        "/pools",
+		group:       "pools",
+		targetTypes: []event.TargetType{event.TargetTypePool},
+	},
+}
+
+// defaultResponseCacheTTLs are the TTLs, in seconds, of each group of routes
+// when they're not set in api:response-cache:ttls.
+var defaultResponseCacheTTLs = map[string]int{
+	"services":      30,
+	"service-plans": 60,
+	"plans":         300,
+	"platforms":     300,
+	"pools":         60,
+}
+
+type responseCacheConfig struct {
+	Enabled bool           `json:"enabled"`
+	TTLs    map[string]int `json:"ttls"`
+}
+
+// responseCacheStorage stores cached responses. Each group has a
+// generation, which is part of the keys of its entries, so invalidating a
+// group only requires changing its generation.
+type responseCacheStorage interface {
+	generation(group string) (string, error)
+	invalidate(group string) error
+	get(key string) ([]byte, bool, error)
+	set(key string, value []byte, ttl time.Duration) error
+}
+
+// cachedResponse is a response stored in the cache, only the headers needed
+// to replay it are kept.
+type cachedResponse struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header"`
+	Body   []byte      `json:"body"`
+}
+
+var cachedResponseHeaders = []string{"Content-Type", "ETag", "X-Total-Count", "Link"}
+
+// responseCacheMiddleware caches the responses of expensive read routes,
+// like the service catalog, plans, platforms and pools lists. Responses are
+// cached by URL and by the permissions of the token, so that tokens with the
+// same permissions share cached responses, and are invalidated when events
+// on the targets of their routes finish.
+type responseCacheMiddleware struct {
+	storage responseCacheStorage
+	ttls    map[string]time.Duration
+	// invalidations tracks the invalidations running in background.
+	invalidations sync.WaitGroup
+}
+
+var _ event.Sink = &responseCacheMiddleware{}
+
+// newResponseCacheMiddleware returns the middleware configured in the
+// api:response-cache key, or nil when the cache is disabled. Responses are
+// stored in Redis when it's configured with the redis-* keys of the same
+// section, or in memory otherwise.
+func newResponseCacheMiddleware() (*responseCacheMiddleware, error) {
+	var conf responseCacheConfig
+	err := internalConfig.UnmarshalConfig("api:response-cache", &conf)
+	if err != nil {
+		if _, isNotFound := errors.Cause(err).(config.ErrKeyNotFound); isNotFound {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "unable to load api response cache")
+	}
+	if !conf.Enabled {
+		return nil, nil
+	}
+	m := &responseCacheMiddleware{ttls: map[string]time.Duration{}}
+	for group, ttl := range defaultResponseCacheTTLs {
+		if confTTL, ok := conf.TTLs[group]; ok {
+			ttl = confTTL
+		}
+		m.ttls[group] = time.Duration(ttl) * time.Second
+	}
+	for group := range conf.TTLs {
+		if _, ok := defaultResponseCacheTTLs[group]; !ok {
+			return nil, errors.Errorf("unable to load api response cache: unknown route group %q", group)
+		}
+	}
+	client, err := tsuruRedis.NewRedisDefaultConfig("api-response-cache", router.ConfigGetterFromPrefix("api:response-cache"), nil)
+	switch err {
+	case nil:
+		m.storage = &redisResponseCacheStorage{client: client}
+	case tsuruRedis.ErrNoRedisConfig:
+		m.storage = newMemoryResponseCacheStorage()
+	default:
+		return nil, errors.Wrap(err, "unable to connect to api response cache redis")
+	}
+	return m, nil
+}
+
+func (m *responseCacheMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	t := context.GetAuthToken(r)
+	route := findResponseCacheRoute(r)
+	if t == nil || route == nil || m.ttls[route.group] <= 0 {
+		next(w, r)
+		return
+	}
+	key, err := m.key(route.group, r, t)
+	if err != nil {
+		log.Errorf("[response cache] unable to build key: %v", err)
+		next(w, r)
+		return
+	}
+	data, found, err := m.storage.get(key)
+	if err != nil {
+		log.Errorf("[response cache] unable to get %q: %v", key, err)
+	}
+	if found {
+		var cached cachedResponse
+		if err = json.Unmarshal(data, &cached); err == nil {
+			writeCachedResponse(w, r, &cached)
+			return
+		}
+		log.Errorf("[response cache] unable to decode %q: %v", key, err)
+	}
+	w.Header().Set(responseCacheHeader, "MISS")
+	recorder := &responseCacheRecorder{ResponseWriter: w, status: http.StatusOK}
+	next(recorder, r)
+	if context.GetRequestError(r) != nil || (recorder.status != http.StatusOK && recorder.status != http.StatusNoContent) {
+		return
+	}
+	cached := cachedResponse{Status: recorder.status, Header: http.Header{}, Body: recorder.body.Bytes()}
+	for _, name := range cachedResponseHeaders {
+		if values := w.Header().Values(name); len(values) > 0 {
+			cached.Header[http.CanonicalHeaderKey(name)] = values
+		}
+	}
+	data, err = json.Marshal(cached)
+	if err == nil {
+		err = m.storage.set(key, data, m.ttls[route.group])
+	}
+	if err != nil {
+		log.Errorf("[response cache] unable to set %q: %v", key, err)
+	}
+}
+
+func findResponseCacheRoute(r *http.Request) *responseCacheRoute {
+	if r.Method != http.MethodGet {
+		return nil
+	}
+	pathTemplate := r.URL.Query().Get(":mux-path-template")
+	for i := range responseCacheRoutes {
+		if responseCacheRoutes[i].path == pathTemplate {
+			return &responseCacheRoutes[i]
+		}
+	}
+	return nil
+}
+
+// key returns the cache key of the request, built from the current
+// generation of the group, the URL and the permissions of the token, which
+// are the only inputs of the cached routes.
+func (m *responseCacheMiddleware) key(group string, r *http.Request, t auth.Token) (string, error) {
+	gen, err := m.storage.generation(group)
+	if err != nil {
+		return "", err
+	}
+	perms, err := t.Permissions()
+	if err != nil {
+		return "", err
+	}
+	permNames := make([]string, len(perms))
+	for i, p := range perms {
+		permNames[i] = fmt.Sprintf("%s %s %s", p.Scheme.FullName(), p.Context.CtxType, p.Context.Value)
+	}
+	sort.Strings(permNames)
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n%s\n", r.URL.Path, r.URL.Query().Encode())
+	for _, name := range permNames {
+		fmt.Fprintln(h, name)
+	}
+	return fmt.Sprintf("%s:%s:%s", group, gen, hex.EncodeToString(h.Sum(nil))), nil
+}
+
+func writeCachedResponse(w http.ResponseWriter, r *http.Request, cached *cachedResponse) {
+	for name, values := range cached.Header {
+		w.Header()[name] = values
+	}
+	w.Header().Set(responseCacheHeader, "HIT")
+	if etag := cached.Header.Get("ETag"); etag != "" && etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.Header().Del("Content-Type")
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.WriteHeader(cached.Status)
+	w.Write(cached.Body)
+}
+
+// Notify invalidates the groups of routes affected by the finished event,
+// it's called by the event package as an event.Sink.
+func (m *responseCacheMiddleware) Notify(evtID string) {
+	m.invalidations.Add(1)
+	go func() {
+		defer m.invalidations.Done()
+		err := m.invalidateEvent(evtID)
+		if err != nil {
+			log.Errorf("[response cache] unable to invalidate cache for event %s: %v", evtID, err)
+		}
+	}()
+}
+
+func (m *responseCacheMiddleware) invalidateEvent(evtID string) error {
+	evt, err := event.GetByHexID(evtID)
+	if err != nil {
+		return err
+	}
+	targetTypes := map[event.TargetType]struct{}{evt.Target.Type: {}}
+	for _, et := range evt.ExtraTargets {
+		targetTypes[et.Target.Type] = struct{}{}
+	}
+	for _, route := range responseCacheRoutes {
+		for _, targetType := range route.targetTypes {
+			if _, ok := targetTypes[targetType]; ok {
+				err = m.storage.invalidate(route.group)
+				if err != nil {
+					return err
+				}
+				break
+			}
+		}
+	}
+	return nil
+}
+
+type responseCacheRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseCacheRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseCacheRecorder) Write(data []byte) (int, error) {
+	r.body.Write(data)
+	return r.ResponseWriter.Write(data)
+}
+
+type memoryResponseCacheEntry struct {
+	value    []byte
+	expireAt time.Time
+}
+
+type memoryResponseCacheStorage struct {
+	now         func() time.Time
+	mu          sync.Mutex
+	generations map[string]int
+	entries     map[string]memoryResponseCacheEntry
+	lastCleanup time.Time
+}
+
+func newMemoryResponseCacheStorage() *memoryResponseCacheStorage {
+	return &memoryResponseCacheStorage{
+		now:         time.Now,
+		generations: map[string]int{},
+		entries:     map[string]memoryResponseCacheEntry{},
+	}
+}
+
+func (s *memoryResponseCacheStorage) generation(group string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return strconv.Itoa(s.generations[group]), nil
+}
+
+func (s *memoryResponseCacheStorage) invalidate(group string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.generations[group]++
+	return nil
+}
+
+func (s *memoryResponseCacheStorage) get(key string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.entries[key]
+	if !ok || !s.now().Before(entry.expireAt) {
+		return nil, false, nil
+	}
+	return entry.value, true, nil
+}
+
+func (s *memoryResponseCacheStorage) set(key string, value []byte, ttl time.Duration) error {
+	now := s.now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cleanup(now)
+	s.entries[key] = memoryResponseCacheEntry{value: value, expireAt: now.Add(ttl)}
+	return nil
+}
+
+// cleanup discards expired entries, including those of past generations,
+// which are never read again.
+func (s *memoryResponseCacheStorage) cleanup(now time.Time) {
+	if now.Sub(s.lastCleanup) < responseCacheCleanupInterval {
+		return
+	}
+	for key, entry := range s.entries {
+		if !now.Before(entry.expireAt) {
+			delete(s.entries, key)
+		}
+	}
+	s.lastCleanup = now
+}
+
+// redisResponseCacheStorage shares the cache among API instances, so that
+// invalidations in any of them are seen by all.
+type redisResponseCacheStorage struct {
+	client tsuruRedis.Client
+}
+
+func (s *redisResponseCacheStorage) generation(group string) (string, error) {
+	gen, err := s.client.Get(responseCacheRedisPrefix + "generation:" + group).Result()
+	if err == redis.Nil {
+		return "0", nil
+	}
+	return gen, err
+}
+
+func (s *redisResponseCacheStorage) invalidate(group string) error {
+	gen := strconv.FormatInt(time.Now().UnixNano(), 10)
+	return s.client.Set(responseCacheRedisPrefix+"generation:"+group, gen, 0).Err()
+}
+
+func (s *redisResponseCacheStorage) get(key string) ([]byte, bool, error) {
+	value, err := s.client.Get(responseCacheRedisPrefix + key).Bytes()
+	if err == redis.Nil {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+func (s *redisResponseCacheStorage) set(key string, value []byte, ttl time.Duration) error {
+	return s.client.Set(responseCacheRedisPrefix+key, value, ttl).Err()
+}
@@ -0,0 +1,210 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/api/context"
+	"github.com/tsuru/tsuru/auth"
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/permission"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+	check "gopkg.in/check.v1"
+)
+
+func newTestResponseCacheMiddleware(c *check.C) *responseCacheMiddleware {
+	config.Set("api:response-cache:enabled", true)
+	defer config.Unset("api:response-cache")
+	m, err := newResponseCacheMiddleware()
+	c.Assert(err, check.IsNil)
+	c.Assert(m, check.NotNil)
+	c.Assert(m.storage, check.FitsTypeOf, &memoryResponseCacheStorage{})
+	return m
+}
+
+// cachedRequest sends a request to the response cache middleware, calls
+// counts the calls to the handler behind it.
+func cachedRequest(c *check.C, m *responseCacheMiddleware, t auth.Token, url string, calls *int, header http.Header) (*httptest.ResponseRecorder, *http.Request) {
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest("GET", url, nil)
+	c.Assert(err, check.IsNil)
+	for name, values := range header {
+		request.Header[name] = values
+	}
+	context.SetAuthToken(request, t)
+	m.ServeHTTP(recorder, request, func(w http.ResponseWriter, r *http.Request) {
+		*calls++
+		writeJSONWithETag(w, r, map[string]int{"calls": *calls})
+	})
+	return recorder, request
+}
+
+func (s *S) TestNewResponseCacheMiddlewareDisabled(c *check.C) {
+	m, err := newResponseCacheMiddleware()
+	c.Assert(err, check.IsNil)
+	c.Assert(m, check.IsNil)
+	config.Set("api:response-cache:enabled", false)
+	defer config.Unset("api:response-cache")
+	m, err = newResponseCacheMiddleware()
+	c.Assert(err, check.IsNil)
+	c.Assert(m, check.IsNil)
+}
+
+func (s *S) TestNewResponseCacheMiddlewareTTLs(c *check.C) {
+	config.Set("api:response-cache", map[interface{}]interface{}{
+		"enabled": true,
+		"ttls":    map[interface{}]interface{}{"plans": 10, "pools": 0},
+	})
+	defer config.Unset("api:response-cache")
+	m, err := newResponseCacheMiddleware()
+	c.Assert(err, check.IsNil)
+	c.Assert(m.ttls, check.DeepEquals, map[string]time.Duration{
+		"services":      30 * time.Second,
+		"service-plans": time.Minute,
+		"plans":         10 * time.Second,
+		"platforms":     5 * time.Minute,
+		"pools":         0,
+	})
+}
+
+func (s *S) TestNewResponseCacheMiddlewareUnknownGroup(c *check.C) {
+	config.Set("api:response-cache", map[interface{}]interface{}{
+		"enabled": true,
+		"ttls":    map[interface{}]interface{}{"apps": 10},
+	})
+	defer config.Unset("api:response-cache")
+	m, err := newResponseCacheMiddleware()
+	c.Assert(err, check.ErrorMatches, `unable to load api response cache: unknown route group "apps"`)
+	c.Assert(m, check.IsNil)
+}
+
+func (s *S) TestResponseCacheMiddleware(c *check.C) {
+	m := newTestResponseCacheMiddleware(c)
+	var calls int
+	url := "/1.0/plans?:mux-path-template=/plans"
+	recorder, _ := cachedRequest(c, m, s.token, url, &calls, nil)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(recorder.Header().Get(responseCacheHeader), check.Equals, "MISS")
+	c.Assert(recorder.Body.String(), check.Equals, `{"calls":1}`+"\n")
+	etag := recorder.Header().Get("ETag")
+	recorder, _ = cachedRequest(c, m, s.token, url, &calls, nil)
+	c.Assert(calls, check.Equals, 1)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(recorder.Header().Get(responseCacheHeader), check.Equals, "HIT")
+	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, "application/json")
+	c.Assert(recorder.Header().Get("ETag"), check.Equals, etag)
+	c.Assert(recorder.Body.String(), check.Equals, `{"calls":1}`+"\n")
+	recorder, _ = cachedRequest(c, m, s.token, url, &calls, http.Header{"If-None-Match": {etag}})
+	c.Assert(calls, check.Equals, 1)
+	c.Assert(recorder.Code, check.Equals, http.StatusNotModified)
+	c.Assert(recorder.Body.String(), check.Equals, "")
+	recorder, _ = cachedRequest(c, m, s.token, url+"&limit=1", &calls, nil)
+	c.Assert(calls, check.Equals, 2)
+	c.Assert(recorder.Header().Get(responseCacheHeader), check.Equals, "MISS")
+}
+
+func (s *S) TestResponseCacheMiddlewareByPermissions(c *check.C) {
+	m := newTestResponseCacheMiddleware(c)
+	var calls int
+	url := "/1.0/pools?:mux-path-template=/pools"
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermPoolRead,
+		Context: permission.Context(permTypes.CtxTeam, s.team.Name),
+	})
+	cachedRequest(c, m, s.token, url, &calls, nil)
+	recorder, _ := cachedRequest(c, m, token, url, &calls, nil)
+	c.Assert(calls, check.Equals, 2)
+	c.Assert(recorder.Body.String(), check.Equals, `{"calls":2}`+"\n")
+	recorder, _ = cachedRequest(c, m, token, url, &calls, nil)
+	c.Assert(calls, check.Equals, 2)
+	c.Assert(recorder.Body.String(), check.Equals, `{"calls":2}`+"\n")
+}
+
+func (s *S) TestResponseCacheMiddlewareIgnoresOtherRoutes(c *check.C) {
+	m := newTestResponseCacheMiddleware(c)
+	var calls int
+	for i := 0; i < 2; i++ {
+		recorder, _ := cachedRequest(c, m, s.token, "/1.0/apps?:mux-path-template=/apps", &calls, nil)
+		c.Assert(recorder.Header().Get(responseCacheHeader), check.Equals, "")
+	}
+	c.Assert(calls, check.Equals, 2)
+}
+
+func (s *S) TestResponseCacheMiddlewareErrorsNotCached(c *check.C) {
+	m := newTestResponseCacheMiddleware(c)
+	var calls int
+	for i := 0; i < 2; i++ {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest("GET", "/1.0/platforms?:mux-path-template=/platforms", nil)
+		c.Assert(err, check.IsNil)
+		context.SetAuthToken(request, s.token)
+		m.ServeHTTP(recorder, request, func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			context.AddRequestError(r, &tsuruErrors.HTTP{Code: http.StatusServiceUnavailable, Message: "unavailable"})
+		})
+	}
+	c.Assert(calls, check.Equals, 2)
+}
+
+func (s *S) TestResponseCacheMiddlewareInvalidatedByEvents(c *check.C) {
+	m := newTestResponseCacheMiddleware(c)
+	var plansCalls, poolsCalls int
+	plansURL := "/1.0/plans?:mux-path-template=/plans"
+	poolsURL := "/1.0/pools?:mux-path-template=/pools"
+	cachedRequest(c, m, s.token, plansURL, &plansCalls, nil)
+	cachedRequest(c, m, s.token, poolsURL, &poolsCalls, nil)
+	evt, err := event.New(&event.Opts{
+		Target:  event.Target{Type: event.TargetTypePlan, Value: "plan1"},
+		Owner:   s.token,
+		Kind:    permission.PermPlanCreate,
+		Allowed: event.Allowed(permission.PermPlanReadEvents),
+	})
+	c.Assert(err, check.IsNil)
+	err = evt.Done(nil)
+	c.Assert(err, check.IsNil)
+	m.Notify(evt.UniqueID.Hex())
+	m.invalidations.Wait()
+	recorder, _ := cachedRequest(c, m, s.token, plansURL, &plansCalls, nil)
+	c.Assert(plansCalls, check.Equals, 2)
+	c.Assert(recorder.Header().Get(responseCacheHeader), check.Equals, "MISS")
+	recorder, _ = cachedRequest(c, m, s.token, poolsURL, &poolsCalls, nil)
+	c.Assert(poolsCalls, check.Equals, 1)
+	c.Assert(recorder.Header().Get(responseCacheHeader), check.Equals, "HIT")
+}
+
+func (s *S) TestMemoryResponseCacheStorage(c *check.C) {
+	now := time.Now()
+	storage := newMemoryResponseCacheStorage()
+	storage.now = func() time.Time { return now }
+	gen, err := storage.generation("plans")
+	c.Assert(err, check.IsNil)
+	c.Assert(gen, check.Equals, "0")
+	key := fmt.Sprintf("plans:%s:k1", gen)
+	err = storage.set(key, []byte("value"), time.Minute)
+	c.Assert(err, check.IsNil)
+	value, found, err := storage.get(key)
+	c.Assert(err, check.IsNil)
+	c.Assert(found, check.Equals, true)
+	c.Assert(string(value), check.Equals, "value")
+	now = now.Add(time.Minute)
+	_, found, err = storage.get(key)
+	c.Assert(err, check.IsNil)
+	c.Assert(found, check.Equals, false)
+	err = storage.invalidate("plans")
+	c.Assert(err, check.IsNil)
+	gen, err = storage.generation("plans")
+	c.Assert(err, check.IsNil)
+	c.Assert(gen, check.Equals, "1")
+	now = now.Add(responseCacheCleanupInterval)
+	err = storage.set("plans:1:k1", []byte("value"), time.Minute)
+	c.Assert(err, check.IsNil)
+	c.Assert(storage.entries, check.HasLen, 1)
+}
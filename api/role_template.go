@@ -0,0 +1,181 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/servicemanager"
+	authTypes "github.com/tsuru/tsuru/types/auth"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+)
+
+func roleTemplateError(err error) error {
+	switch err {
+	case permTypes.ErrRoleTemplateNotFound:
+		return &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+	case permTypes.ErrRoleTemplateAlreadyExists, permTypes.ErrRoleAlreadyExists:
+		return &errors.HTTP{Code: http.StatusConflict, Message: err.Error()}
+	case permTypes.ErrInvalidRoleTemplateName, permTypes.ErrInvalidRoleName, permTypes.ErrInvalidPermissionName, permTypes.ErrRemoveBuiltinRoleTemplate:
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: err.Error()}
+	}
+	switch err.(type) {
+	case *permTypes.ErrPermissionNotFound, *permTypes.ErrPermissionNotAllowed:
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: err.Error()}
+	}
+	return err
+}
+
+// title: role template list
+// path: /role-templates
+// method: GET
+// produce: application/json
+// responses:
+//	200: OK
+//	401: Unauthorized
+func listRoleTemplates(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	if !(permission.Check(t, permission.PermRoleCreate) ||
+		permission.Check(t, permission.PermRoleTemplateCreate) ||
+		permission.Check(t, permission.PermRoleTemplateDelete)) {
+		return permission.ErrUnauthorized
+	}
+	templates, err := permission.ListRoleTemplates()
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(templates)
+}
+
+// title: role template create
+// path: /role-templates
+// method: POST
+// consume: application/x-www-form-urlencoded
+// responses:
+//	201: Role template created
+//	400: Invalid data
+//	401: Unauthorized
+//	409: Role template already exists
+func addRoleTemplate(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	if !permission.Check(t, permission.PermRoleTemplateCreate) {
+		return permission.ErrUnauthorized
+	}
+	name := InputValue(r, "name")
+	if name == "" {
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: permTypes.ErrInvalidRoleTemplateName.Error()}
+	}
+	evt, err := event.New(&event.Opts{
+		Target:     event.Target{Type: event.TargetTypeRoleTemplate, Value: name},
+		Kind:       permission.PermRoleTemplateCreate,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		CustomData: event.FormToCustomData(InputFields(r)),
+		Allowed:    event.Allowed(permission.PermRoleReadEvents),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	permissions, _ := InputValues(r, "permission")
+	err = permission.AddRoleTemplate(permission.RoleTemplate{
+		Name:        name,
+		Description: InputValue(r, "description"),
+		SchemeNames: permissions,
+	})
+	if err != nil {
+		return roleTemplateError(err)
+	}
+	w.WriteHeader(http.StatusCreated)
+	return nil
+}
+
+// title: role template remove
+// path: /role-templates/{name}
+// method: DELETE
+// responses:
+//	200: Role template removed
+//	400: Invalid data
+//	401: Unauthorized
+//	404: Role template not found
+func removeRoleTemplate(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	if !permission.Check(t, permission.PermRoleTemplateDelete) {
+		return permission.ErrUnauthorized
+	}
+	name := r.URL.Query().Get(":name")
+	evt, err := event.New(&event.Opts{
+		Target:     event.Target{Type: event.TargetTypeRoleTemplate, Value: name},
+		Kind:       permission.PermRoleTemplateDelete,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		CustomData: event.FormToCustomData(InputFields(r)),
+		Allowed:    event.Allowed(permission.PermRoleReadEvents),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	return roleTemplateError(permission.DestroyRoleTemplate(name))
+}
+
+// title: role template instantiate
+// path: /role-templates/{name}/roles
+// method: POST
+// consume: application/x-www-form-urlencoded
+// produce: application/json
+// responses:
+//	201: Role created
+//	400: Invalid data
+//	401: Unauthorized
+//	404: Role template or team not found
+//	409: Role already exists
+func instantiateRoleTemplate(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	// instantiating a template creates a role and adds permissions to it.
+	if !permission.Check(t, permission.PermRoleCreate) || !permission.Check(t, permission.PermRoleUpdatePermissionAdd) {
+		return permission.ErrUnauthorized
+	}
+	teamName := InputValue(r, "team")
+	if teamName == "" {
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: "team is required"}
+	}
+	template, err := permission.FindRoleTemplate(r.URL.Query().Get(":name"))
+	if err != nil {
+		return roleTemplateError(err)
+	}
+	_, err = servicemanager.Team.FindByName(r.Context(), teamName)
+	if err != nil {
+		if err == authTypes.ErrTeamNotFound {
+			return &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+		}
+		return err
+	}
+	roleName := InputValue(r, "name")
+	if roleName == "" {
+		roleName = template.RoleName(teamName)
+	}
+	evt, err := event.New(&event.Opts{
+		Target:     event.Target{Type: event.TargetTypeRole, Value: roleName},
+		Kind:       permission.PermRoleCreate,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		CustomData: event.FormToCustomData(InputFields(r)),
+		Allowed:    event.Allowed(permission.PermRoleReadEvents),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	role, err := template.Instantiate(roleName)
+	if err != nil {
+		return roleTemplateError(err)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	return json.NewEncoder(w).Encode(role)
+}
@@ -0,0 +1,249 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/event/eventtest"
+	"github.com/tsuru/tsuru/permission"
+	authTypes "github.com/tsuru/tsuru/types/auth"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestListRoleTemplates(c *check.C) {
+	err := permission.AddRoleTemplate(permission.RoleTemplate{Name: "deployer", SchemeNames: []string{"app.deploy"}})
+	c.Assert(err, check.IsNil)
+	req, err := http.NewRequest(http.MethodGet, "/1.13/role-templates", nil)
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermRoleCreate,
+		Context: permission.Context(permTypes.CtxGlobal, ""),
+	})
+	req.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, req)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, "application/json")
+	var templates []permission.RoleTemplate
+	err = json.NewDecoder(recorder.Body).Decode(&templates)
+	c.Assert(err, check.IsNil)
+	c.Assert(templates, check.HasLen, 4)
+	c.Assert(templates[0].Name, check.Equals, "developer")
+	c.Assert(templates[0].Builtin, check.Equals, true)
+	c.Assert(templates[3].Name, check.Equals, "deployer")
+	c.Assert(templates[3].Builtin, check.Equals, false)
+}
+
+func (s *S) TestListRoleTemplatesUnauthorized(c *check.C) {
+	req, err := http.NewRequest(http.MethodGet, "/1.13/role-templates", nil)
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c)
+	req.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, req)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
+
+func (s *S) TestAddRoleTemplate(c *check.C) {
+	body := strings.NewReader("name=deployer&description=deploys&permission=app.deploy&permission=app.read")
+	req, err := http.NewRequest(http.MethodPost, "/1.13/role-templates", body)
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermRoleTemplateCreate,
+		Context: permission.Context(permTypes.CtxGlobal, ""),
+	})
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, req)
+	c.Assert(recorder.Code, check.Equals, http.StatusCreated)
+	t, err := permission.FindRoleTemplate("deployer")
+	c.Assert(err, check.IsNil)
+	c.Assert(t.Description, check.Equals, "deploys")
+	c.Assert(t.SchemeNames, check.DeepEquals, []string{"app.deploy", "app.read"})
+	c.Assert(eventtest.EventDesc{
+		Target: event.Target{Type: event.TargetTypeRoleTemplate, Value: "deployer"},
+		Owner:  token.GetUserName(),
+		Kind:   "role.template.create",
+		StartCustomData: []map[string]interface{}{
+			{"name": "name", "value": "deployer"},
+		},
+	}, eventtest.HasEvent)
+}
+
+func (s *S) TestAddRoleTemplateInvalidPermission(c *check.C) {
+	body := strings.NewReader("name=creator&permission=team.create")
+	req, err := http.NewRequest(http.MethodPost, "/1.13/role-templates", body)
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, req)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+}
+
+func (s *S) TestAddRoleTemplateBuiltinName(c *check.C) {
+	body := strings.NewReader("name=developer&permission=app.read")
+	req, err := http.NewRequest(http.MethodPost, "/1.13/role-templates", body)
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, req)
+	c.Assert(recorder.Code, check.Equals, http.StatusConflict)
+}
+
+func (s *S) TestRemoveRoleTemplate(c *check.C) {
+	err := permission.AddRoleTemplate(permission.RoleTemplate{Name: "deployer", SchemeNames: []string{"app.deploy"}})
+	c.Assert(err, check.IsNil)
+	req, err := http.NewRequest(http.MethodDelete, "/1.13/role-templates/deployer", nil)
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermRoleTemplateDelete,
+		Context: permission.Context(permTypes.CtxGlobal, ""),
+	})
+	req.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, req)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	_, err = permission.FindRoleTemplate("deployer")
+	c.Assert(err, check.Equals, permTypes.ErrRoleTemplateNotFound)
+	c.Assert(eventtest.EventDesc{
+		Target: event.Target{Type: event.TargetTypeRoleTemplate, Value: "deployer"},
+		Owner:  token.GetUserName(),
+		Kind:   "role.template.delete",
+	}, eventtest.HasEvent)
+}
+
+func (s *S) TestRemoveRoleTemplateBuiltin(c *check.C) {
+	req, err := http.NewRequest(http.MethodDelete, "/1.13/role-templates/developer", nil)
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, req)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(recorder.Body.String(), check.Equals, permTypes.ErrRemoveBuiltinRoleTemplate.Error()+"\n")
+}
+
+func (s *S) TestRemoveRoleTemplateNotFound(c *check.C) {
+	req, err := http.NewRequest(http.MethodDelete, "/1.13/role-templates/unknown", nil)
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, req)
+	c.Assert(recorder.Code, check.Equals, http.StatusNotFound)
+}
+
+func (s *S) TestInstantiateRoleTemplate(c *check.C) {
+	s.mockService.Team.OnFindByName = func(name string) (*authTypes.Team, error) {
+		c.Assert(name, check.Equals, "myteam")
+		return &authTypes.Team{Name: name}, nil
+	}
+	req, err := http.NewRequest(http.MethodPost, "/1.13/role-templates/developer/roles", strings.NewReader("team=myteam"))
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermRoleCreate,
+		Context: permission.Context(permTypes.CtxGlobal, ""),
+	}, permission.Permission{
+		Scheme:  permission.PermRoleUpdatePermissionAdd,
+		Context: permission.Context(permTypes.CtxGlobal, ""),
+	})
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, req)
+	c.Assert(recorder.Code, check.Equals, http.StatusCreated)
+	var role permission.Role
+	err = json.NewDecoder(recorder.Body).Decode(&role)
+	c.Assert(err, check.IsNil)
+	c.Assert(role.Name, check.Equals, "myteam-developer")
+	dbRole, err := permission.FindRole("myteam-developer")
+	c.Assert(err, check.IsNil)
+	c.Assert(dbRole.ContextType, check.Equals, permTypes.CtxTeam)
+	c.Assert(dbRole.Template, check.Equals, "developer")
+	c.Assert(dbRole.SchemeNames, check.DeepEquals, []string{"app", "job", "service-instance", "team.read.events", "team.read.quota"})
+	c.Assert(eventtest.EventDesc{
+		Target: event.Target{Type: event.TargetTypeRole, Value: "myteam-developer"},
+		Owner:  token.GetUserName(),
+		Kind:   "role.create",
+		StartCustomData: []map[string]interface{}{
+			{"name": "team", "value": "myteam"},
+		},
+	}, eventtest.HasEvent)
+}
+
+func (s *S) TestInstantiateRoleTemplateWithoutPermissionAdd(c *check.C) {
+	req, err := http.NewRequest(http.MethodPost, "/1.13/role-templates/developer/roles", strings.NewReader("team=myteam"))
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermRoleCreate,
+		Context: permission.Context(permTypes.CtxGlobal, ""),
+	})
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, req)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+	_, err = permission.FindRole("myteam-developer")
+	c.Assert(err, check.Equals, permTypes.ErrRoleNotFound)
+}
+
+func (s *S) TestInstantiateRoleTemplateWithName(c *check.C) {
+	req, err := http.NewRequest(http.MethodPost, "/1.13/role-templates/auditor/roles", strings.NewReader("team=myteam&name=myteam-readers"))
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, req)
+	c.Assert(recorder.Code, check.Equals, http.StatusCreated)
+	role, err := permission.FindRole("myteam-readers")
+	c.Assert(err, check.IsNil)
+	c.Assert(role.Template, check.Equals, "auditor")
+	req, err = http.NewRequest(http.MethodPost, "/1.13/role-templates/auditor/roles", strings.NewReader("team=myteam&name=myteam-readers"))
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder = httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, req)
+	c.Assert(recorder.Code, check.Equals, http.StatusConflict)
+}
+
+func (s *S) TestInstantiateRoleTemplateTeamNotFound(c *check.C) {
+	s.mockService.Team.OnFindByName = func(name string) (*authTypes.Team, error) {
+		return nil, authTypes.ErrTeamNotFound
+	}
+	req, err := http.NewRequest(http.MethodPost, "/1.13/role-templates/developer/roles", strings.NewReader("team=unknown"))
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, req)
+	c.Assert(recorder.Code, check.Equals, http.StatusNotFound)
+}
+
+func (s *S) TestInstantiateRoleTemplateNotFound(c *check.C) {
+	req, err := http.NewRequest(http.MethodPost, "/1.13/role-templates/unknown/roles", strings.NewReader("team=myteam"))
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, req)
+	c.Assert(recorder.Code, check.Equals, http.StatusNotFound)
+}
+
+func (s *S) TestInstantiateRoleTemplateWithoutTeam(c *check.C) {
+	req, err := http.NewRequest(http.MethodPost, "/1.13/role-templates/developer/roles", nil)
+	c.Assert(err, check.IsNil)
+	req.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, req)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+}
@@ -7,7 +7,10 @@ package api
 import (
 	"net/http"
 	"net/url"
+	"reflect"
 	"regexp"
+	"runtime"
+	"strconv"
 	"strings"
 
 	"github.com/gorilla/mux"
@@ -22,6 +25,13 @@ const (
 
 	routeNameVariable    = ":mux-route-name"
 	pathTemplateVariable = ":mux-path-template"
+
+	// LatestVersion is the current major version of the API. Routes added
+	// with 1.x versions are also served under it, unless a route with the
+	// same method and path is added with LatestVersion, so that handlers may
+	// change their responses in the new version without breaking old
+	// clients.
+	LatestVersion = "2"
 )
 
 type Route struct {
@@ -31,14 +41,18 @@ type Route struct {
 
 func NewRouter() *DelayedRouter {
 	return &DelayedRouter{
-		mux:    mux.NewRouter(),
-		routes: map[*mux.Route]*Route{},
+		mux:            mux.NewRouter(),
+		routes:         map[*mux.Route]*Route{},
+		latestVersions: map[string]string{},
 	}
 }
 
 type DelayedRouter struct {
 	mux    *mux.Router
 	routes map[*mux.Route]*Route
+	// latestVersions holds the highest version added for each method and
+	// path, which is the one served under LatestVersion.
+	latestVersions map[string]string
 }
 
 func (r *DelayedRouter) registerMatch(req *http.Request, match mux.RouteMatch) {
@@ -59,21 +73,41 @@ func (r *DelayedRouter) registerMatch(req *http.Request, match mux.RouteMatch) {
 }
 
 func (r *DelayedRouter) addRoute(name, version, path string, h http.Handler, methods ...string) *mux.Route {
+	if name == "" {
+		name = handlerName(h)
+	}
 	muxRoute := r.mux.NewRoute().Handler(h).Methods(methods...)
 	route := &Route{route: muxRoute, version: version}
 	r.routes[muxRoute] = route
 	versionRegexp := regexp.MustCompile("/(?P<version>[0-9.]+)/")
+	for _, method := range methods {
+		key := method + " " + path
+		if latest, ok := r.latestVersions[key]; !ok || versionLess(latest, version) {
+			r.latestVersions[key] = version
+		}
+	}
 	versionedRoute := muxRoute.MatcherFunc(func(httpRequest *http.Request, rm *mux.RouteMatch) bool {
 		d := versionRegexp.FindStringSubmatch(httpRequest.URL.Path)
-		return len(d) > 1 && r.routes[muxRoute].version == d[1]
+		if len(d) < 2 {
+			return false
+		}
+		if d[1] == LatestVersion {
+			return r.latestVersions[httpRequest.Method+" "+path] == route.version
+		}
+		return route.version == d[1]
 	}).PathPrefix(versionMatcher).Path(path)
-	plainRoute := r.mux.NewRoute().Path(path).Handler(h).Methods(methods...)
+	// Requests without version are served by the legacy routes.
+	if version != LatestVersion {
+		plainRoute := r.mux.NewRoute().Path(path).Handler(h).Methods(methods...)
+		if name != "" {
+			plainRoute.Name(name)
+		}
+	}
 	if name != "" {
-		plainRoute.Name(name)
 		versionedRoute.Name(name)
 	}
 	for _, method := range methods {
-		observability.PrePopulateMetrics(method, path)
+		observability.PrePopulateMetrics(name, method, path)
 	}
 	return muxRoute
 }
@@ -102,3 +136,35 @@ func (r *DelayedRouter) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	observability.StartSpan(req)
 	context.SetDelayedHandler(req, match.Handler)
 }
+
+// handlerName returns the name of the function handling h, without its
+// package, which names the routes added without a name.
+func handlerName(h http.Handler) string {
+	v := reflect.ValueOf(h)
+	if v.Kind() != reflect.Func {
+		return ""
+	}
+	fn := runtime.FuncForPC(v.Pointer())
+	if fn == nil {
+		return ""
+	}
+	name := fn.Name()
+	name = name[strings.LastIndex(name, "/")+1:]
+	name = name[strings.Index(name, ".")+1:]
+	return strings.TrimSuffix(name, "-fm")
+}
+
+// versionLess reports whether the version a is lower than b, comparing their
+// dot separated numbers.
+func versionLess(a, b string) bool {
+	partsA := strings.Split(a, ".")
+	partsB := strings.Split(b, ".")
+	for i := 0; i < len(partsA) && i < len(partsB); i++ {
+		na, _ := strconv.Atoi(partsA[i])
+		nb, _ := strconv.Atoi(partsB[i])
+		if na != nb {
+			return na < nb
+		}
+	}
+	return len(partsA) < len(partsB)
+}
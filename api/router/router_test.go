@@ -134,3 +134,106 @@ func (s *S) TestDelayedRouterAddNamed(c *check.C) {
 	c.Assert(err, check.IsNil)
 	c.Assert(tpl, check.Equals, "/{version:[0-9.]+}/dream/{world}")
 }
+
+func dreamHandler(w http.ResponseWriter, r *http.Request) {}
+
+func (s *S) TestDelayedRouterHandlerName(c *check.C) {
+	router := NewRouter()
+	router.Add("1.0", "GET", "/dream/{world}", http.HandlerFunc(dreamHandler))
+	for _, prefix := range []string{"/", "/1.0/"} {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest("GET", fmt.Sprintf("%vdream/tel'aran'rhiod", prefix), nil)
+		c.Assert(err, check.IsNil)
+		router.ServeHTTP(recorder, request)
+		c.Assert(request.URL.Query().Get(":mux-route-name"), check.Equals, "dreamHandler")
+	}
+}
+
+func (s *S) TestHandlerName(c *check.C) {
+	c.Assert(handlerName(http.HandlerFunc(dreamHandler)), check.Equals, "dreamHandler")
+	c.Assert(handlerName(http.NotFoundHandler()), check.Equals, "NotFound")
+	c.Assert(handlerName(http.FileServer(http.Dir("/"))), check.Equals, "")
+}
+
+func (s *S) TestLatestVersionServesLegacyRoutes(c *check.C) {
+	router := NewRouter()
+	var version string
+	router.Add("1.0", "GET", "/dream/{world}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		version = "1.0"
+	}))
+	router.Add("1.13", "GET", "/dream/{world}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		version = "1.13"
+	}))
+	router.Add("1.2", "GET", "/dream/{world}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		version = "1.2"
+	}))
+	router.Add("1.0", "POST", "/dream/{world}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		version = "1.0"
+	}))
+	tests := []struct {
+		method   string
+		path     string
+		expected string
+	}{
+		{"GET", "/2/dream/tel'aran'rhiod", "1.13"},
+		{"POST", "/2/dream/tel'aran'rhiod", "1.0"},
+		{"GET", "/1.2/dream/tel'aran'rhiod", "1.2"},
+		{"GET", "/dream/tel'aran'rhiod", "1.0"},
+	}
+	for _, tt := range tests {
+		version = ""
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(tt.method, tt.path, nil)
+		c.Assert(err, check.IsNil)
+		router.ServeHTTP(recorder, request)
+		runDelayedHandler(recorder, request)
+		c.Assert(version, check.Equals, tt.expected, check.Commentf("%s %s", tt.method, tt.path))
+		c.Assert(request.URL.Query().Get(":world"), check.Equals, "tel'aran'rhiod")
+	}
+}
+
+func (s *S) TestLatestVersionRoute(c *check.C) {
+	router := NewRouter()
+	var version string
+	router.Add(LatestVersion, "GET", "/dream/{world}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		version = LatestVersion
+	}))
+	router.Add("1.0", "GET", "/dream/{world}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		version = "1.0"
+	}))
+	router.Add(LatestVersion, "GET", "/nightmare", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		version = LatestVersion
+	}))
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"/2/dream/tel'aran'rhiod", LatestVersion},
+		{"/1.0/dream/tel'aran'rhiod", "1.0"},
+		{"/dream/tel'aran'rhiod", "1.0"},
+		{"/2/nightmare", LatestVersion},
+	}
+	for _, tt := range tests {
+		version = ""
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest("GET", tt.path, nil)
+		c.Assert(err, check.IsNil)
+		router.ServeHTTP(recorder, request)
+		runDelayedHandler(recorder, request)
+		c.Assert(version, check.Equals, tt.expected, check.Commentf("%s", tt.path))
+	}
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest("GET", "/nightmare", nil)
+	c.Assert(err, check.IsNil)
+	router.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNotFound)
+}
+
+func (s *S) TestVersionLess(c *check.C) {
+	c.Assert(versionLess("1.0", "1.1"), check.Equals, true)
+	c.Assert(versionLess("1.2", "1.13"), check.Equals, true)
+	c.Assert(versionLess("1.13", "1.2"), check.Equals, false)
+	c.Assert(versionLess("1.13", "2"), check.Equals, true)
+	c.Assert(versionLess("2", "2"), check.Equals, false)
+	c.Assert(versionLess("1", "1.0"), check.Equals, true)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/tsuru/tsuru/app"
 	"github.com/tsuru/tsuru/auth"
 	"github.com/tsuru/tsuru/errors"
 	"github.com/tsuru/tsuru/event"
@@ -95,12 +96,16 @@ func addAutoScaleUnits(w http.ResponseWriter, r *http.Request, t auth.Token) (er
 		return err
 	}
 	defer func() { evt.Done(err) }()
-	return a.AutoScale(spec)
+	err = a.AutoScale(spec)
+	if _, ok := err.(*app.PlanUnitsLimitError); ok {
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: err.Error()}
+	}
+	return err
 }
 
 // title: remove unit auto scale
 // path: /apps/{app}/units/autoscale
-// method: POST
+// method: DELETE
 // consume: application/json
 // responses:
 //   200: Ok
@@ -32,20 +32,28 @@ import (
 	"github.com/tsuru/tsuru/api/tracker"
 	"github.com/tsuru/tsuru/app"
 	"github.com/tsuru/tsuru/app/bind"
+	"github.com/tsuru/tsuru/app/hibernate"
 	"github.com/tsuru/tsuru/app/image"
 	"github.com/tsuru/tsuru/app/image/gc"
 	"github.com/tsuru/tsuru/app/version"
 	"github.com/tsuru/tsuru/applog"
+	"github.com/tsuru/tsuru/audit"
 	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/auth/ldapsync"
 	_ "github.com/tsuru/tsuru/auth/native"
 	_ "github.com/tsuru/tsuru/auth/oauth"
+	_ "github.com/tsuru/tsuru/auth/oidc"
+	"github.com/tsuru/tsuru/auth/rolegrant"
 	_ "github.com/tsuru/tsuru/auth/saml"
+	"github.com/tsuru/tsuru/auth/teamtoken"
 	"github.com/tsuru/tsuru/autoscale"
 	"github.com/tsuru/tsuru/db"
 	"github.com/tsuru/tsuru/event"
+	eventKafka "github.com/tsuru/tsuru/event/kafka"
 	"github.com/tsuru/tsuru/event/webhook"
 	"github.com/tsuru/tsuru/hc"
 	"github.com/tsuru/tsuru/healer"
+	"github.com/tsuru/tsuru/job"
 	"github.com/tsuru/tsuru/log"
 	"github.com/tsuru/tsuru/provision"
 	"github.com/tsuru/tsuru/provision/cluster"
@@ -112,6 +120,10 @@ func setupServices() error {
 	if err != nil {
 		return err
 	}
+	servicemanager.ServiceAccount, err = auth.ServiceAccountService()
+	if err != nil {
+		return err
+	}
 	servicemanager.AppCache, err = app.CacheService()
 	if err != nil {
 		return err
@@ -144,6 +156,14 @@ func setupServices() error {
 	if err != nil {
 		return err
 	}
+	servicemanager.JobUnitsQuota, err = job.UnitsQuotaService()
+	if err != nil {
+		return err
+	}
+	servicemanager.JobMemoryQuota, err = job.MemoryQuotaService()
+	if err != nil {
+		return err
+	}
 	servicemanager.Webhook, err = webhook.WebhookService()
 	if err != nil {
 		return err
@@ -188,6 +208,14 @@ func setupServices() error {
 	if err != nil {
 		return err
 	}
+	servicemanager.Job, err = job.JobService()
+	if err != nil {
+		return err
+	}
+	servicemanager.Audit, err = audit.AuditService()
+	if err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -224,6 +252,10 @@ func RunServer(dry bool) http.Handler {
 		m.Add("1.0", http.MethodGet, "/", Handler(index))
 	}
 	m.Add("1.0", http.MethodGet, "/info", AuthorizationRequiredHandler(info))
+	m.Add("1.13", http.MethodGet, "/openapi.json", Handler(openAPISpec))
+	m.Add("1.13", http.MethodPost, "/graphql", AuthorizationRequiredHandler(graphQLQuery))
+	m.Add("1.13", http.MethodGet, "/audit", AuthorizationRequiredHandler(auditList))
+	m.Add("1.13", http.MethodPost, "/batch", AuthorizationRequiredHandler(batch))
 
 	m.Add("1.0", http.MethodGet, "/services/instances", AuthorizationRequiredHandler(serviceInstances))
 	m.Add("1.0", http.MethodPost, "/services/{service}/instances", AuthorizationRequiredHandler(createServiceInstance))
@@ -255,9 +287,19 @@ func RunServer(dry bool) http.Handler {
 	m.Add("1.0", http.MethodGet, "/apps/{app}", AuthorizationRequiredHandler(appInfo))
 	m.Add("1.0", http.MethodDelete, "/apps/{app}", AuthorizationRequiredHandler(appDelete))
 	m.Add("1.0", http.MethodPut, "/apps/{app}", AuthorizationRequiredHandler(updateApp))
+	m.Add("1.13", http.MethodPost, "/apps/{app}/rename", AuthorizationRequiredHandler(renameApp))
+	m.Add("1.13", http.MethodGet, "/apps/{app}/export", AuthorizationRequiredHandler(exportApp))
+	m.Add("1.13", http.MethodPost, "/apps/import", AuthorizationRequiredHandler(importApp))
+	m.Add("1.13", http.MethodPut, "/apps/{app}/pools/{pool}", AuthorizationRequiredHandler(setAppExtraPool))
+	m.Add("1.13", http.MethodDelete, "/apps/{app}/pools/{pool}", AuthorizationRequiredHandler(removeAppExtraPool))
+	m.Add("1.13", http.MethodPost, "/apps/{app}/maintenance", AuthorizationRequiredHandler(setAppMaintenance))
+	m.Add("1.13", http.MethodDelete, "/apps/{app}/maintenance", AuthorizationRequiredHandler(unsetAppMaintenance))
+	m.Add("1.13", http.MethodPost, "/apps/{app}/freeze", AuthorizationRequiredHandler(freezeApp))
+	m.Add("1.13", http.MethodDelete, "/apps/{app}/freeze", AuthorizationRequiredHandler(unfreezeApp))
 	m.Add("1.0", http.MethodPost, "/apps/{app}/cname", AuthorizationRequiredHandler(setCName))
 	m.Add("1.0", http.MethodDelete, "/apps/{app}/cname", AuthorizationRequiredHandler(unsetCName))
 	m.Add("1.0", http.MethodPost, "/apps/{app}/run", AuthorizationRequiredHandler(runCommand))
+	m.Add("1.13", http.MethodGet, "/apps/{app}/runs/{id}", AuthorizationRequiredHandler(runInfo))
 	m.Add("1.0", http.MethodPost, "/apps/{app}/restart", AuthorizationRequiredHandler(restart))
 	m.Add("1.0", http.MethodPost, "/apps/{app}/start", AuthorizationRequiredHandler(start))
 	m.Add("1.0", http.MethodPost, "/apps/{app}/stop", AuthorizationRequiredHandler(stop))
@@ -274,9 +316,14 @@ func RunServer(dry bool) http.Handler {
 	m.Add("1.9", http.MethodGet, "/apps/{app}/units/autoscale", AuthorizationRequiredHandler(autoScaleUnitsInfo))
 	m.Add("1.9", http.MethodPost, "/apps/{app}/units/autoscale", AuthorizationRequiredHandler(addAutoScaleUnits))
 	m.Add("1.9", http.MethodDelete, "/apps/{app}/units/autoscale", AuthorizationRequiredHandler(removeAutoScaleUnits))
+	m.Add("1.13", http.MethodGet, "/apps/{app}/units/metrics", AuthorizationRequiredHandler(unitsMetrics))
+	m.Add("1.13", http.MethodGet, "/apps/{app}/resources/recommendations", AuthorizationRequiredHandler(appResourceRecommendations))
+	m.Add("1.13", http.MethodGet, "/apps/{app}/timeline", AuthorizationRequiredHandler(appTimeline))
 	m.Add("1.0", http.MethodPost, "/apps/{app}/units/register", AuthorizationRequiredHandler(registerUnit))
 	m.Add("1.0", http.MethodPost, "/apps/{app}/units/{unit}", AuthorizationRequiredHandler(setUnitStatus))
 	m.Add("1.12", http.MethodDelete, "/apps/{app}/units/{unit}", AuthorizationRequiredHandler(killUnit))
+	m.Add("1.13", http.MethodPost, "/apps/{app}/units/{unit}/restart", AuthorizationRequiredHandler(restartUnit))
+	m.Add("1.13", http.MethodPost, "/apps/{app}/units/{unit}/debug", AuthorizationRequiredHandler(debugUnit))
 	m.Add("1.0", http.MethodPut, "/apps/{app}/teams/{team}", AuthorizationRequiredHandler(grantAppAccess))
 	m.Add("1.0", http.MethodDelete, "/apps/{app}/teams/{team}", AuthorizationRequiredHandler(revokeAppAccess))
 	m.AddNamed("log-get", "1.0", http.MethodGet, "/apps/{app}/log", AuthorizationRequiredHandler(appLog))
@@ -284,7 +331,10 @@ func RunServer(dry bool) http.Handler {
 	m.Add("1.0", http.MethodPost, "/apps/{app}/log", AuthorizationRequiredHandler(addLog))
 	m.Add("1.0", http.MethodPost, "/apps/{app}/deploy/rollback", AuthorizationRequiredHandler(deployRollback))
 	m.Add("1.4", http.MethodPut, "/apps/{app}/deploy/rollback/update", AuthorizationRequiredHandler(deployRollbackUpdate))
+	m.Add("1.13", http.MethodGet, "/apps/{app}/deploy/rollback/envs", AuthorizationRequiredHandler(deployRollbackEnvDiff))
 	m.Add("1.3", http.MethodPost, "/apps/{app}/deploy/rebuild", AuthorizationRequiredHandler(deployRebuild))
+	m.Add("1.13", http.MethodPost, "/apps/{app}/deploy/canary/promote", AuthorizationRequiredHandler(deployCanaryPromote))
+	m.Add("1.13", http.MethodPost, "/apps/{app}/deploy/canary/abort", AuthorizationRequiredHandler(deployCanaryAbort))
 	m.Add("1.0", http.MethodGet, "/apps/{app}/metric/envs", AuthorizationRequiredHandler(appMetricEnvs))
 	m.Add("1.0", http.MethodPost, "/apps/{app}/routes", AuthorizationRequiredHandler(appRebuildRoutes))
 	m.Add("1.2", http.MethodGet, "/apps/{app}/certificate", AuthorizationRequiredHandler(listCertificates))
@@ -307,7 +357,11 @@ func RunServer(dry bool) http.Handler {
 	m.Add("1.3", http.MethodPost, "/events/blocks", AuthorizationRequiredHandler(eventBlockAdd))
 	m.Add("1.3", http.MethodDelete, "/events/blocks/{uuid}", AuthorizationRequiredHandler(eventBlockRemove))
 	m.Add("1.1", http.MethodGet, "/events/kinds", AuthorizationRequiredHandler(kindList))
+	m.Add("1.13", http.MethodGet, "/events/summary", AuthorizationRequiredHandler(eventSummary))
+	m.Add("1.13", http.MethodPost, "/events/cancel", AuthorizationRequiredHandler(eventBulkCancel))
+	m.Add("1.13", http.MethodPost, "/events/owner", AuthorizationRequiredHandler(eventOwnerReassign))
 	m.Add("1.1", http.MethodGet, "/events/{uuid}", AuthorizationRequiredHandler(eventInfo))
+	m.Add("1.13", http.MethodGet, "/events/{uuid}/diff", AuthorizationRequiredHandler(eventDiff))
 	m.Add("1.1", http.MethodPost, "/events/{uuid}/cancel", AuthorizationRequiredHandler(eventCancel))
 
 	m.Add("1.6", http.MethodGet, "/events/webhooks", AuthorizationRequiredHandler(webhookList))
@@ -315,6 +369,10 @@ func RunServer(dry bool) http.Handler {
 	m.Add("1.6", http.MethodGet, "/events/webhooks/{name}", AuthorizationRequiredHandler(webhookInfo))
 	m.Add("1.6", http.MethodPut, "/events/webhooks/{name}", AuthorizationRequiredHandler(webhookUpdate))
 	m.Add("1.6", http.MethodDelete, "/events/webhooks/{name}", AuthorizationRequiredHandler(webhookDelete))
+	m.Add("1.13", http.MethodGet, "/events/webhooks/{name}/deadletters", AuthorizationRequiredHandler(webhookDeadLetterList))
+	m.Add("1.13", http.MethodPost, "/events/webhooks/{name}/deadletters/{id}/redispatch", AuthorizationRequiredHandler(webhookDeadLetterRedispatch))
+	m.Add("1.13", http.MethodPost, "/events/webhooks/{name}/dispatch", AuthorizationRequiredHandler(webhookDispatch))
+	m.Add("1.13", http.MethodPost, "/events/webhooks/{name}/test", AuthorizationRequiredHandler(webhookTestFire))
 
 	m.Add("1.0", http.MethodGet, "/platforms", AuthorizationRequiredHandler(platformList))
 	m.Add("1.0", http.MethodPost, "/platforms", AuthorizationRequiredHandler(platformAdd))
@@ -331,9 +389,10 @@ func RunServer(dry bool) http.Handler {
 	m.Add("1.0", http.MethodPost, "/apps/{appname}/diff", AuthorizationRequiredHandler(diffDeploy))
 	m.Add("1.5", http.MethodPost, "/apps/{appname}/build", AuthorizationRequiredHandler(build))
 
-	// Shell also doesn't use {app} on purpose. Middlewares don't play well
-	// with websocket.
+	// Shell and log stream also don't use {app} on purpose. Middlewares don't
+	// play well with websocket.
 	m.Add("1.0", http.MethodGet, "/apps/{appname}/shell", http.HandlerFunc(remoteShellHandler))
+	m.Add("1.13", http.MethodGet, "/apps/{appname}/log/ws", http.HandlerFunc(appLogStream))
 
 	m.Add("1.0", http.MethodGet, "/users", AuthorizationRequiredHandler(listUsers))
 	m.Add("1.0", http.MethodPost, "/users", Handler(createUser))
@@ -343,6 +402,7 @@ func RunServer(dry bool) http.Handler {
 
 	m.Add("1.0", http.MethodPost, "/auth/saml", Handler(samlCallbackLogin))
 	m.Add("1.0", http.MethodGet, "/auth/saml", Handler(samlMetadata))
+	m.Add("1.13", http.MethodGet, "/auth/ldap-sync", AuthorizationRequiredHandler(ldapSyncReport))
 
 	m.Add("1.0", http.MethodPost, "/users/{email}/password", Handler(resetPassword))
 	m.Add("1.0", http.MethodPost, "/users/{email}/tokens", Handler(login))
@@ -353,6 +413,16 @@ func RunServer(dry bool) http.Handler {
 	m.Add("1.0", http.MethodDelete, "/users", AuthorizationRequiredHandler(removeUser))
 	m.Add("1.0", http.MethodGet, "/users/api-key", AuthorizationRequiredHandler(showAPIToken))
 	m.Add("1.0", http.MethodPost, "/users/api-key", AuthorizationRequiredHandler(regenerateAPIToken))
+	m.Add("1.13", http.MethodPost, "/users/2fa", AuthorizationRequiredHandler(enrollTwoFactor))
+	m.Add("1.13", http.MethodPut, "/users/2fa", AuthorizationRequiredHandler(enableTwoFactor))
+	m.Add("1.13", http.MethodDelete, "/users/2fa", AuthorizationRequiredHandler(disableTwoFactor))
+	m.Add("1.13", http.MethodPost, "/users/2fa/recovery-codes", AuthorizationRequiredHandler(regenerateRecoveryCodes))
+	m.Add("1.13", http.MethodDelete, "/users/{email}/2fa", AuthorizationRequiredHandler(resetTwoFactor))
+	m.Add("1.13", http.MethodGet, "/users/{email}/sessions", AuthorizationRequiredHandler(listSessions))
+	m.Add("1.13", http.MethodDelete, "/users/{email}/sessions/{id}", AuthorizationRequiredHandler(revokeSession))
+	m.Add("1.13", http.MethodDelete, "/users/{email}/lockout", AuthorizationRequiredHandler(unlockUser))
+	m.Add("1.13", http.MethodPost, "/users/{email}/impersonate", AuthorizationRequiredHandler(impersonateUser))
+	m.Add("1.13", http.MethodDelete, "/users/impersonate", AuthorizationRequiredHandler(endImpersonation))
 
 	m.Add("1.0", http.MethodGet, "/logs", websocket.Handler(addLogs))
 
@@ -363,6 +433,10 @@ func RunServer(dry bool) http.Handler {
 	m.Add("1.4", http.MethodGet, "/teams/{name}", AuthorizationRequiredHandler(teamInfo))
 	m.Add("1.12", http.MethodGet, "/teams/{name}/quota", AuthorizationRequiredHandler(getTeamQuota))
 	m.Add("1.12", http.MethodPut, "/teams/{name}/quota", AuthorizationRequiredHandler(changeTeamQuota))
+	m.Add("1.13", http.MethodPut, "/teams/{name}/allowlist", AuthorizationRequiredHandler(setTeamAllowlist))
+	m.Add("1.13", http.MethodPut, "/teams/{name}/parent", AuthorizationRequiredHandler(setTeamParent))
+	m.Add("1.13", http.MethodGet, "/teams/{name}/job-quota", AuthorizationRequiredHandler(getTeamJobQuota))
+	m.Add("1.13", http.MethodPut, "/teams/{name}/job-quota", AuthorizationRequiredHandler(changeTeamJobQuota))
 
 	m.Add("1.0", http.MethodPost, "/swap", AuthorizationRequiredHandler(swap))
 
@@ -403,9 +477,15 @@ func RunServer(dry bool) http.Handler {
 	m.Add("1.0", http.MethodGet, "/role/default", AuthorizationRequiredHandler(listDefaultRoles))
 	m.Add("1.0", http.MethodPost, "/role/default", AuthorizationRequiredHandler(addDefaultRole))
 	m.Add("1.0", http.MethodDelete, "/role/default", AuthorizationRequiredHandler(removeDefaultRole))
+	m.Add("1.13", http.MethodGet, "/role-templates", AuthorizationRequiredHandler(listRoleTemplates))
+	m.Add("1.13", http.MethodPost, "/role-templates", AuthorizationRequiredHandler(addRoleTemplate))
+	m.Add("1.13", http.MethodDelete, "/role-templates/{name}", AuthorizationRequiredHandler(removeRoleTemplate))
+	m.Add("1.13", http.MethodPost, "/role-templates/{name}/roles", AuthorizationRequiredHandler(instantiateRoleTemplate))
 	m.Add("1.0", http.MethodGet, "/permissions", AuthorizationRequiredHandler(listPermissions))
 	m.Add("1.6", http.MethodPost, "/roles/{name}/token", AuthorizationRequiredHandler(assignRoleToToken))
 	m.Add("1.6", http.MethodDelete, "/roles/{name}/token/{token_id}", AuthorizationRequiredHandler(dissociateRoleFromToken))
+	m.Add("1.13", http.MethodPost, "/roles/{name}/service-account", AuthorizationRequiredHandler(assignRoleToServiceAccount))
+	m.Add("1.13", http.MethodDelete, "/roles/{name}/service-account/{service_account}", AuthorizationRequiredHandler(dissociateRoleFromServiceAccount))
 	m.Add("1.9", http.MethodPost, "/roles/{name}/group", AuthorizationRequiredHandler(assignRoleToGroup))
 	m.Add("1.9", http.MethodDelete, "/roles/{name}/group/{group_name}", AuthorizationRequiredHandler(dissociateRoleFromGroup))
 
@@ -465,6 +545,7 @@ func RunServer(dry bool) http.Handler {
 	m.Add("1.4", http.MethodPost, "/provisioner/clusters/{name}", AuthorizationRequiredHandler(updateCluster))
 	m.Add("1.3", http.MethodGet, "/provisioner/clusters", AuthorizationRequiredHandler(listClusters))
 	m.Add("1.8", http.MethodGet, "/provisioner/clusters/{name}", AuthorizationRequiredHandler(clusterInfo))
+	m.Add("1.13", http.MethodGet, "/provisioner/clusters/{name}/status", AuthorizationRequiredHandler(clusterStatus))
 	m.Add("1.3", http.MethodDelete, "/provisioner/clusters/{name}", AuthorizationRequiredHandler(deleteCluster))
 
 	m.Add("1.4", http.MethodGet, "/volumes", AuthorizationRequiredHandler(volumesList))
@@ -476,11 +557,25 @@ func RunServer(dry bool) http.Handler {
 	m.Add("1.4", http.MethodDelete, "/volumes/{name}/bind", AuthorizationRequiredHandler(volumeUnbind))
 	m.Add("1.4", http.MethodGet, "/volumeplans", AuthorizationRequiredHandler(volumePlansList))
 
+	m.Add("1.13", http.MethodGet, "/jobs", AuthorizationRequiredHandler(jobList))
+	m.Add("1.13", http.MethodPost, "/jobs", AuthorizationRequiredHandler(jobCreate))
+	m.Add("1.13", http.MethodGet, "/jobs/{name}", AuthorizationRequiredHandler(jobInfo))
+	m.Add("1.13", http.MethodDelete, "/jobs/{name}", AuthorizationRequiredHandler(jobDelete))
+	m.Add("1.13", http.MethodPost, "/jobs/{name}/run", AuthorizationRequiredHandler(jobRun))
+	m.Add("1.13", http.MethodPost, "/jobs/{name}/trigger", AuthorizationRequiredHandler(jobTrigger))
+
 	m.Add("1.6", http.MethodGet, "/tokens", AuthorizationRequiredHandler(tokenList))
 	m.Add("1.7", http.MethodGet, "/tokens/{token_id}", AuthorizationRequiredHandler(tokenInfo))
 	m.Add("1.6", http.MethodPost, "/tokens", AuthorizationRequiredHandler(tokenCreate))
 	m.Add("1.6", http.MethodDelete, "/tokens/{token_id}", AuthorizationRequiredHandler(tokenDelete))
 	m.Add("1.6", http.MethodPut, "/tokens/{token_id}", AuthorizationRequiredHandler(tokenUpdate))
+	m.Add("1.13", http.MethodPut, "/tokens/{token_id}/allowlist", AuthorizationRequiredHandler(setTokenAllowlist))
+	m.Add("1.13", http.MethodPost, "/tokens/{token_id}/rotate", AuthorizationRequiredHandler(tokenRotate))
+
+	m.Add("1.13", http.MethodGet, "/service-accounts", AuthorizationRequiredHandler(serviceAccountList))
+	m.Add("1.13", http.MethodPost, "/service-accounts", AuthorizationRequiredHandler(serviceAccountCreate))
+	m.Add("1.13", http.MethodGet, "/service-accounts/{name}", AuthorizationRequiredHandler(serviceAccountInfo))
+	m.Add("1.13", http.MethodDelete, "/service-accounts/{name}", AuthorizationRequiredHandler(serviceAccountDelete))
 
 	m.Add("1.7", http.MethodGet, "/brokers", AuthorizationRequiredHandler(serviceBrokerList))
 	m.Add("1.7", http.MethodPost, "/brokers", AuthorizationRequiredHandler(serviceBrokerAdd))
@@ -526,7 +621,20 @@ func RunServer(dry bool) http.Handler {
 	if !dry {
 		n.Use(observability.NewMiddleware())
 	}
+	cors, err := newCORSMiddleware()
+	if err != nil {
+		fatal(err)
+	}
+	if cors != nil {
+		n.Use(cors)
+	}
 	n.UseHandler(m)
+	if audit.Enabled() {
+		n.Use(newAuditMiddleware())
+	}
+	if disableCompression, _ := config.GetBool("api:disable-compression"); !disableCompression {
+		n.Use(&compressionMiddleware{})
+	}
 	n.Use(&flushingWriterMiddleware{
 		latencyConfig: map[string]time.Duration{
 			"log-get":          500 * time.Millisecond,
@@ -536,7 +644,28 @@ func RunServer(dry bool) http.Handler {
 	n.Use(negroni.HandlerFunc(setRequestIDHeaderMiddleware))
 	n.Use(negroni.HandlerFunc(errorHandlingMiddleware))
 	n.Use(negroni.HandlerFunc(setVersionHeadersMiddleware))
+	n.Use(negroni.HandlerFunc(setDeprecationHeadersMiddleware))
 	n.Use(negroni.HandlerFunc(authTokenMiddleware))
+	rateLimiter, err := newRateLimitMiddleware()
+	if err != nil {
+		fatal(err)
+	}
+	if rateLimiter != nil {
+		n.Use(rateLimiter)
+	}
+	ipAllowlist, err := newIPAllowlistMiddleware()
+	if err != nil {
+		fatal(err)
+	}
+	n.Use(ipAllowlist)
+	responseCache, err := newResponseCacheMiddleware()
+	if err != nil {
+		fatal(err)
+	}
+	if responseCache != nil {
+		event.RegisterSink(responseCache)
+		n.Use(responseCache)
+	}
 	n.UseHandler(http.HandlerFunc(runDelayedHandler))
 
 	form.DefaultEncoder = form.DefaultEncoder.UseJSONTags(false)
@@ -612,6 +741,11 @@ func startServer(handler http.Handler) error {
 	if shutdownTimeoutInt != 0 {
 		srvConf.shutdownTimeout = time.Duration(shutdownTimeoutInt) * time.Second
 	}
+	srvConf.drainTimeout = srvConf.shutdownTimeout
+	drainTimeoutInt, _ := config.GetInt("shutdown-drain-timeout")
+	if drainTimeoutInt > 0 && time.Duration(drainTimeoutInt)*time.Second < srvConf.shutdownTimeout {
+		srvConf.drainTimeout = time.Duration(drainTimeoutInt) * time.Second
+	}
 	go srvConf.handleSignals(srvConf.shutdownTimeout)
 
 	defer srvConf.shutdown(srvConf.shutdownTimeout)
@@ -676,10 +810,38 @@ func startServer(handler http.Handler) error {
 	if err != nil {
 		return errors.Wrap(err, "unable to load events throttling config")
 	}
+	err = eventKafka.Initialize()
+	if err != nil {
+		return errors.Wrap(err, "unable to initialize events kafka exporter")
+	}
 	err = gc.Initialize()
 	if err != nil {
 		return errors.Wrap(err, "unable to initialize old image gc")
 	}
+	err = hibernate.Initialize()
+	if err != nil {
+		return errors.Wrap(err, "unable to initialize app hibernator")
+	}
+	err = teamtoken.Initialize()
+	if err != nil {
+		return errors.Wrap(err, "unable to initialize team token expiration warnings")
+	}
+	err = ldapsync.Initialize()
+	if err != nil {
+		return errors.Wrap(err, "unable to initialize ldap sync")
+	}
+	err = rolegrant.Initialize()
+	if err != nil {
+		return errors.Wrap(err, "unable to initialize temporary role revocation")
+	}
+	err = audit.Initialize()
+	if err != nil {
+		return errors.Wrap(err, "unable to initialize api audit log")
+	}
+	err = cluster.InitializeStatus()
+	if err != nil {
+		return errors.Wrap(err, "unable to initialize cluster status")
+	}
 	err = service.InitializeSync(bindAppsLister)
 	if err != nil {
 		return err
@@ -939,6 +1101,10 @@ type srvConfig struct {
 	httpsSrv        *http.Server
 	certificate     *tls.Certificate
 	shutdownTimeout time.Duration
+	// drainTimeout is how long the shutdown waits for in-flight requests,
+	// shell sessions and async operations, events still running afterwards
+	// are marked as interrupted. Defaults to shutdownTimeout.
+	drainTimeout time.Duration
 	// roots holds a set of trusted certificates that are used by certificate
 	// validator to check a given certificate. If roots is nil, the system
 	// certificates are used instead.
@@ -996,18 +1162,43 @@ func (conf *srvConfig) shutdown(shutdownTimeout time.Duration) {
 }
 
 func (conf *srvConfig) onceShutdown(shutdownTimeout time.Duration) {
+	drainTimeout := conf.drainTimeout
+	if drainTimeout == 0 || drainTimeout > shutdownTimeout {
+		drainTimeout = shutdownTimeout
+	}
 	var wg sync.WaitGroup
-	defer wg.Wait()
+	defer func() {
+		wg.Wait()
+		err := event.MarkInterrupted(errInterruptedByShutdown)
+		if err != nil {
+			fmt.Printf("[shutdown] error marking interrupted events: %v\n", err)
+		}
+	}()
 	shutdownSrv := func(srv *http.Server) {
 		defer wg.Done()
 		fmt.Printf("[shutdown] tsuru is shutting down server %v, waiting for pending connections to finish.\n", srv.Addr)
-		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		ctx, cancel := context.WithTimeout(context.Background(), drainTimeout)
 		defer cancel()
 		err := srv.Shutdown(ctx)
 		if err != nil {
 			fmt.Printf("[shutdown] error while shutting down server %v: %v\n", srv.Addr, err)
 		}
 	}
+	waitOperations := func(name string, tracker *operationTracker) {
+		defer wg.Done()
+		if n := tracker.running(); n > 0 {
+			fmt.Printf("[shutdown] tsuru is waiting for %d %s to finish.\n", n, name)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), drainTimeout)
+		defer cancel()
+		err := tracker.wait(ctx)
+		if err != nil {
+			fmt.Printf("[shutdown] error while waiting for %s: %v\n", name, err)
+		}
+	}
+	wg.Add(2)
+	go waitOperations("shell sessions", shellSessions)
+	go waitOperations("async operations", asyncOperations)
 	if conf.httpSrv != nil {
 		wg.Add(1)
 		go shutdownSrv(conf.httpSrv)
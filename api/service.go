@@ -6,7 +6,6 @@ package api
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -37,6 +36,8 @@ func provisionReadableServices(ctx context.Context, t auth.Token, contexts []per
 // responses:
 //   200: List services
 //   204: No content
+//   304: Not modified
+//   400: Invalid data
 //   401: Unauthorized
 func serviceList(w http.ResponseWriter, r *http.Request, t auth.Token) error {
 	ctx := r.Context()
@@ -45,6 +46,11 @@ func serviceList(w http.ResponseWriter, r *http.Request, t auth.Token) error {
 	if err != nil {
 		return err
 	}
+	start, end, err := paginate(w, r, len(services))
+	if err != nil {
+		return err
+	}
+	services = services[start:end]
 	sInstances, err := service.GetServiceInstancesByServices(services)
 	if err != nil {
 		return err
@@ -63,8 +69,7 @@ func serviceList(w http.ResponseWriter, r *http.Request, t auth.Token) error {
 		w.WriteHeader(http.StatusNoContent)
 		return nil
 	}
-	w.Header().Set("Content-Type", "application/json")
-	return json.NewEncoder(w).Encode(results)
+	return writeJSONWithETag(w, r, results)
 }
 
 type serviceInput struct {
@@ -0,0 +1,205 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/servicemanager"
+	authTypes "github.com/tsuru/tsuru/types/auth"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+)
+
+func serviceAccountNotFound(err error) error {
+	if err == authTypes.ErrServiceAccountNotFound {
+		return &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+	}
+	return err
+}
+
+// canUseServiceAccount checks that the token is allowed to use every role of
+// the service account, tokens bound to it must not grant more than the caller
+// is able to assign.
+func canUseServiceAccount(t auth.Token, sa authTypes.ServiceAccount) error {
+	for _, ri := range sa.Roles {
+		role, err := permission.FindRole(ri.Name)
+		if err != nil {
+			return err
+		}
+		err = canUseRole(t, role, ri.ContextValue)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// canUseTokenServiceAccount checks canUseServiceAccount for the service
+// account the team token is bound to, if any.
+func canUseTokenServiceAccount(ctx context.Context, t auth.Token, teamToken authTypes.TeamToken) error {
+	if teamToken.ServiceAccount == "" {
+		return nil
+	}
+	sa, err := servicemanager.ServiceAccount.FindByName(ctx, teamToken.ServiceAccount)
+	if err != nil {
+		return serviceAccountNotFound(err)
+	}
+	return canUseServiceAccount(t, sa)
+}
+
+// title: service account list
+// path: /service-accounts
+// method: GET
+// produce: application/json
+// responses:
+//   200: List service accounts
+//   204: No content
+//   401: Unauthorized
+func serviceAccountList(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	ctx := r.Context()
+	teams, err := permission.ListContextValues(t, permission.PermTeamServiceAccountRead, false)
+	if err != nil {
+		return err
+	}
+	accounts, err := servicemanager.ServiceAccount.List(ctx, teams)
+	if err != nil {
+		return err
+	}
+	if len(accounts) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(accounts)
+}
+
+// title: service account info
+// path: /service-accounts/{name}
+// method: GET
+// produce: application/json
+// responses:
+//   200: Get service account
+//   401: Unauthorized
+//   403: Forbidden
+//   404: Service account not found
+func serviceAccountInfo(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	ctx := r.Context()
+	sa, err := servicemanager.ServiceAccount.FindByName(ctx, r.URL.Query().Get(":name"))
+	if err != nil {
+		return serviceAccountNotFound(err)
+	}
+	allowed := permission.Check(t, permission.PermTeamServiceAccountRead,
+		permission.Context(permTypes.CtxTeam, sa.Team),
+	)
+	if !allowed {
+		return permission.ErrUnauthorized
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(sa)
+}
+
+// title: service account create
+// path: /service-accounts
+// method: POST
+// consume: application/x-www-form-urlencoded
+// produce: application/json
+// responses:
+//   201: Service account created
+//   400: Invalid data
+//   401: Unauthorized
+//   403: Forbidden
+//   409: Service account already exists
+func serviceAccountCreate(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	if auth.IsImpersonated(t) {
+		return errImpersonated
+	}
+	ctx := r.Context()
+	var args authTypes.ServiceAccountCreateArgs
+	err = ParseInput(r, &args)
+	if err != nil {
+		return err
+	}
+	if args.Team == "" {
+		args.Team, err = autoTeamOwner(ctx, t, permission.PermTeamServiceAccountCreate)
+		if err != nil {
+			return err
+		}
+	}
+	allowed := permission.Check(t, permission.PermTeamServiceAccountCreate,
+		permission.Context(permTypes.CtxTeam, args.Team),
+	)
+	if !allowed {
+		return permission.ErrUnauthorized
+	}
+	evt, err := event.New(&event.Opts{
+		Target:     teamTarget(args.Team),
+		Kind:       permission.PermTeamServiceAccountCreate,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		CustomData: event.FormToCustomData(InputFields(r)),
+		Allowed:    event.Allowed(permission.PermTeamReadEvents, permission.Context(permTypes.CtxTeam, args.Team)),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	sa, err := servicemanager.ServiceAccount.Create(ctx, args, t)
+	if err == authTypes.ErrServiceAccountAlreadyExists {
+		return &errors.HTTP{Code: http.StatusConflict, Message: err.Error()}
+	}
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	return json.NewEncoder(w).Encode(sa)
+}
+
+// title: service account delete
+// path: /service-accounts/{name}
+// method: DELETE
+// responses:
+//   200: Service account removed
+//   401: Unauthorized
+//   403: Forbidden
+//   404: Service account not found
+//   412: Service account has tokens or owns apps
+func serviceAccountDelete(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	ctx := r.Context()
+	sa, err := servicemanager.ServiceAccount.FindByName(ctx, r.URL.Query().Get(":name"))
+	if err != nil {
+		return serviceAccountNotFound(err)
+	}
+	allowed := permission.Check(t, permission.PermTeamServiceAccountDelete,
+		permission.Context(permTypes.CtxTeam, sa.Team),
+	)
+	if !allowed {
+		return permission.ErrUnauthorized
+	}
+	evt, err := event.New(&event.Opts{
+		Target:     teamTarget(sa.Team),
+		Kind:       permission.PermTeamServiceAccountDelete,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		CustomData: event.FormToCustomData(InputFields(r)),
+		Allowed:    event.Allowed(permission.PermTeamReadEvents, permission.Context(permTypes.CtxTeam, sa.Team)),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	err = servicemanager.ServiceAccount.Delete(ctx, sa.Name)
+	switch err {
+	case authTypes.ErrServiceAccountHasTokens, authTypes.ErrServiceAccountOwnsApps:
+		return &errors.HTTP{Code: http.StatusPreconditionFailed, Message: err.Error()}
+	}
+	return serviceAccountNotFound(err)
+}
@@ -0,0 +1,255 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/event/eventtest"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/permission/permissiontest"
+	"github.com/tsuru/tsuru/servicemanager"
+	authTypes "github.com/tsuru/tsuru/types/auth"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestServiceAccountCreate(c *check.C) {
+	body := strings.NewReader(`name=deployer&description=ci&team=` + s.team.Name)
+	request, err := http.NewRequest("POST", "/1.13/service-accounts", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusCreated, check.Commentf("body: %q", recorder.Body.String()))
+	var result authTypes.ServiceAccount
+	err = json.Unmarshal(recorder.Body.Bytes(), &result)
+	c.Assert(err, check.IsNil)
+	c.Assert(result.Name, check.Equals, "deployer")
+	c.Assert(result.Team, check.Equals, s.team.Name)
+	c.Assert(result.CreatorEmail, check.Equals, s.user.Email)
+	c.Assert(eventtest.EventDesc{
+		Target: event.Target{Type: event.TargetTypeTeam, Value: s.team.Name},
+		Owner:  s.user.Email,
+		Kind:   "team.service-account.create",
+		StartCustomData: []map[string]interface{}{
+			{"name": "name", "value": "deployer"},
+			{"name": "description", "value": "ci"},
+			{"name": "team", "value": s.team.Name},
+		},
+	}, eventtest.HasEvent)
+
+	recorder = httptest.NewRecorder()
+	request, err = http.NewRequest("POST", "/1.13/service-accounts", strings.NewReader(`name=deployer&team=`+s.team.Name))
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusConflict)
+}
+
+func (s *S) TestServiceAccountCreateNoPermission(c *check.C) {
+	body := strings.NewReader(`name=deployer&team=` + s.team.Name)
+	request, err := http.NewRequest("POST", "/1.13/service-accounts", body)
+	c.Assert(err, check.IsNil)
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermTeamServiceAccountRead,
+		Context: permission.Context(permTypes.CtxTeam, s.team.Name),
+	})
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
+
+func (s *S) TestServiceAccountListAndInfo(c *check.C) {
+	_, err := servicemanager.ServiceAccount.Create(context.TODO(), authTypes.ServiceAccountCreateArgs{Name: "deployer", Team: s.team.Name}, s.token)
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest("GET", "/1.13/service-accounts", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	var accounts []authTypes.ServiceAccount
+	err = json.Unmarshal(recorder.Body.Bytes(), &accounts)
+	c.Assert(err, check.IsNil)
+	c.Assert(accounts, check.HasLen, 1)
+	c.Assert(accounts[0].Name, check.Equals, "deployer")
+
+	token := userWithPermission(c, permission.Permission{
+		Scheme:  permission.PermTeamServiceAccountRead,
+		Context: permission.Context(permTypes.CtxTeam, "otherteam"),
+	})
+	request, err = http.NewRequest("GET", "/1.13/service-accounts", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder = httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNoContent)
+
+	request, err = http.NewRequest("GET", "/1.13/service-accounts/deployer", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder = httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+
+	request, err = http.NewRequest("GET", "/1.13/service-accounts/deployer", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder = httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	var sa authTypes.ServiceAccount
+	err = json.Unmarshal(recorder.Body.Bytes(), &sa)
+	c.Assert(err, check.IsNil)
+	c.Assert(sa.Name, check.Equals, "deployer")
+}
+
+func (s *S) TestServiceAccountInfoNotFound(c *check.C) {
+	request, err := http.NewRequest("GET", "/1.13/service-accounts/deployer", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNotFound)
+}
+
+func (s *S) TestServiceAccountDelete(c *check.C) {
+	_, err := servicemanager.ServiceAccount.Create(context.TODO(), authTypes.ServiceAccountCreateArgs{Name: "deployer", Team: s.team.Name}, s.token)
+	c.Assert(err, check.IsNil)
+	teamToken, err := servicemanager.TeamToken.Create(context.TODO(), authTypes.TeamTokenCreateArgs{Team: s.team.Name, ServiceAccount: "deployer"}, s.token)
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest("DELETE", "/1.13/service-accounts/deployer", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusPreconditionFailed)
+	c.Assert(recorder.Body.String(), check.Equals, authTypes.ErrServiceAccountHasTokens.Error()+"\n")
+
+	err = servicemanager.TeamToken.Delete(context.TODO(), teamToken.TokenID)
+	c.Assert(err, check.IsNil)
+	request, err = http.NewRequest("DELETE", "/1.13/service-accounts/deployer", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder = httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	_, err = servicemanager.ServiceAccount.FindByName(context.TODO(), "deployer")
+	c.Assert(err, check.Equals, authTypes.ErrServiceAccountNotFound)
+	c.Assert(eventtest.EventDesc{
+		Target: event.Target{Type: event.TargetTypeTeam, Value: s.team.Name},
+		Owner:  s.user.Email,
+		Kind:   "team.service-account.delete",
+		StartCustomData: []map[string]interface{}{
+			{"name": ":name", "value": "deployer"},
+		},
+	}, eventtest.HasEvent)
+}
+
+func (s *S) TestTeamTokenCreateForServiceAccount(c *check.C) {
+	_, err := servicemanager.ServiceAccount.Create(context.TODO(), authTypes.ServiceAccountCreateArgs{Name: "deployer", Team: s.team.Name}, s.token)
+	c.Assert(err, check.IsNil)
+	body := strings.NewReader(`token_id=t1&service_account=deployer`)
+	request, err := http.NewRequest("POST", "/1.6/tokens", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusCreated, check.Commentf("body: %q", recorder.Body.String()))
+	var result authTypes.TeamToken
+	err = json.Unmarshal(recorder.Body.Bytes(), &result)
+	c.Assert(err, check.IsNil)
+	c.Assert(result.Team, check.Equals, s.team.Name)
+	c.Assert(result.ServiceAccount, check.Equals, "deployer")
+}
+
+func (s *S) TestAssignRoleToServiceAccount(c *check.C) {
+	_, err := permission.NewRole("newrole", "team", "")
+	c.Assert(err, check.IsNil)
+	_, err = servicemanager.ServiceAccount.Create(context.TODO(), authTypes.ServiceAccountCreateArgs{Name: "deployer", Team: s.team.Name}, s.token)
+	c.Assert(err, check.IsNil)
+	body := strings.NewReader(`context=` + s.team.Name + `&service_account=deployer`)
+	request, err := http.NewRequest(http.MethodPost, "/1.13/roles/newrole/service-account", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK, check.Commentf("body: %q", recorder.Body.String()))
+	sa, err := servicemanager.ServiceAccount.FindByName(context.TODO(), "deployer")
+	c.Assert(err, check.IsNil)
+	c.Assert(sa.Roles, check.DeepEquals, []authTypes.RoleInstance{{Name: "newrole", ContextValue: s.team.Name}})
+
+	request, err = http.NewRequest(http.MethodDelete, "/1.13/roles/newrole/service-account/deployer?context="+s.team.Name, nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder = httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK, check.Commentf("body: %q", recorder.Body.String()))
+	sa, err = servicemanager.ServiceAccount.FindByName(context.TODO(), "deployer")
+	c.Assert(err, check.IsNil)
+	c.Assert(sa.Roles, check.HasLen, 0)
+}
+
+func (s *S) TestAssignRoleToServiceAccountNotFound(c *check.C) {
+	_, err := permission.NewRole("newrole", "team", "")
+	c.Assert(err, check.IsNil)
+	body := strings.NewReader(`context=` + s.team.Name + `&service_account=deployer`)
+	request, err := http.NewRequest(http.MethodPost, "/1.13/roles/newrole/service-account", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNotFound)
+}
+
+func (s *S) TestTeamTokenForServiceAccountRequiresItsRoles(c *check.C) {
+	role, err := permission.NewRole("deployer-role", "team", "")
+	c.Assert(err, check.IsNil)
+	err = role.AddPermissions("app.deploy")
+	c.Assert(err, check.IsNil)
+	_, err = servicemanager.ServiceAccount.Create(context.TODO(), authTypes.ServiceAccountCreateArgs{Name: "deployer", Team: s.team.Name}, s.token)
+	c.Assert(err, check.IsNil)
+	err = servicemanager.ServiceAccount.AddRole(context.TODO(), "deployer", role.Name, s.team.Name)
+	c.Assert(err, check.IsNil)
+	teamToken, err := servicemanager.TeamToken.Create(context.TODO(), authTypes.TeamTokenCreateArgs{Team: s.team.Name, ServiceAccount: "deployer"}, s.token)
+	c.Assert(err, check.IsNil)
+	_, token := permissiontest.CustomUserWithPermission(c, nativeScheme, "tokencreator", permission.Permission{
+		Scheme:  permission.PermTeamToken,
+		Context: permission.Context(permTypes.CtxTeam, s.team.Name),
+	})
+	requests := []struct {
+		method string
+		url    string
+		body   string
+	}{
+		{method: http.MethodPost, url: "/1.6/tokens", body: "token_id=t1&service_account=deployer"},
+		{method: http.MethodPut, url: "/1.6/tokens/" + teamToken.TokenID, body: "regenerate=true"},
+		{method: http.MethodPost, url: "/1.6/tokens/" + teamToken.TokenID + "/rotate", body: ""},
+	}
+	for _, req := range requests {
+		request, err := http.NewRequest(req.method, req.url, strings.NewReader(req.body))
+		c.Assert(err, check.IsNil)
+		request.Header.Set("Authorization", "bearer "+token.GetValue())
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		recorder := httptest.NewRecorder()
+		s.testServer.ServeHTTP(recorder, request)
+		c.Check(recorder.Code, check.Equals, http.StatusForbidden, check.Commentf("%s %s: %q", req.method, req.url, recorder.Body.String()))
+	}
+	_, err = servicemanager.TeamToken.FindByTokenID(context.TODO(), "t1")
+	c.Assert(err, check.Equals, authTypes.ErrTeamTokenNotFound)
+}
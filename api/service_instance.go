@@ -42,11 +42,16 @@ func serviceIntancePermName(serviceName, instanceName string) string {
 // consume: application/x-www-form-urlencoded
 // responses:
 //   201: Service created
+//   202: Service instance creation started
 //   400: Invalid data
 //   401: Unauthorized
 //   409: Service already exists
 func createServiceInstance(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
 	ctx := r.Context()
+	async, err := isAsync(r)
+	if err != nil {
+		return err
+	}
 	serviceName := r.URL.Query().Get(":service")
 	srv, err := getService(ctx, serviceName)
 	if err != nil {
@@ -86,6 +91,14 @@ func createServiceInstance(w http.ResponseWriter, r *http.Request, t auth.Token)
 			return permission.ErrUnauthorized
 		}
 	}
+	if async {
+		// the instance is validated again on its creation, validating it
+		// here reports invalid instances and conflicts before responding.
+		err = service.ValidateServiceInstance(ctx, instance, &srv)
+		if err != nil {
+			return createServiceInstanceError(err, &srv, &instance)
+		}
+	}
 
 	evt, err := event.New(&event.Opts{
 		Target:     serviceInstanceTarget(serviceName, instance.Name),
@@ -99,9 +112,24 @@ func createServiceInstance(w http.ResponseWriter, r *http.Request, t auth.Token)
 	if err != nil {
 		return err
 	}
-	defer func() { evt.Done(err) }()
 	requestID := requestIDHeader(r)
+	if async {
+		return runAsync(w, evt, func(ctx stdContext.Context) (interface{}, error) {
+			return nil, service.CreateServiceInstance(ctx, instance, &srv, evt, requestID)
+		})
+	}
+	defer func() { evt.Done(err) }()
 	err = service.CreateServiceInstance(ctx, instance, &srv, evt, requestID)
+	if err != nil {
+		return createServiceInstanceError(err, &srv, &instance)
+	}
+	w.WriteHeader(http.StatusCreated)
+	return nil
+}
+
+// createServiceInstanceError converts errors of service instance creation to
+// their HTTP errors.
+func createServiceInstanceError(err error, srv *service.Service, instance *service.ServiceInstance) error {
 	if err == service.ErrMultiClusterViolatingConstraint {
 		return &tsuruErrors.HTTP{
 			Code:    http.StatusBadRequest,
@@ -120,9 +148,6 @@ func createServiceInstance(w http.ResponseWriter, r *http.Request, t auth.Token)
 			Message: err.Error(),
 		}
 	}
-	if err == nil {
-		w.WriteHeader(http.StatusCreated)
-	}
 	return err
 }
 
@@ -350,6 +375,7 @@ func readableServices(ctx stdContext.Context, t auth.Token, contexts []permTypes
 // responses:
 //   200: List services instances
 //   204: No content
+//   400: Invalid data
 //   401: Unauthorized
 func serviceInstances(w http.ResponseWriter, r *http.Request, t auth.Token) error {
 	ctx := r.Context()
@@ -387,6 +413,11 @@ func serviceInstances(w http.ResponseWriter, r *http.Request, t auth.Token) erro
 		entry := servicesMap[name]
 		result = append(result, *entry)
 	}
+	start, end, err := paginate(w, r, len(result))
+	if err != nil {
+		return err
+	}
+	result = result[start:end]
 	if len(result) == 0 {
 		w.WriteHeader(http.StatusNoContent)
 		return nil
@@ -25,6 +25,7 @@ import (
 	"github.com/tsuru/tsuru/db"
 	"github.com/tsuru/tsuru/db/dbtest"
 	"github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event"
 	"github.com/tsuru/tsuru/event/eventtest"
 	"github.com/tsuru/tsuru/io"
 	"github.com/tsuru/tsuru/permission"
@@ -339,6 +340,67 @@ func (s *ServiceInstanceSuite) TestCreateInstance(c *check.C) {
 	})
 }
 
+func (s *ServiceInstanceSuite) TestCreateInstanceAsync(c *check.C) {
+	params := map[string]interface{}{
+		"name":         "brainsql",
+		"service_name": "mysql",
+		"owner":        s.team.Name,
+		"token":        "bearer " + s.token.GetValue(),
+	}
+	recorder, request := makeRequestToCreateServiceInstance(params, c)
+	request.URL.RawQuery = "async=true"
+	s.testServer.ServeHTTP(recorder, request)
+	eventID := asyncEventID(c, recorder)
+	waitAsyncOperations(c)
+	evt, err := event.GetByHexID(eventID)
+	c.Assert(err, check.IsNil)
+	c.Assert(evt.Running, check.Equals, false)
+	c.Assert(evt.Error, check.Equals, "")
+	instance, err := service.GetServiceInstance(stdContext.TODO(), "mysql", "brainsql")
+	c.Assert(err, check.IsNil)
+	c.Assert(instance.TeamOwner, check.Equals, "tsuruteam")
+}
+
+func (s *ServiceInstanceSuite) TestCreateInstanceAsyncNameAlreadyExists(c *check.C) {
+	params := map[string]interface{}{
+		"name":         "brainsql",
+		"service_name": "mysql",
+		"owner":        s.team.Name,
+		"token":        "bearer " + s.token.GetValue(),
+	}
+	recorder, request := makeRequestToCreateServiceInstance(params, c)
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusCreated)
+	recorder, request = makeRequestToCreateServiceInstance(params, c)
+	request.URL.RawQuery = "async=true"
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusConflict)
+	c.Assert(recorder.Body.String(), check.Equals, service.ErrInstanceNameAlreadyExists.Error()+"\n")
+	evts, err := event.List(&event.Filter{
+		Target:    serviceInstanceTarget("mysql", "brainsql"),
+		KindNames: []string{permission.PermServiceInstanceCreate.FullName()},
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(evts, check.HasLen, 1)
+}
+
+func (s *ServiceInstanceSuite) TestCreateInstanceAsyncInvalidName(c *check.C) {
+	params := map[string]interface{}{
+		"name":         "1brainsql",
+		"service_name": "mysql",
+		"owner":        s.team.Name,
+		"token":        "bearer " + s.token.GetValue(),
+	}
+	recorder, request := makeRequestToCreateServiceInstance(params, c)
+	request.URL.RawQuery = "async=true"
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(recorder.Body.String(), check.Equals, service.ErrInvalidInstanceName.Error()+"\n")
+	evts, err := event.List(&event.Filter{Target: serviceInstanceTarget("mysql", "1brainsql")})
+	c.Assert(err, check.IsNil)
+	c.Assert(evts, check.HasLen, 0)
+}
+
 func (s *ServiceInstanceSuite) TestCreateInstanceWithInvalidPoolConstraint(c *check.C) {
 	multiCluterservice := &service.Service{
 		Name:           "mysql-multicluster",
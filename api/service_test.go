@@ -126,6 +126,35 @@ func (s *ProvisionSuite) TestServiceListGetAllServicesFromUsersTeam(c *check.C)
 	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, "application/json")
 }
 
+func (s *ProvisionSuite) TestServiceListNotModified(c *check.C) {
+	srv := service.Service{
+		Name:       "mongodb",
+		OwnerTeams: []string{s.team.Name},
+		Endpoint:   map[string]string{"production": "http://localhost:1234"},
+		Password:   "abcde",
+	}
+	err := service.Create(srv)
+	c.Assert(err, check.IsNil)
+	recorder, request := s.makeRequestToServicesHandler(c)
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	etag := recorder.Header().Get("ETag")
+	c.Assert(etag, check.Matches, `W/".+"`)
+	recorder, request = s.makeRequestToServicesHandler(c)
+	request.Header.Set("If-None-Match", etag)
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNotModified)
+	c.Assert(recorder.Body.Len(), check.Equals, 0)
+	si := service.ServiceInstance{Name: "my_nosql", ServiceName: srv.Name, Teams: []string{s.team.Name}}
+	err = s.conn.ServiceInstances().Insert(si)
+	c.Assert(err, check.IsNil)
+	recorder, request = s.makeRequestToServicesHandler(c)
+	request.Header.Set("If-None-Match", etag)
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(recorder.Header().Get("ETag"), check.Not(check.Equals), etag)
+}
+
 func (s *ProvisionSuite) TestServiceListEmptyList(c *check.C) {
 	recorder, request := s.makeRequestToServicesHandler(c)
 	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
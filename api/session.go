@@ -0,0 +1,110 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/tsuru/tsuru/app"
+	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/log"
+	"github.com/tsuru/tsuru/permission"
+	authTypes "github.com/tsuru/tsuru/types/auth"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+)
+
+const sessionsUnsupportedMsg = "Authentication scheme does not support listing sessions."
+
+// touchSession records the use of the session of the token, for schemes
+// keeping track of sessions.
+func touchSession(r *http.Request, t auth.Token) {
+	scheme, ok := app.AuthScheme.(auth.SessionScheme)
+	if !ok {
+		return
+	}
+	err := scheme.TouchSession(r.Context(), t, auditSourceIP(r))
+	if err != nil {
+		log.Errorf("unable to record the use of session of %q: %v", t.GetUserName(), err)
+	}
+}
+
+func sessionUser(r *http.Request, t auth.Token, perm *permission.PermissionScheme) (auth.SessionScheme, *auth.User, error) {
+	scheme, ok := app.AuthScheme.(auth.SessionScheme)
+	if !ok {
+		return nil, nil, &errors.HTTP{Code: http.StatusBadRequest, Message: sessionsUnsupportedMsg}
+	}
+	email := r.URL.Query().Get(":email")
+	if !permission.Check(t, perm, permission.Context(permTypes.CtxUser, email)) {
+		return nil, nil, permission.ErrUnauthorized
+	}
+	u, err := auth.GetUserByEmail(email)
+	if err != nil {
+		if err == authTypes.ErrUserNotFound {
+			return nil, nil, &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+		}
+		return nil, nil, err
+	}
+	return scheme, u, nil
+}
+
+// title: session list
+// path: /users/{email}/sessions
+// method: GET
+// produce: application/json
+// responses:
+//   200: OK
+//   400: Invalid data
+//   401: Unauthorized
+//   403: Forbidden
+//   404: User not found
+func listSessions(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	scheme, u, err := sessionUser(r, t, permission.PermUserReadSessions)
+	if err != nil {
+		return err
+	}
+	sessions, err := scheme.Sessions(r.Context(), u, t)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(sessions)
+}
+
+// title: session revoke
+// path: /users/{email}/sessions/{id}
+// method: DELETE
+// responses:
+//   200: Session revoked
+//   400: Invalid data
+//   401: Unauthorized
+//   403: Forbidden
+//   404: Session not found
+func revokeSession(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	scheme, u, err := sessionUser(r, t, permission.PermUserUpdateSessions)
+	if err != nil {
+		return err
+	}
+	id := r.URL.Query().Get(":id")
+	evt, err := event.New(&event.Opts{
+		Target:     userTarget(u.Email),
+		Kind:       permission.PermUserUpdateSessions,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		CustomData: []map[string]interface{}{{"name": "id", "value": id}},
+		Allowed:    event.Allowed(permission.PermUserReadEvents, permission.Context(permTypes.CtxUser, u.Email)),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	err = scheme.RevokeSession(r.Context(), u, id)
+	if err == auth.ErrSessionNotFound {
+		return &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+	}
+	return err
+}
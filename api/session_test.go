@@ -0,0 +1,131 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/event/eventtest"
+	check "gopkg.in/check.v1"
+)
+
+func (s *AuthSuite) listSessions(c *check.C, email string, token auth.Token) []auth.Session {
+	request, err := http.NewRequest(http.MethodGet, "/1.13/users/"+email+"/sessions", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, "application/json")
+	var sessions []auth.Session
+	err = json.NewDecoder(recorder.Body).Decode(&sessions)
+	c.Assert(err, check.IsNil)
+	return sessions
+}
+
+func (s *AuthSuite) TestListSessions(c *check.C) {
+	u := &auth.User{Email: "me@globo.com", Password: "123456"}
+	_, err := nativeScheme.Create(context.TODO(), u)
+	c.Assert(err, check.IsNil)
+	token, err := nativeScheme.Login(context.TODO(), map[string]string{"email": u.Email, "password": "123456"})
+	c.Assert(err, check.IsNil)
+	_, err = nativeScheme.Login(context.TODO(), map[string]string{"email": u.Email, "password": "123456"})
+	c.Assert(err, check.IsNil)
+	sessions := s.listSessions(c, u.Email, token)
+	c.Assert(sessions, check.HasLen, 2)
+	c.Assert(sessions[0].Current, check.Equals, true)
+	c.Assert(sessions[0].LastUse.IsZero(), check.Equals, false)
+	c.Assert(sessions[0].LastAddress, check.Not(check.Equals), "")
+	c.Assert(sessions[1].Current, check.Equals, false)
+	c.Assert(sessions[1].LastUse.IsZero(), check.Equals, true)
+	adminSessions := s.listSessions(c, u.Email, s.token)
+	c.Assert(adminSessions, check.HasLen, 2)
+	c.Assert(adminSessions[0].Current, check.Equals, false)
+}
+
+func (s *AuthSuite) TestListSessionsUserNotFound(c *check.C) {
+	request, err := http.NewRequest(http.MethodGet, "/1.13/users/unknown@globo.com/sessions", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNotFound)
+}
+
+func (s *AuthSuite) TestListSessionsOfOtherUserUnauthorized(c *check.C) {
+	u := &auth.User{Email: "me@globo.com", Password: "123456"}
+	_, err := nativeScheme.Create(context.TODO(), u)
+	c.Assert(err, check.IsNil)
+	token, err := nativeScheme.Login(context.TODO(), map[string]string{"email": u.Email, "password": "123456"})
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest(http.MethodGet, "/1.13/users/"+s.user.Email+"/sessions", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
+
+func (s *AuthSuite) TestRevokeSession(c *check.C) {
+	u := &auth.User{Email: "me@globo.com", Password: "123456"}
+	_, err := nativeScheme.Create(context.TODO(), u)
+	c.Assert(err, check.IsNil)
+	token, err := nativeScheme.Login(context.TODO(), map[string]string{"email": u.Email, "password": "123456"})
+	c.Assert(err, check.IsNil)
+	other, err := nativeScheme.Login(context.TODO(), map[string]string{"email": u.Email, "password": "123456"})
+	c.Assert(err, check.IsNil)
+	sessions := s.listSessions(c, u.Email, token)
+	c.Assert(sessions, check.HasLen, 2)
+	url := fmt.Sprintf("/1.13/users/%s/sessions/%s", u.Email, sessions[1].ID)
+	request, err := http.NewRequest(http.MethodDelete, url, nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	_, err = nativeScheme.Auth(context.TODO(), "bearer "+other.GetValue())
+	c.Assert(err, check.Equals, auth.ErrInvalidToken)
+	c.Assert(eventtest.EventDesc{
+		Target: userTarget(u.Email),
+		Owner:  u.Email,
+		Kind:   "user.update.sessions",
+		StartCustomData: []map[string]interface{}{
+			{"name": "id", "value": sessions[1].ID},
+		},
+	}, eventtest.HasEvent)
+}
+
+func (s *AuthSuite) TestRevokeSessionNotFound(c *check.C) {
+	url := fmt.Sprintf("/1.13/users/%s/sessions/%s", s.user.Email, "5f1b2c3d4e5f6a7b8c9d0e1f")
+	request, err := http.NewRequest(http.MethodDelete, url, nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNotFound)
+	c.Assert(recorder.Body.String(), check.Equals, auth.ErrSessionNotFound.Error()+"\n")
+}
+
+func (s *AuthSuite) TestRevokeSessionOfOtherUserUnauthorized(c *check.C) {
+	u := &auth.User{Email: "me@globo.com", Password: "123456"}
+	_, err := nativeScheme.Create(context.TODO(), u)
+	c.Assert(err, check.IsNil)
+	token, err := nativeScheme.Login(context.TODO(), map[string]string{"email": u.Email, "password": "123456"})
+	c.Assert(err, check.IsNil)
+	sessions := s.listSessions(c, s.user.Email, s.token)
+	c.Assert(sessions, check.Not(check.HasLen), 0)
+	url := fmt.Sprintf("/1.13/users/%s/sessions/%s", s.user.Email, sessions[0].ID)
+	request, err := http.NewRequest(http.MethodDelete, url, nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
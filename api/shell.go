@@ -109,6 +109,8 @@ func remoteShellHandler(w http.ResponseWriter, r *http.Request) {
 		fmt.Fprintf(w, "unable to upgrade ws connection: %v", err)
 		return
 	}
+	shellSessions.add()
+	defer shellSessions.done()
 	var httpErr *errors.HTTP
 	defer func() {
 		if httpErr != nil {
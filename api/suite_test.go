@@ -17,12 +17,14 @@ import (
 	"github.com/tsuru/tsuru/app"
 	"github.com/tsuru/tsuru/app/version"
 	"github.com/tsuru/tsuru/applog"
+	"github.com/tsuru/tsuru/audit"
 	"github.com/tsuru/tsuru/auth"
 	"github.com/tsuru/tsuru/auth/native"
 	"github.com/tsuru/tsuru/autoscale"
 	"github.com/tsuru/tsuru/db"
 	"github.com/tsuru/tsuru/db/dbtest"
 	"github.com/tsuru/tsuru/healer"
+	"github.com/tsuru/tsuru/job"
 	"github.com/tsuru/tsuru/permission"
 	"github.com/tsuru/tsuru/permission/permissiontest"
 	"github.com/tsuru/tsuru/provision"
@@ -143,6 +145,10 @@ func (s *S) SetUpTest(c *check.C) {
 	c.Assert(err, check.IsNil)
 	servicemanager.AuthGroup, err = auth.GroupService()
 	c.Assert(err, check.IsNil)
+	servicemanager.Job, err = job.JobService()
+	c.Assert(err, check.IsNil)
+	servicemanager.Audit, err = audit.AuditService()
+	c.Assert(err, check.IsNil)
 }
 
 func (s *S) setupMocks() {
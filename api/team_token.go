@@ -81,15 +81,31 @@ func tokenInfo(w http.ResponseWriter, r *http.Request, t auth.Token) error {
 // produce: application/json
 // responses:
 //   201: Token created
+//   400: Invalid data
 //   401: Unauthorized
+//   403: Forbidden
 //   409: Token already exists
 func tokenCreate(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	if auth.IsImpersonated(t) {
+		return errImpersonated
+	}
 	ctx := r.Context()
 	var args authTypes.TeamTokenCreateArgs
 	err = ParseInput(r, &args)
 	if err != nil {
 		return err
 	}
+	var sa *authTypes.ServiceAccount
+	if args.ServiceAccount != "" {
+		found, saErr := servicemanager.ServiceAccount.FindByName(ctx, args.ServiceAccount)
+		if saErr != nil {
+			return serviceAccountNotFound(saErr)
+		}
+		sa = &found
+		if args.Team == "" {
+			args.Team = sa.Team
+		}
+	}
 	if args.Team == "" {
 		args.Team, err = autoTeamOwner(ctx, t, permission.PermTeamTokenCreate)
 		if err != nil {
@@ -102,6 +118,12 @@ func tokenCreate(w http.ResponseWriter, r *http.Request, t auth.Token) (err erro
 	if !allowed {
 		return permission.ErrUnauthorized
 	}
+	if sa != nil {
+		err = canUseServiceAccount(t, *sa)
+		if err != nil {
+			return err
+		}
+	}
 	evt, err := event.New(&event.Opts{
 		Target:     teamTarget(args.Team),
 		Kind:       permission.PermTeamTokenCreate,
@@ -115,6 +137,10 @@ func tokenCreate(w http.ResponseWriter, r *http.Request, t auth.Token) (err erro
 	}
 	defer func() { evt.Done(err) }()
 	token, err := servicemanager.TeamToken.Create(ctx, args, t)
+	switch err {
+	case authTypes.ErrServiceAccountNotFound, authTypes.ErrServiceAccountTeamMismatch:
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: err.Error()}
+	}
 	if err != nil {
 		return err
 	}
@@ -137,7 +163,9 @@ func tokenCreate(w http.ResponseWriter, r *http.Request, t auth.Token) (err erro
 // produce: application/json
 // responses:
 //   200: Token updated
+//   400: Invalid data
 //   401: Unauthorized
+//   403: Forbidden
 //   404: Token not found
 func tokenUpdate(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
 	ctx := r.Context()
@@ -146,6 +174,9 @@ func tokenUpdate(w http.ResponseWriter, r *http.Request, t auth.Token) (err erro
 	if err != nil {
 		return err
 	}
+	if args.Regenerate && auth.IsImpersonated(t) {
+		return errImpersonated
+	}
 	args.TokenID = r.URL.Query().Get(":token_id")
 	teamToken, err := servicemanager.TeamToken.FindByTokenID(ctx, args.TokenID)
 	if err != nil {
@@ -163,6 +194,10 @@ func tokenUpdate(w http.ResponseWriter, r *http.Request, t auth.Token) (err erro
 	if !allowed {
 		return permission.ErrUnauthorized
 	}
+	err = canUseTokenServiceAccount(ctx, t, teamToken)
+	if err != nil {
+		return err
+	}
 	evt, err := event.New(&event.Opts{
 		Target:     teamTarget(teamToken.Team),
 		Kind:       permission.PermTeamTokenUpdate,
@@ -188,6 +223,72 @@ func tokenUpdate(w http.ResponseWriter, r *http.Request, t auth.Token) (err erro
 	return json.NewEncoder(w).Encode(teamToken)
 }
 
+// title: token rotate
+// path: /tokens/{token_id}/rotate
+// method: POST
+// produce: application/json
+// responses:
+//   200: Token rotated
+//   400: Invalid data
+//   401: Unauthorized
+//   403: Forbidden
+//   404: Token not found
+func tokenRotate(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	if auth.IsImpersonated(t) {
+		return errImpersonated
+	}
+	ctx := r.Context()
+	var args authTypes.TeamTokenRotateArgs
+	err = ParseInput(r, &args)
+	if err != nil {
+		return err
+	}
+	args.TokenID = r.URL.Query().Get(":token_id")
+	teamToken, err := servicemanager.TeamToken.FindByTokenID(ctx, args.TokenID)
+	if err != nil {
+		if err == authTypes.ErrTeamTokenNotFound {
+			return &errors.HTTP{
+				Code:    http.StatusNotFound,
+				Message: err.Error(),
+			}
+		}
+		return err
+	}
+	allowed := permission.Check(t, permission.PermTeamTokenUpdateRotate,
+		permission.Context(permTypes.CtxTeam, teamToken.Team),
+	)
+	if !allowed {
+		return permission.ErrUnauthorized
+	}
+	err = canUseTokenServiceAccount(ctx, t, teamToken)
+	if err != nil {
+		return err
+	}
+	evt, err := event.New(&event.Opts{
+		Target:     teamTarget(teamToken.Team),
+		Kind:       permission.PermTeamTokenUpdateRotate,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		CustomData: event.FormToCustomData(InputFields(r)),
+		Allowed:    event.Allowed(permission.PermTeamReadEvents, permission.Context(permTypes.CtxTeam, teamToken.Team)),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	teamToken, err = servicemanager.TeamToken.Rotate(ctx, args, t)
+	if err == authTypes.ErrTeamTokenNotFound {
+		return &errors.HTTP{
+			Code:    http.StatusNotFound,
+			Message: err.Error(),
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return json.NewEncoder(w).Encode(teamToken)
+}
+
 // title: token delete
 // path: /tokens/{token_id}
 // method: DELETE
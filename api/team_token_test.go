@@ -143,6 +143,103 @@ func (s *S) TestTeamTokenCreateAutomaticTeam(c *check.C) {
 	}, eventtest.HasEvent)
 }
 
+func (s *S) TestTeamTokenCreateWithScopes(c *check.C) {
+	body := strings.NewReader(`token_id=t1&scopes=app.deploy:app:myapp&scopes=app.read&team=` + s.team.Name)
+	request, err := http.NewRequest("POST", "/1.6/tokens", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusCreated, check.Commentf("body: %q", recorder.Body.String()))
+	var result authTypes.TeamToken
+	err = json.Unmarshal(recorder.Body.Bytes(), &result)
+	c.Assert(err, check.IsNil)
+	c.Assert(result.Scopes, check.DeepEquals, []authTypes.TeamTokenScope{
+		{Permission: "app.deploy", ContextType: "app", ContextValue: "myapp"},
+		{Permission: "app.read", ContextType: "global"},
+	})
+}
+
+func (s *S) TestTeamTokenCreateInvalidScope(c *check.C) {
+	body := strings.NewReader(`token_id=t1&scopes=app.deploy:service:mysql&team=` + s.team.Name)
+	request, err := http.NewRequest("POST", "/1.6/tokens", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest, check.Commentf("body: %q", recorder.Body.String()))
+	c.Assert(recorder.Body.String(), check.Equals, `invalid scope "app.deploy:service:mysql": permission "app.deploy" can't be used with context "service"`+"\n")
+}
+
+func (s *S) scopedTeamToken(c *check.C, scopes ...string) authTypes.Token {
+	role, err := permission.NewRole("team-admin", "team", "")
+	c.Assert(err, check.IsNil)
+	err = role.AddPermissions("app", "role")
+	c.Assert(err, check.IsNil)
+	token, err := servicemanager.TeamToken.Create(context.TODO(), authTypes.TeamTokenCreateArgs{
+		Team:    s.team.Name,
+		TokenID: "scoped",
+		Scopes:  scopes,
+	}, s.token)
+	c.Assert(err, check.IsNil)
+	err = servicemanager.TeamToken.AddRole(context.TODO(), token.TokenID, "team-admin", s.team.Name)
+	c.Assert(err, check.IsNil)
+	authToken, err := servicemanager.TeamToken.Authenticate(context.TODO(), "bearer "+token.Token)
+	c.Assert(err, check.IsNil)
+	return authToken
+}
+
+func (s *S) TestScopedTeamTokenCanUseRole(c *check.C) {
+	token := s.scopedTeamToken(c, "app.read")
+	readRole, err := permission.NewRole("app-reader", "team", "")
+	c.Assert(err, check.IsNil)
+	err = readRole.AddPermissions("app.read")
+	c.Assert(err, check.IsNil)
+	deployRole, err := permission.NewRole("app-deployer", "team", "")
+	c.Assert(err, check.IsNil)
+	err = deployRole.AddPermissions("app.deploy")
+	c.Assert(err, check.IsNil)
+	c.Assert(canUseRole(token, readRole, s.team.Name), check.IsNil)
+	err = canUseRole(token, deployRole, s.team.Name)
+	c.Assert(err, check.ErrorMatches, `User not authorized to use permission app.deploy\(team `+s.team.Name+`\)`)
+}
+
+func (s *S) TestScopedTeamTokenEventList(c *check.C) {
+	evt, err := event.New(&event.Opts{
+		Target:  event.Target{Type: event.TargetTypeApp, Value: "myapp"},
+		Kind:    permission.PermAppDeploy,
+		Owner:   s.token,
+		Allowed: event.Allowed(permission.PermAppReadEvents, permission.Context(permTypes.CtxTeam, s.team.Name)),
+	})
+	c.Assert(err, check.IsNil)
+	err = evt.Done(nil)
+	c.Assert(err, check.IsNil)
+	token := s.scopedTeamToken(c, "app.deploy")
+	request, err := http.NewRequest("GET", "/events", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNoContent)
+	err = servicemanager.TeamToken.Delete(context.TODO(), "scoped")
+	c.Assert(err, check.IsNil)
+	err = permission.DestroyRole("team-admin")
+	c.Assert(err, check.IsNil)
+	token = s.scopedTeamToken(c, "app.read")
+	request, err = http.NewRequest("GET", "/events", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder = httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	var result []event.Event
+	err = json.Unmarshal(recorder.Body.Bytes(), &result)
+	c.Assert(err, check.IsNil)
+	c.Assert(result, check.HasLen, 1)
+}
+
 func (s *S) TestTeamTokenCreateNoPermission(c *check.C) {
 	body := strings.NewReader(`token_id=t1&description=desc&expires_in=60&team=` + s.team.Name)
 	request, err := http.NewRequest("POST", "/1.6/tokens", body)
@@ -240,6 +337,49 @@ func (s *S) TestTeamTokenUpdate(c *check.C) {
 	}, eventtest.HasEvent)
 }
 
+func (s *S) TestTeamTokenRotate(c *check.C) {
+	originalToken, err := servicemanager.TeamToken.Create(context.TODO(), authTypes.TeamTokenCreateArgs{
+		Team:    s.team.Name,
+		TokenID: "id1",
+	}, s.token)
+	c.Assert(err, check.IsNil)
+
+	body := strings.NewReader(`grace_period=600`)
+	request, err := http.NewRequest("POST", "/1.13/tokens/id1/rotate", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK, check.Commentf("body: %q", recorder.Body.String()))
+	var result authTypes.TeamToken
+	err = json.Unmarshal(recorder.Body.Bytes(), &result)
+	c.Assert(err, check.IsNil)
+	c.Assert(result.Token, check.Not(check.Equals), originalToken.Token)
+	c.Assert(result.PreviousTokenExpiresAt.Sub(result.RotatedAt), check.Equals, 10*time.Minute)
+
+	_, err = servicemanager.TeamToken.Authenticate(context.TODO(), "bearer "+originalToken.Token)
+	c.Assert(err, check.IsNil)
+	c.Assert(eventtest.EventDesc{
+		Target: event.Target{Type: event.TargetTypeTeam, Value: s.team.Name},
+		Owner:  s.user.Email,
+		Kind:   "team.token.update.rotate",
+		StartCustomData: []map[string]interface{}{
+			{"name": ":token_id", "value": "id1"},
+			{"name": "grace_period", "value": "600"},
+		},
+	}, eventtest.HasEvent)
+}
+
+func (s *S) TestTeamTokenRotateNotFound(c *check.C) {
+	request, err := http.NewRequest("POST", "/1.13/tokens/id1/rotate", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNotFound)
+}
+
 func (s *S) TestTeamTokenInfo(c *check.C) {
 	newToken, err := servicemanager.TeamToken.Create(context.TODO(), authTypes.TeamTokenCreateArgs{
 		Team:        s.team.Name,
@@ -0,0 +1,226 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/tsuru/tsuru/app"
+	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/permission"
+	authTypes "github.com/tsuru/tsuru/types/auth"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+)
+
+const (
+	twoFactorUnsupportedMsg = "Authentication scheme does not support two-factor authentication."
+	twoFactorUserOnlyMsg    = "Two-factor authentication is only available to users."
+)
+
+// twoFactorCode returns the two-factor authentication code sent in the otp
+// field, or in the X-Tsuru-OTP header.
+func twoFactorCode(r *http.Request) string {
+	if code := r.Header.Get("X-Tsuru-OTP"); code != "" {
+		return code
+	}
+	return InputValue(r, "otp")
+}
+
+// checkTwoFactor confirms sensitive operations with the two-factor
+// authentication code of the user of the token, when the user has it enabled.
+func checkTwoFactor(r *http.Request, t auth.Token) error {
+	scheme, ok := app.AuthScheme.(auth.TwoFactorScheme)
+	if !ok {
+		return nil
+	}
+	err := scheme.VerifyTwoFactor(r.Context(), t, twoFactorCode(r))
+	if err != nil {
+		return handleAuthError(err)
+	}
+	return nil
+}
+
+func twoFactorScheme(t auth.Token) (auth.TwoFactorScheme, error) {
+	scheme, ok := app.AuthScheme.(auth.TwoFactorScheme)
+	if !ok {
+		return nil, &errors.HTTP{Code: http.StatusBadRequest, Message: twoFactorUnsupportedMsg}
+	}
+	if t.IsAppToken() {
+		return nil, &errors.HTTP{Code: http.StatusBadRequest, Message: twoFactorUserOnlyMsg}
+	}
+	if auth.IsImpersonated(t) {
+		return nil, errImpersonated
+	}
+	u, err := t.User()
+	if err != nil {
+		return nil, err
+	}
+	if u.FromToken {
+		return nil, &errors.HTTP{Code: http.StatusBadRequest, Message: twoFactorUserOnlyMsg}
+	}
+	return scheme, nil
+}
+
+func newTwoFactorEvent(r *http.Request, t auth.Token, email string, kind *permission.PermissionScheme) (*event.Event, error) {
+	return event.New(&event.Opts{
+		Target:     userTarget(email),
+		Kind:       kind,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		Allowed:    event.Allowed(permission.PermUserReadEvents, permission.Context(permTypes.CtxUser, email)),
+	})
+}
+
+// title: enroll two-factor authentication
+// path: /users/2fa
+// method: POST
+// produce: application/json
+// responses:
+//   200: Enrollment started
+//   400: Invalid data
+//   401: Unauthorized
+//   409: Two-factor authentication already enabled
+func enrollTwoFactor(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	scheme, err := twoFactorScheme(t)
+	if err != nil {
+		return err
+	}
+	evt, err := newTwoFactorEvent(r, t, t.GetUserName(), permission.PermUserUpdateTwofactor)
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	enrollment, err := scheme.EnrollTwoFactor(r.Context(), t)
+	if err != nil {
+		return handleAuthError(err)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(enrollment)
+}
+
+// title: enable two-factor authentication
+// path: /users/2fa
+// method: PUT
+// consume: application/x-www-form-urlencoded
+// produce: application/json
+// responses:
+//   200: Two-factor authentication enabled
+//   400: Invalid data
+//   401: Unauthorized
+//   409: Two-factor authentication already enabled
+func enableTwoFactor(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	scheme, err := twoFactorScheme(t)
+	if err != nil {
+		return err
+	}
+	evt, err := newTwoFactorEvent(r, t, t.GetUserName(), permission.PermUserUpdateTwofactor)
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	code := twoFactorCode(r)
+	if code == "" {
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: auth.ErrTwoFactorRequired.Error()}
+	}
+	recoveryCodes, err := scheme.EnableTwoFactor(r.Context(), t, code)
+	if err != nil {
+		return handleAuthError(err)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(recoveryCodes)
+}
+
+// title: disable two-factor authentication
+// path: /users/2fa
+// method: DELETE
+// responses:
+//   200: Two-factor authentication disabled
+//   400: Invalid data
+//   401: Unauthorized
+func disableTwoFactor(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	scheme, err := twoFactorScheme(t)
+	if err != nil {
+		return err
+	}
+	evt, err := newTwoFactorEvent(r, t, t.GetUserName(), permission.PermUserUpdateTwofactor)
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	err = scheme.DisableTwoFactor(r.Context(), t, twoFactorCode(r))
+	if err != nil {
+		return handleAuthError(err)
+	}
+	return nil
+}
+
+// title: regenerate two-factor authentication recovery codes
+// path: /users/2fa/recovery-codes
+// method: POST
+// produce: application/json
+// responses:
+//   200: Recovery codes regenerated
+//   400: Invalid data
+//   401: Unauthorized
+func regenerateRecoveryCodes(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	scheme, err := twoFactorScheme(t)
+	if err != nil {
+		return err
+	}
+	evt, err := newTwoFactorEvent(r, t, t.GetUserName(), permission.PermUserUpdateTwofactor)
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	recoveryCodes, err := scheme.RegenerateRecoveryCodes(r.Context(), t, twoFactorCode(r))
+	if err != nil {
+		return handleAuthError(err)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(recoveryCodes)
+}
+
+// title: reset two-factor authentication
+// path: /users/{email}/2fa
+// method: DELETE
+// responses:
+//   200: Two-factor authentication reset
+//   400: Invalid data
+//   401: Unauthorized
+//   403: Forbidden
+//   404: User not found
+func resetTwoFactor(w http.ResponseWriter, r *http.Request, t auth.Token) (err error) {
+	scheme, ok := app.AuthScheme.(auth.TwoFactorScheme)
+	if !ok {
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: twoFactorUnsupportedMsg}
+	}
+	if !permission.Check(t, permission.PermUserUpdateTwofactorReset) {
+		return permission.ErrUnauthorized
+	}
+	email := r.URL.Query().Get(":email")
+	evt, err := newTwoFactorEvent(r, t, email, permission.PermUserUpdateTwofactorReset)
+	if err != nil {
+		return err
+	}
+	defer func() { evt.Done(err) }()
+	if err = checkTwoFactor(r, t); err != nil {
+		return err
+	}
+	u, err := auth.GetUserByEmail(email)
+	if err != nil {
+		if err == authTypes.ErrUserNotFound {
+			return &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+		}
+		return err
+	}
+	err = scheme.ResetTwoFactor(r.Context(), u)
+	if err != nil {
+		return handleAuthError(err)
+	}
+	return nil
+}
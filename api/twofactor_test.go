@@ -0,0 +1,306 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/tsuru/tsuru/app"
+	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/event/eventtest"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/permission/permissiontest"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+	check "gopkg.in/check.v1"
+)
+
+// currentTOTPCode returns the code an authenticator app would show for the
+// secret.
+func currentTOTPCode(c *check.C, secret string) string {
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	c.Assert(err, check.IsNil)
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(time.Now().Unix()/30))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0xf
+	return fmt.Sprintf("%06d", (binary.BigEndian.Uint32(sum[offset:offset+4])&0x7fffffff)%1000000)
+}
+
+// enableTwoFactor enables two-factor authentication for the user of the
+// token and returns its recovery codes.
+func (s *AuthSuite) enableTwoFactor(c *check.C, token auth.Token) []string {
+	scheme := app.AuthScheme.(auth.TwoFactorScheme)
+	enrollment, err := scheme.EnrollTwoFactor(context.TODO(), token)
+	c.Assert(err, check.IsNil)
+	codes, err := scheme.EnableTwoFactor(context.TODO(), token, currentTOTPCode(c, enrollment.Secret))
+	c.Assert(err, check.IsNil)
+	return codes
+}
+
+func (s *AuthSuite) TestEnrollAndEnableTwoFactor(c *check.C) {
+	request, err := http.NewRequest(http.MethodPost, "/1.13/users/2fa", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(recorder.Header().Get("Content-Type"), check.Equals, "application/json")
+	var enrollment auth.TwoFactorEnrollment
+	err = json.NewDecoder(recorder.Body).Decode(&enrollment)
+	c.Assert(err, check.IsNil)
+	c.Assert(enrollment.Secret, check.Not(check.Equals), "")
+	c.Assert(enrollment.URL, check.Matches, `otpauth://totp/tsuru:.*secret=`+enrollment.Secret+`.*`)
+	body := strings.NewReader("otp=" + currentTOTPCode(c, enrollment.Secret))
+	request, err = http.NewRequest(http.MethodPut, "/1.13/users/2fa", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder = httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	var codes []string
+	err = json.NewDecoder(recorder.Body).Decode(&codes)
+	c.Assert(err, check.IsNil)
+	c.Assert(codes, check.HasLen, 10)
+	c.Assert(eventtest.EventDesc{
+		Target: userTarget(s.token.GetUserName()),
+		Owner:  s.token.GetUserName(),
+		Kind:   "user.update.twofactor",
+	}, eventtest.HasEvent)
+}
+
+func (s *AuthSuite) TestEnableTwoFactorWithoutCode(c *check.C) {
+	request, err := http.NewRequest(http.MethodPut, "/1.13/users/2fa", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+}
+
+func (s *AuthSuite) TestEnrollTwoFactorAppToken(c *check.C) {
+	token, err := nativeScheme.AppLogin(context.TODO(), "myapp")
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest(http.MethodPost, "/1.13/users/2fa", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+	c.Assert(recorder.Body.String(), check.Equals, twoFactorUserOnlyMsg+"\n")
+}
+
+func (s *AuthSuite) TestLoginWithTwoFactor(c *check.C) {
+	u := &auth.User{Email: "me@globo.com", Password: "123456"}
+	_, err := nativeScheme.Create(context.TODO(), u)
+	c.Assert(err, check.IsNil)
+	token, err := nativeScheme.Login(context.TODO(), map[string]string{"email": u.Email, "password": "123456"})
+	c.Assert(err, check.IsNil)
+	codes := s.enableTwoFactor(c, token)
+	request, err := http.NewRequest(http.MethodPost, "/auth/login", strings.NewReader("email=me@globo.com&password=123456"))
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusUnauthorized)
+	c.Assert(recorder.Header().Get("X-Tsuru-Error-Code"), check.Equals, "auth.two-factor-required")
+	request, err = http.NewRequest(http.MethodPost, "/auth/login", strings.NewReader("email=me@globo.com&password=123456&otp=000000"))
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	recorder = httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusUnauthorized)
+	request, err = http.NewRequest(http.MethodPost, "/auth/login", strings.NewReader("email=me@globo.com&password=123456&otp="+codes[0]))
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	recorder = httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+}
+
+func (s *AuthSuite) TestChangePasswordWithTwoFactor(c *check.C) {
+	u := &auth.User{Email: "me@globo.com", Password: "123456"}
+	_, err := nativeScheme.Create(context.TODO(), u)
+	c.Assert(err, check.IsNil)
+	token, err := nativeScheme.Login(context.TODO(), map[string]string{"email": u.Email, "password": "123456"})
+	c.Assert(err, check.IsNil)
+	codes := s.enableTwoFactor(c, token)
+	request, err := http.NewRequest(http.MethodPut, "/users/password", strings.NewReader("old=123456&new=654321&confirm=654321"))
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusUnauthorized)
+	c.Assert(recorder.Header().Get("X-Tsuru-Error-Code"), check.Equals, "auth.two-factor-required")
+	request, err = http.NewRequest(http.MethodPut, "/users/password", strings.NewReader("old=123456&new=654321&confirm=654321&otp="+codes[0]))
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder = httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	evts, err := event.List(&event.Filter{
+		Target:    userTarget(u.Email),
+		KindNames: []string{permission.PermUserUpdatePassword.FullName()},
+	})
+	c.Assert(err, check.IsNil)
+	for _, evt := range evts {
+		var data []map[string]interface{}
+		err = evt.StartData(&data)
+		c.Assert(err, check.IsNil)
+		c.Assert(data, check.HasLen, 0)
+	}
+}
+
+func (s *AuthSuite) TestRegenerateAPITokenWithTwoFactor(c *check.C) {
+	codes := s.enableTwoFactor(c, s.token)
+	request, err := http.NewRequest(http.MethodPost, "/users/api-key", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusUnauthorized)
+	request, err = http.NewRequest(http.MethodPost, "/users/api-key", strings.NewReader("otp="+codes[0]))
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder = httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(eventtest.EventDesc{
+		Target: userTarget(s.token.GetUserName()),
+		Owner:  s.token.GetUserName(),
+		Kind:   "user.update.token",
+	}, eventtest.HasEvent)
+}
+
+func (s *AuthSuite) TestRemoveUserWithTwoFactor(c *check.C) {
+	u := &auth.User{Email: "me@globo.com", Password: "123456"}
+	_, err := nativeScheme.Create(context.TODO(), u)
+	c.Assert(err, check.IsNil)
+	token, err := nativeScheme.Login(context.TODO(), map[string]string{"email": u.Email, "password": "123456"})
+	c.Assert(err, check.IsNil)
+	codes := s.enableTwoFactor(c, token)
+	request, err := http.NewRequest(http.MethodDelete, "/users", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusUnauthorized)
+	_, err = auth.GetUserByEmail(u.Email)
+	c.Assert(err, check.IsNil)
+	request, err = http.NewRequest(http.MethodDelete, "/users?otp="+codes[0], nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder = httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	_, err = auth.GetUserByEmail(u.Email)
+	c.Assert(err, check.NotNil)
+}
+
+func (s *AuthSuite) TestDisableTwoFactor(c *check.C) {
+	codes := s.enableTwoFactor(c, s.token)
+	request, err := http.NewRequest(http.MethodDelete, "/1.13/users/2fa", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusUnauthorized)
+	request, err = http.NewRequest(http.MethodDelete, "/1.13/users/2fa", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	request.Header.Set("X-Tsuru-OTP", codes[0])
+	recorder = httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	request, err = http.NewRequest(http.MethodDelete, "/1.13/users/2fa", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	request.Header.Set("X-Tsuru-OTP", codes[1])
+	recorder = httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+}
+
+func (s *AuthSuite) TestRegenerateRecoveryCodes(c *check.C) {
+	codes := s.enableTwoFactor(c, s.token)
+	request, err := http.NewRequest(http.MethodPost, "/1.13/users/2fa/recovery-codes", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	request.Header.Set("X-Tsuru-OTP", codes[0])
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	var newCodes []string
+	err = json.NewDecoder(recorder.Body).Decode(&newCodes)
+	c.Assert(err, check.IsNil)
+	c.Assert(newCodes, check.HasLen, 10)
+	err = app.AuthScheme.(auth.TwoFactorScheme).VerifyTwoFactor(context.TODO(), s.token, codes[1])
+	c.Assert(err, check.NotNil)
+	err = app.AuthScheme.(auth.TwoFactorScheme).VerifyTwoFactor(context.TODO(), s.token, newCodes[1])
+	c.Assert(err, check.IsNil)
+}
+
+func (s *AuthSuite) TestResetTwoFactor(c *check.C) {
+	u := &auth.User{Email: "me@globo.com", Password: "123456"}
+	_, err := nativeScheme.Create(context.TODO(), u)
+	c.Assert(err, check.IsNil)
+	token, err := nativeScheme.Login(context.TODO(), map[string]string{"email": u.Email, "password": "123456"})
+	c.Assert(err, check.IsNil)
+	s.enableTwoFactor(c, token)
+	request, err := http.NewRequest(http.MethodDelete, "/1.13/users/me@globo.com/2fa", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(eventtest.EventDesc{
+		Target: userTarget(u.Email),
+		Owner:  s.token.GetUserName(),
+		Kind:   "user.update.twofactor.reset",
+	}, eventtest.HasEvent)
+	_, err = nativeScheme.Login(context.TODO(), map[string]string{"email": u.Email, "password": "123456"})
+	c.Assert(err, check.IsNil)
+	recorder = httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+}
+
+func (s *AuthSuite) TestResetTwoFactorUserNotFound(c *check.C) {
+	request, err := http.NewRequest(http.MethodDelete, "/1.13/users/unknown@globo.com/2fa", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNotFound)
+}
+
+func (s *AuthSuite) TestResetTwoFactorWithoutPermission(c *check.C) {
+	_, token := permissiontest.CustomUserWithPermission(c, nativeScheme, "user-admin", permission.Permission{
+		Scheme:  permission.PermUserUpdate,
+		Context: permission.Context(permTypes.CtxUser, s.user.Email),
+	})
+	request, err := http.NewRequest(http.MethodDelete, "/1.13/users/"+s.user.Email+"/2fa", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusForbidden)
+}
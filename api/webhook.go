@@ -6,9 +6,12 @@ package api
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 
+	"github.com/globalsign/mgo/bson"
 	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/errors"
 	"github.com/tsuru/tsuru/event"
 	"github.com/tsuru/tsuru/permission"
 	"github.com/tsuru/tsuru/servicemanager"
@@ -192,3 +195,156 @@ func webhookDelete(w http.ResponseWriter, r *http.Request, t auth.Token) error {
 	}()
 	return servicemanager.Webhook.Delete(webhookName)
 }
+
+// title: webhook dead letter list
+// path: /events/webhooks/{name}/deadletters
+// method: GET
+// produce: application/json
+// responses:
+//   200: List dead letters
+//   204: No content
+//   401: Unauthorized
+//   404: Webhook not found
+func webhookDeadLetterList(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	webhookName := r.URL.Query().Get(":name")
+	webhook, err := servicemanager.Webhook.Find(webhookName)
+	if err != nil {
+		if err == eventTypes.ErrWebhookNotFound {
+			w.WriteHeader(http.StatusNotFound)
+		}
+		return err
+	}
+	ctx := permission.Context(permTypes.CtxTeam, webhook.TeamOwner)
+	if !permission.Check(t, permission.PermWebhookRead, ctx) {
+		return permission.ErrUnauthorized
+	}
+	deadLetters, err := servicemanager.Webhook.DeadLetters(webhookName)
+	if err != nil {
+		return err
+	}
+	if len(deadLetters) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(deadLetters)
+}
+
+// title: webhook dead letter redispatch
+// path: /events/webhooks/{name}/deadletters/{id}/redispatch
+// method: POST
+// responses:
+//   200: Dead letter redispatched
+//   401: Unauthorized
+//   404: Webhook or dead letter not found
+func webhookDeadLetterRedispatch(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	webhookName := r.URL.Query().Get(":name")
+	webhook, err := servicemanager.Webhook.Find(webhookName)
+	if err != nil {
+		if err == eventTypes.ErrWebhookNotFound {
+			w.WriteHeader(http.StatusNotFound)
+		}
+		return err
+	}
+	ctx := permission.Context(permTypes.CtxTeam, webhook.TeamOwner)
+	if !permission.Check(t, permission.PermWebhookUpdate, ctx) {
+		return permission.ErrUnauthorized
+	}
+	evt, err := event.New(&event.Opts{
+		Target:     event.Target{Type: event.TargetTypeWebhook, Value: webhook.Name},
+		Kind:       permission.PermWebhookUpdate,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		CustomData: event.FormToCustomData(InputFields(r)),
+		Allowed:    event.Allowed(permission.PermWebhookReadEvents, ctx),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		evt.Done(err)
+	}()
+	err = servicemanager.Webhook.Redispatch(webhook.Name, r.URL.Query().Get(":id"))
+	if err == eventTypes.ErrDeadLetterNotFound {
+		w.WriteHeader(http.StatusNotFound)
+	}
+	return err
+}
+
+// title: webhook event dispatch
+// path: /events/webhooks/{name}/dispatch
+// method: POST
+// consume: application/x-www-form-urlencoded
+// responses:
+//   200: Event dispatched
+//   400: Invalid event id
+//   401: Unauthorized
+//   404: Webhook or event not found
+func webhookDispatch(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	webhookName := r.URL.Query().Get(":name")
+	webhook, err := servicemanager.Webhook.Find(webhookName)
+	if err != nil {
+		if err == eventTypes.ErrWebhookNotFound {
+			w.WriteHeader(http.StatusNotFound)
+		}
+		return err
+	}
+	ctx := permission.Context(permTypes.CtxTeam, webhook.TeamOwner)
+	if !permission.Check(t, permission.PermWebhookUpdate, ctx) {
+		return permission.ErrUnauthorized
+	}
+	eventID := InputValue(r, "event_id")
+	if !bson.IsObjectIdHex(eventID) {
+		return &errors.HTTP{Code: http.StatusBadRequest, Message: fmt.Sprintf("event_id parameter is not ObjectId: %s", eventID)}
+	}
+	dispatched, err := event.GetByHexID(eventID)
+	if err != nil {
+		return &errors.HTTP{Code: http.StatusNotFound, Message: err.Error()}
+	}
+	scheme, err := permission.SafeGet(dispatched.Allowed.Scheme)
+	if err != nil {
+		return err
+	}
+	if !permission.Check(t, scheme, dispatched.Allowed.Contexts...) {
+		return permission.ErrUnauthorized
+	}
+	evt, err := event.New(&event.Opts{
+		Target:     event.Target{Type: event.TargetTypeWebhook, Value: webhook.Name},
+		Kind:       permission.PermWebhookUpdate,
+		Owner:      t,
+		RemoteAddr: r.RemoteAddr,
+		CustomData: event.FormToCustomData(InputFields(r)),
+		Allowed:    event.Allowed(permission.PermWebhookReadEvents, ctx),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		evt.Done(err)
+	}()
+	err = servicemanager.Webhook.Dispatch(webhook.Name, eventID)
+	return err
+}
+
+// title: webhook test
+// path: /events/webhooks/{name}/test
+// method: POST
+// responses:
+//   200: Test event delivered
+//   401: Unauthorized
+//   404: Webhook not found
+func webhookTestFire(w http.ResponseWriter, r *http.Request, t auth.Token) error {
+	webhookName := r.URL.Query().Get(":name")
+	webhook, err := servicemanager.Webhook.Find(webhookName)
+	if err != nil {
+		if err == eventTypes.ErrWebhookNotFound {
+			w.WriteHeader(http.StatusNotFound)
+		}
+		return err
+	}
+	ctx := permission.Context(permTypes.CtxTeam, webhook.TeamOwner)
+	if !permission.Check(t, permission.PermWebhookUpdate, ctx) {
+		return permission.ErrUnauthorized
+	}
+	return servicemanager.Webhook.TestFire(webhook.Name, t.GetUserName())
+}
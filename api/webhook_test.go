@@ -9,10 +9,14 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync/atomic"
 
 	"github.com/ajg/form"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tsuru/tsuru/event"
 	"github.com/tsuru/tsuru/permission"
 	"github.com/tsuru/tsuru/servicemanager"
+	"github.com/tsuru/tsuru/storage"
 	eventTypes "github.com/tsuru/tsuru/types/event"
 	permTypes "github.com/tsuru/tsuru/types/permission"
 	check "gopkg.in/check.v1"
@@ -394,3 +398,184 @@ func (s *S) TestWebhookInfoNotFound(c *check.C) {
 	s.testServer.ServeHTTP(recorder, request)
 	c.Assert(recorder.Code, check.Equals, http.StatusNotFound)
 }
+
+func (s *S) TestWebhookDeadLetterList(c *check.C) {
+	err := servicemanager.Webhook.Create(eventTypes.Webhook{
+		TeamOwner: s.team.Name,
+		Name:      "wh1",
+		URL:       "http://me/xyz",
+	})
+	c.Assert(err, check.IsNil)
+	dbDriver, err := storage.GetCurrentDbDriver()
+	c.Assert(err, check.IsNil)
+	err = dbDriver.WebhookStorage.InsertDeadLetter(eventTypes.WebhookDeadLetter{
+		ID:       "dl1",
+		Webhook:  "wh1",
+		EventID:  "evt1",
+		Attempts: 3,
+		Error:    "my error",
+	})
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest("GET", "/1.13/events/webhooks/wh1/deadletters", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	var deadLetters []eventTypes.WebhookDeadLetter
+	err = json.Unmarshal(recorder.Body.Bytes(), &deadLetters)
+	c.Assert(err, check.IsNil)
+	c.Assert(deadLetters, check.HasLen, 1)
+	c.Assert(deadLetters[0].ID, check.Equals, "dl1")
+	c.Assert(deadLetters[0].EventID, check.Equals, "evt1")
+	c.Assert(deadLetters[0].Attempts, check.Equals, 3)
+	c.Assert(deadLetters[0].Error, check.Equals, "my error")
+}
+
+func (s *S) TestWebhookDeadLetterListEmpty(c *check.C) {
+	err := servicemanager.Webhook.Create(eventTypes.Webhook{
+		TeamOwner: s.team.Name,
+		Name:      "wh1",
+		URL:       "http://me/xyz",
+	})
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest("GET", "/1.13/events/webhooks/wh1/deadletters", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNoContent)
+}
+
+func (s *S) TestWebhookDeadLetterRedispatchNotFound(c *check.C) {
+	err := servicemanager.Webhook.Create(eventTypes.Webhook{
+		TeamOwner: s.team.Name,
+		Name:      "wh1",
+		URL:       "http://me/xyz",
+	})
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest("POST", "/1.13/events/webhooks/wh1/deadletters/dl1/redispatch", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNotFound)
+}
+
+func (s *S) TestWebhookDeadLetterRedispatchOtherWebhook(c *check.C) {
+	err := servicemanager.Webhook.Create(eventTypes.Webhook{
+		TeamOwner: s.team.Name,
+		Name:      "wh1",
+		URL:       "http://me/xyz",
+	})
+	c.Assert(err, check.IsNil)
+	err = servicemanager.Webhook.Create(eventTypes.Webhook{
+		TeamOwner: s.team.Name,
+		Name:      "wh2",
+		URL:       "http://me/abc",
+	})
+	c.Assert(err, check.IsNil)
+	dbDriver, err := storage.GetCurrentDbDriver()
+	c.Assert(err, check.IsNil)
+	err = dbDriver.WebhookStorage.InsertDeadLetter(eventTypes.WebhookDeadLetter{
+		ID:      "dl1",
+		Webhook: "wh2",
+		EventID: bson.NewObjectId().Hex(),
+	})
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest("POST", "/1.13/events/webhooks/wh1/deadletters/dl1/redispatch", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNotFound)
+	_, err = dbDriver.WebhookStorage.FindDeadLetter("dl1")
+	c.Assert(err, check.IsNil)
+}
+
+func (s *S) TestWebhookDispatch(c *check.C) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+	}))
+	defer srv.Close()
+	err := servicemanager.Webhook.Create(eventTypes.Webhook{
+		TeamOwner: s.team.Name,
+		Name:      "wh1",
+		URL:       srv.URL,
+	})
+	c.Assert(err, check.IsNil)
+	evt, err := event.New(&event.Opts{
+		Target:  event.Target{Type: event.TargetTypeApp, Value: "myapp"},
+		Kind:    permission.PermAppUpdateEnvSet,
+		Owner:   s.token,
+		Allowed: event.Allowed(permission.PermAppReadEvents),
+	})
+	c.Assert(err, check.IsNil)
+	err = evt.Done(nil)
+	c.Assert(err, check.IsNil)
+	body := strings.NewReader("event_id=" + evt.UniqueID.Hex())
+	request, err := http.NewRequest("POST", "/1.13/events/webhooks/wh1/dispatch", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(atomic.LoadInt32(&calls), check.Equals, int32(1))
+}
+
+func (s *S) TestWebhookDispatchInvalidEvent(c *check.C) {
+	err := servicemanager.Webhook.Create(eventTypes.Webhook{
+		TeamOwner: s.team.Name,
+		Name:      "wh1",
+		URL:       "http://me/xyz",
+	})
+	c.Assert(err, check.IsNil)
+	body := strings.NewReader("event_id=abc")
+	request, err := http.NewRequest("POST", "/1.13/events/webhooks/wh1/dispatch", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusBadRequest)
+	body = strings.NewReader("event_id=" + bson.NewObjectId().Hex())
+	request, err = http.NewRequest("POST", "/1.13/events/webhooks/wh1/dispatch", body)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	recorder = httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNotFound)
+}
+
+func (s *S) TestWebhookTestFire(c *check.C) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+	}))
+	defer srv.Close()
+	err := servicemanager.Webhook.Create(eventTypes.Webhook{
+		TeamOwner: s.team.Name,
+		Name:      "wh1",
+		URL:       srv.URL,
+	})
+	c.Assert(err, check.IsNil)
+	request, err := http.NewRequest("POST", "/1.13/events/webhooks/wh1/test", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusOK)
+	c.Assert(atomic.LoadInt32(&calls), check.Equals, int32(1))
+}
+
+func (s *S) TestWebhookTestFireNotFound(c *check.C) {
+	request, err := http.NewRequest("POST", "/1.13/events/webhooks/wh1/test", nil)
+	c.Assert(err, check.IsNil)
+	request.Header.Set("Authorization", "bearer "+s.token.GetValue())
+	recorder := httptest.NewRecorder()
+	s.testServer.ServeHTTP(recorder, request)
+	c.Assert(recorder.Code, check.Equals, http.StatusNotFound)
+}
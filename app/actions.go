@@ -26,7 +26,6 @@ import (
 	"github.com/tsuru/tsuru/router/rebuild"
 	"github.com/tsuru/tsuru/servicemanager"
 	appTypes "github.com/tsuru/tsuru/types/app"
-	authTypes "github.com/tsuru/tsuru/types/auth"
 	"github.com/tsuru/tsuru/types/quota"
 )
 
@@ -44,7 +43,7 @@ var reserveTeamApp = action.Action{
 		default:
 			return nil, errors.New("first parameter must be *App.")
 		}
-		if err := servicemanager.TeamQuota.Inc(ctx.Context, &authTypes.Team{Name: app.TeamOwner}, 1); err != nil {
+		if err := reserveTeamQuota(ctx.Context, app.TeamOwner, app.Plan); err != nil {
 			return nil, err
 		}
 		return map[string]string{"app": app.Name, "team": app.TeamOwner}, nil
@@ -52,7 +51,7 @@ var reserveTeamApp = action.Action{
 	Backward: func(ctx action.BWContext) {
 		m := ctx.FWResult.(map[string]string)
 		if teamStr, ok := m["team"]; ok {
-			servicemanager.TeamQuota.Inc(ctx.Context, &authTypes.Team{Name: teamStr}, -1)
+			releaseTeamQuota(ctx.Context, teamStr)
 		}
 	},
 	MinParams: 2,
@@ -919,8 +918,9 @@ var swapCNamesInDatabaseAction = action.Action{
 	Forward: func(ctx action.FWContext) (action.Result, error) {
 		app1 := ctx.Params[0].(*App)
 		app2 := ctx.Params[1].(*App)
+		cnames := ctx.Params[2].([]string)
 
-		return nil, swapCNamesInDatabase(app1, app2)
+		return nil, swapCNamesInDatabase(app1, app2, cnames)
 	},
 }
 
@@ -935,8 +935,9 @@ var swapReEnsureBackendsAction = action.Action{
 	Backward: func(ctx action.BWContext) {
 		app1 := ctx.Params[0].(*App)
 		app2 := ctx.Params[1].(*App)
+		cnames := ctx.Params[2].([]string)
 
-		err := swapCNamesInDatabase(app1, app2)
+		err := swapCNamesInDatabase(app1, app2, cnames)
 		if err != nil {
 			return
 		}
@@ -945,13 +946,19 @@ var swapReEnsureBackendsAction = action.Action{
 	},
 }
 
-func swapCNamesInDatabase(app1 *App, app2 *App) error {
+// swapCNamesInDatabase exchanges the cnames of the apps, when cnames is not
+// empty only the given cnames are moved to the other app.
+func swapCNamesInDatabase(app1 *App, app2 *App, cnames []string) error {
 	conn, err := db.Conn()
 	if err != nil {
 		return err
 	}
 	defer conn.Close()
-	app1.CName, app2.CName = app2.CName, app1.CName
+	if len(cnames) == 0 {
+		app1.CName, app2.CName = app2.CName, app1.CName
+	} else {
+		app1.CName, app2.CName = moveCNames(app1.CName, app2.CName, cnames)
+	}
 	updateCName := func(app *App) error {
 		return conn.Apps().Update(
 			bson.M{"name": app.Name},
@@ -970,6 +977,28 @@ func swapCNamesInDatabase(app1 *App, app2 *App) error {
 	return nil
 }
 
+// moveCNames returns the cnames lists after moving the selected cnames to the
+// list they're not in.
+func moveCNames(cnames1, cnames2, selected []string) ([]string, []string) {
+	newCNames1 := []string{}
+	newCNames2 := []string{}
+	for _, cname := range cnames1 {
+		if cnameInSet(cname, selected) {
+			newCNames2 = append(newCNames2, cname)
+		} else {
+			newCNames1 = append(newCNames1, cname)
+		}
+	}
+	for _, cname := range cnames2 {
+		if cnameInSet(cname, selected) {
+			newCNames1 = append(newCNames1, cname)
+		} else {
+			newCNames2 = append(newCNames2, cname)
+		}
+	}
+	return newCNames1, newCNames2
+}
+
 func swapRebuildRoutes(ctx context.Context, app1 *App, app2 *App) error {
 	// preserveOldCnames makes the operation without downtime, however when using diffent pools we cant do that
 	preserveOldCNames := app1.Pool == app2.Pool
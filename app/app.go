@@ -27,6 +27,7 @@ import (
 	"github.com/tsuru/tsuru/action"
 	"github.com/tsuru/tsuru/app/bind"
 	"github.com/tsuru/tsuru/app/image"
+	"github.com/tsuru/tsuru/app/secretref"
 	"github.com/tsuru/tsuru/auth"
 	"github.com/tsuru/tsuru/builder"
 	"github.com/tsuru/tsuru/db"
@@ -66,13 +67,19 @@ var (
 
 	ErrRouterAlreadyLinked = errors.New("router already linked to this app")
 
-	ErrNoVersionProvisioner = errors.New("The current app provisioner does not support multiple versions handling")
-	ErrKillUnitProvisioner  = errors.New("The current app provisioner does not support killing a unit")
-	ErrSwapMultipleVersions = errors.New("swapping apps with multiple versions is not allowed")
-	ErrSwapMultipleRouters  = errors.New("swapping apps with multiple routers is not supported")
-	ErrSwapDifferentRouters = errors.New("swapping apps with different routers is not supported")
-	ErrSwapNoCNames         = errors.New("no cnames to swap")
-	ErrSwapDeprecated       = errors.New("swapping using router api v2 will work only with cnameOnly")
+	ErrNoVersionProvisioner   = errors.New("The current app provisioner does not support multiple versions handling")
+	ErrKillUnitProvisioner    = errors.New("The current app provisioner does not support killing a unit")
+	ErrRestartUnitProvisioner = errors.New("The current app provisioner does not support restarting a unit")
+	ErrDebugUnitProvisioner   = errors.New("The current app provisioner does not support debugging a unit")
+	ErrSwapMultipleVersions   = errors.New("swapping apps with multiple versions is not allowed")
+	ErrSwapMultipleRouters    = errors.New("swapping apps with multiple routers is not supported")
+	ErrSwapDifferentRouters   = errors.New("swapping apps with different routers is not supported")
+	ErrSwapNoCNames           = errors.New("no cnames to swap")
+	ErrSwapDeprecated         = errors.New("swapping using router api v2 will work only with cnameOnly")
+	ErrSwapAddressWithCNames  = &tsuruErrors.ValidationError{Message: "swapping only the router address can't be combined with cnames swap"}
+	ErrInternalAppRouter      = &tsuruErrors.ValidationError{Message: "internal apps can't be added to routers"}
+
+	ErrInvalidTerminationGracePeriod = &tsuruErrors.ValidationError{Message: "termination grace period must be greater than or equal to 0"}
 )
 
 var (
@@ -124,6 +131,22 @@ type App struct {
 	Error           string
 	Routers         []appTypes.AppRouter
 	Metadata        appTypes.Metadata
+	Canary          *CanaryDeploy `json:",omitempty" bson:",omitempty"`
+	// ProcessPlans holds the plans of processes not using the app plan.
+	ProcessPlans map[string]appTypes.Plan `json:",omitempty" bson:",omitempty"`
+	// ExtraPools holds the pools, besides Pool, where the app is deployed.
+	ExtraPools []ExtraPool `json:",omitempty" bson:",omitempty"`
+	// Maintenance is set while the app is in maintenance mode.
+	Maintenance *Maintenance `json:",omitempty" bson:",omitempty"`
+	// Internal apps are not added to routers, they're only reachable by
+	// their internal addresses.
+	Internal bool `json:",omitempty" bson:",omitempty"`
+	// TerminationGracePeriods holds the time, in seconds, units of each
+	// process have to finish after being asked to stop.
+	TerminationGracePeriods map[string]int `json:",omitempty" bson:",omitempty"`
+	// Scheduling holds tolerations and affinity of the units of the app,
+	// added to the ones of the pool.
+	Scheduling *appTypes.Scheduling `json:",omitempty" bson:",omitempty"`
 
 	// UUID is a v4 UUID lazily generated on the first call to GetUUID()
 	UUID string
@@ -274,6 +297,24 @@ func (app *App) MarshalJSON() ([]byte, error) {
 	result["deploys"] = app.Deploys
 	result["teamowner"] = app.TeamOwner
 	result["plan"] = plan
+	if len(app.ProcessPlans) > 0 {
+		result["processPlans"] = app.ProcessPlans
+	}
+	if len(app.ExtraPools) > 0 {
+		result["extraPools"] = app.ExtraPools
+	}
+	if len(app.TerminationGracePeriods) > 0 {
+		result["terminationGracePeriods"] = app.TerminationGracePeriods
+	}
+	if app.Scheduling != nil {
+		result["scheduling"] = app.Scheduling
+	}
+	if app.Maintenance != nil {
+		result["maintenance"] = app.Maintenance
+	}
+	if app.Internal {
+		result["internal"] = true
+	}
 	result["lock"] = app.Lock
 	result["tags"] = app.Tags
 	result["routers"] = routers
@@ -358,6 +399,70 @@ func GetByName(ctx context.Context, name string) (*App, error) {
 //       1. Save the app in the database
 //       2. Provision the app using the provisioner
 func CreateApp(ctx context.Context, app *App, user *auth.User) error {
+	err := prepareNewApp(ctx, app, user)
+	if err != nil {
+		return err
+	}
+	actions := []*action.Action{
+		&reserveTeamApp,
+		&reserveUserApp,
+		&insertApp,
+		&createAppToken,
+		&exportEnvironmentsAction,
+		&provisionApp,
+		&addRouterBackend,
+	}
+	pipeline := action.NewPipeline(actions...)
+	err = pipeline.Execute(ctx, app, user)
+	if err != nil {
+		return &appTypes.AppCreationError{App: app.Name, Err: err}
+	}
+	return nil
+}
+
+// ValidateNewApp runs the validations of CreateApp, including the quotas of
+// the team and the user, filling the app with the values it would be created
+// with. Nothing is saved or provisioned.
+func ValidateNewApp(ctx context.Context, app *App, user *auth.User) error {
+	err := prepareNewApp(ctx, app, user)
+	if err != nil {
+		return err
+	}
+	err = checkAppNotBeingCreated(app.Name)
+	if err != nil {
+		return err
+	}
+	weight := planQuotaWeight(TeamQuotaUnit(), app.Plan)
+	err = checkQuota(ctx, servicemanager.TeamQuota, TeamQuotaItem(app.TeamOwner), weight)
+	if err == nil {
+		err = checkAncestorTeamQuotas(ctx, app.TeamOwner, weight)
+	}
+	if err == nil && !user.FromToken {
+		err = checkQuota(ctx, servicemanager.UserQuota, user, 1)
+	}
+	if err != nil {
+		return &appTypes.AppCreationError{App: app.Name, Err: err}
+	}
+	return nil
+}
+
+// checkAppNotBeingCreated fails when the creation of an app with the same
+// name is still running. Apps are only saved in the middle of their creation,
+// so looking them up by name doesn't tell whether the name is taken.
+func checkAppNotBeingCreated(name string) error {
+	_, err := event.GetRunning(event.Target{Type: event.TargetTypeApp, Value: name}, permission.PermAppCreate.FullName())
+	if err == event.ErrEventNotFound {
+		return nil
+	}
+	if err != nil {
+		return errors.WithMessage(err, "unable to check if app already exists")
+	}
+	return &appTypes.AppCreationError{Err: ErrAppAlreadyExists, App: name}
+}
+
+// prepareNewApp fills the app with its default pool, plan, routers and
+// platform, validating it.
+func prepareNewApp(ctx context.Context, app *App, user *auth.User) error {
 	if app.ctx == nil {
 		app.ctx = ctx
 	}
@@ -400,28 +505,18 @@ func CreateApp(ctx context.Context, app *App, user *auth.User) error {
 			return err
 		}
 	}
-	err = app.validateNew(ctx)
-	if err != nil {
-		return err
-	}
-	actions := []*action.Action{
-		&reserveTeamApp,
-		&reserveUserApp,
-		&insertApp,
-		&createAppToken,
-		&exportEnvironmentsAction,
-		&provisionApp,
-		&addRouterBackend,
-	}
-	pipeline := action.NewPipeline(actions...)
-	err = pipeline.Execute(ctx, app, user)
-	if err != nil {
-		return &appTypes.AppCreationError{App: app.Name, Err: err}
-	}
-	return nil
+	return app.validateNew(ctx)
 }
 
 func (app *App) configureCreateRouters() error {
+	if app.Internal {
+		if len(app.Routers) > 0 || (app.Router != "" && app.Router != routerNone) {
+			return ErrInternalAppRouter
+		}
+		app.Router = ""
+		app.RouterOpts = nil
+		return nil
+	}
 	if len(app.Routers) > 0 {
 		return nil
 	}
@@ -457,6 +552,11 @@ type UpdateAppArgs struct {
 	UpdateData    App
 	Writer        io.Writer
 	ShouldRestart bool
+	// Internal changes whether the app is internal when set.
+	Internal *bool
+	// DryRun runs the validations of the update, changing only the app in
+	// memory, without saving or provisioning it.
+	DryRun bool
 }
 
 // Update changes informations of the application.
@@ -495,6 +595,57 @@ func (app *App) Update(args UpdateAppArgs) (err error) {
 		app.Plan = *plan
 	}
 	app.Plan.MergeOverride(args.UpdateData.Plan.Override)
+	if len(args.UpdateData.ProcessPlans) > 0 {
+		processPlans := make(map[string]appTypes.Plan, len(app.ProcessPlans))
+		for process, plan := range app.ProcessPlans {
+			processPlans[process] = plan
+		}
+		for process, plan := range args.UpdateData.ProcessPlans {
+			if plan.Name == "" {
+				delete(processPlans, process)
+				continue
+			}
+			newPlan, errFind := servicemanager.Plan.FindByName(app.ctx, plan.Name)
+			if errFind != nil {
+				return errFind
+			}
+			processPlans[process] = *newPlan
+		}
+		app.ProcessPlans = nil
+		if len(processPlans) > 0 {
+			app.ProcessPlans = processPlans
+		}
+	}
+	if len(args.UpdateData.TerminationGracePeriods) > 0 {
+		gracePeriods := make(map[string]int, len(app.TerminationGracePeriods))
+		for process, seconds := range app.TerminationGracePeriods {
+			gracePeriods[process] = seconds
+		}
+		for process, seconds := range args.UpdateData.TerminationGracePeriods {
+			if seconds < 0 {
+				return ErrInvalidTerminationGracePeriod
+			}
+			if seconds == 0 {
+				delete(gracePeriods, process)
+				continue
+			}
+			gracePeriods[process] = seconds
+		}
+		app.TerminationGracePeriods = nil
+		if len(gracePeriods) > 0 {
+			app.TerminationGracePeriods = gracePeriods
+		}
+	}
+	if args.UpdateData.Scheduling != nil {
+		err = args.UpdateData.Scheduling.Validate()
+		if err != nil {
+			return err
+		}
+		app.Scheduling = nil
+		if !args.UpdateData.Scheduling.Empty() {
+			app.Scheduling = args.UpdateData.Scheduling
+		}
+	}
 	if teamOwner != "" {
 		team, errTeam := servicemanager.Team.FindByName(app.ctx, teamOwner)
 		if errTeam != nil {
@@ -502,7 +653,7 @@ func (app *App) Update(args UpdateAppArgs) (err error) {
 		}
 		app.TeamOwner = team.Name
 		defer func() {
-			if err == nil {
+			if err == nil && !args.DryRun {
 				app.Grant(team)
 			}
 		}()
@@ -530,10 +681,20 @@ func (app *App) Update(args UpdateAppArgs) (err error) {
 	if args.UpdateData.UpdatePlatform {
 		app.UpdatePlatform = true
 	}
+	if args.Internal != nil {
+		app.Internal = *args.Internal
+	}
+	err = app.checkTeamQuotaChange(app.ctx, &oldApp, args.DryRun)
+	if err != nil {
+		return err
+	}
 	err = app.validate()
 	if err != nil {
 		return err
 	}
+	if args.DryRun {
+		return app.validateUpdateDryRun(&oldApp, newProv, oldProv)
+	}
 	actions := []*action.Action{
 		&saveApp,
 	}
@@ -554,12 +715,65 @@ func (app *App) Update(args UpdateAppArgs) (err error) {
 			&provisionAppNewProvisioner,
 			&provisionAppAddUnits,
 			&destroyAppOldProvisioner)
-	} else if !reflect.DeepEqual(app.Plan, oldApp.Plan) && args.ShouldRestart {
+	} else if (!reflect.DeepEqual(app.Plan, oldApp.Plan) || !reflect.DeepEqual(app.ProcessPlans, oldApp.ProcessPlans) || !app.Metadata.Equal(oldApp.Metadata) || app.Internal != oldApp.Internal || !reflect.DeepEqual(app.TerminationGracePeriods, oldApp.TerminationGracePeriods) || !reflect.DeepEqual(app.Scheduling, oldApp.Scheduling)) && args.ShouldRestart {
 		actions = append(actions, &restartApp)
 	} else if app.Pool != oldApp.Pool && !updatePipelineAdded {
 		actions = append(actions, &restartApp)
 	}
-	return action.NewPipeline(actions...).Execute(app.ctx, app, &oldApp, args.Writer)
+	err = action.NewPipeline(actions...).Execute(app.ctx, app, &oldApp, args.Writer)
+	if err != nil {
+		return err
+	}
+	if app.Internal != oldApp.Internal {
+		return app.updateInternalRouters()
+	}
+	return nil
+}
+
+// validateUpdateDryRun runs the validations of Update that happen while
+// provisioning the changes.
+func (app *App) validateUpdateDryRun(oldApp *App, newProv, oldProv provision.Provisioner) error {
+	if newProv.GetName() != oldProv.GetName() {
+		err := validateVolumes(app.ctx, app)
+		if err != nil {
+			return err
+		}
+	}
+	if app.Internal || !oldApp.Internal || len(app.GetRouters()) > 0 {
+		return nil
+	}
+	appPool, err := pool.GetPoolByName(app.ctx, app.GetPool())
+	if err != nil {
+		return err
+	}
+	_, err = appPool.GetDefaultRouter()
+	return err
+}
+
+// updateInternalRouters removes the routers of apps made internal and adds
+// the pool default router to apps that are no longer internal.
+func (app *App) updateInternalRouters() error {
+	if app.Internal {
+		for _, r := range app.GetRouters() {
+			err := app.RemoveRouter(r.Name)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if len(app.GetRouters()) > 0 {
+		return nil
+	}
+	appPool, err := pool.GetPoolByName(app.ctx, app.GetPool())
+	if err != nil {
+		return err
+	}
+	routerName, err := appPool.GetDefaultRouter()
+	if err != nil {
+		return err
+	}
+	return app.AddRouter(appTypes.AppRouter{Name: routerName})
 }
 
 func validateVolumes(ctx context.Context, app *App) error {
@@ -764,7 +978,7 @@ func Delete(ctx context.Context, app *App, evt *event.Event, requestID string) e
 		logErr("Unable to release app quota", err)
 	}
 
-	err = servicemanager.TeamQuota.Inc(ctx, &authTypes.Team{Name: app.TeamOwner}, -1)
+	err = releaseTeamQuota(ctx, app.TeamOwner)
 	if err != nil {
 		logErr("Unable to release team quota", err)
 	}
@@ -792,6 +1006,12 @@ func Delete(ctx context.Context, app *App, evt *event.Event, requestID string) e
 	if err != nil {
 		logErr("Unable to destroy app in provisioner", err)
 	}
+	err = app.forEachExtraPool(func(poolApp *App, poolProv provision.Provisioner) error {
+		return poolProv.Destroy(ctx, poolApp)
+	})
+	if err != nil {
+		logErr("Unable to destroy app in extra pools", err)
+	}
 	return nil
 }
 
@@ -876,24 +1096,10 @@ func (app *App) UnbindUnit(unit *provision.Unit) error {
 // AddUnits creates n new units within the provisioner, saves new units in the
 // database and enqueues the apprc serialization.
 func (app *App) AddUnits(n uint, process, versionStr string, w io.Writer) error {
-	if n == 0 {
-		return errors.New("Cannot add zero units.")
-	}
-
-	err := app.ensureNoAutoscaler(process)
-	if err != nil {
-		return err
-	}
-
-	units, err := app.Units()
+	_, err := app.checkAddUnits(n, process)
 	if err != nil {
 		return err
 	}
-	for _, u := range units {
-		if (u.Status == provision.StatusAsleep) || (u.Status == provision.StatusStopped) {
-			return errors.New("Cannot add units to an app that has stopped or sleeping units")
-		}
-	}
 	version, err := app.getVersion(app.ctx, versionStr)
 	if err != nil {
 		return err
@@ -910,6 +1116,55 @@ func (app *App) AddUnits(n uint, process, versionStr string, w io.Writer) error
 	return nil
 }
 
+// ValidateAddUnits runs the validations of AddUnits, including the quota of
+// the app, without adding the units. It returns the number of units the
+// process would have.
+func (app *App) ValidateAddUnits(n uint, process, versionStr string) (int, error) {
+	processUnits, err := app.checkAddUnits(n, process)
+	if err != nil {
+		return 0, err
+	}
+	_, err = app.getVersion(app.ctx, versionStr)
+	if err != nil {
+		return 0, err
+	}
+	err = checkQuota(app.ctx, servicemanager.AppQuota, app, int(n))
+	if err != nil {
+		return 0, err
+	}
+	return processUnits + int(n), nil
+}
+
+// checkAddUnits checks whether n units may be added to the process, returning
+// its current number of units.
+func (app *App) checkAddUnits(n uint, process string) (int, error) {
+	if n == 0 {
+		return 0, errors.New("Cannot add zero units.")
+	}
+	err := app.ensureNoAutoscaler(process)
+	if err != nil {
+		return 0, err
+	}
+	units, err := app.Units()
+	if err != nil {
+		return 0, err
+	}
+	for _, u := range units {
+		if (u.Status == provision.StatusAsleep) || (u.Status == provision.StatusStopped) {
+			return 0, errors.New("Cannot add units to an app that has stopped or sleeping units")
+		}
+	}
+	processUnits, err := app.processUnitsCount(process)
+	if err != nil {
+		return 0, err
+	}
+	err = app.checkPlanMaxUnits(process, processUnits+int(n))
+	if err != nil {
+		return 0, err
+	}
+	return processUnits, nil
+}
+
 func (app *App) ensureNoAutoscaler(process string) error {
 	prov, err := app.getProvisioner()
 	if err != nil {
@@ -937,7 +1192,7 @@ func (app *App) ensureNoAutoscaler(process string) error {
 //     1. Remove units from the provisioner
 //     2. Update quota
 func (app *App) RemoveUnits(ctx context.Context, n uint, process, versionStr string, w io.Writer) error {
-	err := app.ensureNoAutoscaler(process)
+	_, err := app.checkRemoveUnits(n, process)
 	if err != nil {
 		return err
 	}
@@ -958,6 +1213,41 @@ func (app *App) RemoveUnits(ctx context.Context, n uint, process, versionStr str
 	return nil
 }
 
+// ValidateRemoveUnits runs the validations of RemoveUnits, without removing
+// the units. It returns the number of units the process would have.
+func (app *App) ValidateRemoveUnits(ctx context.Context, n uint, process, versionStr string) (int, error) {
+	processUnits, err := app.checkRemoveUnits(n, process)
+	if err != nil {
+		return 0, err
+	}
+	if int(n) > processUnits {
+		return 0, errors.Errorf("cannot remove %d units, the app has %d units", n, processUnits)
+	}
+	_, err = app.getVersion(ctx, versionStr)
+	if err != nil {
+		return 0, err
+	}
+	return processUnits - int(n), nil
+}
+
+// checkRemoveUnits checks whether n units may be removed from the process,
+// returning its current number of units.
+func (app *App) checkRemoveUnits(n uint, process string) (int, error) {
+	err := app.ensureNoAutoscaler(process)
+	if err != nil {
+		return 0, err
+	}
+	processUnits, err := app.processUnitsCount(process)
+	if err != nil {
+		return 0, err
+	}
+	err = app.checkPlanMinUnits(process, processUnits-int(n))
+	if err != nil {
+		return 0, err
+	}
+	return processUnits, nil
+}
+
 // SetUnitStatus changes the status of the given unit.
 func (app *App) SetUnitStatus(unitName string, status provision.Status) error {
 	units, err := app.Units()
@@ -992,6 +1282,21 @@ func (app *App) KillUnit(unitName string, force bool) error {
 	return unitProv.KillUnit(app.ctx, app, unitName, force)
 }
 
+// RestartUnit restarts a single unit of the app.
+func (app *App) RestartUnit(ctx context.Context, unitName string, w io.Writer) error {
+	prov, err := app.getProvisioner()
+	if err != nil {
+		return err
+	}
+	unitProv, ok := prov.(provision.RestartUnitProvisioner)
+	if !ok {
+		return ErrRestartUnitProvisioner
+	}
+	w = app.withLogWriter(w)
+	fmt.Fprintf(w, "---- Restarting unit %q ----\n", unitName)
+	return unitProv.RestartUnit(ctx, app, unitName, w)
+}
+
 type UpdateUnitsResult struct {
 	ID    string
 	Found bool
@@ -1253,6 +1558,12 @@ func (app *App) validatePlan() error {
 		msg := fmt.Sprintf("App plan %q is not allowed on pool %q", app.Plan.Name, pool.Name)
 		return &tsuruErrors.ValidationError{Message: msg}
 	}
+	for process, plan := range app.ProcessPlans {
+		if !planSet.Includes(plan.Name) {
+			msg := fmt.Sprintf("Plan %q of process %q is not allowed on pool %q", plan.Name, process, pool.Name)
+			return &tsuruErrors.ValidationError{Message: msg}
+		}
+	}
 	return nil
 }
 
@@ -1348,7 +1659,7 @@ func (app *App) InstanceEnvs(serviceName, instanceName string) map[string]bind.E
 // command.
 func (app *App) Run(cmd string, w io.Writer, args provision.RunArgs) error {
 	if !args.Isolated && !app.available() {
-		return errors.New("App must be available to run non-isolated commands")
+		return errRunAppUnavailable
 	}
 	logWriter := LogWriter{AppName: app.Name, Source: "app-run"}
 	logWriter.Async()
@@ -1409,6 +1720,11 @@ func (app *App) Restart(ctx context.Context, process, versionStr string, w io.Wr
 		return err
 	}
 	err = prov.Restart(ctx, app, process, version, w)
+	if err == nil {
+		err = app.forEachExtraPool(func(poolApp *App, poolProv provision.Provisioner) error {
+			return poolProv.Restart(ctx, poolApp, process, version, w)
+		})
+	}
 	if err != nil {
 		log.Errorf("[restart] error on restart the app %s - %s", app.Name, err)
 		return newErrorWithLog(err, app, "restart")
@@ -1514,6 +1830,11 @@ func (app *App) Stop(ctx context.Context, w io.Writer, process, versionStr strin
 	}
 
 	err = prov.Stop(ctx, app, process, version, w)
+	if err == nil {
+		err = app.forEachExtraPool(func(poolApp *App, poolProv provision.Provisioner) error {
+			return poolProv.Stop(ctx, poolApp, process, version, w)
+		})
+	}
 	if err != nil {
 		log.Errorf("[stop] error on stop the app %s - %s", app.Name, err)
 		return err
@@ -1650,6 +1971,35 @@ func (app *App) GetMilliCPU() int {
 	return app.Plan.CPUMilli
 }
 
+// GetProcessMemory returns the memory limit (in bytes) for the given
+// process, falling back to the app limit if the process has no plan.
+func (app *App) GetProcessMemory(process string) int64 {
+	if plan, ok := app.ProcessPlans[process]; ok {
+		return plan.Memory
+	}
+	return app.GetMemory()
+}
+
+func (app *App) GetProcessMilliCPU(process string) int {
+	if plan, ok := app.ProcessPlans[process]; ok {
+		return plan.CPUMilli
+	}
+	return app.GetMilliCPU()
+}
+
+func (app *App) GetProcessGPU(process string) (int, string) {
+	plan := app.processPlan(process)
+	return plan.GPU, plan.GPUType
+}
+
+func (app *App) GetProcessTerminationGracePeriod(process string) int {
+	return app.TerminationGracePeriods[process]
+}
+
+func (app *App) GetScheduling() *appTypes.Scheduling {
+	return app.Scheduling
+}
+
 // GetSwap returns the swap limit (in bytes) for the app.
 func (app *App) GetSwap() int64 {
 	return app.Plan.Swap
@@ -1753,6 +2103,7 @@ func interpolate(mergedEnvs map[string]bind.EnvVar, toInterpolate map[string]str
 	}
 	env := mergedEnvs[envName]
 	env.Value = mergedEnvs[varName].Value
+	env.SecretRef = mergedEnvs[varName].SecretRef
 	mergedEnvs[envName] = env
 }
 
@@ -1790,6 +2141,16 @@ func (app *App) SetEnvs(setEnvs bind.SetEnvArgs) error {
 		if err != nil {
 			return err
 		}
+		if !env.SecretRef {
+			continue
+		}
+		if env.Alias != "" {
+			return &tsuruErrors.ValidationError{Message: fmt.Sprintf("env %q can't be both an alias and a secret reference", env.Name)}
+		}
+		err = secretref.Validate(env.Value, secretref.Scope{Team: app.TeamOwner, Pool: app.Pool})
+		if err != nil {
+			return err
+		}
 	}
 
 	if setEnvs.Writer != nil && len(setEnvs.Envs) > 0 {
@@ -1813,6 +2174,10 @@ func (app *App) SetEnvs(setEnvs bind.SetEnvArgs) error {
 		app.setEnv(env)
 	}
 
+	if setEnvs.DryRun {
+		return nil
+	}
+
 	conn, err := db.Conn()
 	if err != nil {
 		return err
@@ -1875,6 +2240,11 @@ func (app *App) restartIfUnits(w io.Writer) error {
 		return err
 	}
 	err = prov.Restart(app.ctx, app, "", version, w)
+	if err == nil {
+		err = app.forEachExtraPool(func(poolApp *App, poolProv provision.Provisioner) error {
+			return poolProv.Restart(app.ctx, poolApp, "", version, w)
+		})
+	}
 	if err != nil {
 		return newErrorWithLog(err, app, "restart")
 	}
@@ -1959,6 +2329,7 @@ func (app *App) AddInstance(addArgs bind.AddInstanceArgs) error {
 	if addArgs.ShouldRestart {
 		return app.restartIfUnits(addArgs.Writer)
 	}
+	app.updateServiceEnvs()
 	return nil
 }
 
@@ -1990,9 +2361,36 @@ func (app *App) RemoveInstance(removeArgs bind.RemoveInstanceArgs) error {
 	if removeArgs.ShouldRestart {
 		return app.restartIfUnits(removeArgs.Writer)
 	}
+	app.updateServiceEnvs()
 	return nil
 }
 
+// updateServiceEnvs notifies the provisioners of the app about changed
+// service envs, when the app isn't restarted to load them. The bind or unbind
+// already succeeded at this point, so errors are only logged.
+func (app *App) updateServiceEnvs() {
+	prov, err := app.getProvisioner()
+	if err != nil {
+		log.Errorf("[update-service-envs] unable to get provisioner for app %q: %v", app.Name, err)
+		return
+	}
+	if envsProv, ok := prov.(provision.ServiceEnvsProvisioner); ok {
+		err = envsProv.UpdateServiceEnvs(app.Context(), app)
+		if err != nil {
+			log.Errorf("[update-service-envs] unable to update service envs of app %q: %v", app.Name, err)
+		}
+	}
+	err = app.forEachExtraPool(func(poolApp *App, poolProv provision.Provisioner) error {
+		if envsProv, ok := poolProv.(provision.ServiceEnvsProvisioner); ok {
+			return envsProv.UpdateServiceEnvs(app.Context(), poolApp)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Errorf("[update-service-envs] unable to update service envs of app %q in extra pools: %v", app.Name, err)
+	}
+}
+
 // LastLogs returns a list of the last `lines` log of the app, matching the
 // fields in the log instance received as an example.
 func (app *App) LastLogs(ctx context.Context, logService appTypes.AppLogService, args appTypes.ListLogArgs) ([]appTypes.Applog, error) {
@@ -2027,6 +2425,7 @@ type Filter struct {
 	Statuses    []string
 	Locked      bool
 	Tags        []string
+	TagSelector []appTypes.TagRequirement
 	Extra       map[string][]string
 }
 
@@ -2100,6 +2499,13 @@ func (f *Filter) Query() bson.M {
 	if len(tags) > 0 {
 		query["tags"] = bson.M{"$all": tags}
 	}
+	if len(f.TagSelector) > 0 {
+		and, _ := query["$and"].([]bson.M)
+		for _, req := range f.TagSelector {
+			and = append(and, tagRequirementQuery(req))
+		}
+		query["$and"] = and
+	}
 	return query
 }
 
@@ -2273,7 +2679,39 @@ func (app *App) hasMultipleVersions(ctx context.Context) (bool, error) {
 }
 
 // Swap calls the Router.Swap and updates the app.CName in the database.
+// SwapOptions controls what is exchanged between apps by SwapWithOptions.
+type SwapOptions struct {
+	// CNameOnly swaps only the cnames of the apps, keeping their router
+	// addresses.
+	CNameOnly bool
+	// CNames limits the swap to the given cnames, each one is moved to the
+	// app it doesn't belong to. It implies CNameOnly.
+	CNames []string
+	// AddressOnly swaps only the router addresses of the apps, keeping their
+	// cnames.
+	AddressOnly bool
+}
+
 func Swap(ctx context.Context, app1, app2 *App, cnameOnly bool) error {
+	return SwapWithOptions(ctx, app1, app2, SwapOptions{CNameOnly: cnameOnly})
+}
+
+// SwapWithOptions exchanges the router addresses and cnames of two apps,
+// allowing staged cutovers by swapping only part of them.
+func SwapWithOptions(ctx context.Context, app1, app2 *App, opts SwapOptions) error {
+	if len(opts.CNames) > 0 {
+		opts.CNameOnly = true
+	}
+	if opts.AddressOnly && opts.CNameOnly {
+		return ErrSwapAddressWithCNames
+	}
+	for _, cname := range opts.CNames {
+		if !cnameInSet(cname, app1.CName) && !cnameInSet(cname, app2.CName) {
+			return &tsuruErrors.ValidationError{
+				Message: fmt.Sprintf("cname %q doesn't belong to %q or %q", cname, app1.Name, app2.Name),
+			}
+		}
+	}
 	app1Multiple, err := app1.hasMultipleVersions(ctx)
 	if err != nil {
 		return err
@@ -2300,27 +2738,58 @@ func Swap(ctx context.Context, app1, app2 *App, cnameOnly bool) error {
 		return err
 	}
 
-	if cnameOnly && len(app1.CName) == 0 && len(app2.CName) == 0 {
+	if opts.CNameOnly && len(app1.CName) == 0 && len(app2.CName) == 0 {
 		return ErrSwapNoCNames
 	}
 
 	_, isRouterV2 := r.(router.RouterV2)
-	if !cnameOnly && isRouterV2 {
+	if !opts.CNameOnly && isRouterV2 {
 		return ErrSwapDeprecated
 	}
 
 	// router v2 swap with rebuild with PreserveOldCNames
 	if !isRouterV2 {
-		err = r.Swap(ctx, app1, app2, cnameOnly)
+		err = swapInRouter(ctx, r, app1, app2, opts)
 		if err != nil {
 			return err
 		}
 	}
 
+	if opts.AddressOnly {
+		return swapRebuildRoutes(ctx, app1, app2)
+	}
 	return action.NewPipeline(
 		&swapCNamesInDatabaseAction,
 		&swapReEnsureBackendsAction,
-	).Execute(ctx, app1, app2)
+	).Execute(ctx, app1, app2, opts.CNames)
+}
+
+func swapInRouter(ctx context.Context, r router.Router, app1, app2 *App, opts SwapOptions) error {
+	if len(opts.CNames) == 0 {
+		err := r.Swap(ctx, app1, app2, opts.CNameOnly)
+		if err != nil {
+			return err
+		}
+		if opts.AddressOnly {
+			// the backends were exchanged along with their cnames, they're
+			// swapped again to remain with their apps.
+			return r.Swap(ctx, app1, app2, true)
+		}
+		return nil
+	}
+	var cnames1, cnames2 []string
+	for _, cname := range opts.CNames {
+		if cnameInSet(cname, app1.CName) {
+			cnames1 = append(cnames1, cname)
+		} else {
+			cnames2 = append(cnames2, cname)
+		}
+	}
+	err := router.MoveCNames(ctx, r, cnames1, app1, app2)
+	if err != nil {
+		return err
+	}
+	return router.MoveCNames(ctx, r, cnames2, app2, app1)
 }
 
 // Start starts the app calling the provisioner.Start method and
@@ -2341,6 +2810,11 @@ func (app *App) Start(ctx context.Context, w io.Writer, process, versionStr stri
 		return err
 	}
 	err = prov.Start(ctx, app, process, version, w)
+	if err == nil {
+		err = app.forEachExtraPool(func(poolApp *App, poolProv provision.Provisioner) error {
+			return poolProv.Start(ctx, poolApp, process, version, w)
+		})
+	}
 	if err != nil {
 		log.Errorf("[start] error on start the app %s - %s", app.Name, err)
 		return newErrorWithLog(err, app, "start")
@@ -2388,6 +2862,9 @@ func (app *App) RegisterUnit(ctx context.Context, unitId string, customData map[
 }
 
 func (app *App) AddRouter(appRouter appTypes.AppRouter) error {
+	if app.Internal {
+		return ErrInternalAppRouter
+	}
 	for _, r := range app.GetRouters() {
 		if appRouter.Name == r.Name {
 			return ErrRouterAlreadyLinked
@@ -2645,6 +3122,21 @@ func (app *App) Shell(opts provision.ExecOptions) error {
 	return execProv.ExecuteCommand(app.ctx, opts)
 }
 
+// DebugUnit attaches a debug container to a running unit of the app and
+// streams a shell from it.
+func (app *App) DebugUnit(ctx context.Context, opts provision.DebugOptions) error {
+	prov, err := app.getProvisioner()
+	if err != nil {
+		return err
+	}
+	debugProv, ok := prov.(provision.DebugUnitProvisioner)
+	if !ok {
+		return ErrDebugUnitProvisioner
+	}
+	opts.App = app
+	return debugProv.DebugUnit(ctx, opts)
+}
+
 func (app *App) SetCertificate(name, certificate, key string) error {
 	err := app.validateNameForCert(name)
 	if err != nil {
@@ -2766,7 +3258,17 @@ func (app *App) RoutableAddresses(ctx context.Context) ([]appTypes.RoutableAddre
 	if err != nil {
 		return nil, err
 	}
-	return prov.RoutableAddresses(ctx, app)
+	addrs, err := prov.RoutableAddresses(ctx, app)
+	if err != nil {
+		return nil, err
+	}
+	extraAddrs, err := app.extraPoolsRoutableAddresses(ctx)
+	if err != nil {
+		return nil, err
+	}
+	addrs = append(addrs, extraAddrs...)
+	app.weightCanaryAddresses(addrs)
+	return addrs, nil
 }
 
 func (app *App) withLogWriter(w io.Writer) io.Writer {
@@ -2797,7 +3299,10 @@ func RenameTeam(ctx context.Context, oldName, newName string) error {
 		evt, err = event.NewInternal(&event.Opts{
 			Target:       event.Target{Type: event.TargetTypeApp, Value: a.Name},
 			InternalKind: "team rename",
-			Allowed:      event.Allowed(permission.PermAppReadEvents, permission.Context(permTypes.CtxApp, a.Name)),
+			Allowed: event.Allowed(permission.PermAppReadEvents,
+				permission.Context(permTypes.CtxApp, a.Name),
+				permission.Context(permTypes.CtxPool, a.Pool),
+			),
 		})
 		if err != nil {
 			return errors.Wrap(err, "unable to create event")
@@ -2944,6 +3449,10 @@ func (app *App) GetMetadata() appTypes.Metadata {
 	return app.Metadata
 }
 
+func (app *App) IsInternal() bool {
+	return app.Internal
+}
+
 func (app *App) AutoScaleInfo() ([]provision.AutoScaleSpec, error) {
 	prov, err := app.getProvisioner()
 	if err != nil {
@@ -2981,6 +3490,10 @@ func (app *App) UnitsMetrics() ([]provision.UnitMetric, error) {
 }
 
 func (app *App) AutoScale(spec provision.AutoScaleSpec) error {
+	err := app.checkAutoScalePlanLimits(spec.Process, spec.MinUnits, spec.MaxUnits)
+	if err != nil {
+		return err
+	}
 	prov, err := app.getProvisioner()
 	if err != nil {
 		return err
@@ -48,6 +48,7 @@ import (
 	routerTypes "github.com/tsuru/tsuru/types/router"
 	volumeTypes "github.com/tsuru/tsuru/types/volume"
 	check "gopkg.in/check.v1"
+	apiv1 "k8s.io/api/core/v1"
 )
 
 func (s *S) TestGetAppByName(c *check.C) {
@@ -208,6 +209,49 @@ func (s *S) TestSwapAppOnlyWithoutCnames(c *check.C) {
 	c.Assert(err, check.Equals, ErrSwapNoCNames)
 }
 
+func (s *S) TestSwapSelectedCNames(c *check.C) {
+	a := App{Name: "ritual", Platform: "ruby", TeamOwner: s.team.Name, CName: []string{"ritual.io", "www.ritual.io"}}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	app2 := &App{Name: "app2", TeamOwner: s.team.Name, CName: []string{"app2.io", "www.app2.io"}}
+	err = CreateApp(context.TODO(), app2, s.user)
+	c.Assert(err, check.IsNil)
+	err = SwapWithOptions(context.TODO(), &a, app2, SwapOptions{CNames: []string{"www.ritual.io", "www.app2.io"}})
+	c.Assert(err, check.IsNil)
+	c.Assert(a.CName, check.DeepEquals, []string{"ritual.io", "www.app2.io"})
+	c.Assert(app2.CName, check.DeepEquals, []string{"app2.io", "www.ritual.io"})
+	c.Assert(routertest.FakeRouter.HasCNameFor(a.Name, "www.app2.io"), check.Equals, true)
+	c.Assert(routertest.FakeRouter.HasCNameFor(a.Name, "ritual.io"), check.Equals, true)
+	c.Assert(routertest.FakeRouter.HasCNameFor(app2.Name, "www.ritual.io"), check.Equals, true)
+	c.Assert(routertest.FakeRouter.HasCNameFor(app2.Name, "app2.io"), check.Equals, true)
+	dbApp, err := GetByName(context.TODO(), a.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.CName, check.DeepEquals, []string{"ritual.io", "www.app2.io"})
+	err = SwapWithOptions(context.TODO(), &a, app2, SwapOptions{CNames: []string{"unknown.io"}})
+	c.Assert(err, check.ErrorMatches, `cname "unknown.io" doesn't belong to "ritual" or "app2"`)
+}
+
+func (s *S) TestSwapAddressOnly(c *check.C) {
+	a := App{Name: "ritual", Platform: "ruby", TeamOwner: s.team.Name, CName: []string{"ritual.io"}}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	app2 := &App{Name: "app2", TeamOwner: s.team.Name, CName: []string{"app2.io"}}
+	err = CreateApp(context.TODO(), app2, s.user)
+	c.Assert(err, check.IsNil)
+	err = SwapWithOptions(context.TODO(), &a, app2, SwapOptions{AddressOnly: true})
+	c.Assert(err, check.IsNil)
+	c.Assert(a.CName, check.DeepEquals, []string{"ritual.io"})
+	c.Assert(app2.CName, check.DeepEquals, []string{"app2.io"})
+	backend1, err := router.Retrieve(a.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(backend1, check.Equals, app2.Name)
+	dbApp, err := GetByName(context.TODO(), a.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.CName, check.DeepEquals, []string{"ritual.io"})
+	err = SwapWithOptions(context.TODO(), &a, app2, SwapOptions{AddressOnly: true, CNameOnly: true})
+	c.Assert(err, check.Equals, ErrSwapAddressWithCNames)
+}
+
 func (s *S) TestDeleteSwappedAppOnlyCname(c *check.C) {
 	a := App{
 		Name:      "ritual",
@@ -646,6 +690,68 @@ func (s *S) TestCreteAppTeamQuotaExceeded(c *check.C) {
 	c.Assert(qe.Requested, check.Equals, uint(1))
 }
 
+func (s *S) TestValidateNewApp(c *check.C) {
+	a := App{Name: "america", Platform: "python", TeamOwner: s.team.Name}
+	s.mockService.TeamQuota.OnInc = func(item quota.QuotaItem, delta int) error {
+		c.Fatal("team quota must not be changed")
+		return nil
+	}
+	s.mockService.UserQuota.OnInc = func(item quota.QuotaItem, delta int) error {
+		c.Fatal("user quota must not be changed")
+		return nil
+	}
+	err := ValidateNewApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	c.Assert(a.Pool, check.Equals, s.Pool)
+	c.Assert(a.Plan.Name, check.Equals, s.defaultPlan.Name)
+	_, err = GetByName(context.TODO(), a.Name)
+	c.Assert(err, check.Equals, appTypes.ErrAppNotFound)
+}
+
+func (s *S) TestValidateNewAppAlreadyExists(c *check.C) {
+	a := App{Name: "america", Platform: "python", TeamOwner: s.team.Name}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	err = ValidateNewApp(context.TODO(), &App{Name: "america", Platform: "python", TeamOwner: s.team.Name}, s.user)
+	e, ok := err.(*appTypes.AppCreationError)
+	c.Assert(ok, check.Equals, true)
+	c.Assert(e.Err, check.Equals, ErrAppAlreadyExists)
+}
+
+func (s *S) TestValidateNewAppBeingCreated(c *check.C) {
+	evt, err := event.New(&event.Opts{
+		Target:   event.Target{Type: event.TargetTypeApp, Value: "america"},
+		Kind:     permission.PermAppCreate,
+		RawOwner: event.Owner{Type: event.OwnerTypeUser, Name: s.user.Email},
+		Allowed:  event.Allowed(permission.PermApp),
+	})
+	c.Assert(err, check.IsNil)
+	a := App{Name: "america", Platform: "python", TeamOwner: s.team.Name}
+	err = ValidateNewApp(context.TODO(), &a, s.user)
+	e, ok := err.(*appTypes.AppCreationError)
+	c.Assert(ok, check.Equals, true)
+	c.Assert(e.Err, check.Equals, ErrAppAlreadyExists)
+	err = evt.Done(nil)
+	c.Assert(err, check.IsNil)
+	err = ValidateNewApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+}
+
+func (s *S) TestValidateNewAppTeamQuotaExceeded(c *check.C) {
+	a := App{Name: "america", Platform: "python", TeamOwner: s.team.Name}
+	s.mockService.TeamQuota.OnGet = func(item quota.QuotaItem) (*quota.Quota, error) {
+		c.Assert(item.GetName(), check.Equals, s.team.Name)
+		return &quota.Quota{InUse: 10, Limit: 10}, nil
+	}
+	err := ValidateNewApp(context.TODO(), &a, s.user)
+	e, ok := err.(*appTypes.AppCreationError)
+	c.Assert(ok, check.Equals, true)
+	qe, ok := e.Err.(*quota.QuotaExceededError)
+	c.Assert(ok, check.Equals, true)
+	c.Assert(qe.Available, check.Equals, uint(0))
+	c.Assert(qe.Requested, check.Equals, uint(1))
+}
+
 func (s *S) TestCreateAppTeamOwner(c *check.C) {
 	app := App{Name: "america", Platform: "python", TeamOwner: "tsuruteam"}
 	err := CreateApp(context.TODO(), &app, s.user)
@@ -868,6 +974,52 @@ func (s *S) TestAddUnits(c *check.C) {
 	}
 }
 
+func (s *S) TestAddUnitsAbovePlanMaxUnits(c *check.C) {
+	s.plan = appTypes.Plan{Name: "limited", Memory: 4194304, MaxUnits: 3}
+	a := App{Name: "warpaint", Platform: "python", TeamOwner: s.team.Name, Plan: appTypes.Plan{Name: "limited"}}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	newSuccessfulAppVersion(c, &a)
+	err = a.AddUnits(2, "web", "", nil)
+	c.Assert(err, check.IsNil)
+	err = a.AddUnits(3, "worker", "", nil)
+	c.Assert(err, check.IsNil)
+	err = a.AddUnits(2, "web", "", nil)
+	c.Assert(err, check.FitsTypeOf, &PlanUnitsLimitError{})
+	c.Assert(err, check.ErrorMatches, `plan "limited" allows at most 3 units per process, process "web" would have 4 units`)
+	units, err := a.Units()
+	c.Assert(err, check.IsNil)
+	c.Assert(units, check.HasLen, 5)
+}
+
+func (s *S) TestRemoveUnitsBelowPlanMinUnits(c *check.C) {
+	s.plan = appTypes.Plan{Name: "limited", Memory: 4194304, MinUnits: 2}
+	a := App{Name: "warpaint", Platform: "python", TeamOwner: s.team.Name, Plan: appTypes.Plan{Name: "limited"}}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	newSuccessfulAppVersion(c, &a)
+	err = a.AddUnits(3, "web", "", nil)
+	c.Assert(err, check.IsNil)
+	err = a.RemoveUnits(context.TODO(), 1, "web", "", nil)
+	c.Assert(err, check.IsNil)
+	err = a.RemoveUnits(context.TODO(), 1, "web", "", nil)
+	c.Assert(err, check.ErrorMatches, `plan "limited" allows at least 2 units per process, process "web" would have 1 units`)
+	units, err := a.Units()
+	c.Assert(err, check.IsNil)
+	c.Assert(units, check.HasLen, 2)
+}
+
+func (s *S) TestAutoScaleOutsidePlanLimits(c *check.C) {
+	s.plan = appTypes.Plan{Name: "limited", Memory: 4194304, MinUnits: 2, MaxUnits: 10}
+	a := App{Name: "warpaint", Platform: "python", TeamOwner: s.team.Name, Plan: appTypes.Plan{Name: "limited"}}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	err = a.AutoScale(provision.AutoScaleSpec{Process: "web", MinUnits: 2, MaxUnits: 200})
+	c.Assert(err, check.ErrorMatches, `plan "limited" allows at most 10 units per process, process "web" would have 200 units`)
+	err = a.AutoScale(provision.AutoScaleSpec{Process: "web", MinUnits: 1, MaxUnits: 10})
+	c.Assert(err, check.ErrorMatches, `plan "limited" allows at least 2 units per process, process "web" would have 1 units`)
+}
+
 func (s *S) TestAddUnitsInStoppedApp(c *check.C) {
 	a := App{
 		Name: "sejuani", Platform: "python",
@@ -979,6 +1131,56 @@ func (s *S) TestAddUnitsQuotaExceeded(c *check.C) {
 	c.Assert(units, check.HasLen, 0)
 }
 
+func (s *S) TestValidateAddUnits(c *check.C) {
+	a := App{Name: "warpaint", Platform: "python", TeamOwner: s.team.Name}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	newSuccessfulAppVersion(c, &a)
+	err = a.AddUnits(2, "web", "", nil)
+	c.Assert(err, check.IsNil)
+	s.mockService.AppQuota.OnInc = func(item quota.QuotaItem, quantity int) error {
+		c.Fatal("app quota must not be changed")
+		return nil
+	}
+	units, err := a.ValidateAddUnits(3, "web", "")
+	c.Assert(err, check.IsNil)
+	c.Assert(units, check.Equals, 5)
+	c.Assert(s.provisioner.GetUnits(&a), check.HasLen, 2)
+	_, err = a.ValidateAddUnits(0, "web", "")
+	c.Assert(err, check.ErrorMatches, "Cannot add zero units.")
+}
+
+func (s *S) TestValidateAddUnitsQuotaExceeded(c *check.C) {
+	a := App{Name: "warpaint", Platform: "python", TeamOwner: s.team.Name}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	newSuccessfulAppVersion(c, &a)
+	s.mockService.AppQuota.OnGet = func(item quota.QuotaItem) (*quota.Quota, error) {
+		c.Assert(item.GetName(), check.Equals, a.Name)
+		return &quota.Quota{InUse: 6, Limit: 7}, nil
+	}
+	_, err = a.ValidateAddUnits(2, "web", "")
+	e, ok := pkgErrors.Cause(err).(*quota.QuotaExceededError)
+	c.Assert(ok, check.Equals, true)
+	c.Assert(e.Available, check.Equals, uint(1))
+	c.Assert(e.Requested, check.Equals, uint(2))
+}
+
+func (s *S) TestValidateRemoveUnits(c *check.C) {
+	a := App{Name: "chemistry", Platform: "python", TeamOwner: s.team.Name}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	newSuccessfulAppVersion(c, &a)
+	err = a.AddUnits(3, "web", "", nil)
+	c.Assert(err, check.IsNil)
+	units, err := a.ValidateRemoveUnits(context.TODO(), 2, "web", "")
+	c.Assert(err, check.IsNil)
+	c.Assert(units, check.Equals, 1)
+	c.Assert(s.provisioner.GetUnits(&a), check.HasLen, 3)
+	_, err = a.ValidateRemoveUnits(context.TODO(), 4, "web", "")
+	c.Assert(err, check.ErrorMatches, "cannot remove 4 units, the app has 3 units")
+}
+
 func (s *S) TestAddUnitsMultiple(c *check.C) {
 	app := App{
 		Name: "warpaint", Platform: "ruby",
@@ -1618,6 +1820,78 @@ func (s *S) TestSetEnvsValidation(c *check.C) {
 	}
 }
 
+func (s *S) TestSetEnvsDryRun(c *check.C) {
+	a := App{Name: "myapp", TeamOwner: s.team.Name}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	envs := []bind.EnvVar{{Name: "DATABASE_HOST", Value: "localhost"}}
+	err = a.SetEnvs(bind.SetEnvArgs{Envs: envs, DryRun: true})
+	c.Assert(err, check.IsNil)
+	c.Assert(a.Env["DATABASE_HOST"].Value, check.Equals, "localhost")
+	dbApp, err := GetByName(context.TODO(), a.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.Env, check.HasLen, 0)
+	err = a.SetEnvs(bind.SetEnvArgs{Envs: []bind.EnvVar{{Name: "0INVALID", Value: "x"}}, DryRun: true})
+	c.Assert(err, check.ErrorMatches, "Invalid environment variable name: '0INVALID'")
+}
+
+func (s *S) TestSetEnvsSecretReference(c *check.C) {
+	a := App{
+		Name:      "myapp",
+		TeamOwner: s.team.Name,
+	}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	value := "vault:secret/" + s.team.Name + "/db#password"
+	envs := []bind.EnvVar{{Name: "DB_PASS", Value: value, SecretRef: true}}
+	config.Set("secret-managers:vault:path-prefix", "secret/{team}")
+	defer config.Unset("secret-managers")
+	err = a.SetEnvs(bind.SetEnvArgs{Envs: envs})
+	c.Assert(err, check.ErrorMatches, `invalid secret reference ".*": vault secret manager is not configured`)
+	config.Set("secret-managers:vault:address", "http://vault:8200")
+	config.Set("secret-managers:vault:token", "t0k3n")
+	err = a.SetEnvs(bind.SetEnvArgs{Envs: envs})
+	c.Assert(err, check.IsNil)
+	dbApp, err := GetByName(context.TODO(), a.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.Env["DB_PASS"].Value, check.Equals, value)
+	c.Assert(dbApp.Env["DB_PASS"].SecretRef, check.Equals, true)
+}
+
+func (s *S) TestSetEnvsSecretReferenceOutsidePrefix(c *check.C) {
+	a := App{
+		Name:      "myapp",
+		TeamOwner: s.team.Name,
+	}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	config.Set("secret-managers:vault:address", "http://vault:8200")
+	config.Set("secret-managers:vault:token", "t0k3n")
+	config.Set("secret-managers:vault:path-prefix", "secret/{team}")
+	defer config.Unset("secret-managers")
+	envs := []bind.EnvVar{{Name: "DB_PASS", Value: "vault:secret/otherteam/db#password", SecretRef: true}}
+	err = a.SetEnvs(bind.SetEnvArgs{Envs: envs})
+	c.Assert(err, check.ErrorMatches, `invalid secret reference "vault:secret/otherteam/db#password": path is not under the allowed prefix "secret/`+s.team.Name+`"`)
+	dbApp, err := GetByName(context.TODO(), a.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.Env["DB_PASS"].Value, check.Equals, "")
+}
+
+func (s *S) TestSetEnvsValueLookingLikeSecretReference(c *check.C) {
+	a := App{
+		Name:      "myapp",
+		TeamOwner: s.team.Name,
+	}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	envs := []bind.EnvVar{{Name: "URL", Value: "vault:secret/db#password"}}
+	err = a.SetEnvs(bind.SetEnvArgs{Envs: envs})
+	c.Assert(err, check.IsNil)
+	dbApp, err := GetByName(context.TODO(), a.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.Env["URL"].SecretRef, check.Equals, false)
+}
+
 func (s *S) TestUnsetEnvKeepServiceVariables(c *check.C) {
 	a := App{
 		Name: "myapp",
@@ -2219,6 +2493,24 @@ func (s *S) TestAddInstanceWithUnitsNoRestart(c *check.C) {
 		Public: false,
 	})
 	c.Assert(s.provisioner.Restarts(a, ""), check.Equals, 0)
+	c.Assert(s.provisioner.ServiceEnvsUpdates(a), check.Equals, 1)
+}
+
+func (s *S) TestAddInstanceNoRestartIgnoresServiceEnvsUpdateError(c *check.C) {
+	a := &App{Name: "dark", Quota: quota.Quota{Limit: 10}, TeamOwner: s.team.Name}
+	err := CreateApp(context.TODO(), a, s.user)
+	c.Assert(err, check.IsNil)
+	s.provisioner.PrepareFailure("UpdateServiceEnvs", stderrors.New("unable to update network policy"))
+	err = a.AddInstance(bind.AddInstanceArgs{
+		Envs: []bind.ServiceEnvVar{
+			{EnvVar: bind.EnvVar{Name: "DATABASE_HOST", Value: "localhost"}, InstanceName: "myinstance", ServiceName: "myservice"},
+		},
+		ShouldRestart: false,
+	})
+	c.Assert(err, check.IsNil)
+	a, err = GetByName(context.TODO(), a.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(a.Envs()["DATABASE_HOST"].Value, check.Equals, "localhost")
 }
 
 func (s *S) TestAddInstanceMultipleServices(c *check.C) {
@@ -2535,6 +2827,7 @@ func (s *S) TestRemoveInstanceWithUnitsNoRestart(c *check.C) {
 	c.Assert(serviceEnvVal, check.DeepEquals, map[string]interface{}{})
 	c.Assert(allEnvs["DATABASE_NAME"], check.DeepEquals, bind.EnvVar{})
 	c.Assert(s.provisioner.Restarts(a, ""), check.Equals, 0)
+	c.Assert(s.provisioner.ServiceEnvsUpdates(a), check.Equals, 2)
 }
 
 func (s *S) TestIsValid(c *check.C) {
@@ -2621,6 +2914,41 @@ func (s *S) TestRestart(c *check.C) {
 	c.Assert(restarts, check.Equals, 1)
 }
 
+func (s *S) TestRestartUnit(c *check.C) {
+	a := App{Name: "someapp", Platform: "django", TeamOwner: s.team.Name}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	s.provisioner.AddUnits(context.TODO(), &a, 2, "web", nil, nil)
+	units := s.provisioner.GetUnits(&a)
+	var b bytes.Buffer
+	err = a.RestartUnit(context.TODO(), units[1].ID, &b)
+	c.Assert(err, check.IsNil)
+	c.Assert(b.String(), check.Matches, `(?s).*---- Restarting unit "`+units[1].ID+`" ----.*`)
+	c.Assert(s.provisioner.UnitRestarts(&a, units[0].ID), check.Equals, 0)
+	c.Assert(s.provisioner.UnitRestarts(&a, units[1].ID), check.Equals, 1)
+	err = a.RestartUnit(context.TODO(), "unknown", &b)
+	c.Assert(err, check.DeepEquals, &provision.UnitNotFoundError{ID: "unknown"})
+}
+
+func (s *S) TestDebugUnit(c *check.C) {
+	a := App{Name: "someapp", Platform: "django", TeamOwner: s.team.Name}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	s.provisioner.AddUnits(context.TODO(), &a, 2, "web", nil, nil)
+	units := s.provisioner.GetUnits(&a)
+	var b bytes.Buffer
+	err = a.DebugUnit(context.TODO(), provision.DebugOptions{Unit: units[1].ID, Image: "busybox", Stdout: &b})
+	c.Assert(err, check.IsNil)
+	c.Assert(b.String(), check.Equals, "debugging unit "+units[1].ID)
+	c.Assert(s.provisioner.Debugs(units[0].ID), check.HasLen, 0)
+	debugs := s.provisioner.Debugs(units[1].ID)
+	c.Assert(debugs, check.HasLen, 1)
+	c.Assert(debugs[0].App.GetName(), check.Equals, a.Name)
+	c.Assert(debugs[0].Image, check.Equals, "busybox")
+	err = a.DebugUnit(context.TODO(), provision.DebugOptions{Unit: "unknown"})
+	c.Assert(err, check.DeepEquals, &provision.UnitNotFoundError{ID: "unknown"})
+}
+
 func (s *S) TestStop(c *check.C) {
 	a := App{Name: "app", TeamOwner: s.team.Name}
 	err := CreateApp(context.TODO(), &a, s.user)
@@ -3050,7 +3378,7 @@ func (s *S) TestAppMarshalJSONWithAutoscaleProv(c *check.C) {
 			map[string]interface{}{
 				"process": "p1",
 				"recommendations": []interface{}{
-					map[string]interface{}{"type": "target", "cpu": "100m", "memory": "100MiB"},
+					map[string]interface{}{"type": "target", "cpu": "100m", "memory": "100Mi"},
 				},
 			},
 		},
@@ -5063,6 +5391,94 @@ func (s *S) TestUpdateDescription(c *check.C) {
 	c.Assert(dbApp.Description, check.Equals, "bleble")
 }
 
+func (s *S) TestUpdateDryRun(c *check.C) {
+	a := App{Name: "example", Platform: "python", TeamOwner: s.team.Name, Description: "blabla"}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	updateData := App{Description: "bleble", Tags: []string{"tag1"}}
+	err = a.Update(UpdateAppArgs{UpdateData: updateData, Writer: new(bytes.Buffer), DryRun: true})
+	c.Assert(err, check.IsNil)
+	c.Assert(a.Description, check.Equals, "bleble")
+	c.Assert(a.Tags, check.DeepEquals, []string{"tag1"})
+	dbApp, err := GetByName(context.TODO(), a.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.Description, check.Equals, "blabla")
+	c.Assert(dbApp.Tags, check.HasLen, 0)
+}
+
+func (s *S) TestUpdateTerminationGracePeriods(c *check.C) {
+	app := App{Name: "example", Platform: "python", TeamOwner: s.team.Name}
+	err := CreateApp(context.TODO(), &app, s.user)
+	c.Assert(err, check.IsNil)
+	updateData := App{TerminationGracePeriods: map[string]int{"web": 120, "worker": 600}}
+	err = app.Update(UpdateAppArgs{UpdateData: updateData, Writer: new(bytes.Buffer)})
+	c.Assert(err, check.IsNil)
+	dbApp, err := GetByName(context.TODO(), app.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.TerminationGracePeriods, check.DeepEquals, map[string]int{"web": 120, "worker": 600})
+	c.Assert(dbApp.GetProcessTerminationGracePeriod("worker"), check.Equals, 600)
+	c.Assert(dbApp.GetProcessTerminationGracePeriod("other"), check.Equals, 0)
+	updateData = App{TerminationGracePeriods: map[string]int{"web": 0, "worker": 300}}
+	err = app.Update(UpdateAppArgs{UpdateData: updateData, Writer: new(bytes.Buffer)})
+	c.Assert(err, check.IsNil)
+	dbApp, err = GetByName(context.TODO(), app.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.TerminationGracePeriods, check.DeepEquals, map[string]int{"worker": 300})
+	updateData = App{TerminationGracePeriods: map[string]int{"worker": 0}}
+	err = app.Update(UpdateAppArgs{UpdateData: updateData, Writer: new(bytes.Buffer)})
+	c.Assert(err, check.IsNil)
+	dbApp, err = GetByName(context.TODO(), app.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.TerminationGracePeriods, check.IsNil)
+}
+
+func (s *S) TestUpdateTerminationGracePeriodsInvalid(c *check.C) {
+	app := App{Name: "example", Platform: "python", TeamOwner: s.team.Name}
+	err := CreateApp(context.TODO(), &app, s.user)
+	c.Assert(err, check.IsNil)
+	updateData := App{TerminationGracePeriods: map[string]int{"web": -1}}
+	err = app.Update(UpdateAppArgs{UpdateData: updateData, Writer: new(bytes.Buffer)})
+	c.Assert(err, check.Equals, ErrInvalidTerminationGracePeriod)
+	dbApp, err := GetByName(context.TODO(), app.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.TerminationGracePeriods, check.IsNil)
+}
+
+func (s *S) TestUpdateScheduling(c *check.C) {
+	app := App{Name: "example", Platform: "python", TeamOwner: s.team.Name}
+	err := CreateApp(context.TODO(), &app, s.user)
+	c.Assert(err, check.IsNil)
+	scheduling := &appTypes.Scheduling{
+		Tolerations: []apiv1.Toleration{{Key: "gpu", Operator: apiv1.TolerationOpExists, Effect: apiv1.TaintEffectNoSchedule}},
+	}
+	updateData := App{Scheduling: scheduling}
+	err = app.Update(UpdateAppArgs{UpdateData: updateData, Writer: new(bytes.Buffer)})
+	c.Assert(err, check.IsNil)
+	dbApp, err := GetByName(context.TODO(), app.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.GetScheduling(), check.DeepEquals, scheduling)
+	updateData = App{Scheduling: &appTypes.Scheduling{}}
+	err = app.Update(UpdateAppArgs{UpdateData: updateData, Writer: new(bytes.Buffer)})
+	c.Assert(err, check.IsNil)
+	dbApp, err = GetByName(context.TODO(), app.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.Scheduling, check.IsNil)
+}
+
+func (s *S) TestUpdateSchedulingInvalid(c *check.C) {
+	app := App{Name: "example", Platform: "python", TeamOwner: s.team.Name}
+	err := CreateApp(context.TODO(), &app, s.user)
+	c.Assert(err, check.IsNil)
+	updateData := App{Scheduling: &appTypes.Scheduling{
+		Tolerations: []apiv1.Toleration{{Key: "gpu", Operator: "Gt"}},
+	}}
+	err = app.Update(UpdateAppArgs{UpdateData: updateData, Writer: new(bytes.Buffer)})
+	c.Assert(err, check.ErrorMatches, `invalid toleration operator "Gt"`)
+	dbApp, err := GetByName(context.TODO(), app.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.Scheduling, check.IsNil)
+}
+
 func (s *S) TestUpdateAppPlatform(c *check.C) {
 	app := App{Name: "example", Platform: "python", TeamOwner: s.team.Name}
 	err := CreateApp(context.TODO(), &app, s.user)
@@ -5333,6 +5749,74 @@ func (s *S) TestUpdatePlanShouldRestart(c *check.C) {
 	c.Assert(s.provisioner.Restarts(dbApp, ""), check.Equals, 1)
 }
 
+func (s *S) TestUpdateProcessPlans(c *check.C) {
+	s.plan = appTypes.Plan{Name: "something", CpuShare: 100, Memory: 268435456, CPUMilli: 500}
+	a := App{Name: "my-test-app", Routers: []appTypes.AppRouter{{Name: "fake"}}, Plan: appTypes.Plan{Memory: 536870912, CpuShare: 50}, TeamOwner: s.team.Name}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	s.provisioner.AddUnits(context.TODO(), &a, 1, "worker", newSuccessfulAppVersion(c, &a), nil)
+	updateData := App{Name: "my-test-app", ProcessPlans: map[string]appTypes.Plan{"worker": {Name: "something"}}}
+	err = a.Update(UpdateAppArgs{UpdateData: updateData, Writer: new(bytes.Buffer), ShouldRestart: true})
+	c.Assert(err, check.IsNil)
+	dbApp, err := GetByName(context.TODO(), a.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.ProcessPlans, check.DeepEquals, map[string]appTypes.Plan{"worker": s.plan})
+	c.Assert(dbApp.GetProcessMemory("worker"), check.Equals, int64(268435456))
+	c.Assert(dbApp.GetProcessMilliCPU("worker"), check.Equals, 500)
+	c.Assert(dbApp.GetProcessMemory("web"), check.Equals, int64(536870912))
+	c.Assert(s.provisioner.Restarts(dbApp, ""), check.Equals, 1)
+	updateData = App{Name: "my-test-app", ProcessPlans: map[string]appTypes.Plan{"worker": {}}}
+	err = dbApp.Update(UpdateAppArgs{UpdateData: updateData, Writer: new(bytes.Buffer), ShouldRestart: true})
+	c.Assert(err, check.IsNil)
+	dbApp, err = GetByName(context.TODO(), a.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.ProcessPlans, check.IsNil)
+	c.Assert(s.provisioner.Restarts(dbApp, ""), check.Equals, 2)
+}
+
+func (s *S) TestUpdateMetadataShouldRestart(c *check.C) {
+	a := App{Name: "my-test-app", Routers: []appTypes.AppRouter{{Name: "fake"}}, TeamOwner: s.team.Name, Metadata: appTypes.Metadata{
+		Labels: []appTypes.MetadataItem{{Name: "team", Value: "payments"}},
+	}}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	s.provisioner.AddUnits(context.TODO(), &a, 1, "web", newSuccessfulAppVersion(c, &a), nil)
+	updateData := App{Name: "my-test-app", Metadata: appTypes.Metadata{
+		Labels: []appTypes.MetadataItem{{Name: "team", Value: "payments"}},
+	}}
+	err = a.Update(UpdateAppArgs{UpdateData: updateData, Writer: new(bytes.Buffer), ShouldRestart: true})
+	c.Assert(err, check.IsNil)
+	c.Assert(s.provisioner.Restarts(&a, ""), check.Equals, 0)
+	updateData = App{Name: "my-test-app", Metadata: appTypes.Metadata{
+		Labels:      []appTypes.MetadataItem{{Name: "team", Delete: true}},
+		Annotations: []appTypes.MetadataItem{{Name: "cost-center", Value: "42"}},
+	}}
+	err = a.Update(UpdateAppArgs{UpdateData: updateData, Writer: new(bytes.Buffer), ShouldRestart: true})
+	c.Assert(err, check.IsNil)
+	dbApp, err := GetByName(context.TODO(), a.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.Metadata.Labels, check.HasLen, 0)
+	c.Assert(dbApp.Metadata.Annotations, check.DeepEquals, []appTypes.MetadataItem{{Name: "cost-center", Value: "42"}})
+	c.Assert(s.provisioner.Restarts(&a, ""), check.Equals, 1)
+}
+
+func (s *S) TestUpdateProcessPlansWithConstraint(c *check.C) {
+	s.plan = appTypes.Plan{Name: "something", CpuShare: 100, Memory: 268435456}
+	err := pool.SetPoolConstraint(&pool.PoolConstraint{
+		PoolExpr:  "pool1",
+		Field:     pool.ConstraintTypePlan,
+		Values:    []string{s.plan.Name},
+		Blacklist: true,
+	})
+	c.Assert(err, check.IsNil)
+	a := App{Name: "my-test-app", Routers: []appTypes.AppRouter{{Name: "fake"}}, Plan: appTypes.Plan{Memory: 536870912, CpuShare: 50}, TeamOwner: s.team.Name}
+	err = CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	updateData := App{Name: "my-test-app", ProcessPlans: map[string]appTypes.Plan{"worker": {Name: "something"}}}
+	err = a.Update(UpdateAppArgs{UpdateData: updateData, Writer: new(bytes.Buffer)})
+	c.Assert(err, check.ErrorMatches, `Plan "something" of process "worker" is not allowed on pool "pool1"`)
+}
+
 func (s *S) TestUpdatePlanWithConstraint(c *check.C) {
 	s.plan = appTypes.Plan{Name: "something", CpuShare: 100, Memory: 268435456}
 	err := pool.SetPoolConstraint(&pool.PoolConstraint{
@@ -17,6 +17,9 @@ type EnvVar struct {
 	Alias     string `json:"alias"`
 	Public    bool   `json:"public"`
 	ManagedBy string `json:"managedBy,omitempty"`
+	// SecretRef marks the value as a reference to a secret stored in an
+	// external secret manager, see the secretref package.
+	SecretRef bool `json:"secretRef,omitempty" bson:",omitempty"`
 }
 
 type ServiceEnvVar struct {
@@ -60,6 +63,8 @@ type SetEnvArgs struct {
 	ManagedBy     string
 	PruneUnused   bool
 	ShouldRestart bool
+	// DryRun validates the variables and changes them only in memory.
+	DryRun bool
 }
 
 type UnsetEnvArgs struct {
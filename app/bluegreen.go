@@ -0,0 +1,29 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import "github.com/pkg/errors"
+
+// BlueGreenSwitch is how traffic is switched to the new version in a
+// blue-green deploy. The new version is deployed alongside the current one
+// with no traffic and only receives it after passing the healthchecks run by
+// the provisioner during the deploy.
+type BlueGreenSwitch string
+
+const (
+	// BlueGreenManual keeps the new version with no traffic until it is
+	// promoted, or aborted, like a canary deploy.
+	BlueGreenManual = BlueGreenSwitch("manual")
+	// BlueGreenAuto switches all traffic to the new version as soon as the
+	// deploy succeeds.
+	BlueGreenAuto = BlueGreenSwitch("auto")
+)
+
+var ErrInvalidBlueGreenSwitch = errors.New("blue-green switch must be one of: manual, auto")
+
+// Valid reports whether s is a known switch mode.
+func (s BlueGreenSwitch) Valid() bool {
+	return s == BlueGreenManual || s == BlueGreenAuto
+}
@@ -0,0 +1,70 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"bytes"
+	"context"
+
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestDeployBlueGreenInvalidSwitch(c *check.C) {
+	a, evt := s.createCanaryApp(c, nil)
+	_, err := Deploy(context.TODO(), DeployOptions{
+		App:          a,
+		Image:        "myimage",
+		OutputStream: &bytes.Buffer{},
+		Event:        evt,
+		BlueGreen:    BlueGreenSwitch("later"),
+	})
+	c.Assert(err, check.Equals, ErrInvalidBlueGreenSwitch)
+}
+
+func (s *S) TestDeployBlueGreenConflictingFlags(c *check.C) {
+	a, evt := s.createCanaryApp(c, nil)
+	_, err := Deploy(context.TODO(), DeployOptions{
+		App:          a,
+		Image:        "myimage",
+		OutputStream: &bytes.Buffer{},
+		Event:        evt,
+		BlueGreen:    BlueGreenAuto,
+		CanaryWeight: 10,
+	})
+	c.Assert(err, check.ErrorMatches, "conflicting deploy flags, canary and blue-green")
+	_, err = Deploy(context.TODO(), DeployOptions{
+		App:              a,
+		Image:            "myimage",
+		OutputStream:     &bytes.Buffer{},
+		Event:            evt,
+		BlueGreen:        BlueGreenManual,
+		OverrideVersions: true,
+	})
+	c.Assert(err, check.ErrorMatches, "conflicting deploy flags, blue-green and override-old-versions")
+}
+
+func (s *S) TestDeployBlueGreenNoVersionProvisioner(c *check.C) {
+	a, evt := s.createCanaryApp(c, nil)
+	_, err := Deploy(context.TODO(), DeployOptions{
+		App:          a,
+		Image:        "myimage",
+		OutputStream: &bytes.Buffer{},
+		Event:        evt,
+		BlueGreen:    BlueGreenAuto,
+	})
+	c.Assert(err, check.Equals, ErrNoVersionProvisioner)
+}
+
+func (s *S) TestDeployBlueGreenCanaryInProgress(c *check.C) {
+	a, evt := s.createCanaryApp(c, &CanaryDeploy{Version: 2})
+	_, err := Deploy(context.TODO(), DeployOptions{
+		App:          a,
+		Image:        "myimage",
+		OutputStream: &bytes.Buffer{},
+		Event:        evt,
+		BlueGreen:    BlueGreenManual,
+	})
+	c.Assert(err, check.Equals, ErrCanaryDeployInProgress)
+}
@@ -0,0 +1,306 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/tsuru/tsuru/app/bind"
+	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/router"
+	"github.com/tsuru/tsuru/service"
+	"github.com/tsuru/tsuru/servicemanager"
+	appTypes "github.com/tsuru/tsuru/types/app"
+	"github.com/tsuru/tsuru/types/quota"
+	volumeTypes "github.com/tsuru/tsuru/types/volume"
+)
+
+// BundleVersion is the version of the format of bundles generated by Export.
+const BundleVersion = 1
+
+// Bundle is a portable representation of an app, holding what's needed to
+// create it again in another tsuru installation. Deployed versions and units
+// are not part of a bundle, the app must be deployed after being imported.
+type Bundle struct {
+	Version          int                     `json:"version"`
+	App              BundleApp               `json:"app"`
+	Envs             []bind.EnvVar           `json:"envs,omitempty"`
+	Certificates     []BundleCertificate     `json:"certificates,omitempty"`
+	ServiceInstances []BundleServiceInstance `json:"serviceInstances,omitempty"`
+	Volumes          []BundleVolume          `json:"volumes,omitempty"`
+}
+
+type BundleApp struct {
+	Name         string               `json:"name"`
+	Description  string               `json:"description,omitempty"`
+	Platform     string               `json:"platform,omitempty"`
+	Pool         string               `json:"pool"`
+	TeamOwner    string               `json:"teamOwner"`
+	Teams        []string             `json:"teams,omitempty"`
+	Tags         []string             `json:"tags,omitempty"`
+	Plan         string               `json:"plan"`
+	ProcessPlans map[string]string    `json:"processPlans,omitempty"`
+	Metadata     appTypes.Metadata    `json:"metadata"`
+	Routers      []appTypes.AppRouter `json:"routers,omitempty"`
+	CNames       []string             `json:"cnames,omitempty"`
+}
+
+// BundleCertificate is a TLS certificate of an app. Keys are never exported,
+// a certificate is only set on import when its key is filled in the bundle.
+type BundleCertificate struct {
+	CName       string `json:"cname"`
+	Certificate string `json:"certificate"`
+	Key         string `json:"key,omitempty"`
+}
+
+type BundleServiceInstance struct {
+	Service  string `json:"service"`
+	Instance string `json:"instance"`
+}
+
+type BundleVolume struct {
+	Name       string            `json:"name"`
+	Plan       string            `json:"plan"`
+	Pool       string            `json:"pool"`
+	TeamOwner  string            `json:"teamOwner"`
+	Opts       map[string]string `json:"opts,omitempty"`
+	MountPoint string            `json:"mountPoint"`
+	ReadOnly   bool              `json:"readOnly"`
+}
+
+// Export returns the bundle of the app. Values of private environment
+// variables are included.
+func (app *App) Export(ctx context.Context) (*Bundle, error) {
+	bundle := Bundle{
+		Version: BundleVersion,
+		App: BundleApp{
+			Name:        app.Name,
+			Description: app.Description,
+			Platform:    app.Platform,
+			Pool:        app.Pool,
+			TeamOwner:   app.TeamOwner,
+			Teams:       app.Teams,
+			Tags:        app.Tags,
+			Plan:        app.Plan.Name,
+			Metadata:    app.Metadata,
+			CNames:      app.CName,
+		},
+		Envs: []bind.EnvVar{},
+	}
+	if app.PlatformVersion != "" && app.PlatformVersion != "latest" {
+		bundle.App.Platform = fmt.Sprintf("%s:%s", app.Platform, app.PlatformVersion)
+	}
+	if len(app.ProcessPlans) > 0 {
+		bundle.App.ProcessPlans = make(map[string]string, len(app.ProcessPlans))
+		for process, plan := range app.ProcessPlans {
+			bundle.App.ProcessPlans[process] = plan.Name
+		}
+	}
+	for _, r := range app.GetRouters() {
+		bundle.App.Routers = append(bundle.App.Routers, appTypes.AppRouter{Name: r.Name, Opts: r.Opts})
+	}
+	for _, env := range envSnapshot(app) {
+		bundle.Envs = append(bundle.Envs, bind.EnvVar(env))
+	}
+	var err error
+	bundle.Certificates, err = app.exportCertificates()
+	if err != nil {
+		return nil, err
+	}
+	instances, err := service.GetServiceInstancesBoundToApp(app.Name)
+	if err != nil {
+		return nil, err
+	}
+	for _, si := range instances {
+		bundle.ServiceInstances = append(bundle.ServiceInstances, BundleServiceInstance{
+			Service:  si.ServiceName,
+			Instance: si.Name,
+		})
+	}
+	volumes, err := servicemanager.Volume.ListByApp(ctx, app.Name)
+	if err != nil {
+		return nil, err
+	}
+	for i := range volumes {
+		binds, err := servicemanager.Volume.BindsForApp(ctx, &volumes[i], app.Name)
+		if err != nil {
+			return nil, err
+		}
+		for _, b := range binds {
+			bundle.Volumes = append(bundle.Volumes, BundleVolume{
+				Name:       volumes[i].Name,
+				Plan:       volumes[i].Plan.Name,
+				Pool:       volumes[i].Pool,
+				TeamOwner:  volumes[i].TeamOwner,
+				Opts:       volumes[i].Opts,
+				MountPoint: b.ID.MountPoint,
+				ReadOnly:   b.ReadOnly,
+			})
+		}
+	}
+	return &bundle, nil
+}
+
+func (app *App) exportCertificates() ([]BundleCertificate, error) {
+	var certificates []BundleCertificate
+	seen := map[string]struct{}{}
+	for _, appRouter := range app.GetRouters() {
+		r, err := router.Get(app.ctx, appRouter.Name)
+		if err != nil {
+			return nil, err
+		}
+		tlsRouter, ok := r.(router.TLSRouter)
+		if !ok {
+			continue
+		}
+		for _, cname := range app.CName {
+			if _, ok := seen[cname]; ok {
+				continue
+			}
+			cert, err := tlsRouter.GetCertificate(app.ctx, app, cname)
+			if err == router.ErrCertificateNotFound || cert == "" {
+				continue
+			}
+			if err != nil {
+				return nil, errors.Wrapf(err, "error in router %q", appRouter.Name)
+			}
+			seen[cname] = struct{}{}
+			certificates = append(certificates, BundleCertificate{CName: cname, Certificate: cert})
+		}
+	}
+	sort.Slice(certificates, func(i, j int) bool {
+		return certificates[i].CName < certificates[j].CName
+	})
+	return certificates, nil
+}
+
+// ImportBundle creates a new app from a bundle generated by Export. After the
+// app is created, failures to bind service instances and volumes or to set
+// certificates and cnames are reported to w and don't interrupt the import.
+// Volumes not found are created using their definitions in the bundle.
+func ImportBundle(ctx context.Context, bundle *Bundle, user *auth.User, evt *event.Event, requestID string, w io.Writer) (*App, error) {
+	if bundle.Version != BundleVersion {
+		return nil, errors.Errorf("unsupported bundle version %d", bundle.Version)
+	}
+	a := App{
+		Name:        bundle.App.Name,
+		Description: bundle.App.Description,
+		Platform:    bundle.App.Platform,
+		Pool:        bundle.App.Pool,
+		TeamOwner:   bundle.App.TeamOwner,
+		Tags:        bundle.App.Tags,
+		Plan:        appTypes.Plan{Name: bundle.App.Plan},
+		Metadata:    bundle.App.Metadata,
+		Routers:     bundle.App.Routers,
+		Quota:       quota.UnlimitedQuota,
+	}
+	fmt.Fprintf(w, "---- Creating app %q ----\n", a.Name)
+	err := CreateApp(ctx, &a, user)
+	if err != nil {
+		return nil, err
+	}
+	if len(bundle.App.ProcessPlans) > 0 {
+		processPlans := make(map[string]appTypes.Plan, len(bundle.App.ProcessPlans))
+		for process, plan := range bundle.App.ProcessPlans {
+			processPlans[process] = appTypes.Plan{Name: plan}
+		}
+		err = a.Update(UpdateAppArgs{UpdateData: App{ProcessPlans: processPlans}, Writer: w})
+		if err != nil {
+			return &a, err
+		}
+	}
+	for _, teamName := range bundle.App.Teams {
+		if teamName == a.TeamOwner {
+			continue
+		}
+		team, errTeam := servicemanager.Team.FindByName(ctx, teamName)
+		if errTeam == nil {
+			errTeam = a.Grant(team)
+		}
+		if errTeam != nil {
+			fmt.Fprintf(w, " ---> Unable to grant access to team %q: %s\n", teamName, errTeam)
+		}
+	}
+	envs := make([]bind.EnvVar, 0, len(bundle.Envs))
+	for _, env := range bundle.Envs {
+		if _, ok := snapshotIgnoredEnvs[env.Name]; ok {
+			continue
+		}
+		envs = append(envs, env)
+	}
+	err = a.SetEnvs(bind.SetEnvArgs{Envs: envs, Writer: ioutil.Discard})
+	if err != nil {
+		return &a, err
+	}
+	if len(bundle.App.CNames) > 0 {
+		err = a.AddCName(bundle.App.CNames...)
+		if err != nil {
+			fmt.Fprintf(w, " ---> Unable to add cnames: %s\n", err)
+		}
+	}
+	for _, cert := range bundle.Certificates {
+		if cert.Key == "" {
+			fmt.Fprintf(w, " ---> Certificate for %q has no key, it must be set again\n", cert.CName)
+			continue
+		}
+		err = a.SetCertificate(cert.CName, cert.Certificate, cert.Key)
+		if err != nil {
+			fmt.Fprintf(w, " ---> Unable to set certificate for %q: %s\n", cert.CName, err)
+		}
+	}
+	for _, bundleSI := range bundle.ServiceInstances {
+		err = importServiceInstance(ctx, &a, bundleSI, evt, requestID, w)
+		if err != nil {
+			fmt.Fprintf(w, " ---> Unable to bind service instance %q of service %q: %s\n", bundleSI.Instance, bundleSI.Service, err)
+		}
+	}
+	for _, bundleVolume := range bundle.Volumes {
+		err = importVolume(ctx, &a, bundleVolume, w)
+		if err != nil {
+			fmt.Fprintf(w, " ---> Unable to bind volume %q: %s\n", bundleVolume.Name, err)
+		}
+	}
+	return &a, nil
+}
+
+func importServiceInstance(ctx context.Context, a *App, bundleSI BundleServiceInstance, evt *event.Event, requestID string, w io.Writer) error {
+	si, err := service.GetServiceInstance(ctx, bundleSI.Service, bundleSI.Instance)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, " ---> Binding service instance %q of service %q\n", si.Name, si.ServiceName)
+	return si.BindApp(a, nil, false, w, evt, requestID)
+}
+
+func importVolume(ctx context.Context, a *App, bundleVolume BundleVolume, w io.Writer) error {
+	v, err := servicemanager.Volume.Get(ctx, bundleVolume.Name)
+	if err == volumeTypes.ErrVolumeNotFound {
+		fmt.Fprintf(w, " ---> Creating volume %q\n", bundleVolume.Name)
+		v = &volumeTypes.Volume{
+			Name:      bundleVolume.Name,
+			Pool:      bundleVolume.Pool,
+			TeamOwner: bundleVolume.TeamOwner,
+			Plan:      volumeTypes.VolumePlan{Name: bundleVolume.Plan},
+			Opts:      bundleVolume.Opts,
+		}
+		err = servicemanager.Volume.Create(ctx, v)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, " ---> Binding volume %q at %q\n", v.Name, bundleVolume.MountPoint)
+	return servicemanager.Volume.BindApp(ctx, &volumeTypes.BindOpts{
+		Volume:     v,
+		AppName:    a.Name,
+		MountPoint: bundleVolume.MountPoint,
+		ReadOnly:   bundleVolume.ReadOnly,
+	})
+}
@@ -0,0 +1,143 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/app/bind"
+	"github.com/tsuru/tsuru/service"
+	"github.com/tsuru/tsuru/servicemanager"
+	appTypes "github.com/tsuru/tsuru/types/app"
+	volumeTypes "github.com/tsuru/tsuru/types/volume"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestExport(c *check.C) {
+	cname := "app.io"
+	cert, err := ioutil.ReadFile("testdata/certificate.crt")
+	c.Assert(err, check.IsNil)
+	key, err := ioutil.ReadFile("testdata/private.key")
+	c.Assert(err, check.IsNil)
+	a := App{
+		Name:        "my-test-app",
+		Description: "my app",
+		TeamOwner:   s.team.Name,
+		Routers:     []appTypes.AppRouter{{Name: "fake-tls"}},
+		CName:       []string{cname},
+		Tags:        []string{"tag1"},
+	}
+	err = CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	err = a.SetCertificate(cname, string(cert), string(key))
+	c.Assert(err, check.IsNil)
+	err = a.SetEnvs(bind.SetEnvArgs{Envs: []bind.EnvVar{
+		{Name: "PUBLIC", Value: "1", Public: true},
+		{Name: "PRIVATE", Value: "secret"},
+	}})
+	c.Assert(err, check.IsNil)
+	err = s.conn.ServiceInstances().Insert(service.ServiceInstance{
+		Name:        "mydb",
+		ServiceName: "mysql",
+		Apps:        []string{a.Name},
+	})
+	c.Assert(err, check.IsNil)
+	config.Set("volume-plans:nfs:fake:plugin", "nfs")
+	defer config.Unset("volume-plans")
+	v1 := volumeTypes.Volume{Name: "v1", Pool: s.Pool, TeamOwner: s.team.Name, Plan: volumeTypes.VolumePlan{Name: "nfs"}}
+	err = servicemanager.Volume.Create(context.TODO(), &v1)
+	c.Assert(err, check.IsNil)
+	err = servicemanager.Volume.BindApp(context.TODO(), &volumeTypes.BindOpts{
+		Volume:     &v1,
+		AppName:    a.Name,
+		MountPoint: "/mnt",
+		ReadOnly:   true,
+	})
+	c.Assert(err, check.IsNil)
+	bundle, err := a.Export(context.TODO())
+	c.Assert(err, check.IsNil)
+	c.Assert(bundle, check.DeepEquals, &Bundle{
+		Version: BundleVersion,
+		App: BundleApp{
+			Name:        a.Name,
+			Description: "my app",
+			Pool:        s.Pool,
+			TeamOwner:   s.team.Name,
+			Teams:       []string{s.team.Name},
+			Tags:        []string{"tag1"},
+			Plan:        s.plan.Name,
+			Routers:     []appTypes.AppRouter{{Name: "fake-tls"}},
+			CNames:      []string{cname},
+		},
+		Envs: []bind.EnvVar{
+			{Name: "PRIVATE", Value: "secret"},
+			{Name: "PUBLIC", Value: "1", Public: true},
+		},
+		Certificates: []BundleCertificate{
+			{CName: cname, Certificate: string(cert)},
+		},
+		ServiceInstances: []BundleServiceInstance{
+			{Service: "mysql", Instance: "mydb"},
+		},
+		Volumes: []BundleVolume{
+			{Name: "v1", Plan: "nfs", Pool: s.Pool, TeamOwner: s.team.Name, MountPoint: "/mnt", ReadOnly: true},
+		},
+	})
+}
+
+func (s *S) TestImportBundle(c *check.C) {
+	config.Set("volume-plans:nfs:fake:plugin", "nfs")
+	defer config.Unset("volume-plans")
+	bundle := Bundle{
+		Version: BundleVersion,
+		App: BundleApp{
+			Name:      "imported-app",
+			Pool:      s.Pool,
+			TeamOwner: s.team.Name,
+			Plan:      s.plan.Name,
+			Tags:      []string{"tag1"},
+			Metadata: appTypes.Metadata{
+				Labels: []appTypes.MetadataItem{{Name: "a", Value: "b"}},
+			},
+		},
+		Envs: []bind.EnvVar{
+			{Name: "PRIVATE", Value: "secret"},
+			{Name: "TSURU_APP_TOKEN", Value: "old-token"},
+		},
+		Certificates: []BundleCertificate{
+			{CName: "app.io", Certificate: "cert"},
+		},
+		ServiceInstances: []BundleServiceInstance{
+			{Service: "mysql", Instance: "mydb"},
+		},
+		Volumes: []BundleVolume{
+			{Name: "v1", Plan: "nfs", Pool: s.Pool, TeamOwner: s.team.Name, MountPoint: "/mnt"},
+		},
+	}
+	w := &bytes.Buffer{}
+	a, err := ImportBundle(context.TODO(), &bundle, s.user, nil, "", w)
+	c.Assert(err, check.IsNil)
+	c.Assert(w.String(), check.Matches, `(?s)---- Creating app "imported-app" ----.*Certificate for "app.io" has no key, it must be set again.*Unable to bind service instance "mydb" of service "mysql".*Creating volume "v1".*Binding volume "v1" at "/mnt".*`)
+	dbApp, err := GetByName(context.TODO(), a.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.Tags, check.DeepEquals, []string{"tag1"})
+	c.Assert(dbApp.Metadata, check.DeepEquals, bundle.App.Metadata)
+	c.Assert(dbApp.Env["PRIVATE"], check.DeepEquals, bind.EnvVar{Name: "PRIVATE", Value: "secret"})
+	c.Assert(dbApp.Env["TSURU_APP_TOKEN"].Value, check.Not(check.Equals), "old-token")
+	v, err := servicemanager.Volume.Get(context.TODO(), "v1")
+	c.Assert(err, check.IsNil)
+	binds, err := servicemanager.Volume.BindsForApp(context.TODO(), v, a.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(binds, check.HasLen, 1)
+	c.Assert(binds[0].ID.MountPoint, check.Equals, "/mnt")
+}
+
+func (s *S) TestImportBundleInvalidVersion(c *check.C) {
+	_, err := ImportBundle(context.TODO(), &Bundle{Version: 99}, s.user, nil, "", ioutil.Discard)
+	c.Assert(err, check.ErrorMatches, "unsupported bundle version 99")
+}
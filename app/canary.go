@@ -0,0 +1,237 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/globalsign/mgo/bson"
+	"github.com/pkg/errors"
+	"github.com/tsuru/tsuru/db"
+	"github.com/tsuru/tsuru/provision"
+	"github.com/tsuru/tsuru/router"
+	"github.com/tsuru/tsuru/router/rebuild"
+	"github.com/tsuru/tsuru/servicemanager"
+	appTypes "github.com/tsuru/tsuru/types/app"
+)
+
+var (
+	ErrNoCanaryDeploy         = errors.New("no canary deploy in progress")
+	ErrCanaryDeployInProgress = errors.New("canary deploy in progress, promote or abort it first")
+	ErrInvalidCanaryWeight    = errors.New("canary weight must be between 1 and 99")
+)
+
+// CanaryDeploy holds the version of an app receiving part of its traffic
+// after a canary deploy, until the deploy is promoted or aborted. Versions
+// waiting for a manual blue-green switch are canaries with no weight.
+type CanaryDeploy struct {
+	Version int `json:"version"`
+	Weight  int `json:"weight"`
+}
+
+// canaryPrefix returns the router prefix used by provisioners for the web
+// process of the given version.
+func canaryPrefix(version int) string {
+	return fmt.Sprintf("v%d.version", version)
+}
+
+func validateCanary(ctx context.Context, opts DeployOptions) error {
+	if opts.CanaryWeight == 0 && opts.BlueGreen == "" {
+		if opts.NewVersion && opts.App.Canary != nil {
+			return ErrCanaryDeployInProgress
+		}
+		return nil
+	}
+	if opts.CanaryWeight != 0 && opts.BlueGreen != "" {
+		return errors.New("conflicting deploy flags, canary and blue-green")
+	}
+	if opts.BlueGreen != "" && !opts.BlueGreen.Valid() {
+		return ErrInvalidBlueGreenSwitch
+	}
+	if opts.CanaryWeight < 0 || opts.CanaryWeight > 99 {
+		return ErrInvalidCanaryWeight
+	}
+	if opts.OverrideVersions {
+		strategy := "canary"
+		if opts.BlueGreen != "" {
+			strategy = "blue-green"
+		}
+		return errors.Errorf("conflicting deploy flags, %s and override-old-versions", strategy)
+	}
+	if opts.App.Canary != nil {
+		return ErrCanaryDeployInProgress
+	}
+	prov, err := opts.App.getProvisioner()
+	if err != nil {
+		return err
+	}
+	if _, ok := prov.(provision.VersionsProvisioner); !ok {
+		return ErrNoVersionProvisioner
+	}
+	if opts.CanaryWeight == 0 {
+		return nil
+	}
+	for _, appRouter := range opts.App.GetRouters() {
+		r, err := router.Get(ctx, appRouter.Name)
+		if err != nil {
+			return err
+		}
+		if _, ok := r.(router.RouterV2); !ok {
+			return errors.Errorf("router %q does not support canary deploys", appRouter.Name)
+		}
+	}
+	return nil
+}
+
+// updateCanary starts a canary deploy after its version is deployed, switches
+// the traffic of automatic blue-green deploys and ends the current canary
+// when a deploy overrides every other version.
+func updateCanary(ctx context.Context, opts DeployOptions, imageID string) error {
+	if opts.CanaryWeight != 0 {
+		return opts.App.startCanary(ctx, imageID, opts.CanaryWeight)
+	}
+	switch opts.BlueGreen {
+	case BlueGreenManual:
+		fmt.Fprintln(opts.Event, " ---> New version deployed with no traffic, promote it to switch the app traffic")
+		return opts.App.startCanary(ctx, imageID, 0)
+	case BlueGreenAuto:
+		version, err := servicemanager.AppVersion.VersionByImageOrVersion(ctx, opts.App, imageID)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(opts.Event, " ---> Switching traffic to version %d\n", version.Version())
+		return opts.App.routeOnlyVersion(ctx, version)
+	}
+	if opts.OverrideVersions && opts.App.Canary != nil {
+		return opts.App.clearCanary()
+	}
+	return nil
+}
+
+func (app *App) startCanary(ctx context.Context, imageID string, weight int) error {
+	version, err := servicemanager.AppVersion.VersionByImageOrVersion(ctx, app, imageID)
+	if err != nil {
+		return err
+	}
+	canary := &CanaryDeploy{Version: version.Version(), Weight: weight}
+	conn, err := db.Conn()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	err = conn.Apps().Update(
+		bson.M{"name": app.Name},
+		bson.M{"$set": bson.M{"canary": canary}},
+	)
+	if err != nil {
+		return err
+	}
+	app.Canary = canary
+	return nil
+}
+
+func (app *App) clearCanary() error {
+	conn, err := db.Conn()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	err = conn.Apps().Update(
+		bson.M{"name": app.Name},
+		bson.M{"$unset": bson.M{"canary": ""}},
+	)
+	if err != nil {
+		return err
+	}
+	app.Canary = nil
+	return nil
+}
+
+// weightCanaryAddresses sets the canary weight in the routable addresses of
+// the canary version.
+func (app *App) weightCanaryAddresses(addrs []appTypes.RoutableAddresses) {
+	if app.Canary == nil {
+		return
+	}
+	prefix := canaryPrefix(app.Canary.Version)
+	for i := range addrs {
+		if addrs[i].Prefix == prefix {
+			addrs[i].Weight = app.Canary.Weight
+		}
+	}
+}
+
+// PromoteCanary makes the canary version the only routable version of the
+// app, sending all its traffic to it. Units of the previous versions are
+// kept, without traffic, until removed.
+func (app *App) PromoteCanary(ctx context.Context, w io.Writer) error {
+	if app.Canary == nil {
+		return ErrNoCanaryDeploy
+	}
+	w = app.withLogWriter(w)
+	canaryVersion, err := app.getVersion(ctx, strconv.Itoa(app.Canary.Version))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, " ---> Promoting canary version %d\n", app.Canary.Version)
+	err = app.routeOnlyVersion(ctx, canaryVersion)
+	if err != nil {
+		return err
+	}
+	err = app.clearCanary()
+	if err != nil {
+		return err
+	}
+	rebuild.RoutesRebuildOrEnqueueWithProgress(app.Name, w)
+	return nil
+}
+
+// routeOnlyVersion makes the given version the only routable version of the
+// app. Routes must be rebuilt afterwards.
+func (app *App) routeOnlyVersion(ctx context.Context, routable appTypes.AppVersion) error {
+	versions, err := app.DeployedVersions()
+	if err != nil {
+		return err
+	}
+	err = app.SetRoutable(ctx, routable, true)
+	if err != nil {
+		return err
+	}
+	for _, v := range versions {
+		if v == routable.Version() {
+			continue
+		}
+		var version appTypes.AppVersion
+		version, err = app.getVersion(ctx, strconv.Itoa(v))
+		if err != nil {
+			return err
+		}
+		err = app.SetRoutable(ctx, version, false)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AbortCanary stops sending traffic to the canary version and stops its
+// units.
+func (app *App) AbortCanary(ctx context.Context, w io.Writer) error {
+	if app.Canary == nil {
+		return ErrNoCanaryDeploy
+	}
+	w = app.withLogWriter(w)
+	version := strconv.Itoa(app.Canary.Version)
+	fmt.Fprintf(w, " ---> Aborting canary version %s\n", version)
+	err := app.clearCanary()
+	if err != nil {
+		return err
+	}
+	rebuild.RoutesRebuildOrEnqueueWithProgress(app.Name, w)
+	return app.Stop(ctx, w, "", version)
+}
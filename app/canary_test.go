@@ -0,0 +1,144 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"bytes"
+	"context"
+	"net/url"
+
+	"github.com/globalsign/mgo/bson"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/permission"
+	appTypes "github.com/tsuru/tsuru/types/app"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) createCanaryApp(c *check.C, canary *CanaryDeploy) (*App, *event.Event) {
+	a := App{
+		Name:      "some-app",
+		Platform:  "django",
+		Teams:     []string{s.team.Name},
+		TeamOwner: s.team.Name,
+		Router:    "fake",
+	}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	if canary != nil {
+		err = s.conn.Apps().Update(bson.M{"name": a.Name}, bson.M{"$set": bson.M{"canary": canary}})
+		c.Assert(err, check.IsNil)
+		a.Canary = canary
+	}
+	evt, err := event.New(&event.Opts{
+		Target:   event.Target{Type: "app", Value: a.Name},
+		Kind:     permission.PermAppDeploy,
+		RawOwner: event.Owner{Type: event.OwnerTypeUser, Name: s.user.Email},
+		Allowed:  event.Allowed(permission.PermApp),
+	})
+	c.Assert(err, check.IsNil)
+	return &a, evt
+}
+
+func (s *S) TestDeployCanaryInvalidWeight(c *check.C) {
+	a, evt := s.createCanaryApp(c, nil)
+	for _, weight := range []int{-1, 100} {
+		_, err := Deploy(context.TODO(), DeployOptions{
+			App:          a,
+			Image:        "myimage",
+			OutputStream: &bytes.Buffer{},
+			Event:        evt,
+			CanaryWeight: weight,
+		})
+		c.Assert(err, check.Equals, ErrInvalidCanaryWeight)
+	}
+}
+
+func (s *S) TestDeployCanaryWithOverrideVersions(c *check.C) {
+	a, evt := s.createCanaryApp(c, nil)
+	_, err := Deploy(context.TODO(), DeployOptions{
+		App:              a,
+		Image:            "myimage",
+		OutputStream:     &bytes.Buffer{},
+		Event:            evt,
+		CanaryWeight:     10,
+		OverrideVersions: true,
+	})
+	c.Assert(err, check.ErrorMatches, "conflicting deploy flags, canary and override-old-versions")
+}
+
+func (s *S) TestDeployCanaryNoVersionProvisioner(c *check.C) {
+	a, evt := s.createCanaryApp(c, nil)
+	_, err := Deploy(context.TODO(), DeployOptions{
+		App:          a,
+		Image:        "myimage",
+		OutputStream: &bytes.Buffer{},
+		Event:        evt,
+		CanaryWeight: 10,
+	})
+	c.Assert(err, check.Equals, ErrNoVersionProvisioner)
+}
+
+func (s *S) TestDeployCanaryInProgress(c *check.C) {
+	a, evt := s.createCanaryApp(c, &CanaryDeploy{Version: 2, Weight: 10})
+	_, err := Deploy(context.TODO(), DeployOptions{
+		App:          a,
+		Image:        "myimage",
+		OutputStream: &bytes.Buffer{},
+		Event:        evt,
+		CanaryWeight: 20,
+	})
+	c.Assert(err, check.Equals, ErrCanaryDeployInProgress)
+	_, err = Deploy(context.TODO(), DeployOptions{
+		App:          a,
+		Image:        "myimage",
+		OutputStream: &bytes.Buffer{},
+		Event:        evt,
+		NewVersion:   true,
+	})
+	c.Assert(err, check.Equals, ErrCanaryDeployInProgress)
+}
+
+func (s *S) TestDeployOverrideVersionsEndsCanary(c *check.C) {
+	a, evt := s.createCanaryApp(c, &CanaryDeploy{Version: 2, Weight: 10})
+	_, err := Deploy(context.TODO(), DeployOptions{
+		App:              a,
+		Image:            "myimage",
+		OutputStream:     &bytes.Buffer{},
+		Event:            evt,
+		OverrideVersions: true,
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(a.Canary, check.IsNil)
+	dbApp, err := GetByName(context.TODO(), a.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.Canary, check.IsNil)
+}
+
+func (s *S) TestRoutableAddressesCanaryWeight(c *check.C) {
+	a, _ := s.createCanaryApp(c, &CanaryDeploy{Version: 2, Weight: 10})
+	addr := &url.URL{Scheme: "http", Host: "10.0.0.1:1234"}
+	s.provisioner.MockRoutableAddresses(a, []appTypes.RoutableAddresses{
+		{Addresses: []*url.URL{addr}},
+		{Prefix: "v1.version", Addresses: []*url.URL{addr}},
+		{Prefix: "v2.version", Addresses: []*url.URL{addr}},
+		{Prefix: "v2.version.web.process", Addresses: []*url.URL{addr}},
+	})
+	addrs, err := a.RoutableAddresses(context.TODO())
+	c.Assert(err, check.IsNil)
+	c.Assert(addrs, check.DeepEquals, []appTypes.RoutableAddresses{
+		{Addresses: []*url.URL{addr}},
+		{Prefix: "v1.version", Addresses: []*url.URL{addr}},
+		{Prefix: "v2.version", Addresses: []*url.URL{addr}, Weight: 10},
+		{Prefix: "v2.version.web.process", Addresses: []*url.URL{addr}},
+	})
+}
+
+func (s *S) TestFinishCanaryNotInProgress(c *check.C) {
+	a, _ := s.createCanaryApp(c, nil)
+	err := a.PromoteCanary(context.TODO(), &bytes.Buffer{})
+	c.Assert(err, check.Equals, ErrNoCanaryDeploy)
+	err = a.AbortCanary(context.TODO(), &bytes.Buffer{})
+	c.Assert(err, check.Equals, ErrNoCanaryDeploy)
+}
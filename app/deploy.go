@@ -82,7 +82,7 @@ func findValidImages(ctx context.Context, appNames []string) (set.Set, error) {
 
 // ListDeploys returns the list of deploy that match a given filter.
 func ListDeploys(ctx context.Context, filter *Filter, skip, limit int) ([]DeployData, error) {
-	var rawFilter bson.M
+	var allowedTargets []event.TargetFilter
 	if !filter.IsEmpty() {
 		appsList, err := List(ctx, filter)
 		if err != nil {
@@ -92,15 +92,15 @@ func ListDeploys(ctx context.Context, filter *Filter, skip, limit int) ([]Deploy
 		for i, a := range appsList {
 			apps[i] = a.GetName()
 		}
-		rawFilter = bson.M{"target.value": bson.M{"$in": apps}}
+		allowedTargets = []event.TargetFilter{{Type: event.TargetTypeApp, Values: apps}}
 	}
 	evts, err := event.List(&event.Filter{
-		Target:    event.Target{Type: event.TargetTypeApp},
-		Raw:       rawFilter,
-		KindNames: []string{permission.PermAppDeploy.FullName()},
-		KindType:  event.KindTypePermission,
-		Limit:     limit,
-		Skip:      skip,
+		Target:         event.Target{Type: event.TargetTypeApp},
+		AllowedTargets: allowedTargets,
+		KindNames:      []string{permission.PermAppDeploy.FullName()},
+		KindType:       event.KindTypePermission,
+		Limit:          limit,
+		Skip:           skip,
 	})
 	if err != nil {
 		return nil, err
@@ -196,6 +196,15 @@ type DeployOptions struct {
 	Build            bool
 	NewVersion       bool
 	OverrideVersions bool
+	// CanaryWeight, when set, deploys a new version receiving this
+	// percentage of the app traffic until it's promoted or aborted.
+	CanaryWeight int
+	// BlueGreen, when set, deploys a new version with no traffic and
+	// switches the app traffic to it as configured.
+	BlueGreen BlueGreenSwitch
+	// RestoreEnvs, on rollbacks, restores the app environment variables
+	// from the snapshot taken when the version was deployed.
+	RestoreEnvs bool
 }
 
 func (o *DeployOptions) GetOrigin() string {
@@ -313,6 +322,10 @@ func (e *errorWithLog) Error() string {
 }
 
 func validateVersions(ctx context.Context, opts DeployOptions) error {
+	err := validateCanary(ctx, opts)
+	if err != nil {
+		return err
+	}
 	if opts.NewVersion && opts.OverrideVersions {
 		return errors.New("conflicting deploy flags, new-version and override-old-versions")
 	}
@@ -336,6 +349,12 @@ func Deploy(ctx context.Context, opts DeployOptions) (string, error) {
 	if opts.Event == nil {
 		return "", errors.Errorf("missing event in deploy opts")
 	}
+	if opts.App.Maintenance != nil {
+		return "", ErrAppInMaintenance
+	}
+	if opts.CanaryWeight != 0 || opts.BlueGreen != "" {
+		opts.NewVersion = true
+	}
 	err := validateVersions(ctx, opts)
 	if err != nil {
 		return "", err
@@ -345,6 +364,9 @@ func Deploy(ctx context.Context, opts DeployOptions) (string, error) {
 	defer logWriter.Close()
 	opts.Event.SetLogWriter(io.MultiWriter(&tsuruIo.NoErrorWriter{Writer: opts.OutputStream}, &logWriter))
 	imageID, err := deployToProvisioner(ctx, &opts, opts.Event)
+	if err == nil {
+		err = updateCanary(ctx, opts, imageID)
+	}
 	rebuild.RoutesRebuildOrEnqueueWithProgress(opts.App.Name, opts.Event)
 	if err != nil {
 		return "", newErrorWithLog(err, opts.App, "deploy")
@@ -389,6 +411,7 @@ func deployToProvisioner(ctx context.Context, opts *DeployOptions, evt *event.Ev
 	}
 
 	var version appTypes.AppVersion
+	var envsStored bool
 	if opts.Kind == DeployRollback {
 		version, err = servicemanager.AppVersion.VersionByImageOrVersion(ctx, opts.App, opts.Image)
 		if err != nil {
@@ -400,19 +423,53 @@ func deployToProvisioner(ctx context.Context, opts *DeployOptions, evt *event.Ev
 		} else if versionInfo.Disabled {
 			return "", errors.Errorf("the selected version is disabled for rollback: %s", version.VersionInfo().DisabledReason)
 		}
+		if opts.RestoreEnvs {
+			previousEnv := opts.App.Env
+			err = opts.App.restoreEnvSnapshot(version, evt)
+			if err != nil {
+				return "", err
+			}
+			defer func() {
+				if !envsStored {
+					opts.App.Env = previousEnv
+				}
+			}()
+		}
 	} else {
 		version, err = builderDeploy(ctx, deployer, opts, evt)
 		if err != nil {
 			return "", err
 		}
 	}
-	return deployer.Deploy(ctx, provision.DeployArgs{
+	snapshot := envSnapshot(opts.App)
+	imageID, err := deployer.Deploy(ctx, provision.DeployArgs{
 		App:              opts.App,
 		Version:          version,
 		Event:            evt,
 		PreserveVersions: opts.NewVersion,
 		OverrideVersions: opts.OverrideVersions,
 	})
+	if err != nil {
+		return "", err
+	}
+	err = opts.App.deployToExtraPools(ctx, version, evt)
+	if err != nil {
+		return "", err
+	}
+	if opts.Kind == DeployRollback && opts.RestoreEnvs {
+		err = opts.App.storeEnvs()
+		if err != nil {
+			return "", err
+		}
+		envsStored = true
+	}
+	if opts.Kind != DeployRollback {
+		err = version.AddData(appTypes.AddVersionDataArgs{EnvSnapshot: snapshot})
+		if err != nil {
+			log.Errorf("[deploy] unable to store environment variables snapshot of %s: %v", version, err)
+		}
+	}
+	return imageID, nil
 }
 
 func builderDeploy(ctx context.Context, prov provision.BuilderDeploy, opts *DeployOptions, evt *event.Event) (appTypes.AppVersion, error) {
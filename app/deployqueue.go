@@ -0,0 +1,116 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/globalsign/mgo/bson"
+	"github.com/pkg/errors"
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/db"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/log"
+)
+
+const defaultDeployQueueTimeout = 30 * time.Minute
+
+var (
+	ErrDeployQueueTimeout = errors.New("timeout waiting for the previous deploys of the app to finish")
+
+	deployQueuePollInterval = time.Second
+	// deployQueueStaleAfter is how long an entry is kept in the queue without
+	// heartbeats, freeing the queue from deploys whose API instance died.
+	deployQueueStaleAfter = time.Minute
+)
+
+type deployQueueEntry struct {
+	ID        bson.ObjectId `bson:"_id"`
+	App       string
+	Heartbeat time.Time
+}
+
+// DeployQueueTimeout returns how long a queued deploy waits by default for
+// the deploys ahead of it.
+func DeployQueueTimeout() time.Duration {
+	timeout, err := config.GetDuration("deploy:queue-timeout")
+	if err != nil || timeout <= 0 {
+		return defaultDeployQueueTimeout
+	}
+	return timeout
+}
+
+// QueueDeploy waits for the deploys of the app targeted by evtOpts enqueued
+// before this one and creates the deploy event once this deploy is the first
+// in line and the app lock is released. The position in the queue is
+// reported to w whenever it changes.
+func QueueDeploy(ctx context.Context, evtOpts *event.Opts, timeout time.Duration, w io.Writer) (*event.Event, error) {
+	appName := evtOpts.Target.Value
+	if evtOpts.RetryTimeout == 0 {
+		evtOpts.RetryTimeout = deployQueuePollInterval
+	}
+	conn, err := db.Conn()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	coll := conn.DeployQueue()
+	entry := deployQueueEntry{ID: bson.NewObjectId(), App: appName, Heartbeat: time.Now().UTC()}
+	err = coll.Insert(entry)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if rmErr := coll.RemoveId(entry.ID); rmErr != nil {
+			log.Errorf("[deploy queue] unable to remove queue entry for app %q: %v", appName, rmErr)
+		}
+	}()
+	timeoutCh := time.After(timeout)
+	lastPosition := -1
+	var lastLocker string
+	for {
+		now := time.Now().UTC()
+		err = coll.UpdateId(entry.ID, bson.M{"$set": bson.M{"heartbeat": now}})
+		if err != nil {
+			return nil, err
+		}
+		position, err := coll.Find(bson.M{
+			"app":       appName,
+			"_id":       bson.M{"$lt": entry.ID},
+			"heartbeat": bson.M{"$gte": now.Add(-deployQueueStaleAfter)},
+		}).Count()
+		if err != nil {
+			return nil, err
+		}
+		if position > 0 && position != lastPosition {
+			fmt.Fprintf(w, " ---> Deploy queued, waiting for %d deploy(s) ahead\n", position)
+		}
+		lastPosition = position
+		if position == 0 {
+			evt, err := event.New(evtOpts)
+			if err == nil {
+				return evt, nil
+			}
+			lockErr, ok := err.(event.ErrEventLocked)
+			if !ok {
+				return nil, err
+			}
+			if locker := lockErr.Event.UniqueID.Hex(); locker != lastLocker {
+				fmt.Fprintf(w, " ---> Deploy queued, waiting for event %s (%s) to finish\n", locker, lockErr.Event.Kind)
+				lastLocker = locker
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-timeoutCh:
+			return nil, ErrDeployQueueTimeout
+		case <-time.After(deployQueuePollInterval):
+		}
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"github.com/globalsign/mgo/bson"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/safe"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) deployEventOpts(appName string) *event.Opts {
+	return &event.Opts{
+		Target:   event.Target{Type: event.TargetTypeApp, Value: appName},
+		Kind:     permission.PermAppDeploy,
+		RawOwner: event.Owner{Type: event.OwnerTypeUser, Name: s.user.Email},
+		Allowed:  event.Allowed(permission.PermAppReadEvents, permission.Context(permTypes.CtxApp, appName)),
+	}
+}
+
+func (s *S) TestQueueDeploy(c *check.C) {
+	var buf bytes.Buffer
+	evt, err := QueueDeploy(context.TODO(), s.deployEventOpts("myapp"), time.Minute, &buf)
+	c.Assert(err, check.IsNil)
+	c.Assert(evt.Done(nil), check.IsNil)
+	c.Assert(buf.String(), check.Equals, "")
+	count, err := s.conn.DeployQueue().Find(nil).Count()
+	c.Assert(err, check.IsNil)
+	c.Assert(count, check.Equals, 0)
+}
+
+func (s *S) TestQueueDeployWaitsLockedApp(c *check.C) {
+	defer func(interval time.Duration) { deployQueuePollInterval = interval }(deployQueuePollInterval)
+	deployQueuePollInterval = 10 * time.Millisecond
+	running, err := event.New(s.deployEventOpts("myapp"))
+	c.Assert(err, check.IsNil)
+	buf := safe.NewBuffer(nil)
+	done := make(chan error)
+	go func() {
+		evt, queueErr := QueueDeploy(context.TODO(), s.deployEventOpts("myapp"), time.Minute, buf)
+		if queueErr == nil {
+			queueErr = evt.Done(nil)
+		}
+		done <- queueErr
+	}()
+	timeout := time.After(5 * time.Second)
+	for !bytes.Contains(buf.Bytes(), []byte("waiting for event")) {
+		select {
+		case <-timeout:
+			c.Fatal("timeout waiting for queued deploy")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	c.Assert(running.Done(nil), check.IsNil)
+	select {
+	case err = <-done:
+		c.Assert(err, check.IsNil)
+	case <-time.After(5 * time.Second):
+		c.Fatal("timeout waiting for queued deploy")
+	}
+	c.Assert(buf.String(), check.Matches, `(?s).*---> Deploy queued, waiting for event `+running.UniqueID.Hex()+` \(app.deploy\) to finish.*`)
+}
+
+func (s *S) TestQueueDeployWaitsDeploysAhead(c *check.C) {
+	defer func(interval time.Duration) { deployQueuePollInterval = interval }(deployQueuePollInterval)
+	deployQueuePollInterval = 10 * time.Millisecond
+	err := s.conn.DeployQueue().Insert(deployQueueEntry{ID: bson.NewObjectId(), App: "myapp", Heartbeat: time.Now().UTC()})
+	c.Assert(err, check.IsNil)
+	err = s.conn.DeployQueue().Insert(deployQueueEntry{ID: bson.NewObjectId(), App: "otherapp", Heartbeat: time.Now().UTC()})
+	c.Assert(err, check.IsNil)
+	var buf bytes.Buffer
+	evt, err := QueueDeploy(context.TODO(), s.deployEventOpts("myapp"), 100*time.Millisecond, &buf)
+	c.Assert(err, check.Equals, ErrDeployQueueTimeout)
+	c.Assert(evt, check.IsNil)
+	c.Assert(buf.String(), check.Equals, " ---> Deploy queued, waiting for 1 deploy(s) ahead\n")
+	count, err := s.conn.DeployQueue().Find(bson.M{"app": "myapp"}).Count()
+	c.Assert(err, check.IsNil)
+	c.Assert(count, check.Equals, 1)
+}
+
+func (s *S) TestQueueDeployIgnoresStaleEntries(c *check.C) {
+	err := s.conn.DeployQueue().Insert(deployQueueEntry{
+		ID:        bson.NewObjectId(),
+		App:       "myapp",
+		Heartbeat: time.Now().UTC().Add(-2 * deployQueueStaleAfter),
+	})
+	c.Assert(err, check.IsNil)
+	var buf bytes.Buffer
+	evt, err := QueueDeploy(context.TODO(), s.deployEventOpts("myapp"), time.Minute, &buf)
+	c.Assert(err, check.IsNil)
+	c.Assert(evt.Done(nil), check.IsNil)
+	c.Assert(buf.String(), check.Equals, "")
+}
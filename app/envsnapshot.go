@@ -0,0 +1,170 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/globalsign/mgo/bson"
+	"github.com/pkg/errors"
+	"github.com/tsuru/tsuru/app/bind"
+	"github.com/tsuru/tsuru/db"
+	"github.com/tsuru/tsuru/servicemanager"
+	appTypes "github.com/tsuru/tsuru/types/app"
+)
+
+const (
+	EnvDiffAdded   = "added"
+	EnvDiffChanged = "changed"
+	EnvDiffRemoved = "removed"
+)
+
+// snapshotIgnoredEnvs are managed by tsuru itself, they're neither stored in
+// nor restored from version snapshots.
+var snapshotIgnoredEnvs = map[string]struct{}{
+	"TSURU_APPNAME":   {},
+	"TSURU_APP_TOKEN": {},
+	"TSURU_APPDIR":    {},
+	"TSURU_SERVICE":   {},
+}
+
+type ErrNoEnvSnapshot struct {
+	Version int
+}
+
+func (e ErrNoEnvSnapshot) Error() string {
+	return fmt.Sprintf("version %d has no environment variables snapshot", e.Version)
+}
+
+// EnvDiff is a change to an app environment variable required to restore the
+// snapshot of a version. Values of private variables are suppressed.
+type EnvDiff struct {
+	Name     string `json:"name"`
+	Action   string `json:"action"`
+	Current  string `json:"current,omitempty"`
+	Snapshot string `json:"snapshot,omitempty"`
+}
+
+func envSnapshot(app *App) []appTypes.VersionEnvVar {
+	snapshot := []appTypes.VersionEnvVar{}
+	for _, env := range app.Env {
+		if _, ok := snapshotIgnoredEnvs[env.Name]; ok {
+			continue
+		}
+		snapshot = append(snapshot, appTypes.VersionEnvVar(env))
+	}
+	sort.Slice(snapshot, func(i, j int) bool {
+		return snapshot[i].Name < snapshot[j].Name
+	})
+	return snapshot
+}
+
+func envDiff(app *App, version appTypes.AppVersion) ([]EnvDiff, error) {
+	snapshot := version.VersionInfo().EnvSnapshot
+	if snapshot == nil {
+		return nil, ErrNoEnvSnapshot{Version: version.Version()}
+	}
+	displayValue := func(value string, public bool) string {
+		if public {
+			return value
+		}
+		return SuppressedEnv
+	}
+	diff := []EnvDiff{}
+	inSnapshot := map[string]struct{}{}
+	for _, env := range snapshot {
+		inSnapshot[env.Name] = struct{}{}
+		current, ok := app.Env[env.Name]
+		if !ok {
+			diff = append(diff, EnvDiff{
+				Name:     env.Name,
+				Action:   EnvDiffAdded,
+				Snapshot: displayValue(env.Value, env.Public),
+			})
+			continue
+		}
+		if appTypes.VersionEnvVar(current) != env {
+			diff = append(diff, EnvDiff{
+				Name:     env.Name,
+				Action:   EnvDiffChanged,
+				Current:  displayValue(current.Value, current.Public),
+				Snapshot: displayValue(env.Value, env.Public),
+			})
+		}
+	}
+	for name, current := range app.Env {
+		if _, ok := snapshotIgnoredEnvs[name]; ok {
+			continue
+		}
+		if _, ok := inSnapshot[name]; ok {
+			continue
+		}
+		diff = append(diff, EnvDiff{
+			Name:    name,
+			Action:  EnvDiffRemoved,
+			Current: displayValue(current.Value, current.Public),
+		})
+	}
+	sort.Slice(diff, func(i, j int) bool {
+		return diff[i].Name < diff[j].Name
+	})
+	return diff, nil
+}
+
+// RollbackEnvDiff returns the changes to the app environment variables a
+// rollback to the version identified by image restoring its snapshot would
+// make.
+func (app *App) RollbackEnvDiff(ctx context.Context, image string) ([]EnvDiff, error) {
+	version, err := servicemanager.AppVersion.VersionByImageOrVersion(ctx, app, image)
+	if err != nil {
+		return nil, err
+	}
+	return envDiff(app, version)
+}
+
+// restoreEnvSnapshot replaces the app environment variables with the ones in
+// the snapshot of the version, keeping the ones managed by tsuru. Only the
+// app in memory is changed, so the deploy uses the restored envs, they're
+// stored by storeEnvs once the deploy succeeds. Units are not restarted.
+func (app *App) restoreEnvSnapshot(version appTypes.AppVersion, w io.Writer) error {
+	diff, err := envDiff(app, version)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "---- Restoring environment variables of version %d ----\n", version.Version())
+	if len(diff) == 0 {
+		fmt.Fprintln(w, " ---> No changes")
+		return nil
+	}
+	for _, d := range diff {
+		fmt.Fprintf(w, " ---> %s %s\n", d.Name, d.Action)
+	}
+	newEnv := map[string]bind.EnvVar{}
+	for name, env := range app.Env {
+		if _, ok := snapshotIgnoredEnvs[name]; ok {
+			newEnv[name] = env
+		}
+	}
+	for _, env := range version.VersionInfo().EnvSnapshot {
+		newEnv[env.Name] = bind.EnvVar(env)
+	}
+	app.Env = newEnv
+	return nil
+}
+
+// storeEnvs saves the environment variables of the app in memory to the
+// database.
+func (app *App) storeEnvs() error {
+	conn, err := db.Conn()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	err = conn.Apps().Update(bson.M{"name": app.Name}, bson.M{"$set": bson.M{"env": app.Env}})
+	return errors.WithStack(err)
+}
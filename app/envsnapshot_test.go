@@ -0,0 +1,164 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/tsuru/tsuru/app/bind"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/servicemanager"
+	appTypes "github.com/tsuru/tsuru/types/app"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) deployWithEnvs(c *check.C, a *App, envs ...bind.EnvVar) appTypes.AppVersion {
+	err := a.SetEnvs(bind.SetEnvArgs{Envs: envs})
+	c.Assert(err, check.IsNil)
+	evt, err := event.New(&event.Opts{
+		Target:   event.Target{Type: "app", Value: a.Name},
+		Kind:     permission.PermAppDeploy,
+		RawOwner: event.Owner{Type: event.OwnerTypeUser, Name: s.user.Email},
+		Allowed:  event.Allowed(permission.PermApp),
+	})
+	c.Assert(err, check.IsNil)
+	buf := strings.NewReader("my file")
+	_, err = Deploy(context.TODO(), DeployOptions{
+		App:          a,
+		File:         ioutil.NopCloser(buf),
+		FileSize:     int64(buf.Len()),
+		OutputStream: ioutil.Discard,
+		Event:        evt,
+	})
+	c.Assert(err, check.IsNil)
+	evt.Done(nil)
+	version, err := servicemanager.AppVersion.LatestSuccessfulVersion(context.TODO(), a)
+	c.Assert(err, check.IsNil)
+	return version
+}
+
+func (s *S) TestDeployStoresEnvSnapshot(c *check.C) {
+	a := App{Name: "some-app", Platform: "django", TeamOwner: s.team.Name, Router: "fake"}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	version := s.deployWithEnvs(c, &a,
+		bind.EnvVar{Name: "B", Value: "2"},
+		bind.EnvVar{Name: "A", Value: "1", Public: true},
+		bind.EnvVar{Name: "TSURU_APPDIR", Value: "/home/application/current"},
+	)
+	c.Assert(version.VersionInfo().EnvSnapshot, check.DeepEquals, []appTypes.VersionEnvVar{
+		{Name: "A", Value: "1", Public: true},
+		{Name: "B", Value: "2"},
+	})
+}
+
+func (s *S) TestRollbackEnvDiff(c *check.C) {
+	a := App{Name: "some-app", Platform: "django", TeamOwner: s.team.Name, Router: "fake"}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	version := s.deployWithEnvs(c, &a,
+		bind.EnvVar{Name: "KEPT", Value: "1", Public: true},
+		bind.EnvVar{Name: "CHANGED", Value: "old", Public: true},
+		bind.EnvVar{Name: "REMOVED", Value: "secret"},
+	)
+	err = a.UnsetEnvs(bind.UnsetEnvArgs{VariableNames: []string{"REMOVED"}})
+	c.Assert(err, check.IsNil)
+	s.deployWithEnvs(c, &a,
+		bind.EnvVar{Name: "CHANGED", Value: "new", Public: true},
+		bind.EnvVar{Name: "ADDED", Value: "x", Public: true},
+	)
+	diff, err := a.RollbackEnvDiff(context.TODO(), version.VersionInfo().DeployImage)
+	c.Assert(err, check.IsNil)
+	c.Assert(diff, check.DeepEquals, []EnvDiff{
+		{Name: "ADDED", Action: EnvDiffRemoved, Current: "x"},
+		{Name: "CHANGED", Action: EnvDiffChanged, Current: "new", Snapshot: "old"},
+		{Name: "REMOVED", Action: EnvDiffAdded, Snapshot: SuppressedEnv},
+	})
+}
+
+func (s *S) TestRollbackEnvDiffWithoutSnapshot(c *check.C) {
+	a := App{Name: "some-app", Platform: "django", TeamOwner: s.team.Name, Router: "fake"}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	version := newSuccessfulAppVersion(c, &a)
+	_, err = a.RollbackEnvDiff(context.TODO(), version.VersionInfo().DeployImage)
+	c.Assert(err, check.DeepEquals, ErrNoEnvSnapshot{Version: version.Version()})
+}
+
+func (s *S) TestRollbackRestoreEnvs(c *check.C) {
+	a := App{Name: "some-app", Platform: "django", TeamOwner: s.team.Name, Router: "fake"}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	version := s.deployWithEnvs(c, &a,
+		bind.EnvVar{Name: "A", Value: "old"},
+	)
+	s.deployWithEnvs(c, &a,
+		bind.EnvVar{Name: "A", Value: "new"},
+		bind.EnvVar{Name: "B", Value: "2"},
+	)
+	evt, err := event.New(&event.Opts{
+		Target:   event.Target{Type: "app", Value: a.Name},
+		Kind:     permission.PermAppDeploy,
+		RawOwner: event.Owner{Type: event.OwnerTypeUser, Name: s.user.Email},
+		Allowed:  event.Allowed(permission.PermApp),
+	})
+	c.Assert(err, check.IsNil)
+	token := a.Env["TSURU_APP_TOKEN"]
+	writer := &bytes.Buffer{}
+	_, err = Deploy(context.TODO(), DeployOptions{
+		App:          &a,
+		OutputStream: writer,
+		Image:        version.VersionInfo().DeployImage,
+		Rollback:     true,
+		RestoreEnvs:  true,
+		Event:        evt,
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(writer.String(), check.Matches, `(?s).*Restoring environment variables of version 1.*A changed.*B removed.*`)
+	dbApp, err := GetByName(context.TODO(), a.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.Env["A"], check.DeepEquals, bind.EnvVar{Name: "A", Value: "old"})
+	_, ok := dbApp.Env["B"]
+	c.Assert(ok, check.Equals, false)
+	c.Assert(dbApp.Env["TSURU_APP_TOKEN"], check.DeepEquals, token)
+}
+
+func (s *S) TestRollbackRestoreEnvsDeployFailure(c *check.C) {
+	a := App{Name: "some-app", Platform: "django", TeamOwner: s.team.Name, Router: "fake"}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	version := s.deployWithEnvs(c, &a,
+		bind.EnvVar{Name: "A", Value: "old"},
+	)
+	s.deployWithEnvs(c, &a,
+		bind.EnvVar{Name: "A", Value: "new"},
+	)
+	evt, err := event.New(&event.Opts{
+		Target:   event.Target{Type: "app", Value: a.Name},
+		Kind:     permission.PermAppDeploy,
+		RawOwner: event.Owner{Type: event.OwnerTypeUser, Name: s.user.Email},
+		Allowed:  event.Allowed(permission.PermApp),
+	})
+	c.Assert(err, check.IsNil)
+	s.provisioner.PrepareFailure("Deploy", errors.New("deploy error"))
+	_, err = Deploy(context.TODO(), DeployOptions{
+		App:          &a,
+		OutputStream: ioutil.Discard,
+		Image:        version.VersionInfo().DeployImage,
+		Rollback:     true,
+		RestoreEnvs:  true,
+		Event:        evt,
+	})
+	c.Assert(err, check.ErrorMatches, ".*deploy error.*")
+	c.Assert(a.Env["A"].Value, check.Equals, "new")
+	dbApp, err := GetByName(context.TODO(), a.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.Env["A"], check.DeepEquals, bind.EnvVar{Name: "A", Value: "new"})
+}
@@ -0,0 +1,92 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/permission"
+)
+
+var (
+	ErrAppAlreadyFrozen = &tsuruErrors.ValidationError{Message: "app is already frozen"}
+	ErrAppNotFrozen     = errors.New("app is not frozen")
+	ErrFreezeReason     = &tsuruErrors.ValidationError{Message: "reason is required"}
+)
+
+// freezeKinds lists the events blocked while the app is frozen.
+var freezeKinds = []string{
+	permission.PermAppDeploy.FullName(),
+	permission.PermAppUpdateDeployRollback.FullName(),
+	permission.PermAppUpdateDeployCanary.FullName(),
+	permission.PermAppUpdateEnvSet.FullName(),
+	permission.PermAppUpdateEnvUnset.FullName(),
+	permission.PermAppUpdateUnitAdd.FullName(),
+	permission.PermAppUpdateUnitRemove.FullName(),
+	permission.PermAppUpdateUnitAutoscaleAdd.FullName(),
+	permission.PermAppUpdateUnitAutoscaleRemove.FullName(),
+}
+
+func (app *App) isFreezeBlock(b *event.Block) bool {
+	return b.Target == event.Target{Type: event.TargetTypeApp, Value: app.Name} && len(b.KindNames) > 0
+}
+
+// Freeze blocks deploys, env changes and scaling of the app until it's
+// unfrozen or, when expiresAt is set, until the freeze expires. It's
+// implemented as an event block targeting the app.
+func (app *App) Freeze(reason string, expiresAt time.Time) (*event.Block, error) {
+	if reason == "" {
+		return nil, ErrFreezeReason
+	}
+	current, err := app.ActiveFreeze()
+	if err != nil {
+		return nil, err
+	}
+	if current != nil {
+		return nil, ErrAppAlreadyFrozen
+	}
+	block := &event.Block{
+		Target:    event.Target{Type: event.TargetTypeApp, Value: app.Name},
+		KindNames: freezeKinds,
+		Reason:    reason,
+		ExpiresAt: expiresAt,
+	}
+	err = event.AddBlock(block)
+	if err != nil {
+		return nil, err
+	}
+	return block, nil
+}
+
+// ActiveFreeze returns the block freezing the app, or nil when the app is not
+// frozen.
+func (app *App) ActiveFreeze() (*event.Block, error) {
+	active := true
+	blocks, err := event.ListBlocks(&active)
+	if err != nil {
+		return nil, err
+	}
+	for i := range blocks {
+		if app.isFreezeBlock(&blocks[i]) {
+			return &blocks[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// Unfreeze lifts the freeze of the app.
+func (app *App) Unfreeze() error {
+	block, err := app.ActiveFreeze()
+	if err != nil {
+		return err
+	}
+	if block == nil {
+		return ErrAppNotFrozen
+	}
+	return event.RemoveBlock(block.ID)
+}
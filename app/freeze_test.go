@@ -0,0 +1,73 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"time"
+
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/permission"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestFreeze(c *check.C) {
+	a := App{Name: "frozen-app", TeamOwner: s.team.Name}
+	expiresAt := time.Now().Add(time.Hour)
+	block, err := a.Freeze("release window", expiresAt)
+	c.Assert(err, check.IsNil)
+	c.Assert(block.Target, check.Equals, event.Target{Type: event.TargetTypeApp, Value: a.Name})
+	c.Assert(block.KindNames, check.DeepEquals, freezeKinds)
+	active, err := a.ActiveFreeze()
+	c.Assert(err, check.IsNil)
+	c.Assert(active, check.NotNil)
+	c.Assert(active.ID, check.Equals, block.ID)
+	c.Assert(active.Reason, check.Equals, "release window")
+	_, err = a.Freeze("again", time.Time{})
+	c.Assert(err, check.Equals, ErrAppAlreadyFrozen)
+	other := App{Name: "other-app"}
+	active, err = other.ActiveFreeze()
+	c.Assert(err, check.IsNil)
+	c.Assert(active, check.IsNil)
+}
+
+func (s *S) TestFreezeWithoutReason(c *check.C) {
+	a := App{Name: "frozen-app"}
+	_, err := a.Freeze("", time.Time{})
+	c.Assert(err, check.Equals, ErrFreezeReason)
+}
+
+func (s *S) TestFreezeIgnoresOtherBlocks(c *check.C) {
+	err := event.AddBlock(&event.Block{
+		Target: event.Target{Type: event.TargetTypeApp, Value: "frozen-app"},
+		Reason: "admin block",
+	})
+	c.Assert(err, check.IsNil)
+	a := App{Name: "frozen-app"}
+	active, err := a.ActiveFreeze()
+	c.Assert(err, check.IsNil)
+	c.Assert(active, check.IsNil)
+	err = a.Unfreeze()
+	c.Assert(err, check.Equals, ErrAppNotFrozen)
+}
+
+func (s *S) TestFreezeBlocksEvents(c *check.C) {
+	a := App{Name: "frozen-app", TeamOwner: s.team.Name}
+	_, err := a.Freeze("release window", time.Time{})
+	c.Assert(err, check.IsNil)
+	_, err = event.New(&event.Opts{
+		Target:   event.Target{Type: event.TargetTypeApp, Value: a.Name},
+		Kind:     permission.PermAppUpdateEnvSet,
+		RawOwner: event.Owner{Type: event.OwnerTypeUser, Name: s.user.Email},
+		Allowed:  event.Allowed(permission.PermApp),
+	})
+	c.Assert(err, check.FitsTypeOf, event.ErrEventBlocked{})
+	evt := s.newAppEvent(c, &a, permission.PermAppUpdateDescription)
+	evt.Done(nil)
+	err = a.Unfreeze()
+	c.Assert(err, check.IsNil)
+	active, err := a.ActiveFreeze()
+	c.Assert(err, check.IsNil)
+	c.Assert(active, check.IsNil)
+}
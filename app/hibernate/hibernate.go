@@ -0,0 +1,195 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hibernate
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/tsuru/tsuru/api/shutdown"
+	"github.com/tsuru/tsuru/app"
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/log"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/provision"
+	"github.com/tsuru/tsuru/provision/pool"
+	"github.com/tsuru/tsuru/router"
+	"github.com/tsuru/tsuru/servicemanager"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+)
+
+const hibernateRunInterval = time.Minute
+
+var hibernatedAppsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "tsuru",
+	Subsystem: "hibernate",
+	Name:      "apps_total",
+	Help:      "The number of times that idle apps were put to sleep by result",
+}, []string{"result"})
+
+func Initialize() error {
+	h := &hibernator{once: &sync.Once{}}
+	h.start()
+	shutdown.Register(h)
+	return nil
+}
+
+// hibernator periodically puts to sleep the idle apps of pools with
+// hibernate enabled. Their routes point to the pool activator, which starts
+// the app again on the first request.
+type hibernator struct {
+	once   *sync.Once
+	stopCh chan struct{}
+}
+
+func (h *hibernator) start() {
+	h.once.Do(func() {
+		h.stopCh = make(chan struct{})
+		go h.spin()
+	})
+}
+
+func (h *hibernator) Shutdown(ctx context.Context) error {
+	if h.stopCh == nil {
+		return nil
+	}
+	h.stopCh <- struct{}{}
+	h.stopCh = nil
+	h.once = &sync.Once{}
+	return nil
+}
+
+func (h *hibernator) spin() {
+	for {
+		err := runHibernate(context.Background())
+		if err != nil {
+			log.Errorf("[hibernate] %v", err)
+		}
+		select {
+		case <-h.stopCh:
+			return
+		case <-time.After(hibernateRunInterval):
+		}
+	}
+}
+
+func runHibernate(ctx context.Context) error {
+	pools, err := pool.ListAllPools(ctx)
+	if err != nil {
+		return err
+	}
+	multi := tsuruErrors.NewMultiError()
+	for _, p := range pools {
+		hibernateConfig, err := p.GetHibernateConfig()
+		if err != nil {
+			multi.Add(errors.Wrapf(err, "invalid hibernate config for pool %q", p.Name))
+			continue
+		}
+		if hibernateConfig == nil {
+			continue
+		}
+		apps, err := app.List(ctx, &app.Filter{Pool: p.Name})
+		if err != nil {
+			multi.Add(err)
+			continue
+		}
+		for i := range apps {
+			err = hibernateIfIdle(ctx, &apps[i], hibernateConfig)
+			if err != nil {
+				multi.Add(errors.Wrapf(err, "unable to hibernate app %q", apps[i].Name))
+			}
+		}
+	}
+	return multi.ToError()
+}
+
+func hibernateIfIdle(ctx context.Context, a *app.App, hibernateConfig *pool.HibernateConfig) error {
+	awake, err := isAwake(a)
+	if err != nil || !awake {
+		return err
+	}
+	lastActivity, err := lastActivity(ctx, a)
+	if err != nil || lastActivity.IsZero() {
+		return err
+	}
+	if time.Since(lastActivity) < hibernateConfig.IdleTimeout {
+		return nil
+	}
+	evt, err := event.NewInternal(&event.Opts{
+		Target:       event.Target{Type: event.TargetTypeApp, Value: a.Name},
+		InternalKind: "hibernate",
+		Allowed:      event.Allowed(permission.PermAppReadEvents, permission.Context(permTypes.CtxApp, a.Name)),
+	})
+	if err != nil {
+		if _, ok := err.(event.ErrEventLocked); ok {
+			return nil
+		}
+		return err
+	}
+	log.Debugf("[hibernate] app %q idle since %s, putting it to sleep", a.Name, lastActivity)
+	err = a.Sleep(ctx, evt, "", "", hibernateConfig.Activator)
+	evt.Done(err)
+	if err != nil {
+		hibernatedAppsTotal.WithLabelValues("error").Inc()
+		return err
+	}
+	hibernatedAppsTotal.WithLabelValues("success").Inc()
+	return nil
+}
+
+func isAwake(a *app.App) (bool, error) {
+	units, err := a.Units()
+	if err != nil {
+		return false, err
+	}
+	for _, u := range units {
+		if u.Status != provision.StatusAsleep && u.Status != provision.StatusStopped {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// lastActivity returns the last time the app received a request, or was
+// deployed, whichever is newer. A zero time is returned if any of the app
+// routers is unable to report activity or to put the app to sleep, as the app
+// can't be hibernated.
+func lastActivity(ctx context.Context, a *app.App) (time.Time, error) {
+	var last time.Time
+	routers := a.GetRouters()
+	if len(routers) == 0 {
+		return last, nil
+	}
+	for _, appRouter := range routers {
+		r, err := router.Get(ctx, appRouter.Name)
+		if err != nil {
+			return last, err
+		}
+		activityRouter, ok := r.(router.ActivityRouter)
+		if _, isRouterV2 := r.(router.RouterV2); !ok || isRouterV2 {
+			return time.Time{}, nil
+		}
+		routerActivity, err := activityRouter.LastActivity(ctx, a)
+		if err != nil {
+			return last, err
+		}
+		if routerActivity.After(last) {
+			last = routerActivity
+		}
+	}
+	version, err := servicemanager.AppVersion.LatestSuccessfulVersion(ctx, a)
+	if err != nil {
+		return time.Time{}, nil
+	}
+	if updatedAt := version.VersionInfo().UpdatedAt; updatedAt.After(last) {
+		last = updatedAt
+	}
+	return last, nil
+}
@@ -0,0 +1,169 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hibernate
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/app"
+	"github.com/tsuru/tsuru/app/version"
+	"github.com/tsuru/tsuru/applog"
+	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/auth/native"
+	"github.com/tsuru/tsuru/db"
+	"github.com/tsuru/tsuru/db/dbtest"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/permission/permissiontest"
+	"github.com/tsuru/tsuru/provision"
+	"github.com/tsuru/tsuru/provision/pool"
+	"github.com/tsuru/tsuru/provision/provisiontest"
+	"github.com/tsuru/tsuru/router/routertest"
+	"github.com/tsuru/tsuru/servicemanager"
+	servicemock "github.com/tsuru/tsuru/servicemanager/mock"
+	_ "github.com/tsuru/tsuru/storage/mongodb"
+	appTypes "github.com/tsuru/tsuru/types/app"
+	authTypes "github.com/tsuru/tsuru/types/auth"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+	"golang.org/x/crypto/bcrypt"
+	check "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) { check.TestingT(t) }
+
+type S struct {
+	storage     *db.Storage
+	user        *auth.User
+	team        string
+	mockService servicemock.MockService
+}
+
+var _ = check.Suite(&S{})
+
+func (s *S) SetUpSuite(c *check.C) {
+	config.Set("log:disable-syslog", true)
+	config.Set("database:url", "127.0.0.1:27017?maxPoolSize=100")
+	config.Set("database:name", "app_hibernate_tests")
+	config.Set("routers:fake:type", "fake")
+	config.Set("routers:fake-activity:type", "fake-activity")
+	config.Set("auth:hash-cost", bcrypt.MinCost)
+	var err error
+	s.storage, err = db.Conn()
+	c.Assert(err, check.IsNil)
+	provision.DefaultProvisioner = "fake"
+	app.AuthScheme = auth.ManagedScheme(native.NativeScheme{})
+}
+
+func (s *S) SetUpTest(c *check.C) {
+	provisiontest.ProvisionerInstance.Reset()
+	routertest.FakeRouter.Reset()
+	routertest.ActivityRouter.Reset()
+	s.user, _ = permissiontest.CustomUserWithPermission(c, app.AuthScheme, "majortom", permission.Permission{
+		Scheme:  permission.PermAll,
+		Context: permission.Context(permTypes.CtxGlobal, ""),
+	})
+	s.team = "myteam"
+	servicemock.SetMockService(&s.mockService)
+	plan := appTypes.Plan{
+		Name:     "default",
+		Default:  true,
+		CpuShare: 100,
+	}
+	s.mockService.Plan.OnList = func() ([]appTypes.Plan, error) {
+		return []appTypes.Plan{plan}, nil
+	}
+	s.mockService.Plan.OnDefaultPlan = func() (*appTypes.Plan, error) {
+		return &plan, nil
+	}
+	s.mockService.Team.OnList = func() ([]authTypes.Team, error) {
+		return []authTypes.Team{{Name: s.team}}, nil
+	}
+	var err error
+	servicemanager.AppVersion, err = version.AppVersionService()
+	c.Assert(err, check.IsNil)
+	servicemanager.AppLog, err = applog.AppLogService()
+	c.Assert(err, check.IsNil)
+}
+
+func (s *S) TearDownTest(c *check.C) {
+	err := dbtest.ClearAllCollections(s.storage.Apps().Database)
+	c.Assert(err, check.IsNil)
+}
+
+func (s *S) TearDownSuite(c *check.C) {
+	dbtest.ClearAllCollections(s.storage.Apps().Database)
+	s.storage.Close()
+}
+
+func (s *S) createApp(c *check.C, name, routerName string, labels map[string]string) *app.App {
+	err := pool.AddPool(context.TODO(), pool.AddPoolOptions{Name: "p1", Labels: labels})
+	c.Assert(err, check.IsNil)
+	a := &app.App{Name: name, TeamOwner: s.team, Pool: "p1", Router: routerName}
+	err = app.CreateApp(context.TODO(), a, s.user)
+	c.Assert(err, check.IsNil)
+	version, err := servicemanager.AppVersion.NewAppVersion(context.TODO(), appTypes.NewVersionArgs{App: a})
+	c.Assert(err, check.IsNil)
+	err = version.CommitBuildImage()
+	c.Assert(err, check.IsNil)
+	err = version.CommitSuccessful()
+	c.Assert(err, check.IsNil)
+	err = provisiontest.ProvisionerInstance.AddUnits(context.TODO(), a, 2, "web", version, nil)
+	c.Assert(err, check.IsNil)
+	return a
+}
+
+func (s *S) TestHibernatorStartNothingToDo(c *check.C) {
+	h := &hibernator{once: &sync.Once{}}
+	h.start()
+	err := h.Shutdown(context.Background())
+	c.Assert(err, check.IsNil)
+}
+
+func (s *S) TestRunHibernateIdleApp(c *check.C) {
+	a := s.createApp(c, "myapp", "fake-activity", map[string]string{
+		"hibernate-activator":    "http://activator:8080",
+		"hibernate-idle-timeout": "1ns",
+	})
+	routertest.ActivityRouter.Activity[a.Name] = time.Now().Add(-time.Hour)
+	err := runHibernate(context.TODO())
+	c.Assert(err, check.IsNil)
+	c.Assert(provisiontest.ProvisionerInstance.Sleeps(a, ""), check.Equals, 1)
+	c.Assert(routertest.ActivityRouter.HasRoute(a.Name, "http://activator:8080"), check.Equals, true)
+	err = runHibernate(context.TODO())
+	c.Assert(err, check.IsNil)
+	c.Assert(provisiontest.ProvisionerInstance.Sleeps(a, ""), check.Equals, 1)
+}
+
+func (s *S) TestRunHibernateActiveApp(c *check.C) {
+	a := s.createApp(c, "myapp", "fake-activity", map[string]string{
+		"hibernate-activator":    "http://activator:8080",
+		"hibernate-idle-timeout": "1h",
+	})
+	routertest.ActivityRouter.Activity[a.Name] = time.Now()
+	err := runHibernate(context.TODO())
+	c.Assert(err, check.IsNil)
+	c.Assert(provisiontest.ProvisionerInstance.Sleeps(a, ""), check.Equals, 0)
+}
+
+func (s *S) TestRunHibernatePoolWithoutHibernate(c *check.C) {
+	a := s.createApp(c, "myapp", "fake-activity", nil)
+	routertest.ActivityRouter.Activity[a.Name] = time.Now().Add(-time.Hour)
+	err := runHibernate(context.TODO())
+	c.Assert(err, check.IsNil)
+	c.Assert(provisiontest.ProvisionerInstance.Sleeps(a, ""), check.Equals, 0)
+}
+
+func (s *S) TestRunHibernateRouterWithoutActivity(c *check.C) {
+	a := s.createApp(c, "myapp", "fake", map[string]string{
+		"hibernate-activator":    "http://activator:8080",
+		"hibernate-idle-timeout": "1ns",
+	})
+	err := runHibernate(context.TODO())
+	c.Assert(err, check.IsNil)
+	c.Assert(provisiontest.ProvisionerInstance.Sleeps(a, ""), check.Equals, 0)
+}
@@ -371,11 +371,7 @@ func versionsSafeToRemove(appVersions []appTypes.AppVersionInfo) ([]appTypes.App
 	}
 
 	events, err := event.List(&event.Filter{
-		Raw: bson.M{
-			"uniqueid": bson.M{
-				"$in": uniqueIds,
-			},
-		},
+		UniqueIDs: uniqueIds,
 	})
 
 	if err != nil {
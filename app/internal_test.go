@@ -0,0 +1,68 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/tsuru/tsuru/router/routertest"
+	appTypes "github.com/tsuru/tsuru/types/app"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestCreateInternalApp(c *check.C) {
+	a := App{Name: "backend", Platform: "python", TeamOwner: s.team.Name, Internal: true}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	c.Assert(a.GetRouters(), check.HasLen, 0)
+	c.Assert(routertest.FakeRouter.HasBackend(a.Name), check.Equals, false)
+	dbApp, err := GetByName(context.TODO(), a.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.Internal, check.Equals, true)
+	c.Assert(dbApp.IsInternal(), check.Equals, true)
+	c.Assert(dbApp.GetRouters(), check.HasLen, 0)
+}
+
+func (s *S) TestCreateInternalAppWithRouter(c *check.C) {
+	a := App{Name: "backend", Platform: "python", TeamOwner: s.team.Name, Internal: true, Router: "fake"}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.Equals, ErrInternalAppRouter)
+	a = App{Name: "backend", Platform: "python", TeamOwner: s.team.Name, Internal: true, Routers: []appTypes.AppRouter{{Name: "fake"}}}
+	err = CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.Equals, ErrInternalAppRouter)
+}
+
+func (s *S) TestAddRouterToInternalApp(c *check.C) {
+	a := App{Name: "backend", Platform: "python", TeamOwner: s.team.Name, Internal: true}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	err = a.AddRouter(appTypes.AppRouter{Name: "fake"})
+	c.Assert(err, check.Equals, ErrInternalAppRouter)
+	c.Assert(routertest.FakeRouter.HasBackend(a.Name), check.Equals, false)
+}
+
+func (s *S) TestUpdateAppInternal(c *check.C) {
+	a := App{Name: "backend", Platform: "python", TeamOwner: s.team.Name}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	c.Assert(routertest.FakeRouter.HasBackend(a.Name), check.Equals, true)
+	internal := true
+	err = a.Update(UpdateAppArgs{Internal: &internal, Writer: new(bytes.Buffer)})
+	c.Assert(err, check.IsNil)
+	c.Assert(routertest.FakeRouter.HasBackend(a.Name), check.Equals, false)
+	dbApp, err := GetByName(context.TODO(), a.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.Internal, check.Equals, true)
+	c.Assert(dbApp.GetRouters(), check.HasLen, 0)
+	internal = false
+	err = dbApp.Update(UpdateAppArgs{Internal: &internal, Writer: new(bytes.Buffer)})
+	c.Assert(err, check.IsNil)
+	c.Assert(routertest.FakeRouter.HasBackend(a.Name), check.Equals, true)
+	dbApp, err = GetByName(context.TODO(), a.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.Internal, check.Equals, false)
+	c.Assert(dbApp.GetRouters(), check.DeepEquals, []appTypes.AppRouter{{Name: "fake"}})
+}
@@ -0,0 +1,117 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/globalsign/mgo/bson"
+	"github.com/pkg/errors"
+	"github.com/tsuru/tsuru/db"
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/router"
+)
+
+var (
+	ErrAppInMaintenance      = errors.New("app is in maintenance mode, deploys are blocked until it's lifted")
+	ErrAppNotInMaintenance   = errors.New("app is not in maintenance mode")
+	ErrInvalidMaintenanceOpt = &tsuruErrors.ValidationError{Message: "retry after must be greater than or equal to 0"}
+)
+
+// Maintenance holds the response sent by the app routers while the app is
+// in maintenance mode. Units keep running but receive no requests.
+type Maintenance struct {
+	Page       string `json:"page,omitempty"`
+	RetryAfter int    `json:"retryAfter,omitempty"`
+}
+
+func (app *App) maintenanceRouters(ctx context.Context) ([]router.MaintenanceRouter, error) {
+	var routers []router.MaintenanceRouter
+	for _, appRouter := range app.GetRouters() {
+		r, err := router.Get(ctx, appRouter.Name)
+		if err != nil {
+			return nil, err
+		}
+		maintenanceRouter, ok := r.(router.MaintenanceRouter)
+		if !ok {
+			return nil, &tsuruErrors.ValidationError{
+				Message: fmt.Sprintf("router %q does not support maintenance mode", appRouter.Name),
+			}
+		}
+		routers = append(routers, maintenanceRouter)
+	}
+	return routers, nil
+}
+
+// SetMaintenance puts the app in maintenance mode, making its routers answer
+// requests with the maintenance page, or with a 503 response when there's no
+// page. It can be called again to change the page of an app in maintenance.
+func (app *App) SetMaintenance(ctx context.Context, m Maintenance, w io.Writer) error {
+	if m.RetryAfter < 0 {
+		return ErrInvalidMaintenanceOpt
+	}
+	routers, err := app.maintenanceRouters(ctx)
+	if err != nil {
+		return err
+	}
+	w = app.withLogWriter(w)
+	fmt.Fprintf(w, "---- Enabling maintenance mode of %q ----\n", app.Name)
+	opts := router.MaintenanceOpts{Page: m.Page, RetryAfter: m.RetryAfter}
+	for i, r := range routers {
+		err = r.SetMaintenance(ctx, app, opts)
+		if err != nil {
+			if app.Maintenance == nil {
+				for _, setRouter := range routers[:i] {
+					setRouter.UnsetMaintenance(ctx, app)
+				}
+			}
+			return err
+		}
+	}
+	conn, err := db.Conn()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	err = conn.Apps().Update(bson.M{"name": app.Name}, bson.M{"$set": bson.M{"maintenance": m}})
+	if err != nil {
+		return err
+	}
+	app.Maintenance = &m
+	return nil
+}
+
+// UnsetMaintenance lifts the maintenance mode of the app, sending requests
+// to its units again.
+func (app *App) UnsetMaintenance(ctx context.Context, w io.Writer) error {
+	if app.Maintenance == nil {
+		return ErrAppNotInMaintenance
+	}
+	routers, err := app.maintenanceRouters(ctx)
+	if err != nil {
+		return err
+	}
+	w = app.withLogWriter(w)
+	fmt.Fprintf(w, "---- Disabling maintenance mode of %q ----\n", app.Name)
+	for _, r := range routers {
+		err = r.UnsetMaintenance(ctx, app)
+		if err != nil && err != router.ErrBackendNotFound {
+			return err
+		}
+	}
+	conn, err := db.Conn()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	err = conn.Apps().Update(bson.M{"name": app.Name}, bson.M{"$unset": bson.M{"maintenance": ""}})
+	if err != nil {
+		return err
+	}
+	app.Maintenance = nil
+	return nil
+}
@@ -0,0 +1,69 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"context"
+	"io/ioutil"
+	"strings"
+
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/router"
+	"github.com/tsuru/tsuru/router/routertest"
+	appTypes "github.com/tsuru/tsuru/types/app"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestSetMaintenance(c *check.C) {
+	a := App{Name: "down", Platform: "python", TeamOwner: s.team.Name, Routers: []appTypes.AppRouter{{Name: "fake-maintenance"}}}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	err = a.SetMaintenance(context.TODO(), Maintenance{Page: "<h1>down</h1>", RetryAfter: 60}, ioutil.Discard)
+	c.Assert(err, check.IsNil)
+	c.Assert(a.Maintenance, check.DeepEquals, &Maintenance{Page: "<h1>down</h1>", RetryAfter: 60})
+	c.Assert(routertest.MaintenanceRouter.Maintenance[a.Name], check.DeepEquals, router.MaintenanceOpts{Page: "<h1>down</h1>", RetryAfter: 60})
+	dbApp, err := GetByName(context.TODO(), a.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.Maintenance, check.DeepEquals, a.Maintenance)
+	err = a.UnsetMaintenance(context.TODO(), ioutil.Discard)
+	c.Assert(err, check.IsNil)
+	c.Assert(a.Maintenance, check.IsNil)
+	c.Assert(routertest.MaintenanceRouter.Maintenance, check.HasLen, 0)
+	dbApp, err = GetByName(context.TODO(), a.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.Maintenance, check.IsNil)
+	err = a.UnsetMaintenance(context.TODO(), ioutil.Discard)
+	c.Assert(err, check.Equals, ErrAppNotInMaintenance)
+}
+
+func (s *S) TestSetMaintenanceRouterNotSupported(c *check.C) {
+	a := App{Name: "down", Platform: "python", TeamOwner: s.team.Name}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	err = a.SetMaintenance(context.TODO(), Maintenance{}, ioutil.Discard)
+	c.Assert(err, check.ErrorMatches, `router "fake" does not support maintenance mode`)
+	c.Assert(a.Maintenance, check.IsNil)
+	err = a.SetMaintenance(context.TODO(), Maintenance{RetryAfter: -1}, ioutil.Discard)
+	c.Assert(err, check.Equals, ErrInvalidMaintenanceOpt)
+}
+
+func (s *S) TestDeployInMaintenance(c *check.C) {
+	a := App{Name: "down", Platform: "python", TeamOwner: s.team.Name, Routers: []appTypes.AppRouter{{Name: "fake-maintenance"}}}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	err = a.SetMaintenance(context.TODO(), Maintenance{}, ioutil.Discard)
+	c.Assert(err, check.IsNil)
+	evt := s.newAppEvent(c, &a, permission.PermAppDeploy)
+	defer evt.Done(nil)
+	buf := strings.NewReader("my file")
+	_, err = Deploy(context.TODO(), DeployOptions{
+		App:          &a,
+		File:         ioutil.NopCloser(buf),
+		FileSize:     int64(buf.Len()),
+		OutputStream: ioutil.Discard,
+		Event:        evt,
+	})
+	c.Assert(err, check.Equals, ErrAppInMaintenance)
+}
@@ -0,0 +1,284 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/globalsign/mgo/bson"
+	"github.com/pkg/errors"
+	"github.com/tsuru/tsuru/db"
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/log"
+	"github.com/tsuru/tsuru/provision"
+	"github.com/tsuru/tsuru/router/rebuild"
+	"github.com/tsuru/tsuru/servicemanager"
+	appTypes "github.com/tsuru/tsuru/types/app"
+)
+
+var (
+	ErrExtraPoolNotFound   = errors.New("pool is not an extra pool of the app")
+	ErrExtraPoolIsMainPool = &tsuruErrors.ValidationError{Message: "extra pool must be different from the app pool"}
+	ErrExtraPoolSharesMain = &tsuruErrors.ValidationError{Message: "extra pool must not use the same cluster and namespace as the app pool"}
+	ErrInvalidExtraUnits   = &tsuruErrors.ValidationError{Message: "number of units in an extra pool must be greater than 0"}
+)
+
+// ExtraPool is a pool, besides the app pool, where the app is also deployed.
+// Units is the number of units of each process running in the pool. Routers
+// send traffic to the units of every pool of the app.
+type ExtraPool struct {
+	Name  string `json:"name"`
+	Units uint   `json:"units"`
+}
+
+// inPool returns a copy of the app placed in the given pool, used to manage
+// the units of the app in its extra pools.
+func (app *App) inPool(pool string) *App {
+	poolApp := *app
+	poolApp.Pool = pool
+	poolApp.provisioner = nil
+	return &poolApp
+}
+
+func (app *App) findExtraPool(pool string) (int, bool) {
+	for i, extra := range app.ExtraPools {
+		if extra.Name == pool {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+// SetExtraPool adds an extra pool to the app, or updates its number of
+// units. The last successful version of the app is deployed to the pool.
+func (app *App) SetExtraPool(ctx context.Context, extra ExtraPool, evt *event.Event) error {
+	if extra.Name == app.Pool {
+		return ErrExtraPoolIsMainPool
+	}
+	if extra.Units == 0 {
+		return ErrInvalidExtraUnits
+	}
+	poolApp := app.inPool(extra.Name)
+	idx, exists := app.findExtraPool(extra.Name)
+	if !exists {
+		err := poolApp.validatePool()
+		if err != nil {
+			return err
+		}
+		err = poolApp.validatePlan()
+		if err != nil {
+			return err
+		}
+	}
+	prov, err := poolApp.getProvisioner()
+	if err != nil {
+		return err
+	}
+	if !exists {
+		err = app.validateExtraPoolResources(ctx, poolApp, prov)
+		if err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(evt, "---- Setting extra pool %q with %d units per process ----\n", extra.Name, extra.Units)
+	if !exists {
+		err = prov.Provision(ctx, poolApp)
+		if err != nil {
+			return err
+		}
+	}
+	extraPools := append([]ExtraPool{}, app.ExtraPools...)
+	if exists {
+		extraPools[idx] = extra
+	} else {
+		extraPools = append(extraPools, extra)
+	}
+	err = app.saveExtraPools(extraPools)
+	if err != nil {
+		return err
+	}
+	version, err := servicemanager.AppVersion.LatestSuccessfulVersion(ctx, app)
+	if err == appTypes.ErrNoVersionsAvailable {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	err = deployExtraPool(ctx, poolApp, extra, version, evt)
+	if err != nil {
+		return err
+	}
+	rebuild.RoutesRebuildOrEnqueueWithProgress(app.Name, evt)
+	return nil
+}
+
+// RemoveExtraPool removes the units of the app from an extra pool and stops
+// routing traffic to them.
+func (app *App) RemoveExtraPool(ctx context.Context, pool string, w io.Writer) error {
+	idx, exists := app.findExtraPool(pool)
+	if !exists {
+		return ErrExtraPoolNotFound
+	}
+	w = app.withLogWriter(w)
+	fmt.Fprintf(w, "---- Removing extra pool %q ----\n", pool)
+	extraPools := append([]ExtraPool{}, app.ExtraPools[:idx]...)
+	extraPools = append(extraPools, app.ExtraPools[idx+1:]...)
+	err := app.saveExtraPools(extraPools)
+	if err != nil {
+		return err
+	}
+	rebuild.RoutesRebuildOrEnqueueWithProgress(app.Name, w)
+	poolApp := app.inPool(pool)
+	prov, err := poolApp.getProvisioner()
+	if err != nil {
+		return err
+	}
+	return prov.Destroy(ctx, poolApp)
+}
+
+// validateExtraPoolResources refuses extra pools whose units would be
+// managed by the same resources as the units in the app pool, as deploying to
+// the extra pool would overwrite them and removing it would destroy them.
+func (app *App) validateExtraPoolResources(ctx context.Context, poolApp *App, prov provision.Provisioner) error {
+	appProv, err := app.getProvisioner()
+	if err != nil {
+		return err
+	}
+	if appProv.GetName() != prov.GetName() {
+		return nil
+	}
+	resourcesProv, ok := prov.(provision.PoolResourcesProvisioner)
+	if !ok {
+		return nil
+	}
+	shared, err := resourcesProv.SharesPoolResources(ctx, app, poolApp.Pool)
+	if err != nil {
+		return err
+	}
+	if shared {
+		return ErrExtraPoolSharesMain
+	}
+	return nil
+}
+
+func (app *App) saveExtraPools(extraPools []ExtraPool) error {
+	conn, err := db.Conn()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	update := bson.M{"$set": bson.M{"extrapools": extraPools}}
+	if len(extraPools) == 0 {
+		extraPools = nil
+		update = bson.M{"$unset": bson.M{"extrapools": ""}}
+	}
+	err = conn.Apps().Update(bson.M{"name": app.Name}, update)
+	if err != nil {
+		return err
+	}
+	app.ExtraPools = extraPools
+	return nil
+}
+
+// deployToExtraPools deploys the version to every extra pool of the app.
+func (app *App) deployToExtraPools(ctx context.Context, version appTypes.AppVersion, evt *event.Event) error {
+	for _, extra := range app.ExtraPools {
+		err := deployExtraPool(ctx, app.inPool(extra.Name), extra, version, evt)
+		if err != nil {
+			return errors.Wrapf(err, "unable to deploy to pool %q", extra.Name)
+		}
+	}
+	return nil
+}
+
+func deployExtraPool(ctx context.Context, poolApp *App, extra ExtraPool, version appTypes.AppVersion, evt *event.Event) error {
+	prov, err := poolApp.getProvisioner()
+	if err != nil {
+		return err
+	}
+	deployer, ok := prov.(provision.BuilderDeploy)
+	if !ok {
+		return provision.ProvisionerNotSupported{Prov: prov, Action: "extra pool deploy"}
+	}
+	fmt.Fprintf(evt, "---- Deploying version %d to pool %q ----\n", version.Version(), extra.Name)
+	_, err = deployer.Deploy(ctx, provision.DeployArgs{
+		App:     poolApp,
+		Version: version,
+		Event:   evt,
+	})
+	if err != nil {
+		return err
+	}
+	return ensureExtraPoolUnits(ctx, poolApp, prov, extra, version, evt)
+}
+
+// ensureExtraPoolUnits adds or removes units of each process of the version
+// until the pool has the configured number of units.
+func ensureExtraPoolUnits(ctx context.Context, poolApp *App, prov provision.Provisioner, extra ExtraPool, version appTypes.AppVersion, w io.Writer) error {
+	processes, err := version.Processes()
+	if err != nil {
+		return err
+	}
+	units, err := prov.Units(ctx, poolApp)
+	if err != nil {
+		return err
+	}
+	current := map[string]uint{}
+	for _, u := range units {
+		if u.Version == version.Version() {
+			current[u.ProcessName]++
+		}
+	}
+	for process := range processes {
+		n := current[process]
+		switch {
+		case n < extra.Units:
+			err = prov.AddUnits(ctx, poolApp, extra.Units-n, process, version, w)
+		case n > extra.Units:
+			err = prov.RemoveUnits(ctx, poolApp, n-extra.Units, process, version, w)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// forEachExtraPool calls fn with the app placed in each of its extra pools,
+// returning all the errors found.
+func (app *App) forEachExtraPool(fn func(poolApp *App, prov provision.Provisioner) error) error {
+	multi := tsuruErrors.NewMultiError()
+	for _, extra := range app.ExtraPools {
+		poolApp := app.inPool(extra.Name)
+		prov, err := poolApp.getProvisioner()
+		if err == nil {
+			err = fn(poolApp, prov)
+		}
+		if err != nil {
+			log.Errorf("[extra-pool] error in pool %q of app %q: %v", extra.Name, app.Name, err)
+			multi.Add(errors.Wrapf(err, "error in pool %q", extra.Name))
+		}
+	}
+	return multi.ToError()
+}
+
+// extraPoolsRoutableAddresses returns the addresses of the app units in its
+// extra pools, they're merged by prefix with the ones from the app pool when
+// routes are rebuilt.
+func (app *App) extraPoolsRoutableAddresses(ctx context.Context) ([]appTypes.RoutableAddresses, error) {
+	var addrs []appTypes.RoutableAddresses
+	err := app.forEachExtraPool(func(poolApp *App, prov provision.Provisioner) error {
+		poolAddrs, err := prov.RoutableAddresses(ctx, poolApp)
+		if err != nil {
+			return err
+		}
+		addrs = append(addrs, poolAddrs...)
+		return nil
+	})
+	return addrs, err
+}
@@ -0,0 +1,168 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"context"
+	"io/ioutil"
+	"net/url"
+	"strings"
+
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/provision"
+	"github.com/tsuru/tsuru/provision/pool"
+	"github.com/tsuru/tsuru/provision/provisiontest"
+	appTypes "github.com/tsuru/tsuru/types/app"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) addExtraPoolProvisioner(c *check.C) *provisiontest.FakeProvisioner {
+	p := provisiontest.NewFakeProvisioner()
+	p.Name = "fake-extra"
+	provision.Register("fake-extra", func() (provision.Provisioner, error) {
+		return p, nil
+	})
+	err := pool.AddPool(context.TODO(), pool.AddPoolOptions{Name: "extra", Provisioner: "fake-extra", Public: true})
+	c.Assert(err, check.IsNil)
+	return p
+}
+
+func (s *S) newAppEvent(c *check.C, a *App, kind *permission.PermissionScheme) *event.Event {
+	evt, err := event.New(&event.Opts{
+		Target:   event.Target{Type: "app", Value: a.Name},
+		Kind:     kind,
+		RawOwner: event.Owner{Type: event.OwnerTypeUser, Name: s.user.Email},
+		Allowed:  event.Allowed(permission.PermApp),
+	})
+	c.Assert(err, check.IsNil)
+	return evt
+}
+
+func (s *S) TestSetExtraPool(c *check.C) {
+	p := s.addExtraPoolProvisioner(c)
+	defer provision.Unregister("fake-extra")
+	a := App{Name: "multi", Platform: "python", TeamOwner: s.team.Name}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	version := newSuccessfulAppVersion(c, &a)
+	err = version.AddData(appTypes.AddVersionDataArgs{
+		Processes: map[string][]string{"web": {"run"}, "worker": {"work"}},
+	})
+	c.Assert(err, check.IsNil)
+	evt := s.newAppEvent(c, &a, permission.PermAppUpdatePool)
+	defer evt.Done(nil)
+	err = a.SetExtraPool(context.TODO(), ExtraPool{Name: "extra", Units: 2}, evt)
+	c.Assert(err, check.IsNil)
+	c.Assert(a.ExtraPools, check.DeepEquals, []ExtraPool{{Name: "extra", Units: 2}})
+	dbApp, err := GetByName(context.TODO(), a.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.ExtraPools, check.DeepEquals, []ExtraPool{{Name: "extra", Units: 2}})
+	poolApp := a.inPool("extra")
+	c.Assert(p.Provisioned(poolApp), check.Equals, true)
+	units, err := p.Units(context.TODO(), poolApp)
+	c.Assert(err, check.IsNil)
+	c.Assert(units, check.HasLen, 4)
+	err = a.SetExtraPool(context.TODO(), ExtraPool{Name: "extra", Units: 1}, evt)
+	c.Assert(err, check.IsNil)
+	c.Assert(a.ExtraPools, check.DeepEquals, []ExtraPool{{Name: "extra", Units: 1}})
+	units, err = p.Units(context.TODO(), poolApp)
+	c.Assert(err, check.IsNil)
+	c.Assert(units, check.HasLen, 2)
+}
+
+func (s *S) TestSetExtraPoolInvalid(c *check.C) {
+	a := App{Name: "multi", Platform: "python", TeamOwner: s.team.Name}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	evt := s.newAppEvent(c, &a, permission.PermAppUpdatePool)
+	defer evt.Done(nil)
+	err = a.SetExtraPool(context.TODO(), ExtraPool{Name: a.Pool, Units: 1}, evt)
+	c.Assert(err, check.Equals, ErrExtraPoolIsMainPool)
+	err = a.SetExtraPool(context.TODO(), ExtraPool{Name: "other", Units: 0}, evt)
+	c.Assert(err, check.Equals, ErrInvalidExtraUnits)
+	err = a.SetExtraPool(context.TODO(), ExtraPool{Name: "not-found", Units: 1}, evt)
+	c.Assert(err, check.Equals, pool.ErrPoolNotFound)
+	c.Assert(a.ExtraPools, check.IsNil)
+}
+
+func (s *S) TestSetExtraPoolSharingAppPoolResources(c *check.C) {
+	err := pool.AddPool(context.TODO(), pool.AddPoolOptions{Name: "same-cluster", Public: true})
+	c.Assert(err, check.IsNil)
+	a := App{Name: "multi", Platform: "python", TeamOwner: s.team.Name}
+	err = CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	s.provisioner.MockSharedPools(a.Pool, "same-cluster")
+	evt := s.newAppEvent(c, &a, permission.PermAppUpdatePool)
+	defer evt.Done(nil)
+	err = a.SetExtraPool(context.TODO(), ExtraPool{Name: "same-cluster", Units: 1}, evt)
+	c.Assert(err, check.Equals, ErrExtraPoolSharesMain)
+	c.Assert(a.ExtraPools, check.IsNil)
+}
+
+func (s *S) TestRemoveExtraPool(c *check.C) {
+	p := s.addExtraPoolProvisioner(c)
+	defer provision.Unregister("fake-extra")
+	a := App{Name: "multi", Platform: "python", TeamOwner: s.team.Name}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	evt := s.newAppEvent(c, &a, permission.PermAppUpdatePool)
+	defer evt.Done(nil)
+	err = a.SetExtraPool(context.TODO(), ExtraPool{Name: "extra", Units: 1}, evt)
+	c.Assert(err, check.IsNil)
+	err = a.RemoveExtraPool(context.TODO(), "extra", evt)
+	c.Assert(err, check.IsNil)
+	c.Assert(a.ExtraPools, check.IsNil)
+	c.Assert(p.Provisioned(a.inPool("extra")), check.Equals, false)
+	dbApp, err := GetByName(context.TODO(), a.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.ExtraPools, check.IsNil)
+	err = a.RemoveExtraPool(context.TODO(), "extra", evt)
+	c.Assert(err, check.Equals, ErrExtraPoolNotFound)
+}
+
+func (s *S) TestRoutableAddressesWithExtraPools(c *check.C) {
+	p := s.addExtraPoolProvisioner(c)
+	defer provision.Unregister("fake-extra")
+	a := App{Name: "multi", Platform: "python", TeamOwner: s.team.Name}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	evt := s.newAppEvent(c, &a, permission.PermAppUpdatePool)
+	defer evt.Done(nil)
+	err = a.SetExtraPool(context.TODO(), ExtraPool{Name: "extra", Units: 1}, evt)
+	c.Assert(err, check.IsNil)
+	mainAddrs := []appTypes.RoutableAddresses{{Addresses: []*url.URL{{Host: "main:80"}}}}
+	extraAddrs := []appTypes.RoutableAddresses{{Addresses: []*url.URL{{Host: "extra:80"}}}}
+	s.provisioner.MockRoutableAddresses(&a, mainAddrs)
+	p.MockRoutableAddresses(a.inPool("extra"), extraAddrs)
+	addrs, err := a.RoutableAddresses(context.TODO())
+	c.Assert(err, check.IsNil)
+	c.Assert(addrs, check.DeepEquals, append(mainAddrs, extraAddrs...))
+}
+
+func (s *S) TestDeployToExtraPools(c *check.C) {
+	p := s.addExtraPoolProvisioner(c)
+	defer provision.Unregister("fake-extra")
+	a := App{Name: "multi", Platform: "python", TeamOwner: s.team.Name}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	evt := s.newAppEvent(c, &a, permission.PermAppUpdatePool)
+	err = a.SetExtraPool(context.TODO(), ExtraPool{Name: "extra", Units: 1}, evt)
+	c.Assert(err, check.IsNil)
+	evt.Done(nil)
+	evt = s.newAppEvent(c, &a, permission.PermAppDeploy)
+	defer evt.Done(nil)
+	buf := strings.NewReader("my file")
+	_, err = Deploy(context.TODO(), DeployOptions{
+		App:          &a,
+		File:         ioutil.NopCloser(buf),
+		FileSize:     int64(buf.Len()),
+		OutputStream: ioutil.Discard,
+		Event:        evt,
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(evt.Log(), check.Matches, `(?s).*---- Deploying version 1 to pool "extra" ----.*`)
+	c.Assert(p.Provisioned(a.inPool("extra")), check.Equals, true)
+}
@@ -6,6 +6,7 @@ package app
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/pkg/errors"
 	tsuruErrors "github.com/tsuru/tsuru/errors"
@@ -167,6 +168,12 @@ func (s *planService) Create(ctx context.Context, plan appTypes.Plan) error {
 	if plan.Memory > 0 && plan.Memory < 4194304 {
 		return appTypes.ErrLimitOfMemory
 	}
+	if plan.MaxUnits > 0 && plan.MinUnits > plan.MaxUnits {
+		return appTypes.ErrLimitOfUnits
+	}
+	if plan.GPU < 0 || (plan.GPUType != "" && plan.GPU == 0) {
+		return appTypes.ErrLimitOfGPU
+	}
 	return s.storage.Insert(ctx, plan)
 }
 
@@ -211,3 +218,73 @@ func (s *planService) ensureDefault(ctx context.Context) error {
 
 	return multiErr.ToError()
 }
+
+// PlanUnitsLimitError is returned when scaling a process would leave it with
+// a number of units outside the limits of its plan.
+type PlanUnitsLimitError struct {
+	Plan    string
+	Process string
+	Units   int
+	Limit   uint
+	IsMax   bool
+}
+
+func (e *PlanUnitsLimitError) Error() string {
+	bound := "at least"
+	if e.IsMax {
+		bound = "at most"
+	}
+	return fmt.Sprintf("plan %q allows %s %d units per process, process %q would have %d units", e.Plan, bound, e.Limit, e.Process, e.Units)
+}
+
+// processPlan returns the plan used by the given process of the app.
+func (app *App) processPlan(process string) appTypes.Plan {
+	if plan, ok := app.ProcessPlans[process]; ok {
+		return plan
+	}
+	return app.Plan
+}
+
+// checkPlanMaxUnits returns an error if the process would end up with more
+// units than allowed by its plan.
+func (app *App) checkPlanMaxUnits(process string, units int) error {
+	plan := app.processPlan(process)
+	if plan.MaxUnits > 0 && units > int(plan.MaxUnits) {
+		return &PlanUnitsLimitError{Plan: plan.Name, Process: process, Units: units, Limit: plan.MaxUnits, IsMax: true}
+	}
+	return nil
+}
+
+// checkPlanMinUnits returns an error if the process would end up with less
+// units than required by its plan.
+func (app *App) checkPlanMinUnits(process string, units int) error {
+	plan := app.processPlan(process)
+	if plan.MinUnits > 0 && units < int(plan.MinUnits) {
+		return &PlanUnitsLimitError{Plan: plan.Name, Process: process, Units: units, Limit: plan.MinUnits}
+	}
+	return nil
+}
+
+// checkAutoScalePlanLimits returns an error if the autoscale spec allows the
+// process to scale outside the limits of its plan.
+func (app *App) checkAutoScalePlanLimits(process string, minUnits, maxUnits uint) error {
+	err := app.checkPlanMaxUnits(process, int(maxUnits))
+	if err != nil {
+		return err
+	}
+	return app.checkPlanMinUnits(process, int(minUnits))
+}
+
+func (app *App) processUnitsCount(process string) (int, error) {
+	units, err := app.Units()
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, u := range units {
+		if process == "" || u.ProcessName == process {
+			count++
+		}
+	}
+	return count, nil
+}
@@ -50,8 +50,27 @@ func (s *S) TestPlanAddInvalid(c *check.C) {
 			Swap:     1024,
 			CpuShare: 100,
 		},
+		{
+			Name:     "plan1",
+			Memory:   9223372036854775807,
+			CpuShare: 100,
+			MinUnits: 5,
+			MaxUnits: 2,
+		},
+		{
+			Name:     "plan1",
+			Memory:   9223372036854775807,
+			CpuShare: 100,
+			GPUType:  "nvidia-tesla-t4",
+		},
+		{
+			Name:     "plan1",
+			Memory:   9223372036854775807,
+			CpuShare: 100,
+			GPU:      -1,
+		},
 	}
-	expectedError := []error{appTypes.PlanValidationError{Field: "name"}, appTypes.ErrLimitOfCpuShare, appTypes.ErrLimitOfMemory}
+	expectedError := []error{appTypes.PlanValidationError{Field: "name"}, appTypes.ErrLimitOfCpuShare, appTypes.ErrLimitOfMemory, appTypes.ErrLimitOfUnits, appTypes.ErrLimitOfGPU, appTypes.ErrLimitOfGPU}
 	ps := &planService{
 		storage: &appTypes.MockPlanStorage{
 			OnInsert: func(appTypes.Plan) error {
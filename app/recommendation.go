@@ -0,0 +1,139 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"math"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/tsuru/tsuru/provision/pool"
+	"github.com/tsuru/tsuru/servicemanager"
+	appTypes "github.com/tsuru/tsuru/types/app"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const recommendationTypeTarget = "target"
+
+// ResourceRecommendation compares the resources reserved by the plan of a
+// process with the ones recommended for its units, along with the smallest
+// plan allowed on the app pool fitting the recommendation.
+type ResourceRecommendation struct {
+	Process             string `json:"process"`
+	Plan                string `json:"plan"`
+	CPUMilli            int    `json:"cpumilli"`
+	Memory              int64  `json:"memory"`
+	RecommendedCPUMilli int    `json:"recommendedCPUMilli"`
+	RecommendedMemory   int64  `json:"recommendedMemory"`
+	SuggestedPlan       string `json:"suggestedPlan,omitempty"`
+}
+
+// ResourceRecommendations returns the recommendations of resources for the
+// processes of the app with a target recommendation from the provisioner.
+func (app *App) ResourceRecommendations() ([]ResourceRecommendation, error) {
+	recommended, err := app.VerticalAutoScaleRecommendations()
+	if err != nil {
+		return nil, err
+	}
+	if len(recommended) == 0 {
+		return nil, nil
+	}
+	plans, err := app.poolPlans()
+	if err != nil {
+		return nil, err
+	}
+	var result []ResourceRecommendation
+	for _, r := range recommended {
+		for _, rec := range r.Recommendations {
+			if rec.Type != recommendationTypeTarget {
+				continue
+			}
+			cpu, err := resource.ParseQuantity(rec.CPU)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid cpu recommendation for process %q", r.Process)
+			}
+			memory, err := resource.ParseQuantity(rec.Memory)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid memory recommendation for process %q", r.Process)
+			}
+			planName := app.Plan.Name
+			if plan, ok := app.ProcessPlans[r.Process]; ok {
+				planName = plan.Name
+			}
+			recommendation := ResourceRecommendation{
+				Process:             r.Process,
+				Plan:                planName,
+				CPUMilli:            app.GetProcessMilliCPU(r.Process),
+				Memory:              app.GetProcessMemory(r.Process),
+				RecommendedCPUMilli: int(cpu.MilliValue()),
+				RecommendedMemory:   memory.Value(),
+			}
+			if plan := smallestFittingPlan(plans, recommendation.RecommendedCPUMilli, recommendation.RecommendedMemory); plan != nil {
+				recommendation.SuggestedPlan = plan.Name
+			}
+			result = append(result, recommendation)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Process < result[j].Process
+	})
+	return result, nil
+}
+
+func (app *App) poolPlans() ([]appTypes.Plan, error) {
+	p, err := pool.GetPoolByName(app.ctx, app.Pool)
+	if err != nil {
+		return nil, err
+	}
+	names, err := p.GetPlans()
+	if err != nil {
+		return nil, err
+	}
+	allowed := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		allowed[name] = struct{}{}
+	}
+	plans, err := servicemanager.Plan.List(app.ctx)
+	if err != nil {
+		return nil, err
+	}
+	var result []appTypes.Plan
+	for _, plan := range plans {
+		if _, ok := allowed[plan.Name]; ok {
+			result = append(result, plan)
+		}
+	}
+	return result, nil
+}
+
+// smallestFittingPlan returns the plan with the least memory, and then the
+// least cpu, whose resources are enough for the given cpu and memory. Plans
+// without limits of a resource fit any amount of it.
+func smallestFittingPlan(plans []appTypes.Plan, cpuMilli int, memory int64) *appTypes.Plan {
+	var best *appTypes.Plan
+	for i := range plans {
+		plan := &plans[i]
+		planMemory, planCPU := planLimit(plan.Memory), planLimit(int64(plan.CPUMilli))
+		if planMemory < memory || planCPU < int64(cpuMilli) {
+			continue
+		}
+		if best == nil {
+			best = plan
+			continue
+		}
+		bestMemory, bestCPU := planLimit(best.Memory), planLimit(int64(best.CPUMilli))
+		if planMemory < bestMemory || (planMemory == bestMemory && planCPU < bestCPU) {
+			best = plan
+		}
+	}
+	return best
+}
+
+func planLimit(value int64) int64 {
+	if value == 0 {
+		return math.MaxInt64
+	}
+	return value
+}
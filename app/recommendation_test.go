@@ -0,0 +1,75 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"context"
+
+	"github.com/tsuru/tsuru/provision"
+	"github.com/tsuru/tsuru/provision/provisiontest"
+	appTypes "github.com/tsuru/tsuru/types/app"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestResourceRecommendations(c *check.C) {
+	oldProvisioner := provision.DefaultProvisioner
+	defer func() { provision.DefaultProvisioner = oldProvisioner }()
+	provision.DefaultProvisioner = "autoscaleProv"
+	provision.Register("autoscaleProv", func() (provision.Provisioner, error) {
+		return &provisiontest.AutoScaleProvisioner{FakeProvisioner: provisiontest.ProvisionerInstance}, nil
+	})
+	defer provision.Unregister("autoscaleProv")
+	s.plan = appTypes.Plan{Name: "small", Memory: 128 * 1024 * 1024, CPUMilli: 200}
+	a := App{Name: "myapp", TeamOwner: s.team.Name}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	recommendations, err := a.ResourceRecommendations()
+	c.Assert(err, check.IsNil)
+	c.Assert(recommendations, check.IsNil)
+	err = a.AutoScale(provision.AutoScaleSpec{Process: "p1"})
+	c.Assert(err, check.IsNil)
+	recommendations, err = a.ResourceRecommendations()
+	c.Assert(err, check.IsNil)
+	c.Assert(recommendations, check.DeepEquals, []ResourceRecommendation{
+		{
+			Process:             "p1",
+			Plan:                "default-plan",
+			Memory:              1024,
+			RecommendedCPUMilli: 100,
+			RecommendedMemory:   100 * 1024 * 1024,
+			SuggestedPlan:       "small",
+		},
+	})
+}
+
+func (s *S) TestSmallestFittingPlan(c *check.C) {
+	plans := []appTypes.Plan{
+		{Name: "unlimited"},
+		{Name: "large", Memory: 1024, CPUMilli: 1000},
+		{Name: "medium-cpu", Memory: 512, CPUMilli: 1000},
+		{Name: "medium", Memory: 512, CPUMilli: 500},
+		{Name: "small", Memory: 256, CPUMilli: 250},
+		{Name: "small-no-cpu-limit", Memory: 256},
+	}
+	tests := []struct {
+		cpuMilli int
+		memory   int64
+		expected string
+	}{
+		{cpuMilli: 100, memory: 100, expected: "small"},
+		{cpuMilli: 300, memory: 100, expected: "small-no-cpu-limit"},
+		{cpuMilli: 300, memory: 300, expected: "medium"},
+		{cpuMilli: 600, memory: 300, expected: "medium-cpu"},
+		{cpuMilli: 600, memory: 800, expected: "large"},
+		{cpuMilli: 2000, memory: 800, expected: "unlimited"},
+	}
+	for _, tt := range tests {
+		plan := smallestFittingPlan(plans, tt.cpuMilli, tt.memory)
+		c.Assert(plan, check.NotNil)
+		c.Check(plan.Name, check.Equals, tt.expected, check.Commentf("cpu %d, memory %d", tt.cpuMilli, tt.memory))
+	}
+	c.Assert(smallestFittingPlan(plans[1:], 2000, 800), check.IsNil)
+	c.Assert(smallestFittingPlan(nil, 100, 100), check.IsNil)
+}
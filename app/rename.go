@@ -0,0 +1,341 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	"github.com/pkg/errors"
+	"github.com/tsuru/tsuru/action"
+	"github.com/tsuru/tsuru/app/bind"
+	"github.com/tsuru/tsuru/db"
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/log"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/router"
+	"github.com/tsuru/tsuru/service"
+	"github.com/tsuru/tsuru/servicemanager"
+	appTypes "github.com/tsuru/tsuru/types/app"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+)
+
+var ErrRenameSameName = &tsuruErrors.ValidationError{Message: "new app name must be different from the current one"}
+
+// appRenameFns update the references to an app name kept outside of the app
+// document. They're called with the names swapped to roll back a rename.
+var appRenameFns = []func(ctx context.Context, oldName, newName string) error{
+	func(ctx context.Context, oldName, newName string) error {
+		return servicemanager.AppVersion.RenameApp(ctx, oldName, newName)
+	},
+	renameAppEvents,
+}
+
+func renameAppEvents(ctx context.Context, oldName, newName string) error {
+	err := event.RenameTarget(
+		event.Target{Type: event.TargetTypeApp, Value: oldName},
+		event.Target{Type: event.TargetTypeApp, Value: newName},
+	)
+	if err != nil {
+		return err
+	}
+	return event.RenameAllowedContext(
+		permission.Context(permTypes.CtxApp, oldName),
+		permission.Context(permTypes.CtxApp, newName),
+	)
+}
+
+// Rename changes the name of the app, keeping its versions, environment
+// variables and event history. The service instances bound to the app are
+// bound again under the new name, so service APIs provide the envs of the
+// new binding, and the app is provisioned and its units are added under the
+// new name before the units, router backends and service bindings of the old
+// name are removed. Logs already stored for the app stay under the old name.
+func (app *App) Rename(ctx context.Context, newName string, evt *event.Event, requestID string) error {
+	if newName == app.Name {
+		return ErrRenameSameName
+	}
+	isSwapped, swappedWith, err := router.IsSwapped(app.GetName())
+	if err != nil {
+		return errors.Wrap(err, "unable to check if app is swapped")
+	}
+	if isSwapped {
+		return errors.Errorf("application is swapped with %q, cannot rename it", swappedWith)
+	}
+	volumes, err := servicemanager.Volume.ListByApp(ctx, app.Name)
+	if err != nil {
+		return err
+	}
+	if len(volumes) > 0 {
+		return errors.New("can't rename an app with bound volumes")
+	}
+	_, err = GetByName(ctx, newName)
+	if err == nil {
+		return ErrAppAlreadyExists
+	}
+	if err != appTypes.ErrAppNotFound {
+		return err
+	}
+	renamed := *app
+	renamed.Name = newName
+	renamed.Quota.InUse = 0
+	renamed.Env = make(map[string]bind.EnvVar, len(app.Env))
+	for k, v := range app.Env {
+		renamed.Env[k] = v
+	}
+	if appNameEnv, ok := renamed.Env["TSURU_APPNAME"]; ok {
+		appNameEnv.Value = newName
+		renamed.Env["TSURU_APPNAME"] = appNameEnv
+	}
+	// The envs of the service instances are set again when they're bound to
+	// the new name.
+	renamed.ServiceEnvs = nil
+	err = renamed.validateNew(ctx)
+	if err != nil {
+		return err
+	}
+	var w io.Writer = ioutil.Discard
+	if evt != nil {
+		w = evt
+	}
+	w = app.withLogWriter(w)
+	fmt.Fprintf(w, "---- Renaming application %q to %q ----\n", app.Name, newName)
+	err = action.NewPipeline(
+		&createRenamedAppToken,
+		&insertRenamedApp,
+		&renameAppReferences,
+		&addRouterBackend,
+		&bindRenamedAppServiceInstances,
+		&provisionAppNewProvisioner,
+		&provisionAppAddUnits,
+		&destroyAppOldProvisioner,
+		&removeRenamedApp,
+	).Execute(ctx, &renamed, app, w, evt, requestID)
+	if err != nil {
+		return err
+	}
+	*app = renamed
+	return nil
+}
+
+var createRenamedAppToken = action.Action{
+	Name: "rename-app-create-token",
+	Forward: func(ctx action.FWContext) (action.Result, error) {
+		app, ok := ctx.Params[0].(*App)
+		if !ok {
+			return nil, errors.New("expected app ptr as first arg")
+		}
+		tokenEnv, ok := app.Env["TSURU_APP_TOKEN"]
+		if !ok {
+			return nil, nil
+		}
+		t, err := AuthScheme.AppLogin(ctx.Context, app.Name)
+		if err != nil {
+			return nil, err
+		}
+		tokenEnv.Value = t.GetValue()
+		app.Env["TSURU_APP_TOKEN"] = tokenEnv
+		return t.GetValue(), nil
+	},
+	Backward: func(ctx action.BWContext) {
+		if token, _ := ctx.FWResult.(string); token != "" {
+			AuthScheme.Logout(ctx.Context, token)
+		}
+	},
+	MinParams: 2,
+}
+
+var insertRenamedApp = action.Action{
+	Name: "rename-app-insert-app",
+	Forward: func(ctx action.FWContext) (action.Result, error) {
+		app, ok := ctx.Params[0].(*App)
+		if !ok {
+			return nil, errors.New("expected app ptr as first arg")
+		}
+		conn, err := db.Conn()
+		if err != nil {
+			return nil, err
+		}
+		defer conn.Close()
+		err = conn.Apps().Insert(app)
+		if mgo.IsDup(err) {
+			return nil, ErrAppAlreadyExists
+		}
+		if err != nil {
+			return nil, err
+		}
+		if plog, ok := servicemanager.AppLog.(appTypes.AppLogServiceProvision); ok {
+			plog.Provision(app.Name)
+		}
+		return nil, nil
+	},
+	Backward: func(ctx action.BWContext) {
+		app := ctx.Params[0].(*App)
+		removeApp(app)
+	},
+	MinParams: 2,
+}
+
+var renameAppReferences = action.Action{
+	Name: "rename-app-references",
+	Forward: func(ctx action.FWContext) (action.Result, error) {
+		app, ok := ctx.Params[0].(*App)
+		if !ok {
+			return nil, errors.New("expected app ptr as first arg")
+		}
+		oldApp, ok := ctx.Params[1].(*App)
+		if !ok {
+			return nil, errors.New("expected app ptr as second arg")
+		}
+		for i, fn := range appRenameFns {
+			err := fn(ctx.Context, oldApp.Name, app.Name)
+			if err != nil {
+				rollbackAppRename(ctx.Context, appRenameFns[:i], oldApp.Name, app.Name)
+				return nil, err
+			}
+		}
+		return nil, nil
+	},
+	Backward: func(ctx action.BWContext) {
+		app := ctx.Params[0].(*App)
+		oldApp := ctx.Params[1].(*App)
+		rollbackAppRename(ctx.Context, appRenameFns, oldApp.Name, app.Name)
+	},
+	MinParams: 2,
+}
+
+func rollbackAppRename(ctx context.Context, fns []func(ctx context.Context, oldName, newName string) error, oldName, newName string) {
+	for i := len(fns) - 1; i >= 0; i-- {
+		err := fns[i](ctx, newName, oldName)
+		if err != nil {
+			log.Errorf("BACKWARD rename app - unable to rename %q back to %q: %s", newName, oldName, err)
+		}
+	}
+}
+
+// bindRenamedAppServiceInstances binds the service instances bound to the old
+// app name to the new one, setting the envs returned by the service APIs for
+// the new binding.
+var bindRenamedAppServiceInstances = action.Action{
+	Name: "rename-app-bind-service-instances",
+	Forward: func(ctx action.FWContext) (action.Result, error) {
+		app, ok := ctx.Params[0].(*App)
+		if !ok {
+			return nil, errors.New("expected app ptr as first arg")
+		}
+		oldApp, ok := ctx.Params[1].(*App)
+		if !ok {
+			return nil, errors.New("expected app ptr as second arg")
+		}
+		w, _ := ctx.Params[2].(io.Writer)
+		evt, _ := ctx.Params[3].(*event.Event)
+		requestID, _ := ctx.Params[4].(string)
+		instances, err := service.GetServiceInstancesBoundToApp(oldApp.Name)
+		if err != nil {
+			return nil, err
+		}
+		var bound []service.ServiceInstance
+		for _, si := range instances {
+			fmt.Fprintf(w, "---- Binding service instance %q of service %q to %q ----\n", si.Name, si.ServiceName, app.Name)
+			instance, err := service.GetServiceInstance(ctx.Context, si.ServiceName, si.Name)
+			if err == nil {
+				err = instance.BindApp(app, nil, false, w, evt, requestID)
+			}
+			if err != nil {
+				unbindServiceInstances(ctx.Context, app, bound, evt, requestID)
+				return nil, errors.Wrapf(err, "unable to bind service instance %q of service %q", si.Name, si.ServiceName)
+			}
+			bound = append(bound, si)
+		}
+		return bound, nil
+	},
+	Backward: func(ctx action.BWContext) {
+		app := ctx.Params[0].(*App)
+		evt, _ := ctx.Params[3].(*event.Event)
+		requestID, _ := ctx.Params[4].(string)
+		bound, _ := ctx.FWResult.([]service.ServiceInstance)
+		unbindServiceInstances(ctx.Context, app, bound, evt, requestID)
+	},
+	MinParams: 5,
+}
+
+// unbindServiceInstances removes the bindings of the app with the service
+// instances, ignoring failures of the service APIs, which are only logged.
+func unbindServiceInstances(ctx context.Context, app *App, instances []service.ServiceInstance, evt *event.Event, requestID string) {
+	for _, si := range instances {
+		instance, err := service.GetServiceInstance(ctx, si.ServiceName, si.Name)
+		if err == nil {
+			err = instance.UnbindApp(service.UnbindAppArgs{
+				App:         app,
+				ForceRemove: true,
+				Event:       evt,
+				RequestID:   requestID,
+			})
+		}
+		if err != nil && err != service.ErrAppNotBound {
+			log.Errorf("[rename-app: %s] unable to unbind service instance %q of service %q: %s", app.Name, si.Name, si.ServiceName, err)
+		}
+	}
+}
+
+// removeRenamedApp cleans up what's left of the old app name. Failures are
+// only reported as the app is already running under its new name.
+var removeRenamedApp = action.Action{
+	Name: "rename-app-remove-old-app",
+	Forward: func(ctx action.FWContext) (action.Result, error) {
+		oldApp, ok := ctx.Params[1].(*App)
+		if !ok {
+			return nil, errors.New("expected app ptr as second arg")
+		}
+		w, _ := ctx.Params[2].(io.Writer)
+		if w == nil {
+			w = ioutil.Discard
+		}
+		logErr := func(msg string, err error) {
+			fmt.Fprintf(w, "%s: %s\n", msg, err)
+			log.Errorf("[rename-app: %s] %s: %s", oldApp.Name, msg, err)
+		}
+		evt, _ := ctx.Params[3].(*event.Event)
+		requestID, _ := ctx.Params[4].(string)
+		instances, err := service.GetServiceInstancesBoundToApp(oldApp.Name)
+		if err != nil {
+			logErr("Unable to list service instances bound to the old app", err)
+		}
+		unbindServiceInstances(ctx.Context, oldApp, instances, evt, requestID)
+		err = removeAllRoutersBackend(ctx.Context, oldApp)
+		if err != nil {
+			logErr("Failed to remove router backend", err)
+		}
+		err = router.Remove(oldApp.Name)
+		if err != nil {
+			logErr("Failed to remove router backend from database", err)
+		}
+		if token, ok := oldApp.Env["TSURU_APP_TOKEN"]; ok {
+			err = AuthScheme.AppLogout(ctx.Context, token.Value)
+			if err != nil {
+				logErr("Unable to remove old app token", err)
+			}
+		}
+		// The logs of the old name are kept, so only the app document is
+		// removed.
+		conn, err := db.Conn()
+		if err != nil {
+			logErr("Unable to remove old app from db", err)
+			return nil, nil
+		}
+		defer conn.Close()
+		err = conn.Apps().Remove(bson.M{"name": oldApp.Name})
+		if err != nil {
+			logErr("Unable to remove old app from db", err)
+		}
+		return nil, nil
+	},
+	MinParams: 2,
+}
@@ -0,0 +1,179 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/globalsign/mgo/bson"
+	"github.com/tsuru/tsuru/app/bind"
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/router/routertest"
+	"github.com/tsuru/tsuru/service"
+	"github.com/tsuru/tsuru/servicemanager"
+	appTypes "github.com/tsuru/tsuru/types/app"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) newRenameEvent(c *check.C, appName string) *event.Event {
+	evt, err := event.New(&event.Opts{
+		Target:   event.Target{Type: event.TargetTypeApp, Value: appName},
+		Kind:     permission.PermAppUpdateRename,
+		RawOwner: event.Owner{Type: event.OwnerTypeUser, Name: s.user.Email},
+		Allowed:  event.Allowed(permission.PermApp),
+	})
+	c.Assert(err, check.IsNil)
+	return evt
+}
+
+func (s *S) TestRenameApp(c *check.C) {
+	a := App{
+		Name:      "oldname",
+		Platform:  "python",
+		TeamOwner: s.team.Name,
+		Router:    "fake",
+	}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	version := newSuccessfulAppVersion(c, &a)
+	err = s.provisioner.AddUnits(context.TODO(), &a, 2, "web", version, nil)
+	c.Assert(err, check.IsNil)
+	evt := s.newRenameEvent(c, a.Name)
+	defer evt.Done(nil)
+	err = a.Rename(context.TODO(), "newname", evt, "")
+	c.Assert(err, check.IsNil)
+	c.Assert(a.Name, check.Equals, "newname")
+	c.Assert(evt.Log(), check.Matches, `(?s).*---- Renaming application "oldname" to "newname" ----.*`)
+	_, err = GetByName(context.TODO(), "oldname")
+	c.Assert(err, check.Equals, appTypes.ErrAppNotFound)
+	dbApp, err := GetByName(context.TODO(), "newname")
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.Env["TSURU_APPNAME"].Value, check.Equals, "newname")
+	versions, err := servicemanager.AppVersion.AppVersions(context.TODO(), dbApp)
+	c.Assert(err, check.IsNil)
+	c.Assert(versions.Versions, check.HasLen, 1)
+	c.Assert(s.provisioner.Provisioned(&App{Name: "oldname"}), check.Equals, false)
+	c.Assert(s.provisioner.GetUnits(dbApp), check.HasLen, 2)
+	c.Assert(routertest.FakeRouter.HasBackend("oldname"), check.Equals, false)
+	c.Assert(routertest.FakeRouter.HasBackend("newname"), check.Equals, true)
+}
+
+func (s *S) TestRenameAppRebindsServiceInstances(c *check.C) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		requests = append(requests, r.Method+" "+r.URL.Path+" "+r.Form.Get("app-name"))
+		if r.Method == http.MethodPost {
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"DATABASE_USER":%q}`, r.Form.Get("app-name"))
+		}
+	}))
+	defer server.Close()
+	err := service.Create(service.Service{
+		Name:       "mysql",
+		Endpoint:   map[string]string{"production": server.URL},
+		Password:   "abcde",
+		OwnerTeams: []string{s.team.Name},
+	})
+	c.Assert(err, check.IsNil)
+	a := App{Name: "oldname", Platform: "python", TeamOwner: s.team.Name, Router: "fake"}
+	err = CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	err = s.conn.ServiceInstances().Insert(service.ServiceInstance{Name: "mydb", ServiceName: "mysql", Apps: []string{a.Name}})
+	c.Assert(err, check.IsNil)
+	err = a.AddInstance(bind.AddInstanceArgs{
+		Envs: []bind.ServiceEnvVar{
+			{EnvVar: bind.EnvVar{Name: "DATABASE_USER", Value: "oldname"}, InstanceName: "mydb", ServiceName: "mysql"},
+		},
+	})
+	c.Assert(err, check.IsNil)
+	evt := s.newRenameEvent(c, a.Name)
+	defer evt.Done(nil)
+	err = a.Rename(context.TODO(), "newname", evt, "")
+	c.Assert(err, check.IsNil)
+	c.Assert(requests, check.DeepEquals, []string{
+		"POST /resources/mydb/bind-app newname",
+		"DELETE /resources/mydb/bind-app oldname",
+	})
+	var dbInstance service.ServiceInstance
+	err = s.conn.ServiceInstances().Find(bson.M{"name": "mydb"}).One(&dbInstance)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbInstance.Apps, check.DeepEquals, []string{"newname"})
+	dbApp, err := GetByName(context.TODO(), "newname")
+	c.Assert(err, check.IsNil)
+	envs := dbApp.Envs()
+	c.Assert(envs["DATABASE_USER"].Value, check.Equals, "newname")
+	c.Assert(envs[TsuruServicesEnvVar].Value, check.Equals, `{"mysql":[{"instance_name":"mydb","envs":{"DATABASE_USER":"newname"}}]}`)
+}
+
+func (s *S) TestRenameAppBindServiceInstanceError(c *check.C) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+	err := service.Create(service.Service{
+		Name:       "mysql",
+		Endpoint:   map[string]string{"production": server.URL},
+		Password:   "abcde",
+		OwnerTeams: []string{s.team.Name},
+	})
+	c.Assert(err, check.IsNil)
+	a := App{Name: "oldname", Platform: "python", TeamOwner: s.team.Name, Router: "fake"}
+	err = CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	err = s.conn.ServiceInstances().Insert(service.ServiceInstance{Name: "mydb", ServiceName: "mysql", Apps: []string{a.Name}})
+	c.Assert(err, check.IsNil)
+	evt := s.newRenameEvent(c, a.Name)
+	defer evt.Done(nil)
+	err = a.Rename(context.TODO(), "newname", evt, "")
+	c.Assert(err, check.ErrorMatches, `(?s)unable to bind service instance "mydb" of service "mysql": .*`)
+	c.Assert(a.Name, check.Equals, "oldname")
+	_, err = GetByName(context.TODO(), "oldname")
+	c.Assert(err, check.IsNil)
+	_, err = GetByName(context.TODO(), "newname")
+	c.Assert(err, check.Equals, appTypes.ErrAppNotFound)
+	var dbInstance service.ServiceInstance
+	err = s.conn.ServiceInstances().Find(bson.M{"name": "mydb"}).One(&dbInstance)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbInstance.Apps, check.DeepEquals, []string{"oldname"})
+}
+
+func (s *S) TestRenameAppSameName(c *check.C) {
+	a := App{Name: "myapp", Platform: "python", TeamOwner: s.team.Name}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	err = a.Rename(context.TODO(), "myapp", nil, "")
+	c.Assert(err, check.Equals, ErrRenameSameName)
+}
+
+func (s *S) TestRenameAppAlreadyExists(c *check.C) {
+	a := App{Name: "myapp", Platform: "python", TeamOwner: s.team.Name}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	other := App{Name: "otherapp", Platform: "python", TeamOwner: s.team.Name}
+	err = CreateApp(context.TODO(), &other, s.user)
+	c.Assert(err, check.IsNil)
+	err = a.Rename(context.TODO(), "otherapp", nil, "")
+	c.Assert(err, check.Equals, ErrAppAlreadyExists)
+	_, err = GetByName(context.TODO(), "myapp")
+	c.Assert(err, check.IsNil)
+}
+
+func (s *S) TestRenameAppInvalidName(c *check.C) {
+	a := App{Name: "myapp", Platform: "python", TeamOwner: s.team.Name}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	err = a.Rename(context.TODO(), "Invalid_Name", nil, "")
+	c.Assert(err, check.FitsTypeOf, &tsuruErrors.ValidationError{})
+	_, err = GetByName(context.TODO(), "myapp")
+	c.Assert(err, check.IsNil)
+}
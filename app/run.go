@@ -0,0 +1,138 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/provision"
+)
+
+var (
+	ErrRunNotFound = errors.New("run not found")
+
+	errRunAppUnavailable = errors.New("App must be available to run non-isolated commands")
+)
+
+type RunStatus string
+
+const (
+	RunStatusRunning   = RunStatus("running")
+	RunStatusSucceeded = RunStatus("succeeded")
+	RunStatusFailed    = RunStatus("failed")
+)
+
+// RunResult holds the status and the captured output of a command executed
+// with app run. Its ID is the unique ID of the event tracking the run.
+type RunResult struct {
+	ID      string
+	Command string
+	Status  RunStatus
+	// ExitCode is the exit code of the command, it's only set when the run
+	// has finished and the provisioner reported the code.
+	ExitCode  *int `json:",omitempty"`
+	Error     string
+	Output    string
+	StartTime time.Time
+	EndTime   time.Time
+}
+
+type runEndData struct {
+	ExitCode *int `bson:",omitempty"`
+}
+
+type exitStatusError interface {
+	ExitStatus() int
+}
+
+func runExitCode(err error) *int {
+	code := 0
+	if err != nil {
+		exitErr, ok := errors.Cause(err).(exitStatusError)
+		if !ok {
+			return nil
+		}
+		code = exitErr.ExitStatus()
+	}
+	return &code
+}
+
+// RunDone finishes the event of a command run, storing the exit code of the
+// command along with the event.
+func RunDone(evt *event.Event, err error) error {
+	return evt.DoneCustomData(err, runEndData{ExitCode: runExitCode(err)})
+}
+
+// RunDetached runs the command in background, the output is captured by the
+// event, which is finished once the command exits. The result of the run can
+// be retrieved later with GetRun, using the unique ID of the event.
+func (app *App) RunDetached(cmd string, evt *event.Event, args provision.RunArgs) error {
+	if !args.Isolated && !app.available() {
+		RunDone(evt, errRunAppUnavailable)
+		return errRunAppUnavailable
+	}
+	app.ReplaceContext(context.Background())
+	go func() {
+		RunDone(evt, app.Run(cmd, evt, args))
+	}()
+	return nil
+}
+
+// GetRun returns the result of a command executed in the app, identified by
+// the unique ID of the event tracking it.
+func (app *App) GetRun(id string) (*RunResult, error) {
+	evt, err := event.GetByHexID(id)
+	if err != nil {
+		if err == event.ErrEventNotFound {
+			return nil, ErrRunNotFound
+		}
+		return nil, err
+	}
+	if evt.Target != (event.Target{Type: event.TargetTypeApp, Value: app.Name}) ||
+		evt.Kind.Name != permission.PermAppRun.FullName() {
+		return nil, ErrRunNotFound
+	}
+	result := RunResult{
+		ID:        evt.UniqueID.Hex(),
+		Command:   runCommandFromEvent(evt),
+		Error:     evt.Error,
+		Output:    evt.Log(),
+		StartTime: evt.StartTime,
+		EndTime:   evt.EndTime,
+	}
+	switch {
+	case evt.Running:
+		result.Status = RunStatusRunning
+	case evt.Error != "":
+		result.Status = RunStatusFailed
+	default:
+		result.Status = RunStatusSucceeded
+	}
+	if !evt.Running {
+		var endData runEndData
+		if err = evt.EndData(&endData); err == nil {
+			result.ExitCode = endData.ExitCode
+		}
+	}
+	return &result, nil
+}
+
+func runCommandFromEvent(evt *event.Event) string {
+	var fields []map[string]interface{}
+	if err := evt.StartData(&fields); err != nil {
+		return ""
+	}
+	for _, field := range fields {
+		if field["name"] == "command" {
+			cmd, _ := field["value"].(string)
+			return cmd
+		}
+	}
+	return ""
+}
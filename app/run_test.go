@@ -0,0 +1,151 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/provision"
+	check "gopkg.in/check.v1"
+)
+
+type fakeExitError struct {
+	code int
+}
+
+func (e fakeExitError) Error() string {
+	return "command terminated with non-zero exit code"
+}
+
+func (e fakeExitError) ExitStatus() int {
+	return e.code
+}
+
+func (s *S) newRunEvent(c *check.C, a *App, cmd string) *event.Event {
+	evt, err := event.New(&event.Opts{
+		Target:     event.Target{Type: event.TargetTypeApp, Value: a.Name},
+		Kind:       permission.PermAppRun,
+		RawOwner:   event.Owner{Type: event.OwnerTypeUser, Name: s.user.Email},
+		CustomData: []map[string]interface{}{{"name": "command", "value": cmd}},
+		Allowed:    event.Allowed(permission.PermApp),
+	})
+	c.Assert(err, check.IsNil)
+	return evt
+}
+
+func waitRunFinished(c *check.C, a *App, id string) *RunResult {
+	timeout := time.After(5 * time.Second)
+	for {
+		result, err := a.GetRun(id)
+		c.Assert(err, check.IsNil)
+		if result.Status != RunStatusRunning {
+			return result
+		}
+		select {
+		case <-timeout:
+			c.Fatalf("timeout waiting for run %q to finish", id)
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+}
+
+func (s *S) TestRunDoneExitCode(c *check.C) {
+	a := App{Name: "myapp", TeamOwner: s.team.Name}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	evt := s.newRunEvent(c, &a, "false")
+	evt.Write([]byte("something failed\n"))
+	err = RunDone(evt, errors.WithStack(fakeExitError{code: 3}))
+	c.Assert(err, check.IsNil)
+	result, err := a.GetRun(evt.UniqueID.Hex())
+	c.Assert(err, check.IsNil)
+	c.Assert(result.ID, check.Equals, evt.UniqueID.Hex())
+	c.Assert(result.Command, check.Equals, "false")
+	c.Assert(result.Status, check.Equals, RunStatusFailed)
+	c.Assert(result.ExitCode, check.NotNil)
+	c.Assert(*result.ExitCode, check.Equals, 3)
+	c.Assert(result.Output, check.Matches, `(?s).*something failed\n`)
+	c.Assert(result.EndTime.IsZero(), check.Equals, false)
+}
+
+func (s *S) TestRunDoneUnknownExitCode(c *check.C) {
+	a := App{Name: "myapp", TeamOwner: s.team.Name}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	evt := s.newRunEvent(c, &a, "ls")
+	err = RunDone(evt, errors.New("unit not found"))
+	c.Assert(err, check.IsNil)
+	result, err := a.GetRun(evt.UniqueID.Hex())
+	c.Assert(err, check.IsNil)
+	c.Assert(result.Status, check.Equals, RunStatusFailed)
+	c.Assert(result.Error, check.Equals, "unit not found")
+	c.Assert(result.ExitCode, check.IsNil)
+}
+
+func (s *S) TestGetRunRunning(c *check.C) {
+	a := App{Name: "myapp", TeamOwner: s.team.Name}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	evt := s.newRunEvent(c, &a, "sleep 100")
+	defer evt.Abort()
+	result, err := a.GetRun(evt.UniqueID.Hex())
+	c.Assert(err, check.IsNil)
+	c.Assert(result.Status, check.Equals, RunStatusRunning)
+	c.Assert(result.ExitCode, check.IsNil)
+}
+
+func (s *S) TestGetRunNotFound(c *check.C) {
+	a := App{Name: "myapp", TeamOwner: s.team.Name}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	other := App{Name: "otherapp", TeamOwner: s.team.Name}
+	err = CreateApp(context.TODO(), &other, s.user)
+	c.Assert(err, check.IsNil)
+	evt := s.newRunEvent(c, &other, "ls")
+	err = RunDone(evt, nil)
+	c.Assert(err, check.IsNil)
+	_, err = a.GetRun(evt.UniqueID.Hex())
+	c.Assert(err, check.Equals, ErrRunNotFound)
+	deployEvt := s.newAppEvent(c, &a, permission.PermAppDeploy)
+	err = deployEvt.Done(nil)
+	c.Assert(err, check.IsNil)
+	_, err = a.GetRun(deployEvt.UniqueID.Hex())
+	c.Assert(err, check.Equals, ErrRunNotFound)
+	_, err = a.GetRun("5fa4d5a7a0fab4a1ad4d5ff2")
+	c.Assert(err, check.Equals, ErrRunNotFound)
+}
+
+func (s *S) TestRunDetached(c *check.C) {
+	s.provisioner.PrepareOutput([]byte("a lot of files"))
+	a := App{Name: "myapp", TeamOwner: s.team.Name}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	s.provisioner.AddUnits(context.TODO(), &a, 1, "web", newSuccessfulAppVersion(c, &a), nil)
+	evt := s.newRunEvent(c, &a, "ls -lh")
+	err = a.RunDetached("ls -lh", evt, provision.RunArgs{Once: true})
+	c.Assert(err, check.IsNil)
+	result := waitRunFinished(c, &a, evt.UniqueID.Hex())
+	c.Assert(result.Status, check.Equals, RunStatusSucceeded)
+	c.Assert(result.Command, check.Equals, "ls -lh")
+	c.Assert(result.ExitCode, check.NotNil)
+	c.Assert(*result.ExitCode, check.Equals, 0)
+	c.Assert(result.Output, check.Matches, `(?s).*a lot of files.*`)
+}
+
+func (s *S) TestRunDetachedUnavailableApp(c *check.C) {
+	a := App{Name: "myapp", TeamOwner: s.team.Name}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	evt := s.newRunEvent(c, &a, "ls")
+	err = a.RunDetached("ls", evt, provision.RunArgs{})
+	c.Assert(err, check.ErrorMatches, "App must be available to run non-isolated commands")
+	result, err := a.GetRun(evt.UniqueID.Hex())
+	c.Assert(err, check.IsNil)
+	c.Assert(result.Status, check.Equals, RunStatusFailed)
+}
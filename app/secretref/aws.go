@@ -0,0 +1,85 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package secretref
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/pkg/errors"
+	"github.com/tsuru/config"
+	tsuruNet "github.com/tsuru/tsuru/net"
+)
+
+func init() {
+	Register("aws", &awsProvider{})
+}
+
+// awsProvider reads secrets from AWS Secrets Manager, references are in the
+// form "aws:<secret-id>" or "aws:<secret-id>#<key>" for secrets holding a
+// JSON object.
+type awsProvider struct{}
+
+func (p *awsProvider) Validate(ref Reference) error {
+	if region, _ := config.GetString("secret-managers:aws:region"); region == "" {
+		return errors.New("aws secret manager is not configured")
+	}
+	return nil
+}
+
+func (p *awsProvider) client() (*secretsmanager.SecretsManager, error) {
+	region, _ := config.GetString("secret-managers:aws:region")
+	if region == "" {
+		return nil, errors.New("aws secret manager is not configured")
+	}
+	cfg := aws.Config{
+		Region:     aws.String(region),
+		HTTPClient: tsuruNet.Dial15Full60ClientNoKeepAlive,
+	}
+	if endpoint, _ := config.GetString("secret-managers:aws:endpoint"); endpoint != "" {
+		cfg.Endpoint = aws.String(endpoint)
+	}
+	keyID, _ := config.GetString("secret-managers:aws:key-id")
+	secretKey, _ := config.GetString("secret-managers:aws:secret-key")
+	if keyID != "" && secretKey != "" {
+		cfg.Credentials = credentials.NewStaticCredentials(keyID, secretKey, "")
+	}
+	sess, err := session.NewSession(&cfg)
+	if err != nil {
+		return nil, err
+	}
+	return secretsmanager.New(sess), nil
+}
+
+func (p *awsProvider) Resolve(ctx context.Context, ref Reference) (string, error) {
+	cli, err := p.client()
+	if err != nil {
+		return "", err
+	}
+	output, err := cli.GetSecretValueWithContext(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(ref.Path),
+	})
+	if err != nil {
+		return "", err
+	}
+	secret := aws.StringValue(output.SecretString)
+	if ref.Key == "" {
+		return secret, nil
+	}
+	var data map[string]interface{}
+	err = json.Unmarshal([]byte(secret), &data)
+	if err != nil {
+		return "", errors.Wrap(err, "secret is not a JSON object")
+	}
+	value, ok := data[ref.Key]
+	if !ok {
+		return "", errors.Errorf("key %q not found in secret", ref.Key)
+	}
+	return stringValue(value), nil
+}
@@ -0,0 +1,165 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package secretref handles env var values referencing secrets stored in
+// external secret managers, like "vault:secret/db#password". Only the
+// reference is stored by tsuru, the secret value is resolved by provisioners
+// when the app units are created.
+//
+// References are only accepted under the path prefix configured for each
+// provider in "secret-managers:<provider>:path-prefix", which may contain the
+// {team} and {pool} placeholders, so apps can't read secrets belonging to
+// other teams with the credentials used by tsuru.
+package secretref
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/tsuru/config"
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+)
+
+// Reference points to a secret stored in an external secret manager.
+type Reference struct {
+	Provider string
+	Path     string
+	Key      string
+}
+
+func (r Reference) String() string {
+	if r.Key == "" {
+		return fmt.Sprintf("%s:%s", r.Provider, r.Path)
+	}
+	return fmt.Sprintf("%s:%s#%s", r.Provider, r.Path, r.Key)
+}
+
+// Scope identifies the app using a reference, it's used to build the path
+// prefix allowed for the reference.
+type Scope struct {
+	Team string
+	Pool string
+}
+
+// Provider resolves references to secrets stored in a secret manager.
+type Provider interface {
+	Validate(ref Reference) error
+	Resolve(ctx context.Context, ref Reference) (string, error)
+}
+
+var (
+	providersMu sync.RWMutex
+	providers   = map[string]Provider{}
+)
+
+// Register registers a secret manager provider, values prefixed by
+// "<name>:" are handled as references to secrets in this provider.
+func Register(name string, p Provider) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	providers[name] = p
+}
+
+// Unregister removes a provider from the registry.
+func Unregister(name string) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	delete(providers, name)
+}
+
+func getProvider(name string) (Provider, bool) {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+	p, ok := providers[name]
+	return p, ok
+}
+
+// Parse returns the reference in the value. The returned bool is false when
+// the value does not reference a registered provider.
+func Parse(value string) (Reference, bool) {
+	parts := strings.SplitN(value, ":", 2)
+	if len(parts) != 2 {
+		return Reference{}, false
+	}
+	if _, ok := getProvider(parts[0]); !ok {
+		return Reference{}, false
+	}
+	ref := Reference{Provider: parts[0], Path: parts[1]}
+	if idx := strings.LastIndex(ref.Path, "#"); idx >= 0 {
+		ref.Key = ref.Path[idx+1:]
+		ref.Path = ref.Path[:idx]
+	}
+	return ref, true
+}
+
+// Validate checks whether the value is a well formed reference allowed for
+// the scope.
+func Validate(value string, scope Scope) error {
+	ref, ok := Parse(value)
+	if !ok {
+		return &tsuruErrors.ValidationError{Message: fmt.Sprintf("invalid secret reference %q: unknown secret manager", value)}
+	}
+	if ref.Path == "" {
+		return &tsuruErrors.ValidationError{Message: fmt.Sprintf("invalid secret reference %q: missing path", value)}
+	}
+	if err := checkPrefix(ref, scope); err != nil {
+		return &tsuruErrors.ValidationError{Message: fmt.Sprintf("invalid secret reference %q: %v", value, err)}
+	}
+	p, _ := getProvider(ref.Provider)
+	if err := p.Validate(ref); err != nil {
+		return &tsuruErrors.ValidationError{Message: fmt.Sprintf("invalid secret reference %q: %v", value, err)}
+	}
+	return nil
+}
+
+// Resolve returns the secret referenced by the value. The path prefix is
+// checked again, as the app may have changed its team or pool since the
+// reference was stored.
+func Resolve(ctx context.Context, value string, scope Scope) (string, error) {
+	ref, ok := Parse(value)
+	if !ok {
+		return "", errors.Errorf("invalid secret reference %q: unknown secret manager", value)
+	}
+	if err := checkPrefix(ref, scope); err != nil {
+		return "", errors.Wrapf(err, "unable to resolve secret %q", ref)
+	}
+	p, _ := getProvider(ref.Provider)
+	secret, err := p.Resolve(ctx, ref)
+	if err != nil {
+		return "", errors.Wrapf(err, "unable to resolve secret %q", ref)
+	}
+	return secret, nil
+}
+
+// checkPrefix ensures the reference path is under the prefix configured for
+// its provider, a "/" prefix allows any path. Paths with relative segments or
+// characters changing how the path is interpreted by the provider API are
+// refused.
+func checkPrefix(ref Reference, scope Scope) error {
+	prefix, _ := config.GetString("secret-managers:" + ref.Provider + ":path-prefix")
+	if prefix == "" {
+		return errors.Errorf("%s secret manager has no path prefix configured", ref.Provider)
+	}
+	if (strings.Contains(prefix, "{team}") && scope.Team == "") || (strings.Contains(prefix, "{pool}") && scope.Pool == "") {
+		return errors.New("app team and pool are required to check the path prefix")
+	}
+	prefix = strings.NewReplacer("{team}", scope.Team, "{pool}", scope.Pool).Replace(prefix)
+	prefix = strings.Trim(prefix, "/")
+	path := strings.TrimLeft(ref.Path, "/")
+	if strings.ContainsAny(path, "?%\\") {
+		return errors.New("path contains invalid characters")
+	}
+	for _, segment := range strings.Split(path, "/") {
+		if segment == "." || segment == ".." {
+			return errors.New("path must not contain relative segments")
+		}
+	}
+	if prefix != "" && path != prefix && !strings.HasPrefix(path, prefix+"/") {
+		return errors.Errorf("path is not under the allowed prefix %q", prefix)
+	}
+	return nil
+}
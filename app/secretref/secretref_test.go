@@ -0,0 +1,144 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package secretref
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/tsuru/config"
+	check "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) { check.TestingT(t) }
+
+type S struct{}
+
+var _ = check.Suite(&S{})
+
+func (s *S) TearDownTest(c *check.C) {
+	config.Unset("secret-managers")
+}
+
+func (s *S) TestParse(c *check.C) {
+	tests := []struct {
+		value string
+		ref   Reference
+		ok    bool
+	}{
+		{value: "vault:secret/db#password", ref: Reference{Provider: "vault", Path: "secret/db", Key: "password"}, ok: true},
+		{value: "aws:prod/db", ref: Reference{Provider: "aws", Path: "prod/db"}, ok: true},
+		{value: "aws:prod/db#user", ref: Reference{Provider: "aws", Path: "prod/db", Key: "user"}, ok: true},
+		{value: "postgres://user:pass@db:5432/app"},
+		{value: "plain"},
+		{value: ""},
+	}
+	for _, tt := range tests {
+		ref, ok := Parse(tt.value)
+		c.Check(ok, check.Equals, tt.ok, check.Commentf("value: %q", tt.value))
+		c.Check(ref, check.DeepEquals, tt.ref, check.Commentf("value: %q", tt.value))
+	}
+}
+
+func (s *S) TestValidate(c *check.C) {
+	scope := Scope{Team: "myteam", Pool: "mypool"}
+	err := Validate("plain", scope)
+	c.Assert(err, check.ErrorMatches, `invalid secret reference "plain": unknown secret manager`)
+	err = Validate("vault:secret/myteam/db#password", scope)
+	c.Assert(err, check.ErrorMatches, `invalid secret reference "vault:secret/myteam/db#password": vault secret manager has no path prefix configured`)
+	config.Set("secret-managers:vault:path-prefix", "secret/{team}")
+	err = Validate("vault:secret/myteam/db#password", scope)
+	c.Assert(err, check.ErrorMatches, `invalid secret reference "vault:secret/myteam/db#password": vault secret manager is not configured`)
+	config.Set("secret-managers:vault:address", "http://vault:8200")
+	config.Set("secret-managers:vault:token", "t0k3n")
+	c.Assert(Validate("vault:secret/myteam/db#password", scope), check.IsNil)
+	err = Validate("vault:secret/myteam/db", scope)
+	c.Assert(err, check.ErrorMatches, `invalid secret reference "vault:secret/myteam/db": missing secret key`)
+	err = Validate("vault:#password", scope)
+	c.Assert(err, check.ErrorMatches, `invalid secret reference "vault:#password": missing path`)
+}
+
+func (s *S) TestValidateOutsidePrefix(c *check.C) {
+	config.Set("secret-managers:vault:address", "http://vault:8200")
+	config.Set("secret-managers:vault:token", "t0k3n")
+	config.Set("secret-managers:vault:path-prefix", "secret/{pool}/{team}/")
+	config.Set("secret-managers:aws:region", "us-east-1")
+	config.Set("secret-managers:aws:path-prefix", "tsuru/{team}")
+	scope := Scope{Team: "myteam", Pool: "mypool"}
+	c.Assert(Validate("vault:secret/mypool/myteam/db#password", scope), check.IsNil)
+	c.Assert(Validate("vault:/secret/mypool/myteam/db#password", scope), check.IsNil)
+	c.Assert(Validate("aws:tsuru/myteam/db", scope), check.IsNil)
+	tests := []struct {
+		value string
+		err   string
+	}{
+		{value: "vault:secret/mypool/otherteam/db#password", err: `path is not under the allowed prefix "secret/mypool/myteam"`},
+		{value: "vault:secret/mypool/myteam-other/db#password", err: `path is not under the allowed prefix "secret/mypool/myteam"`},
+		{value: "vault:secret/otherpool/myteam/db#password", err: `path is not under the allowed prefix "secret/mypool/myteam"`},
+		{value: "vault:secret/mypool/myteam/../other/db#password", err: `path must not contain relative segments`},
+		{value: "vault:secret/mypool/myteam/%2e%2e/db#password", err: `path contains invalid characters`},
+		{value: "vault:secret/mypool/myteam/db?version=1#password", err: `path contains invalid characters`},
+		{value: "aws:prod/db", err: `path is not under the allowed prefix "tsuru/myteam"`},
+	}
+	for _, tt := range tests {
+		err := Validate(tt.value, scope)
+		c.Check(err, check.ErrorMatches, regexp.QuoteMeta(fmt.Sprintf("invalid secret reference %q: %s", tt.value, tt.err)))
+	}
+	err := Validate("vault:secret/mypool/db#password", Scope{Pool: "mypool"})
+	c.Assert(err, check.ErrorMatches, `.*app team and pool are required to check the path prefix`)
+}
+
+func (s *S) TestResolveVault(c *check.C) {
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.Header.Get("X-Vault-Token"), check.Equals, "t0k3n")
+		paths = append(paths, r.URL.Path)
+		switch r.URL.Path {
+		case "/v1/kv/data/db":
+			w.Write([]byte(`{"data": {"data": {"password": "s3cr3t"}, "metadata": {"version": 1}}}`))
+		case "/v1/secret/db":
+			w.Write([]byte(`{"data": {"password": "0ld", "port": 5432}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	config.Set("secret-managers:vault:address", srv.URL)
+	config.Set("secret-managers:vault:token", "t0k3n")
+	config.Set("secret-managers:vault:path-prefix", "/")
+	scope := Scope{Team: "myteam", Pool: "mypool"}
+	value, err := Resolve(context.TODO(), "vault:kv/data/db#password", scope)
+	c.Assert(err, check.IsNil)
+	c.Assert(value, check.Equals, "s3cr3t")
+	value, err = Resolve(context.TODO(), "vault:secret/db#password", scope)
+	c.Assert(err, check.IsNil)
+	c.Assert(value, check.Equals, "0ld")
+	value, err = Resolve(context.TODO(), "vault:secret/db#port", scope)
+	c.Assert(err, check.IsNil)
+	c.Assert(value, check.Equals, "5432")
+	_, err = Resolve(context.TODO(), "vault:secret/db#user", scope)
+	c.Assert(err, check.ErrorMatches, `unable to resolve secret "vault:secret/db#user": key "user" not found in secret`)
+	_, err = Resolve(context.TODO(), "vault:secret/other#user", scope)
+	c.Assert(err, check.ErrorMatches, `unable to resolve secret "vault:secret/other#user": invalid response from vault \(404\).*`)
+	c.Assert(paths, check.DeepEquals, []string{"/v1/kv/data/db", "/v1/secret/db", "/v1/secret/db", "/v1/secret/db", "/v1/secret/other"})
+	_, err = Resolve(context.TODO(), "plain", scope)
+	c.Assert(err, check.ErrorMatches, `invalid secret reference "plain": unknown secret manager`)
+}
+
+func (s *S) TestResolveOutsidePrefix(c *check.C) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.Errorf("unexpected request to %q", r.URL.Path)
+	}))
+	defer srv.Close()
+	config.Set("secret-managers:vault:address", srv.URL)
+	config.Set("secret-managers:vault:token", "t0k3n")
+	config.Set("secret-managers:vault:path-prefix", "secret/{team}")
+	_, err := Resolve(context.TODO(), "vault:secret/otherteam/db#password", Scope{Team: "myteam", Pool: "mypool"})
+	c.Assert(err, check.ErrorMatches, `unable to resolve secret "vault:secret/otherteam/db#password": path is not under the allowed prefix "secret/myteam"`)
+}
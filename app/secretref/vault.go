@@ -0,0 +1,94 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package secretref
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/tsuru/config"
+	tsuruNet "github.com/tsuru/tsuru/net"
+)
+
+func init() {
+	Register("vault", &vaultProvider{})
+}
+
+// vaultProvider reads secrets from the KV secrets engine of HashiCorp Vault,
+// references are in the form "vault:<path>#<key>".
+type vaultProvider struct{}
+
+func (p *vaultProvider) config() (string, string, error) {
+	address, _ := config.GetString("secret-managers:vault:address")
+	token, _ := config.GetString("secret-managers:vault:token")
+	if address == "" || token == "" {
+		return "", "", errors.New("vault secret manager is not configured")
+	}
+	return strings.TrimRight(address, "/"), token, nil
+}
+
+func (p *vaultProvider) Validate(ref Reference) error {
+	if ref.Key == "" {
+		return errors.New("missing secret key")
+	}
+	_, _, err := p.config()
+	return err
+}
+
+func (p *vaultProvider) Resolve(ctx context.Context, ref Reference) (string, error) {
+	address, token, err := p.config()
+	if err != nil {
+		return "", err
+	}
+	url := fmt.Sprintf("%s/v1/%s", address, strings.TrimLeft(ref.Path, "/"))
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("X-Vault-Token", token)
+	rsp, err := tsuruNet.Dial15Full60ClientNoKeepAlive.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer rsp.Body.Close()
+	if rsp.StatusCode != http.StatusOK {
+		data, _ := ioutil.ReadAll(rsp.Body)
+		return "", errors.Errorf("invalid response from vault (%d): %s", rsp.StatusCode, string(data))
+	}
+	var result struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	err = json.NewDecoder(rsp.Body).Decode(&result)
+	if err != nil {
+		return "", errors.Wrap(err, "unable to decode vault response")
+	}
+	data := result.Data
+	// secrets from the version 2 of the KV engine are wrapped along with
+	// their metadata.
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		if _, ok = data["metadata"]; ok {
+			data = nested
+		}
+	}
+	value, ok := data[ref.Key]
+	if !ok {
+		return "", errors.Errorf("key %q not found in secret", ref.Key)
+	}
+	return stringValue(value), nil
+}
+
+func stringValue(value interface{}) string {
+	if str, ok := value.(string); ok {
+		return str
+	}
+	data, _ := json.Marshal(value)
+	return string(data)
+}
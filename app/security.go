@@ -5,11 +5,20 @@
 package app
 
 import (
+	"github.com/globalsign/mgo/bson"
+	"github.com/tsuru/config"
 	"github.com/tsuru/tsuru/app/bind"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/permission"
 )
 
 var SuppressedEnv = "*** (private variable)"
 
+func init() {
+	event.RegisterRedactor(permission.PermAppDeploy.FullName(), suppressSensitiveEnvs)
+	event.RegisterRedactor(permission.PermAppUpdateBind.FullName(), event.FormFieldsRedactor("parameters"))
+}
+
 func (a *App) SuppressSensitiveEnvs() {
 	newEnv := map[string]bind.EnvVar{}
 	for key, env := range a.Env {
@@ -26,3 +35,30 @@ func (a *App) SuppressSensitiveEnvs() {
 		}
 	}
 }
+
+func suppressSensitiveEnvs(e *event.Event) error {
+	if supressEnabled, _ := config.GetBool("events:suppress-sensitive-envs"); !supressEnabled {
+		return nil
+	}
+	if e.Kind.Name != permission.PermAppDeploy.FullName() || len(e.StartCustomData.Data) == 0 {
+		return nil
+	}
+
+	deployOptions := &DeployOptions{}
+	err := bson.Unmarshal(e.StartCustomData.Data, deployOptions)
+	if err != nil {
+		return err
+	}
+
+	if deployOptions.App == nil {
+		return nil
+	}
+
+	deployOptions.App.SuppressSensitiveEnvs()
+
+	e.StartCustomData.Data, err = bson.Marshal(deployOptions)
+	if err != nil {
+		return err
+	}
+	return nil
+}
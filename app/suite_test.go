@@ -106,6 +106,7 @@ func (s *S) SetUpSuite(c *check.C) {
 	config.Set("docker:registry", "registry.somewhere")
 	config.Set("routers:fake-tls:type", "fake-tls")
 	config.Set("routers:fake-v2:type", "fake-v2")
+	config.Set("routers:fake-maintenance:type", "fake-maintenance")
 	config.Set("auth:hash-cost", bcrypt.MinCost)
 	s.conn, err = db.Conn()
 	c.Assert(err, check.IsNil)
@@ -131,12 +132,14 @@ func (s *S) SetUpTest(c *check.C) {
 	routertest.HCRouter.Reset()
 	routertest.TLSRouter.Reset()
 	routertest.OptsRouter.Reset()
+	routertest.MaintenanceRouter.Reset()
 	queue.ResetQueue()
 	rebuild.Shutdown(context.Background())
 	routertest.FakeRouter.Reset()
 	routertest.HCRouter.Reset()
 	routertest.TLSRouter.Reset()
 	routertest.OptsRouter.Reset()
+	routertest.MaintenanceRouter.Reset()
 	pool.ResetCache()
 	err := rebuild.Initialize(func(appName string) (rebuild.RebuildApp, error) {
 		a, err := GetByName(context.TODO(), appName)
@@ -0,0 +1,71 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/globalsign/mgo/bson"
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+	appTypes "github.com/tsuru/tsuru/types/app"
+)
+
+// ParseTagSelector parses a comma separated list of tag requirements, like
+// "env=prod,team!=x". Each requirement may be in the form "key=value",
+// "key!=value", "key", matching apps with the tag regardless of its value,
+// or "!key", matching apps without the tag.
+func ParseTagSelector(selector string) ([]appTypes.TagRequirement, error) {
+	var requirements []appTypes.TagRequirement
+	for _, part := range strings.Split(selector, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		var req appTypes.TagRequirement
+		switch {
+		case strings.Contains(part, "!="):
+			parts := strings.SplitN(part, "!=", 2)
+			req = appTypes.TagRequirement{Key: parts[0], Operator: appTypes.TagNotEquals, Value: parts[1]}
+		case strings.Contains(part, "=="):
+			parts := strings.SplitN(part, "==", 2)
+			req = appTypes.TagRequirement{Key: parts[0], Operator: appTypes.TagEquals, Value: parts[1]}
+		case strings.Contains(part, "="):
+			parts := strings.SplitN(part, "=", 2)
+			req = appTypes.TagRequirement{Key: parts[0], Operator: appTypes.TagEquals, Value: parts[1]}
+		case strings.HasPrefix(part, "!"):
+			req = appTypes.TagRequirement{Key: part[1:], Operator: appTypes.TagDoesNotExist}
+		default:
+			req = appTypes.TagRequirement{Key: part, Operator: appTypes.TagExists}
+		}
+		req.Key = strings.TrimSpace(req.Key)
+		req.Value = strings.TrimSpace(req.Value)
+		if req.Key == "" {
+			return nil, &tsuruErrors.ValidationError{Message: fmt.Sprintf("invalid tag selector %q: missing key", part)}
+		}
+		requirements = append(requirements, req)
+	}
+	return requirements, nil
+}
+
+func tagRequirementQuery(r appTypes.TagRequirement) bson.M {
+	tag := r.Key + "=" + r.Value
+	// matches tags with the requirement key, with or without value.
+	keyMatcher := []interface{}{
+		r.Key,
+		bson.RegEx{Pattern: "^" + regexp.QuoteMeta(r.Key) + "="},
+	}
+	switch r.Operator {
+	case appTypes.TagEquals:
+		return bson.M{"tags": tag}
+	case appTypes.TagNotEquals:
+		return bson.M{"tags": bson.M{"$ne": tag}}
+	case appTypes.TagDoesNotExist:
+		return bson.M{"tags": bson.M{"$nin": keyMatcher}}
+	default:
+		return bson.M{"tags": bson.M{"$in": keyMatcher}}
+	}
+}
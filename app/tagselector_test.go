@@ -0,0 +1,71 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"context"
+	"sort"
+
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+	appTypes "github.com/tsuru/tsuru/types/app"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestParseTagSelector(c *check.C) {
+	reqs, err := ParseTagSelector("env=prod, team!=x,tier==web,monitored,!deprecated")
+	c.Assert(err, check.IsNil)
+	c.Assert(reqs, check.DeepEquals, []appTypes.TagRequirement{
+		{Key: "env", Operator: appTypes.TagEquals, Value: "prod"},
+		{Key: "team", Operator: appTypes.TagNotEquals, Value: "x"},
+		{Key: "tier", Operator: appTypes.TagEquals, Value: "web"},
+		{Key: "monitored", Operator: appTypes.TagExists},
+		{Key: "deprecated", Operator: appTypes.TagDoesNotExist},
+	})
+	reqs, err = ParseTagSelector("")
+	c.Assert(err, check.IsNil)
+	c.Assert(reqs, check.IsNil)
+}
+
+func (s *S) TestParseTagSelectorInvalid(c *check.C) {
+	for _, selector := range []string{"=prod", "!=x", "!", "env=prod,=x"} {
+		_, err := ParseTagSelector(selector)
+		c.Assert(err, check.FitsTypeOf, &tsuruErrors.ValidationError{}, check.Commentf("selector %q", selector))
+	}
+}
+
+func (s *S) TestListFilteringByTagSelector(c *check.C) {
+	apps := []App{
+		{Name: "app1", TeamOwner: s.team.Name, Tags: []string{"env=prod", "team=x", "monitored"}},
+		{Name: "app2", TeamOwner: s.team.Name, Tags: []string{"env=prod", "team=y", "monitored=true"}},
+		{Name: "app3", TeamOwner: s.team.Name, Tags: []string{"env=dev", "deprecated"}},
+	}
+	for i := range apps {
+		err := CreateApp(context.TODO(), &apps[i], s.user)
+		c.Assert(err, check.IsNil)
+	}
+	tests := []struct {
+		selector string
+		expected []string
+	}{
+		{selector: "env=prod", expected: []string{"app1", "app2"}},
+		{selector: "env=prod,team!=x", expected: []string{"app2"}},
+		{selector: "monitored", expected: []string{"app1", "app2"}},
+		{selector: "!deprecated", expected: []string{"app1", "app2"}},
+		{selector: "!monitored", expected: []string{"app3"}},
+		{selector: "env=staging", expected: nil},
+	}
+	for _, tt := range tests {
+		reqs, err := ParseTagSelector(tt.selector)
+		c.Assert(err, check.IsNil)
+		result, err := List(context.TODO(), &Filter{TagSelector: reqs})
+		c.Assert(err, check.IsNil)
+		var names []string
+		for _, a := range result {
+			names = append(names, a.Name)
+		}
+		sort.Strings(names)
+		c.Assert(names, check.DeepEquals, tt.expected, check.Commentf("selector %q", tt.selector))
+	}
+}
@@ -0,0 +1,200 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"context"
+
+	"github.com/globalsign/mgo/bson"
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/db"
+	"github.com/tsuru/tsuru/servicemanager"
+	appTypes "github.com/tsuru/tsuru/types/app"
+	authTypes "github.com/tsuru/tsuru/types/auth"
+	"github.com/tsuru/tsuru/types/quota"
+)
+
+const (
+	// TeamQuotaUnitApps makes each app consume one unit of the team quota.
+	TeamQuotaUnitApps = "apps"
+	// TeamQuotaUnitMemory makes each app consume the memory of its plan, in
+	// megabytes, from the team quota.
+	TeamQuotaUnitMemory = "memory"
+	// TeamQuotaUnitCPU makes each app consume the CPU of its plan, in
+	// millicores, from the team quota.
+	TeamQuotaUnitCPU = "cpu"
+)
+
+// TeamQuotaUnit returns the unit in which team quotas are expressed.
+func TeamQuotaUnit() string {
+	unit, _ := config.GetString("quota:teams:unit")
+	switch unit {
+	case TeamQuotaUnitMemory, TeamQuotaUnitCPU:
+		return unit
+	}
+	return TeamQuotaUnitApps
+}
+
+// TeamQuotaItem returns the quota item of the team, whose quota usage is
+// computed from the plans of the team apps when team quotas are expressed in
+// resource units.
+func TeamQuotaItem(teamName string) quota.QuotaItem {
+	unit := TeamQuotaUnit()
+	if unit == TeamQuotaUnitApps {
+		return &authTypes.Team{Name: teamName}
+	}
+	return &teamQuotaItem{name: teamName, unit: unit}
+}
+
+type teamQuotaItem struct {
+	name string
+	unit string
+}
+
+func (t *teamQuotaItem) GetName() string {
+	return t.name
+}
+
+func (t *teamQuotaItem) GetQuotaInUse() (int, error) {
+	conn, err := db.Conn()
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+	var apps []App
+	err = conn.Apps().Find(bson.M{"teamowner": t.name}).Select(bson.M{"plan": 1}).All(&apps)
+	if err != nil {
+		return 0, err
+	}
+	inUse := 0
+	for _, a := range apps {
+		inUse += planQuotaWeight(t.unit, a.Plan)
+	}
+	return inUse, nil
+}
+
+// planQuotaWeight returns how much of the team quota is consumed by an app
+// using the plan. Plans without limits consume a single unit.
+func planQuotaWeight(unit string, plan appTypes.Plan) int {
+	var weight int
+	switch unit {
+	case TeamQuotaUnitMemory:
+		weight = int(plan.Memory / (1024 * 1024))
+	case TeamQuotaUnitCPU:
+		weight = plan.CPUMilli
+	}
+	if weight < 1 {
+		weight = 1
+	}
+	return weight
+}
+
+// reserveTeamQuota reserves the team quota consumed by the app.
+func reserveTeamQuota(ctx context.Context, teamName string, plan appTypes.Plan) error {
+	unit := TeamQuotaUnit()
+	weight := planQuotaWeight(unit, plan)
+	err := checkAncestorTeamQuotas(ctx, teamName, weight)
+	if err != nil {
+		return err
+	}
+	return servicemanager.TeamQuota.Inc(ctx, TeamQuotaItem(teamName), weight)
+}
+
+// checkAncestorTeamQuotas checks whether the quotas of the teams the team is
+// nested in allow using delta more units. The quota of a parent team limits
+// the combined usage of the parent and all its descendants.
+func checkAncestorTeamQuotas(ctx context.Context, teamName string, delta int) error {
+	ancestors, err := servicemanager.Team.FindAncestors(ctx, teamName)
+	if err != nil {
+		return err
+	}
+	for _, ancestor := range ancestors {
+		q, err := servicemanager.TeamQuota.Get(ctx, TeamQuotaItem(ancestor.Name))
+		if err != nil {
+			return err
+		}
+		if q.IsUnlimited() {
+			continue
+		}
+		descendants, err := servicemanager.Team.FindDescendants(ctx, ancestor.Name)
+		if err != nil {
+			return err
+		}
+		inUse := q.InUse
+		for _, descendant := range descendants {
+			dq, err := servicemanager.TeamQuota.Get(ctx, TeamQuotaItem(descendant.Name))
+			if err != nil {
+				return err
+			}
+			inUse += dq.InUse
+		}
+		if inUse+delta > q.Limit {
+			available := q.Limit - inUse
+			if available < 0 {
+				available = 0
+			}
+			return &quota.QuotaExceededError{
+				Available: uint(available),
+				Requested: uint(delta),
+			}
+		}
+	}
+	return nil
+}
+
+// releaseTeamQuota releases the team quota consumed by the app. The usage of
+// quotas in resource units is computed from the team apps, so there's
+// nothing to release.
+func releaseTeamQuota(ctx context.Context, teamName string) error {
+	if TeamQuotaUnit() != TeamQuotaUnitApps {
+		return nil
+	}
+	return servicemanager.TeamQuota.Inc(ctx, TeamQuotaItem(teamName), -1)
+}
+
+// checkTeamQuotaChange checks whether the team quota allows the app to change
+// its plan or team owner, reserving the difference unless dryRun is set.
+func (app *App) checkTeamQuotaChange(ctx context.Context, oldApp *App, dryRun bool) error {
+	unit := TeamQuotaUnit()
+	if unit == TeamQuotaUnitApps {
+		return nil
+	}
+	weight := planQuotaWeight(unit, app.Plan)
+	delta := weight
+	if app.TeamOwner == oldApp.TeamOwner {
+		delta = weight - planQuotaWeight(unit, oldApp.Plan)
+	}
+	if delta <= 0 {
+		return nil
+	}
+	err := checkAncestorTeamQuotas(ctx, app.TeamOwner, delta)
+	if err != nil {
+		return err
+	}
+	if dryRun {
+		return checkQuota(ctx, servicemanager.TeamQuota, TeamQuotaItem(app.TeamOwner), delta)
+	}
+	return servicemanager.TeamQuota.Inc(ctx, TeamQuotaItem(app.TeamOwner), delta)
+}
+
+// checkQuota checks whether the quota of item allows using delta more units,
+// without reserving them.
+func checkQuota(ctx context.Context, service quota.QuotaService, item quota.QuotaItem, delta int) error {
+	q, err := service.Get(ctx, item)
+	if err != nil {
+		return err
+	}
+	if !q.IsUnlimited() && q.InUse+delta > q.Limit {
+		available := q.Limit - q.InUse
+		if available < 0 {
+			available = 0
+		}
+		return &quota.QuotaExceededError{
+			Available: uint(available),
+			Requested: uint(delta),
+		}
+	}
+	return nil
+}
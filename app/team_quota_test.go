@@ -0,0 +1,138 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/tsuru/config"
+	appTypes "github.com/tsuru/tsuru/types/app"
+	authTypes "github.com/tsuru/tsuru/types/auth"
+	"github.com/tsuru/tsuru/types/quota"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestTeamQuotaItem(c *check.C) {
+	item := TeamQuotaItem("team1")
+	c.Assert(item, check.DeepEquals, &authTypes.Team{Name: "team1"})
+	config.Set("quota:teams:unit", "memory")
+	defer config.Unset("quota:teams:unit")
+	item = TeamQuotaItem("team1")
+	c.Assert(item, check.DeepEquals, &teamQuotaItem{name: "team1", unit: TeamQuotaUnitMemory})
+	config.Set("quota:teams:unit", "invalid")
+	c.Assert(TeamQuotaUnit(), check.Equals, TeamQuotaUnitApps)
+}
+
+func (s *S) TestPlanQuotaWeight(c *check.C) {
+	plan := appTypes.Plan{Memory: 512 * 1024 * 1024, CPUMilli: 250}
+	c.Assert(planQuotaWeight(TeamQuotaUnitApps, plan), check.Equals, 1)
+	c.Assert(planQuotaWeight(TeamQuotaUnitMemory, plan), check.Equals, 512)
+	c.Assert(planQuotaWeight(TeamQuotaUnitCPU, plan), check.Equals, 250)
+	c.Assert(planQuotaWeight(TeamQuotaUnitMemory, appTypes.Plan{}), check.Equals, 1)
+	c.Assert(planQuotaWeight(TeamQuotaUnitCPU, appTypes.Plan{}), check.Equals, 1)
+}
+
+func (s *S) TestTeamQuotaItemInUse(c *check.C) {
+	s.plan = appTypes.Plan{Name: "large", Memory: 1024 * 1024 * 1024, CPUMilli: 1000}
+	a1 := App{Name: "app1", TeamOwner: s.team.Name, Plan: appTypes.Plan{Name: "large"}}
+	err := CreateApp(context.TODO(), &a1, s.user)
+	c.Assert(err, check.IsNil)
+	a2 := App{Name: "app2", TeamOwner: s.team.Name}
+	err = CreateApp(context.TODO(), &a2, s.user)
+	c.Assert(err, check.IsNil)
+	item := &teamQuotaItem{name: s.team.Name, unit: TeamQuotaUnitMemory}
+	inUse, err := item.GetQuotaInUse()
+	c.Assert(err, check.IsNil)
+	c.Assert(inUse, check.Equals, 1024+planQuotaWeight(TeamQuotaUnitMemory, s.defaultPlan))
+	item = &teamQuotaItem{name: s.team.Name, unit: TeamQuotaUnitCPU}
+	inUse, err = item.GetQuotaInUse()
+	c.Assert(err, check.IsNil)
+	c.Assert(inUse, check.Equals, 1000+planQuotaWeight(TeamQuotaUnitCPU, s.defaultPlan))
+}
+
+func (s *S) TestCreateAppReservesTeamQuotaByPlan(c *check.C) {
+	config.Set("quota:teams:unit", "memory")
+	defer config.Unset("quota:teams:unit")
+	s.plan = appTypes.Plan{Name: "large", Memory: 2048 * 1024 * 1024}
+	var reserved []int
+	s.mockService.TeamQuota.OnInc = func(item quota.QuotaItem, quantity int) error {
+		c.Assert(item, check.DeepEquals, &teamQuotaItem{name: s.team.Name, unit: TeamQuotaUnitMemory})
+		reserved = append(reserved, quantity)
+		return nil
+	}
+	a := App{Name: "app1", TeamOwner: s.team.Name, Plan: appTypes.Plan{Name: "large"}}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	c.Assert(reserved, check.DeepEquals, []int{2048})
+	err = Delete(context.TODO(), &a, nil, "")
+	c.Assert(err, check.IsNil)
+	c.Assert(reserved, check.DeepEquals, []int{2048})
+}
+
+func (s *S) TestCreateAppTeamQuotaExceeded(c *check.C) {
+	config.Set("quota:teams:unit", "cpu")
+	defer config.Unset("quota:teams:unit")
+	s.plan = appTypes.Plan{Name: "large", CPUMilli: 2000}
+	s.mockService.TeamQuota.OnInc = func(item quota.QuotaItem, quantity int) error {
+		return &quota.QuotaExceededError{Available: 1000, Requested: uint(quantity)}
+	}
+	a := App{Name: "app1", TeamOwner: s.team.Name, Plan: appTypes.Plan{Name: "large"}}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.NotNil)
+	e, ok := err.(*appTypes.AppCreationError)
+	c.Assert(ok, check.Equals, true)
+	c.Assert(e.Err, check.DeepEquals, &quota.QuotaExceededError{Available: 1000, Requested: 2000})
+}
+
+func (s *S) TestUpdateAppPlanChecksTeamQuota(c *check.C) {
+	a := App{Name: "app1", TeamOwner: s.team.Name}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	config.Set("quota:teams:unit", "memory")
+	defer config.Unset("quota:teams:unit")
+	s.plan = appTypes.Plan{Name: "large", Memory: 1024 * 1024 * 1024}
+	var reserved []int
+	s.mockService.TeamQuota.OnInc = func(item quota.QuotaItem, quantity int) error {
+		reserved = append(reserved, quantity)
+		return &quota.QuotaExceededError{Available: 0, Requested: uint(quantity)}
+	}
+	err = a.Update(UpdateAppArgs{UpdateData: App{Plan: appTypes.Plan{Name: "large"}}, Writer: new(bytes.Buffer)})
+	c.Assert(err, check.FitsTypeOf, &quota.QuotaExceededError{})
+	c.Assert(reserved, check.DeepEquals, []int{1024 - planQuotaWeight(TeamQuotaUnitMemory, s.defaultPlan)})
+	dbApp, err := GetByName(context.TODO(), a.Name)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbApp.Plan.Name, check.Equals, s.defaultPlan.Name)
+}
+
+func (s *S) TestCreateAppAncestorTeamQuotaExceeded(c *check.C) {
+	s.mockService.Team.OnFindAncestors = func(name string) ([]authTypes.Team, error) {
+		c.Assert(name, check.Equals, s.team.Name)
+		return []authTypes.Team{{Name: "org"}}, nil
+	}
+	s.mockService.Team.OnFindDescendants = func(names []string) ([]authTypes.Team, error) {
+		c.Assert(names, check.DeepEquals, []string{"org"})
+		return []authTypes.Team{{Name: s.team.Name, Parent: "org"}, {Name: "sibling", Parent: "org"}}, nil
+	}
+	usage := map[string]quota.Quota{
+		"org":       {InUse: 1, Limit: 5},
+		s.team.Name: {InUse: 2, Limit: -1},
+		"sibling":   {InUse: 2, Limit: -1},
+	}
+	s.mockService.TeamQuota.OnGet = func(item quota.QuotaItem) (*quota.Quota, error) {
+		q := usage[item.GetName()]
+		return &q, nil
+	}
+	s.mockService.TeamQuota.OnInc = func(item quota.QuotaItem, quantity int) error {
+		c.Fatal("team quota must not be changed")
+		return nil
+	}
+	a := App{Name: "app1", TeamOwner: s.team.Name}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.NotNil)
+	e, ok := err.(*appTypes.AppCreationError)
+	c.Assert(ok, check.Equals, true)
+	c.Assert(e.Err, check.DeepEquals, &quota.QuotaExceededError{Available: 0, Requested: 1})
+}
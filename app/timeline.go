@@ -0,0 +1,123 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"fmt"
+	"time"
+
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/permission"
+)
+
+type TimelineCategory string
+
+const (
+	TimelineDeploy  = TimelineCategory("deploy")
+	TimelineEnv     = TimelineCategory("env")
+	TimelineScale   = TimelineCategory("scale")
+	TimelineHealing = TimelineCategory("healing")
+	TimelineBind    = TimelineCategory("bind")
+)
+
+var timelineCategories = []TimelineCategory{
+	TimelineDeploy,
+	TimelineEnv,
+	TimelineScale,
+	TimelineHealing,
+	TimelineBind,
+}
+
+var timelineKinds = map[TimelineCategory][]string{
+	TimelineDeploy: {
+		permission.PermAppDeploy.FullName(),
+		permission.PermAppUpdateDeployRollback.FullName(),
+		permission.PermAppUpdateDeployCanary.FullName(),
+	},
+	TimelineEnv: {
+		permission.PermAppUpdateEnvSet.FullName(),
+		permission.PermAppUpdateEnvUnset.FullName(),
+	},
+	TimelineScale: {
+		permission.PermAppUpdateUnitAdd.FullName(),
+		permission.PermAppUpdateUnitRemove.FullName(),
+		permission.PermAppUpdateUnitAutoscaleAdd.FullName(),
+		permission.PermAppUpdateUnitAutoscaleRemove.FullName(),
+	},
+	TimelineHealing: {"healer"},
+	TimelineBind: {
+		permission.PermAppUpdateBind.FullName(),
+		permission.PermAppUpdateUnbind.FullName(),
+	},
+}
+
+// TimelineOpts holds the options used to list the timeline of an app.
+type TimelineOpts struct {
+	// Categories limits the timeline to the given categories, all of them
+	// are listed when it's empty.
+	Categories []TimelineCategory
+	Since      time.Time
+	Until      time.Time
+	// Limit is the maximum number of events listed, it's adjusted by
+	// Timeline when unset or above the events list limit.
+	Limit int
+	// After is the unique ID of the last event previously returned, only
+	// older events are listed.
+	After string
+	// Permissions holds the permissions of the user listing the timeline,
+	// only events allowed by them are listed.
+	Permissions []permission.Permission
+}
+
+// TimelineEntry is an event in the timeline of an app.
+type TimelineEntry struct {
+	Category TimelineCategory
+	*event.Event
+}
+
+// Timeline returns the deploys, env changes, scale operations, healing and
+// service bind events of the app, merged in a single feed sorted from the
+// newest to the oldest.
+func (app *App) Timeline(opts *TimelineOpts) ([]TimelineEntry, error) {
+	categories := opts.Categories
+	if len(categories) == 0 {
+		categories = timelineCategories
+	}
+	kindCategory := map[string]TimelineCategory{}
+	var kindNames []string
+	for _, category := range categories {
+		kinds, ok := timelineKinds[category]
+		if !ok {
+			return nil, &tsuruErrors.ValidationError{
+				Message: fmt.Sprintf("invalid timeline category %q, valid categories are: %v", category, timelineCategories),
+			}
+		}
+		for _, kind := range kinds {
+			kindCategory[kind] = category
+		}
+		kindNames = append(kindNames, kinds...)
+	}
+	filter := &event.Filter{
+		Target:    event.Target{Type: event.TargetTypeApp, Value: app.Name},
+		KindNames: kindNames,
+		Since:     opts.Since,
+		Until:     opts.Until,
+		Limit:     opts.Limit,
+		After:     opts.After,
+	}
+	filter.PruneUserValues()
+	filter.Permissions = opts.Permissions
+	opts.Limit = filter.Limit
+	evts, err := event.List(filter)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]TimelineEntry, len(evts))
+	for i, evt := range evts {
+		entries[i] = TimelineEntry{Category: kindCategory[evt.Kind.Name], Event: evt}
+	}
+	return entries, nil
+}
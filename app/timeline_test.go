@@ -0,0 +1,88 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"context"
+
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/permission"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) createTimelineEvents(c *check.C, a *App) {
+	kinds := []*permission.PermissionScheme{
+		permission.PermAppDeploy,
+		permission.PermAppUpdateEnvSet,
+		permission.PermAppUpdateDescription,
+		permission.PermAppUpdateUnitAdd,
+		permission.PermAppUpdateBind,
+	}
+	for _, kind := range kinds {
+		evt := s.newAppEvent(c, a, kind)
+		err := evt.Done(nil)
+		c.Assert(err, check.IsNil)
+	}
+	evt, err := event.NewInternal(&event.Opts{
+		Target: event.Target{Type: event.TargetTypeContainer, Value: "c1"},
+		ExtraTargets: []event.ExtraTarget{
+			{Target: event.Target{Type: event.TargetTypeApp, Value: a.Name}},
+		},
+		InternalKind: "healer",
+		Allowed:      event.Allowed(permission.PermApp),
+	})
+	c.Assert(err, check.IsNil)
+	err = evt.Done(nil)
+	c.Assert(err, check.IsNil)
+}
+
+func (s *S) TestTimeline(c *check.C) {
+	a := App{Name: "myapp", Platform: "python", TeamOwner: s.team.Name}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	other := App{Name: "other", Platform: "python", TeamOwner: s.team.Name}
+	err = CreateApp(context.TODO(), &other, s.user)
+	c.Assert(err, check.IsNil)
+	s.createTimelineEvents(c, &a)
+	s.createTimelineEvents(c, &other)
+	opts := TimelineOpts{}
+	entries, err := a.Timeline(&opts)
+	c.Assert(err, check.IsNil)
+	c.Assert(opts.Limit, check.Equals, 100)
+	var categories []TimelineCategory
+	for _, entry := range entries {
+		c.Assert(entry.Target.Value == a.Name || entry.ExtraTargets[0].Target.Value == a.Name, check.Equals, true)
+		categories = append(categories, entry.Category)
+	}
+	c.Assert(categories, check.DeepEquals, []TimelineCategory{
+		TimelineHealing, TimelineBind, TimelineScale, TimelineEnv, TimelineDeploy,
+	})
+}
+
+func (s *S) TestTimelineCategoriesAndPagination(c *check.C) {
+	a := App{Name: "myapp", Platform: "python", TeamOwner: s.team.Name}
+	err := CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	s.createTimelineEvents(c, &a)
+	opts := TimelineOpts{Categories: []TimelineCategory{TimelineDeploy, TimelineEnv, TimelineScale}, Limit: 2}
+	entries, err := a.Timeline(&opts)
+	c.Assert(err, check.IsNil)
+	c.Assert(entries, check.HasLen, 2)
+	c.Assert(entries[0].Category, check.Equals, TimelineScale)
+	c.Assert(entries[1].Category, check.Equals, TimelineEnv)
+	opts.After = entries[1].UniqueID.Hex()
+	entries, err = a.Timeline(&opts)
+	c.Assert(err, check.IsNil)
+	c.Assert(entries, check.HasLen, 1)
+	c.Assert(entries[0].Category, check.Equals, TimelineDeploy)
+	c.Assert(entries[0].Kind.Name, check.Equals, "app.deploy")
+}
+
+func (s *S) TestTimelineInvalidCategory(c *check.C) {
+	a := App{Name: "myapp", Platform: "python", TeamOwner: s.team.Name}
+	_, err := a.Timeline(&TimelineOpts{Categories: []TimelineCategory{"invalid"}})
+	c.Assert(err, check.FitsTypeOf, &tsuruErrors.ValidationError{})
+}
@@ -22,10 +22,15 @@ type customData struct {
 	Hooks       *provTypes.TsuruYamlHooks
 	Healthcheck *provTypes.TsuruYamlHealthcheck
 	Kubernetes  *tsuruYamlKubernetesConfig
+	Shutdown    provTypes.TsuruYamlShutdown
+	Sidecars    []provTypes.TsuruYamlSidecar
+
+	InitContainers []provTypes.TsuruYamlInitContainer `json:"init_containers"`
 }
 
 type tsuruYamlKubernetesConfig struct {
-	Groups []tsuruYamlKubernetesGroup
+	Groups                        []tsuruYamlKubernetesGroup
+	TerminationGracePeriodSeconds map[string]int `json:"termination_grace_period_seconds,omitempty"`
 }
 
 type tsuruYamlKubernetesGroup struct {
@@ -62,12 +67,18 @@ func unmarshalYamlData(data map[string]interface{}) (provTypes.TsuruYamlData, er
 	result := provTypes.TsuruYamlData{
 		Hooks:       custom.Hooks,
 		Healthcheck: custom.Healthcheck,
+		Shutdown:    custom.Shutdown,
+		Sidecars:    custom.Sidecars,
+
+		InitContainers: custom.InitContainers,
 	}
 	if custom.Kubernetes == nil {
 		return result, nil
 	}
 
-	result.Kubernetes = &provTypes.TsuruYamlKubernetesConfig{}
+	result.Kubernetes = &provTypes.TsuruYamlKubernetesConfig{
+		TerminationGracePeriodSeconds: custom.Kubernetes.TerminationGracePeriodSeconds,
+	}
 	for _, g := range custom.Kubernetes.Groups {
 		group := provTypes.TsuruYamlKubernetesGroup{}
 		for _, proc := range g.Processes {
@@ -114,7 +125,9 @@ func marshalCustomData(data map[string]interface{}) (map[string]interface{}, err
 	if yamlData.Kubernetes == nil {
 		return result, nil
 	}
-	kubeConfig := &tsuruYamlKubernetesConfig{}
+	kubeConfig := &tsuruYamlKubernetesConfig{
+		TerminationGracePeriodSeconds: yamlData.Kubernetes.TerminationGracePeriodSeconds,
+	}
 
 	for groupName, groupData := range yamlData.Kubernetes.Groups {
 		group := tsuruYamlKubernetesGroup{Name: groupName}
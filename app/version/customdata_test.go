@@ -5,6 +5,7 @@
 package version
 
 import (
+	provTypes "github.com/tsuru/tsuru/types/provision"
 	check "gopkg.in/check.v1"
 )
 
@@ -37,3 +38,87 @@ func (s *S) TestGetProcessesFromProcfile(c *check.C) {
 		c.Check(v, check.DeepEquals, t.expected, check.Commentf("failed test %d", i))
 	}
 }
+
+func (s *S) TestMarshalUnmarshalCustomDataShutdown(c *check.C) {
+	data, err := marshalCustomData(map[string]interface{}{
+		"shutdown": map[string]interface{}{
+			"worker": map[string]interface{}{
+				"commands":        []string{"stop-consuming", "wait-jobs"},
+				"timeout_seconds": 300,
+			},
+		},
+		"kubernetes": map[string]interface{}{
+			"termination_grace_period_seconds": map[string]int{"web": 60},
+		},
+	})
+	c.Assert(err, check.IsNil)
+	yamlData, err := unmarshalYamlData(data)
+	c.Assert(err, check.IsNil)
+	c.Assert(yamlData.Shutdown, check.DeepEquals, provTypes.TsuruYamlShutdown{
+		"worker": {
+			Commands:       []string{"stop-consuming", "wait-jobs"},
+			TimeoutSeconds: 300,
+		},
+	})
+	c.Assert(yamlData.Kubernetes, check.DeepEquals, &provTypes.TsuruYamlKubernetesConfig{
+		TerminationGracePeriodSeconds: map[string]int{"web": 60},
+	})
+}
+
+func (s *S) TestMarshalUnmarshalCustomDataSidecars(c *check.C) {
+	data, err := marshalCustomData(map[string]interface{}{
+		"sidecars": []interface{}{
+			map[string]interface{}{
+				"name":      "envoy",
+				"image":     "envoyproxy/envoy",
+				"command":   []string{"envoy"},
+				"resources": map[string]interface{}{"cpu": "100m", "memory": "64Mi"},
+				"volumes":   []interface{}{map[string]interface{}{"name": "config", "path": "/etc/envoy"}},
+				"processes": []string{"web"},
+			},
+		},
+	})
+	c.Assert(err, check.IsNil)
+	yamlData, err := unmarshalYamlData(data)
+	c.Assert(err, check.IsNil)
+	c.Assert(yamlData.Sidecars, check.DeepEquals, []provTypes.TsuruYamlSidecar{
+		{
+			Name:      "envoy",
+			Image:     "envoyproxy/envoy",
+			Command:   []string{"envoy"},
+			Resources: provTypes.TsuruYamlSidecarResources{CPU: "100m", Memory: "64Mi"},
+			Volumes:   []provTypes.TsuruYamlSidecarVolume{{Name: "config", Path: "/etc/envoy"}},
+			Processes: []string{"web"},
+		},
+	})
+	c.Assert(yamlData.SidecarsForProcess("web"), check.HasLen, 1)
+	c.Assert(yamlData.InitContainers, check.IsNil)
+	c.Assert(yamlData.SidecarsForProcess("worker"), check.HasLen, 0)
+}
+
+func (s *S) TestMarshalUnmarshalCustomDataInitContainers(c *check.C) {
+	data, err := marshalCustomData(map[string]interface{}{
+		"init_containers": []interface{}{
+			map[string]interface{}{
+				"name":      "migrate",
+				"command":   []string{"python", "manage.py", "migrate"},
+				"processes": []string{"web"},
+			},
+			map[string]interface{}{
+				"name":  "fetch-config",
+				"image": "config-fetcher",
+			},
+		},
+	})
+	c.Assert(err, check.IsNil)
+	yamlData, err := unmarshalYamlData(data)
+	c.Assert(err, check.IsNil)
+	c.Assert(yamlData.InitContainers, check.DeepEquals, []provTypes.TsuruYamlInitContainer{
+		{Name: "migrate", Command: []string{"python", "manage.py", "migrate"}, Processes: []string{"web"}},
+		{Name: "fetch-config", Image: "config-fetcher"},
+	})
+	c.Assert(yamlData.InitContainersForProcess("web"), check.HasLen, 2)
+	c.Assert(yamlData.InitContainersForProcess("worker"), check.DeepEquals, []provTypes.TsuruYamlInitContainer{
+		{Name: "fetch-config", Image: "config-fetcher"},
+	})
+}
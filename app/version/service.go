@@ -117,6 +117,10 @@ func (s *appVersionService) MarkVersionsToRemoval(ctx context.Context, appName s
 	return s.storage.MarkVersionsToRemoval(ctx, appName, versions, opts...)
 }
 
+func (s *appVersionService) RenameApp(ctx context.Context, oldName, newName string) error {
+	return s.storage.RenameApp(ctx, oldName, newName)
+}
+
 func (s *appVersionService) AppVersionFromInfo(ctx context.Context, app appTypes.App, info appTypes.AppVersionInfo) (appTypes.AppVersion, error) {
 	return newAppVersionImpl(ctx, s.storage, app, &info)
 }
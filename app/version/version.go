@@ -149,6 +149,9 @@ func (v *appVersionImpl) AddData(args appTypes.AddVersionDataArgs) error {
 	if args.ExposedPorts != nil {
 		v.versionInfo.ExposedPorts = args.ExposedPorts
 	}
+	if args.EnvSnapshot != nil {
+		v.versionInfo.EnvSnapshot = args.EnvSnapshot
+	}
 	return v.storage.UpdateVersion(v.ctx, v.app.GetName(), v.versionInfo)
 }
 
@@ -0,0 +1,125 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package audit records the authenticated calls to the tsuru API in a
+// collection of its own, independent from events, which only cover actions
+// changing the state of tsuru.
+package audit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/api/shutdown"
+	"github.com/tsuru/tsuru/log"
+	"github.com/tsuru/tsuru/servicemanager"
+	"github.com/tsuru/tsuru/storage"
+	auditTypes "github.com/tsuru/tsuru/types/audit"
+)
+
+const (
+	defaultRetentionDays  = 30
+	removeExpiredInterval = time.Hour
+)
+
+type auditService struct {
+	storage auditTypes.AuditStorage
+}
+
+var _ auditTypes.AuditService = &auditService{}
+
+func AuditService() (auditTypes.AuditService, error) {
+	dbDriver, err := storage.GetCurrentDbDriver()
+	if err != nil {
+		dbDriver, err = storage.GetDefaultDbDriver()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &auditService{storage: dbDriver.AuditStorage}, nil
+}
+
+// Enabled returns whether API calls should be recorded, according to the
+// api:audit:enabled config.
+func Enabled() bool {
+	enabled, _ := config.GetBool("api:audit:enabled")
+	return enabled
+}
+
+// Retention returns for how long entries are kept, according to the
+// api:audit:retention-days config.
+func Retention() time.Duration {
+	days, err := config.GetInt("api:audit:retention-days")
+	if err != nil || days <= 0 {
+		days = defaultRetentionDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+func (s *auditService) Add(ctx context.Context, entry auditTypes.Entry) error {
+	if entry.Time.IsZero() {
+		entry.Time = time.Now().UTC()
+	}
+	return s.storage.Insert(ctx, entry)
+}
+
+func (s *auditService) List(ctx context.Context, f *auditTypes.Filter) ([]auditTypes.Entry, error) {
+	return s.storage.List(ctx, f)
+}
+
+func (s *auditService) RemoveExpired(ctx context.Context) (int, error) {
+	return s.storage.RemoveBefore(ctx, time.Now().UTC().Add(-Retention()))
+}
+
+// Initialize starts the periodic removal of expired entries, when the audit
+// log is enabled.
+func Initialize() error {
+	if !Enabled() {
+		return nil
+	}
+	r := &remover{once: &sync.Once{}}
+	r.start()
+	shutdown.Register(r)
+	return nil
+}
+
+type remover struct {
+	once   *sync.Once
+	stopCh chan struct{}
+}
+
+func (r *remover) start() {
+	r.once.Do(func() {
+		r.stopCh = make(chan struct{})
+		go r.spin()
+	})
+}
+
+func (r *remover) Shutdown(ctx context.Context) error {
+	if r.stopCh == nil {
+		return nil
+	}
+	r.stopCh <- struct{}{}
+	r.stopCh = nil
+	r.once = &sync.Once{}
+	return nil
+}
+
+func (r *remover) spin() {
+	for {
+		removed, err := servicemanager.Audit.RemoveExpired(context.Background())
+		if err != nil {
+			log.Errorf("[audit] unable to remove expired entries: %v", err)
+		} else if removed > 0 {
+			log.Debugf("[audit] removed %d expired entries", removed)
+		}
+		select {
+		case <-r.stopCh:
+			return
+		case <-time.After(removeExpiredInterval):
+		}
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/tsuru/config"
+	auditTypes "github.com/tsuru/tsuru/types/audit"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestAddAndList(c *check.C) {
+	err := s.service.Add(context.TODO(), auditTypes.Entry{Method: "GET", Path: "/apps", Owner: "me@example.com"})
+	c.Assert(err, check.IsNil)
+	entries, err := s.service.List(context.TODO(), &auditTypes.Filter{Owner: "me@example.com"})
+	c.Assert(err, check.IsNil)
+	c.Assert(entries, check.HasLen, 1)
+	c.Assert(entries[0].Method, check.Equals, "GET")
+	c.Assert(entries[0].Time.IsZero(), check.Equals, false)
+	c.Assert(time.Since(entries[0].Time) < time.Minute, check.Equals, true)
+}
+
+func (s *S) TestRetention(c *check.C) {
+	c.Assert(Retention(), check.Equals, 30*24*time.Hour)
+	config.Set("api:audit:retention-days", 7)
+	c.Assert(Retention(), check.Equals, 7*24*time.Hour)
+	config.Set("api:audit:retention-days", -1)
+	c.Assert(Retention(), check.Equals, 30*24*time.Hour)
+}
+
+func (s *S) TestRemoveExpired(c *check.C) {
+	config.Set("api:audit:retention-days", 1)
+	now := time.Now().UTC()
+	for _, t := range []time.Time{now.Add(-48 * time.Hour), now.Add(-25 * time.Hour), now.Add(-time.Hour)} {
+		err := s.service.Add(context.TODO(), auditTypes.Entry{Time: t, Method: "GET", Path: "/apps"})
+		c.Assert(err, check.IsNil)
+	}
+	removed, err := s.service.RemoveExpired(context.TODO())
+	c.Assert(err, check.IsNil)
+	c.Assert(removed, check.Equals, 2)
+	entries, err := s.service.List(context.TODO(), nil)
+	c.Assert(err, check.IsNil)
+	c.Assert(entries, check.HasLen, 1)
+}
+
+func (s *S) TestInitializeDisabled(c *check.C) {
+	err := Initialize()
+	c.Assert(err, check.IsNil)
+}
@@ -0,0 +1,107 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/servicemanager"
+	appTypes "github.com/tsuru/tsuru/types/app"
+	authTypes "github.com/tsuru/tsuru/types/auth"
+)
+
+// ParseAllowedCIDRs validates the networks of an IP allowlist, plain IP
+// addresses are converted to single address networks and empty entries are
+// ignored.
+func ParseAllowedCIDRs(cidrs []string) ([]string, error) {
+	var result []string
+	for _, cidr := range cidrs {
+		cidr = strings.TrimSpace(cidr)
+		if cidr == "" {
+			continue
+		}
+		if !strings.Contains(cidr, "/") {
+			ip := net.ParseIP(cidr)
+			if ip == nil {
+				return nil, &tsuruErrors.ValidationError{Message: fmt.Sprintf("invalid IP address %q", cidr)}
+			}
+			bits := 32
+			if ip.To4() == nil {
+				bits = 128
+			}
+			cidr = fmt.Sprintf("%s/%d", ip, bits)
+		}
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, &tsuruErrors.ValidationError{Message: fmt.Sprintf("invalid CIDR %q", cidr)}
+		}
+		result = append(result, ipNet.String())
+	}
+	return result, nil
+}
+
+// AddressAllowed returns whether ip belongs to one of the networks in cidrs,
+// an empty allowlist allows every address.
+func AddressAllowed(cidrs []string, ip net.IP) bool {
+	if len(cidrs) == 0 {
+		return true
+	}
+	if ip == nil {
+		return false
+	}
+	for _, cidr := range cidrs {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err == nil && ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// TokenAllowlists returns the IP allowlists restricting the requests
+// authenticated with t. Team tokens are restricted by their own allowlist and
+// by the allowlist of their team, and app tokens by the allowlist of the team
+// owning the app. The address of a request must be allowed by all of them.
+func TokenAllowlists(ctx context.Context, t authTypes.Token) ([][]string, error) {
+	var teamName string
+	var allowlists [][]string
+	switch token := t.(type) {
+	case *teamToken:
+		if len(token.AllowedCIDRs) > 0 {
+			allowlists = append(allowlists, token.AllowedCIDRs)
+		}
+		teamName = token.Team
+	default:
+		if !t.IsAppToken() || t.GetAppName() == "" {
+			return nil, nil
+		}
+		a, err := servicemanager.App.GetByName(ctx, t.GetAppName())
+		if err == appTypes.ErrAppNotFound {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		teamName = a.GetTeamOwner()
+	}
+	if teamName == "" {
+		return allowlists, nil
+	}
+	team, err := servicemanager.Team.FindByName(ctx, teamName)
+	if err == authTypes.ErrTeamNotFound {
+		return allowlists, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if team != nil && len(team.AllowedCIDRs) > 0 {
+		allowlists = append(allowlists, team.AllowedCIDRs)
+	}
+	return allowlists, nil
+}
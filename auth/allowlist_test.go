@@ -0,0 +1,66 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"context"
+	"net"
+
+	"github.com/tsuru/tsuru/servicemanager"
+	authTypes "github.com/tsuru/tsuru/types/auth"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestParseAllowedCIDRs(c *check.C) {
+	cidrs, err := ParseAllowedCIDRs([]string{"10.0.0.0/8", " 192.168.1.10 ", "", "2001:db8::1", "172.16.5.4/16"})
+	c.Assert(err, check.IsNil)
+	c.Assert(cidrs, check.DeepEquals, []string{"10.0.0.0/8", "192.168.1.10/32", "2001:db8::1/128", "172.16.0.0/16"})
+	cidrs, err = ParseAllowedCIDRs(nil)
+	c.Assert(err, check.IsNil)
+	c.Assert(cidrs, check.IsNil)
+	_, err = ParseAllowedCIDRs([]string{"10.0.0.0/33"})
+	c.Assert(err, check.ErrorMatches, `invalid CIDR "10.0.0.0/33"`)
+	_, err = ParseAllowedCIDRs([]string{"my-runner"})
+	c.Assert(err, check.ErrorMatches, `invalid IP address "my-runner"`)
+}
+
+func (s *S) TestAddressAllowed(c *check.C) {
+	cidrs := []string{"10.0.0.0/8", "192.168.1.10/32"}
+	c.Assert(AddressAllowed(cidrs, net.ParseIP("10.1.2.3")), check.Equals, true)
+	c.Assert(AddressAllowed(cidrs, net.ParseIP("192.168.1.10")), check.Equals, true)
+	c.Assert(AddressAllowed(cidrs, net.ParseIP("192.168.1.11")), check.Equals, false)
+	c.Assert(AddressAllowed(cidrs, nil), check.Equals, false)
+	c.Assert(AddressAllowed(nil, net.ParseIP("192.168.1.11")), check.Equals, true)
+}
+
+func (s *S) TestTokenAllowlists(c *check.C) {
+	teamToken, err := servicemanager.TeamToken.Create(context.TODO(), authTypes.TeamTokenCreateArgs{
+		Team:    s.team.Name,
+		TokenID: "ci",
+	}, &userToken{user: s.user})
+	c.Assert(err, check.IsNil)
+	t, err := servicemanager.TeamToken.Authenticate(context.TODO(), "bearer "+teamToken.Token)
+	c.Assert(err, check.IsNil)
+	allowlists, err := TokenAllowlists(context.TODO(), t)
+	c.Assert(err, check.IsNil)
+	c.Assert(allowlists, check.HasLen, 0)
+	err = servicemanager.TeamToken.SetAllowedCIDRs(context.TODO(), "ci", []string{"10.0.0.0/8"})
+	c.Assert(err, check.IsNil)
+	err = servicemanager.Team.SetAllowedCIDRs(context.TODO(), s.team.Name, []string{"10.1.0.0/16", "192.168.0.1"})
+	c.Assert(err, check.IsNil)
+	t, err = servicemanager.TeamToken.Authenticate(context.TODO(), "bearer "+teamToken.Token)
+	c.Assert(err, check.IsNil)
+	allowlists, err = TokenAllowlists(context.TODO(), t)
+	c.Assert(err, check.IsNil)
+	c.Assert(allowlists, check.DeepEquals, [][]string{{"10.0.0.0/8"}, {"10.1.0.0/16", "192.168.0.1/32"}})
+}
+
+func (s *S) TestTokenAllowlistsUserToken(c *check.C) {
+	err := servicemanager.Team.SetAllowedCIDRs(context.TODO(), s.team.Name, []string{"10.1.0.0/16"})
+	c.Assert(err, check.IsNil)
+	allowlists, err := TokenAllowlists(context.TODO(), &userToken{user: s.user})
+	c.Assert(err, check.IsNil)
+	c.Assert(allowlists, check.HasLen, 0)
+}
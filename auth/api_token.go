@@ -15,6 +15,7 @@ import (
 type APIToken struct {
 	Token     string `json:"token" bson:"apikey"`
 	UserEmail string `json:"email" bson:"email"`
+	teams     TeamHierarchy
 }
 
 func (t *APIToken) GetValue() string {
@@ -38,7 +39,7 @@ func (t *APIToken) GetAppName() string {
 }
 
 func (t *APIToken) Permissions() ([]permission.Permission, error) {
-	return BaseTokenPermission(t)
+	return BaseTokenPermission(t, &t.teams)
 }
 
 func getAPIToken(header string) (*APIToken, error) {
@@ -0,0 +1,179 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"crypto"
+	"fmt"
+	"time"
+
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/db"
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/permission"
+	authTypes "github.com/tsuru/tsuru/types/auth"
+)
+
+const (
+	defaultImpersonationDuration    = 30 * time.Minute
+	defaultImpersonationMaxDuration = time.Hour
+)
+
+var (
+	_ authTypes.Token             = &ImpersonationToken{}
+	_ authTypes.ImpersonatedToken = &ImpersonationToken{}
+)
+
+// ImpersonationToken lets an admin act as another user for a limited time,
+// with the permissions of the user, to debug permission issues. Events and
+// audit log entries of calls made with it record the impersonator.
+type ImpersonationToken struct {
+	Token        string    `json:"token" bson:"_id"`
+	UserEmail    string    `json:"email"`
+	Impersonator string    `json:"impersonator"`
+	Reason       string    `json:"reason"`
+	CreatedAt    time.Time `json:"created_at"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	teams        TeamHierarchy
+}
+
+func (t *ImpersonationToken) GetValue() string {
+	return t.Token
+}
+
+func (t *ImpersonationToken) User() (*authTypes.User, error) {
+	return ConvertOldUser(GetUserByEmail(t.UserEmail))
+}
+
+func (t *ImpersonationToken) IsAppToken() bool {
+	return false
+}
+
+func (t *ImpersonationToken) GetUserName() string {
+	return t.UserEmail
+}
+
+func (t *ImpersonationToken) GetAppName() string {
+	return ""
+}
+
+func (t *ImpersonationToken) GetImpersonator() string {
+	return t.Impersonator
+}
+
+func (t *ImpersonationToken) Permissions() ([]permission.Permission, error) {
+	return BaseTokenPermission(t, &t.teams)
+}
+
+// IsImpersonated returns whether the token is used by an admin acting as
+// another user.
+func IsImpersonated(t authTypes.Token) bool {
+	it, ok := t.(authTypes.ImpersonatedToken)
+	return ok && it.GetImpersonator() != ""
+}
+
+func impersonationMaxDuration() time.Duration {
+	if d, err := config.GetDuration("auth:impersonation:max-duration"); err == nil && d > 0 {
+		return d
+	}
+	return defaultImpersonationMaxDuration
+}
+
+// CreateImpersonationToken issues a token acting as the user on behalf of
+// the impersonator, valid for the duration, or for 30 minutes when it's
+// zero, up to the auth:impersonation:max-duration config.
+func CreateImpersonationToken(u *User, impersonator, reason string, duration time.Duration) (*ImpersonationToken, error) {
+	if reason == "" {
+		return nil, &tsuruErrors.ValidationError{Message: "a reason is required to impersonate users"}
+	}
+	if u.Email == impersonator {
+		return nil, &tsuruErrors.ValidationError{Message: "users can't impersonate themselves"}
+	}
+	if duration == 0 {
+		duration = defaultImpersonationDuration
+	}
+	if max := impersonationMaxDuration(); duration < 0 || duration > max {
+		return nil, &tsuruErrors.ValidationError{Message: fmt.Sprintf("impersonation duration must be positive and at most %s", max)}
+	}
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	t := ImpersonationToken{
+		Token:        generateToken(u.Email+impersonator, crypto.SHA256),
+		UserEmail:    u.Email,
+		Impersonator: impersonator,
+		Reason:       reason,
+		CreatedAt:    now,
+		ExpiresAt:    now.Add(duration),
+	}
+	conn, err := db.Conn()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	coll := conn.ImpersonationTokens()
+	coll.EnsureIndex(mgo.Index{Key: []string{"expiresat"}, ExpireAfter: time.Second})
+	err = coll.Insert(t)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// ImpersonationAuth returns the impersonation token in the header, expired
+// tokens are invalid even before being removed by the database.
+func ImpersonationAuth(header string) (*ImpersonationToken, error) {
+	token, err := ParseToken(header)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := db.Conn()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	var t ImpersonationToken
+	err = conn.ImpersonationTokens().FindId(token).One(&t)
+	if err != nil {
+		if err == mgo.ErrNotFound {
+			return nil, ErrInvalidToken
+		}
+		return nil, err
+	}
+	if !time.Now().Before(t.ExpiresAt) {
+		return nil, ErrInvalidToken
+	}
+	return &t, nil
+}
+
+// RevokeImpersonationToken removes the impersonation token, ending the
+// impersonation before it expires.
+func RevokeImpersonationToken(token string) error {
+	conn, err := db.Conn()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	err = conn.ImpersonationTokens().RemoveId(token)
+	if err == mgo.ErrNotFound {
+		return ErrInvalidToken
+	}
+	return err
+}
+
+// RemoveImpersonationTokens removes the impersonation tokens acting as the
+// user or issued by it.
+func RemoveImpersonationTokens(email string) error {
+	conn, err := db.Conn()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	_, err = conn.ImpersonationTokens().RemoveAll(bson.M{"$or": []bson.M{
+		{"useremail": email},
+		{"impersonator": email},
+	}})
+	return err
+}
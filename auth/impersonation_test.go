@@ -0,0 +1,113 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"time"
+
+	"github.com/globalsign/mgo/bson"
+	"github.com/tsuru/config"
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestCreateImpersonationToken(c *check.C) {
+	t, err := CreateImpersonationToken(s.user, "admin@example.com", "debugging permissions", 0)
+	c.Assert(err, check.IsNil)
+	c.Assert(t.Token, check.Not(check.Equals), "")
+	c.Assert(t.GetUserName(), check.Equals, s.user.Email)
+	c.Assert(t.GetImpersonator(), check.Equals, "admin@example.com")
+	c.Assert(t.Reason, check.Equals, "debugging permissions")
+	c.Assert(t.ExpiresAt.Sub(t.CreatedAt), check.Equals, 30*time.Minute)
+	c.Assert(IsImpersonated(t), check.Equals, true)
+	u, err := t.User()
+	c.Assert(err, check.IsNil)
+	c.Assert(u.Email, check.Equals, s.user.Email)
+	var stored ImpersonationToken
+	err = s.conn.ImpersonationTokens().FindId(t.Token).One(&stored)
+	c.Assert(err, check.IsNil)
+	c.Assert(stored, check.DeepEquals, *t)
+}
+
+func (s *S) TestCreateImpersonationTokenRequiresReason(c *check.C) {
+	_, err := CreateImpersonationToken(s.user, "admin@example.com", "", 0)
+	c.Assert(err, check.FitsTypeOf, &tsuruErrors.ValidationError{})
+	c.Assert(err, check.ErrorMatches, "a reason is required to impersonate users")
+}
+
+func (s *S) TestCreateImpersonationTokenSelf(c *check.C) {
+	_, err := CreateImpersonationToken(s.user, s.user.Email, "why not", 0)
+	c.Assert(err, check.FitsTypeOf, &tsuruErrors.ValidationError{})
+	c.Assert(err, check.ErrorMatches, "users can't impersonate themselves")
+}
+
+func (s *S) TestCreateImpersonationTokenMaxDuration(c *check.C) {
+	_, err := CreateImpersonationToken(s.user, "admin@example.com", "debugging", 2*time.Hour)
+	c.Assert(err, check.ErrorMatches, "impersonation duration must be positive and at most 1h0m0s")
+	_, err = CreateImpersonationToken(s.user, "admin@example.com", "debugging", -time.Minute)
+	c.Assert(err, check.ErrorMatches, "impersonation duration must be positive and at most 1h0m0s")
+	config.Set("auth:impersonation:max-duration", "3h")
+	defer config.Unset("auth:impersonation")
+	t, err := CreateImpersonationToken(s.user, "admin@example.com", "debugging", 2*time.Hour)
+	c.Assert(err, check.IsNil)
+	c.Assert(t.ExpiresAt.Sub(t.CreatedAt), check.Equals, 2*time.Hour)
+}
+
+func (s *S) TestImpersonationAuth(c *check.C) {
+	t, err := CreateImpersonationToken(s.user, "admin@example.com", "debugging", time.Minute)
+	c.Assert(err, check.IsNil)
+	found, err := ImpersonationAuth("bearer " + t.Token)
+	c.Assert(err, check.IsNil)
+	c.Assert(found.GetUserName(), check.Equals, s.user.Email)
+	c.Assert(found.GetImpersonator(), check.Equals, "admin@example.com")
+}
+
+func (s *S) TestImpersonationAuthExpired(c *check.C) {
+	t, err := CreateImpersonationToken(s.user, "admin@example.com", "debugging", time.Minute)
+	c.Assert(err, check.IsNil)
+	err = s.conn.ImpersonationTokens().UpdateId(t.Token, bson.M{"$set": bson.M{"expiresat": time.Now().Add(-time.Second)}})
+	c.Assert(err, check.IsNil)
+	_, err = ImpersonationAuth("bearer " + t.Token)
+	c.Assert(err, check.Equals, ErrInvalidToken)
+}
+
+func (s *S) TestImpersonationAuthNotFound(c *check.C) {
+	_, err := ImpersonationAuth("bearer unknown-token")
+	c.Assert(err, check.Equals, ErrInvalidToken)
+}
+
+func (s *S) TestRevokeImpersonationToken(c *check.C) {
+	t, err := CreateImpersonationToken(s.user, "admin@example.com", "debugging", 0)
+	c.Assert(err, check.IsNil)
+	err = RevokeImpersonationToken(t.Token)
+	c.Assert(err, check.IsNil)
+	_, err = ImpersonationAuth("bearer " + t.Token)
+	c.Assert(err, check.Equals, ErrInvalidToken)
+	err = RevokeImpersonationToken(t.Token)
+	c.Assert(err, check.Equals, ErrInvalidToken)
+}
+
+func (s *S) TestRemoveImpersonationTokens(c *check.C) {
+	other := &User{Email: "other@example.com", Password: "123456"}
+	_, err := CreateImpersonationToken(s.user, "admin@example.com", "debugging", 0)
+	c.Assert(err, check.IsNil)
+	_, err = CreateImpersonationToken(other, s.user.Email, "debugging", 0)
+	c.Assert(err, check.IsNil)
+	kept, err := CreateImpersonationToken(other, "admin@example.com", "debugging", 0)
+	c.Assert(err, check.IsNil)
+	err = RemoveImpersonationTokens(s.user.Email)
+	c.Assert(err, check.IsNil)
+	var tokens []ImpersonationToken
+	err = s.conn.ImpersonationTokens().Find(nil).All(&tokens)
+	c.Assert(err, check.IsNil)
+	c.Assert(tokens, check.HasLen, 1)
+	c.Assert(tokens[0].Token, check.Equals, kept.Token)
+}
+
+func (s *S) TestIsImpersonated(c *check.C) {
+	c.Assert(IsImpersonated(&ImpersonationToken{Impersonator: "admin@example.com"}), check.Equals, true)
+	c.Assert(IsImpersonated(&ImpersonationToken{}), check.Equals, false)
+	c.Assert(IsImpersonated(&APIToken{}), check.Equals, false)
+}
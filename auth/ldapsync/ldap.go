@@ -0,0 +1,404 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ldapsync
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// LDAP protocol tags, see RFC 4511.
+const (
+	berTagInteger     = 0x02
+	berTagOctetString = 0x04
+	berTagBoolean     = 0x01
+	berTagEnumerated  = 0x0a
+	berTagSequence    = 0x30
+
+	ldapTagBindRequest         = 0x60
+	ldapTagBindResponse        = 0x61
+	ldapTagUnbindRequest       = 0x42
+	ldapTagSearchRequest       = 0x63
+	ldapTagSearchResultEntry   = 0x64
+	ldapTagSearchResultDone    = 0x65
+	ldapTagSearchResultRef     = 0x73
+	ldapTagExtendedRequest     = 0x77
+	ldapTagExtendedResponse    = 0x78
+	ldapTagExtendedName        = 0x80
+	ldapTagSimpleAuth          = 0x80
+	ldapTagFilterEqualityMatch = 0xa3
+
+	ldapScopeWholeSubtree = 2
+	ldapResultSuccess     = 0
+
+	// ldapOIDStartTLS is the name of the StartTLS extended operation, see
+	// RFC 4511 section 4.14.
+	ldapOIDStartTLS = "1.3.6.1.4.1.1466.20037"
+
+	// maxBERElementLength limits the size of the elements read from the
+	// server, so that a bogus length doesn't exhaust the memory.
+	maxBERElementLength = 1 << 20
+
+	defaultLDAPTimeout = 30 * time.Second
+)
+
+type berElement struct {
+	tag     byte
+	content []byte
+}
+
+func berEncode(tag byte, content []byte) []byte {
+	length := len(content)
+	var header []byte
+	if length < 0x80 {
+		header = []byte{tag, byte(length)}
+	} else {
+		var lengthBytes []byte
+		for l := length; l > 0; l >>= 8 {
+			lengthBytes = append([]byte{byte(l)}, lengthBytes...)
+		}
+		header = append([]byte{tag, 0x80 | byte(len(lengthBytes))}, lengthBytes...)
+	}
+	return append(header, content...)
+}
+
+func berInt(tag byte, v int) []byte {
+	var content []byte
+	for {
+		content = append([]byte{byte(v)}, content...)
+		if (v < 0x80 && v >= -0x80) || len(content) == 8 {
+			break
+		}
+		v >>= 8
+	}
+	return berEncode(tag, content)
+}
+
+func berString(tag byte, s string) []byte {
+	return berEncode(tag, []byte(s))
+}
+
+func berConstructed(tag byte, elements ...[]byte) []byte {
+	var content []byte
+	for _, e := range elements {
+		content = append(content, e...)
+	}
+	return berEncode(tag, content)
+}
+
+func readBERElement(r io.ByteReader) (berElement, error) {
+	tag, err := r.ReadByte()
+	if err != nil {
+		return berElement{}, err
+	}
+	b, err := r.ReadByte()
+	if err != nil {
+		return berElement{}, err
+	}
+	length := int(b)
+	if b&0x80 != 0 {
+		n := int(b & 0x7f)
+		if n == 0 || n > 4 {
+			return berElement{}, errors.Errorf("unsupported ber length of %d bytes", n)
+		}
+		length = 0
+		for i := 0; i < n; i++ {
+			b, err = r.ReadByte()
+			if err != nil {
+				return berElement{}, err
+			}
+			length = length<<8 | int(b)
+		}
+	}
+	if length > maxBERElementLength {
+		return berElement{}, errors.Errorf("ber element of %d bytes exceeds the limit of %d bytes", length, maxBERElementLength)
+	}
+	content := make([]byte, length)
+	for i := range content {
+		content[i], err = r.ReadByte()
+		if err != nil {
+			return berElement{}, err
+		}
+	}
+	return berElement{tag: tag, content: content}, nil
+}
+
+func (e berElement) children() ([]berElement, error) {
+	var children []berElement
+	r := &byteReader{data: e.content}
+	for r.pos < len(r.data) {
+		child, err := readBERElement(r)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid ber element")
+		}
+		children = append(children, child)
+	}
+	return children, nil
+}
+
+func (e berElement) int() int {
+	var v int
+	for i, b := range e.content {
+		if i == 0 && b&0x80 != 0 {
+			v = -1
+		}
+		v = v<<8 | int(b)
+	}
+	return v
+}
+
+type byteReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *byteReader) ReadByte() (byte, error) {
+	if r.pos >= len(r.data) {
+		return 0, io.ErrUnexpectedEOF
+	}
+	b := r.data[r.pos]
+	r.pos++
+	return b, nil
+}
+
+// ldapEntry is an entry returned by a search, with the values of the
+// requested attributes.
+type ldapEntry struct {
+	DN         string
+	Attributes map[string][]string
+}
+
+// ldapConn is a minimal LDAP client, supporting only StartTLS, the simple
+// bind and searches with equality filters used by the sync. Connections are
+// always encrypted, ldap URLs are upgraded with StartTLS before any other
+// operation.
+type ldapConn struct {
+	conn      net.Conn
+	reader    *bufio.Reader
+	timeout   time.Duration
+	messageID int
+}
+
+func dialLDAP(ctx context.Context, rawURL string, timeout time.Duration, rootCAs *x509.CertPool) (*ldapConn, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid ldap url %q", rawURL)
+	}
+	host := u.Host
+	tlsConfig := &tls.Config{ServerName: u.Hostname(), RootCAs: rootCAs}
+	var conn net.Conn
+	dialer := &net.Dialer{Timeout: timeout}
+	switch u.Scheme {
+	case "ldap":
+		if u.Port() == "" {
+			host = net.JoinHostPort(u.Hostname(), "389")
+		}
+		conn, err = dialer.DialContext(ctx, "tcp", host)
+	case "ldaps":
+		if u.Port() == "" {
+			host = net.JoinHostPort(u.Hostname(), "636")
+		}
+		tlsDialer := &tls.Dialer{NetDialer: dialer, Config: tlsConfig}
+		conn, err = tlsDialer.DialContext(ctx, "tcp", host)
+	default:
+		return nil, errors.Errorf("invalid ldap url %q, the scheme must be ldap or ldaps", rawURL)
+	}
+	if err != nil {
+		return nil, err
+	}
+	c := &ldapConn{conn: conn, reader: bufio.NewReader(conn), timeout: timeout}
+	if u.Scheme == "ldap" {
+		err = c.startTLS(tlsConfig)
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// startTLS upgrades the connection to TLS, credentials are never sent in
+// cleartext.
+func (c *ldapConn) startTLS(config *tls.Config) error {
+	id, err := c.send(berConstructed(ldapTagExtendedRequest,
+		berString(ldapTagExtendedName, ldapOIDStartTLS),
+	))
+	if err != nil {
+		return err
+	}
+	op, err := c.receive(id)
+	if err != nil {
+		return err
+	}
+	if op.tag != ldapTagExtendedResponse {
+		return errors.Errorf("unexpected ldap response to starttls: %#x", op.tag)
+	}
+	err = ldapResultError(op)
+	if err != nil {
+		return errors.Wrap(err, "unable to start tls")
+	}
+	tlsConn := tls.Client(c.conn, config)
+	tlsConn.SetDeadline(time.Now().Add(c.timeout))
+	err = tlsConn.Handshake()
+	if err != nil {
+		return errors.Wrap(err, "unable to start tls")
+	}
+	c.conn = tlsConn
+	c.reader = bufio.NewReader(tlsConn)
+	return nil
+}
+
+func (c *ldapConn) send(op []byte) (int, error) {
+	c.messageID++
+	msg := berConstructed(berTagSequence, berInt(berTagInteger, c.messageID), op)
+	c.conn.SetDeadline(time.Now().Add(c.timeout))
+	_, err := c.conn.Write(msg)
+	return c.messageID, err
+}
+
+func (c *ldapConn) receive(messageID int) (berElement, error) {
+	for {
+		msg, err := readBERElement(c.reader)
+		if err != nil {
+			return berElement{}, err
+		}
+		parts, err := msg.children()
+		if err != nil {
+			return berElement{}, err
+		}
+		if msg.tag != berTagSequence || len(parts) < 2 {
+			return berElement{}, errors.New("invalid ldap message")
+		}
+		if parts[0].int() != messageID {
+			continue
+		}
+		return parts[1], nil
+	}
+}
+
+func ldapResultError(op berElement) error {
+	parts, err := op.children()
+	if err != nil {
+		return err
+	}
+	if len(parts) < 3 {
+		return errors.New("invalid ldap result")
+	}
+	code := parts[0].int()
+	if code == ldapResultSuccess {
+		return nil
+	}
+	return errors.Errorf("ldap result code %d: %s", code, parts[2].content)
+}
+
+func (c *ldapConn) bind(dn, password string) error {
+	id, err := c.send(berConstructed(ldapTagBindRequest,
+		berInt(berTagInteger, 3),
+		berString(berTagOctetString, dn),
+		berString(ldapTagSimpleAuth, password),
+	))
+	if err != nil {
+		return err
+	}
+	op, err := c.receive(id)
+	if err != nil {
+		return err
+	}
+	if op.tag != ldapTagBindResponse {
+		return errors.Errorf("unexpected ldap response to bind: %#x", op.tag)
+	}
+	return errors.Wrap(ldapResultError(op), "unable to bind to ldap")
+}
+
+// search returns the entries under base whose attribute attr is equal to
+// value, with the values of the attributes in attrs.
+func (c *ldapConn) search(base, attr, value string, attrs []string) ([]ldapEntry, error) {
+	var attrElements [][]byte
+	for _, a := range attrs {
+		attrElements = append(attrElements, berString(berTagOctetString, a))
+	}
+	id, err := c.send(berConstructed(ldapTagSearchRequest,
+		berString(berTagOctetString, base),
+		berInt(berTagEnumerated, ldapScopeWholeSubtree),
+		berInt(berTagEnumerated, 0),
+		berInt(berTagInteger, 0),
+		berInt(berTagInteger, 0),
+		berEncode(berTagBoolean, []byte{0}),
+		berConstructed(ldapTagFilterEqualityMatch,
+			berString(berTagOctetString, attr),
+			berString(berTagOctetString, value),
+		),
+		berConstructed(berTagSequence, attrElements...),
+	))
+	if err != nil {
+		return nil, err
+	}
+	var entries []ldapEntry
+	for {
+		op, err := c.receive(id)
+		if err != nil {
+			return nil, err
+		}
+		switch op.tag {
+		case ldapTagSearchResultEntry:
+			entry, err := parseLDAPEntry(op)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, entry)
+		case ldapTagSearchResultRef:
+		case ldapTagSearchResultDone:
+			return entries, ldapResultError(op)
+		default:
+			return nil, errors.Errorf("unexpected ldap response to search: %#x", op.tag)
+		}
+	}
+}
+
+func parseLDAPEntry(op berElement) (ldapEntry, error) {
+	parts, err := op.children()
+	if err != nil {
+		return ldapEntry{}, err
+	}
+	if len(parts) < 2 {
+		return ldapEntry{}, errors.New("invalid ldap search entry")
+	}
+	entry := ldapEntry{DN: string(parts[0].content), Attributes: map[string][]string{}}
+	attributes, err := parts[1].children()
+	if err != nil {
+		return ldapEntry{}, err
+	}
+	for _, attribute := range attributes {
+		fields, err := attribute.children()
+		if err != nil {
+			return ldapEntry{}, err
+		}
+		if len(fields) < 2 {
+			return ldapEntry{}, errors.New("invalid ldap attribute")
+		}
+		values, err := fields[1].children()
+		if err != nil {
+			return ldapEntry{}, err
+		}
+		name := string(fields[0].content)
+		for _, v := range values {
+			entry.Attributes[name] = append(entry.Attributes[name], string(v.content))
+		}
+	}
+	return entry, nil
+}
+
+func (c *ldapConn) Close() error {
+	c.send(berEncode(ldapTagUnbindRequest, nil))
+	return c.conn.Close()
+}
@@ -0,0 +1,216 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ldapsync
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"net/http/httptest"
+
+	check "gopkg.in/check.v1"
+)
+
+// fakeLDAPServer answers StartTLS requests, binds with the password "secret"
+// and searches with the entries of the members of the searched group.
+type fakeLDAPServer struct {
+	listener  net.Listener
+	members   map[string][]ldapEntry
+	binds     []string
+	tlsConfig *tls.Config
+	rootCAs   *x509.CertPool
+	noTLS     bool
+}
+
+func newFakeLDAPServer(c *check.C, members map[string][]ldapEntry) *fakeLDAPServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, check.IsNil)
+	tlsSrv := httptest.NewTLSServer(nil)
+	tlsSrv.Close()
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(tlsSrv.Certificate())
+	srv := &fakeLDAPServer{
+		listener:  listener,
+		members:   members,
+		tlsConfig: &tls.Config{Certificates: tlsSrv.TLS.Certificates},
+		rootCAs:   rootCAs,
+	}
+	go srv.serve()
+	return srv
+}
+
+func (s *fakeLDAPServer) url() string {
+	return "ldap://" + s.listener.Addr().String()
+}
+
+func (s *fakeLDAPServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *fakeLDAPServer) handle(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	for {
+		msg, err := readBERElement(reader)
+		if err != nil {
+			return
+		}
+		parts, _ := msg.children()
+		id := berInt(berTagInteger, parts[0].int())
+		op := parts[1]
+		fields, _ := op.children()
+		reply := func(ops ...[]byte) {
+			for _, o := range ops {
+				conn.Write(berConstructed(berTagSequence, id, o))
+			}
+		}
+		result := func(tag byte, code int, message string) []byte {
+			return berConstructed(tag,
+				berInt(berTagEnumerated, code),
+				berString(berTagOctetString, ""),
+				berString(berTagOctetString, message),
+			)
+		}
+		switch op.tag {
+		case ldapTagExtendedRequest:
+			if s.noTLS || string(fields[0].content) != ldapOIDStartTLS {
+				reply(result(ldapTagExtendedResponse, 2, "unsupported extended operation"))
+				continue
+			}
+			reply(result(ldapTagExtendedResponse, 0, ""))
+			conn = tls.Server(conn, s.tlsConfig)
+			reader = bufio.NewReader(conn)
+		case ldapTagBindRequest:
+			s.binds = append(s.binds, string(fields[1].content))
+			if string(fields[2].content) != "secret" {
+				reply(result(ldapTagBindResponse, 49, "invalid credentials"))
+				continue
+			}
+			reply(result(ldapTagBindResponse, 0, ""))
+		case ldapTagSearchRequest:
+			filter, _ := fields[6].children()
+			var ops [][]byte
+			for _, entry := range s.members[string(filter[1].content)] {
+				var attributes [][]byte
+				for name, values := range entry.Attributes {
+					var vals [][]byte
+					for _, v := range values {
+						vals = append(vals, berString(berTagOctetString, v))
+					}
+					attributes = append(attributes, berConstructed(berTagSequence,
+						berString(berTagOctetString, name),
+						berConstructed(0x31, vals...),
+					))
+				}
+				ops = append(ops, berConstructed(ldapTagSearchResultEntry,
+					berString(berTagOctetString, entry.DN),
+					berConstructed(berTagSequence, attributes...),
+				))
+			}
+			ops = append(ops, result(ldapTagSearchResultDone, 0, ""))
+			reply(ops...)
+		case ldapTagUnbindRequest:
+			return
+		}
+	}
+}
+
+func (s *S) TestBEREncoding(c *check.C) {
+	c.Assert(berInt(berTagInteger, 3), check.DeepEquals, []byte{0x02, 0x01, 0x03})
+	c.Assert(berInt(berTagInteger, 300), check.DeepEquals, []byte{0x02, 0x02, 0x01, 0x2c})
+	c.Assert(berInt(berTagInteger, 128), check.DeepEquals, []byte{0x02, 0x02, 0x00, 0x80})
+	long := make([]byte, 300)
+	encoded := berEncode(berTagOctetString, long)
+	c.Assert(encoded[:4], check.DeepEquals, []byte{0x04, 0x82, 0x01, 0x2c})
+	element, err := readBERElement(&byteReader{data: encoded})
+	c.Assert(err, check.IsNil)
+	c.Assert(element.tag, check.Equals, byte(berTagOctetString))
+	c.Assert(element.content, check.HasLen, 300)
+	element, err = readBERElement(&byteReader{data: berInt(berTagInteger, 300)})
+	c.Assert(err, check.IsNil)
+	c.Assert(element.int(), check.Equals, 300)
+	_, err = readBERElement(&byteReader{data: []byte{berTagOctetString, 0x84, 0x7f, 0xff, 0xff, 0xff}})
+	c.Assert(err, check.ErrorMatches, "ber element of 2147483647 bytes exceeds the limit of 1048576 bytes")
+}
+
+func (s *S) TestLDAPDirectoryGroupMembers(c *check.C) {
+	srv := newFakeLDAPServer(c, map[string][]ldapEntry{
+		"cn=devs,dc=example,dc=com": {
+			{DN: "uid=alice,dc=example,dc=com", Attributes: map[string][]string{"mail": {"alice@example.com"}}},
+			{DN: "uid=bob,dc=example,dc=com", Attributes: map[string][]string{"Mail": {"bob@example.com"}}},
+			{DN: "uid=nomail,dc=example,dc=com", Attributes: map[string][]string{}},
+		},
+	})
+	defer srv.listener.Close()
+	dir, err := newDirectory(context.TODO(), &syncConfig{
+		url:               srv.url(),
+		bindDN:            "cn=tsuru,dc=example,dc=com",
+		bindPassword:      "secret",
+		baseDN:            "dc=example,dc=com",
+		memberOfAttribute: defaultMemberOfAttribute,
+		emailAttribute:    defaultEmailAttribute,
+		rootCAs:           srv.rootCAs,
+	})
+	c.Assert(err, check.IsNil)
+	defer dir.Close()
+	members, err := dir.groupMembers("cn=devs,dc=example,dc=com")
+	c.Assert(err, check.IsNil)
+	c.Assert(members, check.DeepEquals, []string{"alice@example.com", "bob@example.com"})
+	members, err = dir.groupMembers("cn=other,dc=example,dc=com")
+	c.Assert(err, check.IsNil)
+	c.Assert(members, check.HasLen, 0)
+	c.Assert(srv.binds, check.DeepEquals, []string{"cn=tsuru,dc=example,dc=com"})
+}
+
+func (s *S) TestLDAPDirectoryInvalidCredentials(c *check.C) {
+	srv := newFakeLDAPServer(c, nil)
+	defer srv.listener.Close()
+	_, err := newDirectory(context.TODO(), &syncConfig{
+		url:          srv.url(),
+		bindDN:       "cn=tsuru,dc=example,dc=com",
+		bindPassword: "wrong",
+		rootCAs:      srv.rootCAs,
+	})
+	c.Assert(err, check.ErrorMatches, "unable to bind to ldap: ldap result code 49: invalid credentials")
+}
+
+func (s *S) TestLDAPDirectoryRequiresStartTLS(c *check.C) {
+	srv := newFakeLDAPServer(c, nil)
+	srv.noTLS = true
+	defer srv.listener.Close()
+	_, err := newDirectory(context.TODO(), &syncConfig{
+		url:          srv.url(),
+		bindDN:       "cn=tsuru,dc=example,dc=com",
+		bindPassword: "secret",
+		rootCAs:      srv.rootCAs,
+	})
+	c.Assert(err, check.ErrorMatches, "unable to start tls: ldap result code 2: unsupported extended operation")
+	c.Assert(srv.binds, check.HasLen, 0)
+}
+
+func (s *S) TestLDAPDirectoryUntrustedCertificate(c *check.C) {
+	srv := newFakeLDAPServer(c, nil)
+	defer srv.listener.Close()
+	_, err := newDirectory(context.TODO(), &syncConfig{
+		url:          srv.url(),
+		bindDN:       "cn=tsuru,dc=example,dc=com",
+		bindPassword: "secret",
+	})
+	c.Assert(err, check.ErrorMatches, "unable to start tls: .*certificate.*")
+	c.Assert(srv.binds, check.HasLen, 0)
+}
+
+func (s *S) TestLDAPDirectoryInvalidURL(c *check.C) {
+	_, err := newDirectory(context.TODO(), &syncConfig{url: "http://ldap.example.com"})
+	c.Assert(err, check.ErrorMatches, `invalid ldap url "http://ldap.example.com", the scheme must be ldap or ldaps`)
+}
@@ -0,0 +1,452 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package ldapsync periodically synchronizes the roles of users in tsuru
+// teams with the groups they belong to in an LDAP or Active Directory server.
+package ldapsync
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/api/shutdown"
+	"github.com/tsuru/tsuru/auth"
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/log"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/set"
+	authTypes "github.com/tsuru/tsuru/types/auth"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+)
+
+const (
+	// SyncEventKind is the internal kind of the events created when the
+	// sync changes the roles of users in a team.
+	SyncEventKind = "team.role.ldap-sync"
+
+	// runEventKind is the internal kind of the events holding the lock of
+	// the periodic sync, so that a single API replica runs it.
+	runEventKind = "ldap-sync"
+
+	defaultSyncInterval      = time.Hour
+	defaultMemberOfAttribute = "memberOf"
+	defaultEmailAttribute    = "mail"
+)
+
+var ErrNotConfigured = &tsuruErrors.ValidationError{Message: "ldap sync is not configured"}
+
+// RoleChange is a role, in the context of a team, granted to or revoked from
+// a user by the sync.
+type RoleChange struct {
+	Email string `json:"email"`
+	Role  string `json:"role"`
+	Team  string `json:"team"`
+}
+
+// Report lists the changes needed to make the role assignments match the
+// groups in the directory.
+type Report struct {
+	Add    []RoleChange `json:"add"`
+	Remove []RoleChange `json:"remove"`
+}
+
+type groupMapping struct {
+	group string
+	team  string
+	role  string
+}
+
+type syncConfig struct {
+	url               string
+	bindDN            string
+	bindPassword      string
+	baseDN            string
+	memberOfAttribute string
+	emailAttribute    string
+	rootCAs           *x509.CertPool
+	mappings          []groupMapping
+}
+
+// directory lists the emails of the members of groups.
+type directory interface {
+	groupMembers(group string) ([]string, error)
+	Close() error
+}
+
+var newDirectory = func(ctx context.Context, conf *syncConfig) (directory, error) {
+	conn, err := dialLDAP(ctx, conf.url, defaultLDAPTimeout, conf.rootCAs)
+	if err != nil {
+		return nil, err
+	}
+	if conf.bindDN != "" {
+		err = conn.bind(conf.bindDN, conf.bindPassword)
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return &ldapDirectory{conn: conn, conf: conf}, nil
+}
+
+type ldapDirectory struct {
+	conn *ldapConn
+	conf *syncConfig
+}
+
+func (d *ldapDirectory) groupMembers(group string) ([]string, error) {
+	entries, err := d.conn.search(d.conf.baseDN, d.conf.memberOfAttribute, group, []string{d.conf.emailAttribute})
+	if err != nil {
+		return nil, err
+	}
+	var emails []string
+	for _, entry := range entries {
+		for name, values := range entry.Attributes {
+			if strings.EqualFold(name, d.conf.emailAttribute) && len(values) > 0 {
+				emails = append(emails, values[0])
+			}
+		}
+	}
+	return emails, nil
+}
+
+func (d *ldapDirectory) Close() error {
+	return d.conn.Close()
+}
+
+// Enabled returns whether the ldap sync is configured.
+func Enabled() bool {
+	url, _ := config.GetString("auth:ldap-sync:url")
+	return url != ""
+}
+
+func loadConfig() (*syncConfig, error) {
+	if !Enabled() {
+		return nil, ErrNotConfigured
+	}
+	conf := &syncConfig{
+		memberOfAttribute: defaultMemberOfAttribute,
+		emailAttribute:    defaultEmailAttribute,
+	}
+	conf.url, _ = config.GetString("auth:ldap-sync:url")
+	conf.bindDN, _ = config.GetString("auth:ldap-sync:bind-dn")
+	conf.bindPassword, _ = config.GetString("auth:ldap-sync:bind-password")
+	var err error
+	conf.baseDN, err = config.GetString("auth:ldap-sync:base-dn")
+	if err != nil {
+		return nil, errors.New("auth:ldap-sync:base-dn is required")
+	}
+	if attr, _ := config.GetString("auth:ldap-sync:member-of-attribute"); attr != "" {
+		conf.memberOfAttribute = attr
+	}
+	if attr, _ := config.GetString("auth:ldap-sync:email-attribute"); attr != "" {
+		conf.emailAttribute = attr
+	}
+	if caFile, _ := config.GetString("auth:ldap-sync:ca-file"); caFile != "" {
+		data, err := ioutil.ReadFile(caFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to read auth:ldap-sync:ca-file")
+		}
+		conf.rootCAs = x509.NewCertPool()
+		if !conf.rootCAs.AppendCertsFromPEM(data) {
+			return nil, errors.New("auth:ldap-sync:ca-file has no valid PEM certificates")
+		}
+	}
+	rawMappings, err := config.Get("auth:ldap-sync:mappings")
+	if err != nil {
+		return nil, errors.New("auth:ldap-sync:mappings is required")
+	}
+	entries, ok := rawMappings.([]interface{})
+	if !ok {
+		return nil, errors.New("auth:ldap-sync:mappings must be a list of groups, teams and roles")
+	}
+	for _, entry := range entries {
+		fields, ok := entry.(map[interface{}]interface{})
+		if !ok {
+			return nil, errors.New("auth:ldap-sync:mappings must be a list of groups, teams and roles")
+		}
+		if fields["group"] == nil || fields["team"] == nil || fields["role"] == nil {
+			return nil, errors.New("every entry in auth:ldap-sync:mappings requires a group, a team and a role")
+		}
+		conf.mappings = append(conf.mappings, groupMapping{
+			group: fmt.Sprint(fields["group"]),
+			team:  fmt.Sprint(fields["team"]),
+			role:  fmt.Sprint(fields["role"]),
+		})
+	}
+	return conf, nil
+}
+
+// Plan returns the changes the next sync would apply, without applying them.
+func Plan(ctx context.Context) (*Report, error) {
+	conf, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	dir, err := newDirectory(ctx, conf)
+	if err != nil {
+		return nil, err
+	}
+	defer dir.Close()
+	return plan(conf, dir)
+}
+
+// Sync grants and revokes roles so that the users with the roles of the
+// mappings are the members of the mapped groups.
+func Sync(ctx context.Context) (*Report, error) {
+	report, planErr := Plan(ctx)
+	if report == nil {
+		return nil, planErr
+	}
+	err := apply(report)
+	if err != nil {
+		return report, err
+	}
+	return report, planErr
+}
+
+type assignment struct {
+	role string
+	team string
+}
+
+// plan compares the members of the mapped groups with the users holding the
+// mapped roles. Roles aren't revoked in the teams of groups that couldn't be
+// listed, so that directory errors don't remove access, and roles in teams or
+// of roles that aren't mapped are never changed. Members without a tsuru
+// user are ignored.
+func plan(conf *syncConfig, dir directory) (*Report, error) {
+	wanted := map[assignment]set.Set{}
+	failed := map[assignment]bool{}
+	multi := tsuruErrors.NewMultiError()
+	for _, m := range conf.mappings {
+		a := assignment{role: m.role, team: m.team}
+		if wanted[a] == nil {
+			wanted[a] = set.Set{}
+		}
+		members, err := dir.groupMembers(m.group)
+		if err != nil {
+			multi.Add(errors.Wrapf(err, "unable to list members of group %q", m.group))
+			failed[a] = true
+			continue
+		}
+		wanted[a].Add(members...)
+	}
+	assignments := make([]assignment, 0, len(wanted))
+	for a := range wanted {
+		assignments = append(assignments, a)
+	}
+	sort.Slice(assignments, func(i, j int) bool {
+		if assignments[i].team == assignments[j].team {
+			return assignments[i].role < assignments[j].role
+		}
+		return assignments[i].team < assignments[j].team
+	})
+	report := &Report{}
+	for _, a := range assignments {
+		users, err := auth.ListUsersWithRole(a.role)
+		if err != nil {
+			return nil, err
+		}
+		current := set.Set{}
+		for _, u := range users {
+			for _, r := range u.Roles {
+				if r.Name == a.role && r.ContextValue == a.team {
+					current.Add(u.Email)
+				}
+			}
+		}
+		for _, email := range wanted[a].Sorted() {
+			if current.Includes(email) {
+				continue
+			}
+			_, err = auth.GetUserByEmail(email)
+			if err != nil {
+				if _, ok := err.(*tsuruErrors.ValidationError); ok || err == authTypes.ErrUserNotFound {
+					continue
+				}
+				return nil, err
+			}
+			report.Add = append(report.Add, RoleChange{Email: email, Role: a.role, Team: a.team})
+		}
+		if failed[a] {
+			continue
+		}
+		for _, email := range current.Sorted() {
+			if !wanted[a].Includes(email) {
+				report.Remove = append(report.Remove, RoleChange{Email: email, Role: a.role, Team: a.team})
+			}
+		}
+	}
+	return report, multi.ToError()
+}
+
+func apply(report *Report) error {
+	changesByTeam := map[string]*Report{}
+	teamReport := func(team string) *Report {
+		if changesByTeam[team] == nil {
+			changesByTeam[team] = &Report{}
+		}
+		return changesByTeam[team]
+	}
+	multi := tsuruErrors.NewMultiError()
+	for _, change := range report.Add {
+		err := changeRole(change, true)
+		if err != nil {
+			multi.Add(err)
+			continue
+		}
+		teamReport(change.Team).Add = append(teamReport(change.Team).Add, change)
+	}
+	for _, change := range report.Remove {
+		err := changeRole(change, false)
+		if err != nil {
+			multi.Add(err)
+			continue
+		}
+		teamReport(change.Team).Remove = append(teamReport(change.Team).Remove, change)
+	}
+	for team, changes := range changesByTeam {
+		err := registerSyncEvent(team, changes)
+		if err != nil {
+			multi.Add(errors.Wrapf(err, "unable to register ldap sync event for team %q", team))
+		}
+	}
+	return multi.ToError()
+}
+
+func changeRole(change RoleChange, add bool) error {
+	user, err := auth.GetUserByEmail(change.Email)
+	if err != nil {
+		return errors.Wrapf(err, "unable to find user %q", change.Email)
+	}
+	if add {
+		log.Debugf("[ldap sync] granting role %q in team %q to %q", change.Role, change.Team, change.Email)
+		err = user.AddRole(change.Role, change.Team)
+	} else {
+		log.Debugf("[ldap sync] revoking role %q in team %q from %q", change.Role, change.Team, change.Email)
+		err = user.RemoveRole(change.Role, change.Team)
+	}
+	return errors.Wrapf(err, "unable to change role %q in team %q of user %q", change.Role, change.Team, change.Email)
+}
+
+func registerSyncEvent(team string, changes *Report) error {
+	evt, err := event.NewInternal(&event.Opts{
+		Target:       event.Target{Type: event.TargetTypeTeam, Value: team},
+		InternalKind: SyncEventKind,
+		DisableLock:  true,
+		CustomData:   changes,
+		Allowed:      event.Allowed(permission.PermTeamReadEvents, permission.Context(permTypes.CtxTeam, team)),
+	})
+	if err != nil {
+		return err
+	}
+	return evt.Done(nil)
+}
+
+var runTarget = event.Target{Type: event.TargetTypeGlobal, Value: runEventKind}
+
+// runPeriodicSync runs the sync holding the lock of an internal event, it's
+// skipped while another API replica is syncing or when the last sync ran
+// less than an interval ago.
+func runPeriodicSync(ctx context.Context) error {
+	evt, err := event.NewInternal(&event.Opts{
+		Target:       runTarget,
+		InternalKind: runEventKind,
+		Allowed:      event.Allowed(permission.PermRoleReadLdapSync),
+	})
+	if err != nil {
+		if _, ok := err.(event.ErrEventLocked); ok {
+			return nil
+		}
+		return errors.Wrap(err, "could not create event")
+	}
+	running := false
+	last, err := event.List(&event.Filter{
+		Target:    runTarget,
+		KindNames: []string{runEventKind},
+		Running:   &running,
+		Limit:     1,
+	})
+	if err != nil {
+		evt.Abort()
+		return err
+	}
+	if len(last) > 0 && time.Since(last[0].StartTime) < syncInterval() {
+		return evt.Abort()
+	}
+	report, err := Sync(ctx)
+	if report != nil {
+		log.Debugf("[ldap sync] %d roles granted, %d roles revoked", len(report.Add), len(report.Remove))
+	}
+	evt.Done(err)
+	return err
+}
+
+func syncInterval() time.Duration {
+	interval, err := config.GetDuration("auth:ldap-sync:interval")
+	if err != nil || interval <= 0 {
+		return defaultSyncInterval
+	}
+	return interval
+}
+
+func Initialize() error {
+	if !Enabled() {
+		return nil
+	}
+	_, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	s := &syncer{once: &sync.Once{}}
+	s.start()
+	shutdown.Register(s)
+	return nil
+}
+
+// syncer periodically runs the ldap sync.
+type syncer struct {
+	once   *sync.Once
+	stopCh chan struct{}
+}
+
+func (s *syncer) start() {
+	s.once.Do(func() {
+		s.stopCh = make(chan struct{})
+		go s.spin()
+	})
+}
+
+func (s *syncer) Shutdown(ctx context.Context) error {
+	if s.stopCh == nil {
+		return nil
+	}
+	s.stopCh <- struct{}{}
+	s.stopCh = nil
+	s.once = &sync.Once{}
+	return nil
+}
+
+func (s *syncer) spin() {
+	for {
+		err := runPeriodicSync(context.Background())
+		if err != nil {
+			log.Errorf("[ldap sync] %v", err)
+		}
+		select {
+		case <-s.stopCh:
+			return
+		case <-time.After(syncInterval()):
+		}
+	}
+}
@@ -0,0 +1,175 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ldapsync
+
+import (
+	"context"
+	"errors"
+
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/event/eventtest"
+	"github.com/tsuru/tsuru/permission"
+	check "gopkg.in/check.v1"
+)
+
+type fakeDirectory struct {
+	members map[string][]string
+	closed  bool
+}
+
+func (d *fakeDirectory) groupMembers(group string) ([]string, error) {
+	members, ok := d.members[group]
+	if !ok {
+		return nil, errors.New("group not found")
+	}
+	return members, nil
+}
+
+func (d *fakeDirectory) Close() error {
+	d.closed = true
+	return nil
+}
+
+func (s *S) setFakeDirectory(members map[string][]string) *fakeDirectory {
+	config.Set("auth:ldap-sync:url", "ldap://ldap.example.com")
+	config.Set("auth:ldap-sync:base-dn", "dc=example,dc=com")
+	config.Set("auth:ldap-sync:mappings", []interface{}{
+		map[interface{}]interface{}{"group": "cn=devs", "team": "devs", "role": "team-member"},
+		map[interface{}]interface{}{"group": "cn=sre", "team": "sre", "role": "team-member"},
+	})
+	dir := &fakeDirectory{members: members}
+	newDirectory = func(ctx context.Context, conf *syncConfig) (directory, error) {
+		return dir, nil
+	}
+	return dir
+}
+
+func createUser(c *check.C, email string, roles ...[2]string) *auth.User {
+	u := &auth.User{Email: email}
+	err := u.Create()
+	c.Assert(err, check.IsNil)
+	for _, r := range roles {
+		err = u.AddRole(r[0], r[1])
+		c.Assert(err, check.IsNil)
+	}
+	return u
+}
+
+func (s *S) TestPlan(c *check.C) {
+	dir := s.setFakeDirectory(map[string][]string{
+		"cn=devs": {"alice@example.com", "bob@example.com", "unknown@example.com"},
+		"cn=sre":  {"carol@example.com"},
+	})
+	createUser(c, "alice@example.com", [2]string{"team-member", "devs"})
+	createUser(c, "bob@example.com")
+	createUser(c, "carol@example.com")
+	createUser(c, "dave@example.com", [2]string{"team-member", "devs"}, [2]string{"team-member", "unmapped"})
+	report, err := Plan(context.TODO())
+	c.Assert(err, check.IsNil)
+	c.Assert(dir.closed, check.Equals, true)
+	c.Assert(report, check.DeepEquals, &Report{
+		Add: []RoleChange{
+			{Email: "bob@example.com", Role: "team-member", Team: "devs"},
+			{Email: "carol@example.com", Role: "team-member", Team: "sre"},
+		},
+		Remove: []RoleChange{
+			{Email: "dave@example.com", Role: "team-member", Team: "devs"},
+		},
+	})
+	u, err := auth.GetUserByEmail("bob@example.com")
+	c.Assert(err, check.IsNil)
+	c.Assert(u.Roles, check.HasLen, 0)
+}
+
+func (s *S) TestPlanDoesNotRevokeRolesOfFailedGroups(c *check.C) {
+	s.setFakeDirectory(map[string][]string{
+		"cn=sre": {"carol@example.com"},
+	})
+	createUser(c, "dave@example.com", [2]string{"team-member", "devs"})
+	createUser(c, "carol@example.com")
+	report, err := Plan(context.TODO())
+	c.Assert(err, check.ErrorMatches, `(?s).*unable to list members of group "cn=devs": group not found.*`)
+	c.Assert(report, check.DeepEquals, &Report{
+		Add: []RoleChange{
+			{Email: "carol@example.com", Role: "team-member", Team: "sre"},
+		},
+	})
+}
+
+func (s *S) TestPlanNotConfigured(c *check.C) {
+	_, err := Plan(context.TODO())
+	c.Assert(err, check.Equals, ErrNotConfigured)
+}
+
+func (s *S) TestSync(c *check.C) {
+	s.setFakeDirectory(map[string][]string{
+		"cn=devs": {"bob@example.com"},
+		"cn=sre":  {},
+	})
+	createUser(c, "bob@example.com")
+	createUser(c, "dave@example.com", [2]string{"team-member", "devs"}, [2]string{"team-member", "unmapped"})
+	report, err := Sync(context.TODO())
+	c.Assert(err, check.IsNil)
+	c.Assert(report.Add, check.HasLen, 1)
+	c.Assert(report.Remove, check.HasLen, 1)
+	u, err := auth.GetUserByEmail("bob@example.com")
+	c.Assert(err, check.IsNil)
+	c.Assert(u.Roles, check.HasLen, 1)
+	c.Assert(u.Roles[0].Name, check.Equals, "team-member")
+	c.Assert(u.Roles[0].ContextValue, check.Equals, "devs")
+	u, err = auth.GetUserByEmail("dave@example.com")
+	c.Assert(err, check.IsNil)
+	c.Assert(u.Roles, check.HasLen, 1)
+	c.Assert(u.Roles[0].ContextValue, check.Equals, "unmapped")
+	c.Assert(eventtest.EventDesc{
+		Target: event.Target{Type: event.TargetTypeTeam, Value: "devs"},
+		Kind:   SyncEventKind,
+	}, eventtest.HasEvent)
+	report, err = Plan(context.TODO())
+	c.Assert(err, check.IsNil)
+	c.Assert(report, check.DeepEquals, &Report{})
+}
+
+func (s *S) TestRunPeriodicSyncSkipsRecentSync(c *check.C) {
+	s.setFakeDirectory(map[string][]string{
+		"cn=devs": {"alice@example.com"},
+		"cn=sre":  {},
+	})
+	createUser(c, "alice@example.com")
+	err := runPeriodicSync(context.TODO())
+	c.Assert(err, check.IsNil)
+	alice, err := auth.GetUserByEmail("alice@example.com")
+	c.Assert(err, check.IsNil)
+	c.Assert(alice.Roles, check.HasLen, 1)
+	err = alice.RemoveRole("team-member", "devs")
+	c.Assert(err, check.IsNil)
+	err = runPeriodicSync(context.TODO())
+	c.Assert(err, check.IsNil)
+	alice, err = auth.GetUserByEmail("alice@example.com")
+	c.Assert(err, check.IsNil)
+	c.Assert(alice.Roles, check.HasLen, 0)
+}
+
+func (s *S) TestRunPeriodicSyncLocked(c *check.C) {
+	s.setFakeDirectory(map[string][]string{
+		"cn=devs": {"alice@example.com"},
+		"cn=sre":  {},
+	})
+	createUser(c, "alice@example.com")
+	evt, err := event.NewInternal(&event.Opts{
+		Target:       runTarget,
+		InternalKind: runEventKind,
+		Allowed:      event.Allowed(permission.PermRoleReadLdapSync),
+	})
+	c.Assert(err, check.IsNil)
+	defer evt.Abort()
+	err = runPeriodicSync(context.TODO())
+	c.Assert(err, check.IsNil)
+	alice, err := auth.GetUserByEmail("alice@example.com")
+	c.Assert(err, check.IsNil)
+	c.Assert(alice.Roles, check.HasLen, 0)
+}
@@ -0,0 +1,158 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package native
+
+import (
+	"context"
+	"time"
+
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/db"
+	"github.com/tsuru/tsuru/log"
+)
+
+const (
+	defaultLockoutDuration    = 5 * time.Minute
+	defaultLockoutMaxDuration = time.Hour
+)
+
+var _ auth.LockoutScheme = NativeScheme{}
+
+// loginFailures holds the failed login attempts of a user. Once the user
+// reaches the configured number of failures, each new failure locks the user
+// out for twice as long as the previous one.
+type loginFailures struct {
+	Email       string `bson:"_id"`
+	Failures    int
+	LastFailure time.Time
+	LockedUntil time.Time
+}
+
+type lockoutConfig struct {
+	maxFailures int
+	duration    time.Duration
+	maxDuration time.Duration
+}
+
+// getLockoutConfig reads the auth:lockout settings, the lockout is disabled
+// when auth:lockout:max-failures isn't set.
+func getLockoutConfig() lockoutConfig {
+	conf := lockoutConfig{
+		duration:    defaultLockoutDuration,
+		maxDuration: defaultLockoutMaxDuration,
+	}
+	conf.maxFailures, _ = config.GetInt("auth:lockout:max-failures")
+	if d, err := config.GetDuration("auth:lockout:duration"); err == nil && d > 0 {
+		conf.duration = d
+	}
+	if d, err := config.GetDuration("auth:lockout:max-duration"); err == nil && d > 0 {
+		conf.maxDuration = d
+	}
+	if conf.maxDuration < conf.duration {
+		conf.maxDuration = conf.duration
+	}
+	return conf
+}
+
+func (c lockoutConfig) enabled() bool {
+	return c.maxFailures > 0
+}
+
+func (c lockoutConfig) lockDuration(failures int) time.Duration {
+	d := c.duration
+	for i := c.maxFailures; i < failures && d < c.maxDuration; i++ {
+		d *= 2
+	}
+	if d > c.maxDuration {
+		return c.maxDuration
+	}
+	return d
+}
+
+func checkLockout(email string) error {
+	if !getLockoutConfig().enabled() {
+		return nil
+	}
+	conn, err := db.Conn()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	var f loginFailures
+	err = conn.LoginFailures().FindId(email).One(&f)
+	if err == mgo.ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if now().Before(f.LockedUntil) {
+		return &auth.AccountLockedError{Until: f.LockedUntil}
+	}
+	return nil
+}
+
+// registerLoginFailure counts a failed login attempt of the user, failures
+// are forgotten once the user goes through the maximum lockout duration
+// without new ones.
+func registerLoginFailure(email string) error {
+	conf := getLockoutConfig()
+	if !conf.enabled() {
+		return nil
+	}
+	conn, err := db.Conn()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	current := now()
+	coll := conn.LoginFailures()
+	err = coll.Remove(bson.M{
+		"_id":         email,
+		"lastfailure": bson.M{"$lt": current.Add(-conf.maxDuration)},
+		"lockeduntil": bson.M{"$lt": current},
+	})
+	if err != nil && err != mgo.ErrNotFound {
+		return err
+	}
+	var f loginFailures
+	_, err = coll.FindId(email).Apply(mgo.Change{
+		Update: bson.M{
+			"$inc": bson.M{"failures": 1},
+			"$set": bson.M{"lastfailure": current},
+		},
+		Upsert:    true,
+		ReturnNew: true,
+	}, &f)
+	if err != nil {
+		return err
+	}
+	if f.Failures < conf.maxFailures {
+		return nil
+	}
+	until := current.Add(conf.lockDuration(f.Failures))
+	log.Debugf("[lockout] user %q locked out until %s after %d failed login attempts", email, until, f.Failures)
+	return coll.UpdateId(email, bson.M{"$max": bson.M{"lockeduntil": until}})
+}
+
+func resetLoginFailures(email string) error {
+	conn, err := db.Conn()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	err = conn.LoginFailures().RemoveId(email)
+	if err == mgo.ErrNotFound {
+		return nil
+	}
+	return err
+}
+
+func (s NativeScheme) Unlock(ctx context.Context, user *auth.User) error {
+	return resetLoginFailures(user.Email)
+}
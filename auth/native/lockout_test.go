@@ -0,0 +1,110 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package native
+
+import (
+	"context"
+	"time"
+
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/auth"
+	check "gopkg.in/check.v1"
+)
+
+func enableLockout(maxFailures int) func() {
+	config.Set("auth:lockout:max-failures", maxFailures)
+	config.Set("auth:lockout:duration", "5m")
+	config.Set("auth:lockout:max-duration", "1h")
+	return func() {
+		config.Unset("auth:lockout")
+	}
+}
+
+func (s *S) login(password string) error {
+	_, err := nativeScheme.Login(context.TODO(), map[string]string{"email": s.user.Email, "password": password})
+	return err
+}
+
+func (s *S) TestLockoutConfigLockDuration(c *check.C) {
+	conf := lockoutConfig{maxFailures: 3, duration: 5 * time.Minute, maxDuration: time.Hour}
+	c.Assert(conf.lockDuration(3), check.Equals, 5*time.Minute)
+	c.Assert(conf.lockDuration(4), check.Equals, 10*time.Minute)
+	c.Assert(conf.lockDuration(5), check.Equals, 20*time.Minute)
+	c.Assert(conf.lockDuration(7), check.Equals, time.Hour)
+	c.Assert(conf.lockDuration(100), check.Equals, time.Hour)
+}
+
+func (s *S) TestLockoutDisabledByDefault(c *check.C) {
+	for i := 0; i < 10; i++ {
+		err := s.login("wrong-password")
+		c.Assert(err, check.FitsTypeOf, auth.AuthenticationFailure{})
+	}
+	c.Assert(s.login("123456"), check.IsNil)
+}
+
+func (s *S) TestLoginLockedOutAfterFailures(c *check.C) {
+	defer enableLockout(3)()
+	current := time.Now()
+	now = func() time.Time { return current }
+	defer func() { now = time.Now }()
+	for i := 0; i < 3; i++ {
+		err := s.login("wrong-password")
+		c.Assert(err, check.FitsTypeOf, auth.AuthenticationFailure{})
+	}
+	err := s.login("123456")
+	c.Assert(err, check.FitsTypeOf, &auth.AccountLockedError{})
+	c.Assert(err.(*auth.AccountLockedError).Until.Sub(current) > 4*time.Minute, check.Equals, true)
+	now = func() time.Time { return current.Add(6 * time.Minute) }
+	err = s.login("wrong-password")
+	c.Assert(err, check.FitsTypeOf, auth.AuthenticationFailure{})
+	now = func() time.Time { return current.Add(12 * time.Minute) }
+	err = s.login("123456")
+	c.Assert(err, check.FitsTypeOf, &auth.AccountLockedError{})
+	now = func() time.Time { return current.Add(17 * time.Minute) }
+	c.Assert(s.login("123456"), check.IsNil)
+	n, err := s.conn.LoginFailures().FindId(s.user.Email).Count()
+	c.Assert(err, check.IsNil)
+	c.Assert(n, check.Equals, 0)
+}
+
+func (s *S) TestLoginFailuresResetOnSuccess(c *check.C) {
+	defer enableLockout(3)()
+	c.Assert(s.login("wrong-password"), check.NotNil)
+	c.Assert(s.login("wrong-password"), check.NotNil)
+	c.Assert(s.login("123456"), check.IsNil)
+	c.Assert(s.login("wrong-password"), check.NotNil)
+	c.Assert(s.login("wrong-password"), check.NotNil)
+	c.Assert(s.login("123456"), check.IsNil)
+}
+
+func (s *S) TestLoginFailuresExpire(c *check.C) {
+	defer enableLockout(3)()
+	current := time.Now()
+	now = func() time.Time { return current }
+	defer func() { now = time.Now }()
+	c.Assert(s.login("wrong-password"), check.NotNil)
+	c.Assert(s.login("wrong-password"), check.NotNil)
+	now = func() time.Time { return current.Add(2 * time.Hour) }
+	c.Assert(s.login("wrong-password"), check.NotNil)
+	var f loginFailures
+	err := s.conn.LoginFailures().FindId(s.user.Email).One(&f)
+	c.Assert(err, check.IsNil)
+	c.Assert(f.Failures, check.Equals, 1)
+	c.Assert(f.LockedUntil.IsZero(), check.Equals, true)
+}
+
+func (s *S) TestUnlock(c *check.C) {
+	defer enableLockout(1)()
+	c.Assert(s.login("wrong-password"), check.NotNil)
+	c.Assert(s.login("123456"), check.FitsTypeOf, &auth.AccountLockedError{})
+	err := nativeScheme.Unlock(context.TODO(), s.user)
+	c.Assert(err, check.IsNil)
+	c.Assert(s.login("123456"), check.IsNil)
+}
+
+func (s *S) TestUnlockNotLocked(c *check.C) {
+	err := nativeScheme.Unlock(context.TODO(), s.user)
+	c.Assert(err, check.IsNil)
+}
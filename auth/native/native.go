@@ -7,9 +7,11 @@ package native
 import (
 	"context"
 
+	"github.com/globalsign/mgo"
 	"github.com/tsuru/tsuru/auth"
 	"github.com/tsuru/tsuru/db"
 	"github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/log"
 	"github.com/tsuru/tsuru/validation"
 )
 
@@ -46,10 +48,22 @@ func (s NativeScheme) Login(ctx context.Context, params map[string]string) (auth
 	if err != nil {
 		return nil, err
 	}
-	token, err := createToken(user, password)
+	err = checkLockout(user.Email)
 	if err != nil {
 		return nil, err
 	}
+	token, err := createToken(user, password, params["otp"])
+	if err != nil {
+		if _, ok := err.(auth.AuthenticationFailure); ok {
+			if lockErr := registerLoginFailure(user.Email); lockErr != nil {
+				log.Errorf("unable to register failed login of %q: %v", user.Email, lockErr)
+			}
+		}
+		return nil, err
+	}
+	if err = resetLoginFailures(user.Email); err != nil {
+		log.Errorf("unable to reset failed logins of %q: %v", user.Email, err)
+	}
 	return token, nil
 }
 
@@ -73,8 +87,9 @@ func (s NativeScheme) Create(ctx context.Context, user *auth.User) (*auth.User,
 	if !validation.ValidateEmail(user.Email) {
 		return nil, ErrInvalidEmail
 	}
-	if !validation.ValidateLength(user.Password, passwordMinLen, passwordMaxLen) {
-		return nil, ErrInvalidPassword
+	policy := getPasswordPolicy()
+	if err := policy.validate(user.Password); err != nil {
+		return nil, err
 	}
 	if _, err := auth.GetUserByEmail(user.Email); err == nil {
 		return nil, ErrEmailRegistered
@@ -85,6 +100,9 @@ func (s NativeScheme) Create(ctx context.Context, user *auth.User) (*auth.User,
 	if err := user.Create(); err != nil {
 		return nil, err
 	}
+	if err := policy.recordPassword(user.Email, user.Password); err != nil {
+		return nil, err
+	}
 	return user, nil
 }
 
@@ -96,12 +114,21 @@ func (s NativeScheme) ChangePassword(ctx context.Context, token auth.Token, oldP
 	if err = checkPassword(user.Password, oldPassword); err != nil {
 		return ErrPasswordMismatch
 	}
-	if !validation.ValidateLength(newPassword, passwordMinLen, passwordMaxLen) {
-		return ErrInvalidPassword
+	policy := getPasswordPolicy()
+	if err = policy.validate(newPassword); err != nil {
+		return err
+	}
+	if err = policy.checkHistory(user.Email, user.Password, newPassword); err != nil {
+		return err
 	}
 	user.Password = newPassword
-	hashPassword(user)
-	return user.Update()
+	if err = hashPassword(user); err != nil {
+		return err
+	}
+	if err = user.Update(); err != nil {
+		return err
+	}
+	return policy.recordPassword(user.Email, user.Password)
 }
 
 func (s NativeScheme) StartPasswordReset(ctx context.Context, user *auth.User) error {
@@ -132,13 +159,17 @@ func (s NativeScheme) ResetPassword(ctx context.Context, user *auth.User, resetT
 	if passToken.UserEmail != user.Email {
 		return auth.ErrInvalidToken
 	}
-	password := generatePassword(12)
+	policy := getPasswordPolicy()
+	password := policy.generate()
 	user.Password = password
 	hashPassword(user)
 	go sendNewPassword(user, password)
 	passToken.Used = true
 	conn.PasswordTokens().UpdateId(passToken.Token, passToken)
-	return user.Update()
+	if err = user.Update(); err != nil {
+		return err
+	}
+	return policy.recordPassword(user.Email, user.Password)
 }
 
 func (s NativeScheme) Remove(ctx context.Context, u *auth.User) error {
@@ -146,6 +177,18 @@ func (s NativeScheme) Remove(ctx context.Context, u *auth.User) error {
 	if err != nil {
 		return err
 	}
+	err = removeTwoFactor(u.Email)
+	if err != nil && err != mgo.ErrNotFound {
+		return err
+	}
+	err = resetLoginFailures(u.Email)
+	if err != nil {
+		return err
+	}
+	err = removePasswordRecord(u.Email)
+	if err != nil {
+		return err
+	}
 	return u.Delete()
 }
 
@@ -0,0 +1,240 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package native
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/db"
+	"github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/validation"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// bcryptMaxLen is the maximum length of passwords hashed by bcrypt, longer
+// ones can't be checked.
+const bcryptMaxLen = 72
+
+var ErrPasswordExpired = &errors.NotAuthorizedError{Message: "your password has expired, reset it to log in again"}
+
+type passwordPolicy struct {
+	minLength        int
+	maxLength        int
+	requireUppercase bool
+	requireLowercase bool
+	requireDigit     bool
+	requireSymbol    bool
+	history          int
+	maxAge           time.Duration
+}
+
+// passwordRecord holds the hashes of the last passwords of a user, newest
+// last, and when the password was last changed.
+type passwordRecord struct {
+	Email     string `bson:"_id"`
+	Hashes    []string
+	ChangedAt time.Time
+}
+
+// getPasswordPolicy reads the auth:password settings, by default passwords
+// only need to be between 6 and 50 characters long.
+func getPasswordPolicy() passwordPolicy {
+	p := passwordPolicy{
+		minLength: passwordMinLen,
+		maxLength: passwordMaxLen,
+	}
+	if v, err := config.GetInt("auth:password:min-length"); err == nil && v > 0 {
+		p.minLength = v
+	}
+	if v, err := config.GetInt("auth:password:max-length"); err == nil && v > 0 {
+		p.maxLength = v
+	}
+	if p.maxLength > bcryptMaxLen {
+		p.maxLength = bcryptMaxLen
+	}
+	if p.minLength > p.maxLength {
+		p.minLength = p.maxLength
+	}
+	p.requireUppercase, _ = config.GetBool("auth:password:require-uppercase")
+	p.requireLowercase, _ = config.GetBool("auth:password:require-lowercase")
+	p.requireDigit, _ = config.GetBool("auth:password:require-digit")
+	p.requireSymbol, _ = config.GetBool("auth:password:require-symbol")
+	if v, err := config.GetInt("auth:password:history"); err == nil && v > 0 {
+		p.history = v
+	}
+	if d, err := config.GetDuration("auth:password:max-age"); err == nil && d > 0 {
+		p.maxAge = d
+	}
+	return p
+}
+
+func (p passwordPolicy) validLength(password string) bool {
+	return validation.ValidateLength(password, p.minLength, p.maxLength)
+}
+
+// validate checks the length and the complexity of the password, it doesn't
+// check the history of the user.
+func (p passwordPolicy) validate(password string) error {
+	if !p.validLength(password) {
+		if p.minLength == passwordMinLen && p.maxLength == passwordMaxLen {
+			return ErrInvalidPassword
+		}
+		return &errors.ValidationError{
+			Message: fmt.Sprintf("password length should be least %d characters and at most %d characters", p.minLength, p.maxLength),
+		}
+	}
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			symbol = true
+		}
+	}
+	var missing []string
+	if p.requireUppercase && !upper {
+		missing = append(missing, "an uppercase letter")
+	}
+	if p.requireLowercase && !lower {
+		missing = append(missing, "a lowercase letter")
+	}
+	if p.requireDigit && !digit {
+		missing = append(missing, "a digit")
+	}
+	if p.requireSymbol && !symbol {
+		missing = append(missing, "a symbol")
+	}
+	if len(missing) > 0 {
+		return &errors.ValidationError{
+			Message: "password must contain at least " + strings.Join(missing, ", "),
+		}
+	}
+	return nil
+}
+
+// checkHistory ensures the password isn't one of the last passwords of the
+// user, including the current one, whose hash is always checked.
+func (p passwordPolicy) checkHistory(email, currentHash, password string) error {
+	if p.history == 0 {
+		return nil
+	}
+	record, err := getPasswordRecord(email)
+	if err != nil {
+		return err
+	}
+	hashes := record.Hashes
+	if len(hashes) > p.history {
+		hashes = hashes[len(hashes)-p.history:]
+	}
+	hashes = append(hashes, currentHash)
+	for _, hash := range hashes {
+		if bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil {
+			return &errors.ValidationError{
+				Message: fmt.Sprintf("password must not be one of the last %d passwords", p.history),
+			}
+		}
+	}
+	return nil
+}
+
+// checkExpiration fails when the password of the user is older than the
+// maximum age. Passwords set before the policy was enabled start aging on
+// the first check.
+func (p passwordPolicy) checkExpiration(email string) error {
+	if p.maxAge == 0 {
+		return nil
+	}
+	conn, err := db.Conn()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	var record passwordRecord
+	_, err = conn.PasswordHistory().FindId(email).Apply(mgo.Change{
+		Update:    bson.M{"$setOnInsert": bson.M{"changedat": now()}},
+		Upsert:    true,
+		ReturnNew: true,
+	}, &record)
+	if err != nil {
+		return err
+	}
+	if now().After(record.ChangedAt.Add(p.maxAge)) {
+		return ErrPasswordExpired
+	}
+	return nil
+}
+
+// recordPassword stores the hash of the new password of the user, keeping
+// as many hashes as the history requires.
+func (p passwordPolicy) recordPassword(email, hash string) error {
+	conn, err := db.Conn()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	update := bson.M{"$set": bson.M{"changedat": now()}}
+	if p.history > 0 {
+		update["$push"] = bson.M{"hashes": bson.M{"$each": []string{hash}, "$slice": -p.history}}
+	} else {
+		update["$unset"] = bson.M{"hashes": ""}
+	}
+	_, err = conn.PasswordHistory().UpsertId(email, update)
+	return err
+}
+
+// generate returns a random password complying with the policy.
+func (p passwordPolicy) generate() string {
+	length := 12
+	if length < p.minLength {
+		length = p.minLength
+	}
+	if length > p.maxLength {
+		length = p.maxLength
+	}
+	for {
+		password := generatePassword(length)
+		if p.validate(password) == nil {
+			return password
+		}
+	}
+}
+
+func getPasswordRecord(email string) (passwordRecord, error) {
+	conn, err := db.Conn()
+	if err != nil {
+		return passwordRecord{}, err
+	}
+	defer conn.Close()
+	var record passwordRecord
+	err = conn.PasswordHistory().FindId(email).One(&record)
+	if err == mgo.ErrNotFound {
+		return passwordRecord{}, nil
+	}
+	return record, err
+}
+
+func removePasswordRecord(email string) error {
+	conn, err := db.Conn()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	err = conn.PasswordHistory().RemoveId(email)
+	if err == mgo.ErrNotFound {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,158 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package native
+
+import (
+	"context"
+	"time"
+
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/errors"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestGetPasswordPolicyDefaults(c *check.C) {
+	c.Assert(getPasswordPolicy(), check.DeepEquals, passwordPolicy{minLength: 6, maxLength: 50})
+}
+
+func (s *S) TestGetPasswordPolicy(c *check.C) {
+	config.Set("auth:password:min-length", 10)
+	config.Set("auth:password:max-length", 100)
+	config.Set("auth:password:require-uppercase", true)
+	config.Set("auth:password:require-digit", true)
+	config.Set("auth:password:history", 3)
+	config.Set("auth:password:max-age", "720h")
+	defer config.Unset("auth:password")
+	c.Assert(getPasswordPolicy(), check.DeepEquals, passwordPolicy{
+		minLength:        10,
+		maxLength:        bcryptMaxLen,
+		requireUppercase: true,
+		requireDigit:     true,
+		history:          3,
+		maxAge:           720 * time.Hour,
+	})
+}
+
+func (s *S) TestPasswordPolicyValidate(c *check.C) {
+	p := passwordPolicy{
+		minLength:        8,
+		maxLength:        20,
+		requireUppercase: true,
+		requireLowercase: true,
+		requireDigit:     true,
+		requireSymbol:    true,
+	}
+	tests := []struct {
+		password string
+		message  string
+	}{
+		{"Ab1$", "password length should be least 8 characters and at most 20 characters"},
+		{"Abcdefgh1$Abcdefgh1$x", "password length should be least 8 characters and at most 20 characters"},
+		{"abcdefgh", "password must contain at least an uppercase letter, a digit, a symbol"},
+		{"ABCDEFG1", "password must contain at least a lowercase letter, a symbol"},
+		{"Abcdefg1", "password must contain at least a symbol"},
+		{"Abcdefg1$", ""},
+		{"Abc defg1", ""},
+	}
+	for _, tt := range tests {
+		err := p.validate(tt.password)
+		if tt.message == "" {
+			c.Check(err, check.IsNil, check.Commentf("password %q", tt.password))
+			continue
+		}
+		c.Check(err, check.FitsTypeOf, &errors.ValidationError{})
+		c.Check(err, check.ErrorMatches, tt.message, check.Commentf("password %q", tt.password))
+	}
+}
+
+func (s *S) TestPasswordPolicyValidateDefaultLength(c *check.C) {
+	p := passwordPolicy{minLength: passwordMinLen, maxLength: passwordMaxLen}
+	c.Assert(p.validate("12345"), check.Equals, ErrInvalidPassword)
+	c.Assert(p.validate("123456"), check.IsNil)
+}
+
+func (s *S) TestPasswordPolicyGenerate(c *check.C) {
+	p := passwordPolicy{
+		minLength:        16,
+		maxLength:        20,
+		requireUppercase: true,
+		requireLowercase: true,
+		requireDigit:     true,
+		requireSymbol:    true,
+	}
+	for i := 0; i < 20; i++ {
+		password := p.generate()
+		c.Assert(password, check.HasLen, 16)
+		c.Assert(p.validate(password), check.IsNil)
+	}
+}
+
+func (s *S) TestNativeCreateEnforcesPasswordPolicy(c *check.C) {
+	config.Set("auth:password:require-digit", true)
+	defer config.Unset("auth:password")
+	user := &auth.User{Email: "x@x.com", Password: "abcdefgh"}
+	_, err := nativeScheme.Create(context.TODO(), user)
+	c.Assert(err, check.ErrorMatches, "password must contain at least a digit")
+	_, err = auth.GetUserByEmail("x@x.com")
+	c.Assert(err, check.NotNil)
+	user = &auth.User{Email: "x@x.com", Password: "abcdefg1"}
+	_, err = nativeScheme.Create(context.TODO(), user)
+	c.Assert(err, check.IsNil)
+}
+
+func (s *S) TestChangePasswordEnforcesPasswordPolicy(c *check.C) {
+	config.Set("auth:password:min-length", 8)
+	defer config.Unset("auth:password")
+	err := nativeScheme.ChangePassword(context.TODO(), s.token, "123456", "1234567")
+	c.Assert(err, check.ErrorMatches, "password length should be least 8 characters and at most 50 characters")
+	err = nativeScheme.ChangePassword(context.TODO(), s.token, "123456", "12345678")
+	c.Assert(err, check.IsNil)
+}
+
+func (s *S) TestChangePasswordHistory(c *check.C) {
+	config.Set("auth:password:history", 2)
+	defer config.Unset("auth:password")
+	err := nativeScheme.ChangePassword(context.TODO(), s.token, "123456", "123456")
+	c.Assert(err, check.ErrorMatches, "password must not be one of the last 2 passwords")
+	err = nativeScheme.ChangePassword(context.TODO(), s.token, "123456", "abcdef")
+	c.Assert(err, check.IsNil)
+	err = nativeScheme.ChangePassword(context.TODO(), s.token, "abcdef", "123456")
+	c.Assert(err, check.ErrorMatches, "password must not be one of the last 2 passwords")
+	err = nativeScheme.ChangePassword(context.TODO(), s.token, "abcdef", "ghijkl")
+	c.Assert(err, check.IsNil)
+	err = nativeScheme.ChangePassword(context.TODO(), s.token, "ghijkl", "123456")
+	c.Assert(err, check.IsNil)
+	record, err := getPasswordRecord(s.user.Email)
+	c.Assert(err, check.IsNil)
+	c.Assert(record.Hashes, check.HasLen, 2)
+}
+
+func (s *S) TestLoginPasswordExpired(c *check.C) {
+	config.Set("auth:password:max-age", "720h")
+	defer config.Unset("auth:password")
+	current := time.Now()
+	now = func() time.Time { return current }
+	defer func() { now = time.Now }()
+	err := nativeScheme.ChangePassword(context.TODO(), s.token, "123456", "abcdef")
+	c.Assert(err, check.IsNil)
+	now = func() time.Time { return current.Add(719 * time.Hour) }
+	c.Assert(s.login("abcdef"), check.IsNil)
+	now = func() time.Time { return current.Add(721 * time.Hour) }
+	c.Assert(s.login("abcdef"), check.Equals, ErrPasswordExpired)
+}
+
+func (s *S) TestLoginPasswordExpiredStartsAgingWithoutRecord(c *check.C) {
+	err := removePasswordRecord(s.user.Email)
+	c.Assert(err, check.IsNil)
+	config.Set("auth:password:max-age", "720h")
+	defer config.Unset("auth:password")
+	current := time.Now()
+	now = func() time.Time { return current }
+	defer func() { now = time.Now }()
+	c.Assert(s.login("123456"), check.IsNil)
+	now = func() time.Time { return current.Add(721 * time.Hour) }
+	c.Assert(s.login("123456"), check.Equals, ErrPasswordExpired)
+}
@@ -0,0 +1,92 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package native
+
+import (
+	"context"
+	"time"
+
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/db"
+)
+
+const sessionTouchInterval = time.Minute
+
+var _ auth.SessionScheme = NativeScheme{}
+
+func (s NativeScheme) Sessions(ctx context.Context, user *auth.User, current auth.Token) ([]auth.Session, error) {
+	conn, err := db.Conn()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	var tokens []Token
+	err = conn.Tokens().Find(bson.M{"useremail": user.Email}).Sort("creation").All(&tokens)
+	if err != nil {
+		return nil, err
+	}
+	sessions := []auth.Session{}
+	for _, t := range tokens {
+		session := auth.Session{
+			ID:          t.ID.Hex(),
+			Creation:    t.Creation,
+			LastUse:     t.LastUse,
+			LastAddress: t.LastAddress,
+			Current:     current != nil && current.GetValue() == t.Token,
+		}
+		if t.Expires > 0 {
+			session.ExpiresAt = t.Creation.Add(t.Expires)
+			if time.Now().After(session.ExpiresAt) {
+				continue
+			}
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, nil
+}
+
+func (s NativeScheme) RevokeSession(ctx context.Context, user *auth.User, id string) error {
+	if !bson.IsObjectIdHex(id) {
+		return auth.ErrSessionNotFound
+	}
+	conn, err := db.Conn()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	err = conn.Tokens().Remove(bson.M{"_id": bson.ObjectIdHex(id), "useremail": user.Email})
+	if err == mgo.ErrNotFound {
+		return auth.ErrSessionNotFound
+	}
+	return err
+}
+
+func (s NativeScheme) TouchSession(ctx context.Context, token auth.Token, address string) error {
+	t, ok := token.(*Token)
+	if !ok || t.ID == "" || t.IsAppToken() {
+		return nil
+	}
+	now := time.Now()
+	if now.Sub(t.LastUse) < sessionTouchInterval && t.LastAddress == address {
+		return nil
+	}
+	conn, err := db.Conn()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	err = conn.Tokens().UpdateId(t.ID, bson.M{"$set": bson.M{"lastuse": now, "lastaddress": address}})
+	if err == mgo.ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	t.LastUse = now
+	t.LastAddress = address
+	return nil
+}
@@ -0,0 +1,103 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package native
+
+import (
+	"context"
+	"time"
+
+	"github.com/globalsign/mgo/bson"
+	"github.com/tsuru/tsuru/auth"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestSessions(c *check.C) {
+	other, err := nativeScheme.Login(context.TODO(), map[string]string{"email": s.user.Email, "password": "123456"})
+	c.Assert(err, check.IsNil)
+	expired := Token{
+		ID:        bson.NewObjectId(),
+		Token:     "expired-token",
+		Creation:  time.Now().Add(-time.Hour),
+		Expires:   time.Minute,
+		UserEmail: s.user.Email,
+	}
+	err = s.conn.Tokens().Insert(expired)
+	c.Assert(err, check.IsNil)
+	sessions, err := nativeScheme.Sessions(context.TODO(), s.user, s.token)
+	c.Assert(err, check.IsNil)
+	c.Assert(sessions, check.HasLen, 2)
+	c.Assert(sessions[0].ID, check.Equals, s.token.(*Token).ID.Hex())
+	c.Assert(sessions[0].Current, check.Equals, true)
+	c.Assert(sessions[0].ExpiresAt.Sub(sessions[0].Creation), check.Equals, tokenExpire)
+	c.Assert(sessions[1].ID, check.Equals, other.(*Token).ID.Hex())
+	c.Assert(sessions[1].Current, check.Equals, false)
+}
+
+func (s *S) TestSessionsIgnoresOtherUsers(c *check.C) {
+	u := &auth.User{Email: "wolverine@xmen.com", Password: "123456"}
+	_, err := nativeScheme.Create(context.TODO(), u)
+	c.Assert(err, check.IsNil)
+	sessions, err := nativeScheme.Sessions(context.TODO(), u, nil)
+	c.Assert(err, check.IsNil)
+	c.Assert(sessions, check.HasLen, 0)
+}
+
+func (s *S) TestRevokeSession(c *check.C) {
+	other, err := nativeScheme.Login(context.TODO(), map[string]string{"email": s.user.Email, "password": "123456"})
+	c.Assert(err, check.IsNil)
+	err = nativeScheme.RevokeSession(context.TODO(), s.user, other.(*Token).ID.Hex())
+	c.Assert(err, check.IsNil)
+	_, err = nativeScheme.Auth(context.TODO(), "bearer "+other.GetValue())
+	c.Assert(err, check.Equals, auth.ErrInvalidToken)
+	_, err = nativeScheme.Auth(context.TODO(), "bearer "+s.token.GetValue())
+	c.Assert(err, check.IsNil)
+}
+
+func (s *S) TestRevokeSessionNotFound(c *check.C) {
+	err := nativeScheme.RevokeSession(context.TODO(), s.user, bson.NewObjectId().Hex())
+	c.Assert(err, check.Equals, auth.ErrSessionNotFound)
+	err = nativeScheme.RevokeSession(context.TODO(), s.user, "invalid")
+	c.Assert(err, check.Equals, auth.ErrSessionNotFound)
+}
+
+func (s *S) TestRevokeSessionOfOtherUser(c *check.C) {
+	u := &auth.User{Email: "wolverine@xmen.com", Password: "123456"}
+	_, err := nativeScheme.Create(context.TODO(), u)
+	c.Assert(err, check.IsNil)
+	err = nativeScheme.RevokeSession(context.TODO(), u, s.token.(*Token).ID.Hex())
+	c.Assert(err, check.Equals, auth.ErrSessionNotFound)
+	_, err = nativeScheme.Auth(context.TODO(), "bearer "+s.token.GetValue())
+	c.Assert(err, check.IsNil)
+}
+
+func (s *S) TestTouchSession(c *check.C) {
+	t := s.token.(*Token)
+	err := nativeScheme.TouchSession(context.TODO(), t, "10.0.0.1")
+	c.Assert(err, check.IsNil)
+	var stored Token
+	err = s.conn.Tokens().FindId(t.ID).One(&stored)
+	c.Assert(err, check.IsNil)
+	c.Assert(stored.LastAddress, check.Equals, "10.0.0.1")
+	c.Assert(stored.LastUse.IsZero(), check.Equals, false)
+	lastUse := stored.LastUse
+	err = nativeScheme.TouchSession(context.TODO(), t, "10.0.0.1")
+	c.Assert(err, check.IsNil)
+	err = s.conn.Tokens().FindId(t.ID).One(&stored)
+	c.Assert(err, check.IsNil)
+	c.Assert(stored.LastUse.Equal(lastUse), check.Equals, true)
+	err = nativeScheme.TouchSession(context.TODO(), t, "10.0.0.2")
+	c.Assert(err, check.IsNil)
+	err = s.conn.Tokens().FindId(t.ID).One(&stored)
+	c.Assert(err, check.IsNil)
+	c.Assert(stored.LastAddress, check.Equals, "10.0.0.2")
+}
+
+func (s *S) TestTouchSessionAppToken(c *check.C) {
+	t, err := nativeScheme.AppLogin(context.TODO(), "myapp")
+	c.Assert(err, check.IsNil)
+	err = nativeScheme.TouchSession(context.TODO(), t, "10.0.0.1")
+	c.Assert(err, check.IsNil)
+	c.Assert(t.(*Token).LastAddress, check.Equals, "")
+}
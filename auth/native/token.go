@@ -37,11 +37,17 @@ var (
 )
 
 type Token struct {
+	ID        bson.ObjectId `json:"-" bson:"_id,omitempty"`
 	Token     string        `json:"token"`
 	Creation  time.Time     `json:"creation"`
 	Expires   time.Duration `json:"expires"`
 	UserEmail string        `json:"email"`
 	AppName   string        `json:"app"`
+	// LastUse and LastAddress are updated when the token is used, at most
+	// once every sessionTouchInterval from the same address.
+	LastUse     time.Time `json:"lastUse,omitempty"`
+	LastAddress string    `json:"lastAddress,omitempty"`
+	teams       auth.TeamHierarchy
 }
 
 func (t *Token) GetValue() string {
@@ -65,7 +71,7 @@ func (t *Token) GetAppName() string {
 }
 
 func (t *Token) Permissions() ([]permission.Permission, error) {
-	return auth.BaseTokenPermission(t)
+	return auth.BaseTokenPermission(t, &t.teams)
 }
 
 func loadConfig() error {
@@ -120,7 +126,7 @@ func newUserToken(u *auth.User) (*Token, error) {
 	if err := loadConfig(); err != nil {
 		return nil, err
 	}
-	t := Token{}
+	t := Token{ID: bson.NewObjectId()}
 	t.Creation = time.Now()
 	t.Expires = tokenExpire
 	t.Token = token(u.Email, crypto.SHA1)
@@ -161,7 +167,7 @@ func removeOldTokens(userEmail string) error {
 }
 
 func checkPassword(passwordHash string, password string) error {
-	if !validation.ValidateLength(password, passwordMinLen, passwordMaxLen) {
+	if !validation.ValidateLength(password, passwordMinLen, passwordMaxLen) && !getPasswordPolicy().validLength(password) {
 		return &tsuruErrors.ValidationError{Message: passwordError}
 	}
 	if bcrypt.CompareHashAndPassword([]byte(passwordHash), []byte(password)) == nil {
@@ -170,13 +176,19 @@ func checkPassword(passwordHash string, password string) error {
 	return auth.AuthenticationFailure{Message: "Authentication failed, wrong password."}
 }
 
-func createToken(u *auth.User, password string) (*Token, error) {
+func createToken(u *auth.User, password, otp string) (*Token, error) {
 	if u.Email == "" {
 		return nil, errors.New("User does not have an email")
 	}
 	if err := checkPassword(u.Password, password); err != nil {
 		return nil, err
 	}
+	if err := checkTwoFactor(u.Email, otp); err != nil {
+		return nil, err
+	}
+	if err := getPasswordPolicy().checkExpiration(u.Email); err != nil {
+		return nil, err
+	}
 	conn, err := db.Conn()
 	if err != nil {
 		return nil, err
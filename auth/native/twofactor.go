@@ -0,0 +1,330 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package native
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/db"
+	"github.com/tsuru/tsuru/errors"
+)
+
+const (
+	totpPeriod         = 30
+	totpDigits         = 6
+	totpSkew           = 1
+	totpSecretSize     = 20
+	recoveryCodesCount = 10
+	recoveryCodeSize   = 10
+)
+
+var (
+	ErrTwoFactorEnabled     = &errors.ConflictError{Message: "two-factor authentication is already enabled"}
+	ErrTwoFactorNotEnabled  = &errors.ValidationError{Message: "two-factor authentication is not enabled"}
+	ErrTwoFactorNotEnrolled = &errors.ValidationError{Message: "two-factor authentication enrollment wasn't started"}
+	ErrInvalidTwoFactorCode = auth.AuthenticationFailure{Message: "Authentication failed, invalid two-factor authentication code."}
+)
+
+var (
+	_ auth.TwoFactorScheme = &NativeScheme{}
+
+	now = time.Now
+
+	base32Encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+)
+
+// twoFactor is the two-factor authentication secret of a user. Secrets are
+// only used once enabled, with a code proving the user added it to an
+// authenticator app. Recovery codes are stored hashed and removed when used.
+type twoFactor struct {
+	Email         string `bson:"_id"`
+	Secret        string
+	Enabled       bool
+	RecoveryCodes []string
+	LastCounter   int64
+}
+
+func (s NativeScheme) EnrollTwoFactor(ctx context.Context, token auth.Token) (*auth.TwoFactorEnrollment, error) {
+	email := token.GetUserName()
+	tf, err := getTwoFactor(email)
+	if err != nil && err != mgo.ErrNotFound {
+		return nil, err
+	}
+	if tf != nil && tf.Enabled {
+		return nil, ErrTwoFactorEnabled
+	}
+	secret := make([]byte, totpSecretSize)
+	if _, err = rand.Read(secret); err != nil {
+		return nil, err
+	}
+	tf = &twoFactor{Email: email, Secret: base32Encoding.EncodeToString(secret)}
+	conn, err := db.Conn()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	_, err = conn.TwoFactorSecrets().Upsert(bson.M{"_id": email, "enabled": false}, tf)
+	if err != nil {
+		if mgo.IsDup(err) {
+			return nil, ErrTwoFactorEnabled
+		}
+		return nil, err
+	}
+	return &auth.TwoFactorEnrollment{Secret: tf.Secret, URL: tf.url()}, nil
+}
+
+func (s NativeScheme) EnableTwoFactor(ctx context.Context, token auth.Token, code string) ([]string, error) {
+	tf, err := getTwoFactor(token.GetUserName())
+	if err != nil {
+		if err == mgo.ErrNotFound {
+			return nil, ErrTwoFactorNotEnrolled
+		}
+		return nil, err
+	}
+	if tf.Enabled {
+		return nil, ErrTwoFactorEnabled
+	}
+	counter, ok := tf.validateTOTP(code)
+	if !ok {
+		return nil, ErrInvalidTwoFactorCode
+	}
+	codes, hashes := newRecoveryCodes()
+	conn, err := db.Conn()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	err = conn.TwoFactorSecrets().Update(bson.M{"_id": tf.Email, "secret": tf.Secret, "enabled": false}, bson.M{
+		"$set": bson.M{"enabled": true, "lastcounter": counter, "recoverycodes": hashes},
+	})
+	if err != nil {
+		if err == mgo.ErrNotFound {
+			return nil, ErrTwoFactorNotEnrolled
+		}
+		return nil, err
+	}
+	return codes, nil
+}
+
+func (s NativeScheme) DisableTwoFactor(ctx context.Context, token auth.Token, code string) error {
+	tf, err := getEnabledTwoFactor(token.GetUserName())
+	if err != nil {
+		return err
+	}
+	if err = tf.verify(code); err != nil {
+		return err
+	}
+	return removeTwoFactor(tf.Email)
+}
+
+func (s NativeScheme) RegenerateRecoveryCodes(ctx context.Context, token auth.Token, code string) ([]string, error) {
+	tf, err := getEnabledTwoFactor(token.GetUserName())
+	if err != nil {
+		return nil, err
+	}
+	if err = tf.verify(code); err != nil {
+		return nil, err
+	}
+	codes, hashes := newRecoveryCodes()
+	conn, err := db.Conn()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	err = conn.TwoFactorSecrets().Update(bson.M{"_id": tf.Email, "enabled": true}, bson.M{
+		"$set": bson.M{"recoverycodes": hashes},
+	})
+	if err != nil {
+		if err == mgo.ErrNotFound {
+			return nil, ErrTwoFactorNotEnabled
+		}
+		return nil, err
+	}
+	return codes, nil
+}
+
+// ResetTwoFactor removes the two-factor authentication of the user, either
+// enabled or with a pending enrollment. It's meant for users that lost both
+// their authenticator app and recovery codes.
+func (s NativeScheme) ResetTwoFactor(ctx context.Context, user *auth.User) error {
+	err := removeTwoFactor(user.Email)
+	if err == mgo.ErrNotFound {
+		return ErrTwoFactorNotEnabled
+	}
+	return err
+}
+
+func (s NativeScheme) VerifyTwoFactor(ctx context.Context, token auth.Token, code string) error {
+	if token.IsAppToken() {
+		return nil
+	}
+	return checkTwoFactor(token.GetUserName(), code)
+}
+
+// checkTwoFactor verifies the code when the user has two-factor
+// authentication enabled.
+func checkTwoFactor(email, code string) error {
+	tf, err := getEnabledTwoFactor(email)
+	if err != nil {
+		if err == ErrTwoFactorNotEnabled {
+			return nil
+		}
+		return err
+	}
+	return tf.verify(code)
+}
+
+func getTwoFactor(email string) (*twoFactor, error) {
+	conn, err := db.Conn()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	var tf twoFactor
+	err = conn.TwoFactorSecrets().FindId(email).One(&tf)
+	if err != nil {
+		return nil, err
+	}
+	return &tf, nil
+}
+
+func getEnabledTwoFactor(email string) (*twoFactor, error) {
+	tf, err := getTwoFactor(email)
+	if err != nil {
+		if err == mgo.ErrNotFound {
+			return nil, ErrTwoFactorNotEnabled
+		}
+		return nil, err
+	}
+	if !tf.Enabled {
+		return nil, ErrTwoFactorNotEnabled
+	}
+	return tf, nil
+}
+
+func removeTwoFactor(email string) error {
+	conn, err := db.Conn()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	return conn.TwoFactorSecrets().RemoveId(email)
+}
+
+// verify checks either a TOTP or a recovery code. Codes can only be used
+// once: TOTP codes older than the last one used and used recovery codes are
+// rejected.
+func (tf *twoFactor) verify(code string) error {
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return auth.ErrTwoFactorRequired
+	}
+	conn, err := db.Conn()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if counter, ok := tf.validateTOTP(code); ok {
+		err = conn.TwoFactorSecrets().Update(
+			bson.M{"_id": tf.Email, "enabled": true, "lastcounter": bson.M{"$lt": counter}},
+			bson.M{"$set": bson.M{"lastcounter": counter}},
+		)
+	} else {
+		hash := hashRecoveryCode(code)
+		err = conn.TwoFactorSecrets().Update(
+			bson.M{"_id": tf.Email, "enabled": true, "recoverycodes": hash},
+			bson.M{"$pull": bson.M{"recoverycodes": hash}},
+		)
+	}
+	if err == mgo.ErrNotFound {
+		return ErrInvalidTwoFactorCode
+	}
+	return err
+}
+
+// validateTOTP returns the time step of the code, codes of the previous and
+// next steps are accepted to allow for clock drift.
+func (tf *twoFactor) validateTOTP(code string) (int64, bool) {
+	secret, err := base32Encoding.DecodeString(tf.Secret)
+	if err != nil || len(code) != totpDigits {
+		return 0, false
+	}
+	current := now().Unix() / totpPeriod
+	for counter := current - totpSkew; counter <= current+totpSkew; counter++ {
+		if subtle.ConstantTimeCompare([]byte(totpCode(secret, counter)), []byte(code)) == 1 {
+			return counter, true
+		}
+	}
+	return 0, false
+}
+
+func (tf *twoFactor) url() string {
+	issuer, _ := config.GetString("auth:two-factor:issuer")
+	if issuer == "" {
+		issuer = "tsuru"
+	}
+	params := url.Values{}
+	params.Set("secret", tf.Secret)
+	params.Set("issuer", issuer)
+	params.Set("algorithm", "SHA1")
+	params.Set("digits", fmt.Sprint(totpDigits))
+	params.Set("period", fmt.Sprint(totpPeriod))
+	u := url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + issuer + ":" + tf.Email,
+		RawQuery: params.Encode(),
+	}
+	return u.String()
+}
+
+// totpCode generates the code for a time step as described in RFC 6238.
+func totpCode(secret []byte, counter int64) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(counter))
+	mac := hmac.New(sha1.New, secret)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0xf
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", totpDigits, value%1000000)
+}
+
+func newRecoveryCodes() (codes []string, hashes []string) {
+	for i := 0; i < recoveryCodesCount; i++ {
+		var key [recoveryCodeSize]byte
+		n, err := rand.Read(key[:])
+		for n < len(key) || err != nil {
+			n, err = rand.Read(key[:])
+		}
+		code := strings.ToLower(base32Encoding.EncodeToString(key[:]))[:recoveryCodeSize]
+		code = code[:recoveryCodeSize/2] + "-" + code[recoveryCodeSize/2:]
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+	return codes, hashes
+}
+
+func hashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.Replace(code, "-", "", -1))
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,223 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package native
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/auth"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) enableTwoFactor(c *check.C) (string, []string) {
+	enrollment, err := nativeScheme.EnrollTwoFactor(context.TODO(), s.token)
+	c.Assert(err, check.IsNil)
+	codes, err := nativeScheme.EnableTwoFactor(context.TODO(), s.token, currentCode(c, enrollment.Secret))
+	c.Assert(err, check.IsNil)
+	return enrollment.Secret, codes
+}
+
+func currentCode(c *check.C, secret string) string {
+	key, err := base32Encoding.DecodeString(secret)
+	c.Assert(err, check.IsNil)
+	return totpCode(key, now().Unix()/totpPeriod)
+}
+
+func freezeClock() {
+	current := now()
+	now = func() time.Time { return current }
+}
+
+// advanceClock moves the clock to the next time step, so that a new code is
+// accepted.
+func advanceClock(c *check.C) {
+	current := now()
+	now = func() time.Time { return current.Add(totpPeriod * time.Second) }
+}
+
+func (s *S) TestTOTPCode(c *check.C) {
+	secret := []byte("12345678901234567890")
+	tests := []struct {
+		time int64
+		code string
+	}{
+		{time: 59, code: "287082"},
+		{time: 1111111109, code: "081804"},
+		{time: 1234567890, code: "005924"},
+		{time: 2000000000, code: "279037"},
+	}
+	for _, tt := range tests {
+		c.Check(totpCode(secret, tt.time/totpPeriod), check.Equals, tt.code)
+	}
+}
+
+func (s *S) TestRecoveryCodes(c *check.C) {
+	codes, hashes := newRecoveryCodes()
+	c.Assert(codes, check.HasLen, recoveryCodesCount)
+	c.Assert(hashes, check.HasLen, recoveryCodesCount)
+	c.Assert(codes[0], check.Matches, `[a-z2-7]{5}-[a-z2-7]{5}`)
+	c.Assert(codes[0], check.Not(check.Equals), codes[1])
+	c.Assert(hashRecoveryCode(codes[0]), check.Equals, hashes[0])
+	c.Assert(hashRecoveryCode(codes[0][:5]+codes[0][6:]), check.Equals, hashes[0])
+}
+
+func (s *S) TestEnrollTwoFactor(c *check.C) {
+	config.Set("auth:two-factor:issuer", "my tsuru")
+	defer config.Unset("auth:two-factor:issuer")
+	enrollment, err := nativeScheme.EnrollTwoFactor(context.TODO(), s.token)
+	c.Assert(err, check.IsNil)
+	c.Assert(enrollment.Secret, check.HasLen, 32)
+	u, err := url.Parse(enrollment.URL)
+	c.Assert(err, check.IsNil)
+	c.Assert(u.Scheme, check.Equals, "otpauth")
+	c.Assert(u.Host, check.Equals, "totp")
+	c.Assert(u.Path, check.Equals, "/my tsuru:timeredbull@globo.com")
+	c.Assert(u.Query().Get("secret"), check.Equals, enrollment.Secret)
+	c.Assert(u.Query().Get("issuer"), check.Equals, "my tsuru")
+	tf, err := getTwoFactor(s.user.Email)
+	c.Assert(err, check.IsNil)
+	c.Assert(tf.Enabled, check.Equals, false)
+	c.Assert(tf.Secret, check.Equals, enrollment.Secret)
+	other, err := nativeScheme.EnrollTwoFactor(context.TODO(), s.token)
+	c.Assert(err, check.IsNil)
+	c.Assert(other.Secret, check.Not(check.Equals), enrollment.Secret)
+}
+
+func (s *S) TestEnrollTwoFactorAlreadyEnabled(c *check.C) {
+	s.enableTwoFactor(c)
+	_, err := nativeScheme.EnrollTwoFactor(context.TODO(), s.token)
+	c.Assert(err, check.Equals, ErrTwoFactorEnabled)
+}
+
+func (s *S) TestEnableTwoFactor(c *check.C) {
+	enrollment, err := nativeScheme.EnrollTwoFactor(context.TODO(), s.token)
+	c.Assert(err, check.IsNil)
+	_, err = nativeScheme.EnableTwoFactor(context.TODO(), s.token, "000000")
+	c.Assert(err, check.Equals, ErrInvalidTwoFactorCode)
+	codes, err := nativeScheme.EnableTwoFactor(context.TODO(), s.token, currentCode(c, enrollment.Secret))
+	c.Assert(err, check.IsNil)
+	c.Assert(codes, check.HasLen, recoveryCodesCount)
+	tf, err := getTwoFactor(s.user.Email)
+	c.Assert(err, check.IsNil)
+	c.Assert(tf.Enabled, check.Equals, true)
+	c.Assert(tf.RecoveryCodes, check.HasLen, recoveryCodesCount)
+	c.Assert(tf.RecoveryCodes[0], check.Equals, hashRecoveryCode(codes[0]))
+	_, err = nativeScheme.EnableTwoFactor(context.TODO(), s.token, currentCode(c, enrollment.Secret))
+	c.Assert(err, check.Equals, ErrTwoFactorEnabled)
+}
+
+func (s *S) TestEnableTwoFactorNotEnrolled(c *check.C) {
+	_, err := nativeScheme.EnableTwoFactor(context.TODO(), s.token, "123456")
+	c.Assert(err, check.Equals, ErrTwoFactorNotEnrolled)
+}
+
+func (s *S) TestNativeLoginWithTwoFactor(c *check.C) {
+	freezeClock()
+	defer func() { now = time.Now }()
+	secret, _ := s.enableTwoFactor(c)
+	params := map[string]string{"email": s.user.Email, "password": "123456"}
+	_, err := nativeScheme.Login(context.TODO(), params)
+	c.Assert(err, check.Equals, auth.ErrTwoFactorRequired)
+	params["otp"] = "000000"
+	_, err = nativeScheme.Login(context.TODO(), params)
+	c.Assert(err, check.Equals, ErrInvalidTwoFactorCode)
+	params["otp"] = currentCode(c, secret)
+	_, err = nativeScheme.Login(context.TODO(), params)
+	c.Assert(err, check.Equals, ErrInvalidTwoFactorCode)
+	advanceClock(c)
+	params["otp"] = currentCode(c, secret)
+	token, err := nativeScheme.Login(context.TODO(), params)
+	c.Assert(err, check.IsNil)
+	c.Assert(token.GetUserName(), check.Equals, s.user.Email)
+	_, err = nativeScheme.Login(context.TODO(), params)
+	c.Assert(err, check.Equals, ErrInvalidTwoFactorCode)
+	params["password"] = "xxxxxx"
+	_, err = nativeScheme.Login(context.TODO(), params)
+	_, isAuthFail := err.(auth.AuthenticationFailure)
+	c.Assert(isAuthFail, check.Equals, true)
+	c.Assert(err, check.Not(check.Equals), ErrInvalidTwoFactorCode)
+}
+
+func (s *S) TestNativeLoginWithRecoveryCode(c *check.C) {
+	_, codes := s.enableTwoFactor(c)
+	params := map[string]string{"email": s.user.Email, "password": "123456", "otp": codes[3]}
+	_, err := nativeScheme.Login(context.TODO(), params)
+	c.Assert(err, check.IsNil)
+	_, err = nativeScheme.Login(context.TODO(), params)
+	c.Assert(err, check.Equals, ErrInvalidTwoFactorCode)
+	tf, err := getTwoFactor(s.user.Email)
+	c.Assert(err, check.IsNil)
+	c.Assert(tf.RecoveryCodes, check.HasLen, recoveryCodesCount-1)
+}
+
+func (s *S) TestNativeLoginWithPendingTwoFactor(c *check.C) {
+	_, err := nativeScheme.EnrollTwoFactor(context.TODO(), s.token)
+	c.Assert(err, check.IsNil)
+	_, err = nativeScheme.Login(context.TODO(), map[string]string{"email": s.user.Email, "password": "123456"})
+	c.Assert(err, check.IsNil)
+}
+
+func (s *S) TestVerifyTwoFactor(c *check.C) {
+	freezeClock()
+	defer func() { now = time.Now }()
+	err := nativeScheme.VerifyTwoFactor(context.TODO(), s.token, "")
+	c.Assert(err, check.IsNil)
+	secret, _ := s.enableTwoFactor(c)
+	err = nativeScheme.VerifyTwoFactor(context.TODO(), s.token, "")
+	c.Assert(err, check.Equals, auth.ErrTwoFactorRequired)
+	advanceClock(c)
+	err = nativeScheme.VerifyTwoFactor(context.TODO(), s.token, currentCode(c, secret))
+	c.Assert(err, check.IsNil)
+	appToken, err := nativeScheme.AppLogin(context.TODO(), "myapp")
+	c.Assert(err, check.IsNil)
+	err = nativeScheme.VerifyTwoFactor(context.TODO(), appToken, "")
+	c.Assert(err, check.IsNil)
+}
+
+func (s *S) TestDisableTwoFactor(c *check.C) {
+	err := nativeScheme.DisableTwoFactor(context.TODO(), s.token, "123456")
+	c.Assert(err, check.Equals, ErrTwoFactorNotEnabled)
+	_, codes := s.enableTwoFactor(c)
+	err = nativeScheme.DisableTwoFactor(context.TODO(), s.token, "")
+	c.Assert(err, check.Equals, auth.ErrTwoFactorRequired)
+	err = nativeScheme.DisableTwoFactor(context.TODO(), s.token, codes[0])
+	c.Assert(err, check.IsNil)
+	_, err = getTwoFactor(s.user.Email)
+	c.Assert(err, check.NotNil)
+	_, err = nativeScheme.Login(context.TODO(), map[string]string{"email": s.user.Email, "password": "123456"})
+	c.Assert(err, check.IsNil)
+}
+
+func (s *S) TestRegenerateRecoveryCodes(c *check.C) {
+	_, codes := s.enableTwoFactor(c)
+	newCodes, err := nativeScheme.RegenerateRecoveryCodes(context.TODO(), s.token, codes[0])
+	c.Assert(err, check.IsNil)
+	c.Assert(newCodes, check.HasLen, recoveryCodesCount)
+	err = nativeScheme.VerifyTwoFactor(context.TODO(), s.token, codes[1])
+	c.Assert(err, check.Equals, ErrInvalidTwoFactorCode)
+	err = nativeScheme.VerifyTwoFactor(context.TODO(), s.token, newCodes[1])
+	c.Assert(err, check.IsNil)
+}
+
+func (s *S) TestResetTwoFactor(c *check.C) {
+	err := nativeScheme.ResetTwoFactor(context.TODO(), s.user)
+	c.Assert(err, check.Equals, ErrTwoFactorNotEnabled)
+	s.enableTwoFactor(c)
+	err = nativeScheme.ResetTwoFactor(context.TODO(), s.user)
+	c.Assert(err, check.IsNil)
+	_, err = nativeScheme.Login(context.TODO(), map[string]string{"email": s.user.Email, "password": "123456"})
+	c.Assert(err, check.IsNil)
+}
+
+func (s *S) TestRemoveUserWithTwoFactor(c *check.C) {
+	s.enableTwoFactor(c)
+	err := nativeScheme.Remove(context.TODO(), s.user)
+	c.Assert(err, check.IsNil)
+	_, err = getTwoFactor(s.user.Email)
+	c.Assert(err, check.NotNil)
+}
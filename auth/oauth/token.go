@@ -22,6 +22,7 @@ var _ authTypes.Token = &tokenWrapper{}
 type tokenWrapper struct {
 	oauth2.Token
 	UserEmail string `json:"email"`
+	teams     auth.TeamHierarchy
 }
 
 func (t *tokenWrapper) GetValue() string {
@@ -45,7 +46,7 @@ func (t *tokenWrapper) GetAppName() string {
 }
 
 func (t *tokenWrapper) Permissions() ([]permission.Permission, error) {
-	return auth.BaseTokenPermission(t)
+	return auth.BaseTokenPermission(t, &t.teams)
 }
 
 func getToken(header string) (*tokenWrapper, error) {
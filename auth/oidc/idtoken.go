@@ -0,0 +1,341 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// clockSkew is the tolerance when checking the expiration of ID tokens.
+const clockSkew = time.Minute
+
+var ErrInvalidIDToken = errors.New("invalid ID token")
+
+// providerMetadata is the subset of the OpenID provider metadata, served at
+// the discovery endpoint, used by the scheme.
+type providerMetadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// provider is an OpenID provider found through discovery, along with the
+// keys used to sign its ID tokens.
+type provider struct {
+	providerMetadata
+	client *http.Client
+
+	mu   sync.Mutex
+	keys map[string]crypto.PublicKey
+}
+
+func discover(ctx context.Context, client *http.Client, issuer string) (*provider, error) {
+	var metadata providerMetadata
+	wellKnown := strings.TrimSuffix(issuer, "/") + "/.well-known/openid-configuration"
+	err := getJSON(ctx, client, wellKnown, &metadata)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to discover openid provider")
+	}
+	if strings.TrimSuffix(metadata.Issuer, "/") != strings.TrimSuffix(issuer, "/") {
+		return nil, errors.Errorf("openid provider issuer %q doesn't match the configured issuer %q", metadata.Issuer, issuer)
+	}
+	if metadata.AuthorizationEndpoint == "" || metadata.TokenEndpoint == "" || metadata.JWKSURI == "" {
+		return nil, errors.Errorf("openid provider %q metadata is missing endpoints", issuer)
+	}
+	return &provider{providerMetadata: metadata, client: client}, nil
+}
+
+func getJSON(ctx context.Context, client *http.Client, url string, result interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	rsp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+	data, err := ioutil.ReadAll(rsp.Body)
+	if err != nil {
+		return err
+	}
+	if rsp.StatusCode != http.StatusOK {
+		return errors.Errorf("unexpected response from %s %d: %s", url, rsp.StatusCode, data)
+	}
+	err = json.Unmarshal(data, result)
+	if err != nil {
+		return errors.Wrapf(err, "unable to parse response from %s", url)
+	}
+	return nil
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (k *jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, errors.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{
+			Curve: curve,
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}, nil
+	}
+	return nil, errors.Errorf("unsupported key type %q", k.Kty)
+}
+
+// loadKeys fetches the signing keys of the provider, keys that can't be
+// parsed, or aren't used for signatures, are ignored.
+func (p *provider) loadKeys(ctx context.Context) error {
+	var keySet struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	err := getJSON(ctx, p.client, p.JWKSURI, &keySet)
+	if err != nil {
+		return errors.Wrap(err, "unable to load openid provider keys")
+	}
+	keys := map[string]crypto.PublicKey{}
+	for _, k := range keySet.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			continue
+		}
+		keys[k.Kid] = key
+	}
+	p.keys = keys
+	return nil
+}
+
+// key returns the key identified by kid, keys are reloaded when kid isn't
+// known, as providers rotate them. An empty kid is only accepted when the
+// provider has a single key.
+func (p *provider) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	find := func() crypto.PublicKey {
+		if kid == "" && len(p.keys) == 1 {
+			for _, key := range p.keys {
+				return key
+			}
+		}
+		return p.keys[kid]
+	}
+	if key := find(); key != nil {
+		return key, nil
+	}
+	err := p.loadKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if key := find(); key != nil {
+		return key, nil
+	}
+	return nil, errors.Wrapf(ErrInvalidIDToken, "unknown signing key %q", kid)
+}
+
+// idTokenClaims are the claims of an ID token.
+type idTokenClaims map[string]interface{}
+
+func (c idTokenClaims) String(name string) string {
+	value, _ := c[name].(string)
+	return value
+}
+
+// Strings returns the values of a claim that may be either a string or a
+// list of strings.
+func (c idTokenClaims) Strings(name string) []string {
+	switch value := c[name].(type) {
+	case string:
+		return []string{value}
+	case []interface{}:
+		var result []string
+		for _, v := range value {
+			if s, ok := v.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	}
+	return nil
+}
+
+func (c idTokenClaims) Time(name string) (time.Time, bool) {
+	value, ok := c[name].(float64)
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(value), 0), true
+}
+
+// verifyIDToken checks the signature of an ID token, issued by the provider
+// to clientID, and returns its claims.
+func (p *provider) verifyIDToken(ctx context.Context, rawToken, clientID string, now time.Time) (idTokenClaims, error) {
+	parts := strings.Split(rawToken, ".")
+	if len(parts) != 3 {
+		return nil, errors.Wrap(ErrInvalidIDToken, "malformed token")
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	err := decodeSegment(parts[0], &header)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidIDToken, "malformed signature")
+	}
+	key, err := p.key(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	err = verifySignature(header.Alg, key, []byte(parts[0]+"."+parts[1]), signature)
+	if err != nil {
+		return nil, err
+	}
+	var claims idTokenClaims
+	err = decodeSegment(parts[1], &claims)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSuffix(claims.String("iss"), "/") != strings.TrimSuffix(p.Issuer, "/") {
+		return nil, errors.Wrapf(ErrInvalidIDToken, "unexpected issuer %q", claims.String("iss"))
+	}
+	audienceFound := false
+	for _, aud := range claims.Strings("aud") {
+		if aud == clientID {
+			audienceFound = true
+			break
+		}
+	}
+	if !audienceFound {
+		return nil, errors.Wrap(ErrInvalidIDToken, "token wasn't issued to this client")
+	}
+	expiry, ok := claims.Time("exp")
+	if !ok || now.After(expiry.Add(clockSkew)) {
+		return nil, errors.Wrap(ErrInvalidIDToken, "token is expired")
+	}
+	return claims, nil
+}
+
+func decodeSegment(segment string, result interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return errors.Wrap(ErrInvalidIDToken, "malformed token")
+	}
+	err = json.Unmarshal(data, result)
+	if err != nil {
+		return errors.Wrap(ErrInvalidIDToken, "malformed token")
+	}
+	return nil
+}
+
+func verifySignature(alg string, key crypto.PublicKey, signed, signature []byte) error {
+	if len(alg) != 5 {
+		return errors.Wrapf(ErrInvalidIDToken, "unsupported algorithm %q", alg)
+	}
+	var hash crypto.Hash
+	switch alg[2:] {
+	case "256":
+		hash = crypto.SHA256
+	case "384":
+		hash = crypto.SHA384
+	case "512":
+		hash = crypto.SHA512
+	default:
+		return errors.Wrapf(ErrInvalidIDToken, "unsupported algorithm %q", alg)
+	}
+	h := hash.New()
+	h.Write(signed)
+	digest := h.Sum(nil)
+	switch {
+	case strings.HasPrefix(alg, "RS"):
+		rsaKey, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return errors.Wrapf(ErrInvalidIDToken, "key doesn't match algorithm %q", alg)
+		}
+		if rsa.VerifyPKCS1v15(rsaKey, hash, digest, signature) != nil {
+			return errors.Wrap(ErrInvalidIDToken, "invalid signature")
+		}
+		return nil
+	case strings.HasPrefix(alg, "ES"):
+		ecKey, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return errors.Wrapf(ErrInvalidIDToken, "key doesn't match algorithm %q", alg)
+		}
+		size := (ecKey.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return errors.Wrap(ErrInvalidIDToken, "invalid signature")
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(ecKey, digest, r, s) {
+			return errors.Wrap(ErrInvalidIDToken, "invalid signature")
+		}
+		return nil
+	}
+	return errors.Wrapf(ErrInvalidIDToken, "unsupported algorithm %q", alg)
+}
@@ -0,0 +1,131 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	check "gopkg.in/check.v1"
+)
+
+func signECDSA(c *check.C, key crypto.Signer, digest []byte) []byte {
+	ecKey := key.(*ecdsa.PrivateKey)
+	r, sig, err := ecdsa.Sign(rand.Reader, ecKey, digest)
+	c.Assert(err, check.IsNil)
+	size := (ecKey.Curve.Params().BitSize + 7) / 8
+	signature := make([]byte, 2*size)
+	r.FillBytes(signature[:size])
+	sig.FillBytes(signature[size:])
+	return signature
+}
+
+func (s *S) TestDiscover(c *check.C) {
+	p, err := discover(context.TODO(), http.DefaultClient, s.server.URL)
+	c.Assert(err, check.IsNil)
+	c.Assert(p.Issuer, check.Equals, s.server.URL)
+	c.Assert(p.AuthorizationEndpoint, check.Equals, s.server.URL+"/auth")
+	c.Assert(p.TokenEndpoint, check.Equals, s.server.URL+"/token")
+	c.Assert(p.JWKSURI, check.Equals, s.server.URL+"/keys")
+}
+
+func (s *S) TestDiscoverIssuerMismatch(c *check.C) {
+	_, err := discover(context.TODO(), http.DefaultClient, s.server.URL+"/")
+	c.Assert(err, check.IsNil)
+	_, err = discover(context.TODO(), http.DefaultClient, s.server.URL+"/other")
+	c.Assert(err, check.ErrorMatches, `openid provider issuer ".*" doesn't match the configured issuer ".*/other"`)
+	_, err = discover(context.TODO(), http.DefaultClient, s.server.URL+"/realms/tsuru")
+	c.Assert(err, check.ErrorMatches, `unable to discover openid provider: unexpected response from .* 404: `)
+}
+
+func (s *S) TestVerifyIDToken(c *check.C) {
+	p, err := discover(context.TODO(), http.DefaultClient, s.server.URL)
+	c.Assert(err, check.IsNil)
+	rawToken := s.idToken(c, map[string]interface{}{
+		"email":  "rand@althor.com",
+		"groups": []string{"g1", "g2"},
+	})
+	claims, err := p.verifyIDToken(context.TODO(), rawToken, "clientid", s.now)
+	c.Assert(err, check.IsNil)
+	c.Assert(claims.String("email"), check.Equals, "rand@althor.com")
+	c.Assert(claims.Strings("groups"), check.DeepEquals, []string{"g1", "g2"})
+	c.Assert(claims.Strings("aud"), check.DeepEquals, []string{"clientid"})
+	reqs := len(s.reqs)
+	_, err = p.verifyIDToken(context.TODO(), rawToken, "clientid", s.now)
+	c.Assert(err, check.IsNil)
+	c.Assert(s.reqs, check.HasLen, reqs)
+}
+
+func (s *S) TestVerifyIDTokenInvalid(c *check.C) {
+	p, err := discover(context.TODO(), http.DefaultClient, s.server.URL)
+	c.Assert(err, check.IsNil)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	c.Assert(err, check.IsNil)
+	validClaims := map[string]interface{}{
+		"iss": s.server.URL,
+		"aud": "clientid",
+		"exp": s.now.Add(time.Hour).Unix(),
+	}
+	tests := []struct {
+		token string
+		err   string
+	}{
+		{token: "abc", err: "malformed token: invalid ID token"},
+		{token: s.idToken(c, map[string]interface{}{"aud": "otherclient"}), err: "token wasn't issued to this client: invalid ID token"},
+		{token: s.idToken(c, map[string]interface{}{"aud": []string{"otherclient", "clientid"}}), err: ""},
+		{token: s.idToken(c, map[string]interface{}{"iss": "https://evil.example.com"}), err: `unexpected issuer "https://evil.example.com": invalid ID token`},
+		{token: s.idToken(c, map[string]interface{}{"exp": s.now.Add(-2 * time.Minute).Unix()}), err: "token is expired: invalid ID token"},
+		{token: s.idToken(c, map[string]interface{}{"exp": s.now.Add(-30 * time.Second).Unix()}), err: ""},
+		{token: signToken(c, otherKey, "RS256", "key1", validClaims), err: "invalid signature: invalid ID token"},
+		{token: signToken(c, s.key, "RS256", "key2", validClaims), err: `unknown signing key "key2": invalid ID token`},
+		{token: signToken(c, s.key, "none", "key1", validClaims), err: `unsupported algorithm "none": invalid ID token`},
+		{token: signToken(c, s.key, "HS256", "key1", validClaims), err: `unsupported algorithm "HS256": invalid ID token`},
+	}
+	for _, tt := range tests {
+		_, err = p.verifyIDToken(context.TODO(), tt.token, "clientid", s.now)
+		if tt.err == "" {
+			c.Check(err, check.IsNil)
+			continue
+		}
+		c.Check(errors.Cause(err), check.Equals, ErrInvalidIDToken)
+		c.Check(err, check.ErrorMatches, tt.err)
+	}
+}
+
+func (s *S) TestVerifyIDTokenECDSA(c *check.C) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	c.Assert(err, check.IsNil)
+	jwk := jsonWebKey{
+		Kty: "EC",
+		Crv: "P-256",
+		X:   base64.RawURLEncoding.EncodeToString(key.X.Bytes()),
+		Y:   base64.RawURLEncoding.EncodeToString(key.Y.Bytes()),
+	}
+	publicKey, err := jwk.publicKey()
+	c.Assert(err, check.IsNil)
+	p := &provider{
+		providerMetadata: providerMetadata{Issuer: "https://issuer.example.com"},
+		keys:             map[string]crypto.PublicKey{"": publicKey},
+	}
+	rawToken := signToken(c, key, "ES256", "", map[string]interface{}{
+		"iss":   "https://issuer.example.com",
+		"aud":   "clientid",
+		"exp":   s.now.Add(time.Hour).Unix(),
+		"email": "rand@althor.com",
+	})
+	claims, err := p.verifyIDToken(context.TODO(), rawToken, "clientid", s.now)
+	c.Assert(err, check.IsNil)
+	c.Assert(claims.String("email"), check.Equals, "rand@althor.com")
+	_, err = p.verifyIDToken(context.TODO(), signToken(c, s.key, "RS256", "", map[string]interface{}{}), "clientid", s.now)
+	c.Assert(err, check.ErrorMatches, `key doesn't match algorithm "RS256": invalid ID token`)
+}
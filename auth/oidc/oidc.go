@@ -0,0 +1,559 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package oidc implements an auth scheme for OpenID Connect providers, like
+// Keycloak, Okta and Azure AD, using the authorization code flow with PKCE.
+package oidc
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/auth/native"
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/log"
+	tsuruNet "github.com/tsuru/tsuru/net"
+	"github.com/tsuru/tsuru/set"
+	authTypes "github.com/tsuru/tsuru/types/auth"
+	"golang.org/x/oauth2"
+)
+
+// defaultProvider is the name of the provider configured directly in
+// auth:oidc, other providers are configured in auth:oidc:providers.
+const defaultProvider = "default"
+
+var (
+	ErrMissingCodeError        = &tsuruErrors.ValidationError{Message: "You must provide code to login"}
+	ErrMissingCodeRedirectURL  = &tsuruErrors.ValidationError{Message: "You must provide the used redirect url to login"}
+	ErrMissingProvider         = &tsuruErrors.ValidationError{Message: "You must provide the provider to login"}
+	ErrMissingIDToken          = &tsuruErrors.NotAuthorizedError{Message: "Couldn't get ID token from the provider."}
+	ErrEmptyUserEmail          = &tsuruErrors.NotAuthorizedError{Message: "Couldn't parse user email."}
+	ErrUnverifiedUserEmail     = &tsuruErrors.NotAuthorizedError{Message: "User email isn't verified by the provider."}
+	ErrInvalidNonce            = &tsuruErrors.NotAuthorizedError{Message: "ID token nonce doesn't match."}
+	ErrUserFromAnotherProvider = &tsuruErrors.NotAuthorizedError{Message: "User is registered through another provider."}
+
+	_ auth.Scheme = &oidcScheme{}
+)
+
+type oidcScheme struct {
+	now func() time.Time
+
+	mu sync.Mutex
+	// providers holds the discovered OpenID providers by issuer.
+	providers map[string]*provider
+	// refreshMu serializes token refreshes, so that concurrent requests
+	// with an expired token don't use its refresh token more than once.
+	refreshMu sync.Mutex
+}
+
+type oidcConfig struct {
+	name   string
+	oauth2 oauth2.Config
+	// provider is the discovered OpenID provider, it's nil for plain OAuth
+	// 2.0 providers, whose claims are fetched from userinfoURL.
+	provider     *provider
+	userinfoURL  string
+	callbackPort int
+	emailClaim   string
+	groupsClaim  string
+	teamRole     string
+	teamMapping  map[string]string
+}
+
+func init() {
+	auth.RegisterScheme("oidc", &oidcScheme{now: time.Now})
+}
+
+func configPrefix(name string) string {
+	if name == defaultProvider {
+		return "auth:oidc"
+	}
+	return "auth:oidc:providers:" + name
+}
+
+// providerNames returns the sorted names of the configured providers, the
+// provider configured directly in auth:oidc is named "default".
+func providerNames() ([]string, error) {
+	names := set.Set{}
+	for _, key := range []string{"auth:oidc:issuer", "auth:oidc:auth-url"} {
+		if _, err := config.Get(key); err == nil {
+			names.Add(defaultProvider)
+		}
+	}
+	if providers, err := config.Get("auth:oidc:providers"); err == nil {
+		entries, ok := providers.(map[interface{}]interface{})
+		if !ok {
+			return nil, errors.New("auth:oidc:providers must be a map of provider names to their settings")
+		}
+		for name := range entries {
+			if fmt.Sprint(name) == defaultProvider {
+				return nil, errors.Errorf("auth:oidc:providers can't have a provider named %q", defaultProvider)
+			}
+			names.Add(fmt.Sprint(name))
+		}
+	}
+	return names.Sorted(), nil
+}
+
+// resolveProvider returns the name of the provider used to log in. Without a
+// name, the default provider is used, or the only one when there's no
+// default.
+func resolveProvider(name string) (string, error) {
+	names, err := providerNames()
+	if err != nil {
+		return "", err
+	}
+	configured := set.FromSlice(names)
+	if name != "" {
+		if !configured.Includes(name) {
+			return "", &tsuruErrors.ValidationError{Message: fmt.Sprintf("Unknown provider %q", name)}
+		}
+		return name, nil
+	}
+	switch {
+	case len(names) == 0 || configured.Includes(defaultProvider):
+		return defaultProvider, nil
+	case len(names) == 1:
+		return names[0], nil
+	}
+	return "", ErrMissingProvider
+}
+
+// loadConfig loads the config of the provider, OpenID provider metadata is
+// discovered on the first call and cached while the issuer doesn't change.
+func (s *oidcScheme) loadConfig(ctx context.Context, name string) (*oidcConfig, error) {
+	prefix := configPrefix(name)
+	issuer, issuerErr := config.GetString(prefix + ":issuer")
+	var authURL, tokenURL, userinfoURL string
+	if issuerErr != nil {
+		var err error
+		authURL, err = config.GetString(prefix + ":auth-url")
+		if err != nil {
+			return nil, issuerErr
+		}
+		tokenURL, err = config.GetString(prefix + ":token-url")
+		if err != nil {
+			return nil, err
+		}
+		userinfoURL, err = config.GetString(prefix + ":userinfo-url")
+		if err != nil {
+			return nil, err
+		}
+	}
+	clientID, err := config.GetString(prefix + ":client-id")
+	if err != nil {
+		return nil, err
+	}
+	clientSecret, _ := config.GetString(prefix + ":client-secret")
+	scopes, err := config.GetList(prefix + ":scopes")
+	if issuerErr == nil {
+		if err != nil {
+			scopes = []string{"openid", "email", "profile"}
+		}
+		if !set.FromSlice(scopes).Includes("openid") {
+			scopes = append([]string{"openid"}, scopes...)
+		}
+	}
+	callbackPort, err := config.GetInt(prefix + ":callback-port")
+	if err != nil {
+		log.Debugf("%s:callback-port not found using random port: %s", prefix, err)
+	}
+	conf := oidcConfig{
+		name:         name,
+		userinfoURL:  userinfoURL,
+		callbackPort: callbackPort,
+		emailClaim:   "email",
+		groupsClaim:  "groups",
+		teamMapping:  map[string]string{},
+	}
+	if claim, err := config.GetString(prefix + ":email-claim"); err == nil {
+		conf.emailClaim = claim
+	}
+	if claim, err := config.GetString(prefix + ":groups-claim"); err == nil {
+		conf.groupsClaim = claim
+	}
+	if mapping, err := config.Get(prefix + ":team-mapping"); err == nil {
+		entries, ok := mapping.(map[interface{}]interface{})
+		if !ok {
+			return nil, errors.Errorf("%s:team-mapping must be a map of groups to teams", prefix)
+		}
+		for group, team := range entries {
+			conf.teamMapping[fmt.Sprint(group)] = fmt.Sprint(team)
+		}
+		conf.teamRole, err = config.GetString(prefix + ":team-role")
+		if err != nil {
+			return nil, errors.Errorf("%s:team-role is required with %s:team-mapping", prefix, prefix)
+		}
+	}
+	if issuerErr == nil {
+		conf.provider, err = s.getProvider(ctx, issuer)
+		if err != nil {
+			return nil, err
+		}
+		authURL = conf.provider.AuthorizationEndpoint
+		tokenURL = conf.provider.TokenEndpoint
+	}
+	conf.oauth2 = oauth2.Config{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		Scopes:       scopes,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  authURL,
+			TokenURL: tokenURL,
+		},
+	}
+	return &conf, nil
+}
+
+// origin identifies the provider in the users registered through it.
+func (c *oidcConfig) origin() string {
+	return "oidc:" + c.name
+}
+
+func (s *oidcScheme) getProvider(ctx context.Context, issuer string) (*provider, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if p, ok := s.providers[issuer]; ok {
+		return p, nil
+	}
+	p, err := discover(ctx, tsuruNet.Dial15Full60ClientWithPool, issuer)
+	if err != nil {
+		return nil, err
+	}
+	if s.providers == nil {
+		s.providers = map[string]*provider{}
+	}
+	s.providers[issuer] = p
+	return p, nil
+}
+
+func (s *oidcScheme) clientContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, oauth2.HTTPClient, tsuruNet.Dial15Full60ClientWithPool)
+}
+
+// Login exchanges the authorization code, obtained by the client from the
+// provider, for a tsuru token. Clients using PKCE must send the code
+// verifier in the codeVerifier param, and the nonce, when sent in the
+// authorization request, in the nonce param. With more than one provider,
+// the provider param selects the one the code was obtained from.
+func (s *oidcScheme) Login(ctx context.Context, params map[string]string) (auth.Token, error) {
+	code, ok := params["code"]
+	if !ok {
+		return nil, ErrMissingCodeError
+	}
+	redirectURL, ok := params["redirectUrl"]
+	if !ok {
+		return nil, ErrMissingCodeRedirectURL
+	}
+	providerName, err := resolveProvider(params["provider"])
+	if err != nil {
+		return nil, err
+	}
+	conf, err := s.loadConfig(ctx, providerName)
+	if err != nil {
+		return nil, err
+	}
+	conf.oauth2.RedirectURL = redirectURL
+	var opts []oauth2.AuthCodeOption
+	if verifier := params["codeVerifier"]; verifier != "" {
+		opts = append(opts, oauth2.SetAuthURLParam("code_verifier", verifier))
+	}
+	providerToken, err := conf.oauth2.Exchange(s.clientContext(ctx), code, opts...)
+	if err != nil {
+		return nil, err
+	}
+	var claims idTokenClaims
+	if conf.provider == nil {
+		claims, err = s.userinfo(ctx, conf, providerToken)
+	} else {
+		claims, err = s.verifyToken(ctx, conf, providerToken)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if nonce := params["nonce"]; nonce != "" && conf.provider != nil && claims.String("nonce") != nonce {
+		return nil, ErrInvalidNonce
+	}
+	user, err := s.syncUser(conf, claims)
+	if err != nil {
+		return nil, err
+	}
+	token := newToken(user.Email, conf.name, providerToken)
+	err = token.save()
+	if err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+func (s *oidcScheme) verifyToken(ctx context.Context, conf *oidcConfig, providerToken *oauth2.Token) (idTokenClaims, error) {
+	rawIDToken, _ := providerToken.Extra("id_token").(string)
+	if rawIDToken == "" {
+		return nil, ErrMissingIDToken
+	}
+	claims, err := conf.provider.verifyIDToken(ctx, rawIDToken, conf.oauth2.ClientID, s.now())
+	if err != nil {
+		return nil, &tsuruErrors.NotAuthorizedError{Message: err.Error()}
+	}
+	return claims, nil
+}
+
+// userinfo fetches the claims of the user from plain OAuth 2.0 providers,
+// which don't issue ID tokens.
+func (s *oidcScheme) userinfo(ctx context.Context, conf *oidcConfig, providerToken *oauth2.Token) (idTokenClaims, error) {
+	var claims idTokenClaims
+	client := conf.oauth2.Client(s.clientContext(ctx), providerToken)
+	err := getJSON(ctx, client, conf.userinfoURL, &claims)
+	if err != nil {
+		return nil, &tsuruErrors.NotAuthorizedError{Message: err.Error()}
+	}
+	return claims, nil
+}
+
+// syncUser returns the user identified by the ID token claims, creating it
+// when user registration is enabled, and updates its groups and the roles
+// of the teams mapped from its groups. Users may only log in through the
+// provider they were registered through, users registered before their
+// origin was tracked are bound to the default provider. Only users whose
+// email was verified by the provider are accepted.
+func (s *oidcScheme) syncUser(conf *oidcConfig, claims idTokenClaims) (*auth.User, error) {
+	email := claims.String(conf.emailClaim)
+	if email == "" {
+		return nil, ErrEmptyUserEmail
+	}
+	if verified, _ := claims["email_verified"].(bool); !verified {
+		return nil, ErrUnverifiedUserEmail
+	}
+	groups := claims.Strings(conf.groupsClaim)
+	user, err := auth.GetUserByEmail(email)
+	if err != nil {
+		if err != authTypes.ErrUserNotFound {
+			return nil, err
+		}
+		registrationEnabled, _ := config.GetBool("auth:user-registration")
+		if !registrationEnabled {
+			return nil, err
+		}
+		user = &auth.User{Email: email, Groups: groups, Origin: conf.origin()}
+		err = user.Create()
+	} else {
+		changed := user.Origin == ""
+		if changed {
+			name, resolveErr := resolveProvider("")
+			if resolveErr != nil || name != conf.name {
+				return nil, ErrUserFromAnotherProvider
+			}
+			user.Origin = conf.origin()
+		}
+		if user.Origin != conf.origin() {
+			return nil, ErrUserFromAnotherProvider
+		}
+		if !set.FromSlice(user.Groups).Equal(set.FromSlice(groups)) {
+			user.Groups = groups
+			changed = true
+		}
+		if changed {
+			err = user.Update()
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = syncTeamRoles(conf, user, groups)
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// syncTeamRoles grants the team role to the user in the teams mapped from
+// its groups, and revokes it from the mapped teams of groups it doesn't
+// belong to anymore. Roles in teams that aren't mapped are left untouched.
+func syncTeamRoles(conf *oidcConfig, user *auth.User, groups []string) error {
+	if len(conf.teamMapping) == 0 {
+		return nil
+	}
+	mapped := set.Set{}
+	wanted := set.Set{}
+	for group, team := range conf.teamMapping {
+		mapped.Add(team)
+		if set.FromSlice(groups).Includes(group) {
+			wanted.Add(team)
+		}
+	}
+	current := set.Set{}
+	for _, role := range user.Roles {
+		if role.Name == conf.teamRole {
+			current.Add(role.ContextValue)
+		}
+	}
+	for _, team := range mapped.Sorted() {
+		var err error
+		switch {
+		case wanted.Includes(team) && !current.Includes(team):
+			err = user.AddRole(conf.teamRole, team)
+		case !wanted.Includes(team) && current.Includes(team):
+			err = user.RemoveRole(conf.teamRole, team)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *oidcScheme) AppLogin(ctx context.Context, appName string) (auth.Token, error) {
+	nativeScheme := native.NativeScheme{}
+	return nativeScheme.AppLogin(ctx, appName)
+}
+
+func (s *oidcScheme) AppLogout(ctx context.Context, token string) error {
+	nativeScheme := native.NativeScheme{}
+	return nativeScheme.AppLogout(ctx, token)
+}
+
+func (s *oidcScheme) Logout(ctx context.Context, token string) error {
+	return deleteToken(token)
+}
+
+// Auth returns the token in header. When the token of the provider is
+// expired, it's refreshed, so that the tsuru token is valid for as long as
+// the provider keeps the session of the user.
+func (s *oidcScheme) Auth(ctx context.Context, header string) (auth.Token, error) {
+	token, err := getToken(header)
+	if err != nil {
+		nativeScheme := native.NativeScheme{}
+		token, nativeErr := nativeScheme.Auth(ctx, header)
+		if nativeErr == nil && token.IsAppToken() {
+			return token, nil
+		}
+		return nil, err
+	}
+	if s.now().Before(token.ProviderToken.Expiry) {
+		return token, nil
+	}
+	err = s.refresh(ctx, token)
+	if err != nil {
+		log.Debugf("[oidc] unable to refresh token of %s: %v", token.UserEmail, err)
+		return nil, auth.ErrInvalidToken
+	}
+	return token, nil
+}
+
+func (s *oidcScheme) refresh(ctx context.Context, token *tokenWrapper) error {
+	if token.ProviderToken.RefreshToken == "" {
+		return errors.New("token can't be refreshed")
+	}
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
+	current, err := getToken(token.Token)
+	if err != nil {
+		return err
+	}
+	if s.now().Before(current.ProviderToken.Expiry) {
+		*token = *current
+		return nil
+	}
+	conf, err := s.loadConfig(ctx, current.providerName())
+	if err != nil {
+		return err
+	}
+	expired := &oauth2.Token{RefreshToken: current.ProviderToken.RefreshToken, Expiry: current.ProviderToken.Expiry}
+	providerToken, err := conf.oauth2.TokenSource(s.clientContext(ctx), expired).Token()
+	if err != nil {
+		return err
+	}
+	var claims idTokenClaims
+	if conf.provider == nil {
+		claims, err = s.userinfo(ctx, conf, providerToken)
+	} else if _, ok := providerToken.Extra("id_token").(string); ok {
+		claims, err = s.verifyToken(ctx, conf, providerToken)
+	}
+	if err != nil {
+		return err
+	}
+	if claims != nil {
+		user, err := s.syncUser(conf, claims)
+		if err != nil {
+			return err
+		}
+		if user.Email != current.UserEmail {
+			return errors.New("refreshed token belongs to another user")
+		}
+	}
+	err = current.updateProviderToken(providerToken)
+	if err != nil {
+		return err
+	}
+	*token = *current
+	return nil
+}
+
+func (s *oidcScheme) Name() string {
+	return "oidc"
+}
+
+// Info returns the authorization URL for clients, with the redirect URL
+// replaced by a placeholder, of the default provider and of every provider
+// in the providers key. Clients should add the code challenge of the PKCE
+// flow to the URLs of OpenID providers.
+func (s *oidcScheme) Info(ctx context.Context) (auth.SchemeInfo, error) {
+	names, err := providerNames()
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		names = []string{defaultProvider}
+	}
+	defaultName, _ := resolveProvider("")
+	info := auth.SchemeInfo{}
+	providers := make([]map[string]string, 0, len(names))
+	for _, name := range names {
+		conf, err := s.loadConfig(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		conf.oauth2.RedirectURL = "__redirect_url__"
+		providerInfo := map[string]string{
+			"name":         name,
+			"authorizeUrl": conf.oauth2.AuthCodeURL(""),
+			"port":         strconv.Itoa(conf.callbackPort),
+		}
+		if conf.provider != nil {
+			providerInfo["codeChallengeMethod"] = "S256"
+		}
+		if name == defaultName {
+			for k, v := range providerInfo {
+				if k != "name" {
+					info[k] = v
+				}
+			}
+		}
+		providers = append(providers, providerInfo)
+	}
+	info["providers"] = providers
+	return info, nil
+}
+func (s *oidcScheme) Create(ctx context.Context, user *auth.User) (*auth.User, error) {
+	user.Password = ""
+	err := user.Create()
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (s *oidcScheme) Remove(ctx context.Context, u *auth.User) error {
+	err := deleteAllTokens(u.Email)
+	if err != nil {
+		return err
+	}
+	return u.Delete()
+}
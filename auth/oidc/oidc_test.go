@@ -0,0 +1,420 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package oidc
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/permission"
+	authTypes "github.com/tsuru/tsuru/types/auth"
+	check "gopkg.in/check.v1"
+)
+
+func loginParams() map[string]string {
+	return map[string]string{
+		"code":        "abcdefg",
+		"redirectUrl": "http://localhost",
+	}
+}
+
+func (s *S) TestOIDCLoginWithoutCode(c *check.C) {
+	params := loginParams()
+	delete(params, "code")
+	_, err := s.newScheme().Login(context.TODO(), params)
+	c.Assert(err, check.Equals, ErrMissingCodeError)
+}
+
+func (s *S) TestOIDCLoginWithoutRedirectUrl(c *check.C) {
+	params := loginParams()
+	delete(params, "redirectUrl")
+	_, err := s.newScheme().Login(context.TODO(), params)
+	c.Assert(err, check.Equals, ErrMissingCodeRedirectURL)
+}
+
+func (s *S) TestOIDCLogin(c *check.C) {
+	params := loginParams()
+	params["codeVerifier"] = "myverifier"
+	token, err := s.newScheme().Login(context.TODO(), params)
+	c.Assert(err, check.IsNil)
+	c.Assert(token.GetValue(), check.HasLen, 64)
+	c.Assert(token.GetUserName(), check.Equals, "rand@althor.com")
+	c.Assert(token.IsAppToken(), check.Equals, false)
+	u, err := token.User()
+	c.Assert(err, check.IsNil)
+	c.Assert(u.Email, check.Equals, "rand@althor.com")
+	var tokenBody url.Values
+	for i, r := range s.reqs {
+		if r.URL.Path == "/token" {
+			tokenBody, err = url.ParseQuery(s.bodies[i])
+			c.Assert(err, check.IsNil)
+		}
+	}
+	c.Assert(tokenBody, check.DeepEquals, url.Values{
+		"code":          {"abcdefg"},
+		"code_verifier": {"myverifier"},
+		"grant_type":    {"authorization_code"},
+		"redirect_uri":  {"http://localhost"},
+	})
+	dbToken, err := getToken("bearer " + token.GetValue())
+	c.Assert(err, check.IsNil)
+	c.Assert(dbToken.UserEmail, check.Equals, "rand@althor.com")
+	c.Assert(dbToken.ProviderToken.AccessToken, check.Equals, "access1")
+	c.Assert(dbToken.ProviderToken.RefreshToken, check.Equals, "refresh1")
+}
+
+func (s *S) TestOIDCLoginRegistrationDisabled(c *check.C) {
+	config.Set("auth:user-registration", false)
+	defer config.Set("auth:user-registration", true)
+	_, err := s.newScheme().Login(context.TODO(), loginParams())
+	c.Assert(err, check.Equals, authTypes.ErrUserNotFound)
+}
+
+func (s *S) TestOIDCLoginMissingIDToken(c *check.C) {
+	delete(s.tokenResponse, "id_token")
+	_, err := s.newScheme().Login(context.TODO(), loginParams())
+	c.Assert(err, check.Equals, ErrMissingIDToken)
+}
+
+func (s *S) TestOIDCLoginInvalidIDToken(c *check.C) {
+	s.tokenResponse["id_token"] = s.idToken(c, map[string]interface{}{"email": "rand@althor.com", "aud": "other"})
+	_, err := s.newScheme().Login(context.TODO(), loginParams())
+	c.Assert(err, check.ErrorMatches, "token wasn't issued to this client: invalid ID token")
+	_, err = auth.GetUserByEmail("rand@althor.com")
+	c.Assert(err, check.Equals, authTypes.ErrUserNotFound)
+}
+
+func (s *S) TestOIDCLoginNonce(c *check.C) {
+	s.tokenResponse["id_token"] = s.idToken(c, map[string]interface{}{"email": "rand@althor.com", "nonce": "n1"})
+	params := loginParams()
+	params["nonce"] = "n2"
+	_, err := s.newScheme().Login(context.TODO(), params)
+	c.Assert(err, check.Equals, ErrInvalidNonce)
+	params["nonce"] = "n1"
+	_, err = s.newScheme().Login(context.TODO(), params)
+	c.Assert(err, check.IsNil)
+}
+
+func (s *S) TestOIDCLoginUnverifiedEmail(c *check.C) {
+	s.tokenResponse["id_token"] = s.idToken(c, map[string]interface{}{"email": "rand@althor.com", "email_verified": false})
+	_, err := s.newScheme().Login(context.TODO(), loginParams())
+	c.Assert(err, check.Equals, ErrUnverifiedUserEmail)
+}
+
+func (s *S) TestOIDCLoginEmailClaim(c *check.C) {
+	config.Set("auth:oidc:email-claim", "upn")
+	defer config.Unset("auth:oidc:email-claim")
+	_, err := s.newScheme().Login(context.TODO(), loginParams())
+	c.Assert(err, check.Equals, ErrEmptyUserEmail)
+	s.tokenResponse["id_token"] = s.idToken(c, map[string]interface{}{"upn": "mat@althor.com"})
+	token, err := s.newScheme().Login(context.TODO(), loginParams())
+	c.Assert(err, check.IsNil)
+	c.Assert(token.GetUserName(), check.Equals, "mat@althor.com")
+}
+
+func (s *S) TestOIDCLoginGroupsAndTeams(c *check.C) {
+	_, err := permission.NewRole("team-member", "team", "")
+	c.Assert(err, check.IsNil)
+	config.Set("auth:oidc:team-mapping", map[interface{}]interface{}{
+		"tsuru-devs": "devs",
+		"tsuru-ops":  "ops",
+	})
+	config.Set("auth:oidc:team-role", "team-member")
+	defer config.Unset("auth:oidc:team-mapping")
+	defer config.Unset("auth:oidc:team-role")
+	s.tokenResponse["id_token"] = s.idToken(c, map[string]interface{}{
+		"email":  "rand@althor.com",
+		"groups": []string{"tsuru-devs", "other"},
+	})
+	_, err = s.newScheme().Login(context.TODO(), loginParams())
+	c.Assert(err, check.IsNil)
+	u, err := auth.GetUserByEmail("rand@althor.com")
+	c.Assert(err, check.IsNil)
+	c.Assert(u.Groups, check.DeepEquals, []string{"tsuru-devs", "other"})
+	c.Assert(u.Roles, check.DeepEquals, []authTypes.RoleInstance{{Name: "team-member", ContextValue: "devs"}})
+	err = u.AddRole("team-member", "qa")
+	c.Assert(err, check.IsNil)
+	s.tokenResponse["id_token"] = s.idToken(c, map[string]interface{}{
+		"email":  "rand@althor.com",
+		"groups": []string{"tsuru-ops"},
+	})
+	_, err = s.newScheme().Login(context.TODO(), loginParams())
+	c.Assert(err, check.IsNil)
+	u, err = auth.GetUserByEmail("rand@althor.com")
+	c.Assert(err, check.IsNil)
+	c.Assert(u.Groups, check.DeepEquals, []string{"tsuru-ops"})
+	c.Assert(u.Roles, check.DeepEquals, []authTypes.RoleInstance{
+		{Name: "team-member", ContextValue: "qa"},
+		{Name: "team-member", ContextValue: "ops"},
+	})
+}
+
+func (s *S) TestOIDCLoginTeamMappingWithoutRole(c *check.C) {
+	config.Set("auth:oidc:team-mapping", map[interface{}]interface{}{"tsuru-devs": "devs"})
+	defer config.Unset("auth:oidc:team-mapping")
+	_, err := s.newScheme().Login(context.TODO(), loginParams())
+	c.Assert(err, check.ErrorMatches, "auth:oidc:team-role is required with auth:oidc:team-mapping")
+}
+
+func (s *S) TestOIDCAuth(c *check.C) {
+	scheme := s.newScheme()
+	token, err := scheme.Login(context.TODO(), loginParams())
+	c.Assert(err, check.IsNil)
+	authToken, err := scheme.Auth(context.TODO(), "bearer "+token.GetValue())
+	c.Assert(err, check.IsNil)
+	c.Assert(authToken.GetValue(), check.Equals, token.GetValue())
+	c.Assert(authToken.GetUserName(), check.Equals, "rand@althor.com")
+	_, err = scheme.Auth(context.TODO(), "bearer invalid")
+	c.Assert(err, check.Equals, auth.ErrInvalidToken)
+}
+
+func (s *S) TestOIDCAuthRefresh(c *check.C) {
+	scheme := s.newScheme()
+	token, err := scheme.Login(context.TODO(), loginParams())
+	c.Assert(err, check.IsNil)
+	s.now = s.now.Add(2 * time.Hour)
+	s.tokenResponse = map[string]interface{}{
+		"access_token":  "access2",
+		"token_type":    "Bearer",
+		"refresh_token": "refresh2",
+		"expires_in":    3600 * 4,
+		"id_token":      s.idToken(c, map[string]interface{}{"email": "rand@althor.com"}),
+	}
+	s.reqs = nil
+	s.bodies = nil
+	authToken, err := scheme.Auth(context.TODO(), "bearer "+token.GetValue())
+	c.Assert(err, check.IsNil)
+	c.Assert(authToken.GetValue(), check.Equals, token.GetValue())
+	c.Assert(s.reqs, check.HasLen, 1)
+	c.Assert(s.reqs[0].URL.Path, check.Equals, "/token")
+	body, err := url.ParseQuery(s.bodies[0])
+	c.Assert(err, check.IsNil)
+	c.Assert(body.Get("grant_type"), check.Equals, "refresh_token")
+	c.Assert(body.Get("refresh_token"), check.Equals, "refresh1")
+	dbToken, err := getToken(token.GetValue())
+	c.Assert(err, check.IsNil)
+	c.Assert(dbToken.ProviderToken.AccessToken, check.Equals, "access2")
+	c.Assert(dbToken.ProviderToken.RefreshToken, check.Equals, "refresh2")
+	_, err = scheme.Auth(context.TODO(), "bearer "+token.GetValue())
+	c.Assert(err, check.IsNil)
+	c.Assert(s.reqs, check.HasLen, 1)
+}
+
+func (s *S) TestOIDCAuthRefreshWithoutRefreshToken(c *check.C) {
+	delete(s.tokenResponse, "refresh_token")
+	scheme := s.newScheme()
+	token, err := scheme.Login(context.TODO(), loginParams())
+	c.Assert(err, check.IsNil)
+	s.now = s.now.Add(2 * time.Hour)
+	_, err = scheme.Auth(context.TODO(), "bearer "+token.GetValue())
+	c.Assert(err, check.Equals, auth.ErrInvalidToken)
+}
+
+func (s *S) TestOIDCLogout(c *check.C) {
+	scheme := s.newScheme()
+	token, err := scheme.Login(context.TODO(), loginParams())
+	c.Assert(err, check.IsNil)
+	err = scheme.Logout(context.TODO(), token.GetValue())
+	c.Assert(err, check.IsNil)
+	_, err = getToken(token.GetValue())
+	c.Assert(err, check.Equals, auth.ErrInvalidToken)
+}
+
+func (s *S) TestOIDCRemove(c *check.C) {
+	scheme := s.newScheme()
+	token, err := scheme.Login(context.TODO(), loginParams())
+	c.Assert(err, check.IsNil)
+	u, err := auth.GetUserByEmail("rand@althor.com")
+	c.Assert(err, check.IsNil)
+	err = scheme.Remove(context.TODO(), u)
+	c.Assert(err, check.IsNil)
+	_, err = getToken(token.GetValue())
+	c.Assert(err, check.Equals, auth.ErrInvalidToken)
+	_, err = auth.GetUserByEmail("rand@althor.com")
+	c.Assert(err, check.Equals, authTypes.ErrUserNotFound)
+}
+
+func (s *S) TestOIDCName(c *check.C) {
+	c.Assert(s.newScheme().Name(), check.Equals, "oidc")
+}
+
+func (s *S) TestOIDCInfo(c *check.C) {
+	config.Set("auth:oidc:callback-port", 8080)
+	defer config.Unset("auth:oidc:callback-port")
+	info, err := s.newScheme().Info(context.TODO())
+	c.Assert(err, check.IsNil)
+	c.Assert(info["authorizeUrl"], check.Matches, s.server.URL+"/auth.*")
+	c.Assert(info["authorizeUrl"], check.Matches, ".*client_id=clientid.*")
+	c.Assert(info["authorizeUrl"], check.Matches, ".*redirect_uri=__redirect_url__.*")
+	c.Assert(info["authorizeUrl"], check.Matches, ".*scope=openid\\+email\\+profile.*")
+	c.Assert(info["port"], check.Equals, "8080")
+	c.Assert(info["codeChallengeMethod"], check.Equals, "S256")
+}
+
+func (s *S) setOAuthProvider(name string) func() {
+	prefix := "auth:oidc:providers:" + name
+	config.Set(prefix+":auth-url", s.server.URL+"/auth")
+	config.Set(prefix+":token-url", s.server.URL+"/token")
+	config.Set(prefix+":userinfo-url", s.server.URL+"/userinfo")
+	config.Set(prefix+":client-id", name+"-clientid")
+	config.Set(prefix+":scopes", []string{"user:email"})
+	return func() {
+		config.Unset("auth:oidc:providers")
+	}
+}
+
+func (s *S) TestOIDCLoginOrigin(c *check.C) {
+	token, err := s.newScheme().Login(context.TODO(), loginParams())
+	c.Assert(err, check.IsNil)
+	u, err := token.User()
+	c.Assert(err, check.IsNil)
+	c.Assert(u.Origin, check.Equals, "oidc:default")
+	dbToken, err := getToken(token.GetValue())
+	c.Assert(err, check.IsNil)
+	c.Assert(dbToken.Provider, check.Equals, "default")
+}
+
+func (s *S) TestOIDCLoginWithProvider(c *check.C) {
+	defer s.setOAuthProvider("github")()
+	s.userinfoResponse = map[string]interface{}{"email": "mat@althor.com", "email_verified": true, "groups": []string{"contractors"}}
+	params := loginParams()
+	params["provider"] = "github"
+	token, err := s.newScheme().Login(context.TODO(), params)
+	c.Assert(err, check.IsNil)
+	c.Assert(token.GetUserName(), check.Equals, "mat@althor.com")
+	u, err := auth.GetUserByEmail("mat@althor.com")
+	c.Assert(err, check.IsNil)
+	c.Assert(u.Origin, check.Equals, "oidc:github")
+	c.Assert(u.Groups, check.DeepEquals, []string{"contractors"})
+	dbToken, err := getToken(token.GetValue())
+	c.Assert(err, check.IsNil)
+	c.Assert(dbToken.Provider, check.Equals, "github")
+	var tokenBody url.Values
+	for i, r := range s.reqs {
+		if r.URL.Path == "/token" {
+			tokenBody, err = url.ParseQuery(s.bodies[i])
+			c.Assert(err, check.IsNil)
+		}
+	}
+	c.Assert(tokenBody.Get("grant_type"), check.Equals, "authorization_code")
+	for _, r := range s.reqs {
+		if r.URL.Path == "/token" {
+			clientID, _, _ := r.BasicAuth()
+			c.Assert(clientID, check.Equals, "github-clientid")
+		}
+	}
+	c.Assert(s.reqs[len(s.reqs)-1].URL.Path, check.Equals, "/userinfo")
+	c.Assert(s.reqs[len(s.reqs)-1].Header.Get("Authorization"), check.Equals, "Bearer access1")
+}
+
+func (s *S) TestOIDCLoginUnknownProvider(c *check.C) {
+	params := loginParams()
+	params["provider"] = "github"
+	_, err := s.newScheme().Login(context.TODO(), params)
+	c.Assert(err, check.ErrorMatches, `Unknown provider "github"`)
+}
+
+func (s *S) TestOIDCLoginMissingProvider(c *check.C) {
+	config.Unset("auth:oidc:issuer")
+	defer config.Set("auth:oidc:issuer", s.server.URL)
+	defer s.setOAuthProvider("github")()
+	s.setOAuthProvider("gitlab")
+	_, err := s.newScheme().Login(context.TODO(), loginParams())
+	c.Assert(err, check.Equals, ErrMissingProvider)
+	config.Unset("auth:oidc:providers:gitlab")
+	token, err := s.newScheme().Login(context.TODO(), loginParams())
+	c.Assert(err, check.IsNil)
+	dbToken, err := getToken(token.GetValue())
+	c.Assert(err, check.IsNil)
+	c.Assert(dbToken.Provider, check.Equals, "github")
+}
+
+func (s *S) TestOIDCLoginUserFromAnotherProvider(c *check.C) {
+	defer s.setOAuthProvider("github")()
+	_, err := s.newScheme().Login(context.TODO(), loginParams())
+	c.Assert(err, check.IsNil)
+	params := loginParams()
+	params["provider"] = "github"
+	_, err = s.newScheme().Login(context.TODO(), params)
+	c.Assert(err, check.Equals, ErrUserFromAnotherProvider)
+}
+
+func (s *S) TestOIDCLoginBindsUserWithoutOriginToDefaultProvider(c *check.C) {
+	defer s.setOAuthProvider("github")()
+	u := &auth.User{Email: "rand@althor.com"}
+	err := u.Create()
+	c.Assert(err, check.IsNil)
+	params := loginParams()
+	params["provider"] = "github"
+	_, err = s.newScheme().Login(context.TODO(), params)
+	c.Assert(err, check.Equals, ErrUserFromAnotherProvider)
+	u, err = auth.GetUserByEmail("rand@althor.com")
+	c.Assert(err, check.IsNil)
+	c.Assert(u.Origin, check.Equals, "")
+	_, err = s.newScheme().Login(context.TODO(), loginParams())
+	c.Assert(err, check.IsNil)
+	u, err = auth.GetUserByEmail("rand@althor.com")
+	c.Assert(err, check.IsNil)
+	c.Assert(u.Origin, check.Equals, "oidc:default")
+}
+
+func (s *S) TestOIDCLoginMissingEmailVerified(c *check.C) {
+	s.tokenResponse["id_token"] = s.idToken(c, map[string]interface{}{"email": "rand@althor.com", "email_verified": nil})
+	_, err := s.newScheme().Login(context.TODO(), loginParams())
+	c.Assert(err, check.Equals, ErrUnverifiedUserEmail)
+}
+
+func (s *S) TestOIDCAuthRefreshWithProvider(c *check.C) {
+	defer s.setOAuthProvider("github")()
+	scheme := s.newScheme()
+	params := loginParams()
+	params["provider"] = "github"
+	token, err := scheme.Login(context.TODO(), params)
+	c.Assert(err, check.IsNil)
+	s.now = s.now.Add(2 * time.Hour)
+	s.tokenResponse = map[string]interface{}{
+		"access_token":  "access2",
+		"token_type":    "Bearer",
+		"refresh_token": "refresh2",
+		"expires_in":    3600 * 4,
+	}
+	s.reqs = nil
+	s.bodies = nil
+	_, err = scheme.Auth(context.TODO(), "bearer "+token.GetValue())
+	c.Assert(err, check.IsNil)
+	c.Assert(s.reqs, check.HasLen, 2)
+	c.Assert(s.reqs[0].URL.Path, check.Equals, "/token")
+	clientID, _, _ := s.reqs[0].BasicAuth()
+	c.Assert(clientID, check.Equals, "github-clientid")
+	c.Assert(s.reqs[1].URL.Path, check.Equals, "/userinfo")
+}
+
+func (s *S) TestOIDCInfoProviders(c *check.C) {
+	defer s.setOAuthProvider("github")()
+	info, err := s.newScheme().Info(context.TODO())
+	c.Assert(err, check.IsNil)
+	c.Assert(info["authorizeUrl"], check.Matches, ".*client_id=clientid.*")
+	c.Assert(info["codeChallengeMethod"], check.Equals, "S256")
+	providers, ok := info["providers"].([]map[string]string)
+	c.Assert(ok, check.Equals, true)
+	c.Assert(providers, check.HasLen, 2)
+	c.Assert(providers[0]["name"], check.Equals, "default")
+	c.Assert(providers[0]["codeChallengeMethod"], check.Equals, "S256")
+	c.Assert(providers[1]["name"], check.Equals, "github")
+	c.Assert(providers[1]["authorizeUrl"], check.Matches, s.server.URL+"/auth.*")
+	c.Assert(providers[1]["authorizeUrl"], check.Matches, ".*client_id=github-clientid.*")
+	c.Assert(providers[1]["authorizeUrl"], check.Matches, ".*scope=user%3Aemail.*")
+	c.Assert(providers[1]["codeChallengeMethod"], check.Equals, "")
+}
+
+func (s *S) TestProviderNamesReservedDefault(c *check.C) {
+	config.Set("auth:oidc:providers:default:issuer", s.server.URL)
+	defer config.Unset("auth:oidc:providers")
+	_, err := providerNames()
+	c.Assert(err, check.ErrorMatches, `auth:oidc:providers can't have a provider named "default"`)
+}
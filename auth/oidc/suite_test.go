@@ -0,0 +1,157 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package oidc
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/db"
+	"github.com/tsuru/tsuru/db/dbtest"
+	check "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) { check.TestingT(t) }
+
+type S struct {
+	conn   *db.Storage
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	now    time.Time
+	reqs   []*http.Request
+	bodies []string
+	// tokenResponse is the response of the token endpoint of the fake
+	// provider.
+	tokenResponse map[string]interface{}
+	// userinfoResponse is the response of the userinfo endpoint, used by
+	// plain OAuth 2.0 providers.
+	userinfoResponse map[string]interface{}
+}
+
+var _ = check.Suite(&S{})
+
+func (s *S) SetUpSuite(c *check.C) {
+	var err error
+	s.key, err = rsa.GenerateKey(rand.Reader, 2048)
+	c.Assert(err, check.IsNil)
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, err := ioutil.ReadAll(r.Body)
+		c.Assert(err, check.IsNil)
+		s.bodies = append(s.bodies, string(b))
+		s.reqs = append(s.reqs, r)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/.well-known/openid-configuration", "/other/.well-known/openid-configuration":
+			json.NewEncoder(w).Encode(map[string]string{
+				"issuer":                 s.server.URL,
+				"authorization_endpoint": s.server.URL + "/auth",
+				"token_endpoint":         s.server.URL + "/token",
+				"jwks_uri":               s.server.URL + "/keys",
+			})
+		case "/keys":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"keys": []map[string]string{{
+					"kty": "RSA",
+					"kid": "key1",
+					"use": "sig",
+					"n":   base64.RawURLEncoding.EncodeToString(s.key.N.Bytes()),
+					"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(s.key.E)).Bytes()),
+				}},
+			})
+		case "/token":
+			json.NewEncoder(w).Encode(s.tokenResponse)
+		case "/userinfo":
+			json.NewEncoder(w).Encode(s.userinfoResponse)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	config.Set("auth:oidc:issuer", s.server.URL)
+	config.Set("auth:oidc:client-id", "clientid")
+	config.Set("auth:oidc:client-secret", "clientsecret")
+	config.Set("auth:oidc:collection", "oidc_token")
+	config.Set("database:url", "127.0.0.1:27017?maxPoolSize=100")
+	config.Set("database:name", "tsuru_auth_oidc_test")
+	config.Set("auth:user-registration", true)
+}
+
+func (s *S) SetUpTest(c *check.C) {
+	s.conn, _ = db.Conn()
+	s.reqs = make([]*http.Request, 0)
+	s.bodies = make([]string, 0)
+	s.now = time.Now()
+	s.tokenResponse = map[string]interface{}{
+		"access_token":  "access1",
+		"token_type":    "Bearer",
+		"refresh_token": "refresh1",
+		"expires_in":    3600,
+		"id_token":      s.idToken(c, map[string]interface{}{"email": "rand@althor.com"}),
+	}
+	s.userinfoResponse = map[string]interface{}{"email": "rand@althor.com", "email_verified": true}
+}
+
+func (s *S) TearDownTest(c *check.C) {
+	err := dbtest.ClearAllCollections(s.conn.Users().Database)
+	c.Assert(err, check.IsNil)
+	s.conn.Close()
+}
+
+func (s *S) TearDownSuite(c *check.C) {
+	s.server.Close()
+	conn, err := db.Conn()
+	c.Assert(err, check.IsNil)
+	defer conn.Close()
+	dbtest.ClearAllCollections(conn.Users().Database)
+}
+
+func (s *S) newScheme() *oidcScheme {
+	return &oidcScheme{now: func() time.Time { return s.now }}
+}
+
+// idToken returns an ID token signed by the fake provider, claims override
+// the default claims of a valid token.
+func (s *S) idToken(c *check.C, claims map[string]interface{}) string {
+	allClaims := map[string]interface{}{
+		"iss":            s.server.URL,
+		"aud":            "clientid",
+		"sub":            "user1",
+		"exp":            s.now.Add(time.Hour).Unix(),
+		"iat":            s.now.Unix(),
+		"email_verified": true,
+	}
+	for k, v := range claims {
+		allClaims[k] = v
+	}
+	return signToken(c, s.key, "RS256", "key1", allClaims)
+}
+
+func signToken(c *check.C, key crypto.Signer, alg, kid string, claims map[string]interface{}) string {
+	header, err := json.Marshal(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"})
+	c.Assert(err, check.IsNil)
+	payload, err := json.Marshal(claims)
+	c.Assert(err, check.IsNil)
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	hash := crypto.SHA256
+	h := hash.New()
+	h.Write([]byte(signed))
+	var signature []byte
+	if _, isRSA := key.(*rsa.PrivateKey); isRSA {
+		signature, err = key.Sign(rand.Reader, h.Sum(nil), hash)
+		c.Assert(err, check.IsNil)
+	} else {
+		signature = signECDSA(c, key, h.Sum(nil))
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
@@ -0,0 +1,147 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package oidc
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/db"
+	"github.com/tsuru/tsuru/db/storage"
+	"github.com/tsuru/tsuru/log"
+	"github.com/tsuru/tsuru/permission"
+	authTypes "github.com/tsuru/tsuru/types/auth"
+	"golang.org/x/oauth2"
+)
+
+var _ authTypes.Token = &tokenWrapper{}
+
+// tokenWrapper is a tsuru token issued after a login with the OpenID
+// provider. The token of the provider is kept so that the session can be
+// refreshed when it expires.
+type tokenWrapper struct {
+	Token         string       `json:"token"`
+	UserEmail     string       `json:"email"`
+	Creation      time.Time    `json:"creation"`
+	ProviderToken oauth2.Token `json:"-"`
+	// Provider is the name of the provider the user logged in with, it's
+	// empty in tokens issued before multiple providers were supported.
+	Provider string `json:"provider,omitempty"`
+	teams    auth.TeamHierarchy
+}
+
+func newToken(email, providerName string, providerToken *oauth2.Token) *tokenWrapper {
+	var key [32]byte
+	n, err := rand.Read(key[:])
+	for n < len(key) || err != nil {
+		n, err = rand.Read(key[:])
+	}
+	return &tokenWrapper{
+		Token:         hex.EncodeToString(key[:]),
+		UserEmail:     email,
+		Creation:      time.Now().UTC(),
+		ProviderToken: *providerToken,
+		Provider:      providerName,
+	}
+}
+
+func (t *tokenWrapper) providerName() string {
+	if t.Provider == "" {
+		return defaultProvider
+	}
+	return t.Provider
+}
+
+func (t *tokenWrapper) GetValue() string {
+	return t.Token
+}
+
+func (t *tokenWrapper) User() (*authTypes.User, error) {
+	return auth.ConvertOldUser(auth.GetUserByEmail(t.UserEmail))
+}
+
+func (t *tokenWrapper) IsAppToken() bool {
+	return false
+}
+
+func (t *tokenWrapper) GetUserName() string {
+	return t.UserEmail
+}
+
+func (t *tokenWrapper) GetAppName() string {
+	return ""
+}
+
+func (t *tokenWrapper) Permissions() ([]permission.Permission, error) {
+	return auth.BaseTokenPermission(t, &t.teams)
+}
+
+func getToken(header string) (*tokenWrapper, error) {
+	var t tokenWrapper
+	token, err := auth.ParseToken(header)
+	if err != nil {
+		return nil, err
+	}
+	coll := collection()
+	defer coll.Close()
+	err = coll.Find(bson.M{"token": token}).One(&t)
+	if err != nil {
+		if err == mgo.ErrNotFound {
+			return nil, auth.ErrInvalidToken
+		}
+		return nil, err
+	}
+	return &t, nil
+}
+
+func deleteToken(token string) error {
+	coll := collection()
+	defer coll.Close()
+	return coll.Remove(bson.M{"token": token})
+}
+
+func deleteAllTokens(email string) error {
+	coll := collection()
+	defer coll.Close()
+	_, err := coll.RemoveAll(bson.M{"useremail": email})
+	return err
+}
+
+func (t *tokenWrapper) save() error {
+	coll := collection()
+	defer coll.Close()
+	return coll.Insert(t)
+}
+
+func (t *tokenWrapper) updateProviderToken(providerToken *oauth2.Token) error {
+	coll := collection()
+	defer coll.Close()
+	err := coll.Update(bson.M{"token": t.Token}, bson.M{"$set": bson.M{"providertoken": providerToken}})
+	if err != nil {
+		return err
+	}
+	t.ProviderToken = *providerToken
+	return nil
+}
+
+func collection() *storage.Collection {
+	name, err := config.GetString("auth:oidc:collection")
+	if err != nil {
+		name = "oidc_tokens"
+	}
+	conn, err := db.Conn()
+	if err != nil {
+		log.Errorf("Failed to connect to the database: %s", err)
+	}
+	coll := conn.Collection(name)
+	coll.EnsureIndex(mgo.Index{Key: []string{"token"}, Unique: true})
+	coll.EnsureIndex(mgo.Index{Key: []string{"useremail"}})
+	return coll
+}
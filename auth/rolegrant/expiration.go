@@ -0,0 +1,122 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package rolegrant revokes temporary role assignments of users once they
+// expire.
+package rolegrant
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tsuru/tsuru/api/shutdown"
+	"github.com/tsuru/tsuru/auth"
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/log"
+	"github.com/tsuru/tsuru/permission"
+	authTypes "github.com/tsuru/tsuru/types/auth"
+)
+
+const (
+	// ExpiredEventKind is the internal kind of the events created when a
+	// temporary role assignment expires and is revoked.
+	ExpiredEventKind = "role.assignment.expired"
+
+	revokeRunInterval = time.Minute
+)
+
+func Initialize() error {
+	r := &revoker{once: &sync.Once{}}
+	r.start()
+	shutdown.Register(r)
+	return nil
+}
+
+// revoker periodically revokes the expired temporary role assignments. They
+// already stop granting permissions once expired, the revocation removes them
+// from the users and records it in events.
+type revoker struct {
+	once   *sync.Once
+	stopCh chan struct{}
+}
+
+func (r *revoker) start() {
+	r.once.Do(func() {
+		r.stopCh = make(chan struct{})
+		go r.spin()
+	})
+}
+
+func (r *revoker) Shutdown(ctx context.Context) error {
+	if r.stopCh == nil {
+		return nil
+	}
+	r.stopCh <- struct{}{}
+	r.stopCh = nil
+	r.once = &sync.Once{}
+	return nil
+}
+
+func (r *revoker) spin() {
+	for {
+		err := RevokeExpired(time.Now())
+		if err != nil {
+			log.Errorf("[role expiration] %v", err)
+		}
+		select {
+		case <-r.stopCh:
+			return
+		case <-time.After(revokeRunInterval):
+		}
+	}
+}
+
+// RevokeExpired revokes the temporary role assignments expired at the given
+// time, creating an event for each of them.
+func RevokeExpired(now time.Time) error {
+	users, err := auth.ListUsersWithExpiredRoles(now)
+	if err != nil {
+		return err
+	}
+	multi := tsuruErrors.NewMultiError()
+	for i := range users {
+		expired, err := users[i].RemoveExpiredRoles(now)
+		if err != nil {
+			multi.Add(errors.Wrapf(err, "unable to revoke expired roles of %q", users[i].Email))
+			continue
+		}
+		for _, role := range expired {
+			err = registerRevocation(users[i].Email, role)
+			if err != nil {
+				multi.Add(errors.Wrapf(err, "unable to register revocation of role %q of %q", role.Name, users[i].Email))
+			}
+		}
+	}
+	return multi.ToError()
+}
+
+func registerRevocation(email string, role authTypes.RoleInstance) error {
+	evt, err := event.NewInternal(&event.Opts{
+		Target: event.Target{Type: event.TargetTypeRole, Value: role.Name},
+		ExtraTargets: []event.ExtraTarget{
+			{Target: event.Target{Type: event.TargetTypeUser, Value: email}},
+		},
+		InternalKind: ExpiredEventKind,
+		DisableLock:  true,
+		CustomData: map[string]interface{}{
+			"email":      email,
+			"context":    role.ContextValue,
+			"expires_at": role.ExpiresAt,
+		},
+		Allowed: event.Allowed(permission.PermRoleReadEvents),
+	})
+	if err != nil {
+		return err
+	}
+	log.Debugf("[role expiration] revoked role %q in context %q of %q", role.Name, role.ContextValue, email)
+	return evt.Done(nil)
+}
@@ -33,6 +33,7 @@ type Token struct {
 	Expires   time.Duration `json:"expires"`
 	UserEmail string        `json:"email"`
 	AppName   string        `json:"app"`
+	teams     auth.TeamHierarchy
 }
 
 func (t *Token) GetValue() string {
@@ -56,7 +57,7 @@ func (t *Token) GetAppName() string {
 }
 
 func (t *Token) Permissions() ([]permission.Permission, error) {
-	return auth.BaseTokenPermission(t)
+	return auth.BaseTokenPermission(t, &t.teams)
 }
 
 func loadConfig() error {
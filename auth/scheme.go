@@ -6,6 +6,8 @@ package auth
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/pkg/errors"
 )
@@ -32,6 +34,72 @@ type ManagedScheme interface {
 	ChangePassword(ctx context.Context, token Token, oldPassword string, newPassword string) error
 }
 
+// TwoFactorScheme is implemented by schemes supporting two-factor
+// authentication with time-based one-time passwords (TOTP). Operations on the
+// user of a token are confirmed by a code from the authenticator app or by
+// one of the recovery codes of the user.
+type TwoFactorScheme interface {
+	Scheme
+	EnrollTwoFactor(ctx context.Context, token Token) (*TwoFactorEnrollment, error)
+	EnableTwoFactor(ctx context.Context, token Token, code string) ([]string, error)
+	DisableTwoFactor(ctx context.Context, token Token, code string) error
+	RegenerateRecoveryCodes(ctx context.Context, token Token, code string) ([]string, error)
+	ResetTwoFactor(ctx context.Context, user *User) error
+	// VerifyTwoFactor checks the code for the user of the token, users
+	// without two-factor authentication enabled don't need one.
+	VerifyTwoFactor(ctx context.Context, token Token, code string) error
+}
+
+// TwoFactorEnrollment holds the secret to be added to the authenticator app
+// of the user, either directly or through the otpauth URL.
+type TwoFactorEnrollment struct {
+	Secret string `json:"secret"`
+	URL    string `json:"url"`
+}
+
+var ErrTwoFactorRequired = errors.New("two-factor authentication code required")
+
+// SessionScheme is implemented by schemes able to list the sessions of users
+// and to revoke them individually. TouchSession records the use of the
+// session of the token, from the given address.
+type SessionScheme interface {
+	Scheme
+	Sessions(ctx context.Context, user *User, current Token) ([]Session, error)
+	RevokeSession(ctx context.Context, user *User, id string) error
+	TouchSession(ctx context.Context, token Token, address string) error
+}
+
+// Session is an active login of a user, identified without exposing its
+// token. Current is set for the session of the token listing the sessions.
+type Session struct {
+	ID          string    `json:"id"`
+	Creation    time.Time `json:"creation"`
+	ExpiresAt   time.Time `json:"expiresAt"`
+	LastUse     time.Time `json:"lastUse"`
+	LastAddress string    `json:"lastAddress"`
+	Current     bool      `json:"current"`
+}
+
+var ErrSessionNotFound = errors.New("session not found")
+
+// LockoutScheme is implemented by schemes locking users out after repeated
+// authentication failures. Unlock clears the failures of the user, allowing
+// new login attempts right away.
+type LockoutScheme interface {
+	Scheme
+	Unlock(ctx context.Context, user *User) error
+}
+
+// AccountLockedError is returned on login attempts while the user is locked
+// out.
+type AccountLockedError struct {
+	Until time.Time
+}
+
+func (e *AccountLockedError) Error() string {
+	return fmt.Sprintf("Too many failed login attempts, try again after %s.", e.Until.UTC().Format(time.RFC3339))
+}
+
 type AuthenticationFailure struct {
 	Message string
 }
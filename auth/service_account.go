@@ -0,0 +1,127 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/servicemanager"
+	"github.com/tsuru/tsuru/storage"
+	appTypes "github.com/tsuru/tsuru/types/app"
+	authTypes "github.com/tsuru/tsuru/types/auth"
+	"github.com/tsuru/tsuru/validation"
+)
+
+// TsuruServiceAccountEmailDomain is the e-mail domain used to fake users from
+// service accounts, the same way as TsuruTokenEmailDomain.
+const TsuruServiceAccountEmailDomain = "tsuru-service-account"
+
+func IsEmailFromServiceAccount(email string) bool {
+	return strings.HasSuffix(email, fmt.Sprintf("@%s", TsuruServiceAccountEmailDomain))
+}
+
+func serviceAccountEmail(name string) string {
+	return fmt.Sprintf("%s@%s", name, TsuruServiceAccountEmailDomain)
+}
+
+type serviceAccountService struct {
+	storage      authTypes.ServiceAccountStorage
+	tokenStorage authTypes.TeamTokenStorage
+}
+
+var _ authTypes.ServiceAccountService = &serviceAccountService{}
+
+func ServiceAccountService() (authTypes.ServiceAccountService, error) {
+	dbDriver, err := storage.GetCurrentDbDriver()
+	if err != nil {
+		dbDriver, err = storage.GetDefaultDbDriver()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &serviceAccountService{
+		storage:      dbDriver.ServiceAccountStorage,
+		tokenStorage: dbDriver.TeamTokenStorage,
+	}, nil
+}
+
+func (s *serviceAccountService) Create(ctx context.Context, args authTypes.ServiceAccountCreateArgs, token authTypes.Token) (authTypes.ServiceAccount, error) {
+	if !validation.ValidateName(args.Name) {
+		return authTypes.ServiceAccount{}, &tsuruErrors.ValidationError{Message: "invalid service account name"}
+	}
+	u, err := token.User()
+	if err != nil {
+		return authTypes.ServiceAccount{}, err
+	}
+	_, err = servicemanager.Team.FindByName(ctx, args.Team)
+	if err != nil {
+		return authTypes.ServiceAccount{}, err
+	}
+	sa := authTypes.ServiceAccount{
+		Name:         args.Name,
+		Team:         args.Team,
+		Description:  args.Description,
+		CreatedAt:    time.Now().UTC(),
+		CreatorEmail: u.Email,
+	}
+	err = s.storage.Insert(ctx, sa)
+	if err != nil {
+		return authTypes.ServiceAccount{}, err
+	}
+	return sa, nil
+}
+
+func (s *serviceAccountService) FindByName(ctx context.Context, name string) (authTypes.ServiceAccount, error) {
+	sa, err := s.storage.FindByName(ctx, name)
+	if err != nil {
+		return authTypes.ServiceAccount{}, err
+	}
+	return *sa, nil
+}
+
+func (s *serviceAccountService) List(ctx context.Context, teams []string) ([]authTypes.ServiceAccount, error) {
+	return s.storage.FindByTeams(ctx, teams)
+}
+
+func (s *serviceAccountService) AddRole(ctx context.Context, name, roleName, contextValue string) error {
+	_, err := permission.FindRole(roleName)
+	if err != nil {
+		return err
+	}
+	return s.storage.AddRole(ctx, name, authTypes.RoleInstance{Name: roleName, ContextValue: contextValue})
+}
+
+func (s *serviceAccountService) RemoveRole(ctx context.Context, name, roleName, contextValue string) error {
+	return s.storage.RemoveRole(ctx, name, authTypes.RoleInstance{Name: roleName, ContextValue: contextValue})
+}
+
+// Delete removes a service account. Its tokens must be removed first, and the
+// apps it owns must be moved to another owner, as they'd be left orphan.
+func (s *serviceAccountService) Delete(ctx context.Context, name string) error {
+	_, err := s.storage.FindByName(ctx, name)
+	if err != nil {
+		return err
+	}
+	tokens, err := s.tokenStorage.FindByServiceAccount(ctx, name)
+	if err != nil {
+		return err
+	}
+	if len(tokens) > 0 {
+		return authTypes.ErrServiceAccountHasTokens
+	}
+	apps, err := servicemanager.App.List(ctx, &appTypes.Filter{UserOwner: serviceAccountEmail(name)})
+	if err != nil {
+		return err
+	}
+	if len(apps) > 0 {
+		return authTypes.ErrServiceAccountOwnsApps
+	}
+	return s.storage.Delete(ctx, name)
+}
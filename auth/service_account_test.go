@@ -0,0 +1,165 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"context"
+
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/servicemanager"
+	appTypes "github.com/tsuru/tsuru/types/app"
+	authTypes "github.com/tsuru/tsuru/types/auth"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) createServiceAccount(c *check.C, name string) authTypes.ServiceAccount {
+	sa, err := servicemanager.ServiceAccount.Create(context.TODO(), authTypes.ServiceAccountCreateArgs{
+		Name:        name,
+		Team:        s.team.Name,
+		Description: "ci",
+	}, &userToken{user: s.user})
+	c.Assert(err, check.IsNil)
+	return sa
+}
+
+func (s *S) Test_ServiceAccountService_Create(c *check.C) {
+	sa := s.createServiceAccount(c, "deployer")
+	c.Assert(sa.CreatedAt.IsZero(), check.Equals, false)
+	c.Assert(sa.CreatorEmail, check.Equals, s.user.Email)
+	dbSA, err := servicemanager.ServiceAccount.FindByName(context.TODO(), "deployer")
+	c.Assert(err, check.IsNil)
+	c.Assert(dbSA.Team, check.Equals, s.team.Name)
+	c.Assert(dbSA.Description, check.Equals, "ci")
+	_, err = servicemanager.ServiceAccount.Create(context.TODO(), authTypes.ServiceAccountCreateArgs{
+		Name: "deployer",
+		Team: s.team.Name,
+	}, &userToken{user: s.user})
+	c.Assert(err, check.Equals, authTypes.ErrServiceAccountAlreadyExists)
+}
+
+func (s *S) Test_ServiceAccountService_Create_Invalid(c *check.C) {
+	_, err := servicemanager.ServiceAccount.Create(context.TODO(), authTypes.ServiceAccountCreateArgs{
+		Name: "Invalid Name",
+		Team: s.team.Name,
+	}, &userToken{user: s.user})
+	c.Assert(err, check.ErrorMatches, "invalid service account name")
+	_, err = servicemanager.ServiceAccount.Create(context.TODO(), authTypes.ServiceAccountCreateArgs{
+		Name: "deployer",
+		Team: "unknown",
+	}, &userToken{user: s.user})
+	c.Assert(err, check.Equals, authTypes.ErrTeamNotFound)
+}
+
+func (s *S) Test_ServiceAccountService_List(c *check.C) {
+	s.createServiceAccount(c, "deployer")
+	accounts, err := servicemanager.ServiceAccount.List(context.TODO(), []string{s.team.Name})
+	c.Assert(err, check.IsNil)
+	c.Assert(accounts, check.HasLen, 1)
+	c.Assert(accounts[0].Name, check.Equals, "deployer")
+	accounts, err = servicemanager.ServiceAccount.List(context.TODO(), []string{"other"})
+	c.Assert(err, check.IsNil)
+	c.Assert(accounts, check.HasLen, 0)
+}
+
+func (s *S) Test_ServiceAccountService_Roles(c *check.C) {
+	_, err := permission.NewRole("app-deployer", "app", "")
+	c.Assert(err, check.IsNil)
+	s.createServiceAccount(c, "deployer")
+	err = servicemanager.ServiceAccount.AddRole(context.TODO(), "deployer", "app-deployer", "myapp")
+	c.Assert(err, check.IsNil)
+	sa, err := servicemanager.ServiceAccount.FindByName(context.TODO(), "deployer")
+	c.Assert(err, check.IsNil)
+	c.Assert(sa.Roles, check.DeepEquals, []authTypes.RoleInstance{{Name: "app-deployer", ContextValue: "myapp"}})
+	err = servicemanager.ServiceAccount.AddRole(context.TODO(), "deployer", "unknown-role", "")
+	c.Assert(err, check.Equals, permTypes.ErrRoleNotFound)
+	err = servicemanager.ServiceAccount.RemoveRole(context.TODO(), "deployer", "app-deployer", "myapp")
+	c.Assert(err, check.IsNil)
+	sa, err = servicemanager.ServiceAccount.FindByName(context.TODO(), "deployer")
+	c.Assert(err, check.IsNil)
+	c.Assert(sa.Roles, check.HasLen, 0)
+}
+
+func (s *S) Test_ServiceAccountService_Delete(c *check.C) {
+	servicemanager.App = &appTypes.MockAppService{
+		OnList: func(filter *appTypes.Filter) ([]appTypes.App, error) {
+			c.Assert(filter, check.DeepEquals, &appTypes.Filter{UserOwner: "deployer@tsuru-service-account"})
+			return nil, nil
+		},
+	}
+	s.createServiceAccount(c, "deployer")
+	token, err := servicemanager.TeamToken.Create(context.TODO(), authTypes.TeamTokenCreateArgs{
+		Team:           s.team.Name,
+		ServiceAccount: "deployer",
+	}, &userToken{user: s.user})
+	c.Assert(err, check.IsNil)
+	err = servicemanager.ServiceAccount.Delete(context.TODO(), "deployer")
+	c.Assert(err, check.Equals, authTypes.ErrServiceAccountHasTokens)
+	err = servicemanager.TeamToken.Delete(context.TODO(), token.TokenID)
+	c.Assert(err, check.IsNil)
+	err = servicemanager.ServiceAccount.Delete(context.TODO(), "deployer")
+	c.Assert(err, check.IsNil)
+	_, err = servicemanager.ServiceAccount.FindByName(context.TODO(), "deployer")
+	c.Assert(err, check.Equals, authTypes.ErrServiceAccountNotFound)
+}
+
+func (s *S) Test_ServiceAccountService_Delete_OwnsApps(c *check.C) {
+	servicemanager.App = &appTypes.MockAppService{
+		OnList: func(filter *appTypes.Filter) ([]appTypes.App, error) {
+			return []appTypes.App{&appTypes.MockApp{Name: "my-app1"}}, nil
+		},
+	}
+	s.createServiceAccount(c, "deployer")
+	err := servicemanager.ServiceAccount.Delete(context.TODO(), "deployer")
+	c.Assert(err, check.Equals, authTypes.ErrServiceAccountOwnsApps)
+}
+
+func (s *S) Test_TeamTokenService_ServiceAccount(c *check.C) {
+	r, err := permission.NewRole("app-deployer", "app", "")
+	c.Assert(err, check.IsNil)
+	err = r.AddPermissions("app.deploy")
+	c.Assert(err, check.IsNil)
+	s.createServiceAccount(c, "deployer")
+	err = servicemanager.ServiceAccount.AddRole(context.TODO(), "deployer", "app-deployer", "myapp")
+	c.Assert(err, check.IsNil)
+	token, err := servicemanager.TeamToken.Create(context.TODO(), authTypes.TeamTokenCreateArgs{
+		TokenID:        "t1",
+		Team:           s.team.Name,
+		ServiceAccount: "deployer",
+	}, &userToken{user: s.user})
+	c.Assert(err, check.IsNil)
+	c.Assert(token.ServiceAccount, check.Equals, "deployer")
+	t, err := servicemanager.TeamToken.Authenticate(context.TODO(), "bearer "+token.Token)
+	c.Assert(err, check.IsNil)
+	c.Assert(t.(authTypes.ServiceAccountToken).GetServiceAccountName(), check.Equals, "deployer")
+	u, err := t.User()
+	c.Assert(err, check.IsNil)
+	c.Assert(u.Email, check.Equals, "deployer@tsuru-service-account")
+	c.Assert(IsEmailFromServiceAccount(u.Email), check.Equals, true)
+	perms, err := t.Permissions()
+	c.Assert(err, check.IsNil)
+	c.Assert(perms, check.DeepEquals, []permission.Permission{
+		{Scheme: permission.PermAppDeploy, Context: permission.Context(permTypes.CtxApp, "myapp")},
+	})
+	err = servicemanager.TeamToken.AddRole(context.TODO(), "t1", "app-deployer", "otherapp")
+	c.Assert(err, check.Equals, authTypes.ErrServiceAccountTokenRoles)
+}
+
+func (s *S) Test_TeamTokenService_ServiceAccount_Invalid(c *check.C) {
+	_, err := servicemanager.TeamToken.Create(context.TODO(), authTypes.TeamTokenCreateArgs{
+		Team:           s.team.Name,
+		ServiceAccount: "deployer",
+	}, &userToken{user: s.user})
+	c.Assert(err, check.Equals, authTypes.ErrServiceAccountNotFound)
+	u := authTypes.User(*s.user)
+	err = servicemanager.Team.Create(context.TODO(), "otherteam", nil, &u)
+	c.Assert(err, check.IsNil)
+	s.createServiceAccount(c, "deployer")
+	_, err = servicemanager.TeamToken.Create(context.TODO(), authTypes.TeamTokenCreateArgs{
+		Team:           "otherteam",
+		ServiceAccount: "deployer",
+	}, &userToken{user: s.user})
+	c.Assert(err, check.Equals, authTypes.ErrServiceAccountTeamMismatch)
+}
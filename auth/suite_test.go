@@ -42,6 +42,8 @@ func (s *S) SetUpSuite(c *check.C) {
 	var err error
 	servicemanager.TeamToken, err = TeamTokenService()
 	c.Assert(err, check.IsNil)
+	servicemanager.ServiceAccount, err = ServiceAccountService()
+	c.Assert(err, check.IsNil)
 	servicemanager.Team, err = TeamService()
 	c.Assert(err, check.IsNil)
 	servicemanager.AuthGroup, err = GroupService()
@@ -14,6 +14,7 @@ import (
 	"github.com/tsuru/config"
 	"github.com/tsuru/tsuru/db"
 	"github.com/tsuru/tsuru/log"
+	"github.com/tsuru/tsuru/servicemanager"
 	"github.com/tsuru/tsuru/storage"
 	authTypes "github.com/tsuru/tsuru/types/auth"
 	permTypes "github.com/tsuru/tsuru/types/permission"
@@ -48,10 +49,12 @@ func (t *teamService) Create(ctx context.Context, name string, tags []string, us
 		return err
 	}
 	team := authTypes.Team{
-		Name:         strings.TrimSpace(name),
-		CreatingUser: user.Email,
-		Tags:         processTags(tags),
-		Quota:        q,
+		Name:           strings.TrimSpace(name),
+		CreatingUser:   user.Email,
+		Tags:           processTags(tags),
+		Quota:          q,
+		JobUnitsQuota:  quota.UnlimitedQuota,
+		JobMemoryQuota: quota.UnlimitedQuota,
 	}
 	if err = t.validate(team); err != nil {
 		return err
@@ -77,6 +80,50 @@ func (t *teamService) Update(ctx context.Context, name string, tags []string) er
 	return t.storage.Update(ctx, *team)
 }
 
+func (t *teamService) SetAllowedCIDRs(ctx context.Context, name string, cidrs []string) error {
+	cidrs, err := ParseAllowedCIDRs(cidrs)
+	if err != nil {
+		return err
+	}
+	team, err := t.storage.FindByName(ctx, name)
+	if err != nil {
+		return err
+	}
+	team.AllowedCIDRs = cidrs
+	return t.storage.Update(ctx, *team)
+}
+
+func (t *teamService) SetParent(ctx context.Context, name, parent string) error {
+	parent = strings.TrimSpace(parent)
+	if parent == name {
+		return authTypes.ErrTeamParentCycle
+	}
+	team, err := t.storage.FindByName(ctx, name)
+	if err != nil {
+		return err
+	}
+	if parent != "" {
+		_, err = t.storage.FindByName(ctx, parent)
+		if err == authTypes.ErrTeamNotFound {
+			return authTypes.ErrTeamParentNotFound
+		}
+		if err != nil {
+			return err
+		}
+		ancestors, err := t.FindAncestors(ctx, parent)
+		if err != nil {
+			return err
+		}
+		for _, ancestor := range ancestors {
+			if ancestor.Name == name {
+				return authTypes.ErrTeamParentCycle
+			}
+		}
+	}
+	team.Parent = parent
+	return t.storage.Update(ctx, *team)
+}
+
 func (t *teamService) List(ctx context.Context) ([]authTypes.Team, error) {
 	return t.storage.FindAll(ctx)
 }
@@ -89,6 +136,59 @@ func (t *teamService) FindByNames(ctx context.Context, names []string) ([]authTy
 	return t.storage.FindByNames(ctx, names)
 }
 
+// FindAncestors returns the teams the named team is nested in, starting from
+// its parent.
+func (t *teamService) FindAncestors(ctx context.Context, name string) ([]authTypes.Team, error) {
+	team, err := t.storage.FindByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	var ancestors []authTypes.Team
+	visited := map[string]bool{team.Name: true}
+	for team.Parent != "" && !visited[team.Parent] {
+		visited[team.Parent] = true
+		team, err = t.storage.FindByName(ctx, team.Parent)
+		if err == authTypes.ErrTeamNotFound {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		ancestors = append(ancestors, *team)
+	}
+	return ancestors, nil
+}
+
+// FindDescendants returns every team nested, directly or not, in one of the
+// named teams.
+func (t *teamService) FindDescendants(ctx context.Context, names ...string) ([]authTypes.Team, error) {
+	var descendants []authTypes.Team
+	found := make(map[string]bool)
+	traversed := make(map[string]bool, len(names))
+	for _, name := range names {
+		traversed[name] = true
+	}
+	for len(names) > 0 {
+		children, err := t.storage.FindByParents(ctx, names)
+		if err != nil {
+			return nil, err
+		}
+		names = nil
+		for _, child := range children {
+			if found[child.Name] {
+				continue
+			}
+			found[child.Name] = true
+			descendants = append(descendants, child)
+			if !traversed[child.Name] {
+				traversed[child.Name] = true
+				names = append(names, child.Name)
+			}
+		}
+	}
+	return descendants, nil
+}
+
 func (t *teamService) Remove(ctx context.Context, teamName string) error {
 	conn, err := db.Conn()
 	if err != nil {
@@ -111,9 +211,38 @@ func (t *teamService) Remove(ctx context.Context, teamName string) error {
 	if len(serviceInstances) > 0 {
 		return &authTypes.ErrTeamStillUsed{ServiceInstances: serviceInstances}
 	}
+	children, err := t.storage.FindByParents(ctx, []string{teamName})
+	if err != nil {
+		return err
+	}
+	if len(children) > 0 {
+		childNames := make([]string, len(children))
+		for i, child := range children {
+			childNames[i] = child.Name
+		}
+		return &authTypes.ErrTeamStillUsed{Teams: childNames}
+	}
 	return t.storage.Delete(ctx, authTypes.Team{Name: teamName})
 }
 
+// RenameParentTeam moves the teams nested in oldName to newName.
+func RenameParentTeam(ctx context.Context, oldName, newName string) error {
+	descendants, err := servicemanager.Team.FindDescendants(ctx, oldName)
+	if err != nil {
+		return err
+	}
+	for _, team := range descendants {
+		if team.Parent != oldName {
+			continue
+		}
+		err = servicemanager.Team.SetParent(ctx, team.Name, newName)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (t *teamService) validate(team authTypes.Team) error {
 	if !teamNameRegexp.MatchString(team.Name) {
 		return authTypes.ErrInvalidTeamName
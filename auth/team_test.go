@@ -8,7 +8,9 @@ import (
 	"context"
 
 	"github.com/globalsign/mgo/bson"
+	tsuruErrors "github.com/tsuru/tsuru/errors"
 	authTypes "github.com/tsuru/tsuru/types/auth"
+	"github.com/tsuru/tsuru/types/quota"
 	check "gopkg.in/check.v1"
 )
 
@@ -22,6 +24,8 @@ func (s *S) TestTeamServiceCreate(c *check.C) {
 				c.Assert(t.Name, check.Equals, teamName)
 				c.Assert(t.CreatingUser, check.DeepEquals, one.Email)
 				c.Assert(t.Tags, check.DeepEquals, []string{"tag1", "tag2"})
+				c.Assert(t.JobUnitsQuota, check.DeepEquals, quota.UnlimitedQuota)
+				c.Assert(t.JobMemoryQuota, check.DeepEquals, quota.UnlimitedQuota)
 				return nil
 			},
 		},
@@ -52,6 +56,29 @@ func (s *S) TestTeamServiceUpdate(c *check.C) {
 	c.Assert(err, check.IsNil)
 }
 
+func (s *S) TestTeamServiceSetAllowedCIDRs(c *check.C) {
+	var updated bool
+	ts := &teamService{
+		storage: &authTypes.MockTeamStorage{
+			OnFindByName: func(name string) (*authTypes.Team, error) {
+				return &authTypes.Team{Name: name, Tags: []string{"tag1"}}, nil
+			},
+			OnUpdate: func(t authTypes.Team) error {
+				updated = true
+				c.Assert(t.Name, check.Equals, "pos")
+				c.Assert(t.Tags, check.DeepEquals, []string{"tag1"})
+				c.Assert(t.AllowedCIDRs, check.DeepEquals, []string{"10.0.0.0/8", "192.168.0.1/32"})
+				return nil
+			},
+		},
+	}
+	err := ts.SetAllowedCIDRs(context.TODO(), "pos", []string{"10.0.0.0/8", "192.168.0.1"})
+	c.Assert(err, check.IsNil)
+	c.Assert(updated, check.Equals, true)
+	err = ts.SetAllowedCIDRs(context.TODO(), "pos", []string{"10.0.0.0/64"})
+	c.Assert(err, check.FitsTypeOf, &tsuruErrors.ValidationError{})
+}
+
 func (s *S) TestTeamServiceCreateDuplicate(c *check.C) {
 	teamName := "pos"
 	u := authTypes.User{Email: "king@pos.com"}
@@ -126,6 +153,9 @@ func (s *S) TestTeamServiceRemove(c *check.C) {
 	teamName := "atreides"
 	ts := &teamService{
 		storage: &authTypes.MockTeamStorage{
+			OnFindByParents: func(parents []string) ([]authTypes.Team, error) {
+				return nil, nil
+			},
 			OnDelete: func(t authTypes.Team) error {
 				c.Assert(t.Name, check.Equals, teamName)
 				return nil
@@ -171,6 +201,119 @@ func (s *S) TestTeamServiceRemoveWithServiceInstances(c *check.C) {
 	c.Assert(err, check.ErrorMatches, "Service instances: vladimir")
 }
 
+func (s *S) TestTeamServiceRemoveWithChildren(c *check.C) {
+	ts := &teamService{
+		storage: &authTypes.MockTeamStorage{
+			OnFindByParents: func(parents []string) ([]authTypes.Team, error) {
+				c.Assert(parents, check.DeepEquals, []string{"corrino"})
+				return []authTypes.Team{{Name: "sardaukar", Parent: "corrino"}}, nil
+			},
+			OnDelete: func(t authTypes.Team) error {
+				c.Fail()
+				return nil
+			},
+		},
+	}
+	err := ts.Remove(context.TODO(), "corrino")
+	c.Assert(err, check.ErrorMatches, "Teams: sardaukar")
+}
+
+func teamHierarchyStorage(teams ...authTypes.Team) *authTypes.MockTeamStorage {
+	byName := make(map[string]authTypes.Team)
+	for _, t := range teams {
+		byName[t.Name] = t
+	}
+	return &authTypes.MockTeamStorage{
+		OnFindByName: func(name string) (*authTypes.Team, error) {
+			t, ok := byName[name]
+			if !ok {
+				return nil, authTypes.ErrTeamNotFound
+			}
+			return &t, nil
+		},
+		OnFindByParents: func(parents []string) ([]authTypes.Team, error) {
+			var children []authTypes.Team
+			for _, t := range teams {
+				for _, p := range parents {
+					if t.Parent == p {
+						children = append(children, byName[t.Name])
+					}
+				}
+			}
+			return children, nil
+		},
+		OnUpdate: func(t authTypes.Team) error {
+			byName[t.Name] = t
+			for i := range teams {
+				if teams[i].Name == t.Name {
+					teams[i] = t
+				}
+			}
+			return nil
+		},
+	}
+}
+
+func (s *S) TestTeamServiceSetParent(c *check.C) {
+	ts := &teamService{storage: teamHierarchyStorage(
+		authTypes.Team{Name: "org"},
+		authTypes.Team{Name: "team1"},
+	)}
+	err := ts.SetParent(context.TODO(), "team1", " org ")
+	c.Assert(err, check.IsNil)
+	team, err := ts.FindByName(context.TODO(), "team1")
+	c.Assert(err, check.IsNil)
+	c.Assert(team.Parent, check.Equals, "org")
+	err = ts.SetParent(context.TODO(), "team1", "")
+	c.Assert(err, check.IsNil)
+	team, err = ts.FindByName(context.TODO(), "team1")
+	c.Assert(err, check.IsNil)
+	c.Assert(team.Parent, check.Equals, "")
+}
+
+func (s *S) TestTeamServiceSetParentValidation(c *check.C) {
+	ts := &teamService{storage: teamHierarchyStorage(
+		authTypes.Team{Name: "org"},
+		authTypes.Team{Name: "team1", Parent: "org"},
+		authTypes.Team{Name: "team2", Parent: "team1"},
+	)}
+	err := ts.SetParent(context.TODO(), "org", "org")
+	c.Assert(err, check.Equals, authTypes.ErrTeamParentCycle)
+	err = ts.SetParent(context.TODO(), "org", "team2")
+	c.Assert(err, check.Equals, authTypes.ErrTeamParentCycle)
+	err = ts.SetParent(context.TODO(), "team1", "unknown")
+	c.Assert(err, check.Equals, authTypes.ErrTeamParentNotFound)
+	err = ts.SetParent(context.TODO(), "unknown", "org")
+	c.Assert(err, check.Equals, authTypes.ErrTeamNotFound)
+}
+
+func (s *S) TestTeamServiceFindAncestorsAndDescendants(c *check.C) {
+	ts := &teamService{storage: teamHierarchyStorage(
+		authTypes.Team{Name: "org"},
+		authTypes.Team{Name: "team1", Parent: "org"},
+		authTypes.Team{Name: "team2", Parent: "team1"},
+		authTypes.Team{Name: "other"},
+	)}
+	ancestors, err := ts.FindAncestors(context.TODO(), "team2")
+	c.Assert(err, check.IsNil)
+	c.Assert(ancestors, check.DeepEquals, []authTypes.Team{
+		{Name: "team1", Parent: "org"},
+		{Name: "org"},
+	})
+	descendants, err := ts.FindDescendants(context.TODO(), "org", "other")
+	c.Assert(err, check.IsNil)
+	c.Assert(descendants, check.DeepEquals, []authTypes.Team{
+		{Name: "team1", Parent: "org"},
+		{Name: "team2", Parent: "team1"},
+	})
+	descendants, err = ts.FindDescendants(context.TODO(), "team1", "org")
+	c.Assert(err, check.IsNil)
+	c.Assert(descendants, check.DeepEquals, []authTypes.Team{
+		{Name: "team1", Parent: "org"},
+		{Name: "team2", Parent: "team1"},
+	})
+}
+
 func (s *S) TestTeamServiceList(c *check.C) {
 	teams := []authTypes.Team{
 		{Name: "corrino"},
@@ -12,6 +12,8 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	"github.com/tsuru/config"
+	tsuruErrors "github.com/tsuru/tsuru/errors"
 	"github.com/tsuru/tsuru/permission"
 	"github.com/tsuru/tsuru/servicemanager"
 	"github.com/tsuru/tsuru/storage"
@@ -27,15 +29,24 @@ import (
 // should not be able to register using it.
 const TsuruTokenEmailDomain = "tsuru-team-token"
 
+const defaultTeamTokenRotationGracePeriod = time.Hour
+
 func IsEmailFromTeamToken(email string) bool {
 	return strings.HasSuffix(email, fmt.Sprintf("@%s", TsuruTokenEmailDomain))
 }
 
-type teamToken authTypes.TeamToken
+// teamToken keeps the team hierarchy used to expand the token permissions
+// for the request it authenticates.
+type teamToken struct {
+	authTypes.TeamToken
+	teams TeamHierarchy
+}
 
 var (
-	_ authTypes.Token      = &teamToken{}
-	_ authTypes.NamedToken = &teamToken{}
+	_ authTypes.Token               = &teamToken{}
+	_ authTypes.NamedToken          = &teamToken{}
+	_ authTypes.ServiceAccountToken = &teamToken{}
+	_ permission.ScopedToken        = &teamToken{}
 )
 
 func (t *teamToken) GetValue() string {
@@ -43,8 +54,12 @@ func (t *teamToken) GetValue() string {
 }
 
 func (t *teamToken) User() (*authTypes.User, error) {
+	email := fmt.Sprintf("%s@%s", t.TokenID, TsuruTokenEmailDomain)
+	if t.ServiceAccount != "" {
+		email = serviceAccountEmail(t.ServiceAccount)
+	}
 	return &authTypes.User{
-		Email:     fmt.Sprintf("%s@%s", t.TokenID, TsuruTokenEmailDomain),
+		Email:     email,
 		Quota:     quota.UnlimitedQuota,
 		Roles:     t.Roles,
 		FromToken: true,
@@ -63,16 +78,33 @@ func (t *teamToken) GetTokenName() string {
 	return t.TokenID
 }
 
+func (t *teamToken) GetServiceAccountName() string {
+	return t.ServiceAccount
+}
+
 func (t *teamToken) GetAppName() string {
 	return ""
 }
 
 func (t *teamToken) Permissions() ([]permission.Permission, error) {
-	return expandRolePermissions(t.Roles)
+	perms, err := t.UnscopedPermissions()
+	if err != nil {
+		return nil, err
+	}
+	return permission.ScopePermissions(t, perms), nil
+}
+
+func (t *teamToken) UnscopedPermissions() ([]permission.Permission, error) {
+	return expandRolePermissions(context.TODO(), t.Roles, &t.teams)
+}
+
+func (t *teamToken) PermissionScopes() []permission.Permission {
+	return scopePermissions(t.Scopes)
 }
 
 type teamTokenService struct {
-	storage authTypes.TeamTokenStorage
+	storage               authTypes.TeamTokenStorage
+	serviceAccountStorage authTypes.ServiceAccountStorage
 }
 
 func TeamTokenService() (authTypes.TeamTokenService, error) {
@@ -84,7 +116,8 @@ func TeamTokenService() (authTypes.TeamTokenService, error) {
 		}
 	}
 	return &teamTokenService{
-		storage: dbDriver.TeamTokenStorage,
+		storage:               dbDriver.TeamTokenStorage,
+		serviceAccountStorage: dbDriver.ServiceAccountStorage,
 	}, nil
 }
 
@@ -104,11 +137,23 @@ func (s *teamTokenService) Authenticate(ctx context.Context, header string) (aut
 	if !storedToken.ExpiresAt.IsZero() && storedToken.ExpiresAt.Before(now) {
 		return nil, authTypes.ErrTeamTokenExpired
 	}
+	if storedToken.Token != tokenStr && storedToken.PreviousTokenExpiresAt.Before(now) {
+		return nil, ErrInvalidToken
+	}
+	if storedToken.ServiceAccount != "" {
+		storedToken.Roles, err = s.tokenRoles(ctx, storedToken)
+		if err == authTypes.ErrServiceAccountNotFound {
+			err = ErrInvalidToken
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
 	err = s.storage.UpdateLastAccess(ctx, tokenStr)
 	if err != nil {
 		return nil, err
 	}
-	token := teamToken(*storedToken)
+	token := teamToken{TeamToken: *storedToken}
 	return &token, nil
 }
 
@@ -117,7 +162,11 @@ func (s *teamTokenService) Delete(ctx context.Context, tokenID string) error {
 	if err != nil {
 		return err
 	}
-	tt := teamToken(*token)
+	if token.ServiceAccount != "" {
+		// apps created with the token are owned by its service account
+		return s.storage.Delete(ctx, tokenID)
+	}
+	tt := teamToken{TeamToken: *token}
 	u, err := tt.User()
 	if err != nil {
 		return err
@@ -141,7 +190,24 @@ func (s *teamTokenService) Create(ctx context.Context, args authTypes.TeamTokenC
 	if err != nil {
 		return authTypes.TeamToken{}, err
 	}
+	if args.ServiceAccount != "" {
+		sa, err := s.serviceAccountStorage.FindByName(ctx, args.ServiceAccount)
+		if err != nil {
+			return authTypes.TeamToken{}, err
+		}
+		if sa.Team != args.Team {
+			return authTypes.TeamToken{}, authTypes.ErrServiceAccountTeamMismatch
+		}
+	}
+	scopes, err := ParseTeamTokenScopes(args.Scopes)
+	if err != nil {
+		return authTypes.TeamToken{}, err
+	}
 	now := time.Now().UTC()
+	expiresAt, err := teamTokenExpiration(now, args.ExpiresIn)
+	if err != nil {
+		return authTypes.TeamToken{}, err
+	}
 	resultToken := authTypes.TeamToken{
 		Token:        generateToken(args.Team, crypto.SHA256),
 		TokenID:      args.TokenID,
@@ -149,9 +215,10 @@ func (s *teamTokenService) Create(ctx context.Context, args authTypes.TeamTokenC
 		Team:         args.Team,
 		CreatedAt:    now,
 		CreatorEmail: u.Email,
-	}
-	if args.ExpiresIn != 0 {
-		resultToken.ExpiresAt = now.Add(time.Duration(args.ExpiresIn) * time.Second)
+		Scopes:       scopes,
+		ExpiresAt:    expiresAt,
+
+		ServiceAccount: args.ServiceAccount,
 	}
 	if resultToken.TokenID == "" {
 		resultToken.TokenID = fmt.Sprintf("%s-%s", resultToken.Team, resultToken.Token[:5])
@@ -163,6 +230,19 @@ func (s *teamTokenService) Create(ctx context.Context, args authTypes.TeamTokenC
 	return resultToken, err
 }
 
+func (s *teamTokenService) SetAllowedCIDRs(ctx context.Context, tokenID string, cidrs []string) error {
+	cidrs, err := ParseAllowedCIDRs(cidrs)
+	if err != nil {
+		return err
+	}
+	token, err := s.storage.FindByTokenID(ctx, tokenID)
+	if err != nil {
+		return err
+	}
+	token.AllowedCIDRs = cidrs
+	return s.storage.Update(ctx, *token)
+}
+
 func (s *teamTokenService) AddRole(ctx context.Context, tokenID string, roleName, contextValue string) error {
 	_, err := permission.FindRole(roleName)
 	if err != nil {
@@ -172,6 +252,9 @@ func (s *teamTokenService) AddRole(ctx context.Context, tokenID string, roleName
 	if err != nil {
 		return err
 	}
+	if token.ServiceAccount != "" {
+		return authTypes.ErrServiceAccountTokenRoles
+	}
 	token.Roles = append(token.Roles, authTypes.RoleInstance{
 		Name: roleName, ContextValue: contextValue,
 	})
@@ -201,6 +284,40 @@ func (s *teamTokenService) FindByTokenID(ctx context.Context, tokenID string) (a
 	return *t, nil
 }
 
+func teamTokenMaxLifetime() time.Duration {
+	maxLifetime, _ := config.GetDuration("auth:team-token:max-lifetime")
+	return maxLifetime
+}
+
+func teamTokenRotationGracePeriod() time.Duration {
+	gracePeriod, err := config.GetDuration("auth:team-token:rotation-grace-period")
+	if err != nil {
+		return defaultTeamTokenRotationGracePeriod
+	}
+	return gracePeriod
+}
+
+// teamTokenExpiration returns when a team token issued at now expires, given
+// its lifetime in seconds. Tokens default to the maximum lifetime, when one is
+// configured, and can't outlive it.
+func teamTokenExpiration(now time.Time, expiresIn int) (time.Time, error) {
+	lifetime := time.Duration(expiresIn) * time.Second
+	if maxLifetime := teamTokenMaxLifetime(); maxLifetime > 0 {
+		if lifetime > maxLifetime {
+			return time.Time{}, &tsuruErrors.ValidationError{
+				Message: fmt.Sprintf("team token lifetime must not exceed %s", maxLifetime),
+			}
+		}
+		if lifetime == 0 {
+			lifetime = maxLifetime
+		}
+	}
+	if lifetime == 0 {
+		return time.Time{}, nil
+	}
+	return now.Add(lifetime), nil
+}
+
 func getTokenTeams(t Token) []string {
 	var teams []string
 	contexts := permission.ContextsForPermission(t, permission.PermTeamTokenRead, permTypes.CtxGlobal, permTypes.CtxTeam)
@@ -225,13 +342,29 @@ func (s *teamTokenService) Update(ctx context.Context, args authTypes.TeamTokenU
 		token.Description = args.Description
 	}
 	if args.ExpiresIn > 0 {
-		token.ExpiresAt = time.Now().UTC().Add(time.Duration(args.ExpiresIn) * time.Second)
+		token.ExpiresAt, err = teamTokenExpiration(time.Now().UTC(), args.ExpiresIn)
+		if err != nil {
+			return authTypes.TeamToken{}, err
+		}
+		token.ExpirationWarnedAt = time.Time{}
 	} else if args.ExpiresIn < 0 {
+		if teamTokenMaxLifetime() > 0 {
+			return authTypes.TeamToken{}, &tsuruErrors.ValidationError{Message: "team tokens must expire"}
+		}
 		token.ExpiresAt = time.Time{}
+		token.ExpirationWarnedAt = time.Time{}
 	}
 	if args.Regenerate {
 		token.Token = generateToken(token.Team, crypto.SHA256)
 	}
+	if args.ClearScopes {
+		token.Scopes = nil
+	} else if len(args.Scopes) > 0 {
+		token.Scopes, err = ParseTeamTokenScopes(args.Scopes)
+		if err != nil {
+			return authTypes.TeamToken{}, err
+		}
+	}
 	err = s.storage.Update(ctx, *token)
 	if err != nil {
 		return authTypes.TeamToken{}, err
@@ -240,7 +373,7 @@ func (s *teamTokenService) Update(ctx context.Context, args authTypes.TeamTokenU
 	if err != nil {
 		return authTypes.TeamToken{}, err
 	}
-	canView, err := canViewTokenValue(userPerms, token)
+	canView, err := s.canViewTokenValue(ctx, userPerms, token)
 	if err != nil {
 		return authTypes.TeamToken{}, err
 	}
@@ -250,6 +383,81 @@ func (s *teamTokenService) Update(ctx context.Context, args authTypes.TeamTokenU
 	return *token, nil
 }
 
+// Rotate issues a new secret for a team token. The previous secret remains
+// valid during the grace period, so that clients can be updated without
+// downtime.
+func (s *teamTokenService) Rotate(ctx context.Context, args authTypes.TeamTokenRotateArgs, t authTypes.Token) (authTypes.TeamToken, error) {
+	token, err := s.storage.FindByTokenID(ctx, args.TokenID)
+	if err != nil {
+		return authTypes.TeamToken{}, err
+	}
+	now := time.Now().UTC()
+	if args.ExpiresIn < 0 {
+		return authTypes.TeamToken{}, &tsuruErrors.ValidationError{Message: "expires_in must not be negative"}
+	}
+	if args.ExpiresIn > 0 || teamTokenMaxLifetime() > 0 {
+		token.ExpiresAt, err = teamTokenExpiration(now, args.ExpiresIn)
+		if err != nil {
+			return authTypes.TeamToken{}, err
+		}
+		token.ExpirationWarnedAt = time.Time{}
+	}
+	gracePeriod := time.Duration(args.GracePeriod) * time.Second
+	if args.GracePeriod == 0 {
+		gracePeriod = teamTokenRotationGracePeriod()
+	}
+	token.PreviousToken = ""
+	token.PreviousTokenExpiresAt = time.Time{}
+	if gracePeriod > 0 {
+		token.PreviousToken = token.Token
+		token.PreviousTokenExpiresAt = now.Add(gracePeriod)
+	}
+	token.Token = generateToken(token.Team, crypto.SHA256)
+	token.RotatedAt = now
+	err = s.storage.Update(ctx, *token)
+	if err != nil {
+		return authTypes.TeamToken{}, err
+	}
+	userPerms, err := t.Permissions()
+	if err != nil {
+		return authTypes.TeamToken{}, err
+	}
+	canView, err := s.canViewTokenValue(ctx, userPerms, token)
+	if err != nil {
+		return authTypes.TeamToken{}, err
+	}
+	if !canView {
+		token.Token = ""
+	}
+	return *token, nil
+}
+
+// FindExpiring returns the team tokens that expire within the given duration
+// and whose owners weren't warned about it yet.
+func (s *teamTokenService) FindExpiring(ctx context.Context, within time.Duration) ([]authTypes.TeamToken, error) {
+	now := time.Now().UTC()
+	tokens, err := s.storage.FindExpiringBefore(ctx, now.Add(within))
+	if err != nil {
+		return nil, err
+	}
+	var expiring []authTypes.TeamToken
+	for _, token := range tokens {
+		if token.ExpiresAt.After(now) && token.ExpirationWarnedAt.IsZero() {
+			expiring = append(expiring, token)
+		}
+	}
+	return expiring, nil
+}
+
+func (s *teamTokenService) SetExpirationWarned(ctx context.Context, tokenID string) error {
+	token, err := s.storage.FindByTokenID(ctx, tokenID)
+	if err != nil {
+		return err
+	}
+	token.ExpirationWarnedAt = time.Now().UTC()
+	return s.storage.Update(ctx, *token)
+}
+
 func (s *teamTokenService) Info(ctx context.Context, tokenID string, t authTypes.Token) (authTypes.TeamToken, error) {
 	token, err := s.storage.FindByTokenID(ctx, tokenID)
 	if err != nil {
@@ -259,7 +467,7 @@ func (s *teamTokenService) Info(ctx context.Context, tokenID string, t authTypes
 	if err != nil {
 		return authTypes.TeamToken{}, err
 	}
-	canView, err := canViewTokenValue(userPerms, token)
+	canView, err := s.canViewTokenValue(ctx, userPerms, token)
 	if err != nil {
 		return authTypes.TeamToken{}, err
 	}
@@ -279,7 +487,7 @@ func (s *teamTokenService) FindByUserToken(ctx context.Context, t authTypes.Toke
 		return nil, err
 	}
 	for i, teamToken := range teamTokens {
-		canView, err := canViewTokenValue(userPerms, &teamToken)
+		canView, err := s.canViewTokenValue(ctx, userPerms, &teamToken)
 		if err != nil {
 			return nil, err
 		}
@@ -304,8 +512,28 @@ func canUseRole(userPerms []permission.Permission, roleName, contextValue string
 	return true, nil
 }
 
-func canViewTokenValue(userPerms []permission.Permission, teamToken *authTypes.TeamToken) (bool, error) {
-	for _, roleInstance := range teamToken.Roles {
+// tokenRoles returns the roles of a team token, which are the roles of its
+// service account, if any.
+func (s *teamTokenService) tokenRoles(ctx context.Context, teamToken *authTypes.TeamToken) ([]authTypes.RoleInstance, error) {
+	if teamToken.ServiceAccount == "" {
+		return teamToken.Roles, nil
+	}
+	sa, err := s.serviceAccountStorage.FindByName(ctx, teamToken.ServiceAccount)
+	if err != nil {
+		return nil, err
+	}
+	return sa.Roles, nil
+}
+
+func (s *teamTokenService) canViewTokenValue(ctx context.Context, userPerms []permission.Permission, teamToken *authTypes.TeamToken) (bool, error) {
+	roles, err := s.tokenRoles(ctx, teamToken)
+	if err == authTypes.ErrServiceAccountNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	for _, roleInstance := range roles {
 		canUse, err := canUseRole(userPerms, roleInstance.Name, roleInstance.ContextValue)
 		if err != nil {
 			return false, err
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	"github.com/tsuru/config"
 	"github.com/tsuru/tsuru/permission"
 	"github.com/tsuru/tsuru/servicemanager"
 	appTypes "github.com/tsuru/tsuru/types/app"
@@ -322,6 +323,71 @@ func (s *S) Test_TeamTokenService_Update_Description(c *check.C) {
 	c.Assert(t, check.DeepEquals, expected)
 }
 
+func (s *S) Test_TeamTokenService_SetAllowedCIDRs(c *check.C) {
+	_, err := servicemanager.TeamToken.Create(context.TODO(), authTypes.TeamTokenCreateArgs{
+		Team:    s.team.Name,
+		TokenID: "t1",
+	}, &userToken{user: s.user})
+	c.Assert(err, check.IsNil)
+	err = servicemanager.TeamToken.SetAllowedCIDRs(context.TODO(), "t1", []string{"10.0.0.0/8", "192.168.0.1"})
+	c.Assert(err, check.IsNil)
+	t, err := servicemanager.TeamToken.FindByTokenID(context.TODO(), "t1")
+	c.Assert(err, check.IsNil)
+	c.Assert(t.AllowedCIDRs, check.DeepEquals, []string{"10.0.0.0/8", "192.168.0.1/32"})
+	err = servicemanager.TeamToken.SetAllowedCIDRs(context.TODO(), "t1", nil)
+	c.Assert(err, check.IsNil)
+	t, err = servicemanager.TeamToken.FindByTokenID(context.TODO(), "t1")
+	c.Assert(err, check.IsNil)
+	c.Assert(t.AllowedCIDRs, check.IsNil)
+	err = servicemanager.TeamToken.SetAllowedCIDRs(context.TODO(), "t2", nil)
+	c.Assert(err, check.Equals, authTypes.ErrTeamTokenNotFound)
+}
+
+func (s *S) Test_TeamTokenService_Scopes(c *check.C) {
+	token, err := servicemanager.TeamToken.Create(context.TODO(), authTypes.TeamTokenCreateArgs{
+		Team:    s.team.Name,
+		TokenID: "t1",
+		Scopes:  []string{"app.deploy:app:myapp", "app.read"},
+	}, &userToken{user: s.user})
+	c.Assert(err, check.IsNil)
+	expectedScopes := []authTypes.TeamTokenScope{
+		{Permission: "app.deploy", ContextType: "app", ContextValue: "myapp"},
+		{Permission: "app.read", ContextType: "global"},
+	}
+	c.Assert(token.Scopes, check.DeepEquals, expectedScopes)
+	authToken, err := servicemanager.TeamToken.Authenticate(context.TODO(), "bearer "+token.Token)
+	c.Assert(err, check.IsNil)
+	scoped, ok := authToken.(permission.ScopedToken)
+	c.Assert(ok, check.Equals, true)
+	c.Assert(scoped.PermissionScopes(), check.DeepEquals, []permission.Permission{
+		{Scheme: permission.PermAppDeploy, Context: permission.Context(permTypes.CtxApp, "myapp")},
+		{Scheme: permission.PermAppRead, Context: permission.Context(permTypes.CtxGlobal, "")},
+	})
+	updated, err := servicemanager.TeamToken.Update(context.TODO(), authTypes.TeamTokenUpdateArgs{
+		TokenID: "t1",
+		Scopes:  []string{"app.deploy:team:" + s.team.Name},
+	}, &userToken{user: s.user})
+	c.Assert(err, check.IsNil)
+	c.Assert(updated.Scopes, check.DeepEquals, []authTypes.TeamTokenScope{
+		{Permission: "app.deploy", ContextType: "team", ContextValue: s.team.Name},
+	})
+	updated, err = servicemanager.TeamToken.Update(context.TODO(), authTypes.TeamTokenUpdateArgs{
+		TokenID:     "t1",
+		ClearScopes: true,
+	}, &userToken{user: s.user})
+	c.Assert(err, check.IsNil)
+	c.Assert(updated.Scopes, check.IsNil)
+	authToken, err = servicemanager.TeamToken.Authenticate(context.TODO(), "bearer "+token.Token)
+	c.Assert(err, check.IsNil)
+	c.Assert(authToken.(permission.ScopedToken).PermissionScopes(), check.IsNil)
+	_, err = servicemanager.TeamToken.Create(context.TODO(), authTypes.TeamTokenCreateArgs{
+		Team:    s.team.Name,
+		TokenID: "t2",
+		Scopes:  []string{"app.invalid:app:myapp"},
+	}, &userToken{user: s.user})
+	c.Assert(err, check.ErrorMatches, `invalid scope "app.invalid:app:myapp": unknown permission "app.invalid"`)
+}
+
 func (s *S) Test_TeamTokenService_Update_Regenerate(c *check.C) {
 	_, err := servicemanager.TeamToken.Create(context.TODO(), authTypes.TeamTokenCreateArgs{
 		Team:        s.team.Name,
@@ -386,6 +452,97 @@ func (s *S) Test_TeamTokenService_Update_Expires(c *check.C) {
 	c.Assert(updatedToken.ExpiresAt.IsZero(), check.Equals, true)
 }
 
+func (s *S) Test_TeamTokenService_MaxLifetime(c *check.C) {
+	config.Set("auth:team-token:max-lifetime", "2h")
+	defer config.Unset("auth:team-token:max-lifetime")
+	token, err := servicemanager.TeamToken.Create(context.TODO(), authTypes.TeamTokenCreateArgs{Team: s.team.Name, TokenID: "t1"}, &userToken{user: s.user})
+	c.Assert(err, check.IsNil)
+	c.Assert(token.ExpiresAt.Sub(token.CreatedAt), check.Equals, 2*time.Hour)
+	_, err = servicemanager.TeamToken.Create(context.TODO(), authTypes.TeamTokenCreateArgs{Team: s.team.Name, TokenID: "t2", ExpiresIn: 3 * 60 * 60}, &userToken{user: s.user})
+	c.Assert(err, check.ErrorMatches, "team token lifetime must not exceed 2h0m0s")
+	updatedToken, err := servicemanager.TeamToken.Update(context.TODO(), authTypes.TeamTokenUpdateArgs{TokenID: "t1", ExpiresIn: 60 * 60}, &userToken{user: s.user})
+	c.Assert(err, check.IsNil)
+	c.Assert(updatedToken.ExpiresAt.Before(token.ExpiresAt), check.Equals, true)
+	_, err = servicemanager.TeamToken.Update(context.TODO(), authTypes.TeamTokenUpdateArgs{TokenID: "t1", ExpiresIn: 3 * 60 * 60}, &userToken{user: s.user})
+	c.Assert(err, check.ErrorMatches, "team token lifetime must not exceed 2h0m0s")
+	_, err = servicemanager.TeamToken.Update(context.TODO(), authTypes.TeamTokenUpdateArgs{TokenID: "t1", ExpiresIn: -1}, &userToken{user: s.user})
+	c.Assert(err, check.ErrorMatches, "team tokens must expire")
+}
+
+func (s *S) Test_TeamTokenService_Rotate(c *check.C) {
+	token, err := servicemanager.TeamToken.Create(context.TODO(), authTypes.TeamTokenCreateArgs{Team: s.team.Name, TokenID: "t1"}, &userToken{user: s.user})
+	c.Assert(err, check.IsNil)
+	rotated, err := servicemanager.TeamToken.Rotate(context.TODO(), authTypes.TeamTokenRotateArgs{TokenID: "t1"}, &userToken{user: s.user})
+	c.Assert(err, check.IsNil)
+	c.Assert(rotated.Token, check.Not(check.Equals), token.Token)
+	c.Assert(rotated.ExpiresAt.IsZero(), check.Equals, true)
+	c.Assert(rotated.RotatedAt.IsZero(), check.Equals, false)
+	c.Assert(rotated.PreviousTokenExpiresAt.Sub(rotated.RotatedAt), check.Equals, time.Hour)
+	t, err := servicemanager.TeamToken.Authenticate(context.TODO(), "bearer "+rotated.Token)
+	c.Assert(err, check.IsNil)
+	c.Assert(t.GetValue(), check.Equals, rotated.Token)
+	t, err = servicemanager.TeamToken.Authenticate(context.TODO(), "bearer "+token.Token)
+	c.Assert(err, check.IsNil)
+	c.Assert(t.GetValue(), check.Equals, rotated.Token)
+	dbToken, err := servicemanager.TeamToken.FindByTokenID(context.TODO(), "t1")
+	c.Assert(err, check.IsNil)
+	c.Assert(dbToken.PreviousToken, check.Equals, token.Token)
+
+	_, err = servicemanager.TeamToken.Rotate(context.TODO(), authTypes.TeamTokenRotateArgs{TokenID: "t1", GracePeriod: -1, ExpiresIn: 60}, &userToken{user: s.user})
+	c.Assert(err, check.IsNil)
+	_, err = servicemanager.TeamToken.Authenticate(context.TODO(), "bearer "+rotated.Token)
+	c.Assert(err, check.Equals, ErrInvalidToken)
+	dbToken, err = servicemanager.TeamToken.FindByTokenID(context.TODO(), "t1")
+	c.Assert(err, check.IsNil)
+	c.Assert(dbToken.PreviousToken, check.Equals, "")
+	c.Assert(dbToken.ExpiresAt.Sub(dbToken.RotatedAt), check.Equals, time.Minute)
+}
+
+func (s *S) Test_TeamTokenService_Rotate_GracePeriodExpired(c *check.C) {
+	token, err := servicemanager.TeamToken.Create(context.TODO(), authTypes.TeamTokenCreateArgs{Team: s.team.Name, TokenID: "t1"}, &userToken{user: s.user})
+	c.Assert(err, check.IsNil)
+	_, err = servicemanager.TeamToken.Rotate(context.TODO(), authTypes.TeamTokenRotateArgs{TokenID: "t1", GracePeriod: 60}, &userToken{user: s.user})
+	c.Assert(err, check.IsNil)
+	dbToken, err := servicemanager.TeamToken.FindByTokenID(context.TODO(), "t1")
+	c.Assert(err, check.IsNil)
+	dbToken.PreviousTokenExpiresAt = time.Now().Add(-time.Second)
+	err = servicemanager.TeamToken.(*teamTokenService).storage.Update(context.TODO(), dbToken)
+	c.Assert(err, check.IsNil)
+	_, err = servicemanager.TeamToken.Authenticate(context.TODO(), "bearer "+token.Token)
+	c.Assert(err, check.Equals, ErrInvalidToken)
+}
+
+func (s *S) Test_TeamTokenService_Rotate_NotFound(c *check.C) {
+	_, err := servicemanager.TeamToken.Rotate(context.TODO(), authTypes.TeamTokenRotateArgs{TokenID: "t1"}, &userToken{user: s.user})
+	c.Assert(err, check.Equals, authTypes.ErrTeamTokenNotFound)
+}
+
+func (s *S) Test_TeamTokenService_FindExpiring(c *check.C) {
+	_, err := servicemanager.TeamToken.Create(context.TODO(), authTypes.TeamTokenCreateArgs{Team: s.team.Name, TokenID: "t1", ExpiresIn: 60 * 60}, &userToken{user: s.user})
+	c.Assert(err, check.IsNil)
+	_, err = servicemanager.TeamToken.Create(context.TODO(), authTypes.TeamTokenCreateArgs{Team: s.team.Name, TokenID: "t2", ExpiresIn: 3 * 60 * 60}, &userToken{user: s.user})
+	c.Assert(err, check.IsNil)
+	_, err = servicemanager.TeamToken.Create(context.TODO(), authTypes.TeamTokenCreateArgs{Team: s.team.Name, TokenID: "t3", ExpiresIn: -1}, &userToken{user: s.user})
+	c.Assert(err, check.IsNil)
+	_, err = servicemanager.TeamToken.Create(context.TODO(), authTypes.TeamTokenCreateArgs{Team: s.team.Name, TokenID: "t4"}, &userToken{user: s.user})
+	c.Assert(err, check.IsNil)
+	tokens, err := servicemanager.TeamToken.FindExpiring(context.TODO(), 2*time.Hour)
+	c.Assert(err, check.IsNil)
+	c.Assert(tokens, check.HasLen, 1)
+	c.Assert(tokens[0].TokenID, check.Equals, "t1")
+	err = servicemanager.TeamToken.SetExpirationWarned(context.TODO(), "t1")
+	c.Assert(err, check.IsNil)
+	tokens, err = servicemanager.TeamToken.FindExpiring(context.TODO(), 2*time.Hour)
+	c.Assert(err, check.IsNil)
+	c.Assert(tokens, check.HasLen, 0)
+	_, err = servicemanager.TeamToken.Update(context.TODO(), authTypes.TeamTokenUpdateArgs{TokenID: "t1", ExpiresIn: 60}, &userToken{user: s.user})
+	c.Assert(err, check.IsNil)
+	tokens, err = servicemanager.TeamToken.FindExpiring(context.TODO(), 2*time.Hour)
+	c.Assert(err, check.IsNil)
+	c.Assert(tokens, check.HasLen, 1)
+	c.Assert(tokens[0].TokenID, check.Equals, "t1")
+}
+
 func (s *S) Test_TeamToken_Permissions(c *check.C) {
 	r1, err := permission.NewRole("app-deployer", "app", "")
 	c.Assert(err, check.IsNil)
@@ -395,13 +552,13 @@ func (s *S) Test_TeamToken_Permissions(c *check.C) {
 	c.Assert(err, check.IsNil)
 	err = r2.AddPermissions("app.update")
 	c.Assert(err, check.IsNil)
-	token := &teamToken{
+	token := &teamToken{TeamToken: authTypes.TeamToken{
 		Team: s.team.Name,
 		Roles: []authTypes.RoleInstance{
 			{Name: "app-deployer", ContextValue: "myapp"},
 			{Name: "app-updater", ContextValue: "myapp"},
 		},
-	}
+	}}
 	perms, err := token.Permissions()
 	c.Assert(err, check.IsNil)
 	c.Assert(perms, check.HasLen, 3)
@@ -0,0 +1,125 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package teamtoken
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/api/shutdown"
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/log"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/servicemanager"
+	authTypes "github.com/tsuru/tsuru/types/auth"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+)
+
+const (
+	// ExpiringEventKind is the internal kind of the events created when a
+	// team token is about to expire, which may be used to trigger webhooks.
+	ExpiringEventKind = "team.token.expiring"
+
+	expirationRunInterval    = 10 * time.Minute
+	defaultExpirationWarning = 7 * 24 * time.Hour
+)
+
+func Initialize() error {
+	w := &expirationWarner{once: &sync.Once{}}
+	w.start()
+	shutdown.Register(w)
+	return nil
+}
+
+// expirationWarner periodically creates events for the team tokens that are
+// about to expire, once per token, so that their teams can rotate them.
+type expirationWarner struct {
+	once   *sync.Once
+	stopCh chan struct{}
+}
+
+func (w *expirationWarner) start() {
+	w.once.Do(func() {
+		w.stopCh = make(chan struct{})
+		go w.spin()
+	})
+}
+
+func (w *expirationWarner) Shutdown(ctx context.Context) error {
+	if w.stopCh == nil {
+		return nil
+	}
+	w.stopCh <- struct{}{}
+	w.stopCh = nil
+	w.once = &sync.Once{}
+	return nil
+}
+
+func (w *expirationWarner) spin() {
+	for {
+		err := warnExpiring(context.Background())
+		if err != nil {
+			log.Errorf("[team token expiration] %v", err)
+		}
+		select {
+		case <-w.stopCh:
+			return
+		case <-time.After(expirationRunInterval):
+		}
+	}
+}
+
+func expirationWarning() time.Duration {
+	warning, err := config.GetDuration("auth:team-token:expiration-warning")
+	if err != nil {
+		return defaultExpirationWarning
+	}
+	return warning
+}
+
+func warnExpiring(ctx context.Context) error {
+	warning := expirationWarning()
+	if warning <= 0 {
+		return nil
+	}
+	tokens, err := servicemanager.TeamToken.FindExpiring(ctx, warning)
+	if err != nil {
+		return err
+	}
+	multi := tsuruErrors.NewMultiError()
+	for _, token := range tokens {
+		err = warnToken(ctx, token)
+		if err != nil {
+			multi.Add(errors.Wrapf(err, "unable to warn about the expiration of team token %q", token.TokenID))
+		}
+	}
+	return multi.ToError()
+}
+
+func warnToken(ctx context.Context, token authTypes.TeamToken) error {
+	evt, err := event.NewInternal(&event.Opts{
+		Target:       event.Target{Type: event.TargetTypeTeam, Value: token.Team},
+		InternalKind: ExpiringEventKind,
+		DisableLock:  true,
+		CustomData: map[string]interface{}{
+			"token_id":   token.TokenID,
+			"expires_at": token.ExpiresAt,
+		},
+		Allowed: event.Allowed(permission.PermTeamReadEvents, permission.Context(permTypes.CtxTeam, token.Team)),
+	})
+	if err != nil {
+		return err
+	}
+	log.Debugf("[team token expiration] team token %q expires at %s", token.TokenID, token.ExpiresAt)
+	err = evt.Done(nil)
+	if err != nil {
+		return err
+	}
+	return servicemanager.TeamToken.SetExpirationWarned(ctx, token.TokenID)
+}
@@ -0,0 +1,128 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package teamtoken
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/auth"
+	"github.com/tsuru/tsuru/db"
+	"github.com/tsuru/tsuru/db/dbtest"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/event/eventtest"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/servicemanager"
+	servicemock "github.com/tsuru/tsuru/servicemanager/mock"
+	_ "github.com/tsuru/tsuru/storage/mongodb"
+	authTypes "github.com/tsuru/tsuru/types/auth"
+	check "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) { check.TestingT(t) }
+
+type S struct {
+	storage     *db.Storage
+	user        *auth.User
+	mockService servicemock.MockService
+}
+
+var _ = check.Suite(&S{})
+
+func (s *S) SetUpSuite(c *check.C) {
+	config.Set("log:disable-syslog", true)
+	config.Set("database:url", "127.0.0.1:27017?maxPoolSize=100")
+	config.Set("database:name", "auth_teamtoken_tests")
+	var err error
+	s.storage, err = db.Conn()
+	c.Assert(err, check.IsNil)
+}
+
+func (s *S) SetUpTest(c *check.C) {
+	servicemock.SetMockService(&s.mockService)
+	s.mockService.Team.OnFindByName = func(name string) (*authTypes.Team, error) {
+		return &authTypes.Team{Name: name}, nil
+	}
+	var err error
+	servicemanager.TeamToken, err = auth.TeamTokenService()
+	c.Assert(err, check.IsNil)
+	s.user = &auth.User{Email: "majortom@groundcontrol.com"}
+}
+
+func (s *S) TearDownTest(c *check.C) {
+	err := dbtest.ClearAllCollections(s.storage.Apps().Database)
+	c.Assert(err, check.IsNil)
+}
+
+func (s *S) TearDownSuite(c *check.C) {
+	dbtest.ClearAllCollections(s.storage.Apps().Database)
+	s.storage.Close()
+}
+
+func (s *S) createToken(c *check.C, tokenID string, expiresIn int) authTypes.TeamToken {
+	token, err := servicemanager.TeamToken.Create(context.TODO(), authTypes.TeamTokenCreateArgs{
+		TokenID:   tokenID,
+		Team:      "myteam",
+		ExpiresIn: expiresIn,
+	}, &userToken{user: s.user})
+	c.Assert(err, check.IsNil)
+	return token
+}
+
+type userToken struct {
+	user *auth.User
+}
+
+func (t *userToken) GetValue() string    { return "" }
+func (t *userToken) GetAppName() string  { return "" }
+func (t *userToken) GetUserName() string { return t.user.Email }
+func (t *userToken) IsAppToken() bool    { return false }
+func (t *userToken) User() (*authTypes.User, error) {
+	return &authTypes.User{Email: t.user.Email}, nil
+}
+func (t *userToken) Permissions() ([]permission.Permission, error) {
+	return nil, nil
+}
+
+func (s *S) TestExpirationWarnerStartNothingToDo(c *check.C) {
+	w := &expirationWarner{once: &sync.Once{}}
+	w.start()
+	err := w.Shutdown(context.Background())
+	c.Assert(err, check.IsNil)
+}
+
+func (s *S) TestWarnExpiring(c *check.C) {
+	s.createToken(c, "t1", 60*60)
+	s.createToken(c, "t2", 30*24*60*60)
+	s.createToken(c, "t3", 0)
+	err := warnExpiring(context.TODO())
+	c.Assert(err, check.IsNil)
+	c.Assert(eventtest.EventDesc{
+		Target: event.Target{Type: event.TargetTypeTeam, Value: "myteam"},
+		Kind:   ExpiringEventKind,
+		StartCustomData: map[string]interface{}{
+			"token_id": "t1",
+		},
+	}, eventtest.HasEvent)
+	evts, err := event.All()
+	c.Assert(err, check.IsNil)
+	c.Assert(evts, check.HasLen, 1)
+	err = warnExpiring(context.TODO())
+	c.Assert(err, check.IsNil)
+	evts, err = event.All()
+	c.Assert(err, check.IsNil)
+	c.Assert(evts, check.HasLen, 1)
+}
+
+func (s *S) TestWarnExpiringDisabled(c *check.C) {
+	config.Set("auth:team-token:expiration-warning", 0)
+	defer config.Unset("auth:team-token:expiration-warning")
+	s.createToken(c, "t1", 60)
+	err := warnExpiring(context.TODO())
+	c.Assert(err, check.IsNil)
+	c.Assert(eventtest.EventDesc{IsEmpty: true}, eventtest.HasEvent)
+}
@@ -5,6 +5,7 @@
 package auth
 
 import (
+	"context"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -50,7 +51,9 @@ func ParseToken(header string) (string, error) {
 	return value, ErrInvalidToken
 }
 
-func BaseTokenPermission(t Token) ([]permission.Permission, error) {
+// BaseTokenPermission returns the permissions of the user of t, teams caches
+// the team hierarchy between calls and may be nil.
+func BaseTokenPermission(t Token, teams *TeamHierarchy) ([]permission.Permission, error) {
 	if t.IsAppToken() {
 		// TODO(cezarsa): Improve handling of app tokens. These permissions
 		// listed here are the ones required by deploy-agent and legacy tsuru-
@@ -82,5 +85,5 @@ func BaseTokenPermission(t Token) ([]permission.Permission, error) {
 	if err != nil {
 		return nil, err
 	}
-	return u.Permissions()
+	return u.permissions(context.TODO(), teams)
 }
@@ -0,0 +1,86 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"fmt"
+	"strings"
+
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/log"
+	"github.com/tsuru/tsuru/permission"
+	authTypes "github.com/tsuru/tsuru/types/auth"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+)
+
+// ParseTeamTokenScopes validates the scopes of a team token, in the format
+// <permission>[:<context type>:<context value>], e.g. app.deploy:app:myapp.
+// Scopes without a context are global and empty entries are ignored.
+func ParseTeamTokenScopes(scopes []string) ([]authTypes.TeamTokenScope, error) {
+	var result []authTypes.TeamTokenScope
+	for _, raw := range scopes {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+		parts := strings.SplitN(raw, ":", 3)
+		scheme, err := permission.SafeGet(parts[0])
+		if err != nil || parts[0] == "" {
+			return nil, &tsuruErrors.ValidationError{Message: fmt.Sprintf("invalid scope %q: unknown permission %q", raw, parts[0])}
+		}
+		scope := authTypes.TeamTokenScope{Permission: parts[0], ContextType: string(permTypes.CtxGlobal)}
+		if len(parts) > 1 {
+			scope.ContextType = parts[1]
+		}
+		if len(parts) > 2 {
+			scope.ContextValue = parts[2]
+		}
+		ctxType, err := permission.ParseContext(scope.ContextType)
+		if err != nil {
+			return nil, &tsuruErrors.ValidationError{Message: fmt.Sprintf("invalid scope %q: %v", raw, err)}
+		}
+		if !contextAllowed(scheme, ctxType) {
+			return nil, &tsuruErrors.ValidationError{Message: fmt.Sprintf("invalid scope %q: permission %q can't be used with context %q", raw, scope.Permission, ctxType)}
+		}
+		if ctxType == permTypes.CtxGlobal && scope.ContextValue != "" {
+			return nil, &tsuruErrors.ValidationError{Message: fmt.Sprintf("invalid scope %q: global context doesn't take a value", raw)}
+		}
+		if ctxType != permTypes.CtxGlobal && scope.ContextValue == "" {
+			return nil, &tsuruErrors.ValidationError{Message: fmt.Sprintf("invalid scope %q: context %q requires a value", raw, ctxType)}
+		}
+		result = append(result, scope)
+	}
+	return result, nil
+}
+
+func contextAllowed(scheme *permission.PermissionScheme, ctxType permTypes.ContextType) bool {
+	for _, allowed := range scheme.AllowedContexts() {
+		if allowed == ctxType {
+			return true
+		}
+	}
+	return false
+}
+
+// scopePermissions converts the scopes of a team token to permissions. Scopes
+// whose permissions were removed after the token was scoped allow nothing.
+func scopePermissions(scopes []authTypes.TeamTokenScope) []permission.Permission {
+	if len(scopes) == 0 {
+		return nil
+	}
+	perms := make([]permission.Permission, 0, len(scopes))
+	for _, scope := range scopes {
+		scheme, err := permission.SafeGet(scope.Permission)
+		if err != nil {
+			log.Errorf("ignoring invalid team token scope %q: %v", scope.Permission, err)
+			continue
+		}
+		perms = append(perms, permission.Permission{
+			Scheme:  scheme,
+			Context: permission.Context(permTypes.ContextType(scope.ContextType), scope.ContextValue),
+		})
+	}
+	return perms
+}
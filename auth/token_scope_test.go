@@ -0,0 +1,60 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"github.com/tsuru/tsuru/permission"
+	authTypes "github.com/tsuru/tsuru/types/auth"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestParseTeamTokenScopes(c *check.C) {
+	scopes, err := ParseTeamTokenScopes([]string{"app.deploy:app:myapp", " ", "app.read", "team.read:global", "pool:pool:p1"})
+	c.Assert(err, check.IsNil)
+	c.Assert(scopes, check.DeepEquals, []authTypes.TeamTokenScope{
+		{Permission: "app.deploy", ContextType: "app", ContextValue: "myapp"},
+		{Permission: "app.read", ContextType: "global"},
+		{Permission: "team.read", ContextType: "global"},
+		{Permission: "pool", ContextType: "pool", ContextValue: "p1"},
+	})
+	scopes, err = ParseTeamTokenScopes(nil)
+	c.Assert(err, check.IsNil)
+	c.Assert(scopes, check.IsNil)
+}
+
+func (s *S) TestParseTeamTokenScopesInvalid(c *check.C) {
+	tests := []struct {
+		scope string
+		err   string
+	}{
+		{scope: "app.unknown", err: `invalid scope "app.unknown": unknown permission "app.unknown"`},
+		{scope: ":app:myapp", err: `invalid scope ":app:myapp": unknown permission ""`},
+		{scope: "app.deploy:planet:earth", err: `invalid scope "app.deploy:planet:earth": invalid context type "planet"`},
+		{scope: "app.deploy:service:mysql", err: `invalid scope "app.deploy:service:mysql": permission "app.deploy" can't be used with context "service"`},
+		{scope: "app.deploy:app", err: `invalid scope "app.deploy:app": context "app" requires a value`},
+		{scope: "app.deploy:global:myapp", err: `invalid scope "app.deploy:global:myapp": global context doesn't take a value`},
+	}
+	for _, tt := range tests {
+		_, err := ParseTeamTokenScopes([]string{tt.scope})
+		c.Check(err, check.ErrorMatches, tt.err)
+	}
+}
+
+func (s *S) TestScopePermissions(c *check.C) {
+	c.Assert(scopePermissions(nil), check.IsNil)
+	perms := scopePermissions([]authTypes.TeamTokenScope{
+		{Permission: "app.deploy", ContextType: "app", ContextValue: "myapp"},
+		{Permission: "removed.permission", ContextType: "global"},
+	})
+	c.Assert(perms, check.DeepEquals, []permission.Permission{
+		{Scheme: permission.PermAppDeploy, Context: permission.Context(permTypes.CtxApp, "myapp")},
+	})
+	perms = scopePermissions([]authTypes.TeamTokenScope{
+		{Permission: "removed.permission", ContextType: "global"},
+	})
+	c.Assert(perms, check.NotNil)
+	c.Assert(perms, check.HasLen, 0)
+}
@@ -5,10 +5,12 @@
 package auth
 
 import (
+	"context"
 	"crypto"
 	"crypto/rand"
 	_ "crypto/sha256"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/globalsign/mgo/bson"
@@ -26,13 +28,16 @@ import (
 )
 
 type User struct {
-	Quota     quota.Quota
-	Email     string
-	Password  string
-	APIKey    string
-	Roles     []authTypes.RoleInstance `bson:",omitempty"`
-	Groups    []string                 `bson:",omitempty"`
-	FromToken bool                     `bson:",omitempty"`
+	Quota    quota.Quota
+	Email    string
+	Password string
+	APIKey   string
+	Roles    []authTypes.RoleInstance `bson:",omitempty"`
+	Groups   []string                 `bson:",omitempty"`
+	// Origin identifies the identity provider the user was registered through,
+	// like "oidc:github".
+	Origin    string `bson:",omitempty"`
+	FromToken bool   `bson:",omitempty"`
 }
 
 func listUsers(filter bson.M) ([]User, error) {
@@ -64,10 +69,11 @@ func ListUsersWithPermissions(wantedPerms ...permission.Permission) ([]User, err
 		return nil, err
 	}
 	var filteredUsers []User
+	var teams TeamHierarchy
 	// TODO(cezarsa): Too slow! Think about faster implementation in the future.
 usersLoop:
 	for _, u := range allUsers {
-		perms, err := u.Permissions()
+		perms, err := u.permissions(context.TODO(), &teams)
 		if err != nil {
 			return nil, err
 		}
@@ -133,7 +139,10 @@ func (u *User) Delete() error {
 	if err != nil {
 		log.Errorf("failed to remove user %q from the database: %s", u.Email, err)
 	}
-
+	err = RemoveImpersonationTokens(u.Email)
+	if err != nil {
+		log.Errorf("failed to remove impersonation tokens of user %q: %s", u.Email, err)
+	}
 	return nil
 }
 
@@ -182,10 +191,14 @@ func (u *User) Reload() error {
 	return conn.Users().Find(bson.M{"email": u.Email}).One(u)
 }
 
-func expandRolePermissions(roleInstances []authTypes.RoleInstance) ([]permission.Permission, error) {
+func expandRolePermissions(ctx context.Context, roleInstances []authTypes.RoleInstance, teams *TeamHierarchy) ([]permission.Permission, error) {
 	var permissions []permission.Permission
 	roles := make(map[string]*permission.Role)
+	now := time.Now()
 	for _, roleData := range roleInstances {
+		if roleData.Expired(now) {
+			continue
+		}
 		role := roles[roleData.Name]
 		if role == nil {
 			foundRole, err := permission.FindRole(roleData.Name)
@@ -197,7 +210,103 @@ func expandRolePermissions(roleInstances []authTypes.RoleInstance) ([]permission
 		}
 		permissions = append(permissions, role.PermissionsFor(roleData.ContextValue)...)
 	}
-	return permissions, nil
+	if teams == nil {
+		teams = &TeamHierarchy{}
+	}
+	return teams.expand(ctx, permissions)
+}
+
+// TeamHierarchy caches the teams nested in other teams. Tokens are loaded on
+// every request and keep one, so the hierarchy is queried once per request
+// instead of on every permission check. The zero value is ready to use.
+type TeamHierarchy struct {
+	loaded   map[string]bool
+	linked   map[string]bool
+	children map[string][]string
+}
+
+// teamHierarchyMu guards every TeamHierarchy, it's never held while querying
+// the database.
+var teamHierarchyMu sync.Mutex
+
+// missing returns the teams whose descendants were not loaded yet.
+func (h *TeamHierarchy) missing(teams []string) []string {
+	teamHierarchyMu.Lock()
+	defer teamHierarchyMu.Unlock()
+	var missing []string
+	for _, team := range teams {
+		if !h.loaded[team] {
+			missing = append(missing, team)
+		}
+	}
+	return missing
+}
+
+// add records the descendants loaded for the given teams.
+func (h *TeamHierarchy) add(teams []string, descendants []authTypes.Team) {
+	teamHierarchyMu.Lock()
+	defer teamHierarchyMu.Unlock()
+	if h.loaded == nil {
+		h.loaded = make(map[string]bool)
+		h.linked = make(map[string]bool)
+		h.children = make(map[string][]string)
+	}
+	for _, team := range teams {
+		h.loaded[team] = true
+	}
+	for _, team := range descendants {
+		if !h.linked[team.Name] {
+			h.linked[team.Name] = true
+			h.children[team.Parent] = append(h.children[team.Parent], team.Name)
+		}
+		h.loaded[team.Name] = true
+	}
+}
+
+// expand extends permissions granted on a team to every team nested in it.
+func (h *TeamHierarchy) expand(ctx context.Context, permissions []permission.Permission) ([]permission.Permission, error) {
+	var teams []string
+	seen := make(map[string]bool)
+	for _, perm := range permissions {
+		if perm.Context.CtxType == permTypes.CtxTeam && !seen[perm.Context.Value] {
+			seen[perm.Context.Value] = true
+			teams = append(teams, perm.Context.Value)
+		}
+	}
+	if len(teams) == 0 {
+		return permissions, nil
+	}
+	if missing := h.missing(teams); len(missing) > 0 {
+		descendants, err := servicemanager.Team.FindDescendants(ctx, missing...)
+		if err != nil {
+			return nil, err
+		}
+		h.add(missing, descendants)
+	}
+	teamHierarchyMu.Lock()
+	defer teamHierarchyMu.Unlock()
+	expanded := permissions
+	for _, perm := range permissions {
+		if perm.Context.CtxType != permTypes.CtxTeam {
+			continue
+		}
+		pending := h.children[perm.Context.Value]
+		visited := map[string]bool{perm.Context.Value: true}
+		for len(pending) > 0 {
+			name := pending[0]
+			pending = pending[1:]
+			if visited[name] {
+				continue
+			}
+			visited[name] = true
+			expanded = append(expanded, permission.Permission{
+				Scheme:  perm.Scheme,
+				Context: permission.Context(permTypes.CtxTeam, name),
+			})
+			pending = append(pending, h.children[name]...)
+		}
+	}
+	return expanded, nil
 }
 
 func (u *User) UserGroups() ([]authTypes.Group, error) {
@@ -213,6 +322,10 @@ func (u *User) UserGroups() ([]authTypes.Group, error) {
 }
 
 func (u *User) Permissions() ([]permission.Permission, error) {
+	return u.permissions(context.TODO(), nil)
+}
+
+func (u *User) permissions(ctx context.Context, teams *TeamHierarchy) ([]permission.Permission, error) {
 	groups, err := u.UserGroups()
 	if err != nil {
 		return nil, err
@@ -221,7 +334,7 @@ func (u *User) Permissions() ([]permission.Permission, error) {
 	for _, group := range groups {
 		allRoles = append(allRoles, group.Roles...)
 	}
-	permissions, err := expandRolePermissions(allRoles)
+	permissions, err := expandRolePermissions(ctx, allRoles, teams)
 	if err != nil {
 		return nil, err
 	}
@@ -232,6 +345,17 @@ func (u *User) Permissions() ([]permission.Permission, error) {
 }
 
 func (u *User) AddRole(roleName string, contextValue string) error {
+	return u.addRole(roleName, contextValue, nil)
+}
+
+// AddTemporaryRole assigns the role to the user until expiresAt, replacing
+// previous temporary assignments of the role in the same context.
+func (u *User) AddTemporaryRole(roleName string, contextValue string, expiresAt time.Time) error {
+	expiresAt = expiresAt.UTC().Truncate(time.Millisecond)
+	return u.addRole(roleName, contextValue, &expiresAt)
+}
+
+func (u *User) addRole(roleName string, contextValue string, expiresAt *time.Time) error {
 	_, err := permission.FindRole(roleName)
 	if err != nil {
 		return err
@@ -241,22 +365,68 @@ func (u *User) AddRole(roleName string, contextValue string) error {
 		return err
 	}
 	defer conn.Close()
+	// Permanent assignments supersede temporary ones, which are also
+	// replaced by new temporary assignments.
 	err = conn.Users().Update(bson.M{"email": u.Email}, bson.M{
-		"$addToSet": bson.M{
-			// Order matters in $addToSet, that's why bson.D is used instead
-			// of bson.M.
-			"roles": bson.D([]bson.DocElem{
-				{Name: "name", Value: roleName},
-				{Name: "contextvalue", Value: contextValue},
-			}),
+		"$pull": bson.M{
+			"roles": bson.M{"name": roleName, "contextvalue": contextValue, "expiresat": bson.M{"$exists": true}},
 		},
 	})
 	if err != nil {
 		return err
 	}
+	// Order matters in $addToSet, that's why bson.D is used instead of
+	// bson.M.
+	role := bson.D([]bson.DocElem{
+		{Name: "name", Value: roleName},
+		{Name: "contextvalue", Value: contextValue},
+	})
+	if expiresAt != nil {
+		role = append(role, bson.DocElem{Name: "expiresat", Value: *expiresAt})
+	}
+	err = conn.Users().Update(bson.M{"email": u.Email}, bson.M{
+		"$addToSet": bson.M{"roles": role},
+	})
+	if err != nil {
+		return err
+	}
 	return u.Reload()
 }
 
+// ListUsersWithExpiredRoles returns the users with temporary role assignments
+// expired at the given time.
+func ListUsersWithExpiredRoles(now time.Time) ([]User, error) {
+	return listUsers(bson.M{"roles.expiresat": bson.M{"$lte": now}})
+}
+
+// RemoveExpiredRoles revokes the temporary role assignments of the user
+// expired at the given time, returning them.
+func (u *User) RemoveExpiredRoles(now time.Time) ([]authTypes.RoleInstance, error) {
+	var expired []authTypes.RoleInstance
+	for _, r := range u.Roles {
+		if r.Expired(now) {
+			expired = append(expired, r)
+		}
+	}
+	if len(expired) == 0 {
+		return nil, nil
+	}
+	conn, err := db.Conn()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	err = conn.Users().Update(bson.M{"email": u.Email}, bson.M{
+		"$pull": bson.M{
+			"roles": bson.M{"expiresat": bson.M{"$lte": now}},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return expired, u.Reload()
+}
+
 func UpdateRoleFromAllUsers(roleName, newRoleName, ctx, desc string) error {
 	role, err := permission.FindRole(roleName)
 	if err != nil {
@@ -5,7 +5,9 @@
 package auth
 
 import (
+	"context"
 	"sort"
+	"time"
 
 	"github.com/globalsign/mgo/bson"
 	"github.com/tsuru/config"
@@ -176,6 +178,93 @@ func (s *S) TestUserAddRole(c *check.C) {
 	c.Assert(uDB.Roles, check.DeepEquals, expected)
 }
 
+func (s *S) TestUserAddTemporaryRole(c *check.C) {
+	_, err := permission.NewRole("r1", "app", "")
+	c.Assert(err, check.IsNil)
+	u := User{Email: "me@tsuru.com", Password: "123"}
+	err = u.Create()
+	c.Assert(err, check.IsNil)
+	expiresAt := time.Now().Add(time.Hour).UTC().Truncate(time.Millisecond)
+	err = u.AddTemporaryRole("r1", "myapp", expiresAt.Add(-time.Minute))
+	c.Assert(err, check.IsNil)
+	err = u.AddTemporaryRole("r1", "myapp", expiresAt)
+	c.Assert(err, check.IsNil)
+	c.Assert(u.Roles, check.HasLen, 1)
+	c.Assert(u.Roles[0].Name, check.Equals, "r1")
+	c.Assert(u.Roles[0].ExpiresAt.Equal(expiresAt), check.Equals, true)
+	err = u.AddRole("r1", "myapp")
+	c.Assert(err, check.IsNil)
+	c.Assert(u.Roles, check.DeepEquals, []authTypes.RoleInstance{{Name: "r1", ContextValue: "myapp"}})
+	err = u.AddTemporaryRole("r1", "myapp", expiresAt)
+	c.Assert(err, check.IsNil)
+	c.Assert(u.Roles, check.HasLen, 2)
+	err = u.RemoveRole("r1", "myapp")
+	c.Assert(err, check.IsNil)
+	c.Assert(u.Roles, check.HasLen, 0)
+}
+
+func (s *S) TestUserPermissionsIgnoresExpiredRoles(c *check.C) {
+	r1, err := permission.NewRole("r1", "app", "")
+	c.Assert(err, check.IsNil)
+	err = r1.AddPermissions("app.deploy")
+	c.Assert(err, check.IsNil)
+	u := User{Email: "me@tsuru.com", Password: "123"}
+	err = u.Create()
+	c.Assert(err, check.IsNil)
+	err = u.AddTemporaryRole("r1", "myapp", time.Now().Add(time.Hour))
+	c.Assert(err, check.IsNil)
+	err = u.AddTemporaryRole("r1", "oldapp", time.Now().Add(time.Hour))
+	c.Assert(err, check.IsNil)
+	past := time.Now().Add(-time.Minute)
+	err = s.conn.Users().Update(bson.M{"email": u.Email, "roles.contextvalue": "oldapp"}, bson.M{"$set": bson.M{"roles.$.expiresat": past}})
+	c.Assert(err, check.IsNil)
+	err = u.Reload()
+	c.Assert(err, check.IsNil)
+	perms, err := u.Permissions()
+	c.Assert(err, check.IsNil)
+	c.Assert(perms, check.DeepEquals, []permission.Permission{
+		{Scheme: permission.PermUser, Context: permission.Context(permTypes.CtxUser, u.Email)},
+		{Scheme: permission.PermAppDeploy, Context: permission.Context(permTypes.CtxApp, "myapp")},
+	})
+}
+
+func (s *S) TestRemoveExpiredRoles(c *check.C) {
+	_, err := permission.NewRole("r1", "app", "")
+	c.Assert(err, check.IsNil)
+	u := User{Email: "me@tsuru.com", Password: "123"}
+	err = u.Create()
+	c.Assert(err, check.IsNil)
+	other := User{Email: "other@tsuru.com", Password: "123"}
+	err = other.Create()
+	c.Assert(err, check.IsNil)
+	now := time.Now()
+	err = u.AddRole("r1", "permanent")
+	c.Assert(err, check.IsNil)
+	err = u.AddTemporaryRole("r1", "expired", now.Add(-time.Minute))
+	c.Assert(err, check.IsNil)
+	err = u.AddTemporaryRole("r1", "active", now.Add(time.Hour))
+	c.Assert(err, check.IsNil)
+	err = other.AddTemporaryRole("r1", "active", now.Add(time.Hour))
+	c.Assert(err, check.IsNil)
+	users, err := ListUsersWithExpiredRoles(now)
+	c.Assert(err, check.IsNil)
+	c.Assert(users, check.HasLen, 1)
+	c.Assert(users[0].Email, check.Equals, u.Email)
+	expired, err := users[0].RemoveExpiredRoles(now)
+	c.Assert(err, check.IsNil)
+	c.Assert(expired, check.HasLen, 1)
+	c.Assert(expired[0].ContextValue, check.Equals, "expired")
+	var contexts []string
+	for _, r := range users[0].Roles {
+		contexts = append(contexts, r.ContextValue)
+	}
+	sort.Strings(contexts)
+	c.Assert(contexts, check.DeepEquals, []string{"active", "permanent"})
+	users, err = ListUsersWithExpiredRoles(now)
+	c.Assert(err, check.IsNil)
+	c.Assert(users, check.HasLen, 0)
+}
+
 func (s *S) TestUserRemoveRole(c *check.C) {
 	u := User{
 		Email:    "me@tsuru.com",
@@ -297,6 +386,68 @@ func (s *S) TestUserPermissionsIncludeGroups(c *check.C) {
 	})
 }
 
+func (s *S) TestUserPermissionsIncludeNestedTeams(c *check.C) {
+	u := User{Email: "me@tsuru.com", Password: "123"}
+	err := u.Create()
+	c.Assert(err, check.IsNil)
+	for _, name := range []string{"org", "team1", "team2"} {
+		err = servicemanager.Team.Create(context.TODO(), name, nil, &authTypes.User{Email: u.Email})
+		c.Assert(err, check.IsNil)
+	}
+	err = servicemanager.Team.SetParent(context.TODO(), "team1", "org")
+	c.Assert(err, check.IsNil)
+	err = servicemanager.Team.SetParent(context.TODO(), "team2", "team1")
+	c.Assert(err, check.IsNil)
+
+	r1, err := permission.NewRole("r1", "team", "")
+	c.Assert(err, check.IsNil)
+	err = r1.AddPermissions("app.deploy")
+	c.Assert(err, check.IsNil)
+	err = u.AddRole("r1", "team1")
+	c.Assert(err, check.IsNil)
+
+	perms, err := u.Permissions()
+	c.Assert(err, check.IsNil)
+	c.Assert(perms, check.DeepEquals, []permission.Permission{
+		{Scheme: permission.PermUser, Context: permission.Context(permTypes.CtxUser, u.Email)},
+		{Scheme: permission.PermAppDeploy, Context: permission.Context(permTypes.CtxTeam, "team1")},
+		{Scheme: permission.PermAppDeploy, Context: permission.Context(permTypes.CtxTeam, "team2")},
+	})
+}
+
+func (s *S) TestTokenPermissionsLoadTeamHierarchyOnce(c *check.C) {
+	u := User{Email: "me@tsuru.com", Password: "123"}
+	err := u.Create()
+	c.Assert(err, check.IsNil)
+	r1, err := permission.NewRole("r1", "team", "")
+	c.Assert(err, check.IsNil)
+	err = r1.AddPermissions("app.deploy")
+	c.Assert(err, check.IsNil)
+	err = u.AddRole("r1", "team1")
+	c.Assert(err, check.IsNil)
+	var calls int
+	oldTeamService := servicemanager.Team
+	defer func() { servicemanager.Team = oldTeamService }()
+	servicemanager.Team = &authTypes.MockTeamService{
+		OnFindDescendants: func(names []string) ([]authTypes.Team, error) {
+			calls++
+			c.Assert(names, check.DeepEquals, []string{"team1"})
+			return []authTypes.Team{{Name: "team2", Parent: "team1"}}, nil
+		},
+	}
+	token := &APIToken{UserEmail: u.Email}
+	for i := 0; i < 2; i++ {
+		perms, err := token.Permissions()
+		c.Assert(err, check.IsNil)
+		c.Assert(perms, check.DeepEquals, []permission.Permission{
+			{Scheme: permission.PermUser, Context: permission.Context(permTypes.CtxUser, u.Email)},
+			{Scheme: permission.PermAppDeploy, Context: permission.Context(permTypes.CtxTeam, "team1")},
+			{Scheme: permission.PermAppDeploy, Context: permission.Context(permTypes.CtxTeam, "team2")},
+		})
+	}
+	c.Assert(calls, check.Equals, 1)
+}
+
 func (s *S) TestUserPermissionsWithRemovedRole(c *check.C) {
 	role, err := permission.NewRole("test", "team", "")
 	c.Assert(err, check.IsNil)
@@ -79,8 +79,10 @@ func Conn() (*Storage, error) {
 // Apps returns the apps collection from MongoDB.
 func (s *Storage) Apps() *storage.Collection {
 	nameIndex := mgo.Index{Key: []string{"name"}, Unique: true}
+	tagsIndex := mgo.Index{Key: []string{"tags"}}
 	c := s.Collection("apps")
 	c.EnsureIndex(nameIndex)
+	c.EnsureIndex(tagsIndex)
 	return c
 }
 
@@ -125,6 +127,30 @@ func (s *Storage) PasswordTokens() *storage.Collection {
 	return s.Collection("password_tokens")
 }
 
+// TwoFactorSecrets returns the collection of two-factor authentication
+// secrets of users.
+func (s *Storage) TwoFactorSecrets() *storage.Collection {
+	return s.Collection("two_factor_secrets")
+}
+
+// LoginFailures returns the collection of failed login attempts of users,
+// used to lock them out after repeated failures.
+func (s *Storage) LoginFailures() *storage.Collection {
+	return s.Collection("login_failures")
+}
+
+// PasswordHistory returns the collection of previous password hashes of
+// users and when they were last changed, used by the password policy.
+func (s *Storage) PasswordHistory() *storage.Collection {
+	return s.Collection("password_history")
+}
+
+// ImpersonationTokens returns the collection of the tokens used by admins to
+// act as other users.
+func (s *Storage) ImpersonationTokens() *storage.Collection {
+	return s.Collection("impersonation_tokens")
+}
+
 func (s *Storage) UserActions() *storage.Collection {
 	return s.Collection("user_actions")
 }
@@ -141,6 +167,11 @@ func (s *Storage) Roles() *storage.Collection {
 	return s.Collection("roles")
 }
 
+// RoleTemplates returns the collection of user-defined role templates.
+func (s *Storage) RoleTemplates() *storage.Collection {
+	return s.Collection("role_templates")
+}
+
 func (s *Storage) Limiter() *storage.Collection {
 	return s.Collection("limiter")
 }
@@ -152,11 +183,17 @@ func (s *Storage) Events() *storage.Collection {
 	kindIndex := mgo.Index{Key: []string{"kind.name"}}
 	startTimeIndex := mgo.Index{Key: []string{"-starttime"}}
 	uniqueIdIndex := mgo.Index{Key: []string{"uniqueid"}}
+	parentIdIndex := mgo.Index{Key: []string{"parentid"}, Sparse: true, Background: true}
 	runningIndex := mgo.Index{Key: []string{"running"}}
 	allowedSchemeIndex := mgo.Index{Key: []string{"allowed.scheme"}}
 	latestTargetKindIndex := mgo.Index{Key: []string{"target.value", "kind.name", "-starttime"}, Background: true}
 	latestTargetIndex := mgo.Index{Key: []string{"target.value", "-starttime"}, Background: true}
 	latestExtraTargetIndex := mgo.Index{Key: []string{"extratargets.target.value", "-starttime"}, Background: true}
+	searchTextIndex := mgo.Index{
+		Key:             []string{"$text:target.value", "$text:kind.name", "$text:owner.name"},
+		DefaultLanguage: "none",
+		Background:      true,
+	}
 
 	c := s.Collection("events")
 	c.EnsureIndex(ownerIndex)
@@ -165,11 +202,13 @@ func (s *Storage) Events() *storage.Collection {
 	c.EnsureIndex(kindIndex)
 	c.EnsureIndex(startTimeIndex)
 	c.EnsureIndex(uniqueIdIndex)
+	c.EnsureIndex(parentIdIndex)
 	c.EnsureIndex(runningIndex)
 	c.EnsureIndex(allowedSchemeIndex)
 	c.EnsureIndex(latestTargetKindIndex)
 	c.EnsureIndex(latestTargetIndex)
 	c.EnsureIndex(latestExtraTargetIndex)
+	c.EnsureIndex(searchTextIndex)
 	return c
 }
 
@@ -186,6 +225,13 @@ func (s *Storage) EventBlocks() *storage.Collection {
 	return c
 }
 
+func (s *Storage) DeployQueue() *storage.Collection {
+	appIndex := mgo.Index{Key: []string{"app", "_id"}}
+	c := s.Collection("deploy_queue")
+	c.EnsureIndex(appIndex)
+	return c
+}
+
 func (s *Storage) InstallHosts() *storage.Collection {
 	nameIndex := mgo.Index{Key: []string{"name"}, Unique: true}
 	c := s.Collection("install_hosts")
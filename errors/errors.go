@@ -19,6 +19,11 @@ type HTTP struct {
 
 	// Message explaining what went wrong.
 	Message string
+
+	// ErrorCode is a stable identifier of the error, like
+	// app.quota.exceeded, so that clients are able to handle failures
+	// without parsing the message.
+	ErrorCode string
 }
 
 func (e *HTTP) Error() string {
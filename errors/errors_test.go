@@ -22,7 +22,7 @@ type S struct{}
 var _ = check.Suite(&S{})
 
 func (s *S) TestHTTPError(c *check.C) {
-	e := HTTP{500, "Internal server error"}
+	e := HTTP{Code: 500, Message: "Internal server error"}
 	c.Assert(e.Error(), check.Equals, e.Message)
 }
 
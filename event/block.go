@@ -5,6 +5,7 @@
 package event
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
@@ -12,6 +13,8 @@ import (
 	"github.com/globalsign/mgo"
 	"github.com/globalsign/mgo/bson"
 	"github.com/tsuru/tsuru/db"
+	"github.com/tsuru/tsuru/servicemanager"
+	"github.com/tsuru/tsuru/set"
 )
 
 type ErrActiveEventBlockNotFound struct {
@@ -41,17 +44,89 @@ type Block struct {
 	StartTime  time.Time
 	EndTime    time.Time `bson:"endtime,omitempty"`
 	KindName   string
+	KindNames  []string `bson:"kindnames,omitempty"`
 	OwnerName  string
 	Target     Target            `bson:"target,omitempty"`
 	Conditions map[string]string `bson:"conditions,omitempty"`
+	TeamName   string            `bson:"teamname,omitempty"`
+	PoolName   string            `bson:"poolname,omitempty"`
+	Tags       []string          `bson:"tags,omitempty"`
 	Reason     string
 	Active     bool
+	ExpiresAt  time.Time `bson:"expiresat,omitempty"`
+}
+
+// blockScope holds the team, pool and tags related to an event target, lazily
+// loaded only when a block with such conditions is evaluated.
+type blockScope struct {
+	evt    *Event
+	loaded bool
+	teams  set.Set
+	pools  set.Set
+	tags   set.Set
+}
+
+func (s *blockScope) load() {
+	if s.loaded {
+		return
+	}
+	s.loaded = true
+	s.teams, s.pools, s.tags = set.Set{}, set.Set{}, set.Set{}
+	targets := []Target{s.evt.Target}
+	for _, et := range s.evt.ExtraTargets {
+		targets = append(targets, et.Target)
+	}
+	for _, t := range targets {
+		switch t.Type {
+		case TargetTypeTeam:
+			s.teams.Add(t.Value)
+		case TargetTypePool:
+			s.pools.Add(t.Value)
+		case TargetTypeApp:
+			if servicemanager.App == nil {
+				continue
+			}
+			a, err := servicemanager.App.GetByName(context.TODO(), t.Value)
+			if err != nil {
+				continue
+			}
+			s.teams.Add(a.GetTeamOwner())
+			s.pools.Add(a.GetPool())
+			s.tags.Add(a.ListTags()...)
+		}
+	}
+}
+
+func (s *blockScope) matches(team, pool string, tags []string) bool {
+	if team == "" && pool == "" && len(tags) == 0 {
+		return true
+	}
+	s.load()
+	if team != "" && !s.teams.Includes(team) {
+		return false
+	}
+	if pool != "" && !s.pools.Includes(pool) {
+		return false
+	}
+	for _, tag := range tags {
+		if !s.tags.Includes(tag) {
+			return false
+		}
+	}
+	return true
 }
 
 func (b *Block) Blocks(e *Event) bool {
+	return b.blocks(e, &blockScope{evt: e})
+}
+
+func (b *Block) blocks(e *Event, scope *blockScope) bool {
 	if !(strings.HasPrefix(e.Kind.Name, b.KindName) || b.KindName == "") {
 		return false
 	}
+	if len(b.KindNames) > 0 && !hasAnyPrefix(e.Kind.Name, b.KindNames) {
+		return false
+	}
 	if !(e.Owner.Name == b.OwnerName || b.OwnerName == "") {
 		return false
 	}
@@ -75,11 +150,23 @@ func (b *Block) Blocks(e *Event) bool {
 			}
 		}
 	}
-	return true
+	return scope.matches(b.TeamName, b.PoolName, b.Tags)
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
 }
 
 func (b *Block) String() string {
 	kind := b.KindName
+	if len(b.KindNames) > 0 {
+		kind = strings.Join(b.KindNames, ", ")
+	}
 	if kind == "" {
 		kind = "all actions"
 	}
@@ -91,7 +178,24 @@ func (b *Block) String() string {
 	if b.Target.Type != "" {
 		target = b.Target.String()
 	}
-	return fmt.Sprintf("block %s by %s on %s: %s", kind, owner, target, b.Reason)
+	var scope []string
+	if b.TeamName != "" {
+		scope = append(scope, fmt.Sprintf("team %q", b.TeamName))
+	}
+	if b.PoolName != "" {
+		scope = append(scope, fmt.Sprintf("pool %q", b.PoolName))
+	}
+	if len(b.Tags) > 0 {
+		scope = append(scope, fmt.Sprintf("tags %q", b.Tags))
+	}
+	if len(scope) > 0 {
+		target = fmt.Sprintf("%s in %s", target, strings.Join(scope, ", "))
+	}
+	msg := fmt.Sprintf("block %s by %s on %s: %s", kind, owner, target, b.Reason)
+	if !b.ExpiresAt.IsZero() {
+		msg = fmt.Sprintf("%s (expires at %s)", msg, b.ExpiresAt.Format(time.RFC3339))
+	}
+	return msg
 }
 
 func AddBlock(b *Block) error {
@@ -121,6 +225,10 @@ func RemoveBlock(id bson.ObjectId) error {
 }
 
 func ListBlocks(active *bool) ([]Block, error) {
+	err := deactivateExpiredBlocks()
+	if err != nil {
+		return nil, err
+	}
 	query := bson.M{}
 	if active != nil {
 		query["active"] = *active
@@ -128,6 +236,31 @@ func ListBlocks(active *bool) ([]Block, error) {
 	return listBlocks(query)
 }
 
+// deactivateExpiredBlocks marks active blocks whose expiration time has
+// already passed as inactive, using the expiration time as their end time.
+func deactivateExpiredBlocks() error {
+	conn, err := db.Conn()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	var expired []Block
+	err = conn.EventBlocks().Find(bson.M{"active": true, "expiresat": bson.M{"$lte": time.Now()}}).All(&expired)
+	if err != nil {
+		return err
+	}
+	for _, b := range expired {
+		err = conn.EventBlocks().Update(
+			bson.M{"_id": b.ID, "active": true},
+			bson.M{"$set": bson.M{"active": false, "endtime": b.ExpiresAt}},
+		)
+		if err != nil && err != mgo.ErrNotFound {
+			return err
+		}
+	}
+	return nil
+}
+
 func listBlocks(query bson.M) ([]Block, error) {
 	conn, err := db.Conn()
 	if err != nil {
@@ -148,13 +281,20 @@ func checkIsBlocked(evt *Event) error {
 		return nil
 	}
 
-	blocks, err := listBlocks(bson.M{"active": true})
+	blocks, err := listBlocks(bson.M{
+		"active": true,
+		"$or": []bson.M{
+			{"expiresat": bson.M{"$exists": false}},
+			{"expiresat": bson.M{"$gt": time.Now()}},
+		},
+	})
 	if err != nil {
 		return err
 	}
 
+	scope := &blockScope{evt: evt}
 	for _, b := range blocks {
-		if b.Blocks(evt) {
+		if b.blocks(evt, scope) {
 			return ErrEventBlocked{event: evt, block: &b}
 		}
 	}
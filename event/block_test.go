@@ -9,6 +9,8 @@ import (
 	"time"
 
 	"github.com/globalsign/mgo/bson"
+	"github.com/tsuru/tsuru/servicemanager"
+	appTypes "github.com/tsuru/tsuru/types/app"
 	check "gopkg.in/check.v1"
 )
 
@@ -127,3 +129,119 @@ func (s *S) TestCheckIsBlocked(c *check.C) {
 		}
 	}
 }
+
+func (s *S) TestListBlocksDeactivatesExpired(c *check.C) {
+	expired := &Block{KindName: "app.deploy", Reason: "maintenance", ExpiresAt: time.Now().Add(-time.Minute)}
+	err := AddBlock(expired)
+	c.Assert(err, check.IsNil)
+	valid := &Block{KindName: "app.create", Reason: "maintenance", ExpiresAt: time.Now().Add(time.Hour)}
+	err = AddBlock(valid)
+	c.Assert(err, check.IsNil)
+	active := true
+	blocks, err := ListBlocks(&active)
+	c.Assert(err, check.IsNil)
+	c.Assert(blocks, check.HasLen, 1)
+	c.Assert(blocks[0].ID, check.Equals, valid.ID)
+	inactive := false
+	blocks, err = ListBlocks(&inactive)
+	c.Assert(err, check.IsNil)
+	c.Assert(blocks, check.HasLen, 1)
+	c.Assert(blocks[0].ID, check.Equals, expired.ID)
+	c.Assert(blocks[0].EndTime.Unix(), check.Equals, expired.ExpiresAt.Unix())
+}
+
+func (s *S) TestCheckIsBlockedIgnoresExpired(c *check.C) {
+	err := AddBlock(&Block{KindName: "app.deploy", Reason: "maintenance", ExpiresAt: time.Now().Add(-time.Minute)})
+	c.Assert(err, check.IsNil)
+	err = checkIsBlocked(&Event{eventData: eventData{Kind: Kind{Name: "app.deploy"}}})
+	c.Assert(err, check.IsNil)
+	block := &Block{KindName: "app.deploy", Reason: "maintenance", ExpiresAt: time.Now().Add(time.Hour)}
+	err = AddBlock(block)
+	c.Assert(err, check.IsNil)
+	err = checkIsBlocked(&Event{eventData: eventData{Kind: Kind{Name: "app.deploy"}}})
+	c.Assert(err, check.FitsTypeOf, ErrEventBlocked{})
+	c.Assert(err.(ErrEventBlocked).block.ID, check.Equals, block.ID)
+}
+
+func (s *S) TestBlockStringWithExpiration(c *check.C) {
+	expiresAt := time.Date(2022, 5, 10, 12, 0, 0, 0, time.UTC)
+	b := Block{KindName: "app.deploy", Reason: "maintenance", ExpiresAt: expiresAt}
+	c.Assert(b.String(), check.Equals, "block app.deploy by all users on all targets: maintenance (expires at 2022-05-10T12:00:00Z)")
+}
+
+func (s *S) TestCheckIsBlockedByTeamPoolAndTags(c *check.C) {
+	oldAppService := servicemanager.App
+	defer func() { servicemanager.App = oldAppService }()
+	servicemanager.App = &appTypes.MockAppService{
+		Apps: []appTypes.App{
+			&appTypes.MockApp{Name: "prod-app", TeamOwner: "team1", Pool: "prod", Tags: []string{"critical", "web"}},
+			&appTypes.MockApp{Name: "dev-app", TeamOwner: "team1", Pool: "dev", Tags: []string{"web"}},
+			&appTypes.MockApp{Name: "other-app", TeamOwner: "team2", Pool: "dev"},
+		},
+	}
+	blocks := map[string]*Block{
+		"blockProdDeploys": {KindName: "app.deploy", PoolName: "prod"},
+		"blockTeam2":       {TeamName: "team2"},
+		"blockCritical":    {KindName: "app.update", Tags: []string{"critical", "web"}},
+	}
+	for _, b := range blocks {
+		err := AddBlock(b)
+		c.Assert(err, check.IsNil)
+	}
+	tt := []struct {
+		event     *Event
+		blockedBy *Block
+	}{
+		{&Event{eventData: eventData{Kind: Kind{Name: "app.deploy"}, Target: Target{Type: TargetTypeApp, Value: "prod-app"}}}, blocks["blockProdDeploys"]},
+		{&Event{eventData: eventData{Kind: Kind{Name: "app.deploy"}, Target: Target{Type: TargetTypeApp, Value: "dev-app"}}}, nil},
+		{&Event{eventData: eventData{Kind: Kind{Name: "pool.update"}, Target: Target{Type: TargetTypePool, Value: "prod"}}}, nil},
+		{&Event{eventData: eventData{Kind: Kind{Name: "app.deploy"}, Target: Target{Type: TargetTypeApp, Value: "other-app"}}}, blocks["blockTeam2"]},
+		{&Event{eventData: eventData{Kind: Kind{Name: "team.update"}, Target: Target{Type: TargetTypeTeam, Value: "team2"}}}, blocks["blockTeam2"]},
+		{&Event{eventData: eventData{Kind: Kind{Name: "app.update.env.set"}, Target: Target{Type: TargetTypeApp, Value: "prod-app"}}}, blocks["blockCritical"]},
+		{&Event{eventData: eventData{Kind: Kind{Name: "app.update.env.set"}, Target: Target{Type: TargetTypeApp, Value: "dev-app"}}}, nil},
+		{&Event{eventData: eventData{Kind: Kind{Name: "app.deploy"}, Target: Target{Type: TargetTypeApp, Value: "unknown-app"}}}, nil},
+		{&Event{eventData: eventData{
+			Kind:         Kind{Name: "app.update.bind"},
+			Target:       Target{Type: TargetTypeServiceInstance, Value: "mysql/db"},
+			ExtraTargets: []ExtraTarget{{Target: Target{Type: TargetTypeApp, Value: "other-app"}}},
+		}}, blocks["blockTeam2"]},
+	}
+	for i, t := range tt {
+		errBlock := checkIsBlocked(t.event)
+		if t.blockedBy == nil {
+			c.Check(errBlock, check.IsNil, check.Commentf("(%d)", i))
+			continue
+		}
+		c.Assert(errBlock, check.FitsTypeOf, ErrEventBlocked{}, check.Commentf("(%d)", i))
+		c.Check(errBlock.(ErrEventBlocked).block.ID, check.Equals, t.blockedBy.ID, check.Commentf("(%d)", i))
+	}
+}
+
+func (s *S) TestBlockStringWithScope(c *check.C) {
+	b := Block{KindName: "app.deploy", Reason: "incident", TeamName: "team1", PoolName: "prod", Tags: []string{"critical"}}
+	c.Assert(b.String(), check.Equals, `block app.deploy by all users on all targets in team "team1", pool "prod", tags ["critical"]: incident`)
+}
+
+func (s *S) TestBlockBlocksWithKindNames(c *check.C) {
+	b := Block{
+		KindNames: []string{"app.deploy", "app.update.env.set"},
+		Target:    Target{Type: TargetTypeApp, Value: "frozen-app"},
+	}
+	tt := []struct {
+		kind    string
+		target  string
+		blocked bool
+	}{
+		{"app.deploy", "frozen-app", true},
+		{"app.deploy.rollback", "frozen-app", true},
+		{"app.update.env.set", "frozen-app", true},
+		{"app.update.env.unset", "frozen-app", false},
+		{"app.update.description", "frozen-app", false},
+		{"app.deploy", "other-app", false},
+	}
+	for _, t := range tt {
+		evt := &Event{eventData: eventData{Kind: Kind{Name: t.kind}, Target: Target{Type: TargetTypeApp, Value: t.target}}}
+		c.Check(b.Blocks(evt), check.Equals, t.blocked, check.Commentf("kind %q, target %q", t.kind, t.target))
+	}
+	c.Assert(b.String(), check.Equals, "block app.deploy, app.update.env.set by all users on app(frozen-app): ")
+}
@@ -0,0 +1,112 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+
+	"github.com/globalsign/mgo/bson"
+	eventTypes "github.com/tsuru/tsuru/types/event"
+)
+
+// Changes holds normalized snapshots of the event target before and after
+// the update performed by the event.
+type Changes = eventTypes.Changes
+
+// FieldChange describes a field whose value differs between the snapshots
+// stored in the event. Nested fields are named using dots.
+type FieldChange struct {
+	Field  string      `json:"field"`
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// SetChanges stores snapshots of the event target before and after the
+// update. Both values are normalized using their JSON representation.
+func (e *Event) SetChanges(before, after interface{}) error {
+	var changes Changes
+	var err error
+	changes.Before, err = normalizeSnapshot(before)
+	if err != nil {
+		return err
+	}
+	changes.After, err = normalizeSnapshot(after)
+	if err != nil {
+		return err
+	}
+	store, err := eventStorage()
+	if err != nil {
+		return err
+	}
+	err = store.SetChanges(context.TODO(), e.ID, changes)
+	if err != nil {
+		return err
+	}
+	e.Changes = &changes
+	return nil
+}
+
+// Diff returns the fields changed by the event, sorted by name. It returns
+// nil if no changes were stored in the event.
+func (e *Event) Diff() []FieldChange {
+	if e.Changes == nil {
+		return nil
+	}
+	before := map[string]interface{}{}
+	flattenSnapshot("", e.Changes.Before, before)
+	after := map[string]interface{}{}
+	flattenSnapshot("", e.Changes.After, after)
+	var diff []FieldChange
+	for field, value := range before {
+		if afterValue, ok := after[field]; !ok || !reflect.DeepEqual(value, afterValue) {
+			diff = append(diff, FieldChange{Field: field, Before: value, After: afterValue})
+		}
+	}
+	for field, value := range after {
+		if _, ok := before[field]; !ok {
+			diff = append(diff, FieldChange{Field: field, After: value})
+		}
+	}
+	sort.Slice(diff, func(i, j int) bool {
+		return diff[i].Field < diff[j].Field
+	})
+	return diff
+}
+
+func normalizeSnapshot(value interface{}) (map[string]interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var snapshot map[string]interface{}
+	err = json.Unmarshal(data, &snapshot)
+	if err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+func flattenSnapshot(prefix string, value map[string]interface{}, dst map[string]interface{}) {
+	for k, v := range value {
+		field := k
+		if prefix != "" {
+			field = prefix + "." + k
+		}
+		switch nested := v.(type) {
+		case map[string]interface{}:
+			flattenSnapshot(field, nested, dst)
+		case bson.M:
+			flattenSnapshot(field, nested, dst)
+		default:
+			dst[field] = v
+		}
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package event
+
+import (
+	"github.com/tsuru/tsuru/permission"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestEventSetChangesAndDiff(c *check.C) {
+	evt, err := New(&Opts{
+		Target:  Target{Type: "pool", Value: "mypool"},
+		Kind:    permission.PermPoolUpdate,
+		Owner:   s.token,
+		Allowed: Allowed(permission.PermPoolReadEvents),
+	})
+	c.Assert(err, check.IsNil)
+	type snapshot struct {
+		Name    string            `json:"name"`
+		Default bool              `json:"default"`
+		Labels  map[string]string `json:"labels"`
+		Teams   []string          `json:"teams"`
+	}
+	err = evt.SetChanges(
+		snapshot{Name: "mypool", Labels: map[string]string{"a": "1", "b": "2"}, Teams: []string{"t1"}},
+		snapshot{Name: "mypool", Default: true, Labels: map[string]string{"a": "1", "c": "3"}, Teams: []string{"t1"}},
+	)
+	c.Assert(err, check.IsNil)
+	expected := []FieldChange{
+		{Field: "default", Before: false, After: true},
+		{Field: "labels.b", Before: "2"},
+		{Field: "labels.c", After: "3"},
+	}
+	c.Assert(evt.Diff(), check.DeepEquals, expected)
+	err = evt.Done(nil)
+	c.Assert(err, check.IsNil)
+	dbEvt, err := GetByID(evt.UniqueID)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbEvt.Diff(), check.DeepEquals, expected)
+}
+
+func (s *S) TestEventDiffWithoutChanges(c *check.C) {
+	evt, err := New(&Opts{
+		Target:  Target{Type: "pool", Value: "mypool"},
+		Kind:    permission.PermPoolUpdate,
+		Owner:   s.token,
+		Allowed: Allowed(permission.PermPoolReadEvents),
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(evt.Diff(), check.IsNil)
+}
@@ -12,25 +12,26 @@ import (
 	"net"
 	"net/url"
 	"reflect"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
-	"github.com/globalsign/mgo"
 	"github.com/globalsign/mgo/bson"
+	"github.com/opentracing/opentracing-go"
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/tsuru/config"
 	"github.com/tsuru/tsuru/auth"
 	internalConfig "github.com/tsuru/tsuru/config"
 	"github.com/tsuru/tsuru/db"
-	"github.com/tsuru/tsuru/db/storage"
 	"github.com/tsuru/tsuru/log"
 	"github.com/tsuru/tsuru/permission"
 	"github.com/tsuru/tsuru/servicemanager"
+	"github.com/tsuru/tsuru/storage"
 	authTypes "github.com/tsuru/tsuru/types/auth"
+	eventTypes "github.com/tsuru/tsuru/types/event"
 	permTypes "github.com/tsuru/tsuru/types/permission"
-	"github.com/tsuru/tsuru/types/tracker"
 )
 
 var (
@@ -38,7 +39,17 @@ var (
 		Name:    "tsuru_events_duration_seconds",
 		Help:    "The duration of events in seconds",
 		Buckets: []float64{1, 5, 10, 60, 600, 1800},
-	}, []string{"kind"})
+	}, []string{"kind", "target_type", "success"})
+
+	eventsStarted = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "tsuru_events_started_total",
+		Help: "The total number of events started",
+	}, []string{"kind", "target_type"})
+
+	eventsFailed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "tsuru_events_failed_total",
+		Help: "The total number of events finished with error",
+	}, []string{"kind", "target_type"})
 
 	eventCurrent = prometheus.NewGaugeVec(prometheus.GaugeOpts{
 		Name: "tsuru_events_current",
@@ -67,12 +78,11 @@ const (
 )
 
 var (
-	throttlingInfo  = map[string]ThrottlingSpec{}
-	errInvalidQuery = errors.New("invalid query")
+	throttlingInfo = map[string]ThrottlingSpec{}
 
 	ErrNotCancelable          = errors.New("event is not cancelable")
 	ErrCancelAlreadyRequested = errors.New("event cancel already requested")
-	ErrEventNotFound          = errors.New("event not found")
+	ErrEventNotFound          = eventTypes.ErrEventNotFound
 	ErrNoTarget               = ErrValidation("event target is mandatory")
 	ErrNoKind                 = ErrValidation("event kind is mandatory")
 	ErrNoOwner                = ErrValidation("event owner is mandatory")
@@ -83,11 +93,16 @@ var (
 	ErrInvalidOwner           = ErrValidation("event owner must not be set on internal events")
 	ErrInvalidKind            = ErrValidation("event kind must not be set on internal events")
 	ErrInvalidTargetType      = errors.New("invalid event target type")
+	ErrInvalidCursor          = ErrValidation("event cursor must be a valid event id")
+	ErrCursorWithSkipOrSort   = ErrValidation("event cursor cannot be combined with skip or sort")
 
-	OwnerTypeUser     = ownerType("user")
-	OwnerTypeApp      = ownerType("app")
-	OwnerTypeInternal = ownerType("internal")
-	OwnerTypeToken    = ownerType("token")
+	OwnerTypeUser        = ownerType("user")
+	OwnerTypeApp         = ownerType("app")
+	OwnerTypeInternal    = ownerType("internal")
+	OwnerTypeToken       = ownerType("token")
+	OwnerTypeRemovedUser = ownerType("removed-user")
+
+	OwnerTypeServiceAccount = ownerType("service-account")
 
 	KindTypePermission = kindType("permission")
 	KindTypeInternal   = kindType("internal")
@@ -104,6 +119,7 @@ var (
 	TargetTypeUser            = TargetType("user")
 	TargetTypeIaas            = TargetType("iaas")
 	TargetTypeRole            = TargetType("role")
+	TargetTypeRoleTemplate    = TargetType("role-template")
 	TargetTypePlatform        = TargetType("platform")
 	TargetTypePlan            = TargetType("plan")
 	TargetTypeNodeContainer   = TargetType("node-container")
@@ -114,6 +130,7 @@ var (
 	TargetTypeWebhook         = TargetType("webhook")
 	TargetTypeGC              = TargetType("gc")
 	TargetTypeRouter          = TargetType("router")
+	TargetTypeJob             = TargetType("job")
 )
 
 const (
@@ -121,13 +138,14 @@ const (
 )
 
 func init() {
-	prometheus.MustRegister(eventDuration, eventCurrent, eventsRejected)
+	prometheus.MustRegister(eventDuration, eventCurrent, eventsRejected, eventsStarted, eventsFailed)
 }
 
 type ErrThrottled struct {
 	Spec       *ThrottlingSpec
 	Target     Target
 	AllTargets bool
+	Owner      *Owner
 }
 
 func (err ErrThrottled) Error() string {
@@ -141,6 +159,9 @@ func (err ErrThrottled) Error() string {
 	} else {
 		extraTarget = fmt.Sprintf("%s %q", err.Target.Type, err.Target.Value)
 	}
+	if err.Owner != nil {
+		extraTarget = fmt.Sprintf("%s by %s", extraTarget, err.Owner)
+	}
 	return fmt.Sprintf("event throttled, limit for%s %s is %d every %v", extra, extraTarget, err.Spec.Max, err.Spec.Time)
 }
 
@@ -156,90 +177,26 @@ func (err ErrEventLocked) Error() string {
 	return fmt.Sprintf("event locked: %v", err.Event)
 }
 
-type Target struct {
-	Type  TargetType
-	Value string
-}
-
-func (id Target) GetBSON() (interface{}, error) {
-	return bson.D{{Name: "type", Value: id.Type}, {Name: "value", Value: id.Value}}, nil
-}
-
-func (id Target) IsValid() bool {
-	return id.Type != ""
-}
-
-func (id Target) String() string {
-	return fmt.Sprintf("%s(%s)", id.Type, id.Value)
-}
-
-type eventID struct {
-	Target Target
-	ObjId  bson.ObjectId
-}
-
-func (id *eventID) SetBSON(raw bson.Raw) error {
-	err := raw.Unmarshal(&id.Target)
-	if err != nil {
-		return raw.Unmarshal(&id.ObjId)
-	}
-	return nil
-}
-
-func (id eventID) GetBSON() (interface{}, error) {
-	if len(id.ObjId) != 0 {
-		return id.ObjId, nil
-	}
-	return id.Target.GetBSON()
-}
+type (
+	Target            = eventTypes.Target
+	TargetType        = eventTypes.TargetType
+	ExtraTarget       = eventTypes.ExtraTarget
+	Owner             = eventTypes.Owner
+	Kind              = eventTypes.Kind
+	AllowedPermission = eventTypes.AllowedPermission
+	LogEntry          = eventTypes.LogEntry
+	TargetFilter      = eventTypes.TargetFilter
+
+	ownerType  = eventTypes.OwnerType
+	kindType   = eventTypes.KindType
+	eventID    = eventTypes.EventID
+	cancelInfo = eventTypes.CancelInfo
+)
 
 // This private type allow us to export the main Event struct without allowing
 // access to its public fields. (They have to be public for database
 // serializing).
-type eventData struct {
-	ID              eventID `bson:"_id"`
-	UniqueID        bson.ObjectId
-	StartTime       time.Time
-	EndTime         time.Time     `bson:",omitempty"`
-	Target          Target        `bson:",omitempty"`
-	ExtraTargets    []ExtraTarget `bson:",omitempty"`
-	StartCustomData bson.Raw      `bson:",omitempty"`
-	EndCustomData   bson.Raw      `bson:",omitempty"`
-	OtherCustomData bson.Raw      `bson:",omitempty"`
-	Kind            Kind
-	Owner           Owner
-	SourceIP        string
-	LockUpdateTime  time.Time
-	Error           string
-	Log             string     `bson:",omitempty"`
-	StructuredLog   []LogEntry `bson:",omitempty"`
-	CancelInfo      cancelInfo
-	Cancelable      bool
-	Running         bool
-	Allowed         AllowedPermission
-	AllowedCancel   AllowedPermission
-	Instance        tracker.TrackedInstance
-}
-
-type LogEntry struct {
-	Date    time.Time
-	Message string
-}
-
-type cancelInfo struct {
-	Owner     string
-	StartTime time.Time
-	AckTime   time.Time
-	Reason    string
-	Asked     bool
-	Canceled  bool
-}
-
-type ownerType string
-
-type kindType string
-
-type TargetType string
+type eventData = eventTypes.EventData
 
 func GetTargetType(t string) (TargetType, error) {
 	switch t {
@@ -265,6 +222,8 @@ func GetTargetType(t string) (TargetType, error) {
 		return TargetTypeIaas, nil
 	case "role":
 		return TargetTypeRole, nil
+	case "role-template":
+		return TargetTypeRoleTemplate, nil
 	case "platform":
 		return TargetTypePlatform, nil
 	case "plan":
@@ -283,28 +242,12 @@ func GetTargetType(t string) (TargetType, error) {
 		return TargetTypeWebhook, nil
 	case "router":
 		return TargetTypeRouter, nil
+	case "job":
+		return TargetTypeJob, nil
 	}
 	return TargetType(""), ErrInvalidTargetType
 }
 
-type Owner struct {
-	Type ownerType
-	Name string
-}
-
-type Kind struct {
-	Type kindType
-	Name string
-}
-
-func (o Owner) String() string {
-	return fmt.Sprintf("%s %s", o.Type, o.Name)
-}
-
-func (k Kind) String() string {
-	return k.Name
-}
-
 type ThrottlingSpec struct {
 	TargetType TargetType    `json:"target-type"`
 	KindName   string        `json:"kind-name"`
@@ -312,6 +255,7 @@ type ThrottlingSpec struct {
 	Time       time.Duration `json:"window"`
 	AllTargets bool          `json:"all-targets"`
 	WaitFinish bool          `json:"wait-finish"`
+	PerOwner   bool          `json:"per-owner"`
 }
 
 func (d *ThrottlingSpec) UnmarshalJSON(data []byte) error {
@@ -326,7 +270,7 @@ func (d *ThrottlingSpec) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
-func throttlingKey(targetType TargetType, kindName string, allTargets bool) string {
+func throttlingKey(targetType TargetType, kindName string, allTargets, perOwner bool) string {
 	key := string(targetType)
 	if kindName != "" {
 		key = fmt.Sprintf("%s_%s", key, kindName)
@@ -334,6 +278,9 @@ func throttlingKey(targetType TargetType, kindName string, allTargets bool) stri
 	if allTargets {
 		key = fmt.Sprintf("%s_%s", key, "global")
 	}
+	if perOwner {
+		key = fmt.Sprintf("%s_%s", key, "owner")
+	}
 	return key
 }
 
@@ -362,14 +309,14 @@ func loadThrottling() error {
 }
 
 func SetThrottling(spec ThrottlingSpec) {
-	key := throttlingKey(spec.TargetType, spec.KindName, spec.AllTargets)
+	key := throttlingKey(spec.TargetType, spec.KindName, spec.AllTargets, spec.PerOwner)
 	throttlingInfo[key] = spec
 }
 
-func getThrottling(t *Target, k *Kind, allTargets bool) *ThrottlingSpec {
+func getThrottling(t *Target, k *Kind, allTargets, perOwner bool) *ThrottlingSpec {
 	keys := []string{
-		throttlingKey(t.Type, k.Name, allTargets),
-		throttlingKey(t.Type, "", allTargets),
+		throttlingKey(t.Type, k.Name, allTargets, perOwner),
+		throttlingKey(t.Type, "", allTargets, perOwner),
 	}
 	for _, key := range keys {
 		if s, ok := throttlingInfo[key]; ok {
@@ -383,11 +330,7 @@ type Event struct {
 	eventData
 	logMu     sync.Mutex
 	logWriter io.Writer
-}
-
-type ExtraTarget struct {
-	Target Target
-	Lock   bool
+	span      opentracing.Span
 }
 
 type Opts struct {
@@ -404,6 +347,12 @@ type Opts struct {
 	Allowed       AllowedPermission
 	AllowedCancel AllowedPermission
 	RetryTimeout  time.Duration
+	// ParentID is the unique ID of the event which spawned this one.
+	// Cancelling the parent event also cancels its running children.
+	ParentID bson.ObjectId
+	// Context is used as parent of the tracing span covering the event
+	// lifecycle, usually the context of the request creating the event.
+	Context context.Context
 }
 
 func Allowed(scheme *permission.PermissionScheme, contexts ...permTypes.PermissionContext) AllowedPermission {
@@ -413,25 +362,6 @@ func Allowed(scheme *permission.PermissionScheme, contexts ...permTypes.Permissi
 	}
 }
 
-type AllowedPermission struct {
-	Scheme   string
-	Contexts []permTypes.PermissionContext `bson:",omitempty"`
-}
-
-func (ap *AllowedPermission) GetBSON() (interface{}, error) {
-	var ctxs []bson.D
-	for _, ctx := range ap.Contexts {
-		ctxs = append(ctxs, bson.D{
-			{Name: "ctxtype", Value: ctx.CtxType},
-			{Name: "value", Value: ctx.Value},
-		})
-	}
-	return bson.M{
-		"scheme":   ap.Scheme,
-		"contexts": ctxs,
-	}, nil
-}
-
 func (e *Event) String() string {
 	return fmt.Sprintf("%s(%s) running %q start by %s at %s",
 		e.Target.Type,
@@ -442,32 +372,37 @@ func (e *Event) String() string {
 	)
 }
 
-type TargetFilter struct {
-	Type   TargetType
-	Values []string
-}
-
 type Filter struct {
-	Target         Target
-	KindType       kindType
-	KindNames      []string `form:"-"`
-	OwnerType      ownerType
-	OwnerName      string
-	Since          time.Time
-	Until          time.Time
-	Running        *bool
-	ErrorOnly      bool
-	Raw            bson.M
+	Target    Target
+	KindType  kindType
+	KindNames []string `form:"-"`
+	OwnerType ownerType
+	OwnerName string
+	Since     time.Time
+	Until     time.Time
+	Running   *bool
+	ErrorOnly bool
+	// Search performs a full-text search over the event target value, kind
+	// and owner name.
+	Search         string
+	Cancelable     *bool
+	UniqueIDs      []bson.ObjectId
 	AllowedTargets []TargetFilter
 	Permissions    []permission.Permission
+	// Impersonator filters the events of the admin acting as other users.
+	Impersonator string
 
 	Limit int
 	Skip  int
 	Sort  string
+	// After is the unique ID of the last event previously returned, only
+	// events older than it are listed. It may not be combined with Skip or
+	// Sort.
+	After string
 }
 
 func (f *Filter) PruneUserValues() {
-	f.Raw = nil
+	f.UniqueIDs = nil
 	f.AllowedTargets = nil
 	f.Permissions = nil
 	if f.Limit > filterMaxLimit || f.Limit <= 0 {
@@ -488,119 +423,72 @@ func (f *Filter) LoadKindNames(form map[string][]string) {
 	}
 }
 
-func (f *Filter) toQuery() (bson.M, error) {
-	query := bson.M{}
-	permMap := map[string][]permTypes.PermissionContext{}
-	andBlock := []bson.M{}
+// toStorage returns the filter used to find the events in the storage.
+// Permissions are grouped by scheme, a global context allows events in any
+// context of the scheme.
+func (f *Filter) toStorage() *eventTypes.EventFilter {
+	filter := &eventTypes.EventFilter{
+		Target:         f.Target,
+		KindType:       f.KindType,
+		KindNames:      f.KindNames,
+		OwnerType:      f.OwnerType,
+		OwnerName:      f.OwnerName,
+		Since:          f.Since,
+		Until:          f.Until,
+		Running:        f.Running,
+		Cancelable:     f.Cancelable,
+		ErrorOnly:      f.ErrorOnly,
+		Search:         f.Search,
+		AllowedTargets: f.AllowedTargets,
+		Impersonator:   f.Impersonator,
+		UniqueIDs:      f.UniqueIDs,
+		Limit:          f.Limit,
+		Skip:           f.Skip,
+		Sort:           f.Sort,
+	}
 	if f.Permissions != nil {
+		filter.Permissions = []AllowedPermission{}
+		var schemes []string
+		permMap := map[string][]permTypes.PermissionContext{}
+		global := map[string]bool{}
 		for _, p := range f.Permissions {
-			permMap[p.Scheme.FullName()] = append(permMap[p.Scheme.FullName()], p.Context)
-		}
-		var permOrBlock []bson.M
-		for perm, ctxs := range permMap {
-			ctxsBson := []bson.D{}
-			for _, ctx := range ctxs {
-				if ctx.CtxType == permTypes.CtxGlobal {
-					ctxsBson = nil
-					break
-				}
-				ctxsBson = append(ctxsBson, bson.D{
-					{Name: "ctxtype", Value: ctx.CtxType},
-					{Name: "value", Value: ctx.Value},
-				})
-			}
-			toAppend := bson.M{
-				"allowed.scheme": bson.M{"$regex": "^" + strings.Replace(perm, ".", `\.`, -1)},
+			name := p.Scheme.FullName()
+			if _, ok := permMap[name]; !ok {
+				schemes = append(schemes, name)
 			}
-			if ctxsBson != nil {
-				toAppend["allowed.contexts"] = bson.M{"$in": ctxsBson}
+			permMap[name] = append(permMap[name], p.Context)
+			if p.Context.CtxType == permTypes.CtxGlobal {
+				global[name] = true
 			}
-			permOrBlock = append(permOrBlock, toAppend)
 		}
-		andBlock = append(andBlock, bson.M{"$or": permOrBlock})
-	}
-	if f.AllowedTargets != nil {
-		var orBlock []bson.M
-		for _, at := range f.AllowedTargets {
-			f := bson.M{"target.type": at.Type}
-			extraF := bson.M{"extratargets.target.type": at.Type}
-			if at.Values != nil {
-				f["target.value"] = bson.M{"$in": at.Values}
-				extraF["extratargets.target.value"] = bson.M{"$in": at.Values}
+		for _, name := range schemes {
+			perm := AllowedPermission{Scheme: name}
+			if !global[name] {
+				perm.Contexts = permMap[name]
 			}
-			orBlock = append(orBlock, f, extraF)
-		}
-		if len(orBlock) == 0 {
-			return nil, errInvalidQuery
+			filter.Permissions = append(filter.Permissions, perm)
 		}
-		andBlock = append(andBlock, bson.M{"$or": orBlock})
 	}
-	if f.Target.Type != "" {
-		orBlock := []bson.M{
-			{"target.type": f.Target.Type},
-			{"extratargets.target.type": f.Target.Type},
-		}
-		andBlock = append(andBlock, bson.M{"$or": orBlock})
-	}
-	if f.Target.Value != "" {
-		orBlock := []bson.M{
-			{"target.value": f.Target.Value},
-			{"extratargets.target.value": f.Target.Value},
-		}
-		andBlock = append(andBlock, bson.M{"$or": orBlock})
-	}
-	if f.KindType != "" {
-		query["kind.type"] = f.KindType
-	}
-	if len(f.KindNames) > 0 {
-		query["kind.name"] = bson.M{"$in": f.KindNames}
-	}
-	if f.OwnerType != "" {
-		query["owner.type"] = f.OwnerType
-	}
-	if f.OwnerName != "" {
-		query["owner.name"] = f.OwnerName
-	}
-	var timeParts []bson.M
-	if !f.Since.IsZero() {
-		timeParts = append(timeParts, bson.M{"starttime": bson.M{"$gte": f.Since}})
-	}
-	if !f.Until.IsZero() {
-		timeParts = append(timeParts, bson.M{"starttime": bson.M{"$lte": f.Until}})
-	}
-	if len(timeParts) != 0 {
-		andBlock = append(andBlock, timeParts...)
-	}
-	if len(andBlock) > 0 {
-		query["$and"] = andBlock
-	}
-	if f.Running != nil {
-		query["running"] = *f.Running
-	}
-	if f.ErrorOnly {
-		query["error"] = bson.M{"$ne": ""}
-	}
-	if f.Raw != nil {
-		for k, v := range f.Raw {
-			query[k] = v
+	return filter
+}
+
+func eventStorage() (eventTypes.EventStorage, error) {
+	dbDriver, err := storage.GetCurrentDbDriver()
+	if err != nil {
+		dbDriver, err = storage.GetDefaultDbDriver()
+		if err != nil {
+			return nil, err
 		}
 	}
-	return query, nil
+	return dbDriver.EventStorage, nil
 }
 
 func GetKinds() ([]Kind, error) {
-	conn, err := db.Conn()
+	store, err := eventStorage()
 	if err != nil {
 		return nil, err
 	}
-	defer conn.Close()
-	coll := conn.Events()
-	var kinds []Kind
-	err = coll.Find(nil).Distinct("kind", &kinds)
-	if err != nil {
-		return nil, err
-	}
-	return kinds, nil
+	return store.Kinds(context.TODO())
 }
 
 func transformEvent(data eventData) *Event {
@@ -611,26 +499,15 @@ func transformEvent(data eventData) *Event {
 }
 
 func GetRunning(target Target, kind string) (*Event, error) {
-	conn, err := db.Conn()
+	store, err := eventStorage()
 	if err != nil {
 		return nil, err
 	}
-	defer conn.Close()
-	coll := conn.Events()
-	var evtData eventData
-	err = coll.Find(bson.M{
-		"_id":       eventID{Target: target},
-		"kind.name": kind,
-		"running":   true,
-	}).One(&evtData)
+	evtData, err := store.FindRunning(context.TODO(), target, kind)
 	if err != nil {
-		if err == mgo.ErrNotFound {
-			return nil, ErrEventNotFound
-		}
 		return nil, err
 	}
-	evt := transformEvent(evtData)
-	return evt, nil
+	return transformEvent(*evtData), nil
 }
 
 func GetByHexID(hexid string) (*Event, error) {
@@ -641,24 +518,15 @@ func GetByHexID(hexid string) (*Event, error) {
 }
 
 func GetByID(id bson.ObjectId) (*Event, error) {
-	conn, err := db.Conn()
+	store, err := eventStorage()
 	if err != nil {
 		return nil, err
 	}
-	defer conn.Close()
-	coll := conn.Events()
-	var evtData eventData
-	err = coll.Find(bson.M{
-		"uniqueid": id,
-	}).One(&evtData)
+	evtData, err := store.FindByUniqueID(context.TODO(), id)
 	if err != nil {
-		if err == mgo.ErrNotFound {
-			return nil, ErrEventNotFound
-		}
 		return nil, err
 	}
-	evt := transformEvent(evtData)
-	return evt, nil
+	return transformEvent(*evtData), nil
 }
 
 func All() ([]*Event, error) {
@@ -666,45 +534,27 @@ func All() ([]*Event, error) {
 }
 
 func List(filter *Filter) ([]*Event, error) {
-	limit := 0
-	skip := 0
-	var query bson.M
-	var err error
-	sort := "-starttime"
+	store, err := eventStorage()
+	if err != nil {
+		return nil, err
+	}
+	var storageFilter *eventTypes.EventFilter
 	if filter != nil {
-		limit = filterMaxLimit
-		if filter.Limit != 0 {
-			limit = filter.Limit
+		if filter.After != "" && (filter.Skip > 0 || filter.Sort != "") {
+			return nil, ErrCursorWithSkipOrSort
 		}
-		if filter.Sort != "" {
-			sort = filter.Sort
+		storageFilter = filter.toStorage()
+		if storageFilter.Limit == 0 {
+			storageFilter.Limit = filterMaxLimit
 		}
-		if filter.Skip > 0 {
-			skip = filter.Skip
-		}
-		query, err = filter.toQuery()
-		if err != nil {
-			if err == errInvalidQuery {
-				return nil, nil
+		if filter.After != "" {
+			storageFilter.After, err = eventCursor(store, filter.After)
+			if err != nil {
+				return nil, err
 			}
-			return nil, err
 		}
 	}
-	conn, err := db.Conn()
-	if err != nil {
-		return nil, err
-	}
-	defer conn.Close()
-	coll := conn.Events()
-	find := coll.Find(query).Sort(sort)
-	if limit > 0 {
-		find = find.Limit(limit)
-	}
-	if skip > 0 {
-		find = find.Skip(skip)
-	}
-	var allData []eventData
-	err = find.All(&allData)
+	allData, err := store.FindAll(context.TODO(), storageFilter)
 	if err != nil {
 		return nil, err
 	}
@@ -715,6 +565,22 @@ func List(filter *Filter) ([]*Event, error) {
 	return evts, nil
 }
 
+// eventCursor returns the cursor listing events after the event with the
+// given unique ID, according to the default event list order.
+func eventCursor(store eventTypes.EventStorage, after string) (*eventTypes.EventCursor, error) {
+	if !bson.IsObjectIdHex(after) {
+		return nil, ErrInvalidCursor
+	}
+	last, err := store.FindByUniqueID(context.TODO(), bson.ObjectIdHex(after))
+	if err != nil {
+		if err == ErrEventNotFound {
+			return nil, ErrInvalidCursor
+		}
+		return nil, err
+	}
+	return &eventTypes.EventCursor{StartTime: last.StartTime, UniqueID: last.UniqueID}, nil
+}
+
 func New(opts *Opts) (*Event, error) {
 	if opts == nil {
 		return nil, ErrNoOpts
@@ -761,6 +627,22 @@ func NewInternalMany(targets []Target, opts *Opts) (*Event, error) {
 	return NewInternal(opts)
 }
 
+// NewSynthetic returns a finished event which is never stored, useful to
+// validate receivers of event notifications without running real actions.
+func NewSynthetic(target Target, kindName string, owner Owner) *Event {
+	now := time.Now().UTC()
+	uniqID := bson.NewObjectId()
+	return &Event{eventData: eventData{
+		ID:        eventID{ObjId: uniqID},
+		UniqueID:  uniqID,
+		Target:    target,
+		StartTime: now,
+		EndTime:   now,
+		Kind:      Kind{Type: KindTypeInternal, Name: kindName},
+		Owner:     owner,
+	}}
+}
+
 func makeBSONRaw(in interface{}) (bson.Raw, error) {
 	if in == nil {
 		return bson.Raw{}, nil
@@ -794,39 +676,34 @@ func makeBSONRaw(in interface{}) (bson.Raw, error) {
 	}, nil
 }
 
-func checkThrottling(coll *storage.Collection, target *Target, kind *Kind, allTargets bool) error {
-	tSpec := getThrottling(target, kind, allTargets)
+// checkThrottling returns ErrThrottled if the throttling spec matching the
+// target and kind was already reached. When owner is not nil only specs
+// limiting events per owner are considered and only events started by the
+// same owner are counted.
+func checkThrottling(store eventTypes.EventStorage, target *Target, kind *Kind, owner *Owner, allTargets bool) error {
+	tSpec := getThrottling(target, kind, allTargets, owner != nil)
 	if tSpec == nil || tSpec.Max <= 0 || tSpec.Time <= 0 {
 		return nil
 	}
-	query := bson.M{
-		"target.type": target.Type,
-	}
 	now := time.Now().UTC()
-	startTimeQuery := bson.M{"$gt": now.Add(-tSpec.Time)}
+	filter := eventTypes.ThrottlingFilter{
+		TargetType: target.Type,
+		KindName:   tSpec.KindName,
+		Owner:      owner,
+		Since:      now.Add(-tSpec.Time),
+	}
 	if tSpec.WaitFinish {
-		query["$or"] = []bson.M{
-			{"starttime": startTimeQuery},
-			{
-				"running":        true,
-				"lockupdatetime": bson.M{"$gt": now.Add(-lockExpireTimeout)},
-			},
-		}
-	} else {
-		query["starttime"] = startTimeQuery
+		filter.RunningSince = now.Add(-lockExpireTimeout)
 	}
 	if !allTargets {
-		query["target.value"] = target.Value
+		filter.TargetValue = target.Value
 	}
-	if tSpec.KindName != "" {
-		query["kind.name"] = tSpec.KindName
-	}
-	c, err := coll.Find(query).Count()
+	c, err := store.CountThrottled(context.TODO(), filter)
 	if err != nil {
 		return err
 	}
 	if c >= tSpec.Max {
-		return ErrThrottled{Spec: tSpec, Target: *target, AllTargets: allTargets}
+		return ErrThrottled{Spec: tSpec, Target: *target, AllTargets: allTargets, Owner: owner}
 	}
 	return nil
 }
@@ -857,6 +734,9 @@ func newEvtOnce(opts *Opts) (evt *Event, err error) {
 	var k Kind
 	defer func() {
 		eventCurrent.WithLabelValues(k.Name).Inc()
+		if err == nil {
+			eventsStarted.WithLabelValues(k.Name, string(opts.Target.Type)).Inc()
+		}
 		if err != nil {
 			reason := "other"
 			switch err.(type) {
@@ -869,6 +749,10 @@ func newEvtOnce(opts *Opts) (evt *Event, err error) {
 			}
 			if !(reason == rejectBlocked) {
 				eventCurrent.WithLabelValues(k.Name).Dec()
+			} else {
+				// Blocked events are stored and finished with an error, so
+				// they're also accounted as started.
+				eventsStarted.WithLabelValues(k.Name, string(opts.Target.Type)).Inc()
 			}
 			eventsRejected.WithLabelValues(k.Name, reason).Inc()
 			return
@@ -898,6 +782,7 @@ func newEvtOnce(opts *Opts) (evt *Event, err error) {
 		k.Name = opts.Kind.FullName()
 	}
 	var o Owner
+	var impersonator string
 	if opts.Owner == nil {
 		if opts.RawOwner.Name != "" && opts.RawOwner.Type != "" {
 			o = opts.RawOwner
@@ -905,7 +790,10 @@ func newEvtOnce(opts *Opts) (evt *Event, err error) {
 			o.Type = OwnerTypeInternal
 		}
 	} else {
-		if token, ok := opts.Owner.(authTypes.NamedToken); ok {
+		if sa, ok := opts.Owner.(authTypes.ServiceAccountToken); ok && sa.GetServiceAccountName() != "" {
+			o.Type = OwnerTypeServiceAccount
+			o.Name = sa.GetServiceAccountName()
+		} else if token, ok := opts.Owner.(authTypes.NamedToken); ok {
 			o.Type = OwnerTypeToken
 			o.Name = token.GetTokenName()
 		} else if opts.Owner.IsAppToken() {
@@ -915,20 +803,25 @@ func newEvtOnce(opts *Opts) (evt *Event, err error) {
 			o.Type = OwnerTypeUser
 			o.Name = opts.Owner.GetUserName()
 		}
+		if it, ok := opts.Owner.(authTypes.ImpersonatedToken); ok {
+			impersonator = it.GetImpersonator()
+		}
 	}
-	conn, err := db.Conn()
-	if err != nil {
-		return nil, err
-	}
-	defer conn.Close()
-	coll := conn.Events()
-	err = checkThrottling(coll, &opts.Target, &k, false)
+	store, err := eventStorage()
 	if err != nil {
 		return nil, err
 	}
-	err = checkThrottling(coll, &opts.Target, &k, true)
-	if err != nil {
-		return nil, err
+	for _, allTargets := range []bool{false, true} {
+		err = checkThrottling(store, &opts.Target, &k, nil, allTargets)
+		if err != nil {
+			return nil, err
+		}
+		if o.Name != "" {
+			err = checkThrottling(store, &opts.Target, &k, &o, allTargets)
+			if err != nil {
+				return nil, err
+			}
+		}
 	}
 	now := time.Now().UTC()
 	raw, err := makeBSONRaw(opts.CustomData)
@@ -955,6 +848,7 @@ func newEvtOnce(opts *Opts) (evt *Event, err error) {
 	evt = &Event{eventData: eventData{
 		ID:              id,
 		UniqueID:        uniqID,
+		ParentID:        opts.ParentID,
 		ExtraTargets:    opts.ExtraTargets,
 		Target:          opts.Target,
 		StartTime:       now,
@@ -968,10 +862,11 @@ func newEvtOnce(opts *Opts) (evt *Event, err error) {
 		Allowed:         opts.Allowed,
 		AllowedCancel:   opts.AllowedCancel,
 		Instance:        instance,
+		Impersonator:    impersonator,
 	}}
 	maxRetries := 1
 	for i := 0; i < maxRetries+1; i++ {
-		err = coll.Insert(evt.eventData)
+		err = store.Insert(context.TODO(), &evt.eventData)
 		if err == nil {
 			err = checkLocked(evt, opts.DisableLock)
 			if err != nil {
@@ -984,17 +879,18 @@ func newEvtOnce(opts *Opts) (evt *Event, err error) {
 				return nil, err
 			}
 			updater.add(id)
+			evt.startSpan(opts.Context)
 			return evt, nil
 		}
-		if mgo.IsDup(err) {
-			if i >= maxRetries || !checkIsExpired(coll, evt.ID) {
-				var existing Event
-				err = coll.FindId(evt.ID).One(&existing.eventData)
-				if err == mgo.ErrNotFound {
+		if err == eventTypes.ErrEventIDInUse {
+			if i >= maxRetries || !checkIsExpired(store, evt.ID) {
+				var existing *eventData
+				existing, err = store.FindByID(context.TODO(), evt.ID)
+				if err == ErrEventNotFound {
 					maxRetries++
 				}
 				if err == nil {
-					err = ErrEventLocked{Event: &existing}
+					err = ErrEventLocked{Event: &Event{eventData: *existing}}
 				}
 			}
 		} else {
@@ -1004,6 +900,37 @@ func newEvtOnce(opts *Opts) (evt *Event, err error) {
 	return nil, err
 }
 
+func (e *Event) startSpan(ctx context.Context) {
+	var opts []opentracing.StartSpanOption
+	if ctx != nil {
+		if parent := opentracing.SpanFromContext(ctx); parent != nil {
+			opts = append(opts, opentracing.ChildOf(parent.Context()))
+		}
+	}
+	e.span = opentracing.StartSpan("Event "+e.Kind.Name, opts...)
+	e.span.SetTag("event.id", e.UniqueID.Hex())
+	e.span.SetTag("event.kind", e.Kind.Name)
+	e.span.SetTag("event.target.type", string(e.Target.Type))
+	e.span.SetTag("event.target.value", e.Target.Value)
+	e.span.SetTag("event.owner", e.Owner.String())
+}
+
+func (e *Event) finishSpan(doneErr error) {
+	if e.span == nil {
+		return
+	}
+	errMsg := e.Error
+	if errMsg == "" && doneErr != nil {
+		errMsg = doneErr.Error()
+	}
+	if errMsg != "" {
+		e.span.SetTag("error", true)
+		e.span.SetTag("error.object", errMsg)
+	}
+	e.span.Finish()
+	e.span = nil
+}
+
 func checkLocked(evt *Event, disableLock bool) error {
 	var targets []Target
 	if !disableLock {
@@ -1017,32 +944,18 @@ func checkLocked(evt *Event, disableLock bool) error {
 	if len(targets) == 0 {
 		return nil
 	}
-	var orBlock []bson.M
-	for _, t := range targets {
-		tBson, _ := t.GetBSON()
-		orBlock = append(orBlock, bson.M{"_id": tBson}, bson.M{
-			"extratargets": bson.M{"$elemMatch": bson.M{"target": tBson, "lock": true}},
-		})
-	}
-	conn, err := db.Conn()
+	store, err := eventStorage()
 	if err != nil {
 		return err
 	}
-	defer conn.Close()
-	coll := conn.Events()
-	var existing Event
-	err = coll.Find(bson.M{
-		"running":  true,
-		"uniqueid": bson.M{"$ne": evt.UniqueID},
-		"$or":      orBlock,
-	}).One(&existing.eventData)
+	existing, err := store.FindLocking(context.TODO(), targets, evt.UniqueID)
 	if err != nil {
-		if err == mgo.ErrNotFound {
+		if err == ErrEventNotFound {
 			return nil
 		}
 		return err
 	}
-	return ErrEventLocked{Event: &existing}
+	return ErrEventLocked{Event: &Event{eventData: *existing}}
 }
 
 func (e *Event) RawInsert(start, other, end interface{}) error {
@@ -1060,15 +973,13 @@ func (e *Event) RawInsert(start, other, end interface{}) error {
 	if err != nil {
 		return err
 	}
-	conn, err := db.Conn()
+	store, err := eventStorage()
 	if err != nil {
 		return err
 	}
-	defer conn.Close()
-	coll := conn.Events()
 	e.logMu.Lock()
 	defer e.logMu.Unlock()
-	return coll.Insert(e.eventData)
+	return store.Insert(context.TODO(), &e.eventData)
 }
 
 func (e *Event) Abort() error {
@@ -1092,15 +1003,11 @@ func (e *Event) GetLogWriter() io.Writer {
 }
 
 func (e *Event) SetOtherCustomData(data interface{}) error {
-	conn, err := db.Conn()
+	store, err := eventStorage()
 	if err != nil {
 		return err
 	}
-	defer conn.Close()
-	coll := conn.Events()
-	return coll.UpdateId(e.ID, bson.M{
-		"$set": bson.M{"othercustomdata": data},
-	})
+	return store.SetOtherCustomData(context.TODO(), e.ID, data)
 }
 
 func (e *Event) Logf(format string, params ...interface{}) {
@@ -1122,7 +1029,24 @@ func (e *Event) Write(data []byte) (int, error) {
 	return len(data), nil
 }
 
+// SaveLog stores the log written so far to a running event, allowing it to
+// be followed before the event is done.
+func (e *Event) SaveLog() error {
+	e.logMu.Lock()
+	entries := make([]LogEntry, len(e.StructuredLog))
+	copy(entries, e.StructuredLog)
+	e.logMu.Unlock()
+	store, err := eventStorage()
+	if err != nil {
+		return err
+	}
+	return store.SetStructuredLog(context.TODO(), e.ID, entries)
+}
+
 func (e *Event) CancelableContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if e != nil && e.span != nil {
+		ctx = opentracing.ContextWithSpan(ctx, e.span)
+	}
 	ctx, cancel := context.WithCancel(ctx)
 	if e == nil || !e.Cancelable {
 		return ctx, cancel
@@ -1161,31 +1085,45 @@ func (e *Event) TryCancel(reason, owner string) error {
 	if !e.Cancelable || !e.Running {
 		return ErrNotCancelable
 	}
-	conn, err := db.Conn()
+	store, err := eventStorage()
 	if err != nil {
 		return err
 	}
-	defer conn.Close()
-	coll := conn.Events()
-	change := mgo.Change{
-		Update: bson.M{"$set": bson.M{
-			"cancelinfo": cancelInfo{
-				Owner:     owner,
-				Reason:    reason,
-				StartTime: time.Now().UTC(),
-				Asked:     true,
-			},
-		}},
-		ReturnNew: true,
-	}
-	_, err = coll.Find(bson.M{"_id": e.ID, "cancelinfo.asked": false}).Apply(change, &e.eventData)
-	if err == mgo.ErrNotFound {
+	updated, err := store.RequestCancel(context.TODO(), e.ID, cancelInfo{
+		Owner:     owner,
+		Reason:    reason,
+		StartTime: time.Now().UTC(),
+		Asked:     true,
+	})
+	if err == ErrEventNotFound {
 		if _, errID := GetByID(e.UniqueID); errID == ErrEventNotFound {
 			return ErrEventNotFound
 		}
 		err = ErrCancelAlreadyRequested
 	}
-	return err
+	if err != nil {
+		return err
+	}
+	e.eventData = *updated
+	return cancelChildren(store, e.UniqueID, reason, owner)
+}
+
+// cancelChildren asks for the cancellation of every running cancelable event
+// spawned by the event with the given unique ID. Children which finish or are
+// cancelled concurrently are ignored.
+func cancelChildren(store eventTypes.EventStorage, parentID bson.ObjectId, reason, owner string) error {
+	children, err := store.FindRunningChildren(context.TODO(), parentID)
+	if err != nil {
+		return err
+	}
+	for _, data := range children {
+		child := &Event{eventData: data}
+		err = child.TryCancel(reason, owner)
+		if err != nil && err != ErrNotCancelable && err != ErrCancelAlreadyRequested && err != ErrEventNotFound {
+			return err
+		}
+	}
+	return nil
 }
 
 func (e *Event) AckCancel() (bool, error) {
@@ -1194,24 +1132,19 @@ func (e *Event) AckCancel() (bool, error) {
 	if !e.Cancelable || !e.Running {
 		return false, nil
 	}
-	conn, err := db.Conn()
+	store, err := eventStorage()
 	if err != nil {
 		return false, err
 	}
-	defer conn.Close()
-	coll := conn.Events()
-	change := mgo.Change{
-		Update: bson.M{"$set": bson.M{
-			"cancelinfo.acktime":  time.Now().UTC(),
-			"cancelinfo.canceled": true,
-		}},
-		ReturnNew: true,
-	}
-	_, err = coll.Find(bson.M{"_id": e.ID, "cancelinfo.asked": true}).Apply(change, &e.eventData)
-	if err == mgo.ErrNotFound {
+	updated, err := store.AckCancel(context.TODO(), e.ID, time.Now().UTC())
+	if err == ErrEventNotFound {
 		return false, nil
 	}
-	return err == nil, err
+	if err != nil {
+		return false, err
+	}
+	e.eventData = *updated
+	return true, nil
 }
 
 func (e *Event) StartData(value interface{}) error {
@@ -1240,25 +1173,32 @@ func (e *Event) done(evtErr error, customData interface{}, abort bool) (err erro
 	// why we log error messages here.
 	defer func() {
 		e.fillLegacyLog()
-		eventDuration.WithLabelValues(e.Kind.Name).Observe(time.Since(e.StartTime).Seconds())
+		e.finishSpan(err)
+		targetType := string(e.Target.Type)
+		success := e.Error == ""
+		eventDuration.WithLabelValues(e.Kind.Name, targetType, strconv.FormatBool(success)).Observe(time.Since(e.StartTime).Seconds())
 		eventCurrent.WithLabelValues(e.Kind.Name).Dec()
+		if !abort && !success {
+			eventsFailed.WithLabelValues(e.Kind.Name, targetType).Inc()
+		}
 		if err != nil {
 			log.Errorf("[events] error marking event as done - %#v: %s", e, err)
 		} else {
 			if !abort && servicemanager.Webhook != nil {
 				servicemanager.Webhook.Notify(e.UniqueID.Hex())
 			}
+			if !abort {
+				notifySinks(e.UniqueID.Hex())
+			}
 		}
 	}()
 	updater.remove(e.ID)
-	conn, err := db.Conn()
+	store, err := eventStorage()
 	if err != nil {
 		return err
 	}
-	defer conn.Close()
-	coll := conn.Events()
 	if abort {
-		return coll.RemoveId(e.ID)
+		return store.Remove(context.TODO(), e.ID)
 	}
 	if evtErr != nil {
 		if errors.Cause(evtErr) == context.Canceled && !e.CancelInfo.Canceled {
@@ -1281,19 +1221,19 @@ func (e *Event) done(evtErr error, customData interface{}, abort bool) (err erro
 		return err
 	}
 	e.Running = false
-	var dbEvt Event
-	err = coll.FindId(e.ID).One(&dbEvt.eventData)
+	dbEvt, err := store.FindByID(context.TODO(), e.ID)
 	if err == nil {
 		e.OtherCustomData = dbEvt.OtherCustomData
+		e.Owner = dbEvt.Owner
 	}
 	e.logMu.Lock()
 	defer e.logMu.Unlock()
 	if len(e.ID.ObjId) != 0 {
-		return coll.UpdateId(e.ID, e.eventData)
+		return store.Update(context.TODO(), &e.eventData)
 	}
-	defer coll.RemoveId(e.ID)
+	defer store.Remove(context.TODO(), e.ID)
 	e.ID = eventID{ObjId: e.UniqueID}
-	return coll.Insert(e.eventData)
+	return store.Insert(context.TODO(), &e.eventData)
 }
 
 func (e *Event) Log() string {
@@ -1337,10 +1277,10 @@ func (e *Event) LogsFrom(origin *Event) {
 	e.StructuredLog = append(e.StructuredLog, origin.StructuredLog...)
 }
 
-func checkIsExpired(coll *storage.Collection, id interface{}) bool {
-	var existingEvt Event
-	err := coll.FindId(id).One(&existingEvt.eventData)
+func checkIsExpired(store eventTypes.EventStorage, id eventID) bool {
+	data, err := store.FindByID(context.TODO(), id)
 	if err == nil {
+		existingEvt := Event{eventData: *data}
 		now := time.Now().UTC()
 		lastUpdate := existingEvt.LockUpdateTime.UTC()
 		if now.After(lastUpdate.Add(lockExpireTimeout)) {
@@ -19,6 +19,9 @@ import (
 	"time"
 
 	"github.com/globalsign/mgo/bson"
+	"github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/mocktracer"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/tsuru/config"
 	"github.com/tsuru/tsuru/auth"
 	"github.com/tsuru/tsuru/auth/native"
@@ -116,6 +119,33 @@ func (s *S) TestNewDone(c *check.C) {
 	c.Assert(evts[0], check.DeepEquals, expected)
 }
 
+func (s *S) TestNewImpersonated(c *check.C) {
+	token := &auth.ImpersonationToken{UserEmail: s.token.GetUserName(), Impersonator: "admin@example.com"}
+	evt, err := New(&Opts{
+		Target:  Target{Type: "app", Value: "myapp"},
+		Kind:    permission.PermAppUpdateEnvSet,
+		Owner:   token,
+		Allowed: Allowed(permission.PermAppReadEvents),
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(evt.Owner, check.DeepEquals, Owner{Type: OwnerTypeUser, Name: s.token.GetUserName()})
+	c.Assert(evt.Impersonator, check.Equals, "admin@example.com")
+	err = evt.Done(nil)
+	c.Assert(err, check.IsNil)
+	_, err = New(&Opts{
+		Target:  Target{Type: "app", Value: "otherapp"},
+		Kind:    permission.PermAppUpdateEnvSet,
+		Owner:   s.token,
+		Allowed: Allowed(permission.PermAppReadEvents),
+	})
+	c.Assert(err, check.IsNil)
+	evts, err := List(&Filter{Impersonator: "admin@example.com"})
+	c.Assert(err, check.IsNil)
+	c.Assert(evts, check.HasLen, 1)
+	c.Assert(evts[0].Target.Value, check.Equals, "myapp")
+	c.Assert(evts[0].Impersonator, check.Equals, "admin@example.com")
+}
+
 func (s *S) TestNewCustomDataDone(c *check.C) {
 	customData := struct{ A string }{A: "value"}
 	evt, err := New(&Opts{
@@ -548,6 +578,95 @@ func (s *S) TestEventLogfWithWriter(c *check.C) {
 	c.Assert(evts[0].Log(), check.Matches, `(?s)\d{4}-\d{2}-\d{2}.*: hey 42`+"\n")
 }
 
+func (s *S) TestEventSaveLog(c *check.C) {
+	evt, err := New(&Opts{
+		Target:  Target{Type: "app", Value: "myapp"},
+		Kind:    permission.PermAppUpdateEnvSet,
+		Owner:   s.token,
+		Allowed: Allowed(permission.PermAppReadEvents),
+	})
+	c.Assert(err, check.IsNil)
+	evt.Logf("%s %d", "hey", 42)
+	err = evt.SaveLog()
+	c.Assert(err, check.IsNil)
+	dbEvt, err := GetByID(evt.UniqueID)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbEvt.Running, check.Equals, true)
+	c.Assert(dbEvt.Log(), check.Matches, `(?s)\d{4}-\d{2}-\d{2}.*: hey 42`+"\n")
+	evt.Logf("done")
+	err = evt.Done(nil)
+	c.Assert(err, check.IsNil)
+	err = evt.SaveLog()
+	c.Assert(err, check.IsNil)
+	dbEvt, err = GetByID(evt.UniqueID)
+	c.Assert(err, check.IsNil)
+	c.Assert(dbEvt.Log(), check.Matches, `(?s).*: hey 42\n.*: done`+"\n")
+}
+
+func (s *S) TestEventMetrics(c *check.C) {
+	kind := permission.PermAppUpdateEnvSet.FullName()
+	started := testutil.ToFloat64(eventsStarted.WithLabelValues(kind, "app"))
+	failed := testutil.ToFloat64(eventsFailed.WithLabelValues(kind, "app"))
+	evt, err := New(&Opts{
+		Target:  Target{Type: "app", Value: "myapp"},
+		Kind:    permission.PermAppUpdateEnvSet,
+		Owner:   s.token,
+		Allowed: Allowed(permission.PermAppReadEvents),
+	})
+	c.Assert(err, check.IsNil)
+	err = evt.Done(nil)
+	c.Assert(err, check.IsNil)
+	evt, err = New(&Opts{
+		Target:  Target{Type: "app", Value: "myapp"},
+		Kind:    permission.PermAppUpdateEnvSet,
+		Owner:   s.token,
+		Allowed: Allowed(permission.PermAppReadEvents),
+	})
+	c.Assert(err, check.IsNil)
+	err = evt.Done(errors.New("my error"))
+	c.Assert(err, check.IsNil)
+	c.Assert(testutil.ToFloat64(eventsStarted.WithLabelValues(kind, "app")), check.Equals, started+2)
+	c.Assert(testutil.ToFloat64(eventsFailed.WithLabelValues(kind, "app")), check.Equals, failed+1)
+	c.Assert(testutil.CollectAndCount(eventDuration), check.Not(check.Equals), 0)
+}
+
+func (s *S) TestEventTracingSpan(c *check.C) {
+	tracer := mocktracer.New()
+	opentracing.SetGlobalTracer(tracer)
+	defer opentracing.SetGlobalTracer(opentracing.NoopTracer{})
+	parent := tracer.StartSpan("request")
+	evt, err := New(&Opts{
+		Target:        Target{Type: "app", Value: "myapp"},
+		Kind:          permission.PermAppDeploy,
+		Owner:         s.token,
+		Context:       opentracing.ContextWithSpan(context.Background(), parent),
+		Cancelable:    true,
+		Allowed:       Allowed(permission.PermAppReadEvents),
+		AllowedCancel: Allowed(permission.PermAppReadEvents),
+	})
+	c.Assert(err, check.IsNil)
+	ctx, cancel := evt.CancelableContext(context.Background())
+	defer cancel()
+	c.Assert(opentracing.SpanFromContext(ctx), check.Equals, evt.span)
+	err = evt.Done(errors.New("my error"))
+	c.Assert(err, check.IsNil)
+	parent.Finish()
+	spans := tracer.FinishedSpans()
+	c.Assert(spans, check.HasLen, 2)
+	evtSpan := spans[0]
+	c.Assert(evtSpan.OperationName, check.Equals, "Event app.deploy")
+	c.Assert(evtSpan.ParentID, check.Equals, parent.(*mocktracer.MockSpan).SpanContext.SpanID)
+	c.Assert(evtSpan.Tags(), check.DeepEquals, map[string]interface{}{
+		"event.id":           evt.UniqueID.Hex(),
+		"event.kind":         "app.deploy",
+		"event.target.type":  "app",
+		"event.target.value": "myapp",
+		"event.owner":        evt.Owner.String(),
+		"error":              true,
+		"error.object":       "my error",
+	})
+}
+
 func (s *S) TestEventCancel(c *check.C) {
 	evt, err := New(&Opts{
 		Target:        Target{Type: "app", Value: "myapp"},
@@ -624,6 +743,71 @@ func (s *S) TestEventCancelMultipleTimes(c *check.C) {
 	c.Assert(err, check.DeepEquals, ErrCancelAlreadyRequested)
 }
 
+func (s *S) TestEventCancelPropagatesToChildren(c *check.C) {
+	parent, err := New(&Opts{
+		Target:        Target{Type: "pool", Value: "mypool"},
+		Kind:          permission.PermPoolUpdate,
+		Owner:         s.token,
+		Cancelable:    true,
+		Allowed:       Allowed(permission.PermPoolReadEvents),
+		AllowedCancel: Allowed(permission.PermPoolReadEvents),
+	})
+	c.Assert(err, check.IsNil)
+	child, err := New(&Opts{
+		Target:        Target{Type: "node", Value: "http://10.0.0.1"},
+		InternalKind:  "node-upgrade",
+		ParentID:      parent.UniqueID,
+		Cancelable:    true,
+		Allowed:       Allowed(permission.PermPoolReadEvents),
+		AllowedCancel: Allowed(permission.PermPoolReadEvents),
+	})
+	c.Assert(err, check.IsNil)
+	grandChild, err := New(&Opts{
+		Target:        Target{Type: "container", Value: "c1"},
+		InternalKind:  "node-upgrade",
+		ParentID:      child.UniqueID,
+		Cancelable:    true,
+		Allowed:       Allowed(permission.PermPoolReadEvents),
+		AllowedCancel: Allowed(permission.PermPoolReadEvents),
+	})
+	c.Assert(err, check.IsNil)
+	finishedChild, err := New(&Opts{
+		Target:        Target{Type: "node", Value: "http://10.0.0.2"},
+		InternalKind:  "node-upgrade",
+		ParentID:      parent.UniqueID,
+		Cancelable:    true,
+		Allowed:       Allowed(permission.PermPoolReadEvents),
+		AllowedCancel: Allowed(permission.PermPoolReadEvents),
+	})
+	c.Assert(err, check.IsNil)
+	err = finishedChild.Done(nil)
+	c.Assert(err, check.IsNil)
+	notCancelableChild, err := New(&Opts{
+		Target:       Target{Type: "node", Value: "http://10.0.0.3"},
+		InternalKind: "node-upgrade",
+		ParentID:     parent.UniqueID,
+		Allowed:      Allowed(permission.PermPoolReadEvents),
+	})
+	c.Assert(err, check.IsNil)
+	err = parent.TryCancel("because I want", "admin@admin.com")
+	c.Assert(err, check.IsNil)
+	for _, evt := range []*Event{child, grandChild} {
+		dbEvt, err := GetByID(evt.UniqueID)
+		c.Assert(err, check.IsNil)
+		c.Assert(dbEvt.CancelInfo.Asked, check.Equals, true)
+		c.Assert(dbEvt.CancelInfo.Reason, check.Equals, "because I want")
+		c.Assert(dbEvt.CancelInfo.Owner, check.Equals, "admin@admin.com")
+	}
+	for _, evt := range []*Event{finishedChild, notCancelableChild} {
+		dbEvt, err := GetByID(evt.UniqueID)
+		c.Assert(err, check.IsNil)
+		c.Assert(dbEvt.CancelInfo.Asked, check.Equals, false)
+	}
+	canceled, err := child.AckCancel()
+	c.Assert(err, check.IsNil)
+	c.Assert(canceled, check.Equals, true)
+}
+
 func (s *S) TestEventCancelNotCancelable(c *check.C) {
 	evt, err := New(&Opts{
 		Target:  Target{Type: "app", Value: "myapp"},
@@ -798,6 +982,53 @@ func (s *S) TestNewThrottledAllKinds(c *check.C) {
 	c.Assert(err, check.IsNil)
 }
 
+func (s *S) TestNewThrottledPerOwner(c *check.C) {
+	SetThrottling(ThrottlingSpec{
+		TargetType: TargetTypePool,
+		KindName:   permission.PermPoolUpdate.FullName(),
+		Time:       time.Hour,
+		Max:        1,
+		AllTargets: true,
+		PerOwner:   true,
+	})
+	evt, err := New(&Opts{
+		Target:  Target{Type: TargetTypePool, Value: "pool1"},
+		Kind:    permission.PermPoolUpdate,
+		Owner:   s.token,
+		Allowed: Allowed(permission.PermPoolReadEvents),
+	})
+	c.Assert(err, check.IsNil)
+	err = evt.Done(nil)
+	c.Assert(err, check.IsNil)
+	_, err = New(&Opts{
+		Target:  Target{Type: TargetTypePool, Value: "pool2"},
+		Kind:    permission.PermPoolUpdate,
+		Owner:   s.token,
+		Allowed: Allowed(permission.PermPoolReadEvents),
+	})
+	c.Assert(err, check.FitsTypeOf, ErrThrottled{})
+	c.Assert(err, check.ErrorMatches, fmt.Sprintf("event throttled, limit for pool.update on any pool by user %s is 1 every 1h0m0s", s.token.GetUserName()))
+	// A different owner is not throttled
+	evt, err = New(&Opts{
+		Target:   Target{Type: TargetTypePool, Value: "pool2"},
+		Kind:     permission.PermPoolUpdate,
+		RawOwner: Owner{Type: OwnerTypeUser, Name: "other@other.com"},
+		Allowed:  Allowed(permission.PermPoolReadEvents),
+	})
+	c.Assert(err, check.IsNil)
+	err = evt.Done(nil)
+	c.Assert(err, check.IsNil)
+	// Internal events are not throttled by owner
+	evt, err = NewInternal(&Opts{
+		Target:       Target{Type: TargetTypePool, Value: "pool3"},
+		InternalKind: permission.PermPoolUpdate.FullName(),
+		Allowed:      Allowed(permission.PermPoolReadEvents),
+	})
+	c.Assert(err, check.IsNil)
+	err = evt.Done(nil)
+	c.Assert(err, check.IsNil)
+}
+
 func (s *S) TestNewThrottledOneKind(c *check.C) {
 	SetThrottling(ThrottlingSpec{
 		TargetType: TargetTypeApp,
@@ -1079,14 +1310,12 @@ func (s *S) TestListFilterPruneUserValues(c *check.C) {
 		Since:          time.Now(),
 		Until:          time.Now(),
 		Running:        &t,
-		Raw:            bson.M{"a": 1},
 		AllowedTargets: []TargetFilter{{Type: TargetTypeApp, Values: []string{"a1"}}},
 		Limit:          50,
 		Skip:           10,
 		Sort:           "id",
 	}
 	expectedFilter := f
-	expectedFilter.Raw = nil
 	expectedFilter.AllowedTargets = nil
 	f.PruneUserValues()
 	c.Assert(f, check.DeepEquals, expectedFilter)
@@ -1362,6 +1591,12 @@ event:
     window: 60
     all-targets: false
     wait-finish: false
+  - target-type: app
+    kind-name: app.deploy
+    limit: 10
+    window: 60
+    all-targets: true
+    per-owner: true
 `))
 	c.Assert(err, check.IsNil)
 	setBaseConfig()
@@ -1384,6 +1619,14 @@ event:
 			AllTargets: false,
 			WaitFinish: false,
 		},
+		"app_app.deploy_global_owner": {
+			TargetType: TargetTypeApp,
+			KindName:   permission.PermAppDeploy.FullName(),
+			Time:       time.Minute,
+			Max:        10,
+			AllTargets: true,
+			PerOwner:   true,
+		},
 	})
 }
 
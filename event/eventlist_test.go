@@ -7,6 +7,7 @@ package event_test
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strings"
 	"time"
 
@@ -169,6 +170,73 @@ func (s *S) TestListFilterMany(c *check.C) {
 	}, Sort: "_id"}, allEvts[:0])
 }
 
+func (s *S) TestListFilterSearch(c *check.C) {
+	evt1, err := event.New(&event.Opts{
+		Target:  event.Target{Type: "app", Value: "myapp"},
+		Kind:    permission.PermAppDeploy,
+		Owner:   s.token,
+		Allowed: event.Allowed(permission.PermAppReadEvents),
+	})
+	c.Assert(err, check.IsNil)
+	evt2, err := event.NewInternal(&event.Opts{
+		Target:       event.Target{Type: "app", Value: "otherapp"},
+		InternalKind: "healer",
+		CustomData:   map[string]interface{}{"image": "myapp"},
+		Allowed:      event.Allowed(permission.PermAppReadEvents),
+	})
+	c.Assert(err, check.IsNil)
+	evts, err := event.List(&event.Filter{Search: "myapp"})
+	c.Assert(err, check.IsNil)
+	c.Assert(evts, eventtest.EvtEquals, evt1)
+	evts, err = event.List(&event.Filter{Search: "healer"})
+	c.Assert(err, check.IsNil)
+	c.Assert(evts, check.HasLen, 1)
+	c.Assert(evts[0].UniqueID, check.Equals, evt2.UniqueID)
+	evts, err = event.List(&event.Filter{Search: s.token.GetUserName()})
+	c.Assert(err, check.IsNil)
+	c.Assert(evts, eventtest.EvtEquals, evt1)
+	evts, err = event.List(&event.Filter{Search: "notfound"})
+	c.Assert(err, check.IsNil)
+	c.Assert(evts, check.HasLen, 0)
+}
+
+func (s *S) TestListWithCursor(c *check.C) {
+	var allEvts []*event.Event
+	for i := 0; i < 5; i++ {
+		evt, err := event.New(&event.Opts{
+			Target:  event.Target{Type: "app", Value: fmt.Sprintf("myapp%d", i)},
+			Kind:    permission.PermAppUpdateEnvSet,
+			Owner:   s.token,
+			Allowed: event.Allowed(permission.PermAppReadEvents),
+		})
+		c.Assert(err, check.IsNil)
+		allEvts = append([]*event.Event{evt}, allEvts...)
+	}
+	evts, err := event.List(&event.Filter{Limit: 2})
+	c.Assert(err, check.IsNil)
+	c.Assert(evts, eventtest.EvtEquals, allEvts[:2])
+	evts, err = event.List(&event.Filter{Limit: 2, After: evts[1].UniqueID.Hex()})
+	c.Assert(err, check.IsNil)
+	c.Assert(evts, eventtest.EvtEquals, allEvts[2:4])
+	evts, err = event.List(&event.Filter{Limit: 2, After: evts[1].UniqueID.Hex()})
+	c.Assert(err, check.IsNil)
+	c.Assert(evts, eventtest.EvtEquals, allEvts[4])
+	evts, err = event.List(&event.Filter{Limit: 2, After: evts[0].UniqueID.Hex()})
+	c.Assert(err, check.IsNil)
+	c.Assert(evts, check.HasLen, 0)
+}
+
+func (s *S) TestListWithInvalidCursor(c *check.C) {
+	_, err := event.List(&event.Filter{After: "invalid"})
+	c.Assert(err, check.Equals, event.ErrInvalidCursor)
+	_, err = event.List(&event.Filter{After: bson.NewObjectId().Hex()})
+	c.Assert(err, check.Equals, event.ErrInvalidCursor)
+	_, err = event.List(&event.Filter{After: bson.NewObjectId().Hex(), Skip: 1})
+	c.Assert(err, check.Equals, event.ErrCursorWithSkipOrSort)
+	_, err = event.List(&event.Filter{After: bson.NewObjectId().Hex(), Sort: "_id"})
+	c.Assert(err, check.Equals, event.ErrCursorWithSkipOrSort)
+}
+
 func (s *S) TestGetByID(c *check.C) {
 	evt, err := event.New(&event.Opts{
 		Target:  event.Target{Type: "app", Value: "myapp"},
@@ -0,0 +1,172 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package kafka provides an event sink that publishes every finished event
+// to a Kafka topic.
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	kafkaGo "github.com/segmentio/kafka-go"
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/api/shutdown"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/log"
+)
+
+const (
+	defaultTopic = "tsuru-events"
+	writeTimeout = 30 * time.Second
+)
+
+var chanBufferSize = 1000
+
+type messageWriter interface {
+	WriteMessages(ctx context.Context, msgs ...kafkaGo.Message) error
+	Close() error
+}
+
+type exporter struct {
+	writer            messageWriter
+	partitionByTarget bool
+	evtCh             chan string
+	quitCh            chan struct{}
+	doneCh            chan struct{}
+
+	published prometheus.Counter
+	errors    prometheus.Counter
+}
+
+var _ event.Sink = &exporter{}
+
+// Initialize starts the Kafka exporter and registers it as an event sink if
+// event:kafka:brokers is configured.
+func Initialize() error {
+	brokers, _ := config.GetList("event:kafka:brokers")
+	if len(brokers) == 0 {
+		return nil
+	}
+	topic, _ := config.GetString("event:kafka:topic")
+	if topic == "" {
+		topic = defaultTopic
+	}
+	partitionByTarget := true
+	if v, err := config.GetBool("event:kafka:partition-by-target"); err == nil {
+		partitionByTarget = v
+	}
+	var balancer kafkaGo.Balancer = &kafkaGo.RoundRobin{}
+	if partitionByTarget {
+		balancer = &kafkaGo.Hash{}
+	}
+	writer := &kafkaGo.Writer{
+		Addr:     kafkaGo.TCP(brokers...),
+		Topic:    topic,
+		Balancer: balancer,
+	}
+	e, err := newExporter(writer, partitionByTarget)
+	if err != nil {
+		return err
+	}
+	event.RegisterSink(e)
+	shutdown.Register(e)
+	return nil
+}
+
+func newExporter(writer messageWriter, partitionByTarget bool) (*exporter, error) {
+	e := &exporter{
+		writer:            writer,
+		partitionByTarget: partitionByTarget,
+		evtCh:             make(chan string, chanBufferSize),
+		quitCh:            make(chan struct{}),
+		doneCh:            make(chan struct{}),
+		published: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "tsuru_events_kafka_published_total",
+			Help: "The total number of events published to kafka",
+		}),
+		errors: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "tsuru_events_kafka_errors_total",
+			Help: "The total number of errors publishing events to kafka",
+		}),
+	}
+	for _, c := range []prometheus.Collector{e.published, e.errors} {
+		err := prometheus.Register(c)
+		if err != nil {
+			return nil, err
+		}
+	}
+	go e.run()
+	return e, nil
+}
+
+func (e *exporter) String() string {
+	return "kafka event exporter"
+}
+
+func (e *exporter) Notify(evtID string) {
+	select {
+	case e.evtCh <- evtID:
+	case <-e.quitCh:
+	default:
+		e.errors.Inc()
+		log.Errorf("[events kafka] queue full, dropping event %q", evtID)
+	}
+}
+
+func (e *exporter) Shutdown(ctx context.Context) error {
+	event.UnregisterSink(e)
+	close(e.quitCh)
+	select {
+	case <-e.doneCh:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	prometheus.Unregister(e.published)
+	prometheus.Unregister(e.errors)
+	return e.writer.Close()
+}
+
+func (e *exporter) run() {
+	defer close(e.doneCh)
+	for {
+		select {
+		case evtID := <-e.evtCh:
+			err := e.publish(evtID)
+			if err != nil {
+				e.errors.Inc()
+				log.Errorf("[events kafka] error publishing event %q: %v", evtID, err)
+				continue
+			}
+			e.published.Inc()
+		case <-e.quitCh:
+			return
+		}
+	}
+}
+
+func (e *exporter) publish(evtID string) error {
+	evt, err := event.GetByHexID(evtID)
+	if err != nil {
+		return err
+	}
+	err = event.Redact(evt)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(evt)
+	if err != nil {
+		return err
+	}
+	msg := kafkaGo.Message{Value: data}
+	if e.partitionByTarget {
+		msg.Key = []byte(fmt.Sprintf("%s/%s", evt.Target.Type, evt.Target.Value))
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), writeTimeout)
+	defer cancel()
+	return e.writer.WriteMessages(ctx, msg)
+}
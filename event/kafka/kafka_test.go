@@ -0,0 +1,164 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+
+	kafkaGo "github.com/segmentio/kafka-go"
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/db"
+	"github.com/tsuru/tsuru/db/dbtest"
+	"github.com/tsuru/tsuru/event"
+	_ "github.com/tsuru/tsuru/event/webhook"
+	"github.com/tsuru/tsuru/permission"
+	servicemock "github.com/tsuru/tsuru/servicemanager/mock"
+	_ "github.com/tsuru/tsuru/storage/mongodb"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+	check "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) { check.TestingT(t) }
+
+type S struct{}
+
+var _ = check.Suite(&S{})
+
+type fakeWriter struct {
+	sync.Mutex
+	msgs    []kafkaGo.Message
+	written chan struct{}
+	closed  bool
+}
+
+func (w *fakeWriter) WriteMessages(ctx context.Context, msgs ...kafkaGo.Message) error {
+	w.Lock()
+	defer w.Unlock()
+	w.msgs = append(w.msgs, msgs...)
+	w.written <- struct{}{}
+	return nil
+}
+
+func (w *fakeWriter) Close() error {
+	w.Lock()
+	defer w.Unlock()
+	w.closed = true
+	return nil
+}
+
+func (s *S) SetUpTest(c *check.C) {
+	config.Set("database:url", "127.0.0.1:27017?maxPoolSize=150")
+	config.Set("database:name", "tsuru_event_kafka_tests")
+	conn, err := db.Conn()
+	c.Assert(err, check.IsNil)
+	defer conn.Close()
+	err = dbtest.ClearAllCollections(conn.Events().Database)
+	c.Assert(err, check.IsNil)
+	servicemock.SetMockService(&servicemock.MockService{})
+}
+
+func (s *S) TestInitializeNotConfigured(c *check.C) {
+	err := Initialize()
+	c.Assert(err, check.IsNil)
+}
+
+func (s *S) TestExporterPublishesDoneEvents(c *check.C) {
+	writer := &fakeWriter{written: make(chan struct{}, 1)}
+	exp, err := newExporter(writer, true)
+	c.Assert(err, check.IsNil)
+	event.RegisterSink(exp)
+	defer exp.Shutdown(context.Background())
+	evt, err := event.New(&event.Opts{
+		Target:   event.Target{Type: "app", Value: "myapp"},
+		RawOwner: event.Owner{Type: "user", Name: "me@me.com"},
+		Kind:     permission.PermAppUpdateEnvSet,
+		Allowed:  event.Allowed(permission.PermAppReadEvents, permission.Context(permTypes.CtxApp, "myapp")),
+	})
+	c.Assert(err, check.IsNil)
+	err = evt.Done(nil)
+	c.Assert(err, check.IsNil)
+	<-writer.written
+	doneEvt, err := event.GetByID(evt.UniqueID)
+	c.Assert(err, check.IsNil)
+	evtData, err := json.Marshal(doneEvt)
+	c.Assert(err, check.IsNil)
+	writer.Lock()
+	defer writer.Unlock()
+	c.Assert(writer.msgs, check.HasLen, 1)
+	c.Assert(string(writer.msgs[0].Key), check.Equals, "app/myapp")
+	c.Assert(string(writer.msgs[0].Value), check.Equals, string(evtData))
+}
+
+func (s *S) TestExporterPublishesRedactedEvents(c *check.C) {
+	writer := &fakeWriter{written: make(chan struct{}, 1)}
+	exp, err := newExporter(writer, true)
+	c.Assert(err, check.IsNil)
+	event.RegisterSink(exp)
+	defer exp.Shutdown(context.Background())
+	evt, err := event.New(&event.Opts{
+		Target:   event.Target{Type: event.TargetTypeWebhook, Value: "wh1"},
+		RawOwner: event.Owner{Type: "user", Name: "me@me.com"},
+		Kind:     permission.PermWebhookCreate,
+		CustomData: []map[string]interface{}{
+			{"name": "name", "value": "wh1"},
+			{"name": "headers.Authorization.0", "value": "Bearer secret-token"},
+		},
+		Allowed: event.Allowed(permission.PermWebhookReadEvents, permission.Context(permTypes.CtxTeam, "myteam")),
+	})
+	c.Assert(err, check.IsNil)
+	err = evt.Done(nil)
+	c.Assert(err, check.IsNil)
+	<-writer.written
+	writer.Lock()
+	defer writer.Unlock()
+	c.Assert(writer.msgs, check.HasLen, 1)
+	c.Assert(strings.Contains(string(writer.msgs[0].Value), "secret-token"), check.Equals, false)
+	var published event.Event
+	err = json.Unmarshal(writer.msgs[0].Value, &published)
+	c.Assert(err, check.IsNil)
+	var customData []map[string]interface{}
+	err = published.StartData(&customData)
+	c.Assert(err, check.IsNil)
+	c.Assert(customData, check.DeepEquals, []map[string]interface{}{
+		{"name": "name", "value": "wh1"},
+		{"name": "headers.Authorization.0", "value": event.RedactedValue},
+	})
+}
+
+func (s *S) TestExporterWithoutPartitionByTarget(c *check.C) {
+	writer := &fakeWriter{written: make(chan struct{}, 1)}
+	exp, err := newExporter(writer, false)
+	c.Assert(err, check.IsNil)
+	defer exp.Shutdown(context.Background())
+	evt, err := event.New(&event.Opts{
+		Target:   event.Target{Type: "app", Value: "myapp"},
+		RawOwner: event.Owner{Type: "user", Name: "me@me.com"},
+		Kind:     permission.PermAppUpdateEnvSet,
+		Allowed:  event.Allowed(permission.PermAppReadEvents, permission.Context(permTypes.CtxApp, "myapp")),
+	})
+	c.Assert(err, check.IsNil)
+	err = evt.Done(nil)
+	c.Assert(err, check.IsNil)
+	exp.Notify(evt.UniqueID.Hex())
+	<-writer.written
+	writer.Lock()
+	defer writer.Unlock()
+	c.Assert(writer.msgs, check.HasLen, 1)
+	c.Assert(writer.msgs[0].Key, check.IsNil)
+}
+
+func (s *S) TestExporterShutdown(c *check.C) {
+	writer := &fakeWriter{written: make(chan struct{}, 1)}
+	exp, err := newExporter(writer, true)
+	c.Assert(err, check.IsNil)
+	event.RegisterSink(exp)
+	err = exp.Shutdown(context.Background())
+	c.Assert(err, check.IsNil)
+	c.Assert(writer.closed, check.Equals, true)
+}
@@ -0,0 +1,31 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package event
+
+import "context"
+
+// ReassignOwner changes the owner of every event owned by from to the owner
+// to, returning the number of updated events. Running events are updated as
+// well, their stored owner is kept when they finish.
+func ReassignOwner(from, to Owner) (int, error) {
+	if from.Type == "" || from.Name == "" || to.Type == "" {
+		return 0, ErrValidation("event owner type and name are mandatory")
+	}
+	store, err := eventStorage()
+	if err != nil {
+		return 0, err
+	}
+	return store.ReassignOwner(context.TODO(), from, to)
+}
+
+// TombstoneUserOwner marks events owned by a removed user, so they're no
+// longer listed as owned by an existing user while keeping the user name for
+// auditing purposes.
+func TombstoneUserOwner(email string) (int, error) {
+	return ReassignOwner(
+		Owner{Type: OwnerTypeUser, Name: email},
+		Owner{Type: OwnerTypeRemovedUser, Name: email},
+	)
+}
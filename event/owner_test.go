@@ -0,0 +1,71 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package event
+
+import (
+	"github.com/tsuru/tsuru/permission"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestReassignOwner(c *check.C) {
+	newEvt := func(target string, owner Owner) *Event {
+		evt, err := New(&Opts{
+			Target:   Target{Type: "app", Value: target},
+			Kind:     permission.PermAppUpdateEnvSet,
+			RawOwner: owner,
+			Allowed:  Allowed(permission.PermAppReadEvents),
+		})
+		c.Assert(err, check.IsNil)
+		return evt
+	}
+	old := Owner{Type: OwnerTypeUser, Name: "old@example.com"}
+	other := Owner{Type: OwnerTypeUser, Name: "other@example.com"}
+	evt1 := newEvt("app1", old)
+	c.Assert(evt1.Done(nil), check.IsNil)
+	evt2 := newEvt("app2", other)
+	c.Assert(evt2.Done(nil), check.IsNil)
+	running := newEvt("app3", old)
+	updated, err := ReassignOwner(old, Owner{Type: OwnerTypeUser, Name: "new@example.com"})
+	c.Assert(err, check.IsNil)
+	c.Assert(updated, check.Equals, 2)
+	c.Assert(running.Done(nil), check.IsNil)
+	evts, err := List(&Filter{OwnerName: "new@example.com"})
+	c.Assert(err, check.IsNil)
+	c.Assert(evts, check.HasLen, 2)
+	c.Assert(evts[0].UniqueID, check.Equals, running.UniqueID)
+	c.Assert(evts[1].UniqueID, check.Equals, evt1.UniqueID)
+	evts, err = List(&Filter{OwnerName: "old@example.com"})
+	c.Assert(err, check.IsNil)
+	c.Assert(evts, check.HasLen, 0)
+	evts, err = List(&Filter{OwnerName: "other@example.com"})
+	c.Assert(err, check.IsNil)
+	c.Assert(evts, check.HasLen, 1)
+}
+
+func (s *S) TestReassignOwnerInvalid(c *check.C) {
+	_, err := ReassignOwner(Owner{}, Owner{Type: OwnerTypeUser, Name: "x"})
+	c.Assert(err, check.FitsTypeOf, ErrValidation(""))
+}
+
+func (s *S) TestTombstoneUserOwner(c *check.C) {
+	evt, err := New(&Opts{
+		Target:   Target{Type: "app", Value: "app1"},
+		Kind:     permission.PermAppUpdateEnvSet,
+		RawOwner: Owner{Type: OwnerTypeUser, Name: "old@example.com"},
+		Allowed:  Allowed(permission.PermAppReadEvents),
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(evt.Done(nil), check.IsNil)
+	updated, err := TombstoneUserOwner("old@example.com")
+	c.Assert(err, check.IsNil)
+	c.Assert(updated, check.Equals, 1)
+	evts, err := List(&Filter{OwnerType: OwnerTypeUser, OwnerName: "old@example.com"})
+	c.Assert(err, check.IsNil)
+	c.Assert(evts, check.HasLen, 0)
+	evts, err = List(&Filter{OwnerType: OwnerTypeRemovedUser, OwnerName: "old@example.com"})
+	c.Assert(err, check.IsNil)
+	c.Assert(evts, check.HasLen, 1)
+	c.Assert(evts[0].Owner, check.Equals, Owner{Type: OwnerTypeRemovedUser, Name: "old@example.com"})
+}
@@ -0,0 +1,88 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package event
+
+import (
+	"strings"
+	"sync"
+)
+
+// RedactedValue replaces sensitive values removed from event custom data.
+const RedactedValue = "*** (redacted)"
+
+// Redactor strips sensitive data from an event before it is exposed to users.
+// Redactors change only the in-memory event, never the stored one.
+type Redactor func(e *Event) error
+
+var redactors = struct {
+	sync.RWMutex
+	kinds map[string][]Redactor
+}{kinds: map[string][]Redactor{}}
+
+// RegisterRedactor registers a redactor for events of the given kind name.
+// Many redactors may be registered for the same kind, they run in the order
+// they were registered.
+func RegisterRedactor(kindName string, r Redactor) {
+	redactors.Lock()
+	defer redactors.Unlock()
+	redactors.kinds[kindName] = append(redactors.kinds[kindName], r)
+}
+
+// Redact runs every redactor registered for the kind of the event.
+func Redact(e *Event) error {
+	redactors.RLock()
+	rs := redactors.kinds[e.Kind.Name]
+	redactors.RUnlock()
+	for _, r := range rs {
+		err := r(e)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FormFieldsRedactor returns a Redactor masking the values in start custom
+// data created by FormToCustomData whose names match any of the given fields.
+// Names are compared ignoring case and a field also matches its nested names,
+// so "headers" matches "headers.Authorization.0".
+func FormFieldsRedactor(fields ...string) Redactor {
+	for i := range fields {
+		fields[i] = strings.ToLower(fields[i])
+	}
+	matches := func(name string) bool {
+		name = strings.ToLower(name)
+		for _, f := range fields {
+			if name == f || strings.HasPrefix(name, f+".") {
+				return true
+			}
+		}
+		return false
+	}
+	return func(e *Event) error {
+		if len(e.StartCustomData.Data) == 0 {
+			return nil
+		}
+		var data []map[string]interface{}
+		err := e.StartCustomData.Unmarshal(&data)
+		if err != nil {
+			// Custom data not created from a form, nothing to redact.
+			return nil
+		}
+		var changed bool
+		for _, entry := range data {
+			name, _ := entry["name"].(string)
+			if matches(name) {
+				entry["value"] = RedactedValue
+				changed = true
+			}
+		}
+		if !changed {
+			return nil
+		}
+		e.StartCustomData, err = makeBSONRaw(data)
+		return err
+	}
+}
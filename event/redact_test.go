@@ -0,0 +1,74 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package event
+
+import (
+	"errors"
+
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestRedactRunsRegisteredRedactors(c *check.C) {
+	var calls []string
+	RegisterRedactor("test.redact.run", func(e *Event) error {
+		calls = append(calls, "first")
+		return nil
+	})
+	RegisterRedactor("test.redact.run", func(e *Event) error {
+		calls = append(calls, "second")
+		return nil
+	})
+	evt := &Event{eventData: eventData{Kind: Kind{Name: "test.redact.run"}}}
+	err := Redact(evt)
+	c.Assert(err, check.IsNil)
+	c.Assert(calls, check.DeepEquals, []string{"first", "second"})
+	evt = &Event{eventData: eventData{Kind: Kind{Name: "test.redact.other"}}}
+	err = Redact(evt)
+	c.Assert(err, check.IsNil)
+	c.Assert(calls, check.HasLen, 2)
+}
+
+func (s *S) TestRedactError(c *check.C) {
+	RegisterRedactor("test.redact.error", func(e *Event) error {
+		return errors.New("my error")
+	})
+	evt := &Event{eventData: eventData{Kind: Kind{Name: "test.redact.error"}}}
+	err := Redact(evt)
+	c.Assert(err, check.ErrorMatches, "my error")
+}
+
+func (s *S) TestFormFieldsRedactor(c *check.C) {
+	raw, err := makeBSONRaw([]map[string]interface{}{
+		{"name": "name", "value": "mywebhook"},
+		{"name": "Headers.Authorization.0", "value": "Bearer secret"},
+		{"name": "headersx", "value": "visible"},
+		{"name": "ClientKey", "value": "my key"},
+	})
+	c.Assert(err, check.IsNil)
+	evt := &Event{eventData: eventData{StartCustomData: raw}}
+	err = FormFieldsRedactor("headers", "clientkey")(evt)
+	c.Assert(err, check.IsNil)
+	var data []map[string]interface{}
+	err = evt.StartData(&data)
+	c.Assert(err, check.IsNil)
+	c.Assert(data, check.DeepEquals, []map[string]interface{}{
+		{"name": "name", "value": "mywebhook"},
+		{"name": "Headers.Authorization.0", "value": RedactedValue},
+		{"name": "headersx", "value": "visible"},
+		{"name": "ClientKey", "value": RedactedValue},
+	})
+}
+
+func (s *S) TestFormFieldsRedactorIgnoresOtherCustomData(c *check.C) {
+	raw, err := makeBSONRaw(map[string]interface{}{"headers": "value"})
+	c.Assert(err, check.IsNil)
+	evt := &Event{eventData: eventData{StartCustomData: raw}}
+	err = FormFieldsRedactor("headers")(evt)
+	c.Assert(err, check.IsNil)
+	c.Assert(evt.StartCustomData, check.DeepEquals, raw)
+	evt = &Event{}
+	err = FormFieldsRedactor("headers")(evt)
+	c.Assert(err, check.IsNil)
+}
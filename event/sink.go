@@ -0,0 +1,44 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package event
+
+import "sync"
+
+// Sink is implemented by components interested in every finished event, such
+// as exporters to external systems. Notify is called with the event unique
+// ID and must not block.
+type Sink interface {
+	Notify(evtID string)
+}
+
+var (
+	sinksMu sync.RWMutex
+	sinks   []Sink
+)
+
+func RegisterSink(s Sink) {
+	sinksMu.Lock()
+	defer sinksMu.Unlock()
+	sinks = append(sinks, s)
+}
+
+func UnregisterSink(s Sink) {
+	sinksMu.Lock()
+	defer sinksMu.Unlock()
+	for i := range sinks {
+		if sinks[i] == s {
+			sinks = append(sinks[:i], sinks[i+1:]...)
+			return
+		}
+	}
+}
+
+func notifySinks(evtID string) {
+	sinksMu.RLock()
+	defer sinksMu.RUnlock()
+	for _, s := range sinks {
+		s.Notify(evtID)
+	}
+}
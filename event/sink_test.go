@@ -0,0 +1,63 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package event
+
+import (
+	"github.com/tsuru/tsuru/permission"
+	check "gopkg.in/check.v1"
+)
+
+type fakeSink struct {
+	ids []string
+}
+
+func (s *fakeSink) Notify(evtID string) {
+	s.ids = append(s.ids, evtID)
+}
+
+func (s *S) TestSinkNotifiedOnDone(c *check.C) {
+	sink := &fakeSink{}
+	RegisterSink(sink)
+	defer UnregisterSink(sink)
+	evt, err := New(&Opts{
+		Target:  Target{Type: "app", Value: "myapp"},
+		Kind:    permission.PermAppUpdateEnvSet,
+		Owner:   s.token,
+		Allowed: Allowed(permission.PermAppReadEvents),
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(sink.ids, check.HasLen, 0)
+	err = evt.Done(nil)
+	c.Assert(err, check.IsNil)
+	c.Assert(sink.ids, check.DeepEquals, []string{evt.UniqueID.Hex()})
+}
+
+func (s *S) TestSinkNotNotifiedOnAbort(c *check.C) {
+	sink := &fakeSink{}
+	RegisterSink(sink)
+	defer UnregisterSink(sink)
+	evt, err := New(&Opts{
+		Target:  Target{Type: "app", Value: "myapp"},
+		Kind:    permission.PermAppUpdateEnvSet,
+		Owner:   s.token,
+		Allowed: Allowed(permission.PermAppReadEvents),
+	})
+	c.Assert(err, check.IsNil)
+	err = evt.Abort()
+	c.Assert(err, check.IsNil)
+	c.Assert(sink.ids, check.HasLen, 0)
+}
+
+func (s *S) TestUnregisterSink(c *check.C) {
+	sink1 := &fakeSink{}
+	sink2 := &fakeSink{}
+	RegisterSink(sink1)
+	RegisterSink(sink2)
+	UnregisterSink(sink1)
+	defer UnregisterSink(sink2)
+	notifySinks("abc")
+	c.Assert(sink1.ids, check.HasLen, 0)
+	c.Assert(sink2.ids, check.DeepEquals, []string{"abc"})
+}
@@ -0,0 +1,50 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package event
+
+import (
+	"context"
+
+	eventTypes "github.com/tsuru/tsuru/types/event"
+)
+
+const (
+	SummaryGroupByKind       = eventTypes.SummaryGroupByKind
+	SummaryGroupByTargetType = eventTypes.SummaryGroupByTargetType
+	SummaryGroupByTeam       = eventTypes.SummaryGroupByTeam
+)
+
+type SummaryEntry = eventTypes.SummaryEntry
+
+// Summary counts the events matching the filter grouped by kind name, target
+// type or team. Events are grouped by team using the team contexts in which
+// they are allowed, so an event may be counted for many teams and events not
+// bound to any team are left out. Running events are never counted as
+// failures. Limit, Skip, Sort and After are ignored.
+func Summary(filter *Filter, groupBy string) ([]SummaryEntry, error) {
+	switch groupBy {
+	case SummaryGroupByKind, SummaryGroupByTargetType, SummaryGroupByTeam:
+	default:
+		return nil, ErrValidation(eventTypes.ErrInvalidGroupBy.Error())
+	}
+	store, err := eventStorage()
+	if err != nil {
+		return nil, err
+	}
+	var storageFilter *eventTypes.EventFilter
+	if filter != nil {
+		storageFilter = filter.toStorage()
+	}
+	entries, err := store.Summary(context.TODO(), storageFilter, groupBy)
+	if err != nil {
+		return nil, err
+	}
+	for i := range entries {
+		if entries[i].Count > 0 {
+			entries[i].FailureRatio = float64(entries[i].Failures) / float64(entries[i].Count)
+		}
+	}
+	return entries, nil
+}
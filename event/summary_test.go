@@ -0,0 +1,86 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package event
+
+import (
+	"errors"
+	"time"
+
+	"github.com/tsuru/tsuru/permission"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) insertSummaryEvents(c *check.C) {
+	create := func(target Target, kind *permission.PermissionScheme, team string, doneErr error, done bool) {
+		allowed := Allowed(permission.PermPoolReadEvents)
+		if team != "" {
+			allowed = Allowed(permission.PermAppReadEvents, permission.Context(permTypes.CtxTeam, team))
+		}
+		evt, err := New(&Opts{
+			Target:  target,
+			Kind:    kind,
+			Owner:   s.token,
+			Allowed: allowed,
+		})
+		c.Assert(err, check.IsNil)
+		if done {
+			c.Assert(evt.Done(doneErr), check.IsNil)
+		}
+	}
+	create(Target{Type: TargetTypeApp, Value: "app1"}, permission.PermAppDeploy, "team1", errors.New("deploy failed"), true)
+	create(Target{Type: TargetTypeApp, Value: "app2"}, permission.PermAppDeploy, "team1", nil, true)
+	create(Target{Type: TargetTypeApp, Value: "app3"}, permission.PermAppDeploy, "team2", nil, true)
+	create(Target{Type: TargetTypeApp, Value: "app4"}, permission.PermAppUpdateEnvSet, "team2", nil, false)
+	create(Target{Type: TargetTypePool, Value: "pool1"}, permission.PermPoolUpdate, "", errors.New("update failed"), true)
+}
+
+func (s *S) TestSummaryByKind(c *check.C) {
+	s.insertSummaryEvents(c)
+	entries, err := Summary(nil, SummaryGroupByKind)
+	c.Assert(err, check.IsNil)
+	c.Assert(entries, check.DeepEquals, []SummaryEntry{
+		{Key: "app.deploy", Count: 3, Failures: 1, FailureRatio: 1.0 / 3},
+		{Key: "app.update.env.set", Count: 1},
+		{Key: "pool.update", Count: 1, Failures: 1, FailureRatio: 1},
+	})
+}
+
+func (s *S) TestSummaryByTargetType(c *check.C) {
+	s.insertSummaryEvents(c)
+	entries, err := Summary(nil, SummaryGroupByTargetType)
+	c.Assert(err, check.IsNil)
+	c.Assert(entries, check.DeepEquals, []SummaryEntry{
+		{Key: "app", Count: 4, Failures: 1, FailureRatio: 0.25},
+		{Key: "pool", Count: 1, Failures: 1, FailureRatio: 1},
+	})
+}
+
+func (s *S) TestSummaryByTeam(c *check.C) {
+	s.insertSummaryEvents(c)
+	entries, err := Summary(nil, SummaryGroupByTeam)
+	c.Assert(err, check.IsNil)
+	c.Assert(entries, check.DeepEquals, []SummaryEntry{
+		{Key: "team1", Count: 2, Failures: 1, FailureRatio: 0.5},
+		{Key: "team2", Count: 2},
+	})
+}
+
+func (s *S) TestSummaryFilter(c *check.C) {
+	s.insertSummaryEvents(c)
+	entries, err := Summary(&Filter{Since: time.Now().Add(time.Hour)}, SummaryGroupByKind)
+	c.Assert(err, check.IsNil)
+	c.Assert(entries, check.HasLen, 0)
+	entries, err = Summary(&Filter{Target: Target{Type: TargetTypePool}}, SummaryGroupByKind)
+	c.Assert(err, check.IsNil)
+	c.Assert(entries, check.DeepEquals, []SummaryEntry{
+		{Key: "pool.update", Count: 1, Failures: 1, FailureRatio: 1},
+	})
+}
+
+func (s *S) TestSummaryInvalidGroupBy(c *check.C) {
+	_, err := Summary(nil, "owner")
+	c.Assert(err, check.FitsTypeOf, ErrValidation(""))
+}
@@ -0,0 +1,36 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package event
+
+import (
+	"context"
+
+	permTypes "github.com/tsuru/tsuru/types/permission"
+)
+
+// RenameTarget changes the target, or extra target, of every finished event
+// targeting from to the target to, so the history of a renamed object is
+// kept. Running events are left untouched as they hold locks on their
+// targets.
+func RenameTarget(from, to Target) error {
+	if !from.IsValid() || !to.IsValid() {
+		return ErrValidation("event target type is mandatory")
+	}
+	store, err := eventStorage()
+	if err != nil {
+		return err
+	}
+	return store.RenameTarget(context.TODO(), from, to)
+}
+
+// RenameAllowedContext changes the permission contexts required to read and
+// cancel finished events from the context from to the context to.
+func RenameAllowedContext(from, to permTypes.PermissionContext) error {
+	store, err := eventStorage()
+	if err != nil {
+		return err
+	}
+	return store.RenameAllowedContext(context.TODO(), from, to)
+}
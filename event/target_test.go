@@ -0,0 +1,74 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package event
+
+import (
+	"github.com/tsuru/tsuru/permission"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestRenameTarget(c *check.C) {
+	oldTarget := Target{Type: TargetTypeApp, Value: "myapp"}
+	newTarget := Target{Type: TargetTypeApp, Value: "newapp"}
+	evt1, err := New(&Opts{
+		Target:  oldTarget,
+		Kind:    permission.PermAppUpdateEnvSet,
+		Owner:   s.token,
+		Allowed: Allowed(permission.PermAppReadEvents),
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(evt1.Done(nil), check.IsNil)
+	evt2, err := New(&Opts{
+		Target:       Target{Type: TargetTypePool, Value: "pool1"},
+		ExtraTargets: []ExtraTarget{{Target: oldTarget}},
+		Kind:         permission.PermPoolUpdate,
+		Owner:        s.token,
+		Allowed:      Allowed(permission.PermPoolReadEvents),
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(evt2.Done(nil), check.IsNil)
+	running, err := New(&Opts{
+		Target:  oldTarget,
+		Kind:    permission.PermAppUpdateEnvUnset,
+		Owner:   s.token,
+		Allowed: Allowed(permission.PermAppReadEvents),
+	})
+	c.Assert(err, check.IsNil)
+	defer running.Abort()
+	err = RenameTarget(oldTarget, newTarget)
+	c.Assert(err, check.IsNil)
+	evts, err := List(&Filter{Target: newTarget})
+	c.Assert(err, check.IsNil)
+	c.Assert(evts, check.HasLen, 2)
+	evts, err = List(&Filter{Target: oldTarget})
+	c.Assert(err, check.IsNil)
+	c.Assert(evts, check.HasLen, 1)
+	c.Assert(evts[0].UniqueID, check.Equals, running.UniqueID)
+}
+
+func (s *S) TestRenameTargetInvalid(c *check.C) {
+	err := RenameTarget(Target{}, Target{Type: TargetTypeApp, Value: "x"})
+	c.Assert(err, check.FitsTypeOf, ErrValidation(""))
+}
+
+func (s *S) TestRenameAllowedContext(c *check.C) {
+	evt, err := New(&Opts{
+		Target:  Target{Type: TargetTypeApp, Value: "myapp"},
+		Kind:    permission.PermAppUpdateEnvSet,
+		Owner:   s.token,
+		Allowed: Allowed(permission.PermAppReadEvents, permission.Context(permTypes.CtxApp, "myapp"), permission.Context(permTypes.CtxTeam, "team1")),
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(evt.Done(nil), check.IsNil)
+	err = RenameAllowedContext(permission.Context(permTypes.CtxApp, "myapp"), permission.Context(permTypes.CtxApp, "newapp"))
+	c.Assert(err, check.IsNil)
+	updated, err := GetByID(evt.UniqueID)
+	c.Assert(err, check.IsNil)
+	c.Assert(updated.Allowed.Contexts, check.DeepEquals, []permTypes.PermissionContext{
+		permission.Context(permTypes.CtxApp, "newapp"),
+		permission.Context(permTypes.CtxTeam, "team1"),
+	})
+}
@@ -14,16 +14,19 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"text/template"
 	"time"
 
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/tsuru/config"
 	"github.com/tsuru/tsuru/api/shutdown"
 	tsuruErrors "github.com/tsuru/tsuru/errors"
 	"github.com/tsuru/tsuru/event"
 	"github.com/tsuru/tsuru/log"
 	tsuruNet "github.com/tsuru/tsuru/net"
+	"github.com/tsuru/tsuru/permission"
 	"github.com/tsuru/tsuru/storage"
 	eventTypes "github.com/tsuru/tsuru/types/event"
 	"github.com/tsuru/tsuru/validation"
@@ -34,8 +37,56 @@ var (
 
 	chanBufferSize   = 1000
 	defaultUserAgent = "tsuru-webhook-client/1.0"
+
+	defaultRetryMaxAttempts     = 3
+	defaultRetryInitialInterval = time.Second
+	defaultRetryMaxInterval     = time.Minute
+
+	testFireKind = "webhook.test"
 )
 
+func init() {
+	event.RegisterRedactor(permission.PermWebhookCreate.FullName(), event.FormFieldsRedactor("headers"))
+	event.RegisterRedactor(permission.PermWebhookUpdate.FullName(), event.FormFieldsRedactor("headers"))
+}
+
+type retryConfig struct {
+	maxAttempts     int
+	initialInterval time.Duration
+	maxInterval     time.Duration
+}
+
+func loadRetryConfig() retryConfig {
+	cfg := retryConfig{
+		maxAttempts:     defaultRetryMaxAttempts,
+		initialInterval: defaultRetryInitialInterval,
+		maxInterval:     defaultRetryMaxInterval,
+	}
+	if attempts, err := config.GetInt("event:webhooks:retry:max-attempts"); err == nil && attempts > 0 {
+		cfg.maxAttempts = attempts
+	}
+	if interval, err := config.GetDuration("event:webhooks:retry:initial-interval"); err == nil && interval > 0 {
+		cfg.initialInterval = interval
+	}
+	if interval, err := config.GetDuration("event:webhooks:retry:max-interval"); err == nil && interval > 0 {
+		cfg.maxInterval = interval
+	}
+	return cfg
+}
+
+// backoff returns the time to wait before the given attempt, doubling the
+// initial interval on each attempt up to the configured maximum.
+func (c retryConfig) backoff(attempt int) time.Duration {
+	interval := c.initialInterval
+	for i := 1; i < attempt; i++ {
+		interval *= 2
+		if interval >= c.maxInterval {
+			return c.maxInterval
+		}
+	}
+	return interval
+}
+
 func WebhookService() (eventTypes.WebhookService, error) {
 	dbDriver, err := storage.GetCurrentDbDriver()
 	if err != nil {
@@ -46,6 +97,7 @@ func WebhookService() (eventTypes.WebhookService, error) {
 	}
 	s := &webhookService{
 		storage: dbDriver.WebhookStorage,
+		retry:   loadRetryConfig(),
 		evtCh:   make(chan string, chanBufferSize),
 		quitCh:  make(chan struct{}),
 		doneCh:  make(chan struct{}),
@@ -60,15 +112,18 @@ func WebhookService() (eventTypes.WebhookService, error) {
 }
 
 type webhookService struct {
-	storage eventTypes.WebhookStorage
-	evtCh   chan string
-	quitCh  chan struct{}
-	doneCh  chan struct{}
-
-	webhooksLatency prometheus.Histogram
-	webhooksTotal   prometheus.Counter
-	webhooksError   prometheus.Counter
-	webhooksQueue   prometheus.Collector
+	storage   eventTypes.WebhookStorage
+	retry     retryConfig
+	evtCh     chan string
+	quitCh    chan struct{}
+	doneCh    chan struct{}
+	retriesWg sync.WaitGroup
+
+	webhooksLatency    prometheus.Histogram
+	webhooksTotal      prometheus.Counter
+	webhooksError      prometheus.Counter
+	webhooksQueue      prometheus.Collector
+	webhooksDeadLetter prometheus.Counter
 }
 
 func (s *webhookService) initMetrics() error {
@@ -90,11 +145,16 @@ func (s *webhookService) initMetrics() error {
 	}, func() float64 {
 		return float64(len(s.evtCh))
 	})
+	s.webhooksDeadLetter = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "tsuru_webhooks_dead_letters_total",
+		Help: "The total number of webhooks calls moved to the dead letter collection",
+	})
 	for _, c := range []prometheus.Collector{
 		s.webhooksLatency,
 		s.webhooksTotal,
 		s.webhooksError,
 		s.webhooksQueue,
+		s.webhooksDeadLetter,
 	} {
 		err := prometheus.Register(c)
 		if err != nil {
@@ -109,12 +169,23 @@ func (s *webhookService) Shutdown(ctx context.Context) error {
 	prometheus.Unregister(s.webhooksTotal)
 	prometheus.Unregister(s.webhooksError)
 	prometheus.Unregister(s.webhooksQueue)
+	prometheus.Unregister(s.webhooksDeadLetter)
 	close(s.quitCh)
 	select {
 	case <-s.doneCh:
 	case <-ctx.Done():
 		return ctx.Err()
 	}
+	retriesDone := make(chan struct{})
+	go func() {
+		s.retriesWg.Wait()
+		close(retriesDone)
+	}()
+	select {
+	case <-retriesDone:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 	return nil
 }
 
@@ -141,7 +212,7 @@ func (s *webhookService) run() {
 }
 
 func (s *webhookService) handleEvent(evtID string) error {
-	evt, err := event.GetByHexID(evtID)
+	evt, err := redactedEvent(evtID)
 	if err != nil {
 		return err
 	}
@@ -163,11 +234,63 @@ func (s *webhookService) handleEvent(evtID string) error {
 		err = s.doHook(h, evt)
 		if err != nil {
 			log.Errorf("[webhooks] error calling webhook %q for event %q: %v", h.Name, evtID, err)
+			s.retriesWg.Add(1)
+			go s.retryHook(h, evt, err)
 		}
 	}
 	return nil
 }
 
+// retryHook keeps calling a failed webhook with exponential backoff until it
+// succeeds or the maximum number of attempts is reached, in which case the
+// delivery is stored as a dead letter.
+func (s *webhookService) retryHook(hook eventTypes.Webhook, evt *event.Event, err error) {
+	defer s.retriesWg.Done()
+	attempt := 1
+	for ; attempt < s.retry.maxAttempts; attempt++ {
+		select {
+		case <-time.After(s.retry.backoff(attempt)):
+		case <-s.quitCh:
+			s.storeDeadLetter(hook, evt, attempt, err)
+			return
+		}
+		err = s.doHook(hook, evt)
+		if err == nil {
+			return
+		}
+		log.Errorf("[webhooks] error calling webhook %q for event %q (attempt %d): %v", hook.Name, evt.UniqueID.Hex(), attempt+1, err)
+	}
+	s.storeDeadLetter(hook, evt, attempt, err)
+}
+
+func (s *webhookService) storeDeadLetter(hook eventTypes.Webhook, evt *event.Event, attempts int, hookErr error) {
+	s.webhooksDeadLetter.Inc()
+	err := s.storage.InsertDeadLetter(eventTypes.WebhookDeadLetter{
+		Webhook:   hook.Name,
+		EventID:   evt.UniqueID.Hex(),
+		Attempts:  attempts,
+		Error:     hookErr.Error(),
+		CreatedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		log.Errorf("[webhooks] unable to store dead letter for webhook %q and event %q: %v", hook.Name, evt.UniqueID.Hex(), err)
+	}
+}
+
+// redactedEvent returns the event with its sensitive data redacted, so that
+// it's never sent to webhooks.
+func redactedEvent(evtID string) (*event.Event, error) {
+	evt, err := event.GetByHexID(evtID)
+	if err != nil {
+		return nil, err
+	}
+	err = event.Redact(evt)
+	if err != nil {
+		return nil, err
+	}
+	return evt, nil
+}
+
 func webhookBody(hook *eventTypes.Webhook, evt *event.Event) (io.Reader, error) {
 	if hook.Body != "" {
 		tpl, err := template.New(hook.Name).Parse(hook.Body)
@@ -307,3 +430,61 @@ func (s *webhookService) Find(name string) (eventTypes.Webhook, error) {
 func (s *webhookService) List(teams []string) ([]eventTypes.Webhook, error) {
 	return s.storage.FindAllByTeams(teams)
 }
+
+func (s *webhookService) DeadLetters(webhookName string) ([]eventTypes.WebhookDeadLetter, error) {
+	return s.storage.FindDeadLetters(webhookName)
+}
+
+// Redispatch delivers again the event stored in the dead letter, the dead
+// letter is only found if it belongs to the webhook.
+func (s *webhookService) Redispatch(webhookName, deadLetterID string) error {
+	deadLetter, err := s.storage.FindDeadLetter(deadLetterID)
+	if err != nil {
+		return err
+	}
+	if deadLetter.Webhook != webhookName {
+		return eventTypes.ErrDeadLetterNotFound
+	}
+	hook, err := s.storage.FindByName(deadLetter.Webhook)
+	if err != nil {
+		return err
+	}
+	evt, err := redactedEvent(deadLetter.EventID)
+	if err != nil {
+		return err
+	}
+	err = s.doHook(*hook, evt)
+	if err != nil {
+		return err
+	}
+	return s.storage.DeleteDeadLetter(deadLetterID)
+}
+
+// Dispatch delivers a past event to the webhook, regardless of the webhook
+// event filter.
+func (s *webhookService) Dispatch(webhookName, eventID string) error {
+	hook, err := s.storage.FindByName(webhookName)
+	if err != nil {
+		return err
+	}
+	evt, err := redactedEvent(eventID)
+	if err != nil {
+		return err
+	}
+	return s.doHook(*hook, evt)
+}
+
+// TestFire delivers a synthetic event targeting the webhook itself, allowing
+// users to validate their receivers.
+func (s *webhookService) TestFire(webhookName, owner string) error {
+	hook, err := s.storage.FindByName(webhookName)
+	if err != nil {
+		return err
+	}
+	evt := event.NewSynthetic(
+		event.Target{Type: event.TargetTypeWebhook, Value: hook.Name},
+		testFireKind,
+		event.Owner{Type: event.OwnerTypeUser, Name: owner},
+	)
+	return s.doHook(*hook, evt)
+}
@@ -11,7 +11,10 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/tsuru/config"
 	"github.com/tsuru/tsuru/db"
@@ -387,3 +390,238 @@ func (s *S) TestWebhookServiceDeleteNotFound(c *check.C) {
 	err := s.service.Delete("xyz")
 	c.Assert(err, check.Equals, eventTypes.ErrWebhookNotFound)
 }
+
+func (s *S) TestRetryConfigBackoff(c *check.C) {
+	cfg := retryConfig{maxAttempts: 5, initialInterval: time.Second, maxInterval: 5 * time.Second}
+	c.Assert(cfg.backoff(1), check.Equals, time.Second)
+	c.Assert(cfg.backoff(2), check.Equals, 2*time.Second)
+	c.Assert(cfg.backoff(3), check.Equals, 4*time.Second)
+	c.Assert(cfg.backoff(4), check.Equals, 5*time.Second)
+	c.Assert(cfg.backoff(10), check.Equals, 5*time.Second)
+}
+
+func (s *S) TestLoadRetryConfig(c *check.C) {
+	c.Assert(loadRetryConfig(), check.Equals, retryConfig{
+		maxAttempts:     defaultRetryMaxAttempts,
+		initialInterval: defaultRetryInitialInterval,
+		maxInterval:     defaultRetryMaxInterval,
+	})
+	config.Set("event:webhooks:retry:max-attempts", 5)
+	config.Set("event:webhooks:retry:initial-interval", "2s")
+	config.Set("event:webhooks:retry:max-interval", "30s")
+	defer config.Unset("event:webhooks")
+	c.Assert(loadRetryConfig(), check.Equals, retryConfig{
+		maxAttempts:     5,
+		initialInterval: 2 * time.Second,
+		maxInterval:     30 * time.Second,
+	})
+}
+
+func (s *S) TestWebhookServiceNotifyRetry(c *check.C) {
+	s.service.retry = retryConfig{maxAttempts: 3, initialInterval: time.Millisecond, maxInterval: time.Millisecond}
+	evt, err := event.New(&event.Opts{
+		Target:   event.Target{Type: "app", Value: "myapp"},
+		RawOwner: event.Owner{Type: "user", Name: "me@me.com"},
+		Kind:     permission.PermAppUpdateEnvSet,
+		Allowed:  event.Allowed(permission.PermAppReadEvents, permission.Context(permTypes.CtxApp, "myapp")),
+	})
+	c.Assert(err, check.IsNil)
+	err = evt.Done(nil)
+	c.Assert(err, check.IsNil)
+	var calls int32
+	called := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		close(called)
+	}))
+	defer srv.Close()
+	err = s.service.storage.Insert(eventTypes.Webhook{Name: "xyz", URL: srv.URL})
+	c.Assert(err, check.IsNil)
+	s.service.Notify(evt.UniqueID.Hex())
+	<-called
+	s.service.retriesWg.Wait()
+	c.Assert(atomic.LoadInt32(&calls), check.Equals, int32(3))
+	deadLetters, err := s.service.DeadLetters("xyz")
+	c.Assert(err, check.IsNil)
+	c.Assert(deadLetters, check.HasLen, 0)
+}
+
+func (s *S) TestWebhookServiceNotifyDeadLetter(c *check.C) {
+	s.service.retry = retryConfig{maxAttempts: 2, initialInterval: time.Millisecond, maxInterval: time.Millisecond}
+	evt, err := event.New(&event.Opts{
+		Target:   event.Target{Type: "app", Value: "myapp"},
+		RawOwner: event.Owner{Type: "user", Name: "me@me.com"},
+		Kind:     permission.PermAppUpdateEnvSet,
+		Allowed:  event.Allowed(permission.PermAppReadEvents, permission.Context(permTypes.CtxApp, "myapp")),
+	})
+	c.Assert(err, check.IsNil)
+	err = evt.Done(nil)
+	c.Assert(err, check.IsNil)
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+	err = s.service.storage.Insert(eventTypes.Webhook{Name: "xyz", URL: srv.URL})
+	c.Assert(err, check.IsNil)
+	s.service.Notify(evt.UniqueID.Hex())
+	timeout := time.After(5 * time.Second)
+	var deadLetters []eventTypes.WebhookDeadLetter
+	for len(deadLetters) == 0 {
+		select {
+		case <-timeout:
+			c.Fatal("timeout waiting for dead letter")
+		case <-time.After(10 * time.Millisecond):
+		}
+		deadLetters, err = s.service.DeadLetters("xyz")
+		c.Assert(err, check.IsNil)
+	}
+	c.Assert(atomic.LoadInt32(&calls), check.Equals, int32(2))
+	c.Assert(deadLetters, check.HasLen, 1)
+	c.Assert(deadLetters[0].EventID, check.Equals, evt.UniqueID.Hex())
+	c.Assert(deadLetters[0].Attempts, check.Equals, 2)
+	c.Assert(deadLetters[0].Error, check.Equals, "invalid status code calling hook: 502: ")
+}
+
+func (s *S) TestWebhookServiceRedispatch(c *check.C) {
+	evt, err := event.New(&event.Opts{
+		Target:   event.Target{Type: "app", Value: "myapp"},
+		RawOwner: event.Owner{Type: "user", Name: "me@me.com"},
+		Kind:     permission.PermAppUpdateEnvSet,
+		Allowed:  event.Allowed(permission.PermAppReadEvents, permission.Context(permTypes.CtxApp, "myapp")),
+	})
+	c.Assert(err, check.IsNil)
+	err = evt.Done(nil)
+	c.Assert(err, check.IsNil)
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+	}))
+	defer srv.Close()
+	err = s.service.storage.Insert(eventTypes.Webhook{Name: "xyz", URL: srv.URL})
+	c.Assert(err, check.IsNil)
+	err = s.service.storage.InsertDeadLetter(eventTypes.WebhookDeadLetter{
+		ID:      "dl1",
+		Webhook: "xyz",
+		EventID: evt.UniqueID.Hex(),
+	})
+	c.Assert(err, check.IsNil)
+	err = s.service.Redispatch("xyz", "dl1")
+	c.Assert(err, check.IsNil)
+	c.Assert(atomic.LoadInt32(&calls), check.Equals, int32(1))
+	_, err = s.service.storage.FindDeadLetter("dl1")
+	c.Assert(err, check.Equals, eventTypes.ErrDeadLetterNotFound)
+}
+
+func (s *S) TestWebhookServiceRedispatchNotFound(c *check.C) {
+	err := s.service.Redispatch("xyz", "dl1")
+	c.Assert(err, check.Equals, eventTypes.ErrDeadLetterNotFound)
+}
+
+func (s *S) TestWebhookServiceRedispatchOtherWebhook(c *check.C) {
+	err := s.service.storage.InsertDeadLetter(eventTypes.WebhookDeadLetter{
+		ID:      "dl1",
+		Webhook: "other",
+		EventID: "evt1",
+	})
+	c.Assert(err, check.IsNil)
+	err = s.service.Redispatch("xyz", "dl1")
+	c.Assert(err, check.Equals, eventTypes.ErrDeadLetterNotFound)
+	_, err = s.service.storage.FindDeadLetter("dl1")
+	c.Assert(err, check.IsNil)
+}
+
+func (s *S) TestWebhookServiceDispatch(c *check.C) {
+	evt, err := event.New(&event.Opts{
+		Target:   event.Target{Type: "app", Value: "myapp"},
+		RawOwner: event.Owner{Type: "user", Name: "me@me.com"},
+		Kind:     permission.PermAppUpdateEnvSet,
+		Allowed:  event.Allowed(permission.PermAppReadEvents, permission.Context(permTypes.CtxApp, "myapp")),
+	})
+	c.Assert(err, check.IsNil)
+	err = evt.Done(nil)
+	c.Assert(err, check.IsNil)
+	var received event.Event
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+	}))
+	defer srv.Close()
+	err = s.service.storage.Insert(eventTypes.Webhook{
+		Name:        "xyz",
+		URL:         srv.URL,
+		EventFilter: eventTypes.WebhookEventFilter{KindNames: []string{"other.kind"}},
+	})
+	c.Assert(err, check.IsNil)
+	err = s.service.Dispatch("xyz", evt.UniqueID.Hex())
+	c.Assert(err, check.IsNil)
+	c.Assert(received.UniqueID, check.Equals, evt.UniqueID)
+}
+
+func (s *S) TestWebhookServiceDispatchRedactsEvent(c *check.C) {
+	evt, err := event.New(&event.Opts{
+		Target:   event.Target{Type: event.TargetTypeWebhook, Value: "wh1"},
+		RawOwner: event.Owner{Type: "user", Name: "me@me.com"},
+		Kind:     permission.PermWebhookCreate,
+		CustomData: []map[string]interface{}{
+			{"name": "name", "value": "wh1"},
+			{"name": "headers.Authorization.0", "value": "Bearer secret-token"},
+		},
+		Allowed: event.Allowed(permission.PermWebhookReadEvents, permission.Context(permTypes.CtxTeam, "myteam")),
+	})
+	c.Assert(err, check.IsNil)
+	err = evt.Done(nil)
+	c.Assert(err, check.IsNil)
+	var body []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = ioutil.ReadAll(r.Body)
+	}))
+	defer srv.Close()
+	err = s.service.storage.Insert(eventTypes.Webhook{Name: "xyz", URL: srv.URL})
+	c.Assert(err, check.IsNil)
+	err = s.service.Dispatch("xyz", evt.UniqueID.Hex())
+	c.Assert(err, check.IsNil)
+	c.Assert(len(body) > 0, check.Equals, true)
+	c.Assert(strings.Contains(string(body), "secret-token"), check.Equals, false)
+}
+
+func (s *S) TestWebhookServiceDispatchNotFound(c *check.C) {
+	err := s.service.Dispatch("xyz", "5c4f1c4f4f3e4a0001000001")
+	c.Assert(err, check.Equals, eventTypes.ErrWebhookNotFound)
+	err = s.service.storage.Insert(eventTypes.Webhook{Name: "xyz", URL: "http://localhost"})
+	c.Assert(err, check.IsNil)
+	err = s.service.Dispatch("xyz", "5c4f1c4f4f3e4a0001000001")
+	c.Assert(err, check.Equals, event.ErrEventNotFound)
+}
+
+func (s *S) TestWebhookServiceTestFire(c *check.C) {
+	var received event.Event
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+	}))
+	defer srv.Close()
+	err := s.service.storage.Insert(eventTypes.Webhook{Name: "xyz", URL: srv.URL})
+	c.Assert(err, check.IsNil)
+	err = s.service.TestFire("xyz", "me@me.com")
+	c.Assert(err, check.IsNil)
+	c.Assert(received.Target, check.Equals, event.Target{Type: event.TargetTypeWebhook, Value: "xyz"})
+	c.Assert(received.Kind, check.Equals, event.Kind{Type: event.KindTypeInternal, Name: "webhook.test"})
+	c.Assert(received.Owner, check.Equals, event.Owner{Type: event.OwnerTypeUser, Name: "me@me.com"})
+	evts, err := event.All()
+	c.Assert(err, check.IsNil)
+	c.Assert(evts, check.HasLen, 0)
+}
+
+func (s *S) TestWebhookServiceTestFireError(c *check.C) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+	err := s.service.storage.Insert(eventTypes.Webhook{Name: "xyz", URL: srv.URL})
+	c.Assert(err, check.IsNil)
+	err = s.service.TestFire("xyz", "me@me.com")
+	c.Assert(err, check.ErrorMatches, "invalid status code calling hook: 500: ")
+}
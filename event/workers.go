@@ -5,13 +5,12 @@
 package event
 
 import (
+	"context"
 	"sync"
 	"time"
 
-	"github.com/globalsign/mgo"
-	"github.com/globalsign/mgo/bson"
 	"github.com/pkg/errors"
-	"github.com/tsuru/tsuru/db"
+	tsuruErrors "github.com/tsuru/tsuru/errors"
 	"github.com/tsuru/tsuru/log"
 )
 
@@ -49,18 +48,12 @@ func (l *eventCleaner) stop() {
 }
 
 func (l *eventCleaner) tryCleaning() error {
-	conn, err := db.Conn()
+	store, err := eventStorage()
 	if err != nil {
-		return errors.Wrap(err, "[events] [event cleaner] error getting db conn")
+		return errors.Wrap(err, "[events] [event cleaner] error getting event storage")
 	}
 	now := time.Now().UTC()
-	coll := conn.Events()
-	var allData []eventData
-	err = coll.Find(bson.M{
-		"running":        true,
-		"lockupdatetime": bson.M{"$lt": now.Add(-lockExpireTimeout)},
-	}).All(&allData)
-	conn.Close()
+	allData, err := store.FindExpired(context.TODO(), now.Add(-lockExpireTimeout))
 	if err != nil {
 		return errors.Wrap(err, "[events] [event cleaner] error updating expired events")
 	}
@@ -91,6 +84,36 @@ func (l *eventCleaner) spin() {
 	}
 }
 
+// MarkInterrupted marks the events still running in this process as done
+// with the reason error. It's called when the API is shutting down, so that
+// events interrupted by the shutdown aren't left running until they expire.
+func MarkInterrupted(reason error) error {
+	set := updater.setCopy()
+	if len(set) == 0 {
+		return nil
+	}
+	ids := make([]eventID, 0, len(set))
+	for id := range set {
+		ids = append(ids, id)
+	}
+	store, err := eventStorage()
+	if err != nil {
+		return errors.Wrap(err, "[events] [interrupt] error getting event storage")
+	}
+	allData, err := store.FindRunningByIDs(context.TODO(), ids)
+	if err != nil {
+		return errors.Wrap(err, "[events] [interrupt] error listing running events")
+	}
+	multiErr := tsuruErrors.NewMultiError()
+	for _, evtData := range allData {
+		evt := Event{eventData: evtData}
+		if err = evt.Done(reason); err != nil {
+			multiErr.Add(errors.Wrapf(err, "[events] [interrupt] error marking event %s as done", evt.UniqueID.Hex()))
+		}
+	}
+	return multiErr.ToError()
+}
+
 type lockUpdater struct {
 	stopCh chan struct{}
 	once   *sync.Once
@@ -148,22 +171,18 @@ func (l *lockUpdater) spin() {
 		if len(set) == 0 {
 			continue
 		}
-		conn, err := db.Conn()
+		store, err := eventStorage()
 		if err != nil {
-			log.Errorf("[events] [lock update] error getting db conn: %s", err)
+			log.Errorf("[events] [lock update] error getting event storage: %s", err)
 			continue
 		}
-		coll := conn.Events()
-		slice := make([]interface{}, len(set))
-		i := 0
+		ids := make([]eventID, 0, len(set))
 		for id := range set {
-			slice[i], _ = id.GetBSON()
-			i++
+			ids = append(ids, id)
 		}
-		_, err = coll.UpdateAll(bson.M{"_id": bson.M{"$in": slice}}, bson.M{"$set": bson.M{"lockupdatetime": time.Now().UTC()}})
-		if err != nil && err != mgo.ErrNotFound {
+		err = store.UpdateLockTime(context.TODO(), ids, time.Now().UTC())
+		if err != nil {
 			log.Errorf("[events] [lock update] error updating: %s", err)
 		}
-		conn.Close()
 	}
 }
@@ -211,3 +211,32 @@ func (s *S) TestEventCleaner(c *check.C) {
 	c.Assert(evts[0].Running, check.Equals, false)
 	c.Assert(evts[0].Error, check.Matches, `event expired, no update for .*ms`)
 }
+
+func (s *S) TestMarkInterrupted(c *check.C) {
+	running, err := New(&Opts{
+		Target:  Target{Type: "app", Value: "myapp"},
+		Kind:    permission.PermAppDeploy,
+		Owner:   s.token,
+		Allowed: Allowed(permission.PermAppReadEvents),
+	})
+	c.Assert(err, check.IsNil)
+	finished, err := New(&Opts{
+		Target:  Target{Type: "app", Value: "otherapp"},
+		Kind:    permission.PermAppDeploy,
+		Owner:   s.token,
+		Allowed: Allowed(permission.PermAppReadEvents),
+	})
+	c.Assert(err, check.IsNil)
+	err = finished.Done(nil)
+	c.Assert(err, check.IsNil)
+	err = MarkInterrupted(fmt.Errorf("interrupted by API shutdown"))
+	c.Assert(err, check.IsNil)
+	evt, err := GetByID(running.UniqueID)
+	c.Assert(err, check.IsNil)
+	c.Assert(evt.Running, check.Equals, false)
+	c.Assert(evt.Error, check.Equals, "interrupted by API shutdown")
+	evt, err = GetByID(finished.UniqueID)
+	c.Assert(err, check.IsNil)
+	c.Assert(evt.Error, check.Equals, "")
+	c.Assert(updater.setCopy(), check.HasLen, 0)
+}
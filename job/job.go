@@ -0,0 +1,254 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package job
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/provision"
+	"github.com/tsuru/tsuru/provision/pool"
+	"github.com/tsuru/tsuru/servicemanager"
+	"github.com/tsuru/tsuru/set"
+	"github.com/tsuru/tsuru/storage"
+	jobTypes "github.com/tsuru/tsuru/types/job"
+	"github.com/tsuru/tsuru/validation"
+)
+
+var (
+	ErrJobNotSupported = &tsuruErrors.ValidationError{Message: "the pool provisioner does not support jobs"}
+	ErrJobNotCron      = &tsuruErrors.ValidationError{Message: "only cronjobs can be triggered"}
+)
+
+const (
+	maxRetryLimit   = 10
+	maxRetryBackoff = 10 * time.Minute
+)
+
+type jobService struct {
+	storage jobTypes.JobStorage
+}
+
+var _ jobTypes.JobService = &jobService{}
+
+func JobService() (jobTypes.JobService, error) {
+	dbDriver, err := storage.GetCurrentDbDriver()
+	if err != nil {
+		dbDriver, err = storage.GetDefaultDbDriver()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &jobService{storage: dbDriver.JobStorage}, nil
+}
+
+// Create validates and stores the job, then provisions it. Cronjobs are
+// scheduled, one-off jobs only run when Run is called.
+func (s *jobService) Create(ctx context.Context, job *jobTypes.Job) error {
+	prov, err := s.validate(ctx, job)
+	if err != nil {
+		return err
+	}
+	job.CreatedAt = time.Now().UTC()
+	err = s.storage.Insert(ctx, *job)
+	if err != nil {
+		return err
+	}
+	err = prov.CreateJob(ctx, job)
+	if err != nil {
+		if rmErr := s.storage.Remove(ctx, job.Name); rmErr != nil {
+			return errors.Wrapf(err, "unable to remove job after provisioning failure: %v", rmErr)
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *jobService) Get(ctx context.Context, name string) (*jobTypes.Job, error) {
+	return s.storage.Get(ctx, name)
+}
+
+func (s *jobService) List(ctx context.Context, f *jobTypes.Filter) ([]jobTypes.Job, error) {
+	return s.storage.List(ctx, f)
+}
+
+// Run runs the job once, cronjobs are run regardless of their schedule.
+// Failed attempts are retried according to the job retry policy, the
+// outcome of each attempt is written to w. Each attempt must fit in the job
+// quotas of the team owner.
+func (s *jobService) Run(ctx context.Context, job *jobTypes.Job, w io.Writer) error {
+	prov, err := jobProvisioner(ctx, job.Pool)
+	if err != nil {
+		return err
+	}
+	attempts := job.Retry.Limit + 1
+	backoff := time.Duration(job.Retry.BackoffSeconds) * time.Second
+	for attempt := 1; ; attempt++ {
+		err = checkTeamQuota(ctx, job)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "---- Running job %q, attempt %d of %d ----\n", job.Name, attempt, attempts)
+		err = prov.RunJob(ctx, job)
+		if err == nil {
+			fmt.Fprintf(w, "---- Attempt %d succeeded ----\n", attempt)
+			return nil
+		}
+		fmt.Fprintf(w, "---- Attempt %d failed: %v ----\n", attempt, err)
+		if attempt >= attempts {
+			return err
+		}
+		if backoff > 0 {
+			fmt.Fprintf(w, "---- Retrying in %v ----\n", backoff)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+			if backoff > maxRetryBackoff {
+				backoff = maxRetryBackoff
+			}
+		}
+	}
+}
+
+// Trigger starts a run of the cronjob right away, regardless of its schedule.
+// Unlike Run, the run is created from the provisioned cronjob and it's not
+// waited for. The run must fit in the job quotas of the team owner.
+func (s *jobService) Trigger(ctx context.Context, job *jobTypes.Job) error {
+	if !job.IsCron() {
+		return ErrJobNotCron
+	}
+	prov, err := jobProvisioner(ctx, job.Pool)
+	if err != nil {
+		return err
+	}
+	err = checkTeamQuota(ctx, job)
+	if err != nil {
+		return err
+	}
+	return prov.TriggerCronJob(ctx, job)
+}
+
+// Remove removes the job schedule and runs before removing the job.
+func (s *jobService) Remove(ctx context.Context, job *jobTypes.Job) error {
+	prov, err := jobProvisioner(ctx, job.Pool)
+	if err != nil {
+		return err
+	}
+	err = prov.DestroyJob(ctx, job)
+	if err != nil {
+		return err
+	}
+	return s.storage.Remove(ctx, job.Name)
+}
+
+func jobProvisioner(ctx context.Context, poolName string) (provision.JobProvisioner, error) {
+	p, err := pool.GetPoolByName(ctx, poolName)
+	if err != nil {
+		return nil, err
+	}
+	prov, err := p.GetProvisioner()
+	if err != nil {
+		return nil, err
+	}
+	jobProv, ok := prov.(provision.JobProvisioner)
+	if !ok {
+		return nil, ErrJobNotSupported
+	}
+	return jobProv, nil
+}
+
+func (s *jobService) validate(ctx context.Context, job *jobTypes.Job) (provision.JobProvisioner, error) {
+	if !validation.ValidateName(job.Name) {
+		msg := "Invalid job name, job name should have at most 40 " +
+			"characters, containing only lower case letters, numbers or dashes, " +
+			"starting with a letter."
+		return nil, &tsuruErrors.ValidationError{Message: msg}
+	}
+	if job.Image == "" {
+		return nil, &tsuruErrors.ValidationError{Message: "job image is required"}
+	}
+	if job.IsCron() && len(strings.Fields(job.Schedule)) != 5 {
+		return nil, &tsuruErrors.ValidationError{
+			Message: fmt.Sprintf("invalid job schedule %q, it must be a cron expression with 5 fields", job.Schedule),
+		}
+	}
+	switch job.ConcurrencyPolicy {
+	case "", jobTypes.ConcurrencyForbid, jobTypes.ConcurrencyAllow, jobTypes.ConcurrencyReplace:
+	default:
+		return nil, &tsuruErrors.ValidationError{
+			Message: fmt.Sprintf("invalid job concurrency policy %q, valid policies are: %s, %s and %s", job.ConcurrencyPolicy, jobTypes.ConcurrencyForbid, jobTypes.ConcurrencyAllow, jobTypes.ConcurrencyReplace),
+		}
+	}
+	if job.ConcurrencyPolicy != "" && !job.IsCron() {
+		return nil, &tsuruErrors.ValidationError{Message: "concurrency policy is only supported by cronjobs"}
+	}
+	if job.Retry.Limit < 0 || job.Retry.Limit > maxRetryLimit {
+		return nil, &tsuruErrors.ValidationError{
+			Message: fmt.Sprintf("job retry limit must be between 0 and %d", maxRetryLimit),
+		}
+	}
+	if job.Retry.BackoffSeconds < 0 {
+		return nil, &tsuruErrors.ValidationError{Message: "job retry backoff must not be negative"}
+	}
+	_, err := servicemanager.Team.FindByName(ctx, job.TeamOwner)
+	if err != nil {
+		return nil, &tsuruErrors.ValidationError{Message: err.Error()}
+	}
+	var p *pool.Pool
+	if job.Pool == "" {
+		p, err = pool.GetDefaultPool(ctx)
+	} else {
+		p, err = pool.GetPoolByName(ctx, job.Pool)
+	}
+	if err != nil {
+		return nil, &tsuruErrors.ValidationError{Message: err.Error()}
+	}
+	job.Pool = p.Name
+	poolTeams, err := p.GetTeams()
+	if err != nil && err != pool.ErrPoolHasNoTeam {
+		return nil, &tsuruErrors.ValidationError{Message: fmt.Sprintf("failed to get pool %q teams", p.Name)}
+	}
+	var allowed bool
+	for _, team := range poolTeams {
+		if team == job.TeamOwner {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return nil, &tsuruErrors.ValidationError{
+			Message: fmt.Sprintf("Job team owner %q has no access to pool %q", job.TeamOwner, p.Name),
+		}
+	}
+	plan, err := p.GetDefaultJobPlan()
+	if err != nil {
+		return nil, err
+	}
+	if job.Plan.Name != "" {
+		plan, err = servicemanager.Plan.FindByName(ctx, job.Plan.Name)
+		if err != nil {
+			return nil, &tsuruErrors.ValidationError{Message: err.Error()}
+		}
+		plans, err := p.GetJobPlans()
+		if err != nil && err != pool.ErrPoolHasNoJobPlan {
+			return nil, err
+		}
+		if !set.FromSlice(plans).Includes(plan.Name) {
+			return nil, &tsuruErrors.ValidationError{
+				Message: fmt.Sprintf("Job plan %q is not allowed on pool %q", plan.Name, p.Name),
+			}
+		}
+	}
+	job.Plan = *plan
+	return jobProvisioner(ctx, job.Pool)
+}
@@ -0,0 +1,259 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package job
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"time"
+
+	tsuruErrors "github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/provision/pool"
+	"github.com/tsuru/tsuru/provision/provisiontest"
+	"github.com/tsuru/tsuru/servicemanager"
+	appTypes "github.com/tsuru/tsuru/types/app"
+	jobTypes "github.com/tsuru/tsuru/types/job"
+	quotaTypes "github.com/tsuru/tsuru/types/quota"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestCreateOneOffJob(c *check.C) {
+	job := &jobTypes.Job{
+		Name:      "myjob",
+		TeamOwner: "myteam",
+		Image:     "myimage:v1",
+		Command:   []string{"./run.sh"},
+	}
+	err := s.service.Create(context.TODO(), job)
+	c.Assert(err, check.IsNil)
+	c.Assert(job.Pool, check.Equals, "mypool")
+	c.Assert(job.Plan.Name, check.Equals, "default")
+	c.Assert(job.CreatedAt.IsZero(), check.Equals, false)
+	dbJob, err := s.service.Get(context.TODO(), "myjob")
+	c.Assert(err, check.IsNil)
+	c.Assert(dbJob.Image, check.Equals, "myimage:v1")
+	c.Assert(dbJob.Plan.Name, check.Equals, "default")
+	c.Assert(provisiontest.ProvisionerInstance.JobRuns("myjob"), check.Equals, 0)
+	_, isCron := provisiontest.ProvisionerInstance.CronJob("myjob")
+	c.Assert(isCron, check.Equals, false)
+}
+
+func (s *S) TestCreateCronJob(c *check.C) {
+	job := &jobTypes.Job{
+		Name:      "mycron",
+		TeamOwner: "otherteam",
+		Pool:      "privatepool",
+		Plan:      appTypes.Plan{Name: "large"},
+		Image:     "myimage:v1",
+		Schedule:  "0 * * * *",
+	}
+	err := s.service.Create(context.TODO(), job)
+	c.Assert(err, check.IsNil)
+	c.Assert(job.Plan.Memory, check.Equals, int64(4096))
+	schedule, isCron := provisiontest.ProvisionerInstance.CronJob("mycron")
+	c.Assert(isCron, check.Equals, true)
+	c.Assert(schedule, check.Equals, "0 * * * *")
+	c.Assert(provisiontest.ProvisionerInstance.JobRuns("mycron"), check.Equals, 0)
+}
+
+func (s *S) TestCreateJobValidation(c *check.C) {
+	tests := []struct {
+		job      jobTypes.Job
+		expected string
+	}{
+		{
+			job:      jobTypes.Job{Name: "Invalid_Name", TeamOwner: "myteam", Image: "img"},
+			expected: "Invalid job name, job name should have at most 40 characters, containing only lower case letters, numbers or dashes, starting with a letter.",
+		},
+		{
+			job:      jobTypes.Job{Name: "myjob", TeamOwner: "myteam"},
+			expected: "job image is required",
+		},
+		{
+			job:      jobTypes.Job{Name: "myjob", TeamOwner: "myteam", Image: "img", Schedule: "* * *"},
+			expected: `invalid job schedule "* * *", it must be a cron expression with 5 fields`,
+		},
+		{
+			job:      jobTypes.Job{Name: "myjob", TeamOwner: "myteam", Image: "img", Schedule: "* * * * *", ConcurrencyPolicy: "Sometimes"},
+			expected: `invalid job concurrency policy "Sometimes", valid policies are: Forbid, Allow and Replace`,
+		},
+		{
+			job:      jobTypes.Job{Name: "myjob", TeamOwner: "myteam", Image: "img", ConcurrencyPolicy: jobTypes.ConcurrencyAllow},
+			expected: "concurrency policy is only supported by cronjobs",
+		},
+		{
+			job:      jobTypes.Job{Name: "myjob", TeamOwner: "myteam", Image: "img", Retry: jobTypes.RetryPolicy{Limit: 11}},
+			expected: "job retry limit must be between 0 and 10",
+		},
+		{
+			job:      jobTypes.Job{Name: "myjob", TeamOwner: "myteam", Image: "img", Retry: jobTypes.RetryPolicy{BackoffSeconds: -1}},
+			expected: "job retry backoff must not be negative",
+		},
+		{
+			job:      jobTypes.Job{Name: "myjob", TeamOwner: "unknown", Image: "img"},
+			expected: "team not found",
+		},
+		{
+			job:      jobTypes.Job{Name: "myjob", TeamOwner: "myteam", Image: "img", Pool: "privatepool"},
+			expected: `Job team owner "myteam" has no access to pool "privatepool"`,
+		},
+		{
+			job:      jobTypes.Job{Name: "myjob", TeamOwner: "myteam", Image: "img", Plan: appTypes.Plan{Name: "unknown"}},
+			expected: "plan not found",
+		},
+	}
+	for _, tt := range tests {
+		job := tt.job
+		err := s.service.Create(context.TODO(), &job)
+		c.Check(err, check.FitsTypeOf, &tsuruErrors.ValidationError{})
+		c.Check(err, check.ErrorMatches, tt.expected)
+	}
+	jobs, err := s.service.List(context.TODO(), nil)
+	c.Assert(err, check.IsNil)
+	c.Assert(jobs, check.HasLen, 0)
+}
+
+func (s *S) TestCreateJobAlreadyExists(c *check.C) {
+	job := jobTypes.Job{Name: "myjob", TeamOwner: "myteam", Image: "img"}
+	err := s.service.Create(context.TODO(), &job)
+	c.Assert(err, check.IsNil)
+	job = jobTypes.Job{Name: "myjob", TeamOwner: "myteam", Image: "img"}
+	err = s.service.Create(context.TODO(), &job)
+	c.Assert(err, check.Equals, jobTypes.ErrJobAlreadyExists)
+}
+
+func (s *S) TestCreateJobProvisionFailure(c *check.C) {
+	provisiontest.ProvisionerInstance.PrepareFailure("CreateJob", errors.New("provision failed"))
+	job := jobTypes.Job{Name: "myjob", TeamOwner: "myteam", Image: "img"}
+	err := s.service.Create(context.TODO(), &job)
+	c.Assert(err, check.ErrorMatches, "provision failed")
+	_, err = s.service.Get(context.TODO(), "myjob")
+	c.Assert(err, check.Equals, jobTypes.ErrJobNotFound)
+}
+
+func (s *S) TestCreateJobPlanNotAllowed(c *check.C) {
+	err := pool.SetPoolConstraint(&pool.PoolConstraint{PoolExpr: "mypool", Field: pool.ConstraintTypeJobPlan, Values: []string{"default"}})
+	c.Assert(err, check.IsNil)
+	job := jobTypes.Job{Name: "myjob", TeamOwner: "myteam", Image: "img", Plan: appTypes.Plan{Name: "large"}}
+	err = s.service.Create(context.TODO(), &job)
+	c.Assert(err, check.FitsTypeOf, &tsuruErrors.ValidationError{})
+	c.Assert(err, check.ErrorMatches, `Job plan "large" is not allowed on pool "mypool"`)
+}
+
+func (s *S) TestCreateJobDefaultJobPlan(c *check.C) {
+	err := pool.SetPoolConstraint(&pool.PoolConstraint{PoolExpr: "mypool", Field: pool.ConstraintTypeJobPlan, Values: []string{"large"}})
+	c.Assert(err, check.IsNil)
+	job := jobTypes.Job{Name: "myjob", TeamOwner: "myteam", Image: "img"}
+	err = s.service.Create(context.TODO(), &job)
+	c.Assert(err, check.IsNil)
+	c.Assert(job.Plan.Name, check.Equals, "large")
+}
+
+func (s *S) TestRunJob(c *check.C) {
+	job := jobTypes.Job{Name: "mycron", TeamOwner: "myteam", Image: "img", Schedule: "0 * * * *"}
+	err := s.service.Create(context.TODO(), &job)
+	c.Assert(err, check.IsNil)
+	var buf bytes.Buffer
+	err = s.service.Run(context.TODO(), &job, &buf)
+	c.Assert(err, check.IsNil)
+	c.Assert(provisiontest.ProvisionerInstance.JobRuns("mycron"), check.Equals, 1)
+	c.Assert(buf.String(), check.Equals, "---- Running job \"mycron\", attempt 1 of 1 ----\n---- Attempt 1 succeeded ----\n")
+}
+
+func (s *S) TestRunJobQuotaExceeded(c *check.C) {
+	job := jobTypes.Job{Name: "mycron", TeamOwner: "myteam", Image: "img", Schedule: "0 * * * *"}
+	err := s.service.Create(context.TODO(), &job)
+	c.Assert(err, check.IsNil)
+	servicemanager.JobMemoryQuota = &quotaTypes.MockQuotaService{
+		OnInc: func(item quotaTypes.QuotaItem, delta int) error {
+			c.Assert(item.GetName(), check.Equals, "myteam")
+			c.Assert(delta, check.Equals, 1)
+			return &quotaTypes.QuotaExceededError{Available: 0, Requested: 1}
+		},
+	}
+	var buf bytes.Buffer
+	err = s.service.Run(context.TODO(), &job, &buf)
+	c.Assert(err, check.FitsTypeOf, &quotaTypes.QuotaExceededError{})
+	c.Assert(provisiontest.ProvisionerInstance.JobRuns("mycron"), check.Equals, 0)
+	err = s.service.Trigger(context.TODO(), &job)
+	c.Assert(err, check.FitsTypeOf, &quotaTypes.QuotaExceededError{})
+	c.Assert(provisiontest.ProvisionerInstance.JobRuns("mycron"), check.Equals, 0)
+}
+
+func (s *S) TestRunJobRetries(c *check.C) {
+	job := jobTypes.Job{Name: "myjob", TeamOwner: "myteam", Image: "img", Retry: jobTypes.RetryPolicy{Limit: 2}}
+	err := s.service.Create(context.TODO(), &job)
+	c.Assert(err, check.IsNil)
+	dbJob, err := s.service.Get(context.TODO(), "myjob")
+	c.Assert(err, check.IsNil)
+	c.Assert(dbJob.Retry, check.DeepEquals, jobTypes.RetryPolicy{Limit: 2})
+	provisiontest.ProvisionerInstance.PrepareFailure("RunJob", errors.New("exit status 1"))
+	provisiontest.ProvisionerInstance.PrepareFailure("RunJob", errors.New("exit status 2"))
+	var buf bytes.Buffer
+	err = s.service.Run(context.TODO(), dbJob, &buf)
+	c.Assert(err, check.IsNil)
+	c.Assert(provisiontest.ProvisionerInstance.JobRuns("myjob"), check.Equals, 1)
+	c.Assert(buf.String(), check.Equals, `---- Running job "myjob", attempt 1 of 3 ----
+---- Attempt 1 failed: exit status 1 ----
+---- Running job "myjob", attempt 2 of 3 ----
+---- Attempt 2 failed: exit status 2 ----
+---- Running job "myjob", attempt 3 of 3 ----
+---- Attempt 3 succeeded ----
+`)
+}
+
+func (s *S) TestRunJobRetriesExhausted(c *check.C) {
+	job := jobTypes.Job{Name: "myjob", TeamOwner: "myteam", Image: "img", Retry: jobTypes.RetryPolicy{Limit: 1, BackoffSeconds: 1}}
+	err := s.service.Create(context.TODO(), &job)
+	c.Assert(err, check.IsNil)
+	provisiontest.ProvisionerInstance.PrepareFailure("RunJob", errors.New("exit status 1"))
+	provisiontest.ProvisionerInstance.PrepareFailure("RunJob", errors.New("exit status 2"))
+	var buf bytes.Buffer
+	start := time.Now()
+	err = s.service.Run(context.TODO(), &job, &buf)
+	c.Assert(err, check.ErrorMatches, "exit status 2")
+	c.Assert(time.Since(start) >= time.Second, check.Equals, true)
+	c.Assert(provisiontest.ProvisionerInstance.JobRuns("myjob"), check.Equals, 0)
+	c.Assert(buf.String(), check.Equals, `---- Running job "myjob", attempt 1 of 2 ----
+---- Attempt 1 failed: exit status 1 ----
+---- Retrying in 1s ----
+---- Running job "myjob", attempt 2 of 2 ----
+---- Attempt 2 failed: exit status 2 ----
+`)
+}
+
+func (s *S) TestTriggerJob(c *check.C) {
+	job := jobTypes.Job{Name: "mycron", TeamOwner: "myteam", Image: "img", Schedule: "0 * * * *", ConcurrencyPolicy: jobTypes.ConcurrencyReplace}
+	err := s.service.Create(context.TODO(), &job)
+	c.Assert(err, check.IsNil)
+	dbJob, err := s.service.Get(context.TODO(), "mycron")
+	c.Assert(err, check.IsNil)
+	c.Assert(dbJob.ConcurrencyPolicy, check.Equals, jobTypes.ConcurrencyReplace)
+	err = s.service.Trigger(context.TODO(), dbJob)
+	c.Assert(err, check.IsNil)
+	c.Assert(provisiontest.ProvisionerInstance.JobRuns("mycron"), check.Equals, 1)
+}
+
+func (s *S) TestTriggerJobNotCron(c *check.C) {
+	job := jobTypes.Job{Name: "myjob", TeamOwner: "myteam", Image: "img"}
+	err := s.service.Create(context.TODO(), &job)
+	c.Assert(err, check.IsNil)
+	err = s.service.Trigger(context.TODO(), &job)
+	c.Assert(err, check.Equals, ErrJobNotCron)
+	c.Assert(provisiontest.ProvisionerInstance.JobRuns("myjob"), check.Equals, 0)
+}
+
+func (s *S) TestRemoveJob(c *check.C) {
+	job := jobTypes.Job{Name: "mycron", TeamOwner: "myteam", Image: "img", Schedule: "0 * * * *"}
+	err := s.service.Create(context.TODO(), &job)
+	c.Assert(err, check.IsNil)
+	err = s.service.Remove(context.TODO(), &job)
+	c.Assert(err, check.IsNil)
+	_, isCron := provisiontest.ProvisionerInstance.CronJob("mycron")
+	c.Assert(isCron, check.Equals, false)
+	_, err = s.service.Get(context.TODO(), "mycron")
+	c.Assert(err, check.Equals, jobTypes.ErrJobNotFound)
+}
@@ -0,0 +1,122 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package job
+
+import (
+	"context"
+
+	"github.com/tsuru/tsuru/quota"
+	"github.com/tsuru/tsuru/servicemanager"
+	"github.com/tsuru/tsuru/storage"
+	appTypes "github.com/tsuru/tsuru/types/app"
+	jobTypes "github.com/tsuru/tsuru/types/job"
+	quotaTypes "github.com/tsuru/tsuru/types/quota"
+)
+
+const (
+	quotaUnitUnits  = "units"
+	quotaUnitMemory = "memory"
+)
+
+// UnitsQuotaService returns the service handling the team quotas of job
+// units, each running job run consumes one unit.
+func UnitsQuotaService() (quotaTypes.QuotaService, error) {
+	dbDriver, err := storage.GetCurrentDbDriver()
+	if err != nil {
+		dbDriver, err = storage.GetDefaultDbDriver()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &quota.QuotaService{Storage: dbDriver.TeamJobUnitsQuotaStorage}, nil
+}
+
+// MemoryQuotaService returns the service handling the team quotas of job
+// memory, each running job run consumes the memory of its plan, in
+// megabytes.
+func MemoryQuotaService() (quotaTypes.QuotaService, error) {
+	dbDriver, err := storage.GetCurrentDbDriver()
+	if err != nil {
+		dbDriver, err = storage.GetDefaultDbDriver()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &quota.QuotaService{Storage: dbDriver.TeamJobMemoryQuotaStorage}, nil
+}
+
+// UnitsQuotaItem returns the quota item of the team job units, whose usage
+// is computed from the runs of the team jobs that haven't finished.
+func UnitsQuotaItem(teamName string) quotaTypes.QuotaItem {
+	return &teamJobQuotaItem{name: teamName, unit: quotaUnitUnits}
+}
+
+// MemoryQuotaItem returns the quota item of the team job memory, whose usage
+// is computed from the plans of the team jobs with runs that haven't
+// finished.
+func MemoryQuotaItem(teamName string) quotaTypes.QuotaItem {
+	return &teamJobQuotaItem{name: teamName, unit: quotaUnitMemory}
+}
+
+type teamJobQuotaItem struct {
+	name string
+	unit string
+}
+
+func (t *teamJobQuotaItem) GetName() string {
+	return t.name
+}
+
+func (t *teamJobQuotaItem) GetQuotaInUse() (int, error) {
+	dbDriver, err := storage.GetCurrentDbDriver()
+	if err != nil {
+		dbDriver, err = storage.GetDefaultDbDriver()
+		if err != nil {
+			return 0, err
+		}
+	}
+	jobs, err := dbDriver.JobStorage.List(context.TODO(), &jobTypes.Filter{Teams: []string{t.name}})
+	if err != nil {
+		return 0, err
+	}
+	inUse := 0
+	for i := range jobs {
+		prov, err := jobProvisioner(context.TODO(), jobs[i].Pool)
+		if err != nil {
+			return 0, err
+		}
+		active, err := prov.ActiveJobRuns(context.TODO(), &jobs[i])
+		if err != nil {
+			return 0, err
+		}
+		inUse += active * quotaWeight(t.unit, jobs[i].Plan)
+	}
+	return inUse, nil
+}
+
+// quotaWeight returns how much of the team job quota is consumed by a job
+// using the plan. Plans without memory limits consume a single megabyte.
+func quotaWeight(unit string, plan appTypes.Plan) int {
+	if unit == quotaUnitUnits {
+		return 1
+	}
+	weight := int(plan.Memory / (1024 * 1024))
+	if weight < 1 {
+		weight = 1
+	}
+	return weight
+}
+
+// checkTeamQuota checks whether the team job quotas allow a new run of the
+// job, along with the runs of the team jobs that haven't finished. The usage
+// of job quotas is computed from the running job runs, so there's nothing to
+// release when runs finish.
+func checkTeamQuota(ctx context.Context, job *jobTypes.Job) error {
+	err := servicemanager.JobUnitsQuota.Inc(ctx, UnitsQuotaItem(job.TeamOwner), quotaWeight(quotaUnitUnits, job.Plan))
+	if err != nil {
+		return err
+	}
+	return servicemanager.JobMemoryQuota.Inc(ctx, MemoryQuotaItem(job.TeamOwner), quotaWeight(quotaUnitMemory, job.Plan))
+}
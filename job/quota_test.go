@@ -0,0 +1,44 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package job
+
+import (
+	"context"
+
+	"github.com/tsuru/tsuru/provision/provisiontest"
+	appTypes "github.com/tsuru/tsuru/types/app"
+	jobTypes "github.com/tsuru/tsuru/types/job"
+	quotaTypes "github.com/tsuru/tsuru/types/quota"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestTeamJobQuotaInUse(c *check.C) {
+	jobs := []jobTypes.Job{
+		{Name: "job1", TeamOwner: "myteam", Plan: appTypes.Plan{Memory: 256 * 1024 * 1024}},
+		{Name: "job2", TeamOwner: "myteam", Plan: appTypes.Plan{Memory: 512 * 1024 * 1024}},
+		{Name: "job3", TeamOwner: "myteam"},
+		{Name: "job4", TeamOwner: "otherteam", Plan: appTypes.Plan{Memory: 1024 * 1024 * 1024}},
+	}
+	for _, j := range jobs {
+		j.Pool = "mypool"
+		err := s.service.storage.Insert(context.TODO(), j)
+		c.Assert(err, check.IsNil)
+	}
+	inUse, err := UnitsQuotaItem("myteam").(quotaTypes.QuotaItemInUse).GetQuotaInUse()
+	c.Assert(err, check.IsNil)
+	c.Assert(inUse, check.Equals, 0)
+	provisiontest.ProvisionerInstance.SetActiveJobRuns("job1", 2)
+	provisiontest.ProvisionerInstance.SetActiveJobRuns("job3", 1)
+	provisiontest.ProvisionerInstance.SetActiveJobRuns("job4", 1)
+	inUse, err = UnitsQuotaItem("myteam").(quotaTypes.QuotaItemInUse).GetQuotaInUse()
+	c.Assert(err, check.IsNil)
+	c.Assert(inUse, check.Equals, 3)
+	inUse, err = MemoryQuotaItem("myteam").(quotaTypes.QuotaItemInUse).GetQuotaInUse()
+	c.Assert(err, check.IsNil)
+	c.Assert(inUse, check.Equals, 513)
+	inUse, err = MemoryQuotaItem("otherteam").(quotaTypes.QuotaItemInUse).GetQuotaInUse()
+	c.Assert(err, check.IsNil)
+	c.Assert(inUse, check.Equals, 1024)
+}
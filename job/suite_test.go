@@ -0,0 +1,106 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package job
+
+import (
+	"context"
+	"testing"
+
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/db"
+	"github.com/tsuru/tsuru/db/dbtest"
+	"github.com/tsuru/tsuru/provision/pool"
+	"github.com/tsuru/tsuru/provision/provisiontest"
+	"github.com/tsuru/tsuru/servicemanager"
+	_ "github.com/tsuru/tsuru/storage/mongodb"
+	appTypes "github.com/tsuru/tsuru/types/app"
+	authTypes "github.com/tsuru/tsuru/types/auth"
+	quotaTypes "github.com/tsuru/tsuru/types/quota"
+	check "gopkg.in/check.v1"
+)
+
+type S struct {
+	service *jobService
+}
+
+var _ = check.Suite(&S{})
+
+func Test(t *testing.T) {
+	check.TestingT(t)
+}
+
+func (s *S) SetUpSuite(c *check.C) {
+	config.Set("database:url", "127.0.0.1:27017?maxPoolSize=100")
+	config.Set("database:name", "tsuru_job_test")
+}
+
+func (s *S) SetUpTest(c *check.C) {
+	conn, err := db.Conn()
+	c.Assert(err, check.IsNil)
+	defer conn.Close()
+	err = dbtest.ClearAllCollections(conn.Apps().Database)
+	c.Assert(err, check.IsNil)
+	provisiontest.ProvisionerInstance.Reset()
+	teams := []authTypes.Team{{Name: "myteam"}, {Name: "otherteam"}}
+	servicemanager.Team = &authTypes.MockTeamService{
+		OnList: func() ([]authTypes.Team, error) {
+			return teams, nil
+		},
+		OnFindByName: func(name string) (*authTypes.Team, error) {
+			for _, t := range teams {
+				if name == t.Name {
+					return &t, nil
+				}
+			}
+			return nil, authTypes.ErrTeamNotFound
+		},
+	}
+	plans := []appTypes.Plan{
+		{Name: "default", Memory: 512, Default: true},
+		{Name: "large", Memory: 4096},
+	}
+	servicemanager.Plan = &appTypes.MockPlanService{
+		OnList: func() ([]appTypes.Plan, error) {
+			return plans, nil
+		},
+		OnDefaultPlan: func() (*appTypes.Plan, error) {
+			return &plans[0], nil
+		},
+		OnFindByName: func(name string) (*appTypes.Plan, error) {
+			for _, p := range plans {
+				if name == p.Name {
+					return &p, nil
+				}
+			}
+			return nil, appTypes.ErrPlanNotFound
+		},
+	}
+	servicemanager.JobUnitsQuota = &quotaTypes.MockQuotaService{}
+	servicemanager.JobMemoryQuota = &quotaTypes.MockQuotaService{}
+	err = pool.AddPool(context.TODO(), pool.AddPoolOptions{
+		Name:        "mypool",
+		Provisioner: "fake",
+		Default:     true,
+	})
+	c.Assert(err, check.IsNil)
+	err = pool.AddPool(context.TODO(), pool.AddPoolOptions{
+		Name:        "privatepool",
+		Provisioner: "fake",
+	})
+	c.Assert(err, check.IsNil)
+	err = pool.AddTeamsToPool("privatepool", []string{"otherteam"})
+	c.Assert(err, check.IsNil)
+	svc, err := JobService()
+	c.Assert(err, check.IsNil)
+	s.service = svc.(*jobService)
+}
+
+func (s *S) TearDownSuite(c *check.C) {
+	conn, err := db.Conn()
+	c.Assert(err, check.IsNil)
+	defer conn.Close()
+	err = dbtest.ClearAllCollections(conn.DefaultDatabase())
+	c.Assert(err, check.IsNil)
+}
@@ -0,0 +1,25 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package permission
+
+import (
+	"github.com/globalsign/mgo/bson"
+)
+
+// MigrateAppReadPrivateEnv adds app.read.private-env to the roles allowed to
+// read app environment variables, which could read the values of private
+// ones before it existed.
+func MigrateAppReadPrivateEnv() error {
+	coll, err := rolesCollection()
+	if err != nil {
+		return err
+	}
+	defer coll.Close()
+	_, err = coll.UpdateAll(
+		bson.M{"schemenames": "app.read.env"},
+		bson.M{"$addToSet": bson.M{"schemenames": "app.read.private-env"}},
+	)
+	return err
+}
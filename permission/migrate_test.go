@@ -0,0 +1,28 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package permission
+
+import (
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestMigrateAppReadPrivateEnv(c *check.C) {
+	reader, err := NewRole("env-reader", "app", "")
+	c.Assert(err, check.IsNil)
+	err = reader.AddPermissions("app.read.env", "app.read.log")
+	c.Assert(err, check.IsNil)
+	deployer, err := NewRole("deployer", "app", "")
+	c.Assert(err, check.IsNil)
+	err = deployer.AddPermissions("app.deploy")
+	c.Assert(err, check.IsNil)
+	err = MigrateAppReadPrivateEnv()
+	c.Assert(err, check.IsNil)
+	reader, err = FindRole("env-reader")
+	c.Assert(err, check.IsNil)
+	c.Assert(reader.SchemeNames, check.DeepEquals, []string{"app.read.env", "app.read.log", "app.read.private-env"})
+	deployer, err = FindRole("deployer")
+	c.Assert(err, check.IsNil)
+	c.Assert(deployer.SchemeNames, check.DeepEquals, []string{"app.deploy"})
+}
@@ -18,7 +18,7 @@ import (
 	"golang.org/x/text/language"
 )
 
-var ErrUnauthorized = &tsuruErrors.HTTP{Code: http.StatusForbidden, Message: "You don't have permission to do this action"}
+var ErrUnauthorized = &tsuruErrors.HTTP{Code: http.StatusForbidden, Message: "You don't have permission to do this action", ErrorCode: "permission.denied"}
 var ErrTooManyTeams = &tsuruErrors.HTTP{Code: http.StatusBadRequest, Message: "You must provide a team to execute this action."}
 
 type PermissionScheme struct {
@@ -138,6 +138,40 @@ type Token interface {
 	Permissions() ([]Permission, error)
 }
 
+// ScopedToken is implemented by tokens that may be restricted to a subset of
+// the permissions granted by their roles. PermissionScopes returns nil for
+// tokens without restrictions, a non-nil empty list allows nothing.
+// Permissions must already be restricted by the scopes, see
+// ScopePermissions, while UnscopedPermissions returns the permissions granted
+// by the roles, used along with the scopes when checking a known list of
+// contexts.
+type ScopedToken interface {
+	Token
+	PermissionScopes() []Permission
+	UnscopedPermissions() ([]Permission, error)
+}
+
+func tokenScopes(token Token) ([]Permission, bool) {
+	scoped, ok := token.(ScopedToken)
+	if !ok {
+		return nil, false
+	}
+	scopes := scoped.PermissionScopes()
+	return scopes, scopes != nil
+}
+
+// checkPermissions returns the permissions used to check the token against a
+// list of contexts and its scopes, if any.
+func checkPermissions(token Token) ([]Permission, []Permission, bool, error) {
+	scopes, isScoped := tokenScopes(token)
+	if !isScoped {
+		perms, err := token.Permissions()
+		return perms, nil, false, err
+	}
+	perms, err := token.(ScopedToken).UnscopedPermissions()
+	return perms, scopes, true, err
+}
+
 func ListContextValues(t Token, scheme *PermissionScheme, failIfEmpty bool) ([]string, error) {
 	contexts := ContextsForPermission(t, scheme)
 	if len(contexts) == 0 && failIfEmpty {
@@ -173,19 +207,87 @@ func ContextsFromListForPermission(perms []Permission, scheme *PermissionScheme,
 }
 
 func ContextsForPermission(token Token, scheme *PermissionScheme, ctxTypes ...permTypes.ContextType) []permTypes.PermissionContext {
-	perms, err := token.Permissions()
+	perms, scopes, isScoped, err := checkPermissions(token)
 	if err != nil {
 		return []permTypes.PermissionContext{}
 	}
-	return ContextsFromListForPermission(perms, scheme, ctxTypes...)
+	contexts := ContextsFromListForPermission(perms, scheme, ctxTypes...)
+	if isScoped {
+		return intersectContexts(contexts, ContextsFromListForPermission(scopes, scheme, ctxTypes...))
+	}
+	return contexts
+}
+
+// intersectContexts returns the contexts in both lists, a global context in
+// one of them matches all contexts of the other.
+func intersectContexts(a, b []permTypes.PermissionContext) []permTypes.PermissionContext {
+	if hasGlobalContext(a) {
+		return b
+	}
+	if hasGlobalContext(b) {
+		return a
+	}
+	result := []permTypes.PermissionContext{}
+	for _, ctxA := range a {
+		for _, ctxB := range b {
+			if ctxA == ctxB {
+				result = append(result, ctxA)
+				break
+			}
+		}
+	}
+	return result
+}
+
+// ScopePermissions restricts perms to the scopes of the token, so callers
+// reading the token permissions directly can't get past its scopes. Tokens
+// without scopes have perms returned unchanged. Contexts of different types
+// are only kept when one of them is global, as telling whether an app
+// belongs to a team requires loading the app, Check handles these cases
+// using the contexts of the checked object.
+func ScopePermissions(token Token, perms []Permission) []Permission {
+	scopes, isScoped := tokenScopes(token)
+	if !isScoped {
+		return perms
+	}
+	result := []Permission{}
+	for _, perm := range perms {
+		for _, scope := range scopes {
+			var scheme *PermissionScheme
+			switch {
+			case perm.Scheme.IsParent(scope.Scheme):
+				scheme = scope.Scheme
+			case scope.Scheme.IsParent(perm.Scheme):
+				scheme = perm.Scheme
+			default:
+				continue
+			}
+			for _, ctx := range intersectContexts([]permTypes.PermissionContext{perm.Context}, []permTypes.PermissionContext{scope.Context}) {
+				result = append(result, Permission{Scheme: scheme, Context: ctx})
+			}
+		}
+	}
+	return result
+}
+
+func hasGlobalContext(contexts []permTypes.PermissionContext) bool {
+	for _, ctx := range contexts {
+		if ctx.CtxType == permTypes.CtxGlobal {
+			return true
+		}
+	}
+	return false
 }
 
 func Check(token Token, scheme *PermissionScheme, contexts ...permTypes.PermissionContext) bool {
-	perms, err := token.Permissions()
+	perms, scopes, isScoped, err := checkPermissions(token)
 	if err != nil {
 		log.Errorf("unable to read token permissions: %v", err)
 		return false
 	}
+	if isScoped && !CheckFromPermList(scopes, scheme, contexts...) {
+		return false
+	}
 	return CheckFromPermList(perms, scheme, contexts...)
 }
 
@@ -93,6 +93,105 @@ func (s *S) TestCheckSuperToken(c *check.C) {
 	c.Assert(Check(t, PermAppUpdateEnvUnset), check.Equals, true)
 }
 
+type scopedToken struct {
+	userToken
+	scopes []Permission
+}
+
+func (t *scopedToken) PermissionScopes() []Permission {
+	return t.scopes
+}
+
+func (t *scopedToken) Permissions() ([]Permission, error) {
+	return ScopePermissions(t, t.permissions), nil
+}
+
+func (t *scopedToken) UnscopedPermissions() ([]Permission, error) {
+	return t.permissions, nil
+}
+
+func (s *S) TestCheckScopedToken(c *check.C) {
+	t := &scopedToken{
+		userToken: userToken{permissions: []Permission{
+			{Scheme: PermApp, Context: permTypes.PermissionContext{CtxType: permTypes.CtxTeam, Value: "team1"}},
+		}},
+		scopes: []Permission{
+			{Scheme: PermAppDeploy, Context: permTypes.PermissionContext{CtxType: permTypes.CtxApp, Value: "myapp"}},
+			{Scheme: PermAppRead, Context: permTypes.PermissionContext{CtxType: permTypes.CtxGlobal}},
+		},
+	}
+	myapp := []permTypes.PermissionContext{
+		{CtxType: permTypes.CtxApp, Value: "myapp"},
+		{CtxType: permTypes.CtxTeam, Value: "team1"},
+	}
+	otherapp := []permTypes.PermissionContext{
+		{CtxType: permTypes.CtxApp, Value: "otherapp"},
+		{CtxType: permTypes.CtxTeam, Value: "team1"},
+	}
+	c.Assert(Check(t, PermAppDeploy, myapp...), check.Equals, true)
+	c.Assert(Check(t, PermAppDeployRollback, myapp...), check.Equals, true)
+	c.Assert(Check(t, PermAppDeploy, otherapp...), check.Equals, false)
+	c.Assert(Check(t, PermAppUpdateEnvSet, myapp...), check.Equals, false)
+	c.Assert(Check(t, PermAppRead, otherapp...), check.Equals, true)
+	c.Assert(Check(t, PermAppRead, permTypes.PermissionContext{CtxType: permTypes.CtxTeam, Value: "team2"}), check.Equals, false)
+	c.Assert(Check(t, PermAppDeploy, permTypes.PermissionContext{CtxType: permTypes.CtxApp, Value: "myapp"}), check.Equals, false)
+	t.scopes = []Permission{}
+	c.Assert(Check(t, PermAppRead, myapp...), check.Equals, false)
+	t.scopes = nil
+	c.Assert(Check(t, PermAppUpdateEnvSet, otherapp...), check.Equals, true)
+}
+
+func (s *S) TestContextsForPermissionScopedToken(c *check.C) {
+	t := &scopedToken{
+		userToken: userToken{permissions: []Permission{
+			{Scheme: PermApp, Context: permTypes.PermissionContext{CtxType: permTypes.CtxTeam, Value: "team1"}},
+			{Scheme: PermApp, Context: permTypes.PermissionContext{CtxType: permTypes.CtxTeam, Value: "team2"}},
+		}},
+		scopes: []Permission{
+			{Scheme: PermAppRead, Context: permTypes.PermissionContext{CtxType: permTypes.CtxTeam, Value: "team2"}},
+			{Scheme: PermAppDeploy, Context: permTypes.PermissionContext{CtxType: permTypes.CtxApp, Value: "myapp"}},
+			{Scheme: PermTeamRead, Context: permTypes.PermissionContext{CtxType: permTypes.CtxGlobal}},
+		},
+	}
+	c.Assert(ContextsForPermission(t, PermAppRead), check.DeepEquals, []permTypes.PermissionContext{
+		{CtxType: permTypes.CtxTeam, Value: "team2"},
+	})
+	c.Assert(ContextsForPermission(t, PermAppDeploy), check.DeepEquals, []permTypes.PermissionContext{})
+	c.Assert(ContextsForPermission(t, PermAppUpdate), check.DeepEquals, []permTypes.PermissionContext{})
+	t.permissions = []Permission{
+		{Scheme: PermAll, Context: permTypes.PermissionContext{CtxType: permTypes.CtxGlobal}},
+	}
+	c.Assert(ContextsForPermission(t, PermAppDeploy), check.DeepEquals, []permTypes.PermissionContext{
+		{CtxType: permTypes.CtxApp, Value: "myapp"},
+	})
+	c.Assert(ContextsForPermission(t, PermTeamRead), check.DeepEquals, []permTypes.PermissionContext{
+		{CtxType: permTypes.CtxGlobal},
+	})
+}
+
+func (s *S) TestScopePermissions(c *check.C) {
+	t := &scopedToken{
+		userToken: userToken{permissions: []Permission{
+			{Scheme: PermApp, Context: permTypes.PermissionContext{CtxType: permTypes.CtxTeam, Value: "team1"}},
+			{Scheme: PermTeam, Context: permTypes.PermissionContext{CtxType: permTypes.CtxGlobal}},
+			{Scheme: PermRoleUpdateAssign, Context: permTypes.PermissionContext{CtxType: permTypes.CtxGlobal}},
+		}},
+		scopes: []Permission{
+			{Scheme: PermAppDeploy, Context: permTypes.PermissionContext{CtxType: permTypes.CtxApp, Value: "myapp"}},
+			{Scheme: PermAppRead, Context: permTypes.PermissionContext{CtxType: permTypes.CtxGlobal}},
+			{Scheme: PermTeamRead, Context: permTypes.PermissionContext{CtxType: permTypes.CtxTeam, Value: "team2"}},
+		},
+	}
+	c.Assert(ScopePermissions(t, t.permissions), check.DeepEquals, []Permission{
+		{Scheme: PermAppRead, Context: permTypes.PermissionContext{CtxType: permTypes.CtxTeam, Value: "team1"}},
+		{Scheme: PermTeamRead, Context: permTypes.PermissionContext{CtxType: permTypes.CtxTeam, Value: "team2"}},
+	})
+	t.scopes = []Permission{}
+	c.Assert(ScopePermissions(t, t.permissions), check.DeepEquals, []Permission{})
+	t.scopes = nil
+	c.Assert(ScopePermissions(t, t.permissions), check.DeepEquals, t.permissions)
+}
+
 func (s *S) TestGetTeamForPermission(c *check.C) {
 	t := &userToken{
 		permissions: []Permission{
@@ -1,7 +1,7 @@
 // AUTOMATICALLY GENERATED FILE - DO NOT EDIT!
 // Please run 'go generate' to update this file.
 //
-// Copyright 2022 tsuru authors. All rights reserved.
+// Copyright 2026 tsuru authors. All rights reserved.
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
@@ -14,7 +14,7 @@ var (
 	PermAppAdminQuota                    = PermissionRegistry.get("app.admin.quota")                     // [global app team pool]
 	PermAppAdminRoutes                   = PermissionRegistry.get("app.admin.routes")                    // [global app team pool]
 	PermAppBuild                         = PermissionRegistry.get("app.build")                           // [global app team pool]
-	PermAppCreate                        = PermissionRegistry.get("app.create")                          // [global team]
+	PermAppCreate                        = PermissionRegistry.get("app.create")                          // [global team pool]
 	PermAppDelete                        = PermissionRegistry.get("app.delete")                          // [global app team pool]
 	PermAppDeploy                        = PermissionRegistry.get("app.deploy")                          // [global app team pool]
 	PermAppDeployArchiveUrl              = PermissionRegistry.get("app.deploy.archive-url")              // [global app team pool]
@@ -31,6 +31,7 @@ var (
 	PermAppReadInfo                      = PermissionRegistry.get("app.read.info")                       // [global app team pool]
 	PermAppReadLog                       = PermissionRegistry.get("app.read.log")                        // [global app team pool]
 	PermAppReadMetric                    = PermissionRegistry.get("app.read.metric")                     // [global app team pool]
+	PermAppReadPrivateEnv                = PermissionRegistry.get("app.read.private-env")                // [global app team pool]
 	PermAppReadRouter                    = PermissionRegistry.get("app.read.router")                     // [global app team pool]
 	PermAppRun                           = PermissionRegistry.get("app.run")                             // [global app team pool]
 	PermAppRunShell                      = PermissionRegistry.get("app.run.shell")                       // [global app team pool]
@@ -44,20 +45,25 @@ var (
 	PermAppUpdateCnameAdd                = PermissionRegistry.get("app.update.cname.add")                // [global app team pool]
 	PermAppUpdateCnameRemove             = PermissionRegistry.get("app.update.cname.remove")             // [global app team pool]
 	PermAppUpdateDeploy                  = PermissionRegistry.get("app.update.deploy")                   // [global app team pool]
+	PermAppUpdateDeployCanary            = PermissionRegistry.get("app.update.deploy.canary")            // [global app team pool]
 	PermAppUpdateDeployRollback          = PermissionRegistry.get("app.update.deploy.rollback")          // [global app team pool]
 	PermAppUpdateDescription             = PermissionRegistry.get("app.update.description")              // [global app team pool]
 	PermAppUpdateEnv                     = PermissionRegistry.get("app.update.env")                      // [global app team pool]
 	PermAppUpdateEnvSet                  = PermissionRegistry.get("app.update.env.set")                  // [global app team pool]
 	PermAppUpdateEnvUnset                = PermissionRegistry.get("app.update.env.unset")                // [global app team pool]
 	PermAppUpdateEvents                  = PermissionRegistry.get("app.update.events")                   // [global app team pool]
+	PermAppUpdateFreeze                  = PermissionRegistry.get("app.update.freeze")                   // [global app team pool]
 	PermAppUpdateGrant                   = PermissionRegistry.get("app.update.grant")                    // [global app team pool]
 	PermAppUpdateImageReset              = PermissionRegistry.get("app.update.image-reset")              // [global app team pool]
+	PermAppUpdateInternal                = PermissionRegistry.get("app.update.internal")                 // [global app team pool]
 	PermAppUpdateLog                     = PermissionRegistry.get("app.update.log")                      // [global app team pool]
+	PermAppUpdateMaintenance             = PermissionRegistry.get("app.update.maintenance")              // [global app team pool]
 	PermAppUpdateMetadata                = PermissionRegistry.get("app.update.metadata")                 // [global app team pool]
 	PermAppUpdatePlan                    = PermissionRegistry.get("app.update.plan")                     // [global app team pool]
 	PermAppUpdatePlanoverride            = PermissionRegistry.get("app.update.planoverride")             // [global app team pool]
 	PermAppUpdatePlatform                = PermissionRegistry.get("app.update.platform")                 // [global app team pool]
 	PermAppUpdatePool                    = PermissionRegistry.get("app.update.pool")                     // [global app team pool]
+	PermAppUpdateRename                  = PermissionRegistry.get("app.update.rename")                   // [global app team pool]
 	PermAppUpdateRestart                 = PermissionRegistry.get("app.update.restart")                  // [global app team pool]
 	PermAppUpdateRevoke                  = PermissionRegistry.get("app.update.revoke")                   // [global app team pool]
 	PermAppUpdateRoutable                = PermissionRegistry.get("app.update.routable")                 // [global app team pool]
@@ -65,12 +71,14 @@ var (
 	PermAppUpdateRouterAdd               = PermissionRegistry.get("app.update.router.add")               // [global app team pool]
 	PermAppUpdateRouterRemove            = PermissionRegistry.get("app.update.router.remove")            // [global app team pool]
 	PermAppUpdateRouterUpdate            = PermissionRegistry.get("app.update.router.update")            // [global app team pool]
+	PermAppUpdateScheduling              = PermissionRegistry.get("app.update.scheduling")               // [global app team pool]
 	PermAppUpdateSleep                   = PermissionRegistry.get("app.update.sleep")                    // [global app team pool]
 	PermAppUpdateStart                   = PermissionRegistry.get("app.update.start")                    // [global app team pool]
 	PermAppUpdateStop                    = PermissionRegistry.get("app.update.stop")                     // [global app team pool]
 	PermAppUpdateSwap                    = PermissionRegistry.get("app.update.swap")                     // [global app team pool]
 	PermAppUpdateTags                    = PermissionRegistry.get("app.update.tags")                     // [global app team pool]
 	PermAppUpdateTeamowner               = PermissionRegistry.get("app.update.teamowner")                // [global app team pool]
+	PermAppUpdateTerminationGracePeriod  = PermissionRegistry.get("app.update.termination-grace-period") // [global app team pool]
 	PermAppUpdateUnbind                  = PermissionRegistry.get("app.update.unbind")                   // [global app team pool]
 	PermAppUpdateUnbindVolume            = PermissionRegistry.get("app.update.unbind-volume")            // [global app team pool]
 	PermAppUpdateUnit                    = PermissionRegistry.get("app.update.unit")                     // [global app team pool]
@@ -78,10 +86,12 @@ var (
 	PermAppUpdateUnitAutoscale           = PermissionRegistry.get("app.update.unit.autoscale")           // [global app team pool]
 	PermAppUpdateUnitAutoscaleAdd        = PermissionRegistry.get("app.update.unit.autoscale.add")       // [global app team pool]
 	PermAppUpdateUnitAutoscaleRemove     = PermissionRegistry.get("app.update.unit.autoscale.remove")    // [global app team pool]
+	PermAppUpdateUnitKill                = PermissionRegistry.get("app.update.unit.kill")                // [global app team pool]
 	PermAppUpdateUnitRegister            = PermissionRegistry.get("app.update.unit.register")            // [global app team pool]
 	PermAppUpdateUnitRemove              = PermissionRegistry.get("app.update.unit.remove")              // [global app team pool]
-	PermAppUpdateUnitKill                = PermissionRegistry.get("app.update.unit.kill")                // [global app team pool]
 	PermAppUpdateUnitStatus              = PermissionRegistry.get("app.update.unit.status")              // [global app team pool]
+	PermAudit                            = PermissionRegistry.get("audit")                               // [global]
+	PermAuditRead                        = PermissionRegistry.get("audit.read")                          // [global]
 	PermCluster                          = PermissionRegistry.get("cluster")                             // [global]
 	PermClusterAdmin                     = PermissionRegistry.get("cluster.admin")                       // [global]
 	PermClusterCreate                    = PermissionRegistry.get("cluster.create")                      // [global]
@@ -101,6 +111,13 @@ var (
 	PermHealingUpdate                    = PermissionRegistry.get("healing.update")                      // [global pool]
 	PermInstall                          = PermissionRegistry.get("install")                             // [global]
 	PermInstallManage                    = PermissionRegistry.get("install.manage")                      // [global]
+	PermJob                              = PermissionRegistry.get("job")                                 // [global team pool]
+	PermJobCreate                        = PermissionRegistry.get("job.create")                          // [global team pool]
+	PermJobDelete                        = PermissionRegistry.get("job.delete")                          // [global team pool]
+	PermJobRead                          = PermissionRegistry.get("job.read")                            // [global team pool]
+	PermJobReadEvents                    = PermissionRegistry.get("job.read.events")                     // [global team pool]
+	PermJobRun                           = PermissionRegistry.get("job.run")                             // [global team pool]
+	PermJobTrigger                       = PermissionRegistry.get("job.trigger")                         // [global team pool]
 	PermMachine                          = PermissionRegistry.get("machine")                             // [global iaas]
 	PermMachineDelete                    = PermissionRegistry.get("machine.delete")                      // [global iaas]
 	PermMachineRead                      = PermissionRegistry.get("machine.read")                        // [global iaas]
@@ -163,6 +180,10 @@ var (
 	PermRoleDelete                       = PermissionRegistry.get("role.delete")                         // [global]
 	PermRoleRead                         = PermissionRegistry.get("role.read")                           // [global]
 	PermRoleReadEvents                   = PermissionRegistry.get("role.read.events")                    // [global]
+	PermRoleReadLdapSync                 = PermissionRegistry.get("role.read.ldap-sync")                 // [global]
+	PermRoleTemplate                     = PermissionRegistry.get("role.template")                       // [global]
+	PermRoleTemplateCreate               = PermissionRegistry.get("role.template.create")                // [global]
+	PermRoleTemplateDelete               = PermissionRegistry.get("role.template.delete")                // [global]
 	PermRoleUpdate                       = PermissionRegistry.get("role.update")                         // [global]
 	PermRoleUpdateAssign                 = PermissionRegistry.get("role.update.assign")                  // [global]
 	PermRoleUpdateContext                = PermissionRegistry.get("role.update.context")                 // [global]
@@ -220,24 +241,39 @@ var (
 	PermTeamRead                         = PermissionRegistry.get("team.read")                           // [global team]
 	PermTeamReadEvents                   = PermissionRegistry.get("team.read.events")                    // [global team]
 	PermTeamReadQuota                    = PermissionRegistry.get("team.read.quota")                     // [global team]
+	PermTeamServiceAccount               = PermissionRegistry.get("team.service-account")                // [global team]
+	PermTeamServiceAccountCreate         = PermissionRegistry.get("team.service-account.create")         // [global team]
+	PermTeamServiceAccountDelete         = PermissionRegistry.get("team.service-account.delete")         // [global team]
+	PermTeamServiceAccountRead           = PermissionRegistry.get("team.service-account.read")           // [global team]
 	PermTeamToken                        = PermissionRegistry.get("team.token")                          // [global team]
 	PermTeamTokenCreate                  = PermissionRegistry.get("team.token.create")                   // [global team]
 	PermTeamTokenDelete                  = PermissionRegistry.get("team.token.delete")                   // [global team]
 	PermTeamTokenRead                    = PermissionRegistry.get("team.token.read")                     // [global team]
 	PermTeamTokenUpdate                  = PermissionRegistry.get("team.token.update")                   // [global team]
+	PermTeamTokenUpdateAllowlist         = PermissionRegistry.get("team.token.update.allowlist")         // [global]
+	PermTeamTokenUpdateRotate            = PermissionRegistry.get("team.token.update.rotate")            // [global team]
 	PermTeamUpdate                       = PermissionRegistry.get("team.update")                         // [global team]
+	PermTeamUpdateAllowlist              = PermissionRegistry.get("team.update.allowlist")               // [global]
+	PermTeamUpdateParent                 = PermissionRegistry.get("team.update.parent")                  // [global team]
 	PermTeamUpdateQuota                  = PermissionRegistry.get("team.update.quota")                   // [global team]
 	PermUser                             = PermissionRegistry.get("user")                                // [global user]
 	PermUserCreate                       = PermissionRegistry.get("user.create")                         // [global]
 	PermUserDelete                       = PermissionRegistry.get("user.delete")                         // [global user]
+	PermUserImpersonate                  = PermissionRegistry.get("user.impersonate")                    // [global]
 	PermUserRead                         = PermissionRegistry.get("user.read")                           // [global user]
 	PermUserReadEvents                   = PermissionRegistry.get("user.read.events")                    // [global user]
 	PermUserReadQuota                    = PermissionRegistry.get("user.read.quota")                     // [global user]
+	PermUserReadSessions                 = PermissionRegistry.get("user.read.sessions")                  // [global user]
 	PermUserUpdate                       = PermissionRegistry.get("user.update")                         // [global user]
+	PermUserUpdateEvents                 = PermissionRegistry.get("user.update.events")                  // [global user]
 	PermUserUpdatePassword               = PermissionRegistry.get("user.update.password")                // [global user]
 	PermUserUpdateQuota                  = PermissionRegistry.get("user.update.quota")                   // [global user]
 	PermUserUpdateReset                  = PermissionRegistry.get("user.update.reset")                   // [global user]
+	PermUserUpdateSessions               = PermissionRegistry.get("user.update.sessions")                // [global user]
 	PermUserUpdateToken                  = PermissionRegistry.get("user.update.token")                   // [global user]
+	PermUserUpdateTwofactor              = PermissionRegistry.get("user.update.twofactor")               // [global user]
+	PermUserUpdateTwofactorReset         = PermissionRegistry.get("user.update.twofactor.reset")         // [global]
+	PermUserUpdateUnlock                 = PermissionRegistry.get("user.update.unlock")                  // [global]
 	PermVolume                           = PermissionRegistry.get("volume")                              // [global volume team pool]
 	PermVolumeCreate                     = PermissionRegistry.get("volume.create")                       // [global team pool]
 	PermVolumeDelete                     = PermissionRegistry.get("volume.delete")                       // [global volume team pool]
@@ -13,7 +13,7 @@ import (
 var PermissionRegistry = (&registry{}).addWithCtx(
 	"app", []permTypes.ContextType{permTypes.CtxApp, permTypes.CtxTeam, permTypes.CtxPool},
 ).addWithCtx(
-	"app.create", []permTypes.ContextType{permTypes.CtxTeam},
+	"app.create", []permTypes.ContextType{permTypes.CtxTeam, permTypes.CtxPool},
 ).add(
 	"app.update.description",
 	"app.update.tags",
@@ -36,6 +36,7 @@ var PermissionRegistry = (&registry{}).addWithCtx(
 	"app.update.grant",
 	"app.update.revoke",
 	"app.update.teamowner",
+	"app.update.rename",
 	"app.update.cname.add",
 	"app.update.cname.remove",
 	"app.update.plan",
@@ -50,11 +51,17 @@ var PermissionRegistry = (&registry{}).addWithCtx(
 	"app.update.certificate.set",
 	"app.update.certificate.unset",
 	"app.update.deploy.rollback",
+	"app.update.deploy.canary",
 	"app.update.router.add",
 	"app.update.router.update",
 	"app.update.router.remove",
 	"app.update.routable",
 	"app.update.metadata",
+	"app.update.maintenance",
+	"app.update.freeze",
+	"app.update.internal",
+	"app.update.termination-grace-period",
+	"app.update.scheduling",
 	"app.deploy",
 	"app.deploy.archive-url",
 	"app.deploy.build",
@@ -66,6 +73,7 @@ var PermissionRegistry = (&registry{}).addWithCtx(
 	"app.read.deploy",
 	"app.read.router",
 	"app.read.env",
+	"app.read.private-env",
 	"app.read.events",
 	"app.read.metric",
 	"app.read.log",
@@ -115,8 +123,17 @@ var PermissionRegistry = (&registry{}).addWithCtx(
 	"team.token.create",
 	"team.token.delete",
 	"team.token.update",
+	"team.token.update.rotate",
+	"team.service-account.read",
+	"team.service-account.create",
+	"team.service-account.delete",
 	"team.read.quota",
 	"team.update.quota",
+	"team.update.parent",
+).addWithCtx(
+	"team.update.allowlist", []permTypes.ContextType{},
+).addWithCtx(
+	"team.token.update.allowlist", []permTypes.ContextType{},
 ).addWithCtx(
 	"user", []permTypes.ContextType{permTypes.CtxUser},
 ).addWithCtx(
@@ -125,10 +142,20 @@ var PermissionRegistry = (&registry{}).addWithCtx(
 	"user.delete",
 	"user.read.events",
 	"user.read.quota",
+	"user.read.sessions",
 	"user.update.token",
 	"user.update.quota",
 	"user.update.password",
 	"user.update.reset",
+	"user.update.twofactor",
+	"user.update.sessions",
+	"user.update.events",
+).addWithCtx(
+	"user.update.twofactor.reset", []permTypes.ContextType{},
+).addWithCtx(
+	"user.update.unlock", []permTypes.ContextType{},
+).addWithCtx(
+	"user.impersonate", []permTypes.ContextType{},
 ).addWithCtx(
 	"service", []permTypes.ContextType{permTypes.CtxService, permTypes.CtxTeam},
 ).addWithCtx(
@@ -169,6 +196,7 @@ var PermissionRegistry = (&registry{}).addWithCtx(
 	"role.create",
 	"role.delete",
 	"role.read.events",
+	"role.read.ldap-sync",
 	"role.update.name",
 	"role.update.assign",
 	"role.update.dissociate",
@@ -178,6 +206,8 @@ var PermissionRegistry = (&registry{}).addWithCtx(
 	"role.update.permission.remove",
 	"role.default.create",
 	"role.default.delete",
+	"role.template.create",
+	"role.template.delete",
 ).add(
 	"platform.create",
 	"platform.delete",
@@ -219,6 +249,8 @@ var PermissionRegistry = (&registry{}).addWithCtx(
 	"nodecontainer.delete",
 ).add(
 	"install.manage",
+).add(
+	"audit.read",
 ).add(
 	"event-block.read",
 	"event-block.read.events",
@@ -239,6 +271,15 @@ var PermissionRegistry = (&registry{}).addWithCtx(
 	"volume.update.bind",
 	"volume.update.unbind",
 	"volume.delete",
+).addWithCtx(
+	"job", []permTypes.ContextType{permTypes.CtxTeam, permTypes.CtxPool},
+).add(
+	"job.create",
+	"job.read",
+	"job.read.events",
+	"job.run",
+	"job.trigger",
+	"job.delete",
 ).addWithCtx(
 	"webhook", []permTypes.ContextType{permTypes.CtxTeam},
 ).add(
@@ -22,6 +22,8 @@ type Role struct {
 	Description string
 	SchemeNames []string `json:"scheme_names,omitempty"`
 	Events      []string `json:"events,omitempty"`
+	// Template is the name of the role template the role was created from.
+	Template string `json:"template,omitempty"`
 }
 
 func NewRole(name string, ctx string, description string) (Role, error) {
@@ -131,7 +133,9 @@ func DestroyRole(name string) error {
 	return err
 }
 
-func (r *Role) AddPermissions(permNames ...string) error {
+// checkPermissionsAllowed returns an error if any of the permissions doesn't
+// exist or isn't allowed in roles of the context type.
+func checkPermissionsAllowed(ctxType permTypes.ContextType, permNames []string) error {
 	for _, permName := range permNames {
 		if permName == "" {
 			return permTypes.ErrInvalidPermissionName
@@ -144,8 +148,8 @@ func (r *Role) AddPermissions(permNames ...string) error {
 			return &permTypes.ErrPermissionNotFound{Permission: permName}
 		}
 		var found bool
-		for _, ctxType := range reg.AllowedContexts() {
-			if ctxType == r.ContextType {
+		for _, allowed := range reg.AllowedContexts() {
+			if allowed == ctxType {
 				found = true
 				break
 			}
@@ -153,10 +157,18 @@ func (r *Role) AddPermissions(permNames ...string) error {
 		if !found {
 			return &permTypes.ErrPermissionNotAllowed{
 				Permission:  permName,
-				ContextType: r.ContextType,
+				ContextType: ctxType,
 			}
 		}
 	}
+	return nil
+}
+
+func (r *Role) AddPermissions(permNames ...string) error {
+	err := checkPermissionsAllowed(r.ContextType, permNames)
+	if err != nil {
+		return err
+	}
 	coll, err := rolesCollection()
 	if err != nil {
 		return err
@@ -298,7 +310,7 @@ func (r *Role) Add() error {
 		return err
 	}
 	defer coll.Close()
-	insertRole := Role{Name: name, ContextType: r.ContextType, Description: r.Description, SchemeNames: r.SchemeNames, Events: r.Events}
+	insertRole := Role{Name: name, ContextType: r.ContextType, Description: r.Description, SchemeNames: r.SchemeNames, Events: r.Events, Template: r.Template}
 	err = coll.Insert(insertRole)
 	if mgo.IsDup(err) {
 		return permTypes.ErrRoleAlreadyExists
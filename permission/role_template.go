@@ -0,0 +1,201 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package permission
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tsuru/tsuru/db"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+)
+
+// RoleTemplate bundles a set of permissions in the context of a team, to be
+// instantiated as roles for each team instead of adding permissions to roles
+// one by one.
+type RoleTemplate struct {
+	Name        string   `bson:"_id" json:"name"`
+	Description string   `json:"description"`
+	SchemeNames []string `json:"scheme_names"`
+	Builtin     bool     `bson:"-" json:"builtin"`
+}
+
+var builtinRoleTemplates = []RoleTemplate{
+	{
+		Name:        "developer",
+		Description: "Manages and deploys the apps, jobs and service instances of the team.",
+		SchemeNames: []string{
+			"app",
+			"job",
+			"service-instance",
+			"team.read.events",
+			"team.read.quota",
+		},
+	},
+	{
+		Name:        "operator",
+		Description: "Operates the running apps and jobs of the team, without deploying or changing them.",
+		SchemeNames: []string{
+			"app.read",
+			"app.run",
+			"app.update.restart",
+			"app.update.start",
+			"app.update.stop",
+			"app.update.sleep",
+			"app.update.unit",
+			"app.update.log",
+			"app.deploy.rollback",
+			"job.read",
+			"job.run",
+			"service-instance.read",
+			"team.read.events",
+		},
+	},
+	{
+		Name:        "auditor",
+		Description: "Reads the apps, jobs, service instances and events of the team.",
+		SchemeNames: []string{
+			"app.read",
+			"job.read",
+			"service-instance.read",
+			"team.read.events",
+			"team.read.quota",
+		},
+	},
+}
+
+func findBuiltinRoleTemplate(name string) (RoleTemplate, bool) {
+	for _, t := range builtinRoleTemplates {
+		if t.Name == name {
+			t.Builtin = true
+			t.SchemeNames = append([]string(nil), t.SchemeNames...)
+			return t, true
+		}
+	}
+	return RoleTemplate{}, false
+}
+
+// ListRoleTemplates returns the built-in role templates followed by the
+// user-defined ones, sorted by name.
+func ListRoleTemplates() ([]RoleTemplate, error) {
+	templates := make([]RoleTemplate, 0, len(builtinRoleTemplates))
+	for _, t := range builtinRoleTemplates {
+		builtin, _ := findBuiltinRoleTemplate(t.Name)
+		templates = append(templates, builtin)
+	}
+	conn, err := db.Conn()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	var custom []RoleTemplate
+	err = conn.RoleTemplates().Find(nil).Sort("_id").All(&custom)
+	if err != nil {
+		return nil, err
+	}
+	return append(templates, custom...), nil
+}
+
+func FindRoleTemplate(name string) (RoleTemplate, error) {
+	if t, ok := findBuiltinRoleTemplate(name); ok {
+		return t, nil
+	}
+	conn, err := db.Conn()
+	if err != nil {
+		return RoleTemplate{}, err
+	}
+	defer conn.Close()
+	var t RoleTemplate
+	err = conn.RoleTemplates().FindId(name).One(&t)
+	if err == mgo.ErrNotFound {
+		return RoleTemplate{}, permTypes.ErrRoleTemplateNotFound
+	}
+	return t, err
+}
+
+// AddRoleTemplate stores a user-defined role template, its permissions must
+// be allowed in the context of teams.
+func AddRoleTemplate(t RoleTemplate) error {
+	t.Name = strings.TrimSpace(t.Name)
+	if t.Name == "" {
+		return permTypes.ErrInvalidRoleTemplateName
+	}
+	if _, ok := findBuiltinRoleTemplate(t.Name); ok {
+		return permTypes.ErrRoleTemplateAlreadyExists
+	}
+	if len(t.SchemeNames) == 0 {
+		return permTypes.ErrInvalidPermissionName
+	}
+	err := checkPermissionsAllowed(permTypes.CtxTeam, t.SchemeNames)
+	if err != nil {
+		return err
+	}
+	sort.Strings(t.SchemeNames)
+	t.Builtin = false
+	conn, err := db.Conn()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	err = conn.RoleTemplates().Insert(t)
+	if mgo.IsDup(err) {
+		return permTypes.ErrRoleTemplateAlreadyExists
+	}
+	return err
+}
+
+// DestroyRoleTemplate removes a user-defined role template, roles already
+// created from it are kept.
+func DestroyRoleTemplate(name string) error {
+	if _, ok := findBuiltinRoleTemplate(name); ok {
+		return permTypes.ErrRemoveBuiltinRoleTemplate
+	}
+	conn, err := db.Conn()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	err = conn.RoleTemplates().RemoveId(name)
+	if err == mgo.ErrNotFound {
+		return permTypes.ErrRoleTemplateNotFound
+	}
+	return err
+}
+
+// RoleName returns the default name of the role created from the template
+// for the team.
+func (t *RoleTemplate) RoleName(team string) string {
+	return team + "-" + t.Name
+}
+
+// Instantiate creates a role in the context of teams with the permissions of
+// the template.
+func (t *RoleTemplate) Instantiate(roleName string) (Role, error) {
+	err := checkPermissionsAllowed(permTypes.CtxTeam, t.SchemeNames)
+	if err != nil {
+		return Role{}, err
+	}
+	role, err := NewRole(roleName, string(permTypes.CtxTeam), t.Description)
+	if err != nil {
+		return Role{}, err
+	}
+	coll, err := rolesCollection()
+	if err != nil {
+		return Role{}, err
+	}
+	defer coll.Close()
+	err = coll.UpdateId(role.Name, bson.M{"$set": bson.M{"template": t.Name}})
+	if err != nil {
+		return Role{}, err
+	}
+	role.Template = t.Name
+	err = role.AddPermissions(t.SchemeNames...)
+	if err != nil {
+		return Role{}, err
+	}
+	return role, nil
+}
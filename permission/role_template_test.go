@@ -0,0 +1,96 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package permission
+
+import (
+	permTypes "github.com/tsuru/tsuru/types/permission"
+	check "gopkg.in/check.v1"
+)
+
+func (s *S) TestBuiltinRoleTemplatesAreValid(c *check.C) {
+	for _, t := range builtinRoleTemplates {
+		err := checkPermissionsAllowed(permTypes.CtxTeam, t.SchemeNames)
+		c.Check(err, check.IsNil, check.Commentf("template %q", t.Name))
+	}
+}
+
+func (s *S) TestListRoleTemplates(c *check.C) {
+	err := AddRoleTemplate(RoleTemplate{Name: "deployer", SchemeNames: []string{"app.read", "app.deploy"}})
+	c.Assert(err, check.IsNil)
+	templates, err := ListRoleTemplates()
+	c.Assert(err, check.IsNil)
+	var names []string
+	for _, t := range templates {
+		names = append(names, t.Name)
+	}
+	c.Assert(names, check.DeepEquals, []string{"developer", "operator", "auditor", "deployer"})
+	c.Assert(templates[0].Builtin, check.Equals, true)
+	c.Assert(templates[3].Builtin, check.Equals, false)
+	c.Assert(templates[3].SchemeNames, check.DeepEquals, []string{"app.deploy", "app.read"})
+}
+
+func (s *S) TestAddRoleTemplateInvalid(c *check.C) {
+	err := AddRoleTemplate(RoleTemplate{Name: " ", SchemeNames: []string{"app.read"}})
+	c.Assert(err, check.Equals, permTypes.ErrInvalidRoleTemplateName)
+	err = AddRoleTemplate(RoleTemplate{Name: "developer", SchemeNames: []string{"app.read"}})
+	c.Assert(err, check.Equals, permTypes.ErrRoleTemplateAlreadyExists)
+	err = AddRoleTemplate(RoleTemplate{Name: "empty"})
+	c.Assert(err, check.Equals, permTypes.ErrInvalidPermissionName)
+	err = AddRoleTemplate(RoleTemplate{Name: "unknown", SchemeNames: []string{"app.unknown"}})
+	c.Assert(err, check.FitsTypeOf, &permTypes.ErrPermissionNotFound{})
+	err = AddRoleTemplate(RoleTemplate{Name: "global", SchemeNames: []string{"team.create"}})
+	c.Assert(err, check.FitsTypeOf, &permTypes.ErrPermissionNotAllowed{})
+}
+
+func (s *S) TestAddRoleTemplateAlreadyExists(c *check.C) {
+	err := AddRoleTemplate(RoleTemplate{Name: "deployer", SchemeNames: []string{"app.deploy"}})
+	c.Assert(err, check.IsNil)
+	err = AddRoleTemplate(RoleTemplate{Name: "deployer", SchemeNames: []string{"app.read"}})
+	c.Assert(err, check.Equals, permTypes.ErrRoleTemplateAlreadyExists)
+}
+
+func (s *S) TestFindRoleTemplate(c *check.C) {
+	t, err := FindRoleTemplate("auditor")
+	c.Assert(err, check.IsNil)
+	c.Assert(t.Builtin, check.Equals, true)
+	err = AddRoleTemplate(RoleTemplate{Name: "deployer", Description: "deploys", SchemeNames: []string{"app.deploy"}})
+	c.Assert(err, check.IsNil)
+	t, err = FindRoleTemplate("deployer")
+	c.Assert(err, check.IsNil)
+	c.Assert(t, check.DeepEquals, RoleTemplate{Name: "deployer", Description: "deploys", SchemeNames: []string{"app.deploy"}})
+	_, err = FindRoleTemplate("unknown")
+	c.Assert(err, check.Equals, permTypes.ErrRoleTemplateNotFound)
+}
+
+func (s *S) TestDestroyRoleTemplate(c *check.C) {
+	err := AddRoleTemplate(RoleTemplate{Name: "deployer", SchemeNames: []string{"app.deploy"}})
+	c.Assert(err, check.IsNil)
+	err = DestroyRoleTemplate("deployer")
+	c.Assert(err, check.IsNil)
+	_, err = FindRoleTemplate("deployer")
+	c.Assert(err, check.Equals, permTypes.ErrRoleTemplateNotFound)
+	err = DestroyRoleTemplate("deployer")
+	c.Assert(err, check.Equals, permTypes.ErrRoleTemplateNotFound)
+	err = DestroyRoleTemplate("developer")
+	c.Assert(err, check.Equals, permTypes.ErrRemoveBuiltinRoleTemplate)
+}
+
+func (s *S) TestRoleTemplateInstantiate(c *check.C) {
+	t, err := FindRoleTemplate("auditor")
+	c.Assert(err, check.IsNil)
+	role, err := t.Instantiate(t.RoleName("myteam"))
+	c.Assert(err, check.IsNil)
+	c.Assert(role.Name, check.Equals, "myteam-auditor")
+	dbRole, err := FindRole("myteam-auditor")
+	c.Assert(err, check.IsNil)
+	c.Assert(dbRole.ContextType, check.Equals, permTypes.CtxTeam)
+	c.Assert(dbRole.Template, check.Equals, "auditor")
+	c.Assert(dbRole.Description, check.Equals, t.Description)
+	c.Assert(dbRole.SchemeNames, check.DeepEquals, []string{"app.read", "job.read", "service-instance.read", "team.read.events", "team.read.quota"})
+	_, err = t.Instantiate("myteam-auditor")
+	c.Assert(err, check.Equals, permTypes.ErrRoleAlreadyExists)
+	_, err = t.Instantiate(" ")
+	c.Assert(err, check.Equals, permTypes.ErrInvalidRoleName)
+}
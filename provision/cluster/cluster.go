@@ -11,6 +11,8 @@ import (
 
 	"github.com/pkg/errors"
 	tsuruErrors "github.com/tsuru/tsuru/errors"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/permission"
 	"github.com/tsuru/tsuru/provision"
 	"github.com/tsuru/tsuru/storage"
 	provTypes "github.com/tsuru/tsuru/types/provision"
@@ -30,6 +32,11 @@ type clusterService struct {
 
 var _ provTypes.ClusterService = &clusterService{}
 
+func init() {
+	event.RegisterRedactor(permission.PermClusterCreate.FullName(), event.FormFieldsRedactor("clientkey", "kubeconfig.user"))
+	event.RegisterRedactor(permission.PermClusterUpdate.FullName(), event.FormFieldsRedactor("clientkey", "kubeconfig.user"))
+}
+
 func ClusterStorage() (provTypes.ClusterStorage, error) {
 	dbDriver, err := storage.GetCurrentDbDriver()
 	if err != nil {
@@ -111,11 +118,50 @@ poolLoop:
 			return nil, errors.Errorf("unable to find cluster for pool %q", pool)
 		}
 	}
+	for pool, cluster := range result {
+		if standby := standbyCluster(provClusters, &cluster, pool); standby != nil {
+			result[pool] = *standby
+		}
+	}
 	return result, nil
 }
 
 func (s *clusterService) FindByPool(ctx context.Context, prov, pool string) (*provTypes.Cluster, error) {
-	return s.storage.FindByPool(ctx, prov, pool)
+	cluster, err := s.storage.FindByPool(ctx, prov, pool)
+	if err != nil || !cluster.Unavailable {
+		return cluster, err
+	}
+	provClusters, err := s.storage.FindByProvisioner(ctx, prov)
+	if err != nil {
+		return nil, err
+	}
+	if standby := standbyCluster(provClusters, cluster, pool); standby != nil {
+		return standby, nil
+	}
+	return cluster, nil
+}
+
+// standbyCluster returns the cluster serving the pool while the given
+// cluster is unavailable, the first available cluster, by name, having the
+// pool as a standby pool. It returns nil when the given cluster is available
+// or there's no standby cluster for the pool.
+func standbyCluster(clusters []provTypes.Cluster, cluster *provTypes.Cluster, pool string) *provTypes.Cluster {
+	if !cluster.Unavailable {
+		return nil
+	}
+	var standby *provTypes.Cluster
+	for i := range clusters {
+		c := &clusters[i]
+		if c.Unavailable || c.Name == cluster.Name {
+			continue
+		}
+		for _, standbyPool := range c.StandbyPools {
+			if standbyPool == pool && (standby == nil || c.Name < standby.Name) {
+				standby = c
+			}
+		}
+	}
+	return standby
 }
 
 func (s *clusterService) Delete(ctx context.Context, c provTypes.Cluster) error {
@@ -158,10 +204,17 @@ func (s *clusterService) validate(c provTypes.Cluster, isNewCluster bool) error
 			return errors.WithStack(&tsuruErrors.ValidationError{Message: "cannot have both pools and default set"})
 		}
 	} else {
-		if !c.Default {
+		if !c.Default && len(c.StandbyPools) == 0 {
 			return errors.WithStack(&tsuruErrors.ValidationError{Message: "either default or a list of pools must be set"})
 		}
 	}
+	for _, standbyPool := range c.StandbyPools {
+		for _, pool := range c.Pools {
+			if pool == standbyPool {
+				return errors.WithStack(&tsuruErrors.ValidationError{Message: fmt.Sprintf("pool %q cannot be both a pool and a standby pool", pool)})
+			}
+		}
+	}
 	prov, err := provision.Get(c.Provisioner)
 	if err != nil {
 		return errors.WithStack(&tsuruErrors.ValidationError{Message: fmt.Sprintf("provisioner error: %v", err)})
@@ -161,6 +161,25 @@ func (s *S) TestClusterServiceUpdateValidationError(c *check.C) {
 			},
 			err: "cannot have both pools and default set",
 		},
+		{
+			c: provTypes.Cluster{
+				Name:         "c1",
+				Addresses:    []string{"addr1"},
+				StandbyPools: []string{"p1"},
+				Provisioner:  "fake",
+			},
+			err: "",
+		},
+		{
+			c: provTypes.Cluster{
+				Name:         "c1",
+				Addresses:    []string{"addr1"},
+				Pools:        []string{"p1", "p2"},
+				StandbyPools: []string{"p2"},
+				Provisioner:  "fake",
+			},
+			err: `pool "p2" cannot be both a pool and a standby pool`,
+		},
 		{
 			c: provTypes.Cluster{
 				Name:        "c1",
@@ -270,6 +289,33 @@ func (s *S) TestClusterServiceFindByPool(c *check.C) {
 	c.Assert(*result, check.DeepEquals, cluster)
 }
 
+func (s *S) TestClusterServiceFindByPoolUnavailable(c *check.C) {
+	clusters := []provTypes.Cluster{
+		{Name: "cluster1", Provisioner: "kubernetes", Pools: []string{"pool-a"}, Unavailable: true},
+		{Name: "cluster3", Provisioner: "kubernetes", StandbyPools: []string{"pool-a"}},
+		{Name: "cluster2", Provisioner: "kubernetes", StandbyPools: []string{"pool-a"}},
+		{Name: "cluster0", Provisioner: "kubernetes", StandbyPools: []string{"pool-a"}, Unavailable: true},
+	}
+	cs := &clusterService{
+		storage: &provTypes.MockClusterStorage{
+			OnFindByPool: func(prov, pool string) (*provTypes.Cluster, error) {
+				return &clusters[0], nil
+			},
+			OnFindByProvisioner: func(prov string) ([]provTypes.Cluster, error) {
+				c.Assert(prov, check.Equals, "kubernetes")
+				return clusters, nil
+			},
+		},
+	}
+	result, err := cs.FindByPool(context.TODO(), "kubernetes", "pool-a")
+	c.Assert(err, check.IsNil)
+	c.Assert(*result, check.DeepEquals, clusters[2])
+	clusters = clusters[:1]
+	result, err = cs.FindByPool(context.TODO(), "kubernetes", "pool-a")
+	c.Assert(err, check.IsNil)
+	c.Assert(*result, check.DeepEquals, clusters[0])
+}
+
 func (s *S) TestClusterServiceFindByPoolNotFound(c *check.C) {
 	cs := &clusterService{
 		storage: &provTypes.MockClusterStorage{
@@ -385,6 +431,29 @@ func (s *S) TestFindByPools(c *check.C) {
 	})
 }
 
+func (s *S) TestFindByPoolsUnavailable(c *check.C) {
+	clusters := []provTypes.Cluster{
+		{Name: "cluster1", Provisioner: "kubernetes", Pools: []string{"poolA"}, Unavailable: true},
+		{Name: "cluster2", Provisioner: "kubernetes", Default: true, Unavailable: true},
+		{Name: "cluster3", Provisioner: "kubernetes", Pools: []string{"poolC"}, StandbyPools: []string{"poolA", "poolB"}},
+	}
+	cs := &clusterService{
+		storage: &provTypes.MockClusterStorage{
+			OnFindByProvisioner: func(prov string) ([]provTypes.Cluster, error) {
+				return clusters, nil
+			},
+		},
+	}
+	result, err := cs.FindByPools(context.TODO(), "kubernetes", []string{"poolA", "poolB", "poolC", "poolD"})
+	c.Assert(err, check.IsNil)
+	c.Assert(result, check.DeepEquals, map[string]provTypes.Cluster{
+		"poolA": clusters[2],
+		"poolB": clusters[2],
+		"poolC": clusters[2],
+		"poolD": clusters[1],
+	})
+}
+
 func (s *S) TestFindByPoolsNotFound(c *check.C) {
 	prov := "prov1"
 	clusters := []provTypes.Cluster{
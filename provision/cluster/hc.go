@@ -0,0 +1,59 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/tsuru/tsuru/hc"
+	"github.com/tsuru/tsuru/provision"
+	"github.com/tsuru/tsuru/servicemanager"
+	provTypes "github.com/tsuru/tsuru/types/provision"
+)
+
+// ClusterHealthChecker is a provisioner able to check whether a cluster is
+// reachable.
+type ClusterHealthChecker interface {
+	HealthCheckCluster(ctx context.Context, c *provTypes.Cluster) error
+}
+
+func init() {
+	hc.AddChecker("Clusters", healthCheck)
+}
+
+func healthCheck(ctx context.Context) error {
+	if servicemanager.Cluster == nil {
+		return hc.ErrDisabledComponent
+	}
+	clusters, err := servicemanager.Cluster.List(ctx)
+	if err == provTypes.ErrNoCluster || (err == nil && len(clusters) == 0) {
+		return hc.ErrDisabledComponent
+	}
+	if err != nil {
+		return err
+	}
+	var errs []string
+	for i, c := range clusters {
+		prov, err := provision.Get(c.Provisioner)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("cluster %q: %v", c.Name, err))
+			continue
+		}
+		checker, ok := prov.(ClusterHealthChecker)
+		if !ok {
+			continue
+		}
+		if err = checker.HealthCheckCluster(ctx, &clusters[i]); err != nil {
+			errs = append(errs, fmt.Sprintf("cluster %q: %v", c.Name, err))
+		}
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cluster
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/tsuru/tsuru/hc"
+	"github.com/tsuru/tsuru/provision"
+	"github.com/tsuru/tsuru/provision/provisiontest"
+	"github.com/tsuru/tsuru/servicemanager"
+	provTypes "github.com/tsuru/tsuru/types/provision"
+	check "gopkg.in/check.v1"
+)
+
+var _ ClusterHealthChecker = &hcClusterProv{}
+
+type hcClusterProv struct {
+	*provisiontest.FakeProvisioner
+	failures map[string]error
+}
+
+func (p *hcClusterProv) HealthCheckCluster(ctx context.Context, c *provTypes.Cluster) error {
+	return p.failures[c.Name]
+}
+
+func (s *S) TestHealthCheck(c *check.C) {
+	inst := hcClusterProv{
+		FakeProvisioner: provisiontest.ProvisionerInstance,
+		failures:        map[string]error{"c2": errors.New("unreachable")},
+	}
+	provision.Register("fake-cluster", func() (provision.Provisioner, error) {
+		return &inst, nil
+	})
+	defer provision.Unregister("fake-cluster")
+	servicemanager.Cluster = &provTypes.MockClusterService{
+		OnList: func() ([]provTypes.Cluster, error) {
+			return []provTypes.Cluster{
+				{Name: "c1", Provisioner: "fake-cluster"},
+				{Name: "c2", Provisioner: "fake-cluster"},
+				{Name: "c3", Provisioner: "fake"},
+			}, nil
+		},
+	}
+	err := healthCheck(context.TODO())
+	c.Assert(err, check.ErrorMatches, `cluster "c2": unreachable`)
+	delete(inst.failures, "c2")
+	err = healthCheck(context.TODO())
+	c.Assert(err, check.IsNil)
+}
+
+func (s *S) TestHealthCheckNoClusters(c *check.C) {
+	servicemanager.Cluster = &provTypes.MockClusterService{
+		OnList: func() ([]provTypes.Cluster, error) {
+			return nil, provTypes.ErrNoCluster
+		},
+	}
+	err := healthCheck(context.TODO())
+	c.Assert(err, check.Equals, hc.ErrDisabledComponent)
+}
@@ -0,0 +1,150 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cluster
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/tsuru/tsuru/api/shutdown"
+	"github.com/tsuru/tsuru/log"
+	"github.com/tsuru/tsuru/provision"
+	"github.com/tsuru/tsuru/servicemanager"
+	provTypes "github.com/tsuru/tsuru/types/provision"
+)
+
+const statusRunInterval = time.Minute
+
+var ErrStatusNotSupported = errors.New("cluster provisioner does not support status")
+
+// ClusterStatusChecker is a provisioner able to run diagnostics on a cluster,
+// checking its API, nodes and the components required by tsuru.
+type ClusterStatusChecker interface {
+	ClusterStatus(ctx context.Context, c *provTypes.Cluster) provTypes.ClusterStatus
+}
+
+var statuses = &statusCache{entries: map[string]provTypes.ClusterStatus{}}
+
+type statusCache struct {
+	sync.RWMutex
+	entries map[string]provTypes.ClusterStatus
+}
+
+func (s *statusCache) get(name string) (provTypes.ClusterStatus, bool) {
+	s.RLock()
+	defer s.RUnlock()
+	status, ok := s.entries[name]
+	return status, ok
+}
+
+func (s *statusCache) set(name string, status provTypes.ClusterStatus) {
+	s.Lock()
+	defer s.Unlock()
+	s.entries[name] = status
+}
+
+func (s *statusCache) keep(names map[string]struct{}) {
+	s.Lock()
+	defer s.Unlock()
+	for name := range s.entries {
+		if _, ok := names[name]; !ok {
+			delete(s.entries, name)
+		}
+	}
+}
+
+// Status returns the last status evaluated for the cluster. The status is
+// evaluated right away when it's missing or older than twice the interval of
+// the periodic evaluation.
+func Status(ctx context.Context, c *provTypes.Cluster) (*provTypes.ClusterStatus, error) {
+	if status, ok := statuses.get(c.Name); ok && time.Since(status.CheckedAt) < 2*statusRunInterval {
+		return &status, nil
+	}
+	status, err := evaluateStatus(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+func evaluateStatus(ctx context.Context, c *provTypes.Cluster) (provTypes.ClusterStatus, error) {
+	prov, err := provision.Get(c.Provisioner)
+	if err != nil {
+		return provTypes.ClusterStatus{}, err
+	}
+	checker, ok := prov.(ClusterStatusChecker)
+	if !ok {
+		return provTypes.ClusterStatus{}, ErrStatusNotSupported
+	}
+	status := checker.ClusterStatus(ctx, c)
+	status.CheckedAt = time.Now().UTC()
+	statuses.set(c.Name, status)
+	return status, nil
+}
+
+// RefreshStatus evaluates the status of every cluster, keeping them cached.
+func RefreshStatus(ctx context.Context) error {
+	clusters, err := servicemanager.Cluster.List(ctx)
+	if err != nil && err != provTypes.ErrNoCluster {
+		return err
+	}
+	names := make(map[string]struct{}, len(clusters))
+	for i := range clusters {
+		names[clusters[i].Name] = struct{}{}
+		_, err = evaluateStatus(ctx, &clusters[i])
+		if err != nil && err != ErrStatusNotSupported {
+			log.Errorf("[cluster status] unable to evaluate status of cluster %q: %v", clusters[i].Name, err)
+		}
+	}
+	statuses.keep(names)
+	return nil
+}
+
+func InitializeStatus() error {
+	r := &statusRefresher{once: &sync.Once{}}
+	r.start()
+	shutdown.Register(r)
+	return nil
+}
+
+// statusRefresher periodically evaluates the status of the clusters, so
+// requests for it are answered from the cache.
+type statusRefresher struct {
+	once   *sync.Once
+	stopCh chan struct{}
+}
+
+func (r *statusRefresher) start() {
+	r.once.Do(func() {
+		r.stopCh = make(chan struct{})
+		go r.spin()
+	})
+}
+
+func (r *statusRefresher) Shutdown(ctx context.Context) error {
+	if r.stopCh == nil {
+		return nil
+	}
+	r.stopCh <- struct{}{}
+	r.stopCh = nil
+	r.once = &sync.Once{}
+	return nil
+}
+
+func (r *statusRefresher) spin() {
+	for {
+		err := RefreshStatus(context.Background())
+		if err != nil {
+			log.Errorf("[cluster status] %v", err)
+		}
+		select {
+		case <-r.stopCh:
+			return
+		case <-time.After(statusRunInterval):
+		}
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cluster
+
+import (
+	"context"
+	"time"
+
+	"github.com/tsuru/tsuru/provision"
+	"github.com/tsuru/tsuru/provision/provisiontest"
+	"github.com/tsuru/tsuru/servicemanager"
+	provTypes "github.com/tsuru/tsuru/types/provision"
+	check "gopkg.in/check.v1"
+)
+
+var _ ClusterStatusChecker = &statusClusterProv{}
+
+type statusClusterProv struct {
+	*provisiontest.FakeProvisioner
+	calls int
+}
+
+func (p *statusClusterProv) ClusterStatus(ctx context.Context, c *provTypes.Cluster) provTypes.ClusterStatus {
+	p.calls++
+	return provTypes.ClusterStatus{Reachable: true, Version: "v1.25.6"}
+}
+
+func (s *S) TestStatus(c *check.C) {
+	inst := statusClusterProv{FakeProvisioner: provisiontest.ProvisionerInstance}
+	provision.Register("fake-status", func() (provision.Provisioner, error) {
+		return &inst, nil
+	})
+	defer provision.Unregister("fake-status")
+	defer statuses.keep(nil)
+	myCluster := provTypes.Cluster{Name: "c1", Provisioner: "fake-status"}
+	status, err := Status(context.TODO(), &myCluster)
+	c.Assert(err, check.IsNil)
+	c.Assert(status.Reachable, check.Equals, true)
+	c.Assert(status.Version, check.Equals, "v1.25.6")
+	c.Assert(status.CheckedAt.IsZero(), check.Equals, false)
+	_, err = Status(context.TODO(), &myCluster)
+	c.Assert(err, check.IsNil)
+	c.Assert(inst.calls, check.Equals, 1)
+	stale := *status
+	stale.CheckedAt = time.Now().Add(-2 * statusRunInterval)
+	statuses.set(myCluster.Name, stale)
+	_, err = Status(context.TODO(), &myCluster)
+	c.Assert(err, check.IsNil)
+	c.Assert(inst.calls, check.Equals, 2)
+}
+
+func (s *S) TestStatusNotSupported(c *check.C) {
+	_, err := Status(context.TODO(), &provTypes.Cluster{Name: "c1", Provisioner: "fake"})
+	c.Assert(err, check.Equals, ErrStatusNotSupported)
+}
+
+func (s *S) TestRefreshStatus(c *check.C) {
+	inst := statusClusterProv{FakeProvisioner: provisiontest.ProvisionerInstance}
+	provision.Register("fake-status", func() (provision.Provisioner, error) {
+		return &inst, nil
+	})
+	defer provision.Unregister("fake-status")
+	defer statuses.keep(nil)
+	statuses.set("removed", provTypes.ClusterStatus{CheckedAt: time.Now()})
+	servicemanager.Cluster = &provTypes.MockClusterService{
+		OnList: func() ([]provTypes.Cluster, error) {
+			return []provTypes.Cluster{
+				{Name: "c1", Provisioner: "fake-status"},
+				{Name: "c2", Provisioner: "fake"},
+			}, nil
+		},
+	}
+	err := RefreshStatus(context.TODO())
+	c.Assert(err, check.IsNil)
+	c.Assert(inst.calls, check.Equals, 1)
+	status, ok := statuses.get("c1")
+	c.Assert(ok, check.Equals, true)
+	c.Assert(status.Reachable, check.Equals, true)
+	_, ok = statuses.get("c2")
+	c.Assert(ok, check.Equals, false)
+	_, ok = statuses.get("removed")
+	c.Assert(ok, check.Equals, false)
+}
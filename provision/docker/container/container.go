@@ -355,6 +355,10 @@ func (e *execErr) Error() string {
 	return fmt.Sprintf("unexpected exit code: %d", e.code)
 }
 
+func (e *execErr) ExitStatus() int {
+	return e.code
+}
+
 // Commits commits the container, creating an image in Docker. It then returns
 // the image identifier for usage in future container creation.
 func (c *Container) Commit(client provision.BuilderDockerClient, limiter provision.ActionLimiter, writer io.Writer, isDeploy bool) (string, error) {
@@ -461,10 +461,7 @@ func (p *dockerProvisioner) DestroyVersion(ctx context.Context, app provision.Ap
 }
 
 func (p *dockerProvisioner) runRestartAfterHooks(cont *container.Container, yamlData provTypes.TsuruYamlData, w io.Writer) error {
-	if yamlData.Hooks == nil {
-		return nil
-	}
-	cmds := yamlData.Hooks.Restart.After
+	cmds := yamlData.Hooks.RestartAfter(cont.ProcessName)
 	for _, cmd := range cmds {
 		err := cont.Exec(p.ClusterClient(), nil, w, w, container.Pty{}, "/bin/sh", "-lc", cmd)
 		if err != nil {
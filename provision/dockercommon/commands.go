@@ -115,16 +115,14 @@ func LeanContainerCmdsWithExtra(processName string, cmdData ContainerCmdsData, a
 		cmds, err = runWithAgentCmds(app)
 		return cmds, "", err
 	}
-	if cmdData.yamlData.Hooks != nil {
-		extraCmds = append(extraCmds, cmdData.yamlData.Hooks.Restart.Before...)
+	if processName == "" {
+		processName = provision.WebProcessName
 	}
+	extraCmds = append(extraCmds, cmdData.yamlData.Hooks.RestartBefore(processName)...)
 	before := strings.Join(extraCmds, " && ")
 	if before != "" {
 		before += " && "
 	}
-	if processName == "" {
-		processName = provision.WebProcessName
-	}
 	allCmds := []string{
 		"/bin/sh",
 		"-lc",
@@ -135,6 +135,37 @@ func (s *S) TestRunLeanContainersCmdHooks(c *check.C) {
 	c.Assert(cmds, check.DeepEquals, expected)
 }
 
+func (s *S) TestRunLeanContainersCmdProcessHooks(c *check.C) {
+	customData := map[string]interface{}{
+		"hooks": map[string]interface{}{
+			"restart": map[string]interface{}{
+				"before": []string{"cmd1"},
+			},
+			"processes": map[string]interface{}{
+				"worker": map[string]interface{}{
+					"restart": map[string]interface{}{
+						"before": []string{"cmd2"},
+					},
+				},
+			},
+		},
+		"processes": map[string]interface{}{
+			"web":    "python web.py",
+			"worker": "python worker.py",
+		},
+	}
+	fakeApp := provisiontest.NewFakeApp("sample", "python", 0)
+	version := newVersion(c, fakeApp, customData)
+	cmdData, err := dockercommon.ContainerCmdsDataFromVersion(version)
+	c.Assert(err, check.IsNil)
+	cmds, _, err := dockercommon.LeanContainerCmds("web", cmdData, nil)
+	c.Assert(err, check.IsNil)
+	c.Assert(cmds, check.DeepEquals, []string{"/bin/sh", "-lc", "[ -d /home/application/current ] && cd /home/application/current; cmd1 && exec python web.py"})
+	cmds, _, err = dockercommon.LeanContainerCmds("worker", cmdData, nil)
+	c.Assert(err, check.IsNil)
+	c.Assert(cmds, check.DeepEquals, []string{"/bin/sh", "-lc", "[ -d /home/application/current ] && cd /home/application/current; cmd1 && cmd2 && exec python worker.py"})
+}
+
 func (s *S) TestRunLeanContainersCmdNoProcesses(c *check.C) {
 	customData := map[string]interface{}{}
 	fakeApp := provisiontest.NewFakeApp("sample", "python", 0)
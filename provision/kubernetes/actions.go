@@ -16,11 +16,12 @@ import (
 )
 
 type updatePipelineParams struct {
-	p        *kubernetesProvisioner
-	new      provision.App
-	old      provision.App
-	versions []appTypes.AppVersion
-	w        io.Writer
+	p            *kubernetesProvisioner
+	new          provision.App
+	old          provision.App
+	oldNamespace string
+	versions     []appTypes.AppVersion
+	w            io.Writer
 }
 
 var provisionNewApp = action.Action{
@@ -99,7 +100,7 @@ var updateAppCR = action.Action{
 		if err != nil {
 			return nil, err
 		}
-		return nil, updateAppNamespace(ctx.Context, client, params.old.GetName(), client.PoolNamespace(params.new.GetPool()))
+		return nil, updateAppNamespace(ctx.Context, client, params.old.GetName(), client.namespaceForApp(params.new))
 	},
 	Backward: func(ctx action.BWContext) {
 		params := ctx.Params[0].(updatePipelineParams)
@@ -114,7 +115,7 @@ func backwardCR(ctx context.Context, params updatePipelineParams) error {
 	if err != nil {
 		return err
 	}
-	return updateAppNamespace(ctx, client, params.old.GetName(), client.PoolNamespace(params.old.GetPool()))
+	return updateAppNamespace(ctx, client, params.old.GetName(), params.oldNamespace)
 }
 
 var removeOldAppResources = action.Action{
@@ -131,7 +132,7 @@ var removeOldAppResources = action.Action{
 			log.Errorf("failed to remove old resources: %v", err)
 			return nil, nil
 		}
-		oldAppCR.Spec.NamespaceName = client.PoolNamespace(params.old.GetPool())
+		oldAppCR.Spec.NamespaceName = params.oldNamespace
 		err = params.p.removeResources(ctx.Context, client, oldAppCR, params.old)
 		if err != nil {
 			log.Errorf("failed to remove old resources: %v", err)
@@ -6,6 +6,7 @@ package kubernetes
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"strings"
@@ -26,6 +27,11 @@ import (
 
 const (
 	vpaCRDName = "verticalpodautoscalers.autoscaling.k8s.io"
+
+	autoScalePrometheusAnnotation = tsuruLabelPrefix + "autoscale-prometheus"
+
+	prometheusQueryAnnotationFormat = "metric-config.external.%s.prometheus/query"
+	prometheusMetricTypeLabel       = "type"
 )
 
 var errNoDeploy = errors.New("no routable version found for app, at least one deploy is required before configuring autoscale")
@@ -166,15 +172,22 @@ func hpaToSpec(hpa autoscalingv2.HorizontalPodAutoscaler) provision.AutoScaleSpe
 	if hpa.Spec.MinReplicas != nil {
 		spec.MinUnits = uint(*hpa.Spec.MinReplicas)
 	}
+	hpaAnnotation(&hpa, autoScaleSchedulesAnnotation, &spec.Schedules)
+	hpaAnnotation(&hpa, autoScaleLimitsAnnotation, &spec)
+	hpaAnnotation(&hpa, autoScalePrometheusAnnotation, &spec.Prometheus)
 
 	cpuValue := int64(0)
-	if len(hpa.Spec.Metrics) > 0 && hpa.Spec.Metrics[0].Resource != nil {
-		if hpa.Spec.Metrics[0].Resource.Target.AverageUtilization != nil {
-			cpuValue = int64(*hpa.Spec.Metrics[0].Resource.Target.AverageUtilization)
+	for _, metric := range hpa.Spec.Metrics {
+		if metric.Resource == nil {
+			continue
+		}
+		if metric.Resource.Target.AverageUtilization != nil {
+			cpuValue = int64(*metric.Resource.Target.AverageUtilization)
 			cpuValue = cpuValue * 10
-		} else if hpa.Spec.Metrics[0].Resource.Target.AverageValue != nil {
-			cpuValue = hpa.Spec.Metrics[0].Resource.Target.AverageValue.MilliValue()
+		} else if metric.Resource.Target.AverageValue != nil {
+			cpuValue = metric.Resource.Target.AverageValue.MilliValue()
 		}
+		break
 	}
 
 	if cpuValue > 0 {
@@ -184,6 +197,60 @@ func hpaToSpec(hpa autoscalingv2.HorizontalPodAutoscaler) provision.AutoScaleSpe
 	return spec
 }
 
+// setHPAAnnotation stores in the HPA, as json, parts of the autoscale spec
+// that cannot be read back from the HPA spec itself.
+func setHPAAnnotation(hpa *autoscalingv2.HorizontalPodAutoscaler, key string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if hpa.Annotations == nil {
+		hpa.Annotations = map[string]string{}
+	}
+	hpa.Annotations[key] = string(data)
+	return nil
+}
+
+func hpaAnnotation(hpa *autoscalingv2.HorizontalPodAutoscaler, key string, dst interface{}) {
+	data, ok := hpa.Annotations[key]
+	if !ok {
+		return
+	}
+	json.Unmarshal([]byte(data), dst)
+}
+
+// prometheusMetricSpec returns the HPA external metric for a prometheus
+// autoscale metric and sets its query in the HPA annotations, in the format
+// read by kube-metrics-adapter, which must be installed in the cluster. The
+// selector holds the app and process the metric refers to.
+func prometheusMetricSpec(hpa *autoscalingv2.HorizontalPodAutoscaler, metric provision.AutoScalePrometheus, selector map[string]string) autoscalingv2.MetricSpec {
+	name := metric.ExternalMetricName()
+	if hpa.Annotations == nil {
+		hpa.Annotations = map[string]string{}
+	}
+	hpa.Annotations[fmt.Sprintf(prometheusQueryAnnotationFormat, name)] = metric.Query
+	matchLabels := map[string]string{prometheusMetricTypeLabel: "prometheus"}
+	for k, v := range selector {
+		matchLabels[k] = v
+	}
+	threshold := resource.NewMilliQuantity(int64(metric.Threshold*1000), resource.DecimalSI)
+	// Fill string value for easier tests
+	_ = threshold.String()
+	return autoscalingv2.MetricSpec{
+		Type: autoscalingv2.ExternalMetricSourceType,
+		External: &autoscalingv2.ExternalMetricSource{
+			Metric: autoscalingv2.MetricIdentifier{
+				Name:     name,
+				Selector: &metav1.LabelSelector{MatchLabels: matchLabels},
+			},
+			Target: autoscalingv2.MetricTarget{
+				Type:         autoscalingv2.AverageValueMetricType,
+				AverageValue: threshold,
+			},
+		},
+	}
+}
+
 func (p *kubernetesProvisioner) deleteAllAutoScale(ctx context.Context, a provision.App) error {
 	scaleSpecs, err := p.GetAutoScale(ctx, a)
 	if err != nil {
@@ -232,7 +299,11 @@ func (p *kubernetesProvisioner) RemoveAutoScale(ctx context.Context, a provision
 		return errors.WithStack(err)
 	}
 
-	return nil
+	err = deleteAutoScaleSchedules(ctx, client, a, depInfo.process, nil)
+	if err != nil {
+		return err
+	}
+	return errors.Wrap(ensurePDB(ctx, client, a, depInfo.process), "unable to ensure pod disruption budget")
 }
 
 func (p *kubernetesProvisioner) SetAutoScale(ctx context.Context, a provision.App, spec provision.AutoScaleSpec) error {
@@ -266,21 +337,31 @@ func setAutoScale(ctx context.Context, client *ClusterClient, a provision.App, s
 
 	hpaName := hpaNameForApp(a, depInfo.process)
 
-	cpuValue, err := spec.ToCPUValue(a)
-	if err != nil {
-		return errors.WithStack(err)
-	}
+	var metrics []autoscalingv2.MetricSpec
+	if spec.AverageCPU != "" || len(spec.Prometheus) == 0 {
+		cpuValue, err := spec.ToCPUValue(a)
+		if err != nil {
+			return errors.WithStack(err)
+		}
 
-	target := autoscalingv2.MetricTarget{}
-	if a.GetMilliCPU() > 0 {
-		target.Type = autoscalingv2.UtilizationMetricType
-		val := int32(cpuValue)
-		target.AverageUtilization = &val
-	} else {
-		target.Type = autoscalingv2.AverageValueMetricType
-		target.AverageValue = resource.NewMilliQuantity(int64(cpuValue), resource.DecimalSI)
-		// Fill string value for easier tests
-		_ = target.AverageValue.String()
+		target := autoscalingv2.MetricTarget{}
+		if a.GetProcessMilliCPU(depInfo.process) > 0 {
+			target.Type = autoscalingv2.UtilizationMetricType
+			val := int32(cpuValue)
+			target.AverageUtilization = &val
+		} else {
+			target.Type = autoscalingv2.AverageValueMetricType
+			target.AverageValue = resource.NewMilliQuantity(int64(cpuValue), resource.DecimalSI)
+			// Fill string value for easier tests
+			_ = target.AverageValue.String()
+		}
+		metrics = append(metrics, autoscalingv2.MetricSpec{
+			Type: autoscalingv2.ResourceMetricSourceType,
+			Resource: &autoscalingv2.ResourceMetricSource{
+				Name:   "cpu",
+				Target: target,
+			},
+		})
 	}
 
 	policyMin := autoscalingv2.MinPolicySelect
@@ -318,17 +399,41 @@ func setAutoScale(ctx context.Context, client *ClusterClient, a provision.App, s
 					},
 				},
 			},
-			Metrics: []autoscalingv2.MetricSpec{
-				{
-					Type: autoscalingv2.ResourceMetricSourceType,
-					Resource: &autoscalingv2.ResourceMetricSource{
-						Name:   "cpu",
-						Target: target,
-					},
-				},
-			},
 		},
 	}
+	for _, metric := range spec.Prometheus {
+		metrics = append(metrics, prometheusMetricSpec(hpa, metric, labels.ToHPASelector()))
+	}
+	hpa.Spec.Metrics = metrics
+	if len(spec.Schedules) > 0 {
+		err = setHPAAnnotation(hpa, autoScaleSchedulesAnnotation, spec.Schedules)
+		if err != nil {
+			return err
+		}
+		err = setHPAAnnotation(hpa, autoScaleLimitsAnnotation, map[string]uint{
+			"minUnits": spec.MinUnits,
+			"maxUnits": spec.MaxUnits,
+		})
+		if err != nil {
+			return err
+		}
+		var schedule *provision.AutoScaleSchedule
+		schedule, err = activeAutoScaleSchedule(ctx, client, a, depInfo.process, spec)
+		if err != nil {
+			return err
+		}
+		if schedule != nil {
+			scheduleMinUnits := int32(schedule.MinUnits)
+			hpa.Spec.MinReplicas = &scheduleMinUnits
+			hpa.Spec.MaxReplicas = int32(schedule.MaxUnits)
+		}
+	}
+	if len(spec.Prometheus) > 0 {
+		err = setHPAAnnotation(hpa, autoScalePrometheusAnnotation, spec.Prometheus)
+		if err != nil {
+			return err
+		}
+	}
 
 	ns, err := client.AppNamespace(ctx, a)
 	if err != nil {
@@ -350,7 +455,11 @@ func setAutoScale(ctx context.Context, client *ClusterClient, a provision.App, s
 	if err != nil {
 		return errors.WithStack(err)
 	}
-	return nil
+	err = ensureAutoScaleSchedules(ctx, client, a, depInfo.process, hpaName, labels, spec)
+	if err != nil {
+		return err
+	}
+	return errors.Wrap(ensurePDB(ctx, client, a, depInfo.process), "unable to ensure pod disruption budget")
 }
 
 func minimumAutoScaleVersion(ctx context.Context, client *ClusterClient, a provision.App, process string) (*deploymentInfo, error) {
@@ -0,0 +1,282 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kubernetes
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/tsuru/tsuru/provision"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	apiv1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	autoScaleSchedulesAnnotation = tsuruLabelPrefix + "autoscale-schedules"
+	autoScaleLimitsAnnotation    = tsuruLabelPrefix + "autoscale-limits"
+	autoScaleScheduleLabel       = tsuruLabelPrefix + "is-autoscale-schedule"
+	autoScaleSchedulerName       = "tsuru-autoscale-scheduler"
+
+	cronJobNameMaxLen = 52
+)
+
+// autoScaleScheduleCronJobName returns the name of the cron job changing the
+// HPA limits of a process at the start or end of one of its schedules.
+func autoScaleScheduleCronJobName(a provision.App, process string, index int, edge string) string {
+	name := provision.AppProcessName(a, process, 0, fmt.Sprintf("sched-%d-%s", index, edge))
+	if len(name) > cronJobNameMaxLen {
+		hash := fmt.Sprintf("%x", sha256.Sum256([]byte(name)))
+		name = fmt.Sprintf("%s-%s", name[:cronJobNameMaxLen-9], hash[:8])
+	}
+	return name
+}
+
+func autoScaleScheduleSelector(ctx context.Context, a provision.App, process string) (labels.Selector, error) {
+	ls, err := provision.ServiceLabels(ctx, provision.ServiceLabelsOpts{
+		App:     a,
+		Process: process,
+		ServiceLabelExtendedOpts: provision.ServiceLabelExtendedOpts{
+			Prefix:      tsuruLabelPrefix,
+			Provisioner: provisionerName,
+		},
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	set := labels.Set(ls.ToHPASelector())
+	set[autoScaleScheduleLabel] = "true"
+	return labels.SelectorFromSet(set), nil
+}
+
+// ensureAutoScaleSchedules keeps one pair of cron jobs for each schedule in
+// the autoscale spec, the first one patching the HPA limits to the schedule
+// ones when it starts and the second one restoring the spec limits when it
+// ends. The spec limits are kept in the HPA annotations, as its limits are
+// the schedule ones during a schedule window.
+func ensureAutoScaleSchedules(ctx context.Context, client *ClusterClient, a provision.App, process, hpaName string, ls *provision.LabelSet, spec provision.AutoScaleSpec) error {
+	ns, err := client.AppNamespace(ctx, a)
+	if err != nil {
+		return err
+	}
+	if len(spec.Schedules) > 0 {
+		err = ensureAutoScaleSchedulerAccount(ctx, client, ns)
+		if err != nil {
+			return err
+		}
+	}
+	cronLabels := ls.ToLabels()
+	cronLabels[autoScaleScheduleLabel] = "true"
+	image := getKubeConfig().autoScaleSchedulerImage
+	wanted := map[string]struct{}{}
+	for i, schedule := range spec.Schedules {
+		edges := []struct {
+			name     string
+			cron     string
+			minUnits uint
+			maxUnits uint
+		}{
+			{name: "start", cron: schedule.Start, minUnits: schedule.MinUnits, maxUnits: schedule.MaxUnits},
+			{name: "end", cron: schedule.End, minUnits: spec.MinUnits, maxUnits: spec.MaxUnits},
+		}
+		for _, edge := range edges {
+			patch := fmt.Sprintf(`{"spec":{"minReplicas":%d,"maxReplicas":%d}}`, edge.minUnits, edge.maxUnits)
+			cronJob := newAutoScaleScheduleCronJob(
+				autoScaleScheduleCronJobName(a, process, i, edge.name),
+				edge.cron,
+				image,
+				cronLabels,
+				[]string{"kubectl", "patch", "hpa", hpaName, "--type=merge", "-p", patch},
+			)
+			wanted[cronJob.Name] = struct{}{}
+			err = upsertCronJob(ctx, client, ns, cronJob)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return deleteAutoScaleSchedules(ctx, client, a, process, wanted)
+}
+
+// activeAutoScaleSchedule returns the schedule of the spec whose window is
+// currently open, i.e. its start cron job ran after its end one, or nil when
+// the spec limits apply.
+func activeAutoScaleSchedule(ctx context.Context, client *ClusterClient, a provision.App, process string, spec provision.AutoScaleSpec) (*provision.AutoScaleSchedule, error) {
+	if len(spec.Schedules) == 0 {
+		return nil, nil
+	}
+	ns, err := client.AppNamespace(ctx, a)
+	if err != nil {
+		return nil, err
+	}
+	lastRun := func(name, schedule string) (*metav1.Time, error) {
+		cronJob, err := client.BatchV1beta1().CronJobs(ns).Get(ctx, name, metav1.GetOptions{})
+		if k8sErrors.IsNotFound(err) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if cronJob.Spec.Schedule != schedule {
+			return nil, nil
+		}
+		return cronJob.Status.LastScheduleTime, nil
+	}
+	for i, schedule := range spec.Schedules {
+		started, err := lastRun(autoScaleScheduleCronJobName(a, process, i, "start"), schedule.Start)
+		if err != nil {
+			return nil, err
+		}
+		if started == nil {
+			continue
+		}
+		ended, err := lastRun(autoScaleScheduleCronJobName(a, process, i, "end"), schedule.End)
+		if err != nil {
+			return nil, err
+		}
+		if ended == nil || ended.Before(started) {
+			return &spec.Schedules[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func newAutoScaleScheduleCronJob(name, schedule, image string, ls map[string]string, cmd []string) *batchv1beta1.CronJob {
+	historyLimit := int32(1)
+	backoffLimit := int32(3)
+	return &batchv1beta1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: ls,
+		},
+		Spec: batchv1beta1.CronJobSpec{
+			Schedule:                   schedule,
+			ConcurrencyPolicy:          batchv1beta1.ReplaceConcurrent,
+			SuccessfulJobsHistoryLimit: &historyLimit,
+			FailedJobsHistoryLimit:     &historyLimit,
+			JobTemplate: batchv1beta1.JobTemplateSpec{
+				Spec: batchv1.JobSpec{
+					BackoffLimit: &backoffLimit,
+					Template: apiv1.PodTemplateSpec{
+						// Pods must not carry the app labels, otherwise they
+						// would be listed as app units.
+						ObjectMeta: metav1.ObjectMeta{
+							Labels: map[string]string{autoScaleScheduleLabel: "true"},
+						},
+						Spec: apiv1.PodSpec{
+							ServiceAccountName: autoScaleSchedulerName,
+							RestartPolicy:      apiv1.RestartPolicyOnFailure,
+							Containers: []apiv1.Container{
+								{
+									Name:    "kubectl",
+									Image:   image,
+									Command: cmd,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func upsertCronJob(ctx context.Context, client *ClusterClient, ns string, cronJob *batchv1beta1.CronJob) error {
+	existing, err := client.BatchV1beta1().CronJobs(ns).Get(ctx, cronJob.Name, metav1.GetOptions{})
+	if k8sErrors.IsNotFound(err) {
+		_, err = client.BatchV1beta1().CronJobs(ns).Create(ctx, cronJob, metav1.CreateOptions{})
+	} else if err == nil {
+		cronJob.ResourceVersion = existing.ResourceVersion
+		cronJob.Status = existing.Status
+		_, err = client.BatchV1beta1().CronJobs(ns).Update(ctx, cronJob, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// deleteAutoScaleSchedules removes the schedule cron jobs of a process, except
+// for the ones in keep.
+func deleteAutoScaleSchedules(ctx context.Context, client *ClusterClient, a provision.App, process string, keep map[string]struct{}) error {
+	ns, err := client.AppNamespace(ctx, a)
+	if err != nil {
+		return err
+	}
+	selector, err := autoScaleScheduleSelector(ctx, a, process)
+	if err != nil {
+		return err
+	}
+	cronJobs, err := client.BatchV1beta1().CronJobs(ns).List(ctx, metav1.ListOptions{
+		LabelSelector: selector.String(),
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	propagation := metav1.DeletePropagationForeground
+	for _, cronJob := range cronJobs.Items {
+		if _, ok := keep[cronJob.Name]; ok {
+			continue
+		}
+		err = client.BatchV1beta1().CronJobs(ns).Delete(ctx, cronJob.Name, metav1.DeleteOptions{
+			PropagationPolicy: &propagation,
+		})
+		if err != nil && !k8sErrors.IsNotFound(err) {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
+
+// ensureAutoScaleSchedulerAccount creates the service account used by the
+// schedule cron jobs, allowed only to patch HPAs in the namespace.
+func ensureAutoScaleSchedulerAccount(ctx context.Context, client *ClusterClient, ns string) error {
+	meta := metav1.ObjectMeta{
+		Name:   autoScaleSchedulerName,
+		Labels: map[string]string{tsuruLabelPrefix + "is-tsuru": "true"},
+	}
+	_, err := client.CoreV1().ServiceAccounts(ns).Create(ctx, &apiv1.ServiceAccount{ObjectMeta: meta}, metav1.CreateOptions{})
+	if err != nil && !k8sErrors.IsAlreadyExists(err) {
+		return errors.WithStack(err)
+	}
+	_, err = client.RbacV1().Roles(ns).Create(ctx, &rbacv1.Role{
+		ObjectMeta: meta,
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{"autoscaling"},
+				Resources: []string{"horizontalpodautoscalers"},
+				Verbs:     []string{"get", "patch"},
+			},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil && !k8sErrors.IsAlreadyExists(err) {
+		return errors.WithStack(err)
+	}
+	_, err = client.RbacV1().RoleBindings(ns).Create(ctx, &rbacv1.RoleBinding{
+		ObjectMeta: meta,
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      autoScaleSchedulerName,
+				Namespace: ns,
+			},
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "Role",
+			Name:     autoScaleSchedulerName,
+		},
+	}, metav1.CreateOptions{})
+	if err != nil && !k8sErrors.IsAlreadyExists(err) {
+		return errors.WithStack(err)
+	}
+	return nil
+}
@@ -9,6 +9,7 @@ import (
 	"context"
 	"sort"
 	"strconv"
+	"time"
 
 	"github.com/kr/pretty"
 	"github.com/tsuru/tsuru/provision"
@@ -219,6 +220,275 @@ func (s *S) TestProvisionerSetAutoScale(c *check.C) {
 
 }
 
+func (s *S) TestProvisionerSetAutoScaleWithSchedules(c *check.C) {
+	a, wait, rollback := s.mock.DefaultReactions(c)
+	defer rollback()
+	version := newSuccessfulVersion(c, a, map[string]interface{}{
+		"processes": map[string]interface{}{
+			"web": "python myapp.py",
+		},
+	})
+	err := s.p.AddUnits(context.TODO(), a, 1, "web", version, nil)
+	c.Assert(err, check.IsNil)
+	wait()
+	schedules := []provision.AutoScaleSchedule{
+		{Start: "0 8 * * 1-5", End: "0 20 * * 1-5", MinUnits: 5, MaxUnits: 10},
+	}
+	err = s.p.SetAutoScale(context.TODO(), a, provision.AutoScaleSpec{
+		MinUnits:   1,
+		MaxUnits:   2,
+		AverageCPU: "500m",
+		Schedules:  schedules,
+	})
+	c.Assert(err, check.IsNil)
+	ns, err := s.client.AppNamespace(context.TODO(), a)
+	c.Assert(err, check.IsNil)
+	hpa, err := s.client.AutoscalingV2beta2().HorizontalPodAutoscalers(ns).Get(context.TODO(), "myapp-web", metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(hpa.Annotations, check.DeepEquals, map[string]string{
+		"tsuru.io/autoscale-schedules": `[{"start":"0 8 * * 1-5","end":"0 20 * * 1-5","minUnits":5,"maxUnits":10}]`,
+		"tsuru.io/autoscale-limits":    `{"maxUnits":2,"minUnits":1}`,
+	})
+	scales, err := s.p.GetAutoScale(context.TODO(), a)
+	c.Assert(err, check.IsNil)
+	c.Assert(scales, check.HasLen, 1)
+	c.Assert(scales[0].Schedules, check.DeepEquals, schedules)
+
+	start, err := s.client.BatchV1beta1().CronJobs(ns).Get(context.TODO(), "myapp-web-sched-0-start", metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(start.Spec.Schedule, check.Equals, "0 8 * * 1-5")
+	c.Assert(start.Labels["tsuru.io/is-autoscale-schedule"], check.Equals, "true")
+	c.Assert(start.Labels["tsuru.io/app-name"], check.Equals, "myapp")
+	podTemplate := start.Spec.JobTemplate.Spec.Template
+	c.Assert(podTemplate.Labels, check.DeepEquals, map[string]string{"tsuru.io/is-autoscale-schedule": "true"})
+	c.Assert(podTemplate.Spec.ServiceAccountName, check.Equals, "tsuru-autoscale-scheduler")
+	c.Assert(podTemplate.Spec.Containers, check.HasLen, 1)
+	c.Assert(podTemplate.Spec.Containers[0].Image, check.Equals, "bitnami/kubectl:1.20")
+	c.Assert(podTemplate.Spec.Containers[0].Command, check.DeepEquals, []string{
+		"kubectl", "patch", "hpa", "myapp-web", "--type=merge", "-p", `{"spec":{"minReplicas":5,"maxReplicas":10}}`,
+	})
+	end, err := s.client.BatchV1beta1().CronJobs(ns).Get(context.TODO(), "myapp-web-sched-0-end", metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(end.Spec.Schedule, check.Equals, "0 20 * * 1-5")
+	c.Assert(end.Spec.JobTemplate.Spec.Template.Spec.Containers[0].Command, check.DeepEquals, []string{
+		"kubectl", "patch", "hpa", "myapp-web", "--type=merge", "-p", `{"spec":{"minReplicas":1,"maxReplicas":2}}`,
+	})
+	_, err = s.client.CoreV1().ServiceAccounts(ns).Get(context.TODO(), "tsuru-autoscale-scheduler", metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	role, err := s.client.RbacV1().Roles(ns).Get(context.TODO(), "tsuru-autoscale-scheduler", metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(role.Rules[0].Resources, check.DeepEquals, []string{"horizontalpodautoscalers"})
+	_, err = s.client.RbacV1().RoleBindings(ns).Get(context.TODO(), "tsuru-autoscale-scheduler", metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+
+	err = s.p.SetAutoScale(context.TODO(), a, provision.AutoScaleSpec{
+		MinUnits:   1,
+		MaxUnits:   3,
+		AverageCPU: "500m",
+	})
+	c.Assert(err, check.IsNil)
+	cronJobs, err := s.client.BatchV1beta1().CronJobs(ns).List(context.TODO(), metav1.ListOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(cronJobs.Items, check.HasLen, 0)
+	scales, err = s.p.GetAutoScale(context.TODO(), a)
+	c.Assert(err, check.IsNil)
+	c.Assert(scales, check.HasLen, 1)
+	c.Assert(scales[0].Schedules, check.IsNil)
+}
+
+func (s *S) TestEnsureHPADuringScheduleWindow(c *check.C) {
+	a, wait, rollback := s.mock.DefaultReactions(c)
+	defer rollback()
+	version := newSuccessfulVersion(c, a, map[string]interface{}{
+		"processes": map[string]interface{}{
+			"web": "python myapp.py",
+		},
+	})
+	err := s.p.AddUnits(context.TODO(), a, 1, "web", version, nil)
+	c.Assert(err, check.IsNil)
+	wait()
+	spec := provision.AutoScaleSpec{
+		MinUnits:   1,
+		MaxUnits:   2,
+		AverageCPU: "500m",
+		Schedules: []provision.AutoScaleSchedule{
+			{Start: "0 8 * * 1-5", End: "0 20 * * 1-5", MinUnits: 5, MaxUnits: 10},
+		},
+	}
+	err = s.p.SetAutoScale(context.TODO(), a, spec)
+	c.Assert(err, check.IsNil)
+	ns, err := s.client.AppNamespace(context.TODO(), a)
+	c.Assert(err, check.IsNil)
+	setLastSchedule := func(name string, t time.Time) {
+		cronJob, err := s.client.BatchV1beta1().CronJobs(ns).Get(context.TODO(), name, metav1.GetOptions{})
+		c.Assert(err, check.IsNil)
+		cronJob.Status.LastScheduleTime = &metav1.Time{Time: t}
+		_, err = s.client.BatchV1beta1().CronJobs(ns).UpdateStatus(context.TODO(), cronJob, metav1.UpdateOptions{})
+		c.Assert(err, check.IsNil)
+	}
+	now := time.Now().Truncate(time.Second)
+	setLastSchedule("myapp-web-sched-0-end", now.Add(-12*time.Hour))
+	setLastSchedule("myapp-web-sched-0-start", now.Add(-time.Hour))
+	err = ensureHPA(context.TODO(), s.clusterClient, a, "web")
+	c.Assert(err, check.IsNil)
+	hpa, err := s.client.AutoscalingV2beta2().HorizontalPodAutoscalers(ns).Get(context.TODO(), "myapp-web", metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(*hpa.Spec.MinReplicas, check.Equals, int32(5))
+	c.Assert(hpa.Spec.MaxReplicas, check.Equals, int32(10))
+	scales, err := s.p.GetAutoScale(context.TODO(), a)
+	c.Assert(err, check.IsNil)
+	c.Assert(scales, check.HasLen, 1)
+	c.Assert(scales[0].MinUnits, check.Equals, uint(1))
+	c.Assert(scales[0].MaxUnits, check.Equals, uint(2))
+	end, err := s.client.BatchV1beta1().CronJobs(ns).Get(context.TODO(), "myapp-web-sched-0-end", metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(end.Spec.JobTemplate.Spec.Template.Spec.Containers[0].Command, check.DeepEquals, []string{
+		"kubectl", "patch", "hpa", "myapp-web", "--type=merge", "-p", `{"spec":{"minReplicas":1,"maxReplicas":2}}`,
+	})
+
+	setLastSchedule("myapp-web-sched-0-end", now)
+	err = ensureHPA(context.TODO(), s.clusterClient, a, "web")
+	c.Assert(err, check.IsNil)
+	hpa, err = s.client.AutoscalingV2beta2().HorizontalPodAutoscalers(ns).Get(context.TODO(), "myapp-web", metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(*hpa.Spec.MinReplicas, check.Equals, int32(1))
+	c.Assert(hpa.Spec.MaxReplicas, check.Equals, int32(2))
+}
+
+func (s *S) TestProvisionerRemoveAutoScaleWithSchedules(c *check.C) {
+	a, wait, rollback := s.mock.DefaultReactions(c)
+	defer rollback()
+	version := newSuccessfulVersion(c, a, map[string]interface{}{
+		"processes": map[string]interface{}{
+			"web": "python myapp.py",
+		},
+	})
+	err := s.p.AddUnits(context.TODO(), a, 1, "web", version, nil)
+	c.Assert(err, check.IsNil)
+	wait()
+	err = s.p.SetAutoScale(context.TODO(), a, provision.AutoScaleSpec{
+		MinUnits:   1,
+		MaxUnits:   2,
+		AverageCPU: "500m",
+		Schedules: []provision.AutoScaleSchedule{
+			{Start: "0 8 * * *", End: "0 20 * * *", MinUnits: 5, MaxUnits: 10},
+			{Start: "0 0 1 * *", End: "0 0 2 * *", MinUnits: 3, MaxUnits: 4},
+		},
+	})
+	c.Assert(err, check.IsNil)
+	ns, err := s.client.AppNamespace(context.TODO(), a)
+	c.Assert(err, check.IsNil)
+	cronJobs, err := s.client.BatchV1beta1().CronJobs(ns).List(context.TODO(), metav1.ListOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(cronJobs.Items, check.HasLen, 4)
+	err = s.p.RemoveAutoScale(context.TODO(), a, "web")
+	c.Assert(err, check.IsNil)
+	cronJobs, err = s.client.BatchV1beta1().CronJobs(ns).List(context.TODO(), metav1.ListOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(cronJobs.Items, check.HasLen, 0)
+}
+
+func (s *S) TestProvisionerSetAutoScaleWithPrometheus(c *check.C) {
+	a, wait, rollback := s.mock.DefaultReactions(c)
+	defer rollback()
+	version := newSuccessfulVersion(c, a, map[string]interface{}{
+		"processes": map[string]interface{}{
+			"web": "python myapp.py",
+		},
+	})
+	err := s.p.AddUnits(context.TODO(), a, 1, "web", version, nil)
+	c.Assert(err, check.IsNil)
+	wait()
+	prometheus := []provision.AutoScalePrometheus{
+		{Name: "requests_per_second", Query: `sum(rate(http_requests_total{app="myapp"}[1m]))`, Threshold: 10.5},
+	}
+	err = s.p.SetAutoScale(context.TODO(), a, provision.AutoScaleSpec{
+		MinUnits:   1,
+		MaxUnits:   2,
+		AverageCPU: "500m",
+		Prometheus: prometheus,
+	})
+	c.Assert(err, check.IsNil)
+	ns, err := s.client.AppNamespace(context.TODO(), a)
+	c.Assert(err, check.IsNil)
+	hpa, err := s.client.AutoscalingV2beta2().HorizontalPodAutoscalers(ns).Get(context.TODO(), "myapp-web", metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	cpu := resource.MustParse("500m")
+	threshold := resource.MustParse("10500m")
+	expected := testHPAWithTarget(autoscalingv2.MetricTarget{
+		Type:         autoscalingv2.AverageValueMetricType,
+		AverageValue: &cpu,
+	})
+	expected.Annotations = map[string]string{
+		"tsuru.io/autoscale-prometheus":                               `[{"name":"requests_per_second","query":"sum(rate(http_requests_total{app=\"myapp\"}[1m]))","threshold":10.5}]`,
+		"metric-config.external.requests-per-second.prometheus/query": `sum(rate(http_requests_total{app="myapp"}[1m]))`,
+	}
+	expected.Spec.Metrics = append(expected.Spec.Metrics, autoscalingv2.MetricSpec{
+		Type: autoscalingv2.ExternalMetricSourceType,
+		External: &autoscalingv2.ExternalMetricSource{
+			Metric: autoscalingv2.MetricIdentifier{
+				Name: "requests-per-second",
+				Selector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{
+						"type":                 "prometheus",
+						"tsuru.io/is-tsuru":    "true",
+						"tsuru.io/app-name":    "myapp",
+						"tsuru.io/app-process": "web",
+					},
+				},
+			},
+			Target: autoscalingv2.MetricTarget{
+				Type:         autoscalingv2.AverageValueMetricType,
+				AverageValue: &threshold,
+			},
+		},
+	})
+	c.Assert(hpa, check.DeepEquals, expected, check.Commentf("diff: %v", pretty.Diff(hpa, expected)))
+	scales, err := s.p.GetAutoScale(context.TODO(), a)
+	c.Assert(err, check.IsNil)
+	c.Assert(scales, check.DeepEquals, []provision.AutoScaleSpec{
+		{MinUnits: 1, MaxUnits: 2, AverageCPU: "500m", Version: 1, Process: "web", Prometheus: prometheus},
+	})
+}
+
+func (s *S) TestProvisionerSetAutoScaleOnlyPrometheus(c *check.C) {
+	a, wait, rollback := s.mock.DefaultReactions(c)
+	defer rollback()
+	version := newSuccessfulVersion(c, a, map[string]interface{}{
+		"processes": map[string]interface{}{
+			"web": "python myapp.py",
+		},
+	})
+	err := s.p.AddUnits(context.TODO(), a, 1, "web", version, nil)
+	c.Assert(err, check.IsNil)
+	wait()
+	prometheus := []provision.AutoScalePrometheus{
+		{Name: "queue_depth", Query: `max(queue_messages{queue="jobs"})`, Threshold: 100},
+	}
+	err = s.p.SetAutoScale(context.TODO(), a, provision.AutoScaleSpec{
+		MinUnits:   1,
+		MaxUnits:   5,
+		Prometheus: prometheus,
+	})
+	c.Assert(err, check.IsNil)
+	ns, err := s.client.AppNamespace(context.TODO(), a)
+	c.Assert(err, check.IsNil)
+	hpa, err := s.client.AutoscalingV2beta2().HorizontalPodAutoscalers(ns).Get(context.TODO(), "myapp-web", metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(hpa.Spec.Metrics, check.HasLen, 1)
+	c.Assert(hpa.Spec.Metrics[0].Type, check.Equals, autoscalingv2.ExternalMetricSourceType)
+	c.Assert(hpa.Spec.Metrics[0].External.Metric.Name, check.Equals, "queue-depth")
+	c.Assert(hpa.Spec.Metrics[0].External.Metric.Selector.MatchLabels["type"], check.Equals, "prometheus")
+	c.Assert(hpa.Annotations["metric-config.external.queue-depth.prometheus/query"], check.Equals, `max(queue_messages{queue="jobs"})`)
+	scales, err := s.p.GetAutoScale(context.TODO(), a)
+	c.Assert(err, check.IsNil)
+	c.Assert(scales, check.DeepEquals, []provision.AutoScaleSpec{
+		{MinUnits: 1, MaxUnits: 5, Version: 1, Process: "web", Prometheus: prometheus},
+	})
+	err = ensureHPA(context.TODO(), s.clusterClient, a, "web")
+	c.Assert(err, check.IsNil)
+}
+
 func (s *S) TestProvisionerSetAutoScaleMultipleVersions(c *check.C) {
 	a, wait, rollback := s.mock.DefaultReactions(c)
 	defer rollback()
@@ -27,6 +27,7 @@ import (
 	appTypes "github.com/tsuru/tsuru/types/app"
 	imgTypes "github.com/tsuru/tsuru/types/app/image"
 	provTypes "github.com/tsuru/tsuru/types/provision"
+	apiv1 "k8s.io/api/core/v1"
 	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -73,10 +74,21 @@ const (
 	buildServiceAccountKey        = "build-service-account"
 	disablePlatformBuildKey       = "disable-platform-build"
 	disablePDBKey                 = "disable-pdb"
+	pdbMinAvailableKey            = "pdb-min-available"
+	gpuResourceNameKey            = "gpu-resource-name"
+	gpuTypeLabelKey               = "gpu-type-label"
+	teamNamespaceQuotaKey         = "team-namespace-quota"
+	enableNetworkPolicyKey        = "enable-network-policy"
+	netPolicyRouterLabelsKey      = "network-policy-router-namespace-labels"
+	networkPolicyEgressCIDRsKey   = "network-policy-egress-cidrs"
 	defaultLogsFromAPIServer      = false
+	defaultDebugContainerImage    = "busybox"
 	versionedServices             = "enable-versioned-services"
 	dockerConfigJSONKey           = "docker-config-json"
 	dnsConfigNdotsKey             = "dns-config-ndots"
+	podTemplateKey                = "pod-template"
+	schedulerNameKey              = "scheduler-name"
+	debugContainerImageKey        = "debug-container-image"
 
 	dialTimeout  = 30 * time.Second
 	tcpKeepAlive = 30 * time.Second
@@ -111,7 +123,17 @@ var (
 		versionedServices:             "Allow the creation of multiple services for each pair of {process, version} from the app. The default behavior creates versioned services only in a multi versioned deploy scenario.",
 		dockerConfigJSONKey:           "Custom Docker config (~/.docker/config.json) to be mounted on deploy-agent container",
 		disablePDBKey:                 "Disable PodDisruptionBudget for entire pool.",
+		pdbMinAvailableKey:            "Percentage of the units of each process kept available during voluntary disruptions, like node drains, rounded up but always allowing one unit to be disrupted. Processes with a single unit have no PodDisruptionBudget. This config may be prefixed with `<pool-name>:`. Defaults to allowing 10% of the units to be disrupted.",
+		gpuResourceNameKey:            "Name of the resource of GPUs requested by units of apps with plans with GPUs. Defaults to nvidia.com/gpu. This config may be prefixed with `<pool-name>:`.",
+		gpuTypeLabelKey:               "Node label holding the type of the GPUs of the node, units of apps with plans with a GPU type are scheduled to nodes with this label set to the type. It's required by plans with a GPU type. This config may be prefixed with `<pool-name>:`.",
+		teamNamespaceQuotaKey:         "Hard limits of the ResourceQuota created in each team namespace when kubernetes:use-team-namespaces config is enabled, in the format <resource1>=<quantity1>,<resource2>=<quantity2>... e.g. pods=100,limits.memory=64Gi.",
+		enableNetworkPolicyKey:        "Create a NetworkPolicy for each app denying all the traffic of its units except between them, from the routers, to the DNS, to the IP addresses of its bound service instances and to the network-policy-egress-cidrs. This config may be prefixed with `<pool-name>:`.",
+		netPolicyRouterLabelsKey:      "Labels of the namespaces of the routers, allowed to reach apps with network policies, in the format <label1>=<value1>,<label2>=<value2>... It's required by enable-network-policy. This config may be prefixed with `<pool-name>:`.",
+		networkPolicyEgressCIDRsKey:   "Comma separated list of CIDRs apps with network policies are allowed to reach, like the addresses of services given by host names. This config may be prefixed with `<pool-name>:`.",
 		dnsConfigNdotsKey:             "Number of dots in the domain name to be used in the search list for DNS lookups. Default to uses kubernetes default value (5).",
+		schedulerNameKey:              "Name of the scheduler of the pods of apps and jobs, e.g. a bin-packing or volcano scheduler. Defaults to the kubernetes default scheduler. This config may be prefixed with `<pool-name>:`.",
+		debugContainerImageKey:        "Image of the ephemeral containers attached to units by the unit debug API, it must have a shell. Users allowed to deploy the app may request another image. Defaults to busybox. This config may be prefixed with `<pool-name>:`.",
+		podTemplateKey:                "Partial pod template, in YAML or JSON, merged into every app pod. Its labels, annotations and node selector are added to the ones set by tsuru, its runtime class name is used and the env of its containers is added to every app container. This config may be prefixed with `<pool-name>:`.",
 	}
 )
 
@@ -341,6 +363,31 @@ func (c *ClusterClient) PoolNamespace(pool string) string {
 	return prefix
 }
 
+// TeamNamespace returns the namespace of the apps owned by the team when
+// kubernetes:use-team-namespaces config is enabled.
+func (c *ClusterClient) TeamNamespace(team string) string {
+	prefix := "tsuru"
+	if c.CustomData != nil && c.CustomData[namespaceClusterKey] != "" {
+		prefix = c.CustomData[namespaceClusterKey]
+	}
+	return fmt.Sprintf("%s-team-%s", prefix, provision.ValidKubeName(team))
+}
+
+// namespaceForApp returns the namespace where the resources of the app are
+// placed: the namespace of its team owner, when team namespaces are enabled,
+// or the namespace of its pool.
+func (c *ClusterClient) namespaceForApp(a appTypes.App) string {
+	if useTeamNamespaces() {
+		return c.TeamNamespace(a.GetTeamOwner())
+	}
+	return c.PoolNamespace(a.GetPool())
+}
+
+func useTeamNamespaces() bool {
+	useTeamNamespaces, _ := config.GetBool("kubernetes:use-team-namespaces")
+	return useTeamNamespaces
+}
+
 // Namespace returns the namespace to be used by Custom Resources
 func (c *ClusterClient) Namespace() string {
 	if c.CustomData != nil && c.CustomData[namespaceClusterKey] != "" {
@@ -558,6 +605,36 @@ func (c *ClusterClient) dnsConfigNdots(pool string) intstr.IntOrString {
 	return intstr.Parse(DNSConfigNdots)
 }
 
+func (c *ClusterClient) podTemplate(pool string) (*apiv1.PodTemplateSpec, error) {
+	if c.CustomData == nil {
+		return nil, nil
+	}
+	raw := c.configForContext(pool, podTemplateKey)
+	if raw == "" {
+		return nil, nil
+	}
+	var template apiv1.PodTemplateSpec
+	err := yaml.Unmarshal([]byte(raw), &template)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid %s config", podTemplateKey)
+	}
+	return &template, nil
+}
+
+func (c *ClusterClient) schedulerName(pool string) string {
+	if c.CustomData == nil {
+		return ""
+	}
+	return c.configForContext(pool, schedulerNameKey)
+}
+
+func (c *ClusterClient) debugContainerImage(pool string) string {
+	if image := c.configForContext(pool, debugContainerImageKey); image != "" {
+		return image
+	}
+	return defaultDebugContainerImage
+}
+
 func (c *ClusterClient) SinglePool() (bool, error) {
 	if c.CustomData == nil {
 		return false, nil
@@ -659,6 +736,42 @@ func (c *ClusterClient) disablePDB(pool string) bool {
 	return d
 }
 
+// pdbMinAvailable returns the percentage of units kept available by the
+// PodDisruptionBudgets of the pool, or 0 when it's not set.
+func (c *ClusterClient) pdbMinAvailable(pool string) (int, error) {
+	value := c.configForContext(pool, pdbMinAvailableKey)
+	if value == "" {
+		return 0, nil
+	}
+	percentage, err := strconv.Atoi(strings.TrimSuffix(value, "%"))
+	if err != nil || percentage < 0 || percentage > 100 {
+		return 0, errors.Errorf("invalid %s config %q: must be a percentage between 0%% and 100%%", pdbMinAvailableKey, value)
+	}
+	return percentage, nil
+}
+
+// teamNamespaceQuota returns the hard limits of the resource quota of team
+// namespaces, or nil when it's not set.
+func (c *ClusterClient) teamNamespaceQuota() (apiv1.ResourceList, error) {
+	value := c.CustomData[teamNamespaceQuotaKey]
+	if value == "" {
+		return nil, nil
+	}
+	hard := apiv1.ResourceList{}
+	for _, entry := range strings.Split(value, ",") {
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 {
+			return nil, errors.Errorf("invalid %s config %q: entries must be in the format <resource>=<quantity>", teamNamespaceQuotaKey, value)
+		}
+		quantity, err := resource.ParseQuantity(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid %s config %q", teamNamespaceQuotaKey, value)
+		}
+		hard[apiv1.ResourceName(strings.TrimSpace(parts[0]))] = quantity
+	}
+	return hard, nil
+}
+
 func (c *ClusterClient) dockerConfigJSON() string {
 	return c.CustomData[dockerConfigJSONKey]
 }
@@ -709,6 +822,26 @@ func clusterForPool(ctx context.Context, pool string) (*ClusterClient, error) {
 	return NewClusterClient(clust)
 }
 
+// SharesPoolResources returns whether the app would use the same cluster and
+// namespace in the other pool, its kubernetes objects would be shared.
+func (p *kubernetesProvisioner) SharesPoolResources(ctx context.Context, app provision.App, otherPool string) (bool, error) {
+	client, err := clusterForPool(ctx, app.GetPool())
+	if err != nil {
+		return false, err
+	}
+	otherClient, err := clusterForPool(ctx, otherPool)
+	if err != nil {
+		return false, err
+	}
+	if client.Name != otherClient.Name {
+		return false, nil
+	}
+	if useTeamNamespaces() {
+		return true, nil
+	}
+	return client.PoolNamespace(app.GetPool()) == otherClient.PoolNamespace(otherPool), nil
+}
+
 func allClusters(ctx context.Context) ([]*ClusterClient, error) {
 	clusters, err := servicemanager.Cluster.FindByProvisioner(ctx, provisionerName)
 	if err != nil {
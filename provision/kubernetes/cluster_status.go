@@ -0,0 +1,97 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	provTypes "github.com/tsuru/tsuru/types/provision"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const metricsGroupVersion = "metrics.k8s.io/v1beta1"
+
+func (p *kubernetesProvisioner) ClusterStatus(ctx context.Context, c *provTypes.Cluster) provTypes.ClusterStatus {
+	clusterClient, err := NewClusterClient(c)
+	if err == nil {
+		err = clusterClient.SetTimeout(clusterHealthCheckTimeout)
+	}
+	if err != nil {
+		return provTypes.ClusterStatus{Error: err.Error()}
+	}
+	return clusterStatus(ctx, clusterClient)
+}
+
+// clusterStatus checks the version of the cluster API, the readiness of its
+// nodes and whether the ingress and metrics components are available.
+func clusterStatus(ctx context.Context, client *ClusterClient) provTypes.ClusterStatus {
+	var status provTypes.ClusterStatus
+	version, err := client.Discovery().ServerVersion()
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	status.Reachable = true
+	status.Version = version.GitVersion
+	nodes, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		status.Error = fmt.Sprintf("unable to list nodes: %v", err)
+	} else {
+		status.Nodes.Total = len(nodes.Items)
+		for _, node := range nodes.Items {
+			if nodeReady(&node) {
+				status.Nodes.Ready++
+			}
+		}
+	}
+	status.Components = []provTypes.ClusterComponentStatus{
+		ingressStatus(ctx, client),
+		metricsStatus(client),
+	}
+	return status
+}
+
+func nodeReady(node *apiv1.Node) bool {
+	for _, cond := range node.Status.Conditions {
+		if cond.Type == apiv1.NodeReady {
+			return cond.Status == apiv1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func ingressStatus(ctx context.Context, client *ClusterClient) provTypes.ClusterComponentStatus {
+	status := provTypes.ClusterComponentStatus{Name: "ingress"}
+	classes, err := client.NetworkingV1().IngressClasses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		status.Message = err.Error()
+		return status
+	}
+	if len(classes.Items) == 0 {
+		status.Message = "no ingress classes found"
+		return status
+	}
+	names := make([]string, len(classes.Items))
+	for i, class := range classes.Items {
+		names[i] = class.Name
+	}
+	status.OK = true
+	status.Message = fmt.Sprintf("ingress classes: %s", strings.Join(names, ", "))
+	return status
+}
+
+func metricsStatus(client *ClusterClient) provTypes.ClusterComponentStatus {
+	status := provTypes.ClusterComponentStatus{Name: "metrics"}
+	_, err := client.Discovery().ServerResourcesForGroupVersion(metricsGroupVersion)
+	if err != nil {
+		status.Message = err.Error()
+		return status
+	}
+	status.OK = true
+	return status
+}
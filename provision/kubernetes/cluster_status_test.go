@@ -0,0 +1,58 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kubernetes
+
+import (
+	"context"
+
+	provTypes "github.com/tsuru/tsuru/types/provision"
+	check "gopkg.in/check.v1"
+	apiv1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+)
+
+func (s *S) TestClusterStatus(c *check.C) {
+	ctx := context.TODO()
+	s.client.Clientset.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &version.Info{GitVersion: "v1.25.6"}
+	for _, node := range []apiv1.Node{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "n1"},
+			Status:     apiv1.NodeStatus{Conditions: []apiv1.NodeCondition{{Type: apiv1.NodeReady, Status: apiv1.ConditionTrue}}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "n2"},
+			Status:     apiv1.NodeStatus{Conditions: []apiv1.NodeCondition{{Type: apiv1.NodeReady, Status: apiv1.ConditionFalse}}},
+		},
+		{ObjectMeta: metav1.ObjectMeta{Name: "n3"}},
+	} {
+		_, err := s.client.CoreV1().Nodes().Create(ctx, &node, metav1.CreateOptions{})
+		c.Assert(err, check.IsNil)
+	}
+	status := clusterStatus(ctx, s.clusterClient)
+	c.Assert(status, check.DeepEquals, provTypes.ClusterStatus{
+		Reachable: true,
+		Version:   "v1.25.6",
+		Nodes:     provTypes.ClusterNodesStatus{Total: 3, Ready: 1},
+		Components: []provTypes.ClusterComponentStatus{
+			{Name: "ingress", Message: "no ingress classes found"},
+			{Name: "metrics", Message: `GroupVersion "metrics.k8s.io/v1beta1" not found`},
+		},
+	})
+	_, err := s.client.NetworkingV1().IngressClasses().Create(ctx, &networkingv1.IngressClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "nginx"},
+	}, metav1.CreateOptions{})
+	c.Assert(err, check.IsNil)
+	s.client.Clientset.Resources = append(s.client.Clientset.Resources, &metav1.APIResourceList{
+		GroupVersion: metricsGroupVersion,
+	})
+	status = clusterStatus(ctx, s.clusterClient)
+	c.Assert(status.Components, check.DeepEquals, []provTypes.ClusterComponentStatus{
+		{Name: "ingress", OK: true, Message: "ingress classes: nginx"},
+		{Name: "metrics", OK: true},
+	})
+}
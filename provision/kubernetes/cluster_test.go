@@ -307,6 +307,23 @@ func (s *S) TestClusterNamespacePerPoolWithInvalidCharacters(c *check.C) {
 	c.Assert(client.PoolNamespace("my_pool has *INVALID* chars"), check.Equals, "tsuru-my-pool-has--invalid--chars")
 }
 
+func (s *S) TestClusterNamespacePerTeam(c *check.C) {
+	a := provisiontest.NewFakeApp("myapp", "python", 0)
+	a.TeamOwner = "my_team"
+	c1 := provTypes.Cluster{Addresses: []string{"addr1"}, CustomData: map[string]string{"namespace": "x"}}
+	client, err := NewClusterClient(&c1)
+	c.Assert(err, check.IsNil)
+	c.Assert(client.TeamNamespace("my_team"), check.Equals, "x-team-my-team")
+	c.Assert(client.namespaceForApp(a), check.Equals, "x")
+	config.Set("kubernetes:use-team-namespaces", true)
+	defer config.Unset("kubernetes:use-team-namespaces")
+	c.Assert(client.namespaceForApp(a), check.Equals, "x-team-my-team")
+	c1 = provTypes.Cluster{Addresses: []string{"addr1"}}
+	client, err = NewClusterClient(&c1)
+	c.Assert(err, check.IsNil)
+	c.Assert(client.namespaceForApp(a), check.Equals, "tsuru-team-my-team")
+}
+
 func (s *S) TestClusterOvercommitFactor(c *check.C) {
 	c1 := provTypes.Cluster{Addresses: []string{"addr1"}, CustomData: map[string]string{
 		"overcommit-factor":         "2",
@@ -633,3 +650,18 @@ func (s *S) TestClusterDisablePDB(c *check.C) {
 	c.Assert(err, check.IsNil)
 	c.Assert(c4.disablePDB("mypool"), check.Equals, false)
 }
+
+func (s *S) TestSharesPoolResources(c *check.C) {
+	a := provisiontest.NewFakeAppWithPool("myapp", "python", "pool1", 0)
+	shared, err := s.p.SharesPoolResources(context.TODO(), a, "pool2")
+	c.Assert(err, check.IsNil)
+	c.Assert(shared, check.Equals, true)
+	config.Set("kubernetes:use-pool-namespaces", true)
+	defer config.Unset("kubernetes:use-pool-namespaces")
+	shared, err = s.p.SharesPoolResources(context.TODO(), a, "pool2")
+	c.Assert(err, check.IsNil)
+	c.Assert(shared, check.Equals, false)
+	shared, err = s.p.SharesPoolResources(context.TODO(), a, "pool1")
+	c.Assert(err, check.IsNil)
+	c.Assert(shared, check.Equals, true)
+}
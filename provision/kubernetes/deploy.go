@@ -41,6 +41,7 @@ import (
 	routerTypes "github.com/tsuru/tsuru/types/router"
 	appsv1 "k8s.io/api/apps/v1"
 	apiv1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -64,6 +65,9 @@ const (
 	defaultUdpPortName      = "udp-default"
 	backendConfigCRDName    = "backendconfigs.cloud.google.com"
 	backendConfigKey        = "cloud.google.com/backend-config"
+	teamResourceQuotaName   = "tsuru-team-quota"
+
+	defaultTerminationGracePeriod = 30
 )
 
 type InspectData struct {
@@ -357,6 +361,48 @@ func applyAppMetadata(pod *apiv1.Pod, app provision.App) {
 	}
 }
 
+// runPostDeployHooks runs the post-deploy hooks of each process in the
+// version, once per process, in an isolated unit using the deployed image.
+func runPostDeployHooks(ctx context.Context, client *ClusterClient, a provision.App, version appTypes.AppVersion, w io.Writer) error {
+	yamlData, err := version.TsuruYamlData()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	processes, err := version.Processes()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	names := make([]string, 0, len(processes))
+	for name := range processes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, process := range names {
+		hooks := yamlData.Hooks.PostDeploy(process)
+		if len(hooks) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, " ---> Running post-deploy hooks for process %q\n", process)
+		err = runIsolatedCmdPod(ctx, client, execOpts{
+			client: client,
+			app:    a,
+			image:  version.VersionInfo().DeployImage,
+			cmds: []string{
+				"/bin/sh",
+				"-lc",
+				"[ -d /home/application/current ] && cd /home/application/current; " + strings.Join(hooks, " && "),
+			},
+			eventsOutput: w,
+			stdout:       w,
+			stderr:       w,
+		})
+		if err != nil {
+			return errors.Wrapf(err, "error running post-deploy hooks for process %q", process)
+		}
+	}
+	return nil
+}
+
 type registryAuthConfig struct {
 	username  string
 	password  string
@@ -426,6 +472,7 @@ func logPodEvents(ctx context.Context, client *ClusterClient, initialResourceVer
 type hcResult struct {
 	liveness  *apiv1.Probe
 	readiness *apiv1.Probe
+	startup   *apiv1.Probe
 }
 
 func ensureHealthCheckDefaults(hc *provTypes.TsuruYamlHealthcheck) error {
@@ -454,41 +501,86 @@ func ensureHealthCheckDefaults(hc *provTypes.TsuruYamlHealthcheck) error {
 
 func probesFromHC(hc *provTypes.TsuruYamlHealthcheck, client *ClusterClient, port int) (hcResult, error) {
 	var result hcResult
-	if hc == nil || (hc.Path == "" && len(hc.Command) == 0) {
+	if hc == nil {
 		return result, nil
 	}
-	if err := ensureHealthCheckDefaults(hc); err != nil {
-		return result, err
+	if hc.Path != "" || len(hc.Command) > 0 {
+		if err := ensureHealthCheckDefaults(hc); err != nil {
+			return result, err
+		}
+		probe := newProbe(hc.Path, hc.Scheme, hc.Headers, hc.Command, port)
+		probe.FailureThreshold = int32(hc.AllowedFailures)
+		probe.PeriodSeconds = int32(hc.IntervalSeconds)
+		probe.TimeoutSeconds = int32(hc.TimeoutSeconds)
+		result.readiness = probe
+		if hc.ForceRestart {
+			result.liveness = probe
+		}
 	}
-	headers := []apiv1.HTTPHeader{}
-	for header, value := range hc.Headers {
-		headers = append(headers, apiv1.HTTPHeader{Name: header, Value: value})
+	var err error
+	if hc.Startup != nil {
+		result.startup, err = probeFromYaml("startup", hc.Startup, port)
+		if err != nil {
+			return result, err
+		}
 	}
-	sort.Slice(headers, func(i, j int) bool { return headers[i].Name < headers[j].Name })
-	hc.Scheme = strings.ToUpper(hc.Scheme)
-	probe := &apiv1.Probe{
-		FailureThreshold: int32(hc.AllowedFailures),
-		PeriodSeconds:    int32(hc.IntervalSeconds),
-		TimeoutSeconds:   int32(hc.TimeoutSeconds),
-		Handler:          apiv1.Handler{},
-	}
-	if hc.Path != "" {
-		probe.Handler.HTTPGet = &apiv1.HTTPGetAction{
-			Path:        hc.Path,
-			Port:        intstr.FromInt(port),
-			Scheme:      apiv1.URIScheme(hc.Scheme),
-			HTTPHeaders: headers,
+	if hc.Liveness != nil {
+		result.liveness, err = probeFromYaml("liveness", hc.Liveness, port)
+		if err != nil {
+			return result, err
 		}
-	} else {
+	}
+	return result, nil
+}
+
+// probeFromYaml returns the kubernetes probe for a startup or liveness probe,
+// using the same defaults as the healthcheck.
+func probeFromYaml(name string, p *provTypes.TsuruYamlProbe, port int) (*apiv1.Probe, error) {
+	if (p.Path == "") == (len(p.Command) == 0) {
+		return nil, errors.Errorf("healthcheck: %s probe must have either path or command", name)
+	}
+	if p.Scheme == "" {
+		p.Scheme = provision.DefaultHealthcheckScheme
+	}
+	if p.IntervalSeconds == 0 {
+		p.IntervalSeconds = 10
+	}
+	if p.TimeoutSeconds == 0 {
+		p.TimeoutSeconds = 60
+	}
+	if p.AllowedFailures == 0 {
+		p.AllowedFailures = 3
+	}
+	probe := newProbe(p.Path, p.Scheme, p.Headers, p.Command, port)
+	probe.FailureThreshold = int32(p.AllowedFailures)
+	probe.PeriodSeconds = int32(p.IntervalSeconds)
+	probe.TimeoutSeconds = int32(p.TimeoutSeconds)
+	probe.InitialDelaySeconds = int32(p.InitialDelaySeconds)
+	return probe, nil
+}
+
+func newProbe(path, scheme string, hcHeaders map[string]string, command []string, port int) *apiv1.Probe {
+	probe := &apiv1.Probe{
+		Handler: apiv1.Handler{},
+	}
+	if path == "" {
 		probe.Handler.Exec = &apiv1.ExecAction{
-			Command: hc.Command,
+			Command: command,
 		}
+		return probe
 	}
-	result.readiness = probe
-	if hc.ForceRestart {
-		result.liveness = probe
+	headers := []apiv1.HTTPHeader{}
+	for header, value := range hcHeaders {
+		headers = append(headers, apiv1.HTTPHeader{Name: header, Value: value})
 	}
-	return result, nil
+	sort.Slice(headers, func(i, j int) bool { return headers[i].Name < headers[j].Name })
+	probe.Handler.HTTPGet = &apiv1.HTTPGetAction{
+		Path:        path,
+		Port:        intstr.FromInt(port),
+		Scheme:      apiv1.URIScheme(strings.ToUpper(scheme)),
+		HTTPHeaders: headers,
+	}
+	return probe
 }
 
 func ensureNamespaceForApp(ctx context.Context, client *ClusterClient, app provision.App) error {
@@ -496,6 +588,9 @@ func ensureNamespaceForApp(ctx context.Context, client *ClusterClient, app provi
 	if err != nil {
 		return err
 	}
+	if app != nil && useTeamNamespaces() && ns == client.TeamNamespace(app.GetTeamOwner()) {
+		return ensureTeamNamespace(ctx, client, app.GetTeamOwner())
+	}
 	return ensureNamespace(ctx, client, ns)
 }
 
@@ -503,11 +598,56 @@ func ensurePoolNamespace(ctx context.Context, client *ClusterClient, pool string
 	return ensureNamespace(ctx, client, client.PoolNamespace(pool))
 }
 
+// ensureTeamNamespace creates the namespace of the apps of the team, labeled
+// with the team name, and keeps its resource quota in sync with the
+// team-namespace-quota config of the cluster.
+func ensureTeamNamespace(ctx context.Context, client *ClusterClient, team string) error {
+	namespace := client.TeamNamespace(team)
+	teamLabels := map[string]string{tsuruLabelPrefix + "team": provision.ValidKubeName(team)}
+	err := ensureNamespaceWithLabels(ctx, client, namespace, teamLabels)
+	if err != nil {
+		return err
+	}
+	hard, err := client.teamNamespaceQuota()
+	if err != nil || hard == nil {
+		return err
+	}
+	quota := &apiv1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      teamResourceQuotaName,
+			Namespace: namespace,
+			Labels:    teamLabels,
+		},
+		Spec: apiv1.ResourceQuotaSpec{Hard: hard},
+	}
+	existing, err := client.CoreV1().ResourceQuotas(namespace).Get(ctx, quota.Name, metav1.GetOptions{})
+	if k8sErrors.IsNotFound(err) {
+		_, err = client.CoreV1().ResourceQuotas(namespace).Create(ctx, quota, metav1.CreateOptions{})
+		return errors.WithStack(err)
+	}
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if apiequality.Semantic.DeepEqual(existing.Spec.Hard, hard) {
+		return nil
+	}
+	existing.Spec.Hard = hard
+	_, err = client.CoreV1().ResourceQuotas(namespace).Update(ctx, existing, metav1.UpdateOptions{})
+	return errors.WithStack(err)
+}
+
 func ensureNamespace(ctx context.Context, client *ClusterClient, namespace string) error {
+	return ensureNamespaceWithLabels(ctx, client, namespace, nil)
+}
+
+func ensureNamespaceWithLabels(ctx context.Context, client *ClusterClient, namespace string, extraLabels map[string]string) error {
 	nsLabels, err := client.namespaceLabels(namespace)
 	if err != nil {
 		return err
 	}
+	for k, v := range extraLabels {
+		nsLabels[k] = v
+	}
 	ns := apiv1.Namespace{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:   namespace,
@@ -589,42 +729,181 @@ func getClusterNodeSelectorFlag(client *ClusterClient) (bool, error) {
 	return shouldDisable, nil
 }
 
-func defineSelectorAndAffinity(ctx context.Context, a provision.App, client *ClusterClient) (map[string]string, *apiv1.Affinity, error) {
+// defineSelectorAndAffinity returns the node selector, affinity and
+// tolerations of the pods of the app, merging its scheduling settings with
+// the ones of its pool.
+func defineSelectorAndAffinity(ctx context.Context, a provision.App, client *ClusterClient) (map[string]string, *apiv1.Affinity, []apiv1.Toleration, error) {
+	nodeSelector, affinity, tolerations, err := poolSelectorAndAffinity(ctx, a.GetPool(), client)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	scheduling := a.GetScheduling()
+	if scheduling == nil {
+		return nodeSelector, affinity, tolerations, nil
+	}
+	return nodeSelector, mergeAffinity(affinity, scheduling.Affinity), mergeTolerations(tolerations, scheduling.Tolerations), nil
+}
+
+// poolSelectorAndAffinity returns the node selector and affinity placing pods
+// on the nodes of the pool, and the tolerations of its pods.
+func poolSelectorAndAffinity(ctx context.Context, poolName string, client *ClusterClient) (map[string]string, *apiv1.Affinity, []apiv1.Toleration, error) {
 	singlePool, err := client.SinglePool()
 	if err != nil {
-		return nil, nil, errors.WithMessage(err, "misconfigured cluster single pool value")
+		return nil, nil, nil, errors.WithMessage(err, "misconfigured cluster single pool value")
 	}
 	if singlePool {
-		return nil, nil, nil
+		return nil, nil, nil, nil
 	}
 
-	pool, err := pool.GetPoolByName(ctx, a.GetPool())
+	pool, err := pool.GetPoolByName(ctx, poolName)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
+	}
+	tolerations, err := pool.GetTolerations()
+	if err != nil {
+		return nil, nil, nil, err
 	}
 	affinity, err := pool.GetAffinity()
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 	if affinity != nil && affinity.NodeAffinity != nil {
-		return nil, affinity, nil
+		return nil, affinity, tolerations, nil
 	}
 
 	shouldDisable, err := getClusterNodeSelectorFlag(client)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 	if shouldDisable {
-		return nil, affinity, nil
+		return nil, affinity, tolerations, nil
 	}
 
 	return provision.NodeLabels(provision.NodeLabelsOpts{
-		Pool:   a.GetPool(),
+		Pool:   poolName,
 		Prefix: tsuruLabelPrefix,
-	}).ToNodeByPoolSelector(), affinity, nil
+	}).ToNodeByPoolSelector(), affinity, tolerations, nil
+}
+
+// mergeAffinity adds the affinity of an app to the one of its pool. Pod
+// affinity and anti-affinity of the app replace the ones of the pool, while
+// its node affinity is required in addition to the pool one, so apps can't
+// be placed out of the nodes of their pool.
+func mergeAffinity(poolAffinity, appAffinity *apiv1.Affinity) *apiv1.Affinity {
+	if appAffinity == nil {
+		return poolAffinity
+	}
+	result := &apiv1.Affinity{}
+	if poolAffinity != nil {
+		result = poolAffinity.DeepCopy()
+	}
+	if appAffinity.PodAffinity != nil {
+		result.PodAffinity = appAffinity.PodAffinity.DeepCopy()
+	}
+	if appAffinity.PodAntiAffinity != nil {
+		result.PodAntiAffinity = appAffinity.PodAntiAffinity.DeepCopy()
+	}
+	appNodeAffinity := appAffinity.NodeAffinity
+	if appNodeAffinity == nil {
+		return result
+	}
+	if result.NodeAffinity == nil {
+		result.NodeAffinity = &apiv1.NodeAffinity{}
+	}
+	for _, term := range appNodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution {
+		result.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(result.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution, *term.DeepCopy())
+	}
+	appRequired := appNodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	if appRequired == nil || len(appRequired.NodeSelectorTerms) == 0 {
+		return result
+	}
+	poolRequired := result.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	if poolRequired == nil || len(poolRequired.NodeSelectorTerms) == 0 {
+		result.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = appRequired.DeepCopy()
+		return result
+	}
+	// terms are ORed while the requirements of each term are ANDed, so
+	// requiring both means combining each term of the pool with each term
+	// of the app.
+	var terms []apiv1.NodeSelectorTerm
+	for _, poolTerm := range poolRequired.NodeSelectorTerms {
+		for _, appTerm := range appRequired.NodeSelectorTerms {
+			term := poolTerm.DeepCopy()
+			term.MatchExpressions = append(term.MatchExpressions, appTerm.DeepCopy().MatchExpressions...)
+			term.MatchFields = append(term.MatchFields, appTerm.DeepCopy().MatchFields...)
+			terms = append(terms, *term)
+		}
+	}
+	result.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = &apiv1.NodeSelector{NodeSelectorTerms: terms}
+	return result
+}
+
+func mergeTolerations(poolTolerations, appTolerations []apiv1.Toleration) []apiv1.Toleration {
+	result := append([]apiv1.Toleration{}, poolTolerations...)
+	for _, t := range appTolerations {
+		found := false
+		for _, existing := range result {
+			if existing.MatchToleration(&t) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			result = append(result, t)
+		}
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
+
+// topologySpreadConstraints returns the constraints spreading the units of a
+// process of the app across zones or nodes. Constraints of the app replace the
+// ones of its pool with the same topology key, and constraints without a label
+// selector select the units of the process in every version.
+func topologySpreadConstraints(ctx context.Context, a provision.App, selector map[string]string) ([]apiv1.TopologySpreadConstraint, error) {
+	p, err := pool.GetPoolByName(ctx, a.GetPool())
+	if err != nil {
+		return nil, err
+	}
+	constraints, err := p.GetTopologySpreadConstraints()
+	if err != nil {
+		return nil, err
+	}
+	if scheduling := a.GetScheduling(); scheduling != nil {
+		for _, appConstraint := range scheduling.TopologySpreadConstraints {
+			replaced := false
+			for i := range constraints {
+				if constraints[i].TopologyKey == appConstraint.TopologyKey {
+					constraints[i] = appConstraint
+					replaced = true
+					break
+				}
+			}
+			if !replaced {
+				constraints = append(constraints, appConstraint)
+			}
+		}
+	}
+	if len(constraints) == 0 {
+		return nil, nil
+	}
+	result := make([]apiv1.TopologySpreadConstraint, len(constraints))
+	for i, constraint := range constraints {
+		result[i] = *constraint.DeepCopy()
+		if result[i].LabelSelector == nil {
+			result[i].LabelSelector = &metav1.LabelSelector{MatchLabels: selector}
+		}
+	}
+	return result, nil
 }
 
 func createAppDeployment(ctx context.Context, client *ClusterClient, depName string, oldDeployment *appsv1.Deployment, a provision.App, process string, version appTypes.AppVersion, replicas int, labels *provision.LabelSet, selector map[string]string, w io.Writer) (*appsv1.Deployment, *provision.LabelSet, error) {
+	err := ensureAppEnvsSecret(ctx, client, a)
+	if err != nil {
+		return nil, nil, err
+	}
 	realReplicas := int32(replicas)
 	extra := []string{}
 
@@ -663,25 +942,52 @@ func createAppDeployment(ctx context.Context, client *ClusterClient, depName str
 	}
 
 	sleepSec := client.preStopSleepSeconds(a.GetPool())
-	terminationGracePeriod := int64(30 + sleepSec)
+	gracePeriod := a.GetProcessTerminationGracePeriod(process)
+	if gracePeriod == 0 {
+		gracePeriod = yamlData.Kubernetes.GetTerminationGracePeriod(process)
+	}
+	if gracePeriod < 0 {
+		return nil, nil, errors.Errorf("invalid termination grace period for process %q: %d", process, gracePeriod)
+	}
+	if gracePeriod == 0 {
+		gracePeriod = defaultTerminationGracePeriod
+	}
+	shutdownTimeout := yamlData.Shutdown.Timeout(process)
+	if shutdownTimeout < 0 {
+		return nil, nil, errors.Errorf("invalid shutdown timeout for process %q: %d", process, shutdownTimeout)
+	}
+	if shutdownTimeout > gracePeriod {
+		gracePeriod = shutdownTimeout
+	}
+	// the pre stop sleep runs before the app receives SIGTERM, so it must
+	// not consume the time given to the app.
+	terminationGracePeriod := int64(gracePeriod + sleepSec)
 
 	var lifecycle apiv1.Lifecycle
+	var preStopCmds []string
+	preStopHooks := append(append([]string{}, yamlData.Hooks.PreStop(process)...), yamlData.Shutdown.Commands(process)...)
+	if len(preStopHooks) > 0 {
+		preStopCmds = append(preStopCmds, strings.Join(preStopHooks, " && "))
+	}
 	if sleepSec > 0 {
+		// Allow some time for endpoints controller and kube-proxy to
+		// remove the endpoints for the pods before sending SIGTERM to
+		// app. This should reduce the number of failed connections due
+		// to pods stopping while their endpoints are still active.
+		preStopCmds = append(preStopCmds, fmt.Sprintf("sleep %d || true", sleepSec))
+	}
+	if len(preStopCmds) > 0 {
 		lifecycle.PreStop = &apiv1.Handler{
 			Exec: &apiv1.ExecAction{
-				// Allow some time for endpoints controller and kube-proxy to
-				// remove the endpoints for the pods before sending SIGTERM to
-				// app. This should reduce the number of failed connections due
-				// to pods stopping while their endpoints are still active.
-				Command: []string{"sh", "-c", fmt.Sprintf("sleep %d || true", sleepSec)},
+				Command: []string{"sh", "-c", strings.Join(preStopCmds, "; ")},
 			},
 		}
 	}
 
-	if yamlData.Hooks != nil && len(yamlData.Hooks.Restart.After) > 0 {
+	if restartAfter := yamlData.Hooks.RestartAfter(process); len(restartAfter) > 0 {
 		hookCmds := []string{
 			"sh", "-c",
-			strings.Join(yamlData.Hooks.Restart.After, " && "),
+			strings.Join(restartAfter, " && "),
 		}
 		lifecycle.PostStart = &apiv1.Handler{
 			Exec: &apiv1.ExecAction{
@@ -692,7 +998,7 @@ func createAppDeployment(ctx context.Context, client *ClusterClient, depName str
 	maxSurge := client.maxSurge(a.GetPool())
 	maxUnavailable := client.maxUnavailable(a.GetPool())
 	dnsConfig := dnsConfigNdots(client, a)
-	nodeSelector, affinity, err := defineSelectorAndAffinity(ctx, a, client)
+	nodeSelector, affinity, tolerations, err := defineSelectorAndAffinity(ctx, a, client)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -714,7 +1020,7 @@ func createAppDeployment(ctx context.Context, client *ClusterClient, depName str
 	if err != nil {
 		return nil, nil, errors.WithMessage(err, "misconfigured cluster memory overcommit factor")
 	}
-	resourceRequirements, err := appResourceRequirements(a, client, requirementsFactors{
+	resourceRequirements, err := appResourceRequirements(a, process, client, requirementsFactors{
 		overCommit:       overCommit,
 		cpuOverCommit:    cpuOverCommit,
 		cpuBurst:         cpuBurst,
@@ -817,10 +1123,12 @@ func createAppDeployment(ctx context.Context, client *ClusterClient, depName str
 					RestartPolicy:  apiv1.RestartPolicyAlways,
 					NodeSelector:   nodeSelector,
 					Affinity:       affinity,
+					Tolerations:    tolerations,
 					Volumes:        volumes,
 					Subdomain:      headlessServiceName(a, process),
 					ReadinessGates: readinessGates,
 					DNSConfig:      dnsConfig,
+					SchedulerName:  client.schedulerName(a.GetPool()),
 					Containers: []apiv1.Container{
 						{
 							Name:           depName,
@@ -829,6 +1137,7 @@ func createAppDeployment(ctx context.Context, client *ClusterClient, depName str
 							Env:            appEnvs(a, process, version, false),
 							ReadinessProbe: hcData.readiness,
 							LivenessProbe:  hcData.liveness,
+							StartupProbe:   hcData.startup,
 							Resources:      resourceRequirements,
 							VolumeMounts:   mounts,
 							Ports:          containerPorts,
@@ -839,6 +1148,23 @@ func createAppDeployment(ctx context.Context, client *ClusterClient, depName str
 			},
 		},
 	}
+	extras, err := extraContainers(yamlData, process, deployment.Spec.Template.Spec.Containers[0])
+	if err != nil {
+		return nil, nil, err
+	}
+	extras.apply(&deployment.Spec.Template.Spec)
+	err = applyGPU(client, a, process, &deployment.Spec.Template.Spec)
+	if err != nil {
+		return nil, nil, err
+	}
+	deployment.Spec.Template.Spec.TopologySpreadConstraints, err = topologySpreadConstraints(ctx, a, labels.ToAllVersionsSelector())
+	if err != nil {
+		return nil, nil, err
+	}
+	err = applyPodTemplate(client, a.GetPool(), &deployment.Spec.Template.ObjectMeta, &deployment.Spec.Template.Spec)
+	if err != nil {
+		return nil, nil, err
+	}
 	var newDep *appsv1.Deployment
 	if oldDeployment == nil {
 		newDep, err = client.AppsV1().Deployments(ns).Create(ctx, &deployment, metav1.CreateOptions{})
@@ -852,6 +1178,10 @@ func appEnvs(a provision.App, process string, version appTypes.AppVersion, isDep
 	appEnvs := EnvsForApp(a, process, version, isDeploy)
 	envs := make([]apiv1.EnvVar, len(appEnvs))
 	for i, envData := range appEnvs {
+		if secretEnv, ok := secretEnvVar(a, envData); ok {
+			envs[i] = secretEnv
+			continue
+		}
 		envs[i] = apiv1.EnvVar{
 			Name:  envData.Name,
 			Value: strings.ReplaceAll(envData.Value, "$", "$$"),
@@ -1149,6 +1479,7 @@ func monitorDeployment(ctx context.Context, client *ClusterClient, dep *appsv1.D
 	var healthcheckTimeout <-chan time.Time
 	t0 := time.Now()
 	largestReady := int32(0)
+	initLogger := newInitContainersLogger(client, w)
 	for {
 		var specReplicas int32
 		if dep.Spec.Replicas != nil {
@@ -1163,6 +1494,10 @@ func monitorDeployment(ctx context.Context, client *ClusterClient, dep *appsv1.D
 		if oldUpdatedReplicas != dep.Status.UpdatedReplicas {
 			fmt.Fprintf(w, " ---> %d of %d new units created\n", dep.Status.UpdatedReplicas, specReplicas)
 		}
+		err = initLogger.report(ctx, dep)
+		if err != nil {
+			return revision, err
+		}
 		if healthcheckTimeout == nil && dep.Status.UpdatedReplicas == specReplicas {
 			var allInit bool
 			allInit, err = allNewPodsRunning(ctx, client, a, processName, dep, version)
@@ -1255,6 +1590,10 @@ func (m *serviceManager) DeployService(ctx context.Context, opts servicecommon.D
 	if err != nil {
 		return err
 	}
+	err = ensureNetworkPolicy(ctx, m.client, opts.App)
+	if err != nil {
+		return errors.Wrap(err, "unable to ensure network policy")
+	}
 	ns, err := m.client.AppNamespace(ctx, opts.App)
 	if err != nil {
 		return err
@@ -1460,6 +1799,17 @@ func syncAnnotationMap(toAdd map[string]string, metadata map[string]string) {
 
 func syncServiceAnnotations(app provision.App, svcData *svcCreateData) {
 	metadata := app.GetMetadata()
+	for _, annotation := range metadata.Annotations {
+		// annotations with the resource metadata prefix configure tsuru
+		// itself and are not meant to be copied to services.
+		if strings.HasPrefix(annotation.Name, ResourceMetadataPrefix) {
+			continue
+		}
+		if svcData.annotations == nil {
+			svcData.annotations = map[string]string{}
+		}
+		svcData.annotations[annotation.Name] = annotation.Value
+	}
 	annotationsToAdd := make(map[string]string)
 	annotationsRaw, ok := metadata.Annotation(ResourceMetadataPrefix + "service")
 	if ok {
@@ -1638,6 +1988,12 @@ func (m *serviceManager) ensureServices(ctx context.Context, a provision.App, pr
 				ExternalTrafficPolicy: policy,
 			},
 		}
+		if a.IsInternal() {
+			// internal apps are not reachable from routers, only by the
+			// cluster address of their services.
+			svc.Spec.Type = apiv1.ServiceTypeClusterIP
+			svc.Spec.ExternalTrafficPolicy = ""
+		}
 		var isNew bool
 		svc, isNew, err = mergeServices(ctx, m.client, svc)
 		if err != nil {
@@ -1756,8 +2112,10 @@ func mergeServices(ctx context.Context, client *ClusterClient, svc *apiv1.Servic
 		}
 		return nil, false, errors.WithStack(err)
 	}
-	for i := 0; i < len(svc.Spec.Ports) && i < len(existing.Spec.Ports); i++ {
-		svc.Spec.Ports[i].NodePort = existing.Spec.Ports[i].NodePort
+	if svc.Spec.Type != apiv1.ServiceTypeClusterIP {
+		for i := 0; i < len(svc.Spec.Ports) && i < len(existing.Spec.Ports); i++ {
+			svc.Spec.Ports[i].NodePort = existing.Spec.Ports[i].NodePort
+		}
 	}
 	svc.ObjectMeta.ResourceVersion = existing.ObjectMeta.ResourceVersion
 	svc.Spec.ClusterIP = existing.Spec.ClusterIP
@@ -1990,7 +2348,7 @@ func newDeployAgentPod(ctx context.Context, params createPodParams, conf deployA
 	annotations := provision.LabelSet{Prefix: tsuruLabelPrefix}
 	annotations.SetBuildImage(conf.destinationImages[0])
 
-	nodeSelector, affinity, err := defineSelectorAndAffinity(ctx, params.app, params.client)
+	nodeSelector, affinity, tolerations, err := defineSelectorAndAffinity(ctx, params.app, params.client)
 	if err != nil {
 		return apiv1.Pod{}, err
 	}
@@ -2033,7 +2391,9 @@ func newDeployAgentPod(ctx context.Context, params createPodParams, conf deployA
 			ServiceAccountName: params.client.buildServiceAccount(params.app),
 			NodeSelector:       nodeSelector,
 			Affinity:           affinity,
+			Tolerations:        tolerations,
 			DNSConfig:          dnsConfig,
+			SchedulerName:      params.client.schedulerName(params.app.GetPool()),
 			Volumes: append(deployAgentEngineVolumes(pullSecrets), append([]apiv1.Volume{
 				{
 					Name: "intercontainer",
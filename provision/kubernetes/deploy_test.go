@@ -510,6 +510,48 @@ func (s *S) TestServiceManagerDeployServiceWithCustomAnnotationsFromDeployment(c
 	c.Assert(svc2.Annotations, check.DeepEquals, map[string]string{"a1": "v1", "a2": "v2"})
 }
 
+func (s *S) TestServiceManagerDeployServiceWithAppMetadata(c *check.C) {
+	waitDep := s.mock.DeploymentReactions(c)
+	defer waitDep()
+	m := serviceManager{client: s.clusterClient}
+	a := &app.App{Name: "myapp", TeamOwner: s.team.Name, Metadata: appTypes.Metadata{
+		Labels: []appTypes.MetadataItem{
+			{Name: "team", Value: "payments"},
+		},
+		Annotations: []appTypes.MetadataItem{
+			{Name: "cost-center", Value: "42"},
+			{Name: ResourceMetadataPrefix + "service", Value: `{"a1": "v1"}`},
+		},
+	}}
+	err := app.CreateApp(context.TODO(), a, s.user)
+	c.Assert(err, check.IsNil)
+	version := newCommittedVersion(c, a, map[string]interface{}{
+		"processes": map[string]interface{}{
+			"p1": "cm1",
+		},
+	})
+	err = servicecommon.RunServicePipeline(context.TODO(), &m, 0, provision.DeployArgs{
+		App:     a,
+		Version: version,
+	}, servicecommon.ProcessSpec{
+		"p1": servicecommon.ProcessState{Start: true},
+	})
+	c.Assert(err, check.IsNil)
+	waitDep()
+	ns, err := s.client.AppNamespace(context.TODO(), a)
+	c.Assert(err, check.IsNil)
+	dep, err := s.client.Clientset.AppsV1().Deployments(ns).Get(context.TODO(), "myapp-p1", metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(dep.Labels["team"], check.Equals, "payments")
+	c.Assert(dep.Annotations["cost-center"], check.Equals, "42")
+	c.Assert(dep.Spec.Template.Labels["team"], check.Equals, "payments")
+	c.Assert(dep.Spec.Template.Annotations["cost-center"], check.Equals, "42")
+	svc, err := s.client.CoreV1().Services(ns).Get(context.TODO(), "myapp-p1", metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(svc.Labels["team"], check.Equals, "payments")
+	c.Assert(svc.Annotations, check.DeepEquals, map[string]string{"cost-center": "42", "a1": "v1"})
+}
+
 func (s *S) TestServiceManagerDeployServiceWithNodeAffinity(c *check.C) {
 	waitDep := s.mock.DeploymentReactions(c)
 	defer waitDep()
@@ -1316,6 +1358,118 @@ func (s *S) TestServiceManagerDeployServiceWithHC(c *check.C) {
 	}
 }
 
+func (s *S) TestServiceManagerDeployServiceWithStartupAndLivenessProbes(c *check.C) {
+	waitDep := s.mock.DeploymentReactions(c)
+	defer waitDep()
+	m := serviceManager{client: s.clusterClient}
+	a := &app.App{Name: "myapp", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), a, s.user)
+	c.Assert(err, check.IsNil)
+	version := newCommittedVersion(c, a, map[string]interface{}{
+		"processes": map[string]interface{}{
+			"web": "cm1",
+			"p2":  "cmd2",
+		},
+		"healthcheck": provTypes.TsuruYamlHealthcheck{
+			Path:         "/hc",
+			ForceRestart: true,
+			Startup: &provTypes.TsuruYamlProbe{
+				Path:            "/started",
+				Headers:         map[string]string{"Host": "test.com"},
+				AllowedFailures: 30,
+				TimeoutSeconds:  5,
+			},
+			Liveness: &provTypes.TsuruYamlProbe{
+				Command:             []string{"check-alive"},
+				IntervalSeconds:     20,
+				InitialDelaySeconds: 15,
+			},
+		},
+	})
+	err = servicecommon.RunServicePipeline(context.TODO(), &m, 0, provision.DeployArgs{
+		App:     a,
+		Version: version,
+	}, servicecommon.ProcessSpec{
+		"web": servicecommon.ProcessState{Start: true},
+		"p2":  servicecommon.ProcessState{Start: true},
+	})
+	c.Assert(err, check.IsNil)
+	waitDep()
+	nsName, err := s.client.AppNamespace(context.TODO(), a)
+	c.Assert(err, check.IsNil)
+	dep, err := s.client.Clientset.AppsV1().Deployments(nsName).Get(context.TODO(), "myapp-web", metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	container := dep.Spec.Template.Spec.Containers[0]
+	c.Assert(container.ReadinessProbe, check.DeepEquals, &apiv1.Probe{
+		PeriodSeconds:    10,
+		FailureThreshold: 3,
+		TimeoutSeconds:   60,
+		Handler: apiv1.Handler{
+			HTTPGet: &apiv1.HTTPGetAction{
+				Path:        "/hc",
+				Port:        intstr.FromInt(8888),
+				Scheme:      apiv1.URISchemeHTTP,
+				HTTPHeaders: []apiv1.HTTPHeader{},
+			},
+		},
+	})
+	c.Assert(container.StartupProbe, check.DeepEquals, &apiv1.Probe{
+		PeriodSeconds:    10,
+		FailureThreshold: 30,
+		TimeoutSeconds:   5,
+		Handler: apiv1.Handler{
+			HTTPGet: &apiv1.HTTPGetAction{
+				Path:        "/started",
+				Port:        intstr.FromInt(8888),
+				Scheme:      apiv1.URISchemeHTTP,
+				HTTPHeaders: []apiv1.HTTPHeader{{Name: "Host", Value: "test.com"}},
+			},
+		},
+	})
+	c.Assert(container.LivenessProbe, check.DeepEquals, &apiv1.Probe{
+		PeriodSeconds:       20,
+		FailureThreshold:    3,
+		TimeoutSeconds:      60,
+		InitialDelaySeconds: 15,
+		Handler: apiv1.Handler{
+			Exec: &apiv1.ExecAction{
+				Command: []string{"check-alive"},
+			},
+		},
+	})
+	dep, err = s.client.Clientset.AppsV1().Deployments(nsName).Get(context.TODO(), "myapp-p2", metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(dep.Spec.Template.Spec.Containers[0].StartupProbe, check.IsNil)
+	c.Assert(dep.Spec.Template.Spec.Containers[0].LivenessProbe, check.IsNil)
+}
+
+func (s *S) TestServiceManagerDeployServiceWithInvalidStartupProbe(c *check.C) {
+	waitDep := s.mock.DeploymentReactions(c)
+	defer waitDep()
+	m := serviceManager{client: s.clusterClient}
+	a := &app.App{Name: "myapp", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), a, s.user)
+	c.Assert(err, check.IsNil)
+	version := newCommittedVersion(c, a, map[string]interface{}{
+		"processes": map[string]interface{}{
+			"web": "cm1",
+		},
+		"healthcheck": provTypes.TsuruYamlHealthcheck{
+			Startup: &provTypes.TsuruYamlProbe{
+				Path:    "/started",
+				Command: []string{"check-started"},
+			},
+		},
+	})
+	err = servicecommon.RunServicePipeline(context.TODO(), &m, 0, provision.DeployArgs{
+		App:     a,
+		Version: version,
+	}, servicecommon.ProcessSpec{
+		"web": servicecommon.ProcessState{Start: true},
+	})
+	c.Assert(err, check.ErrorMatches, "healthcheck: startup probe must have either path or command")
+}
+
 func (s *S) TestEnsureBackendConfigIfEnabled(c *check.C) {
 	waitDep := s.mock.DeploymentReactions(c)
 	defer waitDep()
@@ -1666,6 +1820,77 @@ func (s *S) TestServiceManagerDeployServiceWithRestartHooks(c *check.C) {
 	c.Assert(cmd[2], check.Matches, `.*before cmd1 && before cmd2 && exec proc2$`)
 }
 
+func (s *S) TestServiceManagerDeployServiceWithProcessHooks(c *check.C) {
+	waitDep := s.mock.DeploymentReactions(c)
+	defer waitDep()
+	m := serviceManager{client: s.clusterClient}
+	a := &app.App{Name: "myapp", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), a, s.user)
+	c.Assert(err, check.IsNil)
+	version := newCommittedVersion(c, a, map[string]interface{}{
+		"processes": map[string]interface{}{
+			"web": "proc1",
+			"p2":  "proc2",
+		},
+		"hooks": provTypes.TsuruYamlHooks{
+			Restart: provTypes.TsuruYamlRestartHooks{
+				Before: []string{"before cmd1"},
+				After:  []string{"after cmd1"},
+			},
+			Processes: map[string]provTypes.TsuruYamlProcessHooks{
+				"p2": {
+					Restart: provTypes.TsuruYamlRestartHooks{
+						Before: []string{"before p2"},
+						After:  []string{"after p2"},
+					},
+					PreStop: []string{"stop p2", "stop p2 again"},
+				},
+			},
+		},
+	})
+	err = servicecommon.RunServicePipeline(context.TODO(), &m, 0, provision.DeployArgs{
+		App:     a,
+		Version: version,
+	}, servicecommon.ProcessSpec{
+		"web": servicecommon.ProcessState{Start: true},
+		"p2":  servicecommon.ProcessState{Start: true},
+	})
+	c.Assert(err, check.IsNil)
+	waitDep()
+	ns, err := s.client.AppNamespace(context.TODO(), a)
+	c.Assert(err, check.IsNil)
+	dep, err := s.client.Clientset.AppsV1().Deployments(ns).Get(context.TODO(), "myapp-web", metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(dep.Spec.Template.Spec.Containers[0].Lifecycle, check.DeepEquals, &apiv1.Lifecycle{
+		PostStart: &apiv1.Handler{
+			Exec: &apiv1.ExecAction{
+				Command: []string{"sh", "-c", "after cmd1"},
+			},
+		},
+		PreStop: &apiv1.Handler{
+			Exec: &apiv1.ExecAction{
+				Command: []string{"sh", "-c", "sleep 10 || true"},
+			},
+		},
+	})
+	c.Assert(dep.Spec.Template.Spec.Containers[0].Command[2], check.Matches, `.*before cmd1 && exec proc1$`)
+	dep, err = s.client.Clientset.AppsV1().Deployments(ns).Get(context.TODO(), "myapp-p2", metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(dep.Spec.Template.Spec.Containers[0].Lifecycle, check.DeepEquals, &apiv1.Lifecycle{
+		PostStart: &apiv1.Handler{
+			Exec: &apiv1.ExecAction{
+				Command: []string{"sh", "-c", "after cmd1 && after p2"},
+			},
+		},
+		PreStop: &apiv1.Handler{
+			Exec: &apiv1.ExecAction{
+				Command: []string{"sh", "-c", "stop p2 && stop p2 again; sleep 10 || true"},
+			},
+		},
+	})
+	c.Assert(dep.Spec.Template.Spec.Containers[0].Command[2], check.Matches, `.*before cmd1 && before p2 && exec proc2$`)
+}
+
 func (s *S) TestServiceManagerDeployServiceWithCustomSleep(c *check.C) {
 	tests := []struct {
 		value         string
@@ -1744,6 +1969,172 @@ func (s *S) TestServiceManagerDeployServiceWithCustomSleep(c *check.C) {
 	}
 }
 
+func (s *S) TestServiceManagerDeployServiceWithTerminationGracePeriod(c *check.C) {
+	waitDep := s.mock.DeploymentReactions(c)
+	defer waitDep()
+	m := serviceManager{client: s.clusterClient}
+	a := &app.App{Name: "myapp", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), a, s.user)
+	c.Assert(err, check.IsNil)
+	version := newCommittedVersion(c, a, map[string]interface{}{
+		"processes": map[string]interface{}{
+			"web":    "proc1",
+			"worker": "proc2",
+			"p3":     "proc3",
+		},
+		"kubernetes": provTypes.TsuruYamlKubernetesConfig{
+			TerminationGracePeriodSeconds: map[string]int{
+				"web":    120,
+				"worker": 600,
+			},
+		},
+	})
+	a.TerminationGracePeriods = map[string]int{"worker": 900}
+	s.clusterClient.CustomData[preStopSleepKey] = "5"
+	err = servicecommon.RunServicePipeline(context.TODO(), &m, 0, provision.DeployArgs{
+		App:     a,
+		Version: version,
+	}, servicecommon.ProcessSpec{
+		"web":    servicecommon.ProcessState{Start: true},
+		"worker": servicecommon.ProcessState{Start: true},
+		"p3":     servicecommon.ProcessState{Start: true},
+	})
+	c.Assert(err, check.IsNil)
+	waitDep()
+	ns, err := s.client.AppNamespace(context.TODO(), a)
+	c.Assert(err, check.IsNil)
+	expected := map[string]int64{
+		"web":    125,
+		"worker": 905,
+		"p3":     35,
+	}
+	for process, grace := range expected {
+		dep, err := s.client.Clientset.AppsV1().Deployments(ns).Get(context.TODO(), "myapp-"+process, metav1.GetOptions{})
+		c.Assert(err, check.IsNil)
+		c.Assert(*dep.Spec.Template.Spec.TerminationGracePeriodSeconds, check.Equals, grace, check.Commentf("process %q", process))
+	}
+}
+
+func (s *S) TestServiceManagerDeployServiceWithShutdown(c *check.C) {
+	waitDep := s.mock.DeploymentReactions(c)
+	defer waitDep()
+	m := serviceManager{client: s.clusterClient}
+	a := &app.App{Name: "myapp", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), a, s.user)
+	c.Assert(err, check.IsNil)
+	version := newCommittedVersion(c, a, map[string]interface{}{
+		"processes": map[string]interface{}{
+			"web":    "proc1",
+			"worker": "proc2",
+		},
+		"hooks": provTypes.TsuruYamlHooks{
+			Processes: map[string]provTypes.TsuruYamlProcessHooks{
+				"worker": {PreStop: []string{"notify"}},
+			},
+		},
+		"shutdown": provTypes.TsuruYamlShutdown{
+			"worker": {
+				Commands:       []string{"stop-consuming", "wait-jobs"},
+				TimeoutSeconds: 300,
+			},
+		},
+	})
+	err = servicecommon.RunServicePipeline(context.TODO(), &m, 0, provision.DeployArgs{
+		App:     a,
+		Version: version,
+	}, servicecommon.ProcessSpec{
+		"web":    servicecommon.ProcessState{Start: true},
+		"worker": servicecommon.ProcessState{Start: true},
+	})
+	c.Assert(err, check.IsNil)
+	waitDep()
+	ns, err := s.client.AppNamespace(context.TODO(), a)
+	c.Assert(err, check.IsNil)
+	dep, err := s.client.Clientset.AppsV1().Deployments(ns).Get(context.TODO(), "myapp-worker", metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(*dep.Spec.Template.Spec.TerminationGracePeriodSeconds, check.Equals, int64(310))
+	c.Assert(dep.Spec.Template.Spec.Containers[0].Lifecycle.PreStop, check.DeepEquals, &apiv1.Handler{
+		Exec: &apiv1.ExecAction{
+			Command: []string{"sh", "-c", "notify && stop-consuming && wait-jobs; sleep 10 || true"},
+		},
+	})
+	dep, err = s.client.Clientset.AppsV1().Deployments(ns).Get(context.TODO(), "myapp-web", metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(*dep.Spec.Template.Spec.TerminationGracePeriodSeconds, check.Equals, int64(40))
+}
+
+func (s *S) TestServiceManagerDeployServiceWithSidecarsAndInitContainers(c *check.C) {
+	waitDep := s.mock.DeploymentReactions(c)
+	defer waitDep()
+	m := serviceManager{client: s.clusterClient}
+	a := &app.App{Name: "myapp", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), a, s.user)
+	c.Assert(err, check.IsNil)
+	version := newCommittedVersion(c, a, map[string]interface{}{
+		"processes": map[string]interface{}{
+			"web":    "proc1",
+			"worker": "proc2",
+		},
+		"sidecars": []provTypes.TsuruYamlSidecar{
+			{
+				Name:      "envoy",
+				Image:     "envoyproxy/envoy:v1.20",
+				Args:      []string{"-c", "/etc/envoy/envoy.yaml"},
+				Resources: provTypes.TsuruYamlSidecarResources{CPU: "100m", Memory: "64Mi"},
+				Processes: []string{"web"},
+			},
+			{
+				Name:    "log-shipper",
+				Image:   "fluent/fluent-bit",
+				Env:     map[string]string{"LOG_DIR": "/var/log/app"},
+				Volumes: []provTypes.TsuruYamlSidecarVolume{{Name: "logs", Path: "/var/log/app"}},
+			},
+		},
+		"init_containers": []provTypes.TsuruYamlInitContainer{
+			{Name: "migrate", Command: []string{"./migrate"}, Processes: []string{"web"}},
+		},
+	})
+	err = servicecommon.RunServicePipeline(context.TODO(), &m, 0, provision.DeployArgs{
+		App:     a,
+		Version: version,
+	}, servicecommon.ProcessSpec{
+		"web":    servicecommon.ProcessState{Start: true},
+		"worker": servicecommon.ProcessState{Start: true},
+	})
+	c.Assert(err, check.IsNil)
+	waitDep()
+	ns, err := s.client.AppNamespace(context.TODO(), a)
+	c.Assert(err, check.IsNil)
+	dep, err := s.client.Clientset.AppsV1().Deployments(ns).Get(context.TODO(), "myapp-web", metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	containers := dep.Spec.Template.Spec.Containers
+	c.Assert(containers, check.HasLen, 3)
+	c.Assert(containers[0].Name, check.Equals, "myapp-web")
+	initContainers := dep.Spec.Template.Spec.InitContainers
+	c.Assert(initContainers, check.HasLen, 1)
+	c.Assert(initContainers[0].Name, check.Equals, "migrate")
+	c.Assert(initContainers[0].Image, check.Equals, containers[0].Image)
+	c.Assert(initContainers[0].Command, check.DeepEquals, []string{"./migrate"})
+	c.Assert(initContainers[0].Env, check.DeepEquals, containers[0].Env)
+	c.Assert(containers[0].VolumeMounts, check.DeepEquals, []apiv1.VolumeMount{{Name: "sidecar-logs", MountPath: "/var/log/app"}})
+	c.Assert(containers[1].Name, check.Equals, "envoy")
+	c.Assert(containers[1].Image, check.Equals, "envoyproxy/envoy:v1.20")
+	c.Assert(containers[1].Args, check.DeepEquals, []string{"-c", "/etc/envoy/envoy.yaml"})
+	c.Assert(containers[1].Resources.Limits.Cpu().String(), check.Equals, "100m")
+	c.Assert(containers[1].Resources.Requests.Memory().String(), check.Equals, "64Mi")
+	c.Assert(containers[2].Name, check.Equals, "log-shipper")
+	c.Assert(containers[2].Env, check.DeepEquals, []apiv1.EnvVar{{Name: "LOG_DIR", Value: "/var/log/app"}})
+	c.Assert(containers[2].VolumeMounts, check.DeepEquals, []apiv1.VolumeMount{{Name: "sidecar-logs", MountPath: "/var/log/app"}})
+	c.Assert(dep.Spec.Template.Spec.Volumes, check.DeepEquals, []apiv1.Volume{
+		{Name: "sidecar-logs", VolumeSource: apiv1.VolumeSource{EmptyDir: &apiv1.EmptyDirVolumeSource{}}},
+	})
+	dep, err = s.client.Clientset.AppsV1().Deployments(ns).Get(context.TODO(), "myapp-worker", metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(dep.Spec.Template.Spec.Containers, check.HasLen, 2)
+	c.Assert(dep.Spec.Template.Spec.Containers[1].Name, check.Equals, "log-shipper")
+	c.Assert(dep.Spec.Template.Spec.InitContainers, check.HasLen, 0)
+}
+
 func (s *S) TestServiceManagerDeployServiceWithKubernetesPorts(c *check.C) {
 	waitDep := s.mock.DeploymentReactions(c)
 	defer waitDep()
@@ -4852,13 +5243,185 @@ func (s *S) TestDefineSelectorAndAffinity(c *check.C) {
 		err := pool.PoolUpdate(context.TODO(), "test-default", pool.UpdatePoolOptions{Labels: t.poolLabels})
 		c.Assert(err, check.IsNil)
 		s.clusterClient.CustomData = t.customData
-		selector, affinity, err := defineSelectorAndAffinity(context.TODO(), t.app, s.clusterClient)
+		selector, affinity, _, err := defineSelectorAndAffinity(context.TODO(), t.app, s.clusterClient)
 		t.assertion(selector, affinity, err, c)
 		err = pool.PoolUpdate(context.TODO(), "test-default", pool.UpdatePoolOptions{Labels: map[string]string{}})
 		c.Assert(err, check.IsNil)
 	}
 }
 
+func (s *S) TestDefineSelectorAndAffinityWithTolerations(c *check.C) {
+	err := pool.PoolUpdate(context.TODO(), "test-default", pool.UpdatePoolOptions{Labels: map[string]string{
+		"tolerations": `[{"key": "dedicated", "operator": "Equal", "value": "test-default", "effect": "NoSchedule"}]`,
+	}})
+	c.Assert(err, check.IsNil)
+	a := &app.App{Name: "myapp", TeamOwner: s.team.Name, Pool: "test-default", Scheduling: &appTypes.Scheduling{
+		Tolerations: []apiv1.Toleration{
+			{Key: "dedicated", Operator: apiv1.TolerationOpEqual, Value: "test-default", Effect: apiv1.TaintEffectNoSchedule},
+			{Key: "gpu", Operator: apiv1.TolerationOpExists},
+		},
+		Affinity: &apiv1.Affinity{
+			PodAntiAffinity: &apiv1.PodAntiAffinity{
+				PreferredDuringSchedulingIgnoredDuringExecution: []apiv1.WeightedPodAffinityTerm{
+					{Weight: 100, PodAffinityTerm: apiv1.PodAffinityTerm{TopologyKey: "kubernetes.io/hostname"}},
+				},
+			},
+		},
+	}}
+	selector, affinity, tolerations, err := defineSelectorAndAffinity(context.TODO(), a, s.clusterClient)
+	c.Assert(err, check.IsNil)
+	c.Assert(selector, check.DeepEquals, map[string]string{"tsuru.io/pool": "test-default"})
+	c.Assert(affinity, check.DeepEquals, a.Scheduling.Affinity)
+	c.Assert(tolerations, check.DeepEquals, []apiv1.Toleration{
+		{Key: "dedicated", Operator: apiv1.TolerationOpEqual, Value: "test-default", Effect: apiv1.TaintEffectNoSchedule},
+		{Key: "gpu", Operator: apiv1.TolerationOpExists},
+	})
+}
+
+func (s *S) TestMergeAffinity(c *check.C) {
+	poolAffinity := &apiv1.Affinity{
+		NodeAffinity: &apiv1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &apiv1.NodeSelector{
+				NodeSelectorTerms: []apiv1.NodeSelectorTerm{
+					{MatchExpressions: []apiv1.NodeSelectorRequirement{{Key: "pool", Operator: apiv1.NodeSelectorOpIn, Values: []string{"p1"}}}},
+					{MatchExpressions: []apiv1.NodeSelectorRequirement{{Key: "pool", Operator: apiv1.NodeSelectorOpIn, Values: []string{"p2"}}}},
+				},
+			},
+		},
+		PodAntiAffinity: &apiv1.PodAntiAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []apiv1.WeightedPodAffinityTerm{
+				{Weight: 1, PodAffinityTerm: apiv1.PodAffinityTerm{TopologyKey: "zone"}},
+			},
+		},
+	}
+	c.Assert(mergeAffinity(poolAffinity, nil), check.Equals, poolAffinity)
+	appAffinity := &apiv1.Affinity{
+		NodeAffinity: &apiv1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &apiv1.NodeSelector{
+				NodeSelectorTerms: []apiv1.NodeSelectorTerm{
+					{MatchExpressions: []apiv1.NodeSelectorRequirement{{Key: "group", Operator: apiv1.NodeSelectorOpIn, Values: []string{"gpu"}}}},
+				},
+			},
+			PreferredDuringSchedulingIgnoredDuringExecution: []apiv1.PreferredSchedulingTerm{
+				{Weight: 10, Preference: apiv1.NodeSelectorTerm{MatchExpressions: []apiv1.NodeSelectorRequirement{{Key: "spot", Operator: apiv1.NodeSelectorOpDoesNotExist}}}},
+			},
+		},
+		PodAntiAffinity: &apiv1.PodAntiAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []apiv1.WeightedPodAffinityTerm{
+				{Weight: 100, PodAffinityTerm: apiv1.PodAffinityTerm{TopologyKey: "kubernetes.io/hostname"}},
+			},
+		},
+	}
+	merged := mergeAffinity(poolAffinity, appAffinity)
+	c.Assert(merged, check.DeepEquals, &apiv1.Affinity{
+		NodeAffinity: &apiv1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &apiv1.NodeSelector{
+				NodeSelectorTerms: []apiv1.NodeSelectorTerm{
+					{MatchExpressions: []apiv1.NodeSelectorRequirement{
+						{Key: "pool", Operator: apiv1.NodeSelectorOpIn, Values: []string{"p1"}},
+						{Key: "group", Operator: apiv1.NodeSelectorOpIn, Values: []string{"gpu"}},
+					}},
+					{MatchExpressions: []apiv1.NodeSelectorRequirement{
+						{Key: "pool", Operator: apiv1.NodeSelectorOpIn, Values: []string{"p2"}},
+						{Key: "group", Operator: apiv1.NodeSelectorOpIn, Values: []string{"gpu"}},
+					}},
+				},
+			},
+			PreferredDuringSchedulingIgnoredDuringExecution: appAffinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution,
+		},
+		PodAntiAffinity: appAffinity.PodAntiAffinity,
+	})
+	c.Assert(poolAffinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[0].MatchExpressions, check.HasLen, 1)
+	merged = mergeAffinity(nil, appAffinity)
+	c.Assert(merged, check.DeepEquals, appAffinity)
+}
+
+func (s *S) TestTopologySpreadConstraints(c *check.C) {
+	err := pool.PoolUpdate(context.TODO(), "test-default", pool.UpdatePoolOptions{Labels: map[string]string{
+		"topology-spread-constraints": `[{"maxSkew": 1, "topologyKey": "topology.kubernetes.io/zone", "whenUnsatisfiable": "ScheduleAnyway"}, {"maxSkew": 1, "topologyKey": "kubernetes.io/hostname", "whenUnsatisfiable": "ScheduleAnyway"}]`,
+	}})
+	c.Assert(err, check.IsNil)
+	defer pool.PoolUpdate(context.TODO(), "test-default", pool.UpdatePoolOptions{Labels: map[string]string{}})
+	selector := map[string]string{"tsuru.io/app-name": "myapp", "tsuru.io/app-process": "web"}
+	a := &app.App{Name: "myapp", TeamOwner: s.team.Name, Pool: "test-default"}
+	constraints, err := topologySpreadConstraints(context.TODO(), a, selector)
+	c.Assert(err, check.IsNil)
+	c.Assert(constraints, check.DeepEquals, []apiv1.TopologySpreadConstraint{
+		{MaxSkew: 1, TopologyKey: "topology.kubernetes.io/zone", WhenUnsatisfiable: apiv1.ScheduleAnyway, LabelSelector: &metav1.LabelSelector{MatchLabels: selector}},
+		{MaxSkew: 1, TopologyKey: "kubernetes.io/hostname", WhenUnsatisfiable: apiv1.ScheduleAnyway, LabelSelector: &metav1.LabelSelector{MatchLabels: selector}},
+	})
+	appSelector := &metav1.LabelSelector{MatchLabels: map[string]string{"tsuru.io/app-name": "myapp"}}
+	a.Scheduling = &appTypes.Scheduling{
+		TopologySpreadConstraints: []apiv1.TopologySpreadConstraint{
+			{MaxSkew: 2, TopologyKey: "topology.kubernetes.io/zone", WhenUnsatisfiable: apiv1.DoNotSchedule},
+			{MaxSkew: 1, TopologyKey: "rack", WhenUnsatisfiable: apiv1.ScheduleAnyway, LabelSelector: appSelector},
+		},
+	}
+	constraints, err = topologySpreadConstraints(context.TODO(), a, selector)
+	c.Assert(err, check.IsNil)
+	c.Assert(constraints, check.DeepEquals, []apiv1.TopologySpreadConstraint{
+		{MaxSkew: 2, TopologyKey: "topology.kubernetes.io/zone", WhenUnsatisfiable: apiv1.DoNotSchedule, LabelSelector: &metav1.LabelSelector{MatchLabels: selector}},
+		{MaxSkew: 1, TopologyKey: "kubernetes.io/hostname", WhenUnsatisfiable: apiv1.ScheduleAnyway, LabelSelector: &metav1.LabelSelector{MatchLabels: selector}},
+		{MaxSkew: 1, TopologyKey: "rack", WhenUnsatisfiable: apiv1.ScheduleAnyway, LabelSelector: appSelector},
+	})
+	c.Assert(a.Scheduling.TopologySpreadConstraints[0].LabelSelector, check.IsNil)
+}
+
+func (s *S) TestServiceManagerDeployServiceWithTopologySpreadConstraints(c *check.C) {
+	waitDep := s.mock.DeploymentReactions(c)
+	defer waitDep()
+	m := serviceManager{client: s.clusterClient}
+	a := &app.App{Name: "myapp", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), a, s.user)
+	c.Assert(err, check.IsNil)
+	a.Scheduling = &appTypes.Scheduling{
+		TopologySpreadConstraints: []apiv1.TopologySpreadConstraint{
+			{MaxSkew: 1, TopologyKey: "topology.kubernetes.io/zone", WhenUnsatisfiable: apiv1.DoNotSchedule},
+		},
+	}
+	version := newCommittedVersion(c, a, map[string]interface{}{
+		"processes": map[string]interface{}{
+			"p1": "cm1",
+		},
+	})
+	err = servicecommon.RunServicePipeline(context.TODO(), &m, 0, provision.DeployArgs{
+		App:     a,
+		Version: version,
+	}, servicecommon.ProcessSpec{
+		"p1": servicecommon.ProcessState{Start: true},
+	})
+	c.Assert(err, check.IsNil)
+	waitDep()
+	ns, err := s.client.AppNamespace(context.TODO(), a)
+	c.Assert(err, check.IsNil)
+	dep, err := s.client.Clientset.AppsV1().Deployments(ns).Get(context.TODO(), "myapp-p1", metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(dep.Spec.Template.Spec.TopologySpreadConstraints, check.DeepEquals, []apiv1.TopologySpreadConstraint{
+		{
+			MaxSkew:           1,
+			TopologyKey:       "topology.kubernetes.io/zone",
+			WhenUnsatisfiable: apiv1.DoNotSchedule,
+			LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{
+				"tsuru.io/app-name":    "myapp",
+				"tsuru.io/app-process": "p1",
+				"tsuru.io/is-build":    "false",
+			}},
+		},
+	})
+}
+
+func (s *S) TestMergeTolerations(c *check.C) {
+	c.Assert(mergeTolerations(nil, nil), check.IsNil)
+	poolTolerations := []apiv1.Toleration{{Key: "dedicated", Operator: apiv1.TolerationOpEqual, Value: "p1", Effect: apiv1.TaintEffectNoSchedule}}
+	c.Assert(mergeTolerations(poolTolerations, []apiv1.Toleration{
+		{Key: "dedicated", Operator: apiv1.TolerationOpEqual, Value: "p1", Effect: apiv1.TaintEffectNoSchedule},
+		{Key: "gpu", Operator: apiv1.TolerationOpExists},
+	}), check.DeepEquals, []apiv1.Toleration{
+		{Key: "dedicated", Operator: apiv1.TolerationOpEqual, Value: "p1", Effect: apiv1.TaintEffectNoSchedule},
+		{Key: "gpu", Operator: apiv1.TolerationOpExists},
+	})
+}
+
 func (s *S) TestEnsureNamespace(c *check.C) {
 	tests := []struct {
 		name       string
@@ -4933,6 +5496,66 @@ func (s *S) TestEnsureNamespace(c *check.C) {
 	}
 }
 
+func (s *S) TestEnsureTeamNamespace(c *check.C) {
+	ctx := context.TODO()
+	s.clusterClient.CustomData[teamNamespaceQuotaKey] = "pods=10, limits.memory=4Gi"
+	err := ensureTeamNamespace(ctx, s.clusterClient, "my_team")
+	c.Assert(err, check.IsNil)
+	ns, err := s.client.CoreV1().Namespaces().Get(ctx, "tsuru-team-my-team", metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(ns.Labels, check.DeepEquals, map[string]string{
+		"name":          "tsuru-team-my-team",
+		"tsuru.io/team": "my-team",
+	})
+	quota, err := s.client.CoreV1().ResourceQuotas("tsuru-team-my-team").Get(ctx, "tsuru-team-quota", metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(quota.Labels, check.DeepEquals, map[string]string{"tsuru.io/team": "my-team"})
+	c.Assert(quota.Spec.Hard, check.DeepEquals, apiv1.ResourceList{
+		apiv1.ResourcePods:         resource.MustParse("10"),
+		apiv1.ResourceLimitsMemory: resource.MustParse("4Gi"),
+	})
+	s.clusterClient.CustomData[teamNamespaceQuotaKey] = "pods=20"
+	err = ensureTeamNamespace(ctx, s.clusterClient, "my_team")
+	c.Assert(err, check.IsNil)
+	quota, err = s.client.CoreV1().ResourceQuotas("tsuru-team-my-team").Get(ctx, "tsuru-team-quota", metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(quota.Spec.Hard, check.DeepEquals, apiv1.ResourceList{
+		apiv1.ResourcePods: resource.MustParse("20"),
+	})
+	s.clusterClient.CustomData[teamNamespaceQuotaKey] = "pods"
+	err = ensureTeamNamespace(ctx, s.clusterClient, "my_team")
+	c.Assert(err, check.ErrorMatches, `invalid team-namespace-quota config "pods": entries must be in the format <resource>=<quantity>`)
+}
+
+func (s *S) TestServiceManagerDeployServiceWithSchedulerName(c *check.C) {
+	waitDep := s.mock.DeploymentReactions(c)
+	defer waitDep()
+	s.clusterClient.CustomData[schedulerNameKey] = "default-scheduler"
+	s.clusterClient.CustomData["test-default:"+schedulerNameKey] = "bin-packing"
+	m := serviceManager{client: s.clusterClient}
+	a := &app.App{Name: "myapp", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), a, s.user)
+	c.Assert(err, check.IsNil)
+	version := newCommittedVersion(c, a, map[string]interface{}{
+		"processes": map[string]interface{}{
+			"p1": "cm1",
+		},
+	})
+	err = servicecommon.RunServicePipeline(context.TODO(), &m, 0, provision.DeployArgs{
+		App:     a,
+		Version: version,
+	}, servicecommon.ProcessSpec{
+		"p1": servicecommon.ProcessState{Start: true},
+	})
+	c.Assert(err, check.IsNil)
+	waitDep()
+	ns, err := s.client.AppNamespace(context.TODO(), a)
+	c.Assert(err, check.IsNil)
+	dep, err := s.client.Clientset.AppsV1().Deployments(ns).Get(context.TODO(), "myapp-p1", metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(dep.Spec.Template.Spec.SchedulerName, check.Equals, "bin-packing")
+}
+
 func (s *S) TestServiceManagerDeployServiceWithDisableHeadless(c *check.C) {
 	waitDep := s.mock.DeploymentReactions(c)
 	defer waitDep()
@@ -5382,6 +6005,37 @@ func (s *S) TestServiceManagerDeployServiceWithCustomLabelsAndAnnotations(c *che
 	c.Assert(dep.Spec.Template.ObjectMeta.Labels["tsuru.io/logs"], check.Equals, "BACKUP")
 }
 
+func (s *S) TestServiceManagerDeployServiceInternalApp(c *check.C) {
+	waitDep := s.mock.DeploymentReactions(c)
+	defer waitDep()
+	m := serviceManager{client: s.clusterClient}
+	a := &app.App{Name: "myapp", TeamOwner: s.team.Name, Internal: true}
+	err := app.CreateApp(context.TODO(), a, s.user)
+	c.Assert(err, check.IsNil)
+	version := newCommittedVersion(c, a, map[string]interface{}{
+		"processes": map[string]interface{}{
+			"p1": "cm1",
+		},
+	})
+	err = servicecommon.RunServicePipeline(context.TODO(), &m, 0, provision.DeployArgs{
+		App:     a,
+		Version: version,
+	}, servicecommon.ProcessSpec{
+		"p1": servicecommon.ProcessState{Start: true},
+	})
+	c.Assert(err, check.IsNil)
+	waitDep()
+	ns, err := s.client.AppNamespace(context.TODO(), a)
+	c.Assert(err, check.IsNil)
+	svc, err := s.client.CoreV1().Services(ns).Get(context.TODO(), "myapp-p1", metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(svc.Spec.Type, check.Equals, apiv1.ServiceTypeClusterIP)
+	c.Assert(svc.Spec.ExternalTrafficPolicy, check.Equals, apiv1.ServiceExternalTrafficPolicyType(""))
+	for _, port := range svc.Spec.Ports {
+		c.Assert(port.NodePort, check.Equals, int32(0))
+	}
+}
+
 func (s *S) TestServiceManagerDeployServiceWithVPA(c *check.C) {
 	waitDep := s.mock.DeploymentReactions(c)
 	defer waitDep()
@@ -0,0 +1,56 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kubernetes
+
+import (
+	"github.com/pkg/errors"
+	"github.com/tsuru/tsuru/provision"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const defaultGPUResourceName = "nvidia.com/gpu"
+
+func (c *ClusterClient) gpuResourceName(pool string) apiv1.ResourceName {
+	if name := c.configForContext(pool, gpuResourceNameKey); name != "" {
+		return apiv1.ResourceName(name)
+	}
+	return defaultGPUResourceName
+}
+
+// applyGPU adds the GPUs of the plan of the process to the resources of the
+// app container, which must be the first one of the pod, and schedules the
+// pod to nodes with GPUs, of the plan GPU type when it's set.
+func applyGPU(client *ClusterClient, a provision.App, process string, spec *apiv1.PodSpec) error {
+	gpu, gpuType := a.GetProcessGPU(process)
+	if gpu <= 0 || len(spec.Containers) == 0 {
+		return nil
+	}
+	resourceName := client.gpuResourceName(a.GetPool())
+	quantity := *resource.NewQuantity(int64(gpu), resource.DecimalSI)
+	resources := &spec.Containers[0].Resources
+	if resources.Limits == nil {
+		resources.Limits = apiv1.ResourceList{}
+	}
+	if resources.Requests == nil {
+		resources.Requests = apiv1.ResourceList{}
+	}
+	resources.Limits[resourceName] = quantity
+	resources.Requests[resourceName] = quantity
+	if gpuType != "" {
+		label := client.configForContext(a.GetPool(), gpuTypeLabelKey)
+		if label == "" {
+			return errors.Errorf("unable to schedule units with gpu type %q: cluster %q has no %s config", gpuType, client.Name, gpuTypeLabelKey)
+		}
+		if spec.NodeSelector == nil {
+			spec.NodeSelector = map[string]string{}
+		}
+		spec.NodeSelector[label] = gpuType
+	}
+	spec.Tolerations = mergeTolerations(spec.Tolerations, []apiv1.Toleration{
+		{Key: string(resourceName), Operator: apiv1.TolerationOpExists, Effect: apiv1.TaintEffectNoSchedule},
+	})
+	return nil
+}
@@ -0,0 +1,76 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kubernetes
+
+import (
+	"github.com/tsuru/tsuru/provision/provisiontest"
+	appTypes "github.com/tsuru/tsuru/types/app"
+	check "gopkg.in/check.v1"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func (s *S) TestApplyGPU(c *check.C) {
+	a := provisiontest.NewFakeApp("myapp", "python", 0)
+	a.ProcessPlans = map[string]appTypes.Plan{
+		"train": {Name: "t4", GPU: 2, GPUType: "nvidia-tesla-t4"},
+		"infer": {Name: "any-gpu", GPU: 1},
+	}
+	newSpec := func() *apiv1.PodSpec {
+		return &apiv1.PodSpec{
+			NodeSelector: map[string]string{"tsuru.io/pool": "gpu"},
+			Containers: []apiv1.Container{{
+				Name: "myapp-web",
+				Resources: apiv1.ResourceRequirements{
+					Limits: apiv1.ResourceList{apiv1.ResourceMemory: resource.MustParse("1Gi")},
+				},
+			}},
+		}
+	}
+	s.clusterClient.CustomData[gpuTypeLabelKey] = "cloud.google.com/gke-accelerator"
+	defer delete(s.clusterClient.CustomData, gpuTypeLabelKey)
+	spec := newSpec()
+	err := applyGPU(s.clusterClient, a, "train", spec)
+	c.Assert(err, check.IsNil)
+	c.Assert(spec.Containers[0].Resources, check.DeepEquals, apiv1.ResourceRequirements{
+		Limits: apiv1.ResourceList{
+			apiv1.ResourceMemory: resource.MustParse("1Gi"),
+			"nvidia.com/gpu":     *resource.NewQuantity(2, resource.DecimalSI),
+		},
+		Requests: apiv1.ResourceList{
+			"nvidia.com/gpu": *resource.NewQuantity(2, resource.DecimalSI),
+		},
+	})
+	c.Assert(spec.NodeSelector, check.DeepEquals, map[string]string{
+		"tsuru.io/pool":                    "gpu",
+		"cloud.google.com/gke-accelerator": "nvidia-tesla-t4",
+	})
+	c.Assert(spec.Tolerations, check.DeepEquals, []apiv1.Toleration{
+		{Key: "nvidia.com/gpu", Operator: apiv1.TolerationOpExists, Effect: apiv1.TaintEffectNoSchedule},
+	})
+	s.clusterClient.CustomData[gpuResourceNameKey] = "amd.com/gpu"
+	defer delete(s.clusterClient.CustomData, gpuResourceNameKey)
+	spec = newSpec()
+	err = applyGPU(s.clusterClient, a, "infer", spec)
+	c.Assert(err, check.IsNil)
+	c.Assert(spec.Containers[0].Resources.Limits["amd.com/gpu"], check.DeepEquals, *resource.NewQuantity(1, resource.DecimalSI))
+	c.Assert(spec.NodeSelector, check.DeepEquals, map[string]string{"tsuru.io/pool": "gpu"})
+	c.Assert(spec.Tolerations, check.DeepEquals, []apiv1.Toleration{
+		{Key: "amd.com/gpu", Operator: apiv1.TolerationOpExists, Effect: apiv1.TaintEffectNoSchedule},
+	})
+	spec = newSpec()
+	err = applyGPU(s.clusterClient, a, "web", spec)
+	c.Assert(err, check.IsNil)
+	c.Assert(spec, check.DeepEquals, newSpec())
+}
+
+func (s *S) TestApplyGPUWithoutTypeLabel(c *check.C) {
+	a := provisiontest.NewFakeApp("myapp", "python", 0)
+	a.GPU = 1
+	a.GPUType = "nvidia-tesla-t4"
+	spec := &apiv1.PodSpec{Containers: []apiv1.Container{{Name: "myapp-web"}}}
+	err := applyGPU(s.clusterClient, a, "web", spec)
+	c.Assert(err, check.ErrorMatches, `unable to schedule units with gpu type "nvidia-tesla-t4": cluster "c1" has no gpu-type-label config`)
+}
@@ -110,6 +110,11 @@ func pdbNameForApp(a provision.App, process string) string {
 	return provision.AppProcessName(a, process, 0, "")
 }
 
+func appEnvsSecretName(a provision.App) string {
+	name := provision.ValidKubeName(a.GetName())
+	return fmt.Sprintf("app-%s-envs", name)
+}
+
 func execCommandPodNameForApp(a provision.App) string {
 	name := provision.ValidKubeName(a.GetName())
 	return fmt.Sprintf("%s-isolated-run", name)
@@ -1091,7 +1096,7 @@ func runPod(ctx context.Context, args runSinglePodArgs) error {
 	if err != nil {
 		return err
 	}
-	nodeSelector, affinity, err := defineSelectorAndAffinity(ctx, args.app, args.client)
+	nodeSelector, affinity, tolerations, err := defineSelectorAndAffinity(ctx, args.app, args.client)
 	if err != nil {
 		return err
 	}
@@ -1123,7 +1128,9 @@ func runPod(ctx context.Context, args runSinglePodArgs) error {
 			ImagePullSecrets:   pullSecrets,
 			ServiceAccountName: serviceAccountNameForApp(args.app),
 			NodeSelector:       nodeSelector,
+			Tolerations:        tolerations,
 			RestartPolicy:      apiv1.RestartPolicyNever,
+			SchedulerName:      args.client.schedulerName(args.app.GetPool()),
 			Containers: []apiv1.Container{
 				{
 					Name:      args.name,
@@ -1139,6 +1146,11 @@ func runPod(ctx context.Context, args runSinglePodArgs) error {
 		},
 	}
 
+	err = applyPodTemplate(args.client, args.app.GetPool(), &pod.ObjectMeta, &pod.Spec)
+	if err != nil {
+		return err
+	}
+
 	var initialResource string
 	if args.eventsOutput != nil {
 		var events *apiv1.EventList
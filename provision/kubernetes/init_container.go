@@ -0,0 +1,90 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kubernetes
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	apiv1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// initContainersLogger writes the logs of init containers of the new units
+// of a deployment to the deploy output once they finish, each run of each
+// init container is written only once.
+type initContainersLogger struct {
+	client  *ClusterClient
+	w       io.Writer
+	written map[string]struct{}
+}
+
+func newInitContainersLogger(client *ClusterClient, w io.Writer) *initContainersLogger {
+	return &initContainersLogger{
+		client:  client,
+		w:       w,
+		written: map[string]struct{}{},
+	}
+}
+
+func (l *initContainersLogger) report(ctx context.Context, dep *appsv1.Deployment) error {
+	if len(dep.Spec.Template.Spec.InitContainers) == 0 {
+		return nil
+	}
+	replica, err := activeReplicaSetForDeployment(ctx, l.client, dep)
+	if err != nil {
+		if k8sErrors.IsNotFound(errors.Cause(err)) {
+			return nil
+		}
+		return err
+	}
+	pods, err := podsForReplicaSet(ctx, l.client, replica)
+	if err != nil {
+		return err
+	}
+	for _, pod := range pods {
+		for _, status := range pod.Status.InitContainerStatuses {
+			terminated, run, previous := status.State.Terminated, status.RestartCount, false
+			if terminated == nil {
+				terminated, run, previous = status.LastTerminationState.Terminated, status.RestartCount-1, true
+			}
+			if terminated == nil {
+				continue
+			}
+			key := fmt.Sprintf("%s/%s/%d", pod.Name, status.Name, run)
+			if _, ok := l.written[key]; ok {
+				continue
+			}
+			l.written[key] = struct{}{}
+			l.write(ctx, &pod, status.Name, terminated, previous)
+		}
+	}
+	return nil
+}
+
+func (l *initContainersLogger) write(ctx context.Context, pod *apiv1.Pod, container string, terminated *apiv1.ContainerStateTerminated, previous bool) {
+	if terminated.ExitCode == 0 {
+		fmt.Fprintf(l.w, " ---> Init container %q finished on unit %s\n", container, pod.Name)
+	} else {
+		fmt.Fprintf(l.w, " ---> Init container %q failed on unit %s with exit code %d\n", container, pod.Name, terminated.ExitCode)
+	}
+	data, err := l.client.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &apiv1.PodLogOptions{
+		Container: container,
+		Previous:  previous,
+	}).DoRaw(ctx)
+	if err != nil {
+		fmt.Fprintf(l.w, "      unable to get logs: %v\n", err)
+		return
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fmt.Fprintf(l.w, "      %s\n", scanner.Text())
+	}
+}
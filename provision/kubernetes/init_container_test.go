@@ -0,0 +1,99 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kubernetes
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	check "gopkg.in/check.v1"
+	appsv1 "k8s.io/api/apps/v1"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func (s *S) TestInitContainersLoggerReport(c *check.C) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.URL.Path, check.Matches, "/api/v1/namespaces/.*/pods/myapp-web-2-abc/log")
+		c.Check(r.FormValue("container"), check.Equals, "migrate")
+		fmt.Fprintf(w, "previous=%s\nmigrated\n", r.FormValue("previous"))
+	}))
+	defer srv.Close()
+	s.clusterClient.RestConfig().Host = srv.URL
+	labels := map[string]string{"tsuru.io/app-name": "myapp"}
+	dep := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "myapp-web",
+			Namespace:   "default",
+			Annotations: map[string]string{replicaDepRevision: "2"},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: apiv1.PodTemplateSpec{
+				Spec: apiv1.PodSpec{
+					InitContainers: []apiv1.Container{{Name: "migrate"}},
+				},
+			},
+		},
+	}
+	_, err := s.client.Clientset.AppsV1().ReplicaSets("default").Create(context.TODO(), &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "myapp-web-2",
+			Namespace:   "default",
+			Labels:      labels,
+			Annotations: map[string]string{replicaDepRevision: "2"},
+		},
+		Spec: appsv1.ReplicaSetSpec{Selector: &metav1.LabelSelector{MatchLabels: labels}},
+	}, metav1.CreateOptions{})
+	c.Assert(err, check.IsNil)
+	pod := &apiv1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "myapp-web-2-abc", Namespace: "default", Labels: labels},
+		Status: apiv1.PodStatus{
+			InitContainerStatuses: []apiv1.ContainerStatus{
+				{Name: "migrate", State: apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}}},
+			},
+		},
+	}
+	pod, err = s.client.Clientset.CoreV1().Pods("default").Create(context.TODO(), pod, metav1.CreateOptions{})
+	c.Assert(err, check.IsNil)
+	buf := &bytes.Buffer{}
+	logger := newInitContainersLogger(s.clusterClient, buf)
+	err = logger.report(context.TODO(), dep)
+	c.Assert(err, check.IsNil)
+	c.Assert(buf.String(), check.Equals, "")
+	pod.Status.InitContainerStatuses[0] = apiv1.ContainerStatus{
+		Name:                 "migrate",
+		RestartCount:         1,
+		State:                apiv1.ContainerState{Waiting: &apiv1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+		LastTerminationState: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{ExitCode: 1}},
+	}
+	pod, err = s.client.Clientset.CoreV1().Pods("default").Update(context.TODO(), pod, metav1.UpdateOptions{})
+	c.Assert(err, check.IsNil)
+	err = logger.report(context.TODO(), dep)
+	c.Assert(err, check.IsNil)
+	c.Assert(buf.String(), check.Equals, ` ---> Init container "migrate" failed on unit myapp-web-2-abc with exit code 1
+      previous=true
+      migrated
+`)
+	pod.Status.InitContainerStatuses[0] = apiv1.ContainerStatus{
+		Name:         "migrate",
+		RestartCount: 1,
+		State:        apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{ExitCode: 0}},
+	}
+	_, err = s.client.Clientset.CoreV1().Pods("default").Update(context.TODO(), pod, metav1.UpdateOptions{})
+	c.Assert(err, check.IsNil)
+	buf.Reset()
+	err = logger.report(context.TODO(), dep)
+	c.Assert(err, check.IsNil)
+	err = logger.report(context.TODO(), dep)
+	c.Assert(err, check.IsNil)
+	c.Assert(buf.String(), check.Equals, ` ---> Init container "migrate" finished on unit myapp-web-2-abc
+      previous=
+      migrated
+`)
+}
@@ -0,0 +1,427 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/log"
+	"github.com/tsuru/tsuru/permission"
+	"github.com/tsuru/tsuru/provision"
+	jobTypes "github.com/tsuru/tsuru/types/job"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	apiv1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	jobHistoryLimit          = 3
+	jobInstantiateAnnotation = "cronjob.kubernetes.io/instantiate"
+
+	jobRunStartedAnnotation  = "tsuru.io/job-run-start-notified"
+	jobRunFinishedAnnotation = "tsuru.io/job-run-finish-notified"
+	jobRunLogsTailLines      = 50
+	jobRunLogsMaxBytes       = 4096
+
+	jobRunStartEventKind   = "job.run.start"
+	jobRunSuccessEventKind = "job.run.success"
+	jobRunFailureEventKind = "job.run.failure"
+)
+
+func jobLabels(job *jobTypes.Job) *provision.LabelSet {
+	return provision.JobLabels(provision.JobLabelsOpts{
+		Name:        job.Name,
+		Provisioner: provisionerName,
+		Pool:        job.Pool,
+		Team:        job.TeamOwner,
+		Prefix:      tsuruLabelPrefix,
+	})
+}
+
+func jobResourceRequirements(job *jobTypes.Job) apiv1.ResourceRequirements {
+	resources := apiv1.ResourceList{}
+	if job.Plan.Memory != 0 {
+		resources[apiv1.ResourceMemory] = *resource.NewQuantity(job.Plan.Memory, resource.BinarySI)
+	}
+	if job.Plan.CPUMilli != 0 {
+		resources[apiv1.ResourceCPU] = *resource.NewMilliQuantity(int64(job.Plan.CPUMilli), resource.DecimalSI)
+	}
+	return apiv1.ResourceRequirements{Limits: resources, Requests: resources}
+}
+
+func jobPodTemplate(ctx context.Context, client *ClusterClient, job *jobTypes.Job) (apiv1.PodTemplateSpec, error) {
+	nodeSelector, affinity, tolerations, err := poolSelectorAndAffinity(ctx, job.Pool, client)
+	if err != nil {
+		return apiv1.PodTemplateSpec{}, err
+	}
+	envNames := make([]string, 0, len(job.Envs))
+	for name := range job.Envs {
+		envNames = append(envNames, name)
+	}
+	sort.Strings(envNames)
+	var envs []apiv1.EnvVar
+	for _, name := range envNames {
+		envs = append(envs, apiv1.EnvVar{Name: name, Value: job.Envs[name]})
+	}
+	return apiv1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: jobLabels(job).ToLabels(),
+		},
+		Spec: apiv1.PodSpec{
+			RestartPolicy: apiv1.RestartPolicyNever,
+			NodeSelector:  nodeSelector,
+			Affinity:      affinity,
+			Tolerations:   tolerations,
+			SchedulerName: client.schedulerName(job.Pool),
+			Containers: []apiv1.Container{
+				{
+					Name:      job.Name,
+					Image:     job.Image,
+					Command:   job.Command,
+					Env:       envs,
+					Resources: jobResourceRequirements(job),
+				},
+			},
+		},
+	}, nil
+}
+
+func jobSpec(ctx context.Context, client *ClusterClient, job *jobTypes.Job, backoffLimit int32) (batchv1.JobSpec, error) {
+	template, err := jobPodTemplate(ctx, client, job)
+	if err != nil {
+		return batchv1.JobSpec{}, err
+	}
+	return batchv1.JobSpec{
+		BackoffLimit: &backoffLimit,
+		Template:     template,
+	}, nil
+}
+
+func (p *kubernetesProvisioner) CreateJob(ctx context.Context, job *jobTypes.Job) error {
+	if !job.IsCron() {
+		return nil
+	}
+	client, err := clusterForPool(ctx, job.Pool)
+	if err != nil {
+		return err
+	}
+	ns := client.PoolNamespace(job.Pool)
+	err = ensureNamespace(ctx, client, ns)
+	if err != nil {
+		return err
+	}
+	spec, err := jobSpec(ctx, client, job, int32(job.Retry.Limit))
+	if err != nil {
+		return err
+	}
+	historyLimit := int32(jobHistoryLimit)
+	cronJob := &batchv1beta1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   job.Name,
+			Labels: jobLabels(job).ToLabels(),
+		},
+		Spec: batchv1beta1.CronJobSpec{
+			Schedule:                   job.Schedule,
+			ConcurrencyPolicy:          batchv1beta1.ConcurrencyPolicy(job.GetConcurrencyPolicy()),
+			SuccessfulJobsHistoryLimit: &historyLimit,
+			FailedJobsHistoryLimit:     &historyLimit,
+			JobTemplate: batchv1beta1.JobTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: jobLabels(job).ToLabels(),
+				},
+				Spec: spec,
+			},
+		},
+	}
+	return upsertCronJob(ctx, client, ns, cronJob)
+}
+
+func (p *kubernetesProvisioner) RunJob(ctx context.Context, job *jobTypes.Job) error {
+	client, err := clusterForPool(ctx, job.Pool)
+	if err != nil {
+		return err
+	}
+	ns := client.PoolNamespace(job.Pool)
+	err = ensureNamespace(ctx, client, ns)
+	if err != nil {
+		return err
+	}
+	spec, err := jobSpec(ctx, client, job, 0)
+	if err != nil {
+		return err
+	}
+	k8sJob, err := client.BatchV1().Jobs(ns).Create(ctx, &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: job.Name + "-",
+			Labels:       jobLabels(job).ToLabels(),
+		},
+		Spec: spec,
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	kubeConf := getKubeConfig()
+	tctx, cancel := context.WithTimeout(ctx, kubeConf.PodRunningTimeout)
+	defer cancel()
+	return waitFor(tctx, func() (bool, error) {
+		return jobFinished(tctx, client, ns, k8sJob.Name)
+	}, nil)
+}
+
+// TriggerCronJob creates a run from the cronjob template, just like kubectl
+// create job --from does.
+func (p *kubernetesProvisioner) TriggerCronJob(ctx context.Context, job *jobTypes.Job) error {
+	client, err := clusterForPool(ctx, job.Pool)
+	if err != nil {
+		return err
+	}
+	ns := client.PoolNamespace(job.Pool)
+	cronJob, err := client.BatchV1beta1().CronJobs(ns).Get(ctx, job.Name, metav1.GetOptions{})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	annotations := map[string]string{jobInstantiateAnnotation: "manual"}
+	for k, v := range cronJob.Spec.JobTemplate.Annotations {
+		annotations[k] = v
+	}
+	_, err = client.BatchV1().Jobs(ns).Create(ctx, &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: job.Name + "-manual-",
+			Labels:       cronJob.Spec.JobTemplate.Labels,
+			Annotations:  annotations,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(cronJob, batchv1beta1.SchemeGroupVersion.WithKind("CronJob")),
+			},
+		},
+		Spec: cronJob.Spec.JobTemplate.Spec,
+	}, metav1.CreateOptions{})
+	return errors.WithStack(err)
+}
+
+func (p *kubernetesProvisioner) ActiveJobRuns(ctx context.Context, job *jobTypes.Job) (int, error) {
+	client, err := clusterForPool(ctx, job.Pool)
+	if err != nil {
+		return 0, err
+	}
+	ns := client.PoolNamespace(job.Pool)
+	selector := labels.SelectorFromSet(labels.Set(jobLabels(job).ToJobSelector()))
+	jobs, err := client.BatchV1().Jobs(ns).List(ctx, metav1.ListOptions{
+		LabelSelector: selector.String(),
+	})
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	active := 0
+	for i := range jobs.Items {
+		if finished, _ := jobRunResult(&jobs.Items[i]); !finished {
+			active++
+		}
+	}
+	return active, nil
+}
+
+func jobFinished(ctx context.Context, client *ClusterClient, ns, name string) (bool, error) {
+	k8sJob, err := client.BatchV1().Jobs(ns).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+	return jobRunResult(k8sJob)
+}
+
+// jobRunResult returns whether the job run has finished and, when it has
+// failed, the reason of the failure.
+func jobRunResult(k8sJob *batchv1.Job) (bool, error) {
+	for _, cond := range k8sJob.Status.Conditions {
+		if cond.Type == batchv1.JobFailed && cond.Status == apiv1.ConditionTrue {
+			return true, errors.Errorf("job %q failed: %s", k8sJob.Name, cond.Message)
+		}
+	}
+	if k8sJob.Status.Failed > 0 && k8sJob.Spec.BackoffLimit != nil && k8sJob.Status.Failed > *k8sJob.Spec.BackoffLimit {
+		return true, errors.Errorf("job %q failed", k8sJob.Name)
+	}
+	return k8sJob.Status.Succeeded > 0, nil
+}
+
+type jobRunEndData struct {
+	Run      string
+	ExitCode *int32 `bson:",omitempty"`
+}
+
+func (c *clusterController) startJobRunNotifier() error {
+	informer, err := c.getJobInformer()
+	if err != nil {
+		return err
+	}
+	onJob := func(obj interface{}) {
+		if !c.isLeader() {
+			return
+		}
+		k8sJob, ok := obj.(*batchv1.Job)
+		if !ok {
+			return
+		}
+		err := c.notifyJobRun(context.Background(), k8sJob)
+		if err != nil {
+			log.Errorf("[job-run-notifier] error notifying run %q: %v", k8sJob.Name, err)
+		}
+	}
+	informer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: onJob,
+		UpdateFunc: func(_, newObj interface{}) {
+			onJob(newObj)
+		},
+	})
+	return nil
+}
+
+// notifyJobRun creates the job.run.start event once the run starts and
+// either the job.run.success or the job.run.failure event once it finishes,
+// allowing event webhooks to notify teams about job runs. Notified runs are
+// annotated so each event is created only once.
+func (c *clusterController) notifyJobRun(ctx context.Context, k8sJob *batchv1.Job) error {
+	l := labelSetFromMeta(&k8sJob.ObjectMeta)
+	if l.JobName() == "" {
+		return nil
+	}
+	finished, runErr := jobRunResult(k8sJob)
+	notifyStart := k8sJob.Annotations[jobRunStartedAnnotation] == ""
+	notifyEnd := finished && k8sJob.Annotations[jobRunFinishedAnnotation] == ""
+	if !notifyStart && !notifyEnd {
+		return nil
+	}
+	opts := &event.Opts{
+		Target:      event.Target{Type: event.TargetTypeJob, Value: l.JobName()},
+		DisableLock: true,
+		Allowed: event.Allowed(permission.PermJobReadEvents,
+			permission.Context(permTypes.CtxTeam, l.JobTeam()),
+			permission.Context(permTypes.CtxPool, l.JobPool()),
+		),
+	}
+	annotations := map[string]string{}
+	if notifyStart {
+		opts.InternalKind = jobRunStartEventKind
+		evt, err := event.NewInternal(opts)
+		if err != nil {
+			return err
+		}
+		evt.Logf("job run %q started", k8sJob.Name)
+		evt.Done(nil)
+		annotations[jobRunStartedAnnotation] = "true"
+	}
+	if notifyEnd {
+		opts.InternalKind = jobRunSuccessEventKind
+		if runErr != nil {
+			opts.InternalKind = jobRunFailureEventKind
+		}
+		evt, err := event.NewInternal(opts)
+		if err != nil {
+			return err
+		}
+		endData := jobRunEndData{Run: k8sJob.Name}
+		pod, err := lastJobRunPod(ctx, c.cluster, k8sJob)
+		if err == nil && pod != nil {
+			endData.ExitCode = podExitCode(pod)
+			if endData.ExitCode != nil && runErr != nil {
+				runErr = errors.Errorf("job %q failed with exit code %d", k8sJob.Name, *endData.ExitCode)
+			}
+			evt.Write([]byte(jobRunLogs(ctx, c.cluster, pod)))
+		}
+		evt.DoneCustomData(runErr, endData)
+		annotations[jobRunFinishedAnnotation] = "true"
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{"annotations": annotations},
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	_, err = c.cluster.BatchV1().Jobs(k8sJob.Namespace).Patch(ctx, k8sJob.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	return errors.WithStack(err)
+}
+
+func lastJobRunPod(ctx context.Context, client *ClusterClient, k8sJob *batchv1.Job) (*apiv1.Pod, error) {
+	pods, err := client.CoreV1().Pods(k8sJob.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{"job-name": k8sJob.Name}).String(),
+	})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	var last *apiv1.Pod
+	for i := range pods.Items {
+		if last == nil || last.CreationTimestamp.Before(&pods.Items[i].CreationTimestamp) {
+			last = &pods.Items[i]
+		}
+	}
+	return last, nil
+}
+
+func podExitCode(pod *apiv1.Pod) *int32 {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.State.Terminated != nil {
+			code := status.State.Terminated.ExitCode
+			return &code
+		}
+	}
+	return nil
+}
+
+// jobRunLogs returns the last lines of the run logs, truncated to
+// jobRunLogsMaxBytes.
+func jobRunLogs(ctx context.Context, client *ClusterClient, pod *apiv1.Pod) string {
+	tailLines := int64(jobRunLogsTailLines)
+	data, err := client.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &apiv1.PodLogOptions{
+		TailLines: &tailLines,
+	}).DoRaw(ctx)
+	if err != nil {
+		return fmt.Sprintf("unable to get job run logs: %v\n", err)
+	}
+	if len(data) > jobRunLogsMaxBytes {
+		data = data[len(data)-jobRunLogsMaxBytes:]
+	}
+	return string(data)
+}
+
+func (p *kubernetesProvisioner) DestroyJob(ctx context.Context, job *jobTypes.Job) error {
+	client, err := clusterForPool(ctx, job.Pool)
+	if err != nil {
+		return err
+	}
+	ns := client.PoolNamespace(job.Pool)
+	propagation := metav1.DeletePropagationBackground
+	err = client.BatchV1beta1().CronJobs(ns).Delete(ctx, job.Name, metav1.DeleteOptions{
+		PropagationPolicy: &propagation,
+	})
+	if err != nil && !k8sErrors.IsNotFound(err) {
+		return errors.WithStack(err)
+	}
+	selector := labels.SelectorFromSet(labels.Set(jobLabels(job).ToJobSelector()))
+	jobs, err := client.BatchV1().Jobs(ns).List(ctx, metav1.ListOptions{
+		LabelSelector: selector.String(),
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	for _, k8sJob := range jobs.Items {
+		err = client.BatchV1().Jobs(ns).Delete(ctx, k8sJob.Name, metav1.DeleteOptions{
+			PropagationPolicy: &propagation,
+		})
+		if err != nil && !k8sErrors.IsNotFound(err) {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
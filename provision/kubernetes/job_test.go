@@ -0,0 +1,349 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kubernetes
+
+import (
+	"context"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/tsuru/tsuru/event"
+	appTypes "github.com/tsuru/tsuru/types/app"
+	jobTypes "github.com/tsuru/tsuru/types/job"
+	check "gopkg.in/check.v1"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	apiv1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ktesting "k8s.io/client-go/testing"
+)
+
+func (s *S) finishJobRuns(failed bool) {
+	s.client.PrependReactor("create", "jobs", func(action ktesting.Action) (bool, runtime.Object, error) {
+		k8sJob := action.(ktesting.CreateAction).GetObject().(*batchv1.Job)
+		k8sJob.Name = k8sJob.GenerateName + "run"
+		if failed {
+			k8sJob.Status.Failed = 1
+			k8sJob.Status.Conditions = []batchv1.JobCondition{
+				{Type: batchv1.JobFailed, Status: apiv1.ConditionTrue, Message: "Job has reached the specified backoff limit"},
+			}
+		} else {
+			k8sJob.Status.Succeeded = 1
+		}
+		return false, nil, nil
+	})
+}
+
+func (s *S) TestCreateJobOneOffJob(c *check.C) {
+	job := &jobTypes.Job{
+		Name:      "myjob",
+		TeamOwner: "admin",
+		Pool:      "test-default",
+		Image:     "myimage:v1",
+	}
+	err := s.p.CreateJob(context.TODO(), job)
+	c.Assert(err, check.IsNil)
+	ns := s.client.PoolNamespace(job.Pool)
+	jobs, err := s.client.BatchV1().Jobs(ns).List(context.TODO(), metav1.ListOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(jobs.Items, check.HasLen, 0)
+	cronJobs, err := s.client.BatchV1beta1().CronJobs(ns).List(context.TODO(), metav1.ListOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(cronJobs.Items, check.HasLen, 0)
+}
+
+func (s *S) TestRunJob(c *check.C) {
+	s.finishJobRuns(false)
+	job := &jobTypes.Job{
+		Name:      "myjob",
+		TeamOwner: "admin",
+		Pool:      "test-default",
+		Plan:      appTypes.Plan{Memory: 1024 * 1024 * 1024, CPUMilli: 500},
+		Image:     "myimage:v1",
+		Command:   []string{"./run.sh", "--all"},
+		Envs:      map[string]string{"B": "2", "A": "1"},
+		Retry:     jobTypes.RetryPolicy{Limit: 3},
+	}
+	err := s.p.RunJob(context.TODO(), job)
+	c.Assert(err, check.IsNil)
+	ns := s.client.PoolNamespace(job.Pool)
+	jobs, err := s.client.BatchV1().Jobs(ns).List(context.TODO(), metav1.ListOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(jobs.Items, check.HasLen, 1)
+	k8sJob := jobs.Items[0]
+	c.Assert(k8sJob.GenerateName, check.Equals, "myjob-")
+	c.Assert(k8sJob.Labels, check.DeepEquals, map[string]string{
+		"tsuru.io/is-tsuru":    "true",
+		"tsuru.io/provisioner": "kubernetes",
+		"tsuru.io/job-name":    "myjob",
+		"tsuru.io/job-pool":    "test-default",
+		"tsuru.io/job-team":    "admin",
+	})
+	c.Assert(*k8sJob.Spec.BackoffLimit, check.Equals, int32(0))
+	podSpec := k8sJob.Spec.Template.Spec
+	c.Assert(podSpec.RestartPolicy, check.Equals, apiv1.RestartPolicyNever)
+	c.Assert(podSpec.NodeSelector, check.DeepEquals, map[string]string{
+		"tsuru.io/pool": "test-default",
+	})
+	c.Assert(podSpec.Containers, check.HasLen, 1)
+	container := podSpec.Containers[0]
+	c.Assert(container.Name, check.Equals, "myjob")
+	c.Assert(container.Image, check.Equals, "myimage:v1")
+	c.Assert(container.Command, check.DeepEquals, []string{"./run.sh", "--all"})
+	c.Assert(container.Env, check.DeepEquals, []apiv1.EnvVar{
+		{Name: "A", Value: "1"},
+		{Name: "B", Value: "2"},
+	})
+	expectedResources := apiv1.ResourceList{
+		apiv1.ResourceMemory: resource.MustParse("1Gi"),
+		apiv1.ResourceCPU:    resource.MustParse("500m"),
+	}
+	c.Assert(container.Resources.Limits, check.DeepEquals, expectedResources)
+	c.Assert(container.Resources.Requests, check.DeepEquals, expectedResources)
+}
+
+func (s *S) TestRunJobFailed(c *check.C) {
+	s.finishJobRuns(true)
+	job := &jobTypes.Job{
+		Name:      "myjob",
+		TeamOwner: "admin",
+		Pool:      "test-default",
+		Image:     "myimage:v1",
+	}
+	err := s.p.RunJob(context.TODO(), job)
+	c.Assert(err, check.ErrorMatches, `job "myjob-run" failed: Job has reached the specified backoff limit`)
+}
+
+func (s *S) TestCreateJobCronJob(c *check.C) {
+	job := &jobTypes.Job{
+		Name:      "mycron",
+		TeamOwner: "admin",
+		Pool:      "test-default",
+		Image:     "myimage:v1",
+		Schedule:  "*/5 * * * *",
+		Retry:     jobTypes.RetryPolicy{Limit: 2, BackoffSeconds: 30},
+	}
+	s.finishJobRuns(false)
+	err := s.p.CreateJob(context.TODO(), job)
+	c.Assert(err, check.IsNil)
+	ns := s.client.PoolNamespace(job.Pool)
+	cronJob, err := s.client.BatchV1beta1().CronJobs(ns).Get(context.TODO(), "mycron", metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(cronJob.Spec.Schedule, check.Equals, "*/5 * * * *")
+	c.Assert(cronJob.Labels["tsuru.io/job-name"], check.Equals, "mycron")
+	c.Assert(cronJob.Spec.JobTemplate.Spec.Template.Spec.Containers[0].Image, check.Equals, "myimage:v1")
+	c.Assert(*cronJob.Spec.JobTemplate.Spec.BackoffLimit, check.Equals, int32(2))
+	c.Assert(cronJob.Spec.ConcurrencyPolicy, check.Equals, batchv1beta1.ForbidConcurrent)
+	jobs, err := s.client.BatchV1().Jobs(ns).List(context.TODO(), metav1.ListOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(jobs.Items, check.HasLen, 0)
+	err = s.p.RunJob(context.TODO(), job)
+	c.Assert(err, check.IsNil)
+	jobs, err = s.client.BatchV1().Jobs(ns).List(context.TODO(), metav1.ListOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(jobs.Items, check.HasLen, 1)
+	c.Assert(jobs.Items[0].Labels["tsuru.io/job-name"], check.Equals, "mycron")
+	c.Assert(*jobs.Items[0].Spec.BackoffLimit, check.Equals, int32(0))
+}
+
+func (s *S) TestCreateJobCronJobConcurrencyPolicy(c *check.C) {
+	job := &jobTypes.Job{
+		Name:              "mycron",
+		TeamOwner:         "admin",
+		Pool:              "test-default",
+		Image:             "myimage:v1",
+		Schedule:          "*/5 * * * *",
+		ConcurrencyPolicy: jobTypes.ConcurrencyReplace,
+	}
+	err := s.p.CreateJob(context.TODO(), job)
+	c.Assert(err, check.IsNil)
+	ns := s.client.PoolNamespace(job.Pool)
+	cronJob, err := s.client.BatchV1beta1().CronJobs(ns).Get(context.TODO(), "mycron", metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(cronJob.Spec.ConcurrencyPolicy, check.Equals, batchv1beta1.ReplaceConcurrent)
+	job.ConcurrencyPolicy = jobTypes.ConcurrencyAllow
+	err = s.p.CreateJob(context.TODO(), job)
+	c.Assert(err, check.IsNil)
+	cronJob, err = s.client.BatchV1beta1().CronJobs(ns).Get(context.TODO(), "mycron", metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(cronJob.Spec.ConcurrencyPolicy, check.Equals, batchv1beta1.AllowConcurrent)
+}
+
+func (s *S) TestTriggerCronJob(c *check.C) {
+	job := &jobTypes.Job{
+		Name:      "mycron",
+		TeamOwner: "admin",
+		Pool:      "test-default",
+		Image:     "myimage:v1",
+		Schedule:  "*/5 * * * *",
+		Retry:     jobTypes.RetryPolicy{Limit: 2},
+	}
+	err := s.p.CreateJob(context.TODO(), job)
+	c.Assert(err, check.IsNil)
+	err = s.p.TriggerCronJob(context.TODO(), job)
+	c.Assert(err, check.IsNil)
+	ns := s.client.PoolNamespace(job.Pool)
+	jobs, err := s.client.BatchV1().Jobs(ns).List(context.TODO(), metav1.ListOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(jobs.Items, check.HasLen, 1)
+	k8sJob := jobs.Items[0]
+	c.Assert(k8sJob.GenerateName, check.Equals, "mycron-manual-")
+	c.Assert(k8sJob.Labels["tsuru.io/job-name"], check.Equals, "mycron")
+	c.Assert(k8sJob.Annotations, check.DeepEquals, map[string]string{
+		"cronjob.kubernetes.io/instantiate": "manual",
+	})
+	c.Assert(k8sJob.OwnerReferences, check.HasLen, 1)
+	c.Assert(k8sJob.OwnerReferences[0].Kind, check.Equals, "CronJob")
+	c.Assert(k8sJob.OwnerReferences[0].Name, check.Equals, "mycron")
+	c.Assert(*k8sJob.Spec.BackoffLimit, check.Equals, int32(2))
+	c.Assert(k8sJob.Spec.Template.Spec.Containers[0].Image, check.Equals, "myimage:v1")
+}
+
+func (s *S) TestTriggerCronJobNotFound(c *check.C) {
+	job := &jobTypes.Job{
+		Name:      "mycron",
+		TeamOwner: "admin",
+		Pool:      "test-default",
+		Image:     "myimage:v1",
+		Schedule:  "*/5 * * * *",
+	}
+	err := s.p.TriggerCronJob(context.TODO(), job)
+	c.Assert(k8sErrors.IsNotFound(errors.Cause(err)), check.Equals, true)
+}
+
+func (s *S) TestDestroyJob(c *check.C) {
+	job := &jobTypes.Job{
+		Name:      "mycron",
+		TeamOwner: "admin",
+		Pool:      "test-default",
+		Image:     "myimage:v1",
+		Schedule:  "*/5 * * * *",
+	}
+	s.finishJobRuns(false)
+	err := s.p.CreateJob(context.TODO(), job)
+	c.Assert(err, check.IsNil)
+	err = s.p.RunJob(context.TODO(), job)
+	c.Assert(err, check.IsNil)
+	err = s.p.DestroyJob(context.TODO(), job)
+	c.Assert(err, check.IsNil)
+	ns := s.client.PoolNamespace(job.Pool)
+	cronJobs, err := s.client.BatchV1beta1().CronJobs(ns).List(context.TODO(), metav1.ListOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(cronJobs.Items, check.HasLen, 0)
+	jobs, err := s.client.BatchV1().Jobs(ns).List(context.TODO(), metav1.ListOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(jobs.Items, check.HasLen, 0)
+	err = s.p.DestroyJob(context.TODO(), job)
+	c.Assert(err, check.IsNil)
+}
+
+func (s *S) TestNotifyJobRun(c *check.C) {
+	job := &jobTypes.Job{Name: "mycron", TeamOwner: "admin", Pool: "test-default"}
+	ns := s.client.PoolNamespace(job.Pool)
+	backoffLimit := int32(0)
+	k8sJob, err := s.client.BatchV1().Jobs(ns).Create(context.TODO(), &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "mycron-1", Namespace: ns, Labels: jobLabels(job).ToLabels()},
+		Spec:       batchv1.JobSpec{BackoffLimit: &backoffLimit},
+	}, metav1.CreateOptions{})
+	c.Assert(err, check.IsNil)
+	ctr := &clusterController{cluster: s.clusterClient}
+	err = ctr.notifyJobRun(context.TODO(), k8sJob)
+	c.Assert(err, check.IsNil)
+	target := event.Target{Type: event.TargetTypeJob, Value: "mycron"}
+	evts, err := event.List(&event.Filter{Target: target})
+	c.Assert(err, check.IsNil)
+	c.Assert(evts, check.HasLen, 1)
+	c.Assert(evts[0].Kind.Name, check.Equals, "job.run.start")
+	c.Assert(evts[0].Kind.Type, check.Equals, event.KindTypeInternal)
+	k8sJob, err = s.client.BatchV1().Jobs(ns).Get(context.TODO(), "mycron-1", metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(k8sJob.Annotations["tsuru.io/job-run-start-notified"], check.Equals, "true")
+	err = ctr.notifyJobRun(context.TODO(), k8sJob)
+	c.Assert(err, check.IsNil)
+	evts, err = event.List(&event.Filter{Target: target})
+	c.Assert(err, check.IsNil)
+	c.Assert(evts, check.HasLen, 1)
+	k8sJob.Status.Succeeded = 1
+	k8sJob, err = s.client.BatchV1().Jobs(ns).Update(context.TODO(), k8sJob, metav1.UpdateOptions{})
+	c.Assert(err, check.IsNil)
+	err = ctr.notifyJobRun(context.TODO(), k8sJob)
+	c.Assert(err, check.IsNil)
+	evts, err = event.List(&event.Filter{Target: target})
+	c.Assert(err, check.IsNil)
+	c.Assert(evts, check.HasLen, 2)
+	c.Assert(evts[0].Kind.Name, check.Equals, "job.run.success")
+	c.Assert(evts[0].Error, check.Equals, "")
+	k8sJob, err = s.client.BatchV1().Jobs(ns).Get(context.TODO(), "mycron-1", metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(k8sJob.Annotations["tsuru.io/job-run-finish-notified"], check.Equals, "true")
+	err = ctr.notifyJobRun(context.TODO(), k8sJob)
+	c.Assert(err, check.IsNil)
+	evts, err = event.List(&event.Filter{Target: target})
+	c.Assert(err, check.IsNil)
+	c.Assert(evts, check.HasLen, 2)
+}
+
+func (s *S) TestNotifyJobRunFailure(c *check.C) {
+	job := &jobTypes.Job{Name: "mycron", TeamOwner: "admin", Pool: "test-default"}
+	ns := s.client.PoolNamespace(job.Pool)
+	backoffLimit := int32(0)
+	k8sJob, err := s.client.BatchV1().Jobs(ns).Create(context.TODO(), &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "mycron-1", Namespace: ns, Labels: jobLabels(job).ToLabels()},
+		Spec:       batchv1.JobSpec{BackoffLimit: &backoffLimit},
+		Status: batchv1.JobStatus{
+			Failed: 1,
+			Conditions: []batchv1.JobCondition{
+				{Type: batchv1.JobFailed, Status: apiv1.ConditionTrue, Message: "Job has reached the specified backoff limit"},
+			},
+		},
+	}, metav1.CreateOptions{})
+	c.Assert(err, check.IsNil)
+	_, err = s.client.CoreV1().Pods(ns).Create(context.TODO(), &apiv1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "mycron-1-abcde", Namespace: ns, Labels: map[string]string{"job-name": "mycron-1"}},
+		Status: apiv1.PodStatus{
+			ContainerStatuses: []apiv1.ContainerStatus{
+				{State: apiv1.ContainerState{Terminated: &apiv1.ContainerStateTerminated{ExitCode: 2}}},
+			},
+		},
+	}, metav1.CreateOptions{})
+	c.Assert(err, check.IsNil)
+	ctr := &clusterController{cluster: s.clusterClient}
+	err = ctr.notifyJobRun(context.TODO(), k8sJob)
+	c.Assert(err, check.IsNil)
+	evts, err := event.List(&event.Filter{Target: event.Target{Type: event.TargetTypeJob, Value: "mycron"}})
+	c.Assert(err, check.IsNil)
+	c.Assert(evts, check.HasLen, 2)
+	kinds := []string{evts[0].Kind.Name, evts[1].Kind.Name}
+	sort.Strings(kinds)
+	c.Assert(kinds, check.DeepEquals, []string{"job.run.failure", "job.run.start"})
+	failure := evts[0]
+	if failure.Kind.Name != "job.run.failure" {
+		failure = evts[1]
+	}
+	c.Assert(failure.Error, check.Equals, `job "mycron-1" failed with exit code 2`)
+	c.Assert(failure.Log(), check.Matches, `(?s).*fake logs.*`)
+	var endData jobRunEndData
+	err = failure.EndData(&endData)
+	c.Assert(err, check.IsNil)
+	c.Assert(endData.Run, check.Equals, "mycron-1")
+	c.Assert(*endData.ExitCode, check.Equals, int32(2))
+}
+
+func (s *S) TestNotifyJobRunIgnoresOtherJobs(c *check.C) {
+	k8sJob, err := s.client.BatchV1().Jobs("default").Create(context.TODO(), &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default"},
+		Status:     batchv1.JobStatus{Succeeded: 1},
+	}, metav1.CreateOptions{})
+	c.Assert(err, check.IsNil)
+	ctr := &clusterController{cluster: s.clusterClient}
+	err = ctr.notifyJobRun(context.TODO(), k8sJob)
+	c.Assert(err, check.IsNil)
+	evts, err := event.List(nil)
+	c.Assert(err, check.IsNil)
+	c.Assert(evts, check.HasLen, 0)
+}
@@ -148,6 +148,7 @@ func listLogsFromPods(ctx context.Context, clusterClient *ClusterClient, ns stri
 			defer wg.Done()
 
 			request := clusterClient.CoreV1().Pods(ns).GetLogs(pod.ObjectMeta.Name, &apiv1.PodLogOptions{
+				Container:  appContainerName(pod),
 				TailLines:  tailLimit,
 				Timestamps: true,
 			})
@@ -295,6 +296,7 @@ func (k *k8sLogsWatcher) watchPod(pod *apiv1.Pod, addedLater bool) {
 	}
 
 	request := k.clusterClient.CoreV1().Pods(k.ns).GetLogs(pod.ObjectMeta.Name, &apiv1.PodLogOptions{
+		Container:  appContainerName(pod),
 		Follow:     true,
 		TailLines:  &tailLines,
 		Timestamps: true,
@@ -396,3 +398,12 @@ func loggablePod(podStatus *apiv1.PodStatus) bool {
 
 	return podStatus.Phase != apiv1.PodPending
 }
+
+// appContainerName returns the name of the container running the app in the
+// pod, sidecars run in the other containers.
+func appContainerName(pod *apiv1.Pod) string {
+	if len(pod.Spec.Containers) == 0 {
+		return ""
+	}
+	return pod.Spec.Containers[0].Name
+}
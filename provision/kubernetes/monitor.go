@@ -26,6 +26,7 @@ import (
 	vpaInternalInterfaces "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/client/informers/externalversions/internalinterfaces"
 	"k8s.io/client-go/informers"
 	autoscalingInformers "k8s.io/client-go/informers/autoscaling/v2beta2"
+	batchInformers "k8s.io/client-go/informers/batch/v1"
 	v1informers "k8s.io/client-go/informers/core/v1"
 	"k8s.io/client-go/informers/internalinterfaces"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -67,6 +68,7 @@ type clusterController struct {
 	nodeInformer            v1informers.NodeInformer
 	hpaInformer             autoscalingInformers.HorizontalPodAutoscalerInformer
 	vpaInformer             vpaV1Informers.VerticalPodAutoscalerInformer
+	jobInformer             batchInformers.JobInformer
 	stopCh                  chan struct{}
 	cancel                  context.CancelFunc
 	resourceReadyCache      map[types.NamespacedName]bool
@@ -196,6 +198,11 @@ func (c *clusterController) start() (v1informers.PodInformer, error) {
 		},
 	})
 
+	err = c.startJobRunNotifier()
+	if err != nil {
+		return nil, err
+	}
+
 	return informer, nil
 }
 
@@ -369,6 +376,21 @@ func (c *clusterController) getVPAInformer() (vpaV1Informers.VerticalPodAutoscal
 	return c.vpaInformer, err
 }
 
+func (c *clusterController) getJobInformer() (batchInformers.JobInformer, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.jobInformer == nil {
+		err := c.withInformerFactory(func(factory informers.SharedInformerFactory) {
+			c.jobInformer = factory.Batch().V1().Jobs()
+			c.jobInformer.Informer()
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return c.jobInformer, nil
+}
+
 func (c *clusterController) getPodInformerWait(wait bool) (v1informers.PodInformer, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
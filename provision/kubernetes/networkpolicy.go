@@ -0,0 +1,227 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/tsuru/tsuru/provision"
+	apiv1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const tsuruServicesEnvVar = "TSURU_SERVICES"
+
+func (c *ClusterClient) networkPolicyEnabled(pool string) bool {
+	enabled, _ := strconv.ParseBool(c.configForContext(pool, enableNetworkPolicyKey))
+	return enabled
+}
+
+// UpdateServiceEnvs updates the network policy of the app, so the egress to
+// the addresses of its service instances follows binds and unbinds that
+// don't restart the app.
+func (p *kubernetesProvisioner) UpdateServiceEnvs(ctx context.Context, a provision.App) error {
+	client, err := clusterForPool(ctx, a.GetPool())
+	if err != nil {
+		return err
+	}
+	if !client.networkPolicyEnabled(a.GetPool()) {
+		return nil
+	}
+	return errors.Wrap(ensureNetworkPolicy(ctx, client, a), "unable to ensure network policy")
+}
+
+// ensureNetworkPolicy keeps the NetworkPolicy isolating the units of the app
+// in sync, or removes it when network policies are disabled for its pool. The
+// removal ignores clusters where tsuru isn't allowed to manage network
+// policies, as they were never enabled there.
+func ensureNetworkPolicy(ctx context.Context, client *ClusterClient, a provision.App) error {
+	ns, err := client.AppNamespace(ctx, a)
+	if err != nil {
+		return err
+	}
+	name := provision.ValidKubeName(a.GetName())
+	if !client.networkPolicyEnabled(a.GetPool()) {
+		err = client.NetworkingV1().NetworkPolicies(ns).Delete(ctx, name, metav1.DeleteOptions{})
+		if err != nil && !k8sErrors.IsNotFound(err) && !k8sErrors.IsForbidden(err) {
+			return errors.WithStack(err)
+		}
+		return nil
+	}
+	policy, err := newNetworkPolicy(client, a, ns)
+	if err != nil {
+		return err
+	}
+	existing, err := client.NetworkingV1().NetworkPolicies(ns).Get(ctx, policy.Name, metav1.GetOptions{})
+	if k8sErrors.IsNotFound(err) {
+		_, err = client.NetworkingV1().NetworkPolicies(ns).Create(ctx, policy, metav1.CreateOptions{})
+		return errors.WithStack(err)
+	}
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if apiequality.Semantic.DeepEqual(existing.Spec, policy.Spec) {
+		return nil
+	}
+	existing.Spec = policy.Spec
+	_, err = client.NetworkingV1().NetworkPolicies(ns).Update(ctx, existing, metav1.UpdateOptions{})
+	return errors.WithStack(err)
+}
+
+// newNetworkPolicy denies all the traffic of the units of the app except:
+// between its units, from the routers, to the DNS, to the addresses of its
+// bound service instances and to the CIDRs allowed in the pool.
+func newNetworkPolicy(client *ClusterClient, a provision.App, ns string) (*networkingv1.NetworkPolicy, error) {
+	routerLabels, err := parseLabels(client.configForContext(a.GetPool(), netPolicyRouterLabelsKey))
+	if err != nil {
+		return nil, err
+	}
+	if len(routerLabels) == 0 {
+		return nil, errors.Errorf("unable to create network policy for app %q: cluster %q has no %s config", a.GetName(), client.Name, netPolicyRouterLabelsKey)
+	}
+	appPeer := networkingv1.NetworkPolicyPeer{
+		PodSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{tsuruLabelPrefix + provision.LabelAppName: a.GetName()},
+		},
+	}
+	egress := []networkingv1.NetworkPolicyEgressRule{
+		{To: []networkingv1.NetworkPolicyPeer{appPeer}},
+		{Ports: dnsPorts()},
+	}
+	cidrs := serviceInstanceCIDRs(a)
+	for _, cidr := range strings.Split(client.configForContext(a.GetPool(), networkPolicyEgressCIDRsKey), ",") {
+		cidr = strings.TrimSpace(cidr)
+		if cidr == "" {
+			continue
+		}
+		if _, _, err = net.ParseCIDR(cidr); err != nil {
+			return nil, errors.Wrapf(err, "invalid %s config", networkPolicyEgressCIDRsKey)
+		}
+		cidrs = append(cidrs, cidr)
+	}
+	if len(cidrs) > 0 {
+		var peers []networkingv1.NetworkPolicyPeer
+		for _, cidr := range cidrs {
+			peers = append(peers, networkingv1.NetworkPolicyPeer{IPBlock: &networkingv1.IPBlock{CIDR: cidr}})
+		}
+		egress = append(egress, networkingv1.NetworkPolicyEgressRule{To: peers})
+	}
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      provision.ValidKubeName(a.GetName()),
+			Namespace: ns,
+			Labels: map[string]string{
+				tsuruLabelPrefix + "is-tsuru":                  "true",
+				tsuruLabelPrefix + provision.LabelAppName:      a.GetName(),
+				tsuruLabelPrefix + provision.LabelAppTeamOwner: a.GetTeamOwner(),
+			},
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{
+				MatchLabels: map[string]string{tsuruLabelPrefix + provision.LabelAppName: a.GetName()},
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: tsuruLabelPrefix + provision.LabelIsBuild, Operator: metav1.LabelSelectorOpNotIn, Values: []string{"true"}},
+				},
+			},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress},
+			Ingress: []networkingv1.NetworkPolicyIngressRule{
+				{From: []networkingv1.NetworkPolicyPeer{
+					appPeer,
+					{NamespaceSelector: &metav1.LabelSelector{MatchLabels: routerLabels}},
+				}},
+			},
+			Egress: egress,
+		},
+	}, nil
+}
+
+func dnsPorts() []networkingv1.NetworkPolicyPort {
+	udp, tcp := apiv1.ProtocolUDP, apiv1.ProtocolTCP
+	port := intstr.FromInt(53)
+	return []networkingv1.NetworkPolicyPort{
+		{Protocol: &udp, Port: &port},
+		{Protocol: &tcp, Port: &port},
+	}
+}
+
+// serviceInstanceCIDRs returns the CIDRs of the IP addresses found in the
+// envs of the service instances bound to the app. Addresses given by host
+// names aren't returned, network policies only match IP addresses.
+func serviceInstanceCIDRs(a provision.App) []string {
+	env, ok := a.Envs()[tsuruServicesEnvVar]
+	if !ok || env.Value == "" {
+		return nil
+	}
+	var services map[string][]struct {
+		Envs map[string]string `json:"envs"`
+	}
+	if err := json.Unmarshal([]byte(env.Value), &services); err != nil {
+		return nil
+	}
+	set := map[string]struct{}{}
+	for _, instances := range services {
+		for _, instance := range instances {
+			for _, value := range instance.Envs {
+				for _, addr := range strings.Split(value, ",") {
+					if ip := parseAddressIP(strings.TrimSpace(addr)); ip != nil {
+						set[ipCIDR(ip)] = struct{}{}
+					}
+				}
+			}
+		}
+	}
+	cidrs := make([]string, 0, len(set))
+	for cidr := range set {
+		cidrs = append(cidrs, cidr)
+	}
+	sort.Strings(cidrs)
+	return cidrs
+}
+
+func parseAddressIP(addr string) net.IP {
+	if ip := net.ParseIP(addr); ip != nil {
+		return ip
+	}
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return net.ParseIP(host)
+	}
+	if u, err := url.Parse(addr); err == nil && u.Host != "" {
+		return net.ParseIP(u.Hostname())
+	}
+	return nil
+}
+
+func ipCIDR(ip net.IP) string {
+	if ip.To4() != nil {
+		return ip.String() + "/32"
+	}
+	return ip.String() + "/128"
+}
+
+func parseLabels(raw string) (map[string]string, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	result := map[string]string{}
+	for _, l := range strings.Split(raw, ",") {
+		parts := strings.SplitN(l, "=", 2)
+		if len(parts) != 2 {
+			return nil, errors.Errorf("invalid label %q: must be in the format <label>=<value>", l)
+		}
+		result[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+	return result, nil
+}
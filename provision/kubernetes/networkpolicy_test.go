@@ -0,0 +1,106 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kubernetes
+
+import (
+	"context"
+
+	"github.com/tsuru/tsuru/app/bind"
+	"github.com/tsuru/tsuru/provision/provisiontest"
+	check "gopkg.in/check.v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ktesting "k8s.io/client-go/testing"
+)
+
+func (s *S) TestEnsureNetworkPolicy(c *check.C) {
+	ctx := context.TODO()
+	a := provisiontest.NewFakeAppWithPool("myapp", "python", "test-default", 0)
+	a.SetEnv(bind.EnvVar{
+		Name:  tsuruServicesEnvVar,
+		Value: `{"mysql":[{"instance_name":"db","envs":{"MYSQL_HOST":"10.0.0.5","MYSQL_URL":"mysql://u:p@10.0.0.6:3306/db","MYSQL_NAME":"db.example.com"}}]}`,
+	})
+	s.clusterClient.CustomData[enableNetworkPolicyKey] = "true"
+	s.clusterClient.CustomData[netPolicyRouterLabelsKey] = "role=router"
+	s.clusterClient.CustomData[networkPolicyEgressCIDRsKey] = "192.168.0.0/16"
+	err := ensureNetworkPolicy(ctx, s.clusterClient, a)
+	c.Assert(err, check.IsNil)
+	ns, err := s.client.AppNamespace(ctx, a)
+	c.Assert(err, check.IsNil)
+	policy, err := s.client.NetworkingV1().NetworkPolicies(ns).Get(ctx, "myapp", metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(policy.Spec.PodSelector.MatchLabels, check.DeepEquals, map[string]string{"tsuru.io/app-name": "myapp"})
+	c.Assert(policy.Spec.Ingress, check.HasLen, 1)
+	c.Assert(policy.Spec.Ingress[0].From[1].NamespaceSelector.MatchLabels, check.DeepEquals, map[string]string{"role": "router"})
+	c.Assert(policy.Spec.Egress, check.HasLen, 3)
+	var cidrs []string
+	for _, peer := range policy.Spec.Egress[2].To {
+		cidrs = append(cidrs, peer.IPBlock.CIDR)
+	}
+	c.Assert(cidrs, check.DeepEquals, []string{"10.0.0.5/32", "10.0.0.6/32", "192.168.0.0/16"})
+	delete(s.clusterClient.CustomData, networkPolicyEgressCIDRsKey)
+	err = ensureNetworkPolicy(ctx, s.clusterClient, a)
+	c.Assert(err, check.IsNil)
+	policy, err = s.client.NetworkingV1().NetworkPolicies(ns).Get(ctx, "myapp", metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(policy.Spec.Egress[2].To, check.HasLen, 2)
+	s.clusterClient.CustomData[enableNetworkPolicyKey] = "false"
+	err = ensureNetworkPolicy(ctx, s.clusterClient, a)
+	c.Assert(err, check.IsNil)
+	_, err = s.client.NetworkingV1().NetworkPolicies(ns).Get(ctx, "myapp", metav1.GetOptions{})
+	c.Assert(k8sErrors.IsNotFound(err), check.Equals, true)
+}
+
+func (s *S) TestEnsureNetworkPolicyWithoutRouterLabels(c *check.C) {
+	a := provisiontest.NewFakeAppWithPool("myapp", "python", "test-default", 0)
+	s.clusterClient.CustomData[enableNetworkPolicyKey] = "true"
+	err := ensureNetworkPolicy(context.TODO(), s.clusterClient, a)
+	c.Assert(err, check.ErrorMatches, `unable to create network policy for app "myapp": cluster "c1" has no network-policy-router-namespace-labels config`)
+}
+
+func (s *S) TestEnsureNetworkPolicyDisabledForbidden(c *check.C) {
+	a := provisiontest.NewFakeAppWithPool("myapp", "python", "test-default", 0)
+	s.client.PrependReactor("delete", "networkpolicies", func(action ktesting.Action) (bool, runtime.Object, error) {
+		return true, nil, k8sErrors.NewForbidden(schema.GroupResource{Group: "networking.k8s.io", Resource: "networkpolicies"}, "myapp", nil)
+	})
+	err := ensureNetworkPolicy(context.TODO(), s.clusterClient, a)
+	c.Assert(err, check.IsNil)
+}
+
+func (s *S) TestUpdateServiceEnvsDisabled(c *check.C) {
+	a := provisiontest.NewFakeAppWithPool("myapp", "python", "test-default", 0)
+	s.client.PrependReactor("*", "networkpolicies", func(action ktesting.Action) (bool, runtime.Object, error) {
+		c.Fatalf("unexpected network policy call: %v", action)
+		return true, nil, nil
+	})
+	err := s.p.UpdateServiceEnvs(context.TODO(), a)
+	c.Assert(err, check.IsNil)
+}
+
+func (s *S) TestUpdateServiceEnvs(c *check.C) {
+	ctx := context.TODO()
+	a := provisiontest.NewFakeAppWithPool("myapp", "python", "test-default", 0)
+	s.clusterClient.CustomData[enableNetworkPolicyKey] = "true"
+	s.clusterClient.CustomData[netPolicyRouterLabelsKey] = "role=router"
+	err := s.p.UpdateServiceEnvs(ctx, a)
+	c.Assert(err, check.IsNil)
+	ns, err := s.client.AppNamespace(ctx, a)
+	c.Assert(err, check.IsNil)
+	policy, err := s.client.NetworkingV1().NetworkPolicies(ns).Get(ctx, "myapp", metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(policy.Spec.Egress, check.HasLen, 2)
+	a.SetEnv(bind.EnvVar{
+		Name:  tsuruServicesEnvVar,
+		Value: `{"mysql":[{"instance_name":"db","envs":{"MYSQL_HOST":"10.0.0.5"}}]}`,
+	})
+	err = s.p.UpdateServiceEnvs(ctx, a)
+	c.Assert(err, check.IsNil)
+	policy, err = s.client.NetworkingV1().NetworkPolicies(ns).Get(ctx, "myapp", metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(policy.Spec.Egress, check.HasLen, 3)
+	c.Assert(policy.Spec.Egress[2].To[0].IPBlock.CIDR, check.Equals, "10.0.0.5/32")
+}
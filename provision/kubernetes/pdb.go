@@ -22,7 +22,7 @@ func ensurePDB(ctx context.Context, client *ClusterClient, app provision.App, pr
 		return err
 	}
 	if pdb == nil {
-		return nil
+		return removePDB(ctx, client, app, process)
 	}
 	existingPDB, err := client.PolicyV1beta1().PodDisruptionBudgets(pdb.Namespace).Get(ctx, pdb.Name, metav1.GetOptions{})
 	if k8sErrors.IsNotFound(err) {
@@ -81,11 +81,49 @@ func removeAllPDBs(ctx context.Context, client *ClusterClient, app provision.App
 	return nil
 }
 
+func removePDB(ctx context.Context, client *ClusterClient, app provision.App, process string) error {
+	ns, err := client.AppNamespace(ctx, app)
+	if err != nil {
+		return err
+	}
+	err = client.PolicyV1beta1().PodDisruptionBudgets(ns).Delete(ctx, pdbNameForApp(app, process), metav1.DeleteOptions{})
+	if err != nil && !k8sErrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// newPDB returns the PodDisruptionBudget of the process, or nil when the
+// process must not have one. When the pool sets the minimum available
+// percentage, it's applied to the units of the process, always allowing one
+// of them to be disrupted so node drains aren't blocked.
 func newPDB(ctx context.Context, client *ClusterClient, app provision.App, process string) (*policyv1beta1.PodDisruptionBudget, error) {
 	if client.disablePDB(app.GetPool()) {
 		return nil, nil
 	}
-	maxUnavailableByProcess := intstr.FromString("10%")
+	spec := policyv1beta1.PodDisruptionBudgetSpec{
+		MaxUnavailable: intOrStringPtr(intstr.FromString("10%")),
+	}
+	minAvailablePercentage, err := client.pdbMinAvailable(app.GetPool())
+	if err != nil {
+		return nil, err
+	}
+	if minAvailablePercentage > 0 {
+		replicas, err := processReplicas(ctx, client, app, process)
+		if err != nil {
+			return nil, err
+		}
+		if replicas < 2 {
+			return nil, nil
+		}
+		minAvailable := (replicas*minAvailablePercentage + 99) / 100
+		if minAvailable > replicas-1 {
+			minAvailable = replicas - 1
+		}
+		spec = policyv1beta1.PodDisruptionBudgetSpec{
+			MinAvailable: intOrStringPtr(intstr.FromInt(minAvailable)),
+		}
+	}
 
 	ns, err := client.AppNamespace(ctx, app)
 	if err != nil {
@@ -93,6 +131,7 @@ func newPDB(ctx context.Context, client *ClusterClient, app provision.App, proce
 	}
 	routableLabels := pdbLabels(app, process)
 	routableLabels.SetIsRoutable()
+	spec.Selector = &metav1.LabelSelector{MatchLabels: routableLabels.ToRoutableSelector()}
 
 	return &policyv1beta1.PodDisruptionBudget{
 		ObjectMeta: metav1.ObjectMeta{
@@ -100,13 +139,42 @@ func newPDB(ctx context.Context, client *ClusterClient, app provision.App, proce
 			Namespace: ns,
 			Labels:    pdbLabels(app, process).ToLabels(),
 		},
-		Spec: policyv1beta1.PodDisruptionBudgetSpec{
-			MaxUnavailable: &maxUnavailableByProcess,
-			Selector:       &metav1.LabelSelector{MatchLabels: routableLabels.ToRoutableSelector()},
-		},
+		Spec: spec,
 	}, nil
 }
 
+// processReplicas returns the number of routable units of the process, or
+// the minimum number of units of its autoscale, as the units of autoscaled
+// processes change without tsuru updating the PodDisruptionBudget.
+func processReplicas(ctx context.Context, client *ClusterClient, app provision.App, process string) (int, error) {
+	autoScaleSpecs, err := getAutoScale(ctx, client, app, process)
+	if err != nil {
+		return 0, err
+	}
+	if len(autoScaleSpecs) > 0 {
+		minUnits := int(autoScaleSpecs[0].MinUnits)
+		for _, spec := range autoScaleSpecs[1:] {
+			if int(spec.MinUnits) < minUnits {
+				minUnits = int(spec.MinUnits)
+			}
+		}
+		return minUnits, nil
+	}
+	groupedDeps, err := deploymentsDataForProcess(ctx, client, app, process)
+	if err != nil {
+		return 0, err
+	}
+	replicas := 0
+	for _, deps := range groupedDeps.versioned {
+		for _, dep := range deps {
+			if dep.isRoutable {
+				replicas += dep.replicas
+			}
+		}
+	}
+	return replicas, nil
+}
+
 func pdbLabels(app provision.App, process string) *provision.LabelSet {
 	return provision.PDBLabels(provision.PDBLabelsOpts{
 		App:         app,
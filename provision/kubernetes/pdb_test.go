@@ -9,7 +9,10 @@ import (
 
 	"github.com/tsuru/tsuru/app"
 	check "gopkg.in/check.v1"
+	appsv1 "k8s.io/api/apps/v1"
+	apiv1 "k8s.io/api/core/v1"
 	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
@@ -70,3 +73,89 @@ func (s *S) TestNewPDB(c *check.C) {
 		}
 	}
 }
+
+func (s *S) TestNewPDBWithMinAvailable(c *check.C) {
+	a := &app.App{Name: "myapp", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), a, s.user)
+	c.Assert(err, check.IsNil)
+	ns, err := s.client.AppNamespace(context.TODO(), a)
+	c.Assert(err, check.IsNil)
+	s.clusterClient.CustomData["test-default:pdb-min-available"] = "75%"
+	defer delete(s.clusterClient.CustomData, "test-default:pdb-min-available")
+	pdb, err := newPDB(context.TODO(), s.clusterClient, a, "p1")
+	c.Assert(err, check.IsNil)
+	c.Assert(pdb, check.IsNil)
+	replicas := int32(1)
+	dep := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "myapp-p1",
+			Namespace: ns,
+			Labels:    map[string]string{"tsuru.io/app-name": "myapp", "tsuru.io/app-process": "p1", "tsuru.io/is-build": "false"},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Template: apiv1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						"tsuru.io/app-name":    "myapp",
+						"tsuru.io/app-process": "p1",
+						"tsuru.io/is-build":    "false",
+						"tsuru.io/app-version": "1",
+						"tsuru.io/is-routable": "true",
+					},
+				},
+			},
+		},
+	}
+	dep, err = s.client.AppsV1().Deployments(ns).Create(context.TODO(), dep, metav1.CreateOptions{})
+	c.Assert(err, check.IsNil)
+	tests := []struct {
+		replicas     int32
+		percentage   string
+		minAvailable *intstr.IntOrString
+	}{
+		{replicas: 1, percentage: "75%"},
+		{replicas: 4, percentage: "75%", minAvailable: intOrStringPtr(intstr.FromInt(3))},
+		{replicas: 10, percentage: "75%", minAvailable: intOrStringPtr(intstr.FromInt(8))},
+		{replicas: 2, percentage: "100%", minAvailable: intOrStringPtr(intstr.FromInt(1))},
+		{replicas: 3, percentage: "50", minAvailable: intOrStringPtr(intstr.FromInt(2))},
+	}
+	for _, tt := range tests {
+		s.clusterClient.CustomData["test-default:pdb-min-available"] = tt.percentage
+		*dep.Spec.Replicas = tt.replicas
+		dep, err = s.client.AppsV1().Deployments(ns).Update(context.TODO(), dep, metav1.UpdateOptions{})
+		c.Assert(err, check.IsNil)
+		pdb, err = newPDB(context.TODO(), s.clusterClient, a, "p1")
+		c.Assert(err, check.IsNil)
+		if tt.minAvailable == nil {
+			c.Check(pdb, check.IsNil)
+			continue
+		}
+		c.Assert(pdb, check.NotNil)
+		c.Check(pdb.Spec.MinAvailable, check.DeepEquals, tt.minAvailable, check.Commentf("replicas %d, %s", tt.replicas, tt.percentage))
+		c.Check(pdb.Spec.MaxUnavailable, check.IsNil)
+	}
+	s.clusterClient.CustomData["test-default:pdb-min-available"] = "120%"
+	_, err = newPDB(context.TODO(), s.clusterClient, a, "p1")
+	c.Assert(err, check.ErrorMatches, `invalid pdb-min-available config "120%": must be a percentage between 0% and 100%`)
+}
+
+func (s *S) TestEnsurePDBRemovesPDBWhenNotNeeded(c *check.C) {
+	a := &app.App{Name: "myapp", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), a, s.user)
+	c.Assert(err, check.IsNil)
+	ns, err := s.client.AppNamespace(context.TODO(), a)
+	c.Assert(err, check.IsNil)
+	err = ensurePDB(context.TODO(), s.clusterClient, a, "p1")
+	c.Assert(err, check.IsNil)
+	_, err = s.client.PolicyV1beta1().PodDisruptionBudgets(ns).Get(context.TODO(), "myapp-p1", metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	s.clusterClient.CustomData["test-default:pdb-min-available"] = "50%"
+	defer delete(s.clusterClient.CustomData, "test-default:pdb-min-available")
+	err = ensurePDB(context.TODO(), s.clusterClient, a, "p1")
+	c.Assert(err, check.IsNil)
+	_, err = s.client.PolicyV1beta1().PodDisruptionBudgets(ns).Get(context.TODO(), "myapp-p1", metav1.GetOptions{})
+	c.Assert(k8sErrors.IsNotFound(err), check.Equals, true)
+	err = ensurePDB(context.TODO(), s.clusterClient, a, "p1")
+	c.Assert(err, check.IsNil)
+}
@@ -0,0 +1,69 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kubernetes
+
+import (
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// applyPodTemplate merges the pod template configured for the pool in the
+// cluster into an app pod. Values set by tsuru are kept when both define the
+// same label, annotation, node selector or env.
+func applyPodTemplate(client *ClusterClient, pool string, meta *metav1.ObjectMeta, spec *apiv1.PodSpec) error {
+	template, err := client.podTemplate(pool)
+	if err != nil || template == nil {
+		return err
+	}
+	meta.Labels = mergeMissing(meta.Labels, template.Labels)
+	meta.Annotations = mergeMissing(meta.Annotations, template.Annotations)
+	spec.NodeSelector = mergeMissing(spec.NodeSelector, template.Spec.NodeSelector)
+	if spec.RuntimeClassName == nil {
+		spec.RuntimeClassName = template.Spec.RuntimeClassName
+	}
+	var envs []apiv1.EnvVar
+	for _, c := range template.Spec.Containers {
+		envs = append(envs, c.Env...)
+	}
+	for i := range spec.Containers {
+		spec.Containers[i].Env = mergeMissingEnvs(spec.Containers[i].Env, envs)
+	}
+	return nil
+}
+
+// mergeMissing returns a copy of dst with the keys of src it doesn't have,
+// dst itself may be shared with other objects and is never changed.
+func mergeMissing(dst, src map[string]string) map[string]string {
+	if len(src) == 0 {
+		return dst
+	}
+	result := make(map[string]string, len(dst)+len(src))
+	for k, v := range src {
+		result[k] = v
+	}
+	for k, v := range dst {
+		result[k] = v
+	}
+	return result
+}
+
+func mergeMissingEnvs(dst, src []apiv1.EnvVar) []apiv1.EnvVar {
+	if len(src) == 0 {
+		return dst
+	}
+	defined := make(map[string]struct{}, len(dst))
+	for _, env := range dst {
+		defined[env.Name] = struct{}{}
+	}
+	result := append([]apiv1.EnvVar{}, dst...)
+	for _, env := range src {
+		if _, ok := defined[env.Name]; ok {
+			continue
+		}
+		defined[env.Name] = struct{}{}
+		result = append(result, env)
+	}
+	return result
+}
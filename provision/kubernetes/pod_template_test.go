@@ -0,0 +1,111 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kubernetes
+
+import (
+	provTypes "github.com/tsuru/tsuru/types/provision"
+	check "gopkg.in/check.v1"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func (s *S) TestClusterPodTemplate(c *check.C) {
+	c1, err := NewClusterClient(&provTypes.Cluster{Addresses: []string{"addr1"}})
+	c.Assert(err, check.IsNil)
+	template, err := c1.podTemplate("mypool")
+	c.Assert(err, check.IsNil)
+	c.Assert(template, check.IsNil)
+	c2, err := NewClusterClient(&provTypes.Cluster{Addresses: []string{"addr1"}, CustomData: map[string]string{
+		"pod-template":        `{"spec": {"runtimeClassName": "gvisor"}}`,
+		"mypool:pod-template": "spec:\n  runtimeClassName: kata\n",
+	}})
+	c.Assert(err, check.IsNil)
+	template, err = c2.podTemplate("mypool")
+	c.Assert(err, check.IsNil)
+	c.Assert(*template.Spec.RuntimeClassName, check.Equals, "kata")
+	template, err = c2.podTemplate("otherpool")
+	c.Assert(err, check.IsNil)
+	c.Assert(*template.Spec.RuntimeClassName, check.Equals, "gvisor")
+	c3, err := NewClusterClient(&provTypes.Cluster{Addresses: []string{"addr1"}, CustomData: map[string]string{
+		"pod-template": `{"spec": []}`,
+	}})
+	c.Assert(err, check.IsNil)
+	_, err = c3.podTemplate("mypool")
+	c.Assert(err, check.ErrorMatches, "invalid pod-template config: .*")
+}
+
+func (s *S) TestApplyPodTemplate(c *check.C) {
+	client, err := NewClusterClient(&provTypes.Cluster{Addresses: []string{"addr1"}, CustomData: map[string]string{
+		"mypool:pod-template": `
+metadata:
+  labels:
+    team-cost-center: "42"
+    tsuru.io/app-name: other
+  annotations:
+    example.com/scrape: "true"
+spec:
+  runtimeClassName: gvisor
+  nodeSelector:
+    example.com/zone: a
+    tsuru.io/pool: other
+  containers:
+  - env:
+    - name: NODE_NAME
+      valueFrom:
+        fieldRef:
+          fieldPath: spec.nodeName
+    - name: PORT
+      value: "1234"
+`,
+	}})
+	c.Assert(err, check.IsNil)
+	annotations := map[string]string{"tsuru.io/build-image": "img"}
+	meta := metav1.ObjectMeta{
+		Labels:      map[string]string{"tsuru.io/app-name": "myapp"},
+		Annotations: annotations,
+	}
+	spec := apiv1.PodSpec{
+		NodeSelector: map[string]string{"tsuru.io/pool": "mypool"},
+		Containers: []apiv1.Container{
+			{Name: "c1", Env: []apiv1.EnvVar{{Name: "PORT", Value: "8888"}}},
+			{Name: "c2"},
+		},
+	}
+	err = applyPodTemplate(client, "mypool", &meta, &spec)
+	c.Assert(err, check.IsNil)
+	c.Assert(meta.Labels, check.DeepEquals, map[string]string{
+		"tsuru.io/app-name": "myapp",
+		"team-cost-center":  "42",
+	})
+	c.Assert(meta.Annotations, check.DeepEquals, map[string]string{
+		"tsuru.io/build-image": "img",
+		"example.com/scrape":   "true",
+	})
+	c.Assert(annotations, check.DeepEquals, map[string]string{"tsuru.io/build-image": "img"})
+	c.Assert(spec.NodeSelector, check.DeepEquals, map[string]string{
+		"tsuru.io/pool":    "mypool",
+		"example.com/zone": "a",
+	})
+	c.Assert(*spec.RuntimeClassName, check.Equals, "gvisor")
+	nodeName := apiv1.EnvVar{
+		Name:      "NODE_NAME",
+		ValueFrom: &apiv1.EnvVarSource{FieldRef: &apiv1.ObjectFieldSelector{FieldPath: "spec.nodeName"}},
+	}
+	c.Assert(spec.Containers[0].Env, check.DeepEquals, []apiv1.EnvVar{{Name: "PORT", Value: "8888"}, nodeName})
+	c.Assert(spec.Containers[1].Env, check.DeepEquals, []apiv1.EnvVar{nodeName, {Name: "PORT", Value: "1234"}})
+}
+
+func (s *S) TestApplyPodTemplateNotConfigured(c *check.C) {
+	client, err := NewClusterClient(&provTypes.Cluster{Addresses: []string{"addr1"}, CustomData: map[string]string{
+		"otherpool:pod-template": `{"spec": {"runtimeClassName": "gvisor"}}`,
+	}})
+	c.Assert(err, check.IsNil)
+	meta := metav1.ObjectMeta{Labels: map[string]string{"a": "b"}}
+	spec := apiv1.PodSpec{Containers: []apiv1.Container{{Name: "c1"}}}
+	err = applyPodTemplate(client, "mypool", &meta, &spec)
+	c.Assert(err, check.IsNil)
+	c.Assert(meta, check.DeepEquals, metav1.ObjectMeta{Labels: map[string]string{"a": "b"}})
+	c.Assert(spec, check.DeepEquals, apiv1.PodSpec{Containers: []apiv1.Container{{Name: "c1"}}})
+}
@@ -66,7 +66,9 @@ const (
 	defaultDeploymentProgressTimeout           = 10 * time.Minute
 	defaultAttachTimeoutAfterContainerFinished = time.Minute
 	defaultSidecarImageName                    = "tsuru/deploy-agent:0.10.2"
+	defaultAutoScaleSchedulerImage             = "bitnami/kubectl:1.20"
 	defaultPreStopSleepSeconds                 = 10
+	clusterHealthCheckTimeout                  = 5 * time.Second
 )
 
 var (
@@ -85,25 +87,33 @@ type kubernetesProvisioner struct {
 }
 
 var (
-	_ provision.Provisioner              = &kubernetesProvisioner{}
-	_ provision.NodeProvisioner          = &kubernetesProvisioner{}
-	_ provision.NodeContainerProvisioner = &kubernetesProvisioner{}
-	_ provision.MessageProvisioner       = &kubernetesProvisioner{}
-	_ provision.SleepableProvisioner     = &kubernetesProvisioner{}
-	_ provision.VolumeProvisioner        = &kubernetesProvisioner{}
-	_ provision.BuilderDeploy            = &kubernetesProvisioner{}
-	_ provision.BuilderDeployKubeClient  = &kubernetesProvisioner{}
-	_ provision.InitializableProvisioner = &kubernetesProvisioner{}
-	_ provision.InterAppProvisioner      = &kubernetesProvisioner{}
-	_ provision.HCProvisioner            = &kubernetesProvisioner{}
-	_ provision.VersionsProvisioner      = &kubernetesProvisioner{}
-	_ provision.LogsProvisioner          = &kubernetesProvisioner{}
-	_ provision.MetricsProvisioner       = &kubernetesProvisioner{}
-	_ provision.AutoScaleProvisioner     = &kubernetesProvisioner{}
-	_ cluster.ClusteredProvisioner       = &kubernetesProvisioner{}
-	_ provision.UpdatableProvisioner     = &kubernetesProvisioner{}
-	_ provision.MultiRegistryProvisioner = &kubernetesProvisioner{}
-	_ provision.KillUnitProvisioner      = &kubernetesProvisioner{}
+	_ provision.Provisioner                 = &kubernetesProvisioner{}
+	_ provision.NodeProvisioner             = &kubernetesProvisioner{}
+	_ provision.NodeContainerProvisioner    = &kubernetesProvisioner{}
+	_ provision.MessageProvisioner          = &kubernetesProvisioner{}
+	_ provision.SleepableProvisioner        = &kubernetesProvisioner{}
+	_ provision.VolumeProvisioner           = &kubernetesProvisioner{}
+	_ provision.JobProvisioner              = &kubernetesProvisioner{}
+	_ provision.BuilderDeploy               = &kubernetesProvisioner{}
+	_ provision.BuilderDeployKubeClient     = &kubernetesProvisioner{}
+	_ provision.InitializableProvisioner    = &kubernetesProvisioner{}
+	_ provision.InterAppProvisioner         = &kubernetesProvisioner{}
+	_ provision.HCProvisioner               = &kubernetesProvisioner{}
+	_ provision.VersionsProvisioner         = &kubernetesProvisioner{}
+	_ provision.LogsProvisioner             = &kubernetesProvisioner{}
+	_ provision.MetricsProvisioner          = &kubernetesProvisioner{}
+	_ provision.AutoScaleProvisioner        = &kubernetesProvisioner{}
+	_ cluster.ClusteredProvisioner          = &kubernetesProvisioner{}
+	_ cluster.ClusterHealthChecker          = &kubernetesProvisioner{}
+	_ cluster.ClusterStatusChecker          = &kubernetesProvisioner{}
+	_ provision.UpdatableProvisioner        = &kubernetesProvisioner{}
+	_ provision.MultiRegistryProvisioner    = &kubernetesProvisioner{}
+	_ provision.KillUnitProvisioner         = &kubernetesProvisioner{}
+	_ provision.RestartUnitProvisioner      = &kubernetesProvisioner{}
+	_ provision.DebugUnitProvisioner        = &kubernetesProvisioner{}
+	_ provision.ServiceEnvsProvisioner      = &kubernetesProvisioner{}
+	_ provision.PoolResourcesProvisioner    = &kubernetesProvisioner{}
+	_ provision.ClusterResourcesProvisioner = &kubernetesProvisioner{}
 
 	mainKubernetesProvisioner *kubernetesProvisioner
 )
@@ -126,7 +136,10 @@ type kubernetesConfig struct {
 	LogLevel           int
 	deploySidecarImage string
 	deployInspectImage string
-	APITimeout         time.Duration
+	// autoScaleSchedulerImage is the image, containing kubectl, used by the
+	// cron jobs changing the autoscale limits of apps with schedules.
+	autoScaleSchedulerImage string
+	APITimeout              time.Duration
 	// PodReadyTimeout is the timeout for a pod to become ready after already
 	// running.
 	PodReadyTimeout time.Duration
@@ -158,6 +171,10 @@ func getKubeConfig() kubernetesConfig {
 	if conf.deployInspectImage == "" {
 		conf.deployInspectImage = defaultSidecarImageName
 	}
+	conf.autoScaleSchedulerImage, _ = config.GetString("kubernetes:autoscale-scheduler-image")
+	if conf.autoScaleSchedulerImage == "" {
+		conf.autoScaleSchedulerImage = defaultAutoScaleSchedulerImage
+	}
 	apiTimeout, _ := config.GetFloat("kubernetes:api-timeout")
 	if apiTimeout != 0 {
 		conf.APITimeout = time.Duration(apiTimeout * float64(time.Second))
@@ -323,6 +340,19 @@ func (p *kubernetesProvisioner) DeleteCluster(ctx context.Context, c *provTypes.
 	return nil
 }
 
+func (p *kubernetesProvisioner) HealthCheckCluster(ctx context.Context, c *provTypes.Cluster) error {
+	clusterClient, err := NewClusterClient(c)
+	if err != nil {
+		return err
+	}
+	err = clusterClient.SetTimeout(clusterHealthCheckTimeout)
+	if err != nil {
+		return err
+	}
+	_, err = clusterClient.Discovery().ServerVersion()
+	return err
+}
+
 func (p *kubernetesProvisioner) GetName() string {
 	return provisionerName
 }
@@ -354,6 +384,34 @@ func (p *kubernetesProvisioner) Destroy(ctx context.Context, a provision.App) er
 	return tclient.TsuruV1().Apps(client.Namespace()).Delete(ctx, a.GetName(), metav1.DeleteOptions{})
 }
 
+// RemoveClusterResources removes the app from the cluster, which must not be
+// the one serving the pool of the app anymore.
+func (p *kubernetesProvisioner) RemoveClusterResources(ctx context.Context, a provision.App, clusterName string) error {
+	clust, err := servicemanager.Cluster.FindByName(ctx, clusterName)
+	if err != nil {
+		return err
+	}
+	client, err := NewClusterClient(clust)
+	if err != nil {
+		return err
+	}
+	tclient, err := TsuruClientForConfig(client.restConfig)
+	if err != nil {
+		return err
+	}
+	app, err := tclient.TsuruV1().Apps(client.Namespace()).Get(ctx, a.GetName(), metav1.GetOptions{})
+	if err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if err := p.removeResources(ctx, client, app, a); err != nil {
+		return err
+	}
+	return tclient.TsuruV1().Apps(client.Namespace()).Delete(ctx, a.GetName(), metav1.DeleteOptions{})
+}
+
 func (p *kubernetesProvisioner) DestroyVersion(ctx context.Context, a provision.App, version appTypes.AppVersion) error {
 	client, err := clusterForPool(ctx, a.GetPool())
 	if err != nil {
@@ -487,10 +545,18 @@ func (p *kubernetesProvisioner) removeResources(ctx context.Context, client *Clu
 	if err = removeAllPDBs(ctx, client, app); err != nil {
 		multiErrors.Add(errors.WithStack(err))
 	}
+	err = client.NetworkingV1().NetworkPolicies(tsuruApp.Spec.NamespaceName).Delete(ctx, provision.ValidKubeName(app.GetName()), metav1.DeleteOptions{})
+	if err != nil && !k8sErrors.IsNotFound(err) {
+		multiErrors.Add(errors.WithStack(err))
+	}
 	err = client.CoreV1().ServiceAccounts(tsuruApp.Spec.NamespaceName).Delete(ctx, tsuruApp.Spec.ServiceAccountName, metav1.DeleteOptions{})
 	if err != nil && !k8sErrors.IsNotFound(err) {
 		multiErrors.Add(errors.WithStack(err))
 	}
+	err = deleteAppEnvsSecret(ctx, client, tsuruApp.Spec.NamespaceName, app)
+	if err != nil {
+		multiErrors.Add(err)
+	}
 	err = p.deleteAllAutoScale(ctx, app)
 	if err != nil {
 		multiErrors.Add(err)
@@ -655,7 +721,11 @@ func changeUnits(ctx context.Context, a provision.App, units int, processName st
 		return err
 	}
 	fmt.Fprintf(w, "---- Patching from %d to %d units ----\n", *dep.Spec.Replicas, newReplicas)
-	return patchDeployment(ctx, client, a, patchType, patch, dep, version, w, processName)
+	err = patchDeployment(ctx, client, a, patchType, patch, dep, version, w, processName)
+	if err != nil {
+		return err
+	}
+	return errors.Wrap(ensurePDB(ctx, client, a, processName), "unable to ensure pod disruption budget")
 }
 
 func replicasPatch(replicas int, process string) (types.PatchType, []byte, error) {
@@ -1498,6 +1568,10 @@ func (p *kubernetesProvisioner) Deploy(ctx context.Context, args provision.Deplo
 	if err != nil {
 		return "", errors.WithStack(err)
 	}
+	err = runPostDeployHooks(ctx, client, args.App, args.Version, args.Event)
+	if err != nil {
+		return "", err
+	}
 	err = ensureAppCustomResourceSynced(ctx, client, args.App)
 	if err != nil {
 		return "", err
@@ -1581,13 +1655,21 @@ func runIsolatedCmdPod(ctx context.Context, client *ClusterClient, opts execOpts
 		}
 		opts.image = version.VersionInfo().DeployImage
 	}
+	err = ensureAppEnvsSecret(ctx, client, opts.app)
+	if err != nil {
+		return err
+	}
 	appEnvs := provision.EnvsForApp(opts.app, "", false, version)
 	var envs []apiv1.EnvVar
 	for _, envData := range appEnvs {
+		if secretEnv, ok := secretEnvVar(opts.app, envData); ok {
+			envs = append(envs, secretEnv)
+			continue
+		}
 		envs = append(envs, apiv1.EnvVar{Name: envData.Name, Value: envData.Value})
 	}
 
-	requirements, err := appResourceRequirements(opts.app, client, requirementsFactors{
+	requirements, err := appResourceRequirements(opts.app, "", client, requirementsFactors{
 		overCommit: 1,
 	})
 	if err != nil {
@@ -1661,7 +1743,7 @@ func (p *kubernetesProvisioner) ValidateVolume(ctx context.Context, vol *volumeT
 }
 
 func (p *kubernetesProvisioner) UpdateApp(ctx context.Context, old, new provision.App, w io.Writer) error {
-	if old.GetPool() == new.GetPool() {
+	if old.GetPool() == new.GetPool() && old.GetTeamOwner() == new.GetTeamOwner() {
 		return nil
 	}
 	client, err := clusterForPool(ctx, old.GetPool())
@@ -1673,7 +1755,15 @@ func (p *kubernetesProvisioner) UpdateApp(ctx context.Context, old, new provisio
 		return err
 	}
 	sameCluster := client.GetCluster().Name == newClient.GetCluster().Name
-	sameNamespace := client.PoolNamespace(old.GetPool()) == client.PoolNamespace(new.GetPool())
+	oldNamespace := client.namespaceForApp(old)
+	if sameCluster {
+		// apps created before changing the namespaces mode keep their
+		// namespace, the one in their custom resource, until moved.
+		if ns, nsErr := client.AppNamespace(ctx, old); nsErr == nil {
+			oldNamespace = ns
+		}
+	}
+	sameNamespace := oldNamespace == client.namespaceForApp(new)
 	if sameCluster && !sameNamespace {
 		var volumes []volumeTypes.Volume
 		volumes, err = servicemanager.Volume.ListByApp(ctx, old.GetName())
@@ -1690,11 +1780,12 @@ func (p *kubernetesProvisioner) UpdateApp(ctx context.Context, old, new provisio
 	}
 
 	params := updatePipelineParams{
-		old:      old,
-		new:      new,
-		w:        w,
-		p:        p,
-		versions: versions,
+		old:          old,
+		new:          new,
+		oldNamespace: oldNamespace,
+		w:            w,
+		p:            p,
+		versions:     versions,
 	}
 	if !sameCluster {
 		if len(versions) > 1 {
@@ -1709,7 +1800,7 @@ func (p *kubernetesProvisioner) UpdateApp(ctx context.Context, old, new provisio
 
 		return action.NewPipeline(actions...).Execute(ctx, params)
 	}
-	// same cluster and it is not configured with per-pool-namespace, nothing to do.
+	// same cluster and namespace, nothing to do.
 	if sameNamespace {
 		return nil
 	}
@@ -1841,7 +1932,7 @@ func ensureAppCustomResource(ctx context.Context, client *ClusterClient, a provi
 	}
 	_, err = tclient.TsuruV1().Apps(client.Namespace()).Create(ctx, &tsuruv1.App{
 		ObjectMeta: metav1.ObjectMeta{Name: a.GetName()},
-		Spec:       tsuruv1.AppSpec{NamespaceName: client.PoolNamespace(a.GetPool())},
+		Spec:       tsuruv1.AppSpec{NamespaceName: client.namespaceForApp(a)},
 	}, metav1.CreateOptions{})
 	return err
 }
@@ -1521,6 +1521,46 @@ func (s *S) TestDeploy(c *check.C) {
 	})
 }
 
+func (s *S) TestDeployWithPostDeployHooks(c *check.C) {
+	a, wait, rollback := s.mock.DefaultReactions(c)
+	defer rollback()
+	evt, err := event.New(&event.Opts{
+		Target:  event.Target{Type: event.TargetTypeApp, Value: a.GetName()},
+		Kind:    permission.PermAppDeploy,
+		Owner:   s.token,
+		Allowed: event.Allowed(permission.PermAppDeploy),
+	})
+	c.Assert(err, check.IsNil)
+	var isolatedCmds []string
+	s.client.PrependReactor("create", "pods", func(action ktesting.Action) (bool, runtime.Object, error) {
+		pod := action.(ktesting.CreateAction).GetObject().(*apiv1.Pod)
+		if pod.Name == "myapp-isolated-run" {
+			isolatedCmds = pod.Spec.Containers[0].Command
+		}
+		return false, nil, nil
+	})
+	customData := map[string]interface{}{
+		"processes": map[string]interface{}{
+			"web": "run mycmd arg1",
+		},
+		"hooks": provTypes.TsuruYamlHooks{
+			Processes: map[string]provTypes.TsuruYamlProcessHooks{
+				"web": {PostDeploy: []string{"migrate", "warmup"}},
+			},
+		},
+	}
+	version := newCommittedVersion(c, a, customData)
+	_, err = s.p.Deploy(context.TODO(), provision.DeployArgs{App: a, Version: version, Event: evt})
+	c.Assert(err, check.IsNil, check.Commentf("%+v", err))
+	wait()
+	c.Assert(isolatedCmds, check.DeepEquals, []string{
+		"/bin/sh",
+		"-lc",
+		"[ -d /home/application/current ] && cd /home/application/current; migrate && warmup",
+	})
+	c.Assert(evt.Log(), check.Matches, `(?s).*---> Running post-deploy hooks for process "web".*`)
+}
+
 func (s *S) TestDeployWithDisabledUnitRegister(c *check.C) {
 	s.clusterClient.CustomData[disableUnitRegisterCmdKey] = "true"
 	a, wait, rollback := s.mock.DefaultReactions(c)
@@ -65,15 +65,15 @@ func burstValue(v int64, burst float64) int64 {
 	return int64(float64(v) * burst)
 }
 
-func appResourceRequirements(app provision.App, client *ClusterClient, factors requirementsFactors) (apiv1.ResourceRequirements, error) {
+func appResourceRequirements(app provision.App, process string, client *ClusterClient, factors requirementsFactors) (apiv1.ResourceRequirements, error) {
 	resourceLimits := apiv1.ResourceList{}
 	resourceRequests := apiv1.ResourceList{}
-	memory := app.GetMemory()
+	memory := app.GetProcessMemory(process)
 	if memory != 0 {
 		resourceLimits[apiv1.ResourceMemory] = factors.memoryLimits(memory)
 		resourceRequests[apiv1.ResourceMemory] = factors.memoryRequests(memory)
 	}
-	cpuMilli := int64(app.GetMilliCPU())
+	cpuMilli := int64(app.GetProcessMilliCPU(process))
 	if cpuMilli != 0 {
 		resourceLimits[apiv1.ResourceCPU] = factors.cpuLimits(cpuMilli)
 		resourceRequests[apiv1.ResourceCPU] = factors.cpuRequests(cpuMilli)
@@ -2,6 +2,7 @@ package kubernetes
 
 import (
 	"github.com/tsuru/tsuru/provision/provisiontest"
+	appTypes "github.com/tsuru/tsuru/types/app"
 	provTypes "github.com/tsuru/tsuru/types/provision"
 	check "gopkg.in/check.v1"
 )
@@ -89,7 +90,7 @@ func (s *S) TestGetAppResourceRequirements(c *check.C) {
 	}
 
 	for _, testCase := range testsCases {
-		requirements, err := appResourceRequirements(a, clusterClient, testCase.factors)
+		requirements, err := appResourceRequirements(a, "web", clusterClient, testCase.factors)
 		c.Assert(err, check.IsNil)
 
 		memoryLimits := requirements.Limits["memory"]
@@ -105,3 +106,27 @@ func (s *S) TestGetAppResourceRequirements(c *check.C) {
 		c.Assert(cpuRequests.String(), check.Equals, testCase.expectedRequestsCPU)
 	}
 }
+
+func (s *S) TestGetAppResourceRequirementsProcessPlan(c *check.C) {
+	a := provisiontest.NewFakeApp("myapp", "plat", 1)
+	a.Memory = 10 * 1024
+	a.MilliCPU = 1000
+	a.ProcessPlans = map[string]appTypes.Plan{
+		"worker": {Name: "small", Memory: 2 * 1024, CPUMilli: 250},
+	}
+	clusterClient := &ClusterClient{
+		Cluster: &provTypes.Cluster{},
+	}
+	requirements, err := appResourceRequirements(a, "worker", clusterClient, requirementsFactors{overCommit: 1})
+	c.Assert(err, check.IsNil)
+	memoryLimits := requirements.Limits["memory"]
+	c.Assert(memoryLimits.String(), check.Equals, "2Ki")
+	cpuLimits := requirements.Limits["cpu"]
+	c.Assert(cpuLimits.String(), check.Equals, "250m")
+	requirements, err = appResourceRequirements(a, "web", clusterClient, requirementsFactors{overCommit: 1})
+	c.Assert(err, check.IsNil)
+	memoryLimits = requirements.Limits["memory"]
+	c.Assert(memoryLimits.String(), check.Equals, "10Ki")
+	cpuLimits = requirements.Limits["cpu"]
+	c.Assert(cpuLimits.String(), check.Equals, "1")
+}
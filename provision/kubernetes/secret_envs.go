@@ -0,0 +1,86 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kubernetes
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/tsuru/tsuru/app/bind"
+	"github.com/tsuru/tsuru/app/secretref"
+	"github.com/tsuru/tsuru/provision"
+	apiv1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ensureAppEnvsSecret resolves the app env vars marked as references to
+// secrets in external secret managers and stores their values in a kubernetes secret,
+// referenced by the app containers. The secret is removed when no env var
+// references external secrets.
+func ensureAppEnvsSecret(ctx context.Context, client *ClusterClient, a provision.App) error {
+	data := map[string][]byte{}
+	scope := secretref.Scope{Team: a.GetTeamOwner(), Pool: a.GetPool()}
+	for _, env := range a.Envs() {
+		if !env.SecretRef {
+			continue
+		}
+		value, err := secretref.Resolve(ctx, env.Value, scope)
+		if err != nil {
+			return errors.Wrapf(err, "unable to resolve env %q", env.Name)
+		}
+		data[env.Name] = []byte(value)
+	}
+	ns, err := client.AppNamespace(ctx, a)
+	if err != nil {
+		return err
+	}
+	secretName := appEnvsSecretName(a)
+	if len(data) == 0 {
+		return deleteAppEnvsSecret(ctx, client, ns, a)
+	}
+	secret := &apiv1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secretName,
+			Namespace: ns,
+			Labels: map[string]string{
+				tsuruLabelPrefix + provision.LabelAppName: a.GetName(),
+			},
+		},
+		Type: apiv1.SecretTypeOpaque,
+		Data: data,
+	}
+	_, err = client.CoreV1().Secrets(ns).Update(ctx, secret, metav1.UpdateOptions{})
+	if err != nil && k8sErrors.IsNotFound(err) {
+		_, err = client.CoreV1().Secrets(ns).Create(ctx, secret, metav1.CreateOptions{})
+	}
+	return errors.WithStack(err)
+}
+
+func deleteAppEnvsSecret(ctx context.Context, client *ClusterClient, ns string, a provision.App) error {
+	err := client.CoreV1().Secrets(ns).Delete(ctx, appEnvsSecretName(a), metav1.DeleteOptions{})
+	if err != nil && !k8sErrors.IsNotFound(err) {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// secretEnvVar returns the container env var reading the value of env vars
+// referencing external secrets from the secret created by
+// ensureAppEnvsSecret.
+func secretEnvVar(a provision.App, env bind.EnvVar) (apiv1.EnvVar, bool) {
+	if !env.SecretRef {
+		return apiv1.EnvVar{}, false
+	}
+	return apiv1.EnvVar{
+		Name: env.Name,
+		ValueFrom: &apiv1.EnvVarSource{
+			SecretKeyRef: &apiv1.SecretKeySelector{
+				LocalObjectReference: apiv1.LocalObjectReference{Name: appEnvsSecretName(a)},
+				Key:                  env.Name,
+			},
+		},
+	}, true
+}
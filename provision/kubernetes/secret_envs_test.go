@@ -0,0 +1,63 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kubernetes
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/tsuru/config"
+	"github.com/tsuru/tsuru/app"
+	"github.com/tsuru/tsuru/app/bind"
+	check "gopkg.in/check.v1"
+	apiv1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func (s *S) TestEnsureAppEnvsSecret(c *check.C) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.URL.Path, check.Equals, "/v1/secret/"+s.team.Name+"/db")
+		w.Write([]byte(`{"data": {"password": "s3cr3t"}}`))
+	}))
+	defer srv.Close()
+	config.Set("secret-managers:vault:address", srv.URL)
+	config.Set("secret-managers:vault:token", "t0k3n")
+	config.Set("secret-managers:vault:path-prefix", "secret/{team}")
+	defer config.Unset("secret-managers")
+	a := &app.App{Name: "myapp", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), a, s.user)
+	c.Assert(err, check.IsNil)
+	err = a.SetEnvs(bind.SetEnvArgs{Envs: []bind.EnvVar{
+		{Name: "DB_PASS", Value: "vault:secret/" + s.team.Name + "/db#password", SecretRef: true},
+		{Name: "DB_USER", Value: "admin"},
+		{Name: "LEGACY", Value: "vault:not-a-reference"},
+	}})
+	c.Assert(err, check.IsNil)
+	err = ensureAppEnvsSecret(context.TODO(), s.clusterClient, a)
+	c.Assert(err, check.IsNil)
+	secret, err := s.client.CoreV1().Secrets("default").Get(context.TODO(), "app-myapp-envs", metav1.GetOptions{})
+	c.Assert(err, check.IsNil)
+	c.Assert(secret.Data, check.DeepEquals, map[string][]byte{"DB_PASS": []byte("s3cr3t")})
+	envs := appEnvs(a, "web", nil, false)
+	c.Assert(envs[0], check.DeepEquals, apiv1.EnvVar{
+		Name: "DB_PASS",
+		ValueFrom: &apiv1.EnvVarSource{
+			SecretKeyRef: &apiv1.SecretKeySelector{
+				LocalObjectReference: apiv1.LocalObjectReference{Name: "app-myapp-envs"},
+				Key:                  "DB_PASS",
+			},
+		},
+	})
+	c.Assert(envs[1], check.DeepEquals, apiv1.EnvVar{Name: "DB_USER", Value: "admin"})
+	c.Assert(envs[2], check.DeepEquals, apiv1.EnvVar{Name: "LEGACY", Value: "vault:not-a-reference"})
+	err = a.UnsetEnvs(bind.UnsetEnvArgs{VariableNames: []string{"DB_PASS"}})
+	c.Assert(err, check.IsNil)
+	err = ensureAppEnvsSecret(context.TODO(), s.clusterClient, a)
+	c.Assert(err, check.IsNil)
+	_, err = s.client.CoreV1().Secrets("default").Get(context.TODO(), "app-myapp-envs", metav1.GetOptions{})
+	c.Assert(k8sErrors.IsNotFound(err), check.Equals, true)
+}
@@ -0,0 +1,162 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kubernetes
+
+import (
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	provTypes "github.com/tsuru/tsuru/types/provision"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const sidecarVolumePrefix = "sidecar-"
+
+// podExtras holds the init containers and sidecars declared in tsuru.yaml for
+// a process, along with the empty volumes they share with the app and the
+// mounts of these volumes in the app container.
+type podExtras struct {
+	initContainers []apiv1.Container
+	sidecars       []apiv1.Container
+	volumes        []apiv1.Volume
+	appMounts      []apiv1.VolumeMount
+
+	names       map[string]struct{}
+	volumePaths map[string]string
+}
+
+// extraContainers builds the init containers and sidecars of the process,
+// app is the container running the process, whose image and envs are used by
+// init containers without image.
+func extraContainers(yamlData provTypes.TsuruYamlData, process string, app apiv1.Container) (*podExtras, error) {
+	extras := &podExtras{
+		names:       map[string]struct{}{app.Name: {}},
+		volumePaths: map[string]string{},
+	}
+	for _, init := range yamlData.InitContainersForProcess(process) {
+		var envs []apiv1.EnvVar
+		if init.Image == "" {
+			if len(init.Command) == 0 {
+				return nil, errors.Errorf("command of init container %q is required when it runs the app image", init.Name)
+			}
+			init.Image = app.Image
+			envs = app.Env
+		}
+		container, err := extras.container("init container", provTypes.TsuruYamlSidecar{
+			Name:      init.Name,
+			Image:     init.Image,
+			Command:   init.Command,
+			Args:      init.Args,
+			Env:       init.Env,
+			Resources: init.Resources,
+			Volumes:   init.Volumes,
+		}, envs)
+		if err != nil {
+			return nil, err
+		}
+		extras.initContainers = append(extras.initContainers, container)
+	}
+	for _, sidecar := range yamlData.SidecarsForProcess(process) {
+		container, err := extras.container("sidecar", sidecar, nil)
+		if err != nil {
+			return nil, err
+		}
+		extras.sidecars = append(extras.sidecars, container)
+	}
+	return extras, nil
+}
+
+func (e *podExtras) container(kind string, c provTypes.TsuruYamlSidecar, envs []apiv1.EnvVar) (apiv1.Container, error) {
+	if errs := validation.IsDNS1123Label(c.Name); len(errs) > 0 {
+		return apiv1.Container{}, errors.Errorf("invalid %s name %q: %s", kind, c.Name, strings.Join(errs, ", "))
+	}
+	if _, ok := e.names[c.Name]; ok {
+		return apiv1.Container{}, errors.Errorf("duplicated %s name %q", kind, c.Name)
+	}
+	e.names[c.Name] = struct{}{}
+	if c.Image == "" {
+		return apiv1.Container{}, errors.Errorf("image of %s %q is required", kind, c.Name)
+	}
+	resources, err := sidecarResources(c.Resources)
+	if err != nil {
+		return apiv1.Container{}, errors.Wrapf(err, "invalid resources of %s %q", kind, c.Name)
+	}
+	container := apiv1.Container{
+		Name:      c.Name,
+		Image:     c.Image,
+		Command:   c.Command,
+		Args:      c.Args,
+		Resources: resources,
+	}
+	container.Env = append(container.Env, envs...)
+	envNames := make([]string, 0, len(c.Env))
+	for name := range c.Env {
+		envNames = append(envNames, name)
+	}
+	sort.Strings(envNames)
+	for _, name := range envNames {
+		container.Env = append(container.Env, apiv1.EnvVar{Name: name, Value: c.Env[name]})
+	}
+	for _, v := range c.Volumes {
+		if errs := validation.IsDNS1123Label(sidecarVolumePrefix + v.Name); len(errs) > 0 {
+			return apiv1.Container{}, errors.Errorf("invalid volume name %q of %s %q: %s", v.Name, kind, c.Name, strings.Join(errs, ", "))
+		}
+		if !path.IsAbs(v.Path) {
+			return apiv1.Container{}, errors.Errorf("path of volume %q of %s %q must be absolute", v.Name, kind, c.Name)
+		}
+		volumeName := sidecarVolumePrefix + v.Name
+		if existing, ok := e.volumePaths[v.Name]; !ok {
+			e.volumePaths[v.Name] = v.Path
+			e.volumes = append(e.volumes, apiv1.Volume{
+				Name:         volumeName,
+				VolumeSource: apiv1.VolumeSource{EmptyDir: &apiv1.EmptyDirVolumeSource{}},
+			})
+			e.appMounts = append(e.appMounts, apiv1.VolumeMount{Name: volumeName, MountPath: v.Path})
+		} else if existing != v.Path {
+			return apiv1.Container{}, errors.Errorf("volume %q must be mounted at the same path in every container", v.Name)
+		}
+		container.VolumeMounts = append(container.VolumeMounts, apiv1.VolumeMount{Name: volumeName, MountPath: v.Path})
+	}
+	return container, nil
+}
+
+// apply adds the extra containers and volumes to the pod spec, whose first
+// container must be the one running the app.
+func (e *podExtras) apply(spec *apiv1.PodSpec) {
+	spec.InitContainers = append(spec.InitContainers, e.initContainers...)
+	spec.Containers = append(spec.Containers, e.sidecars...)
+	spec.Volumes = append(spec.Volumes, e.volumes...)
+	if len(spec.Containers) > 0 {
+		spec.Containers[0].VolumeMounts = append(spec.Containers[0].VolumeMounts, e.appMounts...)
+	}
+}
+
+// sidecarResources reserves and limits the cpu and memory of a sidecar to the
+// same values, so sidecars don't steal resources from the app.
+func sidecarResources(r provTypes.TsuruYamlSidecarResources) (apiv1.ResourceRequirements, error) {
+	list := apiv1.ResourceList{}
+	if r.CPU != "" {
+		cpu, err := resource.ParseQuantity(r.CPU)
+		if err != nil {
+			return apiv1.ResourceRequirements{}, err
+		}
+		list[apiv1.ResourceCPU] = cpu
+	}
+	if r.Memory != "" {
+		memory, err := resource.ParseQuantity(r.Memory)
+		if err != nil {
+			return apiv1.ResourceRequirements{}, err
+		}
+		list[apiv1.ResourceMemory] = memory
+	}
+	if len(list) == 0 {
+		return apiv1.ResourceRequirements{}, nil
+	}
+	return apiv1.ResourceRequirements{Requests: list, Limits: list.DeepCopy()}, nil
+}
@@ -0,0 +1,177 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kubernetes
+
+import (
+	provTypes "github.com/tsuru/tsuru/types/provision"
+	check "gopkg.in/check.v1"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func (s *S) TestExtraContainersSidecars(c *check.C) {
+	yamlData := provTypes.TsuruYamlData{
+		Sidecars: []provTypes.TsuruYamlSidecar{
+			{
+				Name:      "envoy",
+				Image:     "envoyproxy/envoy",
+				Command:   []string{"envoy"},
+				Env:       map[string]string{"B": "2", "A": "1"},
+				Resources: provTypes.TsuruYamlSidecarResources{Memory: "64Mi"},
+				Volumes:   []provTypes.TsuruYamlSidecarVolume{{Name: "config", Path: "/etc/envoy"}},
+			},
+			{
+				Name:      "config-reloader",
+				Image:     "reloader",
+				Volumes:   []provTypes.TsuruYamlSidecarVolume{{Name: "config", Path: "/etc/envoy"}},
+				Processes: []string{"web"},
+			},
+		},
+	}
+	extras, err := extraContainers(yamlData, "worker", apiv1.Container{Name: "myapp-worker"})
+	c.Assert(err, check.IsNil)
+	memory := resource.MustParse("64Mi")
+	c.Assert(extras.sidecars, check.DeepEquals, []apiv1.Container{
+		{
+			Name:    "envoy",
+			Image:   "envoyproxy/envoy",
+			Command: []string{"envoy"},
+			Env:     []apiv1.EnvVar{{Name: "A", Value: "1"}, {Name: "B", Value: "2"}},
+			Resources: apiv1.ResourceRequirements{
+				Requests: apiv1.ResourceList{apiv1.ResourceMemory: memory},
+				Limits:   apiv1.ResourceList{apiv1.ResourceMemory: memory},
+			},
+			VolumeMounts: []apiv1.VolumeMount{{Name: "sidecar-config", MountPath: "/etc/envoy"}},
+		},
+	})
+	c.Assert(extras.initContainers, check.IsNil)
+	c.Assert(extras.volumes, check.DeepEquals, []apiv1.Volume{
+		{Name: "sidecar-config", VolumeSource: apiv1.VolumeSource{EmptyDir: &apiv1.EmptyDirVolumeSource{}}},
+	})
+	c.Assert(extras.appMounts, check.DeepEquals, []apiv1.VolumeMount{{Name: "sidecar-config", MountPath: "/etc/envoy"}})
+	extras, err = extraContainers(yamlData, "web", apiv1.Container{Name: "myapp-web"})
+	c.Assert(err, check.IsNil)
+	c.Assert(extras.sidecars, check.HasLen, 2)
+	c.Assert(extras.sidecars[1].VolumeMounts, check.DeepEquals, []apiv1.VolumeMount{{Name: "sidecar-config", MountPath: "/etc/envoy"}})
+	c.Assert(extras.volumes, check.HasLen, 1)
+	c.Assert(extras.appMounts, check.HasLen, 1)
+	extras, err = extraContainers(provTypes.TsuruYamlData{}, "web", apiv1.Container{Name: "myapp-web"})
+	c.Assert(err, check.IsNil)
+	c.Assert(extras.sidecars, check.IsNil)
+	c.Assert(extras.volumes, check.IsNil)
+	c.Assert(extras.appMounts, check.IsNil)
+}
+
+func (s *S) TestExtraContainersInitContainers(c *check.C) {
+	yamlData := provTypes.TsuruYamlData{
+		InitContainers: []provTypes.TsuruYamlInitContainer{
+			{
+				Name:      "migrate",
+				Command:   []string{"python", "manage.py", "migrate"},
+				Env:       map[string]string{"MIGRATE": "1"},
+				Processes: []string{"web"},
+			},
+			{
+				Name:    "fetch-config",
+				Image:   "config-fetcher",
+				Volumes: []provTypes.TsuruYamlSidecarVolume{{Name: "config", Path: "/etc/app"}},
+			},
+		},
+		Sidecars: []provTypes.TsuruYamlSidecar{
+			{Name: "reloader", Image: "reloader", Volumes: []provTypes.TsuruYamlSidecarVolume{{Name: "config", Path: "/etc/app"}}},
+		},
+	}
+	app := apiv1.Container{
+		Name:  "myapp-web",
+		Image: "tsuru/app-myapp:v1",
+		Env:   []apiv1.EnvVar{{Name: "DATABASE_URL", Value: "mysql://db"}},
+	}
+	extras, err := extraContainers(yamlData, "web", app)
+	c.Assert(err, check.IsNil)
+	c.Assert(extras.initContainers, check.DeepEquals, []apiv1.Container{
+		{
+			Name:    "migrate",
+			Image:   "tsuru/app-myapp:v1",
+			Command: []string{"python", "manage.py", "migrate"},
+			Env:     []apiv1.EnvVar{{Name: "DATABASE_URL", Value: "mysql://db"}, {Name: "MIGRATE", Value: "1"}},
+		},
+		{
+			Name:         "fetch-config",
+			Image:        "config-fetcher",
+			VolumeMounts: []apiv1.VolumeMount{{Name: "sidecar-config", MountPath: "/etc/app"}},
+		},
+	})
+	c.Assert(app.Env, check.HasLen, 1)
+	c.Assert(extras.sidecars, check.HasLen, 1)
+	c.Assert(extras.volumes, check.HasLen, 1)
+	spec := apiv1.PodSpec{Containers: []apiv1.Container{app}}
+	extras.apply(&spec)
+	c.Assert(spec.InitContainers, check.HasLen, 2)
+	c.Assert(spec.Containers, check.HasLen, 2)
+	c.Assert(spec.Containers[0].VolumeMounts, check.DeepEquals, []apiv1.VolumeMount{{Name: "sidecar-config", MountPath: "/etc/app"}})
+	c.Assert(spec.Volumes, check.HasLen, 1)
+	extras, err = extraContainers(yamlData, "worker", app)
+	c.Assert(err, check.IsNil)
+	c.Assert(extras.initContainers, check.HasLen, 1)
+	c.Assert(extras.initContainers[0].Name, check.Equals, "fetch-config")
+}
+
+func (s *S) TestExtraContainersInvalid(c *check.C) {
+	tests := []struct {
+		yamlData provTypes.TsuruYamlData
+		err      string
+	}{
+		{
+			yamlData: provTypes.TsuruYamlData{Sidecars: []provTypes.TsuruYamlSidecar{{Name: "Envoy", Image: "envoy"}}},
+			err:      `invalid sidecar name "Envoy": .*`,
+		},
+		{
+			yamlData: provTypes.TsuruYamlData{Sidecars: []provTypes.TsuruYamlSidecar{{Name: "myapp-web", Image: "envoy"}}},
+			err:      `duplicated sidecar name "myapp-web"`,
+		},
+		{
+			yamlData: provTypes.TsuruYamlData{Sidecars: []provTypes.TsuruYamlSidecar{{Name: "envoy", Image: "envoy"}, {Name: "envoy", Image: "envoy"}}},
+			err:      `duplicated sidecar name "envoy"`,
+		},
+		{
+			yamlData: provTypes.TsuruYamlData{Sidecars: []provTypes.TsuruYamlSidecar{{Name: "envoy"}}},
+			err:      `image of sidecar "envoy" is required`,
+		},
+		{
+			yamlData: provTypes.TsuruYamlData{Sidecars: []provTypes.TsuruYamlSidecar{{Name: "envoy", Image: "envoy", Resources: provTypes.TsuruYamlSidecarResources{CPU: "lots"}}}},
+			err:      `invalid resources of sidecar "envoy": .*`,
+		},
+		{
+			yamlData: provTypes.TsuruYamlData{Sidecars: []provTypes.TsuruYamlSidecar{{Name: "envoy", Image: "envoy", Volumes: []provTypes.TsuruYamlSidecarVolume{{Name: "config", Path: "etc/envoy"}}}}},
+			err:      `path of volume "config" of sidecar "envoy" must be absolute`,
+		},
+		{
+			yamlData: provTypes.TsuruYamlData{Sidecars: []provTypes.TsuruYamlSidecar{{Name: "envoy", Image: "envoy", Volumes: []provTypes.TsuruYamlSidecarVolume{{Name: "my_config", Path: "/etc/envoy"}}}}},
+			err:      `invalid volume name "my_config" of sidecar "envoy": .*`,
+		},
+		{
+			yamlData: provTypes.TsuruYamlData{Sidecars: []provTypes.TsuruYamlSidecar{
+				{Name: "envoy", Image: "envoy", Volumes: []provTypes.TsuruYamlSidecarVolume{{Name: "config", Path: "/etc/envoy"}}},
+				{Name: "reloader", Image: "reloader", Volumes: []provTypes.TsuruYamlSidecarVolume{{Name: "config", Path: "/config"}}},
+			}},
+			err: `volume "config" must be mounted at the same path in every container`,
+		},
+		{
+			yamlData: provTypes.TsuruYamlData{InitContainers: []provTypes.TsuruYamlInitContainer{{Name: "migrate"}}},
+			err:      `command of init container "migrate" is required when it runs the app image`,
+		},
+		{
+			yamlData: provTypes.TsuruYamlData{
+				InitContainers: []provTypes.TsuruYamlInitContainer{{Name: "envoy", Image: "envoy-init"}},
+				Sidecars:       []provTypes.TsuruYamlSidecar{{Name: "envoy", Image: "envoy"}},
+			},
+			err: `duplicated sidecar name "envoy"`,
+		},
+	}
+	for _, tt := range tests {
+		_, err := extraContainers(tt.yamlData, "web", apiv1.Container{Name: "myapp-web", Image: "myapp"})
+		c.Check(err, check.ErrorMatches, tt.err)
+	}
+}
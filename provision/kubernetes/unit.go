@@ -7,11 +7,18 @@ package kubernetes
 import (
 	"context"
 	"fmt"
+	"io"
 
 	"github.com/pkg/errors"
 	"github.com/tsuru/tsuru/provision"
+	apiv1 "k8s.io/api/core/v1"
 	policyV1Beta1 "k8s.io/api/policy/v1beta1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
 )
 
 func (p *kubernetesProvisioner) KillUnit(ctx context.Context, app provision.App, unitName string, force bool) error {
@@ -54,3 +61,146 @@ func (p *kubernetesProvisioner) KillUnit(ctx context.Context, app provision.App,
 	}
 	return nil
 }
+
+// RestartUnit deletes the pod of the unit, respecting its termination grace
+// period, so that its controller replaces it with a fresh one.
+func (p *kubernetesProvisioner) RestartUnit(ctx context.Context, app provision.App, unitName string, w io.Writer) error {
+	clusterClient, err := clusterForPool(ctx, app.GetPool())
+	if err != nil {
+		return err
+	}
+	ns, err := clusterClient.AppNamespace(ctx, app)
+	if err != nil {
+		return err
+	}
+	pod, err := clusterClient.CoreV1().Pods(ns).Get(ctx, unitName, metav1.GetOptions{})
+	if err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return &provision.UnitNotFoundError{ID: unitName}
+		}
+		return errors.Wrap(err, "Unable to find pod")
+	}
+	appName := app.GetName()
+	if pod.Labels["tsuru.io/app-name"] != appName {
+		return &provision.UnitNotFoundError{ID: unitName}
+	}
+	err = clusterClient.CoreV1().Pods(ns).Delete(ctx, pod.Name, metav1.DeleteOptions{})
+	if err != nil {
+		return errors.Wrap(err, "Unable to delete pod")
+	}
+	fmt.Fprintf(w, " ---> Unit %q deleted, a new unit will replace it\n", unitName)
+	return nil
+}
+
+// DebugUnit adds an ephemeral container, targeting the app container of the
+// unit, to its pod and attaches to its shell. It allows troubleshooting units
+// of images without a shell, like distroless ones.
+func (p *kubernetesProvisioner) DebugUnit(ctx context.Context, opts provision.DebugOptions) error {
+	client, err := clusterForPool(ctx, opts.App.GetPool())
+	if err != nil {
+		return err
+	}
+	ns, err := client.AppNamespace(ctx, opts.App)
+	if err != nil {
+		return err
+	}
+	pod, err := client.CoreV1().Pods(ns).Get(ctx, opts.Unit, metav1.GetOptions{})
+	if err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return &provision.UnitNotFoundError{ID: opts.Unit}
+		}
+		return errors.WithStack(err)
+	}
+	if labelSetFromMeta(&pod.ObjectMeta).AppName() != opts.App.GetName() {
+		return &provision.UnitNotFoundError{ID: opts.Unit}
+	}
+	if pod.Status.Phase != apiv1.PodRunning {
+		return errors.Errorf("unit %q is not running", opts.Unit)
+	}
+	image := opts.Image
+	if image == "" {
+		image = client.debugContainerImage(opts.App.GetPool())
+	}
+	container := apiv1.EphemeralContainer{
+		EphemeralContainerCommon: apiv1.EphemeralContainerCommon{
+			Name:    fmt.Sprintf("debugger-%s", rand.String(5)),
+			Image:   image,
+			Command: []string{"sh"},
+			Stdin:   true,
+			TTY:     opts.Stdin != nil,
+		},
+		TargetContainerName: pod.Spec.Containers[0].Name,
+	}
+	if opts.Term != "" {
+		container.Env = []apiv1.EnvVar{{Name: "TERM", Value: opts.Term}}
+	}
+	ephemeral, err := client.CoreV1().Pods(ns).GetEphemeralContainers(ctx, pod.Name, metav1.GetOptions{})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	ephemeral.EphemeralContainers = append(ephemeral.EphemeralContainers, container)
+	_, err = client.CoreV1().Pods(ns).UpdateEphemeralContainers(ctx, pod.Name, ephemeral, metav1.UpdateOptions{})
+	if err != nil {
+		return errors.Wrap(err, "unable to add debug container, ephemeral containers may be disabled in the cluster")
+	}
+	kubeConf := getKubeConfig()
+	tctx, cancel := context.WithTimeout(ctx, kubeConf.PodRunningTimeout)
+	defer cancel()
+	err = waitFor(tctx, func() (bool, error) {
+		pod, err = client.CoreV1().Pods(ns).Get(ctx, pod.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, errors.WithStack(err)
+		}
+		for _, status := range pod.Status.EphemeralContainerStatuses {
+			if status.Name != container.Name {
+				continue
+			}
+			if status.State.Terminated != nil {
+				return false, errors.Errorf("debug container terminated: %s", status.State.Terminated.Reason)
+			}
+			return status.State.Running != nil, nil
+		}
+		return false, nil
+	}, nil)
+	if err != nil {
+		return err
+	}
+	restCli, err := rest.RESTClientFor(client.restConfig)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	req := restCli.Post().
+		Resource("pods").
+		Name(pod.Name).
+		Namespace(ns).
+		SubResource("attach").
+		Param("container", container.Name)
+	req.VersionedParams(&apiv1.PodAttachOptions{
+		Container: container.Name,
+		Stdin:     opts.Stdin != nil,
+		Stdout:    true,
+		Stderr:    true,
+		TTY:       container.TTY,
+	}, scheme.ParameterCodec)
+	exec, err := keepAliveSpdyExecutor(client.restConfig, "POST", req.URL())
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	var sizeQueue remotecommand.TerminalSizeQueue
+	if opts.Width != 0 && opts.Height != 0 {
+		sizeQueue = &fixedSizeQueue{
+			sz: &remotecommand.TerminalSize{
+				Width:  uint16(opts.Width),
+				Height: uint16(opts.Height),
+			},
+		}
+	}
+	err = exec.Stream(remotecommand.StreamOptions{
+		Stdin:             opts.Stdin,
+		Stdout:            opts.Stdout,
+		Stderr:            opts.Stderr,
+		Tty:               container.TTY,
+		TerminalSizeQueue: sizeQueue,
+	})
+	return errors.WithStack(err)
+}
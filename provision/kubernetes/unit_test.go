@@ -0,0 +1,147 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package kubernetes
+
+import (
+	"bytes"
+	"context"
+	"strings"
+
+	"github.com/tsuru/tsuru/provision"
+	"github.com/tsuru/tsuru/provision/provisiontest"
+	"github.com/tsuru/tsuru/safe"
+	check "gopkg.in/check.v1"
+	apiv1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ktesting "k8s.io/client-go/testing"
+)
+
+func (s *S) TestRestartUnit(c *check.C) {
+	a := provisiontest.NewFakeApp("myapp", "python", 0)
+	ns, err := s.client.AppNamespace(context.TODO(), a)
+	c.Assert(err, check.IsNil)
+	_, err = s.client.CoreV1().Pods(ns).Create(context.TODO(), &apiv1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "myapp-web-pod-1",
+			Namespace: ns,
+			Labels:    map[string]string{"tsuru.io/app-name": "myapp"},
+		},
+	}, metav1.CreateOptions{})
+	c.Assert(err, check.IsNil)
+	buf := &bytes.Buffer{}
+	err = s.p.RestartUnit(context.TODO(), a, "myapp-web-pod-1", buf)
+	c.Assert(err, check.IsNil)
+	c.Assert(buf.String(), check.Equals, " ---> Unit \"myapp-web-pod-1\" deleted, a new unit will replace it\n")
+	_, err = s.client.CoreV1().Pods(ns).Get(context.TODO(), "myapp-web-pod-1", metav1.GetOptions{})
+	c.Assert(k8sErrors.IsNotFound(err), check.Equals, true)
+}
+
+func (s *S) TestRestartUnitNotFound(c *check.C) {
+	a := provisiontest.NewFakeApp("myapp", "python", 0)
+	ns, err := s.client.AppNamespace(context.TODO(), a)
+	c.Assert(err, check.IsNil)
+	_, err = s.client.CoreV1().Pods(ns).Create(context.TODO(), &apiv1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "otherapp-web-pod-1",
+			Namespace: ns,
+			Labels:    map[string]string{"tsuru.io/app-name": "otherapp"},
+		},
+	}, metav1.CreateOptions{})
+	c.Assert(err, check.IsNil)
+	err = s.p.RestartUnit(context.TODO(), a, "otherapp-web-pod-1", &bytes.Buffer{})
+	c.Assert(err, check.DeepEquals, &provision.UnitNotFoundError{ID: "otherapp-web-pod-1"})
+	err = s.p.RestartUnit(context.TODO(), a, "unknown", &bytes.Buffer{})
+	c.Assert(err, check.DeepEquals, &provision.UnitNotFoundError{ID: "unknown"})
+}
+
+func (s *S) TestDebugUnit(c *check.C) {
+	a, _, rollback := s.mock.DefaultReactions(c)
+	defer rollback()
+	s.clusterClient.CustomData[debugContainerImageKey] = "tools:latest"
+	ns, err := s.client.AppNamespace(context.TODO(), a)
+	c.Assert(err, check.IsNil)
+	_, err = s.client.CoreV1().Pods(ns).Create(context.TODO(), &apiv1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "myapp-web-pod-1",
+			Namespace: ns,
+			Labels:    map[string]string{"tsuru.io/app-name": "myapp"},
+		},
+		Spec: apiv1.PodSpec{
+			Containers: []apiv1.Container{{Name: "myapp-web"}},
+		},
+		Status: apiv1.PodStatus{Phase: apiv1.PodRunning},
+	}, metav1.CreateOptions{})
+	c.Assert(err, check.IsNil)
+	var added []apiv1.EphemeralContainer
+	s.client.PrependReactor("get", "pods", func(action ktesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() == "ephemeralcontainers" {
+			return true, &apiv1.EphemeralContainers{ObjectMeta: metav1.ObjectMeta{Name: "myapp-web-pod-1", Namespace: ns}}, nil
+		}
+		if len(added) == 0 {
+			return false, nil, nil
+		}
+		obj, err := s.client.Tracker().Get(action.GetResource(), ns, action.(ktesting.GetAction).GetName())
+		if err != nil {
+			return true, nil, err
+		}
+		pod := obj.(*apiv1.Pod).DeepCopy()
+		for _, container := range added {
+			pod.Status.EphemeralContainerStatuses = append(pod.Status.EphemeralContainerStatuses, apiv1.ContainerStatus{
+				Name:  container.Name,
+				State: apiv1.ContainerState{Running: &apiv1.ContainerStateRunning{}},
+			})
+		}
+		return true, pod, nil
+	})
+	s.client.PrependReactor("update", "pods", func(action ktesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "ephemeralcontainers" {
+			return false, nil, nil
+		}
+		ephemeral := action.(ktesting.UpdateAction).GetObject().(*apiv1.EphemeralContainers)
+		added = ephemeral.EphemeralContainers
+		return true, ephemeral, nil
+	})
+	buf := safe.NewBuffer([]byte("ls /proc/1/root"))
+	conn := &provisiontest.FakeConn{Buf: buf}
+	err = s.p.DebugUnit(context.TODO(), provision.DebugOptions{
+		App:    a,
+		Unit:   "myapp-web-pod-1",
+		Stdin:  conn,
+		Stdout: conn,
+		Stderr: conn,
+		Term:   "xterm",
+	})
+	c.Assert(err, check.IsNil, check.Commentf("%+v", err))
+	c.Assert(added, check.HasLen, 1)
+	debugger := added[0]
+	c.Assert(strings.HasPrefix(debugger.Name, "debugger-"), check.Equals, true)
+	c.Assert(debugger.Image, check.Equals, "tools:latest")
+	c.Assert(debugger.TargetContainerName, check.Equals, "myapp-web")
+	c.Assert(debugger.Env, check.DeepEquals, []apiv1.EnvVar{{Name: "TERM", Value: "xterm"}})
+	rollback()
+	c.Assert(s.mock.Stream[debugger.Name].Stdin, check.Equals, "ls /proc/1/root")
+	c.Assert(s.mock.Stream[debugger.Name].Urls, check.HasLen, 1)
+	c.Assert(s.mock.Stream[debugger.Name].Urls[0].Path, check.Equals, "/api/v1/namespaces/default/pods/myapp-web-pod-1/attach")
+}
+
+func (s *S) TestDebugUnitNotFound(c *check.C) {
+	a := provisiontest.NewFakeApp("myapp", "python", 0)
+	ns, err := s.client.AppNamespace(context.TODO(), a)
+	c.Assert(err, check.IsNil)
+	_, err = s.client.CoreV1().Pods(ns).Create(context.TODO(), &apiv1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "otherapp-web-pod-1",
+			Namespace: ns,
+			Labels:    map[string]string{"tsuru.io/app-name": "otherapp"},
+		},
+	}, metav1.CreateOptions{})
+	c.Assert(err, check.IsNil)
+	err = s.p.DebugUnit(context.TODO(), provision.DebugOptions{App: a, Unit: "otherapp-web-pod-1"})
+	c.Assert(err, check.DeepEquals, &provision.UnitNotFoundError{ID: "otherapp-web-pod-1"})
+	err = s.p.DebugUnit(context.TODO(), provision.DebugOptions{App: a, Unit: "unknown"})
+	c.Assert(err, check.DeepEquals, &provision.UnitNotFoundError{ID: "unknown"})
+}
@@ -50,6 +50,10 @@ const (
 	labelVolumePlan = "volume-plan"
 	labelVolumeTeam = "volume-team"
 
+	labelJobName = "job-name"
+	labelJobPool = "job-pool"
+	labelJobTeam = "job-team"
+
 	labelBuildImage = "build-image"
 	labelRestarts   = "restarts"
 
@@ -149,6 +153,10 @@ func (s *LabelSet) ToVolumeSelector() map[string]string {
 	return withPrefix(subMap(s.Labels, labelVolumeName), s.Prefix)
 }
 
+func (s *LabelSet) ToJobSelector() map[string]string {
+	return withPrefix(subMap(s.Labels, labelJobName), s.Prefix)
+}
+
 func (s *LabelSet) ToHPASelector() map[string]string {
 	keys := []string{labelIsTsuru, LabelAppName}
 	if s.getLabel(LabelAppProcess) != "" {
@@ -165,6 +173,18 @@ func (s *LabelSet) AppName() string {
 	return s.getLabel(LabelAppName)
 }
 
+func (s *LabelSet) JobName() string {
+	return s.getLabel(labelJobName)
+}
+
+func (s *LabelSet) JobPool() string {
+	return s.getLabel(labelJobPool)
+}
+
+func (s *LabelSet) JobTeam() string {
+	return s.getLabel(labelJobTeam)
+}
+
 func (s *LabelSet) AppProcess() string {
 	return s.getLabel(LabelAppProcess)
 }
@@ -594,6 +614,25 @@ func VolumeLabels(opts VolumeLabelsOpts) *LabelSet {
 	return &LabelSet{Labels: labels, Prefix: opts.Prefix}
 }
 
+type JobLabelsOpts struct {
+	Name        string
+	Provisioner string
+	Pool        string
+	Team        string
+	Prefix      string
+}
+
+func JobLabels(opts JobLabelsOpts) *LabelSet {
+	labels := map[string]string{
+		labelIsTsuru:     strconv.FormatBool(true),
+		labelProvisioner: opts.Provisioner,
+		labelJobName:     opts.Name,
+		labelJobPool:     opts.Pool,
+		labelJobTeam:     opts.Team,
+	}
+	return &LabelSet{Labels: labels, Prefix: opts.Prefix}
+}
+
 type ImageBuildLabelsOpts struct {
 	Name         string
 	CustomLabels map[string]string
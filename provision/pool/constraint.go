@@ -19,7 +19,7 @@ import (
 
 var (
 	ErrInvalidConstraintType = errors.Errorf("invalid constraint type. Valid types are: %s", validConstraintTypes)
-	validConstraintTypes     = []poolConstraintType{ConstraintTypeTeam, ConstraintTypeService, ConstraintTypeRouter, ConstraintTypePlan, ConstraintTypeVolumePlan}
+	validConstraintTypes     = []poolConstraintType{ConstraintTypeTeam, ConstraintTypeService, ConstraintTypeRouter, ConstraintTypePlan, ConstraintTypeVolumePlan, ConstraintTypeJobPlan, ConstraintTypeGPU}
 )
 
 type poolConstraintType string
@@ -30,6 +30,8 @@ const (
 	ConstraintTypeService    = poolConstraintType("service")
 	ConstraintTypePlan       = poolConstraintType("plan")
 	ConstraintTypeVolumePlan = poolConstraintType("volume-plan")
+	ConstraintTypeJobPlan    = poolConstraintType("job-plan")
+	ConstraintTypeGPU        = poolConstraintType("gpu")
 )
 
 type regexpCache struct {
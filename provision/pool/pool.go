@@ -8,8 +8,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/ghodss/yaml"
 	"github.com/globalsign/mgo"
@@ -39,12 +41,19 @@ var (
 	ErrPoolHasNoService               = errors.New("no service found for pool")
 	ErrPoolHasNoPlan                  = errors.New("no plan found for pool")
 	ErrPoolHasNoVolumePlan            = errors.New("no volume-plan found for pool")
+	ErrPoolHasNoJobPlan               = errors.New("no job-plan found for pool")
 )
 
 const (
-	affinityKey         = "affinity"
-	buildPlanKey        = "build-plan"
-	buildPlanSideCarKey = "build-plan-sidecar"
+	affinityKey             = "affinity"
+	tolerationsKey          = "tolerations"
+	topologySpreadKey       = "topology-spread-constraints"
+	buildPlanKey            = "build-plan"
+	buildPlanSideCarKey     = "build-plan-sidecar"
+	hibernateActivatorKey   = "hibernate-activator"
+	hibernateIdleTimeoutKey = "hibernate-idle-timeout"
+
+	defaultHibernateIdleTimeout = time.Hour
 )
 
 type Pool struct {
@@ -57,6 +66,13 @@ type Pool struct {
 	ctx context.Context
 }
 
+// HibernateConfig holds how idle apps in a pool are put to sleep. Their
+// routes point to Activator, which wakes the app on the first request.
+type HibernateConfig struct {
+	Activator   *url.URL
+	IdleTimeout time.Duration
+}
+
 type AddPoolOptions struct {
 	Name        string
 	Public      bool
@@ -87,6 +103,34 @@ func (p *Pool) GetAffinity() (*apiv1.Affinity, error) {
 	return nil, nil
 }
 
+// GetTolerations returns the tolerations added to the units running in the
+// pool, so they can be placed on tainted nodes dedicated to it.
+func (p *Pool) GetTolerations() ([]apiv1.Toleration, error) {
+	if tolerations, ok := p.Labels[tolerationsKey]; ok {
+		var k8sTolerations []apiv1.Toleration
+		if err := yaml.Unmarshal([]byte(tolerations), &k8sTolerations); err != nil {
+			return nil, err
+		}
+		return k8sTolerations, nil
+	}
+
+	return nil, nil
+}
+
+// GetTopologySpreadConstraints returns the constraints spreading the units of
+// each process of the apps running in the pool across zones or nodes.
+func (p *Pool) GetTopologySpreadConstraints() ([]apiv1.TopologySpreadConstraint, error) {
+	if constraints, ok := p.Labels[topologySpreadKey]; ok {
+		var k8sConstraints []apiv1.TopologySpreadConstraint
+		if err := yaml.Unmarshal([]byte(constraints), &k8sConstraints); err != nil {
+			return nil, err
+		}
+		return k8sConstraints, nil
+	}
+
+	return nil, nil
+}
+
 func (p *Pool) GetBuildPlan() map[string]string {
 	if _, ok := p.Labels[buildPlanKey]; !ok {
 		return nil
@@ -100,6 +144,34 @@ func (p *Pool) GetBuildPlan() map[string]string {
 	return plans
 }
 
+// GetHibernateConfig returns the hibernate settings of the pool, or nil if
+// apps in the pool must never be hibernated.
+func (p *Pool) GetHibernateConfig() (*HibernateConfig, error) {
+	return hibernateConfigFromLabels(p.Labels)
+}
+
+func hibernateConfigFromLabels(labels map[string]string) (*HibernateConfig, error) {
+	activator, ok := labels[hibernateActivatorKey]
+	if !ok {
+		return nil, nil
+	}
+	activatorURL, err := url.Parse(activator)
+	if err != nil || activatorURL.Scheme == "" || activatorURL.Host == "" {
+		return nil, &tsuruErrors.ValidationError{Message: fmt.Sprintf("invalid %s label %q: must be an absolute URL", hibernateActivatorKey, activator)}
+	}
+	hibernateConfig := &HibernateConfig{
+		Activator:   activatorURL,
+		IdleTimeout: defaultHibernateIdleTimeout,
+	}
+	if idleTimeout, ok := labels[hibernateIdleTimeoutKey]; ok {
+		hibernateConfig.IdleTimeout, err = time.ParseDuration(idleTimeout)
+		if err != nil || hibernateConfig.IdleTimeout <= 0 {
+			return nil, &tsuruErrors.ValidationError{Message: fmt.Sprintf("invalid %s label %q: must be a positive duration", hibernateIdleTimeoutKey, idleTimeout)}
+		}
+	}
+	return hibernateConfig, nil
+}
+
 func (p *Pool) GetProvisioner() (provision.Provisioner, error) {
 	if p.Provisioner != "" {
 		return provision.Get(p.Provisioner)
@@ -153,16 +225,44 @@ func (p *Pool) GetPlans() ([]string, error) {
 	return nil, ErrPoolHasNoPlan
 }
 
-func (p *Pool) GetDefaultPlan() (*appTypes.Plan, error) {
-	constraints, err := getConstraintsForPool(p.Name, ConstraintTypePlan)
+// GetJobPlans returns the plans allowed for jobs in the pool, they're
+// constrained independently from the plans allowed for apps.
+func (p *Pool) GetJobPlans() ([]string, error) {
+	allowedValues, err := p.allowedValues()
 	if err != nil {
 		return nil, err
 	}
+	if c := allowedValues[ConstraintTypeJobPlan]; len(c) > 0 {
+		return c, nil
+	}
+	return nil, ErrPoolHasNoJobPlan
+}
+
+func (p *Pool) GetDefaultPlan() (*appTypes.Plan, error) {
 	defaultPlan, err := servicemanager.Plan.DefaultPlan(p.ctx)
 	if err != nil {
 		return nil, err
 	}
-	constraint := constraints[ConstraintTypePlan]
+	return p.getDefaultPlan(ConstraintTypePlan, defaultPlan)
+}
+
+// GetDefaultJobPlan returns the plan used by jobs created in the pool without
+// a plan. The default plan for apps is used when the pool has no job-plan
+// constraint.
+func (p *Pool) GetDefaultJobPlan() (*appTypes.Plan, error) {
+	defaultPlan, err := p.GetDefaultPlan()
+	if err != nil {
+		return nil, err
+	}
+	return p.getDefaultPlan(ConstraintTypeJobPlan, defaultPlan)
+}
+
+func (p *Pool) getDefaultPlan(field poolConstraintType, defaultPlan *appTypes.Plan) (*appTypes.Plan, error) {
+	constraints, err := getConstraintsForPool(p.Name, field)
+	if err != nil {
+		return nil, err
+	}
+	constraint := constraints[field]
 	if constraint == nil || len(constraint.Values) == 0 {
 		return defaultPlan, nil
 	}
@@ -173,8 +273,8 @@ func (p *Pool) GetDefaultPlan() (*appTypes.Plan, error) {
 		if err != nil {
 			return nil, err
 		}
-		if len(allowed[ConstraintTypePlan]) > 0 {
-			plan, err = servicemanager.Plan.FindByName(p.ctx, allowed[ConstraintTypePlan][0])
+		if len(allowed[field]) > 0 {
+			plan, err = servicemanager.Plan.FindByName(p.ctx, allowed[field][0])
 			if err != nil {
 				return nil, err
 			}
@@ -253,7 +353,7 @@ func (p *Pool) allowedValues() (map[poolConstraintType][]string, error) {
 	if err != nil {
 		return nil, err
 	}
-	plans, err := plansNames(p.ctx)
+	plans, err := plansNames(p.ctx, p.Name)
 	if err != nil {
 		return nil, err
 	}
@@ -268,8 +368,9 @@ func (p *Pool) allowedValues() (map[poolConstraintType][]string, error) {
 		ConstraintTypeTeam:       teams,
 		ConstraintTypePlan:       plans,
 		ConstraintTypeVolumePlan: volumePlans,
+		ConstraintTypeJobPlan:    plans,
 	}
-	constraints, err := getConstraintsForPool(p.Name, ConstraintTypeTeam, ConstraintTypeRouter, ConstraintTypeService, ConstraintTypePlan, ConstraintTypeVolumePlan)
+	constraints, err := getConstraintsForPool(p.Name, ConstraintTypeTeam, ConstraintTypeRouter, ConstraintTypeService, ConstraintTypePlan, ConstraintTypeVolumePlan, ConstraintTypeJobPlan)
 	if err != nil {
 		return nil, err
 	}
@@ -338,13 +439,28 @@ func servicesNames(ctx context.Context) ([]string, error) {
 	return names, nil
 }
 
-func plansNames(ctx context.Context) ([]string, error) {
+// plansNames returns the names of the plans which may be used on the pool,
+// plans with GPUs are only included when the gpu constraint of the pool
+// allows their GPU type.
+func plansNames(ctx context.Context, pool string) ([]string, error) {
 	plans, err := servicemanager.Plan.List(ctx)
 	if err != nil {
 		return nil, err
 	}
 	var names []string
+	var gpuConstraints map[poolConstraintType]*PoolConstraint
 	for _, p := range plans {
+		if p.GPU > 0 {
+			if gpuConstraints == nil {
+				gpuConstraints, err = getConstraintsForPool(pool, ConstraintTypeGPU)
+				if err != nil {
+					return nil, err
+				}
+			}
+			if !gpuConstraints[ConstraintTypeGPU].check(p.GPUType) {
+				continue
+			}
+		}
 		names = append(names, p.Name)
 	}
 	return names, nil
@@ -377,6 +493,24 @@ func validateLabels(labels map[string]string) error {
 			return err
 		}
 	}
+	if tolerationsStr, ok := labels[tolerationsKey]; ok {
+		var tolerations []apiv1.Toleration
+		if err := yaml.Unmarshal([]byte(tolerationsStr), &tolerations); err != nil {
+			return err
+		}
+	}
+	if constraintsStr, ok := labels[topologySpreadKey]; ok {
+		var constraints []apiv1.TopologySpreadConstraint
+		if err := yaml.Unmarshal([]byte(constraintsStr), &constraints); err != nil {
+			return err
+		}
+		if err := appTypes.ValidateTopologySpreadConstraints(constraints); err != nil {
+			return err
+		}
+	}
+	if _, err := hibernateConfigFromLabels(labels); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -673,6 +807,36 @@ func PoolUpdate(ctx context.Context, name string, opts UpdatePoolOptions) error
 	return err
 }
 
+// ValidatePoolUpdate runs the validations of PoolUpdate, returning the pool
+// as it would be after the update. Nothing is changed.
+func ValidatePoolUpdate(ctx context.Context, name string, opts UpdatePoolOptions) (*Pool, error) {
+	p, err := GetPoolByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Default != nil && *opts.Default && !opts.Force {
+		defaultPools, err := listPools(ctx, bson.M{"default": true})
+		if err != nil {
+			return nil, err
+		}
+		if len(defaultPools) > 0 {
+			return nil, ErrDefaultPoolAlreadyExists
+		}
+	}
+	if len(opts.Labels) > 0 {
+		if err = validateLabels(opts.Labels); err != nil {
+			return nil, err
+		}
+	}
+	if opts.Default != nil {
+		p.Default = *opts.Default
+	}
+	if opts.Labels != nil {
+		p.Labels = opts.Labels
+	}
+	return p, nil
+}
+
 func exprAsGlobPattern(expr string) string {
 	parts := strings.Split(expr, "*")
 	for i := range parts {
@@ -738,7 +902,7 @@ func (p *poolService) allowedValues(ctx context.Context, pool string) (map[poolC
 	if err != nil {
 		return nil, err
 	}
-	plans, err := plansNames(ctx)
+	plans, err := plansNames(ctx, pool)
 	if err != nil {
 		return nil, err
 	}
@@ -8,6 +8,7 @@ import (
 	"context"
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/globalsign/mgo/bson"
 	"github.com/tsuru/config"
@@ -279,6 +280,30 @@ func (s *S) TestAddPoolValidateLabels(c *check.C) {
 			},
 			expectedErr: "invalid character 'i' looking for beginning of value",
 		},
+		{
+			testName: "tolerations label with invalid format",
+			opts: AddPoolOptions{
+				Name:   "pool2",
+				Labels: map[string]string{tolerationsKey: `invalid object`},
+			},
+			expectedErr: "error unmarshaling JSON: json: cannot unmarshal string into Go value of type []v1.Toleration",
+		},
+		{
+			testName: "hibernate activator label with relative url",
+			opts: AddPoolOptions{
+				Name:   "pool2",
+				Labels: map[string]string{hibernateActivatorKey: "activator"},
+			},
+			expectedErr: `invalid hibernate-activator label "activator": must be an absolute URL`,
+		},
+		{
+			testName: "hibernate idle timeout label with invalid duration",
+			opts: AddPoolOptions{
+				Name:   "pool2",
+				Labels: map[string]string{hibernateActivatorKey: "http://activator:8080", hibernateIdleTimeoutKey: "-1m"},
+			},
+			expectedErr: `invalid hibernate-idle-timeout label "-1m": must be a positive duration`,
+		},
 	}
 
 	for _, t := range tt {
@@ -287,6 +312,22 @@ func (s *S) TestAddPoolValidateLabels(c *check.C) {
 	}
 }
 
+func (s *S) TestGetHibernateConfig(c *check.C) {
+	p := Pool{Name: "pool1"}
+	hibernateConfig, err := p.GetHibernateConfig()
+	c.Assert(err, check.IsNil)
+	c.Assert(hibernateConfig, check.IsNil)
+	p.Labels = map[string]string{hibernateActivatorKey: "http://activator:8080"}
+	hibernateConfig, err = p.GetHibernateConfig()
+	c.Assert(err, check.IsNil)
+	c.Assert(hibernateConfig.Activator.String(), check.Equals, "http://activator:8080")
+	c.Assert(hibernateConfig.IdleTimeout, check.Equals, time.Hour)
+	p.Labels[hibernateIdleTimeoutKey] = "15m"
+	hibernateConfig, err = p.GetHibernateConfig()
+	c.Assert(err, check.IsNil)
+	c.Assert(hibernateConfig.IdleTimeout, check.Equals, 15*time.Minute)
+}
+
 func (s *S) TestAddTeamToPoolNotFound(c *check.C) {
 	err := AddTeamsToPool("notfound", []string{"ateam"})
 	c.Assert(err, check.Equals, ErrPoolNotFound)
@@ -587,6 +628,37 @@ func (s *S) TestPoolUpdateDontHaveSideEffects(c *check.C) {
 	c.Assert(constraint.AllowsAll(), check.Equals, true)
 }
 
+func (s *S) TestValidatePoolUpdate(c *check.C) {
+	err := AddPool(context.TODO(), AddPoolOptions{Name: "pool1", Default: true})
+	c.Assert(err, check.IsNil)
+	err = AddPool(context.TODO(), AddPoolOptions{Name: "pool2", Labels: map[string]string{"a": "b"}})
+	c.Assert(err, check.IsNil)
+	p, err := ValidatePoolUpdate(context.TODO(), "pool2", UpdatePoolOptions{Default: boolPtr(true), Force: true, Labels: map[string]string{"c": "d"}})
+	c.Assert(err, check.IsNil)
+	c.Assert(p.Default, check.Equals, true)
+	c.Assert(p.Labels, check.DeepEquals, map[string]string{"c": "d"})
+	p, err = GetPoolByName(context.TODO(), "pool2")
+	c.Assert(err, check.IsNil)
+	c.Assert(p.Default, check.Equals, false)
+	c.Assert(p.Labels, check.DeepEquals, map[string]string{"a": "b"})
+	p, err = GetPoolByName(context.TODO(), "pool1")
+	c.Assert(err, check.IsNil)
+	c.Assert(p.Default, check.Equals, true)
+}
+
+func (s *S) TestValidatePoolUpdateErrors(c *check.C) {
+	err := AddPool(context.TODO(), AddPoolOptions{Name: "pool1", Default: true})
+	c.Assert(err, check.IsNil)
+	err = AddPool(context.TODO(), AddPoolOptions{Name: "pool2"})
+	c.Assert(err, check.IsNil)
+	_, err = ValidatePoolUpdate(context.TODO(), "notfound", UpdatePoolOptions{})
+	c.Assert(err, check.Equals, ErrPoolNotFound)
+	_, err = ValidatePoolUpdate(context.TODO(), "pool2", UpdatePoolOptions{Default: boolPtr(true)})
+	c.Assert(err, check.Equals, ErrDefaultPoolAlreadyExists)
+	_, err = ValidatePoolUpdate(context.TODO(), "pool2", UpdatePoolOptions{Labels: map[string]string{hibernateActivatorKey: "not-a-url"}})
+	c.Assert(err, check.ErrorMatches, ".*must be an absolute URL")
+}
+
 func (s *S) TestListPool(c *check.C) {
 	err := AddPool(context.TODO(), AddPoolOptions{Name: "pool1"})
 	c.Assert(err, check.IsNil)
@@ -718,6 +790,100 @@ func (s *S) TestGetPlans(c *check.C) {
 	c.Assert(plans, check.DeepEquals, []string{"plan1", "plan2"})
 }
 
+func (s *S) TestGetJobPlans(c *check.C) {
+	err := AddPool(context.TODO(), AddPoolOptions{Name: "pool1"})
+	c.Assert(err, check.IsNil)
+	err = SetPoolConstraint(&PoolConstraint{PoolExpr: "pool*", Field: ConstraintTypePlan, Values: []string{"plan1"}})
+	c.Assert(err, check.IsNil)
+	err = SetPoolConstraint(&PoolConstraint{PoolExpr: "pool*", Field: ConstraintTypeJobPlan, Values: []string{"plan2"}})
+	c.Assert(err, check.IsNil)
+	pool, err := GetPoolByName(context.TODO(), "pool1")
+	c.Assert(err, check.IsNil)
+	plans, err := pool.GetJobPlans()
+	c.Assert(err, check.IsNil)
+	c.Assert(plans, check.DeepEquals, []string{"plan2"})
+	plans, err = pool.GetPlans()
+	c.Assert(err, check.IsNil)
+	c.Assert(plans, check.DeepEquals, []string{"plan1"})
+}
+
+func (s *S) TestGetPlansWithGPU(c *check.C) {
+	s.plans = []appTypes.Plan{
+		{Name: "plan1"},
+		{Name: "t4", GPU: 1, GPUType: "nvidia-tesla-t4"},
+		{Name: "a100", GPU: 2, GPUType: "nvidia-tesla-a100"},
+		{Name: "any-gpu", GPU: 1},
+	}
+	err := AddPool(context.TODO(), AddPoolOptions{Name: "pool1"})
+	c.Assert(err, check.IsNil)
+	err = AddPool(context.TODO(), AddPoolOptions{Name: "gpu1"})
+	c.Assert(err, check.IsNil)
+	err = AddPool(context.TODO(), AddPoolOptions{Name: "gpu2"})
+	c.Assert(err, check.IsNil)
+	err = SetPoolConstraint(&PoolConstraint{PoolExpr: "gpu1", Field: ConstraintTypeGPU, Values: []string{"nvidia-tesla-t4"}})
+	c.Assert(err, check.IsNil)
+	err = SetPoolConstraint(&PoolConstraint{PoolExpr: "gpu2", Field: ConstraintTypeGPU, Values: []string{"*"}})
+	c.Assert(err, check.IsNil)
+	tests := map[string][]string{
+		"pool1": {"plan1"},
+		"gpu1":  {"plan1", "t4"},
+		"gpu2":  {"plan1", "t4", "a100", "any-gpu"},
+	}
+	for name, expected := range tests {
+		pool, err := GetPoolByName(context.TODO(), name)
+		c.Assert(err, check.IsNil)
+		plans, err := pool.GetPlans()
+		c.Assert(err, check.IsNil)
+		c.Check(plans, check.DeepEquals, expected, check.Commentf("pool %s", name))
+		plans, err = pool.GetJobPlans()
+		c.Assert(err, check.IsNil)
+		c.Check(plans, check.DeepEquals, expected, check.Commentf("pool %s", name))
+	}
+}
+
+func (s *S) mockPlanLookup() {
+	s.mockPlanService.OnDefaultPlan = func() (*appTypes.Plan, error) {
+		return &s.plans[0], nil
+	}
+	s.mockPlanService.OnFindByName = func(name string) (*appTypes.Plan, error) {
+		for _, p := range s.plans {
+			if p.Name == name {
+				return &p, nil
+			}
+		}
+		return nil, appTypes.ErrPlanNotFound
+	}
+}
+
+func (s *S) TestGetDefaultJobPlan(c *check.C) {
+	s.mockPlanLookup()
+	err := AddPool(context.TODO(), AddPoolOptions{Name: "pool1"})
+	c.Assert(err, check.IsNil)
+	err = SetPoolConstraint(&PoolConstraint{PoolExpr: "pool1", Field: ConstraintTypeJobPlan, Values: []string{"plan2"}})
+	c.Assert(err, check.IsNil)
+	pool, err := GetPoolByName(context.TODO(), "pool1")
+	c.Assert(err, check.IsNil)
+	plan, err := pool.GetDefaultJobPlan()
+	c.Assert(err, check.IsNil)
+	c.Assert(plan.Name, check.Equals, "plan2")
+	plan, err = pool.GetDefaultPlan()
+	c.Assert(err, check.IsNil)
+	c.Assert(plan.Name, check.Equals, "plan1")
+}
+
+func (s *S) TestGetDefaultJobPlanWithoutConstraint(c *check.C) {
+	s.mockPlanLookup()
+	err := AddPool(context.TODO(), AddPoolOptions{Name: "pool1"})
+	c.Assert(err, check.IsNil)
+	err = SetPoolConstraint(&PoolConstraint{PoolExpr: "pool1", Field: ConstraintTypePlan, Values: []string{"plan2"}})
+	c.Assert(err, check.IsNil)
+	pool, err := GetPoolByName(context.TODO(), "pool1")
+	c.Assert(err, check.IsNil)
+	plan, err := pool.GetDefaultJobPlan()
+	c.Assert(err, check.IsNil)
+	c.Assert(plan.Name, check.Equals, "plan2")
+}
+
 func (s *S) TestGetDefaultRouterFromConstraint(c *check.C) {
 	config.Set("routers:router1:type", "hipache")
 	config.Set("routers:router2:type", "hipache")
@@ -819,11 +985,12 @@ func (s *S) TestPoolAllowedValues(c *check.C) {
 		ConstraintTypeService:    nil,
 		ConstraintTypePlan:       {"plan1", "plan2"},
 		ConstraintTypeVolumePlan: {"nfs"},
+		ConstraintTypeJobPlan:    {"plan1", "plan2"},
 	})
 	pool.Name = "other"
 	constraints, err = pool.allowedValues()
 	c.Assert(err, check.IsNil)
-	c.Assert(constraints, check.HasLen, 5)
+	c.Assert(constraints, check.HasLen, 6)
 	sort.Strings(constraints[ConstraintTypeTeam])
 	c.Assert(constraints[ConstraintTypeTeam], check.DeepEquals, []string{
 		"ateam", "pteam", "pubteam", "team1", "test",
@@ -928,3 +1095,46 @@ func (s *S) TestGetAffinity(c *check.C) {
 		t.assertion(t.testName, c, affinity, err)
 	}
 }
+
+func (s *S) TestGetTolerations(c *check.C) {
+	p := Pool{Name: "pool1", Labels: map[string]string{tolerationsKey: `[{"key":"dedicated","operator":"Equal","value":"pool1","effect":"NoSchedule"}]`}}
+	tolerations, err := p.GetTolerations()
+	c.Assert(err, check.IsNil)
+	c.Assert(tolerations, check.DeepEquals, []apiv1.Toleration{
+		{Key: "dedicated", Operator: apiv1.TolerationOpEqual, Value: "pool1", Effect: apiv1.TaintEffectNoSchedule},
+	})
+	p = Pool{Name: "pool1", Labels: map[string]string{tolerationsKey: `invalid tolerations`}}
+	tolerations, err = p.GetTolerations()
+	c.Assert(err, check.NotNil)
+	c.Assert(tolerations, check.IsNil)
+	p = Pool{Name: "pool1"}
+	tolerations, err = p.GetTolerations()
+	c.Assert(err, check.IsNil)
+	c.Assert(tolerations, check.IsNil)
+}
+
+func (s *S) TestGetTopologySpreadConstraints(c *check.C) {
+	p := Pool{Name: "pool1", Labels: map[string]string{topologySpreadKey: `[{"maxSkew":1,"topologyKey":"topology.kubernetes.io/zone","whenUnsatisfiable":"ScheduleAnyway"}]`}}
+	constraints, err := p.GetTopologySpreadConstraints()
+	c.Assert(err, check.IsNil)
+	c.Assert(constraints, check.DeepEquals, []apiv1.TopologySpreadConstraint{
+		{MaxSkew: 1, TopologyKey: "topology.kubernetes.io/zone", WhenUnsatisfiable: apiv1.ScheduleAnyway},
+	})
+	p = Pool{Name: "pool1", Labels: map[string]string{topologySpreadKey: `invalid constraints`}}
+	constraints, err = p.GetTopologySpreadConstraints()
+	c.Assert(err, check.NotNil)
+	c.Assert(constraints, check.IsNil)
+	p = Pool{Name: "pool1"}
+	constraints, err = p.GetTopologySpreadConstraints()
+	c.Assert(err, check.IsNil)
+	c.Assert(constraints, check.IsNil)
+}
+
+func (s *S) TestPoolUpdateInvalidTopologySpreadConstraints(c *check.C) {
+	err := AddPool(context.TODO(), AddPoolOptions{Name: "pool1", Provisioner: "kubernetes"})
+	c.Assert(err, check.IsNil)
+	err = PoolUpdate(context.TODO(), "pool1", UpdatePoolOptions{
+		Labels: map[string]string{topologySpreadKey: `[{"maxSkew":0,"topologyKey":"zone","whenUnsatisfiable":"DoNotSchedule"}]`},
+	})
+	c.Assert(err, check.ErrorMatches, `max skew of topology spread constraint "zone" must be greater than zero`)
+}
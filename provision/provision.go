@@ -13,6 +13,7 @@ import (
 	"io"
 	"net"
 	"net/url"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
@@ -24,6 +25,7 @@ import (
 	"github.com/tsuru/tsuru/event"
 	appTypes "github.com/tsuru/tsuru/types/app"
 	imgTypes "github.com/tsuru/tsuru/types/app/image"
+	jobTypes "github.com/tsuru/tsuru/types/job"
 	provTypes "github.com/tsuru/tsuru/types/provision"
 	volumeTypes "github.com/tsuru/tsuru/types/volume"
 )
@@ -246,6 +248,20 @@ type App interface {
 
 	GetMemory() int64
 	GetMilliCPU() int
+	// GetProcessMemory and GetProcessMilliCPU return the limits of a
+	// process, which may use a plan other than the app one.
+	GetProcessMemory(process string) int64
+	GetProcessMilliCPU(process string) int
+	// GetProcessGPU returns the number and type of GPUs of each unit of the
+	// process, the type is empty when any GPU may be used.
+	GetProcessGPU(process string) (int, string)
+	// GetProcessTerminationGracePeriod returns the time, in seconds, units
+	// of the process have to finish after being asked to stop, 0 means the
+	// provisioner default.
+	GetProcessTerminationGracePeriod(process string) int
+	// GetScheduling returns the tolerations and affinity of the units of
+	// the app, nil when only the ones of the pool are used.
+	GetScheduling() *appTypes.Scheduling
 	GetSwap() int64
 	GetCpuShare() int
 
@@ -263,6 +279,10 @@ type App interface {
 	GetMetadata() appTypes.Metadata
 
 	GetRegistry() (imgTypes.ImageRegistry, error)
+
+	// IsInternal returns whether the app must only be reachable from inside
+	// the cluster, it's never added to routers.
+	IsInternal() bool
 }
 
 type BuilderDockerClient interface {
@@ -475,6 +495,51 @@ type KillUnitProvisioner interface {
 	KillUnit(ctx context.Context, app App, unit string, force bool) error
 }
 
+// RestartUnitProvisioner is a provisioner able to restart a single unit of an
+// app, leaving the other units untouched.
+type RestartUnitProvisioner interface {
+	RestartUnit(ctx context.Context, app App, unit string, w io.Writer) error
+}
+
+// ServiceEnvsProvisioner is a provisioner that must be notified when service
+// instances are bound to, or unbound from, an app without restarting it.
+type ServiceEnvsProvisioner interface {
+	UpdateServiceEnvs(ctx context.Context, app App) error
+}
+
+// PoolResourcesProvisioner is a provisioner able to tell whether the units of
+// an app in its pool and in another pool would be managed by the same
+// resources, e.g. pools using the same kubernetes cluster and namespace.
+type PoolResourcesProvisioner interface {
+	SharesPoolResources(ctx context.Context, app App, otherPool string) (bool, error)
+}
+
+// ClusterResourcesProvisioner is a provisioner able to remove the resources of
+// an app from a cluster that doesn't serve its pool anymore, e.g. after the
+// pool failed over to a standby cluster.
+type ClusterResourcesProvisioner interface {
+	RemoveClusterResources(ctx context.Context, app App, cluster string) error
+}
+
+// DebugOptions holds the options to attach a debug container to a unit.
+type DebugOptions struct {
+	App    App
+	Unit   string
+	Image  string
+	Stdout io.Writer
+	Stderr io.Writer
+	Stdin  io.Reader
+	Width  int
+	Height int
+	Term   string
+}
+
+// DebugUnitProvisioner is a provisioner able to attach a debug container,
+// sharing the process namespace of a running unit, and stream a shell from it.
+type DebugUnitProvisioner interface {
+	DebugUnit(ctx context.Context, opts DebugOptions) error
+}
+
 // HCProvisioner is a provisioner that may handle loadbalancing healthchecks.
 type HCProvisioner interface {
 	// HandlesHC returns true if the provisioner will handle healthchecking
@@ -572,16 +637,56 @@ type VolumeProvisioner interface {
 	DeleteVolume(ctx context.Context, volumeName, pool string) error
 }
 
+// JobProvisioner is a provisioner able to run jobs and cronjobs.
+type JobProvisioner interface {
+	// CreateJob provisions the job, scheduling it when it's a cronjob.
+	// Scheduled runs are retried up to the job retry limit.
+	CreateJob(context.Context, *jobTypes.Job) error
+	// RunJob runs the job once, regardless of its schedule, and waits for it
+	// to finish. Failed runs are not retried.
+	RunJob(context.Context, *jobTypes.Job) error
+	// TriggerCronJob starts a run of the cronjob right away, using the
+	// provisioned schedule as template. It doesn't wait for the run to
+	// finish.
+	TriggerCronJob(context.Context, *jobTypes.Job) error
+	// DestroyJob removes the job schedule and all of its runs.
+	DestroyJob(context.Context, *jobTypes.Job) error
+	// ActiveJobRuns returns the number of runs of the job that haven't
+	// finished yet.
+	ActiveJobRuns(context.Context, *jobTypes.Job) (int, error)
+}
+
 type CleanImageProvisioner interface {
 	CleanImage(appName string, image string) error
 }
 
 type AutoScaleSpec struct {
-	Process    string `json:"process"`
-	MinUnits   uint   `json:"minUnits"`
-	MaxUnits   uint   `json:"maxUnits"`
-	AverageCPU string `json:"averageCPU"`
-	Version    int    `json:"version"`
+	Process    string                `json:"process"`
+	MinUnits   uint                  `json:"minUnits"`
+	MaxUnits   uint                  `json:"maxUnits"`
+	AverageCPU string                `json:"averageCPU"`
+	Version    int                   `json:"version"`
+	Schedules  []AutoScaleSchedule   `json:"schedules,omitempty"`
+	Prometheus []AutoScalePrometheus `json:"prometheus,omitempty"`
+}
+
+// AutoScalePrometheus scales a process based on the result of a Prometheus
+// query, adding units while the query value averaged by the number of units
+// is above the threshold.
+type AutoScalePrometheus struct {
+	Name      string  `json:"name"`
+	Query     string  `json:"query"`
+	Threshold float64 `json:"threshold"`
+}
+
+// AutoScaleSchedule replaces the autoscale minimum and maximum units of a
+// process between the times matched by the Start and End cron expressions,
+// e.g. to scale up before a known traffic peak.
+type AutoScaleSchedule struct {
+	Start    string `json:"start"`
+	End      string `json:"end"`
+	MinUnits uint   `json:"minUnits"`
+	MaxUnits uint   `json:"maxUnits"`
 }
 
 type RecommendedResources struct {
@@ -607,7 +712,7 @@ func (s AutoScaleSpec) ToCPUValue(a App) (int, error) {
 		cpu = cpu / 10
 	}
 
-	cpuLimit := a.GetMilliCPU()
+	cpuLimit := a.GetProcessMilliCPU(s.Process)
 	if cpuLimit == 0 {
 		// No cpu limit is set in app, the AverageCPU value must be considered
 		// as absolute milli cores and we cannot validate it.
@@ -635,9 +740,67 @@ func (s AutoScaleSpec) Validate(quotaLimit int, a App) error {
 	if quotaLimit > 0 && s.MaxUnits > uint(quotaLimit) {
 		return errors.New("maximum units cannot be greater than quota limit")
 	}
-	_, err := s.ToCPUValue(a)
-	if err != nil {
-		return err
+	if s.AverageCPU != "" || len(s.Prometheus) == 0 {
+		_, err := s.ToCPUValue(a)
+		if err != nil {
+			return err
+		}
+	}
+	names := map[string]struct{}{}
+	for _, metric := range s.Prometheus {
+		err := metric.validate()
+		if err != nil {
+			return err
+		}
+		if _, ok := names[metric.ExternalMetricName()]; ok {
+			return errors.Errorf("duplicated prometheus metric name %q", metric.Name)
+		}
+		names[metric.ExternalMetricName()] = struct{}{}
+	}
+	for _, schedule := range s.Schedules {
+		err := schedule.validate(quotaLimit)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var prometheusMetricNameRegexp = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+
+// ExternalMetricName returns the metric name as a valid kubernetes name, used
+// to name the external metric and its query in the HPA.
+func (m AutoScalePrometheus) ExternalMetricName() string {
+	return strings.Trim(ValidKubeName(m.Name), "-.")
+}
+
+func (m AutoScalePrometheus) validate() error {
+	if !prometheusMetricNameRegexp.MatchString(m.Name) || m.ExternalMetricName() == "" {
+		return errors.Errorf("invalid prometheus metric name %q", m.Name)
+	}
+	if strings.TrimSpace(m.Query) == "" {
+		return errors.Errorf("prometheus metric %q requires a query", m.Name)
+	}
+	if m.Threshold <= 0 {
+		return errors.Errorf("prometheus metric %q threshold must be greater than 0", m.Name)
+	}
+	return nil
+}
+
+func (s AutoScaleSchedule) validate(quotaLimit int) error {
+	for _, expr := range []string{s.Start, s.End} {
+		if len(strings.Fields(expr)) != 5 {
+			return errors.Errorf("invalid schedule cron expression %q, it must have 5 fields", expr)
+		}
+	}
+	if s.MinUnits == 0 {
+		return errors.New("schedule minimum units must be greater than 0")
+	}
+	if s.MaxUnits < s.MinUnits {
+		return errors.New("schedule maximum units must be greater than or equal to minimum units")
+	}
+	if quotaLimit > 0 && s.MaxUnits > uint(quotaLimit) {
+		return errors.New("schedule maximum units cannot be greater than quota limit")
 	}
 	return nil
 }
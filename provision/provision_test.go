@@ -235,3 +235,95 @@ func (ProvisionSuite) TestValidate(c *check.C) {
 		c.Check(err, check.ErrorMatches, test.expected)
 	}
 }
+
+func (ProvisionSuite) TestAutoScalePrometheusValidate(c *check.C) {
+	var tests = []struct {
+		input    AutoScalePrometheus
+		expected string
+	}{
+		{
+			AutoScalePrometheus{Name: "", Query: "up", Threshold: 1},
+			`invalid prometheus metric name ""`,
+		},
+		{
+			AutoScalePrometheus{Name: "requests-per-second", Query: "up", Threshold: 1},
+			`invalid prometheus metric name "requests-per-second"`,
+		},
+		{
+			AutoScalePrometheus{Name: "__", Query: "up", Threshold: 1},
+			`invalid prometheus metric name "__"`,
+		},
+		{
+			AutoScalePrometheus{Name: "rps", Query: " ", Threshold: 1},
+			`prometheus metric "rps" requires a query`,
+		},
+		{
+			AutoScalePrometheus{Name: "rps", Query: "up", Threshold: 0},
+			`prometheus metric "rps" threshold must be greater than 0`,
+		},
+	}
+	for _, test := range tests {
+		err := test.input.validate()
+		c.Check(err, check.ErrorMatches, test.expected)
+	}
+	err := AutoScalePrometheus{Name: "requests_per_second", Query: "sum(rate(http_requests_total[1m]))", Threshold: 0.5}.validate()
+	c.Check(err, check.IsNil)
+}
+
+func (ProvisionSuite) TestValidateWithPrometheus(c *check.C) {
+	spec := AutoScaleSpec{
+		MinUnits: 1,
+		MaxUnits: 5,
+		Prometheus: []AutoScalePrometheus{
+			{Name: "rps", Query: "up", Threshold: 1},
+			{Name: "queue", Query: "up", Threshold: 1},
+		},
+	}
+	c.Check(spec.Validate(10, nil), check.IsNil)
+	spec.Prometheus = append(spec.Prometheus, AutoScalePrometheus{Name: "rps", Query: "up", Threshold: 2})
+	c.Check(spec.Validate(10, nil), check.ErrorMatches, `duplicated prometheus metric name "rps"`)
+	spec.Prometheus = []AutoScalePrometheus{
+		{Name: "queue_depth", Query: "up", Threshold: 1},
+		{Name: "queue:depth", Query: "up", Threshold: 1},
+	}
+	c.Check(spec.Validate(10, nil), check.ErrorMatches, `duplicated prometheus metric name "queue:depth"`)
+}
+
+func (ProvisionSuite) TestAutoScalePrometheusExternalMetricName(c *check.C) {
+	c.Check(AutoScalePrometheus{Name: "requests_per_second"}.ExternalMetricName(), check.Equals, "requests-per-second")
+	c.Check(AutoScalePrometheus{Name: "_Queue:Depth_"}.ExternalMetricName(), check.Equals, "queue-depth")
+}
+
+func (ProvisionSuite) TestAutoScaleScheduleValidate(c *check.C) {
+	var tests = []struct {
+		input    AutoScaleSchedule
+		expected string
+	}{
+		{
+			AutoScaleSchedule{Start: "0 8 * *", End: "0 20 * * *", MinUnits: 2, MaxUnits: 5},
+			`invalid schedule cron expression "0 8 \* \*", it must have 5 fields`,
+		},
+		{
+			AutoScaleSchedule{Start: "0 8 * * *", End: "", MinUnits: 2, MaxUnits: 5},
+			`invalid schedule cron expression "", it must have 5 fields`,
+		},
+		{
+			AutoScaleSchedule{Start: "0 8 * * *", End: "0 20 * * *", MinUnits: 0, MaxUnits: 5},
+			"schedule minimum units must be greater than 0",
+		},
+		{
+			AutoScaleSchedule{Start: "0 8 * * *", End: "0 20 * * *", MinUnits: 6, MaxUnits: 5},
+			"schedule maximum units must be greater than or equal to minimum units",
+		},
+		{
+			AutoScaleSchedule{Start: "0 8 * * *", End: "0 20 * * *", MinUnits: 5, MaxUnits: 11},
+			"schedule maximum units cannot be greater than quota limit",
+		},
+	}
+	for _, test := range tests {
+		err := test.input.validate(10)
+		c.Check(err, check.ErrorMatches, test.expected)
+	}
+	err := AutoScaleSchedule{Start: "0 8 * * 1-5", End: "0 20 * * 1-5", MinUnits: 5, MaxUnits: 5}.validate(10)
+	c.Check(err, check.IsNil)
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/tsuru/tsuru/router/routertest"
 	appTypes "github.com/tsuru/tsuru/types/app"
 	imgTypes "github.com/tsuru/tsuru/types/app/image"
+	jobTypes "github.com/tsuru/tsuru/types/job"
 	provTypes "github.com/tsuru/tsuru/types/provision"
 	volumeTypes "github.com/tsuru/tsuru/types/volume"
 )
@@ -37,21 +38,27 @@ var (
 	errNotProvisioned         = &provision.Error{Reason: "App is not provisioned."}
 	uniqueIpCounter     int32 = 0
 
-	_ provision.Provisioner              = &FakeProvisioner{}
-	_ provision.NodeProvisioner          = &FakeProvisioner{}
-	_ provision.NodeContainerProvisioner = &FakeProvisioner{}
-	_ provision.InterAppProvisioner      = &FakeProvisioner{}
-	_ provision.UpdatableProvisioner     = &FakeProvisioner{}
-	_ provision.Provisioner              = &FakeProvisioner{}
-	_ provision.LogsProvisioner          = &FakeProvisioner{}
-	_ provision.MetricsProvisioner       = &FakeProvisioner{}
-	_ provision.VolumeProvisioner        = &FakeProvisioner{}
-	_ provision.SleepableProvisioner     = &FakeProvisioner{}
-	_ provision.AppFilterProvisioner     = &FakeProvisioner{}
-	_ provision.ExecutableProvisioner    = &FakeProvisioner{}
-	_ provision.NodeRebalanceProvisioner = &FakeProvisioner{}
-	_ provision.App                      = &FakeApp{}
-	_ bind.App                           = &FakeApp{}
+	_ provision.Provisioner                 = &FakeProvisioner{}
+	_ provision.NodeProvisioner             = &FakeProvisioner{}
+	_ provision.NodeContainerProvisioner    = &FakeProvisioner{}
+	_ provision.InterAppProvisioner         = &FakeProvisioner{}
+	_ provision.UpdatableProvisioner        = &FakeProvisioner{}
+	_ provision.Provisioner                 = &FakeProvisioner{}
+	_ provision.LogsProvisioner             = &FakeProvisioner{}
+	_ provision.MetricsProvisioner          = &FakeProvisioner{}
+	_ provision.VolumeProvisioner           = &FakeProvisioner{}
+	_ provision.JobProvisioner              = &FakeProvisioner{}
+	_ provision.SleepableProvisioner        = &FakeProvisioner{}
+	_ provision.AppFilterProvisioner        = &FakeProvisioner{}
+	_ provision.ExecutableProvisioner       = &FakeProvisioner{}
+	_ provision.NodeRebalanceProvisioner    = &FakeProvisioner{}
+	_ provision.RestartUnitProvisioner      = &FakeProvisioner{}
+	_ provision.DebugUnitProvisioner        = &FakeProvisioner{}
+	_ provision.ServiceEnvsProvisioner      = &FakeProvisioner{}
+	_ provision.PoolResourcesProvisioner    = &FakeProvisioner{}
+	_ provision.ClusterResourcesProvisioner = &FakeProvisioner{}
+	_ provision.App                         = &FakeApp{}
+	_ bind.App                              = &FakeApp{}
 )
 
 func init() {
@@ -77,6 +84,8 @@ type FakeApp struct {
 	Swap              int64
 	CpuShare          int
 	MilliCPU          int
+	GPU               int
+	GPUType           string
 	commMut           sync.Mutex
 	Deploys           uint
 	env               map[string]bind.EnvVar
@@ -91,6 +100,10 @@ type FakeApp struct {
 	Tags              []string
 	Metadata          appTypes.Metadata
 	InternalAddresses []provision.AppInternalAddress
+	ProcessPlans      map[string]appTypes.Plan
+	Internal          bool
+	GracePeriods      map[string]int
+	Scheduling        *appTypes.Scheduling
 }
 
 func NewFakeApp(name, platform string, units int) *FakeApp {
@@ -131,6 +144,27 @@ func (a *FakeApp) GetMemory() int64 {
 	return a.Memory
 }
 
+func (a *FakeApp) GetProcessMilliCPU(process string) int {
+	if plan, ok := a.ProcessPlans[process]; ok {
+		return plan.CPUMilli
+	}
+	return a.MilliCPU
+}
+
+func (a *FakeApp) GetProcessMemory(process string) int64 {
+	if plan, ok := a.ProcessPlans[process]; ok {
+		return plan.Memory
+	}
+	return a.Memory
+}
+
+func (a *FakeApp) GetProcessGPU(process string) (int, string) {
+	if plan, ok := a.ProcessPlans[process]; ok {
+		return plan.GPU, plan.GPUType
+	}
+	return a.GPU, a.GPUType
+}
+
 func (a *FakeApp) GetSwap() int64 {
 	return a.Swap
 }
@@ -372,6 +406,18 @@ func (app *FakeApp) GetMetadata() appTypes.Metadata {
 	return app.Metadata
 }
 
+func (app *FakeApp) IsInternal() bool {
+	return app.Internal
+}
+
+func (app *FakeApp) GetProcessTerminationGracePeriod(process string) int {
+	return app.GracePeriods[process]
+}
+
+func (app *FakeApp) GetScheduling() *appTypes.Scheduling {
+	return app.Scheduling
+}
+
 func (app *FakeApp) GetRegistry() (imgTypes.ImageRegistry, error) {
 	return "", nil
 }
@@ -396,9 +442,15 @@ type FakeProvisioner struct {
 	apps           map[string]provisionedApp
 	mut            sync.RWMutex
 	execs          map[string][]provision.ExecOptions
+	debugs         map[string][]provision.DebugOptions
 	execsMut       sync.Mutex
 	nodes          map[string]FakeNode
 	nodeContainers map[string]int
+	cronJobs       map[string]string
+	jobRuns        map[string]int
+	activeJobRuns  map[string]int
+	sharedPools    map[string]string
+	removedFrom    map[string][]string
 }
 
 func NewFakeProvisioner() *FakeProvisioner {
@@ -407,8 +459,14 @@ func NewFakeProvisioner() *FakeProvisioner {
 	p.failures = make(chan failure, 8)
 	p.apps = make(map[string]provisionedApp)
 	p.execs = make(map[string][]provision.ExecOptions)
+	p.debugs = make(map[string][]provision.DebugOptions)
 	p.nodes = make(map[string]FakeNode)
 	p.nodeContainers = make(map[string]int)
+	p.cronJobs = make(map[string]string)
+	p.jobRuns = make(map[string]int)
+	p.activeJobRuns = make(map[string]int)
+	p.sharedPools = make(map[string]string)
+	p.removedFrom = make(map[string][]string)
 	return &p
 }
 
@@ -752,6 +810,21 @@ func (p *FakeProvisioner) Restarts(a provision.App, process string) int {
 	return p.apps[a.GetName()].restarts[process]
 }
 
+// ServiceEnvsUpdates returns the number of times the provisioner was notified
+// about changed service envs of the app.
+func (p *FakeProvisioner) ServiceEnvsUpdates(a provision.App) int {
+	p.mut.RLock()
+	defer p.mut.RUnlock()
+	return p.apps[a.GetName()].serviceEnvsUpdates
+}
+
+// UnitRestarts returns the number of restarts for a given unit.
+func (p *FakeProvisioner) UnitRestarts(a provision.App, unit string) int {
+	p.mut.RLock()
+	defer p.mut.RUnlock()
+	return p.apps[a.GetName()].unitRestarts[unit]
+}
+
 // Starts returns the number of starts for a given app.
 func (p *FakeProvisioner) Starts(app provision.App, process string) int {
 	p.mut.RLock()
@@ -786,6 +859,13 @@ func (p *FakeProvisioner) Execs(unit string) []provision.ExecOptions {
 	return p.execs[unit]
 }
 
+// Debugs return all debug calls to the given unit.
+func (p *FakeProvisioner) Debugs(unit string) []provision.DebugOptions {
+	p.execsMut.Lock()
+	defer p.execsMut.Unlock()
+	return p.debugs[unit]
+}
+
 // AllExecs return all exec calls to all units.
 func (p *FakeProvisioner) AllExecs() map[string][]provision.ExecOptions {
 	p.execsMut.Lock()
@@ -856,6 +936,7 @@ func (p *FakeProvisioner) Reset() {
 
 	p.execsMut.Lock()
 	p.execs = make(map[string][]provision.ExecOptions)
+	p.debugs = make(map[string][]provision.DebugOptions)
 	p.execsMut.Unlock()
 
 	p.mut.Lock()
@@ -865,6 +946,14 @@ func (p *FakeProvisioner) Reset() {
 
 	p.nodeContainers = make(map[string]int)
 
+	p.mut.Lock()
+	p.cronJobs = make(map[string]string)
+	p.jobRuns = make(map[string]int)
+	p.activeJobRuns = make(map[string]int)
+	p.sharedPools = make(map[string]string)
+	p.removedFrom = make(map[string][]string)
+	p.mut.Unlock()
+
 	for {
 		select {
 		case <-p.outputs:
@@ -943,11 +1032,12 @@ func (p *FakeProvisioner) Provision(ctx context.Context, app provision.App) erro
 	p.mut.Lock()
 	defer p.mut.Unlock()
 	p.apps[app.GetName()] = provisionedApp{
-		app:      app,
-		restarts: make(map[string]int),
-		starts:   make(map[string]int),
-		stops:    make(map[string]int),
-		sleeps:   make(map[string]int),
+		app:          app,
+		restarts:     make(map[string]int),
+		unitRestarts: make(map[string]int),
+		starts:       make(map[string]int),
+		stops:        make(map[string]int),
+		sleeps:       make(map[string]int),
 	}
 	return nil
 }
@@ -970,6 +1060,43 @@ func (p *FakeProvisioner) Restart(ctx context.Context, app provision.App, proces
 	return nil
 }
 
+func (p *FakeProvisioner) UpdateServiceEnvs(ctx context.Context, app provision.App) error {
+	if err := p.getError("UpdateServiceEnvs"); err != nil {
+		return err
+	}
+	p.mut.Lock()
+	defer p.mut.Unlock()
+	pApp, ok := p.apps[app.GetName()]
+	if !ok {
+		return nil
+	}
+	pApp.serviceEnvsUpdates++
+	p.apps[app.GetName()] = pApp
+	return nil
+}
+
+func (p *FakeProvisioner) RestartUnit(ctx context.Context, app provision.App, unit string, w io.Writer) error {
+	if err := p.getError("RestartUnit"); err != nil {
+		return err
+	}
+	p.mut.Lock()
+	defer p.mut.Unlock()
+	pApp, ok := p.apps[app.GetName()]
+	if !ok {
+		return errNotProvisioned
+	}
+	for _, u := range pApp.units {
+		if u.ID == unit {
+			pApp.unitRestarts[unit]++
+			if w != nil {
+				fmt.Fprintf(w, "restarting unit %s", unit)
+			}
+			return nil
+		}
+	}
+	return &provision.UnitNotFoundError{ID: unit}
+}
+
 func (p *FakeProvisioner) Start(ctx context.Context, app provision.App, process string, version appTypes.AppVersion, w io.Writer) error {
 	p.mut.Lock()
 	defer p.mut.Unlock()
@@ -1157,6 +1284,39 @@ func (p *FakeProvisioner) UnitsMetrics(ctx context.Context, a provision.App) ([]
 	return unitsMetrics, nil
 }
 
+// MockSharedPools makes the provisioner report that both pools share the
+// same resources.
+func (p *FakeProvisioner) MockSharedPools(pool, otherPool string) {
+	p.mut.Lock()
+	defer p.mut.Unlock()
+	p.sharedPools[pool] = otherPool
+	p.sharedPools[otherPool] = pool
+}
+
+func (p *FakeProvisioner) SharesPoolResources(ctx context.Context, app provision.App, otherPool string) (bool, error) {
+	p.mut.RLock()
+	defer p.mut.RUnlock()
+	return p.sharedPools[app.GetPool()] == otherPool, nil
+}
+
+func (p *FakeProvisioner) RemoveClusterResources(ctx context.Context, app provision.App, cluster string) error {
+	if err := p.getError("RemoveClusterResources"); err != nil {
+		return err
+	}
+	p.mut.Lock()
+	defer p.mut.Unlock()
+	p.removedFrom[app.GetName()] = append(p.removedFrom[app.GetName()], cluster)
+	return nil
+}
+
+// RemovedClusterResources returns the clusters the app was removed from by
+// RemoveClusterResources.
+func (p *FakeProvisioner) RemovedClusterResources(app provision.App) []string {
+	p.mut.RLock()
+	defer p.mut.RUnlock()
+	return p.removedFrom[app.GetName()]
+}
+
 func (p *FakeProvisioner) MockRoutableAddresses(app provision.App, addrs []appTypes.RoutableAddresses) {
 	p.mut.Lock()
 	defer p.mut.Unlock()
@@ -1358,6 +1518,28 @@ func (p *FakeProvisioner) ExecuteCommand(ctx context.Context, opts provision.Exe
 	return err
 }
 
+func (p *FakeProvisioner) DebugUnit(ctx context.Context, opts provision.DebugOptions) error {
+	if err := p.getError("DebugUnit"); err != nil {
+		return err
+	}
+	units, err := p.Units(ctx, opts.App)
+	if err != nil {
+		return err
+	}
+	for _, u := range units {
+		if u.ID == opts.Unit {
+			p.execsMut.Lock()
+			p.debugs[opts.Unit] = append(p.debugs[opts.Unit], opts)
+			p.execsMut.Unlock()
+			if opts.Stdout != nil {
+				fmt.Fprintf(opts.Stdout, "debugging unit %s", opts.Unit)
+			}
+			return nil
+		}
+	}
+	return &provision.UnitNotFoundError{ID: opts.Unit}
+}
+
 func (p *FakeProvisioner) FilterAppsByUnitStatus(ctx context.Context, apps []provision.App, status []string) ([]provision.App, error) {
 	filteredApps := []provision.App{}
 	for i := range apps {
@@ -1401,6 +1583,86 @@ func (p *FakeProvisioner) IsVolumeProvisioned(ctx context.Context, name, pool st
 	return false, nil
 }
 
+func (p *FakeProvisioner) CreateJob(ctx context.Context, job *jobTypes.Job) error {
+	if err := p.getError("CreateJob"); err != nil {
+		return err
+	}
+	if !job.IsCron() {
+		return nil
+	}
+	p.mut.Lock()
+	defer p.mut.Unlock()
+	p.cronJobs[job.Name] = job.Schedule
+	return nil
+}
+
+func (p *FakeProvisioner) RunJob(ctx context.Context, job *jobTypes.Job) error {
+	if err := p.getError("RunJob"); err != nil {
+		return err
+	}
+	p.mut.Lock()
+	defer p.mut.Unlock()
+	p.jobRuns[job.Name]++
+	return nil
+}
+
+func (p *FakeProvisioner) TriggerCronJob(ctx context.Context, job *jobTypes.Job) error {
+	if err := p.getError("TriggerCronJob"); err != nil {
+		return err
+	}
+	p.mut.Lock()
+	defer p.mut.Unlock()
+	if _, ok := p.cronJobs[job.Name]; !ok {
+		return errors.Errorf("cronjob %q not found", job.Name)
+	}
+	p.jobRuns[job.Name]++
+	return nil
+}
+
+func (p *FakeProvisioner) DestroyJob(ctx context.Context, job *jobTypes.Job) error {
+	if err := p.getError("DestroyJob"); err != nil {
+		return err
+	}
+	p.mut.Lock()
+	defer p.mut.Unlock()
+	delete(p.cronJobs, job.Name)
+	delete(p.jobRuns, job.Name)
+	delete(p.activeJobRuns, job.Name)
+	return nil
+}
+
+func (p *FakeProvisioner) ActiveJobRuns(ctx context.Context, job *jobTypes.Job) (int, error) {
+	if err := p.getError("ActiveJobRuns"); err != nil {
+		return 0, err
+	}
+	p.mut.RLock()
+	defer p.mut.RUnlock()
+	return p.activeJobRuns[job.Name], nil
+}
+
+// SetActiveJobRuns sets the number of runs of the job reported as still
+// running by ActiveJobRuns.
+func (p *FakeProvisioner) SetActiveJobRuns(name string, n int) {
+	p.mut.Lock()
+	defer p.mut.Unlock()
+	p.activeJobRuns[name] = n
+}
+
+// CronJob returns the schedule of the job, if it's provisioned as a cronjob.
+func (p *FakeProvisioner) CronJob(name string) (string, bool) {
+	p.mut.RLock()
+	defer p.mut.RUnlock()
+	schedule, ok := p.cronJobs[name]
+	return schedule, ok
+}
+
+// JobRuns returns how many times the job was run.
+func (p *FakeProvisioner) JobRuns(name string) int {
+	p.mut.RLock()
+	defer p.mut.RUnlock()
+	return p.jobRuns[name]
+}
+
 func (p *FakeProvisioner) UpdateApp(ctx context.Context, old, new provision.App, w io.Writer) error {
 	provApp := p.apps[old.GetName()]
 	provApp.app = new
@@ -1515,17 +1777,19 @@ func (p *PipelineErrorFakeProvisioner) DeployPipeline() *action.Pipeline {
 }
 
 type provisionedApp struct {
-	units     []provision.Unit
-	app       provision.App
-	restarts  map[string]int
-	starts    map[string]int
-	stops     map[string]int
-	sleeps    map[string]int
-	cnames    []string
-	unitLen   int
-	lastData  map[string]interface{}
-	image     string
-	mockAddrs []appTypes.RoutableAddresses
+	units              []provision.Unit
+	app                provision.App
+	restarts           map[string]int
+	serviceEnvsUpdates int
+	unitRestarts       map[string]int
+	starts             map[string]int
+	stops              map[string]int
+	sleeps             map[string]int
+	cnames             []string
+	unitLen            int
+	lastData           map[string]interface{}
+	image              string
+	mockAddrs          []appTypes.RoutableAddresses
 }
 
 type AutoScaleProvisioner struct {
@@ -1547,7 +1811,7 @@ func (p *AutoScaleProvisioner) GetVerticalAutoScaleRecommendations(ctx context.C
 		return nil, nil
 	}
 	return []provision.RecommendedResources{
-		{Process: "p1", Recommendations: []provision.RecommendedProcessResources{{Type: "target", CPU: "100m", Memory: "100MiB"}}},
+		{Process: "p1", Recommendations: []provision.RecommendedProcessResources{{Type: "target", CPU: "100m", Memory: "100Mi"}}},
 	}, nil
 }
 
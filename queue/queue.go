@@ -17,8 +17,41 @@ import (
 	"github.com/tsuru/monsterqueue"
 	"github.com/tsuru/monsterqueue/mongodb"
 	"github.com/tsuru/tsuru/api/shutdown"
+	"github.com/tsuru/tsuru/db/storage"
+	"github.com/tsuru/tsuru/hc"
 )
 
+func init() {
+	hc.AddChecker("Queue", healthCheck)
+}
+
+// healthCheck pings the queue database, the queue is only checked when it's
+// in use by this process.
+func healthCheck(ctx context.Context) error {
+	queueData.RLock()
+	inUse := queueData.instance != nil
+	queueData.RUnlock()
+	if !inUse {
+		return hc.ErrDisabledComponent
+	}
+	url, dbname := queueDBConfig()
+	conn, err := storage.Open(url, dbname)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	return conn.Collection("queue_tasks").Database.Session.Ping()
+}
+
+func queueDBConfig() (string, string) {
+	queueMongoURL, _ := config.GetString("queue:mongo-url")
+	if queueMongoURL == "" {
+		queueMongoURL = "localhost:27017"
+	}
+	queueMongoDB, _ := config.GetString("queue:mongo-database")
+	return queueMongoURL, queueMongoDB
+}
+
 type queueInstanceData struct {
 	sync.RWMutex
 	instance monsterqueue.Queue
@@ -91,11 +124,7 @@ func Queue() (monsterqueue.Queue, error) {
 	if queueData.instance != nil {
 		return queueData.instance, nil
 	}
-	queueMongoURL, _ := config.GetString("queue:mongo-url")
-	if queueMongoURL == "" {
-		queueMongoURL = "localhost:27017"
-	}
-	queueMongoDB, _ := config.GetString("queue:mongo-database")
+	queueMongoURL, queueMongoDB := queueDBConfig()
 	pollingInterval, _ := config.GetFloat("queue:mongo-polling-interval")
 	if pollingInterval == 0.0 {
 		pollingInterval = 1.0
@@ -13,6 +13,7 @@ import (
 	"github.com/tsuru/config"
 	"github.com/tsuru/monsterqueue"
 	"github.com/tsuru/tsuru/api/shutdown"
+	"github.com/tsuru/tsuru/hc"
 	check "gopkg.in/check.v1"
 )
 
@@ -57,3 +58,12 @@ func (s *S) TestQueue(c *check.C) {
 	shutdown.Do(context.Background(), ioutil.Discard)
 	c.Assert(queueData.instance, check.IsNil)
 }
+
+func (s *S) TestHealthCheck(c *check.C) {
+	err := healthCheck(context.TODO())
+	c.Assert(err, check.Equals, hc.ErrDisabledComponent)
+	_, err = Queue()
+	c.Assert(err, check.IsNil)
+	err = healthCheck(context.TODO())
+	c.Assert(err, check.IsNil)
+}
@@ -16,6 +16,8 @@ import (
 )
 
 var capMap = map[string][]string{
+	"activity":    {"router.ActivityRouter", "apiRouterWithActivity"},
+	"maintenance": {"router.MaintenanceRouter", "apiRouterWithMaintenance"},
 	"v2":          {"router.RouterV2", "apiRouterV2"},
 	"cname":       {"router.CNameRouter", "apiRouterWithCnameSupport"},
 	"tls":         {"router.TLSRouter", "apiRouterWithTLSSupport"},
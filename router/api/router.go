@@ -15,8 +15,10 @@ import (
 	"net/url"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/pkg/errors"
+	"github.com/tsuru/tsuru/hc"
 	"github.com/tsuru/tsuru/log"
 	"github.com/tsuru/tsuru/net"
 	poolMultiCluster "github.com/tsuru/tsuru/provision/pool/multicluster"
@@ -41,6 +43,8 @@ var (
 	_ router.InfoRouter              = &apiRouterWithInfo{}
 	_ router.StatusRouter            = &apiRouterWithStatus{}
 	_ router.PrefixRouter            = &apiRouterWithPrefix{}
+	_ router.ActivityRouter          = &apiRouterWithActivity{}
+	_ router.MaintenanceRouter       = &apiRouterWithMaintenance{}
 )
 
 type apiRouter struct {
@@ -68,6 +72,10 @@ type apiRouterWithStatus struct{ *apiRouter }
 
 type apiRouterWithPrefix struct{ *apiRouter }
 
+type apiRouterWithActivity struct{ *apiRouter }
+
+type apiRouterWithMaintenance struct{ *apiRouter }
+
 type routesReq struct {
 	Prefix    string            `json:"prefix"`
 	Addresses []string          `json:"addresses"`
@@ -93,6 +101,10 @@ type certData struct {
 	Key         string `json:"key"`
 }
 
+type activityResp struct {
+	LastRequest time.Time `json:"lastRequest"`
+}
+
 type backendResp struct {
 	Address   string   `json:"address"`
 	Addresses []string `json:"addresses"`
@@ -108,12 +120,15 @@ var (
 	capStatus      = capability("status")
 	capPrefix      = capability("prefix")
 	capV2          = capability("v2")
+	capActivity    = capability("activity")
+	capMaintenance = capability("maintenance")
 
-	allCaps = []capability{capCName, capTLS, capHealthcheck, capInfo, capStatus, capPrefix, capV2}
+	allCaps = []capability{capCName, capTLS, capHealthcheck, capInfo, capStatus, capPrefix, capV2, capActivity, capMaintenance}
 )
 
 func init() {
 	router.Register(routerType, createRouter)
+	hc.AddChecker("Router API", router.BuildHealthCheck(routerType))
 }
 
 func createRouter(routerName string, config router.ConfigGetter) (router.Router, error) {
@@ -607,6 +622,66 @@ func (r *apiRouterWithStatus) GetBackendStatus(ctx context.Context, app router.A
 	return rsp, nil
 }
 
+func (r *apiRouterWithActivity) LastActivity(ctx context.Context, app router.App) (time.Time, error) {
+	backendName, err := router.Retrieve(app.GetName())
+	if err != nil {
+		return time.Time{}, err
+	}
+	headers, err := r.getExtraHeadersFromApp(ctx, app)
+	if err != nil {
+		return time.Time{}, err
+	}
+	data, code, err := r.do(ctx, http.MethodGet, fmt.Sprintf("backend/%s/activity", backendName), headers, nil)
+	if code == http.StatusNotFound {
+		return time.Time{}, router.ErrBackendNotFound
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	var rsp activityResp
+	err = json.Unmarshal(data, &rsp)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return rsp.LastRequest, nil
+}
+
+func (r *apiRouterWithMaintenance) SetMaintenance(ctx context.Context, app router.App, opts router.MaintenanceOpts) error {
+	backendName, err := router.Retrieve(app.GetName())
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(opts)
+	if err != nil {
+		return err
+	}
+	headers, err := r.getExtraHeadersFromApp(ctx, app)
+	if err != nil {
+		return err
+	}
+	_, code, err := r.do(ctx, http.MethodPut, fmt.Sprintf("backend/%s/maintenance", backendName), headers, bytes.NewReader(b))
+	if code == http.StatusNotFound {
+		return router.ErrBackendNotFound
+	}
+	return err
+}
+
+func (r *apiRouterWithMaintenance) UnsetMaintenance(ctx context.Context, app router.App) error {
+	backendName, err := router.Retrieve(app.GetName())
+	if err != nil {
+		return err
+	}
+	headers, err := r.getExtraHeadersFromApp(ctx, app)
+	if err != nil {
+		return err
+	}
+	_, code, err := r.do(ctx, http.MethodDelete, fmt.Sprintf("backend/%s/maintenance", backendName), headers, nil)
+	if code == http.StatusNotFound {
+		return router.ErrBackendNotFound
+	}
+	return err
+}
+
 func (r *apiRouterWithPrefix) Addresses(ctx context.Context, app router.App) (addrs []string, err error) {
 	backendName, err := router.Retrieve(app.GetName())
 	if err != nil {
@@ -15,6 +15,7 @@ import (
 	"sort"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/tsuru/config"
@@ -457,6 +458,40 @@ func (s *S) TestHealcheckBackendNotFound(c *check.C) {
 	c.Assert(err, check.DeepEquals, router.ErrBackendNotFound)
 }
 
+func (s *S) TestLastActivity(c *check.C) {
+	lastRequest := time.Date(2022, 3, 10, 15, 4, 5, 0, time.UTC)
+	s.apiRouter.backends["mybackend"].lastRequest = lastRequest
+	activityRouter := &apiRouterWithActivity{s.testRouter}
+	activity, err := activityRouter.LastActivity(context.TODO(), routertest.FakeApp{Name: "mybackend"})
+	c.Assert(err, check.IsNil)
+	c.Assert(activity.Equal(lastRequest), check.Equals, true)
+}
+
+func (s *S) TestLastActivityBackendNotFound(c *check.C) {
+	activityRouter := &apiRouterWithActivity{s.testRouter}
+	_, err := activityRouter.LastActivity(context.TODO(), routertest.FakeApp{Name: "invalid"})
+	c.Assert(err, check.DeepEquals, router.ErrBackendNotFound)
+}
+
+func (s *S) TestSetMaintenance(c *check.C) {
+	maintenanceRouter := &apiRouterWithMaintenance{s.testRouter}
+	opts := router.MaintenanceOpts{Page: "<h1>down</h1>", RetryAfter: 120}
+	err := maintenanceRouter.SetMaintenance(context.TODO(), routertest.FakeApp{Name: "mybackend"}, opts)
+	c.Assert(err, check.IsNil)
+	c.Assert(s.apiRouter.backends["mybackend"].maintenance, check.DeepEquals, &opts)
+	err = maintenanceRouter.UnsetMaintenance(context.TODO(), routertest.FakeApp{Name: "mybackend"})
+	c.Assert(err, check.IsNil)
+	c.Assert(s.apiRouter.backends["mybackend"].maintenance, check.IsNil)
+}
+
+func (s *S) TestSetMaintenanceBackendNotFound(c *check.C) {
+	maintenanceRouter := &apiRouterWithMaintenance{s.testRouter}
+	err := maintenanceRouter.SetMaintenance(context.TODO(), routertest.FakeApp{Name: "invalid"}, router.MaintenanceOpts{})
+	c.Assert(err, check.DeepEquals, router.ErrBackendNotFound)
+	err = maintenanceRouter.UnsetMaintenance(context.TODO(), routertest.FakeApp{Name: "invalid"})
+	c.Assert(err, check.DeepEquals, router.ErrBackendNotFound)
+}
+
 // Router V2 exclusive APIs
 func (s *S) TestEnsureBackend(c *check.C) {
 	routerV2 := &apiRouterV2{s.testRouter}
@@ -614,6 +649,9 @@ func newFakeRouter(c *check.C) *fakeRouterAPI {
 	r.HandleFunc("/backend/{name}/certificate/{cname}", api.addCertificate).Methods(http.MethodPut)
 	r.HandleFunc("/backend/{name}/certificate/{cname}", api.removeCertificate).Methods(http.MethodDelete)
 	r.HandleFunc("/backend/{name}/status", api.getStatusBackend).Methods(http.MethodGet)
+	r.HandleFunc("/backend/{name}/activity", api.getActivityBackend).Methods(http.MethodGet)
+	r.HandleFunc("/backend/{name}/maintenance", api.setMaintenance).Methods(http.MethodPut)
+	r.HandleFunc("/backend/{name}/maintenance", api.unsetMaintenance).Methods(http.MethodDelete)
 	r.HandleFunc("/info", api.getInfo).Methods(http.MethodGet)
 
 	listener, err := net.Listen("tcp", "127.0.0.1:0")
@@ -639,6 +677,8 @@ type backend struct {
 	healthcheck routerTypes.HealthcheckData
 	opts        map[string]interface{}
 	prefixAddrs map[string]routesReq
+	lastRequest time.Time
+	maintenance *router.MaintenanceOpts
 }
 
 type fakeRouterAPI struct {
@@ -660,6 +700,43 @@ func (f *fakeRouterAPI) getStatusBackend(w http.ResponseWriter, r *http.Request)
 	w.Write([]byte(`{"status": "ready", "detail": "anaander"}`))
 }
 
+func (f *fakeRouterAPI) getActivityBackend(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+	backend, ok := f.backends[name]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(activityResp{LastRequest: backend.lastRequest})
+}
+
+func (f *fakeRouterAPI) setMaintenance(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+	backend, ok := f.backends[name]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	var opts router.MaintenanceOpts
+	err := json.NewDecoder(r.Body).Decode(&opts)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	backend.maintenance = &opts
+}
+
+func (f *fakeRouterAPI) unsetMaintenance(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+	backend, ok := f.backends[name]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	backend.maintenance = nil
+}
+
 func (f *fakeRouterAPI) getBackend(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	name := vars["name"]
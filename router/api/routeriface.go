@@ -12,15 +12,17 @@ import (
 )
 
 func toSupportedInterface(base *apiRouter, supports map[capability]bool) router.Router {
+	apiRouterWithActivityInst := &apiRouterWithActivity{base}
 	apiRouterWithCnameSupportInst := &apiRouterWithCnameSupport{base}
 	apiRouterWithHealthcheckSupportInst := &apiRouterWithHealthcheckSupport{base}
 	apiRouterWithInfoInst := &apiRouterWithInfo{base}
+	apiRouterWithMaintenanceInst := &apiRouterWithMaintenance{base}
 	apiRouterWithPrefixInst := &apiRouterWithPrefix{base}
 	apiRouterWithStatusInst := &apiRouterWithStatus{base}
 	apiRouterWithTLSSupportInst := &apiRouterWithTLSSupport{base}
 	apiRouterV2Inst := &apiRouterV2{base}
 
-	if !supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
+	if !supports["activity"] && !supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
@@ -29,1889 +31,9027 @@ func toSupportedInterface(base *apiRouter, supports map[capability]bool) router.
 			base,
 		}
 	}
-	if supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
+	if supports["activity"] && !supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
-			router.CNameRouter
+			router.ActivityRouter
 		}{
 			base,
 			base,
-			apiRouterWithCnameSupportInst,
+			apiRouterWithActivityInst,
 		}
 	}
-	if !supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
+	if !supports["activity"] && supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
-			router.CustomHealthcheckRouter
+			router.CNameRouter
 		}{
 			base,
 			base,
-			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithCnameSupportInst,
 		}
 	}
-	if supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
+	if supports["activity"] && supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
+			router.ActivityRouter
 			router.CNameRouter
-			router.CustomHealthcheckRouter
 		}{
 			base,
 			base,
+			apiRouterWithActivityInst,
 			apiRouterWithCnameSupportInst,
-			apiRouterWithHealthcheckSupportInst,
 		}
 	}
-	if !supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
+	if !supports["activity"] && !supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
-			router.InfoRouter
+			router.CustomHealthcheckRouter
 		}{
 			base,
 			base,
-			apiRouterWithInfoInst,
+			apiRouterWithHealthcheckSupportInst,
 		}
 	}
-	if supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
+	if supports["activity"] && !supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
-			router.CNameRouter
-			router.InfoRouter
+			router.ActivityRouter
+			router.CustomHealthcheckRouter
 		}{
 			base,
 			base,
-			apiRouterWithCnameSupportInst,
-			apiRouterWithInfoInst,
+			apiRouterWithActivityInst,
+			apiRouterWithHealthcheckSupportInst,
 		}
 	}
-	if !supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
+	if !supports["activity"] && supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
+			router.CNameRouter
 			router.CustomHealthcheckRouter
-			router.InfoRouter
 		}{
 			base,
 			base,
+			apiRouterWithCnameSupportInst,
 			apiRouterWithHealthcheckSupportInst,
-			apiRouterWithInfoInst,
 		}
 	}
-	if supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
+	if supports["activity"] && supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
+			router.ActivityRouter
 			router.CNameRouter
 			router.CustomHealthcheckRouter
-			router.InfoRouter
 		}{
 			base,
 			base,
+			apiRouterWithActivityInst,
 			apiRouterWithCnameSupportInst,
 			apiRouterWithHealthcheckSupportInst,
-			apiRouterWithInfoInst,
 		}
 	}
-	if !supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
+	if !supports["activity"] && !supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["maintenance"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
-			router.PrefixRouter
+			router.InfoRouter
 		}{
 			base,
 			base,
-			apiRouterWithPrefixInst,
+			apiRouterWithInfoInst,
 		}
 	}
-	if supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
+	if supports["activity"] && !supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["maintenance"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
-			router.CNameRouter
-			router.PrefixRouter
+			router.ActivityRouter
+			router.InfoRouter
 		}{
 			base,
 			base,
-			apiRouterWithCnameSupportInst,
-			apiRouterWithPrefixInst,
+			apiRouterWithActivityInst,
+			apiRouterWithInfoInst,
 		}
 	}
-	if !supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
+	if !supports["activity"] && supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["maintenance"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
-			router.CustomHealthcheckRouter
-			router.PrefixRouter
+			router.CNameRouter
+			router.InfoRouter
 		}{
 			base,
 			base,
-			apiRouterWithHealthcheckSupportInst,
-			apiRouterWithPrefixInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithInfoInst,
 		}
 	}
-	if supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
+	if supports["activity"] && supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["maintenance"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
+			router.ActivityRouter
 			router.CNameRouter
-			router.CustomHealthcheckRouter
-			router.PrefixRouter
+			router.InfoRouter
 		}{
 			base,
 			base,
+			apiRouterWithActivityInst,
 			apiRouterWithCnameSupportInst,
-			apiRouterWithHealthcheckSupportInst,
-			apiRouterWithPrefixInst,
+			apiRouterWithInfoInst,
 		}
 	}
-	if !supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
+	if !supports["activity"] && !supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["maintenance"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
+			router.CustomHealthcheckRouter
 			router.InfoRouter
-			router.PrefixRouter
 		}{
 			base,
 			base,
+			apiRouterWithHealthcheckSupportInst,
 			apiRouterWithInfoInst,
-			apiRouterWithPrefixInst,
 		}
 	}
-	if supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
+	if supports["activity"] && !supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["maintenance"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
-			router.CNameRouter
+			router.ActivityRouter
+			router.CustomHealthcheckRouter
 			router.InfoRouter
-			router.PrefixRouter
 		}{
 			base,
 			base,
-			apiRouterWithCnameSupportInst,
+			apiRouterWithActivityInst,
+			apiRouterWithHealthcheckSupportInst,
 			apiRouterWithInfoInst,
-			apiRouterWithPrefixInst,
 		}
 	}
-	if !supports["cname"] && supports["healthcheck"] && supports["info"] && supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
+	if !supports["activity"] && supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["maintenance"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
+			router.CNameRouter
 			router.CustomHealthcheckRouter
 			router.InfoRouter
-			router.PrefixRouter
 		}{
 			base,
 			base,
+			apiRouterWithCnameSupportInst,
 			apiRouterWithHealthcheckSupportInst,
 			apiRouterWithInfoInst,
-			apiRouterWithPrefixInst,
 		}
 	}
-	if supports["cname"] && supports["healthcheck"] && supports["info"] && supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
+	if supports["activity"] && supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["maintenance"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
+			router.ActivityRouter
 			router.CNameRouter
 			router.CustomHealthcheckRouter
 			router.InfoRouter
-			router.PrefixRouter
 		}{
 			base,
 			base,
+			apiRouterWithActivityInst,
 			apiRouterWithCnameSupportInst,
 			apiRouterWithHealthcheckSupportInst,
 			apiRouterWithInfoInst,
-			apiRouterWithPrefixInst,
 		}
 	}
-	if !supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+	if !supports["activity"] && !supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["maintenance"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
-			router.StatusRouter
+			router.MaintenanceRouter
 		}{
 			base,
 			base,
-			apiRouterWithStatusInst,
+			apiRouterWithMaintenanceInst,
 		}
 	}
-	if supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+	if supports["activity"] && !supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["maintenance"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
-			router.CNameRouter
-			router.StatusRouter
+			router.ActivityRouter
+			router.MaintenanceRouter
 		}{
 			base,
 			base,
-			apiRouterWithCnameSupportInst,
-			apiRouterWithStatusInst,
+			apiRouterWithActivityInst,
+			apiRouterWithMaintenanceInst,
 		}
 	}
-	if !supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+	if !supports["activity"] && supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["maintenance"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
-			router.CustomHealthcheckRouter
-			router.StatusRouter
+			router.CNameRouter
+			router.MaintenanceRouter
 		}{
 			base,
 			base,
-			apiRouterWithHealthcheckSupportInst,
-			apiRouterWithStatusInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithMaintenanceInst,
 		}
 	}
-	if supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+	if supports["activity"] && supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["maintenance"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
+			router.ActivityRouter
 			router.CNameRouter
-			router.CustomHealthcheckRouter
-			router.StatusRouter
+			router.MaintenanceRouter
 		}{
 			base,
 			base,
+			apiRouterWithActivityInst,
 			apiRouterWithCnameSupportInst,
-			apiRouterWithHealthcheckSupportInst,
-			apiRouterWithStatusInst,
+			apiRouterWithMaintenanceInst,
 		}
 	}
-	if !supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+	if !supports["activity"] && !supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["maintenance"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
-			router.InfoRouter
-			router.StatusRouter
+			router.CustomHealthcheckRouter
+			router.MaintenanceRouter
 		}{
 			base,
 			base,
-			apiRouterWithInfoInst,
-			apiRouterWithStatusInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithMaintenanceInst,
 		}
 	}
-	if supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+	if supports["activity"] && !supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["maintenance"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
-			router.CNameRouter
-			router.InfoRouter
-			router.StatusRouter
+			router.ActivityRouter
+			router.CustomHealthcheckRouter
+			router.MaintenanceRouter
 		}{
 			base,
 			base,
-			apiRouterWithCnameSupportInst,
-			apiRouterWithInfoInst,
-			apiRouterWithStatusInst,
+			apiRouterWithActivityInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithMaintenanceInst,
 		}
 	}
-	if !supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+	if !supports["activity"] && supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["maintenance"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
+			router.CNameRouter
 			router.CustomHealthcheckRouter
-			router.InfoRouter
-			router.StatusRouter
+			router.MaintenanceRouter
 		}{
 			base,
 			base,
+			apiRouterWithCnameSupportInst,
 			apiRouterWithHealthcheckSupportInst,
-			apiRouterWithInfoInst,
-			apiRouterWithStatusInst,
+			apiRouterWithMaintenanceInst,
 		}
 	}
-	if supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+	if supports["activity"] && supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["maintenance"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
+			router.ActivityRouter
 			router.CNameRouter
 			router.CustomHealthcheckRouter
-			router.InfoRouter
-			router.StatusRouter
+			router.MaintenanceRouter
 		}{
 			base,
 			base,
+			apiRouterWithActivityInst,
 			apiRouterWithCnameSupportInst,
 			apiRouterWithHealthcheckSupportInst,
-			apiRouterWithInfoInst,
-			apiRouterWithStatusInst,
+			apiRouterWithMaintenanceInst,
 		}
 	}
-	if !supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+	if !supports["activity"] && !supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["maintenance"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
-			router.PrefixRouter
-			router.StatusRouter
+			router.InfoRouter
+			router.MaintenanceRouter
 		}{
 			base,
 			base,
-			apiRouterWithPrefixInst,
-			apiRouterWithStatusInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
 		}
 	}
-	if supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+	if supports["activity"] && !supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["maintenance"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
-			router.CNameRouter
-			router.PrefixRouter
-			router.StatusRouter
+			router.ActivityRouter
+			router.InfoRouter
+			router.MaintenanceRouter
 		}{
 			base,
 			base,
-			apiRouterWithCnameSupportInst,
-			apiRouterWithPrefixInst,
-			apiRouterWithStatusInst,
+			apiRouterWithActivityInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
 		}
 	}
-	if !supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+	if !supports["activity"] && supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["maintenance"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
-			router.CustomHealthcheckRouter
-			router.PrefixRouter
-			router.StatusRouter
+			router.CNameRouter
+			router.InfoRouter
+			router.MaintenanceRouter
 		}{
 			base,
 			base,
-			apiRouterWithHealthcheckSupportInst,
-			apiRouterWithPrefixInst,
-			apiRouterWithStatusInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
 		}
 	}
-	if supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+	if supports["activity"] && supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["maintenance"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
+			router.ActivityRouter
 			router.CNameRouter
-			router.CustomHealthcheckRouter
-			router.PrefixRouter
-			router.StatusRouter
+			router.InfoRouter
+			router.MaintenanceRouter
 		}{
 			base,
 			base,
+			apiRouterWithActivityInst,
 			apiRouterWithCnameSupportInst,
-			apiRouterWithHealthcheckSupportInst,
-			apiRouterWithPrefixInst,
-			apiRouterWithStatusInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
 		}
 	}
-	if !supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+	if !supports["activity"] && !supports["cname"] && supports["healthcheck"] && supports["info"] && supports["maintenance"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
+			router.CustomHealthcheckRouter
 			router.InfoRouter
-			router.PrefixRouter
-			router.StatusRouter
+			router.MaintenanceRouter
 		}{
 			base,
 			base,
+			apiRouterWithHealthcheckSupportInst,
 			apiRouterWithInfoInst,
-			apiRouterWithPrefixInst,
-			apiRouterWithStatusInst,
+			apiRouterWithMaintenanceInst,
 		}
 	}
-	if supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+	if supports["activity"] && !supports["cname"] && supports["healthcheck"] && supports["info"] && supports["maintenance"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
-			router.CNameRouter
+			router.ActivityRouter
+			router.CustomHealthcheckRouter
 			router.InfoRouter
-			router.PrefixRouter
-			router.StatusRouter
+			router.MaintenanceRouter
 		}{
 			base,
 			base,
-			apiRouterWithCnameSupportInst,
+			apiRouterWithActivityInst,
+			apiRouterWithHealthcheckSupportInst,
 			apiRouterWithInfoInst,
-			apiRouterWithPrefixInst,
-			apiRouterWithStatusInst,
+			apiRouterWithMaintenanceInst,
 		}
 	}
-	if !supports["cname"] && supports["healthcheck"] && supports["info"] && supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+	if !supports["activity"] && supports["cname"] && supports["healthcheck"] && supports["info"] && supports["maintenance"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
+			router.CNameRouter
 			router.CustomHealthcheckRouter
 			router.InfoRouter
-			router.PrefixRouter
-			router.StatusRouter
+			router.MaintenanceRouter
 		}{
 			base,
 			base,
+			apiRouterWithCnameSupportInst,
 			apiRouterWithHealthcheckSupportInst,
 			apiRouterWithInfoInst,
-			apiRouterWithPrefixInst,
-			apiRouterWithStatusInst,
+			apiRouterWithMaintenanceInst,
 		}
 	}
-	if supports["cname"] && supports["healthcheck"] && supports["info"] && supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+	if supports["activity"] && supports["cname"] && supports["healthcheck"] && supports["info"] && supports["maintenance"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
+			router.ActivityRouter
 			router.CNameRouter
 			router.CustomHealthcheckRouter
 			router.InfoRouter
-			router.PrefixRouter
-			router.StatusRouter
+			router.MaintenanceRouter
 		}{
 			base,
 			base,
+			apiRouterWithActivityInst,
 			apiRouterWithCnameSupportInst,
 			apiRouterWithHealthcheckSupportInst,
 			apiRouterWithInfoInst,
-			apiRouterWithPrefixInst,
-			apiRouterWithStatusInst,
+			apiRouterWithMaintenanceInst,
 		}
 	}
-	if !supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+	if !supports["activity"] && !supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
-			router.TLSRouter
+			router.PrefixRouter
 		}{
 			base,
 			base,
-			apiRouterWithTLSSupportInst,
+			apiRouterWithPrefixInst,
 		}
 	}
-	if supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+	if supports["activity"] && !supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
-			router.CNameRouter
-			router.TLSRouter
+			router.ActivityRouter
+			router.PrefixRouter
 		}{
 			base,
 			base,
-			apiRouterWithCnameSupportInst,
-			apiRouterWithTLSSupportInst,
+			apiRouterWithActivityInst,
+			apiRouterWithPrefixInst,
 		}
 	}
-	if !supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+	if !supports["activity"] && supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
-			router.CustomHealthcheckRouter
-			router.TLSRouter
+			router.CNameRouter
+			router.PrefixRouter
 		}{
 			base,
 			base,
-			apiRouterWithHealthcheckSupportInst,
-			apiRouterWithTLSSupportInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithPrefixInst,
 		}
 	}
-	if supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+	if supports["activity"] && supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
+			router.ActivityRouter
 			router.CNameRouter
-			router.CustomHealthcheckRouter
-			router.TLSRouter
+			router.PrefixRouter
 		}{
 			base,
 			base,
+			apiRouterWithActivityInst,
 			apiRouterWithCnameSupportInst,
-			apiRouterWithHealthcheckSupportInst,
-			apiRouterWithTLSSupportInst,
+			apiRouterWithPrefixInst,
 		}
 	}
-	if !supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+	if !supports["activity"] && !supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
-			router.InfoRouter
-			router.TLSRouter
+			router.CustomHealthcheckRouter
+			router.PrefixRouter
 		}{
 			base,
 			base,
-			apiRouterWithInfoInst,
-			apiRouterWithTLSSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithPrefixInst,
 		}
 	}
-	if supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+	if supports["activity"] && !supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
-			router.CNameRouter
-			router.InfoRouter
-			router.TLSRouter
+			router.ActivityRouter
+			router.CustomHealthcheckRouter
+			router.PrefixRouter
 		}{
 			base,
 			base,
-			apiRouterWithCnameSupportInst,
-			apiRouterWithInfoInst,
-			apiRouterWithTLSSupportInst,
+			apiRouterWithActivityInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithPrefixInst,
 		}
 	}
-	if !supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+	if !supports["activity"] && supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
+			router.CNameRouter
 			router.CustomHealthcheckRouter
-			router.InfoRouter
-			router.TLSRouter
+			router.PrefixRouter
 		}{
 			base,
 			base,
+			apiRouterWithCnameSupportInst,
 			apiRouterWithHealthcheckSupportInst,
-			apiRouterWithInfoInst,
-			apiRouterWithTLSSupportInst,
+			apiRouterWithPrefixInst,
 		}
 	}
-	if supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+	if supports["activity"] && supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
+			router.ActivityRouter
 			router.CNameRouter
 			router.CustomHealthcheckRouter
-			router.InfoRouter
-			router.TLSRouter
+			router.PrefixRouter
 		}{
 			base,
 			base,
+			apiRouterWithActivityInst,
 			apiRouterWithCnameSupportInst,
 			apiRouterWithHealthcheckSupportInst,
-			apiRouterWithInfoInst,
-			apiRouterWithTLSSupportInst,
+			apiRouterWithPrefixInst,
 		}
 	}
-	if !supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+	if !supports["activity"] && !supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["maintenance"] && supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
+			router.InfoRouter
 			router.PrefixRouter
-			router.TLSRouter
 		}{
 			base,
 			base,
+			apiRouterWithInfoInst,
 			apiRouterWithPrefixInst,
-			apiRouterWithTLSSupportInst,
 		}
 	}
-	if supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+	if supports["activity"] && !supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["maintenance"] && supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
-			router.CNameRouter
+			router.ActivityRouter
+			router.InfoRouter
 			router.PrefixRouter
-			router.TLSRouter
 		}{
 			base,
 			base,
-			apiRouterWithCnameSupportInst,
+			apiRouterWithActivityInst,
+			apiRouterWithInfoInst,
 			apiRouterWithPrefixInst,
-			apiRouterWithTLSSupportInst,
 		}
 	}
-	if !supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+	if !supports["activity"] && supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["maintenance"] && supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
-			router.CustomHealthcheckRouter
+			router.CNameRouter
+			router.InfoRouter
 			router.PrefixRouter
-			router.TLSRouter
 		}{
 			base,
 			base,
-			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithInfoInst,
 			apiRouterWithPrefixInst,
-			apiRouterWithTLSSupportInst,
 		}
 	}
-	if supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+	if supports["activity"] && supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["maintenance"] && supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
+			router.ActivityRouter
 			router.CNameRouter
-			router.CustomHealthcheckRouter
+			router.InfoRouter
 			router.PrefixRouter
-			router.TLSRouter
 		}{
 			base,
 			base,
+			apiRouterWithActivityInst,
 			apiRouterWithCnameSupportInst,
-			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
 			apiRouterWithPrefixInst,
-			apiRouterWithTLSSupportInst,
 		}
 	}
-	if !supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+	if !supports["activity"] && !supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["maintenance"] && supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
+			router.CustomHealthcheckRouter
 			router.InfoRouter
 			router.PrefixRouter
-			router.TLSRouter
 		}{
 			base,
 			base,
+			apiRouterWithHealthcheckSupportInst,
 			apiRouterWithInfoInst,
 			apiRouterWithPrefixInst,
-			apiRouterWithTLSSupportInst,
 		}
 	}
-	if supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+	if supports["activity"] && !supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["maintenance"] && supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
-			router.CNameRouter
+			router.ActivityRouter
+			router.CustomHealthcheckRouter
 			router.InfoRouter
 			router.PrefixRouter
-			router.TLSRouter
 		}{
 			base,
 			base,
-			apiRouterWithCnameSupportInst,
+			apiRouterWithActivityInst,
+			apiRouterWithHealthcheckSupportInst,
 			apiRouterWithInfoInst,
 			apiRouterWithPrefixInst,
-			apiRouterWithTLSSupportInst,
 		}
 	}
-	if !supports["cname"] && supports["healthcheck"] && supports["info"] && supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+	if !supports["activity"] && supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["maintenance"] && supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
+			router.CNameRouter
 			router.CustomHealthcheckRouter
 			router.InfoRouter
 			router.PrefixRouter
-			router.TLSRouter
 		}{
 			base,
 			base,
+			apiRouterWithCnameSupportInst,
 			apiRouterWithHealthcheckSupportInst,
 			apiRouterWithInfoInst,
 			apiRouterWithPrefixInst,
-			apiRouterWithTLSSupportInst,
 		}
 	}
-	if supports["cname"] && supports["healthcheck"] && supports["info"] && supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+	if supports["activity"] && supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["maintenance"] && supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
+			router.ActivityRouter
 			router.CNameRouter
 			router.CustomHealthcheckRouter
 			router.InfoRouter
 			router.PrefixRouter
-			router.TLSRouter
 		}{
 			base,
 			base,
+			apiRouterWithActivityInst,
 			apiRouterWithCnameSupportInst,
 			apiRouterWithHealthcheckSupportInst,
 			apiRouterWithInfoInst,
 			apiRouterWithPrefixInst,
-			apiRouterWithTLSSupportInst,
 		}
 	}
-	if !supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+	if !supports["activity"] && !supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["maintenance"] && supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+		}{
+			base,
+			base,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["maintenance"] && supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["maintenance"] && supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["maintenance"] && supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["maintenance"] && supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CustomHealthcheckRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+		}{
+			base,
+			base,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["maintenance"] && supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CustomHealthcheckRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["maintenance"] && supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["maintenance"] && supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["maintenance"] && supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+		}{
+			base,
+			base,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["maintenance"] && supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["maintenance"] && supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["maintenance"] && supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && supports["healthcheck"] && supports["info"] && supports["maintenance"] && supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+		}{
+			base,
+			base,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && supports["healthcheck"] && supports["info"] && supports["maintenance"] && supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && supports["healthcheck"] && supports["info"] && supports["maintenance"] && supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && supports["healthcheck"] && supports["info"] && supports["maintenance"] && supports["prefix"] && !supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && !supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.StatusRouter
+		}{
+			base,
+			base,
+			apiRouterWithStatusInst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && !supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.StatusRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithStatusInst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && !supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.StatusRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithStatusInst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && !supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.StatusRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithStatusInst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && !supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CustomHealthcheckRouter
 			router.StatusRouter
+		}{
+			base,
+			base,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithStatusInst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && !supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CustomHealthcheckRouter
+			router.StatusRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithStatusInst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && !supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.StatusRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithStatusInst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && !supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.StatusRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithStatusInst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["maintenance"] && !supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.InfoRouter
+			router.StatusRouter
+		}{
+			base,
+			base,
+			apiRouterWithInfoInst,
+			apiRouterWithStatusInst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["maintenance"] && !supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.InfoRouter
+			router.StatusRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithInfoInst,
+			apiRouterWithStatusInst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["maintenance"] && !supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.InfoRouter
+			router.StatusRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithStatusInst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["maintenance"] && !supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.InfoRouter
+			router.StatusRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithStatusInst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["maintenance"] && !supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.StatusRouter
+		}{
+			base,
+			base,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithStatusInst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["maintenance"] && !supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.StatusRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithStatusInst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["maintenance"] && !supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.StatusRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithStatusInst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["maintenance"] && !supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.StatusRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithStatusInst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["maintenance"] && !supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.MaintenanceRouter
+			router.StatusRouter
+		}{
+			base,
+			base,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithStatusInst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["maintenance"] && !supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.MaintenanceRouter
+			router.StatusRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithStatusInst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["maintenance"] && !supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.MaintenanceRouter
+			router.StatusRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithStatusInst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["maintenance"] && !supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.MaintenanceRouter
+			router.StatusRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithStatusInst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["maintenance"] && !supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CustomHealthcheckRouter
+			router.MaintenanceRouter
+			router.StatusRouter
+		}{
+			base,
+			base,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithStatusInst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["maintenance"] && !supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CustomHealthcheckRouter
+			router.MaintenanceRouter
+			router.StatusRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithStatusInst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["maintenance"] && !supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.MaintenanceRouter
+			router.StatusRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithStatusInst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["maintenance"] && !supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.MaintenanceRouter
+			router.StatusRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithStatusInst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["maintenance"] && !supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.StatusRouter
+		}{
+			base,
+			base,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithStatusInst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["maintenance"] && !supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.StatusRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithStatusInst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["maintenance"] && !supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.StatusRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithStatusInst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["maintenance"] && !supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.StatusRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithStatusInst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && supports["healthcheck"] && supports["info"] && supports["maintenance"] && !supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.StatusRouter
+		}{
+			base,
+			base,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithStatusInst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && supports["healthcheck"] && supports["info"] && supports["maintenance"] && !supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.StatusRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithStatusInst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && supports["healthcheck"] && supports["info"] && supports["maintenance"] && !supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.StatusRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithStatusInst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && supports["healthcheck"] && supports["info"] && supports["maintenance"] && !supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.StatusRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithStatusInst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.PrefixRouter
+			router.StatusRouter
+		}{
+			base,
+			base,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.PrefixRouter
+			router.StatusRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.PrefixRouter
+			router.StatusRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.PrefixRouter
+			router.StatusRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CustomHealthcheckRouter
+			router.PrefixRouter
+			router.StatusRouter
+		}{
+			base,
+			base,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CustomHealthcheckRouter
+			router.PrefixRouter
+			router.StatusRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.PrefixRouter
+			router.StatusRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.PrefixRouter
+			router.StatusRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["maintenance"] && supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.InfoRouter
+			router.PrefixRouter
+			router.StatusRouter
+		}{
+			base,
+			base,
+			apiRouterWithInfoInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["maintenance"] && supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.InfoRouter
+			router.PrefixRouter
+			router.StatusRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithInfoInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["maintenance"] && supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.InfoRouter
+			router.PrefixRouter
+			router.StatusRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["maintenance"] && supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.InfoRouter
+			router.PrefixRouter
+			router.StatusRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["maintenance"] && supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.PrefixRouter
+			router.StatusRouter
+		}{
+			base,
+			base,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["maintenance"] && supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.PrefixRouter
+			router.StatusRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["maintenance"] && supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.PrefixRouter
+			router.StatusRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["maintenance"] && supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.PrefixRouter
+			router.StatusRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["maintenance"] && supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.StatusRouter
+		}{
+			base,
+			base,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["maintenance"] && supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.StatusRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["maintenance"] && supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.StatusRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["maintenance"] && supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.StatusRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["maintenance"] && supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CustomHealthcheckRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.StatusRouter
+		}{
+			base,
+			base,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["maintenance"] && supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CustomHealthcheckRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.StatusRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["maintenance"] && supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.StatusRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["maintenance"] && supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.StatusRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["maintenance"] && supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.StatusRouter
+		}{
+			base,
+			base,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["maintenance"] && supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.StatusRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["maintenance"] && supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.StatusRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["maintenance"] && supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.StatusRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && supports["healthcheck"] && supports["info"] && supports["maintenance"] && supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.StatusRouter
+		}{
+			base,
+			base,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && supports["healthcheck"] && supports["info"] && supports["maintenance"] && supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.StatusRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && supports["healthcheck"] && supports["info"] && supports["maintenance"] && supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.StatusRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && supports["healthcheck"] && supports["info"] && supports["maintenance"] && supports["prefix"] && supports["status"] && !supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.StatusRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && !supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && !supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && !supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && !supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && !supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CustomHealthcheckRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && !supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CustomHealthcheckRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && !supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && !supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["maintenance"] && !supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.InfoRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithInfoInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["maintenance"] && !supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.InfoRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithInfoInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["maintenance"] && !supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.InfoRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["maintenance"] && !supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.InfoRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["maintenance"] && !supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["maintenance"] && !supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["maintenance"] && !supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["maintenance"] && !supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["maintenance"] && !supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.MaintenanceRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["maintenance"] && !supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.MaintenanceRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["maintenance"] && !supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.MaintenanceRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["maintenance"] && !supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.MaintenanceRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["maintenance"] && !supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CustomHealthcheckRouter
+			router.MaintenanceRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["maintenance"] && !supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CustomHealthcheckRouter
+			router.MaintenanceRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["maintenance"] && !supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.MaintenanceRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["maintenance"] && !supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.MaintenanceRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["maintenance"] && !supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["maintenance"] && !supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["maintenance"] && !supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["maintenance"] && !supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && supports["healthcheck"] && supports["info"] && supports["maintenance"] && !supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && supports["healthcheck"] && supports["info"] && supports["maintenance"] && !supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && supports["healthcheck"] && supports["info"] && supports["maintenance"] && !supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && supports["healthcheck"] && supports["info"] && supports["maintenance"] && !supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.PrefixRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithPrefixInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.PrefixRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.PrefixRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.PrefixRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CustomHealthcheckRouter
+			router.PrefixRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CustomHealthcheckRouter
+			router.PrefixRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.PrefixRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.PrefixRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["maintenance"] && supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.InfoRouter
+			router.PrefixRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithInfoInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["maintenance"] && supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.InfoRouter
+			router.PrefixRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithInfoInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["maintenance"] && supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.InfoRouter
+			router.PrefixRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["maintenance"] && supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.InfoRouter
+			router.PrefixRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["maintenance"] && supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.PrefixRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["maintenance"] && supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.PrefixRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["maintenance"] && supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.PrefixRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["maintenance"] && supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.PrefixRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["maintenance"] && supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["maintenance"] && supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["maintenance"] && supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["maintenance"] && supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["maintenance"] && supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CustomHealthcheckRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["maintenance"] && supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CustomHealthcheckRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["maintenance"] && supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["maintenance"] && supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["maintenance"] && supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["maintenance"] && supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["maintenance"] && supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["maintenance"] && supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && supports["healthcheck"] && supports["info"] && supports["maintenance"] && supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && supports["healthcheck"] && supports["info"] && supports["maintenance"] && supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && supports["healthcheck"] && supports["info"] && supports["maintenance"] && supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && supports["healthcheck"] && supports["info"] && supports["maintenance"] && supports["prefix"] && !supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && !supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.StatusRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && !supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.StatusRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && !supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.StatusRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && !supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.StatusRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && !supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CustomHealthcheckRouter
+			router.StatusRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && !supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CustomHealthcheckRouter
+			router.StatusRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && !supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.StatusRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && !supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.StatusRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["maintenance"] && !supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.InfoRouter
+			router.StatusRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithInfoInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["maintenance"] && !supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.InfoRouter
+			router.StatusRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithInfoInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["maintenance"] && !supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.InfoRouter
+			router.StatusRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["maintenance"] && !supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.InfoRouter
+			router.StatusRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["maintenance"] && !supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.StatusRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["maintenance"] && !supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.StatusRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["maintenance"] && !supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.StatusRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["maintenance"] && !supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.StatusRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["maintenance"] && !supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.MaintenanceRouter
+			router.StatusRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["maintenance"] && !supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.MaintenanceRouter
+			router.StatusRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["maintenance"] && !supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.MaintenanceRouter
+			router.StatusRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["maintenance"] && !supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.MaintenanceRouter
+			router.StatusRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["maintenance"] && !supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CustomHealthcheckRouter
+			router.MaintenanceRouter
+			router.StatusRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["maintenance"] && !supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CustomHealthcheckRouter
+			router.MaintenanceRouter
+			router.StatusRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["maintenance"] && !supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.MaintenanceRouter
+			router.StatusRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["maintenance"] && !supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.MaintenanceRouter
+			router.StatusRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["maintenance"] && !supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.StatusRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["maintenance"] && !supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.StatusRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["maintenance"] && !supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.StatusRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["maintenance"] && !supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.StatusRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && supports["healthcheck"] && supports["info"] && supports["maintenance"] && !supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.StatusRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && supports["healthcheck"] && supports["info"] && supports["maintenance"] && !supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.StatusRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && supports["healthcheck"] && supports["info"] && supports["maintenance"] && !supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.StatusRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && supports["healthcheck"] && supports["info"] && supports["maintenance"] && !supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.StatusRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.PrefixRouter
+			router.StatusRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.PrefixRouter
+			router.StatusRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.PrefixRouter
+			router.StatusRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.PrefixRouter
+			router.StatusRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CustomHealthcheckRouter
+			router.PrefixRouter
+			router.StatusRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CustomHealthcheckRouter
+			router.PrefixRouter
+			router.StatusRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.PrefixRouter
+			router.StatusRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.PrefixRouter
+			router.StatusRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["maintenance"] && supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.InfoRouter
+			router.PrefixRouter
+			router.StatusRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithInfoInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["maintenance"] && supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.InfoRouter
+			router.PrefixRouter
+			router.StatusRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithInfoInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["maintenance"] && supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.InfoRouter
+			router.PrefixRouter
+			router.StatusRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["maintenance"] && supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.InfoRouter
+			router.PrefixRouter
+			router.StatusRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["maintenance"] && supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.PrefixRouter
+			router.StatusRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["maintenance"] && supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.PrefixRouter
+			router.StatusRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["maintenance"] && supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.PrefixRouter
+			router.StatusRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["maintenance"] && supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.PrefixRouter
+			router.StatusRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["maintenance"] && supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.StatusRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["maintenance"] && supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.StatusRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["maintenance"] && supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.StatusRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["maintenance"] && supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.StatusRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["maintenance"] && supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CustomHealthcheckRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.StatusRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["maintenance"] && supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CustomHealthcheckRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.StatusRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["maintenance"] && supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.StatusRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["maintenance"] && supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.StatusRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["maintenance"] && supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.StatusRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["maintenance"] && supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.StatusRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["maintenance"] && supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.StatusRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["maintenance"] && supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.StatusRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && supports["healthcheck"] && supports["info"] && supports["maintenance"] && supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.StatusRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && supports["healthcheck"] && supports["info"] && supports["maintenance"] && supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.StatusRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && supports["healthcheck"] && supports["info"] && supports["maintenance"] && supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.StatusRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && supports["healthcheck"] && supports["info"] && supports["maintenance"] && supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.StatusRouter
+			router.TLSRouter
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CustomHealthcheckRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CustomHealthcheckRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["maintenance"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.InfoRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithInfoInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["maintenance"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.InfoRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithInfoInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["maintenance"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.InfoRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["maintenance"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.InfoRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["maintenance"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["maintenance"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["maintenance"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["maintenance"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["maintenance"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.MaintenanceRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithMaintenanceInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["maintenance"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.MaintenanceRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["maintenance"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.MaintenanceRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["maintenance"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.MaintenanceRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["maintenance"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CustomHealthcheckRouter
+			router.MaintenanceRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["maintenance"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CustomHealthcheckRouter
+			router.MaintenanceRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["maintenance"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.MaintenanceRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["maintenance"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.MaintenanceRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["maintenance"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["maintenance"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["maintenance"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["maintenance"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && supports["healthcheck"] && supports["info"] && supports["maintenance"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && supports["healthcheck"] && supports["info"] && supports["maintenance"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && supports["healthcheck"] && supports["info"] && supports["maintenance"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && supports["healthcheck"] && supports["info"] && supports["maintenance"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.PrefixRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithPrefixInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.PrefixRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithPrefixInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.PrefixRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithPrefixInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.PrefixRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithPrefixInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CustomHealthcheckRouter
+			router.PrefixRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithPrefixInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CustomHealthcheckRouter
+			router.PrefixRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithPrefixInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.PrefixRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithPrefixInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.PrefixRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithPrefixInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["maintenance"] && supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.InfoRouter
+			router.PrefixRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithInfoInst,
+			apiRouterWithPrefixInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["maintenance"] && supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.InfoRouter
+			router.PrefixRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithInfoInst,
+			apiRouterWithPrefixInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["maintenance"] && supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.InfoRouter
+			router.PrefixRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithPrefixInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["maintenance"] && supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.InfoRouter
+			router.PrefixRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithPrefixInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["maintenance"] && supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.PrefixRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithPrefixInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["maintenance"] && supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.PrefixRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithPrefixInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["maintenance"] && supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.PrefixRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithPrefixInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["maintenance"] && supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.PrefixRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithPrefixInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["maintenance"] && supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["maintenance"] && supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["maintenance"] && supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["maintenance"] && supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["maintenance"] && supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CustomHealthcheckRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["maintenance"] && supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CustomHealthcheckRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["maintenance"] && supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["maintenance"] && supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["maintenance"] && supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["maintenance"] && supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["maintenance"] && supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["maintenance"] && supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && supports["healthcheck"] && supports["info"] && supports["maintenance"] && supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && supports["healthcheck"] && supports["info"] && supports["maintenance"] && supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && supports["healthcheck"] && supports["info"] && supports["maintenance"] && supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && supports["healthcheck"] && supports["info"] && supports["maintenance"] && supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && !supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.StatusRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithStatusInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && !supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.StatusRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithStatusInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && !supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.StatusRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithStatusInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && !supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.StatusRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithStatusInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && !supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CustomHealthcheckRouter
+			router.StatusRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithStatusInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && !supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CustomHealthcheckRouter
+			router.StatusRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithStatusInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && !supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.StatusRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithStatusInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && !supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.StatusRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithStatusInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["maintenance"] && !supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.InfoRouter
+			router.StatusRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithInfoInst,
+			apiRouterWithStatusInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["maintenance"] && !supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.InfoRouter
+			router.StatusRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithInfoInst,
+			apiRouterWithStatusInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["maintenance"] && !supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.InfoRouter
+			router.StatusRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithStatusInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["maintenance"] && !supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.InfoRouter
+			router.StatusRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithStatusInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["maintenance"] && !supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.StatusRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithStatusInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["maintenance"] && !supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.StatusRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithStatusInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["maintenance"] && !supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.StatusRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithStatusInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["maintenance"] && !supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.StatusRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithStatusInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["maintenance"] && !supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.MaintenanceRouter
+			router.StatusRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithStatusInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["maintenance"] && !supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.MaintenanceRouter
+			router.StatusRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithStatusInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["maintenance"] && !supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.MaintenanceRouter
+			router.StatusRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithStatusInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["maintenance"] && !supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.MaintenanceRouter
+			router.StatusRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithStatusInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["maintenance"] && !supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CustomHealthcheckRouter
+			router.MaintenanceRouter
+			router.StatusRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithStatusInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["maintenance"] && !supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CustomHealthcheckRouter
+			router.MaintenanceRouter
+			router.StatusRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithStatusInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["maintenance"] && !supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.MaintenanceRouter
+			router.StatusRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithStatusInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["maintenance"] && !supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.MaintenanceRouter
+			router.StatusRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithStatusInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["maintenance"] && !supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.StatusRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithStatusInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["maintenance"] && !supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.StatusRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithStatusInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["maintenance"] && !supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.StatusRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithStatusInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["maintenance"] && !supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.StatusRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithStatusInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && supports["healthcheck"] && supports["info"] && supports["maintenance"] && !supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.StatusRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithStatusInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && supports["healthcheck"] && supports["info"] && supports["maintenance"] && !supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.StatusRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithStatusInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && supports["healthcheck"] && supports["info"] && supports["maintenance"] && !supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.StatusRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithStatusInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && supports["healthcheck"] && supports["info"] && supports["maintenance"] && !supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.StatusRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithStatusInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.PrefixRouter
+			router.StatusRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.PrefixRouter
+			router.StatusRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.PrefixRouter
+			router.StatusRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.PrefixRouter
+			router.StatusRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CustomHealthcheckRouter
+			router.PrefixRouter
+			router.StatusRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CustomHealthcheckRouter
+			router.PrefixRouter
+			router.StatusRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.PrefixRouter
+			router.StatusRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.PrefixRouter
+			router.StatusRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["maintenance"] && supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.InfoRouter
+			router.PrefixRouter
+			router.StatusRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithInfoInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["maintenance"] && supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.InfoRouter
+			router.PrefixRouter
+			router.StatusRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithInfoInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["maintenance"] && supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.InfoRouter
+			router.PrefixRouter
+			router.StatusRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["maintenance"] && supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.InfoRouter
+			router.PrefixRouter
+			router.StatusRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["maintenance"] && supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.PrefixRouter
+			router.StatusRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["maintenance"] && supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.PrefixRouter
+			router.StatusRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["maintenance"] && supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.PrefixRouter
+			router.StatusRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["maintenance"] && supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.PrefixRouter
+			router.StatusRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["maintenance"] && supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.StatusRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["maintenance"] && supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.StatusRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["maintenance"] && supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.StatusRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["maintenance"] && supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.StatusRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["maintenance"] && supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CustomHealthcheckRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.StatusRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["maintenance"] && supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CustomHealthcheckRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.StatusRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["maintenance"] && supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.StatusRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["maintenance"] && supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.StatusRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["maintenance"] && supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.StatusRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["maintenance"] && supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.StatusRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["maintenance"] && supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.StatusRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["maintenance"] && supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.StatusRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && supports["healthcheck"] && supports["info"] && supports["maintenance"] && supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.StatusRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && supports["healthcheck"] && supports["info"] && supports["maintenance"] && supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.StatusRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && supports["healthcheck"] && supports["info"] && supports["maintenance"] && supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.StatusRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && supports["healthcheck"] && supports["info"] && supports["maintenance"] && supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
+			router.StatusRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && !supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.TLSRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithTLSSupportInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && !supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.TLSRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithTLSSupportInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && !supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.TLSRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithTLSSupportInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && !supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.TLSRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithTLSSupportInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && !supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CustomHealthcheckRouter
+			router.TLSRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithTLSSupportInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && !supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CustomHealthcheckRouter
+			router.TLSRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithTLSSupportInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && !supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.TLSRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithTLSSupportInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && !supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.TLSRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithTLSSupportInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["maintenance"] && !supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.InfoRouter
+			router.TLSRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithInfoInst,
+			apiRouterWithTLSSupportInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["maintenance"] && !supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.InfoRouter
+			router.TLSRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithInfoInst,
+			apiRouterWithTLSSupportInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["maintenance"] && !supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.InfoRouter
+			router.TLSRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithTLSSupportInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["maintenance"] && !supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.InfoRouter
+			router.TLSRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithTLSSupportInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["maintenance"] && !supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.TLSRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithTLSSupportInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["maintenance"] && !supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.TLSRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithTLSSupportInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["maintenance"] && !supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.TLSRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithTLSSupportInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["maintenance"] && !supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.TLSRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithTLSSupportInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["maintenance"] && !supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.MaintenanceRouter
+			router.TLSRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithTLSSupportInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["maintenance"] && !supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.MaintenanceRouter
+			router.TLSRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithTLSSupportInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["maintenance"] && !supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.MaintenanceRouter
+			router.TLSRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithTLSSupportInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["maintenance"] && !supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.MaintenanceRouter
+			router.TLSRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithTLSSupportInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["maintenance"] && !supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CustomHealthcheckRouter
+			router.MaintenanceRouter
+			router.TLSRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithTLSSupportInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["maintenance"] && !supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CustomHealthcheckRouter
+			router.MaintenanceRouter
+			router.TLSRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithTLSSupportInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["maintenance"] && !supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.MaintenanceRouter
+			router.TLSRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithTLSSupportInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["maintenance"] && !supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.MaintenanceRouter
+			router.TLSRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithTLSSupportInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["maintenance"] && !supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.TLSRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithTLSSupportInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["maintenance"] && !supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.TLSRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithTLSSupportInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["maintenance"] && !supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.TLSRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithTLSSupportInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["maintenance"] && !supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.TLSRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithTLSSupportInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && supports["healthcheck"] && supports["info"] && supports["maintenance"] && !supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.TLSRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithTLSSupportInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && supports["healthcheck"] && supports["info"] && supports["maintenance"] && !supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.TLSRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithTLSSupportInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && supports["healthcheck"] && supports["info"] && supports["maintenance"] && !supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.TLSRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithTLSSupportInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && supports["healthcheck"] && supports["info"] && supports["maintenance"] && !supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.MaintenanceRouter
+			router.TLSRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithTLSSupportInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.PrefixRouter
+			router.TLSRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithPrefixInst,
+			apiRouterWithTLSSupportInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.PrefixRouter
+			router.TLSRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithTLSSupportInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.PrefixRouter
+			router.TLSRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithTLSSupportInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.PrefixRouter
+			router.TLSRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithTLSSupportInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CustomHealthcheckRouter
+			router.PrefixRouter
+			router.TLSRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithTLSSupportInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CustomHealthcheckRouter
+			router.PrefixRouter
+			router.TLSRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithTLSSupportInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.PrefixRouter
+			router.TLSRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithTLSSupportInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.PrefixRouter
+			router.TLSRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithTLSSupportInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["maintenance"] && supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.InfoRouter
+			router.PrefixRouter
+			router.TLSRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithInfoInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithTLSSupportInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["maintenance"] && supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.InfoRouter
+			router.PrefixRouter
+			router.TLSRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithInfoInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithTLSSupportInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["maintenance"] && supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.InfoRouter
+			router.PrefixRouter
+			router.TLSRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithTLSSupportInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["maintenance"] && supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.InfoRouter
+			router.PrefixRouter
+			router.TLSRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithTLSSupportInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["maintenance"] && supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.PrefixRouter
+			router.TLSRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithTLSSupportInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && !supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["maintenance"] && supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.PrefixRouter
+			router.TLSRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithTLSSupportInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["maintenance"] && supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.PrefixRouter
+			router.TLSRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithTLSSupportInst,
+			apiRouterV2Inst,
+		}
+	}
+	if supports["activity"] && supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["maintenance"] && supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.ActivityRouter
+			router.CNameRouter
+			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.PrefixRouter
+			router.TLSRouter
+			router.RouterV2
+		}{
+			base,
+			base,
+			apiRouterWithActivityInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithTLSSupportInst,
+			apiRouterV2Inst,
+		}
+	}
+	if !supports["activity"] && !supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["maintenance"] && supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
+		return &struct {
+			router.Router
+			router.OptsRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
 			router.TLSRouter
+			router.RouterV2
 		}{
 			base,
 			base,
-			apiRouterWithStatusInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
 			apiRouterWithTLSSupportInst,
+			apiRouterV2Inst,
 		}
 	}
-	if supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+	if supports["activity"] && !supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["maintenance"] && supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
-			router.CNameRouter
-			router.StatusRouter
+			router.ActivityRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
 			router.TLSRouter
+			router.RouterV2
 		}{
 			base,
 			base,
-			apiRouterWithCnameSupportInst,
-			apiRouterWithStatusInst,
+			apiRouterWithActivityInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
 			apiRouterWithTLSSupportInst,
+			apiRouterV2Inst,
 		}
 	}
-	if !supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+	if !supports["activity"] && supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["maintenance"] && supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
-			router.CustomHealthcheckRouter
-			router.StatusRouter
+			router.CNameRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
 			router.TLSRouter
+			router.RouterV2
 		}{
 			base,
 			base,
-			apiRouterWithHealthcheckSupportInst,
-			apiRouterWithStatusInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
 			apiRouterWithTLSSupportInst,
+			apiRouterV2Inst,
 		}
 	}
-	if supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+	if supports["activity"] && supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["maintenance"] && supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
+			router.ActivityRouter
 			router.CNameRouter
-			router.CustomHealthcheckRouter
-			router.StatusRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
 			router.TLSRouter
+			router.RouterV2
 		}{
 			base,
 			base,
+			apiRouterWithActivityInst,
 			apiRouterWithCnameSupportInst,
-			apiRouterWithHealthcheckSupportInst,
-			apiRouterWithStatusInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
 			apiRouterWithTLSSupportInst,
+			apiRouterV2Inst,
 		}
 	}
-	if !supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+	if !supports["activity"] && !supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["maintenance"] && supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
-			router.InfoRouter
-			router.StatusRouter
+			router.CustomHealthcheckRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
 			router.TLSRouter
+			router.RouterV2
 		}{
 			base,
 			base,
-			apiRouterWithInfoInst,
-			apiRouterWithStatusInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
 			apiRouterWithTLSSupportInst,
+			apiRouterV2Inst,
 		}
 	}
-	if supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+	if supports["activity"] && !supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["maintenance"] && supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
-			router.CNameRouter
-			router.InfoRouter
-			router.StatusRouter
+			router.ActivityRouter
+			router.CustomHealthcheckRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
 			router.TLSRouter
+			router.RouterV2
 		}{
 			base,
 			base,
-			apiRouterWithCnameSupportInst,
-			apiRouterWithInfoInst,
-			apiRouterWithStatusInst,
+			apiRouterWithActivityInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
 			apiRouterWithTLSSupportInst,
+			apiRouterV2Inst,
 		}
 	}
-	if !supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+	if !supports["activity"] && supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["maintenance"] && supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
+			router.CNameRouter
 			router.CustomHealthcheckRouter
-			router.InfoRouter
-			router.StatusRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
 			router.TLSRouter
+			router.RouterV2
 		}{
 			base,
 			base,
+			apiRouterWithCnameSupportInst,
 			apiRouterWithHealthcheckSupportInst,
-			apiRouterWithInfoInst,
-			apiRouterWithStatusInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
 			apiRouterWithTLSSupportInst,
+			apiRouterV2Inst,
 		}
 	}
-	if supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+	if supports["activity"] && supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["maintenance"] && supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
+			router.ActivityRouter
 			router.CNameRouter
 			router.CustomHealthcheckRouter
-			router.InfoRouter
-			router.StatusRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
 			router.TLSRouter
+			router.RouterV2
 		}{
 			base,
 			base,
+			apiRouterWithActivityInst,
 			apiRouterWithCnameSupportInst,
 			apiRouterWithHealthcheckSupportInst,
-			apiRouterWithInfoInst,
-			apiRouterWithStatusInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
 			apiRouterWithTLSSupportInst,
+			apiRouterV2Inst,
 		}
 	}
-	if !supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+	if !supports["activity"] && !supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["maintenance"] && supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
+			router.InfoRouter
+			router.MaintenanceRouter
 			router.PrefixRouter
-			router.StatusRouter
 			router.TLSRouter
+			router.RouterV2
 		}{
 			base,
 			base,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
 			apiRouterWithPrefixInst,
-			apiRouterWithStatusInst,
 			apiRouterWithTLSSupportInst,
+			apiRouterV2Inst,
 		}
 	}
-	if supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+	if supports["activity"] && !supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["maintenance"] && supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
-			router.CNameRouter
+			router.ActivityRouter
+			router.InfoRouter
+			router.MaintenanceRouter
 			router.PrefixRouter
-			router.StatusRouter
 			router.TLSRouter
+			router.RouterV2
 		}{
 			base,
 			base,
-			apiRouterWithCnameSupportInst,
+			apiRouterWithActivityInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
 			apiRouterWithPrefixInst,
-			apiRouterWithStatusInst,
 			apiRouterWithTLSSupportInst,
+			apiRouterV2Inst,
 		}
 	}
-	if !supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+	if !supports["activity"] && supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["maintenance"] && supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
-			router.CustomHealthcheckRouter
+			router.CNameRouter
+			router.InfoRouter
+			router.MaintenanceRouter
 			router.PrefixRouter
-			router.StatusRouter
 			router.TLSRouter
+			router.RouterV2
 		}{
 			base,
 			base,
-			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
 			apiRouterWithPrefixInst,
-			apiRouterWithStatusInst,
 			apiRouterWithTLSSupportInst,
+			apiRouterV2Inst,
 		}
 	}
-	if supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+	if supports["activity"] && supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["maintenance"] && supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
+			router.ActivityRouter
 			router.CNameRouter
-			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.MaintenanceRouter
 			router.PrefixRouter
-			router.StatusRouter
 			router.TLSRouter
+			router.RouterV2
 		}{
 			base,
 			base,
+			apiRouterWithActivityInst,
 			apiRouterWithCnameSupportInst,
-			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
 			apiRouterWithPrefixInst,
-			apiRouterWithStatusInst,
 			apiRouterWithTLSSupportInst,
+			apiRouterV2Inst,
 		}
 	}
-	if !supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+	if !supports["activity"] && !supports["cname"] && supports["healthcheck"] && supports["info"] && supports["maintenance"] && supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
+			router.CustomHealthcheckRouter
 			router.InfoRouter
+			router.MaintenanceRouter
 			router.PrefixRouter
-			router.StatusRouter
 			router.TLSRouter
+			router.RouterV2
 		}{
 			base,
 			base,
+			apiRouterWithHealthcheckSupportInst,
 			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
 			apiRouterWithPrefixInst,
-			apiRouterWithStatusInst,
 			apiRouterWithTLSSupportInst,
+			apiRouterV2Inst,
 		}
 	}
-	if supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+	if supports["activity"] && !supports["cname"] && supports["healthcheck"] && supports["info"] && supports["maintenance"] && supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
-			router.CNameRouter
+			router.ActivityRouter
+			router.CustomHealthcheckRouter
 			router.InfoRouter
+			router.MaintenanceRouter
 			router.PrefixRouter
-			router.StatusRouter
 			router.TLSRouter
+			router.RouterV2
 		}{
 			base,
 			base,
-			apiRouterWithCnameSupportInst,
+			apiRouterWithActivityInst,
+			apiRouterWithHealthcheckSupportInst,
 			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
 			apiRouterWithPrefixInst,
-			apiRouterWithStatusInst,
 			apiRouterWithTLSSupportInst,
+			apiRouterV2Inst,
 		}
 	}
-	if !supports["cname"] && supports["healthcheck"] && supports["info"] && supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+	if !supports["activity"] && supports["cname"] && supports["healthcheck"] && supports["info"] && supports["maintenance"] && supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
+			router.CNameRouter
 			router.CustomHealthcheckRouter
 			router.InfoRouter
+			router.MaintenanceRouter
 			router.PrefixRouter
-			router.StatusRouter
 			router.TLSRouter
+			router.RouterV2
 		}{
 			base,
 			base,
+			apiRouterWithCnameSupportInst,
 			apiRouterWithHealthcheckSupportInst,
 			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
 			apiRouterWithPrefixInst,
-			apiRouterWithStatusInst,
 			apiRouterWithTLSSupportInst,
+			apiRouterV2Inst,
 		}
 	}
-	if supports["cname"] && supports["healthcheck"] && supports["info"] && supports["prefix"] && supports["status"] && supports["tls"] && !supports["v2"] {
+	if supports["activity"] && supports["cname"] && supports["healthcheck"] && supports["info"] && supports["maintenance"] && supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
+			router.ActivityRouter
 			router.CNameRouter
 			router.CustomHealthcheckRouter
 			router.InfoRouter
+			router.MaintenanceRouter
 			router.PrefixRouter
-			router.StatusRouter
 			router.TLSRouter
+			router.RouterV2
 		}{
 			base,
 			base,
+			apiRouterWithActivityInst,
 			apiRouterWithCnameSupportInst,
 			apiRouterWithHealthcheckSupportInst,
 			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
 			apiRouterWithPrefixInst,
-			apiRouterWithStatusInst,
 			apiRouterWithTLSSupportInst,
+			apiRouterV2Inst,
 		}
 	}
-	if !supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+	if !supports["activity"] && !supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && !supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
+			router.StatusRouter
+			router.TLSRouter
 			router.RouterV2
 		}{
 			base,
 			base,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
 			apiRouterV2Inst,
 		}
 	}
-	if supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+	if supports["activity"] && !supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && !supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
-			router.CNameRouter
+			router.ActivityRouter
+			router.StatusRouter
+			router.TLSRouter
 			router.RouterV2
 		}{
 			base,
 			base,
-			apiRouterWithCnameSupportInst,
+			apiRouterWithActivityInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
 			apiRouterV2Inst,
 		}
 	}
-	if !supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+	if !supports["activity"] && supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && !supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
-			router.CustomHealthcheckRouter
+			router.CNameRouter
+			router.StatusRouter
+			router.TLSRouter
 			router.RouterV2
 		}{
 			base,
 			base,
-			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
 			apiRouterV2Inst,
 		}
 	}
-	if supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+	if supports["activity"] && supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && !supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
+			router.ActivityRouter
 			router.CNameRouter
-			router.CustomHealthcheckRouter
+			router.StatusRouter
+			router.TLSRouter
 			router.RouterV2
 		}{
 			base,
 			base,
+			apiRouterWithActivityInst,
 			apiRouterWithCnameSupportInst,
-			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
 			apiRouterV2Inst,
 		}
 	}
-	if !supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+	if !supports["activity"] && !supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && !supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
-			router.InfoRouter
+			router.CustomHealthcheckRouter
+			router.StatusRouter
+			router.TLSRouter
 			router.RouterV2
 		}{
 			base,
 			base,
-			apiRouterWithInfoInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
 			apiRouterV2Inst,
 		}
 	}
-	if supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+	if supports["activity"] && !supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && !supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
-			router.CNameRouter
-			router.InfoRouter
+			router.ActivityRouter
+			router.CustomHealthcheckRouter
+			router.StatusRouter
+			router.TLSRouter
 			router.RouterV2
 		}{
 			base,
 			base,
-			apiRouterWithCnameSupportInst,
-			apiRouterWithInfoInst,
+			apiRouterWithActivityInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
 			apiRouterV2Inst,
 		}
 	}
-	if !supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+	if !supports["activity"] && supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && !supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
+			router.CNameRouter
 			router.CustomHealthcheckRouter
-			router.InfoRouter
+			router.StatusRouter
+			router.TLSRouter
 			router.RouterV2
 		}{
 			base,
 			base,
+			apiRouterWithCnameSupportInst,
 			apiRouterWithHealthcheckSupportInst,
-			apiRouterWithInfoInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
 			apiRouterV2Inst,
 		}
 	}
-	if supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+	if supports["activity"] && supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && !supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
+			router.ActivityRouter
 			router.CNameRouter
 			router.CustomHealthcheckRouter
-			router.InfoRouter
+			router.StatusRouter
+			router.TLSRouter
 			router.RouterV2
 		}{
 			base,
 			base,
+			apiRouterWithActivityInst,
 			apiRouterWithCnameSupportInst,
 			apiRouterWithHealthcheckSupportInst,
-			apiRouterWithInfoInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
 			apiRouterV2Inst,
 		}
 	}
-	if !supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+	if !supports["activity"] && !supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["maintenance"] && !supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
-			router.PrefixRouter
+			router.InfoRouter
+			router.StatusRouter
+			router.TLSRouter
 			router.RouterV2
 		}{
 			base,
 			base,
-			apiRouterWithPrefixInst,
+			apiRouterWithInfoInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
 			apiRouterV2Inst,
 		}
 	}
-	if supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+	if supports["activity"] && !supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["maintenance"] && !supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
-			router.CNameRouter
-			router.PrefixRouter
+			router.ActivityRouter
+			router.InfoRouter
+			router.StatusRouter
+			router.TLSRouter
 			router.RouterV2
 		}{
 			base,
 			base,
-			apiRouterWithCnameSupportInst,
-			apiRouterWithPrefixInst,
+			apiRouterWithActivityInst,
+			apiRouterWithInfoInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
 			apiRouterV2Inst,
 		}
 	}
-	if !supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+	if !supports["activity"] && supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["maintenance"] && !supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
-			router.CustomHealthcheckRouter
-			router.PrefixRouter
+			router.CNameRouter
+			router.InfoRouter
+			router.StatusRouter
+			router.TLSRouter
 			router.RouterV2
 		}{
 			base,
 			base,
-			apiRouterWithHealthcheckSupportInst,
-			apiRouterWithPrefixInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
 			apiRouterV2Inst,
 		}
 	}
-	if supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+	if supports["activity"] && supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["maintenance"] && !supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
+			router.ActivityRouter
 			router.CNameRouter
-			router.CustomHealthcheckRouter
-			router.PrefixRouter
+			router.InfoRouter
+			router.StatusRouter
+			router.TLSRouter
 			router.RouterV2
 		}{
 			base,
 			base,
+			apiRouterWithActivityInst,
 			apiRouterWithCnameSupportInst,
-			apiRouterWithHealthcheckSupportInst,
-			apiRouterWithPrefixInst,
+			apiRouterWithInfoInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
 			apiRouterV2Inst,
 		}
 	}
-	if !supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+	if !supports["activity"] && !supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["maintenance"] && !supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
+			router.CustomHealthcheckRouter
 			router.InfoRouter
-			router.PrefixRouter
+			router.StatusRouter
+			router.TLSRouter
 			router.RouterV2
 		}{
 			base,
 			base,
+			apiRouterWithHealthcheckSupportInst,
 			apiRouterWithInfoInst,
-			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
 			apiRouterV2Inst,
 		}
 	}
-	if supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+	if supports["activity"] && !supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["maintenance"] && !supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
-			router.CNameRouter
+			router.ActivityRouter
+			router.CustomHealthcheckRouter
 			router.InfoRouter
-			router.PrefixRouter
+			router.StatusRouter
+			router.TLSRouter
 			router.RouterV2
 		}{
 			base,
 			base,
-			apiRouterWithCnameSupportInst,
+			apiRouterWithActivityInst,
+			apiRouterWithHealthcheckSupportInst,
 			apiRouterWithInfoInst,
-			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
 			apiRouterV2Inst,
 		}
 	}
-	if !supports["cname"] && supports["healthcheck"] && supports["info"] && supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+	if !supports["activity"] && supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["maintenance"] && !supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
+			router.CNameRouter
 			router.CustomHealthcheckRouter
 			router.InfoRouter
-			router.PrefixRouter
+			router.StatusRouter
+			router.TLSRouter
 			router.RouterV2
 		}{
 			base,
 			base,
+			apiRouterWithCnameSupportInst,
 			apiRouterWithHealthcheckSupportInst,
 			apiRouterWithInfoInst,
-			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
 			apiRouterV2Inst,
 		}
 	}
-	if supports["cname"] && supports["healthcheck"] && supports["info"] && supports["prefix"] && !supports["status"] && !supports["tls"] && supports["v2"] {
+	if supports["activity"] && supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["maintenance"] && !supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
+			router.ActivityRouter
 			router.CNameRouter
 			router.CustomHealthcheckRouter
 			router.InfoRouter
-			router.PrefixRouter
+			router.StatusRouter
+			router.TLSRouter
 			router.RouterV2
 		}{
 			base,
 			base,
+			apiRouterWithActivityInst,
 			apiRouterWithCnameSupportInst,
 			apiRouterWithHealthcheckSupportInst,
 			apiRouterWithInfoInst,
-			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
 			apiRouterV2Inst,
 		}
 	}
-	if !supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+	if !supports["activity"] && !supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["maintenance"] && !supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
+			router.MaintenanceRouter
 			router.StatusRouter
+			router.TLSRouter
 			router.RouterV2
 		}{
 			base,
 			base,
+			apiRouterWithMaintenanceInst,
 			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
 			apiRouterV2Inst,
 		}
 	}
-	if supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+	if supports["activity"] && !supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["maintenance"] && !supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
-			router.CNameRouter
+			router.ActivityRouter
+			router.MaintenanceRouter
 			router.StatusRouter
+			router.TLSRouter
 			router.RouterV2
 		}{
 			base,
 			base,
-			apiRouterWithCnameSupportInst,
+			apiRouterWithActivityInst,
+			apiRouterWithMaintenanceInst,
 			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
 			apiRouterV2Inst,
 		}
 	}
-	if !supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+	if !supports["activity"] && supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["maintenance"] && !supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
-			router.CustomHealthcheckRouter
+			router.CNameRouter
+			router.MaintenanceRouter
 			router.StatusRouter
+			router.TLSRouter
 			router.RouterV2
 		}{
 			base,
 			base,
-			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithMaintenanceInst,
 			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
 			apiRouterV2Inst,
 		}
 	}
-	if supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+	if supports["activity"] && supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["maintenance"] && !supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
+			router.ActivityRouter
 			router.CNameRouter
-			router.CustomHealthcheckRouter
+			router.MaintenanceRouter
 			router.StatusRouter
+			router.TLSRouter
 			router.RouterV2
 		}{
 			base,
 			base,
+			apiRouterWithActivityInst,
 			apiRouterWithCnameSupportInst,
-			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithMaintenanceInst,
 			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
 			apiRouterV2Inst,
 		}
 	}
-	if !supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+	if !supports["activity"] && !supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["maintenance"] && !supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
-			router.InfoRouter
+			router.CustomHealthcheckRouter
+			router.MaintenanceRouter
 			router.StatusRouter
+			router.TLSRouter
 			router.RouterV2
 		}{
 			base,
 			base,
-			apiRouterWithInfoInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithMaintenanceInst,
 			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
 			apiRouterV2Inst,
 		}
 	}
-	if supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+	if supports["activity"] && !supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["maintenance"] && !supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
-			router.CNameRouter
-			router.InfoRouter
+			router.ActivityRouter
+			router.CustomHealthcheckRouter
+			router.MaintenanceRouter
 			router.StatusRouter
+			router.TLSRouter
 			router.RouterV2
 		}{
 			base,
 			base,
-			apiRouterWithCnameSupportInst,
-			apiRouterWithInfoInst,
+			apiRouterWithActivityInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithMaintenanceInst,
 			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
 			apiRouterV2Inst,
 		}
 	}
-	if !supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+	if !supports["activity"] && supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["maintenance"] && !supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
+			router.CNameRouter
 			router.CustomHealthcheckRouter
-			router.InfoRouter
+			router.MaintenanceRouter
 			router.StatusRouter
+			router.TLSRouter
 			router.RouterV2
 		}{
 			base,
 			base,
+			apiRouterWithCnameSupportInst,
 			apiRouterWithHealthcheckSupportInst,
-			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
 			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
 			apiRouterV2Inst,
 		}
 	}
-	if supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+	if supports["activity"] && supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["maintenance"] && !supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
+			router.ActivityRouter
 			router.CNameRouter
 			router.CustomHealthcheckRouter
-			router.InfoRouter
+			router.MaintenanceRouter
 			router.StatusRouter
+			router.TLSRouter
 			router.RouterV2
 		}{
 			base,
 			base,
+			apiRouterWithActivityInst,
 			apiRouterWithCnameSupportInst,
 			apiRouterWithHealthcheckSupportInst,
-			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
 			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
 			apiRouterV2Inst,
 		}
 	}
-	if !supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+	if !supports["activity"] && !supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["maintenance"] && !supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
-			router.PrefixRouter
+			router.InfoRouter
+			router.MaintenanceRouter
 			router.StatusRouter
+			router.TLSRouter
 			router.RouterV2
 		}{
 			base,
 			base,
-			apiRouterWithPrefixInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
 			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
 			apiRouterV2Inst,
 		}
 	}
-	if supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+	if supports["activity"] && !supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["maintenance"] && !supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
-			router.CNameRouter
-			router.PrefixRouter
+			router.ActivityRouter
+			router.InfoRouter
+			router.MaintenanceRouter
 			router.StatusRouter
+			router.TLSRouter
 			router.RouterV2
 		}{
 			base,
 			base,
-			apiRouterWithCnameSupportInst,
-			apiRouterWithPrefixInst,
+			apiRouterWithActivityInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
 			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
 			apiRouterV2Inst,
 		}
 	}
-	if !supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+	if !supports["activity"] && supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["maintenance"] && !supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
-			router.CustomHealthcheckRouter
-			router.PrefixRouter
+			router.CNameRouter
+			router.InfoRouter
+			router.MaintenanceRouter
 			router.StatusRouter
+			router.TLSRouter
 			router.RouterV2
 		}{
 			base,
 			base,
-			apiRouterWithHealthcheckSupportInst,
-			apiRouterWithPrefixInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
 			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
 			apiRouterV2Inst,
 		}
 	}
-	if supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+	if supports["activity"] && supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["maintenance"] && !supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
+			router.ActivityRouter
 			router.CNameRouter
-			router.CustomHealthcheckRouter
-			router.PrefixRouter
+			router.InfoRouter
+			router.MaintenanceRouter
 			router.StatusRouter
+			router.TLSRouter
 			router.RouterV2
 		}{
 			base,
 			base,
+			apiRouterWithActivityInst,
 			apiRouterWithCnameSupportInst,
-			apiRouterWithHealthcheckSupportInst,
-			apiRouterWithPrefixInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
 			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
 			apiRouterV2Inst,
 		}
 	}
-	if !supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+	if !supports["activity"] && !supports["cname"] && supports["healthcheck"] && supports["info"] && supports["maintenance"] && !supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
+			router.CustomHealthcheckRouter
 			router.InfoRouter
-			router.PrefixRouter
+			router.MaintenanceRouter
 			router.StatusRouter
+			router.TLSRouter
 			router.RouterV2
 		}{
 			base,
 			base,
+			apiRouterWithHealthcheckSupportInst,
 			apiRouterWithInfoInst,
-			apiRouterWithPrefixInst,
+			apiRouterWithMaintenanceInst,
 			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
 			apiRouterV2Inst,
 		}
 	}
-	if supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+	if supports["activity"] && !supports["cname"] && supports["healthcheck"] && supports["info"] && supports["maintenance"] && !supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
-			router.CNameRouter
+			router.ActivityRouter
+			router.CustomHealthcheckRouter
 			router.InfoRouter
-			router.PrefixRouter
+			router.MaintenanceRouter
 			router.StatusRouter
+			router.TLSRouter
 			router.RouterV2
 		}{
 			base,
 			base,
-			apiRouterWithCnameSupportInst,
+			apiRouterWithActivityInst,
+			apiRouterWithHealthcheckSupportInst,
 			apiRouterWithInfoInst,
-			apiRouterWithPrefixInst,
+			apiRouterWithMaintenanceInst,
 			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
 			apiRouterV2Inst,
 		}
 	}
-	if !supports["cname"] && supports["healthcheck"] && supports["info"] && supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+	if !supports["activity"] && supports["cname"] && supports["healthcheck"] && supports["info"] && supports["maintenance"] && !supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
+			router.CNameRouter
 			router.CustomHealthcheckRouter
 			router.InfoRouter
-			router.PrefixRouter
+			router.MaintenanceRouter
 			router.StatusRouter
+			router.TLSRouter
 			router.RouterV2
 		}{
 			base,
 			base,
+			apiRouterWithCnameSupportInst,
 			apiRouterWithHealthcheckSupportInst,
 			apiRouterWithInfoInst,
-			apiRouterWithPrefixInst,
+			apiRouterWithMaintenanceInst,
 			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
 			apiRouterV2Inst,
 		}
 	}
-	if supports["cname"] && supports["healthcheck"] && supports["info"] && supports["prefix"] && supports["status"] && !supports["tls"] && supports["v2"] {
+	if supports["activity"] && supports["cname"] && supports["healthcheck"] && supports["info"] && supports["maintenance"] && !supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
+			router.ActivityRouter
 			router.CNameRouter
 			router.CustomHealthcheckRouter
 			router.InfoRouter
-			router.PrefixRouter
+			router.MaintenanceRouter
 			router.StatusRouter
+			router.TLSRouter
 			router.RouterV2
 		}{
 			base,
 			base,
+			apiRouterWithActivityInst,
 			apiRouterWithCnameSupportInst,
 			apiRouterWithHealthcheckSupportInst,
 			apiRouterWithInfoInst,
-			apiRouterWithPrefixInst,
+			apiRouterWithMaintenanceInst,
 			apiRouterWithStatusInst,
+			apiRouterWithTLSSupportInst,
 			apiRouterV2Inst,
 		}
 	}
-	if !supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
+	if !supports["activity"] && !supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
+			router.PrefixRouter
+			router.StatusRouter
 			router.TLSRouter
 			router.RouterV2
 		}{
 			base,
 			base,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
 			apiRouterWithTLSSupportInst,
 			apiRouterV2Inst,
 		}
 	}
-	if supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
+	if supports["activity"] && !supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
-			router.CNameRouter
+			router.ActivityRouter
+			router.PrefixRouter
+			router.StatusRouter
 			router.TLSRouter
 			router.RouterV2
 		}{
 			base,
 			base,
-			apiRouterWithCnameSupportInst,
+			apiRouterWithActivityInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
 			apiRouterWithTLSSupportInst,
 			apiRouterV2Inst,
 		}
 	}
-	if !supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
+	if !supports["activity"] && supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
-			router.CustomHealthcheckRouter
+			router.CNameRouter
+			router.PrefixRouter
+			router.StatusRouter
 			router.TLSRouter
 			router.RouterV2
 		}{
 			base,
 			base,
-			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
 			apiRouterWithTLSSupportInst,
 			apiRouterV2Inst,
 		}
 	}
-	if supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
+	if supports["activity"] && supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
+			router.ActivityRouter
 			router.CNameRouter
-			router.CustomHealthcheckRouter
+			router.PrefixRouter
+			router.StatusRouter
 			router.TLSRouter
 			router.RouterV2
 		}{
 			base,
 			base,
+			apiRouterWithActivityInst,
 			apiRouterWithCnameSupportInst,
-			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
 			apiRouterWithTLSSupportInst,
 			apiRouterV2Inst,
 		}
 	}
-	if !supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
+	if !supports["activity"] && !supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
-			router.InfoRouter
+			router.CustomHealthcheckRouter
+			router.PrefixRouter
+			router.StatusRouter
 			router.TLSRouter
 			router.RouterV2
 		}{
 			base,
 			base,
-			apiRouterWithInfoInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
 			apiRouterWithTLSSupportInst,
 			apiRouterV2Inst,
 		}
 	}
-	if supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
+	if supports["activity"] && !supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
-			router.CNameRouter
-			router.InfoRouter
+			router.ActivityRouter
+			router.CustomHealthcheckRouter
+			router.PrefixRouter
+			router.StatusRouter
 			router.TLSRouter
 			router.RouterV2
 		}{
 			base,
 			base,
-			apiRouterWithCnameSupportInst,
-			apiRouterWithInfoInst,
+			apiRouterWithActivityInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
 			apiRouterWithTLSSupportInst,
 			apiRouterV2Inst,
 		}
 	}
-	if !supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
+	if !supports["activity"] && supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
+			router.CNameRouter
 			router.CustomHealthcheckRouter
-			router.InfoRouter
+			router.PrefixRouter
+			router.StatusRouter
 			router.TLSRouter
 			router.RouterV2
 		}{
 			base,
 			base,
+			apiRouterWithCnameSupportInst,
 			apiRouterWithHealthcheckSupportInst,
-			apiRouterWithInfoInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
 			apiRouterWithTLSSupportInst,
 			apiRouterV2Inst,
 		}
 	}
-	if supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
+	if supports["activity"] && supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["maintenance"] && supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
+			router.ActivityRouter
 			router.CNameRouter
 			router.CustomHealthcheckRouter
-			router.InfoRouter
+			router.PrefixRouter
+			router.StatusRouter
 			router.TLSRouter
 			router.RouterV2
 		}{
 			base,
 			base,
+			apiRouterWithActivityInst,
 			apiRouterWithCnameSupportInst,
 			apiRouterWithHealthcheckSupportInst,
-			apiRouterWithInfoInst,
+			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
 			apiRouterWithTLSSupportInst,
 			apiRouterV2Inst,
 		}
 	}
-	if !supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
+	if !supports["activity"] && !supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["maintenance"] && supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
+			router.InfoRouter
 			router.PrefixRouter
+			router.StatusRouter
 			router.TLSRouter
 			router.RouterV2
 		}{
 			base,
 			base,
+			apiRouterWithInfoInst,
 			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
 			apiRouterWithTLSSupportInst,
 			apiRouterV2Inst,
 		}
 	}
-	if supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
+	if supports["activity"] && !supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["maintenance"] && supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
-			router.CNameRouter
+			router.ActivityRouter
+			router.InfoRouter
 			router.PrefixRouter
+			router.StatusRouter
 			router.TLSRouter
 			router.RouterV2
 		}{
 			base,
 			base,
-			apiRouterWithCnameSupportInst,
+			apiRouterWithActivityInst,
+			apiRouterWithInfoInst,
 			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
 			apiRouterWithTLSSupportInst,
 			apiRouterV2Inst,
 		}
 	}
-	if !supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
+	if !supports["activity"] && supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["maintenance"] && supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
-			router.CustomHealthcheckRouter
+			router.CNameRouter
+			router.InfoRouter
 			router.PrefixRouter
+			router.StatusRouter
 			router.TLSRouter
 			router.RouterV2
 		}{
 			base,
 			base,
-			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithInfoInst,
 			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
 			apiRouterWithTLSSupportInst,
 			apiRouterV2Inst,
 		}
 	}
-	if supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
+	if supports["activity"] && supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["maintenance"] && supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
+			router.ActivityRouter
 			router.CNameRouter
-			router.CustomHealthcheckRouter
+			router.InfoRouter
 			router.PrefixRouter
+			router.StatusRouter
 			router.TLSRouter
 			router.RouterV2
 		}{
 			base,
 			base,
+			apiRouterWithActivityInst,
 			apiRouterWithCnameSupportInst,
-			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
 			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
 			apiRouterWithTLSSupportInst,
 			apiRouterV2Inst,
 		}
 	}
-	if !supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
+	if !supports["activity"] && !supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["maintenance"] && supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
+			router.CustomHealthcheckRouter
 			router.InfoRouter
 			router.PrefixRouter
+			router.StatusRouter
 			router.TLSRouter
 			router.RouterV2
 		}{
 			base,
 			base,
+			apiRouterWithHealthcheckSupportInst,
 			apiRouterWithInfoInst,
 			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
 			apiRouterWithTLSSupportInst,
 			apiRouterV2Inst,
 		}
 	}
-	if supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
+	if supports["activity"] && !supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["maintenance"] && supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
-			router.CNameRouter
+			router.ActivityRouter
+			router.CustomHealthcheckRouter
 			router.InfoRouter
 			router.PrefixRouter
+			router.StatusRouter
 			router.TLSRouter
 			router.RouterV2
 		}{
 			base,
 			base,
-			apiRouterWithCnameSupportInst,
+			apiRouterWithActivityInst,
+			apiRouterWithHealthcheckSupportInst,
 			apiRouterWithInfoInst,
 			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
 			apiRouterWithTLSSupportInst,
 			apiRouterV2Inst,
 		}
 	}
-	if !supports["cname"] && supports["healthcheck"] && supports["info"] && supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
+	if !supports["activity"] && supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["maintenance"] && supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
+			router.CNameRouter
 			router.CustomHealthcheckRouter
 			router.InfoRouter
 			router.PrefixRouter
+			router.StatusRouter
 			router.TLSRouter
 			router.RouterV2
 		}{
 			base,
 			base,
+			apiRouterWithCnameSupportInst,
 			apiRouterWithHealthcheckSupportInst,
 			apiRouterWithInfoInst,
 			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
 			apiRouterWithTLSSupportInst,
 			apiRouterV2Inst,
 		}
 	}
-	if supports["cname"] && supports["healthcheck"] && supports["info"] && supports["prefix"] && !supports["status"] && supports["tls"] && supports["v2"] {
+	if supports["activity"] && supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["maintenance"] && supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
+			router.ActivityRouter
 			router.CNameRouter
 			router.CustomHealthcheckRouter
 			router.InfoRouter
 			router.PrefixRouter
+			router.StatusRouter
 			router.TLSRouter
 			router.RouterV2
 		}{
 			base,
 			base,
+			apiRouterWithActivityInst,
 			apiRouterWithCnameSupportInst,
 			apiRouterWithHealthcheckSupportInst,
 			apiRouterWithInfoInst,
 			apiRouterWithPrefixInst,
+			apiRouterWithStatusInst,
 			apiRouterWithTLSSupportInst,
 			apiRouterV2Inst,
 		}
 	}
-	if !supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
+	if !supports["activity"] && !supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["maintenance"] && supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
 			router.StatusRouter
 			router.TLSRouter
 			router.RouterV2
 		}{
 			base,
 			base,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
 			apiRouterWithStatusInst,
 			apiRouterWithTLSSupportInst,
 			apiRouterV2Inst,
 		}
 	}
-	if supports["cname"] && !supports["healthcheck"] && !supports["info"] && !supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
+	if supports["activity"] && !supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["maintenance"] && supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
-			router.CNameRouter
+			router.ActivityRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
 			router.StatusRouter
 			router.TLSRouter
 			router.RouterV2
 		}{
 			base,
 			base,
-			apiRouterWithCnameSupportInst,
+			apiRouterWithActivityInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
 			apiRouterWithStatusInst,
 			apiRouterWithTLSSupportInst,
 			apiRouterV2Inst,
 		}
 	}
-	if !supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
+	if !supports["activity"] && supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["maintenance"] && supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
-			router.CustomHealthcheckRouter
+			router.CNameRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
 			router.StatusRouter
 			router.TLSRouter
 			router.RouterV2
 		}{
 			base,
 			base,
-			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
 			apiRouterWithStatusInst,
 			apiRouterWithTLSSupportInst,
 			apiRouterV2Inst,
 		}
 	}
-	if supports["cname"] && supports["healthcheck"] && !supports["info"] && !supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
+	if supports["activity"] && supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["maintenance"] && supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
+			router.ActivityRouter
 			router.CNameRouter
-			router.CustomHealthcheckRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
 			router.StatusRouter
 			router.TLSRouter
 			router.RouterV2
 		}{
 			base,
 			base,
+			apiRouterWithActivityInst,
 			apiRouterWithCnameSupportInst,
-			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
 			apiRouterWithStatusInst,
 			apiRouterWithTLSSupportInst,
 			apiRouterV2Inst,
 		}
 	}
-	if !supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
+	if !supports["activity"] && !supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["maintenance"] && supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
-			router.InfoRouter
+			router.CustomHealthcheckRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
 			router.StatusRouter
 			router.TLSRouter
 			router.RouterV2
 		}{
 			base,
 			base,
-			apiRouterWithInfoInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
 			apiRouterWithStatusInst,
 			apiRouterWithTLSSupportInst,
 			apiRouterV2Inst,
 		}
 	}
-	if supports["cname"] && !supports["healthcheck"] && supports["info"] && !supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
+	if supports["activity"] && !supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["maintenance"] && supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
-			router.CNameRouter
-			router.InfoRouter
+			router.ActivityRouter
+			router.CustomHealthcheckRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
 			router.StatusRouter
 			router.TLSRouter
 			router.RouterV2
 		}{
 			base,
 			base,
-			apiRouterWithCnameSupportInst,
-			apiRouterWithInfoInst,
+			apiRouterWithActivityInst,
+			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
 			apiRouterWithStatusInst,
 			apiRouterWithTLSSupportInst,
 			apiRouterV2Inst,
 		}
 	}
-	if !supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
+	if !supports["activity"] && supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["maintenance"] && supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
+			router.CNameRouter
 			router.CustomHealthcheckRouter
-			router.InfoRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
 			router.StatusRouter
 			router.TLSRouter
 			router.RouterV2
 		}{
 			base,
 			base,
+			apiRouterWithCnameSupportInst,
 			apiRouterWithHealthcheckSupportInst,
-			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
 			apiRouterWithStatusInst,
 			apiRouterWithTLSSupportInst,
 			apiRouterV2Inst,
 		}
 	}
-	if supports["cname"] && supports["healthcheck"] && supports["info"] && !supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
+	if supports["activity"] && supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["maintenance"] && supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
+			router.ActivityRouter
 			router.CNameRouter
 			router.CustomHealthcheckRouter
-			router.InfoRouter
+			router.MaintenanceRouter
+			router.PrefixRouter
 			router.StatusRouter
 			router.TLSRouter
 			router.RouterV2
 		}{
 			base,
 			base,
+			apiRouterWithActivityInst,
 			apiRouterWithCnameSupportInst,
 			apiRouterWithHealthcheckSupportInst,
-			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
+			apiRouterWithPrefixInst,
 			apiRouterWithStatusInst,
 			apiRouterWithTLSSupportInst,
 			apiRouterV2Inst,
 		}
 	}
-	if !supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
+	if !supports["activity"] && !supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["maintenance"] && supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
+			router.InfoRouter
+			router.MaintenanceRouter
 			router.PrefixRouter
 			router.StatusRouter
 			router.TLSRouter
@@ -1919,17 +9059,21 @@ func toSupportedInterface(base *apiRouter, supports map[capability]bool) router.
 		}{
 			base,
 			base,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
 			apiRouterWithPrefixInst,
 			apiRouterWithStatusInst,
 			apiRouterWithTLSSupportInst,
 			apiRouterV2Inst,
 		}
 	}
-	if supports["cname"] && !supports["healthcheck"] && !supports["info"] && supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
+	if supports["activity"] && !supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["maintenance"] && supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
-			router.CNameRouter
+			router.ActivityRouter
+			router.InfoRouter
+			router.MaintenanceRouter
 			router.PrefixRouter
 			router.StatusRouter
 			router.TLSRouter
@@ -1937,18 +9081,22 @@ func toSupportedInterface(base *apiRouter, supports map[capability]bool) router.
 		}{
 			base,
 			base,
-			apiRouterWithCnameSupportInst,
+			apiRouterWithActivityInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
 			apiRouterWithPrefixInst,
 			apiRouterWithStatusInst,
 			apiRouterWithTLSSupportInst,
 			apiRouterV2Inst,
 		}
 	}
-	if !supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
+	if !supports["activity"] && supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["maintenance"] && supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
-			router.CustomHealthcheckRouter
+			router.CNameRouter
+			router.InfoRouter
+			router.MaintenanceRouter
 			router.PrefixRouter
 			router.StatusRouter
 			router.TLSRouter
@@ -1956,19 +9104,23 @@ func toSupportedInterface(base *apiRouter, supports map[capability]bool) router.
 		}{
 			base,
 			base,
-			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithCnameSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
 			apiRouterWithPrefixInst,
 			apiRouterWithStatusInst,
 			apiRouterWithTLSSupportInst,
 			apiRouterV2Inst,
 		}
 	}
-	if supports["cname"] && supports["healthcheck"] && !supports["info"] && supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
+	if supports["activity"] && supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["maintenance"] && supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
+			router.ActivityRouter
 			router.CNameRouter
-			router.CustomHealthcheckRouter
+			router.InfoRouter
+			router.MaintenanceRouter
 			router.PrefixRouter
 			router.StatusRouter
 			router.TLSRouter
@@ -1976,19 +9128,23 @@ func toSupportedInterface(base *apiRouter, supports map[capability]bool) router.
 		}{
 			base,
 			base,
+			apiRouterWithActivityInst,
 			apiRouterWithCnameSupportInst,
-			apiRouterWithHealthcheckSupportInst,
+			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
 			apiRouterWithPrefixInst,
 			apiRouterWithStatusInst,
 			apiRouterWithTLSSupportInst,
 			apiRouterV2Inst,
 		}
 	}
-	if !supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
+	if !supports["activity"] && !supports["cname"] && supports["healthcheck"] && supports["info"] && supports["maintenance"] && supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
+			router.CustomHealthcheckRouter
 			router.InfoRouter
+			router.MaintenanceRouter
 			router.PrefixRouter
 			router.StatusRouter
 			router.TLSRouter
@@ -1996,19 +9152,23 @@ func toSupportedInterface(base *apiRouter, supports map[capability]bool) router.
 		}{
 			base,
 			base,
+			apiRouterWithHealthcheckSupportInst,
 			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
 			apiRouterWithPrefixInst,
 			apiRouterWithStatusInst,
 			apiRouterWithTLSSupportInst,
 			apiRouterV2Inst,
 		}
 	}
-	if supports["cname"] && !supports["healthcheck"] && supports["info"] && supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
+	if supports["activity"] && !supports["cname"] && supports["healthcheck"] && supports["info"] && supports["maintenance"] && supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
-			router.CNameRouter
+			router.ActivityRouter
+			router.CustomHealthcheckRouter
 			router.InfoRouter
+			router.MaintenanceRouter
 			router.PrefixRouter
 			router.StatusRouter
 			router.TLSRouter
@@ -2016,20 +9176,24 @@ func toSupportedInterface(base *apiRouter, supports map[capability]bool) router.
 		}{
 			base,
 			base,
-			apiRouterWithCnameSupportInst,
+			apiRouterWithActivityInst,
+			apiRouterWithHealthcheckSupportInst,
 			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
 			apiRouterWithPrefixInst,
 			apiRouterWithStatusInst,
 			apiRouterWithTLSSupportInst,
 			apiRouterV2Inst,
 		}
 	}
-	if !supports["cname"] && supports["healthcheck"] && supports["info"] && supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
+	if !supports["activity"] && supports["cname"] && supports["healthcheck"] && supports["info"] && supports["maintenance"] && supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
+			router.CNameRouter
 			router.CustomHealthcheckRouter
 			router.InfoRouter
+			router.MaintenanceRouter
 			router.PrefixRouter
 			router.StatusRouter
 			router.TLSRouter
@@ -2037,21 +9201,25 @@ func toSupportedInterface(base *apiRouter, supports map[capability]bool) router.
 		}{
 			base,
 			base,
+			apiRouterWithCnameSupportInst,
 			apiRouterWithHealthcheckSupportInst,
 			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
 			apiRouterWithPrefixInst,
 			apiRouterWithStatusInst,
 			apiRouterWithTLSSupportInst,
 			apiRouterV2Inst,
 		}
 	}
-	if supports["cname"] && supports["healthcheck"] && supports["info"] && supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
+	if supports["activity"] && supports["cname"] && supports["healthcheck"] && supports["info"] && supports["maintenance"] && supports["prefix"] && supports["status"] && supports["tls"] && supports["v2"] {
 		return &struct {
 			router.Router
 			router.OptsRouter
+			router.ActivityRouter
 			router.CNameRouter
 			router.CustomHealthcheckRouter
 			router.InfoRouter
+			router.MaintenanceRouter
 			router.PrefixRouter
 			router.StatusRouter
 			router.TLSRouter
@@ -2059,9 +9227,11 @@ func toSupportedInterface(base *apiRouter, supports map[capability]bool) router.
 		}{
 			base,
 			base,
+			apiRouterWithActivityInst,
 			apiRouterWithCnameSupportInst,
 			apiRouterWithHealthcheckSupportInst,
 			apiRouterWithInfoInst,
+			apiRouterWithMaintenanceInst,
 			apiRouterWithPrefixInst,
 			apiRouterWithStatusInst,
 			apiRouterWithTLSSupportInst,
@@ -85,6 +85,49 @@ func diffRoutes(old []*url.URL, new []*url.URL) (toAdd []*url.URL, toRemove []*u
 	return toAdd, toRemove
 }
 
+// mergeRoutableAddresses combines the addresses with the same prefix, as
+// returned for apps running in more than one pool. The weight of the first
+// entry of each prefix is kept.
+func mergeRoutableAddresses(routes []appTypes.RoutableAddresses) []appTypes.RoutableAddresses {
+	var merged []appTypes.RoutableAddresses
+	prefixIdx := make(map[string]int)
+	for _, route := range routes {
+		idx, ok := prefixIdx[route.Prefix]
+		if !ok {
+			prefixIdx[route.Prefix] = len(merged)
+			route.Addresses = append([]*url.URL{}, route.Addresses...)
+			merged = append(merged, route)
+			continue
+		}
+		merged[idx].Addresses = append(merged[idx].Addresses, route.Addresses...)
+	}
+	return merged
+}
+
+// backendPrefixes returns one backend prefix for each prefix of the routes.
+// Extra data of the first entry of a prefix is its target, extra data of the
+// other entries, from the extra pools of the app, are its extra targets.
+func backendPrefixes(routes []appTypes.RoutableAddresses) []router.BackendPrefix {
+	prefixes := []router.BackendPrefix{}
+	prefixIdx := make(map[string]int)
+	for _, route := range routes {
+		idx, ok := prefixIdx[route.Prefix]
+		if !ok {
+			prefixIdx[route.Prefix] = len(prefixes)
+			prefixes = append(prefixes, router.BackendPrefix{
+				Prefix: route.Prefix,
+				Target: route.ExtraData,
+				Weight: route.Weight,
+			})
+			continue
+		}
+		if route.ExtraData != nil {
+			prefixes[idx].ExtraTargets = append(prefixes[idx].ExtraTargets, route.ExtraData)
+		}
+	}
+	return prefixes
+}
+
 func RebuildRoutesInRouter(ctx context.Context, appRouter appTypes.AppRouter, o RebuildRoutesOpts) (*RebuildRoutesResult, error) {
 	log.Debugf("[rebuild-routes] rebuilding routes for app %q", o.App.GetName())
 	if o.Writer == nil {
@@ -107,7 +150,7 @@ func RebuildRoutesInRouter(ctx context.Context, appRouter appTypes.AppRouter, o
 		}
 		opts := router.EnsureBackendOpts{
 			Opts:        map[string]interface{}{},
-			Prefixes:    []router.BackendPrefix{},
+			Prefixes:    backendPrefixes(routes),
 			CNames:      o.App.GetCname(),
 			Healthcheck: hcData,
 
@@ -117,13 +160,9 @@ func RebuildRoutesInRouter(ctx context.Context, appRouter appTypes.AppRouter, o
 			opts.Opts[key] = opt
 		}
 		var resultRouterV2 RebuildRoutesResult
-		for _, route := range routes {
-			opts.Prefixes = append(opts.Prefixes, router.BackendPrefix{
-				Prefix: route.Prefix,
-				Target: route.ExtraData,
-			})
+		for _, prefix := range opts.Prefixes {
 			resultRouterV2.PrefixResults = append(resultRouterV2.PrefixResults, RebuildPrefixResult{
-				Prefix: route.Prefix,
+				Prefix: prefix.Prefix,
 			})
 		}
 		err = routerV2.EnsureBackend(ctx, o.App, opts)
@@ -222,6 +261,7 @@ func RebuildRoutesInRouter(ctx context.Context, appRouter appTypes.AppRouter, o
 	if err != nil {
 		return nil, err
 	}
+	newRoutes = mergeRoutableAddresses(newRoutes)
 	log.Debugf("[rebuild-routes] addresses for app %q: %+v", o.App.GetName(), newRoutes)
 
 	newPrefixMap := make(map[string]appTypes.RoutableAddresses)
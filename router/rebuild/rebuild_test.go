@@ -10,8 +10,10 @@ import (
 	"net/url"
 	"sort"
 
+	"github.com/tsuru/config"
 	"github.com/tsuru/tsuru/app"
 	"github.com/tsuru/tsuru/provision/provisiontest"
+	"github.com/tsuru/tsuru/router"
 	"github.com/tsuru/tsuru/router/rebuild"
 	"github.com/tsuru/tsuru/router/routertest"
 	"github.com/tsuru/tsuru/servicemanager"
@@ -482,3 +484,62 @@ func (s *S) TestRebuildRoutesMultiplePrefixes(c *check.C) {
 		},
 	})
 }
+
+func (s *S) TestRebuildRoutesMergesAddressesWithSamePrefix(c *check.C) {
+	a := app.App{Name: "my-test-app", TeamOwner: s.team.Name}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	provisiontest.ProvisionerInstance.MockRoutableAddresses(&a, []appTypes.RoutableAddresses{
+		{Addresses: []*url.URL{{Host: "u1", Scheme: "http"}}},
+		{Addresses: []*url.URL{{Host: "u2", Scheme: "http"}}},
+	})
+	changes, err := rebuild.RebuildRoutes(context.TODO(), rebuild.RebuildRoutesOpts{
+		App:  &a,
+		Wait: true,
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(changes, check.DeepEquals, map[string]rebuild.RebuildRoutesResult{
+		"fake": {
+			PrefixResults: []rebuild.RebuildPrefixResult{
+				{Added: []string{"http://u1", "http://u2"}},
+			},
+		},
+	})
+	c.Assert(routertest.FakeRouter.HasRoute(a.Name, "http://u1"), check.Equals, true)
+	c.Assert(routertest.FakeRouter.HasRoute(a.Name, "http://u2"), check.Equals, true)
+}
+
+func (s *S) TestRebuildRoutesRouterV2MergesTargetsWithSamePrefix(c *check.C) {
+	config.Set("routers:fake-v2:type", "fake-v2")
+	defer config.Unset("routers:fake-v2")
+	routertest.FakeRouterV2.Reset()
+	a := app.App{Name: "my-test-app", TeamOwner: s.team.Name}
+	a.Routers = []appTypes.AppRouter{{Name: "fake-v2"}}
+	err := app.CreateApp(context.TODO(), &a, s.user)
+	c.Assert(err, check.IsNil)
+	provisiontest.ProvisionerInstance.MockRoutableAddresses(&a, []appTypes.RoutableAddresses{
+		{Prefix: "web.process", ExtraData: map[string]string{"service": "my-test-app-web", "namespace": "pool1"}},
+		{Prefix: "web.process", ExtraData: map[string]string{"service": "my-test-app-web", "namespace": "pool2"}},
+		{Prefix: "web.process", ExtraData: map[string]string{"service": "my-test-app-web", "namespace": "pool3"}},
+	})
+	changes, err := rebuild.RebuildRoutes(context.TODO(), rebuild.RebuildRoutesOpts{
+		App:  &a,
+		Wait: true,
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(changes, check.DeepEquals, map[string]rebuild.RebuildRoutesResult{
+		"fake-v2": {
+			PrefixResults: []rebuild.RebuildPrefixResult{{Prefix: "web.process"}},
+		},
+	})
+	c.Assert(routertest.FakeRouterV2.BackendPrefixes(a.Name), check.DeepEquals, []router.BackendPrefix{
+		{
+			Prefix: "web.process",
+			Target: map[string]string{"service": "my-test-app-web", "namespace": "pool1"},
+			ExtraTargets: []map[string]string{
+				{"service": "my-test-app-web", "namespace": "pool2"},
+				{"service": "my-test-app-web", "namespace": "pool3"},
+			},
+		},
+	})
+}
@@ -13,6 +13,7 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/globalsign/mgo"
 	"github.com/globalsign/mgo/bson"
@@ -217,6 +218,26 @@ type PrefixRouter interface {
 	RemoveRoutesPrefix(ctx context.Context, app App, addresses appTypes.RoutableAddresses, sync bool) error
 }
 
+// ActivityRouter is a router able to tell when a backend last received a
+// request, used to find idle apps.
+type ActivityRouter interface {
+	LastActivity(ctx context.Context, app App) (time.Time, error)
+}
+
+// MaintenanceOpts configures the response sent by a router to requests
+// for an app in maintenance. An empty page means a 503 response.
+type MaintenanceOpts struct {
+	Page       string `json:"page,omitempty"`
+	RetryAfter int    `json:"retryAfter,omitempty"`
+}
+
+// MaintenanceRouter is a router able to answer the requests for a backend by
+// itself, without forwarding them to the app units.
+type MaintenanceRouter interface {
+	SetMaintenance(ctx context.Context, app App, opts MaintenanceOpts) error
+	UnsetMaintenance(ctx context.Context, app App) error
+}
+
 type BackendStatus string
 
 var (
@@ -358,31 +379,30 @@ func swapCnames(ctx context.Context, r Router, backend1, backend2 App) error {
 	if err != nil {
 		return err
 	}
-	swapCnameRouter, _ := r.(CNameMoveRouter)
 	for _, cname := range cnames1 {
-		if swapCnameRouter == nil {
-			err = cnameRouter.UnsetCName(ctx, cname.Host, backend1)
-			if err != nil {
-				return err
-			}
-			err = cnameRouter.SetCName(ctx, cname.Host, backend2)
-		} else {
-			err = swapCnameRouter.MoveCName(ctx, cname.Host, backend1, backend2)
-		}
+		err = moveCName(ctx, cnameRouter, cname.Host, backend1, backend2)
 		if err != nil {
 			return err
 		}
 	}
 	for _, cname := range cnames2 {
-		if swapCnameRouter == nil {
-			err = cnameRouter.UnsetCName(ctx, cname.Host, backend2)
-			if err != nil {
-				return err
-			}
-			err = cnameRouter.SetCName(ctx, cname.Host, backend1)
-		} else {
-			err = swapCnameRouter.MoveCName(ctx, cname.Host, backend2, backend1)
+		err = moveCName(ctx, cnameRouter, cname.Host, backend2, backend1)
+		if err != nil {
+			return err
 		}
+	}
+	return nil
+}
+
+// MoveCNames moves the given cnames from one backend to the other, it's a
+// noop for routers that don't support cnames.
+func MoveCNames(ctx context.Context, r Router, cnames []string, from, to App) error {
+	cnameRouter, ok := r.(CNameRouter)
+	if !ok {
+		return nil
+	}
+	for _, cname := range cnames {
+		err := moveCName(ctx, cnameRouter, cname, from, to)
 		if err != nil {
 			return err
 		}
@@ -390,6 +410,17 @@ func swapCnames(ctx context.Context, r Router, backend1, backend2 App) error {
 	return nil
 }
 
+func moveCName(ctx context.Context, r CNameRouter, cname string, from, to App) error {
+	if moveRouter, ok := r.(CNameMoveRouter); ok {
+		return moveRouter.MoveCName(ctx, cname, from, to)
+	}
+	err := r.UnsetCName(ctx, cname, from)
+	if err != nil {
+		return err
+	}
+	return r.SetCName(ctx, cname, to)
+}
+
 func swapBackends(ctx context.Context, r Router, backend1, backend2 App) error {
 	if _, isRouterV2 := r.(RouterV2); isRouterV2 {
 		return swapBackendName(backend1.GetName(), backend2.GetName())
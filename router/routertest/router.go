@@ -10,6 +10,7 @@ import (
 	"net/url"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/tsuru/tsuru/router"
@@ -38,6 +39,16 @@ var StatusRouter = statusRouter{
 	},
 }
 
+var ActivityRouter = activityRouter{
+	fakeRouter: newFakeRouter(),
+	Activity:   make(map[string]time.Time),
+}
+
+var MaintenanceRouter = maintenanceRouter{
+	fakeRouter:  newFakeRouter(),
+	Maintenance: make(map[string]router.MaintenanceOpts),
+}
+
 var TLSRouter = tlsRouter{
 	fakeRouter: newFakeRouter(),
 	Certs:      make(map[string]string),
@@ -51,6 +62,7 @@ var PrefixRouter = prefixRouter{
 
 var FakeRouterV2 = fakeRouterV2{
 	fakeRouter: newFakeRouter(),
+	prefixes:   make(map[string][]router.BackendPrefix),
 }
 
 var ErrForcedFailure = errors.New("Forced failure")
@@ -64,6 +76,8 @@ func init() {
 	router.Register("fake-info", createInfoRouter)
 	router.Register("fake-status", createStatusRouter)
 	router.Register("fake-prefix", createPrefixRouter)
+	router.Register("fake-activity", createActivityRouter)
+	router.Register("fake-maintenance", createMaintenanceRouter)
 }
 
 func createRouter(name string, config router.ConfigGetter) (router.Router, error) {
@@ -98,6 +112,14 @@ func createPrefixRouter(name string, config router.ConfigGetter) (router.Router,
 	return &PrefixRouter, nil
 }
 
+func createActivityRouter(name string, config router.ConfigGetter) (router.Router, error) {
+	return &ActivityRouter, nil
+}
+
+func createMaintenanceRouter(name string, config router.ConfigGetter) (router.Router, error) {
+	return &MaintenanceRouter, nil
+}
+
 func newFakeRouter() fakeRouter {
 	return fakeRouter{cnames: make(map[string]string), backends: make(map[string][]string), failuresByIp: make(map[string]bool), healthcheck: make(map[string]routerTypes.HealthcheckData), mutex: &sync.Mutex{}}
 }
@@ -416,6 +438,7 @@ func (r *fakeRouter) Swap(ctx context.Context, app1, app2 router.App, cnameOnly
 
 type fakeRouterV2 struct {
 	fakeRouter
+	prefixes map[string][]router.BackendPrefix
 }
 
 var (
@@ -431,10 +454,25 @@ func (r *fakeRouterV2) EnsureBackend(ctx context.Context, app router.App, opts r
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 	r.backends[name] = nil
+	r.prefixes[name] = opts.Prefixes
 
 	return nil
 }
 
+// BackendPrefixes returns the prefixes of the last ensured backend of the app.
+func (r *fakeRouterV2) BackendPrefixes(name string) []router.BackendPrefix {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.prefixes[name]
+}
+
+func (r *fakeRouterV2) Reset() {
+	r.fakeRouter.Reset()
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.prefixes = make(map[string][]router.BackendPrefix)
+}
+
 type hcRouter struct {
 	fakeRouter
 	err error
@@ -570,6 +608,66 @@ func (r *statusRouter) Reset() {
 	}
 }
 
+type activityRouter struct {
+	fakeRouter
+	Activity map[string]time.Time
+}
+
+var _ router.ActivityRouter = &activityRouter{}
+
+func (r *activityRouter) LastActivity(ctx context.Context, app router.App) (time.Time, error) {
+	backendName, err := router.Retrieve(app.GetName())
+	if err != nil {
+		return time.Time{}, err
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.Activity[backendName], nil
+}
+
+func (r *activityRouter) Reset() {
+	r.fakeRouter.Reset()
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.Activity = make(map[string]time.Time)
+}
+
+type maintenanceRouter struct {
+	fakeRouter
+	Maintenance map[string]router.MaintenanceOpts
+}
+
+var _ router.MaintenanceRouter = &maintenanceRouter{}
+
+func (r *maintenanceRouter) SetMaintenance(ctx context.Context, app router.App, opts router.MaintenanceOpts) error {
+	backendName, err := router.Retrieve(app.GetName())
+	if err != nil {
+		return err
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.Maintenance[backendName] = opts
+	return nil
+}
+
+func (r *maintenanceRouter) UnsetMaintenance(ctx context.Context, app router.App) error {
+	backendName, err := router.Retrieve(app.GetName())
+	if err != nil {
+		return err
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	delete(r.Maintenance, backendName)
+	return nil
+}
+
+func (r *maintenanceRouter) Reset() {
+	r.fakeRouter.Reset()
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.Maintenance = make(map[string]router.MaintenanceOpts)
+}
+
 type prefixRouter struct {
 	fakeRouter
 	prefixRoutes map[string][]appTypes.RoutableAddresses
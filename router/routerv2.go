@@ -16,6 +16,12 @@ import (
 type BackendPrefix struct {
 	Prefix string            `json:"prefix"`
 	Target map[string]string `json:"target"` // in kubernetes cluster be like {serviceName: "", namespace: ""}
+	// Weight is the percentage of the requests to the app default address
+	// that must be sent to this prefix target instead, used by canary deploys.
+	Weight int `json:"weight,omitempty"`
+	// ExtraTargets are the targets of the prefix in the other pools of apps
+	// running in more than one pool.
+	ExtraTargets []map[string]string `json:"extraTargets,omitempty"`
 }
 
 type EnsureBackendOpts struct {
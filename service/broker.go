@@ -9,6 +9,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/globalsign/mgo/bson"
 	uuid "github.com/nu7hatch/gouuid"
@@ -17,6 +18,7 @@ import (
 	"github.com/tsuru/tsuru/app/bind"
 	"github.com/tsuru/tsuru/db"
 	"github.com/tsuru/tsuru/event"
+	"github.com/tsuru/tsuru/hc"
 	"github.com/tsuru/tsuru/log"
 	"github.com/tsuru/tsuru/servicemanager"
 	serviceTypes "github.com/tsuru/tsuru/types/service"
@@ -32,6 +34,39 @@ var ClientFactory = func(config *osb.ClientConfiguration) (osb.Client, error) {
 	return osb.NewClient(config)
 }
 
+func init() {
+	hc.AddChecker("Service brokers", brokersHealthCheck)
+}
+
+// brokersHealthCheck fetches the catalog of each broker, bypassing the
+// catalog cache, to check whether they're reachable.
+func brokersHealthCheck(ctx context.Context) error {
+	if servicemanager.ServiceBroker == nil {
+		return hc.ErrDisabledComponent
+	}
+	brokers, err := servicemanager.ServiceBroker.List()
+	if err != nil {
+		return err
+	}
+	if len(brokers) == 0 {
+		return hc.ErrDisabledComponent
+	}
+	var errs []string
+	for _, b := range brokers {
+		client, err := newClient(b, "")
+		if err == nil {
+			_, err = client.client.GetCatalog()
+		}
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("broker %q: %v", b.Name, err))
+		}
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
+
 // brokerClient implements the Open Service Broker API for stored
 // Brokers
 type brokerClient struct {
@@ -3,11 +3,14 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"sort"
 
 	osb "github.com/pmorie/go-open-service-broker-client/v2"
 	osbfake "github.com/pmorie/go-open-service-broker-client/v2/fake"
+	"github.com/tsuru/tsuru/hc"
 	"github.com/tsuru/tsuru/provision/provisiontest"
+	"github.com/tsuru/tsuru/servicemanager"
 	serviceTypes "github.com/tsuru/tsuru/types/service"
 	check "gopkg.in/check.v1"
 )
@@ -445,6 +448,34 @@ func (s *S) TestBrokerClientInfo(c *check.C) {
 	})
 }
 
+func (s *S) TestBrokersHealthCheck(c *check.C) {
+	oldService := servicemanager.ServiceBroker
+	defer func() { servicemanager.ServiceBroker = oldService }()
+	servicemanager.ServiceBroker = &serviceTypes.MockServiceBrokerService{
+		OnList: func() ([]serviceTypes.Broker, error) {
+			return []serviceTypes.Broker{{Name: "broker1", URL: "http://broker1"}}, nil
+		},
+	}
+	ClientFactory = osbfake.NewFakeClientFunc(osbfake.FakeClientConfiguration{
+		CatalogReaction: &osbfake.CatalogReaction{Response: &osb.CatalogResponse{}},
+	})
+	err := brokersHealthCheck(context.TODO())
+	c.Assert(err, check.IsNil)
+	ClientFactory = osbfake.NewFakeClientFunc(osbfake.FakeClientConfiguration{
+		CatalogReaction: &osbfake.CatalogReaction{Error: errors.New("connection refused")},
+	})
+	err = brokersHealthCheck(context.TODO())
+	c.Assert(err, check.ErrorMatches, `broker "broker1": connection refused`)
+}
+
+func (s *S) TestBrokersHealthCheckNoBrokers(c *check.C) {
+	oldService := servicemanager.ServiceBroker
+	defer func() { servicemanager.ServiceBroker = oldService }()
+	servicemanager.ServiceBroker = &serviceTypes.MockServiceBrokerService{}
+	err := brokersHealthCheck(context.TODO())
+	c.Assert(err, check.Equals, hc.ErrDisabledComponent)
+}
+
 func createTestInstance() ServiceInstance {
 	return ServiceInstance{
 		Name:        "instance",
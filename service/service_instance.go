@@ -22,6 +22,7 @@ import (
 	tsuruErrors "github.com/tsuru/tsuru/errors"
 	"github.com/tsuru/tsuru/event"
 	"github.com/tsuru/tsuru/log"
+	"github.com/tsuru/tsuru/permission"
 	"github.com/tsuru/tsuru/servicemanager"
 	authTypes "github.com/tsuru/tsuru/types/auth"
 )
@@ -44,6 +45,11 @@ var (
 	instanceNameRegexp                          = regexp.MustCompile(`^[A-Za-z][-a-zA-Z0-9_]+$`)
 )
 
+func init() {
+	event.RegisterRedactor(permission.PermServiceInstanceCreate.FullName(), event.FormFieldsRedactor("parameters"))
+	event.RegisterRedactor(permission.PermServiceInstanceUpdate.FullName(), event.FormFieldsRedactor("parameters"))
+}
+
 type ServiceInstance struct {
 	Name        string                 `json:"name"`
 	Id          int                    `json:"id"`
@@ -483,6 +489,12 @@ func validateServiceInstanceTeamOwner(si ServiceInstance) error {
 	return err
 }
 
+// ValidateServiceInstance runs the validations of CreateServiceInstance,
+// including the check for instances with the same name. Nothing is created.
+func ValidateServiceInstance(ctx context.Context, instance ServiceInstance, service *Service) error {
+	return validateServiceInstance(ctx, instance, service)
+}
+
 func CreateServiceInstance(ctx context.Context, instance ServiceInstance, service *Service, evt *event.Event, requestID string) error {
 	err := validateServiceInstance(ctx, instance, service)
 	if err != nil {
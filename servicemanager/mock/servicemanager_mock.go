@@ -28,6 +28,8 @@ type MockService struct {
 	UserQuota                 *quota.MockQuotaService
 	AppQuota                  *quota.MockQuotaService
 	TeamQuota                 *quota.MockQuotaService
+	JobUnitsQuota             *quota.MockQuotaService
+	JobMemoryQuota            *quota.MockQuotaService
 	Cluster                   *provision.MockClusterService
 	ServiceBroker             *service.MockServiceBrokerService
 	ServiceBrokerCatalogCache *service.MockServiceBrokerCatalogCacheService
@@ -48,6 +50,8 @@ func SetMockService(m *MockService) {
 	m.UserQuota = &quota.MockQuotaService{}
 	m.AppQuota = &quota.MockQuotaService{}
 	m.TeamQuota = &quota.MockQuotaService{}
+	m.JobUnitsQuota = &quota.MockQuotaService{}
+	m.JobMemoryQuota = &quota.MockQuotaService{}
 	m.Cluster = &provision.MockClusterService{}
 	m.ServiceBroker = &service.MockServiceBrokerService{}
 	m.ServiceBrokerCatalogCache = &service.MockServiceBrokerCatalogCacheService{}
@@ -67,6 +71,8 @@ func SetMockService(m *MockService) {
 	servicemanager.UserQuota = m.UserQuota
 	servicemanager.AppQuota = m.AppQuota
 	servicemanager.TeamQuota = m.TeamQuota
+	servicemanager.JobUnitsQuota = m.JobUnitsQuota
+	servicemanager.JobMemoryQuota = m.JobMemoryQuota
 	servicemanager.Cluster = m.Cluster
 	servicemanager.ServiceBroker = m.ServiceBroker
 	servicemanager.ServiceBrokerCatalogCache = m.ServiceBrokerCatalogCache
@@ -114,6 +120,9 @@ func (m *MockService) ResetTeam() {
 	m.Team.OnList = nil
 	m.Team.OnRemove = nil
 	m.Team.OnFindByNames = nil
+	m.Team.OnFindAncestors = nil
+	m.Team.OnFindDescendants = nil
+	m.Team.OnSetParent = nil
 }
 
 func (m *MockService) ResetUserQuota() {
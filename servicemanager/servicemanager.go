@@ -7,9 +7,11 @@ package servicemanager
 import (
 	"github.com/tsuru/tsuru/types/app"
 	"github.com/tsuru/tsuru/types/app/image"
+	"github.com/tsuru/tsuru/types/audit"
 	"github.com/tsuru/tsuru/types/auth"
 	"github.com/tsuru/tsuru/types/cache"
 	"github.com/tsuru/tsuru/types/event"
+	"github.com/tsuru/tsuru/types/job"
 	"github.com/tsuru/tsuru/types/provision"
 	"github.com/tsuru/tsuru/types/quota"
 	"github.com/tsuru/tsuru/types/router"
@@ -26,10 +28,13 @@ var (
 	PlatformImage             image.PlatformImageService
 	Team                      auth.TeamService
 	TeamToken                 auth.TeamTokenService
+	ServiceAccount            auth.ServiceAccountService
 	Webhook                   event.WebhookService
 	AppQuota                  quota.QuotaService
 	UserQuota                 quota.QuotaService
 	TeamQuota                 quota.QuotaService
+	JobUnitsQuota             quota.QuotaService
+	JobMemoryQuota            quota.QuotaService
 	Cluster                   provision.ClusterService
 	ServiceBroker             service.ServiceBrokerService
 	ServiceBrokerCatalogCache service.ServiceBrokerCatalogCacheService
@@ -40,4 +45,6 @@ var (
 	AuthGroup                 auth.GroupService
 	Pool                      provision.PoolService
 	Volume                    volume.VolumeService
+	Job                       job.JobService
+	Audit                     audit.AuditService
 )
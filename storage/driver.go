@@ -11,9 +11,11 @@ import (
 	"github.com/tsuru/config"
 	"github.com/tsuru/tsuru/types/app"
 	"github.com/tsuru/tsuru/types/app/image"
+	"github.com/tsuru/tsuru/types/audit"
 	"github.com/tsuru/tsuru/types/auth"
 	"github.com/tsuru/tsuru/types/cache"
 	"github.com/tsuru/tsuru/types/event"
+	"github.com/tsuru/tsuru/types/job"
 	"github.com/tsuru/tsuru/types/provision"
 	"github.com/tsuru/tsuru/types/quota"
 	"github.com/tsuru/tsuru/types/router"
@@ -28,10 +30,14 @@ type DbDriver struct {
 	PlanStorage                      app.PlanStorage
 	AppCacheStorage                  cache.CacheStorage
 	TeamTokenStorage                 auth.TeamTokenStorage
+	ServiceAccountStorage            auth.ServiceAccountStorage
 	UserQuotaStorage                 quota.QuotaStorage
 	AppQuotaStorage                  quota.QuotaStorage
 	TeamQuotaStorage                 quota.QuotaStorage
+	TeamJobUnitsQuotaStorage         quota.QuotaStorage
+	TeamJobMemoryQuotaStorage        quota.QuotaStorage
 	WebhookStorage                   event.WebhookStorage
+	EventStorage                     event.EventStorage
 	ClusterStorage                   provision.ClusterStorage
 	ServiceBrokerStorage             service.ServiceBrokerStorage
 	ServiceBrokerCatalogCacheStorage cache.CacheStorage
@@ -42,6 +48,8 @@ type DbDriver struct {
 	AuthGroupStorage                 auth.GroupStorage
 	PoolStorage                      provision.PoolStorage
 	VolumeStorage                    volume.VolumeStorage
+	JobStorage                       job.JobStorage
+	AuditStorage                     audit.AuditStorage
 }
 
 var (
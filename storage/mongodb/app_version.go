@@ -374,6 +374,32 @@ func (s *appVersionStorage) MarkVersionsToRemoval(ctx context.Context, appName s
 	return s.baseUpdateWhere(ctx, where, update)
 }
 
+// RenameApp moves the versions of an app to a new app name. Versions left
+// behind by a removed app with the new name are discarded.
+func (s *appVersionStorage) RenameApp(ctx context.Context, oldName, newName string) error {
+	span := newMongoDBSpan(ctx, mongoSpanUpdate, appVersionsCollectionName)
+	span.SetQueryStatement(bson.M{"appname": oldName})
+	defer span.Finish()
+
+	coll, err := s.collection()
+	if err != nil {
+		span.SetError(err)
+		return err
+	}
+	defer coll.Close()
+	err = coll.Remove(bson.M{"appname": newName})
+	if err != nil && err != mgo.ErrNotFound {
+		span.SetError(err)
+		return err
+	}
+	err = coll.Update(bson.M{"appname": oldName}, bson.M{"$set": bson.M{"appname": newName}})
+	if err == mgo.ErrNotFound {
+		return nil
+	}
+	span.SetError(err)
+	return err
+}
+
 func (s *appVersionStorage) importLegacyVersions(app appTypes.App) error {
 	imgData, err := s.legacyImagesData(app.GetName())
 	if err != nil {
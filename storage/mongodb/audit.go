@@ -0,0 +1,125 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	"github.com/pkg/errors"
+	"github.com/tsuru/tsuru/db"
+	dbStorage "github.com/tsuru/tsuru/db/storage"
+	auditTypes "github.com/tsuru/tsuru/types/audit"
+)
+
+const auditCollectionName = "api_audit"
+
+var _ auditTypes.AuditStorage = &auditStorage{}
+
+type auditStorage struct{}
+
+func (s *auditStorage) coll(conn *db.Storage) *dbStorage.Collection {
+	c := conn.Collection(auditCollectionName)
+	c.EnsureIndex(mgo.Index{Key: []string{"-time"}, Background: true})
+	c.EnsureIndex(mgo.Index{Key: []string{"owner", "-time"}, Background: true})
+	return c
+}
+
+func (s *auditStorage) Insert(ctx context.Context, entry auditTypes.Entry) error {
+	span := newMongoDBSpan(ctx, mongoSpanInsert, auditCollectionName)
+	defer span.Finish()
+
+	conn, err := db.Conn()
+	if err != nil {
+		span.SetError(err)
+		return err
+	}
+	defer conn.Close()
+	err = s.coll(conn).Insert(entry)
+	span.SetError(err)
+	return errors.WithStack(err)
+}
+
+func (s *auditStorage) List(ctx context.Context, f *auditTypes.Filter) ([]auditTypes.Entry, error) {
+	span := newMongoDBSpan(ctx, mongoSpanFind, auditCollectionName)
+	defer span.Finish()
+
+	conn, err := db.Conn()
+	if err != nil {
+		span.SetError(err)
+		return nil, err
+	}
+	defer conn.Close()
+	if f == nil {
+		f = &auditTypes.Filter{}
+	}
+	query := bson.M{}
+	if f.Owner != "" {
+		query["owner"] = f.Owner
+	}
+	if f.TokenID != "" {
+		query["tokenid"] = f.TokenID
+	}
+	if f.Impersonator != "" {
+		query["impersonator"] = f.Impersonator
+	}
+	if f.Method != "" {
+		query["method"] = f.Method
+	}
+	if f.Path != "" {
+		query["path"] = f.Path
+	}
+	if f.Status != 0 {
+		query["status"] = f.Status
+	}
+	timeQuery := bson.M{}
+	if !f.Since.IsZero() {
+		timeQuery["$gte"] = f.Since
+	}
+	if !f.Until.IsZero() {
+		timeQuery["$lte"] = f.Until
+	}
+	if len(timeQuery) > 0 {
+		query["time"] = timeQuery
+	}
+	span.SetQueryStatement(query)
+	q := s.coll(conn).Find(query).Sort("-time")
+	if f.Skip > 0 {
+		q = q.Skip(f.Skip)
+	}
+	if f.Limit > 0 {
+		q = q.Limit(f.Limit)
+	}
+	var entries []auditTypes.Entry
+	err = q.All(&entries)
+	if err != nil {
+		span.SetError(err)
+		return nil, errors.WithStack(err)
+	}
+	return entries, nil
+}
+
+func (s *auditStorage) RemoveBefore(ctx context.Context, t time.Time) (int, error) {
+	query := bson.M{"time": bson.M{"$lt": t}}
+
+	span := newMongoDBSpan(ctx, mongoSpanDelete, auditCollectionName)
+	span.SetQueryStatement(query)
+	defer span.Finish()
+
+	conn, err := db.Conn()
+	if err != nil {
+		span.SetError(err)
+		return 0, err
+	}
+	defer conn.Close()
+	info, err := s.coll(conn).RemoveAll(query)
+	if err != nil {
+		span.SetError(err)
+		return 0, errors.WithStack(err)
+	}
+	return info.Removed, nil
+}
@@ -23,18 +23,20 @@ type clusterStorage struct{}
 var _ provision.ClusterStorage = &clusterStorage{}
 
 type cluster struct {
-	Name        string `bson:"_id"`
-	Addresses   []string
-	Provisioner string
-	CaCert      []byte            `bson:",omitempty"`
-	ClientCert  []byte            `bson:",omitempty"`
-	ClientKey   []byte            `bson:",omitempty"`
-	Pools       []string          `bson:",omitempty"`
-	CustomData  map[string]string `bson:",omitempty"`
-	Local       bool              `bson:",omitempty"`
-	Default     bool
-	KubeConfig  *provision.KubeConfig `bson:",omitempty"`
-	HTTPProxy   string                `json:"httpProxy,omitempty"`
+	Name         string `bson:"_id"`
+	Addresses    []string
+	Provisioner  string
+	CaCert       []byte            `bson:",omitempty"`
+	ClientCert   []byte            `bson:",omitempty"`
+	ClientKey    []byte            `bson:",omitempty"`
+	Pools        []string          `bson:",omitempty"`
+	CustomData   map[string]string `bson:",omitempty"`
+	Local        bool              `bson:",omitempty"`
+	Default      bool
+	KubeConfig   *provision.KubeConfig `bson:",omitempty"`
+	HTTPProxy    string                `json:"httpProxy,omitempty"`
+	StandbyPools []string              `bson:",omitempty"`
+	Unavailable  bool                  `bson:",omitempty"`
 
 	Writer io.Writer `bson:"-"`
 }
@@ -0,0 +1,526 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mongodb
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tsuru/tsuru/db"
+	"github.com/tsuru/tsuru/types/event"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+)
+
+type eventStorage struct{}
+
+var _ event.EventStorage = &eventStorage{}
+
+func (s *eventStorage) Insert(ctx context.Context, evt *event.EventData) error {
+	conn, err := db.Conn()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	err = conn.Events().Insert(evt)
+	if mgo.IsDup(err) {
+		return event.ErrEventIDInUse
+	}
+	return err
+}
+
+func (s *eventStorage) Update(ctx context.Context, evt *event.EventData) error {
+	conn, err := db.Conn()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	err = conn.Events().UpdateId(evt.ID, evt)
+	if err == mgo.ErrNotFound {
+		return event.ErrEventNotFound
+	}
+	return err
+}
+
+func (s *eventStorage) Remove(ctx context.Context, id event.EventID) error {
+	conn, err := db.Conn()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	err = conn.Events().RemoveId(id)
+	if err == mgo.ErrNotFound {
+		return event.ErrEventNotFound
+	}
+	return err
+}
+
+func (s *eventStorage) findOne(query bson.M) (*event.EventData, error) {
+	conn, err := db.Conn()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	var evt event.EventData
+	err = conn.Events().Find(query).One(&evt)
+	if err == mgo.ErrNotFound {
+		return nil, event.ErrEventNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &evt, nil
+}
+
+func (s *eventStorage) findAll(query bson.M) ([]event.EventData, error) {
+	conn, err := db.Conn()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	var evts []event.EventData
+	err = conn.Events().Find(query).All(&evts)
+	if err != nil {
+		return nil, err
+	}
+	return evts, nil
+}
+
+func (s *eventStorage) FindByID(ctx context.Context, id event.EventID) (*event.EventData, error) {
+	return s.findOne(bson.M{"_id": id})
+}
+
+func (s *eventStorage) FindByUniqueID(ctx context.Context, id bson.ObjectId) (*event.EventData, error) {
+	return s.findOne(bson.M{"uniqueid": id})
+}
+
+func (s *eventStorage) FindRunning(ctx context.Context, target event.Target, kindName string) (*event.EventData, error) {
+	return s.findOne(bson.M{
+		"_id":       event.EventID{Target: target},
+		"kind.name": kindName,
+		"running":   true,
+	})
+}
+
+func (s *eventStorage) FindLocking(ctx context.Context, targets []event.Target, ignoredID bson.ObjectId) (*event.EventData, error) {
+	var orBlock []bson.M
+	for _, t := range targets {
+		tBson, _ := t.GetBSON()
+		orBlock = append(orBlock, bson.M{"_id": tBson}, bson.M{
+			"extratargets": bson.M{"$elemMatch": bson.M{"target": tBson, "lock": true}},
+		})
+	}
+	return s.findOne(bson.M{
+		"running":  true,
+		"uniqueid": bson.M{"$ne": ignoredID},
+		"$or":      orBlock,
+	})
+}
+
+func (s *eventStorage) FindRunningByIDs(ctx context.Context, ids []event.EventID) ([]event.EventData, error) {
+	rawIDs := make([]interface{}, len(ids))
+	for i, id := range ids {
+		rawIDs[i], _ = id.GetBSON()
+	}
+	return s.findAll(bson.M{"_id": bson.M{"$in": rawIDs}, "running": true})
+}
+
+func (s *eventStorage) FindRunningChildren(ctx context.Context, parentID bson.ObjectId) ([]event.EventData, error) {
+	return s.findAll(bson.M{"parentid": parentID, "running": true, "cancelable": true})
+}
+
+func (s *eventStorage) FindExpired(ctx context.Context, lockUpdatedBefore time.Time) ([]event.EventData, error) {
+	return s.findAll(bson.M{
+		"running":        true,
+		"lockupdatetime": bson.M{"$lt": lockUpdatedBefore},
+	})
+}
+
+func (s *eventStorage) FindAll(ctx context.Context, filter *event.EventFilter) ([]event.EventData, error) {
+	query := bson.M{}
+	limit := 0
+	skip := 0
+	sort := []string{"-starttime", "-uniqueid"}
+	if filter != nil {
+		var ok bool
+		query, ok = eventFilterQuery(filter)
+		if !ok {
+			return nil, nil
+		}
+		limit = filter.Limit
+		skip = filter.Skip
+		if filter.Sort != "" {
+			sort = []string{filter.Sort}
+		}
+	}
+	conn, err := db.Conn()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	find := conn.Events().Find(query).Sort(sort...)
+	if limit > 0 {
+		find = find.Limit(limit)
+	}
+	if skip > 0 {
+		find = find.Skip(skip)
+	}
+	var evts []event.EventData
+	err = find.All(&evts)
+	if err != nil {
+		return nil, err
+	}
+	return evts, nil
+}
+
+// eventFilterQuery returns the query matching the events selected by the
+// filter. The returned bool is false when no event may match the filter.
+func eventFilterQuery(f *event.EventFilter) (bson.M, bool) {
+	query := bson.M{}
+	andBlock := []bson.M{}
+	if f.Permissions != nil {
+		var permOrBlock []bson.M
+		for _, perm := range f.Permissions {
+			toAppend := bson.M{
+				"allowed.scheme": bson.M{"$regex": "^" + strings.Replace(perm.Scheme, ".", `\.`, -1)},
+			}
+			if len(perm.Contexts) > 0 {
+				ctxsBson := []bson.D{}
+				for _, ctx := range perm.Contexts {
+					ctxsBson = append(ctxsBson, bson.D{
+						{Name: "ctxtype", Value: ctx.CtxType},
+						{Name: "value", Value: ctx.Value},
+					})
+				}
+				toAppend["allowed.contexts"] = bson.M{"$in": ctxsBson}
+			}
+			permOrBlock = append(permOrBlock, toAppend)
+		}
+		andBlock = append(andBlock, bson.M{"$or": permOrBlock})
+	}
+	if f.AllowedTargets != nil {
+		var orBlock []bson.M
+		for _, at := range f.AllowedTargets {
+			f := bson.M{"target.type": at.Type}
+			extraF := bson.M{"extratargets.target.type": at.Type}
+			if at.Values != nil {
+				f["target.value"] = bson.M{"$in": at.Values}
+				extraF["extratargets.target.value"] = bson.M{"$in": at.Values}
+			}
+			orBlock = append(orBlock, f, extraF)
+		}
+		if len(orBlock) == 0 {
+			return nil, false
+		}
+		andBlock = append(andBlock, bson.M{"$or": orBlock})
+	}
+	if f.Target.Type != "" {
+		andBlock = append(andBlock, bson.M{"$or": []bson.M{
+			{"target.type": f.Target.Type},
+			{"extratargets.target.type": f.Target.Type},
+		}})
+	}
+	if f.Target.Value != "" {
+		andBlock = append(andBlock, bson.M{"$or": []bson.M{
+			{"target.value": f.Target.Value},
+			{"extratargets.target.value": f.Target.Value},
+		}})
+	}
+	if f.KindType != "" {
+		query["kind.type"] = f.KindType
+	}
+	if len(f.KindNames) > 0 {
+		query["kind.name"] = bson.M{"$in": f.KindNames}
+	}
+	if f.OwnerType != "" {
+		query["owner.type"] = f.OwnerType
+	}
+	if f.OwnerName != "" {
+		query["owner.name"] = f.OwnerName
+	}
+	if f.Impersonator != "" {
+		query["impersonator"] = f.Impersonator
+	}
+	if f.UniqueIDs != nil {
+		query["uniqueid"] = bson.M{"$in": f.UniqueIDs}
+	}
+	if !f.Since.IsZero() {
+		andBlock = append(andBlock, bson.M{"starttime": bson.M{"$gte": f.Since}})
+	}
+	if !f.Until.IsZero() {
+		andBlock = append(andBlock, bson.M{"starttime": bson.M{"$lte": f.Until}})
+	}
+	if f.After != nil {
+		andBlock = append(andBlock, bson.M{"$or": []bson.M{
+			{"starttime": bson.M{"$lt": f.After.StartTime}},
+			{"starttime": f.After.StartTime, "uniqueid": bson.M{"$lt": f.After.UniqueID}},
+		}})
+	}
+	if len(andBlock) > 0 {
+		query["$and"] = andBlock
+	}
+	if f.Running != nil {
+		query["running"] = *f.Running
+	}
+	if f.Cancelable != nil {
+		query["cancelable"] = *f.Cancelable
+	}
+	if f.ErrorOnly {
+		query["error"] = bson.M{"$ne": ""}
+	}
+	if f.Search != "" {
+		query["$text"] = bson.M{"$search": f.Search}
+	}
+	return query, true
+}
+
+func (s *eventStorage) CountThrottled(ctx context.Context, f event.ThrottlingFilter) (int, error) {
+	query := bson.M{
+		"target.type": f.TargetType,
+	}
+	startTimeQuery := bson.M{"$gt": f.Since}
+	if !f.RunningSince.IsZero() {
+		query["$or"] = []bson.M{
+			{"starttime": startTimeQuery},
+			{
+				"running":        true,
+				"lockupdatetime": bson.M{"$gt": f.RunningSince},
+			},
+		}
+	} else {
+		query["starttime"] = startTimeQuery
+	}
+	if f.TargetValue != "" {
+		query["target.value"] = f.TargetValue
+	}
+	if f.KindName != "" {
+		query["kind.name"] = f.KindName
+	}
+	if f.Owner != nil {
+		query["owner.type"] = f.Owner.Type
+		query["owner.name"] = f.Owner.Name
+	}
+	conn, err := db.Conn()
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+	return conn.Events().Find(query).Count()
+}
+
+func (s *eventStorage) Kinds(ctx context.Context) ([]event.Kind, error) {
+	conn, err := db.Conn()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	var kinds []event.Kind
+	err = conn.Events().Find(nil).Distinct("kind", &kinds)
+	if err != nil {
+		return nil, err
+	}
+	return kinds, nil
+}
+
+func (s *eventStorage) Summary(ctx context.Context, filter *event.EventFilter, groupBy string) ([]event.SummaryEntry, error) {
+	var groupField string
+	switch groupBy {
+	case event.SummaryGroupByKind:
+		groupField = "$kind.name"
+	case event.SummaryGroupByTargetType:
+		groupField = "$target.type"
+	case event.SummaryGroupByTeam:
+		groupField = "$allowed.contexts.value"
+	default:
+		return nil, event.ErrInvalidGroupBy
+	}
+	query := bson.M{}
+	if filter != nil {
+		var ok bool
+		query, ok = eventFilterQuery(filter)
+		if !ok {
+			return nil, nil
+		}
+	}
+	pipeline := []bson.M{{"$match": query}}
+	if groupBy == event.SummaryGroupByTeam {
+		pipeline = append(pipeline,
+			bson.M{"$unwind": "$allowed.contexts"},
+			bson.M{"$match": bson.M{"allowed.contexts.ctxtype": permTypes.CtxTeam}},
+		)
+	}
+	pipeline = append(pipeline,
+		bson.M{"$group": bson.M{
+			"_id":   groupField,
+			"count": bson.M{"$sum": 1},
+			"failures": bson.M{"$sum": bson.M{"$cond": []interface{}{
+				bson.M{"$and": []bson.M{
+					{"$gt": []interface{}{"$error", ""}},
+					{"$ne": []interface{}{"$running", true}},
+				}},
+				1,
+				0,
+			}}},
+		}},
+		bson.M{"$sort": bson.D{{Name: "count", Value: -1}, {Name: "_id", Value: 1}}},
+	)
+	conn, err := db.Conn()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	var entries []event.SummaryEntry
+	err = conn.Events().Pipe(pipeline).All(&entries)
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (s *eventStorage) UpdateLockTime(ctx context.Context, ids []event.EventID, now time.Time) error {
+	rawIDs := make([]interface{}, len(ids))
+	for i, id := range ids {
+		rawIDs[i], _ = id.GetBSON()
+	}
+	conn, err := db.Conn()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	_, err = conn.Events().UpdateAll(bson.M{"_id": bson.M{"$in": rawIDs}}, bson.M{"$set": bson.M{"lockupdatetime": now}})
+	if err == mgo.ErrNotFound {
+		return nil
+	}
+	return err
+}
+
+func (s *eventStorage) set(id event.EventID, fields bson.M) error {
+	conn, err := db.Conn()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	err = conn.Events().UpdateId(id, bson.M{"$set": fields})
+	if err == mgo.ErrNotFound {
+		return event.ErrEventNotFound
+	}
+	return err
+}
+
+func (s *eventStorage) SetOtherCustomData(ctx context.Context, id event.EventID, data interface{}) error {
+	return s.set(id, bson.M{"othercustomdata": data})
+}
+
+func (s *eventStorage) SetChanges(ctx context.Context, id event.EventID, changes event.Changes) error {
+	return s.set(id, bson.M{"changes": changes})
+}
+
+func (s *eventStorage) SetStructuredLog(ctx context.Context, id event.EventID, entries []event.LogEntry) error {
+	conn, err := db.Conn()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	err = conn.Events().Update(bson.M{"_id": id, "running": true}, bson.M{"$set": bson.M{"structuredlog": entries}})
+	if err == mgo.ErrNotFound {
+		return nil
+	}
+	return err
+}
+
+func (s *eventStorage) apply(query bson.M, update bson.M) (*event.EventData, error) {
+	conn, err := db.Conn()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	var evt event.EventData
+	_, err = conn.Events().Find(query).Apply(mgo.Change{
+		Update:    update,
+		ReturnNew: true,
+	}, &evt)
+	if err == mgo.ErrNotFound {
+		return nil, event.ErrEventNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &evt, nil
+}
+
+func (s *eventStorage) RequestCancel(ctx context.Context, id event.EventID, info event.CancelInfo) (*event.EventData, error) {
+	return s.apply(
+		bson.M{"_id": id, "cancelinfo.asked": false},
+		bson.M{"$set": bson.M{"cancelinfo": info}},
+	)
+}
+
+func (s *eventStorage) AckCancel(ctx context.Context, id event.EventID, ackTime time.Time) (*event.EventData, error) {
+	return s.apply(
+		bson.M{"_id": id, "cancelinfo.asked": true},
+		bson.M{"$set": bson.M{
+			"cancelinfo.acktime":  ackTime,
+			"cancelinfo.canceled": true,
+		}},
+	)
+}
+
+func (s *eventStorage) ReassignOwner(ctx context.Context, from, to event.Owner) (int, error) {
+	conn, err := db.Conn()
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+	info, err := conn.Events().UpdateAll(bson.M{
+		"owner.type": from.Type,
+		"owner.name": from.Name,
+	}, bson.M{"$set": bson.M{"owner": to}})
+	if err != nil {
+		return 0, err
+	}
+	return info.Updated, nil
+}
+
+func (s *eventStorage) RenameTarget(ctx context.Context, from, to event.Target) error {
+	conn, err := db.Conn()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	coll := conn.Events()
+	_, err = coll.UpdateAll(bson.M{
+		"target":  from,
+		"running": false,
+	}, bson.M{"$set": bson.M{"target": to}})
+	if err != nil {
+		return err
+	}
+	_, err = coll.UpdateAll(bson.M{
+		"extratargets.target": from,
+		"running":             false,
+	}, bson.M{"$set": bson.M{"extratargets.$.target": to}})
+	return err
+}
+
+func (s *eventStorage) RenameAllowedContext(ctx context.Context, from, to permTypes.PermissionContext) error {
+	conn, err := db.Conn()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	coll := conn.Events()
+	for _, field := range []string{"allowed", "allowedcancel"} {
+		_, err = coll.UpdateAll(bson.M{
+			field + ".contexts": bson.M{"$elemMatch": bson.M{"ctxtype": from.CtxType, "value": from.Value}},
+			"running":           false,
+		}, bson.M{"$set": bson.M{field + ".contexts.$.value": to.Value}})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mongodb
+
+import (
+	"context"
+
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	"github.com/pkg/errors"
+	"github.com/tsuru/tsuru/db"
+	dbStorage "github.com/tsuru/tsuru/db/storage"
+	jobTypes "github.com/tsuru/tsuru/types/job"
+)
+
+const jobCollectionName = "jobs"
+
+var _ jobTypes.JobStorage = &jobStorage{}
+
+type jobStorage struct{}
+
+func (s *jobStorage) coll(conn *db.Storage) *dbStorage.Collection {
+	return conn.Collection(jobCollectionName)
+}
+
+func (s *jobStorage) Insert(ctx context.Context, job jobTypes.Job) error {
+	span := newMongoDBSpan(ctx, mongoSpanInsert, jobCollectionName)
+	span.SetMongoID(job.Name)
+	defer span.Finish()
+
+	conn, err := db.Conn()
+	if err != nil {
+		span.SetError(err)
+		return err
+	}
+	defer conn.Close()
+	err = s.coll(conn).Insert(job)
+	if mgo.IsDup(err) {
+		return jobTypes.ErrJobAlreadyExists
+	}
+	span.SetError(err)
+	return errors.WithStack(err)
+}
+
+func (s *jobStorage) Get(ctx context.Context, name string) (*jobTypes.Job, error) {
+	span := newMongoDBSpan(ctx, mongoSpanFindID, jobCollectionName)
+	span.SetMongoID(name)
+	defer span.Finish()
+
+	conn, err := db.Conn()
+	if err != nil {
+		span.SetError(err)
+		return nil, err
+	}
+	defer conn.Close()
+	var job jobTypes.Job
+	err = s.coll(conn).FindId(name).One(&job)
+	if err == mgo.ErrNotFound {
+		return nil, jobTypes.ErrJobNotFound
+	}
+	if err != nil {
+		span.SetError(err)
+		return nil, errors.WithStack(err)
+	}
+	return &job, nil
+}
+
+func (s *jobStorage) List(ctx context.Context, f *jobTypes.Filter) ([]jobTypes.Job, error) {
+	span := newMongoDBSpan(ctx, mongoSpanFind, jobCollectionName)
+	defer span.Finish()
+
+	conn, err := db.Conn()
+	if err != nil {
+		span.SetError(err)
+		return nil, err
+	}
+	defer conn.Close()
+	query := bson.M{}
+	if f != nil {
+		query["$or"] = []bson.M{
+			{"_id": bson.M{"$in": f.Names}},
+			{"pool": bson.M{"$in": f.Pools}},
+			{"teamowner": bson.M{"$in": f.Teams}},
+		}
+	}
+	span.SetQueryStatement(query)
+	var jobs []jobTypes.Job
+	err = s.coll(conn).Find(query).Sort("_id").All(&jobs)
+	if err != nil {
+		span.SetError(err)
+		return nil, errors.WithStack(err)
+	}
+	return jobs, nil
+}
+
+func (s *jobStorage) Remove(ctx context.Context, name string) error {
+	span := newMongoDBSpan(ctx, mongoSpanDeleteID, jobCollectionName)
+	span.SetMongoID(name)
+	defer span.Finish()
+
+	conn, err := db.Conn()
+	if err != nil {
+		span.SetError(err)
+		return err
+	}
+	defer conn.Close()
+	err = s.coll(conn).RemoveId(name)
+	if err == mgo.ErrNotFound {
+		return jobTypes.ErrJobNotFound
+	}
+	span.SetError(err)
+	return errors.WithStack(err)
+}
@@ -16,10 +16,14 @@ func init() {
 		PlanStorage:                      &PlanStorage{},
 		AppCacheStorage:                  appCacheStorage(),
 		TeamTokenStorage:                 &teamTokenStorage{},
+		ServiceAccountStorage:            &serviceAccountStorage{},
 		UserQuotaStorage:                 authQuotaStorage(),
 		AppQuotaStorage:                  appQuotaStorage(),
 		TeamQuotaStorage:                 teamQuotaStorage(),
+		TeamJobUnitsQuotaStorage:         teamJobUnitsQuotaStorage(),
+		TeamJobMemoryQuotaStorage:        teamJobMemoryQuotaStorage(),
 		WebhookStorage:                   &webhookStorage{},
+		EventStorage:                     &eventStorage{},
 		ClusterStorage:                   &clusterStorage{},
 		ServiceBrokerStorage:             &serviceBrokerStorage{},
 		ServiceBrokerCatalogCacheStorage: serviceBrokerCatalogCacheStorage(),
@@ -29,6 +33,8 @@ func init() {
 		AuthGroupStorage:                 &authGroupStorage{},
 		PoolStorage:                      &PoolStorage{},
 		VolumeStorage:                    &volumeStorage{},
+		JobStorage:                       &jobStorage{},
+		AuditStorage:                     &auditStorage{},
 	}
 	storage.RegisterDbDriver("mongodb", mongodbDriver)
 }
@@ -28,6 +28,10 @@ type plan struct {
 	CPUMilli int
 	Default  bool
 	Override app.PlanOverride `bson:"-"`
+	MinUnits uint
+	MaxUnits uint
+	GPU      int
+	GPUType  string
 }
 
 func plansCollection(conn *db.Storage) *dbStorage.Collection {
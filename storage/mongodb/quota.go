@@ -18,10 +18,16 @@ var _ quota.QuotaStorage = &quotaStorage{}
 type quotaStorage struct {
 	collection string
 	query      func(string) bson.M
+	// field is the document field holding the quota, "quota" is used when
+	// it's empty.
+	field string
 }
 
-type quotaObject struct {
-	Quota quota.Quota
+func (s *quotaStorage) quotaField() string {
+	if s.field == "" {
+		return "quota"
+	}
+	return s.field
 }
 
 func (s *quotaStorage) SetLimit(ctx context.Context, name string, limit int) error {
@@ -44,7 +50,7 @@ func (s *quotaStorage) SetLimit(ctx context.Context, name string, limit int) err
 
 	err = conn.Collection(s.collection).Update(
 		query,
-		bson.M{"$set": bson.M{"quota.limit": limit}},
+		bson.M{"$set": bson.M{s.quotaField() + ".limit": limit}},
 	)
 	span.SetError(err)
 	return err
@@ -69,7 +75,7 @@ func (s *quotaStorage) Set(ctx context.Context, name string, inUse int) error {
 
 	err = conn.Collection(s.collection).Update(
 		query,
-		bson.M{"$set": bson.M{"quota.inuse": inUse}},
+		bson.M{"$set": bson.M{s.quotaField() + ".inuse": inUse}},
 	)
 	return err
 }
@@ -80,14 +86,15 @@ func (s *quotaStorage) Get(ctx context.Context, name string) (*quota.Quota, erro
 	span.SetQueryStatement(query)
 	defer span.Finish()
 
-	var obj quotaObject
+	var obj map[string]bson.Raw
 	conn, err := db.Conn()
 	if err != nil {
 		span.SetError(err)
 		return nil, err
 	}
 	defer conn.Close()
-	err = conn.Collection(s.collection).Find(query).One(&obj)
+	field := s.quotaField()
+	err = conn.Collection(s.collection).Find(query).Select(bson.M{field: 1}).One(&obj)
 	if err != nil {
 		if err == mgo.ErrNotFound {
 			return nil, quota.ErrQuotaNotFound
@@ -95,5 +102,14 @@ func (s *quotaStorage) Get(ctx context.Context, name string) (*quota.Quota, erro
 		span.SetError(err)
 		return nil, err
 	}
-	return &obj.Quota, nil
+	q := quota.UnlimitedQuota
+	if raw, ok := obj[field]; ok {
+		q = quota.Quota{}
+		err = raw.Unmarshal(&q)
+		if err != nil {
+			span.SetError(err)
+			return nil, err
+		}
+	}
+	return &q, nil
 }
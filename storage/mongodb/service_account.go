@@ -0,0 +1,141 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/globalsign/mgo"
+	"github.com/globalsign/mgo/bson"
+	"github.com/tsuru/tsuru/db"
+	dbStorage "github.com/tsuru/tsuru/db/storage"
+	"github.com/tsuru/tsuru/types/auth"
+)
+
+const serviceAccountsCollectionName = "service_accounts"
+
+type serviceAccountStorage struct{}
+
+type serviceAccount struct {
+	Name         string `bson:"_id"`
+	Team         string
+	Description  string
+	CreatedAt    time.Time           `bson:"created_at"`
+	CreatorEmail string              `bson:"creator_email"`
+	Roles        []auth.RoleInstance `bson:",omitempty"`
+}
+
+var _ auth.ServiceAccountStorage = &serviceAccountStorage{}
+
+func serviceAccountsCollection(conn *db.Storage) *dbStorage.Collection {
+	c := conn.Collection(serviceAccountsCollectionName)
+	c.EnsureIndex(mgo.Index{Key: []string{"team"}})
+	return c
+}
+
+func (s *serviceAccountStorage) Insert(ctx context.Context, sa auth.ServiceAccount) error {
+	span := newMongoDBSpan(ctx, mongoSpanInsert, serviceAccountsCollectionName)
+	defer span.Finish()
+
+	conn, err := db.Conn()
+	if err != nil {
+		span.SetError(err)
+		return err
+	}
+	defer conn.Close()
+	err = serviceAccountsCollection(conn).Insert(serviceAccount(sa))
+	if mgo.IsDup(err) {
+		err = auth.ErrServiceAccountAlreadyExists
+	}
+	span.SetError(err)
+	return err
+}
+
+func (s *serviceAccountStorage) FindByName(ctx context.Context, name string) (*auth.ServiceAccount, error) {
+	results, err := s.findByQuery(ctx, bson.M{"_id": name})
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, auth.ErrServiceAccountNotFound
+	}
+	return &results[0], nil
+}
+
+func (s *serviceAccountStorage) FindByTeams(ctx context.Context, teams []string) ([]auth.ServiceAccount, error) {
+	query := bson.M{}
+	if teams != nil {
+		query["team"] = bson.M{"$in": teams}
+	}
+	return s.findByQuery(ctx, query)
+}
+
+func (s *serviceAccountStorage) findByQuery(ctx context.Context, query bson.M) ([]auth.ServiceAccount, error) {
+	span := newMongoDBSpan(ctx, mongoSpanFind, serviceAccountsCollectionName)
+	defer span.Finish()
+
+	conn, err := db.Conn()
+	if err != nil {
+		span.SetError(err)
+		return nil, err
+	}
+	defer conn.Close()
+	var accounts []serviceAccount
+	err = serviceAccountsCollection(conn).Find(query).Sort("_id").All(&accounts)
+	if err != nil {
+		span.SetError(err)
+		return nil, err
+	}
+	result := make([]auth.ServiceAccount, len(accounts))
+	for i, sa := range accounts {
+		result[i] = auth.ServiceAccount(sa)
+	}
+	return result, nil
+}
+
+func (s *serviceAccountStorage) AddRole(ctx context.Context, name string, role auth.RoleInstance) error {
+	return s.update(ctx, name, bson.M{"$addToSet": bson.M{"roles": roleToBson(role)}})
+}
+
+func (s *serviceAccountStorage) RemoveRole(ctx context.Context, name string, role auth.RoleInstance) error {
+	return s.update(ctx, name, bson.M{"$pullAll": bson.M{"roles": []bson.D{roleToBson(role)}}})
+}
+
+func (s *serviceAccountStorage) update(ctx context.Context, name string, update bson.M) error {
+	span := newMongoDBSpan(ctx, mongoSpanUpdate, serviceAccountsCollectionName)
+	defer span.Finish()
+
+	conn, err := db.Conn()
+	if err != nil {
+		span.SetError(err)
+		return err
+	}
+	defer conn.Close()
+	err = serviceAccountsCollection(conn).UpdateId(name, update)
+	if err == mgo.ErrNotFound {
+		err = auth.ErrServiceAccountNotFound
+	}
+	span.SetError(err)
+	return err
+}
+
+func (s *serviceAccountStorage) Delete(ctx context.Context, name string) error {
+	span := newMongoDBSpan(ctx, mongoSpanDelete, serviceAccountsCollectionName)
+	defer span.Finish()
+
+	conn, err := db.Conn()
+	if err != nil {
+		span.SetError(err)
+		return err
+	}
+	defer conn.Close()
+	err = serviceAccountsCollection(conn).RemoveId(name)
+	if err == mgo.ErrNotFound {
+		err = auth.ErrServiceAccountNotFound
+	}
+	span.SetError(err)
+	return err
+}
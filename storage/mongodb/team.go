@@ -26,6 +26,28 @@ type team struct {
 	CreatingUser string
 	Tags         []string
 	Quota        quota.Quota
+	// JobUnitsQuota and JobMemoryQuota are missing from teams created
+	// before jobs, they're loaded as unlimited.
+	JobUnitsQuota  quota.Quota
+	JobMemoryQuota quota.Quota
+	AllowedCIDRs   []string `bson:",omitempty"`
+	Parent         string   `bson:",omitempty"`
+}
+
+// SetBSON loads missing job quotas as unlimited, otherwise updating teams
+// created before jobs would store them with no limit left.
+func (t *team) SetBSON(raw bson.Raw) error {
+	type teamDocument team
+	doc := teamDocument{
+		JobUnitsQuota:  quota.UnlimitedQuota,
+		JobMemoryQuota: quota.UnlimitedQuota,
+	}
+	err := raw.Unmarshal(&doc)
+	if err != nil {
+		return err
+	}
+	*t = team(doc)
+	return nil
 }
 
 func teamsCollection(conn *db.Storage) *dbStorage.Collection {
@@ -102,6 +124,11 @@ func (s *TeamStorage) FindByNames(ctx context.Context, names []string) ([]auth.T
 	return s.findByQuery(ctx, query)
 }
 
+func (s *TeamStorage) FindByParents(ctx context.Context, parents []string) ([]auth.Team, error) {
+	query := bson.M{"parent": bson.M{"$in": parents}}
+	return s.findByQuery(ctx, query)
+}
+
 func (s *TeamStorage) findByQuery(ctx context.Context, query bson.M) ([]auth.Team, error) {
 	span := newMongoDBSpan(ctx, mongoSpanFind, teamsCollectionName)
 	span.SetQueryStatement(query)
@@ -17,3 +17,23 @@ func teamQuotaStorage() quota.QuotaStorage {
 		},
 	}
 }
+
+func teamJobUnitsQuotaStorage() quota.QuotaStorage {
+	return &quotaStorage{
+		collection: "teams",
+		query: func(name string) bson.M {
+			return bson.M{"_id": name}
+		},
+		field: "jobunitsquota",
+	}
+}
+
+func teamJobMemoryQuotaStorage() quota.QuotaStorage {
+	return &quotaStorage{
+		collection: "teams",
+		query: func(name string) bson.M {
+			return bson.M{"_id": name}
+		},
+		field: "jobmemoryquota",
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mongodb
+
+import (
+	"context"
+
+	"github.com/globalsign/mgo/bson"
+	"github.com/tsuru/tsuru/db"
+	"github.com/tsuru/tsuru/storage/storagetest"
+	"github.com/tsuru/tsuru/types/quota"
+	check "gopkg.in/check.v1"
+)
+
+var _ = check.Suite(&storagetest.TeamJobQuotaSuite{
+	TeamStorage:               &TeamStorage{},
+	TeamJobUnitsQuotaStorage:  teamJobUnitsQuotaStorage(),
+	TeamJobMemoryQuotaStorage: teamJobMemoryQuotaStorage(),
+	SuiteHooks:                &mongodbBaseTest{},
+})
+
+type teamJobQuotaSuite struct {
+	mongodbBaseTest
+}
+
+var _ = check.Suite(&teamJobQuotaSuite{})
+
+func (s *teamJobQuotaSuite) TestGetTeamWithoutJobQuota(c *check.C) {
+	conn, err := db.Conn()
+	c.Assert(err, check.IsNil)
+	defer conn.Close()
+	err = conn.Collection("teams").Insert(bson.M{"_id": "oldteam", "quota": quota.Quota{Limit: 2}})
+	c.Assert(err, check.IsNil)
+	q, err := teamJobUnitsQuotaStorage().Get(context.TODO(), "oldteam")
+	c.Assert(err, check.IsNil)
+	c.Assert(q, check.DeepEquals, &quota.UnlimitedQuota)
+	q, err = teamQuotaStorage().Get(context.TODO(), "oldteam")
+	c.Assert(err, check.IsNil)
+	c.Assert(q, check.DeepEquals, &quota.Quota{Limit: 2})
+}
+
+func (s *teamJobQuotaSuite) TestUpdateTeamWithoutJobQuota(c *check.C) {
+	conn, err := db.Conn()
+	c.Assert(err, check.IsNil)
+	defer conn.Close()
+	err = conn.Collection("teams").Insert(bson.M{"_id": "oldteam", "quota": quota.Quota{Limit: 2}})
+	c.Assert(err, check.IsNil)
+	storage := &TeamStorage{}
+	t, err := storage.FindByName(context.TODO(), "oldteam")
+	c.Assert(err, check.IsNil)
+	c.Assert(t.JobUnitsQuota, check.DeepEquals, quota.UnlimitedQuota)
+	c.Assert(t.JobMemoryQuota, check.DeepEquals, quota.UnlimitedQuota)
+	t.Tags = []string{"tag1"}
+	err = storage.Update(context.TODO(), *t)
+	c.Assert(err, check.IsNil)
+	q, err := teamJobUnitsQuotaStorage().Get(context.TODO(), "oldteam")
+	c.Assert(err, check.IsNil)
+	c.Assert(q, check.DeepEquals, &quota.UnlimitedQuota)
+	q, err = teamJobMemoryQuotaStorage().Get(context.TODO(), "oldteam")
+	c.Assert(err, check.IsNil)
+	c.Assert(q, check.DeepEquals, &quota.UnlimitedQuota)
+}
@@ -28,7 +28,16 @@ type teamToken struct {
 	LastAccess   time.Time `bson:"last_access,omitempty"`
 	CreatorEmail string    `bson:"creator_email"`
 	Team         string
-	Roles        []auth.RoleInstance `bson:",omitempty"`
+	Roles        []auth.RoleInstance   `bson:",omitempty"`
+	AllowedCIDRs []string              `bson:"allowed_cidrs,omitempty"`
+	Scopes       []auth.TeamTokenScope `bson:",omitempty"`
+
+	ServiceAccount string `bson:"service_account,omitempty"`
+
+	PreviousToken          string    `bson:"previous_token,omitempty"`
+	PreviousTokenExpiresAt time.Time `bson:"previous_token_expires_at,omitempty"`
+	RotatedAt              time.Time `bson:"rotated_at,omitempty"`
+	ExpirationWarnedAt     time.Time `bson:"expiration_warned_at,omitempty"`
 }
 
 var _ auth.TeamTokenStorage = &teamTokenStorage{}
@@ -37,6 +46,7 @@ func teamTokensCollection(conn *db.Storage) *dbStorage.Collection {
 	c := conn.Collection(teamsTokensCollectionName)
 	c.EnsureIndex(mgo.Index{Key: []string{"token"}, Unique: true})
 	c.EnsureIndex(mgo.Index{Key: []string{"token_id"}, Unique: true})
+	c.EnsureIndex(mgo.Index{Key: []string{"previous_token"}, Sparse: true})
 	return c
 }
 
@@ -72,8 +82,14 @@ func (s *teamTokenStorage) findOne(ctx context.Context, query bson.M) (*auth.Tea
 	return &results[0], nil
 }
 
+// FindByToken returns the team token with the given secret, either the
+// current one or the previous one, kept after a rotation.
 func (s *teamTokenStorage) FindByToken(ctx context.Context, token string) (*auth.TeamToken, error) {
-	return s.findOne(ctx, bson.M{"token": token})
+	return s.findOne(ctx, tokenQuery(token))
+}
+
+func tokenQuery(token string) bson.M {
+	return bson.M{"$or": []bson.M{{"token": token}, {"previous_token": token}}}
 }
 
 func (s *teamTokenStorage) FindByTokenID(ctx context.Context, tokenID string) (*auth.TeamToken, error) {
@@ -88,6 +104,14 @@ func (s *teamTokenStorage) FindByTeams(ctx context.Context, teamNames []string)
 	return s.findByQuery(ctx, query)
 }
 
+func (s *teamTokenStorage) FindByServiceAccount(ctx context.Context, serviceAccount string) ([]auth.TeamToken, error) {
+	return s.findByQuery(ctx, bson.M{"service_account": serviceAccount})
+}
+
+func (s *teamTokenStorage) FindExpiringBefore(ctx context.Context, t time.Time) ([]auth.TeamToken, error) {
+	return s.findByQuery(ctx, bson.M{"expires_at": bson.M{"$lte": t}})
+}
+
 func (s *teamTokenStorage) findByQuery(ctx context.Context, query bson.M) ([]auth.TeamToken, error) {
 	span := newMongoDBSpan(ctx, mongoSpanFind, teamsTokensCollectionName)
 	defer span.Finish()
@@ -121,9 +145,7 @@ func (s *teamTokenStorage) UpdateLastAccess(ctx context.Context, token string) e
 		return err
 	}
 	defer conn.Close()
-	err = teamTokensCollection(conn).Update(bson.M{
-		"token": token,
-	}, bson.M{
+	err = teamTokensCollection(conn).Update(tokenQuery(token), bson.M{
 		"$set": bson.M{"last_access": time.Now().UTC()},
 	})
 	if err == mgo.ErrNotFound {
@@ -25,6 +25,12 @@ func webhookCollection(conn *db.Storage) *dbStorage.Collection {
 	return coll
 }
 
+func webhookDeadLetterCollection(conn *db.Storage) *dbStorage.Collection {
+	coll := conn.Collection("webhook_dead_letters")
+	coll.EnsureIndex(mgo.Index{Key: []string{"webhook"}})
+	return coll
+}
+
 var _ event.WebhookStorage = &webhookStorage{}
 
 func (s *webhookStorage) Insert(w event.Webhook) error {
@@ -120,7 +126,64 @@ func (s *webhookStorage) Delete(name string) error {
 	defer conn.Close()
 	err = webhookCollection(conn).Remove(bson.M{"name": name})
 	if err == mgo.ErrNotFound {
-		err = event.ErrWebhookNotFound
+		return event.ErrWebhookNotFound
+	}
+	if err != nil {
+		return err
+	}
+	_, err = webhookDeadLetterCollection(conn).RemoveAll(bson.M{"webhook": name})
+	return err
+}
+
+func (s *webhookStorage) InsertDeadLetter(d event.WebhookDeadLetter) error {
+	conn, err := db.Conn()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if d.ID == "" {
+		d.ID = bson.NewObjectId().Hex()
+	}
+	return webhookDeadLetterCollection(conn).Insert(d)
+}
+
+func (s *webhookStorage) FindDeadLetters(webhookName string) ([]event.WebhookDeadLetter, error) {
+	conn, err := db.Conn()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	var deadLetters []event.WebhookDeadLetter
+	err = webhookDeadLetterCollection(conn).Find(bson.M{"webhook": webhookName}).Sort("createdat").All(&deadLetters)
+	return deadLetters, err
+}
+
+func (s *webhookStorage) FindDeadLetter(id string) (*event.WebhookDeadLetter, error) {
+	conn, err := db.Conn()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	var result event.WebhookDeadLetter
+	err = webhookDeadLetterCollection(conn).FindId(id).One(&result)
+	if err != nil {
+		if err == mgo.ErrNotFound {
+			err = event.ErrDeadLetterNotFound
+		}
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (s *webhookStorage) DeleteDeadLetter(id string) error {
+	conn, err := db.Conn()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	err = webhookDeadLetterCollection(conn).RemoveId(id)
+	if err == mgo.ErrNotFound {
+		err = event.ErrDeadLetterNotFound
 	}
 	return err
 }
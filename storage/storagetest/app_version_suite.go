@@ -210,6 +210,28 @@ func (s *AppVersionSuite) TestAppVersionStorage_DeleteVersionIDs(c *check.C) {
 	})
 }
 
+func (s *AppVersionSuite) TestAppVersionStorage_RenameApp(c *check.C) {
+	app := &appTypes.MockApp{Name: "myapp"}
+	vi, err := s.AppVersionStorage.NewAppVersion(context.TODO(), appTypes.NewVersionArgs{App: app})
+	c.Assert(err, check.IsNil)
+	staleApp := &appTypes.MockApp{Name: "newapp"}
+	_, err = s.AppVersionStorage.NewAppVersion(context.TODO(), appTypes.NewVersionArgs{App: staleApp})
+	c.Assert(err, check.IsNil)
+	err = s.AppVersionStorage.DeleteVersions(context.TODO(), "newapp")
+	c.Assert(err, check.IsNil)
+	err = s.AppVersionStorage.RenameApp(context.TODO(), "myapp", "newapp")
+	c.Assert(err, check.IsNil)
+	_, err = s.AppVersionStorage.AppVersions(context.TODO(), app)
+	c.Assert(err, check.Equals, appTypes.ErrNoVersionsAvailable)
+	versions, err := s.AppVersionStorage.AppVersions(context.TODO(), &appTypes.MockApp{Name: "newapp"})
+	c.Assert(err, check.IsNil)
+	c.Assert(versions.AppName, check.Equals, "newapp")
+	c.Assert(versions.Versions, check.HasLen, 1)
+	c.Assert(versions.Versions[vi.Version].Version, check.Equals, vi.Version)
+	err = s.AppVersionStorage.RenameApp(context.TODO(), "notfound", "other")
+	c.Assert(err, check.IsNil)
+}
+
 func (s *AppVersionSuite) TestAppVersionStorage_ConcurrencyDeletes(c *check.C) {
 	app := &appTypes.MockApp{Name: "myapp-concurrent"}
 
@@ -0,0 +1,91 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package storagetest
+
+import (
+	"context"
+	"time"
+
+	auditTypes "github.com/tsuru/tsuru/types/audit"
+	check "gopkg.in/check.v1"
+)
+
+type AuditSuite struct {
+	SuiteHooks
+	AuditStorage auditTypes.AuditStorage
+}
+
+func (s *AuditSuite) insertEntries(c *check.C) time.Time {
+	now := time.Now().UTC().Truncate(time.Second)
+	entries := []auditTypes.Entry{
+		{Time: now.Add(-2 * time.Hour), Method: "GET", Path: "/apps", URL: "/1.0/apps", Status: 200, Owner: "a@example.com", TokenID: "t1"},
+		{Time: now.Add(-time.Hour), Method: "POST", Path: "/apps", URL: "/1.0/apps", Status: 201, Owner: "b@example.com", TokenID: "t2"},
+		{Time: now, Method: "GET", Path: "/apps/{app}", URL: "/1.0/apps/myapp", Status: 404, Owner: "a@example.com", TokenID: "t1", Latency: time.Second},
+	}
+	for _, e := range entries {
+		err := s.AuditStorage.Insert(context.TODO(), e)
+		c.Assert(err, check.IsNil)
+	}
+	return now
+}
+
+func auditURLs(entries []auditTypes.Entry) []string {
+	var result []string
+	for _, e := range entries {
+		result = append(result, e.Method+" "+e.URL)
+	}
+	return result
+}
+
+func (s *AuditSuite) TestInsertList(c *check.C) {
+	now := s.insertEntries(c)
+	entries, err := s.AuditStorage.List(context.TODO(), nil)
+	c.Assert(err, check.IsNil)
+	c.Assert(entries, check.HasLen, 3)
+	entries[0].Time = entries[0].Time.UTC()
+	c.Assert(entries[0], check.DeepEquals, auditTypes.Entry{
+		Time:    now,
+		Method:  "GET",
+		Path:    "/apps/{app}",
+		URL:     "/1.0/apps/myapp",
+		Status:  404,
+		Owner:   "a@example.com",
+		TokenID: "t1",
+		Latency: time.Second,
+	})
+	c.Assert(auditURLs(entries), check.DeepEquals, []string{"GET /1.0/apps/myapp", "POST /1.0/apps", "GET /1.0/apps"})
+}
+
+func (s *AuditSuite) TestListFilter(c *check.C) {
+	now := s.insertEntries(c)
+	tests := []struct {
+		filter   auditTypes.Filter
+		expected []string
+	}{
+		{auditTypes.Filter{Owner: "a@example.com"}, []string{"GET /1.0/apps/myapp", "GET /1.0/apps"}},
+		{auditTypes.Filter{TokenID: "t2"}, []string{"POST /1.0/apps"}},
+		{auditTypes.Filter{Method: "GET", Path: "/apps"}, []string{"GET /1.0/apps"}},
+		{auditTypes.Filter{Status: 404}, []string{"GET /1.0/apps/myapp"}},
+		{auditTypes.Filter{Since: now.Add(-90 * time.Minute)}, []string{"GET /1.0/apps/myapp", "POST /1.0/apps"}},
+		{auditTypes.Filter{Until: now.Add(-90 * time.Minute)}, []string{"GET /1.0/apps"}},
+		{auditTypes.Filter{Limit: 1, Skip: 1}, []string{"POST /1.0/apps"}},
+	}
+	for _, tt := range tests {
+		f := tt.filter
+		entries, err := s.AuditStorage.List(context.TODO(), &f)
+		c.Assert(err, check.IsNil)
+		c.Assert(auditURLs(entries), check.DeepEquals, tt.expected, check.Commentf("filter: %#v", tt.filter))
+	}
+}
+
+func (s *AuditSuite) TestRemoveBefore(c *check.C) {
+	now := s.insertEntries(c)
+	removed, err := s.AuditStorage.RemoveBefore(context.TODO(), now.Add(-30*time.Minute))
+	c.Assert(err, check.IsNil)
+	c.Assert(removed, check.Equals, 2)
+	entries, err := s.AuditStorage.List(context.TODO(), nil)
+	c.Assert(err, check.IsNil)
+	c.Assert(auditURLs(entries), check.DeepEquals, []string{"GET /1.0/apps/myapp"})
+}
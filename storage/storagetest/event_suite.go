@@ -0,0 +1,172 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package storagetest
+
+import (
+	"context"
+	"time"
+
+	"github.com/globalsign/mgo/bson"
+	eventTypes "github.com/tsuru/tsuru/types/event"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+	check "gopkg.in/check.v1"
+)
+
+type EventSuite struct {
+	SuiteHooks
+	EventStorage eventTypes.EventStorage
+}
+
+func newEventData(target eventTypes.Target, running bool) *eventTypes.EventData {
+	uniqueID := bson.NewObjectId()
+	evt := &eventTypes.EventData{
+		UniqueID:  uniqueID,
+		StartTime: time.Now().UTC().Truncate(time.Millisecond),
+		Target:    target,
+		Kind:      eventTypes.Kind{Type: "permission", Name: "app.update"},
+		Owner:     eventTypes.Owner{Type: "user", Name: "me@example.com"},
+		Running:   running,
+		Allowed: eventTypes.AllowedPermission{
+			Scheme:   "app.read.events",
+			Contexts: []permTypes.PermissionContext{{CtxType: permTypes.CtxTeam, Value: "team1"}},
+		},
+	}
+	if running {
+		evt.ID = eventTypes.EventID{Target: target}
+	} else {
+		evt.ID = eventTypes.EventID{ObjId: uniqueID}
+	}
+	return evt
+}
+
+func (s *EventSuite) TestInsertAndFind(c *check.C) {
+	evt := newEventData(eventTypes.Target{Type: "app", Value: "myapp"}, true)
+	err := s.EventStorage.Insert(context.TODO(), evt)
+	c.Assert(err, check.IsNil)
+	found, err := s.EventStorage.FindByID(context.TODO(), evt.ID)
+	c.Assert(err, check.IsNil)
+	c.Assert(found.UniqueID, check.Equals, evt.UniqueID)
+	found, err = s.EventStorage.FindByUniqueID(context.TODO(), evt.UniqueID)
+	c.Assert(err, check.IsNil)
+	c.Assert(found.Target, check.Equals, evt.Target)
+	found, err = s.EventStorage.FindRunning(context.TODO(), evt.Target, "app.update")
+	c.Assert(err, check.IsNil)
+	c.Assert(found.UniqueID, check.Equals, evt.UniqueID)
+	_, err = s.EventStorage.FindByUniqueID(context.TODO(), bson.NewObjectId())
+	c.Assert(err, check.Equals, eventTypes.ErrEventNotFound)
+}
+
+func (s *EventSuite) TestInsertDuplicatedID(c *check.C) {
+	target := eventTypes.Target{Type: "app", Value: "myapp"}
+	err := s.EventStorage.Insert(context.TODO(), newEventData(target, true))
+	c.Assert(err, check.IsNil)
+	err = s.EventStorage.Insert(context.TODO(), newEventData(target, true))
+	c.Assert(err, check.Equals, eventTypes.ErrEventIDInUse)
+}
+
+func (s *EventSuite) TestUpdateAndRemove(c *check.C) {
+	evt := newEventData(eventTypes.Target{Type: "app", Value: "myapp"}, false)
+	err := s.EventStorage.Insert(context.TODO(), evt)
+	c.Assert(err, check.IsNil)
+	evt.Error = "failed"
+	err = s.EventStorage.Update(context.TODO(), evt)
+	c.Assert(err, check.IsNil)
+	found, err := s.EventStorage.FindByID(context.TODO(), evt.ID)
+	c.Assert(err, check.IsNil)
+	c.Assert(found.Error, check.Equals, "failed")
+	err = s.EventStorage.Remove(context.TODO(), evt.ID)
+	c.Assert(err, check.IsNil)
+	err = s.EventStorage.Remove(context.TODO(), evt.ID)
+	c.Assert(err, check.Equals, eventTypes.ErrEventNotFound)
+}
+
+func (s *EventSuite) TestFindLocking(c *check.C) {
+	locking := newEventData(eventTypes.Target{Type: "app", Value: "myapp"}, true)
+	err := s.EventStorage.Insert(context.TODO(), locking)
+	c.Assert(err, check.IsNil)
+	found, err := s.EventStorage.FindLocking(context.TODO(), []eventTypes.Target{locking.Target}, bson.NewObjectId())
+	c.Assert(err, check.IsNil)
+	c.Assert(found.UniqueID, check.Equals, locking.UniqueID)
+	_, err = s.EventStorage.FindLocking(context.TODO(), []eventTypes.Target{locking.Target}, locking.UniqueID)
+	c.Assert(err, check.Equals, eventTypes.ErrEventNotFound)
+	_, err = s.EventStorage.FindLocking(context.TODO(), []eventTypes.Target{{Type: "app", Value: "other"}}, bson.NewObjectId())
+	c.Assert(err, check.Equals, eventTypes.ErrEventNotFound)
+}
+
+func (s *EventSuite) TestFindAll(c *check.C) {
+	evt1 := newEventData(eventTypes.Target{Type: "app", Value: "app1"}, false)
+	evt1.StartTime = evt1.StartTime.Add(-time.Minute)
+	evt2 := newEventData(eventTypes.Target{Type: "app", Value: "app2"}, false)
+	evt3 := newEventData(eventTypes.Target{Type: "node", Value: "node1"}, false)
+	evt3.StartTime = evt3.StartTime.Add(time.Minute)
+	for _, evt := range []*eventTypes.EventData{evt1, evt2, evt3} {
+		err := s.EventStorage.Insert(context.TODO(), evt)
+		c.Assert(err, check.IsNil)
+	}
+	all, err := s.EventStorage.FindAll(context.TODO(), nil)
+	c.Assert(err, check.IsNil)
+	c.Assert(all, check.HasLen, 3)
+	c.Assert(all[0].UniqueID, check.Equals, evt3.UniqueID)
+	all, err = s.EventStorage.FindAll(context.TODO(), &eventTypes.EventFilter{Target: eventTypes.Target{Type: "app"}})
+	c.Assert(err, check.IsNil)
+	c.Assert(all, check.HasLen, 2)
+	all, err = s.EventStorage.FindAll(context.TODO(), &eventTypes.EventFilter{
+		After: &eventTypes.EventCursor{StartTime: evt2.StartTime, UniqueID: evt2.UniqueID},
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(all, check.HasLen, 1)
+	c.Assert(all[0].UniqueID, check.Equals, evt1.UniqueID)
+	all, err = s.EventStorage.FindAll(context.TODO(), &eventTypes.EventFilter{
+		AllowedTargets: []eventTypes.TargetFilter{{Type: "app", Values: []string{"app2"}}},
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(all, check.HasLen, 1)
+	c.Assert(all[0].UniqueID, check.Equals, evt2.UniqueID)
+	all, err = s.EventStorage.FindAll(context.TODO(), &eventTypes.EventFilter{AllowedTargets: []eventTypes.TargetFilter{}})
+	c.Assert(err, check.IsNil)
+	c.Assert(all, check.HasLen, 0)
+	all, err = s.EventStorage.FindAll(context.TODO(), &eventTypes.EventFilter{
+		Permissions: []eventTypes.AllowedPermission{{
+			Scheme:   "app.read",
+			Contexts: []permTypes.PermissionContext{{CtxType: permTypes.CtxTeam, Value: "team2"}},
+		}},
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(all, check.HasLen, 0)
+	all, err = s.EventStorage.FindAll(context.TODO(), &eventTypes.EventFilter{
+		Permissions: []eventTypes.AllowedPermission{{Scheme: "app.read"}},
+	})
+	c.Assert(err, check.IsNil)
+	c.Assert(all, check.HasLen, 3)
+}
+
+func (s *EventSuite) TestRequestAndAckCancel(c *check.C) {
+	evt := newEventData(eventTypes.Target{Type: "app", Value: "myapp"}, true)
+	evt.Cancelable = true
+	err := s.EventStorage.Insert(context.TODO(), evt)
+	c.Assert(err, check.IsNil)
+	_, err = s.EventStorage.AckCancel(context.TODO(), evt.ID, time.Now().UTC())
+	c.Assert(err, check.Equals, eventTypes.ErrEventNotFound)
+	updated, err := s.EventStorage.RequestCancel(context.TODO(), evt.ID, eventTypes.CancelInfo{Owner: "me", Reason: "because", Asked: true})
+	c.Assert(err, check.IsNil)
+	c.Assert(updated.CancelInfo.Asked, check.Equals, true)
+	_, err = s.EventStorage.RequestCancel(context.TODO(), evt.ID, eventTypes.CancelInfo{Owner: "me", Reason: "again", Asked: true})
+	c.Assert(err, check.Equals, eventTypes.ErrEventNotFound)
+	updated, err = s.EventStorage.AckCancel(context.TODO(), evt.ID, time.Now().UTC())
+	c.Assert(err, check.IsNil)
+	c.Assert(updated.CancelInfo.Canceled, check.Equals, true)
+}
+
+func (s *EventSuite) TestReassignOwner(c *check.C) {
+	evt := newEventData(eventTypes.Target{Type: "app", Value: "myapp"}, false)
+	err := s.EventStorage.Insert(context.TODO(), evt)
+	c.Assert(err, check.IsNil)
+	n, err := s.EventStorage.ReassignOwner(context.TODO(), evt.Owner, eventTypes.Owner{Type: "removed-user", Name: evt.Owner.Name})
+	c.Assert(err, check.IsNil)
+	c.Assert(n, check.Equals, 1)
+	found, err := s.EventStorage.FindByID(context.TODO(), evt.ID)
+	c.Assert(err, check.IsNil)
+	c.Assert(found.Owner, check.Equals, eventTypes.Owner{Type: "removed-user", Name: evt.Owner.Name})
+}
@@ -0,0 +1,76 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package storagetest
+
+import (
+	"context"
+	"time"
+
+	appTypes "github.com/tsuru/tsuru/types/app"
+	jobTypes "github.com/tsuru/tsuru/types/job"
+	check "gopkg.in/check.v1"
+)
+
+type JobSuite struct {
+	SuiteHooks
+	JobStorage jobTypes.JobStorage
+}
+
+func (s *JobSuite) TestInsertGetRemove(c *check.C) {
+	job := jobTypes.Job{
+		Name:      "my-job",
+		TeamOwner: "my-team",
+		Pool:      "my-pool",
+		Plan:      appTypes.Plan{Name: "small", Memory: 1024, CPUMilli: 500},
+		Owner:     "me@example.com",
+		Image:     "my-image:v1",
+		Command:   []string{"./run.sh"},
+		Envs:      map[string]string{"FOO": "bar"},
+		Schedule:  "*/5 * * * *",
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+	}
+	err := s.JobStorage.Insert(context.TODO(), job)
+	c.Assert(err, check.IsNil)
+	err = s.JobStorage.Insert(context.TODO(), job)
+	c.Assert(err, check.Equals, jobTypes.ErrJobAlreadyExists)
+	jobDB, err := s.JobStorage.Get(context.TODO(), "my-job")
+	c.Assert(err, check.IsNil)
+	jobDB.CreatedAt = jobDB.CreatedAt.UTC()
+	c.Assert(jobDB, check.DeepEquals, &job)
+	err = s.JobStorage.Remove(context.TODO(), "my-job")
+	c.Assert(err, check.IsNil)
+	_, err = s.JobStorage.Get(context.TODO(), "my-job")
+	c.Assert(err, check.Equals, jobTypes.ErrJobNotFound)
+	err = s.JobStorage.Remove(context.TODO(), "my-job")
+	c.Assert(err, check.Equals, jobTypes.ErrJobNotFound)
+}
+
+func (s *JobSuite) TestList(c *check.C) {
+	jobs := []jobTypes.Job{
+		{Name: "job1", TeamOwner: "team1", Pool: "pool1", Image: "img"},
+		{Name: "job2", TeamOwner: "team2", Pool: "pool1", Image: "img"},
+		{Name: "job3", TeamOwner: "team2", Pool: "pool2", Image: "img"},
+	}
+	for _, j := range jobs {
+		err := s.JobStorage.Insert(context.TODO(), j)
+		c.Assert(err, check.IsNil)
+	}
+	names := func(jobs []jobTypes.Job) []string {
+		var result []string
+		for _, j := range jobs {
+			result = append(result, j.Name)
+		}
+		return result
+	}
+	result, err := s.JobStorage.List(context.TODO(), nil)
+	c.Assert(err, check.IsNil)
+	c.Assert(names(result), check.DeepEquals, []string{"job1", "job2", "job3"})
+	result, err = s.JobStorage.List(context.TODO(), &jobTypes.Filter{Teams: []string{"team1"}})
+	c.Assert(err, check.IsNil)
+	c.Assert(names(result), check.DeepEquals, []string{"job1"})
+	result, err = s.JobStorage.List(context.TODO(), &jobTypes.Filter{Pools: []string{"pool2"}, Names: []string{"job1"}})
+	c.Assert(err, check.IsNil)
+	c.Assert(names(result), check.DeepEquals, []string{"job1", "job3"})
+}
@@ -0,0 +1,91 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package storagetest
+
+import (
+	"context"
+
+	"github.com/tsuru/tsuru/types/auth"
+	check "gopkg.in/check.v1"
+)
+
+type ServiceAccountSuite struct {
+	SuiteHooks
+	ServiceAccountStorage auth.ServiceAccountStorage
+}
+
+func (s *ServiceAccountSuite) TestInsertServiceAccount(c *check.C) {
+	sa := auth.ServiceAccount{Name: "deployer", Team: "team1", Description: "ci"}
+	err := s.ServiceAccountStorage.Insert(context.TODO(), sa)
+	c.Assert(err, check.IsNil)
+	result, err := s.ServiceAccountStorage.FindByName(context.TODO(), "deployer")
+	c.Assert(err, check.IsNil)
+	c.Assert(result.Team, check.Equals, "team1")
+	c.Assert(result.Description, check.Equals, "ci")
+}
+
+func (s *ServiceAccountSuite) TestInsertDuplicateServiceAccount(c *check.C) {
+	sa := auth.ServiceAccount{Name: "deployer", Team: "team1"}
+	err := s.ServiceAccountStorage.Insert(context.TODO(), sa)
+	c.Assert(err, check.IsNil)
+	err = s.ServiceAccountStorage.Insert(context.TODO(), sa)
+	c.Assert(err, check.Equals, auth.ErrServiceAccountAlreadyExists)
+}
+
+func (s *ServiceAccountSuite) TestFindServiceAccountByNameNotFound(c *check.C) {
+	sa, err := s.ServiceAccountStorage.FindByName(context.TODO(), "deployer")
+	c.Assert(err, check.Equals, auth.ErrServiceAccountNotFound)
+	c.Assert(sa, check.IsNil)
+}
+
+func (s *ServiceAccountSuite) TestFindServiceAccountsByTeams(c *check.C) {
+	err := s.ServiceAccountStorage.Insert(context.TODO(), auth.ServiceAccount{Name: "sa1", Team: "team1"})
+	c.Assert(err, check.IsNil)
+	err = s.ServiceAccountStorage.Insert(context.TODO(), auth.ServiceAccount{Name: "sa2", Team: "team2"})
+	c.Assert(err, check.IsNil)
+	err = s.ServiceAccountStorage.Insert(context.TODO(), auth.ServiceAccount{Name: "sa3", Team: "team1"})
+	c.Assert(err, check.IsNil)
+	accounts, err := s.ServiceAccountStorage.FindByTeams(context.TODO(), []string{"team1"})
+	c.Assert(err, check.IsNil)
+	c.Assert(accounts, check.HasLen, 2)
+	c.Assert([]string{accounts[0].Name, accounts[1].Name}, check.DeepEquals, []string{"sa1", "sa3"})
+	accounts, err = s.ServiceAccountStorage.FindByTeams(context.TODO(), nil)
+	c.Assert(err, check.IsNil)
+	c.Assert(accounts, check.HasLen, 3)
+	accounts, err = s.ServiceAccountStorage.FindByTeams(context.TODO(), []string{})
+	c.Assert(err, check.IsNil)
+	c.Assert(accounts, check.HasLen, 0)
+}
+
+func (s *ServiceAccountSuite) TestServiceAccountRoles(c *check.C) {
+	err := s.ServiceAccountStorage.Insert(context.TODO(), auth.ServiceAccount{Name: "deployer", Team: "team1"})
+	c.Assert(err, check.IsNil)
+	role := auth.RoleInstance{Name: "app-deployer", ContextValue: "myapp"}
+	err = s.ServiceAccountStorage.AddRole(context.TODO(), "deployer", role)
+	c.Assert(err, check.IsNil)
+	err = s.ServiceAccountStorage.AddRole(context.TODO(), "deployer", role)
+	c.Assert(err, check.IsNil)
+	sa, err := s.ServiceAccountStorage.FindByName(context.TODO(), "deployer")
+	c.Assert(err, check.IsNil)
+	c.Assert(sa.Roles, check.DeepEquals, []auth.RoleInstance{role})
+	err = s.ServiceAccountStorage.RemoveRole(context.TODO(), "deployer", role)
+	c.Assert(err, check.IsNil)
+	sa, err = s.ServiceAccountStorage.FindByName(context.TODO(), "deployer")
+	c.Assert(err, check.IsNil)
+	c.Assert(sa.Roles, check.HasLen, 0)
+	err = s.ServiceAccountStorage.AddRole(context.TODO(), "other", role)
+	c.Assert(err, check.Equals, auth.ErrServiceAccountNotFound)
+}
+
+func (s *ServiceAccountSuite) TestDeleteServiceAccount(c *check.C) {
+	err := s.ServiceAccountStorage.Insert(context.TODO(), auth.ServiceAccount{Name: "deployer", Team: "team1"})
+	c.Assert(err, check.IsNil)
+	err = s.ServiceAccountStorage.Delete(context.TODO(), "deployer")
+	c.Assert(err, check.IsNil)
+	_, err = s.ServiceAccountStorage.FindByName(context.TODO(), "deployer")
+	c.Assert(err, check.Equals, auth.ErrServiceAccountNotFound)
+	err = s.ServiceAccountStorage.Delete(context.TODO(), "deployer")
+	c.Assert(err, check.Equals, auth.ErrServiceAccountNotFound)
+}
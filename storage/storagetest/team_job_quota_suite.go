@@ -0,0 +1,62 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package storagetest
+
+import (
+	"context"
+
+	"github.com/tsuru/tsuru/types/auth"
+	"github.com/tsuru/tsuru/types/quota"
+	check "gopkg.in/check.v1"
+)
+
+type TeamJobQuotaSuite struct {
+	SuiteHooks
+	TeamStorage               auth.TeamStorage
+	TeamJobUnitsQuotaStorage  quota.QuotaStorage
+	TeamJobMemoryQuotaStorage quota.QuotaStorage
+}
+
+func (s *TeamJobQuotaSuite) TestGet(c *check.C) {
+	team := auth.Team{Name: "myteam", JobUnitsQuota: quota.Quota{Limit: 5, InUse: 2}, JobMemoryQuota: quota.Quota{Limit: 1024}}
+	err := s.TeamStorage.Insert(context.TODO(), team)
+	c.Assert(err, check.IsNil)
+	q, err := s.TeamJobUnitsQuotaStorage.Get(context.TODO(), "myteam")
+	c.Assert(err, check.IsNil)
+	c.Assert(q, check.DeepEquals, &quota.Quota{Limit: 5, InUse: 2})
+	q, err = s.TeamJobMemoryQuotaStorage.Get(context.TODO(), "myteam")
+	c.Assert(err, check.IsNil)
+	c.Assert(q, check.DeepEquals, &quota.Quota{Limit: 1024})
+}
+
+func (s *TeamJobQuotaSuite) TestGetNotFound(c *check.C) {
+	_, err := s.TeamJobUnitsQuotaStorage.Get(context.TODO(), "myteam")
+	c.Assert(err, check.Equals, quota.ErrQuotaNotFound)
+}
+
+func (s *TeamJobQuotaSuite) TestSetLimit(c *check.C) {
+	team := auth.Team{Name: "myteam", JobUnitsQuota: quota.UnlimitedQuota, JobMemoryQuota: quota.UnlimitedQuota}
+	err := s.TeamStorage.Insert(context.TODO(), team)
+	c.Assert(err, check.IsNil)
+	err = s.TeamJobUnitsQuotaStorage.SetLimit(context.TODO(), "myteam", 3)
+	c.Assert(err, check.IsNil)
+	q, err := s.TeamJobUnitsQuotaStorage.Get(context.TODO(), "myteam")
+	c.Assert(err, check.IsNil)
+	c.Assert(q, check.DeepEquals, &quota.Quota{Limit: 3})
+	q, err = s.TeamJobMemoryQuotaStorage.Get(context.TODO(), "myteam")
+	c.Assert(err, check.IsNil)
+	c.Assert(q, check.DeepEquals, &quota.UnlimitedQuota)
+}
+
+func (s *TeamJobQuotaSuite) TestSet(c *check.C) {
+	team := auth.Team{Name: "myteam", JobUnitsQuota: quota.Quota{Limit: 5}, JobMemoryQuota: quota.UnlimitedQuota}
+	err := s.TeamStorage.Insert(context.TODO(), team)
+	c.Assert(err, check.IsNil)
+	err = s.TeamJobUnitsQuotaStorage.Set(context.TODO(), "myteam", 4)
+	c.Assert(err, check.IsNil)
+	q, err := s.TeamJobUnitsQuotaStorage.Get(context.TODO(), "myteam")
+	c.Assert(err, check.IsNil)
+	c.Assert(q, check.DeepEquals, &quota.Quota{Limit: 5, InUse: 4})
+}
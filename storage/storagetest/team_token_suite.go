@@ -164,3 +164,43 @@ func (s *TeamTokenSuite) TestUpdateTeamTokenNotFound(c *check.C) {
 	err := s.TeamTokenStorage.Update(context.TODO(), t)
 	c.Assert(err, check.Equals, auth.ErrTeamTokenNotFound)
 }
+
+func (s *TeamTokenSuite) TestFindTeamTokenByPreviousToken(c *check.C) {
+	t := auth.TeamToken{Token: "5678", TokenID: "a", PreviousToken: "1234"}
+	err := s.TeamTokenStorage.Insert(context.TODO(), t)
+	c.Assert(err, check.IsNil)
+	token, err := s.TeamTokenStorage.FindByToken(context.TODO(), "1234")
+	c.Assert(err, check.IsNil)
+	c.Assert(token.Token, check.Equals, "5678")
+	c.Assert(token.PreviousToken, check.Equals, "1234")
+	err = s.TeamTokenStorage.UpdateLastAccess(context.TODO(), "1234")
+	c.Assert(err, check.IsNil)
+	token, err = s.TeamTokenStorage.FindByToken(context.TODO(), "5678")
+	c.Assert(err, check.IsNil)
+	c.Assert(token.LastAccess.IsZero(), check.Equals, false)
+}
+
+func (s *TeamTokenSuite) TestFindTeamTokensExpiringBefore(c *check.C) {
+	now := time.Now()
+	err := s.TeamTokenStorage.Insert(context.TODO(), auth.TeamToken{Token: "123", TokenID: "1", ExpiresAt: now.Add(time.Hour)})
+	c.Assert(err, check.IsNil)
+	err = s.TeamTokenStorage.Insert(context.TODO(), auth.TeamToken{Token: "456", TokenID: "4", ExpiresAt: now.Add(3 * time.Hour)})
+	c.Assert(err, check.IsNil)
+	err = s.TeamTokenStorage.Insert(context.TODO(), auth.TeamToken{Token: "789", TokenID: "7"})
+	c.Assert(err, check.IsNil)
+	tokens, err := s.TeamTokenStorage.FindExpiringBefore(context.TODO(), now.Add(2*time.Hour))
+	c.Assert(err, check.IsNil)
+	c.Assert(tokens, check.HasLen, 1)
+	c.Assert(tokens[0].Token, check.Equals, "123")
+}
+
+func (s *TeamTokenSuite) TestFindTeamTokensByServiceAccount(c *check.C) {
+	err := s.TeamTokenStorage.Insert(context.TODO(), auth.TeamToken{Token: "123", TokenID: "1", ServiceAccount: "deployer"})
+	c.Assert(err, check.IsNil)
+	err = s.TeamTokenStorage.Insert(context.TODO(), auth.TeamToken{Token: "456", TokenID: "4"})
+	c.Assert(err, check.IsNil)
+	tokens, err := s.TeamTokenStorage.FindByServiceAccount(context.TODO(), "deployer")
+	c.Assert(err, check.IsNil)
+	c.Assert(tokens, check.HasLen, 1)
+	c.Assert(tokens[0].Token, check.Equals, "123")
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"net/http"
 	"sort"
+	"time"
 
 	eventTypes "github.com/tsuru/tsuru/types/event"
 	check "gopkg.in/check.v1"
@@ -239,3 +240,56 @@ func (s *WebhookSuite) TestFindByNameNotFound(c *check.C) {
 	_, err := s.WebhookStorage.FindByName("wh1")
 	c.Assert(err, check.Equals, eventTypes.ErrWebhookNotFound)
 }
+
+func (s *WebhookSuite) TestDeadLetters(c *check.C) {
+	now := time.Now().UTC().Truncate(time.Second)
+	err := s.WebhookStorage.InsertDeadLetter(eventTypes.WebhookDeadLetter{
+		ID:        "dl1",
+		Webhook:   "wh1",
+		EventID:   "evt1",
+		Attempts:  3,
+		Error:     "invalid status code",
+		CreatedAt: now,
+	})
+	c.Assert(err, check.IsNil)
+	err = s.WebhookStorage.InsertDeadLetter(eventTypes.WebhookDeadLetter{Webhook: "wh2", EventID: "evt2"})
+	c.Assert(err, check.IsNil)
+	deadLetters, err := s.WebhookStorage.FindDeadLetters("wh1")
+	c.Assert(err, check.IsNil)
+	c.Assert(deadLetters, check.HasLen, 1)
+	c.Assert(deadLetters[0].CreatedAt.Equal(now), check.Equals, true)
+	deadLetters[0].CreatedAt = now
+	c.Assert(deadLetters[0], check.DeepEquals, eventTypes.WebhookDeadLetter{
+		ID:        "dl1",
+		Webhook:   "wh1",
+		EventID:   "evt1",
+		Attempts:  3,
+		Error:     "invalid status code",
+		CreatedAt: now,
+	})
+	deadLetters, err = s.WebhookStorage.FindDeadLetters("wh2")
+	c.Assert(err, check.IsNil)
+	c.Assert(deadLetters, check.HasLen, 1)
+	c.Assert(deadLetters[0].ID, check.Not(check.Equals), "")
+	dl, err := s.WebhookStorage.FindDeadLetter("dl1")
+	c.Assert(err, check.IsNil)
+	c.Assert(dl.EventID, check.Equals, "evt1")
+	err = s.WebhookStorage.DeleteDeadLetter("dl1")
+	c.Assert(err, check.IsNil)
+	_, err = s.WebhookStorage.FindDeadLetter("dl1")
+	c.Assert(err, check.Equals, eventTypes.ErrDeadLetterNotFound)
+	err = s.WebhookStorage.DeleteDeadLetter("dl1")
+	c.Assert(err, check.Equals, eventTypes.ErrDeadLetterNotFound)
+}
+
+func (s *WebhookSuite) TestDeleteRemovesDeadLetters(c *check.C) {
+	err := s.WebhookStorage.Insert(eventTypes.Webhook{Name: "wh1"})
+	c.Assert(err, check.IsNil)
+	err = s.WebhookStorage.InsertDeadLetter(eventTypes.WebhookDeadLetter{ID: "dl1", Webhook: "wh1"})
+	c.Assert(err, check.IsNil)
+	err = s.WebhookStorage.Delete("wh1")
+	c.Assert(err, check.IsNil)
+	deadLetters, err := s.WebhookStorage.FindDeadLetters("wh1")
+	c.Assert(err, check.IsNil)
+	c.Assert(deadLetters, check.HasLen, 0)
+}
@@ -0,0 +1,42 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package api
+
+const (
+	BatchActionEnvSet  = "env-set"
+	BatchActionRestart = "restart"
+)
+
+// Batch represents a list of operations executed on many apps in a single
+// call to the remote API.
+type Batch struct {
+	Operations []BatchOperation `json:"operations"`
+}
+
+// BatchOperation is an action executed on the apps listed by name in Apps
+// and on the apps matching the tag selector in Tag.
+type BatchOperation struct {
+	Action    string   `json:"action"`
+	Apps      []string `json:"apps,omitempty"`
+	Tag       string   `json:"tag,omitempty"`
+	Envs      []Env    `json:"envs,omitempty"`
+	Private   bool     `json:"private,omitempty"`
+	NoRestart bool     `json:"noRestart,omitempty"`
+	Process   string   `json:"process,omitempty"`
+}
+
+// BatchResult is the outcome of an operation on a single app, Error is empty
+// when the operation succeeded.
+type BatchResult struct {
+	Operation int    `json:"operation"`
+	Action    string `json:"action"`
+	App       string `json:"app"`
+	Error     string `json:"error,omitempty"`
+}
+
+type BatchResponse struct {
+	EventID string        `json:"eventID"`
+	Results []BatchResult `json:"results"`
+}
@@ -20,4 +20,5 @@ type Env struct {
 	Alias     string
 	Private   *bool  `json:"private,omitempty"`
 	ManagedBy string `json:"-" bson:"managedBy"`
+	SecretRef bool   `json:"secretRef,omitempty"`
 }
@@ -16,6 +16,7 @@ type App interface {
 	GetPool() string
 	GetTeamOwner() string
 	GetTeamsName() []string
+	ListTags() []string
 	GetPlatform() string
 	GetPlatformVersion() string
 	GetRegistry() (image.ImageRegistry, error)
@@ -37,6 +38,9 @@ type RoutableAddresses struct {
 	Prefix    string
 	Addresses []*url.URL
 	ExtraData map[string]string
+	// Weight is the percentage of the app traffic sent to this prefix, zero
+	// means the prefix only receives requests addressed to it.
+	Weight int
 }
 
 type Filter struct {
@@ -50,6 +54,7 @@ type Filter struct {
 	Statuses    []string
 	Locked      bool
 	Tags        []string
+	TagSelector []TagRequirement
 	Extra       map[string][]string
 }
 
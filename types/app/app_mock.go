@@ -15,6 +15,7 @@ type MockApp struct {
 	Deploys                                          uint
 	UpdatePlatform                                   bool
 	TeamsName                                        []string
+	Tags                                             []string
 	Registry                                         imgTypes.ImageRegistry
 }
 
@@ -34,6 +35,10 @@ func (a *MockApp) GetTeamOwner() string {
 	return a.TeamOwner
 }
 
+func (a *MockApp) ListTags() []string {
+	return a.Tags
+}
+
 func (a *MockApp) GetPlatform() string {
 	return a.Platform
 }
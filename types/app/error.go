@@ -19,6 +19,8 @@ var (
 	ErrPlanDefaultNotFound    = errors.New("default plan not found")
 	ErrLimitOfCpuShare        = errors.New("The minimum allowed cpu-shares is 2")
 	ErrLimitOfMemory          = errors.New("The minimum allowed memory is 4MB")
+	ErrLimitOfUnits           = errors.New("The minimum units must be lower than or equal to the maximum units")
+	ErrLimitOfGPU             = errors.New("The number of gpus must be positive and is required by the gpu type")
 	ErrPlatformNameMissing    = errors.New("Platform name is required.")
 	ErrPlatformImageMissing   = errors.New("Platform image is required.")
 	ErrPlatformNotFound       = errors.New("Platform doesn't exist.")
@@ -97,7 +97,29 @@ func (m *Metadata) Update(new Metadata) {
 	m.Labels = updateList(m.Labels, new.Labels)
 }
 
+// Equal reports whether both metadata have the same labels and annotations,
+// regardless of their order.
+func (m Metadata) Equal(other Metadata) bool {
+	return sameItems(m.Labels, other.Labels) && sameItems(m.Annotations, other.Annotations)
+}
+
+func sameItems(a, b []MetadataItem) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, item := range a {
+		v, ok := getItem(b, item.Name)
+		if !ok || v != item.Value {
+			return false
+		}
+	}
+	return true
+}
+
 func updateList(list []MetadataItem, newItems []MetadataItem) []MetadataItem {
+	// items are swapped in place on removal, copying the list avoids changing
+	// the original slice shared with other copies of the metadata.
+	list = append([]MetadataItem(nil), list...)
 	for _, item := range newItems {
 		n := hasItem(list, item.Name)
 		if n != -1 {
@@ -22,6 +22,33 @@ func (s S) TestMetadataUpdateList(c *check.C) {
 	c.Assert(result, check.DeepEquals, []MetadataItem{{Name: "found-item", Value: "new-value"}})
 }
 
+func (s S) TestMetadataUpdateKeepsOriginal(c *check.C) {
+	original := Metadata{Labels: []MetadataItem{{Name: "l1", Value: "v1"}, {Name: "l2", Value: "v2"}}}
+	m := original
+	m.Update(Metadata{Labels: []MetadataItem{{Name: "l1", Delete: true}}})
+	c.Assert(m.Labels, check.DeepEquals, []MetadataItem{{Name: "l2", Value: "v2"}})
+	c.Assert(original.Labels, check.DeepEquals, []MetadataItem{{Name: "l1", Value: "v1"}, {Name: "l2", Value: "v2"}})
+}
+
+func (s S) TestMetadataEqual(c *check.C) {
+	m := Metadata{
+		Labels:      []MetadataItem{{Name: "l1", Value: "v1"}, {Name: "l2", Value: "v2"}},
+		Annotations: []MetadataItem{{Name: "a1", Value: "v1"}},
+	}
+	c.Assert(m.Equal(Metadata{
+		Labels:      []MetadataItem{{Name: "l2", Value: "v2"}, {Name: "l1", Value: "v1"}},
+		Annotations: []MetadataItem{{Name: "a1", Value: "v1"}},
+	}), check.Equals, true)
+	c.Assert(m.Equal(Metadata{
+		Labels:      []MetadataItem{{Name: "l1", Value: "v1"}, {Name: "l2", Value: "other"}},
+		Annotations: []MetadataItem{{Name: "a1", Value: "v1"}},
+	}), check.Equals, false)
+	c.Assert(m.Equal(Metadata{
+		Labels: []MetadataItem{{Name: "l1", Value: "v1"}, {Name: "l2", Value: "v2"}},
+	}), check.Equals, false)
+	c.Assert(Metadata{}.Equal(Metadata{Labels: []MetadataItem{}}), check.Equals, true)
+}
+
 func Test(t *testing.T) {
 	check.TestingT(t)
 }
@@ -15,6 +15,14 @@ type Plan struct {
 	CPUMilli int          `json:"cpumilli"`
 	Default  bool         `json:"default,omitempty"`
 	Override PlanOverride `json:"override,omitempty"`
+	// MinUnits and MaxUnits limit the number of units of each process of
+	// apps using the plan, zero means no limit.
+	MinUnits uint `json:"minUnits,omitempty"`
+	MaxUnits uint `json:"maxUnits,omitempty"`
+	// GPU is the number of GPUs of each unit of apps using the plan and
+	// GPUType, when set, the type of these GPUs, like nvidia-tesla-t4.
+	GPU     int    `json:"gpu,omitempty"`
+	GPUType string `json:"gpuType,omitempty"`
 }
 
 type PlanOverride struct {
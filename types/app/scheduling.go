@@ -0,0 +1,77 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"fmt"
+
+	"github.com/tsuru/tsuru/errors"
+	apiv1 "k8s.io/api/core/v1"
+)
+
+// Scheduling holds the tolerations, affinity and topology spread
+// constraints of the units of an app, merged with the ones of its pool by the
+// provisioner.
+type Scheduling struct {
+	Tolerations               []apiv1.Toleration               `json:"tolerations,omitempty"`
+	Affinity                  *apiv1.Affinity                  `json:"affinity,omitempty"`
+	TopologySpreadConstraints []apiv1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+}
+
+func (s *Scheduling) Empty() bool {
+	return s == nil || (len(s.Tolerations) == 0 && s.Affinity == nil && len(s.TopologySpreadConstraints) == 0)
+}
+
+func (s *Scheduling) Validate() error {
+	if s == nil {
+		return nil
+	}
+	for _, t := range s.Tolerations {
+		switch t.Operator {
+		case "", apiv1.TolerationOpEqual:
+		case apiv1.TolerationOpExists:
+			if t.Value != "" {
+				return &errors.ValidationError{Message: fmt.Sprintf("toleration %q with operator Exists must not have a value", t.Key)}
+			}
+		default:
+			return &errors.ValidationError{Message: fmt.Sprintf("invalid toleration operator %q", t.Operator)}
+		}
+		switch t.Effect {
+		case "", apiv1.TaintEffectNoSchedule, apiv1.TaintEffectPreferNoSchedule, apiv1.TaintEffectNoExecute:
+		default:
+			return &errors.ValidationError{Message: fmt.Sprintf("invalid toleration effect %q", t.Effect)}
+		}
+		if t.Key == "" && t.Operator != apiv1.TolerationOpExists {
+			return &errors.ValidationError{Message: "tolerations without key must use the Exists operator"}
+		}
+	}
+	return ValidateTopologySpreadConstraints(s.TopologySpreadConstraints)
+}
+
+// ValidateTopologySpreadConstraints checks the constraints spreading units
+// across topology domains, like zones or nodes. Their label selector may be
+// left empty, it's filled by the provisioner with the labels of the units of
+// each process.
+func ValidateTopologySpreadConstraints(constraints []apiv1.TopologySpreadConstraint) error {
+	keys := map[string]struct{}{}
+	for _, tsc := range constraints {
+		if tsc.TopologyKey == "" {
+			return &errors.ValidationError{Message: "topology spread constraints require a topology key"}
+		}
+		if _, ok := keys[tsc.TopologyKey]; ok {
+			return &errors.ValidationError{Message: fmt.Sprintf("duplicated topology spread constraint for topology key %q", tsc.TopologyKey)}
+		}
+		keys[tsc.TopologyKey] = struct{}{}
+		if tsc.MaxSkew < 1 {
+			return &errors.ValidationError{Message: fmt.Sprintf("max skew of topology spread constraint %q must be greater than zero", tsc.TopologyKey)}
+		}
+		switch tsc.WhenUnsatisfiable {
+		case apiv1.DoNotSchedule, apiv1.ScheduleAnyway:
+		default:
+			return &errors.ValidationError{Message: fmt.Sprintf("invalid whenUnsatisfiable %q of topology spread constraint %q, it must be DoNotSchedule or ScheduleAnyway", tsc.WhenUnsatisfiable, tsc.TopologyKey)}
+		}
+	}
+	return nil
+}
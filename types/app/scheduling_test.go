@@ -0,0 +1,85 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+import (
+	"github.com/tsuru/tsuru/errors"
+	"gopkg.in/check.v1"
+	apiv1 "k8s.io/api/core/v1"
+)
+
+func (s S) TestSchedulingEmpty(c *check.C) {
+	var nilScheduling *Scheduling
+	c.Assert(nilScheduling.Empty(), check.Equals, true)
+	c.Assert((&Scheduling{}).Empty(), check.Equals, true)
+	c.Assert((&Scheduling{Affinity: &apiv1.Affinity{}}).Empty(), check.Equals, false)
+	c.Assert((&Scheduling{Tolerations: []apiv1.Toleration{{Key: "k", Operator: apiv1.TolerationOpExists}}}).Empty(), check.Equals, false)
+	c.Assert((&Scheduling{TopologySpreadConstraints: []apiv1.TopologySpreadConstraint{{TopologyKey: "zone"}}}).Empty(), check.Equals, false)
+}
+
+func (s S) TestSchedulingValidate(c *check.C) {
+	tests := []struct {
+		toleration apiv1.Toleration
+		err        string
+	}{
+		{toleration: apiv1.Toleration{Key: "dedicated", Value: "v1", Effect: apiv1.TaintEffectNoSchedule}},
+		{toleration: apiv1.Toleration{Key: "dedicated", Operator: apiv1.TolerationOpEqual, Value: "v1"}},
+		{toleration: apiv1.Toleration{Operator: apiv1.TolerationOpExists}},
+		{toleration: apiv1.Toleration{Key: "gpu", Operator: apiv1.TolerationOpExists, Effect: apiv1.TaintEffectNoExecute}},
+		{toleration: apiv1.Toleration{Key: "gpu", Operator: apiv1.TolerationOpExists, Value: "v1"}, err: `toleration "gpu" with operator Exists must not have a value`},
+		{toleration: apiv1.Toleration{Key: "gpu", Operator: "Gt"}, err: `invalid toleration operator "Gt"`},
+		{toleration: apiv1.Toleration{Key: "gpu", Effect: "Evict"}, err: `invalid toleration effect "Evict"`},
+		{toleration: apiv1.Toleration{Value: "v1"}, err: "tolerations without key must use the Exists operator"},
+	}
+	for _, tt := range tests {
+		err := (&Scheduling{Tolerations: []apiv1.Toleration{tt.toleration}}).Validate()
+		if tt.err == "" {
+			c.Check(err, check.IsNil)
+			continue
+		}
+		c.Check(err, check.FitsTypeOf, &errors.ValidationError{})
+		c.Check(err, check.ErrorMatches, tt.err)
+	}
+}
+
+func (s S) TestSchedulingValidateTopologySpreadConstraints(c *check.C) {
+	tests := []struct {
+		constraints []apiv1.TopologySpreadConstraint
+		err         string
+	}{
+		{constraints: []apiv1.TopologySpreadConstraint{
+			{MaxSkew: 1, TopologyKey: "topology.kubernetes.io/zone", WhenUnsatisfiable: apiv1.DoNotSchedule},
+			{MaxSkew: 2, TopologyKey: "kubernetes.io/hostname", WhenUnsatisfiable: apiv1.ScheduleAnyway},
+		}},
+		{
+			constraints: []apiv1.TopologySpreadConstraint{{MaxSkew: 1, WhenUnsatisfiable: apiv1.DoNotSchedule}},
+			err:         "topology spread constraints require a topology key",
+		},
+		{
+			constraints: []apiv1.TopologySpreadConstraint{{TopologyKey: "zone", WhenUnsatisfiable: apiv1.DoNotSchedule}},
+			err:         `max skew of topology spread constraint "zone" must be greater than zero`,
+		},
+		{
+			constraints: []apiv1.TopologySpreadConstraint{{MaxSkew: 1, TopologyKey: "zone"}},
+			err:         `invalid whenUnsatisfiable "" of topology spread constraint "zone", it must be DoNotSchedule or ScheduleAnyway`,
+		},
+		{
+			constraints: []apiv1.TopologySpreadConstraint{
+				{MaxSkew: 1, TopologyKey: "zone", WhenUnsatisfiable: apiv1.DoNotSchedule},
+				{MaxSkew: 2, TopologyKey: "zone", WhenUnsatisfiable: apiv1.ScheduleAnyway},
+			},
+			err: `duplicated topology spread constraint for topology key "zone"`,
+		},
+	}
+	for _, tt := range tests {
+		err := (&Scheduling{TopologySpreadConstraints: tt.constraints}).Validate()
+		if tt.err == "" {
+			c.Check(err, check.IsNil)
+			continue
+		}
+		c.Check(err, check.FitsTypeOf, &errors.ValidationError{})
+		c.Check(err, check.ErrorMatches, tt.err)
+	}
+}
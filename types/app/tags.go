@@ -0,0 +1,23 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package app
+
+type TagOperator string
+
+const (
+	TagEquals       = TagOperator("=")
+	TagNotEquals    = TagOperator("!=")
+	TagExists       = TagOperator("exists")
+	TagDoesNotExist = TagOperator("!")
+)
+
+// TagRequirement is a requirement on the tags of apps. Tags in the form
+// "key=value" are matched by key and value, other tags are matched only by
+// their key.
+type TagRequirement struct {
+	Key      string
+	Operator TagOperator
+	Value    string
+}
@@ -57,6 +57,18 @@ type AddVersionDataArgs struct {
 	Processes    map[string][]string
 	CustomData   map[string]interface{}
 	ExposedPorts []string
+	EnvSnapshot  []VersionEnvVar
+}
+
+// VersionEnvVar is an environment variable of the app at the time a version
+// was deployed.
+type VersionEnvVar struct {
+	Name      string
+	Value     string
+	Alias     string
+	Public    bool
+	ManagedBy string
+	SecretRef bool
 }
 
 type AppVersions struct {
@@ -86,6 +98,9 @@ type AppVersionInfo struct {
 	DeploySuccessful bool                   `json:"deploySuccessful"`
 	MarkedToRemoval  bool                   `json:"markedToRemoval"`
 	PastUnits        map[string]int         `json:"pastUnits"`
+	// EnvSnapshot holds the app environment variables when the version was
+	// deployed, it's nil for versions never deployed.
+	EnvSnapshot []VersionEnvVar `json:"-"`
 }
 
 type NewVersionArgs struct {
@@ -123,4 +138,5 @@ type commonAppVersion interface {
 	DeleteVersionIDs(ctx context.Context, appName string, versions []int, opts ...*AppVersionWriteOptions) error
 	MarkToRemoval(ctx context.Context, appName string, opts ...*AppVersionWriteOptions) error
 	MarkVersionsToRemoval(ctx context.Context, appName string, versions []int, opts ...*AppVersionWriteOptions) error
+	RenameApp(ctx context.Context, oldName, newName string) error
 }
@@ -0,0 +1,58 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package audit
+
+import (
+	"context"
+	"time"
+)
+
+// Entry is the record of an authenticated call to the tsuru API.
+type Entry struct {
+	Time time.Time
+	// Method and Path identify the called route, Path is the route template,
+	// e.g. /apps/{app}, while URL is the requested path.
+	Method    string
+	Path      string
+	URL       string
+	Status    int
+	Latency   time.Duration
+	SourceIP  string
+	RequestID string `bson:",omitempty"`
+	// Owner is the user email or the team token name of the caller.
+	Owner string
+	// TokenID identifies the token used in the call without exposing it, it's
+	// a prefix of the token SHA-256 hash.
+	TokenID string
+	// Impersonator is the admin acting as the owner with an impersonation
+	// token.
+	Impersonator string `bson:",omitempty"`
+}
+
+type Filter struct {
+	Owner        string
+	TokenID      string
+	Impersonator string
+	Method       string
+	Path         string
+	Status       int
+	Since        time.Time
+	Until        time.Time
+	Limit        int
+	Skip         int
+}
+
+type AuditService interface {
+	Add(ctx context.Context, entry Entry) error
+	List(ctx context.Context, f *Filter) ([]Entry, error)
+	// RemoveExpired removes the entries older than the configured retention.
+	RemoveExpired(ctx context.Context) (int, error)
+}
+
+type AuditStorage interface {
+	Insert(ctx context.Context, entry Entry) error
+	List(ctx context.Context, f *Filter) ([]Entry, error)
+	RemoveBefore(ctx context.Context, t time.Time) (int, error)
+}
@@ -0,0 +1,56 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ServiceAccount is a non-human identity owned by a team. Its roles are
+// granted to all the team tokens issued for it, so automation doesn't depend
+// on the user that configured it.
+type ServiceAccount struct {
+	Name         string         `json:"name"`
+	Team         string         `json:"team"`
+	Description  string         `json:"description"`
+	CreatedAt    time.Time      `json:"created_at"`
+	CreatorEmail string         `json:"creator_email"`
+	Roles        []RoleInstance `json:"roles,omitempty"`
+}
+
+type ServiceAccountCreateArgs struct {
+	Name        string `json:"name" form:"name"`
+	Team        string `json:"team" form:"team"`
+	Description string `json:"description" form:"description"`
+}
+
+type ServiceAccountStorage interface {
+	Insert(context.Context, ServiceAccount) error
+	FindByName(ctx context.Context, name string) (*ServiceAccount, error)
+	FindByTeams(ctx context.Context, teams []string) ([]ServiceAccount, error)
+	AddRole(ctx context.Context, name string, role RoleInstance) error
+	RemoveRole(ctx context.Context, name string, role RoleInstance) error
+	Delete(ctx context.Context, name string) error
+}
+
+type ServiceAccountService interface {
+	Create(ctx context.Context, args ServiceAccountCreateArgs, token Token) (ServiceAccount, error)
+	FindByName(ctx context.Context, name string) (ServiceAccount, error)
+	List(ctx context.Context, teams []string) ([]ServiceAccount, error)
+	AddRole(ctx context.Context, name, roleName, contextValue string) error
+	RemoveRole(ctx context.Context, name, roleName, contextValue string) error
+	Delete(ctx context.Context, name string) error
+}
+
+var (
+	ErrServiceAccountAlreadyExists = errors.New("service account already exists")
+	ErrServiceAccountNotFound      = errors.New("service account not found")
+	ErrServiceAccountHasTokens     = errors.New("cannot remove service account with tokens")
+	ErrServiceAccountOwnsApps      = errors.New("cannot remove service account who owns apps")
+	ErrServiceAccountTokenRoles    = errors.New("roles of service account tokens must be assigned to the service account")
+	ErrServiceAccountTeamMismatch  = errors.New("team tokens must belong to the team of their service account")
+)
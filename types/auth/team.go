@@ -20,6 +20,16 @@ type Team struct {
 	CreatingUser string      `json:"creatingUser"`
 	Tags         []string    `json:"tags"`
 	Quota        quota.Quota `json:"quota"`
+	// JobUnitsQuota and JobMemoryQuota limit the jobs of the team,
+	// independently from the quota of its apps.
+	JobUnitsQuota  quota.Quota `json:"jobUnitsQuota"`
+	JobMemoryQuota quota.Quota `json:"jobMemoryQuota"`
+	// AllowedCIDRs restricts the addresses from which tokens of the team
+	// may call the API, an empty list allows any address.
+	AllowedCIDRs []string `json:"allowedCIDRs,omitempty"`
+	// Parent is the name of the team this team is nested in. Roles and
+	// quotas of the parent team also apply to its descendants.
+	Parent string `json:"parent,omitempty"`
 }
 
 func (t Team) GetName() string {
@@ -29,9 +39,13 @@ func (t Team) GetName() string {
 type TeamService interface {
 	Create(context.Context, string, []string, *User) error
 	Update(context.Context, string, []string) error
+	SetAllowedCIDRs(ctx context.Context, name string, cidrs []string) error
+	SetParent(ctx context.Context, name, parent string) error
 	List(context.Context) ([]Team, error)
 	FindByName(context.Context, string) (*Team, error)
 	FindByNames(context.Context, []string) ([]Team, error)
+	FindAncestors(ctx context.Context, name string) ([]Team, error)
+	FindDescendants(ctx context.Context, names ...string) ([]Team, error)
 	Remove(context.Context, string) error
 }
 
@@ -41,6 +55,7 @@ type TeamStorage interface {
 	FindAll(context.Context) ([]Team, error)
 	FindByName(context.Context, string) (*Team, error)
 	FindByNames(context.Context, []string) ([]Team, error)
+	FindByParents(context.Context, []string) ([]Team, error)
 	Delete(context.Context, Team) error
 }
 
@@ -51,4 +66,8 @@ var (
 	}
 	ErrTeamAlreadyExists = errors.New("team already exists")
 	ErrTeamNotFound      = errors.New("team not found")
+	ErrTeamParentCycle   = &tsuruErrors.ValidationError{
+		Message: "A team cannot be nested in itself or in one of its descendants.",
+	}
+	ErrTeamParentNotFound = &tsuruErrors.ValidationError{Message: "parent team not found"}
 )
@@ -11,12 +11,13 @@ var _ TeamService = &MockTeamService{}
 
 // MockTeamStorage implements TeamStorage interface
 type MockTeamStorage struct {
-	OnInsert      func(Team) error
-	OnUpdate      func(Team) error
-	OnFindAll     func() ([]Team, error)
-	OnFindByName  func(string) (*Team, error)
-	OnFindByNames func([]string) ([]Team, error)
-	OnDelete      func(Team) error
+	OnInsert        func(Team) error
+	OnUpdate        func(Team) error
+	OnFindAll       func() ([]Team, error)
+	OnFindByName    func(string) (*Team, error)
+	OnFindByNames   func([]string) ([]Team, error)
+	OnFindByParents func([]string) ([]Team, error)
+	OnDelete        func(Team) error
 }
 
 func (m *MockTeamStorage) Insert(ctx context.Context, t Team) error {
@@ -39,17 +40,25 @@ func (m *MockTeamStorage) FindByNames(ctx context.Context, names []string) ([]Te
 	return m.OnFindByNames(names)
 }
 
+func (m *MockTeamStorage) FindByParents(ctx context.Context, parents []string) ([]Team, error) {
+	return m.OnFindByParents(parents)
+}
+
 func (m *MockTeamStorage) Delete(ctx context.Context, t Team) error {
 	return m.OnDelete(t)
 }
 
 type MockTeamService struct {
-	OnCreate      func(string, []string, *User) error
-	OnUpdate      func(string, []string) error
-	OnList        func() ([]Team, error)
-	OnFindByName  func(string) (*Team, error)
-	OnFindByNames func([]string) ([]Team, error)
-	OnRemove      func(string) error
+	OnCreate          func(string, []string, *User) error
+	OnUpdate          func(string, []string) error
+	OnSetAllowedCIDRs func(string, []string) error
+	OnSetParent       func(string, string) error
+	OnList            func() ([]Team, error)
+	OnFindByName      func(string) (*Team, error)
+	OnFindByNames     func([]string) ([]Team, error)
+	OnFindAncestors   func(string) ([]Team, error)
+	OnFindDescendants func([]string) ([]Team, error)
+	OnRemove          func(string) error
 }
 
 func (m *MockTeamService) Create(ctx context.Context, teamName string, tags []string, user *User) error {
@@ -66,6 +75,20 @@ func (m *MockTeamService) Update(ctx context.Context, teamName string, tags []st
 	return m.OnUpdate(teamName, tags)
 }
 
+func (m *MockTeamService) SetAllowedCIDRs(ctx context.Context, teamName string, cidrs []string) error {
+	if m.OnSetAllowedCIDRs == nil {
+		return nil
+	}
+	return m.OnSetAllowedCIDRs(teamName, cidrs)
+}
+
+func (m *MockTeamService) SetParent(ctx context.Context, teamName, parent string) error {
+	if m.OnSetParent == nil {
+		return nil
+	}
+	return m.OnSetParent(teamName, parent)
+}
+
 func (m *MockTeamService) List(ctx context.Context) ([]Team, error) {
 	if m.OnList == nil {
 		return nil, nil
@@ -87,6 +110,20 @@ func (m *MockTeamService) FindByNames(ctx context.Context, teamNames []string) (
 	return m.OnFindByNames(teamNames)
 }
 
+func (m *MockTeamService) FindAncestors(ctx context.Context, teamName string) ([]Team, error) {
+	if m.OnFindAncestors == nil {
+		return nil, nil
+	}
+	return m.OnFindAncestors(teamName)
+}
+
+func (m *MockTeamService) FindDescendants(ctx context.Context, teamNames ...string) ([]Team, error) {
+	if m.OnFindDescendants == nil {
+		return nil, nil
+	}
+	return m.OnFindDescendants(teamNames)
+}
+
 func (m *MockTeamService) Remove(ctx context.Context, teamName string) error {
 	if m.OnRemove == nil {
 		return nil
@@ -11,29 +11,63 @@ import (
 )
 
 type TeamTokenCreateArgs struct {
-	TokenID     string `json:"token_id" form:"token_id"`
-	Description string `json:"description" form:"description"`
-	ExpiresIn   int    `json:"expires_in" form:"expires_in"`
-	Team        string `json:"team" form:"team"`
+	TokenID     string   `json:"token_id" form:"token_id"`
+	Description string   `json:"description" form:"description"`
+	ExpiresIn   int      `json:"expires_in" form:"expires_in"`
+	Team        string   `json:"team" form:"team"`
+	Scopes      []string `json:"scopes" form:"scopes"`
+
+	ServiceAccount string `json:"service_account" form:"service_account"`
 }
 
 type TeamTokenUpdateArgs struct {
+	TokenID     string   `json:"token_id" form:"token_id"`
+	Regenerate  bool     `json:"regenerate" form:"regenerate"`
+	Description string   `json:"description" form:"description"`
+	ExpiresIn   int      `json:"expires_in" form:"expires_in"`
+	Scopes      []string `json:"scopes" form:"scopes"`
+	ClearScopes bool     `json:"clear_scopes" form:"clear_scopes"`
+}
+
+// TeamTokenRotateArgs are the arguments to issue a new secret for a team
+// token. The previous secret remains valid for GracePeriod seconds, a negative
+// value revokes it immediately and zero uses the configured default.
+type TeamTokenRotateArgs struct {
 	TokenID     string `json:"token_id" form:"token_id"`
-	Regenerate  bool   `json:"regenerate" form:"regenerate"`
-	Description string `json:"description" form:"description"`
 	ExpiresIn   int    `json:"expires_in" form:"expires_in"`
+	GracePeriod int    `json:"grace_period" form:"grace_period"`
+}
+
+// TeamTokenScope restricts a team token to a permission in a context, e.g.
+// app.deploy for the app myapp. Tokens with scopes are only allowed to do what
+// both their roles and one of their scopes allow.
+type TeamTokenScope struct {
+	Permission   string `json:"permission"`
+	ContextType  string `json:"context_type"`
+	ContextValue string `json:"context_value,omitempty"`
 }
 
 type TeamToken struct {
-	Token        string         `json:"token"`
-	TokenID      string         `json:"token_id"`
-	Description  string         `json:"description"`
-	CreatedAt    time.Time      `json:"created_at"`
-	ExpiresAt    time.Time      `json:"expires_at"`
-	LastAccess   time.Time      `json:"last_access"`
-	CreatorEmail string         `json:"creator_email"`
-	Team         string         `json:"team"`
-	Roles        []RoleInstance `json:"roles,omitempty"`
+	Token        string           `json:"token"`
+	TokenID      string           `json:"token_id"`
+	Description  string           `json:"description"`
+	CreatedAt    time.Time        `json:"created_at"`
+	ExpiresAt    time.Time        `json:"expires_at"`
+	LastAccess   time.Time        `json:"last_access"`
+	CreatorEmail string           `json:"creator_email"`
+	Team         string           `json:"team"`
+	Roles        []RoleInstance   `json:"roles,omitempty"`
+	AllowedCIDRs []string         `json:"allowed_cidrs,omitempty"`
+	Scopes       []TeamTokenScope `json:"scopes,omitempty"`
+
+	// ServiceAccount is the name of the service account the token acts on
+	// behalf of, whose roles are used instead of the token ones.
+	ServiceAccount string `json:"service_account,omitempty"`
+
+	PreviousToken          string    `json:"-"`
+	PreviousTokenExpiresAt time.Time `json:"previous_token_expires_at"`
+	RotatedAt              time.Time `json:"rotated_at"`
+	ExpirationWarnedAt     time.Time `json:"-"`
 }
 
 type TeamTokenStorage interface {
@@ -41,6 +75,8 @@ type TeamTokenStorage interface {
 	FindByTokenID(ctx context.Context, tokenID string) (*TeamToken, error)
 	FindByToken(ctx context.Context, token string) (*TeamToken, error)
 	FindByTeams(ctx context.Context, teams []string) ([]TeamToken, error)
+	FindByServiceAccount(ctx context.Context, serviceAccount string) ([]TeamToken, error)
+	FindExpiringBefore(ctx context.Context, t time.Time) ([]TeamToken, error)
 	UpdateLastAccess(ctx context.Context, token string) error
 	Update(context.Context, TeamToken) error
 	Delete(ctx context.Context, tokenID string) error
@@ -50,12 +86,16 @@ type TeamTokenService interface {
 	Create(ctx context.Context, args TeamTokenCreateArgs, token Token) (TeamToken, error)
 	Info(ctx context.Context, tokenID string, token Token) (TeamToken, error)
 	Update(ctx context.Context, args TeamTokenUpdateArgs, token Token) (TeamToken, error)
+	Rotate(ctx context.Context, args TeamTokenRotateArgs, token Token) (TeamToken, error)
 	Delete(ctx context.Context, tokenID string) error
 	Authenticate(ctx context.Context, header string) (Token, error)
 	FindByTokenID(ctx context.Context, tokenID string) (TeamToken, error)
 	FindByUserToken(ctx context.Context, t Token) ([]TeamToken, error)
+	SetAllowedCIDRs(ctx context.Context, tokenID string, cidrs []string) error
 	AddRole(ctx context.Context, tokenID string, roleName, contextValue string) error
 	RemoveRole(ctx context.Context, tokenID string, roleName, contextValue string) error
+	FindExpiring(ctx context.Context, within time.Duration) ([]TeamToken, error)
+	SetExpirationWarned(ctx context.Context, tokenID string) error
 }
 
 var (
@@ -18,3 +18,16 @@ type Token interface {
 type NamedToken interface {
 	GetTokenName() string
 }
+
+// ServiceAccountToken is implemented by tokens that may act on behalf of a
+// service account, GetServiceAccountName returns an empty string otherwise.
+type ServiceAccountToken interface {
+	GetServiceAccountName() string
+}
+
+// ImpersonatedToken is implemented by tokens that may be used by an admin
+// acting as another user, GetImpersonator returns the email of the admin or
+// an empty string otherwise.
+type ImpersonatedToken interface {
+	GetImpersonator() string
+}
@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/tsuru/tsuru/types/quota"
 )
@@ -19,6 +20,9 @@ type User struct {
 	APIKey   string
 	Roles    []RoleInstance
 	Groups   []string
+	// Origin identifies the identity provider the user was registered
+	// through, like "oidc:github".
+	Origin string
 	// FromToken denotes whether the user was generated from team token.
 	// In other words, it does not exist in the storage.
 	FromToken bool
@@ -27,11 +31,21 @@ type User struct {
 type RoleInstance struct {
 	Name         string
 	ContextValue string
+	// ExpiresAt is set for temporary role assignments, which stop granting
+	// permissions once expired and are then revoked.
+	ExpiresAt *time.Time `json:",omitempty" bson:",omitempty"`
+}
+
+// Expired returns whether the role instance is a temporary assignment past
+// its expiration.
+func (r RoleInstance) Expired(now time.Time) bool {
+	return r.ExpiresAt != nil && !now.Before(*r.ExpiresAt)
 }
 
 type ErrTeamStillUsed struct {
 	Apps             []string
 	ServiceInstances []string
+	Teams            []string
 }
 
 var (
@@ -45,5 +59,8 @@ func (e *ErrTeamStillUsed) Error() string {
 	if len(e.Apps) > 0 {
 		return fmt.Sprintf("Apps: %s", strings.Join(e.Apps, ", "))
 	}
-	return fmt.Sprintf("Service instances: %s", strings.Join(e.ServiceInstances, ", "))
+	if len(e.ServiceInstances) > 0 {
+		return fmt.Sprintf("Service instances: %s", strings.Join(e.ServiceInstances, ", "))
+	}
+	return fmt.Sprintf("Teams: %s", strings.Join(e.Teams, ", "))
 }
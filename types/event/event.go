@@ -0,0 +1,277 @@
+// Copyright 2026 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package event
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/globalsign/mgo/bson"
+	permTypes "github.com/tsuru/tsuru/types/permission"
+	"github.com/tsuru/tsuru/types/tracker"
+)
+
+var (
+	ErrEventNotFound  = errors.New("event not found")
+	ErrEventIDInUse   = errors.New("event id already in use")
+	ErrInvalidGroupBy = errors.New("invalid group by, must be one of: kind, target, team")
+)
+
+const (
+	SummaryGroupByKind       = "kind"
+	SummaryGroupByTargetType = "target"
+	SummaryGroupByTeam       = "team"
+)
+
+type TargetType string
+
+type OwnerType string
+
+type KindType string
+
+type Target struct {
+	Type  TargetType
+	Value string
+}
+
+func (id Target) GetBSON() (interface{}, error) {
+	return bson.D{{Name: "type", Value: id.Type}, {Name: "value", Value: id.Value}}, nil
+}
+
+func (id Target) IsValid() bool {
+	return id.Type != ""
+}
+
+func (id Target) String() string {
+	return fmt.Sprintf("%s(%s)", id.Type, id.Value)
+}
+
+type ExtraTarget struct {
+	Target Target
+	Lock   bool
+}
+
+type Owner struct {
+	Type OwnerType
+	Name string
+}
+
+func (o Owner) String() string {
+	return fmt.Sprintf("%s %s", o.Type, o.Name)
+}
+
+type Kind struct {
+	Type KindType
+	Name string
+}
+
+func (k Kind) String() string {
+	return k.Name
+}
+
+type AllowedPermission struct {
+	Scheme   string
+	Contexts []permTypes.PermissionContext `bson:",omitempty"`
+}
+
+func (ap *AllowedPermission) GetBSON() (interface{}, error) {
+	var ctxs []bson.D
+	for _, ctx := range ap.Contexts {
+		ctxs = append(ctxs, bson.D{
+			{Name: "ctxtype", Value: ctx.CtxType},
+			{Name: "value", Value: ctx.Value},
+		})
+	}
+	return bson.M{
+		"scheme":   ap.Scheme,
+		"contexts": ctxs,
+	}, nil
+}
+
+// EventID identifies a stored event. Events holding a lock on their target
+// are identified by the target itself, so only one of them may be running at
+// a time, other events are identified by their unique ID.
+type EventID struct {
+	Target Target
+	ObjId  bson.ObjectId
+}
+
+func (id *EventID) SetBSON(raw bson.Raw) error {
+	err := raw.Unmarshal(&id.Target)
+	if err != nil {
+		return raw.Unmarshal(&id.ObjId)
+	}
+	return nil
+}
+
+func (id EventID) GetBSON() (interface{}, error) {
+	if len(id.ObjId) != 0 {
+		return id.ObjId, nil
+	}
+	return id.Target.GetBSON()
+}
+
+type LogEntry struct {
+	Date    time.Time
+	Message string
+}
+
+type CancelInfo struct {
+	Owner     string
+	StartTime time.Time
+	AckTime   time.Time
+	Reason    string
+	Asked     bool
+	Canceled  bool
+}
+
+// Changes holds normalized snapshots of the event target before and after
+// the update performed by the event.
+type Changes struct {
+	Before map[string]interface{}
+	After  map[string]interface{}
+}
+
+// EventData is the stored representation of an event.
+type EventData struct {
+	ID              EventID `bson:"_id"`
+	UniqueID        bson.ObjectId
+	ParentID        bson.ObjectId `bson:",omitempty"`
+	StartTime       time.Time
+	EndTime         time.Time     `bson:",omitempty"`
+	Target          Target        `bson:",omitempty"`
+	ExtraTargets    []ExtraTarget `bson:",omitempty"`
+	StartCustomData bson.Raw      `bson:",omitempty"`
+	EndCustomData   bson.Raw      `bson:",omitempty"`
+	OtherCustomData bson.Raw      `bson:",omitempty"`
+	Changes         *Changes      `bson:",omitempty"`
+	Kind            Kind
+	Owner           Owner
+	SourceIP        string
+	LockUpdateTime  time.Time
+	Error           string
+	Log             string     `bson:",omitempty"`
+	StructuredLog   []LogEntry `bson:",omitempty"`
+	CancelInfo      CancelInfo
+	Cancelable      bool
+	Running         bool
+	Allowed         AllowedPermission
+	AllowedCancel   AllowedPermission
+	Instance        tracker.TrackedInstance
+	// Impersonator is the admin acting as the owner, when the event was
+	// created with an impersonation token.
+	Impersonator string `bson:",omitempty"`
+}
+
+type TargetFilter struct {
+	Type   TargetType
+	Values []string
+}
+
+// EventCursor points to the last event of a page, events are listed from
+// the newest to the oldest one.
+type EventCursor struct {
+	StartTime time.Time
+	UniqueID  bson.ObjectId
+}
+
+// EventFilter holds the conditions used to find events. AllowedTargets and
+// Permissions restrict the events to the ones the caller is allowed to see,
+// a nil list means no restriction while an empty one matches nothing.
+// Permissions without contexts match events allowed for any context.
+type EventFilter struct {
+	Target         Target
+	KindType       KindType
+	KindNames      []string
+	OwnerType      OwnerType
+	OwnerName      string
+	Since          time.Time
+	Until          time.Time
+	Running        *bool
+	Cancelable     *bool
+	ErrorOnly      bool
+	Search         string
+	AllowedTargets []TargetFilter
+	Permissions    []AllowedPermission
+	Impersonator   string
+	UniqueIDs      []bson.ObjectId
+	After          *EventCursor
+
+	Limit int
+	Skip  int
+	Sort  string
+}
+
+// ThrottlingFilter selects the events counted by a throttling spec. Events
+// started after Since are counted, along with the events still running with
+// a lock updated after RunningSince when it's set. An empty TargetValue
+// matches any target of the type.
+type ThrottlingFilter struct {
+	TargetType   TargetType
+	TargetValue  string
+	KindName     string
+	Owner        *Owner
+	Since        time.Time
+	RunningSince time.Time
+}
+
+// SummaryEntry holds the number of events, and how many of them failed, in a
+// summary group.
+type SummaryEntry struct {
+	Key          string  `json:"key" bson:"_id"`
+	Count        int     `json:"count"`
+	Failures     int     `json:"failures"`
+	FailureRatio float64 `json:"failureRatio" bson:"-"`
+}
+
+type EventStorage interface {
+	// Insert stores a new event, returning ErrEventIDInUse when another
+	// event is stored with the same ID.
+	Insert(ctx context.Context, evt *EventData) error
+	// Update replaces the stored event with the same ID.
+	Update(ctx context.Context, evt *EventData) error
+	Remove(ctx context.Context, id EventID) error
+	FindByID(ctx context.Context, id EventID) (*EventData, error)
+	FindByUniqueID(ctx context.Context, id bson.ObjectId) (*EventData, error)
+	FindRunning(ctx context.Context, target Target, kindName string) (*EventData, error)
+	// FindLocking returns a running event, other than the one with the
+	// given unique ID, holding a lock on any of the targets.
+	FindLocking(ctx context.Context, targets []Target, ignoredID bson.ObjectId) (*EventData, error)
+	FindRunningByIDs(ctx context.Context, ids []EventID) ([]EventData, error)
+	FindRunningChildren(ctx context.Context, parentID bson.ObjectId) ([]EventData, error)
+	// FindExpired returns the running events whose lock was last updated
+	// before the given time.
+	FindExpired(ctx context.Context, lockUpdatedBefore time.Time) ([]EventData, error)
+	FindAll(ctx context.Context, filter *EventFilter) ([]EventData, error)
+	CountThrottled(ctx context.Context, filter ThrottlingFilter) (int, error)
+	Kinds(ctx context.Context) ([]Kind, error)
+	// Summary counts the events matching the filter grouped by kind name,
+	// target type or team. Running events are never counted as failures.
+	Summary(ctx context.Context, filter *EventFilter, groupBy string) ([]SummaryEntry, error)
+	UpdateLockTime(ctx context.Context, ids []EventID, now time.Time) error
+	SetOtherCustomData(ctx context.Context, id EventID, data interface{}) error
+	// SetStructuredLog replaces the log of a running event, finished events
+	// are ignored.
+	SetStructuredLog(ctx context.Context, id EventID, entries []LogEntry) error
+	SetChanges(ctx context.Context, id EventID, changes Changes) error
+	// RequestCancel stores the cancel request in the event, returning
+	// ErrEventNotFound when the event is not found or its cancel was
+	// already requested.
+	RequestCancel(ctx context.Context, id EventID, info CancelInfo) (*EventData, error)
+	// AckCancel marks an event with a cancel request as canceled, returning
+	// ErrEventNotFound when no cancel was requested.
+	AckCancel(ctx context.Context, id EventID, ackTime time.Time) (*EventData, error)
+	// ReassignOwner changes the owner of every event owned by from,
+	// returning the number of updated events.
+	ReassignOwner(ctx context.Context, from, to Owner) (int, error)
+	// RenameTarget changes the target, or extra target, of every finished
+	// event targeting from.
+	RenameTarget(ctx context.Context, from, to Target) error
+	// RenameAllowedContext changes the contexts required to read and cancel
+	// finished events.
+	RenameAllowedContext(ctx context.Context, from, to permTypes.PermissionContext) error
+}
@@ -7,11 +7,13 @@ package event
 import (
 	"errors"
 	"net/http"
+	"time"
 )
 
 var (
 	ErrWebhookAlreadyExists = errors.New("webhook already exists with the same name")
 	ErrWebhookNotFound      = errors.New("webhook not found")
+	ErrDeadLetterNotFound   = errors.New("webhook dead letter not found")
 )
 
 type WebhookEventFilter struct {
@@ -36,6 +38,17 @@ type Webhook struct {
 	Insecure    bool               `json:"insecure" form:"insecure"`
 }
 
+// WebhookDeadLetter represents a webhook delivery that failed after all
+// retry attempts were exhausted.
+type WebhookDeadLetter struct {
+	ID        string    `json:"id" bson:"_id"`
+	Webhook   string    `json:"webhook"`
+	EventID   string    `json:"event_id"`
+	Attempts  int       `json:"attempts"`
+	Error     string    `json:"error"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
 type WebhookService interface {
 	Notify(evtID string)
 	Create(Webhook) error
@@ -43,6 +56,10 @@ type WebhookService interface {
 	Delete(string) error
 	Find(string) (Webhook, error)
 	List([]string) ([]Webhook, error)
+	DeadLetters(webhookName string) ([]WebhookDeadLetter, error)
+	Redispatch(webhookName, deadLetterID string) error
+	Dispatch(webhookName, eventID string) error
+	TestFire(webhookName, owner string) error
 }
 
 type WebhookStorage interface {
@@ -52,4 +69,8 @@ type WebhookStorage interface {
 	FindByName(string) (*Webhook, error)
 	FindByEvent(f WebhookEventFilter, isSuccess bool) ([]Webhook, error)
 	Delete(string) error
+	InsertDeadLetter(WebhookDeadLetter) error
+	FindDeadLetters(webhookName string) ([]WebhookDeadLetter, error)
+	FindDeadLetter(id string) (*WebhookDeadLetter, error)
+	DeleteDeadLetter(id string) error
 }
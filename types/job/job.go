@@ -0,0 +1,99 @@
+// Copyright 2022 tsuru authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package job
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+	appTypes "github.com/tsuru/tsuru/types/app"
+)
+
+var (
+	ErrJobNotFound      = errors.New("job not found")
+	ErrJobAlreadyExists = errors.New("a job with the same name already exists")
+)
+
+// ConcurrencyPolicy defines how concurrent runs of a cronjob are handled.
+type ConcurrencyPolicy string
+
+const (
+	// ConcurrencyForbid skips a scheduled run while the previous one is
+	// still running, it's the default policy.
+	ConcurrencyForbid = ConcurrencyPolicy("Forbid")
+	// ConcurrencyAllow allows scheduled runs to run concurrently.
+	ConcurrencyAllow = ConcurrencyPolicy("Allow")
+	// ConcurrencyReplace replaces the running run by the scheduled one.
+	ConcurrencyReplace = ConcurrencyPolicy("Replace")
+)
+
+// Job is a one-off or scheduled batch workload, running its own image with
+// its own plan and envs, independently from any app.
+type Job struct {
+	Name      string `bson:"_id"`
+	TeamOwner string
+	Pool      string
+	Plan      appTypes.Plan
+	Owner     string
+	Image     string
+	Command   []string          `bson:",omitempty"`
+	Envs      map[string]string `bson:",omitempty"`
+	// Schedule is a cron expression defining when the job runs, one-off jobs
+	// have no schedule and run once when they're created.
+	Schedule string `bson:",omitempty"`
+	// ConcurrencyPolicy defines how concurrent scheduled runs of cronjobs
+	// are handled, ConcurrencyForbid is used when it's empty.
+	ConcurrencyPolicy ConcurrencyPolicy `bson:",omitempty"`
+	Retry             RetryPolicy
+	CreatedAt         time.Time
+}
+
+// RetryPolicy defines how failed runs of a job are retried.
+type RetryPolicy struct {
+	// Limit is the number of times a failed run is retried, failed runs are
+	// not retried when it's zero.
+	Limit int `bson:",omitempty"`
+	// BackoffSeconds is the delay before the first retry, doubled at each
+	// subsequent retry. Scheduled runs of cronjobs are retried by the
+	// provisioner, using its own backoff.
+	BackoffSeconds int `bson:",omitempty"`
+}
+
+// IsCron returns whether the job runs periodically.
+func (j *Job) IsCron() bool {
+	return j.Schedule != ""
+}
+
+// GetConcurrencyPolicy returns the concurrency policy of the cronjob.
+func (j *Job) GetConcurrencyPolicy() ConcurrencyPolicy {
+	if j.ConcurrencyPolicy == "" {
+		return ConcurrencyForbid
+	}
+	return j.ConcurrencyPolicy
+}
+
+type Filter struct {
+	Names []string
+	Teams []string
+	Pools []string
+}
+
+type JobService interface {
+	Create(ctx context.Context, job *Job) error
+	Get(ctx context.Context, name string) (*Job, error)
+	List(ctx context.Context, f *Filter) ([]Job, error)
+	Run(ctx context.Context, job *Job, w io.Writer) error
+	Trigger(ctx context.Context, job *Job) error
+	Remove(ctx context.Context, job *Job) error
+}
+
+type JobStorage interface {
+	Insert(ctx context.Context, job Job) error
+	Get(ctx context.Context, name string) (*Job, error)
+	List(ctx context.Context, f *Filter) ([]Job, error)
+	Remove(ctx context.Context, name string) error
+}
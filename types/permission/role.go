@@ -17,6 +17,11 @@ var (
 	ErrInvalidPermissionName = errors.New("invalid permission name")
 	ErrRemoveRoleWithUsers   = errors.New("role has users assigned. you must dissociate them before remove the role.")
 
+	ErrRoleTemplateNotFound      = errors.New("role template not found")
+	ErrRoleTemplateAlreadyExists = errors.New("role template already exists")
+	ErrInvalidRoleTemplateName   = errors.New("invalid role template name")
+	ErrRemoveBuiltinRoleTemplate = errors.New("built-in role templates can't be removed")
+
 	RoleEventUserCreate = &RoleEvent{
 		Name:        "user-create",
 		Context:     CtxGlobal,
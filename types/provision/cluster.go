@@ -8,6 +8,7 @@ import (
 	"context"
 	"errors"
 	"io"
+	"time"
 
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 )
@@ -26,6 +27,12 @@ type Cluster struct {
 	Default     bool              `json:"default"`
 	KubeConfig  *KubeConfig       `json:"kubeConfig,omitempty"`
 	HTTPProxy   string            `json:"httpProxy,omitempty"`
+	// StandbyPools are the pools served by the cluster while the clusters
+	// serving them are unavailable.
+	StandbyPools []string `json:"standbyPools,omitempty"`
+	// Unavailable is set on clusters under maintenance or failing, their
+	// pools are served by the clusters having them as standby pools.
+	Unavailable bool `json:"unavailable,omitempty"`
 
 	Writer io.Writer `json:"-"`
 }
@@ -35,6 +42,30 @@ type KubeConfig struct {
 	AuthInfo clientcmdapi.AuthInfo `json:"user"`
 }
 
+// ClusterStatus is the result of the diagnostics of a cluster, evaluated
+// periodically by tsuru.
+type ClusterStatus struct {
+	Reachable  bool                     `json:"reachable"`
+	Version    string                   `json:"version,omitempty"`
+	Error      string                   `json:"error,omitempty"`
+	Nodes      ClusterNodesStatus       `json:"nodes"`
+	Components []ClusterComponentStatus `json:"components,omitempty"`
+	CheckedAt  time.Time                `json:"checkedAt"`
+}
+
+type ClusterNodesStatus struct {
+	Total int `json:"total"`
+	Ready int `json:"ready"`
+}
+
+// ClusterComponentStatus reports whether a component required by tsuru, like
+// an ingress controller or the metrics API, is available in the cluster.
+type ClusterComponentStatus struct {
+	Name    string `json:"name"`
+	OK      bool   `json:"ok"`
+	Message string `json:"message,omitempty"`
+}
+
 type ClusterHelpInfo struct {
 	ProvisionerHelp string            `json:"provisioner_help"`
 	CustomDataHelp  map[string]string `json:"custom_data_help"`
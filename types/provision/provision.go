@@ -10,11 +10,17 @@ type TsuruYamlData struct {
 	Hooks       *TsuruYamlHooks            `json:"hooks,omitempty" bson:",omitempty"`
 	Healthcheck *TsuruYamlHealthcheck      `json:"healthcheck,omitempty" bson:",omitempty"`
 	Kubernetes  *TsuruYamlKubernetesConfig `json:"kubernetes,omitempty" bson:",omitempty"`
+	Shutdown    TsuruYamlShutdown          `json:"shutdown,omitempty" bson:",omitempty"`
+	Sidecars    []TsuruYamlSidecar         `json:"sidecars,omitempty" bson:",omitempty"`
+	// InitContainers run, in order, before the processes of the app in each
+	// unit.
+	InitContainers []TsuruYamlInitContainer `json:"init_containers,omitempty" yaml:"init_containers" bson:"init_containers,omitempty"`
 }
 
 type TsuruYamlHooks struct {
-	Restart TsuruYamlRestartHooks `json:"restart" bson:",omitempty"`
-	Build   []string              `json:"build" bson:",omitempty"`
+	Restart   TsuruYamlRestartHooks            `json:"restart" bson:",omitempty"`
+	Build     []string                         `json:"build" bson:",omitempty"`
+	Processes map[string]TsuruYamlProcessHooks `json:"processes,omitempty" bson:",omitempty"`
 }
 
 type TsuruYamlRestartHooks struct {
@@ -22,6 +28,157 @@ type TsuruYamlRestartHooks struct {
 	After  []string `json:"after" bson:",omitempty"`
 }
 
+// TsuruYamlProcessHooks holds the hooks of a single process, restart hooks
+// are run after the app wide ones.
+type TsuruYamlProcessHooks struct {
+	Restart    TsuruYamlRestartHooks `json:"restart" bson:",omitempty"`
+	PostDeploy []string              `json:"post_deploy,omitempty" yaml:"post_deploy" bson:"post_deploy,omitempty"`
+	PreStop    []string              `json:"pre_stop,omitempty" yaml:"pre_stop" bson:"pre_stop,omitempty"`
+}
+
+// RestartBefore returns the commands to be run before starting each unit of
+// the process.
+func (h *TsuruYamlHooks) RestartBefore(process string) []string {
+	if h == nil {
+		return nil
+	}
+	return joinHooks(h.Restart.Before, h.Processes[process].Restart.Before)
+}
+
+// RestartAfter returns the commands to be run after starting each unit of
+// the process.
+func (h *TsuruYamlHooks) RestartAfter(process string) []string {
+	if h == nil {
+		return nil
+	}
+	return joinHooks(h.Restart.After, h.Processes[process].Restart.After)
+}
+
+// PostDeploy returns the commands to be run once after the process is
+// deployed.
+func (h *TsuruYamlHooks) PostDeploy(process string) []string {
+	if h == nil {
+		return nil
+	}
+	return h.Processes[process].PostDeploy
+}
+
+// PreStop returns the commands to be run before stopping each unit of the
+// process.
+func (h *TsuruYamlHooks) PreStop(process string) []string {
+	if h == nil {
+		return nil
+	}
+	return h.Processes[process].PreStop
+}
+
+// TsuruYamlShutdown holds the shutdown configuration of each process.
+type TsuruYamlShutdown map[string]TsuruYamlProcessShutdown
+
+// TsuruYamlProcessShutdown holds the commands run before stopping each unit
+// of a process, and how long, in seconds, they may take to finish.
+type TsuruYamlProcessShutdown struct {
+	Commands       []string `json:"commands,omitempty" bson:"commands,omitempty"`
+	TimeoutSeconds int      `json:"timeout_seconds,omitempty" yaml:"timeout_seconds" bson:"timeout_seconds,omitempty"`
+}
+
+// Commands returns the commands to be run before stopping each unit of the
+// process.
+func (s TsuruYamlShutdown) Commands(process string) []string {
+	return s[process].Commands
+}
+
+// Timeout returns the time, in seconds, the shutdown commands of the process
+// may take to finish, or 0 when it's not set.
+func (s TsuruYamlShutdown) Timeout(process string) int {
+	return s[process].TimeoutSeconds
+}
+
+// TsuruYamlSidecar describes a container running alongside the processes of
+// the app in each unit, like proxies or log shippers.
+type TsuruYamlSidecar struct {
+	Name      string                    `json:"name"`
+	Image     string                    `json:"image"`
+	Command   []string                  `json:"command,omitempty" bson:"command,omitempty"`
+	Args      []string                  `json:"args,omitempty" bson:"args,omitempty"`
+	Env       map[string]string         `json:"env,omitempty" bson:"env,omitempty"`
+	Resources TsuruYamlSidecarResources `json:"resources,omitempty" bson:"resources,omitempty"`
+	Volumes   []TsuruYamlSidecarVolume  `json:"volumes,omitempty" bson:"volumes,omitempty"`
+	// Processes restricts the sidecar to units of the listed processes, it
+	// runs in units of every process when empty.
+	Processes []string `json:"processes,omitempty" bson:"processes,omitempty"`
+}
+
+// TsuruYamlSidecarResources holds the cpu and memory, in kubernetes quantity
+// format, reserved and limited to a sidecar.
+type TsuruYamlSidecarResources struct {
+	CPU    string `json:"cpu,omitempty" bson:"cpu,omitempty"`
+	Memory string `json:"memory,omitempty" bson:"memory,omitempty"`
+}
+
+// TsuruYamlSidecarVolume is an empty volume shared by a sidecar and the app,
+// mounted at the same path in both of them.
+type TsuruYamlSidecarVolume struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+}
+
+// TsuruYamlInitContainer describes a container run to completion before the
+// processes of the app start, like migrations runners or config fetchers. It
+// runs the image of the app when image isn't set.
+type TsuruYamlInitContainer struct {
+	Name      string                    `json:"name"`
+	Image     string                    `json:"image,omitempty" bson:"image,omitempty"`
+	Command   []string                  `json:"command,omitempty" bson:"command,omitempty"`
+	Args      []string                  `json:"args,omitempty" bson:"args,omitempty"`
+	Env       map[string]string         `json:"env,omitempty" bson:"env,omitempty"`
+	Resources TsuruYamlSidecarResources `json:"resources,omitempty" bson:"resources,omitempty"`
+	Volumes   []TsuruYamlSidecarVolume  `json:"volumes,omitempty" bson:"volumes,omitempty"`
+	Processes []string                  `json:"processes,omitempty" bson:"processes,omitempty"`
+}
+
+// InitContainersForProcess returns the init containers run in units of the
+// process.
+func (y TsuruYamlData) InitContainersForProcess(process string) []TsuruYamlInitContainer {
+	var containers []TsuruYamlInitContainer
+	for _, container := range y.InitContainers {
+		if runsInProcess(container.Processes, process) {
+			containers = append(containers, container)
+		}
+	}
+	return containers
+}
+
+// SidecarsForProcess returns the sidecars running in units of the process.
+func (y TsuruYamlData) SidecarsForProcess(process string) []TsuruYamlSidecar {
+	var sidecars []TsuruYamlSidecar
+	for _, sidecar := range y.Sidecars {
+		if runsInProcess(sidecar.Processes, process) {
+			sidecars = append(sidecars, sidecar)
+		}
+	}
+	return sidecars
+}
+
+func runsInProcess(processes []string, process string) bool {
+	if len(processes) == 0 {
+		return true
+	}
+	for _, p := range processes {
+		if p == process {
+			return true
+		}
+	}
+	return false
+}
+
+func joinHooks(global, process []string) []string {
+	if len(process) == 0 {
+		return global
+	}
+	return append(append([]string{}, global...), process...)
+}
+
 type TsuruYamlHealthcheck struct {
 	Path                 string            `json:"path"`
 	Method               string            `json:"method"`
@@ -37,13 +194,39 @@ type TsuruYamlHealthcheck struct {
 	IntervalSeconds      int               `json:"interval_seconds,omitempty" yaml:"interval_seconds" bson:"interval_seconds,omitempty"`
 	TimeoutSeconds       int               `json:"timeout_seconds,omitempty" yaml:"timeout_seconds" bson:"timeout_seconds,omitempty"`
 	DeployTimeoutSeconds int               `json:"deploy_timeout_seconds,omitempty" yaml:"deploy_timeout_seconds" bson:"deploy_timeout_seconds,omitempty"`
+	Startup              *TsuruYamlProbe   `json:"startup,omitempty" bson:"startup,omitempty"`
+	Liveness             *TsuruYamlProbe   `json:"liveness,omitempty" bson:"liveness,omitempty"`
+}
+
+// TsuruYamlProbe describes a startup or liveness probe, checked either with a
+// HTTP request to path or by running command.
+type TsuruYamlProbe struct {
+	Path                string            `json:"path,omitempty" bson:"path,omitempty"`
+	Scheme              string            `json:"scheme,omitempty" bson:"scheme,omitempty"`
+	Headers             map[string]string `json:"headers,omitempty" bson:"headers,omitempty"`
+	Command             []string          `json:"command,omitempty" bson:"command,omitempty"`
+	AllowedFailures     int               `json:"allowed_failures,omitempty" yaml:"allowed_failures" bson:"allowed_failures,omitempty"`
+	IntervalSeconds     int               `json:"interval_seconds,omitempty" yaml:"interval_seconds" bson:"interval_seconds,omitempty"`
+	TimeoutSeconds      int               `json:"timeout_seconds,omitempty" yaml:"timeout_seconds" bson:"timeout_seconds,omitempty"`
+	InitialDelaySeconds int               `json:"initial_delay_seconds,omitempty" yaml:"initial_delay_seconds" bson:"initial_delay_seconds,omitempty"`
 }
 
 type TsuruYamlKubernetesConfig struct {
 	Groups map[string]TsuruYamlKubernetesGroup `json:"groups,omitempty"`
+	// TerminationGracePeriodSeconds holds the time, in seconds, each process
+	// has to finish after being asked to stop.
+	TerminationGracePeriodSeconds map[string]int `json:"termination_grace_period_seconds,omitempty" yaml:"termination_grace_period_seconds" bson:"termination_grace_period_seconds,omitempty"`
 }
 
 func (in *TsuruYamlKubernetesConfig) DeepCopyInto(out *TsuruYamlKubernetesConfig) {
+	if in.TerminationGracePeriodSeconds != nil {
+		if out.TerminationGracePeriodSeconds == nil {
+			out.TerminationGracePeriodSeconds = make(map[string]int)
+		}
+		for k, v := range in.TerminationGracePeriodSeconds {
+			out.TerminationGracePeriodSeconds[k] = v
+		}
+	}
 	if in.Groups == nil {
 		return
 	}
@@ -88,6 +271,15 @@ func (y TsuruYamlData) ToRouterHC() router.HealthcheckData {
 	}
 }
 
+// GetTerminationGracePeriod returns the termination grace period of the
+// process, or 0 when it's not set.
+func (y *TsuruYamlKubernetesConfig) GetTerminationGracePeriod(procName string) int {
+	if y == nil {
+		return 0
+	}
+	return y.TerminationGracePeriodSeconds[procName]
+}
+
 func (y *TsuruYamlKubernetesConfig) GetProcessConfigs(procName string) *TsuruYamlKubernetesProcessConfig {
 	for _, group := range y.Groups {
 		for p, proc := range group {
@@ -58,5 +58,9 @@ func (m *MockQuotaService) Set(ctx context.Context, item QuotaItem, quantity int
 }
 
 func (m *MockQuotaService) Get(ctx context.Context, item QuotaItem) (*Quota, error) {
+	if m.OnGet == nil {
+		q := UnlimitedQuota
+		return &q, nil
+	}
 	return m.OnGet(item)
 }